@@ -2,158 +2,110 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
+	"inventory-system/internal/app"
 	"inventory-system/internal/config"
 	"inventory-system/internal/database"
 	"inventory-system/internal/domain"
-	"inventory-system/internal/handler"
-	"inventory-system/internal/infrastructure"
-	"inventory-system/internal/middleware"
-	"inventory-system/internal/repository"
-	"inventory-system/internal/service"
-	"inventory-system/test/mocks"
 
 	"github.com/gin-gonic/gin"
 )
 
+// @title Inventory System API
+// @version 1.0
+// @description API de inventario distribuido multi-tienda: productos, stock y reservas.
+// @description Los endpoints core también se sirven en /api/v2 con envelope {"data", "meta"} (ver docs/API_VERSIONING.md).
+// @BasePath /api/v1
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
-	// Cargar configuración
-	cfg := config.Load()
-
-	// Configurar modo de Gin
-	if cfg.LogLevel != "debug" {
-		gin.SetMode(gin.ReleaseMode)
-	}
-
-	// Initialize database
-	db, err := database.NewDatabaseClient(cfg)
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "archivo de configuración YAML/TOML (las variables de entorno tienen prioridad)")
+	printConfig := flag.Bool("print-config", false, "imprime la configuración efectiva con los secretos ocultos y termina")
+	backfillSnapshots := flag.Bool("backfill-stock-snapshots", false, "emite stock.snapshot para cada fila de stock (reanuda el último backfill sin terminar) y termina")
+	backfillBatch := flag.Int("backfill-batch-size", domain.DefaultSnapshotBackfillBatch, "filas de stock por lote de -backfill-stock-snapshots")
+	backup := flag.Bool("backup", false, "genera un backup de la base de datos en BACKUP_DIR y termina")
+	restoreBackup := flag.String("restore-backup", "", "reemplaza la base de datos de SQLITE_PATH por este backup y termina (con el servidor parado)")
+	flag.Parse()
+
+	// Cargar configuración (archivo opcional + variables de entorno)
+	cfg, err := config.LoadFile(*configPath)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
-	defer db.Close()
 
-	// Aplicar migraciones
-	log.Println("📊 Applying database migrations...")
-	if err := database.InitializeSchema(db, cfg); err != nil {
-		log.Fatalf("Failed to initialize schema: %v", err)
+	if *printConfig {
+		if err := cfg.WriteRedacted(os.Stdout); err != nil {
+			log.Fatalf("Failed to print configuration: %v", err)
+		}
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
-	log.Println("✅ Database migrations applied successfully")
 
-	// ========== Inicializar Repositorios ==========
-	productRepo := repository.NewProductRepository(db)
-	stockRepo := repository.NewStockRepository(db)
-	reservationRepo := repository.NewReservationRepository(db)
-	eventRepo := repository.NewEventRepository(db)
-
-	// ========== Inicializar Event Publisher (Pub/Sub) ==========
-	publisher, err := initializeEventPublisher(cfg)
-	if err != nil {
-		log.Fatalf("Failed to initialize event publisher: %v", err)
+	// Fallar al arrancar en lugar de descubrir la configuración inválida en runtime
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
 	}
-	defer publisher.Close()
-
-	// ========== Inicializar Servicios ==========
-	productService := service.NewProductService(productRepo, eventRepo)
-	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
-	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
-	eventSyncService := service.NewEventSyncService(eventRepo, publisher) // ✅ Inyectar publisher para re-intentos
-
-	// ========== Inicializar Handlers ==========
-	productHandler := handler.NewProductHandler(productService)
-	stockHandler := handler.NewStockHandler(stockService)
-	reservationHandler := handler.NewReservationHandler(reservationService)
 
-	// ========== Crear Router ==========
-	router := gin.New()
-
-	// ========== Middlewares Globales ==========
-	router.Use(middleware.Recovery())
-	router.Use(middleware.Logger())
-	router.Use(middleware.CORS())
-	router.Use(middleware.RequestID())
-
-	// ========== Health Check ==========
-	router.GET("/health", func(c *gin.Context) {
-		dbStatus := "healthy"
-		if err := database.HealthCheck(db); err != nil {
-			dbStatus = "unhealthy: " + err.Error()
+	// La restauración reemplaza el fichero de la BD: no se abre la aplicación
+	if *restoreBackup != "" {
+		previous, err := database.RestoreBackup(*restoreBackup, cfg.SQLitePath)
+		if err != nil {
+			log.Fatalf("Failed to restore backup: %v", err)
 		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"status":      "healthy",
-			"timestamp":   time.Now().Format(time.RFC3339),
-			"instance_id": cfg.InstanceID,
-			"version":     "1.0.0",
-			"database":    dbStatus,
-			"db_driver":   cfg.DatabaseDriver,
-		})
-	})
-
-	// ========== API v1 Routes ==========
-	v1 := router.Group("/api/v1")
-	{
-		// Product endpoints (lectura pública, escritura protegida)
-		products := v1.Group("/products")
-		{
-			// Públicos (sin API Key)
-			products.GET("", productHandler.ListProducts)
-			products.GET("/:id", productHandler.GetProduct)
-			products.GET("/sku/:sku", productHandler.GetProductBySKU)
-
-			// Protegidos (requieren API Key)
-			products.POST("", middleware.APIKeyAuth(cfg.APIKeys), productHandler.CreateProduct)
-			products.PUT("/:id", middleware.APIKeyAuth(cfg.APIKeys), productHandler.UpdateProduct)
-			products.DELETE("/:id", middleware.APIKeyAuth(cfg.APIKeys), productHandler.DeleteProduct)
+		if previous != "" {
+			log.Printf("📦 Previous database kept at %s", previous)
 		}
+		log.Printf("✅ Database %s restored from %s", cfg.SQLitePath, *restoreBackup)
+		return
+	}
 
-		// Stock endpoints (todos protegidos)
-		stock := v1.Group("/stock", middleware.APIKeyAuth(cfg.APIKeys))
-		{
-			stock.POST("", stockHandler.InitializeStock)
-			stock.GET("/product/:productId", stockHandler.GetAllStockByProduct)
-			stock.GET("/store/:storeId", stockHandler.GetAllStockByStore)
-			stock.GET("/low-stock", stockHandler.GetLowStockItems)
-			stock.GET("/:productId/:storeId", stockHandler.GetStockByProductAndStore)
-			stock.GET("/:productId/:storeId/availability", stockHandler.CheckAvailability)
-			stock.PUT("/:productId/:storeId", stockHandler.UpdateStock)
-			stock.POST("/:productId/:storeId/adjust", stockHandler.AdjustStock)
-		}
+	// Configurar modo de Gin
+	if cfg.LogLevel != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
 
-		// Stock transfer endpoint (protegido)
-		v1.POST("/stock/transfer", middleware.APIKeyAuth(cfg.APIKeys), stockHandler.TransferStock)
+	// Cablear la aplicación (BD, migraciones, servicios y rutas)
+	application, err := app.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
 
-		// Reservation endpoints (todos protegidos)
-		reservations := v1.Group("/reservations", middleware.APIKeyAuth(cfg.APIKeys))
-		{
-			reservations.POST("", reservationHandler.CreateReservation)
-			reservations.GET("/:id", reservationHandler.GetReservation)
-			reservations.POST("/:id/confirm", reservationHandler.ConfirmReservation)
-			reservations.POST("/:id/cancel", reservationHandler.CancelReservation)
-			reservations.GET("/store/:storeId/pending", reservationHandler.GetPendingByStore)
-			reservations.GET("/product/:productId/store/:storeId", reservationHandler.GetReservationsByProduct)
-			reservations.GET("/stats", reservationHandler.GetReservationStats)
-		}
+	if *backfillSnapshots {
+		runSnapshotBackfill(application, *backfillBatch)
+		return
 	}
 
-	// ========== Background Workers ==========
-	// Worker para expirar reservas (cada 1 minuto)
-	go startReservationExpirationWorker(reservationService)
+	if *backup {
+		runBackup(application)
+		return
+	}
 
-	// Worker para sincronizar eventos (cada 10 segundos)
-	go startEventSyncWorker(eventSyncService)
+	// ========== Background Workers ==========
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	application.StartWorkers(workersCtx)
 
 	// ========== Servidor HTTP ==========
-	srv := &http.Server{
-		Addr:    ":" + cfg.ServerPort,
-		Handler: router,
+	srv, err := app.NewServer(cfg, application.Router)
+	if err != nil {
+		log.Fatalf("Failed to configure server: %v", err)
+	}
+
+	scheme := "http"
+	if srv.TLSConfig != nil {
+		scheme = "https"
 	}
 
 	// Iniciar servidor en goroutine
@@ -161,14 +113,39 @@ func main() {
 		log.Printf("🚀 Server starting on port %s (instance: %s)", cfg.ServerPort, cfg.InstanceID)
 		log.Printf("📊 Database driver: %s", cfg.DatabaseDriver)
 		log.Printf("🔒 Log level: %s, format: %s", cfg.LogLevel, cfg.LogFormat)
-		log.Printf("� API Keys loaded: %d", len(cfg.APIKeys))
-		log.Printf("��📡 API available at http://localhost:%s/api/v1", cfg.ServerPort)
-
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("🔑 API Keys loaded: %d", application.KeyRing.Len())
+		log.Printf("📡 API available at %s://localhost:%s/api/v1 (HTTP/2: %v)", scheme, cfg.ServerPort, cfg.HTTP2Enabled)
+
+		var err error
+		if srv.TLSConfig != nil {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
 
+	// ========== Servidor de diagnóstico (DEBUG_ADDR) ==========
+	var debugSrv *http.Server
+	if application.DebugRouter != nil {
+		// Sin WriteTimeout: /debug/pprof/profile y /trace escriben durante ?seconds=
+		debugSrv = &http.Server{
+			Addr:              cfg.DebugAddr,
+			Handler:           application.DebugRouter,
+			ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		}
+		go func() {
+			log.Printf("🩺 Debug endpoints available at http://%s/debug/pprof/", cfg.DebugAddr)
+			if err := debugSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to start debug server: %v", err)
+			}
+		}()
+	} else if cfg.DebugEnabled {
+		log.Printf("🩺 Debug endpoints available at %s://localhost:%s/api/v1/admin/debug/pprof/ (API key required)", scheme, cfg.ServerPort)
+	}
+
 	// ========== Graceful Shutdown ==========
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -182,80 +159,59 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
+	if debugSrv != nil {
+		debugSrv.Close()
+	}
+	stopWorkers()
+
+	// Persistir el uso de API keys acumulado en memoria y liberar recursos
+	if err := application.Close(ctx); err != nil {
+		log.Printf("Error closing application: %v", err)
+	}
 
 	log.Println("✅ Server exited gracefully")
 }
 
-// initializeEventPublisher crea el publisher según la configuración.
-// Soporta múltiples implementaciones: redis, kafka, none.
-func initializeEventPublisher(cfg *config.Config) (domain.EventPublisher, error) {
-	broker := strings.ToLower(cfg.MessageBroker)
-
-	switch broker {
-	case "redis":
-		// Redis Streams (opción por defecto - simple y rápida)
-		addr := fmt.Sprintf("%s:%d", cfg.RedisHost, cfg.RedisPort)
-		publisher, err := infrastructure.NewRedisPublisher(infrastructure.RedisPublisherConfig{
-			Addr:       addr,
-			StreamName: "inventory-events",
-			MaxLen:     100000, // Retener últimos 100k eventos
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Redis publisher: %w", err)
-		}
-		log.Printf("✅ Using Redis Streams as message broker (%s)", addr)
-		return publisher, nil
-
-	case "kafka":
-		// Implementación futura para Apache Kafka
-		return nil, fmt.Errorf("Kafka publisher not implemented yet. Set MESSAGE_BROKER=redis")
-
-	case "none", "":
-		// No publisher (solo logging)
-		log.Printf("⚠️  No message broker configured (MESSAGE_BROKER=none)")
-		return mocks.NewNoOpPublisher(), nil
+// runSnapshotBackfill ejecuta el backfill de stock.snapshot sin arrancar el servidor ni los workers.
+// SIGINT/SIGTERM lo detienen; al volver a ejecutarlo continúa desde el último lote guardado.
+func runSnapshotBackfill(application *app.App, batchSize int) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	default:
-		return nil, fmt.Errorf("unknown message broker: %s (options: redis, kafka, none)", broker)
+	backfill, err := application.SnapshotBackfillService.BackfillStockSnapshots(ctx, batchSize)
+	if err != nil {
+		log.Printf("❌ Snapshot backfill stopped: %v", err)
+	} else {
+		log.Printf("✅ Snapshot backfill %s completed: %d stock.snapshot events", backfill.ID, backfill.Emitted)
 	}
-}
 
-// startReservationExpirationWorker worker para expirar reservas
-func startReservationExpirationWorker(service *service.ReservationService) {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	log.Println("⏰ Reservation expiration worker started")
-
-	for range ticker.C {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		count, err := service.ProcessExpiredReservations(ctx)
-		cancel()
+	closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if closeErr := application.Close(closeCtx); closeErr != nil {
+		log.Printf("Error closing application: %v", closeErr)
+	}
 
-		if err != nil {
-			log.Printf("Error processing expired reservations: %v", err)
-		} else if count > 0 {
-			log.Printf("✅ Expired %d reservations", count)
-		}
+	if err != nil {
+		os.Exit(1)
 	}
 }
 
-// startEventSyncWorker worker para sincronizar eventos
-func startEventSyncWorker(service *service.EventSyncService) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	log.Println("📡 Event synchronization worker started")
+// runBackup genera un backup de la base de datos sin arrancar el servidor ni los workers
+func runBackup(application *app.App) {
+	backup, err := application.BackupService.CreateBackup(context.Background())
+	if err != nil {
+		log.Printf("❌ Backup failed: %v", err)
+	} else {
+		log.Printf("✅ Backup %s created in %s (%d bytes)", backup.Name, application.Config.BackupDir, backup.SizeBytes)
+	}
 
-	for range ticker.C {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		count, err := service.SyncPendingEvents(ctx, 100)
-		cancel()
+	closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if closeErr := application.Close(closeCtx); closeErr != nil {
+		log.Printf("Error closing application: %v", closeErr)
+	}
 
-		if err != nil {
-			log.Printf("Error syncing events: %v", err)
-		} else if count > 0 {
-			log.Printf("✅ Synced %d events", count)
-		}
+	if err != nil {
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,66 @@
+// Package apidocs sirve la spec OpenAPI generada a partir de las anotaciones swag de los handlers.
+//
+// swagger.json se genera con swag (go install github.com/swaggo/swag/cmd/swag@latest)
+// y se versiona junto al código, de modo que el binario la sirve sin pasos extra:
+//
+//	go generate ./internal/apidocs
+package apidocs
+
+//go:generate swag init --generalInfo cmd/api/main.go --dir ../../ --output . --outputTypes json --parseInternal
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed swagger.json
+var spec []byte
+
+// swaggerUI carga Swagger UI desde CDN apuntando a /openapi.json
+const swaggerUI = `<!DOCTYPE html>
+<html lang="es">
+<head>
+  <meta charset="utf-8">
+  <title>Inventory System API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>`
+
+// Spec devuelve la spec OpenAPI embebida
+func Spec() []byte {
+	return spec
+}
+
+// Register registra /openapi.json y /swagger/* en el router
+func Register(router *gin.Engine) {
+	router.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	})
+
+	router.GET("/swagger", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
+	})
+
+	router.GET("/swagger/*any", func(c *gin.Context) {
+		switch c.Param("any") {
+		case "/", "/index.html":
+			c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUI))
+		case "/doc.json":
+			// Ruta usada por defecto por gin-swagger, se mantiene por compatibilidad
+			c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+		default:
+			c.Status(http.StatusNotFound)
+		}
+	})
+}
@@ -0,0 +1,1084 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"inventory-system/internal/apidocs"
+	"inventory-system/internal/auth"
+	"inventory-system/internal/config"
+	"inventory-system/internal/database"
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/realtime"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// App agrupa la aplicación completa ya cableada: base de datos, publisher,
+// servicios y router HTTP. main la sirve con un http.Server y los tests E2E
+// con httptest, de modo que ambos arrancan exactamente la misma aplicación.
+type App struct {
+	Config    *config.Config
+	DB        *sql.DB
+	Router    *gin.Engine
+	KeyRing   *auth.KeyRing
+	Publisher domain.EventPublisher
+
+	// DebugRouter sirve pprof y estadísticas de runtime en DEBUG_ADDR (nil si no se usa un puerto aparte)
+	DebugRouter *gin.Engine
+
+	ProductService       *service.ProductService
+	StockService         *service.StockService
+	ReservationService   *service.ReservationService
+	IntentService        *service.ReservationIntentService
+	IdempotencyService   *service.IdempotencyService
+	EventSyncService     *service.EventSyncService
+	APIKeyUsageService   *service.APIKeyUsageService
+	RunDownService       *service.RunDownService
+	PriceService         *service.PriceService
+	StockScheduleService *service.StockScheduleService
+	FlashSaleService     *service.FlashSaleService
+	ExportService        *service.ExportService
+
+	SnapshotBackfillService *service.SnapshotBackfillService
+	RetentionService        *service.RetentionService
+	BackupService           *service.BackupService
+	ABCService              *service.ABCClassificationService
+	LedgerService           *service.LedgerVerificationService
+	StoreHeartbeatService   *service.StoreHeartbeatService
+	NotificationService     *service.NotificationService
+}
+
+// New conecta la base de datos, aplica las migraciones y construye repositorios,
+// servicios, handlers y rutas a partir de la configuración. No arranca workers
+// ni escucha en ningún puerto: eso queda en manos de quien la invoca.
+func New(cfg *config.Config) (*App, error) {
+	// Initialize database
+	db, err := database.NewDatabaseClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	a, err := build(cfg, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func build(cfg *config.Config, db *sql.DB) (*App, error) {
+	// Aplicar migraciones
+	log.Println("📊 Applying database migrations...")
+	if err := database.InitializeSchema(db, cfg); err != nil {
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+	log.Println("✅ Database migrations applied successfully")
+
+	// Datos sintéticos deterministas para integradores
+	if cfg.SandboxMode {
+		if err := database.SeedSandboxData(db, cfg.SandboxProducts, cfg.SandboxSeed); err != nil {
+			return nil, fmt.Errorf("failed to seed sandbox data: %w", err)
+		}
+		log.Printf("🧪 Sandbox mode: in-memory database seeded with %d synthetic products (seed %d)", cfg.SandboxProducts, cfg.SandboxSeed)
+	}
+
+	// ========== Inicializar Repositorios ==========
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	conflictRepo := repository.NewConflictRepository(db)
+	serialRepo := repository.NewSerialRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	exportRepo := repository.NewExportRepository(db)
+	apiKeyUsageRepo := repository.NewAPIKeyUsageRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	storeRepo := repository.NewStoreRepository(db)
+	assortmentJobRepo := repository.NewAssortmentJobRepository(db)
+	transferRepo := repository.NewTransferRepository(db)
+	reservationGroupRepo := repository.NewReservationGroupRepository(db)
+	rundownRepo := repository.NewRunDownRepository(db)
+	priceRepo := repository.NewPriceRepository(db)
+	mediaRepo := repository.NewMediaRepository(db)
+	translationRepo := repository.NewTranslationRepository(db)
+	storeHoursRepo := repository.NewStoreHoursRepository(db)
+	stockVisibilityRepo := repository.NewStockVisibilityRepository(db)
+	fulfillmentPolicyRepo := repository.NewFulfillmentPolicyRepository(db)
+	productUnitRepo := repository.NewProductUnitRepository(db)
+	oversellRepo := repository.NewOversellRepository(db)
+	channelAllocationRepo := repository.NewChannelAllocationRepository(db)
+	stockScheduleRepo := repository.NewStockScheduleRepository(db)
+	stockAdjustmentRepo := repository.NewStockAdjustmentRepository(db)
+	stockHoldRepo := repository.NewStockHoldRepository(db)
+	stockNoteRepo := repository.NewStockNoteRepository(db)
+	adjustmentReasonRepo := repository.NewAdjustmentReasonRepository(db)
+	storeGroupRepo := repository.NewStoreGroupRepository(db)
+	intentRepo := repository.NewReservationIntentRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+
+	// ========== API Keys (configuración + emitidas en runtime) ==========
+	keyRing := auth.NewKeyRing(cfg.APIKeys)
+	keyRing.SetStoreScopes(cfg.APIKeyStoreScopes)
+	issuedKeys, err := apiKeyRepo.ListActiveHashes(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API keys: %w", err)
+	}
+	for hash, key := range issuedKeys {
+		if key.StoreID != "" {
+			keyRing.AddHash(hash, key.Name, key.StoreID)
+		} else {
+			keyRing.AddHash(hash, key.Name)
+		}
+	}
+	if unscoped := keyRing.UnscopedNames(); len(unscoped) > 0 {
+		if len(cfg.APIKeyStoreScopes) > 0 {
+			log.Printf("⚠️  API keys without API_KEY_STORE_SCOPES entry cannot write to any store: %s (use name:* for multi-store keys)", strings.Join(unscoped, ", "))
+		} else {
+			log.Printf("⚠️  API_KEY_STORE_SCOPES is not set: API keys %s can write to every store", strings.Join(unscoped, ", "))
+		}
+	}
+
+	// ========== Inicializar Event Publisher (Pub/Sub) ==========
+	publisher, err := initializeEventPublisher(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize event publisher: %w", err)
+	}
+
+	// El circuit breaker evita que un broker caído bloquee los flujos de negocio:
+	// con el circuito abierto los eventos quedan solo en el outbox
+	breaker := infrastructure.NewCircuitBreakerPublisher(publisher, infrastructure.CircuitBreakerConfig{
+		FailureThreshold: cfg.PublisherBreakerFailures,
+		OpenTimeout:      cfg.PublisherBreakerOpenTimeout,
+	})
+	publisher = breaker
+
+	// ========== Hub de tiempo real (websocket) ==========
+	// El hub recibe los eventos de stock/reservas a través del publisher decorado
+	hub := realtime.NewHub(64)
+	publisher = realtime.NewHubPublisher(publisher, hub, stockRepo, productRepo)
+
+	// ========== Índice de búsqueda (Elasticsearch / Meilisearch) ==========
+	// Va antes de syncPublisher: los re-intentos del outbox también llegan al indexador,
+	// que reconstruye cada documento desde la BD y por tanto tolera repeticiones
+	searchIndex, err := initializeSearchIndex(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize search index: %w", err)
+	}
+	searchIndexer := service.NewSearchIndexer(searchIndex, cfg.SearchBackend, productRepo, stockRepo)
+	if searchIndexer.Enabled() {
+		publisher = infrastructure.NewSearchIndexPublisher(publisher, searchIndexer.HandleEvent)
+	}
+
+	// ========== Read model de disponibilidad (availability_view) ==========
+	// Se reconstruye al arrancar para recoger los cambios hechos con el servicio parado (migraciones,
+	// restauraciones) y después lo mantiene el proyector de forma síncrona con cada evento
+	availabilityViewRepo := repository.NewAvailabilityViewRepository(db)
+	availabilityProjector := service.NewAvailabilityProjector(availabilityViewRepo)
+	rebuild, err := availabilityProjector.Rebuild(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild availability view: %w", err)
+	}
+	log.Printf("📐 Availability view rebuilt: %d rows (%s)", rebuild.Rows, rebuild.Duration)
+	publisher = infrastructure.NewProjectionPublisher(publisher, "availability_view", availabilityProjector.HandleEvent)
+
+	// ========== Avisos de reservas a clientes (email, SMS, push) ==========
+	// Va antes de syncPublisher: cada aviso se reclama en la BD antes de enviarse, así que los
+	// re-intentos del outbox no lo duplican
+	notificationService := service.NewNotificationService(repository.NewNotificationRepository(db), reservationRepo, productRepo, storeRepo)
+	notificationService.SetStoreHoursRepository(storeHoursRepo)
+	if cfg.SMTPHost != "" {
+		notificationService.RegisterNotifier(infrastructure.NewSMTPNotifier(infrastructure.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			Timeout:  cfg.SMTPTimeout,
+		}))
+		log.Printf("✉️  Reservation email notifications enabled (SMTP %s:%d)", cfg.SMTPHost, cfg.SMTPPort)
+	}
+	publisher = infrastructure.NewNotificationPublisher(publisher, notificationService.HandleEvent)
+
+	// ========== Cache de disponibilidad (Redis) ==========
+	// Los re-intentos del outbox usan el publisher sin el decorador del cache para no
+	// aplicar dos veces los deltas de reservas ya contabilizadas
+	syncPublisher := publisher
+	availabilityCache, err := initializeAvailabilityCache(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize availability cache: %w", err)
+	}
+	if availabilityCache != nil {
+		publisher = infrastructure.NewAvailabilityCachePublisher(publisher, availabilityCache)
+	}
+
+	// ========== Inicializar Servicios ==========
+	productService := service.NewProductService(productRepo, eventRepo)
+	policy, err := skuPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	productService.SetSKUPolicy(policy)
+	productService.SetPublisher(publisher)
+	if searchIndex != nil {
+		productService.SetSearchIndex(searchIndex)
+	}
+	fulfillmentService := service.NewFulfillmentService(fulfillmentPolicyRepo, storeRepo)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	stockService.SetRunDownRepository(rundownRepo)
+	stockService.SetStoreGroupRepository(storeGroupRepo)
+	stockService.SetStockVisibilityRepository(stockVisibilityRepo)
+	stockService.SetAdjustmentReasonRepository(adjustmentReasonRepo)
+	stockService.SetAvailabilityView(availabilityViewRepo)
+	stockService.SetAvailabilityForecast(stockScheduleRepo, reservationRepo)
+	stockService.SetStockNoteRepository(stockNoteRepo)
+	stockService.SetFulfillmentService(fulfillmentService)
+	if availabilityCache != nil {
+		stockService.SetAvailabilityCache(availabilityCache)
+	}
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+	reservationService.SetTTLPolicy(reservationTTLPolicy(cfg))
+	reservationService.SetStoreRepository(storeRepo)
+	reservationService.SetStoreHoursRepository(storeHoursRepo)
+	reservationService.SetStoreGroupRepository(storeGroupRepo)
+	reservationService.SetCustomerHoldLimits(domain.CustomerHoldLimits{
+		MaxUnitsPerProduct:     cfg.ReservationMaxUnitsPerCustomer,
+		MaxPendingReservations: cfg.ReservationMaxPendingPerCustomer,
+	})
+	reservationService.SetLowPriorityShortageGrace(cfg.ReservationShortageGrace)
+	reservationService.SetPickupRepository(repository.NewReservationPickupRepository(db), cfg.ReservationPickupWindow)
+	if cfg.ConfirmHookURL != "" {
+		reservationService.AddConfirmHook(infrastructure.NewHTTPConfirmHook(cfg.ConfirmHookURL, cfg.ConfirmHookSecret, cfg.ConfirmHookTimeout), domain.ConfirmHookPolicy{
+			Mode:        domain.ConfirmHookMode(cfg.ConfirmHookMode),
+			MaxAttempts: cfg.ConfirmHookMaxAttempts,
+			Backoff:     cfg.ConfirmHookBackoff,
+		})
+		log.Printf("🪝 Reservation confirm hook enabled (%s mode)", cfg.ConfirmHookMode)
+	}
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo)
+	intentService := service.NewReservationIntentService(intentRepo, productRepo, stockService, reservationService, cfg.ReservationIntentTTL)
+	eventSyncService := service.NewEventSyncService(eventRepo, syncPublisher) // ✅ Inyectar publisher para re-intentos
+	snapshotBackfillService := service.NewSnapshotBackfillService(repository.NewSnapshotBackfillRepository(db), stockRepo, eventRepo, syncPublisher)
+	conflictService := service.NewConflictService(conflictRepo, stockRepo, eventRepo, publisher)
+	conflictService.SetReservationService(reservationService)
+	flashSaleService := service.NewFlashSaleService(repository.NewFlashSaleRepository(db), productRepo, reservationService, service.FlashSaleConfig{
+		QueueSize: cfg.FlashSaleQueueSize,
+		TicketTTL: cfg.FlashSaleTicketTTL,
+	})
+	serialService := service.NewSerialService(serialRepo, reservationRepo)
+	reportService := service.NewReportService(reportRepo)
+	reportService.SetStoreGroupRepository(storeGroupRepo)
+	apiKeyUsageService := service.NewAPIKeyUsageService(apiKeyUsageRepo, keyRing)
+	latencyService := service.NewLatencyService(cfg.LatencyWindow)
+	contentionService := service.NewContentionService(cfg.ContentionWindow)
+	contentionService.SetFlashSaleService(flashSaleService)
+	stockService.SetContentionService(contentionService)
+	conflictService.SetContentionService(contentionService)
+	storeHeartbeatService := service.NewStoreHeartbeatService(repository.NewStoreHeartbeatRepository(db), eventRepo, publisher, cfg.StoreSyncStaleAfter)
+	storeService := service.NewStoreService(storeRepo, apiKeyRepo, stockRepo, productRepo, eventRepo, publisher, keyRing)
+	storeService.SetStoreHoursRepository(storeHoursRepo)
+	storeService.SetStockVisibilityRepository(stockVisibilityRepo)
+	storeGroupService := service.NewStoreGroupService(storeGroupRepo)
+	assortmentService := service.NewAssortmentService(assortmentJobRepo, storeRepo, stockRepo, eventRepo, publisher)
+	assortmentService.SetRunDownRepository(rundownRepo)
+	assortmentService.SetAvailabilityView(availabilityViewRepo)
+	auditService := service.NewAuditService(eventRepo)
+	reservedReconciliationService := service.NewReservedReconciliationService(stockRepo, eventRepo, publisher)
+	ledgerVerificationService := service.NewLedgerVerificationService(repository.NewStockLedgerRepository(db))
+	transferReservationService := service.NewTransferReservationService(reservationService, reservationRepo, stockRepo, transferRepo, eventRepo, publisher)
+	transferReservationService.SetStoreGroupRepository(storeGroupRepo)
+	reservationGroupService := service.NewReservationGroupService(reservationService, reservationRepo, reservationGroupRepo)
+	autoReservationService := service.NewAutoReservationService(reservationService, stockRepo)
+	autoReservationService.SetReservationGroupService(reservationGroupService)
+	autoReservationService.SetFulfillmentService(fulfillmentService)
+	reservationImportService := service.NewReservationImportService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+	rundownService := service.NewRunDownService(rundownRepo, stockRepo, productRepo, eventRepo, publisher)
+	priceService := service.NewPriceService(priceRepo, productRepo)
+	blobStore, err := initializeBlobStore(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize media storage: %w", err)
+	}
+	mediaService := service.NewMediaService(mediaRepo, productRepo, blobStore, int64(cfg.MediaMaxUploadMB)<<20)
+	productService.SetMediaService(mediaService)
+	translationService := service.NewTranslationService(translationRepo, productRepo, localePolicy(cfg))
+	productUnitService := service.NewProductUnitService(productUnitRepo, productRepo, stockRepo)
+	featureFlagRules, err := featureFlagRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+	featureFlagService := service.NewFeatureFlagService(repository.NewFeatureFlagRepository(db), storeRepo, featureFlagRules)
+	transferReservationService.SetFeatureFlags(featureFlagService)
+	productUnitService.SetFeatureFlags(featureFlagService)
+	oversellService := service.NewOversellService(oversellRepo, storeRepo, productRepo)
+	channelAllocationService := service.NewChannelAllocationService(channelAllocationRepo)
+	channelAllocationService.SetFeatureFlags(featureFlagService)
+	stockScheduleService := service.NewStockScheduleService(stockScheduleRepo, stockService)
+	stockScheduleService.SetReservationService(reservationService)
+	stockAdjustmentService := service.NewStockAdjustmentService(stockAdjustmentRepo, stockService, domain.AdjustmentApprovalPolicy{
+		MaxUnits:   cfg.AdjustmentApprovalMaxUnits,
+		MaxPercent: cfg.AdjustmentApprovalMaxPercent,
+	})
+	if cfg.AdjustmentApprovalMaxUnits > 0 || cfg.AdjustmentApprovalMaxPercent > 0 {
+		log.Printf("🔏 Stock adjustment approval enabled (max %d units, max %v%%)", cfg.AdjustmentApprovalMaxUnits, cfg.AdjustmentApprovalMaxPercent)
+	}
+	stockHoldService := service.NewStockHoldService(stockHoldRepo, stockService)
+	stockNoteService := service.NewStockNoteService(stockNoteRepo, stockRepo, eventRepo)
+	exportService := service.NewExportService(exportRepo, initializeExportStore(cfg, blobStore), cfg.ExportRetention)
+	backupService := service.NewBackupService(repository.NewBackupRepository(db), cfg.BackupDir, cfg.BackupRetain)
+	retentionService := service.NewRetentionService(eventRepo, reservationRepo, []domain.RetentionPolicy{
+		{Table: domain.RetentionEvents, MaxAge: cfg.RetentionEvents},
+		{Table: domain.RetentionReservations, MaxAge: cfg.RetentionReservations},
+	})
+	retentionService.SetCleanupLimits(cfg.CleanupMaxBatchSize, cfg.CleanupBatchPause)
+	if cfg.RetentionArchive {
+		retentionService.SetArchiveStore(initializeArchiveStore(cfg, blobStore))
+	}
+	abcService := service.NewABCClassificationService(productRepo, cfg.ABCWindow)
+
+	// ========== Inicializar Handlers ==========
+	productHandler := handler.NewProductHandler(productService)
+	productHandler.SetTranslationService(translationService)
+	stockHandler := handler.NewStockHandler(stockService)
+	stockHandler.SetProductUnitService(productUnitService)
+	stockHandler.SetStockAdjustmentService(stockAdjustmentService)
+	reservationHandler := handler.NewReservationHandler(reservationService, serialService)
+	reservationHandler.SetFlashSaleService(flashSaleService)
+	reservationHandler.SetProductUnitService(productUnitService)
+	intentHandler := handler.NewReservationIntentHandler(intentService)
+	intentHandler.SetProductUnitService(productUnitService)
+	flashSaleHandler := handler.NewFlashSaleHandler(flashSaleService)
+	conflictHandler := handler.NewConflictHandler(conflictService)
+	realtimeHandler := handler.NewRealtimeHandler(hub)
+	serialHandler := handler.NewSerialHandler(serialService)
+	reportHandler := handler.NewReportHandler(reportService)
+	exportHandler := handler.NewExportHandler(exportService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUsageService)
+	latencyHandler := handler.NewLatencyHandler(latencyService)
+	contentionHandler := handler.NewContentionHandler(contentionService)
+	storeHeartbeatHandler := handler.NewStoreHeartbeatHandler(storeHeartbeatService)
+	storeHandler := handler.NewStoreHandler(storeService)
+	storeGroupHandler := handler.NewStoreGroupHandler(storeGroupService)
+	assortmentHandler := handler.NewAssortmentHandler(assortmentService)
+	transferReservationHandler := handler.NewTransferReservationHandler(transferReservationService)
+	transferReservationHandler.SetProductUnitService(productUnitService)
+	autoReservationHandler := handler.NewAutoReservationHandler(autoReservationService)
+	autoReservationHandler.SetProductUnitService(productUnitService)
+	reservationGroupHandler := handler.NewReservationGroupHandler(reservationGroupService)
+	reservationGroupHandler.SetProductUnitService(productUnitService)
+	reservationImportHandler := handler.NewReservationImportHandler(reservationImportService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	reservedReconciliationHandler := handler.NewReservedReconciliationHandler(reservedReconciliationService)
+	ledgerVerificationHandler := handler.NewLedgerVerificationHandler(ledgerVerificationService)
+	eventHandler := handler.NewEventHandler(eventSyncService)
+	cleanupHandler := handler.NewCleanupHandler(retentionService)
+	backupHandler := handler.NewBackupHandler(backupService)
+	abcHandler := handler.NewABCClassificationHandler(abcService)
+	searchIndexHandler := handler.NewSearchIndexHandler(searchIndexer)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	fulfillmentHandler := handler.NewFulfillmentHandler(fulfillmentService)
+	availabilityViewHandler := handler.NewAvailabilityViewHandler(availabilityProjector)
+	featureFlagHandler := handler.NewFeatureFlagHandler(featureFlagService)
+	debugHandler := handler.NewDebugHandler(db, cfg.InstanceID)
+	rundownHandler := handler.NewRunDownHandler(rundownService)
+	priceHandler := handler.NewPriceHandler(priceService)
+	mediaHandler := handler.NewMediaHandler(mediaService)
+	translationHandler := handler.NewTranslationHandler(translationService)
+	productUnitHandler := handler.NewProductUnitHandler(productUnitService)
+	oversellHandler := handler.NewOversellHandler(oversellService)
+	channelAllocationHandler := handler.NewChannelAllocationHandler(channelAllocationService)
+	channelAllocationHandler.SetProductUnitService(productUnitService)
+	stockScheduleHandler := handler.NewStockScheduleHandler(stockScheduleService)
+	stockScheduleHandler.SetProductUnitService(productUnitService)
+	stockAdjustmentHandler := handler.NewStockAdjustmentHandler(stockAdjustmentService)
+	stockHoldHandler := handler.NewStockHoldHandler(stockHoldService)
+	stockHoldHandler.SetProductUnitService(productUnitService)
+	stockNoteHandler := handler.NewStockNoteHandler(stockNoteService)
+	adjustmentReasonHandler := handler.NewAdjustmentReasonHandler(service.NewAdjustmentReasonService(adjustmentReasonRepo))
+	metricsHandler := handler.NewMetricsHandler(reportService, cfg.MetricsLowStockThreshold, cfg.MetricsLowStockTopN)
+	metricsHandler.SetPublisherMonitoring(breaker, eventSyncService)
+	metricsHandler.SetRetentionService(retentionService)
+	metricsHandler.SetLedgerVerificationService(ledgerVerificationService)
+	metricsHandler.SetContentionService(contentionService)
+
+	errorReporter, err := initializeErrorReporter(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize error reporter: %w", err)
+	}
+
+	// ========== Crear Router ==========
+	router := gin.New()
+
+	// ========== Middlewares Globales ==========
+	// Logger antes que Recovery para que los panics aparezcan en el log de acceso como 500
+	router.Use(middleware.Logger())
+	router.Use(middleware.Recovery(errorReporter, cfg.InstanceID))
+	router.Use(middleware.CORS())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.LatencyBudget(latencyService, latencyBudgetPolicy(cfg)))
+	if cfg.CompressionEnabled {
+		router.Use(middleware.Compression(cfg.CompressionMinBytes))
+	}
+	router.Use(middleware.BodyLimit(int64(cfg.MaxRequestBodyKB) << 10))
+	router.Use(middleware.ListLimit(cfg.MaxListLimit))
+	router.Use(middleware.Timeout(requestTimeoutPolicy(cfg)))
+	router.Use(middleware.SalesChannel())
+	router.Use(middleware.APIKeyUsage(apiKeyUsageService))
+	router.Use(middleware.Idempotency(idempotencyService))
+	if cfg.SandboxMode {
+		router.Use(middleware.Sandbox(cfg.SandboxLatency, cfg.SandboxLatencyJitter, cfg.SandboxErrorRate, cfg.SandboxSeed))
+	}
+
+	// ========== Health Check ==========
+	router.GET("/health", func(c *gin.Context) {
+		dbStatus := "healthy"
+		if err := database.HealthCheck(db); err != nil {
+			dbStatus = "unhealthy: " + err.Error()
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":      "healthy",
+			"timestamp":   time.Now().Format(time.RFC3339),
+			"instance_id": cfg.InstanceID,
+			"version":     "1.0.0",
+			"database":    dbStatus,
+			"db_driver":   cfg.DatabaseDriver,
+			"sandbox":     cfg.SandboxMode,
+		})
+	})
+
+	// ========== Readiness ==========
+	// 503 si la BD no responde. Con el broker caído (circuito abierto) la instancia sigue
+	// lista pero degradada: las escrituras funcionan y los eventos esperan en el outbox.
+	router.GET("/ready", func(c *gin.Context) {
+		status := http.StatusOK
+		readiness := "ready"
+
+		dbStatus := "healthy"
+		if err := database.HealthCheck(db); err != nil {
+			dbStatus = "unhealthy: " + err.Error()
+			status = http.StatusServiceUnavailable
+			readiness = "not_ready"
+		}
+
+		publisherStatus := breaker.Status()
+		if status == http.StatusOK && publisherStatus.State != domain.CircuitClosed {
+			readiness = "degraded"
+		}
+
+		pending, err := eventSyncService.GetPendingEventsCount(c.Request.Context())
+		if err != nil {
+			pending = -1
+		}
+
+		c.JSON(status, gin.H{
+			"status":         readiness,
+			"timestamp":      time.Now().Format(time.RFC3339),
+			"instance_id":    cfg.InstanceID,
+			"database":       dbStatus,
+			"publisher":      publisherStatus,
+			"pending_events": pending,
+		})
+	})
+
+	// ========== Métricas de negocio (OpenMetrics) ==========
+	if cfg.EnableMetrics {
+		router.GET("/metrics/stock", metricsHandler.LowStock)
+		router.GET("/metrics/publisher", metricsHandler.Publisher)
+		router.GET("/metrics/retention", metricsHandler.Retention)
+		router.GET("/metrics/ledger", metricsHandler.Ledger)
+		router.GET("/metrics/contention", metricsHandler.Contention)
+		log.Printf("📈 Low-stock metrics available at /metrics/stock (threshold=%d, top=%d)", cfg.MetricsLowStockThreshold, cfg.MetricsLowStockTopN)
+	}
+
+	// ========== API docs (OpenAPI) ==========
+	if cfg.SwaggerEnabled {
+		apidocs.Register(router)
+		log.Println("📚 API docs available at /swagger/index.html")
+	}
+
+	// ========== Imágenes de productos (almacenamiento local) ==========
+	if local, ok := blobStore.(*infrastructure.LocalBlobStore); ok && strings.HasPrefix(cfg.MediaPublicBaseURL, "/") {
+		router.Static(cfg.MediaPublicBaseURL, local.Dir())
+	}
+
+	// ========== API v1 Routes ==========
+	v1 := router.Group("/api/v1", handler.UseSerializer(handler.V1Serializer{}), handler.ValidatePathParams())
+	{
+		// Core endpoints (productos, stock, reservas): deprecados en favor de /api/v2
+		core := v1.Group("", middleware.Deprecation("/api/v2", cfg.APIV1Sunset))
+		handler.RegisterCoreRoutes(core, middleware.APIKeyAuth(keyRing), productHandler, stockHandler, reservationHandler)
+
+		// Serial endpoints (ventas serializadas, protegidos)
+		v1.POST("/reservations/:id/serials", middleware.APIKeyAuth(keyRing), serialHandler.RegisterSerials)
+		v1.GET("/reservations/:id/serials", middleware.APIKeyAuth(keyRing), serialHandler.GetReservationSerials)
+
+		// Avisos enviados al cliente de una reserva (protegido)
+		v1.GET("/reservations/:id/notifications", middleware.APIKeyAuth(keyRing), notificationHandler.ListReservationNotifications)
+
+		// Contacto de los clientes para los avisos de sus reservas (protegidos)
+		customers := v1.Group("/customers", middleware.APIKeyAuth(keyRing))
+		{
+			customers.GET("/:customerId/contact", notificationHandler.GetCustomerContact)
+			customers.PUT("/:customerId/contact", notificationHandler.PutCustomerContact)
+			customers.DELETE("/:customerId/contact", notificationHandler.DeleteCustomerContact)
+		}
+
+		// Reservas servidas desde otra tienda mediante transferencia (protegidos)
+		v1.POST("/reservations/transfer", middleware.APIKeyAuth(keyRing), transferReservationHandler.CreateTransferReservation)
+		v1.GET("/reservations/:id/transfer", middleware.APIKeyAuth(keyRing), transferReservationHandler.GetTransferReservation)
+
+		// Reservas con selección automática de tienda (protegido)
+		v1.POST("/reservations/auto", middleware.APIKeyAuth(keyRing), autoReservationHandler.CreateAutoReservation)
+
+		// Grupos de reservas en distintas tiendas (envío partido, protegidos)
+		v1.POST("/reservations/groups", middleware.APIKeyAuth(keyRing), reservationGroupHandler.CreateReservationGroup)
+		v1.GET("/reservations/groups/:id", middleware.APIKeyAuth(keyRing), reservationGroupHandler.GetReservationGroup)
+		v1.POST("/reservations/groups/:id/confirm", middleware.APIKeyAuth(keyRing), reservationGroupHandler.ConfirmReservationGroup)
+		v1.POST("/reservations/groups/:id/cancel", middleware.APIKeyAuth(keyRing), reservationGroupHandler.CancelReservationGroup)
+
+		v1.POST("/reservations/intents", middleware.APIKeyAuth(keyRing), intentHandler.CreateReservationIntent)
+		v1.GET("/reservations/intents/:token", middleware.APIKeyAuth(keyRing), intentHandler.GetReservationIntent)
+		v1.POST("/reservations/intents/:token/convert", middleware.APIKeyAuth(keyRing), intentHandler.ConvertReservationIntent)
+
+		// Importación de reservas desde un OMS externo (migraciones, protegido)
+		v1.POST("/reservations/import", middleware.APIKeyAuth(keyRing), reservationImportHandler.ImportReservations)
+
+		// Disponibilidad pública por tienda (API key opcional: con ella se ven todas las cantidades)
+		v1.GET("/products/:id/availability", middleware.OptionalAPIKeyAuth(keyRing), stockHandler.GetProductAvailability)
+
+		// Descatalogación con run-down de stock (protegidos)
+		v1.POST("/products/:id/discontinue", middleware.APIKeyAuth(keyRing), rundownHandler.DiscontinueProduct)
+		v1.GET("/products/:id/rundown", middleware.APIKeyAuth(keyRing), rundownHandler.GetRunDown)
+
+		// Historial de precios y cambios programados (protegidos)
+		v1.GET("/products/:id/price-history", middleware.APIKeyAuth(keyRing), priceHandler.GetPriceHistory)
+		v1.POST("/products/:id/scheduled-prices", middleware.APIKeyAuth(keyRing), priceHandler.SchedulePriceChange)
+		v1.GET("/products/:id/scheduled-prices", middleware.APIKeyAuth(keyRing), priceHandler.ListScheduledPriceChanges)
+		v1.DELETE("/products/:id/scheduled-prices/:scheduleId", middleware.APIKeyAuth(keyRing), priceHandler.CancelScheduledPriceChange)
+
+		// Imágenes de productos (protegidos)
+		v1.POST("/products/:id/media", middleware.APIKeyAuth(keyRing), mediaHandler.UploadMedia)
+		v1.GET("/products/:id/media", middleware.APIKeyAuth(keyRing), mediaHandler.ListMedia)
+		v1.PATCH("/products/:id/media/:mediaId", middleware.APIKeyAuth(keyRing), mediaHandler.UpdateMedia)
+		v1.DELETE("/products/:id/media/:mediaId", middleware.APIKeyAuth(keyRing), mediaHandler.DeleteMedia)
+
+		// Traducciones de productos (protegidos)
+		v1.GET("/products/:id/translations", middleware.APIKeyAuth(keyRing), translationHandler.ListTranslations)
+		v1.PUT("/products/:id/translations/:locale", middleware.APIKeyAuth(keyRing), translationHandler.PutTranslation)
+		v1.DELETE("/products/:id/translations/:locale", middleware.APIKeyAuth(keyRing), translationHandler.DeleteTranslation)
+
+		// Unidades de medida de productos (protegidos)
+		v1.GET("/products/:id/units", middleware.APIKeyAuth(keyRing), productUnitHandler.GetProductUnits)
+		v1.PUT("/products/:id/units", middleware.APIKeyAuth(keyRing), productUnitHandler.PutProductUnits)
+		v1.DELETE("/products/:id/units", middleware.APIKeyAuth(keyRing), productUnitHandler.DeleteProductUnits)
+
+		// Serial lookup (garantías, protegido)
+		v1.GET("/serials/:serial", middleware.APIKeyAuth(keyRing), serialHandler.LookupSerial)
+
+		// Stock de seguridad para venta en tienda (protegido)
+		v1.PUT("/stock/:productId/:storeId/safety-stock", middleware.APIKeyAuth(keyRing), stockHandler.SetSafetyStock)
+
+		// Asignaciones de stock por canal de venta (protegidos)
+		v1.GET("/stock/:productId/:storeId/channels", middleware.APIKeyAuth(keyRing), channelAllocationHandler.GetChannelAllocations)
+		v1.POST("/stock/:productId/:storeId/channels/transfer", middleware.APIKeyAuth(keyRing), channelAllocationHandler.TransferChannelAllocation)
+		v1.PUT("/stock/:productId/:storeId/channels/:channel", middleware.APIKeyAuth(keyRing), channelAllocationHandler.PutChannelAllocation)
+		v1.DELETE("/stock/:productId/:storeId/channels/:channel", middleware.APIKeyAuth(keyRing), channelAllocationHandler.DeleteChannelAllocation)
+
+		// Cambios de stock programados (protegidos)
+		v1.POST("/stock/:productId/:storeId/scheduled-changes", middleware.APIKeyAuth(keyRing), stockScheduleHandler.ScheduleStockChange)
+		v1.GET("/stock/:productId/:storeId/scheduled-changes", middleware.APIKeyAuth(keyRing), stockScheduleHandler.ListScheduledStockChanges)
+		v1.DELETE("/stock/:productId/:storeId/scheduled-changes/:scheduleId", middleware.APIKeyAuth(keyRing), stockScheduleHandler.CancelScheduledStockChange)
+
+		// Ajustes manuales de stock pendientes de aprobación (protegidos)
+		adjustments := v1.Group("/adjustments", middleware.APIKeyAuth(keyRing))
+		{
+			adjustments.GET("", stockAdjustmentHandler.ListAdjustments)
+			adjustments.GET("/:id", stockAdjustmentHandler.GetAdjustment)
+			adjustments.POST("/:id/approve", stockAdjustmentHandler.ApproveAdjustment)
+			adjustments.POST("/:id/reject", stockAdjustmentHandler.RejectAdjustment)
+		}
+
+		// Línea temporal de una fila de stock reconstruida desde sus eventos (protegido)
+		v1.GET("/stock/:productId/:storeId/history", middleware.APIKeyAuth(keyRing), stockHandler.GetStockHistory)
+		v1.POST("/stock/:productId/:storeId/preorders/allocate", middleware.APIKeyAuth(keyRing), reservationHandler.AllocatePreorders)
+
+		// Retenciones internas de stock: exposición, reparación, calidad (protegidos)
+		v1.POST("/stock/:productId/:storeId/holds", middleware.APIKeyAuth(keyRing), stockHoldHandler.PlaceHold)
+		holds := v1.Group("/holds", middleware.APIKeyAuth(keyRing))
+		{
+			holds.GET("", stockHoldHandler.ListHolds)
+			holds.GET("/:id", stockHoldHandler.GetHold)
+			holds.POST("/:id/release", stockHoldHandler.ReleaseHold)
+		}
+
+		// Notas del personal sobre filas de stock y movimientos: descuadres, daños (protegidos)
+		v1.POST("/stock/:productId/:storeId/notes", middleware.APIKeyAuth(keyRing), stockNoteHandler.AddNote)
+		v1.GET("/stock/:productId/:storeId/notes", middleware.APIKeyAuth(keyRing), stockNoteHandler.ListNotes)
+
+		// Catálogo de motivos de ajuste (protegido; se gestiona en /admin/adjustment-reasons)
+		v1.GET("/adjustment-reasons", middleware.APIKeyAuth(keyRing), adjustmentReasonHandler.ListAdjustmentReasons)
+
+		// Productos sin disponibilidad (reporte para reposiciones, protegido)
+		v1.GET("/stock/out-of-stock", middleware.APIKeyAuth(keyRing), reportHandler.GetOutOfStock)
+
+		// Movimientos de stock del ledger (JSON en streaming, protegido)
+		v1.GET("/stock/movements", middleware.APIKeyAuth(keyRing), eventHandler.ListMovements)
+
+		// Report endpoints (todos protegidos)
+		reports := v1.Group("/reports", middleware.APIKeyAuth(keyRing))
+		{
+			reports.GET("/overview", reportHandler.GetOverview)
+			reports.GET("/adjustments", reportHandler.GetAdjustmentsByReason)
+			reports.GET("/kpis", reportHandler.GetKPIs)
+
+			// Exportaciones asíncronas: se generan en background y se descargan por la API
+			reports.POST("/exports", exportHandler.CreateExport)
+			reports.GET("/exports/:id", exportHandler.GetExport)
+			reports.GET("/exports/:id/download", exportHandler.DownloadExport)
+		}
+
+		// Sync endpoints (cambios originados en otras instancias)
+		sync := v1.Group("/sync", middleware.APIKeyAuth(keyRing))
+		{
+			sync.POST("/stock", conflictHandler.ApplyRemoteStockUpdate)
+			sync.POST("/heartbeat", storeHeartbeatHandler.PostHeartbeat)
+		}
+
+		// Realtime endpoints (websocket, protegidos)
+		v1.GET("/realtime/availability", middleware.APIKeyAuth(keyRing), realtimeHandler.SubscribeAvailability)
+
+		// Admin endpoints (todos protegidos)
+		admin := v1.Group("/admin", middleware.APIKeyAuth(keyRing))
+		{
+			admin.GET("/conflicts", conflictHandler.ListUnresolvedConflicts)
+			admin.POST("/conflicts/:id/resolve", conflictHandler.ResolveConflict)
+			admin.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+			admin.GET("/api-keys/:id/usage", apiKeyHandler.GetAPIKeyUsage)
+			admin.GET("/latency", latencyHandler.GetLatency)
+			admin.GET("/contention", contentionHandler.GetContention)
+			admin.GET("/stores/status", storeHeartbeatHandler.GetStoresStatus)
+			admin.POST("/stores/:id/bootstrap", storeHandler.BootstrapStore)
+			admin.GET("/stores/:id/hours", storeHandler.GetStoreHours)
+			admin.PUT("/stores/:id/hours", storeHandler.PutStoreHours)
+			admin.DELETE("/stores/:id/hours", storeHandler.DeleteStoreHours)
+			admin.GET("/stores/:id/stock-visibility", storeHandler.GetStockVisibility)
+			admin.PUT("/stores/:id/stock-visibility", storeHandler.PutStockVisibility)
+			admin.DELETE("/stores/:id/stock-visibility", storeHandler.DeleteStockVisibility)
+			admin.PUT("/stores/:id/location-type", storeHandler.PutStoreLocationType)
+			admin.GET("/stores/:id/notifications", notificationHandler.GetStoreNotifications)
+			admin.PUT("/stores/:id/notifications", notificationHandler.PutStoreNotifications)
+			admin.DELETE("/stores/:id/notifications", notificationHandler.DeleteStoreNotifications)
+			admin.GET("/fulfillment-policies", fulfillmentHandler.ListFulfillmentPolicies)
+			admin.GET("/fulfillment-policies/:channel", fulfillmentHandler.GetFulfillmentPolicy)
+			admin.PUT("/fulfillment-policies/:channel", fulfillmentHandler.PutFulfillmentPolicy)
+			admin.DELETE("/fulfillment-policies/:channel", fulfillmentHandler.DeleteFulfillmentPolicy)
+			admin.POST("/store-groups", storeGroupHandler.CreateStoreGroup)
+			admin.GET("/store-groups", storeGroupHandler.ListStoreGroups)
+			admin.GET("/store-groups/:id", storeGroupHandler.GetStoreGroup)
+			admin.PUT("/store-groups/:id", storeGroupHandler.UpdateStoreGroup)
+			admin.DELETE("/store-groups/:id", storeGroupHandler.DeleteStoreGroup)
+			admin.POST("/stores/:id/clone-assortment", assortmentHandler.CloneAssortment)
+			admin.GET("/assortment-jobs/:id", assortmentHandler.GetCloneJob)
+			admin.GET("/audit/verify", auditHandler.VerifyAuditChain)
+			admin.POST("/backups", backupHandler.CreateBackup)
+			admin.GET("/backups", backupHandler.ListBackups)
+			admin.GET("/backups/:name/download", backupHandler.DownloadBackup)
+			admin.GET("/feature-flags", featureFlagHandler.ListFeatureFlags)
+			admin.PUT("/feature-flags/:feature", featureFlagHandler.PutFeatureFlag)
+			admin.DELETE("/feature-flags/:feature", featureFlagHandler.DeleteFeatureFlag)
+			admin.PUT("/adjustment-reasons/:code", adjustmentReasonHandler.PutAdjustmentReason)
+			admin.DELETE("/adjustment-reasons/:code", adjustmentReasonHandler.DeleteAdjustmentReason)
+			admin.GET("/flash-sale/products", flashSaleHandler.ListFlashSaleProducts)
+			admin.PUT("/flash-sale/products/:id", flashSaleHandler.EnableFlashSale)
+			admin.DELETE("/flash-sale/products/:id", flashSaleHandler.DisableFlashSale)
+			admin.GET("/oversell", oversellHandler.ListOversellTolerances)
+			admin.PUT("/oversell/stores/:id", oversellHandler.PutStoreOversell)
+			admin.DELETE("/oversell/stores/:id", oversellHandler.DeleteStoreOversell)
+			admin.PUT("/oversell/products/:id", oversellHandler.PutProductOversell)
+			admin.DELETE("/oversell/products/:id", oversellHandler.DeleteProductOversell)
+			admin.POST("/reservations/expire", reservationHandler.RunReservationExpiration)
+			admin.POST("/stock/reconcile-reserved", reservedReconciliationHandler.ReconcileReserved)
+			admin.POST("/stock/ledger-verification", ledgerVerificationHandler.RunLedgerVerification)
+			admin.GET("/stock/ledger-verification", ledgerVerificationHandler.GetLedgerVerification)
+			admin.GET("/events", eventHandler.ListEvents)
+			admin.POST("/cleanup/:table", cleanupHandler.RunCleanup)
+			admin.POST("/abc-classification", abcHandler.RunABCClassification)
+			admin.POST("/search/reindex", searchIndexHandler.ReindexSearch)
+			admin.POST("/availability-view/rebuild", availabilityViewHandler.RebuildAvailabilityView)
+
+			// Diagnóstico en el puerto principal solo si no se usa DEBUG_ADDR
+			if cfg.DebugEnabled && cfg.DebugAddr == "" {
+				handler.RegisterDebugRoutes(admin.Group("/debug"), debugHandler)
+			}
+		}
+	}
+
+	// ========== Debug Router (DEBUG_ADDR) ==========
+	// Sin API key para que funcione go tool pprof: DEBUG_ADDR no debe ser accesible desde fuera
+	var debugRouter *gin.Engine
+	if cfg.DebugEnabled && cfg.DebugAddr != "" {
+		debugRouter = gin.New()
+		debugRouter.Use(middleware.Recovery(errorReporter, cfg.InstanceID))
+		handler.RegisterDebugRoutes(debugRouter.Group("/debug"), debugHandler)
+	}
+
+	// ========== API v2 Routes ==========
+	// Mismos handlers que v1 con envelope uniforme {"data", "meta"} / {"error": {"code", ...}}
+	v2 := router.Group("/api/v2", handler.UseSerializer(handler.V2Serializer{}), handler.ValidatePathParams())
+	{
+		handler.RegisterCoreRoutes(v2, middleware.APIKeyAuth(keyRing), productHandler, stockHandler, reservationHandler)
+	}
+
+	return &App{
+		Config:               cfg,
+		DB:                   db,
+		Router:               router,
+		KeyRing:              keyRing,
+		Publisher:            publisher,
+		ProductService:       productService,
+		StockService:         stockService,
+		ReservationService:   reservationService,
+		IntentService:        intentService,
+		IdempotencyService:   idempotencyService,
+		EventSyncService:     eventSyncService,
+		APIKeyUsageService:   apiKeyUsageService,
+		RunDownService:       rundownService,
+		PriceService:         priceService,
+		StockScheduleService: stockScheduleService,
+		FlashSaleService:     flashSaleService,
+		ExportService:        exportService,
+
+		SnapshotBackfillService: snapshotBackfillService,
+		RetentionService:        retentionService,
+		BackupService:           backupService,
+		ABCService:              abcService,
+		LedgerService:           ledgerVerificationService,
+		StoreHeartbeatService:   storeHeartbeatService,
+		NotificationService:     notificationService,
+
+		DebugRouter: debugRouter,
+	}, nil
+}
+
+// Close vacía las colas de flash sale, espera a los hooks de confirmación asíncronos, persiste el uso de API keys acumulado en memoria y libera el publisher
+// y la base de datos. Debe llamarse después de detener el servidor HTTP.
+func (a *App) Close(ctx context.Context) error {
+	// Procesar las reservas encoladas antes de cerrar el publisher y la BD
+	a.FlashSaleService.Close()
+	a.ReservationService.WaitConfirmHooks()
+
+	if _, err := a.APIKeyUsageService.Flush(ctx); err != nil {
+		log.Printf("Error flushing API key usage: %v", err)
+	}
+
+	if err := a.Publisher.Close(); err != nil {
+		log.Printf("Error closing event publisher: %v", err)
+	}
+
+	return a.DB.Close()
+}
+
+// initializeEventPublisher crea el publisher según la configuración.
+// Soporta múltiples implementaciones: redis, nats, gcppubsub, sns, sqs, kafka, none.
+func initializeEventPublisher(cfg *config.Config) (domain.EventPublisher, error) {
+	broker := strings.ToLower(cfg.MessageBroker)
+
+	switch broker {
+	case "redis":
+		// Redis Streams (opción por defecto - simple y rápida)
+		addr := fmt.Sprintf("%s:%d", cfg.RedisHost, cfg.RedisPort)
+		publisher, err := infrastructure.NewRedisPublisher(infrastructure.RedisPublisherConfig{
+			Addr:       addr,
+			Password:   cfg.RedisPassword,
+			StreamName: "inventory-events",
+			MaxLen:     100000, // Retener últimos 100k eventos
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Redis publisher: %w", err)
+		}
+		log.Printf("✅ Using Redis Streams as message broker (%s)", addr)
+		return publisher, nil
+
+	case "nats":
+		// NATS JetStream (despliegues que ya operan NATS)
+		publisher, err := infrastructure.NewNATSPublisher(infrastructure.NATSPublisherConfig{
+			URL:             cfg.NATSURL,
+			Token:           cfg.NATSToken,
+			StreamName:      cfg.NATSStream,
+			SubjectTemplate: cfg.NATSSubjectTemplate,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create NATS publisher: %w", err)
+		}
+		log.Printf("✅ Using NATS JetStream as message broker (stream: %s)", cfg.NATSStream)
+		return publisher, nil
+
+	case "gcppubsub":
+		// Google Cloud Pub/Sub (broker gestionado)
+		publisher, err := infrastructure.NewPubSubPublisher(infrastructure.PubSubPublisherConfig{
+			ProjectID:       cfg.GCPProjectID,
+			Topic:           cfg.PubSubTopic,
+			CredentialsFile: cfg.GCPCredentialsFile,
+			Endpoint:        cfg.PubSubEndpoint,
+			EmulatorHost:    cfg.PubSubEmulatorHost,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Pub/Sub publisher: %w", err)
+		}
+		return publisher, nil
+
+	case "sns":
+		// AWS SNS (fan-out a colas SQS)
+		publisher, err := infrastructure.NewSNSPublisher(infrastructure.SNSPublisherConfig{
+			AWSCredentialsConfig: awsCredentials(cfg),
+			TopicARN:             cfg.SNSTopicARN,
+			Endpoint:             cfg.SNSEndpoint,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SNS publisher: %w", err)
+		}
+		return publisher, nil
+
+	case "sqs":
+		// AWS SQS (una única cola, sin fan-out)
+		publisher, err := infrastructure.NewSQSPublisher(infrastructure.SQSPublisherConfig{
+			AWSCredentialsConfig: awsCredentials(cfg),
+			QueueURL:             cfg.SQSQueueURL,
+			Endpoint:             cfg.SQSEndpoint,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SQS publisher: %w", err)
+		}
+		return publisher, nil
+
+	case "kafka":
+		// Implementación futura para Apache Kafka
+		return nil, fmt.Errorf("Kafka publisher not implemented yet. Set MESSAGE_BROKER=redis")
+
+	case "none", "":
+		// No publisher (solo logging)
+		log.Printf("⚠️  No message broker configured (MESSAGE_BROKER=none)")
+		return mocks.NewNoOpPublisher(), nil
+
+	default:
+		return nil, fmt.Errorf("unknown message broker: %s (options: redis, nats, gcppubsub, sns, sqs, kafka, none)", broker)
+	}
+}
+
+// awsCredentials región y credenciales AWS_* para los publishers de AWS
+func awsCredentials(cfg *config.Config) infrastructure.AWSCredentialsConfig {
+	return infrastructure.AWSCredentialsConfig{
+		Region:          cfg.AWSRegion,
+		AccessKeyID:     cfg.AWSAccessKeyID,
+		SecretAccessKey: cfg.AWSSecretAccessKey,
+		SessionToken:    cfg.AWSSessionToken,
+	}
+}
+
+// initializeAvailabilityCache crea el cache de disponibilidad si está habilitado (nil si no)
+func initializeAvailabilityCache(cfg *config.Config) (domain.AvailabilityCache, error) {
+	if !cfg.AvailabilityCacheEnabled {
+		return nil, nil
+	}
+
+	cache, err := infrastructure.NewRedisAvailabilityCache(infrastructure.RedisAvailabilityCacheConfig{
+		Addr:     fmt.Sprintf("%s:%d", cfg.RedisHost, cfg.RedisPort),
+		Password: cfg.RedisPassword,
+		TTL:      cfg.AvailabilityCacheTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// initializeSearchIndex crea el índice de búsqueda según SEARCH_BACKEND (nil con none)
+func initializeSearchIndex(cfg *config.Config) (domain.SearchIndex, error) {
+	switch cfg.SearchBackend {
+	case domain.SearchBackendElasticsearch:
+		return infrastructure.NewElasticsearchIndex(infrastructure.ElasticsearchIndexConfig{
+			URL:    cfg.SearchURL,
+			Index:  cfg.SearchIndex,
+			APIKey: cfg.SearchAPIKey,
+		})
+	case domain.SearchBackendMeilisearch:
+		return infrastructure.NewMeilisearchIndex(infrastructure.MeilisearchIndexConfig{
+			URL:    cfg.SearchURL,
+			Index:  cfg.SearchIndex,
+			APIKey: cfg.SearchAPIKey,
+		})
+	case domain.SearchBackendNone, "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown search backend: %s", cfg.SearchBackend)
+	}
+}
+
+// initializeBlobStore crea el almacenamiento de imágenes según MEDIA_STORAGE
+func initializeBlobStore(cfg *config.Config) (domain.BlobStore, error) {
+	switch cfg.MediaStorage {
+	case "s3":
+		return infrastructure.NewS3BlobStore(infrastructure.S3BlobStoreConfig{
+			AWSCredentialsConfig: awsCredentials(cfg),
+			Bucket:               cfg.MediaS3Bucket,
+			Endpoint:             cfg.MediaS3Endpoint,
+			UsePathStyle:         cfg.MediaS3PathStyle,
+			PublicBaseURL:        cfg.MediaPublicBaseURL,
+		})
+	case "local":
+		log.Printf("🖼️  Product media stored in %s (served at %s)", cfg.MediaLocalDir, cfg.MediaPublicBaseURL)
+		return infrastructure.NewLocalBlobStore(cfg.MediaLocalDir, cfg.MediaPublicBaseURL), nil
+	default:
+		return nil, fmt.Errorf("unknown media storage: %s", cfg.MediaStorage)
+	}
+}
+
+// initializeExportStore retorna el almacenamiento de las exportaciones. En local usan su propio
+// directorio (EXPORT_LOCAL_DIR), que no se sirve en /media; en S3 comparten el bucket de media
+// bajo el prefijo exports/. En ambos casos la descarga pasa por la API autenticada.
+func initializeExportStore(cfg *config.Config, mediaStore domain.BlobStore) domain.BlobStore {
+	if cfg.MediaStorage == "local" {
+		return infrastructure.NewLocalBlobStore(cfg.ExportLocalDir, "")
+	}
+	return mediaStore
+}
+
+// initializeArchiveStore retorna el almacenamiento de los ficheros archivados por la retención:
+// RETENTION_ARCHIVE_DIR en local y el bucket de media (prefijo archive/) en S3
+func initializeArchiveStore(cfg *config.Config, mediaStore domain.BlobStore) domain.BlobStore {
+	if cfg.MediaStorage == "local" {
+		return infrastructure.NewLocalBlobStore(cfg.RetentionArchiveDir, "")
+	}
+	return mediaStore
+}
+
+// initializeErrorReporter crea el reporter de panics según ERROR_REPORTER (nil con "none")
+func initializeErrorReporter(cfg *config.Config) (domain.ErrorReporter, error) {
+	switch cfg.ErrorReporter {
+	case "sentry":
+		reporter, err := infrastructure.NewSentryReporter(cfg.SentryDSN, cfg.Environment)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("🚨 Panics reported to Sentry")
+		return reporter, nil
+	case "webhook":
+		log.Printf("🚨 Panics reported to webhook")
+		return infrastructure.NewWebhookReporter(cfg.ErrorReporterWebhookURL), nil
+	default:
+		return nil, nil
+	}
+}
+
+// longRunningRoutes rutas sin timeout salvo que REQUEST_TIMEOUT_OVERRIDES diga lo contrario:
+// conexiones largas, descargas de ficheros y operaciones que recorren toda la base de datos
+var longRunningRoutes = []string{
+	"GET /api/v1/realtime/availability",
+	"GET /api/v1/reports/exports/:id/download",
+	"POST /api/v1/admin/backups",
+	"GET /api/v1/admin/backups/:name/download",
+	"GET /api/v1/admin/audit/verify",
+	"GET /api/v1/admin/events",
+	"POST /api/v1/admin/cleanup/:table",
+	"GET /api/v1/stock/movements",
+	"/api/v1/admin/debug/*",
+}
+
+// requestTimeoutPolicy construye los timeouts por ruta a partir de REQUEST_TIMEOUT_SECONDS y
+// REQUEST_TIMEOUT_OVERRIDES
+func requestTimeoutPolicy(cfg *config.Config) middleware.TimeoutPolicy {
+	overrides := make(map[string]time.Duration, len(longRunningRoutes)+len(cfg.RequestTimeoutOverrides))
+	for _, route := range longRunningRoutes {
+		overrides[route] = 0
+	}
+	for pattern, timeout := range cfg.RequestTimeoutOverrides {
+		overrides[pattern] = timeout
+	}
+	return middleware.TimeoutPolicy{Default: cfg.RequestTimeout, Overrides: overrides}
+}
+
+// latencyBudgetPolicy construye los presupuestos de latencia por ruta a partir de
+// LATENCY_BUDGET_MS y LATENCY_BUDGET_OVERRIDES. Las rutas de larga duración no tienen presupuesto.
+func latencyBudgetPolicy(cfg *config.Config) middleware.TimeoutPolicy {
+	overrides := make(map[string]time.Duration, len(longRunningRoutes)+len(cfg.LatencyBudgetOverrides))
+	for _, route := range longRunningRoutes {
+		overrides[route] = 0
+	}
+	for pattern, budget := range cfg.LatencyBudgetOverrides {
+		overrides[pattern] = budget
+	}
+	return middleware.TimeoutPolicy{Default: cfg.LatencyBudget, Overrides: overrides}
+}
+
+// localePolicy construye los idiomas del catálogo a partir de PRODUCT_LOCALES
+func localePolicy(cfg *config.Config) domain.LocalePolicy {
+	if len(cfg.ProductLocales) == 0 {
+		return domain.DefaultLocalePolicy()
+	}
+	return domain.LocalePolicy{
+		Default:   cfg.ProductLocales[0],
+		Supported: cfg.ProductLocales,
+	}
+}
+
+// featureFlagRules construye las reglas de feature flags a partir de FEATURE_FLAGS
+func featureFlagRules(cfg *config.Config) ([]*domain.FeatureFlagRule, error) {
+	rules := make([]*domain.FeatureFlagRule, 0, len(cfg.FeatureFlags))
+	for key, enabled := range cfg.FeatureFlags {
+		feature, storeID, _ := strings.Cut(key, "@")
+		if !domain.Feature(feature).IsValid() {
+			return nil, fmt.Errorf("FEATURE_FLAGS: unknown feature %q", feature)
+		}
+		rules = append(rules, &domain.FeatureFlagRule{
+			Feature: domain.Feature(feature),
+			StoreID: storeID,
+			Enabled: enabled,
+			Source:  domain.FeatureFlagSourceConfig,
+		})
+	}
+	return rules, nil
+}
+
+// skuPolicy construye la política de SKUs a partir de la configuración
+func skuPolicy(cfg *config.Config) (domain.SKUPolicy, error) {
+	pattern, err := regexp.Compile(cfg.SKUPattern)
+	if err != nil {
+		return domain.SKUPolicy{}, fmt.Errorf("invalid SKU_PATTERN: %w", err)
+	}
+	return domain.SKUPolicy{
+		Pattern:   pattern,
+		MaxLength: cfg.SKUMaxLength,
+		Uppercase: cfg.SKUUppercase,
+	}, nil
+}
+
+// reservationTTLPolicy construye la política de TTL de reservas a partir de la configuración
+func reservationTTLPolicy(cfg *config.Config) domain.ReservationTTLPolicy {
+	policy := domain.ReservationTTLPolicy{
+		ReservationTTLBounds: domain.ReservationTTLBounds{
+			DefaultMinutes: cfg.ReservationDefaultTTL,
+			MaxMinutes:     cfg.ReservationMaxTTL,
+		},
+		StoreOverrides: make(map[string]domain.ReservationTTLBounds, len(cfg.ReservationTTLOverrides)),
+	}
+	for storeID, bounds := range cfg.ReservationTTLOverrides {
+		policy.StoreOverrides[storeID] = domain.ReservationTTLBounds{
+			DefaultMinutes: bounds.DefaultMinutes,
+			MaxMinutes:     bounds.MaxMinutes,
+		}
+	}
+	return policy
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"inventory-system/internal/config"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// NewServer crea el http.Server que sirve handler con los timeouts, TLS y HTTP/2 de la
+// configuración. Con TLS el servidor tiene TLSConfig y se arranca con ListenAndServeTLS("", "").
+// Con autocert los certificados se obtienen de Let's Encrypt (challenge TLS-ALPN-01), por lo que
+// SERVER_PORT debe ser accesible desde internet como puerto 443.
+func NewServer(cfg *config.Config, handler http.Handler) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:              ":" + cfg.ServerPort,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+
+	switch {
+	case cfg.TLSCertFile != "":
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		srv.TLSConfig = &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		}
+	case len(cfg.TLSAutocertDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLSAutocertDomains...),
+			Cache:      autocert.DirCache(cfg.TLSAutocertCacheDir),
+			Email:      cfg.TLSAutocertEmail,
+		}
+		srv.TLSConfig = manager.TLSConfig()
+		srv.TLSConfig.MinVersion = tls.VersionTLS12
+		if !cfg.HTTP2Enabled {
+			// El manager anuncia h2 por ALPN; sin HTTP/2 el cliente no debe negociarlo
+			srv.TLSConfig.NextProtos = slices.DeleteFunc(srv.TLSConfig.NextProtos, func(proto string) bool { return proto == "h2" })
+		}
+	}
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	if srv.TLSConfig != nil {
+		protocols.SetHTTP2(cfg.HTTP2Enabled)
+	} else {
+		// Sin TLS, HTTP/2 solo con prior knowledge (h2c), p. ej. detrás de un proxy que lo use
+		protocols.SetUnencryptedHTTP2(cfg.HTTP2Enabled)
+	}
+	srv.Protocols = protocols
+
+	return srv, nil
+}
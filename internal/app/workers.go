@@ -0,0 +1,531 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/config"
+	"inventory-system/internal/service"
+)
+
+// StartWorkers arranca los workers de background. Todos terminan cuando se
+// cancela ctx, de modo que un test puede levantar la aplicación completa y
+// detenerla sin dejar goroutines vivas.
+func (a *App) StartWorkers(ctx context.Context) {
+	// Worker para expirar reservas (cada 1 minuto)
+	go startReservationExpirationWorker(ctx, a.ReservationService)
+
+	// Worker para purgar intenciones de reserva caducadas (cada 1 hora)
+	go startReservationIntentWorker(ctx, a.IntentService)
+
+	// Worker para purgar las Idempotency-Key caducadas (cada 1 hora)
+	go startIdempotencyKeyWorker(ctx, a.IdempotencyService)
+
+	// Worker para sincronizar eventos (cada 10 segundos)
+	go startEventSyncWorker(ctx, a.EventSyncService)
+
+	// Worker para persistir el uso de API keys y alertar keys sin uso
+	go startAPIKeyUsageWorker(ctx, a.APIKeyUsageService)
+
+	// Worker para archivar tiendas que agotaron el stock de productos descatalogados (cada 5 minutos)
+	go startRunDownWorker(ctx, a.RunDownService)
+
+	// Worker para aplicar cambios de precio programados (cada 1 minuto)
+	go startScheduledPriceWorker(ctx, a.PriceService)
+
+	// Worker para aplicar cambios de stock programados (cada 1 minuto)
+	go startScheduledStockWorker(ctx, a.StockScheduleService)
+
+	// Worker para purgar los tickets de flash sale resueltos (cada 1 minuto)
+	go startFlashSaleTicketWorker(ctx, a.FlashSaleService)
+
+	// Worker para alertar las tiendas que llevan más de STORE_SYNC_STALE_MINUTES sin sincronizar (cada 1 minuto)
+	go startStoreSyncAlertWorker(ctx, a.StoreHeartbeatService)
+
+	// Worker para avisar a los clientes de las reservas a punto de caducar (cada 1 minuto)
+	go startReservationExpiringWorker(ctx, a.NotificationService)
+
+	// Worker para generar exportaciones y purgar las expiradas (cada 10 segundos)
+	go startExportWorker(ctx, a.ExportService)
+
+	// Worker para borrar (y archivar) los datos que superan su ventana de retención (cada hora)
+	if a.RetentionService.Enabled() {
+		go startRetentionWorker(ctx, a.RetentionService)
+	}
+
+	// Worker para recalcular la clasificación ABC de productos (cada 24 horas)
+	if a.ABCService.Enabled() {
+		go startABCClassificationWorker(ctx, a.ABCService)
+	}
+
+	// Worker para verificar el stock contra el ledger de movimientos (cada LEDGER_VERIFY_INTERVAL_HOURS)
+	if a.Config.LedgerVerifyInterval > 0 {
+		go startLedgerVerificationWorker(ctx, a.LedgerService, a.Config.LedgerVerifyInterval)
+	}
+
+	// Worker para generar backups de la base de datos (cada BACKUP_INTERVAL_HOURS)
+	if a.Config.BackupInterval > 0 {
+		go startBackupWorker(ctx, a.BackupService, a.Config.BackupInterval)
+	}
+
+	// Worker para aplicar rotaciones de API keys (API_KEYS_FILE o secret provider)
+	if a.Config.APIKeysReloadable() {
+		go startAPIKeyReloadWorker(ctx, a.Config, a.KeyRing)
+	}
+}
+
+// startReservationExpirationWorker worker para expirar reservas
+func startReservationExpirationWorker(ctx context.Context, service *service.ReservationService) {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	log.Println("⏰ Reservation expiration worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		count, err := service.ProcessExpiredReservations(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error processing expired reservations: %v", err)
+		} else if count > 0 {
+			log.Printf("✅ Expired %d reservations", count)
+		}
+	}
+}
+
+// startReservationIntentWorker worker para eliminar las intenciones de reserva caducadas
+// hace más de 24 horas
+func startReservationIntentWorker(ctx context.Context, service *service.ReservationIntentService) {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		count, err := service.PurgeExpiredIntents(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error purging reservation intents: %v", err)
+		} else if count > 0 {
+			log.Printf("🧹 Purged %d expired reservation intents", count)
+		}
+	}
+}
+
+// startIdempotencyKeyWorker worker para eliminar las Idempotency-Key registradas hace más de 24 horas
+func startIdempotencyKeyWorker(ctx context.Context, service *service.IdempotencyService) {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		count, err := service.PurgeExpiredKeys(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error purging idempotency keys: %v", err)
+		} else if count > 0 {
+			log.Printf("🧹 Purged %d expired idempotency keys", count)
+		}
+	}
+}
+
+// startEventSyncWorker worker para sincronizar eventos
+func startEventSyncWorker(ctx context.Context, service *service.EventSyncService) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	log.Println("📡 Event synchronization worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		count, err := service.SyncPendingEvents(runCtx, 100)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error syncing events: %v", err)
+		} else if count > 0 {
+			log.Printf("✅ Synced %d events", count)
+		}
+	}
+}
+
+// startAPIKeyUsageWorker worker para persistir el uso de API keys (cada 30 segundos)
+// y alertar sobre keys sin uso durante 90 días (cada 24 horas)
+func startAPIKeyUsageWorker(ctx context.Context, service *service.APIKeyUsageService) {
+	flushTicker := time.NewTicker(30 * time.Second)
+	defer flushTicker.Stop()
+	auditTicker := time.NewTicker(24 * time.Hour)
+	defer auditTicker.Stop()
+
+	log.Println("🔑 API key usage worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-flushTicker.C:
+			runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			if _, err := service.Flush(runCtx); err != nil {
+				log.Printf("Error flushing API key usage: %v", err)
+			}
+			cancel()
+
+		case <-auditTicker.C:
+			runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			unused, err := service.FindUnusedKeys(runCtx)
+			cancel()
+
+			if err != nil {
+				log.Printf("Error checking unused API keys: %v", err)
+				continue
+			}
+			for _, key := range unused {
+				log.Printf("⚠️  API key %s (%s) unused for 90+ days, candidate for revocation", key.KeyID, key.Name)
+			}
+		}
+	}
+}
+
+// startRunDownWorker worker para avanzar los planes de run-down de productos descatalogados
+func startRunDownWorker(ctx context.Context, service *service.RunDownService) {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	log.Println("📉 Product run-down worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		count, err := service.ProcessRunDowns(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error processing product run-downs: %v", err)
+		} else if count > 0 {
+			log.Printf("✅ Archived %d assortment entries of discontinued products", count)
+		}
+	}
+}
+
+// startScheduledPriceWorker worker para aplicar los cambios de precio cuyo effective_at ya llegó
+func startScheduledPriceWorker(ctx context.Context, service *service.PriceService) {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	log.Println("💲 Scheduled price worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		count, err := service.ProcessScheduledPriceChanges(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error applying scheduled price changes: %v", err)
+		} else if count > 0 {
+			log.Printf("✅ Applied %d scheduled price changes", count)
+		}
+	}
+}
+
+// startScheduledStockWorker worker para aplicar los cambios de stock cuyo effective_at ya llegó
+func startScheduledStockWorker(ctx context.Context, service *service.StockScheduleService) {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	log.Println("📦 Scheduled stock worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		count, err := service.ProcessScheduledStockChanges(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error applying scheduled stock changes: %v", err)
+		} else if count > 0 {
+			log.Printf("✅ Applied %d scheduled stock changes", count)
+		}
+	}
+}
+
+// startFlashSaleTicketWorker worker para liberar los tickets de reservas encoladas ya consultables
+// durante FLASH_SALE_TICKET_TTL_MINUTES
+func startFlashSaleTicketWorker(ctx context.Context, service *service.FlashSaleService) {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if purged := service.PurgeExpiredTickets(now); purged > 0 {
+				log.Printf("🧹 Purged %d resolved flash sale tickets", purged)
+			}
+		}
+	}
+}
+
+// startStoreSyncAlertWorker worker para alertar (store.sync_stale) las instancias edge atrasadas
+func startStoreSyncAlertWorker(ctx context.Context, service *service.StoreHeartbeatService) {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		if _, err := service.AlertStaleStores(runCtx); err != nil {
+			log.Printf("Error checking store sync status: %v", err)
+		}
+		cancel()
+	}
+}
+
+// startReservationExpiringWorker worker para avisar (RESERVATION_EXPIRING) de las reservas
+// pendientes que caducan dentro de la antelación de su tienda
+func startReservationExpiringWorker(ctx context.Context, service *service.NotificationService) {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		count, err := service.NotifyExpiring(runCtx, time.Now())
+		cancel()
+
+		if err != nil {
+			log.Printf("Error notifying expiring reservations: %v", err)
+		} else if count > 0 {
+			log.Printf("✉️  Notified %d expiring reservations", count)
+		}
+	}
+}
+
+// startExportWorker worker para generar las exportaciones pendientes y borrar los ficheros
+// cuya retención terminó
+func startExportWorker(ctx context.Context, service *service.ExportService) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	log.Println("📤 Export worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// La generación tiene su propio límite (exportJobTimeout) por job
+		completed, err := service.ProcessPendingExports(ctx)
+		if err != nil {
+			log.Printf("Error processing exports: %v", err)
+		} else if completed > 0 {
+			log.Printf("✅ Completed %d exports", completed)
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		purged, err := service.PurgeExpiredExports(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error purging expired exports: %v", err)
+		} else if purged > 0 {
+			log.Printf("🧹 Purged %d expired exports", purged)
+		}
+	}
+}
+
+// startAPIKeyReloadWorker worker para recargar las API keys rotadas sin reiniciar
+// (cada SECRETS_REFRESH_SECONDS). Si la recarga falla se mantienen las keys actuales.
+func startAPIKeyReloadWorker(ctx context.Context, cfg *config.Config, keyRing *auth.KeyRing) {
+	ticker := time.NewTicker(cfg.SecretsRefreshInterval)
+	defer ticker.Stop()
+
+	log.Printf("🔐 API key reload worker started (every %s)", cfg.SecretsRefreshInterval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		keys, err := cfg.ReloadAPIKeys(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error reloading API keys: %v", err)
+			continue
+		}
+
+		if added, removed := keyRing.ReplaceStatic(keys); added > 0 || removed > 0 {
+			log.Printf("✅ API keys rotated: %d added, %d revoked", added, removed)
+		}
+	}
+}
+
+// startRetentionWorker worker para aplicar las ventanas de retención de eventos y reservas
+func startRetentionWorker(ctx context.Context, service *service.RetentionService) {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	log.Println("🧹 Retention worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Margen amplio: la primera ejecución puede recorrer un histórico grande
+		runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+		deleted, err := service.ApplyRetention(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error applying retention: %v", err)
+		}
+		for table, count := range deleted {
+			if count > 0 {
+				log.Printf("🧹 Retention deleted %d %s", count, table)
+			}
+		}
+	}
+}
+
+// startABCClassificationWorker worker para recalcular la clase ABC de los productos
+func startABCClassificationWorker(ctx context.Context, service *service.ABCClassificationService) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	log.Println("🔤 ABC classification worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		run, err := service.Classify(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error classifying products: %v", err)
+		} else {
+			log.Printf("🔤 Classified %d products (A=%d, B=%d, C=%d)", run.Products, run.ClassA, run.ClassB, run.ClassC)
+		}
+	}
+}
+
+// startLedgerVerificationWorker worker para detectar filas de stock que no cuadran con sus movimientos
+func startLedgerVerificationWorker(ctx context.Context, service *service.LedgerVerificationService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("📒 Stock ledger verification worker started (every %v)", interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+		result, err := service.Verify(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error verifying stock ledger: %v", err)
+		} else {
+			log.Printf("📒 Stock ledger verified: %d rows, %d unverified, %d mismatches (%s)",
+				result.Verified, result.Unverified, len(result.Mismatches), result.Duration)
+		}
+	}
+}
+
+// startBackupWorker worker para generar backups periódicos de la base de datos
+func startBackupWorker(ctx context.Context, service *service.BackupService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("💾 Backup worker started (every %v)", interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+		backup, err := service.CreateBackup(runCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Error creating backup: %v", err)
+		} else {
+			log.Printf("💾 Backup %s created (%d bytes)", backup.Name, backup.SizeBytes)
+		}
+	}
+}
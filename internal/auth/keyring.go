@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// AllStores marca en API_KEY_STORE_SCOPES una key de configuración como multi-tienda (Admin:*)
+const AllStores = "*"
+
+// KeyRing mantiene el conjunto de API keys válidas.
+// Las keys se indexan por su hash SHA-256 para poder cargar keys persistidas
+// (creadas en runtime, p.ej. durante el onboarding de una tienda) sin guardarlas en claro.
+//
+// Cada key puede estar limitada a un conjunto de tiendas (store keys): las emitidas para una
+// tienda lo están a la suya y las de configuración según API_KEY_STORE_SCOPES (por nombre).
+// Con scopes configurados, una key sin entrada no puede escribir en ninguna tienda salvo que se
+// marque como multi-tienda (AllStores). Sin ningún scope configurado todas son multi-tienda.
+type KeyRing struct {
+	mu         sync.RWMutex
+	names      map[string]string   // hash -> nombre
+	static     map[string]struct{} // hashes que vienen de configuración (rotables)
+	stores     map[string][]string // hash -> tiendas (keys emitidas para una tienda)
+	nameScopes map[string][]string // nombre -> tiendas (keys de configuración)
+}
+
+// NewKeyRing crea un keyring con las keys estáticas de configuración (key -> nombre)
+func NewKeyRing(keys map[string]string) *KeyRing {
+	ring := &KeyRing{
+		names:      make(map[string]string, len(keys)),
+		static:     make(map[string]struct{}, len(keys)),
+		stores:     make(map[string][]string),
+		nameScopes: make(map[string][]string),
+	}
+	for key, name := range keys {
+		hash := HashKey(key)
+		ring.names[hash] = name
+		ring.static[hash] = struct{}{}
+	}
+	return ring
+}
+
+// HashKey calcula el hash SHA-256 (hex) de una API key
+func HashKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateKey genera una nueva API key aleatoria con el prefijo indicado
+func GenerateKey(prefix string) (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
+
+// Lookup valida una API key y retorna el nombre asociado
+func (k *KeyRing) Lookup(apiKey string) (string, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	name, ok := k.names[HashKey(apiKey)]
+	return name, ok
+}
+
+// StoreScope retorna las tiendas en las que puede escribir la key (nil = multi-tienda, vacío =
+// ninguna)
+func (k *KeyRing) StoreScope(apiKey string) []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	hash := HashKey(apiKey)
+	if stores, ok := k.stores[hash]; ok {
+		return stores
+	}
+	stores, ok := k.nameScopes[k.names[hash]]
+	switch {
+	case ok && len(stores) == 1 && stores[0] == AllStores:
+		return nil
+	case ok:
+		return stores
+	case len(k.nameScopes) > 0:
+		return []string{}
+	}
+	return nil
+}
+
+// UnscopedNames retorna los nombres de las keys sin tiendas asignadas ni entrada en los scopes
+// (ordenados), para avisar al arrancar: según StoreScope son multi-tienda o no escriben en ninguna
+func (k *KeyRing) UnscopedNames() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var names []string
+	for hash, name := range k.names {
+		if _, ok := k.stores[hash]; ok {
+			continue
+		}
+		if _, ok := k.nameScopes[name]; ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddHash registra una key a partir de su hash (keys persistidas en base de datos),
+// limitada a stores si se indican
+func (k *KeyRing) AddHash(hash, name string, stores ...string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.names[hash] = name
+	if len(stores) > 0 {
+		k.stores[hash] = stores
+	}
+}
+
+// SetStoreScopes limita las keys de configuración a las tiendas de su nombre (nombre -> tiendas).
+// Se aplica también a las keys que se añadan después con ReplaceStatic.
+func (k *KeyRing) SetStoreScopes(scopes map[string][]string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.nameScopes = scopes
+}
+
+// ReplaceStatic sustituye las keys de configuración por un nuevo conjunto (rotación
+// en caliente). Las keys emitidas en runtime (AddHash) no se tocan.
+// Retorna cuántas keys se añadieron y cuántas se revocaron.
+func (k *KeyRing) ReplaceStatic(keys map[string]string) (added, removed int) {
+	next := make(map[string]string, len(keys))
+	for key, name := range keys {
+		next[HashKey(key)] = name
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for hash := range k.static {
+		if _, ok := next[hash]; !ok {
+			delete(k.names, hash)
+			delete(k.static, hash)
+			removed++
+		}
+	}
+	for hash, name := range next {
+		if _, ok := k.static[hash]; !ok {
+			added++
+		}
+		k.names[hash] = name
+		k.static[hash] = struct{}{}
+	}
+
+	return added, removed
+}
+
+// IDs retorna los identificadores públicos de las keys (ver domain.APIKeyID) con su nombre
+func (k *KeyRing) IDs() map[string]string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	ids := make(map[string]string, len(k.names))
+	for hash, name := range k.names {
+		ids[hash[:12]] = name
+	}
+	return ids
+}
+
+// Len retorna la cantidad de keys registradas
+func (k *KeyRing) Len() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return len(k.names)
+}
@@ -1,80 +1,511 @@
 package config
 
 import (
-	"os"
+	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// TTLBounds define el TTL por defecto y máximo (minutos) de las reservas de una tienda.
+// Un valor en 0 hereda la configuración global.
+type TTLBounds struct {
+	DefaultMinutes int
+	MaxMinutes     int
+}
+
 type Config struct {
+	// Entorno de despliegue: development, staging, production.
+	// En production no se cargan las API keys de desarrollo por defecto.
+	Environment string
+
 	// Server
 	ServerPort string
 	InstanceID string // Identificador de esta instancia de API (para logs/métricas)
 
+	// TLS: certificado propio (TLSCertFile/TLSKeyFile) o Let's Encrypt para TLSAutocertDomains.
+	// Sin ninguno de los dos el servidor escucha en HTTP plano (detrás de un proxy)
+	TLSCertFile         string
+	TLSKeyFile          string
+	TLSAutocertDomains  []string
+	TLSAutocertCacheDir string
+	TLSAutocertEmail    string
+
+	// Timeouts del servidor HTTP (0 = sin límite). HTTP2Enabled activa h2 con TLS y h2c sin TLS
+	HTTPReadHeaderTimeout time.Duration
+	HTTPReadTimeout       time.Duration
+	HTTPWriteTimeout      time.Duration
+	HTTPIdleTimeout       time.Duration
+	HTTP2Enabled          bool
+
+	// Reporte de panics: "sentry" (SentryDSN), "webhook" (ErrorReporterWebhookURL) o "none".
+	// Los panics siempre se registran en el log con su stack
+	ErrorReporter           string
+	SentryDSN               string // Secreto: admite SENTRY_DSN_FILE y secret provider
+	ErrorReporterWebhookURL string // Secreto: admite ERROR_REPORTER_WEBHOOK_URL_FILE y secret provider
+
+	// Diagnóstico: pprof, expvar y estadísticas de runtime. Con DebugAddr se sirven en un puerto
+	// aparte sin API key (p. ej. "127.0.0.1:6060"); sin él, en /api/v1/admin/debug con API key
+	DebugEnabled bool
+	DebugAddr    string
+
+	// Timeout de cada request (cancela el context: consultas y espera de conexiones a la BD).
+	// RequestTimeoutOverrides por ruta: "[MÉTODO ]ruta" o prefijo con "*" → timeout (0 = sin límite)
+	RequestTimeout          time.Duration
+	RequestTimeoutOverrides map[string]time.Duration
+
+	// Presupuesto de latencia (SLA) de cada request y overrides por ruta con los mismos patrones que
+	// RequestTimeoutOverrides (0 = sin presupuesto). LatencyWindow: ventana móvil de /admin/latency
+	LatencyBudget          time.Duration
+	LatencyBudgetOverrides map[string]time.Duration
+	LatencyWindow          time.Duration
+
+	// Ventana móvil de /admin/contention: escrituras de stock y conflictos de versión por fila
+	ContentionWindow time.Duration
+
+	// Límites de entrada: tamaño máximo de los bodies JSON y del parámetro ?limit= de los listados
+	MaxRequestBodyKB int
+	MaxListLimit     int
+
+	// Compresión gzip/brotli de las respuestas (según Accept-Encoding) a partir de CompressionMinBytes
+	CompressionEnabled  bool
+	CompressionMinBytes int
+
 	// Database
 	DatabaseDriver string // "sqlite" únicamente
 	SQLitePath     string // Para SQLite: ":memory:" o ruta a archivo
 
+	// Backups de SQLite (VACUUM INTO) en BackupDir: cada BackupInterval (0 = solo bajo demanda),
+	// conservando los BackupRetain más recientes
+	BackupDir      string
+	BackupInterval time.Duration
+	BackupRetain   int
+
 	// Redis
-	RedisHost string
-	RedisPort int
+	RedisHost     string
+	RedisPort     int
+	RedisPassword string // Secreto: admite REDIS_PASSWORD_FILE y secret provider
 
 	// Message Broker
-	MessageBroker string // "redis", "kafka", "none"
+	MessageBroker string // "redis", "nats", "gcppubsub", "sns", "sqs", "kafka", "none"
 
 	// Message broker URLs (configuración específica por broker)
 
 	// Kafka (si MESSAGE_BROKER=kafka)
 	KafkaBrokers string // "localhost:9092,localhost:9093"
 
+	// NATS JetStream (si MESSAGE_BROKER=nats)
+	NATSURL             string // "nats://localhost:4222" (tls:// para TLS)
+	NATSToken           string // Secreto: admite NATS_TOKEN_FILE y secret provider
+	NATSStream          string // Stream JetStream que se crea si no existe
+	NATSSubjectTemplate string // Placeholders: {store_id}, {event_type}, {aggregate_type}
+
+	// Google Cloud Pub/Sub (si MESSAGE_BROKER=gcppubsub)
+	GCPProjectID       string
+	PubSubTopic        string
+	GCPCredentialsFile string // GOOGLE_APPLICATION_CREDENTIALS; vacío = servidor de metadatos
+	PubSubEndpoint     string // Opcional: endpoint regional
+	PubSubEmulatorHost string // Opcional: emulador local, sin autenticación
+
+	// AWS SNS/SQS (si MESSAGE_BROKER=sns o sqs). Las credenciales AWS_* se comparten con
+	// SECRETS_PROVIDER=aws; sin access key se usa la cadena de credenciales del SDK
+	SNSTopicARN        string
+	SNSEndpoint        string // Opcional (LocalStack, VPC endpoint)
+	SQSQueueURL        string
+	SQSEndpoint        string // Opcional (LocalStack, VPC endpoint)
+	AWSRegion          string // Vacío = región del ARN del topic o de la URL de la cola
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string // Secreto: admite AWS_SECRET_ACCESS_KEY_FILE
+	AWSSessionToken    string // Secreto: admite AWS_SESSION_TOKEN_FILE
+
+	// Cache de disponibilidad en Redis (usa REDIS_HOST/REDIS_PORT/REDIS_PASSWORD).
+	// La BD sigue siendo la fuente de verdad; el TTL acota la vida de cada contador
+	AvailabilityCacheEnabled bool
+	AvailabilityCacheTTL     time.Duration
+
+	// Búsqueda de productos en un índice externo (none, elasticsearch, meilisearch) que se
+	// mantiene con los eventos; con none GET /products/search consulta la BD
+	SearchBackend string
+	SearchURL     string
+	SearchIndex   string
+	SearchAPIKey  string // Secreto: admite SEARCH_API_KEY_FILE
+
+	// Imágenes de productos: MEDIA_STORAGE=local (disco, servido en MEDIA_PUBLIC_BASE_URL)
+	// o s3 (usa AWS_REGION y las credenciales AWS_*)
+	MediaStorage       string
+	MediaLocalDir      string
+	MediaPublicBaseURL string // local: ruta servida por la API; s3: opcional (CDN)
+	MediaMaxUploadMB   int
+	MediaS3Bucket      string
+	MediaS3Endpoint    string // Opcional (MinIO, LocalStack)
+	MediaS3PathStyle   bool   // Bucket en la ruta (<endpoint>/<bucket>); por defecto solo con MEDIA_S3_ENDPOINT
+
+	// Exportaciones asíncronas: mismo backend que MEDIA_STORAGE (con s3, prefijo exports/ del
+	// bucket de media; en local, un directorio propio que no se sirve en /media)
+	ExportLocalDir  string
+	ExportRetention time.Duration // Tiempo durante el que se puede descargar el fichero
+
+	// Retención de datos históricos (0 = conservar siempre). Con RetentionArchive las filas se
+	// guardan como JSONL comprimido antes de borrarse: en RetentionArchiveDir con MEDIA_STORAGE=local
+	// o bajo el prefijo archive/ del bucket de media con s3
+	RetentionEvents       time.Duration // Eventos sincronizados (también el log de auditoría)
+	RetentionReservations time.Duration // Reservas terminadas (CONFIRMED, CANCELLED, EXPIRED)
+	RetentionArchive      bool
+	RetentionArchiveDir   string
+
+	// Limpiezas bajo demanda (POST /api/v1/admin/cleanup/:table): tamaño de lote máximo y pausa
+	// entre lotes para no acaparar la BD en producción
+	CleanupMaxBatchSize int
+	CleanupBatchPause   time.Duration
+
+	// Clasificación ABC de productos por valor de consumo (unidades confirmadas × precio) en la
+	// ventana ABCWindow; un worker la recalcula cada día (0 = desactivada)
+	ABCWindow time.Duration
+
+	// Verificación de stock.quantity contra el ledger de movimientos (tabla events) cada
+	// LedgerVerifyInterval (0 = solo bajo demanda)
+	LedgerVerifyInterval time.Duration
+
+	// Idiomas del catálogo: el primero es el de name/description, el resto se traducen
+	ProductLocales []string
+
+	// Feature flags (FEATURE_FLAGS): "feature" o "feature@store_id" -> activada. Las
+	// funcionalidades sin regla están activadas; /admin/feature-flags prevalece sobre esto.
+	FeatureFlags map[string]bool
+
+	// Circuit breaker del publisher: errores consecutivos que lo abren y segundos
+	// abierto antes de volver a probar el broker
+	PublisherBreakerFailures    int
+	PublisherBreakerOpenTimeout time.Duration
+
 	// Business
-	ReservationTTL int // segundos
+	ReservationDefaultTTL   int                  // minutos, aplicado cuando ttl_minutes se omite
+	ReservationMaxTTL       int                  // minutos
+	ReservationTTLOverrides map[string]TTLBounds // store_id -> TTL por defecto/máximo
+
+	// Anti-acaparamiento por cliente (0 = sin límite)
+	ReservationMaxUnitsPerCustomer   int // Unidades de un producto en reservas pendientes, todas las tiendas
+	ReservationMaxPendingPerCustomer int // Reservas pendientes simultáneas por cliente
+
+	// Hook de confirmación de reservas (vacío = desactivado): POST de cada confirmación a
+	// ConfirmHookURL. En modo sync se llama antes de descontar el stock y un fallo deja la
+	// reserva PENDING; en async se llama en segundo plano una vez confirmada
+	ConfirmHookURL         string // Secreto: admite CONFIRM_HOOK_URL_FILE y secret provider
+	ConfirmHookSecret      string // Secreto: admite CONFIRM_HOOK_SECRET_FILE; firma HMAC-SHA256 del cuerpo
+	ConfirmHookMode        string // sync, async
+	ConfirmHookTimeout     time.Duration
+	ConfirmHookMaxAttempts int
+	ConfirmHookBackoff     time.Duration // Espera antes del primer reintento (se duplica en cada uno)
+
+	// Aprobación de ajustes manuales de stock (0 = desactivado): los que superan alguno de los
+	// umbrales quedan pendientes hasta que otra API key los aprueba
+	AdjustmentApprovalMaxUnits   int     // Unidades sumadas o restadas
+	AdjustmentApprovalMaxPercent float64 // Porcentaje de la cantidad actual
+
+	// Intenciones de reserva (add-to-cart sin bloquear stock): vigencia del token
+	ReservationIntentTTL time.Duration
+
+	// Prioridad de reservas: antigüedad mínima de las reservas LOW que el worker de expiración
+	// libera cuando el producto se queda sin disponibilidad (0 = desactivado)
+	ReservationShortageGrace time.Duration
+
+	// Recogida en tienda: plazo desde que la reserva está lista para recoger hasta que caduca
+	ReservationPickupWindow time.Duration
+
+	// Flash sale: cola de reservas para productos en modo alta contención
+	FlashSaleQueueSize int           // Peticiones pendientes por (producto, tienda)
+	FlashSaleTicketTTL time.Duration // Retención de los tickets resueltos
+
+	// Latidos de las instancias edge: una tienda que lleva más de StoreSyncStaleAfter sin
+	// sincronizar se marca como atrasada en /admin/stores/status y se alerta (store.sync_stale)
+	StoreSyncStaleAfter time.Duration
+
+	// Avisos de reservas a clientes por email (vacío = sin email): cada tienda activa los avisos
+	// y sus canales en /admin/stores/{id}/notifications
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string // Secreto: admite SMTP_PASSWORD_FILE y secret provider
+	SMTPFrom     string // Remitente por defecto
+	SMTPTimeout  time.Duration
 
 	// Security (API Key Authentication)
 	APIKeys           map[string]string // key -> store_name
 	RateLimitRequests int               // requests per minute
 
+	// APIKeyStoreScopes limita las keys de configuración (por nombre) a las tiendas en las que
+	// pueden escribir ("*" = multi-tienda). Si hay alguna entrada, las keys sin entrada no
+	// escriben en ninguna tienda; sin ninguna, todas son multi-tienda (con aviso al arrancar).
+	APIKeyStoreScopes map[string][]string
+
+	// Secretos: provider externo (vault, aws, none) y cada cuánto se releen las
+	// API keys rotadas (API_KEYS_FILE o provider). 0 desactiva la recarga
+	SecretsProvider        string
+	SecretsRefreshInterval time.Duration
+
+	// API versioning
+	APIV1Sunset time.Time // Fecha de retiro de /api/v1 (header Sunset). Zero = sin fecha
+
+	// API docs
+	SwaggerEnabled bool // Servir /swagger/* y /openapi.json
+
+	// Sandbox (integradores): BD en memoria con datos sintéticos, sin broker
+	SandboxMode          bool
+	SandboxSeed          int64         // Seed de datos y de la simulación (determinista)
+	SandboxProducts      int           // Productos sintéticos adicionales
+	SandboxLatency       time.Duration // Latencia base simulada en /api
+	SandboxLatencyJitter time.Duration // Latencia aleatoria adicional máxima
+	SandboxErrorRate     float64       // Probabilidad (0-1) de responder 503
+
+	// Catálogo: normalización y validación de SKUs
+	SKUPattern   string // Expresión regular que debe cumplir el SKU tras normalizar (default = domain.DefaultSKUPattern)
+	SKUMaxLength int
+	SKUUppercase bool // Normalizar SKUs a mayúsculas
+
 	// Observability
 	LogLevel      string // debug, info, warn, error
 	LogFormat     string // json, text
 	EnableMetrics bool
+
+	// Exporter de stock bajo (/metrics/stock)
+	MetricsLowStockThreshold int // Disponibilidad por debajo de la cual se exporta la fila
+	MetricsLowStockTopN      int // Máximo de series por producto/tienda en cada scrape
+
+	// Errores de parseo (valores no numéricos, claves desconocidas del archivo...)
+	// acumulados durante la carga; Validate los reporta junto al resto
+	loadErrors []error
+
+	// Fuentes de la carga, conservadas para recargar las API keys en caliente
+	src *source
 }
 
+// Load carga la configuración desde variables de entorno (y .env si existe)
 func Load() *Config {
 	// Cargar .env si existe (ignora error si no existe)
 	_ = godotenv.Load()
 
-	redisPort, _ := strconv.Atoi(getEnv("REDIS_PORT", "6379"))
-	reservationTTL, _ := strconv.Atoi(getEnv("RESERVATION_TTL", "600"))
-	rateLimitRequests, _ := strconv.Atoi(getEnv("RATE_LIMIT_REQUESTS", "100"))
-	enableMetrics, _ := strconv.ParseBool(getEnv("ENABLE_METRICS", "true"))
-
-	return &Config{
-		ServerPort:        getEnv("SERVER_PORT", "8080"),
-		InstanceID:        getEnv("INSTANCE_ID", "api-001"),
-		DatabaseDriver:    getEnv("DATABASE_DRIVER", "sqlite"), // Solo SQLite
-		SQLitePath:        getEnv("SQLITE_PATH", ":memory:"),
-		RedisHost:         getEnv("REDIS_HOST", "localhost"),
-		RedisPort:         redisPort,
-		MessageBroker:     getEnv("MESSAGE_BROKER", "redis"), // Default: Redis (más simple)
-		KafkaBrokers:      getEnv("KAFKA_BROKERS", "localhost:9092"),
-		ReservationTTL:    reservationTTL,
-		APIKeys:           loadAPIKeys(),
-		RateLimitRequests: rateLimitRequests,
-		LogLevel:          getEnv("LOG_LEVEL", "info"),
-		LogFormat:         getEnv("LOG_FORMAT", "json"),
-		EnableMetrics:     enableMetrics,
+	return load(newSource(nil))
+}
+
+// LoadFile carga la configuración desde un archivo YAML o TOML con las variables
+// de entorno por encima: cada variable definida en el entorno tiene prioridad
+// sobre la misma clave del archivo. Con path vacío equivale a Load.
+func LoadFile(path string) (*Config, error) {
+	// Cargar .env si existe (ignora error si no existe)
+	_ = godotenv.Load()
+
+	if path == "" {
+		return load(newSource(nil)), nil
+	}
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return load(newSource(values)), nil
+}
+
+func load(src *source) *Config {
+	// El provider se inicializa primero para que cualquier clave pueda venir de él
+	initSecrets(src)
+
+	environment := strings.ToLower(src.get("APP_ENV", EnvDevelopment))
+	// RESERVATION_TTL (segundos) se mantiene como fallback del TTL por defecto
+	legacyTTLSeconds := src.int("RESERVATION_TTL", 600)
+	sandboxLatencyMs := src.int("SANDBOX_LATENCY_MS", 0)
+	sandboxJitterMs := src.int("SANDBOX_LATENCY_JITTER_MS", 0)
+	secretsRefreshSeconds := src.int("SECRETS_REFRESH_SECONDS", 60)
+	breakerOpenSeconds := src.int("PUBLISHER_BREAKER_OPEN_SECONDS", 30)
+	flashSaleTicketMinutes := src.int("FLASH_SALE_TICKET_TTL_MINUTES", 10)
+	intentMinutes := src.int("RESERVATION_INTENT_TTL_MINUTES", 15)
+	confirmHookTimeoutSeconds := src.int("CONFIRM_HOOK_TIMEOUT_SECONDS", 5)
+	confirmHookBackoffMs := src.int("CONFIRM_HOOK_RETRY_BACKOFF_MS", 500)
+	shortageGraceMinutes := src.int("RESERVATION_LOW_PRIORITY_SHORTAGE_GRACE_MINUTES", 0)
+	pickupWindowHours := src.int("RESERVATION_PICKUP_WINDOW_HOURS", 48)
+	availabilityCacheTTLSeconds := src.int("AVAILABILITY_CACHE_TTL_SECONDS", 300)
+	exportRetentionHours := src.int("EXPORT_RETENTION_HOURS", 24)
+	retentionEventsDays := src.int("RETENTION_EVENTS_DAYS", 0)
+	retentionReservationsDays := src.int("RETENTION_RESERVATIONS_DAYS", 0)
+	cleanupBatchPauseMs := src.int("CLEANUP_BATCH_PAUSE_MS", 200)
+	abcWindowDays := src.int("ABC_WINDOW_DAYS", 90)
+	ledgerVerifyIntervalHours := src.int("LEDGER_VERIFY_INTERVAL_HOURS", 24)
+	backupIntervalHours := src.int("BACKUP_INTERVAL_HOURS", 0)
+	requestTimeoutSeconds := src.int("REQUEST_TIMEOUT_SECONDS", 30)
+	readHeaderTimeoutSeconds := src.int("HTTP_READ_HEADER_TIMEOUT_SECONDS", 10)
+	readTimeoutSeconds := src.int("HTTP_READ_TIMEOUT_SECONDS", 30)
+	writeTimeoutSeconds := src.int("HTTP_WRITE_TIMEOUT_SECONDS", 0)
+	idleTimeoutSeconds := src.int("HTTP_IDLE_TIMEOUT_SECONDS", 120)
+	smtpTimeoutSeconds := src.int("SMTP_TIMEOUT_SECONDS", 10)
+
+	cfg := &Config{
+		Environment:                      environment,
+		ServerPort:                       src.get("SERVER_PORT", "8080"),
+		InstanceID:                       src.get("INSTANCE_ID", "api-001"),
+		TLSCertFile:                      src.get("TLS_CERT_FILE", ""),
+		TLSKeyFile:                       src.get("TLS_KEY_FILE", ""),
+		TLSAutocertDomains:               loadAutocertDomains(src),
+		TLSAutocertCacheDir:              src.get("TLS_AUTOCERT_CACHE_DIR", "./data/autocert"),
+		TLSAutocertEmail:                 src.get("TLS_AUTOCERT_EMAIL", ""),
+		HTTPReadHeaderTimeout:            time.Duration(readHeaderTimeoutSeconds) * time.Second,
+		HTTPReadTimeout:                  time.Duration(readTimeoutSeconds) * time.Second,
+		HTTPWriteTimeout:                 time.Duration(writeTimeoutSeconds) * time.Second,
+		HTTPIdleTimeout:                  time.Duration(idleTimeoutSeconds) * time.Second,
+		HTTP2Enabled:                     src.bool("HTTP2_ENABLED", true),
+		ErrorReporter:                    strings.ToLower(src.get("ERROR_REPORTER", "none")),
+		SentryDSN:                        src.get("SENTRY_DSN", ""),
+		ErrorReporterWebhookURL:          src.get("ERROR_REPORTER_WEBHOOK_URL", ""),
+		DebugEnabled:                     src.bool("DEBUG_ENABLED", false),
+		DebugAddr:                        src.get("DEBUG_ADDR", ""),
+		RequestTimeout:                   time.Duration(requestTimeoutSeconds) * time.Second,
+		RequestTimeoutOverrides:          loadRequestTimeoutOverrides(src),
+		LatencyBudget:                    time.Duration(src.int("LATENCY_BUDGET_MS", 500)) * time.Millisecond,
+		LatencyBudgetOverrides:           loadRouteDurations(src, "LATENCY_BUDGET_OVERRIDES", time.Millisecond, "milliseconds"),
+		LatencyWindow:                    time.Duration(src.int("LATENCY_WINDOW_MINUTES", 5)) * time.Minute,
+		ContentionWindow:                 time.Duration(src.int("CONTENTION_WINDOW_MINUTES", 60)) * time.Minute,
+		MaxRequestBodyKB:                 src.int("MAX_REQUEST_BODY_KB", 1024),
+		MaxListLimit:                     src.int("MAX_LIST_LIMIT", 500),
+		CompressionEnabled:               src.bool("COMPRESSION_ENABLED", true),
+		CompressionMinBytes:              src.int("COMPRESSION_MIN_BYTES", 1024),
+		DatabaseDriver:                   src.get("DATABASE_DRIVER", "sqlite"), // Solo SQLite
+		SQLitePath:                       src.get("SQLITE_PATH", ":memory:"),
+		BackupDir:                        src.get("BACKUP_DIR", "./data/backups"),
+		BackupInterval:                   time.Duration(backupIntervalHours) * time.Hour,
+		BackupRetain:                     src.int("BACKUP_RETAIN", 7),
+		RedisHost:                        src.get("REDIS_HOST", "localhost"),
+		RedisPort:                        src.int("REDIS_PORT", 6379),
+		RedisPassword:                    src.get("REDIS_PASSWORD", ""),
+		MessageBroker:                    src.get("MESSAGE_BROKER", "redis"), // Default: Redis (más simple)
+		KafkaBrokers:                     src.get("KAFKA_BROKERS", "localhost:9092"),
+		NATSURL:                          src.get("NATS_URL", "nats://localhost:4222"),
+		NATSToken:                        src.get("NATS_TOKEN", ""),
+		NATSStream:                       src.get("NATS_STREAM", "INVENTORY"),
+		NATSSubjectTemplate:              src.get("NATS_SUBJECT_TEMPLATE", "inventory.{store_id}.{event_type}"),
+		GCPProjectID:                     src.get("GCP_PROJECT_ID", ""),
+		PubSubTopic:                      src.get("PUBSUB_TOPIC", "inventory-events"),
+		GCPCredentialsFile:               src.get("GOOGLE_APPLICATION_CREDENTIALS", ""),
+		PubSubEndpoint:                   src.get("PUBSUB_ENDPOINT", ""),
+		PubSubEmulatorHost:               src.get("PUBSUB_EMULATOR_HOST", ""),
+		SNSTopicARN:                      src.get("SNS_TOPIC_ARN", ""),
+		SNSEndpoint:                      src.get("SNS_ENDPOINT", ""),
+		SQSQueueURL:                      src.get("SQS_QUEUE_URL", ""),
+		SQSEndpoint:                      src.get("SQS_ENDPOINT", ""),
+		AWSRegion:                        src.get("AWS_REGION", ""),
+		AWSAccessKeyID:                   src.get("AWS_ACCESS_KEY_ID", ""),
+		AWSSecretAccessKey:               src.get("AWS_SECRET_ACCESS_KEY", ""),
+		AWSSessionToken:                  src.get("AWS_SESSION_TOKEN", ""),
+		AvailabilityCacheEnabled:         src.bool("AVAILABILITY_CACHE_ENABLED", false),
+		AvailabilityCacheTTL:             time.Duration(availabilityCacheTTLSeconds) * time.Second,
+		SearchBackend:                    strings.ToLower(src.get("SEARCH_BACKEND", "none")),
+		SearchURL:                        src.get("SEARCH_URL", ""),
+		SearchIndex:                      src.get("SEARCH_INDEX", "products"),
+		SearchAPIKey:                     src.get("SEARCH_API_KEY", ""),
+		MediaStorage:                     strings.ToLower(src.get("MEDIA_STORAGE", "local")),
+		MediaLocalDir:                    src.get("MEDIA_LOCAL_DIR", "./data/media"),
+		MediaPublicBaseURL:               src.get("MEDIA_PUBLIC_BASE_URL", ""),
+		MediaMaxUploadMB:                 src.int("MEDIA_MAX_UPLOAD_MB", 10),
+		MediaS3Bucket:                    src.get("MEDIA_S3_BUCKET", ""),
+		MediaS3Endpoint:                  src.get("MEDIA_S3_ENDPOINT", ""),
+		MediaS3PathStyle:                 src.bool("MEDIA_S3_PATH_STYLE", src.get("MEDIA_S3_ENDPOINT", "") != ""),
+		ExportLocalDir:                   src.get("EXPORT_LOCAL_DIR", "./data/exports"),
+		ExportRetention:                  time.Duration(exportRetentionHours) * time.Hour,
+		RetentionEvents:                  time.Duration(retentionEventsDays) * 24 * time.Hour,
+		RetentionReservations:            time.Duration(retentionReservationsDays) * 24 * time.Hour,
+		RetentionArchive:                 src.bool("RETENTION_ARCHIVE_ENABLED", false),
+		RetentionArchiveDir:              src.get("RETENTION_ARCHIVE_DIR", "./data/archive"),
+		CleanupMaxBatchSize:              src.int("CLEANUP_MAX_BATCH_SIZE", 5000),
+		CleanupBatchPause:                time.Duration(cleanupBatchPauseMs) * time.Millisecond,
+		ABCWindow:                        time.Duration(abcWindowDays) * 24 * time.Hour,
+		LedgerVerifyInterval:             time.Duration(ledgerVerifyIntervalHours) * time.Hour,
+		ProductLocales:                   loadProductLocales(src),
+		FeatureFlags:                     loadFeatureFlags(src),
+		PublisherBreakerFailures:         src.int("PUBLISHER_BREAKER_FAILURES", 5),
+		PublisherBreakerOpenTimeout:      time.Duration(breakerOpenSeconds) * time.Second,
+		ReservationDefaultTTL:            src.int("RESERVATION_DEFAULT_TTL_MINUTES", legacyTTLSeconds/60),
+		ReservationMaxTTL:                src.int("RESERVATION_MAX_TTL_MINUTES", 1440),
+		ReservationTTLOverrides:          loadTTLOverrides(src),
+		ReservationMaxUnitsPerCustomer:   src.int("RESERVATION_MAX_UNITS_PER_CUSTOMER", 0),
+		ReservationMaxPendingPerCustomer: src.int("RESERVATION_MAX_PENDING_PER_CUSTOMER", 0),
+		ConfirmHookURL:                   src.get("CONFIRM_HOOK_URL", ""),
+		ConfirmHookSecret:                src.get("CONFIRM_HOOK_SECRET", ""),
+		ConfirmHookMode:                  strings.ToLower(src.get("CONFIRM_HOOK_MODE", "sync")),
+		ConfirmHookTimeout:               time.Duration(confirmHookTimeoutSeconds) * time.Second,
+		ConfirmHookMaxAttempts:           src.int("CONFIRM_HOOK_MAX_ATTEMPTS", 3),
+		ConfirmHookBackoff:               time.Duration(confirmHookBackoffMs) * time.Millisecond,
+		AdjustmentApprovalMaxUnits:       src.int("ADJUSTMENT_APPROVAL_MAX_UNITS", 0),
+		AdjustmentApprovalMaxPercent:     src.float("ADJUSTMENT_APPROVAL_MAX_PERCENT", 0),
+		ReservationIntentTTL:             time.Duration(intentMinutes) * time.Minute,
+		ReservationShortageGrace:         time.Duration(shortageGraceMinutes) * time.Minute,
+		ReservationPickupWindow:          time.Duration(pickupWindowHours) * time.Hour,
+		FlashSaleQueueSize:               src.int("FLASH_SALE_QUEUE_SIZE", 1000),
+		FlashSaleTicketTTL:               time.Duration(flashSaleTicketMinutes) * time.Minute,
+		StoreSyncStaleAfter:              time.Duration(src.int("STORE_SYNC_STALE_MINUTES", 15)) * time.Minute,
+		SMTPHost:                         src.get("SMTP_HOST", ""),
+		SMTPPort:                         src.int("SMTP_PORT", 587),
+		SMTPUsername:                     src.get("SMTP_USERNAME", ""),
+		SMTPPassword:                     src.get("SMTP_PASSWORD", ""),
+		SMTPFrom:                         src.get("SMTP_FROM", ""),
+		SMTPTimeout:                      time.Duration(smtpTimeoutSeconds) * time.Second,
+		APIKeys:                          loadAPIKeys(src, environment == EnvProduction),
+		RateLimitRequests:                src.int("RATE_LIMIT_REQUESTS", 100),
+		APIKeyStoreScopes:                loadAPIKeyStoreScopes(src),
+		SecretsProvider:                  strings.ToLower(src.get("SECRETS_PROVIDER", "none")),
+		SecretsRefreshInterval:           time.Duration(secretsRefreshSeconds) * time.Second,
+		APIV1Sunset:                      src.date("API_V1_SUNSET"),
+		SwaggerEnabled:                   src.bool("SWAGGER_ENABLED", true),
+		SKUPattern:                       src.get("SKU_PATTERN", `^[A-Za-z0-9][A-Za-z0-9._/-]*$`),
+		SKUMaxLength:                     src.int("SKU_MAX_LENGTH", 64),
+		SKUUppercase:                     src.bool("SKU_UPPERCASE", true),
+		LogLevel:                         src.get("LOG_LEVEL", "info"),
+		LogFormat:                        src.get("LOG_FORMAT", "json"),
+		EnableMetrics:                    src.bool("ENABLE_METRICS", true),
+		MetricsLowStockThreshold:         src.int("METRICS_LOW_STOCK_THRESHOLD", 10),
+		MetricsLowStockTopN:              src.int("METRICS_LOW_STOCK_TOP_N", 50),
+		SandboxMode:                      src.bool("SANDBOX_MODE", false),
+		SandboxSeed:                      src.int64("SANDBOX_SEED", 42),
+		SandboxProducts:                  src.int("SANDBOX_PRODUCTS", 50),
+		SandboxLatency:                   time.Duration(sandboxLatencyMs) * time.Millisecond,
+		SandboxLatencyJitter:             time.Duration(sandboxJitterMs) * time.Millisecond,
+		SandboxErrorRate:                 src.float("SANDBOX_ERROR_RATE", 0),
+	}
+
+	// En sandbox nunca se toca una BD ni un broker reales
+	if cfg.SandboxMode {
+		cfg.SQLitePath = SandboxSQLitePath
+		cfg.MessageBroker = "none"
+		cfg.AvailabilityCacheEnabled = false
+		cfg.SearchBackend = "none"
+		cfg.MediaStorage = "local"
 	}
+	if cfg.MediaStorage == "local" && cfg.MediaPublicBaseURL == "" {
+		cfg.MediaPublicBaseURL = "/media"
+	}
+
+	cfg.loadErrors = append(append([]error(nil), src.errs...), src.unknownKeys()...)
+	cfg.src = src
+
+	return cfg
 }
 
-func loadAPIKeys() map[string]string {
+// Entornos de despliegue reconocidos (APP_ENV)
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// SandboxSQLitePath es la BD en memoria compartida que usa el modo sandbox
+const SandboxSQLitePath = "file:sandbox?mode=memory&cache=shared"
+
+func loadAPIKeys(src *source, production bool) map[string]string {
 	keys := make(map[string]string)
 
 	// Leer de variable de entorno API_KEYS
 	// Formato: key1:name1,key2:name2
-	apiKeysEnv := getEnv("API_KEYS", "")
+	apiKeysEnv := src.get("API_KEYS", "")
 	if apiKeysEnv != "" {
 		pairs := strings.Split(apiKeysEnv, ",")
 		for _, pair := range pairs {
@@ -85,8 +516,9 @@ func loadAPIKeys() map[string]string {
 		}
 	}
 
-	// Si no hay keys configuradas, usar keys por defecto para desarrollo
-	if len(keys) == 0 {
+	// Si no hay keys configuradas, usar keys por defecto para desarrollo.
+	// En producción nunca: Validate rechaza el arranque sin keys.
+	if len(keys) == 0 && !production {
 		keys = map[string]string{
 			"dev-key-store-001": "Store Madrid",
 			"dev-key-store-002": "Store Barcelona",
@@ -97,9 +529,155 @@ func loadAPIKeys() map[string]string {
 	return keys
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+func loadTTLOverrides(src *source) map[string]TTLBounds {
+	overrides := make(map[string]TTLBounds)
+
+	// Leer de variable de entorno RESERVATION_TTL_OVERRIDES
+	// Formato: store1:default:max,store2:default:max (minutos, 0 = heredar global)
+	env := src.get("RESERVATION_TTL_OVERRIDES", "")
+	if env == "" {
+		return overrides
+	}
+
+	for _, entry := range strings.Split(env, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 3 {
+			src.errs = append(src.errs, fmt.Errorf("RESERVATION_TTL_OVERRIDES: invalid entry %q (expected store:default:max)", entry))
+			continue
+		}
+		defaultTTL, err1 := strconv.Atoi(strings.TrimSpace(parts[1]))
+		maxTTL, err2 := strconv.Atoi(strings.TrimSpace(parts[2]))
+		if err1 != nil || err2 != nil {
+			src.errs = append(src.errs, fmt.Errorf("RESERVATION_TTL_OVERRIDES: invalid minutes in entry %q", entry))
+			continue
+		}
+		overrides[strings.TrimSpace(parts[0])] = TTLBounds{
+			DefaultMinutes: defaultTTL,
+			MaxMinutes:     maxTTL,
+		}
+	}
+
+	return overrides
+}
+
+// loadAPIKeyStoreScopes parsea API_KEY_STORE_SCOPES.
+// Formato: name:store_id|store_id,name:store_id (name es el nombre de la key en API_KEYS);
+// name:* marca la key como multi-tienda
+func loadAPIKeyStoreScopes(src *source) map[string][]string {
+	scopes := make(map[string][]string)
+
+	env := src.get("API_KEY_STORE_SCOPES", "")
+	if env == "" {
+		return scopes
+	}
+
+	for _, entry := range strings.Split(env, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		name = strings.TrimSpace(name)
+		var stores []string
+		for _, store := range strings.Split(value, "|") {
+			if store = strings.TrimSpace(store); store != "" {
+				stores = append(stores, store)
+			}
+		}
+		if !ok || name == "" || len(stores) == 0 || (len(stores) > 1 && slices.Contains(stores, "*")) {
+			src.errs = append(src.errs, fmt.Errorf("API_KEY_STORE_SCOPES: invalid entry %q (expected name:store_id|store_id or name:*)", entry))
+			continue
+		}
+		scopes[name] = stores
+	}
+
+	return scopes
+}
+
+// loadFeatureFlags parsea FEATURE_FLAGS.
+// Formato: feature:on,feature@store_id:off (on/off o true/false)
+func loadFeatureFlags(src *source) map[string]bool {
+	flags := make(map[string]bool)
+
+	env := src.get("FEATURE_FLAGS", "")
+	if env == "" {
+		return flags
+	}
+
+	for _, entry := range strings.Split(env, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.HasPrefix(key, "@") || strings.HasSuffix(key, "@") {
+			src.errs = append(src.errs, fmt.Errorf("FEATURE_FLAGS: invalid entry %q (expected feature:on or feature@store_id:off)", entry))
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "on", "true":
+			flags[key] = true
+		case "off", "false":
+			flags[key] = false
+		default:
+			src.errs = append(src.errs, fmt.Errorf("FEATURE_FLAGS: invalid value in entry %q (expected on or off)", entry))
+		}
+	}
+
+	return flags
+}
+
+// loadRequestTimeoutOverrides parsea REQUEST_TIMEOUT_OVERRIDES
+// ("POST /api/v1/reservations=5,/api/v1/reports/*=120", segundos; 0 = sin límite)
+func loadRequestTimeoutOverrides(src *source) map[string]time.Duration {
+	return loadRouteDurations(src, "REQUEST_TIMEOUT_OVERRIDES", time.Second, "seconds")
+}
+
+// loadRouteDurations parsea una lista de "[MÉTODO ]ruta=valor" separados por comas, con el valor
+// en la unidad indicada
+func loadRouteDurations(src *source, key string, unit time.Duration, unitName string) map[string]time.Duration {
+	overrides := make(map[string]time.Duration)
+
+	env := src.get(key, "")
+	if env == "" {
+		return overrides
+	}
+
+	for _, entry := range strings.Split(env, ",") {
+		pattern, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		pattern = strings.Join(strings.Fields(pattern), " ")
+		method, route, hasMethod := strings.Cut(pattern, " ")
+		if !hasMethod {
+			route, method = method, ""
+		}
+		if !ok || !strings.HasPrefix(route, "/") || method != strings.ToUpper(method) {
+			src.errs = append(src.errs, fmt.Errorf("%s: invalid entry %q (expected [METHOD ]/route=%s)", key, entry, unitName))
+			continue
+		}
+		amount, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || amount < 0 {
+			src.errs = append(src.errs, fmt.Errorf("%s: invalid %s in entry %q", key, unitName, entry))
+			continue
+		}
+		overrides[pattern] = time.Duration(amount) * unit
+	}
+
+	return overrides
+}
+
+// loadAutocertDomains parsea TLS_AUTOCERT_DOMAINS ("api.example.com,inventory.example.com")
+func loadAutocertDomains(src *source) []string {
+	domains := make([]string, 0)
+	for _, part := range strings.Split(src.get("TLS_AUTOCERT_DOMAINS", ""), ",") {
+		domain := strings.ToLower(strings.TrimSpace(part))
+		if domain != "" && !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// loadProductLocales parsea PRODUCT_LOCALES ("es,ca,en") en minúsculas y sin duplicados
+func loadProductLocales(src *source) []string {
+	locales := make([]string, 0)
+	for _, part := range strings.Split(src.get("PRODUCT_LOCALES", "es,ca,en"), ",") {
+		locale := strings.ToLower(strings.TrimSpace(part))
+		if locale != "" && !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
+		}
 	}
-	return defaultValue
+	return locales
 }
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted sustituye el valor de los secretos en la salida de --print-config
+const redacted = "[REDACTED]"
+
+// WriteRedacted escribe la configuración efectiva en YAML, con las mismas claves
+// que acepta el archivo de configuración y los secretos ocultos. La salida se
+// puede usar como punto de partida de un archivo de configuración.
+func (c *Config) WriteRedacted(w io.Writer) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, entry := range c.entries() {
+		doc.Content = append(doc.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: strings.ToLower(entry[0])},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: entry[1]},
+		)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	return encoder.Close()
+}
+
+// entries devuelve cada clave de configuración con su valor efectivo
+func (c *Config) entries() [][2]string {
+	sunset := ""
+	if !c.APIV1Sunset.IsZero() {
+		sunset = c.APIV1Sunset.Format("2006-01-02")
+	}
+
+	return [][2]string{
+		{"APP_ENV", c.Environment},
+		{"SERVER_PORT", c.ServerPort},
+		{"INSTANCE_ID", c.InstanceID},
+		{"TLS_CERT_FILE", c.TLSCertFile},
+		{"TLS_KEY_FILE", c.TLSKeyFile},
+		{"TLS_AUTOCERT_DOMAINS", strings.Join(c.TLSAutocertDomains, ",")},
+		{"TLS_AUTOCERT_CACHE_DIR", c.TLSAutocertCacheDir},
+		{"TLS_AUTOCERT_EMAIL", c.TLSAutocertEmail},
+		{"HTTP_READ_HEADER_TIMEOUT_SECONDS", strconv.FormatFloat(c.HTTPReadHeaderTimeout.Seconds(), 'f', -1, 64)},
+		{"HTTP_READ_TIMEOUT_SECONDS", strconv.FormatFloat(c.HTTPReadTimeout.Seconds(), 'f', -1, 64)},
+		{"HTTP_WRITE_TIMEOUT_SECONDS", strconv.FormatFloat(c.HTTPWriteTimeout.Seconds(), 'f', -1, 64)},
+		{"HTTP_IDLE_TIMEOUT_SECONDS", strconv.FormatFloat(c.HTTPIdleTimeout.Seconds(), 'f', -1, 64)},
+		{"HTTP2_ENABLED", strconv.FormatBool(c.HTTP2Enabled)},
+		{"ERROR_REPORTER", c.ErrorReporter},
+		{"SENTRY_DSN", redactSecret(c.SentryDSN)},
+		{"ERROR_REPORTER_WEBHOOK_URL", redactSecret(c.ErrorReporterWebhookURL)},
+		{"DEBUG_ENABLED", strconv.FormatBool(c.DebugEnabled)},
+		{"DEBUG_ADDR", c.DebugAddr},
+		{"REQUEST_TIMEOUT_SECONDS", strconv.FormatFloat(c.RequestTimeout.Seconds(), 'f', -1, 64)},
+		{"REQUEST_TIMEOUT_OVERRIDES", formatRouteDurations(c.RequestTimeoutOverrides, time.Second)},
+		{"LATENCY_BUDGET_MS", strconv.FormatInt(c.LatencyBudget.Milliseconds(), 10)},
+		{"LATENCY_BUDGET_OVERRIDES", formatRouteDurations(c.LatencyBudgetOverrides, time.Millisecond)},
+		{"LATENCY_WINDOW_MINUTES", strconv.FormatFloat(c.LatencyWindow.Minutes(), 'f', -1, 64)},
+		{"CONTENTION_WINDOW_MINUTES", strconv.FormatFloat(c.ContentionWindow.Minutes(), 'f', -1, 64)},
+		{"MAX_REQUEST_BODY_KB", strconv.Itoa(c.MaxRequestBodyKB)},
+		{"MAX_LIST_LIMIT", strconv.Itoa(c.MaxListLimit)},
+		{"COMPRESSION_ENABLED", strconv.FormatBool(c.CompressionEnabled)},
+		{"COMPRESSION_MIN_BYTES", strconv.Itoa(c.CompressionMinBytes)},
+		{"DATABASE_DRIVER", c.DatabaseDriver},
+		{"SQLITE_PATH", c.SQLitePath},
+		{"BACKUP_DIR", c.BackupDir},
+		{"BACKUP_INTERVAL_HOURS", strconv.FormatFloat(c.BackupInterval.Hours(), 'f', -1, 64)},
+		{"BACKUP_RETAIN", strconv.Itoa(c.BackupRetain)},
+		{"MESSAGE_BROKER", c.MessageBroker},
+		{"REDIS_HOST", c.RedisHost},
+		{"REDIS_PORT", strconv.Itoa(c.RedisPort)},
+		{"REDIS_PASSWORD", redactSecret(c.RedisPassword)},
+		{"KAFKA_BROKERS", c.KafkaBrokers},
+		{"NATS_URL", redactURL(c.NATSURL)},
+		{"NATS_TOKEN", redactSecret(c.NATSToken)},
+		{"NATS_STREAM", c.NATSStream},
+		{"NATS_SUBJECT_TEMPLATE", c.NATSSubjectTemplate},
+		{"GCP_PROJECT_ID", c.GCPProjectID},
+		{"PUBSUB_TOPIC", c.PubSubTopic},
+		{"GOOGLE_APPLICATION_CREDENTIALS", c.GCPCredentialsFile},
+		{"PUBSUB_ENDPOINT", c.PubSubEndpoint},
+		{"PUBSUB_EMULATOR_HOST", c.PubSubEmulatorHost},
+		{"SNS_TOPIC_ARN", c.SNSTopicARN},
+		{"SNS_ENDPOINT", c.SNSEndpoint},
+		{"SQS_QUEUE_URL", c.SQSQueueURL},
+		{"SQS_ENDPOINT", c.SQSEndpoint},
+		{"AWS_REGION", c.AWSRegion},
+		{"AWS_ACCESS_KEY_ID", c.AWSAccessKeyID},
+		{"AWS_SECRET_ACCESS_KEY", redactSecret(c.AWSSecretAccessKey)},
+		{"AWS_SESSION_TOKEN", redactSecret(c.AWSSessionToken)},
+		{"AVAILABILITY_CACHE_ENABLED", strconv.FormatBool(c.AvailabilityCacheEnabled)},
+		{"AVAILABILITY_CACHE_TTL_SECONDS", strconv.FormatFloat(c.AvailabilityCacheTTL.Seconds(), 'f', -1, 64)},
+		{"SEARCH_BACKEND", c.SearchBackend},
+		{"SEARCH_URL", c.SearchURL},
+		{"SEARCH_INDEX", c.SearchIndex},
+		{"SEARCH_API_KEY", redactSecret(c.SearchAPIKey)},
+		{"MEDIA_STORAGE", c.MediaStorage},
+		{"MEDIA_LOCAL_DIR", c.MediaLocalDir},
+		{"MEDIA_PUBLIC_BASE_URL", c.MediaPublicBaseURL},
+		{"MEDIA_MAX_UPLOAD_MB", strconv.Itoa(c.MediaMaxUploadMB)},
+		{"MEDIA_S3_BUCKET", c.MediaS3Bucket},
+		{"MEDIA_S3_ENDPOINT", c.MediaS3Endpoint},
+		{"MEDIA_S3_PATH_STYLE", strconv.FormatBool(c.MediaS3PathStyle)},
+		{"EXPORT_LOCAL_DIR", c.ExportLocalDir},
+		{"EXPORT_RETENTION_HOURS", strconv.FormatFloat(c.ExportRetention.Hours(), 'f', -1, 64)},
+		{"RETENTION_EVENTS_DAYS", strconv.FormatFloat(c.RetentionEvents.Hours()/24, 'f', -1, 64)},
+		{"RETENTION_RESERVATIONS_DAYS", strconv.FormatFloat(c.RetentionReservations.Hours()/24, 'f', -1, 64)},
+		{"RETENTION_ARCHIVE_ENABLED", strconv.FormatBool(c.RetentionArchive)},
+		{"RETENTION_ARCHIVE_DIR", c.RetentionArchiveDir},
+		{"CLEANUP_MAX_BATCH_SIZE", strconv.Itoa(c.CleanupMaxBatchSize)},
+		{"CLEANUP_BATCH_PAUSE_MS", strconv.FormatInt(c.CleanupBatchPause.Milliseconds(), 10)},
+		{"ABC_WINDOW_DAYS", strconv.FormatFloat(c.ABCWindow.Hours()/24, 'f', -1, 64)},
+		{"LEDGER_VERIFY_INTERVAL_HOURS", strconv.FormatFloat(c.LedgerVerifyInterval.Hours(), 'f', -1, 64)},
+		{"PRODUCT_LOCALES", strings.Join(c.ProductLocales, ",")},
+		{"FEATURE_FLAGS", formatFeatureFlags(c.FeatureFlags)},
+		{"PUBLISHER_BREAKER_FAILURES", strconv.Itoa(c.PublisherBreakerFailures)},
+		{"PUBLISHER_BREAKER_OPEN_SECONDS", strconv.FormatFloat(c.PublisherBreakerOpenTimeout.Seconds(), 'f', -1, 64)},
+		{"RESERVATION_DEFAULT_TTL_MINUTES", strconv.Itoa(c.ReservationDefaultTTL)},
+		{"RESERVATION_MAX_TTL_MINUTES", strconv.Itoa(c.ReservationMaxTTL)},
+		{"RESERVATION_TTL_OVERRIDES", formatTTLOverrides(c.ReservationTTLOverrides)},
+		{"RESERVATION_MAX_UNITS_PER_CUSTOMER", strconv.Itoa(c.ReservationMaxUnitsPerCustomer)},
+		{"RESERVATION_MAX_PENDING_PER_CUSTOMER", strconv.Itoa(c.ReservationMaxPendingPerCustomer)},
+		{"CONFIRM_HOOK_URL", redactSecret(c.ConfirmHookURL)},
+		{"CONFIRM_HOOK_SECRET", redactSecret(c.ConfirmHookSecret)},
+		{"CONFIRM_HOOK_MODE", c.ConfirmHookMode},
+		{"CONFIRM_HOOK_TIMEOUT_SECONDS", strconv.FormatFloat(c.ConfirmHookTimeout.Seconds(), 'f', -1, 64)},
+		{"CONFIRM_HOOK_MAX_ATTEMPTS", strconv.Itoa(c.ConfirmHookMaxAttempts)},
+		{"CONFIRM_HOOK_RETRY_BACKOFF_MS", strconv.FormatInt(c.ConfirmHookBackoff.Milliseconds(), 10)},
+		{"ADJUSTMENT_APPROVAL_MAX_UNITS", strconv.Itoa(c.AdjustmentApprovalMaxUnits)},
+		{"ADJUSTMENT_APPROVAL_MAX_PERCENT", strconv.FormatFloat(c.AdjustmentApprovalMaxPercent, 'f', -1, 64)},
+		{"RESERVATION_INTENT_TTL_MINUTES", strconv.FormatFloat(c.ReservationIntentTTL.Minutes(), 'f', -1, 64)},
+		{"RESERVATION_LOW_PRIORITY_SHORTAGE_GRACE_MINUTES", strconv.FormatFloat(c.ReservationShortageGrace.Minutes(), 'f', -1, 64)},
+		{"RESERVATION_PICKUP_WINDOW_HOURS", strconv.FormatFloat(c.ReservationPickupWindow.Hours(), 'f', -1, 64)},
+		{"FLASH_SALE_QUEUE_SIZE", strconv.Itoa(c.FlashSaleQueueSize)},
+		{"FLASH_SALE_TICKET_TTL_MINUTES", strconv.FormatFloat(c.FlashSaleTicketTTL.Minutes(), 'f', -1, 64)},
+		{"STORE_SYNC_STALE_MINUTES", strconv.FormatFloat(c.StoreSyncStaleAfter.Minutes(), 'f', -1, 64)},
+		{"SMTP_HOST", c.SMTPHost},
+		{"SMTP_PORT", strconv.Itoa(c.SMTPPort)},
+		{"SMTP_USERNAME", c.SMTPUsername},
+		{"SMTP_PASSWORD", redactSecret(c.SMTPPassword)},
+		{"SMTP_FROM", c.SMTPFrom},
+		{"SMTP_TIMEOUT_SECONDS", strconv.FormatFloat(c.SMTPTimeout.Seconds(), 'f', -1, 64)},
+		{"API_KEYS", formatAPIKeys(c.APIKeys)},
+		{"RATE_LIMIT_REQUESTS", strconv.Itoa(c.RateLimitRequests)},
+		{"API_KEY_STORE_SCOPES", formatAPIKeyStoreScopes(c.APIKeyStoreScopes)},
+		{"SECRETS_PROVIDER", c.SecretsProvider},
+		{"SECRETS_REFRESH_SECONDS", strconv.FormatFloat(c.SecretsRefreshInterval.Seconds(), 'f', -1, 64)},
+		{"API_V1_SUNSET", sunset},
+		{"SWAGGER_ENABLED", strconv.FormatBool(c.SwaggerEnabled)},
+		{"SKU_PATTERN", c.SKUPattern},
+		{"SKU_MAX_LENGTH", strconv.Itoa(c.SKUMaxLength)},
+		{"SKU_UPPERCASE", strconv.FormatBool(c.SKUUppercase)},
+		{"LOG_LEVEL", c.LogLevel},
+		{"LOG_FORMAT", c.LogFormat},
+		{"ENABLE_METRICS", strconv.FormatBool(c.EnableMetrics)},
+		{"METRICS_LOW_STOCK_THRESHOLD", strconv.Itoa(c.MetricsLowStockThreshold)},
+		{"METRICS_LOW_STOCK_TOP_N", strconv.Itoa(c.MetricsLowStockTopN)},
+		{"SANDBOX_MODE", strconv.FormatBool(c.SandboxMode)},
+		{"SANDBOX_SEED", strconv.FormatInt(c.SandboxSeed, 10)},
+		{"SANDBOX_PRODUCTS", strconv.Itoa(c.SandboxProducts)},
+		{"SANDBOX_LATENCY_MS", strconv.FormatInt(c.SandboxLatency.Milliseconds(), 10)},
+		{"SANDBOX_LATENCY_JITTER_MS", strconv.FormatInt(c.SandboxLatencyJitter.Milliseconds(), 10)},
+		{"SANDBOX_ERROR_RATE", strconv.FormatFloat(c.SandboxErrorRate, 'f', -1, 64)},
+	}
+}
+
+// formatAPIKeys serializa las keys en formato key:name ocultando la key
+func formatAPIKeys(keys map[string]string) string {
+	entries := make([]string, 0, len(keys))
+	for key, name := range keys {
+		entries = append(entries, redactKey(key)+":"+name)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// redactKey oculta una API key conservando solo el prefijo, suficiente para
+// identificarla en logs sin exponerla
+func redactKey(key string) string {
+	if len(key) <= 8 {
+		return redacted
+	}
+	return key[:4] + "…" + redacted
+}
+
+// redactURL oculta la contraseña incluida en una URL (user:pass@host)
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
+// redactSecret oculta un secreto por completo; vacío se muestra vacío para
+// distinguir "no configurado" de "configurado"
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
+func formatTTLOverrides(overrides map[string]TTLBounds) string {
+	entries := make([]string, 0, len(overrides))
+	for storeID, bounds := range overrides {
+		entries = append(entries, fmt.Sprintf("%s:%d:%d", storeID, bounds.DefaultMinutes, bounds.MaxMinutes))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+func formatRouteDurations(overrides map[string]time.Duration, unit time.Duration) string {
+	entries := make([]string, 0, len(overrides))
+	for pattern, duration := range overrides {
+		entries = append(entries, pattern+"="+strconv.FormatFloat(float64(duration)/float64(unit), 'f', -1, 64))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// formatAPIKeyStoreScopes serializa los scopes en formato name:store|store
+func formatAPIKeyStoreScopes(scopes map[string][]string) string {
+	entries := make([]string, 0, len(scopes))
+	for name, stores := range scopes {
+		entries = append(entries, name+":"+strings.Join(stores, "|"))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+func formatFeatureFlags(flags map[string]bool) string {
+	entries := make([]string, 0, len(flags))
+	for key, enabled := range flags {
+		value := "off"
+		if enabled {
+			value = "on"
+		}
+		entries = append(entries, key+":"+value)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-system/internal/secrets"
+)
+
+// secretsFetchTimeout límite para consultar el secret provider al arrancar
+const secretsFetchTimeout = 10 * time.Second
+
+// initSecrets construye el provider de SECRETS_PROVIDER y carga sus secretos antes
+// de resolver el resto de claves. Los fallos se acumulan para que Validate los reporte.
+func initSecrets(src *source) {
+	provider, err := newSecretsProvider(src)
+	if err != nil {
+		src.errs = append(src.errs, err)
+		return
+	}
+	if provider == nil {
+		return
+	}
+
+	src.provider = provider
+	ctx, cancel := context.WithTimeout(context.Background(), secretsFetchTimeout)
+	defer cancel()
+	if err := src.refreshSecrets(ctx); err != nil {
+		src.errs = append(src.errs, err)
+	}
+}
+
+// newSecretsProvider crea el provider indicado en SECRETS_PROVIDER (vault, aws, none)
+func newSecretsProvider(src *source) (secrets.Provider, error) {
+	switch kind := strings.ToLower(src.get("SECRETS_PROVIDER", "none")); kind {
+	case "none", "":
+		return nil, nil
+
+	case "vault":
+		addr := src.get("VAULT_ADDR", "")
+		token := src.get("VAULT_TOKEN", "")
+		path := src.get("VAULT_SECRET_PATH", "")
+		if addr == "" || token == "" || path == "" {
+			return nil, errors.New("SECRETS_PROVIDER: vault requires VAULT_ADDR, VAULT_TOKEN and VAULT_SECRET_PATH")
+		}
+		return secrets.NewVaultProvider(addr, token, path), nil
+
+	case "aws":
+		cfg := secrets.AWSProviderConfig{
+			Region:          src.get("AWS_REGION", ""),
+			SecretID:        src.get("AWS_SECRET_ID", ""),
+			AccessKeyID:     src.get("AWS_ACCESS_KEY_ID", ""),
+			SecretAccessKey: src.get("AWS_SECRET_ACCESS_KEY", ""),
+			SessionToken:    src.get("AWS_SESSION_TOKEN", ""),
+			Endpoint:        src.get("AWS_SECRETS_ENDPOINT", ""),
+		}
+		if cfg.SecretID == "" {
+			return nil, errors.New("SECRETS_PROVIDER: aws requires AWS_SECRET_ID")
+		}
+		if (cfg.AccessKeyID == "") != (cfg.SecretAccessKey == "") {
+			return nil, errors.New("SECRETS_PROVIDER: set both AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY or neither (SDK credential chain)")
+		}
+		provider, err := secrets.NewAWSProvider(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("SECRETS_PROVIDER: %w", err)
+		}
+		return provider, nil
+
+	default:
+		return nil, fmt.Errorf("SECRETS_PROVIDER: unknown provider %q (options: vault, aws, none)", kind)
+	}
+}
+
+// APIKeysReloadable indica si las API keys pueden rotar en caliente: vienen de
+// API_KEYS_FILE o de un secret provider (el entorno no cambia sin reiniciar)
+func (c *Config) APIKeysReloadable() bool {
+	if c.src == nil || c.SecretsRefreshInterval <= 0 {
+		return false
+	}
+	return c.src.provider != nil || c.src.secretFile("API_KEYS") != ""
+}
+
+// ReloadAPIKeys vuelve a resolver API_KEYS releyendo API_KEYS_FILE y consultando
+// de nuevo el secret provider, para aplicar rotaciones sin reiniciar el servidor.
+// No es seguro llamarla de forma concurrente.
+func (c *Config) ReloadAPIKeys(ctx context.Context) (map[string]string, error) {
+	if c.src == nil {
+		return c.APIKeys, nil
+	}
+
+	if err := c.src.refreshSecrets(ctx); err != nil {
+		return nil, err
+	}
+
+	mark := len(c.src.errs)
+	keys := loadAPIKeys(c.src, c.Environment == EnvProduction)
+	if len(c.src.errs) > mark {
+		err := errors.Join(c.src.errs[mark:]...)
+		c.src.errs = c.src.errs[:mark]
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("API_KEYS: reloaded key list is empty")
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"inventory-system/internal/secrets"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// source resuelve cada clave de configuración por capas, en este orden:
+//  1. variable de entorno (KEY)
+//  2. archivo de secreto (KEY_FILE, convención de Docker/Kubernetes secrets)
+//  3. secret provider externo (Vault, AWS Secrets Manager)
+//  4. archivo de configuración YAML/TOML
+//  5. valor por defecto
+//
+// Los valores que no se pueden parsear se acumulan en errs en lugar de caer
+// silenciosamente al default.
+type source struct {
+	file     map[string]string
+	secrets  map[string]string
+	provider secrets.Provider
+	used     map[string]bool
+	errs     []error
+}
+
+func newSource(file map[string]string) *source {
+	return &source{file: file, used: make(map[string]bool)}
+}
+
+func (s *source) get(key, defaultValue string) string {
+	s.used[key] = true
+	s.used[key+"_FILE"] = true
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	if path := s.secretFile(key); path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return strings.TrimSpace(string(data))
+		}
+		s.errs = append(s.errs, fmt.Errorf("%s_FILE: %w", key, err))
+	}
+	if value, ok := s.secrets[key]; ok && value != "" {
+		return value
+	}
+	if value, ok := s.file[key]; ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// secretFile devuelve la ruta de KEY_FILE (entorno o archivo de configuración)
+func (s *source) secretFile(key string) string {
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		return path
+	}
+	return s.file[key+"_FILE"]
+}
+
+// refreshSecrets vuelve a leer los secretos del provider externo, si hay uno
+func (s *source) refreshSecrets(ctx context.Context) error {
+	if s.provider == nil {
+		return nil
+	}
+
+	values, err := s.provider.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("secrets provider %s: %w", s.provider.Name(), err)
+	}
+	s.secrets = values
+	return nil
+}
+
+func (s *source) int(key string, defaultValue int) int {
+	raw := s.get(key, "")
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: invalid integer %q", key, raw))
+		return defaultValue
+	}
+	return value
+}
+
+func (s *source) int64(key string, defaultValue int64) int64 {
+	raw := s.get(key, "")
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: invalid integer %q", key, raw))
+		return defaultValue
+	}
+	return value
+}
+
+func (s *source) float(key string, defaultValue float64) float64 {
+	raw := s.get(key, "")
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: invalid number %q", key, raw))
+		return defaultValue
+	}
+	return value
+}
+
+func (s *source) bool(key string, defaultValue bool) bool {
+	raw := s.get(key, "")
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(strings.TrimSpace(raw))
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: invalid boolean %q", key, raw))
+		return defaultValue
+	}
+	return value
+}
+
+// date parsea una fecha YYYY-MM-DD; vacía devuelve el zero value
+func (s *source) date(key string) time.Time {
+	raw := s.get(key, "")
+	if raw == "" {
+		return time.Time{}
+	}
+	value, err := time.Parse("2006-01-02", strings.TrimSpace(raw))
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("%s: invalid date %q (expected YYYY-MM-DD)", key, raw))
+		return time.Time{}
+	}
+	return value
+}
+
+// unknownKeys reporta las claves del archivo que no corresponden a ninguna
+// variable de configuración (típicamente errores de escritura)
+func (s *source) unknownKeys() []error {
+	var unknown []string
+	for key := range s.file {
+		if !s.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	errs := make([]error, 0, len(unknown))
+	for _, key := range unknown {
+		errs = append(errs, fmt.Errorf("config file: unknown key %q", strings.ToLower(key)))
+	}
+	return errs
+}
+
+// readConfigFile lee un archivo YAML (.yaml/.yml) o TOML (.toml) y lo aplana a
+// claves con el nombre de la variable de entorno equivalente: las secciones se
+// unen con "_" (redis: {host: x} → REDIS_HOST) y las listas con ",".
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q (options: .yaml, .yml, .toml)", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flatten("", raw, values)
+	return values, nil
+}
+
+func flatten(prefix string, node map[string]interface{}, out map[string]string) {
+	for key, value := range node {
+		name := strings.ToUpper(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(name, v, out)
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			out[name] = strings.Join(items, ",")
+		case time.Time:
+			// YAML interpreta 2027-01-01 como timestamp
+			out[name] = v.Format("2006-01-02")
+		case nil:
+			out[name] = ""
+		default:
+			out[name] = fmt.Sprint(v)
+		}
+	}
+}
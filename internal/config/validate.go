@@ -0,0 +1,405 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate comprueba la configuración al arrancar. Devuelve todos los problemas
+// encontrados a la vez (errors.Join) para no tener que corregirlos de uno en uno.
+func (c *Config) Validate() error {
+	errs := append([]error(nil), c.loadErrors...)
+
+	switch c.Environment {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		errs = append(errs, fmt.Errorf("APP_ENV: unknown environment %q (options: development, staging, production)", c.Environment))
+	}
+
+	if err := validatePort("SERVER_PORT", c.ServerPort); err != nil {
+		errs = append(errs, err)
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE, TLS_KEY_FILE: both are required to enable TLS"))
+	}
+	if c.TLSCertFile != "" && len(c.TLSAutocertDomains) > 0 {
+		errs = append(errs, errors.New("TLS_AUTOCERT_DOMAINS: cannot be combined with TLS_CERT_FILE"))
+	}
+	if len(c.TLSAutocertDomains) > 0 && c.TLSAutocertCacheDir == "" {
+		errs = append(errs, errors.New("TLS_AUTOCERT_CACHE_DIR: required when TLS_AUTOCERT_DOMAINS is set"))
+	}
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"HTTP_READ_HEADER_TIMEOUT_SECONDS", c.HTTPReadHeaderTimeout},
+		{"HTTP_READ_TIMEOUT_SECONDS", c.HTTPReadTimeout},
+		{"HTTP_WRITE_TIMEOUT_SECONDS", c.HTTPWriteTimeout},
+		{"HTTP_IDLE_TIMEOUT_SECONDS", c.HTTPIdleTimeout},
+	} {
+		if timeout.value < 0 {
+			errs = append(errs, fmt.Errorf("%s: must not be negative, got %v", timeout.name, timeout.value.Seconds()))
+		}
+	}
+	switch c.ErrorReporter {
+	case "none":
+	case "sentry":
+		if u, err := url.Parse(c.SentryDSN); err != nil || u.User == nil || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+			errs = append(errs, errors.New("SENTRY_DSN: required when ERROR_REPORTER=sentry (https://<key>@<host>/<project>)"))
+		}
+	case "webhook":
+		if u, err := url.Parse(c.ErrorReporterWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, errors.New("ERROR_REPORTER_WEBHOOK_URL: http(s) URL required when ERROR_REPORTER=webhook"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("ERROR_REPORTER: unknown reporter %q (options: sentry, webhook, none)", c.ErrorReporter))
+	}
+	if c.DebugAddr != "" {
+		if _, port, err := net.SplitHostPort(c.DebugAddr); err != nil {
+			errs = append(errs, fmt.Errorf("DEBUG_ADDR: invalid address %q (expected host:port)", c.DebugAddr))
+		} else if err := validatePort("DEBUG_ADDR", port); err != nil {
+			errs = append(errs, err)
+		} else if port == c.ServerPort {
+			errs = append(errs, fmt.Errorf("DEBUG_ADDR: port %s is already used by SERVER_PORT", port))
+		}
+	}
+	if c.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("REQUEST_TIMEOUT_SECONDS: must not be negative, got %v", c.RequestTimeout.Seconds()))
+	}
+	if c.LatencyBudget < 0 {
+		errs = append(errs, fmt.Errorf("LATENCY_BUDGET_MS: must not be negative, got %d", c.LatencyBudget.Milliseconds()))
+	}
+	if c.LatencyWindow <= 0 {
+		errs = append(errs, fmt.Errorf("LATENCY_WINDOW_MINUTES: must be positive, got %v", c.LatencyWindow.Minutes()))
+	}
+	if c.ContentionWindow <= 0 {
+		errs = append(errs, fmt.Errorf("CONTENTION_WINDOW_MINUTES: must be positive, got %v", c.ContentionWindow.Minutes()))
+	}
+	if c.MaxRequestBodyKB <= 0 {
+		errs = append(errs, fmt.Errorf("MAX_REQUEST_BODY_KB: must be positive, got %d", c.MaxRequestBodyKB))
+	}
+	if c.MaxListLimit <= 0 {
+		errs = append(errs, fmt.Errorf("MAX_LIST_LIMIT: must be positive, got %d", c.MaxListLimit))
+	}
+	if c.CompressionMinBytes < 0 {
+		errs = append(errs, fmt.Errorf("COMPRESSION_MIN_BYTES: must not be negative, got %d", c.CompressionMinBytes))
+	}
+
+	if c.DatabaseDriver != "sqlite" {
+		errs = append(errs, fmt.Errorf("DATABASE_DRIVER: unsupported driver %q (options: sqlite)", c.DatabaseDriver))
+	}
+	if c.SQLitePath == "" {
+		errs = append(errs, errors.New("SQLITE_PATH: required"))
+	}
+	if c.BackupDir == "" {
+		errs = append(errs, errors.New("BACKUP_DIR: required"))
+	}
+	if c.BackupInterval < 0 {
+		errs = append(errs, fmt.Errorf("BACKUP_INTERVAL_HOURS: cannot be negative, got %v", c.BackupInterval.Hours()))
+	}
+	if c.BackupRetain < 1 {
+		errs = append(errs, fmt.Errorf("BACKUP_RETAIN: must be at least 1, got %d", c.BackupRetain))
+	}
+
+	switch strings.ToLower(c.MessageBroker) {
+	case "redis":
+		if c.RedisHost == "" {
+			errs = append(errs, errors.New("REDIS_HOST: required when MESSAGE_BROKER=redis"))
+		}
+		if c.RedisPort < 1 || c.RedisPort > 65535 {
+			errs = append(errs, fmt.Errorf("REDIS_PORT: %d out of range (1-65535)", c.RedisPort))
+		}
+	case "kafka":
+		if strings.TrimSpace(c.KafkaBrokers) == "" {
+			errs = append(errs, errors.New("KAFKA_BROKERS: required when MESSAGE_BROKER=kafka"))
+		}
+	case "nats":
+		if u, err := url.Parse(c.NATSURL); err != nil || u.Host == "" || (u.Scheme != "nats" && u.Scheme != "tls") {
+			errs = append(errs, fmt.Errorf("NATS_URL: invalid URL %q (expected nats://host:port or tls://host:port)", c.NATSURL))
+		}
+		if err := validateNATSSubjectTemplate(c.NATSSubjectTemplate); err != nil {
+			errs = append(errs, err)
+		}
+	case "gcppubsub":
+		if c.GCPProjectID == "" || c.PubSubTopic == "" {
+			errs = append(errs, errors.New("GCP_PROJECT_ID and PUBSUB_TOPIC: required when MESSAGE_BROKER=gcppubsub"))
+		}
+	case "sns":
+		if !strings.HasPrefix(c.SNSTopicARN, "arn:aws:sns:") {
+			errs = append(errs, fmt.Errorf("SNS_TOPIC_ARN: invalid topic ARN %q", c.SNSTopicARN))
+		}
+		if (c.AWSAccessKeyID == "") != (c.AWSSecretAccessKey == "") {
+			errs = append(errs, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY: set both or neither (SDK credential chain)"))
+		}
+	case "sqs":
+		if u, err := url.Parse(c.SQSQueueURL); err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+			errs = append(errs, fmt.Errorf("SQS_QUEUE_URL: invalid queue URL %q", c.SQSQueueURL))
+		}
+		if (c.AWSAccessKeyID == "") != (c.AWSSecretAccessKey == "") {
+			errs = append(errs, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY: set both or neither (SDK credential chain)"))
+		}
+	case "none", "":
+	default:
+		errs = append(errs, fmt.Errorf("MESSAGE_BROKER: unknown broker %q (options: redis, nats, gcppubsub, sns, sqs, kafka, none)", c.MessageBroker))
+	}
+
+	if c.AvailabilityCacheEnabled {
+		if c.RedisHost == "" {
+			errs = append(errs, errors.New("REDIS_HOST: required when AVAILABILITY_CACHE_ENABLED=true"))
+		}
+		if c.AvailabilityCacheTTL <= 0 {
+			errs = append(errs, fmt.Errorf("AVAILABILITY_CACHE_TTL_SECONDS: must be positive, got %v", c.AvailabilityCacheTTL.Seconds()))
+		}
+	}
+
+	switch c.SearchBackend {
+	case "elasticsearch", "meilisearch":
+		if c.SearchURL == "" {
+			errs = append(errs, fmt.Errorf("SEARCH_URL: required when SEARCH_BACKEND=%s", c.SearchBackend))
+		}
+		if c.SearchIndex == "" {
+			errs = append(errs, fmt.Errorf("SEARCH_INDEX: required when SEARCH_BACKEND=%s", c.SearchBackend))
+		}
+	case "none", "":
+	default:
+		errs = append(errs, fmt.Errorf("SEARCH_BACKEND: unknown backend %q (options: elasticsearch, meilisearch, none)", c.SearchBackend))
+	}
+
+	switch c.MediaStorage {
+	case "local":
+		if c.MediaLocalDir == "" {
+			errs = append(errs, errors.New("MEDIA_LOCAL_DIR: required when MEDIA_STORAGE=local"))
+		}
+		if c.ExportLocalDir == "" {
+			errs = append(errs, errors.New("EXPORT_LOCAL_DIR: required when MEDIA_STORAGE=local"))
+		}
+		if c.RetentionArchive && c.RetentionArchiveDir == "" {
+			errs = append(errs, errors.New("RETENTION_ARCHIVE_DIR: required when RETENTION_ARCHIVE_ENABLED=true and MEDIA_STORAGE=local"))
+		}
+		if !strings.HasPrefix(c.MediaPublicBaseURL, "/") && !strings.HasPrefix(c.MediaPublicBaseURL, "http") {
+			errs = append(errs, fmt.Errorf("MEDIA_PUBLIC_BASE_URL: must be a path or an absolute URL, got %q", c.MediaPublicBaseURL))
+		}
+	case "s3":
+		if c.MediaS3Bucket == "" {
+			errs = append(errs, errors.New("MEDIA_S3_BUCKET: required when MEDIA_STORAGE=s3"))
+		}
+		if c.AWSRegion == "" {
+			errs = append(errs, errors.New("AWS_REGION: required when MEDIA_STORAGE=s3"))
+		}
+		if (c.AWSAccessKeyID == "") != (c.AWSSecretAccessKey == "") {
+			errs = append(errs, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY: set both or neither (SDK credential chain)"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("MEDIA_STORAGE: unknown storage %q (options: local, s3)", c.MediaStorage))
+	}
+	if c.MediaMaxUploadMB <= 0 {
+		errs = append(errs, fmt.Errorf("MEDIA_MAX_UPLOAD_MB: must be positive, got %d", c.MediaMaxUploadMB))
+	}
+	if c.ExportRetention <= 0 {
+		errs = append(errs, fmt.Errorf("EXPORT_RETENTION_HOURS: must be positive, got %v", c.ExportRetention.Hours()))
+	}
+	if c.RetentionEvents < 0 {
+		errs = append(errs, fmt.Errorf("RETENTION_EVENTS_DAYS: cannot be negative, got %v", c.RetentionEvents.Hours()/24))
+	}
+	if c.RetentionReservations < 0 {
+		errs = append(errs, fmt.Errorf("RETENTION_RESERVATIONS_DAYS: cannot be negative, got %v", c.RetentionReservations.Hours()/24))
+	}
+	if c.CleanupMaxBatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("CLEANUP_MAX_BATCH_SIZE: must be positive, got %d", c.CleanupMaxBatchSize))
+	}
+	if c.CleanupBatchPause < 0 {
+		errs = append(errs, fmt.Errorf("CLEANUP_BATCH_PAUSE_MS: cannot be negative, got %d", c.CleanupBatchPause.Milliseconds()))
+	}
+	if c.ABCWindow < 0 {
+		errs = append(errs, fmt.Errorf("ABC_WINDOW_DAYS: cannot be negative, got %v", c.ABCWindow.Hours()/24))
+	}
+	if c.LedgerVerifyInterval < 0 {
+		errs = append(errs, fmt.Errorf("LEDGER_VERIFY_INTERVAL_HOURS: cannot be negative, got %v", c.LedgerVerifyInterval.Hours()))
+	}
+
+	if len(c.ProductLocales) == 0 {
+		errs = append(errs, errors.New("PRODUCT_LOCALES: at least one locale is required"))
+	}
+	for _, locale := range c.ProductLocales {
+		if strings.ContainsAny(locale, "-_ ") {
+			errs = append(errs, fmt.Errorf("PRODUCT_LOCALES: use primary language tags (es, ca, en), got %q", locale))
+		}
+	}
+
+	if c.PublisherBreakerFailures <= 0 {
+		errs = append(errs, fmt.Errorf("PUBLISHER_BREAKER_FAILURES: must be positive, got %d", c.PublisherBreakerFailures))
+	}
+	if c.PublisherBreakerOpenTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("PUBLISHER_BREAKER_OPEN_SECONDS: must be positive, got %v", c.PublisherBreakerOpenTimeout.Seconds()))
+	}
+
+	if c.ReservationDefaultTTL <= 0 {
+		errs = append(errs, fmt.Errorf("RESERVATION_DEFAULT_TTL_MINUTES: must be positive, got %d", c.ReservationDefaultTTL))
+	}
+	if c.ReservationMaxTTL < c.ReservationDefaultTTL {
+		errs = append(errs, fmt.Errorf("RESERVATION_MAX_TTL_MINUTES: %d is lower than the default TTL (%d)", c.ReservationMaxTTL, c.ReservationDefaultTTL))
+	}
+	if c.ReservationMaxUnitsPerCustomer < 0 {
+		errs = append(errs, fmt.Errorf("RESERVATION_MAX_UNITS_PER_CUSTOMER: must be zero (unlimited) or positive, got %d", c.ReservationMaxUnitsPerCustomer))
+	}
+	if c.ReservationMaxPendingPerCustomer < 0 {
+		errs = append(errs, fmt.Errorf("RESERVATION_MAX_PENDING_PER_CUSTOMER: must be zero (unlimited) or positive, got %d", c.ReservationMaxPendingPerCustomer))
+	}
+	if c.ConfirmHookURL != "" {
+		if u, err := url.Parse(c.ConfirmHookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, errors.New("CONFIRM_HOOK_URL: must be an http(s) URL"))
+		}
+		if c.ConfirmHookMode != "sync" && c.ConfirmHookMode != "async" {
+			errs = append(errs, fmt.Errorf("CONFIRM_HOOK_MODE: unknown mode %q (options: sync, async)", c.ConfirmHookMode))
+		}
+		if c.ConfirmHookTimeout <= 0 {
+			errs = append(errs, fmt.Errorf("CONFIRM_HOOK_TIMEOUT_SECONDS: must be positive, got %v", c.ConfirmHookTimeout.Seconds()))
+		}
+		if c.ConfirmHookMaxAttempts <= 0 {
+			errs = append(errs, fmt.Errorf("CONFIRM_HOOK_MAX_ATTEMPTS: must be positive, got %d", c.ConfirmHookMaxAttempts))
+		}
+		if c.ConfirmHookBackoff < 0 {
+			errs = append(errs, fmt.Errorf("CONFIRM_HOOK_RETRY_BACKOFF_MS: cannot be negative, got %d", c.ConfirmHookBackoff.Milliseconds()))
+		}
+	}
+	if c.AdjustmentApprovalMaxUnits < 0 {
+		errs = append(errs, fmt.Errorf("ADJUSTMENT_APPROVAL_MAX_UNITS: must be zero (disabled) or positive, got %d", c.AdjustmentApprovalMaxUnits))
+	}
+	if c.AdjustmentApprovalMaxPercent < 0 {
+		errs = append(errs, fmt.Errorf("ADJUSTMENT_APPROVAL_MAX_PERCENT: must be zero (disabled) or positive, got %v", c.AdjustmentApprovalMaxPercent))
+	}
+	if c.ReservationIntentTTL <= 0 {
+		errs = append(errs, fmt.Errorf("RESERVATION_INTENT_TTL_MINUTES: must be positive, got %v", c.ReservationIntentTTL.Minutes()))
+	}
+	if c.ReservationShortageGrace < 0 {
+		errs = append(errs, fmt.Errorf("RESERVATION_LOW_PRIORITY_SHORTAGE_GRACE_MINUTES: must be zero (disabled) or positive, got %v", c.ReservationShortageGrace.Minutes()))
+	}
+	if c.ReservationPickupWindow <= 0 {
+		errs = append(errs, fmt.Errorf("RESERVATION_PICKUP_WINDOW_HOURS: must be positive, got %v", c.ReservationPickupWindow.Hours()))
+	}
+	if c.FlashSaleQueueSize <= 0 {
+		errs = append(errs, fmt.Errorf("FLASH_SALE_QUEUE_SIZE: must be positive, got %d", c.FlashSaleQueueSize))
+	}
+	if c.FlashSaleTicketTTL <= 0 {
+		errs = append(errs, fmt.Errorf("FLASH_SALE_TICKET_TTL_MINUTES: must be positive, got %v", c.FlashSaleTicketTTL.Minutes()))
+	}
+	if c.StoreSyncStaleAfter <= 0 {
+		errs = append(errs, fmt.Errorf("STORE_SYNC_STALE_MINUTES: must be positive, got %v", c.StoreSyncStaleAfter.Minutes()))
+	}
+	if c.SMTPHost != "" {
+		if c.SMTPPort <= 0 || c.SMTPPort > 65535 {
+			errs = append(errs, fmt.Errorf("SMTP_PORT: must be a valid port, got %d", c.SMTPPort))
+		}
+		if _, err := mail.ParseAddress(c.SMTPFrom); err != nil {
+			errs = append(errs, errors.New("SMTP_FROM: required when SMTP_HOST is set (e.g. Tienda <avisos@example.com>)"))
+		}
+		if c.SMTPTimeout <= 0 {
+			errs = append(errs, fmt.Errorf("SMTP_TIMEOUT_SECONDS: must be positive, got %v", c.SMTPTimeout.Seconds()))
+		}
+	}
+
+	if len(c.APIKeys) == 0 {
+		errs = append(errs, errors.New("API_KEYS: at least one key is required"))
+	}
+	if c.Environment == EnvProduction {
+		for key := range c.APIKeys {
+			if strings.HasPrefix(key, "dev-key-") {
+				errs = append(errs, fmt.Errorf("API_KEYS: development key %q is not allowed in production", redactKey(key)))
+			}
+		}
+	}
+	if len(c.APIKeyStoreScopes) > 0 {
+		names := make(map[string]bool, len(c.APIKeys))
+		for _, name := range c.APIKeys {
+			names[name] = true
+		}
+		scoped := make([]string, 0, len(c.APIKeyStoreScopes))
+		for name := range c.APIKeyStoreScopes {
+			scoped = append(scoped, name)
+		}
+		sort.Strings(scoped)
+		for _, name := range scoped {
+			if !names[name] {
+				errs = append(errs, fmt.Errorf("API_KEY_STORE_SCOPES: no key named %q in API_KEYS", name))
+			}
+		}
+	}
+
+	if _, err := regexp.Compile(c.SKUPattern); err != nil {
+		errs = append(errs, fmt.Errorf("SKU_PATTERN: invalid regular expression: %v", err))
+	}
+	if c.SKUMaxLength <= 0 {
+		errs = append(errs, fmt.Errorf("SKU_MAX_LENGTH: must be positive, got %d", c.SKUMaxLength))
+	}
+
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: unknown level %q (options: debug, info, warn, error)", c.LogLevel))
+	}
+	switch c.LogFormat {
+	case "json", "text":
+	default:
+		errs = append(errs, fmt.Errorf("LOG_FORMAT: unknown format %q (options: json, text)", c.LogFormat))
+	}
+
+	if c.SecretsRefreshInterval < 0 {
+		errs = append(errs, fmt.Errorf("SECRETS_REFRESH_SECONDS: must not be negative, got %v", c.SecretsRefreshInterval.Seconds()))
+	}
+
+	if c.EnableMetrics && c.MetricsLowStockTopN <= 0 {
+		errs = append(errs, fmt.Errorf("METRICS_LOW_STOCK_TOP_N: must be positive, got %d", c.MetricsLowStockTopN))
+	}
+
+	if c.SandboxMode {
+		if c.Environment == EnvProduction {
+			errs = append(errs, errors.New("SANDBOX_MODE: not allowed in production"))
+		}
+		if c.SandboxErrorRate < 0 || c.SandboxErrorRate > 1 {
+			errs = append(errs, fmt.Errorf("SANDBOX_ERROR_RATE: %v out of range (0-1)", c.SandboxErrorRate))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validatePort(key, value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s: invalid port %q", key, value)
+	}
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("%s: %d out of range (1-65535)", key, port)
+	}
+	return nil
+}
+
+// natsPlaceholder reconoce los placeholders de NATS_SUBJECT_TEMPLATE
+var natsPlaceholder = regexp.MustCompile(`\{[^}]*\}`)
+
+// validateNATSSubjectTemplate exige un subject sin comodines ni espacios, que empiece por un
+// token literal (el stream se crea con "<prefijo>.>") y solo con placeholders conocidos
+func validateNATSSubjectTemplate(template string) error {
+	if template == "" || strings.ContainsAny(template, " \t*>") {
+		return fmt.Errorf("NATS_SUBJECT_TEMPLATE: invalid subject %q", template)
+	}
+	if strings.HasPrefix(template, "{") || strings.HasPrefix(template, ".") {
+		return fmt.Errorf("NATS_SUBJECT_TEMPLATE: %q must start with a literal token (e.g. inventory.)", template)
+	}
+	for _, placeholder := range natsPlaceholder.FindAllString(template, -1) {
+		switch placeholder {
+		case "{store_id}", "{event_type}", "{aggregate_type}":
+		default:
+			return fmt.Errorf("NATS_SUBJECT_TEMPLATE: unknown placeholder %s (options: {store_id}, {event_type}, {aggregate_type})", placeholder)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// RestoreBackup reemplaza la base de datos dbPath por el backup backupPath. Comprueba antes la
+// integridad del backup y conserva la base de datos actual como <dbPath>.pre-restore-<timestamp>.
+// El servidor debe estar parado: las conexiones abiertas seguirían usando el fichero anterior.
+func RestoreBackup(backupPath, dbPath string) (string, error) {
+	if dbPath == ":memory:" || strings.Contains(dbPath, "mode=memory") {
+		return "", fmt.Errorf("cannot restore into an in-memory database")
+	}
+
+	if _, err := os.Stat(backupPath); err != nil {
+		return "", fmt.Errorf("backup not found: %w", err)
+	}
+	if err := checkIntegrity(backupPath); err != nil {
+		return "", err
+	}
+
+	// Copiar junto al destino y renombrar: nunca queda una base de datos a medio escribir
+	tmp := dbPath + ".restore-tmp"
+	if err := copyFile(backupPath, tmp); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+
+	var previous string
+	if _, err := os.Stat(dbPath); err == nil {
+		previous = dbPath + ".pre-restore-" + time.Now().UTC().Format("20060102T150405Z")
+		if err := os.Rename(dbPath, previous); err != nil {
+			os.Remove(tmp)
+			return "", fmt.Errorf("failed to keep current database: %w", err)
+		}
+	}
+
+	// El WAL de la base de datos anterior no corresponde al backup
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !os.IsNotExist(err) {
+			return previous, fmt.Errorf("failed to remove %s%s: %w", dbPath, suffix, err)
+		}
+	}
+
+	if err := os.Rename(tmp, dbPath); err != nil {
+		return previous, fmt.Errorf("failed to restore database: %w", err)
+	}
+
+	return previous, nil
+}
+
+// checkIntegrity ejecuta PRAGMA integrity_check sobre el fichero
+func checkIntegrity(path string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow(`PRAGMA integrity_check`).Scan(&result); err != nil {
+		return fmt.Errorf("backup is not a valid SQLite database: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("backup failed integrity check: %s", result)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy backup: %w", err)
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to sync %s: %w", dst, err)
+	}
+	return out.Close()
+}
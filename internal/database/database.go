@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"inventory-system/internal/config"
@@ -26,11 +27,6 @@ func NewDatabaseClient(cfg *config.Config) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to open sqlite connection: %w", err)
 	}
 
-	// Para :memory:, usar una sola conexión (evita crear múltiples DBs en memoria)
-	if cfg.SQLitePath == ":memory:" {
-		db.SetMaxOpenConns(1)
-	}
-
 	log.Printf("✅ Connected to SQLite database: %s", cfg.SQLitePath)
 
 	// Verificar la conexión
@@ -43,5 +39,13 @@ func NewDatabaseClient(cfg *config.Config) (*sql.DB, error) {
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(5 * time.Minute)
 
+	// Para BDs en memoria, usar una sola conexión que nunca se recicle
+	// (cada conexión nueva a :memory: crearía una BD vacía)
+	if cfg.SQLitePath == ":memory:" || strings.Contains(cfg.SQLitePath, "mode=memory") {
+		db.SetMaxOpenConns(1)
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxLifetime(0)
+	}
+
 	return db, nil
 }
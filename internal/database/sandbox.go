@@ -0,0 +1,78 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+
+	"github.com/google/uuid"
+)
+
+// sandboxNamespace genera IDs de producto deterministas (UUID v5) para el modo sandbox
+var sandboxNamespace = uuid.MustParse("6f1c2d4e-7a8b-4c9d-8e0f-1a2b3c4d5e6f")
+
+var sandboxCategories = []string{"electronics", "accessories", "home", "sports", "toys"}
+
+// SeedSandboxData inserta un catálogo sintético de n productos con stock en todas las tiendas.
+// Con la misma seed se generan siempre los mismos IDs, SKUs, precios y cantidades.
+func SeedSandboxData(db *sql.DB, products int, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+
+	rows, err := db.Query(`SELECT id FROM stores ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("failed to list stores: %w", err)
+	}
+	var stores []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan store: %w", err)
+		}
+		stores = append(stores, id)
+	}
+	rows.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := 1; i <= products; i++ {
+		sku := fmt.Sprintf("SBX-%04d", i)
+		productID := uuid.NewSHA1(sandboxNamespace, []byte(sku)).String()
+		category := sandboxCategories[rng.Intn(len(sandboxCategories))]
+		price := float64(rng.Intn(50000)+199) / 100
+
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO products (id, sku, name, description, category, price)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, productID, sku, fmt.Sprintf("Sandbox Product %04d", i), "Producto sintético del modo sandbox", category, price)
+		if err != nil {
+			return fmt.Errorf("failed to seed product %s: %w", sku, err)
+		}
+
+		for _, storeID := range stores {
+			// ~10% de las filas sin stock para poder probar errores de disponibilidad
+			quantity := 0
+			if rng.Intn(10) > 0 {
+				quantity = rng.Intn(200) + 1
+			}
+
+			_, err := tx.Exec(`
+				INSERT OR IGNORE INTO stock (id, product_id, store_id, quantity, reserved, min_stock, version)
+				VALUES (?, ?, ?, ?, 0, ?, 1)
+			`, fmt.Sprintf("sbx-%s-%s", storeID, sku), productID, storeID, quantity, 10)
+			if err != nil {
+				return fmt.Errorf("failed to seed stock %s/%s: %w", sku, storeID, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sandbox seed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// migration paso versionado del esquema.
+//
+// CREATE TABLE IF NOT EXISTS no modifica una tabla que ya existe, así que los cambios sobre
+// tablas del esquema base (columnas nuevas, constraints) van aquí y no en su CREATE TABLE.
+// Los pasos se aplican en orden, cada uno en su propia transacción, y se registran en
+// schema_version: una base de datos existente recibe solo los que le faltan. Deben ser
+// idempotentes, porque una base de datos creada antes de existir schema_version puede tener ya
+// el cambio aunque no esté registrado.
+type migration struct {
+	version     int
+	description string
+	apply       func(tx *sql.Tx) error
+}
+
+// migrations pasos del esquema. Solo se añaden al final: nunca se renumeran ni se modifican
+// los ya publicados.
+var migrations = []migration{
+	{1, "reservations.confirmed_at", func(tx *sql.Tx) error {
+		if err := addColumn(tx, "reservations", "confirmed_at", "TIMESTAMP NULL"); err != nil {
+			return err
+		}
+		// Las reservas confirmadas antes del cambio toman su última actualización
+		_, err := tx.Exec(`
+			UPDATE reservations SET confirmed_at = COALESCE(updated_at, created_at)
+			WHERE status = 'CONFIRMED' AND confirmed_at IS NULL`)
+		return err
+	}},
+	{2, "stock.min_stock y stock.max_stock", func(tx *sql.Tx) error {
+		if err := addColumn(tx, "stock", "min_stock", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return err
+		}
+		return addColumn(tx, "stock", "max_stock", "INTEGER NOT NULL DEFAULT 0")
+	}},
+	{3, "events.seq, events.prev_hash y events.hash", func(tx *sql.Tx) error {
+		// ADD COLUMN no admite UNIQUE: la unicidad de seq va en un índice
+		if err := addColumn(tx, "events", "seq", "INTEGER"); err != nil {
+			return err
+		}
+		if err := addColumn(tx, "events", "prev_hash", "TEXT"); err != nil {
+			return err
+		}
+		if err := addColumn(tx, "events", "hash", "TEXT"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_events_seq ON events(seq)`)
+		return err
+	}},
+	{4, "events.correlation_id", func(tx *sql.Tx) error {
+		if err := addColumn(tx, "events", "correlation_id", "TEXT"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_events_correlation ON events(correlation_id)`)
+		return err
+	}},
+	{5, "products.status", func(tx *sql.Tx) error {
+		if err := addColumn(tx, "products", "status",
+			"TEXT NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('DRAFT', 'ACTIVE', 'DISCONTINUED'))"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_products_status_created ON products(status, created_at)`)
+		return err
+	}},
+	{6, "reservations.priority", func(tx *sql.Tx) error {
+		return addColumn(tx, "reservations", "priority",
+			"TEXT NOT NULL DEFAULT 'NORMAL' CHECK (priority IN ('LOW', 'NORMAL', 'HIGH'))")
+	}},
+	{7, "stock.safety_stock", func(tx *sql.Tx) error {
+		return addColumn(tx, "stock", "safety_stock", "INTEGER NOT NULL DEFAULT 0 CHECK (safety_stock >= 0)")
+	}},
+	{8, "reservations.channel", func(tx *sql.Tx) error {
+		return addColumn(tx, "reservations", "channel",
+			"TEXT NOT NULL DEFAULT '' CHECK (channel IN ('', 'WEB', 'STORE', 'MARKETPLACE'))")
+	}},
+	{9, "products.abc_class", func(tx *sql.Tx) error {
+		if err := addColumn(tx, "products", "abc_class",
+			"TEXT NOT NULL DEFAULT '' CHECK (abc_class IN ('', 'A', 'B', 'C'))"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_products_abc_class ON products(abc_class)`)
+		return err
+	}},
+	{10, "stock.held", func(tx *sql.Tx) error {
+		return addColumn(tx, "stock", "held", "INTEGER NOT NULL DEFAULT 0 CHECK (held >= 0)")
+	}},
+	{11, "events.actor", func(tx *sql.Tx) error {
+		return addColumn(tx, "events", "actor", "TEXT")
+	}},
+	{12, "product_units.rounding", func(tx *sql.Tx) error {
+		return addColumn(tx, "product_units", "rounding", "TEXT NOT NULL DEFAULT 'NEAREST'")
+	}},
+	{13, "scheduled_stock_changes.source y scheduled_stock_changes.reference", func(tx *sql.Tx) error {
+		if err := addColumn(tx, "scheduled_stock_changes", "source",
+			"TEXT NOT NULL DEFAULT 'MANUAL' CHECK (source IN ('MANUAL', 'PURCHASE_ORDER', 'TRANSFER'))"); err != nil {
+			return err
+		}
+		return addColumn(tx, "scheduled_stock_changes", "reference", "TEXT NOT NULL DEFAULT ''")
+	}},
+	{14, "reservations: estado PREORDER", func(tx *sql.Tx) error {
+		return rebuildTable(tx, "reservations", reservationsTableSQL(
+			"'PENDING', 'CONFIRMED', 'CANCELLED', 'EXPIRED', 'PREORDER'"))
+	}},
+	{15, "stores.location_type", func(tx *sql.Tx) error {
+		return addColumn(tx, "stores", "location_type",
+			"TEXT NOT NULL DEFAULT 'STORE' CHECK (location_type IN ('STORE', 'WAREHOUSE', 'DARKSTORE'))")
+	}},
+	{16, "reservations: estados READY_FOR_PICKUP y PICKED_UP", func(tx *sql.Tx) error {
+		if err := rebuildTable(tx, "reservations", reservationsTableSQL(
+			"'PENDING', 'CONFIRMED', 'CANCELLED', 'EXPIRED', 'PREORDER', 'READY_FOR_PICKUP', 'PICKED_UP'")); err != nil {
+			return err
+		}
+		// El worker de expiración también caduca las recogidas no retiradas
+		_, err := tx.Exec(`
+			DROP INDEX IF EXISTS idx_reservations_expires;
+			CREATE INDEX idx_reservations_expires ON reservations(expires_at) WHERE status IN ('PENDING', 'READY_FOR_PICKUP');`)
+		return err
+	}},
+	{17, "stock: sin CHECK de quantity no negativa (tolerancia de sobreventa)", func(tx *sql.Tx) error {
+		// Con sobreventa quantity puede quedar negativa y por debajo de reserved. Sin tolerancia
+		// el suelo lo aplica StockRepository.
+		return rebuildTable(tx, "stock", stockTableSQL)
+	}},
+}
+
+// migrate aplica los pasos de migrations que no constan en schema_version
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_version (
+		    version INTEGER PRIMARY KEY,
+		    description TEXT NOT NULL,
+		    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return fmt.Errorf("failed to create schema_version: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return fmt.Errorf("failed to apply schema migration %d (%s): %w", m.version, m.description, err)
+		}
+		log.Printf("🗄️  Applied schema migration %d: %s", m.version, m.description)
+	}
+
+	return nil
+}
+
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.apply(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_version (version, description) VALUES (?, ?)`,
+		m.version, m.description); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// addColumn añade una columna si la tabla no la tiene ya
+func addColumn(tx *sql.Tx, table, column, definition string) error {
+	columns, err := tableColumns(tx, table)
+	if err != nil {
+		return err
+	}
+	for _, existing := range columns {
+		if existing == column {
+			return nil
+		}
+	}
+
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// rebuildTable sustituye una tabla por otra con la definición createSQL (que crea <table>_new),
+// copiando las columnas comunes y recreando los índices. Es la forma de cambiar constraints en
+// SQLite, que ALTER TABLE no permite modificar.
+func rebuildTable(tx *sql.Tx, table, createSQL string) error {
+	newTable := table + "_new"
+
+	rows, err := tx.Query(`SELECT sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL`, table)
+	if err != nil {
+		return err
+	}
+	var indexes []string
+	for rows.Next() {
+		var indexSQL string
+		if err := rows.Scan(&indexSQL); err != nil {
+			rows.Close()
+			return err
+		}
+		indexes = append(indexes, indexSQL)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", newTable)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(createSQL); err != nil {
+		return err
+	}
+
+	oldColumns, err := tableColumns(tx, table)
+	if err != nil {
+		return err
+	}
+	newColumns, err := tableColumns(tx, newTable)
+	if err != nil {
+		return err
+	}
+	var common []string
+	for _, column := range oldColumns {
+		for _, candidate := range newColumns {
+			if column == candidate {
+				common = append(common, column)
+				break
+			}
+		}
+	}
+	columnList := strings.Join(common, ", ")
+
+	if _, err := tx.Exec(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+		newTable, columnList, columnList, table)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE %s", table)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s RENAME TO %s", newTable, table)); err != nil {
+		return err
+	}
+	for _, indexSQL := range indexes {
+		if _, err := tx.Exec(indexSQL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tableColumns nombres de las columnas de una tabla
+func tableColumns(tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var (
+			cid          int
+			name, kind   string
+			notNull, pk  int
+			defaultValue sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+
+	return columns, rows.Err()
+}
+
+// reservationsTableSQL definición de reservations (como reservations_new) con los estados dados
+func reservationsTableSQL(statuses string) string {
+	return `
+CREATE TABLE reservations_new (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    customer_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status TEXT NOT NULL CHECK (status IN (` + statuses + `)),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    confirmed_at TIMESTAMP NULL,
+    priority TEXT NOT NULL DEFAULT 'NORMAL' CHECK (priority IN ('LOW', 'NORMAL', 'HIGH')),
+    channel TEXT NOT NULL DEFAULT '' CHECK (channel IN ('', 'WEB', 'STORE', 'MARKETPLACE')), -- Canal de venta ('' = sin canal)
+
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+)`
+}
+
+// stockTableSQL definición de stock (como stock_new) sin los CHECK de quantity
+const stockTableSQL = `
+CREATE TABLE stock_new (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0, -- Negativa solo con tolerancia de sobreventa (oversell_tolerances)
+    reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    min_stock INTEGER NOT NULL DEFAULT 0,
+    max_stock INTEGER NOT NULL DEFAULT 0,
+    safety_stock INTEGER NOT NULL DEFAULT 0 CHECK (safety_stock >= 0), -- Para venta en tienda: fuera de lo reservable
+    held INTEGER NOT NULL DEFAULT 0 CHECK (held >= 0), -- Parte de reserved retenida para uso interno (stock_holds)
+
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    UNIQUE(product_id, store_id)
+)`
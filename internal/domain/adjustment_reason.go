@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// Códigos de motivo que se crean con el esquema
+const (
+	AdjustmentReasonDamaged    = "damaged"    // Producto dañado o roto
+	AdjustmentReasonShrinkage  = "shrinkage"  // Merma: robo, pérdida o caducidad
+	AdjustmentReasonFound      = "found"      // Unidades encontradas en un recuento
+	AdjustmentReasonCorrection = "correction" // Corrección de un error de registro
+)
+
+var adjustmentReasonCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+// AdjustmentReason motivo del catálogo con el que se registran los ajustes manuales de stock.
+// Los motivos no se borran (los movimientos ya registrados los referencian): se desactivan.
+type AdjustmentReason struct {
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Validate valida el código y la descripción del motivo
+func (r *AdjustmentReason) Validate() error {
+	if !adjustmentReasonCodePattern.MatchString(r.Code) {
+		return &ValidationError{Field: "code", Message: "code must be 2-32 lowercase letters, digits or underscores, starting with a letter"}
+	}
+	if r.Description == "" {
+		return &ValidationError{Field: "description", Message: "description is required"}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// APIKeyUnusedThreshold tiempo sin uso a partir del cual una API key es candidata a revocación
+const APIKeyUnusedThreshold = 90 * 24 * time.Hour
+
+// APIKeyID obtiene un identificador estable y no secreto para una API key.
+// Se usa en rutas y reportes para no exponer la key en claro.
+func APIKeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// APIKeyUsageDay representa el rollup diario de uso de una API key
+type APIKeyUsageDay struct {
+	Day          string    `json:"day"`
+	RequestCount int       `json:"request_count"`
+	ErrorCount   int       `json:"error_count"`
+	LastUsedAt   time.Time `json:"last_used_at"`
+}
+
+// APIKeyUsage representa el resumen de uso de una API key
+type APIKeyUsage struct {
+	KeyID               string           `json:"key_id"`
+	Name                string           `json:"name"`
+	TotalRequests       int              `json:"total_requests"`
+	TotalErrors         int              `json:"total_errors"`
+	ErrorRate           float64          `json:"error_rate"`
+	LastUsedAt          *time.Time       `json:"last_used_at,omitempty"`
+	RevocationCandidate bool             `json:"revocation_candidate"`
+	Daily               []APIKeyUsageDay `json:"daily,omitempty"`
+}
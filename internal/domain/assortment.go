@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// JobStatus representa el estado de un job asíncrono
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "PENDING"
+	JobStatusRunning   JobStatus = "RUNNING"
+	JobStatusCompleted JobStatus = "COMPLETED"
+	JobStatusFailed    JobStatus = "FAILED"
+)
+
+// AssortmentCloneJob representa un job de clonación de surtido desde una tienda a otras
+type AssortmentCloneJob struct {
+	ID             string                  `json:"id"`
+	SourceStoreID  string                  `json:"source_store_id"`
+	TargetStoreIDs []string                `json:"target_store_ids"`
+	CopyThresholds bool                    `json:"copy_thresholds"`
+	Status         JobStatus               `json:"status"`
+	Report         []AssortmentCloneResult `json:"report"`
+	Error          string                  `json:"error,omitempty"`
+	CreatedAt      time.Time               `json:"created_at"`
+	StartedAt      *time.Time              `json:"started_at,omitempty"`
+	CompletedAt    *time.Time              `json:"completed_at,omitempty"`
+}
+
+// AssortmentCloneResult representa el resultado de la clonación en una tienda destino
+type AssortmentCloneResult struct {
+	StoreID           string `json:"store_id"`
+	Created           int    `json:"created"`
+	ThresholdsUpdated int    `json:"thresholds_updated"`
+	Skipped           int    `json:"skipped"`
+	Error             string `json:"error,omitempty"`
+}
+
+// Validate verifica que el job tenga datos válidos
+func (j *AssortmentCloneJob) Validate() error {
+	if j.SourceStoreID == "" {
+		return &ValidationError{Field: "source_store_id", Message: "Source store ID is required"}
+	}
+	if len(j.TargetStoreIDs) == 0 {
+		return &ValidationError{Field: "target_store_ids", Message: "At least one target store is required"}
+	}
+	for _, target := range j.TargetStoreIDs {
+		if target == "" {
+			return &ValidationError{Field: "target_store_ids", Message: "Target store ID cannot be empty"}
+		}
+		if target == j.SourceStoreID {
+			return &ValidationError{Field: "target_store_ids", Message: "Target store cannot be the source store"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// MaxAuditIssues limita cuántas incidencias se reportan en una verificación
+const MaxAuditIssues = 100
+
+// auditRecord son los campos inmutables de un evento que entran en el hash.
+// synced/synced_at quedan fuera porque cambian al sincronizar con el broker.
+type auditRecord struct {
+	Seq           int64  `json:"seq"`
+	ID            string `json:"id"`
+	EventType     string `json:"event_type"`
+	AggregateID   string `json:"aggregate_id"`
+	AggregateType string `json:"aggregate_type"`
+	StoreID       string `json:"store_id"`
+	Payload       string `json:"payload"`
+	CreatedAt     string `json:"created_at"`
+	PrevHash      string `json:"prev_hash"`
+}
+
+// ComputeEventHash calcula el hash SHA-256 (hex) de un evento encadenado al hash anterior
+func ComputeEventHash(e *Event, prevHash string) string {
+	record, _ := json.Marshal(auditRecord{
+		Seq:           e.Seq,
+		ID:            e.ID,
+		EventType:     e.EventType,
+		AggregateID:   e.AggregateID,
+		AggregateType: e.AggregateType,
+		StoreID:       e.StoreID,
+		Payload:       e.Payload,
+		CreatedAt:     e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:      prevHash,
+	})
+
+	sum := sha256.Sum256(record)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditIssue describe un punto de la cadena que no supera la verificación
+type AuditIssue struct {
+	Seq     int64  `json:"seq"`
+	EventID string `json:"event_id"`
+	Reason  string `json:"reason"`
+}
+
+// AuditVerification es el resultado de verificar un tramo de la cadena
+type AuditVerification struct {
+	FromSeq   int64        `json:"from_seq"`
+	ToSeq     int64        `json:"to_seq"`
+	Checked   int          `json:"checked"`
+	Valid     bool         `json:"valid"`
+	HeadHash  string       `json:"head_hash,omitempty"` // Hash del último registro verificado
+	Issues    []AuditIssue `json:"issues"`
+	Truncated bool         `json:"truncated,omitempty"` // true si hay más de MaxAuditIssues incidencias
+}
+
+// AddIssue registra una incidencia respetando MaxAuditIssues
+func (v *AuditVerification) AddIssue(issue AuditIssue) {
+	v.Valid = false
+	if len(v.Issues) >= MaxAuditIssues {
+		v.Truncated = true
+		return
+	}
+	v.Issues = append(v.Issues, issue)
+}
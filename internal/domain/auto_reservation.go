@@ -0,0 +1,19 @@
+package domain
+
+// AutoReservationAnyStore valor de stores que deja elegir entre todas las tiendas con stock
+const AutoReservationAnyStore = "any"
+
+// AutoReservation resultado de una reserva con selección automática de tienda: una reserva
+// por tienda elegida (varias solo si se permite repartir la cantidad)
+type AutoReservation struct {
+	ProductID    string         `json:"productId"`
+	Requested    int            `json:"requested"`
+	Split        bool           `json:"split"`             // La cantidad se repartió entre varias tiendas
+	GroupID      string         `json:"groupId,omitempty"` // Con reparto: grupo que confirma o cancela todas las reservas
+	Reservations []*Reservation `json:"reservations"`
+}
+
+// IsAnyStore indica si la lista de tiendas pide elegir entre cualquiera (vacía o "any")
+func IsAnyStore(stores []string) bool {
+	return len(stores) == 0 || (len(stores) == 1 && stores[0] == AutoReservationAnyStore)
+}
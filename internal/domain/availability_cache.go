@@ -0,0 +1,31 @@
+package domain
+
+import "context"
+
+// AvailabilityCache cachea la disponibilidad (quantity - reserved) por producto y tienda
+// para responder las consultas sin leer la tabla stock. La BD sigue siendo la fuente de
+// verdad: el cache se rellena desde ella en cada miss y se mantiene al día con los
+// eventos de reservas y stock.
+//
+// Implementaciones disponibles:
+//   - RedisAvailabilityCache: contadores atómicos en Redis compartidos por todas las instancias
+type AvailabilityCache interface {
+	// Get retorna la disponibilidad cacheada. ok es false si la clave no existe (miss).
+	Get(ctx context.Context, productID, storeID string) (available int, ok bool, err error)
+
+	// Set guarda la disponibilidad leída de la BD
+	Set(ctx context.Context, productID, storeID string, available int) error
+
+	// Reserve descuenta quantity de la disponibilidad (DECRBY). Sin clave no hace nada:
+	// el siguiente Get será un miss y se leerá la BD.
+	Reserve(ctx context.Context, productID, storeID string, quantity int) error
+
+	// Release devuelve quantity a la disponibilidad (INCRBY). Sin clave no hace nada.
+	Release(ctx context.Context, productID, storeID string, quantity int) error
+
+	// Invalidate elimina la entrada para que el siguiente Get lea la BD
+	Invalidate(ctx context.Context, productID, storeID string) error
+
+	// Close libera la conexión
+	Close() error
+}
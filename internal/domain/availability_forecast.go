@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// AvailabilityForecast disponibilidad prevista de una fila de stock en una fecha, para prometer
+// preventas: parte del stock actual, libera las reservas pendientes que caducan antes y aplica
+// los cambios programados (pedidos de compra, transferencias) que llegan antes
+type AvailabilityForecast struct {
+	ProductID            string                  `json:"product_id"`
+	StoreID              string                  `json:"store_id"`
+	Date                 time.Time               `json:"date"`      // Se cuenta todo lo anterior a esta fecha
+	Quantity             int                     `json:"quantity"`  // Actual
+	Reserved             int                     `json:"reserved"`  // Actual
+	Available            int                     `json:"available"` // Vendible actual (sin stock de seguridad)
+	SafetyStock          int                     `json:"safety_stock"`
+	ExpiringReservations int                     `json:"expiring_reservations"` // Unidades de reservas PENDING que caducan antes
+	Inbound              int                     `json:"inbound"`               // Unidades que entran por cambios programados
+	Outbound             int                     `json:"outbound"`              // Unidades que salen por cambios programados
+	Preordered           int                     `json:"preordered"`            // Unidades comprometidas en preventas sin asignar
+	ProjectedQuantity    int                     `json:"projected_quantity"`
+	ProjectedReserved    int                     `json:"projected_reserved"`
+	ProjectedAvailable   int                     `json:"projected_available"` // Vendible prevista en la fecha
+	ScheduledChanges     []*ScheduledStockChange `json:"scheduled_changes"`   // Cambios pendientes incluidos
+	Requested            int                     `json:"requested,omitempty"`
+	Sufficient           *bool                   `json:"sufficient,omitempty"` // Solo si se indica la cantidad
+}
+
+// NewAvailabilityForecast proyecta la fila de stock a la fecha. Asume que las reservas pendientes
+// que caducan antes no se confirman, que los cambios programados se aplican en orden y que las
+// preventas sin asignar ya ocupan su parte de lo entrante.
+func NewAvailabilityForecast(stock *Stock, date time.Time, expiringReservations, preordered int, changes []*ScheduledStockChange) *AvailabilityForecast {
+	forecast := &AvailabilityForecast{
+		ProductID:            stock.ProductID,
+		StoreID:              stock.StoreID,
+		Date:                 date,
+		Quantity:             stock.Quantity,
+		Reserved:             stock.Reserved,
+		Available:            stock.Sellable(),
+		SafetyStock:          stock.SafetyStock,
+		ExpiringReservations: expiringReservations,
+		Preordered:           preordered,
+		ScheduledChanges:     changes,
+	}
+
+	quantity := stock.Quantity
+	for _, change := range changes {
+		next := change.NewQuantity(quantity)
+		if next > quantity {
+			forecast.Inbound += next - quantity
+		} else {
+			forecast.Outbound += quantity - next
+		}
+		quantity = next
+	}
+
+	forecast.ProjectedQuantity = quantity
+	forecast.ProjectedReserved = stock.Reserved - expiringReservations
+	if forecast.ProjectedReserved < stock.Held {
+		forecast.ProjectedReserved = stock.Held
+	}
+	forecast.ProjectedReserved += preordered
+	forecast.ProjectedAvailable = forecast.ProjectedQuantity - forecast.ProjectedReserved - stock.SafetyStock
+	return forecast
+}
+
+// Check registra si la cantidad pedida cabe en la disponibilidad prevista
+func (f *AvailabilityForecast) Check(quantity int) {
+	sufficient := f.ProjectedAvailable >= quantity
+	f.Requested = quantity
+	f.Sufficient = &sufficient
+}
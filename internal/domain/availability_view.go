@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// AvailabilityView fila del read model de disponibilidad: la cantidad vendible de un producto en
+// una tienda ya calculada (quantity - reserved - safety_stock) y su tramo, para que las consultas
+// de disponibilidad y stock bajo no tengan que calcularla sobre la tabla stock
+type AvailabilityView struct {
+	ProductID string             `json:"product_id"`
+	StoreID   string             `json:"store_id"`
+	Available int                `json:"available"`
+	MinStock  int                `json:"min_stock"` // Umbral de la fila (0 = DefaultMinStock en el tramo)
+	Status    AvailabilityStatus `json:"status"`    // Tramo con el umbral de la fila (sin StockVisibility)
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// AvailabilityViewRebuild resumen de una reconstrucción completa del read model
+type AvailabilityViewRebuild struct {
+	Rows      int       `json:"rows"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration"`
+}
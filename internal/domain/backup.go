@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// Backup copia completa de la base de datos SQLite generada con VACUUM INTO
+type Backup struct {
+	Name      string    `json:"name" example:"inventory-20260415T030000.000Z.db"`
+	SizeBytes int64     `json:"size_bytes" example:"5242880"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// SalesChannel canal de venta que origina una reserva o una consulta de disponibilidad
+type SalesChannel string
+
+const (
+	SalesChannelWeb         SalesChannel = "WEB"
+	SalesChannelStore       SalesChannel = "STORE"
+	SalesChannelMarketplace SalesChannel = "MARKETPLACE"
+)
+
+// IsValid verifica si el canal es válido
+func (c SalesChannel) IsValid() bool {
+	switch c {
+	case SalesChannelWeb, SalesChannelStore, SalesChannelMarketplace:
+		return true
+	}
+	return false
+}
+
+// ParseSalesChannel normaliza el canal recibido (vacío = sin canal)
+func ParseSalesChannel(value string) (SalesChannel, error) {
+	channel := SalesChannel(strings.ToUpper(strings.TrimSpace(value)))
+	if channel != "" && !channel.IsValid() {
+		return "", &ValidationError{Field: "channel", Message: "channel must be WEB, STORE or MARKETPLACE"}
+	}
+	return channel, nil
+}
+
+// salesChannelKey clave privada del context para el canal de venta
+type salesChannelKey struct{}
+
+// WithSalesChannel devuelve un context que transporta el canal de venta del request
+func WithSalesChannel(ctx context.Context, channel SalesChannel) context.Context {
+	return context.WithValue(ctx, salesChannelKey{}, channel)
+}
+
+// SalesChannelFromContext obtiene el canal de venta del context ("" si no hay)
+func SalesChannelFromContext(ctx context.Context) SalesChannel {
+	if ctx == nil {
+		return ""
+	}
+	channel, _ := ctx.Value(salesChannelKey{}).(SalesChannel)
+	return channel
+}
+
+// ChannelAllocation unidades vendibles de una fila de stock asignadas a un canal. El canal solo
+// reserva contra su asignación; el resto de canales (y las reservas sin canal) no pueden tocarla
+// y reservan contra la parte no asignada.
+type ChannelAllocation struct {
+	ProductID string       `json:"product_id"`
+	StoreID   string       `json:"store_id"`
+	Channel   SalesChannel `json:"channel"`
+	Allocated int          `json:"allocated"` // Unidades asignadas (se descuentan al confirmar)
+	Reserved  int          `json:"reserved"`  // Reservas pendientes del canal contra la asignación
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Remaining retorna las unidades de la asignación que el canal todavía puede reservar
+func (a *ChannelAllocation) Remaining() int {
+	return a.Allocated - a.Reserved
+}
+
+// ChannelAvailable calcula las unidades que puede reservar channel en la fila de stock: su
+// asignación pendiente (acotada por lo vendible) o, si no tiene, lo vendible menos lo que los
+// demás canales tienen asignado y sin reservar
+func ChannelAvailable(stock *Stock, allocations []*ChannelAllocation, channel SalesChannel) int {
+	outstanding := 0
+	for _, allocation := range allocations {
+		if channel != "" && allocation.Channel == channel {
+			return min(allocation.Remaining(), stock.Sellable())
+		}
+		outstanding += max(allocation.Remaining(), 0)
+	}
+	return stock.Sellable() - outstanding
+}
+
+// FindChannelAllocation retorna la asignación de channel (nil si no tiene)
+func FindChannelAllocation(allocations []*ChannelAllocation, channel SalesChannel) *ChannelAllocation {
+	for _, allocation := range allocations {
+		if allocation.Channel == channel {
+			return allocation
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ConfirmHookMode cuándo se ejecuta un hook de confirmación respecto a la confirmación
+type ConfirmHookMode string
+
+const (
+	// ConfirmHookSync se ejecuta antes de descontar el stock: si falla, la reserva sigue PENDING
+	// y la confirmación responde error (p. ej. cobro rechazado)
+	ConfirmHookSync ConfirmHookMode = "sync"
+	// ConfirmHookAsync se ejecuta en segundo plano con la reserva ya confirmada: sus fallos
+	// solo se registran en el log (p. ej. avisar al servicio de pedidos)
+	ConfirmHookAsync ConfirmHookMode = "async"
+)
+
+// IsValid indica si el modo es uno de los soportados
+func (m ConfirmHookMode) IsValid() bool {
+	return m == ConfirmHookSync || m == ConfirmHookAsync
+}
+
+// ReservationConfirmation datos de la confirmación que reciben los hooks
+type ReservationConfirmation struct {
+	ReservationID string    `json:"reservation_id"`
+	ProductID     string    `json:"product_id"`
+	SKU           string    `json:"sku"`
+	StoreID       string    `json:"store_id"`
+	CustomerID    string    `json:"customer_id"`
+	Quantity      int       `json:"quantity"`
+	UnitPrice     float64   `json:"unit_price"`
+	ReferenceID   string    `json:"reference_id,omitempty"` // Ticket o pedido de la venta
+	ConfirmedAt   time.Time `json:"confirmed_at"`
+}
+
+// ConfirmHook integración externa que participa en la confirmación de reservas (capturar el
+// pago, notificar al servicio de pedidos...). Se puede llamar varias veces para la misma
+// reserva si un intento falla: la implementación debe ser idempotente por ReservationID.
+//
+// Implementaciones disponibles:
+//   - HTTPConfirmHook: POST de la confirmación a una URL (CONFIRM_HOOK_URL)
+type ConfirmHook interface {
+	// Name identifica el hook en logs y errores
+	Name() string
+
+	// OnConfirm procesa la confirmación. Un ConfirmRejectedError no se reintenta.
+	OnConfirm(ctx context.Context, confirmation *ReservationConfirmation) error
+}
+
+// ConfirmHookPolicy modo y reintentos con los que se ejecuta un hook
+type ConfirmHookPolicy struct {
+	Mode        ConfirmHookMode
+	MaxAttempts int           // Intentos totales (mínimo 1)
+	Backoff     time.Duration // Espera antes del segundo intento; se duplica en cada reintento
+}
+
+// ConfirmRejectedError lo retorna un hook que rechaza la confirmación de forma definitiva
+// (p. ej. pago denegado): no se reintenta
+type ConfirmRejectedError struct {
+	Reason string
+}
+
+func (e *ConfirmRejectedError) Error() string {
+	return e.Reason
+}
+
+// ConfirmHookError indica que un hook síncrono no aceptó la confirmación: la reserva sigue
+// PENDING y se puede volver a confirmar. Rejected distingue un rechazo del hook (409) de un
+// fallo tras agotar los reintentos (502).
+type ConfirmHookError struct {
+	Hook     string
+	Attempts int
+	Rejected bool
+	Err      error
+}
+
+func (e *ConfirmHookError) Error() string {
+	if e.Rejected {
+		return fmt.Sprintf("confirm hook %s rejected the confirmation: %v", e.Hook, e.Err)
+	}
+	return fmt.Sprintf("confirm hook %s failed after %d attempts: %v", e.Hook, e.Attempts, e.Err)
+}
+
+func (e *ConfirmHookError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ConfirmHookError) Code() string {
+	if e.Rejected {
+		return "CONFIRM_REJECTED"
+	}
+	return "CONFIRM_HOOK_FAILED"
+}
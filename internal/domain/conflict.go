@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// ConflictStatus representa el estado de un conflicto de sincronización
+type ConflictStatus string
+
+const (
+	ConflictStatusAutoResolved ConflictStatus = "AUTO_RESOLVED" // Resuelto por la política de merge
+	ConflictStatusUnresolved   ConflictStatus = "UNRESOLVED"    // Requiere intervención manual
+	ConflictStatusResolved     ConflictStatus = "RESOLVED"      // Resuelto manualmente por un operador
+)
+
+// ConflictStrategyAdjustmentMerge es la política de resolución para stock:
+// last-writer-wins con merge de ajustes. El cambio remoto se interpreta como
+// un delta sobre la versión base que vio el emisor y se re-aplica sobre la
+// versión local más reciente, de modo que no se pierden los ajustes concurrentes.
+const ConflictStrategyAdjustmentMerge = "ADJUSTMENT_MERGE"
+
+// RemoteStockUpdate representa un cambio de stock originado en otra instancia/tienda
+type RemoteStockUpdate struct {
+	ProductID      string    `json:"product_id"`
+	StoreID        string    `json:"store_id"`
+	OriginInstance string    `json:"origin_instance"` // Instancia que originó el cambio
+	BaseVersion    int       `json:"base_version"`    // Versión local que vio el emisor
+	BaseQuantity   int       `json:"base_quantity"`   // Cantidad que vio el emisor
+	NewQuantity    int       `json:"new_quantity"`    // Cantidad escrita por el emisor
+	ChangedAt      time.Time `json:"changed_at"`
+}
+
+// Delta retorna el ajuste que representa el cambio remoto
+func (u *RemoteStockUpdate) Delta() int {
+	return u.NewQuantity - u.BaseQuantity
+}
+
+// Validate verifica que el cambio remoto tenga datos válidos
+func (u *RemoteStockUpdate) Validate() error {
+	if u.ProductID == "" {
+		return &ValidationError{Field: "product_id", Message: "Product ID is required"}
+	}
+	if u.StoreID == "" {
+		return &ValidationError{Field: "store_id", Message: "Store ID is required"}
+	}
+	if u.BaseVersion <= 0 {
+		return &ValidationError{Field: "base_version", Message: "Base version must be positive"}
+	}
+	if u.BaseQuantity < 0 || u.NewQuantity < 0 {
+		return &ValidationError{Field: "quantity", Message: "Quantities cannot be negative"}
+	}
+	return nil
+}
+
+// StockConflict registra una edición concurrente de stock detectada durante la sincronización
+type StockConflict struct {
+	ID               string         `json:"id"`
+	ProductID        string         `json:"productId"`
+	StoreID          string         `json:"storeId"`
+	OriginInstance   string         `json:"originInstance"`
+	Strategy         string         `json:"strategy"`
+	Status           ConflictStatus `json:"status"`
+	LocalVersion     int            `json:"localVersion"`
+	LocalQuantity    int            `json:"localQuantity"`
+	LocalReserved    int            `json:"localReserved"`
+	RemoteBase       int            `json:"remoteBaseVersion"`
+	RemoteQuantity   int            `json:"remoteQuantity"`
+	RemoteDelta      int            `json:"remoteDelta"`
+	ResolvedQuantity *int           `json:"resolvedQuantity,omitempty"`
+	Reason           string         `json:"reason,omitempty"`
+	CreatedAt        time.Time      `json:"createdAt"`
+	ResolvedAt       *time.Time     `json:"resolvedAt,omitempty"`
+}
+
+// MergeStockAdjustment aplica la política ADJUSTMENT_MERGE sobre el stock local.
+// Retorna la cantidad resultante y ok=false si el merge violaría los invariantes
+// del stock (cantidad negativa o menor que lo reservado).
+func MergeStockAdjustment(local *Stock, update *RemoteStockUpdate) (merged int, ok bool) {
+	merged = local.Quantity + update.Delta()
+	if merged < 0 || merged < local.Reserved {
+		return merged, false
+	}
+	return merged, true
+}
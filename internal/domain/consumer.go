@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// ProcessedEventStatus estado de un evento en la tabla de idempotencia de un consumidor
+type ProcessedEventStatus string
+
+const (
+	ProcessedEventProcessing ProcessedEventStatus = "PROCESSING" // Reclamado, el handler está en curso
+	ProcessedEventDone       ProcessedEventStatus = "DONE"       // Aplicado; las re-entregas se ignoran
+)
+
+// DefaultConsumerClaimLease tiempo tras el cual un evento en PROCESSING (consumidor caído
+// a mitad del handler) puede volver a reclamarse
+const DefaultConsumerClaimLease = 5 * time.Minute
+
+// ProcessedEvent registro de idempotencia de un evento para un consumidor.
+// La clave es (consumer, event_id): cada consumidor aplica cada evento una sola vez.
+type ProcessedEvent struct {
+	Consumer    string               `json:"consumer"`
+	EventID     string               `json:"event_id"`
+	EventType   string               `json:"event_type"`
+	Status      ProcessedEventStatus `json:"status"`
+	ClaimedAt   time.Time            `json:"claimed_at"`
+	ProcessedAt *time.Time           `json:"processed_at,omitempty"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// StockContention escrituras con optimistic locking sobre una fila de stock en la ventana
+// móvil y cuántas fallaron por conflicto de versión
+type StockContention struct {
+	ProductID      string     `json:"product_id"`
+	StoreID        string     `json:"store_id"`
+	Writes         int        `json:"writes"`    // Intentos de escritura (incluidos los conflictos)
+	Conflicts      int        `json:"conflicts"` // Escrituras rechazadas por versión desactualizada
+	ConflictRate   float64    `json:"conflict_rate"`
+	LastConflictAt *time.Time `json:"last_conflict_at,omitempty"`
+	FlashSale      bool       `json:"flash_sale"` // El producto ya usa la cola de alta concurrencia
+}
+
+// ContentionReport filas de stock con conflictos de versión en la ventana móvil, de la más
+// disputada a la menos, con los totales acumulados desde el arranque del proceso
+type ContentionReport struct {
+	WindowSeconds  float64            `json:"window_seconds"`
+	Since          time.Time          `json:"since"` // Inicio de la ventana (o del proceso, si es posterior)
+	GeneratedAt    time.Time          `json:"generated_at"`
+	Rows           []*StockContention `json:"rows"`
+	Count          int                `json:"count"`
+	TotalWrites    int64              `json:"total_writes"`    // Desde el arranque del proceso
+	TotalConflicts int64              `json:"total_conflicts"` // Desde el arranque del proceso
+}
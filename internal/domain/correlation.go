@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// correlationIDKey clave privada del context para el ID de correlación
+type correlationIDKey struct{}
+
+// WithCorrelationID devuelve un context que transporta el ID de correlación
+// (el X-Request-ID del request HTTP que originó la operación)
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFromContext obtiene el ID de correlación del context ("" si no hay)
+func CorrelationIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	correlationID, _ := ctx.Value(correlationIDKey{}).(string)
+	return correlationID
+}
+
+// AttachCorrelationID asigna al evento el ID de correlación del context si aún no
+// tiene uno, para poder seguir una operación desde el request hasta los consumidores
+func AttachCorrelationID(ctx context.Context, event *Event) {
+	if event.CorrelationID == "" {
+		event.CorrelationID = CorrelationIDFromContext(ctx)
+	}
+}
+
+// AttachActor asigna al evento el autor de la operación del context si aún no tiene
+func AttachActor(ctx context.Context, event *Event) {
+	if event.Actor == "" {
+		event.Actor = ActorFromContext(ctx)
+	}
+}
+
+// SystemActor se registra como autor cuando la operación no viene de un request autenticado
+const SystemActor = "system"
+
+// actorKey clave privada del context para el autor de la operación
+type actorKey struct{}
+
+// WithActor devuelve un context que transporta el autor de la operación
+// (el nombre asociado a la API key del request)
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext obtiene el autor de la operación (SystemActor si no hay)
+func ActorFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return SystemActor
+	}
+	if actor, _ := ctx.Value(actorKey{}).(string); actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+// storeScopeKey clave privada del context para las tiendas en las que puede escribir el autor
+type storeScopeKey struct{}
+
+// WithStoreScope devuelve un context que limita las escrituras a stores (las tiendas de la
+// API key del request). Un scope nil no limita (keys multi-tienda, workers y procesos internos);
+// uno vacío no deja escribir en ninguna tienda (key sin entrada en API_KEY_STORE_SCOPES).
+func WithStoreScope(ctx context.Context, stores []string) context.Context {
+	return context.WithValue(ctx, storeScopeKey{}, stores)
+}
+
+// StoreScopeFromContext obtiene las tiendas a las que está limitado el autor (nil = todas)
+func StoreScopeFromContext(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	stores, _ := ctx.Value(storeScopeKey{}).([]string)
+	return stores
+}
+
+// ActingStoreFromContext obtiene la tienda desde la que se opera cuando la API key pertenece
+// a una sola tienda ("" si es multi-tienda o no hay request autenticado)
+func ActingStoreFromContext(ctx context.Context) string {
+	if stores := StoreScopeFromContext(ctx); len(stores) == 1 {
+		return stores[0]
+	}
+	return ""
+}
+
+// AuthorizeStoreWrite retorna ForbiddenError si el autor está limitado a otras tiendas, para no
+// aceptar a ciegas el storeID recibido: los eventos deben reflejar la tienda que originó el cambio
+func AuthorizeStoreWrite(ctx context.Context, storeID string) error {
+	stores := StoreScopeFromContext(ctx)
+	if stores == nil {
+		return nil
+	}
+	if len(stores) == 0 {
+		return &ForbiddenError{Message: fmt.Sprintf("API key is not scoped to any store and cannot write to store %s", storeID)}
+	}
+	for _, store := range stores {
+		if store == storeID {
+			return nil
+		}
+	}
+	return &ForbiddenError{Message: fmt.Sprintf("API key is limited to stores %s and cannot write to store %s", strings.Join(stores, ", "), storeID)}
+}
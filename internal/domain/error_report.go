@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PanicReport panic recuperado mientras se procesaba un request HTTP
+type PanicReport struct {
+	RequestID  string    `json:"request_id"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Route      string    `json:"route,omitempty"` // Patrón de la ruta (p. ej. /api/v1/products/:id)
+	Value      string    `json:"value"`           // Valor del panic formateado con %v
+	Stack      string    `json:"stack"`
+	InstanceID string    `json:"instance_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ErrorReporter envía los panics a un servicio externo de seguimiento de errores
+type ErrorReporter interface {
+	ReportPanic(ctx context.Context, report *PanicReport) error
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // DomainError es la interfaz base para todos los errores de dominio
 type DomainError interface {
@@ -8,6 +12,40 @@ type DomainError interface {
 	Code() string
 }
 
+// Centinelas de los errores de dominio: errors.Is(err, ErrNotFound) reconoce un *NotFoundError
+// aunque los servicios lo hayan envuelto con fmt.Errorf("...: %w", err). Para leer los campos
+// del error se usa errors.As con el tipo concreto.
+var (
+	ErrValidation         = errors.New("validation error")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrInsufficientStock  = errors.New("insufficient stock")
+	ErrReservationExpired = errors.New("reservation expired")
+	ErrInvalidState       = errors.New("invalid state")
+	ErrStoreClosed        = errors.New("store closed")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrCustomerLimit      = errors.New("customer limit exceeded")
+	ErrProductInUse       = errors.New("product in use")
+)
+
+// AsDomainError retorna el primer error de dominio de la cadena de err (el más externo)
+func AsDomainError(err error) (DomainError, bool) {
+	var domainErr DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
+
+// ErrorCode retorna el código del error de dominio de la cadena de err (fallback si no hay ninguno)
+func ErrorCode(err error, fallback string) string {
+	if domainErr, ok := AsDomainError(err); ok {
+		return domainErr.Code()
+	}
+	return fallback
+}
+
 // ValidationError representa un error de validación
 type ValidationError struct {
 	Field   string
@@ -22,6 +60,10 @@ func (e *ValidationError) Code() string {
 	return "VALIDATION_ERROR"
 }
 
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
 // NotFoundError representa un recurso no encontrado
 type NotFoundError struct {
 	Resource string
@@ -36,6 +78,10 @@ func (e *NotFoundError) Code() string {
 	return "NOT_FOUND"
 }
 
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // ConflictError representa un conflicto (ej. optimistic lock)
 type ConflictError struct {
 	Message string
@@ -49,12 +95,17 @@ func (e *ConflictError) Code() string {
 	return "CONFLICT"
 }
 
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
 // InsufficientStockError representa stock insuficiente
 type InsufficientStockError struct {
 	ProductID string
 	StoreID   string
 	Available int
 	Requested int
+	Shortage  *StockShortage // Opcional: diagnóstico de la fila al rechazar una reserva
 }
 
 func (e *InsufficientStockError) Error() string {
@@ -66,6 +117,10 @@ func (e *InsufficientStockError) Code() string {
 	return "INSUFFICIENT_STOCK"
 }
 
+func (e *InsufficientStockError) Is(target error) bool {
+	return target == ErrInsufficientStock
+}
+
 // ReservationExpiredError representa una reserva expirada
 type ReservationExpiredError struct {
 	ReservationID string
@@ -80,6 +135,10 @@ func (e *ReservationExpiredError) Code() string {
 	return "RESERVATION_EXPIRED"
 }
 
+func (e *ReservationExpiredError) Is(target error) bool {
+	return target == ErrReservationExpired
+}
+
 // InvalidStateError representa una transición de estado inválida
 type InvalidStateError struct {
 	CurrentState    string
@@ -94,6 +153,31 @@ func (e *InvalidStateError) Code() string {
 	return "INVALID_STATE"
 }
 
+func (e *InvalidStateError) Is(target error) bool {
+	return target == ErrInvalidState
+}
+
+// StoreClosedError se produce al reservar fuera del horario de la tienda o dentro del corte previo al cierre
+type StoreClosedError struct {
+	StoreID     string
+	NextOpening *time.Time // nil si el horario no tiene ninguna franja
+}
+
+func (e *StoreClosedError) Error() string {
+	if e.NextOpening == nil {
+		return fmt.Sprintf("store %s is closed for new reservations", e.StoreID)
+	}
+	return fmt.Sprintf("store %s is closed for new reservations until %s", e.StoreID, e.NextOpening.Format(time.RFC3339))
+}
+
+func (e *StoreClosedError) Code() string {
+	return "STORE_CLOSED"
+}
+
+func (e *StoreClosedError) Is(target error) bool {
+	return target == ErrStoreClosed
+}
+
 // UnauthorizedError representa un error de autenticación
 type UnauthorizedError struct {
 	Message string
@@ -107,6 +191,10 @@ func (e *UnauthorizedError) Code() string {
 	return "UNAUTHORIZED"
 }
 
+func (e *UnauthorizedError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
 // ForbiddenError representa un error de autorización (permisos)
 type ForbiddenError struct {
 	Message string
@@ -119,3 +207,60 @@ func (e *ForbiddenError) Error() string {
 func (e *ForbiddenError) Code() string {
 	return "FORBIDDEN"
 }
+
+func (e *ForbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// Límites de CustomerHoldLimits que puede superar una reserva
+const (
+	CustomerLimitProductUnits        = "max_units_per_product"
+	CustomerLimitPendingReservations = "max_pending_reservations"
+)
+
+// CustomerLimitError indica que el cliente superaría sus límites de retención (anti-acaparamiento).
+// Se responde con 429: el cliente puede reintentar cuando confirme o cancele sus reservas.
+type CustomerLimitError struct {
+	CustomerID string
+	ProductID  string // Solo para max_units_per_product
+	Limit      string
+	Max        int
+	Current    int
+	Requested  int
+}
+
+func (e *CustomerLimitError) Error() string {
+	if e.Limit == CustomerLimitProductUnits {
+		return fmt.Sprintf("customer %s already holds %d units of product %s in pending reservations (max %d, requested %d)",
+			e.CustomerID, e.Current, e.ProductID, e.Max, e.Requested)
+	}
+	return fmt.Sprintf("customer %s already has %d pending reservations (max %d)", e.CustomerID, e.Current, e.Max)
+}
+
+func (e *CustomerLimitError) Code() string {
+	return "CUSTOMER_LIMIT_EXCEEDED"
+}
+
+func (e *CustomerLimitError) Is(target error) bool {
+	return target == ErrCustomerLimit
+}
+
+// ProductInUseError indica que un producto no se puede eliminar porque todavía tiene
+// stock o reservas pendientes. Dependencies se devuelve como detalle en la respuesta 409.
+type ProductInUseError struct {
+	Dependencies ProductDependencies
+}
+
+func (e *ProductInUseError) Error() string {
+	d := e.Dependencies
+	return fmt.Sprintf("product %s is still in use (%d units and %d reserved in %d stores, %d pending reservations); use force=true to archive dependent rows",
+		d.ProductID, d.StockUnits, d.ReservedUnits, len(d.Stores), d.PendingReservations)
+}
+
+func (e *ProductInUseError) Code() string {
+	return "PRODUCT_IN_USE"
+}
+
+func (e *ProductInUseError) Is(target error) bool {
+	return target == ErrProductInUse
+}
@@ -1,8 +1,8 @@
 package domain
 
 import (
-	"encoding/json"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"time"
 )
@@ -18,6 +18,11 @@ type Event struct {
 	CreatedAt     time.Time  `json:"created_at"`
 	Synced        bool       `json:"synced"`
 	SyncedAt      *time.Time `json:"synced_at,omitempty"`
+	Seq           int64      `json:"seq,omitempty"`            // Posición en la cadena de auditoría
+	PrevHash      string     `json:"prev_hash,omitempty"`      // Hash del evento anterior en la cadena
+	Hash          string     `json:"hash,omitempty"`           // Hash de este evento (ver ComputeEventHash)
+	CorrelationID string     `json:"correlation_id,omitempty"` // X-Request-ID del request que lo originó
+	Actor         string     `json:"actor,omitempty"`          // API key que lo originó ("system" en workers)
 }
 
 // Validate verifica que el evento tenga datos válidos
@@ -34,149 +39,388 @@ func (e *Event) Validate() error {
 	return nil
 }
 
-// Helper functions para crear eventos comunes
+// EventFilter define los filtros de un recorrido del ledger de eventos. Los campos vacíos no filtran.
+type EventFilter struct {
+	StoreID       string
+	ProductID     string // aggregate_id
+	EventType     string
+	AfterSeq      int64      // Solo eventos con seq > AfterSeq (cursor para reanudar)
+	From          *time.Time // Inclusivo
+	To            *time.Time // Exclusivo
+	MovementsOnly bool       // Solo movimientos de stock (sin las fotos stock.snapshot del backfill)
+}
+
+// Validate verifica el filtro
+func (f EventFilter) Validate() error {
+	if f.AfterSeq < 0 {
+		return &ValidationError{Field: "after_seq", Message: "after_seq must be zero or positive"}
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return &ValidationError{Field: "from", Message: "from must be before to"}
+	}
+	return nil
+}
+
+// Helper functions para crear eventos comunes.
+// Cada payload es un struct tipado registrado en EventSchemas con su schema_version.
 
 func NewStockUpdatedEvent(productID, storeID string, oldQuantity, newQuantity int) *Event {
-	payload := map[string]interface{}{
-		"product_id":   productID,
-		"store_id":     storeID,
-		"old_quantity": oldQuantity,
-		"new_quantity": newQuantity,
+	return NewStockUpdatedEventWithReason(productID, storeID, oldQuantity, newQuantity, "")
+}
+
+// NewStockUpdatedEventWithReason crea un stock.updated que registra el motivo del ajuste manual
+func NewStockUpdatedEventWithReason(productID, storeID string, oldQuantity, newQuantity int, reason string) *Event {
+	payload := &StockUpdatedPayload{
+		SchemaVersion: DefaultEventSchemaVersion,
+		ProductID:     productID,
+		StoreID:       storeID,
+		OldQuantity:   oldQuantity,
+		NewQuantity:   newQuantity,
+		Reason:        reason,
 	}
-	payloadJSON, _ := json.Marshal(payload)
 
 	return &Event{
 		ID:            generateEventID(),
-		EventType:     "stock.updated",
+		EventType:     EventStockUpdated,
 		AggregateID:   productID,
 		AggregateType: "stock",
 		StoreID:       storeID,
-		Payload:       string(payloadJSON),
+		Payload:       marshalPayload(payload),
 		CreatedAt:     time.Now(),
 		Synced:        false,
 	}
 }
 
 func NewStockCreatedEvent(productID, storeID string, initialQuantity int) *Event {
-	payload := map[string]interface{}{
-		"product_id":       productID,
-		"store_id":         storeID,
-		"initial_quantity": initialQuantity,
+	payload := &StockCreatedPayload{
+		SchemaVersion:   DefaultEventSchemaVersion,
+		ProductID:       productID,
+		StoreID:         storeID,
+		InitialQuantity: initialQuantity,
 	}
-	payloadJSON, _ := json.Marshal(payload)
 
 	return &Event{
 		ID:            generateEventID(),
-		EventType:     "stock.created",
+		EventType:     EventStockCreated,
 		AggregateID:   productID,
 		AggregateType: "stock",
 		StoreID:       storeID,
-		Payload:       string(payloadJSON),
+		Payload:       marshalPayload(payload),
 		CreatedAt:     time.Now(),
 		Synced:        false,
 	}
 }
 
 func NewStockTransferredEvent(productID, fromStoreID, toStoreID string, quantity int) *Event {
-	payload := map[string]interface{}{
-		"product_id":    productID,
-		"from_store_id": fromStoreID,
-		"to_store_id":   toStoreID,
-		"quantity":      quantity,
+	payload := &StockTransferredPayload{
+		SchemaVersion: DefaultEventSchemaVersion,
+		ProductID:     productID,
+		FromStoreID:   fromStoreID,
+		ToStoreID:     toStoreID,
+		Quantity:      quantity,
 	}
-	payloadJSON, _ := json.Marshal(payload)
 
 	return &Event{
 		ID:            generateEventID(),
-		EventType:     "stock.transferred",
+		EventType:     EventStockTransferred,
 		AggregateID:   productID,
 		AggregateType: "stock",
 		StoreID:       fromStoreID,
-		Payload:       string(payloadJSON),
+		Payload:       marshalPayload(payload),
 		CreatedAt:     time.Now(),
 		Synced:        false,
 	}
 }
 
-func NewReservationCreatedEvent(reservationID, productID, storeID string, quantity int) *Event {
-	payload := map[string]interface{}{
-		"reservation_id": reservationID,
-		"product_id":     productID,
-		"store_id":       storeID,
-		"quantity":       quantity,
+// NewStockSnapshotEvent crea el evento stock.snapshot con el estado actual de la fila
+func NewStockSnapshotEvent(stock *Stock, backfillID string) *Event {
+	payload := &StockSnapshotPayload{
+		SchemaVersion: DefaultEventSchemaVersion,
+		ProductID:     stock.ProductID,
+		StoreID:       stock.StoreID,
+		Quantity:      stock.Quantity,
+		Reserved:      stock.Reserved,
+		SafetyStock:   stock.SafetyStock,
+		Available:     stock.Available(),
+		MinStock:      stock.MinStock,
+		MaxStock:      stock.MaxStock,
+		Version:       stock.Version,
+		UpdatedAt:     stock.UpdatedAt,
+		BackfillID:    backfillID,
 	}
-	payloadJSON, _ := json.Marshal(payload)
 
 	return &Event{
 		ID:            generateEventID(),
-		EventType:     "reservation.created",
-		AggregateID:   reservationID,
-		AggregateType: "reservation",
-		StoreID:       storeID,
-		Payload:       string(payloadJSON),
+		EventType:     EventStockSnapshot,
+		AggregateID:   stock.ProductID,
+		AggregateType: "stock",
+		StoreID:       stock.StoreID,
+		Payload:       marshalPayload(payload),
 		CreatedAt:     time.Now(),
 		Synced:        false,
 	}
 }
 
-func NewReservationConfirmedEvent(reservationID, productID, storeID string, quantity int) *Event {
-	payload := map[string]interface{}{
-		"reservation_id": reservationID,
-		"product_id":     productID,
-		"store_id":       storeID,
-		"quantity":       quantity,
+// NewStockReservedCorrectedEvent crea el evento stock.reserved_corrected de una corrección de la reconciliación
+func NewStockReservedCorrectedEvent(productID, storeID string, oldReserved, newReserved int, actor string) *Event {
+	payload := &StockReservedCorrectedPayload{
+		SchemaVersion: DefaultEventSchemaVersion,
+		ProductID:     productID,
+		StoreID:       storeID,
+		OldReserved:   oldReserved,
+		NewReserved:   newReserved,
+		Actor:         actor,
 	}
-	payloadJSON, _ := json.Marshal(payload)
 
 	return &Event{
 		ID:            generateEventID(),
-		EventType:     "reservation.confirmed",
-		AggregateID:   reservationID,
-		AggregateType: "reservation",
+		EventType:     EventStockReservedCorrected,
+		AggregateID:   productID,
+		AggregateType: "stock",
 		StoreID:       storeID,
-		Payload:       string(payloadJSON),
+		Payload:       marshalPayload(payload),
 		CreatedAt:     time.Now(),
 		Synced:        false,
 	}
 }
 
-func NewReservationCancelledEvent(reservationID, productID, storeID string, quantity int) *Event {
-	payload := map[string]interface{}{
-		"reservation_id": reservationID,
-		"product_id":     productID,
-		"store_id":       storeID,
-		"quantity":       quantity,
+// NewStockAdjustmentEvent crea el evento stock.adjustment_* de un ajuste pendiente de aprobación
+func NewStockAdjustmentEvent(eventType string, adjustment *StockAdjustment) *Event {
+	payload := &StockAdjustmentPayload{
+		SchemaVersion:   DefaultEventSchemaVersion,
+		AdjustmentID:    adjustment.ID,
+		ProductID:       adjustment.ProductID,
+		StoreID:         adjustment.StoreID,
+		Type:            string(adjustment.Type),
+		Quantity:        adjustment.Quantity,
+		CurrentQuantity: adjustment.CurrentQuantity,
+		Reason:          adjustment.Reason,
+		RequestedBy:     adjustment.RequestedBy,
+		ReviewedBy:      adjustment.ReviewedBy,
 	}
-	payloadJSON, _ := json.Marshal(payload)
 
 	return &Event{
 		ID:            generateEventID(),
-		EventType:     "reservation.cancelled",
-		AggregateID:   reservationID,
-		AggregateType: "reservation",
-		StoreID:       storeID,
-		Payload:       string(payloadJSON),
+		EventType:     eventType,
+		AggregateID:   adjustment.ProductID,
+		AggregateType: "stock",
+		StoreID:       adjustment.StoreID,
+		Payload:       marshalPayload(payload),
 		CreatedAt:     time.Now(),
 		Synced:        false,
 	}
 }
 
+// NewStockHoldEvent crea el evento stock.hold_* de una retención interna de stock
+func NewStockHoldEvent(eventType string, hold *StockHold) *Event {
+	payload := &StockHoldPayload{
+		SchemaVersion: DefaultEventSchemaVersion,
+		HoldID:        hold.ID,
+		ProductID:     hold.ProductID,
+		StoreID:       hold.StoreID,
+		Type:          string(hold.Type),
+		Quantity:      hold.Quantity,
+		Note:          hold.Note,
+		CreatedBy:     hold.CreatedBy,
+		ReleasedBy:    hold.ReleasedBy,
+	}
+
+	return &Event{
+		ID:            generateEventID(),
+		EventType:     eventType,
+		AggregateID:   hold.ProductID,
+		AggregateType: "stock",
+		StoreID:       hold.StoreID,
+		Payload:       marshalPayload(payload),
+		CreatedAt:     time.Now(),
+		Synced:        false,
+	}
+}
+
+func NewReservationCreatedEvent(reservationID, productID, storeID string, quantity int) *Event {
+	return newReservationEvent(EventReservationCreated, reservationID, productID, storeID, quantity, "")
+}
+
+// NewReservationConfirmedEvent crea el evento de venta. store puede ser nil si la tienda
+// no está dada de alta en la tabla stores; en ese caso solo se informa su ID.
+func NewReservationConfirmedEvent(reservation *Reservation, product *Product, store *Store, referenceID string) *Event {
+	confirmedAt := time.Now()
+	if reservation.ConfirmedAt != nil {
+		confirmedAt = *reservation.ConfirmedAt
+	}
+
+	payload := &ReservationConfirmedPayload{
+		SchemaVersion: ReservationConfirmedSchemaVersion,
+		ReservationID: reservation.ID,
+		ProductID:     reservation.ProductID,
+		StoreID:       reservation.StoreID,
+		Quantity:      reservation.Quantity,
+		CustomerID:    reservation.CustomerID,
+		ReferenceID:   referenceID,
+		Channel:       reservation.Channel,
+		SKU:           product.SKU,
+		UnitPrice:     product.Price,
+		TotalPrice:    product.Price * float64(reservation.Quantity),
+		Store:         EventStoreMetadata{ID: reservation.StoreID},
+		ReservedAt:    reservation.CreatedAt,
+		ConfirmedAt:   confirmedAt,
+	}
+	if store != nil {
+		payload.Store.Name = store.Name
+		payload.Store.City = store.City
+		payload.Store.Country = store.Country
+	}
+
+	return &Event{
+		ID:            generateEventID(),
+		EventType:     EventReservationConfirmed,
+		AggregateID:   reservation.ID,
+		AggregateType: "reservation",
+		StoreID:       reservation.StoreID,
+		Payload:       marshalPayload(payload),
+		CreatedAt:     confirmedAt,
+		Synced:        false,
+	}
+}
+
+// NewReservationPickedUpEvent crea reservation.picked_up: la venta de una recogida en tienda, con
+// el mismo payload que reservation.confirmed
+func NewReservationPickedUpEvent(reservation *Reservation, product *Product, store *Store) *Event {
+	event := NewReservationConfirmedEvent(reservation, product, store, "")
+	event.EventType = EventReservationPickedUp
+	return event
+}
+
+func NewReservationCancelledEvent(reservationID, productID, storeID string, quantity int) *Event {
+	return newReservationEvent(EventReservationCancelled, reservationID, productID, storeID, quantity, "")
+}
+
 func NewReservationExpiredEvent(reservationID, productID, storeID string, quantity int) *Event {
-	payload := map[string]interface{}{
-		"reservation_id": reservationID,
-		"product_id":     productID,
-		"store_id":       storeID,
-		"quantity":       quantity,
+	return newReservationEvent(EventReservationExpired, reservationID, productID, storeID, quantity, "")
+}
+
+// NewReservationLifecycleEvent crea reservation.created, reservation.cancelled o reservation.expired
+// a partir de la reserva, incluyendo el canal de venta que la originó
+func NewReservationLifecycleEvent(eventType string, reservation *Reservation) *Event {
+	return newReservationEvent(eventType, reservation.ID, reservation.ProductID, reservation.StoreID, reservation.Quantity, reservation.Channel)
+}
+
+func newReservationEvent(eventType, reservationID, productID, storeID string, quantity int, channel SalesChannel) *Event {
+	payload := &ReservationEventPayload{
+		SchemaVersion: DefaultEventSchemaVersion,
+		ReservationID: reservationID,
+		ProductID:     productID,
+		StoreID:       storeID,
+		Quantity:      quantity,
+		Channel:       channel,
 	}
-	payloadJSON, _ := json.Marshal(payload)
 
 	return &Event{
 		ID:            generateEventID(),
-		EventType:     "reservation.expired",
+		EventType:     eventType,
 		AggregateID:   reservationID,
 		AggregateType: "reservation",
 		StoreID:       storeID,
-		Payload:       string(payloadJSON),
+		Payload:       marshalPayload(payload),
+		CreatedAt:     time.Now(),
+		Synced:        false,
+	}
+}
+
+// NewTransferEvent crea un evento del ciclo de vida de una transferencia (transfer.draft, transfer.completed, transfer.cancelled)
+func NewTransferEvent(transfer *StockTransfer) *Event {
+	payload := &TransferEventPayload{
+		SchemaVersion: DefaultEventSchemaVersion,
+		TransferID:    transfer.ID,
+		ProductID:     transfer.ProductID,
+		FromStoreID:   transfer.FromStoreID,
+		ToStoreID:     transfer.ToStoreID,
+		Quantity:      transfer.Quantity,
+		ReservationID: transfer.ReservationID,
+	}
+
+	return &Event{
+		ID:            generateEventID(),
+		EventType:     "transfer." + strings.ToLower(string(transfer.Status)),
+		AggregateID:   transfer.ID,
+		AggregateType: "transfer",
+		StoreID:       transfer.FromStoreID,
+		Payload:       marshalPayload(payload),
+		CreatedAt:     time.Now(),
+		Synced:        false,
+	}
+}
+
+// NewProductRunDownEvent crea un evento de run-down para una tienda del plan
+// (product.discontinued al crear el plan, product.archived al agotarse el stock en la tienda)
+func NewProductRunDownEvent(eventType string, rundown *ProductRunDown, entry RunDownStoreEntry) *Event {
+	payload := &ProductRunDownPayload{
+		SchemaVersion:   DefaultEventSchemaVersion,
+		ProductID:       rundown.ProductID,
+		StoreID:         entry.StoreID,
+		Reason:          rundown.Reason,
+		InitialQuantity: entry.InitialQuantity,
+		Quantity:        entry.Quantity,
+		EntryStatus:     entry.Status,
+	}
+
+	return &Event{
+		ID:            generateEventID(),
+		EventType:     eventType,
+		AggregateID:   rundown.ProductID,
+		AggregateType: "product",
+		StoreID:       entry.StoreID,
+		Payload:       marshalPayload(payload),
+		CreatedAt:     time.Now(),
+		Synced:        false,
+	}
+}
+
+// NewProductStatusChangedEvent crea el evento product.status_changed. Es un evento de catálogo,
+// por lo que su origen es CatalogStoreID.
+func NewProductStatusChangedEvent(product *Product, from ProductStatus, reason string) *Event {
+	payload := &ProductStatusChangedPayload{
+		SchemaVersion: DefaultEventSchemaVersion,
+		ProductID:     product.ID,
+		SKU:           product.SKU,
+		From:          from,
+		To:            product.Status,
+		Reason:        reason,
+	}
+
+	return &Event{
+		ID:            generateEventID(),
+		EventType:     EventProductStatusChanged,
+		AggregateID:   product.ID,
+		AggregateType: "product",
+		StoreID:       CatalogStoreID,
+		Payload:       marshalPayload(payload),
+		CreatedAt:     time.Now(),
+		Synced:        false,
+	}
+}
+
+// NewProductEvent crea product.created, product.updated o product.deleted con los datos del
+// producto. Como product.status_changed, su origen es CatalogStoreID.
+func NewProductEvent(eventType string, product *Product) *Event {
+	payload := &ProductEventPayload{
+		SchemaVersion: DefaultEventSchemaVersion,
+		ProductID:     product.ID,
+		SKU:           product.SKU,
+		Name:          product.Name,
+		Description:   product.Description,
+		Category:      product.Category,
+		Price:         product.Price,
+		Status:        product.Status,
+	}
+
+	return &Event{
+		ID:            generateEventID(),
+		EventType:     eventType,
+		AggregateID:   product.ID,
+		AggregateType: "product",
+		StoreID:       CatalogStoreID,
+		Payload:       marshalPayload(payload),
 		CreatedAt:     time.Now(),
 		Synced:        false,
 	}
@@ -194,3 +438,28 @@ func generateEventID() string {
 		now.Nanosecond(),
 		counter)
 }
+
+// NewStoreSyncStaleEvent crea store.sync_stale para una instancia edge atrasada. Su origen es
+// la tienda de la instancia.
+func NewStoreSyncStaleEvent(status *StoreSyncStatus) *Event {
+	payload := &StoreSyncStalePayload{
+		SchemaVersion: DefaultEventSchemaVersion,
+		StoreID:       status.StoreID,
+		InstanceID:    status.InstanceID,
+		PendingEvents: status.PendingEvents,
+		LastSyncAt:    status.LastSyncAt,
+		LastHeartbeat: status.LastHeartbeat,
+		LagMinutes:    status.LagMinutes,
+	}
+
+	return &Event{
+		ID:            generateEventID(),
+		EventType:     EventStoreSyncStale,
+		AggregateID:   status.InstanceID,
+		AggregateType: "store",
+		StoreID:       status.StoreID,
+		Payload:       marshalPayload(payload),
+		CreatedAt:     time.Now(),
+		Synced:        false,
+	}
+}
@@ -0,0 +1,494 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tipos de evento publicados
+const (
+	EventStockUpdated           = "stock.updated"
+	EventStockCreated           = "stock.created"
+	EventStockTransferred       = "stock.transferred"
+	EventStockSnapshot          = "stock.snapshot"
+	EventStockReservedCorrected = "stock.reserved_corrected"
+	EventAdjustmentRequested    = "stock.adjustment_requested"
+	EventAdjustmentApproved     = "stock.adjustment_approved"
+	EventAdjustmentRejected     = "stock.adjustment_rejected"
+	EventStockHoldPlaced        = "stock.hold_placed"
+	EventStockHoldReleased      = "stock.hold_released"
+	EventReservationCreated     = "reservation.created"
+	EventReservationConfirmed   = "reservation.confirmed"
+	EventReservationCancelled   = "reservation.cancelled"
+	EventReservationExpired     = "reservation.expired"
+	EventPreorderCreated        = "reservation.preorder_created"
+	EventPreorderAllocated      = "reservation.preorder_allocated"
+	EventPreorderCancelled      = "reservation.preorder_cancelled"
+	EventReservationReady       = "reservation.ready_for_pickup"
+	EventReservationPickedUp    = "reservation.picked_up"
+	EventTransferDraft          = "transfer.draft"
+	EventTransferCompleted      = "transfer.completed"
+	EventTransferCancelled      = "transfer.cancelled"
+	EventProductDiscontinued    = "product.discontinued"
+	EventProductArchived        = "product.archived"
+	EventProductStatusChanged   = "product.status_changed"
+	EventProductCreated         = "product.created"
+	EventProductUpdated         = "product.updated"
+	EventProductDeleted         = "product.deleted"
+	EventStoreSyncStale         = "store.sync_stale"
+)
+
+// DefaultEventSchemaVersion versión de los payloads que no han cambiado desde que se publicaron.
+// Los eventos anteriores al registro no llevan schema_version y se leen como esta versión.
+const DefaultEventSchemaVersion = 1
+
+// EventPayload lo implementan los payloads tipados de cada tipo de evento
+type EventPayload interface {
+	Validate() error
+}
+
+// StockUpdatedPayload payload de stock.updated (v1)
+type StockUpdatedPayload struct {
+	SchemaVersion int    `json:"schema_version"`
+	ProductID     string `json:"product_id"`
+	StoreID       string `json:"store_id"`
+	OldQuantity   int    `json:"old_quantity"`
+	NewQuantity   int    `json:"new_quantity"`
+	Reason        string `json:"reason,omitempty"` // Código del catálogo de motivos (ajustes manuales)
+}
+
+func (p *StockUpdatedPayload) Validate() error {
+	return requirePayloadFields("product_id", p.ProductID, "store_id", p.StoreID)
+}
+
+// StockCreatedPayload payload de stock.created (v1)
+type StockCreatedPayload struct {
+	SchemaVersion   int    `json:"schema_version"`
+	ProductID       string `json:"product_id"`
+	StoreID         string `json:"store_id"`
+	InitialQuantity int    `json:"initial_quantity"`
+}
+
+func (p *StockCreatedPayload) Validate() error {
+	return requirePayloadFields("product_id", p.ProductID, "store_id", p.StoreID)
+}
+
+// StockTransferredPayload payload de stock.transferred (v1)
+type StockTransferredPayload struct {
+	SchemaVersion int    `json:"schema_version"`
+	ProductID     string `json:"product_id"`
+	FromStoreID   string `json:"from_store_id"`
+	ToStoreID     string `json:"to_store_id"`
+	Quantity      int    `json:"quantity"`
+}
+
+func (p *StockTransferredPayload) Validate() error {
+	return requirePayloadFields("product_id", p.ProductID, "from_store_id", p.FromStoreID, "to_store_id", p.ToStoreID)
+}
+
+// StockSnapshotPayload payload de stock.snapshot (v1): el estado completo de una fila de stock,
+// emitido por el backfill para que los consumidores partan de una línea base
+type StockSnapshotPayload struct {
+	SchemaVersion int       `json:"schema_version"`
+	ProductID     string    `json:"product_id"`
+	StoreID       string    `json:"store_id"`
+	Quantity      int       `json:"quantity"`
+	Reserved      int       `json:"reserved"`
+	SafetyStock   int       `json:"safety_stock"`
+	Available     int       `json:"available"` // quantity - reserved
+	MinStock      int       `json:"min_stock"`
+	MaxStock      int       `json:"max_stock"`
+	Version       int       `json:"version"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	BackfillID    string    `json:"backfill_id"`
+}
+
+func (p *StockSnapshotPayload) Validate() error {
+	return requirePayloadFields("product_id", p.ProductID, "store_id", p.StoreID)
+}
+
+// StockReservedCorrectedPayload payload de stock.reserved_corrected (v1): la reconciliación
+// reemplazó stock.reserved por la suma de las reservas PENDING
+type StockReservedCorrectedPayload struct {
+	SchemaVersion int    `json:"schema_version"`
+	ProductID     string `json:"product_id"`
+	StoreID       string `json:"store_id"`
+	OldReserved   int    `json:"old_reserved"`
+	NewReserved   int    `json:"new_reserved"`
+	Actor         string `json:"actor"`
+}
+
+func (p *StockReservedCorrectedPayload) Validate() error {
+	return requirePayloadFields("product_id", p.ProductID, "store_id", p.StoreID)
+}
+
+// StockAdjustmentPayload payload de stock.adjustment_requested, stock.adjustment_approved y
+// stock.adjustment_rejected (v1). La aprobación emite además el stock.updated del cambio.
+type StockAdjustmentPayload struct {
+	SchemaVersion   int    `json:"schema_version"`
+	AdjustmentID    string `json:"adjustment_id"`
+	ProductID       string `json:"product_id"`
+	StoreID         string `json:"store_id"`
+	Type            string `json:"type"`
+	Quantity        int    `json:"quantity"`
+	CurrentQuantity int    `json:"current_quantity"`
+	Reason          string `json:"reason,omitempty"`
+	RequestedBy     string `json:"requested_by"`
+	ReviewedBy      string `json:"reviewed_by,omitempty"`
+}
+
+func (p *StockAdjustmentPayload) Validate() error {
+	return requirePayloadFields("adjustment_id", p.AdjustmentID, "product_id", p.ProductID, "store_id", p.StoreID)
+}
+
+// StockHoldPayload payload de stock.hold_placed y stock.hold_released (v1): retención interna de
+// unidades (exposición, reparación, calidad) que suma o resta de reserved y held
+type StockHoldPayload struct {
+	SchemaVersion int    `json:"schema_version"`
+	HoldID        string `json:"hold_id"`
+	ProductID     string `json:"product_id"`
+	StoreID       string `json:"store_id"`
+	Type          string `json:"type"`
+	Quantity      int    `json:"quantity"`
+	Note          string `json:"note,omitempty"`
+	CreatedBy     string `json:"created_by"`
+	ReleasedBy    string `json:"released_by,omitempty"`
+}
+
+func (p *StockHoldPayload) Validate() error {
+	return requirePayloadFields("hold_id", p.HoldID, "product_id", p.ProductID, "store_id", p.StoreID, "type", p.Type)
+}
+
+// ReservationEventPayload payload de reservation.created, reservation.cancelled y reservation.expired (v1),
+// y de reservation.confirmed v1
+type ReservationEventPayload struct {
+	SchemaVersion int          `json:"schema_version"`
+	ReservationID string       `json:"reservation_id"`
+	ProductID     string       `json:"product_id"`
+	StoreID       string       `json:"store_id"`
+	Quantity      int          `json:"quantity"`
+	Channel       SalesChannel `json:"channel,omitempty"` // Canal de venta de la reserva (vacío = sin canal)
+}
+
+func (p *ReservationEventPayload) Validate() error {
+	return requirePayloadFields("reservation_id", p.ReservationID, "product_id", p.ProductID, "store_id", p.StoreID)
+}
+
+// ReservationConfirmedSchemaVersion versión actual del payload de reservation.confirmed.
+// v1 solo incluía reservation_id, product_id, store_id y quantity (se mantienen con el mismo nombre);
+// v2 añade precio, cliente, referencia de venta y metadatos de la tienda.
+const ReservationConfirmedSchemaVersion = 2
+
+// EventStoreMetadata datos de la tienda incluidos en los eventos de venta
+type EventStoreMetadata struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	City    string `json:"city,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+// ReservationConfirmedPayload payload de reservation.confirmed (v2): la conversión de una reserva
+// en venta con todo lo que necesita analítica sin volver a consultar la base de datos
+type ReservationConfirmedPayload struct {
+	SchemaVersion int                `json:"schema_version"`
+	ReservationID string             `json:"reservation_id"`
+	ProductID     string             `json:"product_id"`
+	StoreID       string             `json:"store_id"`
+	Quantity      int                `json:"quantity"`
+	CustomerID    string             `json:"customer_id"`
+	ReferenceID   string             `json:"reference_id,omitempty"` // Ticket/pedido del sistema de venta
+	Channel       SalesChannel       `json:"channel,omitempty"`      // Canal de venta de la reserva
+	SKU           string             `json:"sku"`
+	UnitPrice     float64            `json:"unit_price"`  // Precio del producto en el momento de la confirmación
+	TotalPrice    float64            `json:"total_price"` // unit_price * quantity
+	Store         EventStoreMetadata `json:"store"`
+	ReservedAt    time.Time          `json:"reserved_at"`
+	ConfirmedAt   time.Time          `json:"confirmed_at"`
+}
+
+func (p *ReservationConfirmedPayload) Validate() error {
+	if err := requirePayloadFields("reservation_id", p.ReservationID, "product_id", p.ProductID,
+		"store_id", p.StoreID, "customer_id", p.CustomerID, "sku", p.SKU); err != nil {
+		return err
+	}
+	if p.Quantity <= 0 {
+		return &ValidationError{Field: "quantity", Message: "quantity must be positive"}
+	}
+	if p.UnitPrice < 0 {
+		return &ValidationError{Field: "unit_price", Message: "unit_price cannot be negative"}
+	}
+	return nil
+}
+
+// TransferEventPayload payload de transfer.draft, transfer.completed y transfer.cancelled (v1)
+type TransferEventPayload struct {
+	SchemaVersion int    `json:"schema_version"`
+	TransferID    string `json:"transfer_id"`
+	ProductID     string `json:"product_id"`
+	FromStoreID   string `json:"from_store_id"`
+	ToStoreID     string `json:"to_store_id"`
+	Quantity      int    `json:"quantity"`
+	ReservationID string `json:"reservation_id"`
+}
+
+func (p *TransferEventPayload) Validate() error {
+	return requirePayloadFields("transfer_id", p.TransferID, "product_id", p.ProductID,
+		"from_store_id", p.FromStoreID, "to_store_id", p.ToStoreID)
+}
+
+// ProductRunDownPayload payload de product.discontinued y product.archived (v1)
+type ProductRunDownPayload struct {
+	SchemaVersion   int                `json:"schema_version"`
+	ProductID       string             `json:"product_id"`
+	StoreID         string             `json:"store_id"`
+	Reason          string             `json:"reason"`
+	InitialQuantity int                `json:"initial_quantity"`
+	Quantity        int                `json:"quantity"`
+	EntryStatus     RunDownEntryStatus `json:"entry_status"`
+}
+
+func (p *ProductRunDownPayload) Validate() error {
+	return requirePayloadFields("product_id", p.ProductID, "store_id", p.StoreID)
+}
+
+// ProductStatusChangedPayload payload de product.status_changed (v1)
+type ProductStatusChangedPayload struct {
+	SchemaVersion int           `json:"schema_version"`
+	ProductID     string        `json:"product_id"`
+	SKU           string        `json:"sku"`
+	From          ProductStatus `json:"from"`
+	To            ProductStatus `json:"to"`
+	Reason        string        `json:"reason"`
+}
+
+func (p *ProductStatusChangedPayload) Validate() error {
+	if err := requirePayloadFields("product_id", p.ProductID); err != nil {
+		return err
+	}
+	if !p.To.IsValid() {
+		return &ValidationError{Field: "to", Message: fmt.Sprintf("invalid product status %q", p.To)}
+	}
+	return nil
+}
+
+// ProductEventPayload payload de product.created, product.updated y product.deleted (v1): los
+// datos del catálogo tras el cambio (antes de borrarlo en product.deleted)
+type ProductEventPayload struct {
+	SchemaVersion int           `json:"schema_version"`
+	ProductID     string        `json:"product_id"`
+	SKU           string        `json:"sku"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	Category      string        `json:"category"`
+	Price         float64       `json:"price"`
+	Status        ProductStatus `json:"status"`
+}
+
+func (p *ProductEventPayload) Validate() error {
+	return requirePayloadFields("product_id", p.ProductID, "sku", p.SKU)
+}
+
+// StoreSyncStalePayload payload de store.sync_stale (v1): una instancia edge lleva más del umbral
+// sin sincronizar con la central
+type StoreSyncStalePayload struct {
+	SchemaVersion int        `json:"schema_version"`
+	StoreID       string     `json:"store_id"`
+	InstanceID    string     `json:"instance_id"`
+	PendingEvents int        `json:"pending_events"`
+	LastSyncAt    *time.Time `json:"last_sync_at,omitempty"`
+	LastHeartbeat time.Time  `json:"last_heartbeat"`
+	LagMinutes    float64    `json:"lag_minutes"`
+}
+
+func (p *StoreSyncStalePayload) Validate() error {
+	return requirePayloadFields("store_id", p.StoreID, "instance_id", p.InstanceID)
+}
+
+// requirePayloadFields recibe pares nombre/valor y falla con el primer valor vacío
+func requirePayloadFields(fields ...string) error {
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i+1] == "" {
+			return &ValidationError{Field: fields[i], Message: fields[i] + " is required"}
+		}
+	}
+	return nil
+}
+
+type eventSchemaKey struct {
+	eventType string
+	version   int
+}
+
+// EventSchemaRegistry asocia cada tipo de evento y versión con su payload tipado.
+// Permite a los consumidores del stream validar y decodificar payloads sin conocer
+// los constructores de eventos.
+type EventSchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[eventSchemaKey]func() EventPayload
+	latest  map[string]int
+}
+
+// NewEventSchemaRegistry crea un registro vacío
+func NewEventSchemaRegistry() *EventSchemaRegistry {
+	return &EventSchemaRegistry{
+		schemas: make(map[eventSchemaKey]func() EventPayload),
+		latest:  make(map[string]int),
+	}
+}
+
+// Register asocia un tipo de evento y versión con el constructor de su payload
+func (r *EventSchemaRegistry) Register(eventType string, version int, factory func() EventPayload) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.schemas[eventSchemaKey{eventType, version}] = factory
+	if version > r.latest[eventType] {
+		r.latest[eventType] = version
+	}
+}
+
+// Latest retorna la versión más reciente registrada para un tipo de evento
+func (r *EventSchemaRegistry) Latest(eventType string) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	version, ok := r.latest[eventType]
+	return version, ok
+}
+
+// Types retorna los tipos de evento registrados, ordenados
+func (r *EventSchemaRegistry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]string, 0, len(r.latest))
+	for eventType := range r.latest {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Decode decodifica y valida un payload según su tipo y su schema_version
+// (DefaultEventSchemaVersion si no la lleva). Los campos desconocidos se ignoran,
+// de modo que añadir campos a una versión no rompe a los consumidores.
+func (r *EventSchemaRegistry) Decode(eventType string, payload []byte) (EventPayload, int, error) {
+	var header struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(payload, &header); err != nil {
+		return nil, 0, &ValidationError{Field: "payload", Message: fmt.Sprintf("invalid JSON payload: %v", err)}
+	}
+
+	version := DefaultEventSchemaVersion
+	if header.SchemaVersion != nil {
+		version = *header.SchemaVersion
+	}
+
+	r.mu.RLock()
+	factory, ok := r.schemas[eventSchemaKey{eventType, version}]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, version, &ValidationError{
+			Field:   "schema_version",
+			Message: fmt.Sprintf("no schema registered for %s v%d", eventType, version),
+		}
+	}
+
+	decoded := factory()
+	if err := json.Unmarshal(payload, decoded); err != nil {
+		return nil, version, &ValidationError{Field: "payload", Message: fmt.Sprintf("payload does not match %s v%d: %v", eventType, version, err)}
+	}
+	if err := decoded.Validate(); err != nil {
+		return nil, version, err
+	}
+
+	return decoded, version, nil
+}
+
+// DecodeEvent decodifica y valida el payload de un evento
+func (r *EventSchemaRegistry) DecodeEvent(event *Event) (EventPayload, int, error) {
+	return r.Decode(event.EventType, []byte(event.Payload))
+}
+
+// EventSchemas es el registro con los payloads de todos los eventos que publica el sistema
+var EventSchemas = newDefaultEventSchemas()
+
+func newDefaultEventSchemas() *EventSchemaRegistry {
+	r := NewEventSchemaRegistry()
+
+	r.Register(EventStockUpdated, 1, func() EventPayload { return &StockUpdatedPayload{} })
+	r.Register(EventStockCreated, 1, func() EventPayload { return &StockCreatedPayload{} })
+	r.Register(EventStockTransferred, 1, func() EventPayload { return &StockTransferredPayload{} })
+	r.Register(EventStockSnapshot, 1, func() EventPayload { return &StockSnapshotPayload{} })
+	r.Register(EventStockReservedCorrected, 1, func() EventPayload { return &StockReservedCorrectedPayload{} })
+	for _, eventType := range []string{EventAdjustmentRequested, EventAdjustmentApproved, EventAdjustmentRejected} {
+		r.Register(eventType, 1, func() EventPayload { return &StockAdjustmentPayload{} })
+	}
+	for _, eventType := range []string{EventStockHoldPlaced, EventStockHoldReleased} {
+		r.Register(eventType, 1, func() EventPayload { return &StockHoldPayload{} })
+	}
+
+	for _, eventType := range []string{EventReservationCreated, EventReservationCancelled, EventReservationExpired,
+		EventPreorderCreated, EventPreorderAllocated, EventPreorderCancelled, EventReservationReady} {
+		r.Register(eventType, 1, func() EventPayload { return &ReservationEventPayload{} })
+	}
+	r.Register(EventReservationConfirmed, 1, func() EventPayload { return &ReservationEventPayload{} })
+	r.Register(EventReservationConfirmed, ReservationConfirmedSchemaVersion, func() EventPayload { return &ReservationConfirmedPayload{} })
+	r.Register(EventReservationPickedUp, ReservationConfirmedSchemaVersion, func() EventPayload { return &ReservationConfirmedPayload{} })
+
+	for _, eventType := range []string{EventTransferDraft, EventTransferCompleted, EventTransferCancelled} {
+		r.Register(eventType, 1, func() EventPayload { return &TransferEventPayload{} })
+	}
+
+	for _, eventType := range []string{EventProductDiscontinued, EventProductArchived} {
+		r.Register(eventType, 1, func() EventPayload { return &ProductRunDownPayload{} })
+	}
+	r.Register(EventProductStatusChanged, 1, func() EventPayload { return &ProductStatusChangedPayload{} })
+	for _, eventType := range []string{EventProductCreated, EventProductUpdated, EventProductDeleted} {
+		r.Register(eventType, 1, func() EventPayload { return &ProductEventPayload{} })
+	}
+
+	r.Register(EventStoreSyncStale, 1, func() EventPayload { return &StoreSyncStalePayload{} })
+
+	return r
+}
+
+// DecodeEventPayload decodifica el payload de un evento con el registro por defecto
+func DecodeEventPayload(event *Event) (EventPayload, error) {
+	payload, _, err := EventSchemas.DecodeEvent(event)
+	return payload, err
+}
+
+// DecodeEventPayloadAs decodifica el payload de un evento al tipo concreto esperado, p. ej.
+//
+//	payload, err := domain.DecodeEventPayloadAs[*domain.ReservationConfirmedPayload](event)
+//
+// Falla si la versión del evento corresponde a otro tipo de payload.
+func DecodeEventPayloadAs[T EventPayload](event *Event) (T, error) {
+	var zero T
+
+	payload, version, err := EventSchemas.DecodeEvent(event)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := payload.(T)
+	if !ok {
+		return zero, &ValidationError{
+			Field:   "schema_version",
+			Message: fmt.Sprintf("%s v%d payload is %T, not %T", event.EventType, version, payload, zero),
+		}
+	}
+
+	return typed, nil
+}
+
+// marshalPayload serializa un payload tipado para Event.Payload
+func marshalPayload(payload EventPayload) string {
+	payloadJSON, _ := json.Marshal(payload)
+	return string(payloadJSON)
+}
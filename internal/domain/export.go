@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ExportType indica qué datos incluye una exportación
+type ExportType string
+
+const (
+	ExportStock        ExportType = "STOCK"        // Foto actual de las filas de stock
+	ExportMovements    ExportType = "MOVEMENTS"    // Movimientos de stock (eventos stock.*, sin stock.snapshot)
+	ExportReservations ExportType = "RESERVATIONS" // Reservas en todos sus estados
+)
+
+// ExportFormat formato del fichero generado
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "CSV"
+	ExportFormatJSON ExportFormat = "JSON" // Array de objetos con las mismas columnas que el CSV
+)
+
+// Extension retorna la extensión del fichero
+func (f ExportFormat) Extension() string {
+	if f == ExportFormatJSON {
+		return "json"
+	}
+	return "csv"
+}
+
+// ContentType retorna el Content-Type del fichero
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatJSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// ExportFilters limita las filas exportadas. From/To ([from, to)) aplican a la fecha del
+// movimiento o de creación de la reserva; Status solo a las reservas.
+type ExportFilters struct {
+	StoreID   string             `json:"store_id,omitempty"`
+	ProductID string             `json:"product_id,omitempty"`
+	From      *time.Time         `json:"from,omitempty"`
+	To        *time.Time         `json:"to,omitempty"`
+	Status    *ReservationStatus `json:"status,omitempty"`
+}
+
+// ExportJob representa una exportación generada en background. El fichero se guarda en el
+// almacenamiento de blobs y se descarga mientras no haya expirado.
+type ExportJob struct {
+	ID          string        `json:"id"`
+	Type        ExportType    `json:"type"`
+	Format      ExportFormat  `json:"format"`
+	Filters     ExportFilters `json:"filters"`
+	Status      JobStatus     `json:"status"`
+	Actor       string        `json:"requested_by"` // Nombre de la API key que la solicitó
+	Rows        int           `json:"rows"`
+	SizeBytes   int64         `json:"size_bytes"`
+	BlobKey     string        `json:"-"`
+	Error       string        `json:"error,omitempty"`
+	DownloadURL string        `json:"download_url,omitempty"` // Solo COMPLETED
+	CreatedAt   time.Time     `json:"created_at"`
+	StartedAt   *time.Time    `json:"started_at,omitempty"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time    `json:"expires_at,omitempty"` // Fecha a partir de la cual se borra el fichero
+}
+
+// Validate normaliza tipo y formato y verifica que los filtros apliquen al tipo de exportación
+func (j *ExportJob) Validate() error {
+	j.Type = ExportType(strings.ToUpper(strings.TrimSpace(string(j.Type))))
+	switch j.Type {
+	case ExportStock, ExportMovements, ExportReservations:
+	default:
+		return &ValidationError{Field: "type", Message: "type must be STOCK, MOVEMENTS or RESERVATIONS"}
+	}
+
+	j.Format = ExportFormat(strings.ToUpper(strings.TrimSpace(string(j.Format))))
+	if j.Format == "" {
+		j.Format = ExportFormatCSV
+	}
+	if j.Format != ExportFormatCSV && j.Format != ExportFormatJSON {
+		return &ValidationError{Field: "format", Message: "format must be CSV or JSON"}
+	}
+
+	filters := j.Filters
+	if j.Type == ExportStock && (filters.From != nil || filters.To != nil) {
+		return &ValidationError{Field: "filters", Message: "from/to do not apply to STOCK exports"}
+	}
+	if filters.From != nil && filters.To != nil && !filters.From.Before(*filters.To) {
+		return &ValidationError{Field: "filters", Message: "from must be before to"}
+	}
+	if filters.Status != nil {
+		if j.Type != ExportReservations {
+			return &ValidationError{Field: "filters", Message: "status only applies to RESERVATIONS exports"}
+		}
+		if !filters.Status.IsValid() {
+			return &ValidationError{Field: "filters", Message: "status must be PENDING, CONFIRMED, CANCELLED or EXPIRED"}
+		}
+	}
+
+	return nil
+}
+
+// FileName nombre con el que se descarga el fichero
+func (j *ExportJob) FileName() string {
+	return strings.ToLower(string(j.Type)) + "-" + j.CreatedAt.UTC().Format("20060102-150405") + "." + j.Format.Extension()
+}
+
+// ExportColumns columnas de cada tipo de exportación, en orden
+func ExportColumns(exportType ExportType) []string {
+	switch exportType {
+	case ExportStock:
+		return []string{"product_id", "sku", "store_id", "quantity", "reserved", "safety_stock", "available", "min_stock", "max_stock", "updated_at"}
+	case ExportMovements:
+		return []string{"event_id", "created_at", "event_type", "product_id", "store_id", "correlation_id", "payload"}
+	case ExportReservations:
+		return []string{"reservation_id", "product_id", "store_id", "customer_id", "quantity", "status", "priority", "channel", "created_at", "expires_at", "confirmed_at"}
+	}
+	return nil
+}
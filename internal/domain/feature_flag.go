@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Feature funcionalidad que se puede activar o desactivar por tienda sin desplegar
+type Feature string
+
+const (
+	FeatureChannelAllocation    Feature = "channel_allocation"    // Configurar asignaciones de stock por canal
+	FeatureTransferReservations Feature = "transfer_reservations" // POST /reservations/transfer (tienda preferida)
+	FeatureFractionalQuantities Feature = "fractional_quantities" // Cantidades decimales (1.25 KG) en stock y reservas
+)
+
+// Features funcionalidades con feature flag. Están activadas salvo que se desactiven, excepto
+// las de featuresOffByDefault.
+var Features = []Feature{FeatureChannelAllocation, FeatureTransferReservations, FeatureFractionalQuantities}
+
+// featuresOffByDefault funcionalidades que cambian el contrato de la API: se activan por tienda
+var featuresOffByDefault = map[Feature]bool{FeatureFractionalQuantities: true}
+
+// EnabledByDefault indica si la funcionalidad está activada cuando no hay ninguna regla
+func (f Feature) EnabledByDefault() bool {
+	return !featuresOffByDefault[f]
+}
+
+// IsValid verifica si la funcionalidad tiene feature flag
+func (f Feature) IsValid() bool {
+	for _, feature := range Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// FeatureFlagSource origen del valor efectivo de un flag
+type FeatureFlagSource string
+
+const (
+	FeatureFlagSourceDefault FeatureFlagSource = "default" // Valor por defecto (sin configuración)
+	FeatureFlagSourceConfig  FeatureFlagSource = "config"  // FEATURE_FLAGS
+	FeatureFlagSourceAdmin   FeatureFlagSource = "admin"   // /admin/feature-flags (prevalece sobre config)
+)
+
+// FeatureFlagRule activa o desactiva una funcionalidad en todas las tiendas (StoreID vacío)
+// o en una tienda concreta. La regla de la tienda prevalece sobre la global.
+type FeatureFlagRule struct {
+	Feature   Feature           `json:"feature"`
+	StoreID   string            `json:"store_id,omitempty"`
+	Enabled   bool              `json:"enabled"`
+	Source    FeatureFlagSource `json:"source"`
+	UpdatedAt *time.Time        `json:"updated_at,omitempty"` // Solo reglas de admin
+}
+
+// FeatureFlag estado de una funcionalidad: valor global y excepciones por tienda
+type FeatureFlag struct {
+	Feature Feature            `json:"feature"`
+	Enabled bool               `json:"enabled"`
+	Source  FeatureFlagSource  `json:"source"`
+	Stores  []*FeatureFlagRule `json:"stores"`
+}
+
+// FeatureDisabledError indica que la funcionalidad está desactivada para la tienda
+type FeatureDisabledError struct {
+	Feature Feature
+	StoreID string
+}
+
+func (e *FeatureDisabledError) Error() string {
+	return fmt.Sprintf("feature %s is disabled for store %s", e.Feature, e.StoreID)
+}
+
+func (e *FeatureDisabledError) Code() string {
+	return "FEATURE_DISABLED"
+}
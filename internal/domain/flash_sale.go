@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// FlashSaleProduct producto en modo alta contención: sus reservas se encolan y las
+// procesa un único writer por (producto, tienda) en lugar de competir por el lock del stock
+type FlashSaleProduct struct {
+	ProductID string    `json:"product_id"`
+	EnabledBy string    `json:"enabled_by"` // Nombre de la API key que activó el modo
+	EnabledAt time.Time `json:"enabled_at"`
+}
+
+// ReservationTicketStatus estado de una petición de reserva encolada
+type ReservationTicketStatus string
+
+const (
+	ReservationTicketQueued    ReservationTicketStatus = "QUEUED"    // Esperando al writer de su (producto, tienda)
+	ReservationTicketCompleted ReservationTicketStatus = "COMPLETED" // Reserva creada (reservation_id)
+	ReservationTicketFailed    ReservationTicketStatus = "FAILED"    // Rechazada (sin stock, límites del cliente...)
+)
+
+// ReservationTicket token asíncrono que devuelve POST /reservations para productos en
+// flash sale. El cliente consulta GET /reservations/tickets/:token hasta que se resuelve.
+type ReservationTicket struct {
+	Token         string                  `json:"token"`
+	ProductID     string                  `json:"product_id"`
+	StoreID       string                  `json:"store_id"`
+	CustomerID    string                  `json:"customer_id"`
+	Quantity      int                     `json:"quantity"`
+	Status        ReservationTicketStatus `json:"status"`
+	Position      int                     `json:"position"` // Peticiones por delante al encolar
+	ReservationID string                  `json:"reservation_id,omitempty"`
+	ErrorCode     string                  `json:"error_code,omitempty"` // Code() del error de dominio (INSUFFICIENT_STOCK...)
+	Error         string                  `json:"error,omitempty"`
+	CreatedAt     time.Time               `json:"created_at"`
+	CompletedAt   *time.Time              `json:"completed_at,omitempty"`
+}
+
+// IsResolved indica si el ticket ya tiene resultado
+func (t *ReservationTicket) IsResolved() bool {
+	return t.Status != ReservationTicketQueued
+}
+
+// QueueFullError indica que la cola de reservas de un (producto, tienda) está llena.
+// Se responde con 503 y Retry-After.
+type QueueFullError struct {
+	ProductID string
+	StoreID   string
+	Capacity  int
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("reservation queue for product %s in store %s is full (%d pending requests)", e.ProductID, e.StoreID, e.Capacity)
+}
+
+func (e *QueueFullError) Code() string {
+	return "QUEUE_FULL"
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Idempotency-Key en las escrituras de la API
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed" // "true" en las respuestas repetidas
+	MaxIdempotencyKeyLength   = 255
+	IdempotencyKeyTTL         = 24 * time.Hour  // Tras este plazo la clave se puede reutilizar
+	IdempotencyPendingTimeout = 5 * time.Minute // Una petición en curso más antigua se da por perdida
+)
+
+// IdempotencyRecord petición de escritura con Idempotency-Key y la respuesta que se dio. Las
+// claves son por API key (Scope es su hash): dos clientes pueden usar la misma sin chocar.
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	Method      string
+	Path        string
+	RequestHash string // Hash de método, ruta y cuerpo: la clave no vale para otra petición
+	Status      int    // 0 mientras la petición original está en curso
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// Completed indica si la petición original terminó y su respuesta se puede repetir
+func (r *IdempotencyRecord) Completed() bool {
+	return r.Status != 0
+}
+
+// HashIdempotentRequest calcula el hash SHA-256 (hex) de la petición asociada a una clave
+func HashIdempotentRequest(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "regexp"
+
+var (
+	// productIDPattern UUID canónico (8-4-4-4-12 hexadecimales), el formato con el que se crean los productos
+	productIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	// storeIDPattern códigos de tienda como "MAD-001": letras, dígitos, guiones y guiones bajos
+	storeIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+)
+
+// ValidateProductID verifica que el ID de producto sea un UUID. field es el nombre con el que
+// se informa el error (parámetro de la ruta o campo del body).
+func ValidateProductID(field, id string) error {
+	if !productIDPattern.MatchString(id) {
+		return &ValidationError{Field: field, Message: "must be a UUID"}
+	}
+	return nil
+}
+
+// ValidateStoreID verifica el formato de un ID de tienda
+func ValidateStoreID(field, id string) error {
+	if !storeIDPattern.MatchString(id) {
+		return &ValidationError{Field: field, Message: "must be 1-64 letters, digits, hyphens or underscores, starting with a letter or digit"}
+	}
+	return nil
+}
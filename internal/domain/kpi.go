@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// KPIs de negocio que se exponen como series temporales (nombres = target de Grafana)
+const (
+	KPIReservationsPerMinute = "reservations_per_minute" // Reservas creadas por minuto
+	KPIStockOuts             = "stock_outs"              // Filas de stock que se quedaron a 0 unidades
+	KPIFillRate              = "fill_rate"               // Confirmadas / reservas cerradas (confirmadas, canceladas, caducadas)
+	KPIExpiryRate            = "expiry_rate"             // Caducadas / reservas cerradas
+)
+
+// KPIMetrics lista los KPIs disponibles en el orden en que se devuelven
+var KPIMetrics = []string{KPIReservationsPerMinute, KPIStockOuts, KPIFillRate, KPIExpiryRate}
+
+// IsValidKPIMetric indica si el nombre corresponde a un KPI disponible
+func IsValidKPIMetric(metric string) bool {
+	for _, m := range KPIMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// KPIEventKind clasificación de los eventos de los que se derivan los KPIs
+type KPIEventKind string
+
+const (
+	KPIEventReservationCreated   KPIEventKind = "created"
+	KPIEventReservationConfirmed KPIEventKind = "confirmed"
+	KPIEventReservationCancelled KPIEventKind = "cancelled"
+	KPIEventReservationExpired   KPIEventKind = "expired"
+	KPIEventStockOut             KPIEventKind = "stock_out"
+)
+
+// KPIEvent evento del periodo que cuenta para algún KPI
+type KPIEvent struct {
+	Kind KPIEventKind
+	At   time.Time
+}
+
+// KPIDatapoint punto de una serie. Se serializa como [valor, timestamp en ms], el formato del
+// datasource JSON de Grafana; Value nil (sin reservas cerradas en el intervalo) sale como null.
+type KPIDatapoint struct {
+	Value     *float64
+	Timestamp time.Time
+}
+
+// MarshalJSON serializa el punto como [valor, epoch_ms]
+func (p KPIDatapoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]interface{}{p.Value, p.Timestamp.UnixMilli()})
+}
+
+// KPISeries serie temporal de un KPI
+type KPISeries struct {
+	Target     string         `json:"target"`
+	Datapoints []KPIDatapoint `json:"datapoints"`
+}
+
+// KPIBuckets cuenta por intervalo los eventos de los KPIs
+type KPIBuckets struct {
+	From     time.Time // Inicio del primer intervalo (alineado a Interval)
+	Interval time.Duration
+	Counts   []map[KPIEventKind]int
+}
+
+// NewKPIBuckets reparte los eventos en intervalos de interval desde from (alineado) hasta to
+func NewKPIBuckets(from, to time.Time, interval time.Duration, events []KPIEvent) *KPIBuckets {
+	start := from.Truncate(interval)
+	n := int((to.Sub(start) + interval - 1) / interval)
+	if n < 1 {
+		n = 1
+	}
+
+	b := &KPIBuckets{From: start, Interval: interval, Counts: make([]map[KPIEventKind]int, n)}
+	for i := range b.Counts {
+		b.Counts[i] = make(map[KPIEventKind]int)
+	}
+	for _, event := range events {
+		i := int(event.At.Sub(start) / interval)
+		if i < 0 || i >= n {
+			continue
+		}
+		b.Counts[i][event.Kind]++
+	}
+	return b
+}
+
+// Series calcula la serie de un KPI a partir de los conteos por intervalo
+func (b *KPIBuckets) Series(metric string) KPISeries {
+	series := KPISeries{Target: metric, Datapoints: make([]KPIDatapoint, len(b.Counts))}
+	minutes := b.Interval.Minutes()
+	for i, counts := range b.Counts {
+		closed := counts[KPIEventReservationConfirmed] + counts[KPIEventReservationCancelled] + counts[KPIEventReservationExpired]
+
+		var value *float64
+		switch metric {
+		case KPIReservationsPerMinute:
+			value = kpiValue(float64(counts[KPIEventReservationCreated]) / minutes)
+		case KPIStockOuts:
+			value = kpiValue(float64(counts[KPIEventStockOut]))
+		case KPIFillRate:
+			if closed > 0 {
+				value = kpiValue(float64(counts[KPIEventReservationConfirmed]) / float64(closed))
+			}
+		case KPIExpiryRate:
+			if closed > 0 {
+				value = kpiValue(float64(counts[KPIEventReservationExpired]) / float64(closed))
+			}
+		}
+
+		series.Datapoints[i] = KPIDatapoint{Value: value, Timestamp: b.From.Add(time.Duration(i) * b.Interval)}
+	}
+	return series
+}
+
+func kpiValue(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// RouteLatency latencias de una ruta en la ventana móvil, con su presupuesto (SLA)
+type RouteLatency struct {
+	Route           string  `json:"route"` // "MÉTODO /ruta" tal como se registra en gin
+	Count           int     `json:"count"`
+	Errors          int     `json:"errors"` // Respuestas 5xx
+	P50Ms           float64 `json:"p50_ms"`
+	P95Ms           float64 `json:"p95_ms"`
+	P99Ms           float64 `json:"p99_ms"`
+	MaxMs           float64 `json:"max_ms"`
+	BudgetMs        float64 `json:"budget_ms,omitempty"` // Presupuesto de latencia (0 = sin presupuesto)
+	OverBudget      int     `json:"over_budget"`         // Requests que superaron el presupuesto
+	OverBudgetRatio float64 `json:"over_budget_ratio"`
+}
+
+// LatencyReport percentiles por ruta de la ventana móvil, de la ruta con mayor p99 a la menor
+type LatencyReport struct {
+	WindowSeconds float64         `json:"window_seconds"`
+	Since         time.Time       `json:"since"` // Inicio de la ventana (o del proceso, si es posterior)
+	GeneratedAt   time.Time       `json:"generated_at"`
+	Routes        []*RouteLatency `json:"routes"`
+	Count         int             `json:"count"`
+}
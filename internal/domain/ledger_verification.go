@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// LedgerMismatch fila de stock cuya quantity no coincide con la reproducción de sus movimientos
+type LedgerMismatch struct {
+	ProductID    string `json:"product_id"`
+	StoreID      string `json:"store_id"`
+	Quantity     int    `json:"quantity"`      // Valor guardado en stock.quantity
+	Expected     int    `json:"expected"`      // Baseline + movimientos posteriores
+	Difference   int    `json:"difference"`    // Quantity - Expected
+	BaselineType string `json:"baseline_type"` // stock.created o stock.snapshot
+	BaselineSeq  int64  `json:"baseline_seq"`  // seq del evento baseline en la tabla events
+}
+
+// LedgerVerification resultado de reproducir el ledger de movimientos (tabla events) de cada
+// fila de stock: la baseline más reciente (stock.created o stock.snapshot) más los stock.updated
+// y reservation.confirmed posteriores. Las filas sin baseline (anteriores al pipeline de eventos
+// o cuya baseline borró la retención) no se pueden verificar y se cuentan en Unverified.
+type LedgerVerification struct {
+	Checked    int               `json:"checked"`    // Filas de stock revisadas
+	Verified   int               `json:"verified"`   // Filas con baseline
+	Unverified int               `json:"unverified"` // Filas sin baseline
+	Mismatches []*LedgerMismatch `json:"mismatches"`
+	StartedAt  time.Time         `json:"started_at"`
+	Duration   string            `json:"duration"`
+}
+
+// StockLedgerRow quantity actual de una fila de stock y la que resulta de su ledger.
+// BaselineSeq es 0 si la fila no tiene baseline.
+type StockLedgerRow struct {
+	ProductID    string
+	StoreID      string
+	Quantity     int
+	Expected     int
+	BaselineType string
+	BaselineSeq  int64
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalePolicy idiomas del catálogo. Default es el idioma de Product.Name/Description;
+// el resto se guardan como traducciones en product_translations.
+type LocalePolicy struct {
+	Default   string
+	Supported []string // Incluye Default
+}
+
+// DefaultLocalePolicy mercados ES/CA/EN con el castellano como idioma base
+func DefaultLocalePolicy() LocalePolicy {
+	return LocalePolicy{
+		Default:   "es",
+		Supported: []string{"es", "ca", "en"},
+	}
+}
+
+// NormalizeLocale reduce una etiqueta de idioma a su subetiqueta primaria ("ca-ES" → "ca")
+func NormalizeLocale(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if i := strings.IndexAny(raw, "-_"); i >= 0 {
+		raw = raw[:i]
+	}
+	return raw
+}
+
+// IsSupported indica si el idioma (ya normalizado) está habilitado
+func (p LocalePolicy) IsSupported(locale string) bool {
+	for _, supported := range p.Supported {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve elige el idioma de una lectura: ?locale= tiene prioridad sobre Accept-Language
+// y cualquier idioma no habilitado cae al idioma por defecto.
+func (p LocalePolicy) Resolve(queryLocale, acceptLanguage string) string {
+	if queryLocale != "" {
+		if locale := NormalizeLocale(queryLocale); p.IsSupported(locale) {
+			return locale
+		}
+		return p.Default
+	}
+
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale: NormalizeLocale(tag), q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if p.IsSupported(c.locale) {
+			return c.locale
+		}
+	}
+	return p.Default
+}
+
+// TranslationLocale normaliza y valida el idioma de una traducción: debe estar habilitado
+// y no ser el idioma por defecto (ese texto vive en el propio producto)
+func (p LocalePolicy) TranslationLocale(raw string) (string, error) {
+	locale := NormalizeLocale(raw)
+	if !p.IsSupported(locale) {
+		return "", &ValidationError{
+			Field:   "locale",
+			Message: fmt.Sprintf("unsupported locale %q (supported: %s)", raw, strings.Join(p.Supported, ", ")),
+		}
+	}
+	if locale == p.Default {
+		return "", &ValidationError{
+			Field:   "locale",
+			Message: fmt.Sprintf("%q is the default locale: update the product itself", locale),
+		}
+	}
+	return locale, nil
+}
+
+// ProductTranslation nombre y descripción de un producto en un idioma distinto del por defecto
+type ProductTranslation struct {
+	ProductID   string    `json:"product_id"`
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Validate verifica la traducción
+func (t *ProductTranslation) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return &ValidationError{Field: "name", Message: "Name is required"}
+	}
+	return nil
+}
+
+// Apply sustituye nombre y descripción del producto por la traducción. Una descripción
+// vacía mantiene la del idioma por defecto.
+func (t *ProductTranslation) Apply(product *Product) {
+	product.Name = t.Name
+	if t.Description != "" {
+		product.Description = t.Description
+	}
+	product.Locale = t.Locale
+}
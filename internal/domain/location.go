@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// LocationType tipo de ubicación de la red: tienda física, almacén o dark store
+type LocationType string
+
+const (
+	LocationTypeStore     LocationType = "STORE"     // Tienda con público: recogida en tienda
+	LocationTypeWarehouse LocationType = "WAREHOUSE" // Almacén: solo envíos
+	LocationTypeDarkstore LocationType = "DARKSTORE" // Tienda sin público para envíos rápidos y recogida
+)
+
+// LocationTypes lista los tipos de ubicación válidos
+var LocationTypes = []LocationType{LocationTypeStore, LocationTypeWarehouse, LocationTypeDarkstore}
+
+// IsValid verifica si el tipo de ubicación es válido
+func (t LocationType) IsValid() bool {
+	switch t {
+	case LocationTypeStore, LocationTypeWarehouse, LocationTypeDarkstore:
+		return true
+	}
+	return false
+}
+
+// ParseLocationType normaliza el tipo de ubicación recibido (vacío = STORE)
+func ParseLocationType(value string) (LocationType, error) {
+	locationType := LocationType(strings.ToUpper(strings.TrimSpace(value)))
+	if locationType == "" {
+		return LocationTypeStore, nil
+	}
+	if !locationType.IsValid() {
+		return "", &ValidationError{Field: "location_type", Message: "location_type must be STORE, WAREHOUSE or DARKSTORE"}
+	}
+	return locationType, nil
+}
+
+// FulfillmentMode forma de entrega para la que se consulta disponibilidad o se reserva
+type FulfillmentMode string
+
+const (
+	FulfillmentShip   FulfillmentMode = "SHIP"   // Envío desde stock
+	FulfillmentPickup FulfillmentMode = "PICKUP" // Recogida por el cliente
+)
+
+// ParseFulfillmentMode normaliza la forma de entrega recibida (vacío = sin preferencia)
+func ParseFulfillmentMode(value string) (FulfillmentMode, error) {
+	mode := FulfillmentMode(strings.ToUpper(strings.TrimSpace(value)))
+	switch mode {
+	case "", FulfillmentShip, FulfillmentPickup:
+		return mode, nil
+	}
+	return "", &ValidationError{Field: "fulfillment", Message: "fulfillment must be SHIP or PICKUP"}
+}
+
+// FulfillmentPolicy tipos de ubicación desde los que un canal sirve cada forma de entrega, en
+// orden de preferencia. Los tipos que no aparecen no sirven esa forma de entrega.
+type FulfillmentPolicy struct {
+	Channel   SalesChannel   `json:"channel"`
+	Ship      []LocationType `json:"ship"`
+	Pickup    []LocationType `json:"pickup"`
+	Default   bool           `json:"default,omitempty"` // Política por defecto: el canal no tiene una propia
+	UpdatedAt *time.Time     `json:"updated_at,omitempty"`
+}
+
+// DefaultFulfillmentPolicy política de los canales sin configuración propia (y de los requests
+// sin canal): los envíos salen antes de almacenes y las recogidas de tiendas
+func DefaultFulfillmentPolicy(channel SalesChannel) *FulfillmentPolicy {
+	return &FulfillmentPolicy{
+		Channel: channel,
+		Ship:    []LocationType{LocationTypeWarehouse, LocationTypeDarkstore, LocationTypeStore},
+		Pickup:  []LocationType{LocationTypeStore, LocationTypeDarkstore},
+		Default: true,
+	}
+}
+
+// Validate normaliza y valida los tipos de ubicación de la política
+func (p *FulfillmentPolicy) Validate() error {
+	if !p.Channel.IsValid() {
+		return &ValidationError{Field: "channel", Message: "channel must be WEB, STORE or MARKETPLACE"}
+	}
+
+	var err error
+	if p.Ship, err = normalizeLocationTypes("ship", p.Ship); err != nil {
+		return err
+	}
+	if p.Pickup, err = normalizeLocationTypes("pickup", p.Pickup); err != nil {
+		return err
+	}
+	if len(p.Ship) == 0 && len(p.Pickup) == 0 {
+		return &ValidationError{Field: "ship", Message: "at least one location type is required for ship or pickup"}
+	}
+	return nil
+}
+
+// LocationTypes tipos de ubicación que sirven la forma de entrega, en orden de preferencia
+func (p *FulfillmentPolicy) LocationTypes(mode FulfillmentMode) []LocationType {
+	if mode == FulfillmentPickup {
+		return p.Pickup
+	}
+	return p.Ship
+}
+
+// FulfillmentRanker ordena ubicaciones según la preferencia de una forma de entrega
+type FulfillmentRanker struct {
+	ranks     map[LocationType]int
+	locations map[string]LocationType // Tipo de cada tienda (las que faltan cuentan como STORE)
+}
+
+// NewFulfillmentRanker crea el ordenador para la forma de entrega mode de la política
+func NewFulfillmentRanker(policy *FulfillmentPolicy, mode FulfillmentMode, locations map[string]LocationType) *FulfillmentRanker {
+	ranks := make(map[LocationType]int)
+	for i, locationType := range policy.LocationTypes(mode) {
+		ranks[locationType] = i
+	}
+	return &FulfillmentRanker{ranks: ranks, locations: locations}
+}
+
+// LocationType tipo de ubicación de la tienda
+func (r *FulfillmentRanker) LocationType(storeID string) LocationType {
+	if locationType, ok := r.locations[storeID]; ok {
+		return locationType
+	}
+	return LocationTypeStore
+}
+
+// Rank posición de la tienda en la preferencia (menor = antes); ok false si su tipo de
+// ubicación no sirve la forma de entrega
+func (r *FulfillmentRanker) Rank(storeID string) (rank int, ok bool) {
+	rank, ok = r.ranks[r.LocationType(storeID)]
+	return rank, ok
+}
+
+// Less indica si la tienda a va antes que b: prefiere el tipo de ubicación de a en la política
+func (r *FulfillmentRanker) Less(a, b string) bool {
+	ra, _ := r.Rank(a)
+	rb, _ := r.Rank(b)
+	return ra < rb
+}
+
+func normalizeLocationTypes(field string, types []LocationType) ([]LocationType, error) {
+	normalized := make([]LocationType, 0, len(types))
+	seen := make(map[LocationType]bool, len(types))
+	for _, value := range types {
+		locationType := LocationType(strings.ToUpper(strings.TrimSpace(string(value))))
+		if !locationType.IsValid() {
+			return nil, &ValidationError{Field: field, Message: "location types must be STORE, WAREHOUSE or DARKSTORE"}
+		}
+		if seen[locationType] {
+			return nil, &ValidationError{Field: field, Message: "duplicated location type " + string(locationType)}
+		}
+		seen[locationType] = true
+		normalized = append(normalized, locationType)
+	}
+	return normalized, nil
+}
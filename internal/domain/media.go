@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+)
+
+// MediaContentTypes tipos de imagen aceptados y la extensión con la que se guardan
+var MediaContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// MaxMediaAltTextLength longitud máxima del texto alternativo
+const MaxMediaAltTextLength = 255
+
+// ProductMedia representa una imagen de un producto guardada en el blob store
+type ProductMedia struct {
+	ID          string    `json:"id"`
+	ProductID   string    `json:"product_id"`
+	URL         string    `json:"url"`
+	StorageKey  string    `json:"-"` // Clave en el blob store (products/<product_id>/<id>.<ext>)
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	AltText     string    `json:"alt_text"`
+	Position    int       `json:"position"` // Orden de presentación (0 = imagen principal)
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// MediaUpload datos de una imagen recibida en el endpoint de subida
+type MediaUpload struct {
+	Filename    string
+	ContentType string
+	Size        int64
+	AltText     string
+	Position    *int // nil = al final
+	Content     io.Reader
+}
+
+// Validate verifica tipo, tamaño y texto alternativo de la subida
+func (u *MediaUpload) Validate(maxBytes int64) error {
+	contentType := strings.ToLower(strings.TrimSpace(strings.SplitN(u.ContentType, ";", 2)[0]))
+	if _, ok := MediaContentTypes[contentType]; !ok {
+		return &ValidationError{Field: "file", Message: "content type must be image/jpeg, image/png, image/webp or image/gif"}
+	}
+	u.ContentType = contentType
+
+	if u.Size <= 0 {
+		return &ValidationError{Field: "file", Message: "file is empty"}
+	}
+	if maxBytes > 0 && u.Size > maxBytes {
+		return &ValidationError{Field: "file", Message: "file exceeds the maximum upload size"}
+	}
+	if len(u.AltText) > MaxMediaAltTextLength {
+		return &ValidationError{Field: "alt_text", Message: "alt_text cannot exceed 255 characters"}
+	}
+	if u.Position != nil && *u.Position < 0 {
+		return &ValidationError{Field: "position", Message: "position cannot be negative"}
+	}
+	return nil
+}
+
+// ProductMediaPatch cambios de metadatos de una imagen (solo los campos no nil)
+type ProductMediaPatch struct {
+	AltText  *string `json:"alt_text"`
+	Position *int    `json:"position"`
+}
+
+// Validate verifica el patch
+func (p ProductMediaPatch) Validate() error {
+	if p.AltText == nil && p.Position == nil {
+		return &ValidationError{Field: "body", Message: "at least one of alt_text or position is required"}
+	}
+	if p.AltText != nil && len(*p.AltText) > MaxMediaAltTextLength {
+		return &ValidationError{Field: "alt_text", Message: "alt_text cannot exceed 255 characters"}
+	}
+	if p.Position != nil && *p.Position < 0 {
+		return &ValidationError{Field: "position", Message: "position cannot be negative"}
+	}
+	return nil
+}
+
+// BlobStore almacena el contenido binario de las imágenes (y de las exportaciones).
+//
+// Implementaciones disponibles:
+//   - LocalBlobStore: disco local, servido por la propia API en /media
+//   - S3BlobStore: bucket S3 (o compatible: MinIO, LocalStack) con el SDK de AWS
+type BlobStore interface {
+	// Put guarda el contenido bajo key y retorna la URL pública
+	Put(ctx context.Context, key, contentType string, content io.Reader, size int64) (string, error)
+
+	// Get abre el contenido para leerlo (NotFoundError si no existe). El llamador lo cierra.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete elimina el contenido (sin error si no existe)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,335 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// NotificationChannel canal por el que se avisa al cliente
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail NotificationChannel = "EMAIL"
+	NotificationChannelSMS   NotificationChannel = "SMS"
+	NotificationChannelPush  NotificationChannel = "PUSH"
+)
+
+// IsValid verifica si el canal es válido
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case NotificationChannelEmail, NotificationChannelSMS, NotificationChannelPush:
+		return true
+	}
+	return false
+}
+
+// NotificationKind momento de la reserva del que se avisa
+type NotificationKind string
+
+const (
+	NotificationReservationCreated   NotificationKind = "RESERVATION_CREATED"
+	NotificationReservationExpiring  NotificationKind = "RESERVATION_EXPIRING" // Aviso previo al vencimiento (worker)
+	NotificationReservationExpired   NotificationKind = "RESERVATION_EXPIRED"
+	NotificationReservationConfirmed NotificationKind = "RESERVATION_CONFIRMED"
+)
+
+// NotificationKinds todos los avisos, en el orden del ciclo de vida de la reserva
+var NotificationKinds = []NotificationKind{
+	NotificationReservationCreated,
+	NotificationReservationExpiring,
+	NotificationReservationExpired,
+	NotificationReservationConfirmed,
+}
+
+// IsValid verifica si el tipo de aviso es válido
+func (k NotificationKind) IsValid() bool {
+	for _, kind := range NotificationKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationKindsByEvent eventos de reserva que generan un aviso (el de vencimiento próximo
+// no tiene evento: lo genera el worker)
+var notificationKindsByEvent = map[string]NotificationKind{
+	EventReservationCreated:   NotificationReservationCreated,
+	EventPreorderAllocated:    NotificationReservationCreated,
+	EventReservationExpired:   NotificationReservationExpired,
+	EventReservationConfirmed: NotificationReservationConfirmed,
+}
+
+// NotificationKindForEvent retorna el aviso que corresponde a un tipo de evento
+func NotificationKindForEvent(eventType string) (NotificationKind, bool) {
+	kind, ok := notificationKindsByEvent[eventType]
+	return kind, ok
+}
+
+// NotificationKindStatus estado que debe tener la reserva para que el aviso siga vigente: un
+// reservation.created que llega tarde (re-intento del outbox) no avisa de una reserva ya confirmada
+func NotificationKindStatus(kind NotificationKind) ReservationStatus {
+	switch kind {
+	case NotificationReservationExpired:
+		return ReservationStatusExpired
+	case NotificationReservationConfirmed:
+		return ReservationStatusConfirmed
+	}
+	return ReservationStatusPending
+}
+
+// phonePattern teléfono en formato E.164 (+34600111222)
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// CustomerContact datos de contacto de un cliente para los avisos de sus reservas.
+// Las reservas solo guardan customer_id: sin contacto no se avisa al cliente.
+type CustomerContact struct {
+	CustomerID string    `json:"customer_id"`
+	Email      string    `json:"email,omitempty"`
+	Phone      string    `json:"phone,omitempty"`      // E.164
+	PushToken  string    `json:"push_token,omitempty"` // Token del dispositivo en el proveedor de push
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Validate verifica que haya al menos un dato de contacto y su formato
+func (c *CustomerContact) Validate() error {
+	c.Email = strings.TrimSpace(c.Email)
+	c.Phone = strings.TrimSpace(c.Phone)
+	c.PushToken = strings.TrimSpace(c.PushToken)
+
+	if c.CustomerID == "" {
+		return &ValidationError{Field: "customer_id", Message: "customer_id is required"}
+	}
+	if c.Email == "" && c.Phone == "" && c.PushToken == "" {
+		return &ValidationError{Field: "contact", Message: "at least one of email, phone or push_token is required"}
+	}
+	if c.Email != "" {
+		if address, err := mail.ParseAddress(c.Email); err != nil || address.Address != c.Email {
+			return &ValidationError{Field: "email", Message: "email must be a valid address"}
+		}
+	}
+	if c.Phone != "" && !phonePattern.MatchString(c.Phone) {
+		return &ValidationError{Field: "phone", Message: "phone must be in E.164 format (e.g. +34600111222)"}
+	}
+	return nil
+}
+
+// Address destino del cliente en un canal (vacío si no tiene)
+func (c *CustomerContact) Address(channel NotificationChannel) string {
+	switch channel {
+	case NotificationChannelEmail:
+		return c.Email
+	case NotificationChannelSMS:
+		return c.Phone
+	case NotificationChannelPush:
+		return c.PushToken
+	}
+	return ""
+}
+
+// NotificationTemplate plantilla de un aviso (text/template). Subject se usa como asunto del
+// email y título del push; en SMS solo se envía Body.
+type NotificationTemplate struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// DefaultNotificationTemplates plantillas de los avisos sin personalizar por la tienda
+var DefaultNotificationTemplates = map[NotificationKind]NotificationTemplate{
+	NotificationReservationCreated: {
+		Subject: "Tu reserva en {{.StoreName}}",
+		Body:    "Hemos reservado {{.Quantity}} x {{.ProductName}} en {{.StoreName}} hasta el {{.ExpiresAt.Format \"02/01/2006 15:04\"}}. Referencia: {{.ReservationID}}.",
+	},
+	NotificationReservationExpiring: {
+		Subject: "Tu reserva en {{.StoreName}} caduca pronto",
+		Body:    "Tu reserva de {{.Quantity}} x {{.ProductName}} en {{.StoreName}} caduca en {{.MinutesLeft}} minutos. Referencia: {{.ReservationID}}.",
+	},
+	NotificationReservationExpired: {
+		Subject: "Tu reserva en {{.StoreName}} ha caducado",
+		Body:    "Tu reserva de {{.Quantity}} x {{.ProductName}} en {{.StoreName}} ha caducado y las unidades vuelven a estar a la venta. Referencia: {{.ReservationID}}.",
+	},
+	NotificationReservationConfirmed: {
+		Subject: "Reserva confirmada en {{.StoreName}}",
+		Body:    "Tu reserva de {{.Quantity}} x {{.ProductName}} en {{.StoreName}} está confirmada. Referencia: {{.ReservationID}}.",
+	},
+}
+
+// NotificationData datos disponibles en las plantillas
+type NotificationData struct {
+	Kind          NotificationKind
+	ReservationID string
+	CustomerID    string
+	ProductID     string
+	ProductName   string
+	SKU           string
+	StoreID       string
+	StoreName     string
+	Quantity      int
+	ExpiresAt     time.Time // En la zona horaria de la tienda si tiene horario, si no UTC
+	MinutesLeft   int       // Minutos hasta el vencimiento (0 si ya venció)
+}
+
+func parseNotificationTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(text)
+}
+
+// Render genera asunto y cuerpo del aviso a partir de la plantilla
+func (t NotificationTemplate) Render(data *NotificationData) (subject, body string, err error) {
+	render := func(name, text string) (string, error) {
+		tmpl, err := parseNotificationTemplate(name, text)
+		if err != nil {
+			return "", err
+		}
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, data); err != nil {
+			return "", err
+		}
+		return out.String(), nil
+	}
+
+	if subject, err = render("subject", t.Subject); err != nil {
+		return "", "", fmt.Errorf("failed to render subject: %w", err)
+	}
+	if body, err = render("body", t.Body); err != nil {
+		return "", "", fmt.Errorf("failed to render body: %w", err)
+	}
+	return subject, body, nil
+}
+
+// StoreNotificationSettings configuración de los avisos a clientes de una tienda: cada tienda
+// decide si avisa, por qué canales, de qué momentos de la reserva, con qué antelación avisa
+// del vencimiento y con qué textos. Las tiendas sin configuración no envían avisos.
+type StoreNotificationSettings struct {
+	StoreID               string                                    `json:"store_id"`
+	Enabled               bool                                      `json:"enabled"`
+	Channels              []NotificationChannel                     `json:"channels"`
+	Kinds                 []NotificationKind                        `json:"kinds"`                   // Vacío = todos
+	ExpiringBeforeMinutes int                                       `json:"expiring_before_minutes"` // Antelación del aviso de vencimiento (0 = sin aviso)
+	EmailFrom             string                                    `json:"email_from,omitempty"`    // Remitente de los emails (vacío = SMTP_FROM)
+	Templates             map[NotificationKind]NotificationTemplate `json:"templates,omitempty"`     // Sustituyen a las plantillas por defecto
+	UpdatedAt             time.Time                                 `json:"updated_at"`
+}
+
+// Validate verifica canales, avisos, remitente y que las plantillas se puedan renderizar
+func (s *StoreNotificationSettings) Validate() error {
+	if len(s.Channels) == 0 {
+		return &ValidationError{Field: "channels", Message: "at least one channel is required"}
+	}
+	for i, channel := range s.Channels {
+		s.Channels[i] = NotificationChannel(strings.ToUpper(strings.TrimSpace(string(channel))))
+		if !s.Channels[i].IsValid() {
+			return &ValidationError{Field: "channels", Message: fmt.Sprintf("unknown channel %q (options: EMAIL, SMS, PUSH)", channel)}
+		}
+	}
+	for i, kind := range s.Kinds {
+		s.Kinds[i] = NotificationKind(strings.ToUpper(strings.TrimSpace(string(kind))))
+		if !s.Kinds[i].IsValid() {
+			return &ValidationError{Field: "kinds", Message: fmt.Sprintf("unknown notification kind %q", kind)}
+		}
+	}
+	if s.ExpiringBeforeMinutes < 0 {
+		return &ValidationError{Field: "expiring_before_minutes", Message: "expiring_before_minutes cannot be negative"}
+	}
+	if s.EmailFrom != "" {
+		if _, err := mail.ParseAddress(s.EmailFrom); err != nil {
+			return &ValidationError{Field: "email_from", Message: "email_from must be a valid address"}
+		}
+	}
+
+	sample := &NotificationData{ExpiresAt: time.Now()}
+	for kind, tmpl := range s.Templates {
+		if !kind.IsValid() {
+			return &ValidationError{Field: "templates", Message: fmt.Sprintf("unknown notification kind %q", kind)}
+		}
+		if strings.TrimSpace(tmpl.Body) == "" {
+			return &ValidationError{Field: "templates", Message: fmt.Sprintf("%s: body is required", kind)}
+		}
+		if _, _, err := tmpl.Render(sample); err != nil {
+			return &ValidationError{Field: "templates", Message: fmt.Sprintf("%s: %v", kind, err)}
+		}
+	}
+	return nil
+}
+
+// Notifies indica si la tienda avisa de kind
+func (s *StoreNotificationSettings) Notifies(kind NotificationKind) bool {
+	if !s.Enabled {
+		return false
+	}
+	if kind == NotificationReservationExpiring && s.ExpiringBeforeMinutes == 0 {
+		return false
+	}
+	if len(s.Kinds) == 0 {
+		return true
+	}
+	for _, k := range s.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Template plantilla de kind: la de la tienda o la de por defecto
+func (s *StoreNotificationSettings) Template(kind NotificationKind) NotificationTemplate {
+	if tmpl, ok := s.Templates[kind]; ok {
+		return tmpl
+	}
+	return DefaultNotificationTemplates[kind]
+}
+
+// Notification aviso listo para enviar por un canal
+type Notification struct {
+	ID            string // Identificador estable del aviso (reserva, tipo y canal): sirve de clave de idempotencia
+	Kind          NotificationKind
+	Channel       NotificationChannel
+	ReservationID string
+	StoreID       string
+	From          string // Solo email; vacío = remitente por defecto del notifier
+	To            string
+	Subject       string
+	Body          string
+}
+
+// Notifier adaptador de un canal de avisos (SMTP, proveedor de SMS, push...).
+//
+// Implementaciones disponibles:
+//   - SMTPNotifier: email por SMTP (SMTP_HOST)
+type Notifier interface {
+	// Channel canal que atiende el notifier
+	Channel() NotificationChannel
+
+	// Send envía el aviso. Cada aviso se envía una sola vez: los fallos se registran y no se reintentan.
+	Send(ctx context.Context, notification *Notification) error
+}
+
+// NotificationStatus resultado del envío de un aviso
+type NotificationStatus string
+
+const (
+	NotificationStatusPending NotificationStatus = "PENDING" // Reclamado por una instancia, enviándose
+	NotificationStatusSent    NotificationStatus = "SENT"
+	NotificationStatusFailed  NotificationStatus = "FAILED"
+	NotificationStatusSkipped NotificationStatus = "SKIPPED" // Sin contacto en el canal o sin notifier configurado
+)
+
+// NotificationDelivery registro de un aviso de una reserva por un canal. Hay como mucho uno
+// por (reserva, tipo, canal), así que los eventos repetidos no duplican avisos.
+type NotificationDelivery struct {
+	ID            string              `json:"id"`
+	ReservationID string              `json:"reservation_id"`
+	StoreID       string              `json:"store_id"`
+	Kind          NotificationKind    `json:"kind"`
+	Channel       NotificationChannel `json:"channel"`
+	Recipient     string              `json:"recipient,omitempty"`
+	Status        NotificationStatus  `json:"status"`
+	Error         string              `json:"error,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	SentAt        *time.Time          `json:"sent_at,omitempty"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// OversellScope ámbito de una tolerancia de sobreventa
+type OversellScope string
+
+const (
+	OversellScopeStore   OversellScope = "STORE"   // Todos los productos de la tienda
+	OversellScopeProduct OversellScope = "PRODUCT" // El producto en todas las tiendas (prevalece sobre STORE)
+)
+
+// IsValid verifica si el ámbito es válido
+func (s OversellScope) IsValid() bool {
+	return s == OversellScopeStore || s == OversellScopeProduct
+}
+
+// OversellTolerance permite que la disponibilidad (quantity - reserved) baje hasta -MaxUnits
+// en tiendas que venden por encima del stock y reponen desde el almacén. Reservar, confirmar
+// y ajustar respetan el suelo; las transferencias y la sincronización siguen sin sobreventa.
+type OversellTolerance struct {
+	Scope     OversellScope `json:"scope"`
+	ScopeID   string        `json:"scope_id"` // store_id o product_id según Scope
+	MaxUnits  int           `json:"max_units"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Validate normaliza y verifica la tolerancia
+func (t *OversellTolerance) Validate() error {
+	t.Scope = OversellScope(strings.ToUpper(strings.TrimSpace(string(t.Scope))))
+	t.ScopeID = strings.TrimSpace(t.ScopeID)
+	if !t.Scope.IsValid() {
+		return &ValidationError{Field: "scope", Message: "scope must be STORE or PRODUCT"}
+	}
+	if t.ScopeID == "" {
+		return &ValidationError{Field: "scope_id", Message: "scope_id is required"}
+	}
+	if t.MaxUnits <= 0 {
+		return &ValidationError{Field: "max_units", Message: "max_units must be positive"}
+	}
+	return nil
+}
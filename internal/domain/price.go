@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// PriceChangeSource indica cómo se originó un cambio de precio
+type PriceChangeSource string
+
+const (
+	PriceChangeManual    PriceChangeSource = "MANUAL"    // PUT /products/:id
+	PriceChangeScheduled PriceChangeSource = "SCHEDULED" // Aplicado por el worker de precios programados
+)
+
+// Límites de paginación del historial de precios
+const (
+	DefaultPriceHistoryLimit = 50
+	MaxPriceHistoryLimit     = 500
+)
+
+// PriceChange representa una entrada del historial de precios de un producto
+type PriceChange struct {
+	ID          string            `json:"id"`
+	ProductID   string            `json:"product_id"`
+	OldPrice    float64           `json:"old_price"`
+	NewPrice    float64           `json:"new_price"`
+	Actor       string            `json:"actor"` // Nombre de la API key que hizo el cambio (o programó el cambio)
+	Source      PriceChangeSource `json:"source"`
+	ScheduledID string            `json:"scheduled_id,omitempty"` // Cambio programado que lo originó
+	EffectiveAt time.Time         `json:"effective_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// ScheduledPriceStatus representa el estado de un cambio de precio programado
+type ScheduledPriceStatus string
+
+const (
+	ScheduledPricePending   ScheduledPriceStatus = "PENDING"   // Esperando effective_at
+	ScheduledPriceApplied   ScheduledPriceStatus = "APPLIED"   // Precio aplicado por el worker
+	ScheduledPriceCancelled ScheduledPriceStatus = "CANCELLED" // Anulado antes de aplicarse
+)
+
+// ScheduledPriceChange representa un cambio de precio futuro
+type ScheduledPriceChange struct {
+	ID          string               `json:"id"`
+	ProductID   string               `json:"product_id"`
+	NewPrice    float64              `json:"new_price"`
+	Actor       string               `json:"actor"`
+	Status      ScheduledPriceStatus `json:"status"`
+	EffectiveAt time.Time            `json:"effective_at"`
+	CreatedAt   time.Time            `json:"created_at"`
+	AppliedAt   *time.Time           `json:"applied_at,omitempty"`
+}
+
+// Validate verifica que el cambio programado tenga datos válidos
+func (s *ScheduledPriceChange) Validate(now time.Time) error {
+	if s.NewPrice < 0 {
+		return &ValidationError{Field: "new_price", Message: "Price cannot be negative"}
+	}
+	if s.EffectiveAt.IsZero() {
+		return &ValidationError{Field: "effective_at", Message: "effective_at is required"}
+	}
+	if !s.EffectiveAt.After(now) {
+		return &ValidationError{Field: "effective_at", Message: "effective_at must be in the future"}
+	}
+	return nil
+}
@@ -4,18 +4,28 @@ import "time"
 
 // Product representa un producto en el catálogo
 type Product struct {
-	ID          string    `json:"id" db:"id"`
-	SKU         string    `json:"sku" db:"sku"`                 // Código único del producto
-	Name        string    `json:"name" db:"name"`               // Nombre del producto
-	Description string    `json:"description" db:"description"` // Descripción
-	Category    string    `json:"category" db:"category"`       // Categoría
-	Price       float64   `json:"price" db:"price"`             // Precio
-	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
-	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+	ID          string        `json:"id" db:"id"`
+	SKU         string        `json:"sku" db:"sku"`                 // Código único del producto
+	Name        string        `json:"name" db:"name"`               // Nombre del producto
+	Description string        `json:"description" db:"description"` // Descripción
+	Category    string        `json:"category" db:"category"`       // Categoría
+	Price       float64       `json:"price" db:"price"`             // Precio
+	Status      ProductStatus `json:"status" db:"status"`           // Estado del ciclo de vida
+	ABCClass    ABCClass      `json:"abcClass" db:"abc_class"`      // Clase ABC (la asigna el worker de clasificación)
+	CreatedAt   time.Time     `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updatedAt" db:"updated_at"`
+
+	Media  []*ProductMedia `json:"media,omitempty" db:"-"`  // Imágenes en orden de presentación (solo en lecturas)
+	Locale string          `json:"locale,omitempty" db:"-"` // Idioma de name/description (solo en lecturas)
 }
 
 // Validate verifica que el producto tenga datos válidos
 func (p *Product) Validate() error {
+	if p.ID != "" {
+		if err := ValidateProductID("id", p.ID); err != nil {
+			return err
+		}
+	}
 	if p.SKU == "" {
 		return &ValidationError{Field: "sku", Message: "SKU is required"}
 	}
@@ -25,5 +35,105 @@ func (p *Product) Validate() error {
 	if p.Price < 0 {
 		return &ValidationError{Field: "price", Message: "Price cannot be negative"}
 	}
+	if p.Status != "" && !p.Status.IsValid() {
+		return &ValidationError{Field: "status", Message: "Status must be DRAFT, ACTIVE or DISCONTINUED"}
+	}
 	return nil
 }
+
+// ProductStatus representa el estado del ciclo de vida de un producto
+type ProductStatus string
+
+const (
+	ProductStatusDraft        ProductStatus = "DRAFT"        // En preparación: no admite stock ni reservas
+	ProductStatusActive       ProductStatus = "ACTIVE"       // Vendible (estado por defecto)
+	ProductStatusDiscontinued ProductStatus = "DISCONTINUED" // Consultable, pero oculto en los listados por defecto
+)
+
+// CatalogStoreID es el origen de los eventos del catálogo, que no pertenecen a ninguna tienda
+const CatalogStoreID = "CATALOG"
+
+// productStatusTransitions define las transiciones permitidas entre estados
+var productStatusTransitions = map[ProductStatus][]ProductStatus{
+	ProductStatusDraft:        {ProductStatusActive, ProductStatusDiscontinued},
+	ProductStatusActive:       {ProductStatusDiscontinued},
+	ProductStatusDiscontinued: {ProductStatusActive},
+}
+
+// IsValid indica si el estado es uno de los definidos
+func (s ProductStatus) IsValid() bool {
+	_, ok := productStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo indica si el producto puede pasar del estado actual a next
+func (s ProductStatus) CanTransitionTo(next ProductStatus) bool {
+	for _, allowed := range productStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSellable indica si el producto admite inicializar stock y crear reservas
+func (p *Product) IsSellable() bool {
+	return p.Status == ProductStatusActive
+}
+
+// ProductDependencies resume el stock y las reservas que dependen de un producto
+type ProductDependencies struct {
+	ProductID           string   `json:"product_id"`
+	StockUnits          int      `json:"stock_units"`          // Suma de quantity en todas las tiendas
+	ReservedUnits       int      `json:"reserved_units"`       // Suma de reserved en todas las tiendas
+	Stores              []string `json:"stores"`               // Tiendas con unidades o reservas
+	PendingReservations int      `json:"pending_reservations"` // Reservas en estado PENDING
+}
+
+// InUse indica si eliminar el producto perdería stock o reservas vivas
+func (d ProductDependencies) InUse() bool {
+	return d.StockUnits > 0 || d.ReservedUnits > 0 || d.PendingReservations > 0
+}
+
+// ProductArchive resume las filas archivadas al eliminar un producto con force=true
+type ProductArchive struct {
+	ProductID            string    `json:"product_id"`
+	SKU                  string    `json:"sku"`
+	ArchivedStock        int       `json:"archived_stock"`
+	ArchivedReservations int       `json:"archived_reservations"`
+	ArchivedAt           time.Time `json:"archived_at"`
+}
+
+// ProductPatch actualización parcial de un producto: solo se modifican los campos
+// presentes (no nil). El estado se cambia con su propio endpoint.
+type ProductPatch struct {
+	SKU         *string  `json:"sku"`
+	Name        *string  `json:"name"`
+	Description *string  `json:"description"`
+	Category    *string  `json:"category"`
+	Price       *float64 `json:"price"`
+}
+
+// IsEmpty indica si el patch no modifica ningún campo
+func (p ProductPatch) IsEmpty() bool {
+	return p.SKU == nil && p.Name == nil && p.Description == nil && p.Category == nil && p.Price == nil
+}
+
+// Apply copia los campos presentes sobre product
+func (p ProductPatch) Apply(product *Product) {
+	if p.SKU != nil {
+		product.SKU = *p.SKU
+	}
+	if p.Name != nil {
+		product.Name = *p.Name
+	}
+	if p.Description != nil {
+		product.Description = *p.Description
+	}
+	if p.Category != nil {
+		product.Category = *p.Category
+	}
+	if p.Price != nil {
+		product.Price = *p.Price
+	}
+}
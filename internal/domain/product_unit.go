@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// UnitEach unidad base de los productos sin unidades de medida configuradas
+const UnitEach = "EACH"
+
+// maxUnitFactor acota los factores de conversión para que quantity * factor no desborde
+const maxUnitFactor = 100000
+
+// unitPattern nombres de unidad aceptados (tras normalizar a mayúsculas): EACH, BOX, KG, CASE_24...
+var unitPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,15}$`)
+
+// NormalizeUnit normaliza un nombre de unidad (mayúsculas, sin espacios)
+func NormalizeUnit(unit string) string {
+	return strings.ToUpper(strings.TrimSpace(unit))
+}
+
+// maxExactQuantity mayor cantidad que un float64 representa sin perder unidades (2^53)
+const maxExactQuantity = 1 << 53
+
+// roundingTolerance absorbe el error de coma flotante de las conversiones (1.1 * 1000 = 1100.0000000000002)
+const roundingTolerance = 1e-6
+
+// RoundingMode cómo se redondean a unidades base enteras las cantidades decimales
+type RoundingMode string
+
+const (
+	RoundingNearest RoundingMode = "NEAREST" // Al entero más cercano (las mitades, lejos de cero)
+	RoundingDown    RoundingMode = "DOWN"    // Hacia cero: nunca se mueve más de lo pedido
+	RoundingUp      RoundingMode = "UP"      // Lejos de cero
+	RoundingExact   RoundingMode = "EXACT"   // Sin redondeo: rechaza las cantidades que no dan unidades base enteras
+)
+
+// IsValid verifica si el modo de redondeo es válido
+func (m RoundingMode) IsValid() bool {
+	switch m {
+	case RoundingNearest, RoundingDown, RoundingUp, RoundingExact:
+		return true
+	}
+	return false
+}
+
+// Round redondea value a unidades base enteras según el modo
+func (m RoundingMode) Round(value float64) (int, error) {
+	if nearest := math.Round(value); math.Abs(value-nearest) < roundingTolerance {
+		return int(nearest), nil
+	}
+	switch m {
+	case RoundingDown:
+		return int(math.Trunc(value)), nil
+	case RoundingUp:
+		if value < 0 {
+			return int(math.Floor(value)), nil
+		}
+		return int(math.Ceil(value)), nil
+	case RoundingExact:
+		return 0, &ValidationError{
+			Field:   "quantity",
+			Message: fmt.Sprintf("quantity is %g base units and the product does not round (rounding EXACT)", value),
+		}
+	}
+	return int(math.Round(value)), nil
+}
+
+// IsWholeQuantity indica si una cantidad decimal de una petición es un entero representable
+func IsWholeQuantity(quantity float64) bool {
+	return quantity == math.Trunc(quantity) && math.Abs(quantity) <= maxExactQuantity
+}
+
+// UnitConversion unidad de pedido y cuántas unidades base contiene (p. ej. BOX = 12)
+type UnitConversion struct {
+	Unit       string `json:"unit"`
+	Factor     int    `json:"factor"`
+	Fractional bool   `json:"fractional,omitempty"` // Admite cantidades decimales (p. ej. 1.25 KG con base G)
+}
+
+// ProductUnits unidades de medida de un producto. El stock, las reservas y los movimientos se
+// guardan siempre en BaseUnit; las peticiones pueden indicar cantidad + unidad y se convierten
+// con ToBase (las mayoristas piden por cajas, las tiendas cuentan por unidades).
+//
+// El stock es siempre entero: para vender a granel la unidad base es la fracción mínima (G) y la
+// unidad de venta una conversión fractional (KG = 1000); Rounding decide qué hacer cuando una
+// cantidad decimal no da unidades base enteras.
+type ProductUnits struct {
+	ProductID   string           `json:"product_id"`
+	BaseUnit    string           `json:"base_unit"` // Unidad en la que se guarda el stock (EACH, KG...)
+	Conversions []UnitConversion `json:"conversions"`
+	Rounding    RoundingMode     `json:"rounding"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// DefaultProductUnits unidades de un producto sin configuración: solo EACH
+func DefaultProductUnits(productID string) *ProductUnits {
+	return &ProductUnits{ProductID: productID, BaseUnit: UnitEach, Conversions: []UnitConversion{}, Rounding: RoundingNearest}
+}
+
+// Validate normaliza los nombres de unidad y verifica que las conversiones sean coherentes
+func (u *ProductUnits) Validate() error {
+	u.BaseUnit = NormalizeUnit(u.BaseUnit)
+	if !unitPattern.MatchString(u.BaseUnit) {
+		return &ValidationError{Field: "base_unit", Message: fmt.Sprintf("invalid unit %q", u.BaseUnit)}
+	}
+
+	seen := map[string]bool{u.BaseUnit: true}
+	for i := range u.Conversions {
+		conversion := &u.Conversions[i]
+		conversion.Unit = NormalizeUnit(conversion.Unit)
+		if !unitPattern.MatchString(conversion.Unit) {
+			return &ValidationError{Field: "conversions", Message: fmt.Sprintf("invalid unit %q", conversion.Unit)}
+		}
+		if seen[conversion.Unit] {
+			return &ValidationError{Field: "conversions", Message: fmt.Sprintf("unit %s is defined more than once", conversion.Unit)}
+		}
+		if conversion.Factor < 1 || conversion.Factor > maxUnitFactor {
+			return &ValidationError{
+				Field:   "conversions",
+				Message: fmt.Sprintf("factor of %s must be between 1 and %d base units", conversion.Unit, maxUnitFactor),
+			}
+		}
+		seen[conversion.Unit] = true
+	}
+	if u.Conversions == nil {
+		u.Conversions = []UnitConversion{}
+	}
+
+	u.Rounding = RoundingMode(strings.ToUpper(strings.TrimSpace(string(u.Rounding))))
+	if u.Rounding == "" {
+		u.Rounding = RoundingNearest
+	}
+	if !u.Rounding.IsValid() {
+		return &ValidationError{Field: "rounding", Message: "rounding must be NEAREST, DOWN, UP or EXACT"}
+	}
+	return nil
+}
+
+// ToBase convierte una cantidad expresada en unit a unidades base (unit vacía = unidad base).
+// Las cantidades negativas (ajustes) se convierten igual.
+func (u *ProductUnits) ToBase(quantity int, unit string) (int, error) {
+	unit = NormalizeUnit(unit)
+	if unit == "" || unit == u.BaseUnit {
+		return quantity, nil
+	}
+	for _, conversion := range u.Conversions {
+		if conversion.Unit == unit {
+			return quantity * conversion.Factor, nil
+		}
+	}
+	return 0, &ValidationError{
+		Field:   "unit",
+		Message: fmt.Sprintf("unit %s is not defined for product %s (base unit: %s)", unit, u.ProductID, u.BaseUnit),
+	}
+}
+
+// ToBaseDecimal convierte una cantidad decimal (1.25 KG) a unidades base enteras. Las cantidades
+// enteras se convierten como en ToBase; las decimales solo en unidades fractional, aplicando
+// Rounding si el resultado no es entero.
+func (u *ProductUnits) ToBaseDecimal(quantity float64, unit string) (int, error) {
+	if math.IsNaN(quantity) || math.Abs(quantity) > maxExactQuantity {
+		return 0, &ValidationError{Field: "quantity", Message: "quantity is out of range"}
+	}
+	if IsWholeQuantity(quantity) {
+		return u.ToBase(int(quantity), unit)
+	}
+
+	unit = NormalizeUnit(unit)
+	var conversion *UnitConversion
+	for i := range u.Conversions {
+		if u.Conversions[i].Unit == unit {
+			conversion = &u.Conversions[i]
+		}
+	}
+	if conversion == nil || !conversion.Fractional {
+		if unit == "" {
+			unit = u.BaseUnit
+		}
+		return 0, &ValidationError{
+			Field:   "quantity",
+			Message: fmt.Sprintf("unit %s of product %s does not accept fractional quantities", unit, u.ProductID),
+		}
+	}
+
+	base, err := u.Rounding.Round(quantity * float64(conversion.Factor))
+	if err != nil {
+		return 0, err
+	}
+	if base == 0 {
+		return 0, &ValidationError{
+			Field:   "quantity",
+			Message: fmt.Sprintf("%g %s rounds to zero %s", quantity, unit, u.BaseUnit),
+		}
+	}
+	return base, nil
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 // EventPublisher define el contrato para publicar eventos a un message broker.
 // Esta interfaz permite cambiar entre diferentes implementaciones (Kafka, Redis)
@@ -8,6 +12,8 @@ import "context"
 //
 // Implementaciones disponibles:
 //   - RedisPublisher: Para Redis Streams (simple, rápido setup)
+//   - NATSPublisher: Para NATS JetStream (despliegues que ya operan NATS)
+//   - PubSubPublisher / SNSPublisher: Brokers gestionados (Google Cloud Pub/Sub, AWS SNS → SQS)
 //   - KafkaPublisher: Para Apache Kafka (alto throughput, retención larga)
 //   - MockPublisher: Para tests unitarios
 type EventPublisher interface {
@@ -38,3 +44,28 @@ type EventPublisher interface {
 	// Debe ser llamado al finalizar la aplicación (típicamente con defer).
 	Close() error
 }
+
+// ErrPublisherUnavailable lo devuelve el publisher cuando el circuit breaker está abierto:
+// no se intenta contactar con el broker y el evento queda en el outbox (tabla events,
+// synced = false) hasta que el worker de sincronización lo publique.
+var ErrPublisherUnavailable = errors.New("event publisher unavailable (circuit open)")
+
+// CircuitState estado del circuit breaker del publisher
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Publicación normal
+	CircuitOpen     CircuitState = "open"      // Broker caído: se falla rápido sin contactarlo
+	CircuitHalfOpen CircuitState = "half_open" // Se permite una publicación de prueba
+)
+
+// PublisherStatus snapshot del circuit breaker, expuesto en /ready y en /metrics/publisher
+type PublisherStatus struct {
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	FailureThreshold    int          `json:"failure_threshold"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	LastError           string       `json:"last_error,omitempty"`
+	RejectedTotal       uint64       `json:"rejected_total"` // Publicaciones descartadas con el circuito abierto
+	OpensTotal          uint64       `json:"opens_total"`    // Veces que se abrió el circuito
+}
@@ -0,0 +1,136 @@
+package domain
+
+import "time"
+
+// InventoryOverview representa la vista global del inventario para el dashboard de operaciones
+type InventoryOverview struct {
+	Category        string                 `json:"category,omitempty"`
+	GroupID         string                 `json:"group_id,omitempty"`
+	GeneratedAt     time.Time              `json:"generated_at"`
+	Totals          InventoryTotals        `json:"totals"`
+	Stores          []StoreInventoryTotals `json:"stores"`
+	Groups          []GroupInventoryTotals `json:"groups,omitempty"`
+	LowStock        []LowStockProduct      `json:"low_stock"`
+	OutOfStockCount int                    `json:"out_of_stock_count"`
+	OversoldCount   int                    `json:"oversold_count"` // Filas con disponibilidad negativa (sobreventa)
+}
+
+// InventoryTotals representa los totales agregados de stock
+type InventoryTotals struct {
+	StockRows int `json:"stock_rows"`
+	Quantity  int `json:"quantity"`
+	Reserved  int `json:"reserved"` // Reservas de clientes y retenciones internas
+	Held      int `json:"held"`     // Parte de reserved retenida para uso interno
+	Available int `json:"available"`
+}
+
+// StoreInventoryTotals representa los totales de una tienda
+type StoreInventoryTotals struct {
+	StoreID    string `json:"store_id"`
+	StoreName  string `json:"store_name,omitempty"`
+	Products   int    `json:"products"`
+	Quantity   int    `json:"quantity"`
+	Reserved   int    `json:"reserved"`
+	Held       int    `json:"held"`
+	Available  int    `json:"available"`
+	OutOfStock int    `json:"out_of_stock"`
+	Oversold   int    `json:"oversold"`
+}
+
+// GroupInventoryTotals representa los totales de un grupo de tiendas (suma de sus tiendas con stock)
+type GroupInventoryTotals struct {
+	GroupID    string         `json:"group_id"`
+	Name       string         `json:"name"`
+	Type       StoreGroupType `json:"type"`
+	Stores     int            `json:"stores"`
+	StockRows  int            `json:"stock_rows"`
+	Quantity   int            `json:"quantity"`
+	Reserved   int            `json:"reserved"`
+	Held       int            `json:"held"`
+	Available  int            `json:"available"`
+	OutOfStock int            `json:"out_of_stock"`
+	Oversold   int            `json:"oversold"`
+}
+
+// LowStockProduct representa un producto con baja disponibilidad en toda la red
+type LowStockProduct struct {
+	ProductID        string `json:"product_id"`
+	SKU              string `json:"sku"`
+	Name             string `json:"name"`
+	Category         string `json:"category"`
+	TotalAvailable   int    `json:"total_available"`
+	StoresOutOfStock int    `json:"stores_out_of_stock"`
+}
+
+// LowStockEntry representa una fila de stock (producto/tienda) por debajo del umbral
+type LowStockEntry struct {
+	ProductID   string   `json:"product_id"`
+	SKU         string   `json:"sku"`
+	ABCClass    ABCClass `json:"abc_class,omitempty"`
+	StoreID     string   `json:"store_id"`
+	Quantity    int      `json:"quantity"`
+	Reserved    int      `json:"reserved"`
+	SafetyStock int      `json:"safety_stock"`
+	Available   int      `json:"available"` // Vendible: quantity - reserved - safety_stock
+}
+
+// LowStockMetrics representa el snapshot de stock bajo que se exporta a Prometheus.
+// Items está acotado a TopN; Total cuenta todas las filas por debajo del umbral.
+type LowStockMetrics struct {
+	Threshold int             `json:"threshold"`
+	TopN      int             `json:"top_n"`
+	Total     int             `json:"total"`
+	Items     []LowStockEntry `json:"items"`
+}
+
+// Origen del instante en que una fila quedó sin disponibilidad
+const (
+	OutSinceMovement = "movement"  // Último evento que alteró la disponibilidad
+	OutSinceStockRow = "stock_row" // Sin eventos: updated_at de la fila de stock
+)
+
+// OutOfStockItem representa una fila de stock (producto/tienda) sin disponibilidad (available <= 0)
+type OutOfStockItem struct {
+	ProductID      string    `json:"product_id"`
+	SKU            string    `json:"sku"`
+	Name           string    `json:"name"`
+	Category       string    `json:"category"`
+	StoreID        string    `json:"store_id"`
+	Quantity       int       `json:"quantity"`
+	Reserved       int       `json:"reserved"`
+	Held           int       `json:"held"` // Parte de reserved retenida para uso interno
+	Available      int       `json:"available"`
+	OutSince       time.Time `json:"out_since"`
+	OutForSeconds  int64     `json:"out_for_seconds"`
+	OutSinceSource string    `json:"out_since_source"` // movement | stock_row
+	Oversold       bool      `json:"oversold"`         // available < 0: vendido por encima del stock
+}
+
+// OutOfStockReport representa el listado de filas sin disponibilidad, las que llevan más tiempo primero
+type OutOfStockReport struct {
+	StoreID     string           `json:"store_id,omitempty"`
+	GroupID     string           `json:"group_id,omitempty"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Items       []OutOfStockItem `json:"items"`
+	Count       int              `json:"count"`
+	Oversold    int              `json:"oversold"`
+}
+
+// AdjustmentReasonSummary ajustes registrados con un motivo en el periodo del reporte
+type AdjustmentReasonSummary struct {
+	Reason       string `json:"reason"`
+	Adjustments  int    `json:"adjustments"`   // Movimientos stock.updated con el motivo
+	UnitsAdded   int    `json:"units_added"`   // Unidades sumadas por los ajustes positivos
+	UnitsRemoved int    `json:"units_removed"` // Unidades retiradas por los ajustes negativos
+	NetUnits     int    `json:"net_units"`     // units_added - units_removed
+}
+
+// AdjustmentReasonReport ajustes manuales agrupados por motivo, los de más movimientos primero
+type AdjustmentReasonReport struct {
+	StoreID     string                    `json:"store_id,omitempty"`
+	From        *time.Time                `json:"from,omitempty"`
+	To          *time.Time                `json:"to,omitempty"`
+	GeneratedAt time.Time                 `json:"generated_at"`
+	Items       []AdjustmentReasonSummary `json:"items"`
+	Count       int                       `json:"count"`
+}
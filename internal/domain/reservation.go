@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // ReservationStatus representa el estado de una reserva
 type ReservationStatus string
@@ -10,24 +14,79 @@ const (
 	ReservationStatusConfirmed ReservationStatus = "CONFIRMED" // Confirmada, stock comprometido
 	ReservationStatusCancelled ReservationStatus = "CANCELLED" // Cancelada manualmente
 	ReservationStatusExpired   ReservationStatus = "EXPIRED"   // Expirada automáticamente
+	ReservationStatusPreorder  ReservationStatus = "PREORDER"  // Preventa contra stock entrante, sin stock asignado todavía
+
+	ReservationStatusReadyForPickup ReservationStatus = "READY_FOR_PICKUP" // Preparada en tienda esperando al cliente (click-and-collect)
+	ReservationStatusPickedUp       ReservationStatus = "PICKED_UP"        // Recogida por el cliente, stock comprometido
+)
+
+// ReservationPriority prioridad de una reserva (p. ej. pedido pagado frente a carrito).
+// Cuando hay que liberar stock se liberan primero las de menor prioridad.
+type ReservationPriority string
+
+const (
+	ReservationPriorityLow    ReservationPriority = "LOW"    // Retención de carrito
+	ReservationPriorityNormal ReservationPriority = "NORMAL" // Por defecto
+	ReservationPriorityHigh   ReservationPriority = "HIGH"   // Pedido pagado
 )
 
+// IsValid verifica si la prioridad es válida
+func (p ReservationPriority) IsValid() bool {
+	switch p {
+	case ReservationPriorityLow, ReservationPriorityNormal, ReservationPriorityHigh:
+		return true
+	}
+	return false
+}
+
+// Rank retorna el orden de la prioridad (mayor = se conserva antes)
+func (p ReservationPriority) Rank() int {
+	switch p {
+	case ReservationPriorityLow:
+		return 0
+	case ReservationPriorityHigh:
+		return 2
+	}
+	return 1
+}
+
+// ParseReservationPriority normaliza la prioridad recibida (vacía = NORMAL)
+func ParseReservationPriority(value string) (ReservationPriority, error) {
+	if value == "" {
+		return ReservationPriorityNormal, nil
+	}
+	priority := ReservationPriority(strings.ToUpper(strings.TrimSpace(value)))
+	if !priority.IsValid() {
+		return "", &ValidationError{Field: "priority", Message: "priority must be LOW, NORMAL or HIGH"}
+	}
+	return priority, nil
+}
+
 // Reservation representa una reserva temporal de stock
 type Reservation struct {
-	ID         string            `json:"id" db:"id"`
-	ProductID  string            `json:"productId" db:"product_id"`
-	StoreID    string            `json:"storeId" db:"store_id"`       // Tienda donde se reserva
-	CustomerID string            `json:"customerId" db:"customer_id"` // Cliente que reserva
-	Quantity   int               `json:"quantity" db:"quantity"`
-	Status     ReservationStatus `json:"status" db:"status"`
-	ExpiresAt  time.Time         `json:"expiresAt" db:"expires_at"`
-	CreatedAt  time.Time         `json:"createdAt" db:"created_at"`
-	UpdatedAt  *time.Time        `json:"updatedAt,omitempty" db:"updated_at"`
-}
-
-// IsExpired verifica si la reserva ha expirado
+	ID          string              `json:"id" db:"id"`
+	ProductID   string              `json:"productId" db:"product_id"`
+	StoreID     string              `json:"storeId" db:"store_id"`       // Tienda donde se reserva
+	CustomerID  string              `json:"customerId" db:"customer_id"` // Cliente que reserva
+	Quantity    int                 `json:"quantity" db:"quantity"`
+	Status      ReservationStatus   `json:"status" db:"status"`
+	Priority    ReservationPriority `json:"priority" db:"priority"`
+	Channel     SalesChannel        `json:"channel,omitempty" db:"channel"` // Canal de venta que la originó
+	ExpiresAt   time.Time           `json:"expiresAt" db:"expires_at"`
+	ConfirmedAt *time.Time          `json:"confirmedAt,omitempty" db:"confirmed_at"`
+	CreatedAt   time.Time           `json:"createdAt" db:"created_at"`
+	UpdatedAt   *time.Time          `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+// HoldsStock indica si el estado retiene stock reservado (PENDING o READY_FOR_PICKUP)
+func (s ReservationStatus) HoldsStock() bool {
+	return s == ReservationStatusPending || s == ReservationStatusReadyForPickup
+}
+
+// IsExpired verifica si la reserva ha expirado. Una reserva lista para recoger expira al
+// terminar su plazo de recogida.
 func (r *Reservation) IsExpired() bool {
-	return time.Now().After(r.ExpiresAt) && r.Status == ReservationStatusPending
+	return time.Now().After(r.ExpiresAt) && r.Status.HoldsStock()
 }
 
 // CanConfirm verifica si la reserva puede ser confirmada
@@ -42,7 +101,7 @@ func (r *Reservation) CanCancel() bool {
 
 // TimeRemaining retorna el tiempo restante antes de expirar
 func (r *Reservation) TimeRemaining() time.Duration {
-	if r.Status != ReservationStatusPending {
+	if !r.Status.HoldsStock() {
 		return 0
 	}
 	remaining := time.Until(r.ExpiresAt)
@@ -71,3 +130,196 @@ func (r *Reservation) Validate() error {
 	}
 	return nil
 }
+
+// ReservationTTLBounds define el TTL por defecto y el máximo permitido (en minutos)
+type ReservationTTLBounds struct {
+	DefaultMinutes int `json:"default_minutes"`
+	MaxMinutes     int `json:"max_minutes"`
+}
+
+// ReservationTTLPolicy define los TTL de reservas a nivel global y por tienda.
+// Los valores en cero de un override se heredan de la configuración global.
+type ReservationTTLPolicy struct {
+	ReservationTTLBounds
+	StoreOverrides map[string]ReservationTTLBounds `json:"store_overrides,omitempty"`
+}
+
+// DefaultReservationTTLPolicy retorna la política por defecto (10 minutos, máximo 24 horas)
+func DefaultReservationTTLPolicy() ReservationTTLPolicy {
+	return ReservationTTLPolicy{
+		ReservationTTLBounds: ReservationTTLBounds{
+			DefaultMinutes: 10,
+			MaxMinutes:     1440,
+		},
+	}
+}
+
+// BoundsFor obtiene los límites efectivos para una tienda
+func (p ReservationTTLPolicy) BoundsFor(storeID string) ReservationTTLBounds {
+	bounds := p.ReservationTTLBounds
+	if override, ok := p.StoreOverrides[storeID]; ok {
+		if override.DefaultMinutes > 0 {
+			bounds.DefaultMinutes = override.DefaultMinutes
+		}
+		if override.MaxMinutes > 0 {
+			bounds.MaxMinutes = override.MaxMinutes
+		}
+	}
+	return bounds
+}
+
+// Resolve obtiene el TTL a aplicar: el solicitado (0 = usar el por defecto de la tienda),
+// validando que no supere el máximo
+func (p ReservationTTLPolicy) Resolve(storeID string, requestedMinutes int) (int, error) {
+	bounds := p.BoundsFor(storeID)
+
+	if requestedMinutes < 0 {
+		return 0, &ValidationError{Field: "ttlMinutes", Message: "TTL must be positive"}
+	}
+	if requestedMinutes == 0 {
+		if bounds.DefaultMinutes <= 0 {
+			return 0, &ValidationError{Field: "ttlMinutes", Message: "TTL is required (no default TTL configured)"}
+		}
+		requestedMinutes = bounds.DefaultMinutes
+	}
+	if bounds.MaxMinutes > 0 && requestedMinutes > bounds.MaxMinutes {
+		return 0, &ValidationError{
+			Field:   "ttlMinutes",
+			Message: fmt.Sprintf("TTL cannot exceed %d minutes", bounds.MaxMinutes),
+		}
+	}
+
+	return requestedMinutes, nil
+}
+
+// CustomerHoldLimits limita lo que un cliente puede retener en reservas pendientes
+// (anti-acaparamiento en lanzamientos limitados). Cero = sin límite.
+type CustomerHoldLimits struct {
+	MaxUnitsPerProduct     int `json:"max_units_per_product"`    // Unidades de un producto en reservas pendientes, sumando todas las tiendas
+	MaxPendingReservations int `json:"max_pending_reservations"` // Reservas pendientes simultáneas
+}
+
+// Enabled indica si hay algún límite configurado
+func (l CustomerHoldLimits) Enabled() bool {
+	return l.MaxUnitsPerProduct > 0 || l.MaxPendingReservations > 0
+}
+
+// CustomerHolds lo que un cliente retiene actualmente en reservas pendientes no expiradas
+type CustomerHolds struct {
+	PendingReservations int // Reservas pendientes del cliente (todos los productos)
+	ProductUnits        int // Unidades del producto en reservas pendientes (todas las tiendas)
+}
+
+// Check verifica que una nueva reserva de quantity unidades no supere los límites
+func (l CustomerHoldLimits) Check(customerID, productID string, holds CustomerHolds, quantity int) error {
+	if l.MaxPendingReservations > 0 && holds.PendingReservations+1 > l.MaxPendingReservations {
+		return &CustomerLimitError{
+			CustomerID: customerID,
+			Limit:      CustomerLimitPendingReservations,
+			Max:        l.MaxPendingReservations,
+			Current:    holds.PendingReservations,
+			Requested:  1,
+		}
+	}
+	if l.MaxUnitsPerProduct > 0 && holds.ProductUnits+quantity > l.MaxUnitsPerProduct {
+		return &CustomerLimitError{
+			CustomerID: customerID,
+			ProductID:  productID,
+			Limit:      CustomerLimitProductUnits,
+			Max:        l.MaxUnitsPerProduct,
+			Current:    holds.ProductUnits,
+			Requested:  quantity,
+		}
+	}
+	return nil
+}
+
+// ReservationStats representa las estadísticas agregadas de reservas
+type ReservationStats struct {
+	TotalReservations     int                    `json:"total_reservations"`
+	PendingReservations   int                    `json:"pending_reservations"`
+	ConfirmedReservations int                    `json:"confirmed_reservations"`
+	CancelledReservations int                    `json:"cancelled_reservations"`
+	ExpiredReservations   int                    `json:"expired_reservations"`
+	ByStatus              map[string]int         `json:"by_status"`
+	ByStore               map[string]int         `json:"by_store"`
+	ByGroup               map[string]int         `json:"by_group,omitempty"` // Por grupo de tiendas (una tienda cuenta en cada uno de sus grupos)
+	GroupID               string                 `json:"group_id,omitempty"`
+	Window                ReservationWindowStats `json:"window"`
+}
+
+// ReservationWindowStats representa métricas calculadas sobre las reservas creadas en una ventana de tiempo
+type ReservationWindowStats struct {
+	Duration                string    `json:"duration"`
+	Since                   time.Time `json:"since"`
+	Created                 int       `json:"created"`
+	Confirmed               int       `json:"confirmed"` // Confirmadas y recogidas en tienda
+	Expired                 int       `json:"expired"`
+	AvgTimeToConfirmSeconds float64   `json:"avg_time_to_confirm_seconds"`
+	ExpirationRate          float64   `json:"expiration_rate"` // expired / created
+	ConversionRate          float64   `json:"conversion_rate"` // confirmed / created
+}
+
+// Campos por los que se pueden ordenar los listados de reservas
+const (
+	ReservationSortCreatedAt = "created_at"
+	ReservationSortExpiresAt = "expires_at"
+)
+
+// Límites de paginación de los listados de reservas
+const (
+	DefaultReservationListLimit = 50
+	MaxReservationListLimit     = 500
+)
+
+// ReservationFilter define los filtros, el orden y la paginación de un listado de reservas.
+// Los campos vacíos no filtran; Limit <= 0 significa sin límite (uso interno).
+type ReservationFilter struct {
+	ProductID   string
+	StoreID     string
+	CustomerID  string
+	Status      *ReservationStatus
+	CreatedFrom *time.Time // Inclusivo
+	CreatedTo   *time.Time // Exclusivo
+	SortBy      string     // created_at | expires_at
+	SortDesc    bool
+	Limit       int
+	Offset      int
+}
+
+// IsValid verifica que el estado sea uno de los conocidos
+func (s ReservationStatus) IsValid() bool {
+	switch s {
+	case ReservationStatusPending, ReservationStatusConfirmed, ReservationStatusCancelled, ReservationStatusExpired,
+		ReservationStatusPreorder, ReservationStatusReadyForPickup, ReservationStatusPickedUp:
+		return true
+	}
+	return false
+}
+
+// Validate verifica el filtro y aplica los valores por defecto de paginación y orden
+func (f *ReservationFilter) Validate() error {
+	if f.Status != nil && !f.Status.IsValid() {
+		return &ValidationError{Field: "status", Message: fmt.Sprintf("unknown status %q", *f.Status)}
+	}
+	switch f.SortBy {
+	case "":
+		f.SortBy = ReservationSortCreatedAt
+	case ReservationSortCreatedAt, ReservationSortExpiresAt:
+	default:
+		return &ValidationError{Field: "sort", Message: "sort must be created_at or expires_at"}
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		return &ValidationError{Field: "created_from", Message: "created_from must be before created_to"}
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultReservationListLimit
+	}
+	if f.Limit > MaxReservationListLimit {
+		return &ValidationError{Field: "limit", Message: fmt.Sprintf("limit cannot exceed %d", MaxReservationListLimit)}
+	}
+	if f.Offset < 0 {
+		return &ValidationError{Field: "offset", Message: "offset must be zero or positive"}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// ExpirationReason motivo por el que una reserva pendiente se expira
+type ExpirationReason string
+
+const (
+	ExpirationReasonTTL      ExpirationReason = "TTL"                   // Superó su expiresAt
+	ExpirationReasonShortage ExpirationReason = "LOW_PRIORITY_SHORTAGE" // Reserva LOW liberada por falta de stock
+)
+
+// ReservationExpiration reserva expirada (o que se expiraría, con dry-run) en una pasada de expiración
+type ReservationExpiration struct {
+	ReservationID string              `json:"reservation_id"`
+	ProductID     string              `json:"product_id"`
+	StoreID       string              `json:"store_id"`
+	CustomerID    string              `json:"customer_id"`
+	Quantity      int                 `json:"quantity"`
+	Priority      ReservationPriority `json:"priority"`
+	ExpiresAt     time.Time           `json:"expires_at"`
+	Reason        ExpirationReason    `json:"reason"`
+	Error         string              `json:"error,omitempty"` // Motivo si no se pudo expirar
+}
+
+// NewReservationExpiration crea el resultado de expirar una reserva por reason
+func NewReservationExpiration(reservation *Reservation, reason ExpirationReason) *ReservationExpiration {
+	return &ReservationExpiration{
+		ReservationID: reservation.ID,
+		ProductID:     reservation.ProductID,
+		StoreID:       reservation.StoreID,
+		CustomerID:    reservation.CustomerID,
+		Quantity:      reservation.Quantity,
+		Priority:      reservation.Priority,
+		ExpiresAt:     reservation.ExpiresAt,
+		Reason:        reason,
+	}
+}
+
+// ReservationExpirationRun resumen de una pasada de expiración (la del worker o una manual)
+type ReservationExpirationRun struct {
+	DryRun       bool                     `json:"dry_run"`
+	RanAt        time.Time                `json:"ran_at"`
+	Expired      int                      `json:"expired"` // Con dry-run: reservas que se expirarían
+	Failed       int                      `json:"failed"`
+	Reservations []*ReservationExpiration `json:"reservations"`
+}
+
+// Add registra el resultado de una reserva y actualiza los contadores
+func (r *ReservationExpirationRun) Add(result *ReservationExpiration) {
+	if result.Error != "" {
+		r.Failed++
+	} else {
+		r.Expired++
+	}
+	r.Reservations = append(r.Reservations, result)
+}
@@ -0,0 +1,105 @@
+package domain
+
+import "time"
+
+// MaxReservationGroupSize máximo de reservas hijas por grupo
+const MaxReservationGroupSize = 20
+
+// ReservationGroupStatus estado agregado de un grupo de reservas
+type ReservationGroupStatus string
+
+const (
+	ReservationGroupPending   ReservationGroupStatus = "PENDING"   // Todas las hijas pendientes
+	ReservationGroupConfirmed ReservationGroupStatus = "CONFIRMED" // Todas confirmadas
+	ReservationGroupCancelled ReservationGroupStatus = "CANCELLED" // Todas canceladas
+	ReservationGroupExpired   ReservationGroupStatus = "EXPIRED"   // Todas expiradas
+	ReservationGroupPartial   ReservationGroupStatus = "PARTIAL"   // Hijas en estados distintos (p. ej. fallo parcial al confirmar)
+)
+
+// ReservationGroup agrupa reservas de un mismo cliente en distintas tiendas (envío partido)
+// que se confirman o cancelan juntas
+type ReservationGroup struct {
+	ID           string                 `json:"id"`
+	CustomerID   string                 `json:"customerId"`
+	Status       ReservationGroupStatus `json:"status"`
+	Reservations []*Reservation         `json:"reservations"`
+	CreatedAt    time.Time              `json:"createdAt"`
+}
+
+// StoreIDs retorna las tiendas de las reservas hijas, sin repetir
+func (g *ReservationGroup) StoreIDs() []string {
+	var stores []string
+	seen := make(map[string]bool)
+	for _, reservation := range g.Reservations {
+		if !seen[reservation.StoreID] {
+			seen[reservation.StoreID] = true
+			stores = append(stores, reservation.StoreID)
+		}
+	}
+	return stores
+}
+
+// Refresh recalcula el estado agregado a partir de las reservas hijas
+func (g *ReservationGroup) Refresh() {
+	g.Status = ReservationGroupPending
+	for i, reservation := range g.Reservations {
+		status := ReservationGroupStatus(reservation.Status)
+		if i == 0 {
+			g.Status = status
+		} else if status != g.Status {
+			g.Status = ReservationGroupPartial
+			return
+		}
+	}
+}
+
+// ReservationGroupItem reserva hija a crear dentro de un grupo
+type ReservationGroupItem struct {
+	ProductID string
+	StoreID   string
+	Quantity  int
+}
+
+// ReservationGroupOutcome resultado de aplicar una acción del grupo a una reserva hija
+type ReservationGroupOutcome string
+
+const (
+	ReservationGroupSucceeded ReservationGroupOutcome = "SUCCEEDED" // La acción se aplicó
+	ReservationGroupSkipped   ReservationGroupOutcome = "SKIPPED"   // La hija ya estaba en el estado destino
+	ReservationGroupFailed    ReservationGroupOutcome = "FAILED"    // Rechazada (ver Error); la hija conserva su estado
+)
+
+// ReservationGroupResult resultado de la acción en una reserva hija
+type ReservationGroupResult struct {
+	ReservationID string                  `json:"reservationId"`
+	StoreID       string                  `json:"storeId"`
+	Outcome       ReservationGroupOutcome `json:"outcome"`
+	Status        ReservationStatus       `json:"status"` // Estado de la hija tras la acción
+	Error         string                  `json:"error,omitempty"`
+	Err           error                   `json:"-"` // Error original de las FAILED (código HTTP del resultado)
+}
+
+// ReservationGroupActionReport resumen de confirmar o cancelar un grupo: la acción se aplica en
+// cada tienda por separado, así que un fallo en una hija no revierte las demás
+type ReservationGroupActionReport struct {
+	GroupID   string                    `json:"groupId"`
+	Action    ReservationAction         `json:"action"`
+	Status    ReservationGroupStatus    `json:"status"` // Estado agregado del grupo tras la acción
+	Succeeded int                       `json:"succeeded"`
+	Skipped   int                       `json:"skipped"`
+	Failed    int                       `json:"failed"`
+	Results   []*ReservationGroupResult `json:"results"`
+}
+
+// Add registra el resultado de una hija y actualiza los contadores
+func (r *ReservationGroupActionReport) Add(result *ReservationGroupResult) {
+	switch result.Outcome {
+	case ReservationGroupSucceeded:
+		r.Succeeded++
+	case ReservationGroupSkipped:
+		r.Skipped++
+	case ReservationGroupFailed:
+		r.Failed++
+	}
+	r.Results = append(r.Results, result)
+}
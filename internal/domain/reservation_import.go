@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxReservationImportBatch máximo de reservas por petición de importación
+const MaxReservationImportBatch = 500
+
+// ReservationImportOutcome resultado de importar una reserva externa
+type ReservationImportOutcome string
+
+const (
+	ReservationImportImported ReservationImportOutcome = "IMPORTED" // Reserva creada
+	ReservationImportValid    ReservationImportOutcome = "VALID"    // dry-run: se importaría
+	ReservationImportSkipped  ReservationImportOutcome = "SKIPPED"  // El external_id ya se importó antes
+	ReservationImportFailed   ReservationImportOutcome = "FAILED"   // Rechazada (ver Error)
+)
+
+// ReservationImport representa una reserva existente en un OMS externo que se migra al sistema
+// conservando su estado y sus fechas originales (sin aplicar el TTL por defecto)
+type ReservationImport struct {
+	ExternalID  string
+	ProductID   string
+	StoreID     string
+	CustomerID  string
+	Quantity    int
+	Status      ReservationStatus
+	Priority    ReservationPriority
+	Channel     SalesChannel
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	ConfirmedAt *time.Time
+}
+
+// Validate normaliza el estado, la prioridad y el canal y verifica los datos de la reserva externa.
+// Las reservas PENDING deben seguir vigentes: una retención ya expirada no se importa.
+func (i *ReservationImport) Validate(now time.Time) error {
+	if strings.TrimSpace(i.ExternalID) == "" {
+		return &ValidationError{Field: "external_id", Message: "external_id is required"}
+	}
+	if i.ProductID == "" || i.StoreID == "" {
+		return &ValidationError{Field: "product_id", Message: "product_id and store_id are required"}
+	}
+	if i.CustomerID == "" {
+		return &ValidationError{Field: "customer_id", Message: "customer_id is required"}
+	}
+	if i.Quantity <= 0 {
+		return &ValidationError{Field: "quantity", Message: "quantity must be positive"}
+	}
+
+	i.Status = ReservationStatus(strings.ToUpper(strings.TrimSpace(string(i.Status))))
+	switch i.Status {
+	case ReservationStatusPending, ReservationStatusConfirmed, ReservationStatusCancelled, ReservationStatusExpired:
+	default:
+		return &ValidationError{Field: "status", Message: "status must be PENDING, CONFIRMED, CANCELLED or EXPIRED"}
+	}
+
+	priority, err := ParseReservationPriority(string(i.Priority))
+	if err != nil {
+		return err
+	}
+	i.Priority = priority
+
+	if i.Channel != "" {
+		channel, err := ParseSalesChannel(string(i.Channel))
+		if err != nil {
+			return err
+		}
+		i.Channel = channel
+	}
+
+	if i.CreatedAt.IsZero() {
+		return &ValidationError{Field: "created_at", Message: "created_at is required"}
+	}
+	if i.CreatedAt.After(now) {
+		return &ValidationError{Field: "created_at", Message: "created_at cannot be in the future"}
+	}
+
+	if i.Status == ReservationStatusPending {
+		if i.ExpiresAt.IsZero() {
+			return &ValidationError{Field: "expires_at", Message: "expires_at is required for PENDING reservations"}
+		}
+		if !i.ExpiresAt.After(now) {
+			return &ValidationError{Field: "expires_at", Message: "PENDING reservation has already expired"}
+		}
+	} else if i.ExpiresAt.IsZero() {
+		i.ExpiresAt = i.CreatedAt
+	}
+	if i.ExpiresAt.Before(i.CreatedAt) {
+		return &ValidationError{Field: "expires_at", Message: "expires_at must be after created_at"}
+	}
+
+	if i.Status == ReservationStatusConfirmed && i.ConfirmedAt == nil {
+		i.ConfirmedAt = &i.CreatedAt
+	}
+	if i.Status != ReservationStatusConfirmed {
+		i.ConfirmedAt = nil
+	}
+
+	return nil
+}
+
+// Reservation construye la reserva a persistir con el ID indicado
+func (i *ReservationImport) Reservation(id string) *Reservation {
+	return &Reservation{
+		ID:          id,
+		ProductID:   i.ProductID,
+		StoreID:     i.StoreID,
+		CustomerID:  i.CustomerID,
+		Quantity:    i.Quantity,
+		Status:      i.Status,
+		Priority:    i.Priority,
+		Channel:     i.Channel,
+		ExpiresAt:   i.ExpiresAt,
+		ConfirmedAt: i.ConfirmedAt,
+		CreatedAt:   i.CreatedAt,
+	}
+}
+
+// ReservationImportResult resultado de una reserva externa dentro de la importación
+type ReservationImportResult struct {
+	ExternalID    string                   `json:"external_id"`
+	ReservationID string                   `json:"reservation_id,omitempty"`
+	Outcome       ReservationImportOutcome `json:"outcome"`
+	Error         string                   `json:"error,omitempty"`
+	Err           error                    `json:"-"` // Error original de las FAILED (código HTTP del resultado)
+}
+
+// ReservationImportReport resumen de una importación (o de su validación con dry-run)
+type ReservationImportReport struct {
+	DryRun   bool                       `json:"dry_run"`
+	Imported int                        `json:"imported"` // Con dry-run: reservas que se importarían
+	Skipped  int                        `json:"skipped"`
+	Failed   int                        `json:"failed"`
+	Results  []*ReservationImportResult `json:"results"`
+}
+
+// Add registra el resultado de una reserva y actualiza los contadores
+func (r *ReservationImportReport) Add(result *ReservationImportResult) {
+	switch result.Outcome {
+	case ReservationImportImported, ReservationImportValid:
+		r.Imported++
+	case ReservationImportSkipped:
+		r.Skipped++
+	case ReservationImportFailed:
+		r.Failed++
+	}
+	r.Results = append(r.Results, result)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// ReservationIntentStatus estado de una intención de reserva
+type ReservationIntentStatus string
+
+const (
+	ReservationIntentActive    ReservationIntentStatus = "ACTIVE"    // Convertible hasta expires_at
+	ReservationIntentConverted ReservationIntentStatus = "CONVERTED" // Ya generó una reserva (reservation_id)
+	ReservationIntentExpired   ReservationIntentStatus = "EXPIRED"   // Caducada sin convertir
+)
+
+// ReservationIntent reserva "blanda": registra el interés de un cliente (p. ej. al añadir al
+// carrito) y la disponibilidad en ese momento, sin incrementar Reserved. El token se puede
+// convertir en una reserva real mientras no caduque; la conversión vuelve a comprobar el stock.
+type ReservationIntent struct {
+	Token             string                  `json:"token"`
+	ProductID         string                  `json:"product_id"`
+	StoreID           string                  `json:"store_id"`
+	CustomerID        string                  `json:"customer_id"`
+	Quantity          int                     `json:"quantity"`
+	Available         bool                    `json:"available"`          // Había disponibilidad suficiente al registrarla
+	AvailableQuantity int                     `json:"available_quantity"` // Disponibilidad en la tienda al registrarla
+	Status            ReservationIntentStatus `json:"status"`
+	ReservationID     string                  `json:"reservation_id,omitempty"`
+	ExpiresAt         time.Time               `json:"expires_at"`
+	ConvertedAt       *time.Time              `json:"converted_at,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+}
+
+// Validate verifica los datos de la intención
+func (i *ReservationIntent) Validate() error {
+	if i.ProductID == "" {
+		return &ValidationError{Field: "product_id", Message: "product_id is required"}
+	}
+	if i.StoreID == "" {
+		return &ValidationError{Field: "store_id", Message: "store_id is required"}
+	}
+	if i.CustomerID == "" {
+		return &ValidationError{Field: "customer_id", Message: "customer_id is required"}
+	}
+	if i.Quantity <= 0 {
+		return &ValidationError{Field: "quantity", Message: "quantity must be positive"}
+	}
+	return nil
+}
+
+// Refresh calcula Status en el instante indicado
+func (i *ReservationIntent) Refresh(now time.Time) {
+	switch {
+	case i.ConvertedAt != nil:
+		i.Status = ReservationIntentConverted
+	case !now.Before(i.ExpiresAt):
+		i.Status = ReservationIntentExpired
+	default:
+		i.Status = ReservationIntentActive
+	}
+}
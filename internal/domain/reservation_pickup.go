@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Recogida en tienda (click-and-collect)
+const (
+	PickupCodeDigits      = 6              // Dígitos del código que presenta el cliente
+	MaxPickupCodeAttempts = 5              // Códigos erróneos antes de bloquear la recogida
+	DefaultPickupWindow   = 48 * time.Hour // Plazo de recogida por defecto
+)
+
+// ReservationPickup recogida en tienda de una reserva marcada como lista para recoger. El código
+// solo se guarda como hash: se devuelve en claro una única vez, al marcar la reserva.
+type ReservationPickup struct {
+	ReservationID string     `json:"reservation_id"`
+	StoreID       string     `json:"store_id"`
+	Code          string     `json:"code,omitempty"` // Solo en la respuesta de ready-for-pickup
+	CodeHash      string     `json:"-"`
+	Attempts      int        `json:"attempts"` // Códigos erróneos presentados
+	ReadyAt       time.Time  `json:"ready_at"`
+	PickupBy      time.Time  `json:"pickup_by"` // Pasado el plazo la reserva caduca y libera el stock
+	PickedUpAt    *time.Time `json:"picked_up_at,omitempty"`
+}
+
+// NewReservationPickup genera el código de recogida de la reserva con el plazo window desde now
+func NewReservationPickup(reservation *Reservation, now time.Time, window time.Duration) (*ReservationPickup, error) {
+	max := big.NewInt(1)
+	for i := 0; i < PickupCodeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate pickup code: %w", err)
+	}
+	code := fmt.Sprintf("%0*d", PickupCodeDigits, n)
+
+	return &ReservationPickup{
+		ReservationID: reservation.ID,
+		StoreID:       reservation.StoreID,
+		Code:          code,
+		CodeHash:      HashPickupCode(reservation.ID, code),
+		ReadyAt:       now,
+		PickupBy:      now.Add(window),
+	}, nil
+}
+
+// HashPickupCode calcula el hash SHA-256 (hex) del código, salado con el ID de la reserva
+func HashPickupCode(reservationID, code string) string {
+	sum := sha256.Sum256([]byte(reservationID + ":" + strings.TrimSpace(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Locked indica si se agotaron los intentos: la recogida ya no se puede validar con código
+func (p *ReservationPickup) Locked() bool {
+	return p.Attempts >= MaxPickupCodeAttempts
+}
+
+// Matches compara en tiempo constante el código presentado con el de la recogida
+func (p *ReservationPickup) Matches(code string) bool {
+	hash := HashPickupCode(p.ReservationID, code)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(p.CodeHash)) == 1
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ReservationAction acción del ciclo de vida de una reserva
+type ReservationAction string
+
+const (
+	ReservationActionConfirm  ReservationAction = "confirm"
+	ReservationActionCancel   ReservationAction = "cancel"
+	ReservationActionExpire   ReservationAction = "expire"
+	ReservationActionAllocate ReservationAction = "allocate" // Asigna stock real a una preventa
+
+	ReservationActionReadyForPickup ReservationAction = "ready_for_pickup" // La tienda tiene el pedido preparado
+	ReservationActionPickUp         ReservationAction = "pick_up"          // El cliente recoge con su código
+)
+
+// reservationActionTargets estado al que lleva cada acción
+var reservationActionTargets = map[ReservationAction]ReservationStatus{
+	ReservationActionConfirm:  ReservationStatusConfirmed,
+	ReservationActionCancel:   ReservationStatusCancelled,
+	ReservationActionExpire:   ReservationStatusExpired,
+	ReservationActionAllocate: ReservationStatusPending,
+
+	ReservationActionReadyForPickup: ReservationStatusReadyForPickup,
+	ReservationActionPickUp:         ReservationStatusPickedUp,
+}
+
+// Target retorna el estado al que lleva la acción ("" si la acción no existe)
+func (a ReservationAction) Target() ReservationStatus {
+	return reservationActionTargets[a]
+}
+
+// reservationStatusTransitions define las transiciones permitidas entre estados.
+// CONFIRMED, CANCELLED, EXPIRED y PICKED_UP son finales. Una preventa (PREORDER) no caduca ni se
+// puede confirmar: pasa a PENDING cuando se le asigna stock recibido. Una reserva lista para
+// recoger (READY_FOR_PICKUP) sigue reteniendo el stock hasta que el cliente la recoge o vence su
+// plazo de recogida.
+var reservationStatusTransitions = map[ReservationStatus][]ReservationStatus{
+	ReservationStatusPending:        {ReservationStatusConfirmed, ReservationStatusCancelled, ReservationStatusExpired, ReservationStatusReadyForPickup},
+	ReservationStatusPreorder:       {ReservationStatusPending, ReservationStatusCancelled},
+	ReservationStatusReadyForPickup: {ReservationStatusPickedUp, ReservationStatusCancelled, ReservationStatusExpired},
+}
+
+// CanTransitionTo indica si la reserva puede pasar del estado actual a next
+func (s ReservationStatus) CanTransitionTo(next ReservationStatus) bool {
+	for _, allowed := range reservationStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal indica si el estado no admite más transiciones
+func (s ReservationStatus) IsFinal() bool {
+	return len(reservationStatusTransitions[s]) == 0
+}
+
+// ReservationTransition transición de una reserva validada por ReservationStateMachine
+type ReservationTransition struct {
+	Reservation *Reservation
+	Action      ReservationAction
+	From        ReservationStatus
+	To          ReservationStatus
+	At          time.Time
+	Product     *Product // Solo confirm y pick_up: producto con el precio vigente para el evento de venta
+	ReferenceID string   // Solo confirm: referencia de la venta (ticket o pedido)
+}
+
+// ReservationStockEffect efecto de una transición sobre el stock de la reserva
+type ReservationStockEffect int
+
+const (
+	ReservationStockNone    ReservationStockEffect = iota
+	ReservationStockConfirm                        // Venta: descuenta quantity y reserved
+	ReservationStockRelease                        // Devuelve las unidades reservadas
+)
+
+// StockEffect indica qué hacer con el stock de la reserva al persistir la transición. Una
+// preventa sin asignar no tiene stock reservado.
+func (t *ReservationTransition) StockEffect() ReservationStockEffect {
+	if t.From == ReservationStatusPreorder {
+		return ReservationStockNone
+	}
+	switch t.To {
+	case ReservationStatusConfirmed, ReservationStatusPickedUp:
+		return ReservationStockConfirm
+	case ReservationStatusCancelled, ReservationStatusExpired:
+		return ReservationStockRelease
+	}
+	return ReservationStockNone
+}
+
+// ReservationTransitionHook se ejecuta cuando una transición ya se ha persistido
+// (p. ej. para emitir el evento reservation.*)
+type ReservationTransitionHook func(ctx context.Context, transition *ReservationTransition)
+
+// ReservationStateMachine valida las transiciones de estado de las reservas y ejecuta los
+// hooks registrados para el estado destino
+type ReservationStateMachine struct {
+	hooks map[ReservationStatus][]ReservationTransitionHook
+}
+
+// NewReservationStateMachine crea una máquina de estados sin hooks
+func NewReservationStateMachine() *ReservationStateMachine {
+	return &ReservationStateMachine{hooks: make(map[ReservationStatus][]ReservationTransitionHook)}
+}
+
+// OnTransition registra un hook para las transiciones que llevan a status
+func (m *ReservationStateMachine) OnTransition(status ReservationStatus, hook ReservationTransitionHook) {
+	m.hooks[status] = append(m.hooks[status], hook)
+}
+
+// Begin valida action sobre la reserva y retorna la transición, que se aplica con Complete una
+// vez persistida. Retorna InvalidStateError si el estado actual no la permite.
+func (m *ReservationStateMachine) Begin(reservation *Reservation, action ReservationAction) (*ReservationTransition, error) {
+	to, ok := reservationActionTargets[action]
+	if !ok || !reservation.Status.CanTransitionTo(to) {
+		return nil, &InvalidStateError{
+			CurrentState:    string(reservation.Status),
+			AttemptedAction: string(action) + " reservation",
+		}
+	}
+
+	return &ReservationTransition{
+		Reservation: reservation,
+		Action:      action,
+		From:        reservation.Status,
+		To:          to,
+		At:          time.Now(),
+	}, nil
+}
+
+// Complete aplica la transición a la reserva y ejecuta sus hooks
+func (m *ReservationStateMachine) Complete(ctx context.Context, transition *ReservationTransition) {
+	reservation := transition.Reservation
+	reservation.Status = transition.To
+	if transition.To == ReservationStatusConfirmed || transition.To == ReservationStatusPickedUp {
+		reservation.ConfirmedAt = &transition.At
+	}
+
+	for _, hook := range m.hooks[transition.To] {
+		hook(ctx, transition)
+	}
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// ReservedDiscrepancy fila de stock cuyo reserved no coincide con la suma de sus reservas PENDING
+// y sus retenciones internas
+type ReservedDiscrepancy struct {
+	ProductID  string `json:"product_id"`
+	StoreID    string `json:"store_id"`
+	Reserved   int    `json:"reserved"`   // Valor guardado en stock.reserved
+	Expected   int    `json:"expected"`   // Suma de las reservas PENDING más las retenciones internas (held)
+	Difference int    `json:"difference"` // Reserved - Expected (positivo = unidades bloqueadas de más)
+	Corrected  bool   `json:"corrected"`
+}
+
+// ReservedReconciliation resultado de comparar stock.reserved con las reservas abiertas.
+// Sin Applied es solo un informe; con Applied las filas corregidas llevan Corrected.
+type ReservedReconciliation struct {
+	StoreID       string                 `json:"store_id,omitempty"` // Vacío = todas las tiendas
+	Checked       int                    `json:"checked"`            // Filas de stock revisadas
+	Discrepancies []*ReservedDiscrepancy `json:"discrepancies"`
+	Applied       bool                   `json:"applied"`
+	Corrected     int                    `json:"corrected"`
+	ReconciledAt  time.Time              `json:"reconciled_at"`
+}
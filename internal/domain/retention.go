@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetentionTable tabla con datos históricos sujetos a retención
+type RetentionTable string
+
+const (
+	RetentionEvents       RetentionTable = "events"       // Eventos ya publicados (log de auditoría)
+	RetentionReservations RetentionTable = "reservations" // Reservas CONFIRMED, CANCELLED o EXPIRED
+)
+
+// RetentionTables tablas gestionadas por el worker de retención, en el orden en que se procesan
+var RetentionTables = []RetentionTable{RetentionEvents, RetentionReservations}
+
+// RetentionPolicy ventana de retención de una tabla: se borran las filas más antiguas que MaxAge
+type RetentionPolicy struct {
+	Table  RetentionTable
+	MaxAge time.Duration
+}
+
+// RetentionTableStats filas recuperadas por la retención de una tabla desde que arrancó el proceso
+type RetentionTableStats struct {
+	Table     RetentionTable `json:"table"`
+	MaxAge    time.Duration  `json:"max_age"`
+	Deleted   int64          `json:"deleted"`
+	Archived  int64          `json:"archived"`
+	Files     int64          `json:"files"`
+	LastRunAt *time.Time     `json:"last_run_at,omitempty"`
+}
+
+// AuditCheckpoint último eslabón borrado de la cadena de auditoría. La verificación empieza
+// en Seq+1 enlazando con Hash, de modo que la retención no se reporta como un hueco.
+type AuditCheckpoint struct {
+	Seq       int64     `json:"seq"`
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CleanupRequest limpieza bajo demanda de una tabla con las reglas de la retención, por lotes
+type CleanupRequest struct {
+	Table      RetentionTable
+	OlderThan  time.Time
+	BatchSize  int  // Filas por lote
+	MaxBatches int  // 0 = hasta terminar
+	DryRun     bool // Solo cuenta las filas que se borrarían
+}
+
+// Validate verifica la petición contra el tamaño de lote máximo configurado
+func (r CleanupRequest) Validate(maxBatchSize int) error {
+	switch r.Table {
+	case RetentionEvents, RetentionReservations:
+	default:
+		return &ValidationError{Field: "table", Message: fmt.Sprintf("unknown table %q (options: events, reservations)", r.Table)}
+	}
+	if r.OlderThan.IsZero() || !r.OlderThan.Before(time.Now()) {
+		return &ValidationError{Field: "older_than_days", Message: "older_than_days must be positive"}
+	}
+	if r.BatchSize <= 0 || r.BatchSize > maxBatchSize {
+		return &ValidationError{Field: "batch_size", Message: fmt.Sprintf("batch_size must be between 1 and %d", maxBatchSize)}
+	}
+	if r.MaxBatches < 0 {
+		return &ValidationError{Field: "max_batches", Message: "max_batches must be zero or positive"}
+	}
+	return nil
+}
+
+// CleanupResult resultado de una limpieza bajo demanda
+type CleanupResult struct {
+	Table     RetentionTable `json:"table"`
+	OlderThan time.Time      `json:"older_than"`
+	DryRun    bool           `json:"dry_run"`
+	Matched   int64          `json:"matched"`   // Filas que cumplían el criterio al empezar
+	Deleted   int64          `json:"deleted"`   // 0 en dry run
+	Remaining int64          `json:"remaining"` // Pendientes al parar por max_batches o por cancelación
+	Batches   int            `json:"batches"`
+	BatchSize int            `json:"batch_size"`
+	Duration  string         `json:"duration"`
+}
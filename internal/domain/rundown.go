@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+// RunDownStatus representa el estado de un plan de descatalogación
+type RunDownStatus string
+
+const (
+	RunDownStatusInProgress RunDownStatus = "IN_PROGRESS" // Quedan tiendas con stock o reservas
+	RunDownStatusCompleted  RunDownStatus = "COMPLETED"   // Todas las entradas de surtido archivadas
+)
+
+// RunDownEntryStatus representa el estado de una entrada de surtido (producto/tienda) en el plan
+type RunDownEntryStatus string
+
+const (
+	RunDownEntryRunningDown RunDownEntryStatus = "RUNNING_DOWN" // Se sigue vendiendo hasta agotar el stock
+	RunDownEntryArchived    RunDownEntryStatus = "ARCHIVED"     // Stock agotado, entrada retirada del surtido
+)
+
+// ProductRunDown representa el plan de run-down de un producto descatalogado:
+// no se repone stock, las reservas siguen permitidas y cada tienda se archiva al agotarse.
+type ProductRunDown struct {
+	ProductID   string              `json:"product_id"`
+	Status      RunDownStatus       `json:"status"`
+	Reason      string              `json:"reason,omitempty"`
+	Stores      []RunDownStoreEntry `json:"stores"`
+	Progress    RunDownProgress     `json:"progress"`
+	CreatedAt   time.Time           `json:"created_at"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
+}
+
+// RunDownStoreEntry representa el avance del run-down en una tienda
+type RunDownStoreEntry struct {
+	StoreID         string             `json:"store_id"`
+	InitialQuantity int                `json:"initial_quantity"` // Stock al descatalogar
+	Quantity        int                `json:"quantity"`         // Stock actual (0 si está archivada)
+	Reserved        int                `json:"reserved"`
+	Status          RunDownEntryStatus `json:"status"`
+	ArchivedAt      *time.Time         `json:"archived_at,omitempty"`
+}
+
+// RunDownProgress resume el avance del plan
+type RunDownProgress struct {
+	Stores         int     `json:"stores"`
+	ArchivedStores int     `json:"archived_stores"`
+	InitialUnits   int     `json:"initial_units"`
+	RemainingUnits int     `json:"remaining_units"`
+	Percent        float64 `json:"percent"` // Unidades agotadas sobre las iniciales (100 si no había stock)
+}
+
+// CalculateProgress recalcula el resumen a partir de las entradas por tienda
+func (r *ProductRunDown) CalculateProgress() {
+	progress := RunDownProgress{Stores: len(r.Stores)}
+	for _, entry := range r.Stores {
+		progress.InitialUnits += entry.InitialQuantity
+		progress.RemainingUnits += entry.Quantity
+		if entry.Status == RunDownEntryArchived {
+			progress.ArchivedStores++
+		}
+	}
+
+	progress.Percent = 100
+	if progress.InitialUnits > 0 {
+		sold := progress.InitialUnits - progress.RemainingUnits
+		if sold < 0 {
+			sold = 0
+		}
+		progress.Percent = float64(sold) / float64(progress.InitialUnits) * 100
+	}
+
+	r.Progress = progress
+}
+
+// AllArchived indica si todas las entradas de surtido del plan están archivadas
+func (r *ProductRunDown) AllArchived() bool {
+	for _, entry := range r.Stores {
+		if entry.Status != RunDownEntryArchived {
+			return false
+		}
+	}
+	return true
+}
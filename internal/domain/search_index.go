@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Backends de búsqueda (SEARCH_BACKEND)
+const (
+	SearchBackendNone          = "none"
+	SearchBackendElasticsearch = "elasticsearch"
+	SearchBackendMeilisearch   = "meilisearch"
+)
+
+// ProductSearchDocument copia de un producto en el índice de búsqueda, con flags de
+// disponibilidad para filtrar y ordenar resultados sin consultar la tabla stock
+type ProductSearchDocument struct {
+	ID              string        `json:"id"`
+	SKU             string        `json:"sku"`
+	Name            string        `json:"name"`
+	Description     string        `json:"description"`
+	Category        string        `json:"category"`
+	Price           float64       `json:"price"`
+	Status          ProductStatus `json:"status"`
+	InStock         bool          `json:"in_stock"`         // Alguna tienda tiene unidades vendibles
+	AvailableStores []string      `json:"available_stores"` // Tiendas con unidades vendibles
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// NewProductSearchDocument construye el documento del producto con la disponibilidad de sus filas de stock
+func NewProductSearchDocument(product *Product, stocks []*Stock) *ProductSearchDocument {
+	doc := &ProductSearchDocument{
+		ID:              product.ID,
+		SKU:             product.SKU,
+		Name:            product.Name,
+		Description:     product.Description,
+		Category:        product.Category,
+		Price:           product.Price,
+		Status:          product.Status,
+		AvailableStores: []string{},
+		UpdatedAt:       product.UpdatedAt,
+	}
+	for _, stock := range stocks {
+		if stock.Sellable() > 0 {
+			doc.AvailableStores = append(doc.AvailableStores, stock.StoreID)
+		}
+	}
+	doc.InStock = len(doc.AvailableStores) > 0
+	return doc
+}
+
+// SearchIndex índice externo de productos. La BD sigue siendo la fuente de verdad: el
+// índice se mantiene con los eventos y se puede reconstruir entero en cualquier momento.
+//
+// Implementaciones disponibles:
+//   - ElasticsearchIndex: API REST de Elasticsearch (o OpenSearch)
+//   - MeilisearchIndex: API REST de Meilisearch
+type SearchIndex interface {
+	// Upsert crea o reemplaza los documentos
+	Upsert(ctx context.Context, docs ...*ProductSearchDocument) error
+
+	// Delete elimina el documento del producto (sin error si no existe)
+	Delete(ctx context.Context, productID string) error
+
+	// Search retorna los IDs de los productos que coinciden con query, por relevancia
+	Search(ctx context.Context, query string, limit, offset int) ([]string, error)
+
+	// Close libera los recursos del cliente
+	Close() error
+}
+
+// SearchReindexResult resumen de una reconstrucción completa del índice
+type SearchReindexResult struct {
+	Backend   string    `json:"backend"`
+	Indexed   int       `json:"indexed"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// SerialRegistration vincula un número de serie vendido con su venta (para garantías)
+type SerialRegistration struct {
+	ID                string     `json:"id"`
+	SerialNumber      string     `json:"serialNumber"`
+	ProductID         string     `json:"productId"`
+	StoreID           string     `json:"storeId"`
+	ReservationID     string     `json:"reservationId"`
+	CustomerID        string     `json:"customerId"`
+	SoldAt            time.Time  `json:"soldAt"`
+	WarrantyExpiresAt *time.Time `json:"warrantyExpiresAt,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+}
+
+// UnderWarranty indica si la garantía sigue vigente en el instante indicado
+func (s *SerialRegistration) UnderWarranty(at time.Time) bool {
+	return s.WarrantyExpiresAt != nil && at.Before(*s.WarrantyExpiresAt)
+}
+
+// Validate verifica que el registro tenga datos válidos
+func (s *SerialRegistration) Validate() error {
+	if s.SerialNumber == "" {
+		return &ValidationError{Field: "serial_number", Message: "Serial number is required"}
+	}
+	if s.ProductID == "" {
+		return &ValidationError{Field: "product_id", Message: "Product ID is required"}
+	}
+	if s.ReservationID == "" {
+		return &ValidationError{Field: "reservation_id", Message: "Reservation ID is required"}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultSKUPattern acepta letras, dígitos y los separadores . _ - / (sin empezar por separador)
+const DefaultSKUPattern = `^[A-Za-z0-9][A-Za-z0-9._/-]*$`
+
+// DefaultSKUMaxLength longitud máxima por defecto de un SKU
+const DefaultSKUMaxLength = 64
+
+// SKUPolicy define cómo se normalizan y validan los SKUs al crear, actualizar o importar productos
+type SKUPolicy struct {
+	Pattern   *regexp.Regexp // nil = sin restricción de formato
+	MaxLength int            // <= 0 = sin límite
+	Uppercase bool           // Normalizar a mayúsculas
+}
+
+// DefaultSKUPolicy retorna la política por defecto (patrón DefaultSKUPattern, 64 caracteres, mayúsculas)
+func DefaultSKUPolicy() SKUPolicy {
+	return SKUPolicy{
+		Pattern:   regexp.MustCompile(DefaultSKUPattern),
+		MaxLength: DefaultSKUMaxLength,
+		Uppercase: true,
+	}
+}
+
+// Normalize recorta espacios, aplica la normalización de mayúsculas y valida el resultado
+func (p SKUPolicy) Normalize(sku string) (string, error) {
+	sku = strings.TrimSpace(sku)
+	if sku == "" {
+		return "", &ValidationError{Field: "sku", Message: "SKU is required"}
+	}
+	if p.Uppercase {
+		sku = strings.ToUpper(sku)
+	}
+	if p.MaxLength > 0 && len(sku) > p.MaxLength {
+		return "", &ValidationError{Field: "sku", Message: fmt.Sprintf("SKU cannot exceed %d characters", p.MaxLength)}
+	}
+	if p.Pattern != nil && !p.Pattern.MatchString(sku) {
+		return "", &ValidationError{Field: "sku", Message: fmt.Sprintf("SKU %q does not match the pattern %s", sku, p.Pattern)}
+	}
+	return sku, nil
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// DefaultSnapshotBackfillBatch filas de stock por lote del backfill de snapshots
+const DefaultSnapshotBackfillBatch = 500
+
+// SnapshotBackfill representa una ejecución del backfill que emite stock.snapshot para cada
+// fila de stock (producto, tienda). El cursor se guarda tras cada lote, de modo que si el
+// proceso se interrumpe la siguiente ejecución continúa desde la última fila emitida.
+type SnapshotBackfill struct {
+	ID            string     `json:"id"`
+	Status        JobStatus  `json:"status"` // RUNNING hasta recorrer todas las filas, luego COMPLETED
+	LastProductID string     `json:"last_product_id"`
+	LastStoreID   string     `json:"last_store_id"`
+	Emitted       int        `json:"emitted"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
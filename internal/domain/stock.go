@@ -1,16 +1,23 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Stock representa el inventario de un producto en una tienda específica
 type Stock struct {
-	ID        string    `json:"id" db:"id"`
-	ProductID string    `json:"productId" db:"product_id"`
-	StoreID   string    `json:"storeId" db:"store_id"`  // Identificador de la tienda
-	Quantity  int       `json:"quantity" db:"quantity"` // Cantidad total
-	Reserved  int       `json:"reserved" db:"reserved"` // Cantidad reservada (pendiente)
-	Version   int       `json:"version" db:"version"`   // Para optimistic locking
-	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+	ID          string    `json:"id" db:"id"`
+	ProductID   string    `json:"productId" db:"product_id"`
+	StoreID     string    `json:"storeId" db:"store_id"`         // Identificador de la tienda
+	Quantity    int       `json:"quantity" db:"quantity"`        // Cantidad total
+	Reserved    int       `json:"reserved" db:"reserved"`        // Cantidad reservada (pendiente): reservas de clientes y retenciones internas
+	Held        int       `json:"held" db:"held"`                // Parte de Reserved retenida para uso interno (exposición, reparación, calidad)
+	MinStock    int       `json:"minStock" db:"min_stock"`       // Umbral de alerta de stock bajo (0 = sin umbral)
+	MaxStock    int       `json:"maxStock" db:"max_stock"`       // Nivel máximo de reposición (0 = sin límite)
+	SafetyStock int       `json:"safetyStock" db:"safety_stock"` // Reservado para venta en tienda: no se ofrece a reservas ni en disponibilidad
+	Version     int       `json:"version" db:"version"`          // Para optimistic locking
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
 }
 
 // Available calcula el stock disponible (no reservado)
@@ -18,9 +25,14 @@ func (s *Stock) Available() int {
 	return s.Quantity - s.Reserved
 }
 
-// CanReserve verifica si hay suficiente stock disponible para reservar
+// Sellable calcula la cantidad vendible: la disponible menos el stock de seguridad
+func (s *Stock) Sellable() int {
+	return s.Available() - s.SafetyStock
+}
+
+// CanReserve verifica si hay suficiente stock vendible para reservar
 func (s *Stock) CanReserve(quantity int) bool {
-	return s.Available() >= quantity
+	return s.Sellable() >= quantity
 }
 
 // CanFulfill verifica si hay suficiente cantidad total
@@ -42,6 +54,9 @@ func (s *Stock) Validate() error {
 	if s.Reserved < 0 {
 		return &ValidationError{Field: "reserved", Message: "Reserved cannot be negative"}
 	}
+	if s.Held < 0 || s.Held > s.Reserved {
+		return &ValidationError{Field: "held", Message: "Held must be between 0 and reserved"}
+	}
 	if s.Reserved > s.Quantity {
 		return &ValidationError{Field: "reserved", Message: "Reserved cannot exceed quantity"}
 	}
@@ -64,3 +79,71 @@ type StockAvailabilityByStore struct {
 	Reserved  int    `json:"reserved"`
 	Available int    `json:"available"`
 }
+
+// StockComparison representa la comparación de surtido y disponibilidad entre dos tiendas
+type StockComparison struct {
+	StoreA        string              `json:"storeA"`
+	StoreB        string              `json:"storeB"`
+	MinDifference int                 `json:"minDifference"`
+	OnlyInA       []*Stock            `json:"onlyInA"`     // Productos con stock en A pero no en B
+	OnlyInB       []*Stock            `json:"onlyInB"`     // Productos con stock en B pero no en A
+	Disparities   []StockDisparity    `json:"disparities"` // Productos en ambas con gran diferencia de disponibilidad
+	Summary       StockComparisonInfo `json:"summary"`
+}
+
+// StockDisparity representa la diferencia de disponibilidad de un producto entre dos tiendas
+type StockDisparity struct {
+	ProductID     string `json:"productId"`
+	AvailableA    int    `json:"availableA"`
+	AvailableB    int    `json:"availableB"`
+	Difference    int    `json:"difference"`    // AvailableA - AvailableB
+	SurplusStore  string `json:"surplusStore"`  // Tienda candidata a origen de una transferencia
+	ShortageStore string `json:"shortageStore"` // Tienda candidata a destino de una transferencia
+}
+
+// StockComparisonInfo resume el resultado de la comparación
+type StockComparisonInfo struct {
+	Common      int `json:"common"`
+	OnlyInA     int `json:"onlyInA"`
+	OnlyInB     int `json:"onlyInB"`
+	Disparities int `json:"disparities"`
+}
+
+// Límites de paginación del listado de stock bajo
+const (
+	DefaultLowStockLimit = 50
+	MaxLowStockLimit     = 500
+)
+
+// LowStockFilter define el alcance y la paginación de la consulta de stock bajo.
+// Cada fila usa su min_stock como umbral cuando está definido (> 0) y Threshold en caso contrario.
+type LowStockFilter struct {
+	StoreID   string
+	Category  string
+	ABCClass  ABCClass // "" = todas las clases
+	Threshold int
+	Limit     int // <= 0 sin límite (uso interno)
+	Offset    int
+}
+
+// Validate verifica el filtro
+func (f *LowStockFilter) Validate() error {
+	if f.Threshold < 0 {
+		return &ValidationError{Field: "threshold", Message: "threshold cannot be negative"}
+	}
+	if f.Limit > MaxLowStockLimit {
+		return &ValidationError{Field: "limit", Message: fmt.Sprintf("limit cannot exceed %d", MaxLowStockLimit)}
+	}
+	if f.Offset < 0 {
+		return &ValidationError{Field: "offset", Message: "offset must be zero or positive"}
+	}
+	return nil
+}
+
+// LowStockThreshold retorna el umbral efectivo de la fila: min_stock o el umbral de la consulta
+func (s *Stock) LowStockThreshold(fallback int) int {
+	if s.MinStock > 0 {
+		return s.MinStock
+	}
+	return fallback
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// StockAdjustmentType indica cómo se aplica un ajuste manual pendiente de aprobación
+type StockAdjustmentType string
+
+const (
+	StockAdjustmentAdjust StockAdjustmentType = "ADJUST" // POST /adjust: suma quantity (negativa para retirar)
+	StockAdjustmentSet    StockAdjustmentType = "SET"    // PUT /stock: fija la cantidad en quantity
+)
+
+// StockAdjustmentStatus estado de un ajuste manual pendiente de aprobación
+type StockAdjustmentStatus string
+
+const (
+	StockAdjustmentPending  StockAdjustmentStatus = "PENDING"  // Esperando la revisión de otra API key
+	StockAdjustmentApproved StockAdjustmentStatus = "APPROVED" // Aprobado y aplicado al stock
+	StockAdjustmentRejected StockAdjustmentStatus = "REJECTED" // Rechazado; el stock no cambió
+)
+
+// StockAdjustment ajuste manual de stock que supera el umbral de aprobación: no cambia el stock
+// hasta que lo aprueba una API key distinta de la que lo solicitó
+type StockAdjustment struct {
+	ID              string                `json:"id"`
+	ProductID       string                `json:"product_id"`
+	StoreID         string                `json:"store_id"`
+	Type            StockAdjustmentType   `json:"type"`
+	Quantity        int                   `json:"quantity"`         // Ajuste (ADJUST) o cantidad final (SET)
+	CurrentQuantity int                   `json:"current_quantity"` // Cantidad de la fila al solicitarlo
+	Reason          string                `json:"reason,omitempty"`
+	RequestedBy     string                `json:"requested_by"` // Nombre de la API key que lo solicitó
+	Status          StockAdjustmentStatus `json:"status"`
+	ReviewedBy      string                `json:"reviewed_by,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+	ReviewedAt      *time.Time            `json:"reviewed_at,omitempty"`
+}
+
+// Delta retorna las unidades que el ajuste suma (o resta) sobre current
+func (a *StockAdjustment) Delta(current int) int {
+	if a.Type == StockAdjustmentSet {
+		return a.Quantity - current
+	}
+	return a.Quantity
+}
+
+// NewQuantity calcula la cantidad resultante de aplicar el ajuste sobre current
+func (a *StockAdjustment) NewQuantity(current int) int {
+	return current + a.Delta(current)
+}
+
+// AdjustmentApprovalPolicy umbrales a partir de los cuales un ajuste manual requiere aprobación.
+// Cero desactiva cada umbral; con los dos a cero los ajustes se aplican siempre directamente.
+type AdjustmentApprovalPolicy struct {
+	MaxUnits   int     // Unidades por encima de las cuales se requiere aprobación
+	MaxPercent float64 // Porcentaje de la cantidad actual por encima del cual se requiere aprobación
+}
+
+// Enabled indica si hay algún umbral configurado
+func (p AdjustmentApprovalPolicy) Enabled() bool {
+	return p.MaxUnits > 0 || p.MaxPercent > 0
+}
+
+// Requires indica si un ajuste de delta unidades sobre current requiere aprobación. El umbral
+// porcentual no se aplica a filas sin stock (la carga inicial no tiene referencia).
+func (p AdjustmentApprovalPolicy) Requires(current, delta int) bool {
+	if delta < 0 {
+		delta = -delta
+	}
+	if p.MaxUnits > 0 && delta > p.MaxUnits {
+		return true
+	}
+	return p.MaxPercent > 0 && current > 0 && float64(delta)*100 > p.MaxPercent*float64(current)
+}
+
+// SelfApprovalError indica que la API key que solicitó el ajuste intenta revisarlo
+type SelfApprovalError struct {
+	AdjustmentID string
+	Actor        string
+}
+
+func (e *SelfApprovalError) Error() string {
+	return fmt.Sprintf("stock adjustment %s was requested by %s and must be reviewed by a different API key", e.AdjustmentID, e.Actor)
+}
+
+func (e *SelfApprovalError) Code() string {
+	return "SELF_APPROVAL"
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StockHistoryEntry un paso de la línea temporal de una fila de stock: el evento que la cambió,
+// quién lo hizo y cómo quedaron quantity, reserved y version después de aplicarlo
+type StockHistoryEntry struct {
+	Seq           int64     `json:"seq"`
+	EventID       string    `json:"event_id"`
+	EventType     string    `json:"event_type"`
+	At            time.Time `json:"at"`
+	Actor         string    `json:"actor,omitempty"` // API key que originó el cambio ("system" en workers)
+	CorrelationID string    `json:"correlation_id,omitempty"`
+	QuantityDelta int       `json:"quantity_delta"`
+	ReservedDelta int       `json:"reserved_delta"`
+	Quantity      int       `json:"quantity"` // Después del evento
+	Reserved      int       `json:"reserved"` // Después del evento
+	Version       int       `json:"version"`  // Después del evento
+	Reason        string    `json:"reason,omitempty"`
+	Reference     string    `json:"reference,omitempty"` // Reserva, retención o ajuste relacionado
+	// Diferencia entre lo reconstruido y el valor absoluto del evento (cambios sin evento)
+	UntrackedQuantity int          `json:"untracked_quantity,omitempty"`
+	UntrackedReserved int          `json:"untracked_reserved,omitempty"`
+	Notes             []*StockNote `json:"notes,omitempty"` // Notas del personal sobre este movimiento
+}
+
+// StockHistory línea temporal de una fila de stock, del evento más reciente al más antiguo
+type StockHistory struct {
+	ProductID string               `json:"product_id"`
+	StoreID   string               `json:"store_id"`
+	Quantity  int                  `json:"quantity"` // Valores actuales de la fila
+	Reserved  int                  `json:"reserved"`
+	Version   int                  `json:"version"`
+	Entries   []*StockHistoryEntry `json:"entries"`
+	Count     int                  `json:"count"`
+	HasMore   bool                 `json:"has_more"`        // Hay entradas más antiguas: repetir con before_seq
+	Notes     []*StockNote         `json:"notes,omitempty"` // Notas sobre la fila en el tramo de la página
+}
+
+// Límites de paginación de la línea temporal de stock
+const (
+	DefaultStockHistoryLimit = 100
+	MaxStockHistoryLimit     = 1000
+)
+
+// StockHistoryFilter acota la línea temporal (campos vacíos = sin límite)
+type StockHistoryFilter struct {
+	From      *time.Time // Inclusivo
+	To        *time.Time // Exclusivo
+	BeforeSeq int64      // Solo eventos con seq < BeforeSeq (cursor para la página siguiente)
+	Limit     int
+}
+
+// Validate verifica el filtro
+func (f *StockHistoryFilter) Validate() error {
+	if f.BeforeSeq < 0 {
+		return &ValidationError{Field: "before_seq", Message: "before_seq must be zero or positive"}
+	}
+	if f.Limit < 0 || f.Limit > MaxStockHistoryLimit {
+		return &ValidationError{Field: "limit", Message: fmt.Sprintf("limit must be between 1 and %d", MaxStockHistoryLimit)}
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return &ValidationError{Field: "from", Message: "from must be before to"}
+	}
+	return nil
+}
+
+// StockHistoryRewinder reconstruye la línea temporal hacia atrás: parte de los valores actuales
+// de la fila y deshace cada evento, del más reciente al más antiguo. Los eventos con valores
+// absolutos (stock.created, stock.updated, stock.snapshot, stock.reserved_corrected) vuelven a
+// sincronizar lo reconstruido y dejan la diferencia en untracked_*.
+type StockHistoryRewinder struct {
+	quantity int
+	reserved int
+	version  int
+}
+
+// NewStockHistoryRewinder crea un rewinder a partir de la fila de stock actual
+func NewStockHistoryRewinder(stock *Stock) *StockHistoryRewinder {
+	return &StockHistoryRewinder{
+		quantity: stock.Quantity,
+		reserved: stock.Reserved,
+		version:  stock.Version,
+	}
+}
+
+// stockHistoryPayload campos de los payloads de stock y reservas que afectan a la fila
+type stockHistoryPayload struct {
+	OldQuantity     int    `json:"old_quantity"`
+	NewQuantity     int    `json:"new_quantity"`
+	InitialQuantity int    `json:"initial_quantity"`
+	Quantity        int    `json:"quantity"`
+	Reserved        int    `json:"reserved"`
+	Version         int    `json:"version"`
+	OldReserved     int    `json:"old_reserved"`
+	NewReserved     int    `json:"new_reserved"`
+	Reason          string `json:"reason"`
+	Actor           string `json:"actor"`
+	RequestedBy     string `json:"requested_by"`
+	ReviewedBy      string `json:"reviewed_by"`
+	CreatedBy       string `json:"created_by"`
+	ReleasedBy      string `json:"released_by"`
+	ReservationID   string `json:"reservation_id"`
+	HoldID          string `json:"hold_id"`
+	AdjustmentID    string `json:"adjustment_id"`
+	ReferenceID     string `json:"reference_id"`
+}
+
+// Rewind deshace el evento (que debe ser el siguiente más antiguo) y retorna su entrada con los
+// valores posteriores a él
+func (r *StockHistoryRewinder) Rewind(event *Event) *StockHistoryEntry {
+	var p stockHistoryPayload
+	_ = json.Unmarshal([]byte(event.Payload), &p)
+
+	entry := &StockHistoryEntry{
+		Seq:           event.Seq,
+		EventID:       event.ID,
+		EventType:     event.EventType,
+		At:            event.CreatedAt,
+		Actor:         event.Actor,
+		CorrelationID: event.CorrelationID,
+		Reason:        p.Reason,
+	}
+	if entry.Actor == "" {
+		entry.Actor = firstNonEmpty(p.Actor, p.ReleasedBy, p.ReviewedBy, p.CreatedBy, p.RequestedBy)
+	}
+	entry.Reference = firstNonEmpty(p.ReservationID, p.HoldID, p.AdjustmentID, p.ReferenceID)
+
+	qAfter, rAfter, vAfter := r.quantity, r.reserved, r.version
+	dv := 0
+
+	switch event.EventType {
+	case EventStockCreated:
+		entry.UntrackedQuantity = qAfter - p.InitialQuantity
+		qAfter, rAfter, vAfter = p.InitialQuantity, 0, 1
+		entry.QuantityDelta = p.InitialQuantity
+		r.quantity, r.reserved, r.version = 0, 0, 0
+		entry.Quantity, entry.Reserved, entry.Version = qAfter, rAfter, vAfter
+		return entry
+	case EventStockUpdated:
+		entry.UntrackedQuantity = qAfter - p.NewQuantity
+		qAfter = p.NewQuantity
+		entry.QuantityDelta = p.NewQuantity - p.OldQuantity
+		dv = 1
+	case EventStockSnapshot:
+		entry.UntrackedQuantity = qAfter - p.Quantity
+		entry.UntrackedReserved = rAfter - p.Reserved
+		qAfter, rAfter, vAfter = p.Quantity, p.Reserved, p.Version
+	case EventStockReservedCorrected:
+		entry.UntrackedReserved = rAfter - p.NewReserved
+		rAfter = p.NewReserved
+		entry.ReservedDelta = p.NewReserved - p.OldReserved
+	case EventReservationCreated, EventPreorderAllocated, EventStockHoldPlaced:
+		entry.ReservedDelta = p.Quantity
+	case EventReservationCancelled, EventReservationExpired, EventStockHoldReleased:
+		entry.ReservedDelta = -p.Quantity
+	case EventReservationConfirmed, EventReservationPickedUp:
+		entry.QuantityDelta = -p.Quantity
+		entry.ReservedDelta = -p.Quantity
+	}
+	// stock.transferred y stock.adjustment_* son informativos: el cambio llega como stock.updated.
+	// reservation.preorder_created/cancelled no tocan la fila (la preventa aún no tiene stock) ni
+	// reservation.ready_for_pickup (la reserva sigue reteniendo sus unidades).
+
+	entry.Quantity, entry.Reserved, entry.Version = qAfter, rAfter, vAfter
+	r.quantity = qAfter - entry.QuantityDelta
+	r.reserved = rAfter - entry.ReservedDelta
+	r.version = vAfter - dv
+	return entry
+}
+
+// firstNonEmpty retorna el primer valor no vacío
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+// StockHoldType motivo por el que el personal retiene unidades de la venta sin que haya cliente
+type StockHoldType string
+
+const (
+	StockHoldDisplay StockHoldType = "DISPLAY"      // Unidades de exposición en tienda
+	StockHoldRepair  StockHoldType = "REPAIR"       // En reparación o en el servicio técnico
+	StockHoldQuality StockHoldType = "QUALITY_HOLD" // Bloqueadas por control de calidad
+)
+
+// IsValid verifica si el tipo de retención es válido
+func (t StockHoldType) IsValid() bool {
+	switch t {
+	case StockHoldDisplay, StockHoldRepair, StockHoldQuality:
+		return true
+	}
+	return false
+}
+
+// StockHoldStatus estado de una retención interna de stock
+type StockHoldStatus string
+
+const (
+	StockHoldActive   StockHoldStatus = "ACTIVE"   // Las unidades siguen fuera de la venta
+	StockHoldReleased StockHoldStatus = "RELEASED" // Liberada: las unidades vuelven a estar disponibles
+)
+
+// StockHold retención de unidades para uso interno (exposición, reparación, calidad). Como una
+// reserva, cuenta en stock.reserved y reduce la disponibilidad, pero no tiene cliente ni expira:
+// dura hasta que el personal la libera. stock.held suma las retenciones activas de la fila.
+type StockHold struct {
+	ID         string          `json:"id"`
+	ProductID  string          `json:"product_id"`
+	StoreID    string          `json:"store_id"`
+	Type       StockHoldType   `json:"type"`
+	Quantity   int             `json:"quantity"`
+	Note       string          `json:"note,omitempty"`
+	Status     StockHoldStatus `json:"status"`
+	CreatedBy  string          `json:"created_by"` // Nombre de la API key que la creó
+	ReleasedBy string          `json:"released_by,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ReleasedAt *time.Time      `json:"released_at,omitempty"`
+}
+
+// Validate verifica que la retención tenga datos válidos
+func (h *StockHold) Validate() error {
+	if h.ProductID == "" {
+		return &ValidationError{Field: "product_id", Message: "Product ID is required"}
+	}
+	if h.StoreID == "" {
+		return &ValidationError{Field: "store_id", Message: "Store ID is required"}
+	}
+	if !h.Type.IsValid() {
+		return &ValidationError{Field: "type", Message: "type must be DISPLAY, REPAIR or QUALITY_HOLD"}
+	}
+	if h.Quantity <= 0 {
+		return &ValidationError{Field: "quantity", Message: "Quantity must be greater than zero"}
+	}
+	return nil
+}
+
+// StockHoldFilter filtra el listado de retenciones (campos vacíos = todos)
+type StockHoldFilter struct {
+	StoreID   string
+	ProductID string
+	Type      StockHoldType
+	Status    StockHoldStatus
+	Limit     int
+	Offset    int
+}
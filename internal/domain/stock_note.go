@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Límites de las notas de stock
+const (
+	MaxStockNoteLength      = 2000 // Caracteres del texto
+	MaxStockNoteAttachments = 10
+)
+
+// StockNoteAttachment metadatos de una foto o documento adjunto a una nota. El fichero no pasa
+// por el sistema: se guarda donde lo haya subido la tienda y aquí solo se referencia.
+type StockNoteAttachment struct {
+	URL         string `json:"url"`
+	FileName    string `json:"file_name,omitempty"`
+	ContentType string `json:"content_type,omitempty"` // image/* o application/pdf
+	SizeBytes   int64  `json:"size_bytes,omitempty"`
+}
+
+// StockNote nota del personal de tienda sobre una fila de stock o uno de sus movimientos
+// (p. ej. "caja dañada, faltan 3 unidades"), para dar contexto a ajustes posteriores y
+// reclamaciones al proveedor
+type StockNote struct {
+	ID          string                `json:"id"`
+	ProductID   string                `json:"product_id"`
+	StoreID     string                `json:"store_id"`
+	EventID     string                `json:"event_id,omitempty"` // Movimiento al que se refiere (vacío = la fila)
+	Text        string                `json:"text"`
+	Attachments []StockNoteAttachment `json:"attachments,omitempty"`
+	CreatedBy   string                `json:"created_by"` // Nombre de la API key que la creó
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// Validate normaliza y verifica el texto y los adjuntos de la nota
+func (n *StockNote) Validate() error {
+	n.Text = strings.TrimSpace(n.Text)
+	if n.Text == "" {
+		return &ValidationError{Field: "text", Message: "text is required"}
+	}
+	if len([]rune(n.Text)) > MaxStockNoteLength {
+		return &ValidationError{Field: "text", Message: fmt.Sprintf("text must be at most %d characters", MaxStockNoteLength)}
+	}
+	if len(n.Attachments) > MaxStockNoteAttachments {
+		return &ValidationError{Field: "attachments", Message: fmt.Sprintf("at most %d attachments are allowed", MaxStockNoteAttachments)}
+	}
+
+	for i := range n.Attachments {
+		attachment := &n.Attachments[i]
+		attachment.URL = strings.TrimSpace(attachment.URL)
+		parsed, err := url.Parse(attachment.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return &ValidationError{Field: "attachments", Message: fmt.Sprintf("attachment %d: url must be an absolute http(s) URL", i)}
+		}
+		attachment.ContentType = strings.ToLower(strings.TrimSpace(attachment.ContentType))
+		if attachment.ContentType != "" && !strings.HasPrefix(attachment.ContentType, "image/") && attachment.ContentType != "application/pdf" {
+			return &ValidationError{Field: "attachments", Message: fmt.Sprintf("attachment %d: content_type must be an image or application/pdf", i)}
+		}
+		if attachment.SizeBytes < 0 {
+			return &ValidationError{Field: "attachments", Message: fmt.Sprintf("attachment %d: size_bytes must be zero or positive", i)}
+		}
+	}
+	return nil
+}
+
+// IsStockMovementOf indica si el evento es un movimiento de la fila de stock, con el mismo
+// criterio que la línea temporal: eventos de stock del producto en la tienda (o transferencias
+// hacia ella) y eventos de reservas del producto en la tienda
+func (e *Event) IsStockMovementOf(productID, storeID string) bool {
+	var payload struct {
+		ProductID string `json:"product_id"`
+		ToStoreID string `json:"to_store_id"`
+	}
+	_ = json.Unmarshal([]byte(e.Payload), &payload)
+
+	switch e.AggregateType {
+	case "stock":
+		return e.AggregateID == productID &&
+			(e.StoreID == storeID || (e.EventType == EventStockTransferred && payload.ToStoreID == storeID))
+	case "reservation":
+		return e.StoreID == storeID && payload.ProductID == productID
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ScheduledStockChangeType indica cómo se aplica un cambio de stock programado
+type ScheduledStockChangeType string
+
+const (
+	ScheduledStockAdjust ScheduledStockChangeType = "ADJUST" // Suma quantity (negativa para retirar unidades)
+	ScheduledStockSet    ScheduledStockChangeType = "SET"    // Fija la cantidad en quantity
+)
+
+// ScheduledStockSource origen de un cambio programado: los pedidos de compra y las transferencias
+// se programan para su fecha prevista de llegada y cuentan en la disponibilidad futura
+type ScheduledStockSource string
+
+const (
+	ScheduledStockManual        ScheduledStockSource = "MANUAL"         // Programado a mano (p. ej. liberar unidades)
+	ScheduledStockPurchaseOrder ScheduledStockSource = "PURCHASE_ORDER" // Llegada prevista de un pedido de compra
+	ScheduledStockTransfer      ScheduledStockSource = "TRANSFER"       // Llegada prevista de una transferencia
+)
+
+// ScheduledStockStatus representa el estado de un cambio de stock programado
+type ScheduledStockStatus string
+
+const (
+	ScheduledStockPending   ScheduledStockStatus = "PENDING"   // Esperando effective_at
+	ScheduledStockApplied   ScheduledStockStatus = "APPLIED"   // Aplicado por el worker
+	ScheduledStockCancelled ScheduledStockStatus = "CANCELLED" // Anulado antes de aplicarse
+	ScheduledStockFailed    ScheduledStockStatus = "FAILED"    // Rechazado al aplicarse (ver Error); no se reintenta
+)
+
+// ScheduledStockChange representa un ajuste de stock futuro de una fila producto/tienda
+// (p. ej. liberar 500 unidades el viernes a las 10:00)
+type ScheduledStockChange struct {
+	ID          string                   `json:"id"`
+	ProductID   string                   `json:"product_id"`
+	StoreID     string                   `json:"store_id"`
+	Type        ScheduledStockChangeType `json:"type"`
+	Quantity    int                      `json:"quantity"`
+	Actor       string                   `json:"actor"` // Nombre de la API key que lo programó
+	Source      ScheduledStockSource     `json:"source"`
+	Reference   string                   `json:"reference,omitempty"` // Nº de pedido de compra o de transferencia
+	Status      ScheduledStockStatus     `json:"status"`
+	Error       string                   `json:"error,omitempty"` // Motivo del rechazo (FAILED)
+	EffectiveAt time.Time                `json:"effective_at"`
+	CreatedAt   time.Time                `json:"created_at"`
+	AppliedAt   *time.Time               `json:"applied_at,omitempty"`
+}
+
+// Validate normaliza el tipo y verifica que el cambio programado tenga datos válidos
+func (s *ScheduledStockChange) Validate(now time.Time) error {
+	s.Type = ScheduledStockChangeType(strings.ToUpper(strings.TrimSpace(string(s.Type))))
+	if s.Type == "" {
+		s.Type = ScheduledStockAdjust
+	}
+
+	switch s.Type {
+	case ScheduledStockAdjust:
+		if s.Quantity == 0 {
+			return &ValidationError{Field: "quantity", Message: "adjustment cannot be zero"}
+		}
+	case ScheduledStockSet:
+		if s.Quantity < 0 {
+			return &ValidationError{Field: "quantity", Message: "quantity cannot be negative"}
+		}
+	default:
+		return &ValidationError{Field: "type", Message: "type must be ADJUST or SET"}
+	}
+
+	s.Source = ScheduledStockSource(strings.ToUpper(strings.TrimSpace(string(s.Source))))
+	switch s.Source {
+	case "":
+		s.Source = ScheduledStockManual
+	case ScheduledStockManual, ScheduledStockPurchaseOrder, ScheduledStockTransfer:
+	default:
+		return &ValidationError{Field: "source", Message: "source must be MANUAL, PURCHASE_ORDER or TRANSFER"}
+	}
+	s.Reference = strings.TrimSpace(s.Reference)
+
+	if s.EffectiveAt.IsZero() {
+		return &ValidationError{Field: "effective_at", Message: "effective_at is required"}
+	}
+	if !s.EffectiveAt.After(now) {
+		return &ValidationError{Field: "effective_at", Message: "effective_at must be in the future"}
+	}
+	return nil
+}
+
+// NewQuantity calcula la cantidad resultante de aplicar el cambio sobre current
+func (s *ScheduledStockChange) NewQuantity(current int) int {
+	if s.Type == ScheduledStockSet {
+		return s.Quantity
+	}
+	return current + s.Quantity
+}
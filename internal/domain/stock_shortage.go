@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// StockShortage diagnóstico de una reserva rechazada por falta de stock. Se devuelve en
+// "details" del 409 para que el cliente pueda mostrar "quedan 2, se liberan 3 más en 12
+// minutos" u ofrecer una preventa, en lugar de un rechazo sin contexto.
+type StockShortage struct {
+	Quantity          int        `json:"quantity"`
+	Reserved          int        `json:"reserved"`
+	Available         int        `json:"available"` // Reservable para el canal del request
+	Requested         int        `json:"requested"`
+	NextExpiryAt      *time.Time `json:"next_expiry_at,omitempty"`    // Caducidad más próxima de una reserva pendiente
+	NextExpiryUnits   int        `json:"next_expiry_units,omitempty"` // Unidades que se liberan en esa caducidad
+	SufficientAt      *time.Time `json:"sufficient_at,omitempty"`     // Cuándo las caducidades cubrirán lo pedido (nil si no alcanzan)
+	PreorderAvailable int        `json:"preorder_available"`          // Unidades entrantes aún sin comprometer en preventas
+	PreorderPossible  bool       `json:"preorder_possible"`           // Lo pedido cabe en una preventa
+}
+
+// NewStockShortage calcula el diagnóstico de una fila de stock. pending son las reservas
+// pendientes de la fila ordenadas por caducidad; preorderAvailable las unidades entrantes que
+// se pueden pedir en preventa.
+func NewStockShortage(stock *Stock, available, requested int, pending []*Reservation, preorderAvailable int) *StockShortage {
+	shortage := &StockShortage{
+		Quantity:          stock.Quantity,
+		Reserved:          stock.Reserved,
+		Available:         available,
+		Requested:         requested,
+		PreorderAvailable: max(preorderAvailable, 0),
+		PreorderPossible:  preorderAvailable >= requested,
+	}
+
+	freed := 0
+	for _, reservation := range pending {
+		expiresAt := reservation.ExpiresAt
+		if shortage.NextExpiryAt == nil {
+			shortage.NextExpiryAt = &expiresAt
+		}
+		if expiresAt.Equal(*shortage.NextExpiryAt) {
+			shortage.NextExpiryUnits += reservation.Quantity
+		}
+		freed += reservation.Quantity
+		if available+freed >= requested {
+			shortage.SufficientAt = &expiresAt
+			break
+		}
+	}
+
+	return shortage
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// AvailabilityStatus disponibilidad de un producto en una tienda expresada por tramos
+type AvailabilityStatus string
+
+const (
+	AvailabilityInStock    AvailabilityStatus = "in_stock"
+	AvailabilityLowStock   AvailabilityStatus = "low_stock"    // Vendible por debajo del umbral
+	AvailabilityOutOfStock AvailabilityStatus = "out_of_stock" // Sin cantidad vendible
+)
+
+// StockVisibility controla qué ve el público de la disponibilidad de una tienda. Las llamadas
+// con API key siguen recibiendo las cantidades.
+type StockVisibility struct {
+	StoreID           string    `json:"store_id"`
+	HideQuantities    bool      `json:"hide_quantities"`     // Sin API key solo se responde el tramo (status)
+	LowStockThreshold int       `json:"low_stock_threshold"` // Umbral de low_stock (0 = min_stock de la fila o DefaultMinStock)
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// Validate verifica la configuración de visibilidad
+func (v *StockVisibility) Validate() error {
+	if v.LowStockThreshold < 0 {
+		return &ValidationError{Field: "low_stock_threshold", Message: "low_stock_threshold must be zero or positive"}
+	}
+	return nil
+}
+
+// Status retorna el tramo de disponibilidad de la fila. Sin configuración (v nil) se usa el
+// umbral de la fila.
+func (v *StockVisibility) Status(stock *Stock) AvailabilityStatus {
+	threshold := stock.LowStockThreshold(DefaultMinStock)
+	if v != nil && v.LowStockThreshold > 0 {
+		threshold = v.LowStockThreshold
+	}
+
+	switch sellable := stock.Sellable(); {
+	case sellable <= 0:
+		return AvailabilityOutOfStock
+	case sellable <= threshold:
+		return AvailabilityLowStock
+	}
+	return AvailabilityInStock
+}
+
+// HidesQuantities indica si la tienda oculta las cantidades al público
+func (v *StockVisibility) HidesQuantities() bool {
+	return v != nil && v.HideQuantities
+}
+
+// StoreAvailability disponibilidad de un producto en una tienda
+type StoreAvailability struct {
+	StoreID   string             `json:"store_id"`
+	Status    AvailabilityStatus `json:"status"`
+	Available *int               `json:"available,omitempty"` // Cantidad vendible; se omite si la tienda la oculta al público
+	// Tipo de ubicación; solo al consultar para una forma de entrega (fulfillment)
+	LocationType LocationType `json:"location_type,omitempty"`
+}
+
+// ProductAvailability disponibilidad de un producto en las tiendas con stock
+type ProductAvailability struct {
+	ProductID string               `json:"product_id"`
+	Stores    []*StoreAvailability `json:"stores"`
+}
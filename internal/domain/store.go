@@ -0,0 +1,86 @@
+package domain
+
+import "time"
+
+// DefaultMinStock umbral de alerta de stock bajo por defecto (coincide con el threshold por defecto de /stock/low-stock)
+const DefaultMinStock = 10
+
+// Store representa una tienda de la red
+type Store struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address,omitempty"`
+	City      string    `json:"city,omitempty"`
+	Country   string    `json:"country,omitempty"`
+	Phone     string    `json:"phone,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Tipo de ubicación: STORE (por defecto), WAREHOUSE o DARKSTORE. Decide desde dónde se
+	// sirven los envíos y las recogidas según la FulfillmentPolicy del canal.
+	LocationType LocationType `json:"locationType"`
+}
+
+// Validate verifica que la tienda tenga datos válidos
+func (s *Store) Validate() error {
+	if s.ID == "" {
+		return &ValidationError{Field: "id", Message: "Store ID is required"}
+	}
+	if err := ValidateStoreID("id", s.ID); err != nil {
+		return err
+	}
+	if s.Name == "" {
+		return &ValidationError{Field: "name", Message: "Store name is required"}
+	}
+	locationType, err := ParseLocationType(string(s.LocationType))
+	if err != nil {
+		return err
+	}
+	s.LocationType = locationType
+	return nil
+}
+
+// StoreAPIKey representa una API key emitida para una tienda.
+// Key solo se informa en la respuesta que la crea; se persiste únicamente su hash.
+type StoreAPIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	StoreID   string     `json:"storeId"`
+	Key       string     `json:"key,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// AssortmentTemplate define qué productos se dan de alta en una tienda nueva.
+// Las opciones se evalúan en orden: ProductIDs, FromStoreID (copiar surtido), Category.
+// Category también filtra el surtido copiado desde FromStoreID.
+type AssortmentTemplate struct {
+	ProductIDs  []string `json:"product_ids,omitempty"`
+	FromStoreID string   `json:"from_store_id,omitempty"`
+	Category    string   `json:"category,omitempty"`
+}
+
+// IsEmpty indica si el template no define ningún producto
+func (t AssortmentTemplate) IsEmpty() bool {
+	return len(t.ProductIDs) == 0 && t.FromStoreID == "" && t.Category == ""
+}
+
+// StoreBootstrap representa los datos de onboarding de una tienda
+type StoreBootstrap struct {
+	Store    Store
+	Template AssortmentTemplate
+	MinStock int
+	MaxStock int
+}
+
+// StoreBootstrapResult representa el resultado del onboarding de una tienda
+type StoreBootstrapResult struct {
+	Store            *Store       `json:"store"`
+	StoreCreated     bool         `json:"store_created"`
+	StockInitialized int          `json:"stock_initialized"`
+	StockExisting    int          `json:"stock_existing"`
+	MinStock         int          `json:"min_stock"`
+	MaxStock         int          `json:"max_stock"`
+	APIKey           *StoreAPIKey `json:"api_key"`
+	APIKeyCreated    bool         `json:"api_key_created"`
+}
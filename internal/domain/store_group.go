@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StoreGroupType tipo de agrupación de tiendas
+type StoreGroupType string
+
+const (
+	StoreGroupRegion    StoreGroupType = "REGION"
+	StoreGroupFranchise StoreGroupType = "FRANCHISE"
+)
+
+// IsValid verifica que el tipo sea uno de los soportados
+func (t StoreGroupType) IsValid() bool {
+	return t == StoreGroupRegion || t == StoreGroupFranchise
+}
+
+// StoreGroup agrupa tiendas (región, franquicia) para agregar reportes y acotar transferencias.
+//
+// Una tienda pertenece como mucho a un grupo de cada tipo, de modo que los totales por
+// región (o por franquicia) no cuentan dos veces la misma tienda. Con RestrictTransfers,
+// las transferencias que salen o llegan a una tienda del grupo deben quedarse dentro de él.
+type StoreGroup struct {
+	ID                string         `json:"id"`
+	Name              string         `json:"name"`
+	Type              StoreGroupType `json:"type"` // REGION | FRANCHISE
+	RestrictTransfers bool           `json:"restrict_transfers"`
+	StoreIDs          []string       `json:"store_ids"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// Validate verifica nombre, tipo y tiendas del grupo (normaliza y elimina tiendas duplicadas)
+func (g *StoreGroup) Validate() error {
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Name == "" {
+		return &ValidationError{Field: "name", Message: "group name is required"}
+	}
+
+	g.Type = StoreGroupType(strings.ToUpper(strings.TrimSpace(string(g.Type))))
+	if !g.Type.IsValid() {
+		return &ValidationError{Field: "type", Message: "type must be REGION or FRANCHISE"}
+	}
+
+	seen := make(map[string]bool, len(g.StoreIDs))
+	storeIDs := make([]string, 0, len(g.StoreIDs))
+	for _, storeID := range g.StoreIDs {
+		storeID = strings.TrimSpace(storeID)
+		if storeID == "" {
+			return &ValidationError{Field: "store_ids", Message: "store IDs cannot be empty"}
+		}
+		if seen[storeID] {
+			continue
+		}
+		seen[storeID] = true
+		storeIDs = append(storeIDs, storeID)
+	}
+	g.StoreIDs = storeIDs
+
+	return nil
+}
+
+// HasStore indica si la tienda pertenece al grupo
+func (g *StoreGroup) HasStore(storeID string) bool {
+	for _, id := range g.StoreIDs {
+		if id == storeID {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransferAllowed verifica que una transferencia entre dos tiendas respete los grupos
+// restringidos a los que pertenece cualquiera de ellas. groups son los grupos de ambas tiendas.
+func CheckTransferAllowed(fromStoreID, toStoreID string, groups []*StoreGroup) error {
+	for _, group := range groups {
+		if !group.RestrictTransfers {
+			continue
+		}
+		from, to := group.HasStore(fromStoreID), group.HasStore(toStoreID)
+		if from != to {
+			return &ConflictError{
+				Message: fmt.Sprintf("transfers between %s and %s are not allowed: group %s restricts transfers to its own stores", fromStoreID, toStoreID, group.ID),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// StoreHeartbeat último latido recibido de una instancia edge de tienda: cuántos eventos le
+// quedan por sincronizar con la central y cuándo sincronizó por última vez
+type StoreHeartbeat struct {
+	InstanceID    string     `json:"instance_id"`
+	StoreID       string     `json:"store_id"`
+	PendingEvents int        `json:"pending_events"`
+	LastSyncAt    *time.Time `json:"last_sync_at,omitempty"` // Última sincronización con éxito según la instancia
+	ReceivedAt    time.Time  `json:"received_at"`
+	AlertedAt     *time.Time `json:"alerted_at,omitempty"` // Última alerta de sincronización atrasada
+}
+
+// Validate verifica el latido
+func (h *StoreHeartbeat) Validate() error {
+	if h.InstanceID == "" {
+		return &ValidationError{Field: "instance_id", Message: "instance_id is required"}
+	}
+	if h.StoreID == "" {
+		return &ValidationError{Field: "store_id", Message: "store_id is required"}
+	}
+	if h.PendingEvents < 0 {
+		return &ValidationError{Field: "pending_events", Message: "pending_events must be zero or positive"}
+	}
+	return nil
+}
+
+// SyncedThrough instante hasta el que la central tiene los cambios de la instancia: sin eventos
+// pendientes está al día en el momento del latido; si no, en su última sincronización (cero si
+// nunca sincronizó)
+func (h *StoreHeartbeat) SyncedThrough() time.Time {
+	if h.PendingEvents == 0 {
+		return h.ReceivedAt
+	}
+	if h.LastSyncAt == nil {
+		return time.Time{}
+	}
+	return *h.LastSyncAt
+}
+
+// StoreSyncStatus estado de sincronización de una instancia edge
+type StoreSyncStatus struct {
+	InstanceID    string     `json:"instance_id"`
+	StoreID       string     `json:"store_id"`
+	PendingEvents int        `json:"pending_events"`
+	LastSyncAt    *time.Time `json:"last_sync_at,omitempty"`
+	LastHeartbeat time.Time  `json:"last_heartbeat"`
+	SyncedThrough *time.Time `json:"synced_through,omitempty"` // nil si nunca sincronizó con eventos pendientes
+	LagMinutes    float64    `json:"lag_minutes"`              // Minutos sin sincronizar (desde el último latido si nunca sincronizó)
+	Stale         bool       `json:"stale"`                    // Más de staleAfter sin sincronizar
+}
+
+// NewStoreSyncStatus calcula el estado de sincronización de la instancia en now
+func NewStoreSyncStatus(heartbeat *StoreHeartbeat, now time.Time, staleAfter time.Duration) *StoreSyncStatus {
+	status := &StoreSyncStatus{
+		InstanceID:    heartbeat.InstanceID,
+		StoreID:       heartbeat.StoreID,
+		PendingEvents: heartbeat.PendingEvents,
+		LastSyncAt:    heartbeat.LastSyncAt,
+		LastHeartbeat: heartbeat.ReceivedAt,
+	}
+
+	since := heartbeat.SyncedThrough()
+	if !since.IsZero() {
+		status.SyncedThrough = &since
+	} else {
+		since = heartbeat.ReceivedAt
+	}
+	lag := max(now.Sub(since), 0)
+	status.LagMinutes = lag.Minutes()
+	status.Stale = lag > staleAfter
+	return status
+}
+
+// StoreSyncReport estado de sincronización de las instancias edge, de la más atrasada a la más
+// reciente
+type StoreSyncReport struct {
+	StaleAfterMinutes float64            `json:"stale_after_minutes"`
+	Stores            []*StoreSyncStatus `json:"stores"`
+	Count             int                `json:"count"`
+	StaleCount        int                `json:"stale_count"`
+	GeneratedAt       time.Time          `json:"generated_at"`
+}
+
+// NewStoreSyncReport calcula el estado de los latidos en now (staleOnly = solo las atrasadas)
+func NewStoreSyncReport(heartbeats []*StoreHeartbeat, now time.Time, staleAfter time.Duration, staleOnly bool) *StoreSyncReport {
+	report := &StoreSyncReport{
+		StaleAfterMinutes: staleAfter.Minutes(),
+		Stores:            make([]*StoreSyncStatus, 0, len(heartbeats)),
+		GeneratedAt:       now,
+	}
+	for _, heartbeat := range heartbeats {
+		status := NewStoreSyncStatus(heartbeat, now, staleAfter)
+		if status.Stale {
+			report.StaleCount++
+		} else if staleOnly {
+			continue
+		}
+		report.Stores = append(report.Stores, status)
+	}
+	sort.SliceStable(report.Stores, func(i, j int) bool {
+		return report.Stores[i].LagMinutes > report.Stores[j].LagMinutes
+	})
+	report.Count = len(report.Stores)
+	return report
+}
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxScheduleIntervals acota el recorrido de ExpiresAt en horarios con muy pocas horas abiertas
+const maxScheduleIntervals = 1000
+
+// weekdays nombres aceptados en OpeningPeriod.Day
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// OpeningPeriod franja de apertura de un día de la semana en hora local de la tienda.
+// Un día puede tener varias franjas (p. ej. 10:00-14:00 y 17:00-21:00); no cruzan la medianoche.
+type OpeningPeriod struct {
+	Day   string `json:"day"`   // monday ... sunday
+	Open  string `json:"open"`  // HH:MM
+	Close string `json:"close"` // HH:MM, posterior a Open
+}
+
+// StoreHours horario de apertura de una tienda y corte de reservas.
+//
+// Con horario configurado las reservas solo se crean con la tienda abierta y al menos
+// CutoffMinutes antes del cierre de la franja, y el TTL solo consume minutos de apertura:
+// una reserva creada a las 20:50 con 30 minutos de TTL y cierre a las 21:00 expira 20 minutos
+// después de la siguiente apertura. Las tiendas sin horario no tienen restricciones.
+type StoreHours struct {
+	StoreID       string          `json:"store_id"`
+	Timezone      string          `json:"timezone"` // IANA, p. ej. Europe/Madrid
+	Periods       []OpeningPeriod `json:"periods"`
+	CutoffMinutes int             `json:"cutoff_minutes"` // Sin reservas nuevas en los últimos N minutos de cada franja
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// openInterval franja de apertura concreta (instantes absolutos)
+type openInterval struct {
+	start, end time.Time
+}
+
+// dayPeriod franja de un día en minutos desde la medianoche local
+type dayPeriod struct {
+	open, close int
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Validate verifica zona horaria, franjas (sin solapes) y corte
+func (h *StoreHours) Validate() error {
+	if _, err := time.LoadLocation(h.Timezone); err != nil || h.Timezone == "" {
+		return &ValidationError{Field: "timezone", Message: fmt.Sprintf("unknown timezone %q", h.Timezone)}
+	}
+	if len(h.Periods) == 0 {
+		return &ValidationError{Field: "periods", Message: "at least one opening period is required"}
+	}
+	if h.CutoffMinutes < 0 {
+		return &ValidationError{Field: "cutoff_minutes", Message: "cutoff_minutes cannot be negative"}
+	}
+
+	for i := range h.Periods {
+		h.Periods[i].Day = strings.ToLower(strings.TrimSpace(h.Periods[i].Day))
+	}
+	if _, err := h.schedule(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// schedule agrupa las franjas por día de la semana, ordenadas y sin solapes
+func (h *StoreHours) schedule() (map[time.Weekday][]dayPeriod, error) {
+	byDay := make(map[time.Weekday][]dayPeriod)
+	for i, period := range h.Periods {
+		field := fmt.Sprintf("periods[%d]", i)
+		day, ok := weekdays[period.Day]
+		if !ok {
+			return nil, &ValidationError{Field: field + ".day", Message: fmt.Sprintf("unknown day %q", period.Day)}
+		}
+		open, err := parseClock(period.Open)
+		if err != nil {
+			return nil, &ValidationError{Field: field + ".open", Message: err.Error()}
+		}
+		closeAt, err := parseClock(period.Close)
+		if err != nil {
+			return nil, &ValidationError{Field: field + ".close", Message: err.Error()}
+		}
+		if closeAt <= open {
+			return nil, &ValidationError{Field: field + ".close", Message: "close must be after open"}
+		}
+		byDay[day] = append(byDay[day], dayPeriod{open: open, close: closeAt})
+	}
+
+	for day, periods := range byDay {
+		sort.Slice(periods, func(i, j int) bool { return periods[i].open < periods[j].open })
+		for i := 1; i < len(periods); i++ {
+			if periods[i].open < periods[i-1].close {
+				return nil, &ValidationError{Field: "periods", Message: fmt.Sprintf("overlapping periods on %s", strings.ToLower(day.String()))}
+			}
+		}
+	}
+	return byDay, nil
+}
+
+// nextInterval retorna la franja que contiene t o, si está cerrada, la siguiente
+func (h *StoreHours) nextInterval(t time.Time) (openInterval, bool) {
+	loc, err := time.LoadLocation(h.Timezone)
+	if err != nil {
+		return openInterval{}, false
+	}
+	byDay, err := h.schedule()
+	if err != nil {
+		return openInterval{}, false
+	}
+
+	local := t.In(loc)
+	for offset := 0; offset <= 7; offset++ {
+		date := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, loc)
+		for _, period := range byDay[date.Weekday()] {
+			end := date.Add(time.Duration(period.close) * time.Minute)
+			if end.After(t) {
+				return openInterval{start: date.Add(time.Duration(period.open) * time.Minute), end: end}, true
+			}
+		}
+	}
+	return openInterval{}, false
+}
+
+// CheckReservable verifica que a la hora now se puedan crear reservas (tienda abierta y
+// fuera del corte previo al cierre). Retorna StoreClosedError con la siguiente apertura si no.
+func (h *StoreHours) CheckReservable(now time.Time) error {
+	interval, ok := h.nextInterval(now)
+	if !ok {
+		return &StoreClosedError{StoreID: h.StoreID}
+	}
+
+	cutoff := interval.end.Add(-time.Duration(h.CutoffMinutes) * time.Minute)
+	if !now.Before(interval.start) && now.Before(cutoff) {
+		return nil
+	}
+
+	next := interval.start
+	if !now.Before(interval.start) {
+		// Dentro del corte: la siguiente ventana es la próxima franja
+		if following, ok := h.nextInterval(interval.end); ok {
+			next = following.start
+		}
+	}
+	return &StoreClosedError{StoreID: h.StoreID, NextOpening: &next}
+}
+
+// ExpiresAt calcula el vencimiento de un TTL que solo consume tiempo con la tienda abierta
+func (h *StoreHours) ExpiresAt(now time.Time, ttl time.Duration) time.Time {
+	cursor := now
+	remaining := ttl
+	for i := 0; i < maxScheduleIntervals; i++ {
+		interval, ok := h.nextInterval(cursor)
+		if !ok {
+			break
+		}
+		if cursor.Before(interval.start) {
+			cursor = interval.start
+		}
+		available := interval.end.Sub(cursor)
+		if remaining <= available {
+			return cursor.Add(remaining)
+		}
+		remaining -= available
+		cursor = interval.end
+	}
+	return now.Add(ttl)
+}
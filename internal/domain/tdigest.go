@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultTDigestCompression compresión por defecto del t-digest: unos 100-200 centroides,
+// con error de percentiles extremos (p99) muy por debajo del 1%
+const DefaultTDigestCompression = 100
+
+// tdigestCentroid media y peso de un grupo de muestras
+type tdigestCentroid struct {
+	mean   float64
+	weight float64
+}
+
+// TDigest estima percentiles de un flujo de valores con memoria acotada (t-digest de Dunning con
+// fusión por lotes). Los centroides son más pequeños cerca de los extremos, así que p95/p99 se
+// estiman con más precisión que la mediana. No es seguro para uso concurrente.
+type TDigest struct {
+	compression float64
+	centroids   []tdigestCentroid // Ordenados por media
+	buffer      []tdigestCentroid // Valores aún no fusionados
+	count       float64
+	min, max    float64
+}
+
+// NewTDigest crea un t-digest vacío (compression <= 0 = DefaultTDigestCompression)
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = DefaultTDigestCompression
+	}
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add añade un valor
+func (d *TDigest) Add(value float64) {
+	d.addWeighted(value, 1)
+}
+
+func (d *TDigest) addWeighted(value, weight float64) {
+	d.buffer = append(d.buffer, tdigestCentroid{mean: value, weight: weight})
+	d.count += weight
+	d.min = math.Min(d.min, value)
+	d.max = math.Max(d.max, value)
+	if len(d.buffer) >= int(5*d.compression) {
+		d.flush()
+	}
+}
+
+// Merge añade los valores de other (que no se modifica)
+func (d *TDigest) Merge(other *TDigest) {
+	if other == nil || other.count == 0 {
+		return
+	}
+	for _, c := range other.centroids {
+		d.addWeighted(c.mean, c.weight)
+	}
+	for _, c := range other.buffer {
+		d.addWeighted(c.mean, c.weight)
+	}
+	d.min = math.Min(d.min, other.min)
+	d.max = math.Max(d.max, other.max)
+}
+
+// Count retorna el número de valores añadidos
+func (d *TDigest) Count() int {
+	return int(d.count)
+}
+
+// Max retorna el mayor valor añadido (0 si está vacío)
+func (d *TDigest) Max() float64 {
+	if d.count == 0 {
+		return 0
+	}
+	return d.max
+}
+
+// flush fusiona el buffer con los centroides. Dos centroides vecinos se unen mientras el peso
+// resultante no supere 4·n·q·(1-q)/compression, siendo q el cuantil de su centro.
+func (d *TDigest) flush() {
+	if len(d.buffer) == 0 {
+		return
+	}
+
+	all := make([]tdigestCentroid, 0, len(d.centroids)+len(d.buffer))
+	all = append(all, d.centroids...)
+	all = append(all, d.buffer...)
+	d.buffer = d.buffer[:0]
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := make([]tdigestCentroid, 0, len(d.centroids)+1)
+	current := all[0]
+	before := 0.0
+	for _, next := range all[1:] {
+		weight := current.weight + next.weight
+		q := (before + weight/2) / d.count
+		if weight <= 4*d.count*q*(1-q)/d.compression {
+			current.mean += (next.mean - current.mean) * next.weight / weight
+			current.weight = weight
+			continue
+		}
+		before += current.weight
+		merged = append(merged, current)
+		current = next
+	}
+	d.centroids = append(merged, current)
+}
+
+// Quantile estima el valor del cuantil q (0-1), interpolando entre los centros de los
+// centroides. Retorna 0 si está vacío.
+func (d *TDigest) Quantile(q float64) float64 {
+	if d.count == 0 {
+		return 0
+	}
+	d.flush()
+	if q <= 0 {
+		return d.min
+	}
+	if q >= 1 {
+		return d.max
+	}
+
+	index := q * d.count
+	first := d.centroids[0]
+	if index < first.weight/2 {
+		return d.min + (first.mean-d.min)*index/(first.weight/2)
+	}
+
+	center := first.weight / 2 // Posición acumulada del centro del centroide actual
+	for i := 0; i < len(d.centroids)-1; i++ {
+		current, next := d.centroids[i], d.centroids[i+1]
+		nextCenter := center + current.weight/2 + next.weight/2
+		if index < nextCenter {
+			return current.mean + (next.mean-current.mean)*(index-center)/(nextCenter-center)
+		}
+		center = nextCenter
+	}
+
+	last := d.centroids[len(d.centroids)-1]
+	remaining := d.count - center
+	if remaining <= 0 {
+		return last.mean
+	}
+	return last.mean + (d.max-last.mean)*(index-center)/remaining
+}
@@ -0,0 +1,95 @@
+package domain
+
+import "time"
+
+// TransferStatus representa el estado de una transferencia entre tiendas
+type TransferStatus string
+
+const (
+	TransferStatusDraft     TransferStatus = "DRAFT"     // Creada, pendiente de envío
+	TransferStatusCompleted TransferStatus = "COMPLETED" // Mercancía enviada a la tienda destino
+	TransferStatusCancelled TransferStatus = "CANCELLED" // Anulada (p. ej. la reserva vinculada se canceló)
+)
+
+// StockTransfer representa una transferencia de stock entre dos tiendas
+type StockTransfer struct {
+	ID            string         `json:"id" db:"id"`
+	ProductID     string         `json:"productId" db:"product_id"`
+	FromStoreID   string         `json:"fromStoreId" db:"from_store_id"`
+	ToStoreID     string         `json:"toStoreId" db:"to_store_id"`
+	Quantity      int            `json:"quantity" db:"quantity"`
+	Status        TransferStatus `json:"status" db:"status"`
+	ReservationID string         `json:"reservationId,omitempty" db:"reservation_id"` // Reserva que origina la transferencia
+	CreatedAt     time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt     *time.Time     `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+// Validate verifica que la transferencia tenga datos válidos
+func (t *StockTransfer) Validate() error {
+	if t.ProductID == "" {
+		return &ValidationError{Field: "product_id", Message: "Product ID is required"}
+	}
+	if t.FromStoreID == "" {
+		return &ValidationError{Field: "from_store_id", Message: "Source store ID is required"}
+	}
+	if t.ToStoreID == "" {
+		return &ValidationError{Field: "to_store_id", Message: "Destination store ID is required"}
+	}
+	if t.FromStoreID == t.ToStoreID {
+		return &ValidationError{Field: "to_store_id", Message: "cannot transfer to the same store"}
+	}
+	if t.Quantity <= 0 {
+		return &ValidationError{Field: "quantity", Message: "Quantity must be positive"}
+	}
+	return nil
+}
+
+// TransferReservationState representa el estado combinado de una reserva servida por transferencia
+type TransferReservationState string
+
+const (
+	TransferReservationAwaitingTransfer TransferReservationState = "AWAITING_TRANSFER" // Reserva pendiente, transferencia en borrador
+	TransferReservationFulfilled        TransferReservationState = "FULFILLED"         // Reserva confirmada y transferencia completada
+	TransferReservationCancelled        TransferReservationState = "CANCELLED"         // Reserva cancelada/expirada y transferencia anulada
+	TransferReservationOutOfSync        TransferReservationState = "OUT_OF_SYNC"       // Estados incoherentes, requiere revisión
+)
+
+// TransferReservation vincula una reserva en la tienda origen con la transferencia hacia la tienda preferida
+type TransferReservation struct {
+	Reservation      *Reservation             `json:"reservation"`
+	Transfer         *StockTransfer           `json:"transfer"`
+	PreferredStoreID string                   `json:"preferredStoreId"`
+	SourceStoreID    string                   `json:"sourceStoreId"`
+	State            TransferReservationState `json:"state"`
+}
+
+// TransferStatusFor devuelve el estado al que debe pasar la transferencia según la reserva vinculada
+func TransferStatusFor(status ReservationStatus) TransferStatus {
+	switch status {
+	case ReservationStatusConfirmed:
+		return TransferStatusCompleted
+	case ReservationStatusCancelled, ReservationStatusExpired:
+		return TransferStatusCancelled
+	default:
+		return TransferStatusDraft
+	}
+}
+
+// CombinedState calcula el estado combinado de la reserva y su transferencia
+func (tr *TransferReservation) CombinedState() TransferReservationState {
+	if tr.Reservation == nil || tr.Transfer == nil {
+		return TransferReservationOutOfSync
+	}
+	if TransferStatusFor(tr.Reservation.Status) != tr.Transfer.Status {
+		return TransferReservationOutOfSync
+	}
+
+	switch tr.Transfer.Status {
+	case TransferStatusCompleted:
+		return TransferReservationFulfilled
+	case TransferStatusCancelled:
+		return TransferReservationCancelled
+	default:
+		return TransferReservationAwaitingTransfer
+	}
+}
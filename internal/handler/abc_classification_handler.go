@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ABCClassificationHandler maneja la clasificación ABC de productos
+type ABCClassificationHandler struct {
+	classificationService *service.ABCClassificationService
+}
+
+// NewABCClassificationHandler crea un nuevo handler de clasificación ABC
+func NewABCClassificationHandler(classificationService *service.ABCClassificationService) *ABCClassificationHandler {
+	return &ABCClassificationHandler{
+		classificationService: classificationService,
+	}
+}
+
+// RunABCClassification godoc
+// @Summary Ejecutar la clasificación ABC de productos bajo demanda
+// @Description Ejecuta la misma pasada que el worker diario: ordena los productos (salvo DRAFT) por valor de consumo (unidades de reservas confirmadas en los últimos ABC_WINDOW_DAYS × precio actual) y asigna A al primer 80% del valor acumulado, B hasta el 95% y C al resto, incluidos los productos sin consumo.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} ABCClassificationRunResponse
+// @Failure 409 {object} ErrorResponse "Clasificación desactivada (ABC_WINDOW_DAYS=0)"
+// @Security ApiKeyAuth
+// @Router /admin/abc-classification [post]
+func (h *ABCClassificationHandler) RunABCClassification(c *gin.Context) {
+	run, err := h.classificationService.Classify(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	log.Printf("🔤 Classified %d products on demand (A=%d, B=%d, C=%d)", run.Products, run.ClassA, run.ClassB, run.ClassC)
+	c.JSON(http.StatusOK, run)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdjustmentReasonHandler gestiona el catálogo de motivos de ajuste de stock
+type AdjustmentReasonHandler struct {
+	reasonService *service.AdjustmentReasonService
+}
+
+// NewAdjustmentReasonHandler crea un nuevo handler de motivos de ajuste
+func NewAdjustmentReasonHandler(reasonService *service.AdjustmentReasonService) *AdjustmentReasonHandler {
+	return &AdjustmentReasonHandler{
+		reasonService: reasonService,
+	}
+}
+
+// AdjustmentReasonRequest crea o actualiza un motivo de ajuste
+type AdjustmentReasonRequest struct {
+	Description string `json:"description" binding:"required" example:"Devolución de cliente"`
+	Active      *bool  `json:"active" example:"true"` // Opcional: true por defecto
+}
+
+// ListAdjustmentReasons godoc
+// @Summary Listar los motivos de ajuste de stock
+// @Description Códigos que aceptan POST /stock/{productId}/{storeId}/adjust (obligatorio) y PUT /stock/{productId}/{storeId} (opcional)
+// @Tags stock
+// @Produce json
+// @Param include_inactive query bool false "Incluir los motivos desactivados" default(false)
+// @Success 200 {object} AdjustmentReasonListResponse
+// @Security ApiKeyAuth
+// @Router /adjustment-reasons [get]
+func (h *AdjustmentReasonHandler) ListAdjustmentReasons(c *gin.Context) {
+	reasons, err := h.reasonService.ListReasons(c.Request.Context(), c.Query("include_inactive") == "true")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reasons": reasons,
+		"count":   len(reasons),
+	})
+}
+
+// PutAdjustmentReason godoc
+// @Summary Crear o actualizar un motivo de ajuste
+// @Description El código (2-32 minúsculas, dígitos o guiones bajos) es el que se envía como reason en los ajustes
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param code path string true "Código del motivo"
+// @Param request body AdjustmentReasonRequest true "Descripción y estado"
+// @Success 200 {object} AdjustmentReasonResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/adjustment-reasons/{code} [put]
+func (h *AdjustmentReasonHandler) PutAdjustmentReason(c *gin.Context) {
+	var req AdjustmentReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	reason, err := h.reasonService.PutReason(c.Request.Context(), &domain.AdjustmentReason{
+		Code:        c.Param("code"),
+		Description: req.Description,
+		Active:      active,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reason)
+}
+
+// DeleteAdjustmentReason godoc
+// @Summary Desactivar un motivo de ajuste
+// @Description Deja de aceptarse en ajustes nuevos; los movimientos ya registrados y los reportes lo conservan
+// @Tags admin
+// @Produce json
+// @Param code path string true "Código del motivo"
+// @Success 200 {object} AdjustmentReasonResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/adjustment-reasons/{code} [delete]
+func (h *AdjustmentReasonHandler) DeleteAdjustmentReason(c *gin.Context) {
+	reason, err := h.reasonService.DeactivateReason(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reason)
+}
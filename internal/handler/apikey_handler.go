@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler maneja las peticiones HTTP de administración de API keys
+type APIKeyHandler struct {
+	usageService *service.APIKeyUsageService
+}
+
+// NewAPIKeyHandler crea un nuevo handler de API keys
+func NewAPIKeyHandler(usageService *service.APIKeyUsageService) *APIKeyHandler {
+	return &APIKeyHandler{
+		usageService: usageService,
+	}
+}
+
+// ListAPIKeys godoc
+// @Summary Listar API keys con su último uso
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	usages, err := h.usageService.ListUsage(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	candidates := 0
+	for _, usage := range usages {
+		if usage.RevocationCandidate {
+			candidates++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys":              usages,
+		"count":                 len(usages),
+		"revocation_candidates": candidates,
+	})
+}
+
+// GetAPIKeyUsage godoc
+// @Summary Uso de una API key con rollup diario
+// @Tags admin
+// @Produce json
+// @Param id path string true "API key ID"
+// @Param days query int false "Días a incluir" default(30)
+// @Success 200 {object} domain.APIKeyUsage
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/api-keys/{id}/usage [get]
+func (h *APIKeyHandler) GetAPIKeyUsage(c *gin.Context) {
+	keyID := c.Param("id")
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid days", err.Error())
+		return
+	}
+
+	usage, err := h.usageService.GetUsage(c.Request.Context(), keyID, days)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AssortmentHandler maneja las peticiones HTTP de clonación de surtido
+type AssortmentHandler struct {
+	assortmentService *service.AssortmentService
+}
+
+// NewAssortmentHandler crea un nuevo handler de surtido
+func NewAssortmentHandler(assortmentService *service.AssortmentService) *AssortmentHandler {
+	return &AssortmentHandler{
+		assortmentService: assortmentService,
+	}
+}
+
+// CloneAssortmentRequest representa el request de clonación de surtido
+type CloneAssortmentRequest struct {
+	TargetStoreIDs []string `json:"target_store_ids" binding:"required,min=1"`
+	CopyThresholds bool     `json:"copy_thresholds"`
+}
+
+// CloneAssortment godoc
+// @Summary Clonar el surtido de una tienda a otras (job asíncrono)
+// @Description Crea en las tiendas destino las filas de stock (cantidad 0) que faltan y opcionalmente copia los umbrales min/max
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Store ID origen"
+// @Param request body CloneAssortmentRequest true "Tiendas destino"
+// @Success 202 {object} domain.AssortmentCloneJob
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/clone-assortment [post]
+func (h *AssortmentHandler) CloneAssortment(c *gin.Context) {
+	var req CloneAssortmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	job, err := h.assortmentService.StartCloneJob(c.Request.Context(), c.Param("id"), req.TargetStoreIDs, req.CopyThresholds)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Location", "/api/v1/admin/assortment-jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetCloneJob godoc
+// @Summary Obtener estado y reporte de un job de clonación de surtido
+// @Tags admin
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} domain.AssortmentCloneJob
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/assortment-jobs/{id} [get]
+func (h *AssortmentHandler) GetCloneJob(c *gin.Context) {
+	job, err := h.assortmentService.GetCloneJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler maneja las peticiones HTTP de verificación de auditoría
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler crea un nuevo handler de auditoría
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// VerifyAuditChain godoc
+// @Summary Verificar la integridad de la cadena de auditoría
+// @Description Recalcula los hashes encadenados de los eventos en el tramo indicado y reporta registros modificados, reordenados o eliminados
+// @Tags admin
+// @Produce json
+// @Param from query int false "Seq inicial (default: 1)"
+// @Param to query int false "Seq final (default: último registro)"
+// @Success 200 {object} domain.AuditVerification
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/audit/verify [get]
+func (h *AuditHandler) VerifyAuditChain(c *gin.Context) {
+	from, err := strconv.ParseInt(c.DefaultQuery("from", "1"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid from", err.Error())
+		return
+	}
+	to, err := strconv.ParseInt(c.DefaultQuery("to", "0"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid to", err.Error())
+		return
+	}
+
+	result, err := h.auditService.VerifyChain(c.Request.Context(), from, to)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
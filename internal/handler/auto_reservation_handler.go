@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AutoReservationHandler maneja las reservas con selección automática de tienda
+type AutoReservationHandler struct {
+	autoReservationService *service.AutoReservationService
+	unitService            *service.ProductUnitService // Opcional: cantidades en unidades de pedido (BOX, CASE...)
+}
+
+// NewAutoReservationHandler crea un nuevo handler de reservas con selección de tienda
+func NewAutoReservationHandler(autoReservationService *service.AutoReservationService) *AutoReservationHandler {
+	return &AutoReservationHandler{
+		autoReservationService: autoReservationService,
+	}
+}
+
+// SetProductUnitService habilita cantidad + unidad en las peticiones (se convierten a la unidad base)
+func (h *AutoReservationHandler) SetProductUnitService(unitService *service.ProductUnitService) {
+	h.unitService = unitService
+}
+
+// CreateAutoReservationRequest representa la petición de reserva con selección automática de tienda
+type CreateAutoReservationRequest struct {
+	ProductID  string   `json:"product_id" binding:"required"`
+	Stores     []string `json:"stores" example:"MAD-001,BCN-001"` // Tiendas en orden de preferencia; vacía o ["any"] = cualquiera
+	AllowSplit bool     `json:"allow_split"`                      // Repartir entre tiendas si ninguna cubre la cantidad
+	CustomerID string   `json:"customer_id" binding:"required"`
+	Quantity   int      `json:"quantity" binding:"required,min=1"`
+	TTLMinutes int      `json:"ttl_minutes" binding:"omitempty,min=1"`
+	Priority   string   `json:"priority" enums:"LOW,NORMAL,HIGH"` // Opcional: NORMAL por defecto
+	Unit       string   `json:"unit" example:"BOX"`               // Opcional: unidad base del producto por defecto
+	// Opcional: forma de entrega. Limita las tiendas a los tipos de ubicación que la sirven en
+	// el canal (X-Sales-Channel) y, con "any", prueba antes los preferidos
+	Fulfillment string `json:"fulfillment" enums:"SHIP,PICKUP"`
+}
+
+// CreateAutoReservation godoc
+// @Summary Reservar en la primera tienda con disponibilidad
+// @Description Recorre stores en orden (o, con "any", todas las tiendas de más a menos disponibilidad) y reserva en la primera que cubre la cantidad. Con allow_split, si ninguna la cubre sola la reparte en ese orden; si no alcanza no se crea ninguna reserva. Con fulfillment (SHIP o PICKUP) solo se usan las ubicaciones cuyo tipo sirve esa forma de entrega en la política del canal (GET /admin/fulfillment-policies) y, con "any", primero los tipos preferidos (por defecto almacenes para envíos y tiendas para recogida).
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param request body CreateAutoReservationRequest true "Datos de la reserva"
+// @Param X-Sales-Channel header string false "Canal de venta: elige la política de fulfillment (también ?channel=)" Enums(WEB, STORE, MARKETPLACE)
+// @Success 201 {object} AutoReservationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Ninguna tienda (o su suma, con allow_split) tiene stock suficiente"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Failure 429 {object} ErrorResponse "Límite de reservas del cliente superado (anti-acaparamiento)"
+// @Security ApiKeyAuth
+// @Router /reservations/auto [post]
+func (h *AutoReservationHandler) CreateAutoReservation(c *gin.Context) {
+	var req CreateAutoReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	priority, err := domain.ParseReservationPriority(req.Priority)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	fulfillment, err := domain.ParseFulfillmentMode(req.Fulfillment)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	quantity, err := toBaseQuantity(c, h.unitService, req.ProductID, req.Quantity, req.Unit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	result, err := h.autoReservationService.CreateAutoReservation(
+		c.Request.Context(),
+		req.ProductID,
+		req.Stores,
+		req.CustomerID,
+		quantity,
+		req.TTLMinutes,
+		priority,
+		req.AllowSplit,
+		fulfillment,
+	)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AvailabilityViewHandler maneja el mantenimiento del read model de disponibilidad
+type AvailabilityViewHandler struct {
+	projector *service.AvailabilityProjector
+}
+
+// NewAvailabilityViewHandler crea un nuevo handler del read model de disponibilidad
+func NewAvailabilityViewHandler(projector *service.AvailabilityProjector) *AvailabilityViewHandler {
+	return &AvailabilityViewHandler{
+		projector: projector,
+	}
+}
+
+// RebuildAvailabilityView godoc
+// @Summary Reconstruir el read model de disponibilidad
+// @Description Vuelve a calcular availability_view (cantidad vendible y tramo por producto y tienda) desde la tabla stock. El proyector lo mantiene con los eventos y se reconstruye al arrancar; este endpoint sirve si se sospecha de un evento perdido o tras modificar stock fuera de la API.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} AvailabilityViewRebuildResponse
+// @Security ApiKeyAuth
+// @Router /admin/availability-view/rebuild [post]
+func (h *AvailabilityViewHandler) RebuildAvailabilityView(c *gin.Context) {
+	result, err := h.projector.Rebuild(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	log.Printf("📐 Availability view rebuilt on demand: %d rows (%s)", result.Rows, result.Duration)
+	c.JSON(http.StatusOK, result)
+}
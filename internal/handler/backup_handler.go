@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BackupHandler maneja los backups de la base de datos
+type BackupHandler struct {
+	backupService *service.BackupService
+}
+
+// NewBackupHandler crea un nuevo handler de backups
+func NewBackupHandler(backupService *service.BackupService) *BackupHandler {
+	return &BackupHandler{
+		backupService: backupService,
+	}
+}
+
+// CreateBackup godoc
+// @Summary Generar un backup de la base de datos
+// @Description Escribe una copia consistente de la base de datos SQLite (VACUUM INTO) en BACKUP_DIR sin detener el servicio y borra los backups que exceden BACKUP_RETAIN. Las escrituras esperan mientras se genera.
+// @Tags admin
+// @Produce json
+// @Success 201 {object} domain.Backup
+// @Failure 500 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/backups [post]
+func (h *BackupHandler) CreateBackup(c *gin.Context) {
+	backup, err := h.backupService.CreateBackup(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, backup)
+}
+
+// ListBackups godoc
+// @Summary Listar los backups de la base de datos
+// @Description Backups disponibles en BACKUP_DIR, del más reciente al más antiguo
+// @Tags admin
+// @Produce json
+// @Success 200 {array} domain.Backup
+// @Security ApiKeyAuth
+// @Router /admin/backups [get]
+func (h *BackupHandler) ListBackups(c *gin.Context) {
+	backups, err := h.backupService.ListBackups(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, backups)
+}
+
+// DownloadBackup godoc
+// @Summary Descargar un backup de la base de datos
+// @Description Descarga el fichero SQLite para guardarlo fuera del servidor; se restaura con --restore-backup
+// @Tags admin
+// @Produce application/octet-stream
+// @Param name path string true "Nombre del backup"
+// @Success 200 {file} file
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/backups/{name}/download [get]
+func (h *BackupHandler) DownloadBackup(c *gin.Context) {
+	backup, content, err := h.backupService.OpenBackup(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, backup.SizeBytes, "application/octet-stream", content, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", backup.Name),
+	})
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// bindStrictJSON decodifica el body como ShouldBindJSON, pero rechaza los campos desconocidos:
+// en los endpoints de escritura un campo mal escrito ("quantiy") se ignoraría en silencio.
+func bindStrictJSON(c *gin.Context, obj interface{}) error {
+	if c.Request.Body == nil {
+		return errors.New("invalid request")
+	}
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("request body is empty")
+		}
+		return err
+	}
+	if decoder.More() {
+		return errors.New("request body must contain a single JSON object")
+	}
+
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// respondBindError responde al error de bindStrictJSON/ShouldBindJSON: 413 si el body
+// supera MAX_REQUEST_BODY_KB (middleware.BodyLimit), 400 en otro caso
+func respondBindError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondError(c, http.StatusRequestEntityTooLarge, "Payload Too Large", fmt.Sprintf("request body cannot exceed %d bytes", tooLarge.Limit))
+		return
+	}
+	respondError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChannelAllocationHandler maneja el reparto del stock de una fila entre canales de venta
+type ChannelAllocationHandler struct {
+	channelService *service.ChannelAllocationService
+	unitService    *service.ProductUnitService // opcional: cantidades en unidades de pedido
+}
+
+// NewChannelAllocationHandler crea un nuevo handler de asignaciones por canal
+func NewChannelAllocationHandler(channelService *service.ChannelAllocationService) *ChannelAllocationHandler {
+	return &ChannelAllocationHandler{
+		channelService: channelService,
+	}
+}
+
+// SetProductUnitService habilita cantidades en unidades de pedido (quantity + unit)
+func (h *ChannelAllocationHandler) SetProductUnitService(unitService *service.ProductUnitService) {
+	h.unitService = unitService
+}
+
+// ChannelAllocationRequest representa las unidades asignadas a un canal
+type ChannelAllocationRequest struct {
+	Allocated *int   `json:"allocated" binding:"required,min=0" example:"20"`
+	Unit      string `json:"unit" example:"BOX"` // Opcional: unidad base del producto por defecto
+}
+
+// ChannelTransferRequest representa el traspaso de unidades asignadas entre canales
+type ChannelTransferRequest struct {
+	FromChannel string `json:"from_channel" binding:"required" enums:"WEB,STORE,MARKETPLACE" example:"WEB"`
+	ToChannel   string `json:"to_channel" binding:"required" enums:"WEB,STORE,MARKETPLACE" example:"MARKETPLACE"`
+	Quantity    int    `json:"quantity" binding:"required,min=1" example:"5"`
+	Unit        string `json:"unit" example:"BOX"` // Opcional: unidad base del producto por defecto
+}
+
+// GetChannelAllocations godoc
+// @Summary Asignaciones de stock por canal de venta
+// @Description unallocated son las unidades vendibles que no están asignadas (o ya se reservaron) a ningún canal: las usan los canales sin asignación y las reservas sin canal
+// @Tags stock
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Success 200 {object} ChannelAllocationsResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/channels [get]
+func (h *ChannelAllocationHandler) GetChannelAllocations(c *gin.Context) {
+	h.respondAllocations(c, c.Param("productId"), c.Param("storeId"))
+}
+
+// PutChannelAllocation godoc
+// @Summary Asignar stock a un canal de venta
+// @Description El canal solo reserva contra su asignación (X-Sales-Channel o ?channel= en reservas y disponibilidad) y el resto de canales no puede consumirla. La asignación no puede bajar de lo que el canal ya tiene reservado contra ella (409) y lo asignado sin reservar de todos los canales debe caber en la cantidad vendible (409).
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Param channel path string true "Canal de venta" Enums(WEB, STORE, MARKETPLACE)
+// @Param request body ChannelAllocationRequest true "Unidades asignadas"
+// @Success 200 {object} ChannelAllocationsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/channels/{channel} [put]
+func (h *ChannelAllocationHandler) PutChannelAllocation(c *gin.Context) {
+	productID := c.Param("productId")
+	storeID := c.Param("storeId")
+
+	var req ChannelAllocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	allocated, err := toBaseQuantity(c, h.unitService, productID, *req.Allocated, req.Unit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if err := h.channelService.SetAllocation(c.Request.Context(), productID, storeID, c.Param("channel"), allocated); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	h.respondAllocations(c, productID, storeID)
+}
+
+// DeleteChannelAllocation godoc
+// @Summary Eliminar la asignación de un canal de venta
+// @Description Las unidades sin reservar vuelven a la parte no asignada; las reservas pendientes del canal se conservan
+// @Tags stock
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Param channel path string true "Canal de venta" Enums(WEB, STORE, MARKETPLACE)
+// @Success 204 "Asignación eliminada"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/channels/{channel} [delete]
+func (h *ChannelAllocationHandler) DeleteChannelAllocation(c *gin.Context) {
+	err := h.channelService.DeleteAllocation(c.Request.Context(), c.Param("productId"), c.Param("storeId"), c.Param("channel"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// TransferChannelAllocation godoc
+// @Summary Traspasar stock asignado entre canales de venta
+// @Description Mueve unidades asignadas y sin reservar de from_channel a to_channel (crea la asignación de destino si no existe). 409 si from_channel no tiene suficientes.
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Param request body ChannelTransferRequest true "Traspaso"
+// @Success 200 {object} ChannelAllocationsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/channels/transfer [post]
+func (h *ChannelAllocationHandler) TransferChannelAllocation(c *gin.Context) {
+	productID := c.Param("productId")
+	storeID := c.Param("storeId")
+
+	var req ChannelTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	quantity, err := toBaseQuantity(c, h.unitService, productID, req.Quantity, req.Unit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	err = h.channelService.TransferAllocation(c.Request.Context(), productID, storeID, req.FromChannel, req.ToChannel, quantity)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	h.respondAllocations(c, productID, storeID)
+}
+
+// respondAllocations responde con las asignaciones actuales de la fila de stock
+func (h *ChannelAllocationHandler) respondAllocations(c *gin.Context, productID, storeID string) {
+	stock, allocations, err := h.channelService.GetAllocations(c.Request.Context(), productID, storeID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_id":  productID,
+		"store_id":    storeID,
+		"sellable":    stock.Sellable(),
+		"unallocated": domain.ChannelAvailable(stock, allocations, ""),
+		"allocations": allocations,
+		"count":       len(allocations),
+	})
+}
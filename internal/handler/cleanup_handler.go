@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CleanupHandler expone las limpiezas bajo demanda de datos históricos
+type CleanupHandler struct {
+	retentionService *service.RetentionService
+}
+
+// NewCleanupHandler crea un nuevo handler de limpieza
+func NewCleanupHandler(retentionService *service.RetentionService) *CleanupHandler {
+	return &CleanupHandler{
+		retentionService: retentionService,
+	}
+}
+
+// CleanupRequest representa una limpieza bajo demanda
+type CleanupRequest struct {
+	OlderThanDays int   `json:"older_than_days" binding:"required,min=1" example:"90"`
+	BatchSize     int   `json:"batch_size" example:"1000"` // Opcional: 1000 por defecto, máximo CLEANUP_MAX_BATCH_SIZE
+	MaxBatches    int   `json:"max_batches" example:"10"`  // Opcional: 0 = hasta terminar
+	DryRun        *bool `json:"dry_run" example:"false"`   // Opcional: true por defecto, solo cuenta
+}
+
+// RunCleanup godoc
+// @Summary Limpiar datos históricos bajo demanda
+// @Description Borra por lotes, con las reglas del worker de retención, los eventos ya publicados (solo el prefijo de la cadena de auditoría, guardando checkpoint) o las reservas terminadas anteriores a older_than_days. Por defecto es un dry run que solo cuenta las filas: hay que enviar dry_run=false para borrar. Entre lotes espera CLEANUP_BATCH_PAUSE_MS. Archiva las filas si RETENTION_ARCHIVE_ENABLED=true. Solo una limpieza a la vez por tabla.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param table path string true "Tabla" Enums(events, reservations)
+// @Param request body CleanupRequest true "Corte, tamaño de lote y modo"
+// @Success 200 {object} domain.CleanupResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Ya hay una limpieza de la tabla en curso"
+// @Security ApiKeyAuth
+// @Router /admin/cleanup/{table} [post]
+func (h *CleanupHandler) RunCleanup(c *gin.Context) {
+	var req CleanupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	cleanup := domain.CleanupRequest{
+		Table:      domain.RetentionTable(c.Param("table")),
+		OlderThan:  time.Now().AddDate(0, 0, -req.OlderThanDays),
+		BatchSize:  req.BatchSize,
+		MaxBatches: req.MaxBatches,
+		DryRun:     req.DryRun == nil || *req.DryRun,
+	}
+
+	result, err := h.retentionService.Cleanup(c.Request.Context(), cleanup)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if !result.DryRun {
+		log.Printf("🧹 Cleanup of %s older than %d days: %d deleted in %d batches, %d remaining (%s)",
+			result.Table, req.OlderThanDays, result.Deleted, result.Batches, result.Remaining, result.Duration)
+	}
+	c.JSON(http.StatusOK, result)
+}
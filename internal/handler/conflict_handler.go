@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConflictHandler maneja las peticiones HTTP de sincronización y conflictos de stock
+type ConflictHandler struct {
+	conflictService *service.ConflictService
+}
+
+// NewConflictHandler crea un nuevo handler de conflictos
+func NewConflictHandler(conflictService *service.ConflictService) *ConflictHandler {
+	return &ConflictHandler{
+		conflictService: conflictService,
+	}
+}
+
+// ApplyRemoteStockUpdate godoc
+// @Summary Aplicar un cambio de stock originado en otra instancia
+// @Tags sync
+// @Accept json
+// @Produce json
+// @Param request body domain.RemoteStockUpdate true "Cambio remoto"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /sync/stock [post]
+func (h *ConflictHandler) ApplyRemoteStockUpdate(c *gin.Context) {
+	var update domain.RemoteStockUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	conflict, err := h.conflictService.ApplyRemoteStockUpdate(c.Request.Context(), &update)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if conflict == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"applied":  true,
+			"conflict": nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"applied":  conflict.Status == domain.ConflictStatusAutoResolved,
+		"conflict": conflict,
+	})
+}
+
+// ListUnresolvedConflicts godoc
+// @Summary Listar conflictos de stock que requieren intervención manual
+// @Tags admin
+// @Produce json
+// @Param storeId query string false "Filtrar por tienda"
+// @Param limit query int false "Límite de resultados" default(50)
+// @Param offset query int false "Offset para paginación" default(0)
+// @Success 200 {array} domain.StockConflict
+// @Security ApiKeyAuth
+// @Router /admin/conflicts [get]
+func (h *ConflictHandler) ListUnresolvedConflicts(c *gin.Context) {
+	storeID := c.Query("storeId")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	conflicts, err := h.conflictService.ListUnresolved(c.Request.Context(), storeID, limit, offset)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"conflicts": conflicts,
+		"count":     len(conflicts),
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// ResolveConflictRequest representa la petición para resolver un conflicto manualmente
+type ResolveConflictRequest struct {
+	Quantity int    `json:"quantity" binding:"min=0"`
+	Reason   string `json:"reason" binding:"required"`
+}
+
+// ResolveConflict godoc
+// @Summary Resolver manualmente un conflicto de stock
+// @Description Si quantity es inferior a lo reservado se cancelan reservas pendientes empezando por las de menor prioridad (LOW, NORMAL, HIGH) y, a igual prioridad, las más recientes.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del conflicto"
+// @Param request body ResolveConflictRequest true "Cantidad definitiva"
+// @Success 200 {object} domain.StockConflict
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/conflicts/{id}/resolve [post]
+func (h *ConflictHandler) ResolveConflict(c *gin.Context) {
+	id := c.Param("id")
+
+	var req ResolveConflictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	conflict, err := h.conflictService.ResolveConflict(c.Request.Context(), id, req.Quantity, req.Reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, conflict)
+}
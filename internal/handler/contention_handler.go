@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultContentionLimit filas del informe de contención si no se indica ?limit=
+const defaultContentionLimit = 20
+
+// ContentionHandler expone las filas de stock con más conflictos de versión
+type ContentionHandler struct {
+	contentionService *service.ContentionService
+}
+
+// NewContentionHandler crea un nuevo handler de contención
+func NewContentionHandler(contentionService *service.ContentionService) *ContentionHandler {
+	return &ContentionHandler{
+		contentionService: contentionService,
+	}
+}
+
+// GetContention godoc
+// @Summary Filas de stock con más conflictos de versión
+// @Description Escrituras con optimistic locking (actualizaciones y ajustes de cantidad, transferencias entre tiendas y sincronización entre instancias) y conflictos de versión de cada fila (producto, tienda) en la ventana móvil (CONTENTION_WINDOW_MINUTES, 1 hora por defecto), calculados en el proceso. Ordenado por conflictos descendente; flash_sale indica si el producto ya usa la cola de alta concurrencia. Sirve para decidir qué productos pasar a PUT /admin/flash-sale/products/{id}.
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Máximo de filas" default(20)
+// @Success 200 {object} ContentionReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/contention [get]
+func (h *ContentionHandler) GetContention(c *gin.Context) {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if limit <= 0 {
+		limit = defaultContentionLimit
+	}
+
+	report, err := h.contentionService.Report(c.Request.Context(), time.Now(), limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"database/sql"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DebugHandler expone pprof y estadísticas de runtime para diagnosticar el servicio en producción
+type DebugHandler struct {
+	db         *sql.DB
+	instanceID string
+	startedAt  time.Time
+}
+
+// NewDebugHandler crea un nuevo handler de diagnóstico
+func NewDebugHandler(db *sql.DB, instanceID string) *DebugHandler {
+	return &DebugHandler{
+		db:         db,
+		instanceID: instanceID,
+		startedAt:  time.Now(),
+	}
+}
+
+// RuntimeStats estado del proceso: goroutines, memoria, GC y pool de conexiones de la BD
+type RuntimeStats struct {
+	InstanceID    string               `json:"instance_id" example:"api-001"`
+	GoVersion     string               `json:"go_version" example:"go1.24.0"`
+	UptimeSeconds float64              `json:"uptime_seconds" example:"3600"`
+	NumCPU        int                  `json:"num_cpu" example:"4"`
+	GOMAXPROCS    int                  `json:"gomaxprocs" example:"4"`
+	Goroutines    int                  `json:"goroutines" example:"42"`
+	Memory        RuntimeMemoryStats   `json:"memory"`
+	Database      RuntimeDatabaseStats `json:"database"`
+}
+
+// RuntimeMemoryStats subconjunto de runtime.MemStats
+type RuntimeMemoryStats struct {
+	HeapAllocBytes uint64  `json:"heap_alloc_bytes" example:"8388608"`
+	HeapInuseBytes uint64  `json:"heap_inuse_bytes" example:"10485760"`
+	HeapObjects    uint64  `json:"heap_objects" example:"52000"`
+	SysBytes       uint64  `json:"sys_bytes" example:"25165824"`
+	NumGC          uint32  `json:"num_gc" example:"120"`
+	GCPauseTotalMs float64 `json:"gc_pause_total_ms" example:"35.2"`
+	LastGCPauseMs  float64 `json:"last_gc_pause_ms" example:"0.4"`
+	GCCPUFraction  float64 `json:"gc_cpu_fraction" example:"0.002"`
+}
+
+// RuntimeDatabaseStats subconjunto de sql.DBStats
+type RuntimeDatabaseStats struct {
+	OpenConnections int     `json:"open_connections" example:"1"`
+	InUse           int     `json:"in_use" example:"1"`
+	Idle            int     `json:"idle" example:"0"`
+	WaitCount       int64   `json:"wait_count" example:"15"`
+	WaitDurationMs  float64 `json:"wait_duration_ms" example:"120.5"`
+}
+
+// RegisterDebugRoutes monta en group pprof (/pprof/), expvar (/vars) y /runtime
+func RegisterDebugRoutes(group *gin.RouterGroup, h *DebugHandler) {
+	group.GET("/pprof/", gin.WrapF(pprof.Index))
+	group.GET("/pprof/:profile", h.Profile)
+	group.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	group.GET("/vars", gin.WrapH(expvar.Handler()))
+	group.GET("/runtime", h.RuntimeStats)
+}
+
+// Profile sirve un perfil de pprof por nombre. pprof.Index solo resuelve los perfiles bajo
+// /debug/pprof/, así que con otro prefijo (p. ej. /api/v1/admin/debug) se resuelven aquí.
+func (h *DebugHandler) Profile(c *gin.Context) {
+	switch name := c.Param("profile"); name {
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		if runtimepprof.Lookup(name) == nil {
+			respondError(c, http.StatusNotFound, "Not Found", "unknown profile "+name)
+			return
+		}
+		pprof.Handler(name).ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// RuntimeStats godoc
+// @Summary Estadísticas de runtime del proceso
+// @Description Goroutines, memoria, GC y pool de conexiones de la base de datos. Solo disponible con DEBUG_ENABLED=true; junto a /admin/debug/pprof/ (perfiles para go tool pprof) y /admin/debug/vars (expvar).
+// @Tags admin
+// @Produce json
+// @Success 200 {object} RuntimeStats
+// @Security ApiKeyAuth
+// @Router /admin/debug/runtime [get]
+func (h *DebugHandler) RuntimeStats(c *gin.Context) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	dbStats := h.db.Stats()
+
+	stats := RuntimeStats{
+		InstanceID:    h.instanceID,
+		GoVersion:     runtime.Version(),
+		UptimeSeconds: time.Since(h.startedAt).Seconds(),
+		NumCPU:        runtime.NumCPU(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		Goroutines:    runtime.NumGoroutine(),
+		Memory: RuntimeMemoryStats{
+			HeapAllocBytes: mem.HeapAlloc,
+			HeapInuseBytes: mem.HeapInuse,
+			HeapObjects:    mem.HeapObjects,
+			SysBytes:       mem.Sys,
+			NumGC:          mem.NumGC,
+			GCPauseTotalMs: float64(mem.PauseTotalNs) / 1e6,
+			LastGCPauseMs:  float64(mem.PauseNs[(mem.NumGC+255)%256]) / 1e6,
+			GCCPUFraction:  mem.GCCPUFraction,
+		},
+		Database: RuntimeDatabaseStats{
+			OpenConnections: dbStats.OpenConnections,
+			InUse:           dbStats.InUse,
+			Idle:            dbStats.Idle,
+			WaitCount:       dbStats.WaitCount,
+			WaitDurationMs:  float64(dbStats.WaitDuration) / float64(time.Millisecond),
+		},
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,48 @@
+// Package dto define el formato de red de los recursos core (productos, stock y reservas)
+// desacoplado de los structs de dominio. Todos los campos usan snake_case con tags
+// explícitos: un cambio de nombre en el dominio no cambia la respuesta sin tocar este paquete.
+package dto
+
+import (
+	"inventory-system/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Convert sustituye los structs de dominio de una respuesta por sus DTOs, también dentro
+// de gin.H y slices (p. ej. {"product": ..., "created": true}). Los valores sin DTO se
+// devuelven tal cual: ya tienen tags snake_case propios.
+func Convert(data interface{}) interface{} {
+	switch value := data.(type) {
+	case *domain.Product:
+		return NewProduct(value)
+	case []*domain.Product:
+		return NewProducts(value)
+	case *domain.Stock:
+		return NewStock(value)
+	case []*domain.Stock:
+		return NewStocks(value)
+	case *domain.StockComparison:
+		return NewStockComparison(value)
+	case *domain.Reservation:
+		return NewReservation(value)
+	case []*domain.Reservation:
+		return NewReservations(value)
+	case []*domain.SerialRegistration:
+		return NewSerialRegistrations(value)
+	case gin.H:
+		out := make(gin.H, len(value))
+		for key, item := range value {
+			out[key] = Convert(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, item := range value {
+			out[i] = Convert(item)
+		}
+		return out
+	default:
+		return data
+	}
+}
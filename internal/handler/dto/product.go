@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// ProductRequest body de creación y actualización de un producto (POST/PUT /products y
+// PUT /products/sku/{sku}). Solo admite los campos editables: los calculados (abc_class,
+// fechas, imágenes) se rechazan como campos desconocidos.
+type ProductRequest struct {
+	ID          string  `json:"id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SKU         string  `json:"sku" example:"PROD-001"`
+	Name        string  `json:"name" example:"Laptop HP Pavilion 15"`
+	Description string  `json:"description" example:"Laptop de 15 pulgadas"`
+	Category    string  `json:"category" example:"electronics"`
+	Price       float64 `json:"price" example:"899.99"`
+	Status      string  `json:"status,omitempty" example:"ACTIVE"` // Solo se usa en la creación (por defecto ACTIVE)
+}
+
+// ToDomain convierte el body en el producto de dominio
+func (r *ProductRequest) ToDomain() *domain.Product {
+	return &domain.Product{
+		ID:          r.ID,
+		SKU:         r.SKU,
+		Name:        r.Name,
+		Description: r.Description,
+		Category:    r.Category,
+		Price:       r.Price,
+		Status:      domain.ProductStatus(r.Status),
+	}
+}
+
+// Product producto del catálogo
+type Product struct {
+	ID          string                 `json:"id"`
+	SKU         string                 `json:"sku"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Category    string                 `json:"category"`
+	Price       float64                `json:"price"`
+	Status      string                 `json:"status"`
+	ABCClass    string                 `json:"abc_class"` // Vacía hasta la primera clasificación
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	Media       []*domain.ProductMedia `json:"media,omitempty"`
+	Locale      string                 `json:"locale,omitempty"`
+}
+
+// NewProduct convierte un producto de dominio
+func NewProduct(product *domain.Product) *Product {
+	return &Product{
+		ID:          product.ID,
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Description: product.Description,
+		Category:    product.Category,
+		Price:       product.Price,
+		Status:      string(product.Status),
+		ABCClass:    string(product.ABCClass),
+		CreatedAt:   product.CreatedAt,
+		UpdatedAt:   product.UpdatedAt,
+		Media:       product.Media,
+		Locale:      product.Locale,
+	}
+}
+
+// NewProducts convierte un listado de productos
+func NewProducts(products []*domain.Product) []*Product {
+	out := make([]*Product, len(products))
+	for i, product := range products {
+		out[i] = NewProduct(product)
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// Reservation reserva temporal de stock
+type Reservation struct {
+	ID          string     `json:"id"`
+	ProductID   string     `json:"product_id"`
+	StoreID     string     `json:"store_id"`
+	CustomerID  string     `json:"customer_id"`
+	Quantity    int        `json:"quantity"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	Channel     string     `json:"channel,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// NewReservation convierte una reserva de dominio
+func NewReservation(reservation *domain.Reservation) *Reservation {
+	return &Reservation{
+		ID:          reservation.ID,
+		ProductID:   reservation.ProductID,
+		StoreID:     reservation.StoreID,
+		CustomerID:  reservation.CustomerID,
+		Quantity:    reservation.Quantity,
+		Status:      string(reservation.Status),
+		Priority:    string(reservation.Priority),
+		Channel:     string(reservation.Channel),
+		ExpiresAt:   reservation.ExpiresAt,
+		ConfirmedAt: reservation.ConfirmedAt,
+		CreatedAt:   reservation.CreatedAt,
+		UpdatedAt:   reservation.UpdatedAt,
+	}
+}
+
+// NewReservations convierte un listado de reservas
+func NewReservations(reservations []*domain.Reservation) []*Reservation {
+	out := make([]*Reservation, len(reservations))
+	for i, reservation := range reservations {
+		out[i] = NewReservation(reservation)
+	}
+	return out
+}
+
+// SerialRegistration número de serie vendido con su garantía
+type SerialRegistration struct {
+	ID                string     `json:"id"`
+	SerialNumber      string     `json:"serial_number"`
+	ProductID         string     `json:"product_id"`
+	StoreID           string     `json:"store_id"`
+	ReservationID     string     `json:"reservation_id"`
+	CustomerID        string     `json:"customer_id"`
+	SoldAt            time.Time  `json:"sold_at"`
+	WarrantyExpiresAt *time.Time `json:"warranty_expires_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// NewSerialRegistrations convierte los números de serie registrados en una venta
+func NewSerialRegistrations(serials []*domain.SerialRegistration) []*SerialRegistration {
+	out := make([]*SerialRegistration, len(serials))
+	for i, serial := range serials {
+		out[i] = &SerialRegistration{
+			ID:                serial.ID,
+			SerialNumber:      serial.SerialNumber,
+			ProductID:         serial.ProductID,
+			StoreID:           serial.StoreID,
+			ReservationID:     serial.ReservationID,
+			CustomerID:        serial.CustomerID,
+			SoldAt:            serial.SoldAt,
+			WarrantyExpiresAt: serial.WarrantyExpiresAt,
+			CreatedAt:         serial.CreatedAt,
+		}
+	}
+	return out
+}
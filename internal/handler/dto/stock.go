@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// Stock stock de un producto en una tienda, con la disponibilidad ya calculada
+type Stock struct {
+	ID          string    `json:"id"`
+	ProductID   string    `json:"product_id"`
+	StoreID     string    `json:"store_id"`
+	Quantity    int       `json:"quantity"`
+	Reserved    int       `json:"reserved"`  // Reservas de clientes y retenciones internas
+	Held        int       `json:"held"`      // Parte de reserved retenida para uso interno
+	Available   int       `json:"available"` // quantity - reserved
+	SafetyStock int       `json:"safety_stock"`
+	MinStock    int       `json:"min_stock"`
+	MaxStock    int       `json:"max_stock"`
+	Version     int       `json:"version"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewStock convierte una fila de stock de dominio
+func NewStock(stock *domain.Stock) *Stock {
+	return &Stock{
+		ID:          stock.ID,
+		ProductID:   stock.ProductID,
+		StoreID:     stock.StoreID,
+		Quantity:    stock.Quantity,
+		Reserved:    stock.Reserved,
+		Held:        stock.Held,
+		Available:   stock.Available(),
+		SafetyStock: stock.SafetyStock,
+		MinStock:    stock.MinStock,
+		MaxStock:    stock.MaxStock,
+		Version:     stock.Version,
+		UpdatedAt:   stock.UpdatedAt,
+	}
+}
+
+// NewStocks convierte un listado de filas de stock
+func NewStocks(stocks []*domain.Stock) []*Stock {
+	out := make([]*Stock, len(stocks))
+	for i, stock := range stocks {
+		out[i] = NewStock(stock)
+	}
+	return out
+}
+
+// StockComparison comparación de surtido y disponibilidad entre dos tiendas
+type StockComparison struct {
+	StoreA        string            `json:"store_a"`
+	StoreB        string            `json:"store_b"`
+	MinDifference int               `json:"min_difference"`
+	OnlyInA       []*Stock          `json:"only_in_a"`
+	OnlyInB       []*Stock          `json:"only_in_b"`
+	Disparities   []StockDisparity  `json:"disparities"`
+	Summary       StockCompareStats `json:"summary"`
+}
+
+// StockDisparity diferencia de disponibilidad de un producto entre las dos tiendas
+type StockDisparity struct {
+	ProductID     string `json:"product_id"`
+	AvailableA    int    `json:"available_a"`
+	AvailableB    int    `json:"available_b"`
+	Difference    int    `json:"difference"`
+	SurplusStore  string `json:"surplus_store"`
+	ShortageStore string `json:"shortage_store"`
+}
+
+// StockCompareStats resumen de la comparación
+type StockCompareStats struct {
+	Common      int `json:"common"`
+	OnlyInA     int `json:"only_in_a"`
+	OnlyInB     int `json:"only_in_b"`
+	Disparities int `json:"disparities"`
+}
+
+// NewStockComparison convierte una comparación de dominio
+func NewStockComparison(comparison *domain.StockComparison) *StockComparison {
+	out := &StockComparison{
+		StoreA:        comparison.StoreA,
+		StoreB:        comparison.StoreB,
+		MinDifference: comparison.MinDifference,
+		OnlyInA:       NewStocks(comparison.OnlyInA),
+		OnlyInB:       NewStocks(comparison.OnlyInB),
+		Disparities:   make([]StockDisparity, len(comparison.Disparities)),
+		Summary: StockCompareStats{
+			Common:      comparison.Summary.Common,
+			OnlyInA:     comparison.Summary.OnlyInA,
+			OnlyInB:     comparison.Summary.OnlyInB,
+			Disparities: comparison.Summary.Disparities,
+		},
+	}
+	for i, disparity := range comparison.Disparities {
+		out.Disparities[i] = StockDisparity{
+			ProductID:     disparity.ProductID,
+			AvailableA:    disparity.AvailableA,
+			AvailableB:    disparity.AvailableB,
+			Difference:    disparity.Difference,
+			SurplusStore:  disparity.SurplusStore,
+			ShortageStore: disparity.ShortageStore,
+		}
+	}
+	return out
+}
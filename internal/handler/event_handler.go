@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventHandler expone el ledger de eventos como listados JSON en streaming
+type EventHandler struct {
+	eventService *service.EventSyncService
+}
+
+// NewEventHandler crea un nuevo handler de eventos
+func NewEventHandler(eventService *service.EventSyncService) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+	}
+}
+
+// ListEvents godoc
+// @Summary Recorrer el ledger de eventos
+// @Description Eventos por orden de seq, sin paginar: la respuesta se escribe en streaming según se leen y "count" va al final. Para reanudar se envía como after_seq el seq del último evento recibido.
+// @Tags admin
+// @Produce json
+// @Param after_seq query int false "Solo eventos con seq mayor" default(0)
+// @Param type query string false "Tipo de evento (ej. stock.updated)"
+// @Param store_id query string false "Tienda"
+// @Param product_id query string false "Agregado (producto o reserva)"
+// @Param from query string false "Desde (RFC3339 o YYYY-MM-DD, inclusivo)"
+// @Param to query string false "Hasta (RFC3339 o YYYY-MM-DD, exclusivo)"
+// @Success 200 {object} EventListResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/events [get]
+func (h *EventHandler) ListEvents(c *gin.Context) {
+	filter, ok := eventFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	filter.EventType = c.Query("type")
+	if raw := c.Query("after_seq"); raw != "" {
+		afterSeq, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid after_seq", err.Error())
+			return
+		}
+		filter.AfterSeq = afterSeq
+	}
+
+	h.streamEvents(c, filter, "events")
+}
+
+// ListMovements godoc
+// @Summary Listar los movimientos de stock
+// @Description Movimientos del ledger (eventos de stock salvo las fotos stock.snapshot) en orden, sin paginar: la respuesta se escribe en streaming según se leen y "count" va al final. Para rangos muy grandes en CSV/XLSX existe POST /reports/exports.
+// @Tags stock
+// @Produce json
+// @Param store_id query string false "Tienda"
+// @Param product_id query string false "Producto"
+// @Param from query string false "Desde (RFC3339 o YYYY-MM-DD, inclusivo)"
+// @Param to query string false "Hasta (RFC3339 o YYYY-MM-DD, exclusivo)"
+// @Success 200 {object} MovementListResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /stock/movements [get]
+func (h *EventHandler) ListMovements(c *gin.Context) {
+	filter, ok := eventFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	filter.MovementsOnly = true
+
+	h.streamEvents(c, filter, "movements")
+}
+
+// streamEvents escribe los eventos del filtro en itemsKey (v1) o data (v2)
+func (h *EventHandler) streamEvents(c *gin.Context, filter domain.EventFilter, itemsKey string) {
+	meta := gin.H{
+		"store_id":   filter.StoreID,
+		"product_id": filter.ProductID,
+	}
+	respondStream(c, http.StatusOK, meta, meta, itemsKey, func(emit func(interface{}) error) error {
+		return h.eventService.StreamEvents(c.Request.Context(), filter, func(event *domain.Event) error {
+			return emit(event)
+		})
+	})
+}
+
+// eventFilterFromQuery lee los filtros comunes (tienda, producto y rango de fechas); responde 400 si no son válidos
+func eventFilterFromQuery(c *gin.Context) (domain.EventFilter, bool) {
+	filter := domain.EventFilter{
+		StoreID:   c.Query("store_id"),
+		ProductID: c.Query("product_id"),
+	}
+
+	var err error
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		handleError(c, err)
+		return filter, false
+	}
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		handleError(c, err)
+		return filter, false
+	}
+	return filter, true
+}
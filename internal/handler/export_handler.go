@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler maneja las exportaciones asíncronas
+type ExportHandler struct {
+	exportService *service.ExportService
+}
+
+// NewExportHandler crea un nuevo handler de exportaciones
+func NewExportHandler(exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// CreateExportRequest representa la petición de una exportación
+type CreateExportRequest struct {
+	Type    string               `json:"type" binding:"required" enums:"STOCK,MOVEMENTS,RESERVATIONS" example:"MOVEMENTS"`
+	Format  string               `json:"format" enums:"CSV,JSON" example:"CSV"` // Opcional: CSV por defecto
+	Filters domain.ExportFilters `json:"filters"`
+}
+
+// CreateExport godoc
+// @Summary Solicitar una exportación asíncrona
+// @Description Encola la exportación (STOCK: filas de stock actuales; MOVEMENTS: movimientos de stock; RESERVATIONS: reservas) y responde 202. Un worker genera el fichero; el estado se consulta en Location y, al completarse, se descarga en download_url hasta expires_at (EXPORT_RETENTION_HOURS). from/to ([from, to)) filtran por fecha en MOVEMENTS y RESERVATIONS; status solo en RESERVATIONS.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param request body CreateExportRequest true "Tipo, formato y filtros"
+// @Success 202 {object} domain.ExportJob
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reports/exports [post]
+func (h *ExportHandler) CreateExport(c *gin.Context) {
+	var req CreateExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	job, err := h.exportService.RequestExport(c.Request.Context(), &domain.ExportJob{
+		Type:    domain.ExportType(req.Type),
+		Format:  domain.ExportFormat(req.Format),
+		Filters: req.Filters,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Location", "/api/v1/reports/exports/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetExport godoc
+// @Summary Obtener el estado de una exportación
+// @Description Con status COMPLETED incluye download_url, rows y size_bytes; con FAILED el motivo en error
+// @Tags reports
+// @Produce json
+// @Param id path string true "ID de la exportación"
+// @Success 200 {object} domain.ExportJob
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reports/exports/{id} [get]
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	job, err := h.exportService.GetExport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if job.Status == domain.JobStatusCompleted {
+		job.DownloadURL = "/api/v1/reports/exports/" + job.ID + "/download"
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// DownloadExport godoc
+// @Summary Descargar el fichero de una exportación completada
+// @Tags reports
+// @Produce text/csv
+// @Produce json
+// @Param id path string true "ID de la exportación"
+// @Success 200 {file} file
+// @Failure 404 {object} ErrorResponse "No existe o ya expiró"
+// @Failure 409 {object} ErrorResponse "La exportación no está completada"
+// @Security ApiKeyAuth
+// @Router /reports/exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	job, content, err := h.exportService.OpenExport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, job.SizeBytes, job.Format.ContentType(), content, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", job.FileName()),
+	})
+}
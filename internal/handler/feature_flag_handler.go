@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureFlagHandler gestiona los feature flags
+type FeatureFlagHandler struct {
+	featureFlagService *service.FeatureFlagService
+}
+
+// NewFeatureFlagHandler crea un nuevo handler de feature flags
+func NewFeatureFlagHandler(featureFlagService *service.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		featureFlagService: featureFlagService,
+	}
+}
+
+// FeatureFlagRequest activa o desactiva una funcionalidad
+type FeatureFlagRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required" example:"false"`
+	StoreID string `json:"store_id" example:"MAD-001"` // Opcional: vacío = todas las tiendas
+}
+
+// ListFeatureFlags godoc
+// @Summary Listar feature flags
+// @Description Estado efectivo de cada funcionalidad: valor global y excepciones por tienda, con su origen (default, config o admin)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /admin/feature-flags [get]
+func (h *FeatureFlagHandler) ListFeatureFlags(c *gin.Context) {
+	flags, err := h.featureFlagService.ListFlags(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"flags": flags,
+		"count": len(flags),
+	})
+}
+
+// PutFeatureFlag godoc
+// @Summary Activar o desactivar una funcionalidad
+// @Description Sin store_id aplica a todas las tiendas; con store_id, solo a esa tienda (prevalece sobre el valor global). Prevalece sobre FEATURE_FLAGS y se aplica sin reiniciar.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param feature path string true "Funcionalidad" Enums(channel_allocation, transfer_reservations, fractional_quantities)
+// @Param request body FeatureFlagRequest true "Valor y tienda"
+// @Success 200 {object} domain.FeatureFlagRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/feature-flags/{feature} [put]
+func (h *FeatureFlagHandler) PutFeatureFlag(c *gin.Context) {
+	var req FeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	rule, err := h.featureFlagService.SetFlag(c.Request.Context(), &domain.FeatureFlagRule{
+		Feature: domain.Feature(c.Param("feature")),
+		StoreID: req.StoreID,
+		Enabled: *req.Enabled,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteFeatureFlag godoc
+// @Summary Eliminar la regla de admin de una funcionalidad
+// @Description Vuelve a aplicarse FEATURE_FLAGS (o la funcionalidad activada si no hay regla)
+// @Tags admin
+// @Param feature path string true "Funcionalidad" Enums(channel_allocation, transfer_reservations, fractional_quantities)
+// @Param store_id query string false "Tienda (vacío = regla global)"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/feature-flags/{feature} [delete]
+func (h *FeatureFlagHandler) DeleteFeatureFlag(c *gin.Context) {
+	if err := h.featureFlagService.DeleteFlag(c.Request.Context(), domain.Feature(c.Param("feature")), c.Query("store_id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FlashSaleHandler gestiona el modo alta contención (flash sale) de los productos
+type FlashSaleHandler struct {
+	flashSaleService *service.FlashSaleService
+}
+
+// NewFlashSaleHandler crea un nuevo handler de flash sale
+func NewFlashSaleHandler(flashSaleService *service.FlashSaleService) *FlashSaleHandler {
+	return &FlashSaleHandler{
+		flashSaleService: flashSaleService,
+	}
+}
+
+// ListFlashSaleProducts godoc
+// @Summary Listar productos en modo flash sale
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /admin/flash-sale/products [get]
+func (h *FlashSaleHandler) ListFlashSaleProducts(c *gin.Context) {
+	products, err := h.flashSaleService.ListProducts(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products": products,
+		"count":    len(products),
+	})
+}
+
+// EnableFlashSale godoc
+// @Summary Activar el modo flash sale de un producto
+// @Description Las reservas del producto pasan por una cola con un único writer por tienda y POST /reservations responde 202 con un ticket
+// @Tags admin
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/flash-sale/products/{id} [put]
+func (h *FlashSaleHandler) EnableFlashSale(c *gin.Context) {
+	product, err := h.flashSaleService.EnableProduct(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// DisableFlashSale godoc
+// @Summary Desactivar el modo flash sale de un producto
+// @Description Las peticiones ya encoladas se procesan igualmente
+// @Tags admin
+// @Param id path string true "ID del producto"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/flash-sale/products/{id} [delete]
+func (h *FlashSaleHandler) DisableFlashSale(c *gin.Context) {
+	if err := h.flashSaleService.DisableProduct(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FulfillmentHandler maneja las políticas de servicio (envío/recogida) por canal de venta
+type FulfillmentHandler struct {
+	fulfillmentService *service.FulfillmentService
+}
+
+// NewFulfillmentHandler crea un nuevo handler de políticas de fulfillment
+func NewFulfillmentHandler(fulfillmentService *service.FulfillmentService) *FulfillmentHandler {
+	return &FulfillmentHandler{
+		fulfillmentService: fulfillmentService,
+	}
+}
+
+// FulfillmentPolicyRequest representa los tipos de ubicación que sirven cada forma de entrega
+type FulfillmentPolicyRequest struct {
+	Ship   []domain.LocationType `json:"ship" example:"WAREHOUSE,DARKSTORE,STORE"` // En orden de preferencia; vacío = el canal no envía
+	Pickup []domain.LocationType `json:"pickup" example:"STORE,DARKSTORE"`         // En orden de preferencia; vacío = el canal no tiene recogida
+}
+
+// ListFulfillmentPolicies godoc
+// @Summary Políticas de fulfillment de los canales de venta
+// @Description Para cada canal, los tipos de ubicación (STORE, WAREHOUSE, DARKSTORE) desde los que se sirven los envíos (ship) y las recogidas (pickup), en orden de preferencia. Los canales sin política propia usan la de por defecto (default: true): envíos desde WAREHOUSE, DARKSTORE y STORE; recogidas en STORE y DARKSTORE.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} domain.FulfillmentPolicy
+// @Security ApiKeyAuth
+// @Router /admin/fulfillment-policies [get]
+func (h *FulfillmentHandler) ListFulfillmentPolicies(c *gin.Context) {
+	policies, err := h.fulfillmentService.ListPolicies(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+// GetFulfillmentPolicy godoc
+// @Summary Política de fulfillment de un canal de venta
+// @Tags admin
+// @Produce json
+// @Param channel path string true "Canal de venta" Enums(WEB, STORE, MARKETPLACE)
+// @Success 200 {object} domain.FulfillmentPolicy
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/fulfillment-policies/{channel} [get]
+func (h *FulfillmentHandler) GetFulfillmentPolicy(c *gin.Context) {
+	channel, ok := fulfillmentChannel(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.fulfillmentService.GetPolicy(c.Request.Context(), channel)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// PutFulfillmentPolicy godoc
+// @Summary Configurar la política de fulfillment de un canal de venta
+// @Description Reemplaza los tipos de ubicación desde los que el canal sirve envíos y recogidas. La disponibilidad (GET /products/{id}/availability?fulfillment=) y las reservas automáticas (POST /reservations/auto con fulfillment) del canal solo usan esos tipos, los primeros de la lista antes.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param channel path string true "Canal de venta" Enums(WEB, STORE, MARKETPLACE)
+// @Param request body FulfillmentPolicyRequest true "Tipos de ubicación por forma de entrega"
+// @Success 200 {object} domain.FulfillmentPolicy
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/fulfillment-policies/{channel} [put]
+func (h *FulfillmentHandler) PutFulfillmentPolicy(c *gin.Context) {
+	channel, ok := fulfillmentChannel(c)
+	if !ok {
+		return
+	}
+
+	var req FulfillmentPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	policy, err := h.fulfillmentService.SetPolicy(c.Request.Context(), &domain.FulfillmentPolicy{
+		Channel: channel,
+		Ship:    req.Ship,
+		Pickup:  req.Pickup,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeleteFulfillmentPolicy godoc
+// @Summary Eliminar la política de fulfillment de un canal
+// @Description El canal vuelve a la política por defecto
+// @Tags admin
+// @Param channel path string true "Canal de venta" Enums(WEB, STORE, MARKETPLACE)
+// @Success 204 "Política eliminada"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "El canal no tiene política propia"
+// @Security ApiKeyAuth
+// @Router /admin/fulfillment-policies/{channel} [delete]
+func (h *FulfillmentHandler) DeleteFulfillmentPolicy(c *gin.Context) {
+	channel, ok := fulfillmentChannel(c)
+	if !ok {
+		return
+	}
+
+	if err := h.fulfillmentService.DeletePolicy(c.Request.Context(), channel); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// fulfillmentChannel lee el canal de la ruta; responde 400 si no es válido
+func fulfillmentChannel(c *gin.Context) (domain.SalesChannel, bool) {
+	channel, err := domain.ParseSalesChannel(c.Param("channel"))
+	if err == nil && channel == "" {
+		err = &domain.ValidationError{Field: "channel", Message: "channel is required"}
+	}
+	if err != nil {
+		handleError(c, err)
+		return "", false
+	}
+	return channel, true
+}
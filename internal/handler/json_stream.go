@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jsonStreamFlushEvery cada cuántos elementos se hace Flush (el cliente y el compresor reciben
+// el listado por tramos en lugar de al final)
+const jsonStreamFlushEvery = 500
+
+// streamEach produce los elementos de un listado llamando a emit con cada uno
+type streamEach func(emit func(item interface{}) error) error
+
+// streamJSONList escribe {<head>, "<key>": [elementos...], <tail(count)>} codificando cada elemento
+// según lo produce each, sin acumular el listado en memoria. La respuesta empieza con el primer
+// elemento: si each falla antes se responde el error con handleError; si falla después la
+// respuesta queda truncada (JSON inválido) y se registra el error.
+func streamJSONList(c *gin.Context, status int, head gin.H, key string, tail func(count int) gin.H, each streamEach) {
+	stream := &jsonListStream{c: c, status: status, head: head, key: key}
+	err := each(stream.emit)
+	if err == nil && !stream.started {
+		err = stream.start()
+	}
+	if err == nil {
+		err = stream.finish(tail(stream.count))
+	}
+	if err == nil {
+		return
+	}
+
+	if !stream.started {
+		handleError(c, err)
+		return
+	}
+	log.Printf("Error streaming %s: %v", c.FullPath(), err)
+	c.Abort()
+}
+
+// jsonListStream escribe un listado JSON elemento a elemento
+type jsonListStream struct {
+	c       *gin.Context
+	status  int
+	head    gin.H
+	key     string
+	started bool
+	count   int
+}
+
+// start envía las cabeceras, los campos de head y la apertura del array
+func (s *jsonListStream) start() error {
+	s.started = true
+	s.c.Header("Content-Type", "application/json; charset=utf-8")
+	s.c.Status(s.status)
+
+	buffer := []byte{'{'}
+	buffer, err := appendFields(buffer, s.head)
+	if err != nil {
+		return err
+	}
+	if len(s.head) > 0 {
+		buffer = append(buffer, ',')
+	}
+	buffer = appendKey(buffer, s.key)
+	buffer = append(buffer, '[')
+	_, err = s.c.Writer.Write(buffer)
+	return err
+}
+
+// emit escribe un elemento del array
+func (s *jsonListStream) emit(item interface{}) error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	if s.count > 0 {
+		encoded = append([]byte{','}, encoded...)
+	}
+	if _, err := s.c.Writer.Write(encoded); err != nil {
+		return err
+	}
+
+	s.count++
+	if s.count%jsonStreamFlushEvery == 0 {
+		s.c.Writer.Flush()
+	}
+	return nil
+}
+
+// finish cierra el array y escribe los campos de tail
+func (s *jsonListStream) finish(tail gin.H) error {
+	buffer := []byte{']'}
+	if len(tail) > 0 {
+		buffer = append(buffer, ',')
+	}
+	buffer, err := appendFields(buffer, tail)
+	if err != nil {
+		return err
+	}
+	buffer = append(buffer, '}')
+	_, err = s.c.Writer.Write(buffer)
+	return err
+}
+
+// appendFields añade "clave":valor separados por comas, en orden alfabético como c.JSON
+func appendFields(buffer []byte, fields gin.H) ([]byte, error) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		value, err := json.Marshal(fields[key])
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buffer = append(buffer, ',')
+		}
+		buffer = appendKey(buffer, key)
+		buffer = append(buffer, value...)
+	}
+	return buffer, nil
+}
+
+// appendKey añade "clave":
+func appendKey(buffer []byte, key string) []byte {
+	encoded, _ := json.Marshal(key)
+	buffer = append(buffer, encoded...)
+	return append(buffer, ':')
+}
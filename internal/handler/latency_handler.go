@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LatencyHandler expone las latencias por ruta calculadas en el proceso
+type LatencyHandler struct {
+	latencyService *service.LatencyService
+}
+
+// NewLatencyHandler crea un nuevo handler de latencias
+func NewLatencyHandler(latencyService *service.LatencyService) *LatencyHandler {
+	return &LatencyHandler{
+		latencyService: latencyService,
+	}
+}
+
+// GetLatency godoc
+// @Summary Percentiles de latencia por ruta
+// @Description p50/p95/p99 de cada ruta en la ventana móvil (LATENCY_WINDOW_MINUTES), calculados en el proceso con t-digest, con el presupuesto de latencia de la ruta (LATENCY_BUDGET_MS, LATENCY_BUDGET_OVERRIDES) y cuántos requests lo superaron. Ordenado por p99 descendente. Sirve para inspeccionar una instancia sin stack de métricas.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} LatencyReportResponse
+// @Security ApiKeyAuth
+// @Router /admin/latency [get]
+func (h *LatencyHandler) GetLatency(c *gin.Context) {
+	c.JSON(http.StatusOK, h.latencyService.Report(time.Now()))
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LedgerVerificationHandler maneja la verificación de stock contra el ledger de movimientos
+type LedgerVerificationHandler struct {
+	ledgerService *service.LedgerVerificationService
+}
+
+// NewLedgerVerificationHandler crea un nuevo handler de verificación del ledger
+func NewLedgerVerificationHandler(ledgerService *service.LedgerVerificationService) *LedgerVerificationHandler {
+	return &LedgerVerificationHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+// RunLedgerVerification godoc
+// @Summary Verificar el stock contra el ledger de movimientos
+// @Description Reproduce los movimientos de cada fila de stock desde su baseline más reciente (stock.created o stock.snapshot) y lista las filas cuya quantity no coincide. Las filas sin baseline se cuentan como unverified. Es la misma pasada que el worker nocturno (LEDGER_VERIFY_INTERVAL_HOURS).
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.LedgerVerification
+// @Security ApiKeyAuth
+// @Router /admin/stock/ledger-verification [post]
+func (h *LedgerVerificationHandler) RunLedgerVerification(c *gin.Context) {
+	result, err := h.ledgerService.Verify(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	log.Printf("📒 Stock ledger verified on demand: %d rows, %d mismatches (%s)", result.Verified, len(result.Mismatches), result.Duration)
+	c.JSON(http.StatusOK, result)
+}
+
+// GetLedgerVerification godoc
+// @Summary Consultar la última verificación del ledger
+// @Description Devuelve el informe de la última verificación (worker o bajo demanda) desde que arrancó el proceso
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.LedgerVerification
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stock/ledger-verification [get]
+func (h *LedgerVerificationHandler) GetLedgerVerification(c *gin.Context) {
+	result := h.ledgerService.Last()
+	if result == nil {
+		respondError(c, http.StatusNotFound, "Not Found", "the stock ledger has not been verified since the process started")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead margen sobre el tamaño máximo de imagen para cabeceras y campos del formulario
+const multipartOverhead = 1 << 20
+
+// MediaHandler maneja las imágenes de los productos
+type MediaHandler struct {
+	mediaService *service.MediaService
+}
+
+// NewMediaHandler crea un nuevo handler de imágenes
+func NewMediaHandler(mediaService *service.MediaService) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+	}
+}
+
+// UploadMedia godoc
+// @Summary Subir una imagen de un producto
+// @Description multipart/form-data con el campo file (JPEG, PNG, WebP o GIF). El tipo se detecta por el contenido, no por la cabecera del cliente. Sin position la imagen se añade al final.
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param file formData file true "Imagen"
+// @Param alt_text formData string false "Texto alternativo"
+// @Param position formData int false "Posición (0 = imagen principal)"
+// @Success 201 {object} ProductMediaResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/media [post]
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	if maxBytes := h.mediaService.MaxUploadBytes(); maxBytes > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+multipartOverhead)
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, http.StatusRequestEntityTooLarge, "Payload Too Large", "file exceeds the maximum upload size")
+			return
+		}
+		respondError(c, http.StatusBadRequest, "Invalid request body", "multipart field 'file' is required")
+		return
+	}
+
+	upload := &domain.MediaUpload{
+		Filename: header.Filename,
+		Size:     header.Size,
+		AltText:  c.PostForm("alt_text"),
+	}
+	if raw := c.PostForm("position"); raw != "" {
+		position, err := strconv.Atoi(raw)
+		if err != nil {
+			handleError(c, &domain.ValidationError{Field: "position", Message: "position must be an integer"})
+			return
+		}
+		upload.Position = &position
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	defer file.Close()
+
+	// Detectar el tipo por los primeros bytes (la cabecera del cliente no es fiable)
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		handleError(c, err)
+		return
+	}
+	upload.ContentType = http.DetectContentType(sniff[:n])
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		handleError(c, err)
+		return
+	}
+	upload.Content = file
+
+	media, err := h.mediaService.Upload(c.Request.Context(), c.Param("id"), upload)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// ListMedia godoc
+// @Summary Listar las imágenes de un producto
+// @Tags products
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Success 200 {object} ProductMediaListResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/media [get]
+func (h *MediaHandler) ListMedia(c *gin.Context) {
+	productID := c.Param("id")
+	media, err := h.mediaService.ListMedia(c.Request.Context(), productID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_id": productID,
+		"items":      media,
+		"count":      len(media),
+	})
+}
+
+// UpdateMedia godoc
+// @Summary Cambiar el texto alternativo o la posición de una imagen
+// @Description Al cambiar position el resto de imágenes se desplazan para mantener un orden continuo
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param mediaId path string true "ID de la imagen"
+// @Param request body ProductMediaPatchRequest true "Campos a modificar"
+// @Success 200 {object} ProductMediaResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/media/{mediaId} [patch]
+func (h *MediaHandler) UpdateMedia(c *gin.Context) {
+	var patch domain.ProductMediaPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	media, err := h.mediaService.UpdateMedia(c.Request.Context(), c.Param("id"), c.Param("mediaId"), patch)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// DeleteMedia godoc
+// @Summary Eliminar una imagen de un producto
+// @Tags products
+// @Param id path string true "ID del producto"
+// @Param mediaId path string true "ID de la imagen"
+// @Success 204 "Imagen eliminada"
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/media/{mediaId} [delete]
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	if err := h.mediaService.DeleteMedia(c.Request.Context(), c.Param("id"), c.Param("mediaId")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
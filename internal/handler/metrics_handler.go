@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openMetricsContentType es el content type del formato de exposición OpenMetrics 1.0
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// MetricsHandler expone métricas de negocio en formato OpenMetrics para Prometheus
+type MetricsHandler struct {
+	reportService *service.ReportService
+	threshold     int
+	topN          int
+
+	publisherStatus  PublisherStatusProvider
+	eventSyncService *service.EventSyncService
+	retentionService *service.RetentionService
+	ledgerService    *service.LedgerVerificationService
+	contention       *service.ContentionService
+}
+
+// PublisherStatusProvider expone el estado del circuit breaker del publisher
+type PublisherStatusProvider interface {
+	Status() domain.PublisherStatus
+}
+
+// NewMetricsHandler crea un nuevo handler de métricas.
+// threshold es el umbral de disponibilidad y topN el máximo de series por scrape.
+func NewMetricsHandler(reportService *service.ReportService, threshold, topN int) *MetricsHandler {
+	return &MetricsHandler{
+		reportService: reportService,
+		threshold:     threshold,
+		topN:          topN,
+	}
+}
+
+// SetPublisherMonitoring habilita /metrics/publisher con el estado del circuit breaker
+// y los eventos pendientes del outbox
+func (h *MetricsHandler) SetPublisherMonitoring(status PublisherStatusProvider, eventSyncService *service.EventSyncService) {
+	h.publisherStatus = status
+	h.eventSyncService = eventSyncService
+}
+
+// SetRetentionService habilita /metrics/retention con las filas recuperadas por la retención
+func (h *MetricsHandler) SetRetentionService(retentionService *service.RetentionService) {
+	h.retentionService = retentionService
+}
+
+// SetLedgerVerificationService habilita /metrics/ledger con el resultado de la última verificación del ledger
+func (h *MetricsHandler) SetLedgerVerificationService(ledgerService *service.LedgerVerificationService) {
+	h.ledgerService = ledgerService
+}
+
+// SetContentionService habilita /metrics/contention con los conflictos de versión del stock
+func (h *MetricsHandler) SetContentionService(contention *service.ContentionService) {
+	h.contention = contention
+}
+
+// LowStock expone las filas de stock bajo como gauges etiquetados por producto y tienda.
+// GET /metrics/stock
+func (h *MetricsHandler) LowStock(c *gin.Context) {
+	metrics, err := h.reportService.GetLowStockMetrics(c.Request.Context(), h.threshold, h.topN)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, openMetricsContentType, []byte(FormatLowStockMetrics(metrics)))
+}
+
+// FormatLowStockMetrics serializa el snapshot de stock bajo en formato OpenMetrics
+func FormatLowStockMetrics(metrics *domain.LowStockMetrics) string {
+	var b strings.Builder
+
+	b.WriteString("# TYPE inventory_low_stock_available gauge\n")
+	b.WriteString("# HELP inventory_low_stock_available Sellable units (quantity - reserved - safety_stock) of stock rows below the low-stock threshold.\n")
+	for _, item := range metrics.Items {
+		fmt.Fprintf(&b, "inventory_low_stock_available{%s} %d\n", lowStockLabels(item), item.Available)
+	}
+
+	b.WriteString("# TYPE inventory_low_stock_reserved gauge\n")
+	b.WriteString("# HELP inventory_low_stock_reserved Reserved units of stock rows below the low-stock threshold.\n")
+	for _, item := range metrics.Items {
+		fmt.Fprintf(&b, "inventory_low_stock_reserved{%s} %d\n", lowStockLabels(item), item.Reserved)
+	}
+
+	b.WriteString("# TYPE inventory_low_stock_threshold gauge\n")
+	b.WriteString("# HELP inventory_low_stock_threshold Availability below which a stock row is considered low.\n")
+	fmt.Fprintf(&b, "inventory_low_stock_threshold %d\n", metrics.Threshold)
+
+	b.WriteString("# TYPE inventory_low_stock_rows gauge\n")
+	b.WriteString("# HELP inventory_low_stock_rows Stock rows below the threshold, including those not exported because of the top-N limit.\n")
+	fmt.Fprintf(&b, "inventory_low_stock_rows %d\n", metrics.Total)
+
+	b.WriteString("# TYPE inventory_low_stock_exported_rows gauge\n")
+	b.WriteString("# HELP inventory_low_stock_exported_rows Stock rows exported in this scrape (bounded by top-N).\n")
+	fmt.Fprintf(&b, "inventory_low_stock_exported_rows %d\n", len(metrics.Items))
+
+	b.WriteString("# EOF\n")
+	return b.String()
+}
+
+// Publisher expone el estado del circuit breaker del publisher y el tamaño del outbox.
+// GET /metrics/publisher
+func (h *MetricsHandler) Publisher(c *gin.Context) {
+	pending, err := h.eventSyncService.GetPendingEventsCount(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, openMetricsContentType, []byte(FormatPublisherMetrics(h.publisherStatus.Status(), pending)))
+}
+
+// FormatPublisherMetrics serializa el estado del publisher en formato OpenMetrics
+func FormatPublisherMetrics(status domain.PublisherStatus, pendingEvents int) string {
+	var b strings.Builder
+
+	b.WriteString("# TYPE inventory_publisher_circuit_state stateset\n")
+	b.WriteString("# HELP inventory_publisher_circuit_state Current state of the event publisher circuit breaker.\n")
+	for _, state := range []domain.CircuitState{domain.CircuitClosed, domain.CircuitOpen, domain.CircuitHalfOpen} {
+		value := 0
+		if status.State == state {
+			value = 1
+		}
+		fmt.Fprintf(&b, "inventory_publisher_circuit_state{inventory_publisher_circuit_state=\"%s\"} %d\n", state, value)
+	}
+
+	b.WriteString("# TYPE inventory_publisher_consecutive_failures gauge\n")
+	b.WriteString("# HELP inventory_publisher_consecutive_failures Consecutive publish failures counted by the circuit breaker.\n")
+	fmt.Fprintf(&b, "inventory_publisher_consecutive_failures %d\n", status.ConsecutiveFailures)
+
+	b.WriteString("# TYPE inventory_publisher_rejected counter\n")
+	b.WriteString("# HELP inventory_publisher_rejected Publishes short-circuited while the circuit was open (events kept in the outbox).\n")
+	fmt.Fprintf(&b, "inventory_publisher_rejected_total %d\n", status.RejectedTotal)
+
+	b.WriteString("# TYPE inventory_publisher_circuit_opens counter\n")
+	b.WriteString("# HELP inventory_publisher_circuit_opens Times the circuit breaker has opened.\n")
+	fmt.Fprintf(&b, "inventory_publisher_circuit_opens_total %d\n", status.OpensTotal)
+
+	b.WriteString("# TYPE inventory_outbox_pending_events gauge\n")
+	b.WriteString("# HELP inventory_outbox_pending_events Events stored but not yet published to the broker.\n")
+	fmt.Fprintf(&b, "inventory_outbox_pending_events %d\n", pendingEvents)
+
+	b.WriteString("# EOF\n")
+	return b.String()
+}
+
+// Retention expone las filas borradas y archivadas por el worker de retención.
+// GET /metrics/retention
+func (h *MetricsHandler) Retention(c *gin.Context) {
+	c.Data(http.StatusOK, openMetricsContentType, []byte(FormatRetentionMetrics(h.retentionService.Stats())))
+}
+
+// FormatRetentionMetrics serializa las estadísticas de retención en formato OpenMetrics
+func FormatRetentionMetrics(stats []domain.RetentionTableStats) string {
+	var b strings.Builder
+
+	b.WriteString("# TYPE inventory_retention_deleted_rows counter\n")
+	b.WriteString("# HELP inventory_retention_deleted_rows Rows deleted by the retention worker since the process started.\n")
+	for _, table := range stats {
+		fmt.Fprintf(&b, "inventory_retention_deleted_rows_total{table=\"%s\"} %d\n", table.Table, table.Deleted)
+	}
+
+	b.WriteString("# TYPE inventory_retention_archived_rows counter\n")
+	b.WriteString("# HELP inventory_retention_archived_rows Rows archived before deletion since the process started.\n")
+	for _, table := range stats {
+		fmt.Fprintf(&b, "inventory_retention_archived_rows_total{table=\"%s\"} %d\n", table.Table, table.Archived)
+	}
+
+	b.WriteString("# TYPE inventory_retention_archive_files counter\n")
+	b.WriteString("# HELP inventory_retention_archive_files Compressed JSONL archive files written since the process started.\n")
+	for _, table := range stats {
+		fmt.Fprintf(&b, "inventory_retention_archive_files_total{table=\"%s\"} %d\n", table.Table, table.Files)
+	}
+
+	b.WriteString("# TYPE inventory_retention_window_seconds gauge\n")
+	b.WriteString("# HELP inventory_retention_window_seconds Age after which rows of the table are deleted.\n")
+	for _, table := range stats {
+		fmt.Fprintf(&b, "inventory_retention_window_seconds{table=\"%s\"} %d\n", table.Table, int64(table.MaxAge.Seconds()))
+	}
+
+	b.WriteString("# TYPE inventory_retention_last_run_timestamp_seconds gauge\n")
+	b.WriteString("# HELP inventory_retention_last_run_timestamp_seconds Unix time of the last retention run (0 if it has not run yet).\n")
+	for _, table := range stats {
+		var lastRun int64
+		if table.LastRunAt != nil {
+			lastRun = table.LastRunAt.Unix()
+		}
+		fmt.Fprintf(&b, "inventory_retention_last_run_timestamp_seconds{table=\"%s\"} %d\n", table.Table, lastRun)
+	}
+
+	b.WriteString("# EOF\n")
+	return b.String()
+}
+
+// Ledger expone el resultado de la última verificación de quantity contra el ledger de movimientos.
+// GET /metrics/ledger
+func (h *MetricsHandler) Ledger(c *gin.Context) {
+	c.Data(http.StatusOK, openMetricsContentType, []byte(FormatLedgerMetrics(h.ledgerService.Last())))
+}
+
+// FormatLedgerMetrics serializa la última verificación del ledger en formato OpenMetrics
+// (todo a 0 si todavía no se ha ejecutado)
+func FormatLedgerMetrics(verification *domain.LedgerVerification) string {
+	var b strings.Builder
+
+	if verification == nil {
+		verification = &domain.LedgerVerification{}
+	}
+	var lastRun int64
+	if !verification.StartedAt.IsZero() {
+		lastRun = verification.StartedAt.Unix()
+	}
+
+	b.WriteString("# TYPE inventory_ledger_checked_rows gauge\n")
+	b.WriteString("# HELP inventory_ledger_checked_rows Stock rows checked by the last ledger verification.\n")
+	fmt.Fprintf(&b, "inventory_ledger_checked_rows %d\n", verification.Checked)
+
+	b.WriteString("# TYPE inventory_ledger_unverified_rows gauge\n")
+	b.WriteString("# HELP inventory_ledger_unverified_rows Stock rows without a stock.created or stock.snapshot baseline in the event ledger.\n")
+	fmt.Fprintf(&b, "inventory_ledger_unverified_rows %d\n", verification.Unverified)
+
+	b.WriteString("# TYPE inventory_ledger_mismatched_rows gauge\n")
+	b.WriteString("# HELP inventory_ledger_mismatched_rows Stock rows whose quantity does not match the replay of their movements.\n")
+	fmt.Fprintf(&b, "inventory_ledger_mismatched_rows %d\n", len(verification.Mismatches))
+
+	b.WriteString("# TYPE inventory_ledger_mismatch_difference gauge\n")
+	b.WriteString("# HELP inventory_ledger_mismatch_difference Stored quantity minus replayed quantity of each mismatched stock row.\n")
+	for _, mismatch := range verification.Mismatches {
+		fmt.Fprintf(&b, "inventory_ledger_mismatch_difference{product_id=\"%s\",store_id=\"%s\"} %d\n",
+			escapeLabelValue(mismatch.ProductID), escapeLabelValue(mismatch.StoreID), mismatch.Difference)
+	}
+
+	b.WriteString("# TYPE inventory_ledger_last_run_timestamp_seconds gauge\n")
+	b.WriteString("# HELP inventory_ledger_last_run_timestamp_seconds Unix time of the last ledger verification (0 if it has not run yet).\n")
+	fmt.Fprintf(&b, "inventory_ledger_last_run_timestamp_seconds %d\n", lastRun)
+
+	b.WriteString("# EOF\n")
+	return b.String()
+}
+
+// Contention expone las escrituras de stock con optimistic locking y sus conflictos de versión:
+// totales del proceso y las topN filas más disputadas de la ventana móvil.
+// GET /metrics/contention
+func (h *MetricsHandler) Contention(c *gin.Context) {
+	report, err := h.contention.Report(c.Request.Context(), time.Now(), h.topN)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, openMetricsContentType, []byte(FormatContentionMetrics(report)))
+}
+
+// FormatContentionMetrics serializa el informe de contención en formato OpenMetrics
+func FormatContentionMetrics(report *domain.ContentionReport) string {
+	var b strings.Builder
+
+	b.WriteString("# TYPE inventory_stock_versioned_writes counter\n")
+	b.WriteString("# HELP inventory_stock_versioned_writes Stock writes with optimistic locking since the process started, including version conflicts.\n")
+	fmt.Fprintf(&b, "inventory_stock_versioned_writes_total %d\n", report.TotalWrites)
+
+	b.WriteString("# TYPE inventory_stock_version_conflicts counter\n")
+	b.WriteString("# HELP inventory_stock_version_conflicts Stock writes rejected because the row version changed since it was read.\n")
+	fmt.Fprintf(&b, "inventory_stock_version_conflicts_total %d\n", report.TotalConflicts)
+
+	b.WriteString("# TYPE inventory_stock_contention_conflicts gauge\n")
+	b.WriteString("# HELP inventory_stock_contention_conflicts Version conflicts of the most contended stock rows in the moving window.\n")
+	for _, row := range report.Rows {
+		fmt.Fprintf(&b, "inventory_stock_contention_conflicts{product_id=\"%s\",store_id=\"%s\"} %d\n",
+			escapeLabelValue(row.ProductID), escapeLabelValue(row.StoreID), row.Conflicts)
+	}
+
+	b.WriteString("# TYPE inventory_stock_contention_conflict_ratio gauge\n")
+	b.WriteString("# HELP inventory_stock_contention_conflict_ratio Share of writes rejected by version conflicts of the most contended stock rows in the moving window.\n")
+	for _, row := range report.Rows {
+		fmt.Fprintf(&b, "inventory_stock_contention_conflict_ratio{product_id=\"%s\",store_id=\"%s\"} %g\n",
+			escapeLabelValue(row.ProductID), escapeLabelValue(row.StoreID), row.ConflictRate)
+	}
+
+	b.WriteString("# EOF\n")
+	return b.String()
+}
+
+// lowStockLabels incluye abc_class solo en los productos clasificados
+func lowStockLabels(item domain.LowStockEntry) string {
+	labels := fmt.Sprintf(`product_id="%s",sku="%s",store_id="%s"`,
+		escapeLabelValue(item.ProductID),
+		escapeLabelValue(item.SKU),
+		escapeLabelValue(item.StoreID),
+	)
+	if item.ABCClass != domain.ABCClassNone {
+		labels += fmt.Sprintf(`,abc_class="%s"`, item.ABCClass)
+	}
+	return labels
+}
+
+// escapeLabelValue escapa los caracteres especiales de un valor de label (\, " y salto de línea)
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
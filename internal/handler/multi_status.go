@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MultiStatusOutcome resultado de un elemento de una operación masiva
+type MultiStatusOutcome string
+
+const (
+	MultiStatusSucceeded MultiStatusOutcome = "SUCCEEDED" // La operación se aplicó
+	MultiStatusSkipped   MultiStatusOutcome = "SKIPPED"   // Ya estaba aplicada (repetir la petición es seguro)
+	MultiStatusFailed    MultiStatusOutcome = "FAILED"    // Rechazada (ver Code y Error); no afecta al resto
+)
+
+// MultiStatusItem resultado de un elemento de una operación masiva
+type MultiStatusItem struct {
+	Index   int                `json:"index" example:"0"`    // Posición del elemento en la petición
+	Status  int                `json:"status" example:"201"` // Código HTTP que habría respondido la operación individual
+	Outcome MultiStatusOutcome `json:"outcome" enums:"SUCCEEDED,SKIPPED,FAILED"`
+	Code    string             `json:"code,omitempty" example:"INSUFFICIENT_STOCK"` // Código estable del error (el mismo que en /api/v2)
+	Error   string             `json:"error,omitempty"`
+	Data    interface{}        `json:"data,omitempty"` // Datos del elemento propios de cada endpoint
+}
+
+// MultiStatusResponse formato común de los endpoints masivos: cada elemento se procesa por
+// separado y su resultado va en Results con el índice que tenía en la petición. Los endpoints
+// la incrustan en su respuesta para añadir sus propios campos de resumen.
+type MultiStatusResponse struct {
+	Total     int               `json:"total" example:"3"`
+	Succeeded int               `json:"succeeded" example:"2"`
+	Skipped   int               `json:"skipped" example:"0"`
+	Failed    int               `json:"failed" example:"1"`
+	Results   []MultiStatusItem `json:"results"`
+}
+
+// newMultiStatus crea una respuesta vacía con capacidad para size elementos
+func newMultiStatus(size int) MultiStatusResponse {
+	return MultiStatusResponse{Results: make([]MultiStatusItem, 0, size)}
+}
+
+// Succeed registra un elemento aplicado con el código que tendría la operación individual (200, 201...)
+func (m *MultiStatusResponse) Succeed(index, status int, data interface{}) {
+	m.Succeeded++
+	m.add(MultiStatusItem{Index: index, Status: status, Outcome: MultiStatusSucceeded, Data: data})
+}
+
+// Skip registra un elemento que ya estaba aplicado
+func (m *MultiStatusResponse) Skip(index int, data interface{}) {
+	m.Skipped++
+	m.add(MultiStatusItem{Index: index, Status: http.StatusOK, Outcome: MultiStatusSkipped, Data: data})
+}
+
+// Fail registra un elemento rechazado con el código HTTP y el código de error que handleError
+// habría respondido para err
+func (m *MultiStatusResponse) Fail(index int, err error, data interface{}) {
+	status, title := errorStatus(err)
+	m.Failed++
+	m.add(MultiStatusItem{
+		Index:   index,
+		Status:  status,
+		Outcome: MultiStatusFailed,
+		Code:    errorCode(title),
+		Error:   err.Error(),
+		Data:    data,
+	})
+}
+
+func (m *MultiStatusResponse) add(item MultiStatusItem) {
+	m.Total++
+	m.Results = append(m.Results, item)
+}
+
+// HTTPStatus 200 si ningún elemento falló y 207 Multi-Status en otro caso
+func (m *MultiStatusResponse) HTTPStatus() int {
+	if m.Failed > 0 {
+		return http.StatusMultiStatus
+	}
+	return http.StatusOK
+}
+
+// respondMultiStatus responde body (que incrusta multi) con el código de multi.HTTPStatus()
+func respondMultiStatus(c *gin.Context, multi *MultiStatusResponse, body interface{}) {
+	c.JSON(multi.HTTPStatus(), body)
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler maneja la configuración de los avisos de reservas a clientes
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationHandler crea un nuevo handler de notificaciones
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// StoreNotificationSettingsRequest representa la configuración de avisos de una tienda
+type StoreNotificationSettingsRequest struct {
+	Enabled               *bool                                                   `json:"enabled" example:"true"` // Por defecto true
+	Channels              []domain.NotificationChannel                            `json:"channels" binding:"required" example:"EMAIL,SMS"`
+	Kinds                 []domain.NotificationKind                               `json:"kinds,omitempty"`                                // Vacío = todos
+	ExpiringBeforeMinutes *int                                                    `json:"expiring_before_minutes,omitempty" example:"15"` // Por defecto 15; 0 = sin aviso de vencimiento
+	EmailFrom             string                                                  `json:"email_from,omitempty" example:"Tienda Madrid <madrid@example.com>"`
+	Templates             map[domain.NotificationKind]domain.NotificationTemplate `json:"templates,omitempty"` // Sustituyen a las plantillas por defecto
+}
+
+// defaultExpiringBeforeMinutes antelación del aviso de vencimiento si no se indica
+const defaultExpiringBeforeMinutes = 15
+
+// GetStoreNotifications godoc
+// @Summary Configuración de los avisos de reservas de una tienda
+// @Tags admin
+// @Produce json
+// @Param id path string true "Store ID"
+// @Success 200 {object} domain.StoreNotificationSettings
+// @Failure 404 {object} ErrorResponse "Tienda inexistente o sin avisos configurados"
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/notifications [get]
+func (h *NotificationHandler) GetStoreNotifications(c *gin.Context) {
+	settings, err := h.notificationService.GetSettings(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// PutStoreNotifications godoc
+// @Summary Configurar los avisos de reservas a los clientes de una tienda
+// @Description Activa los avisos al cliente cuando su reserva se crea, está a punto de caducar (expiring_before_minutes antes), caduca o se confirma, por los canales indicados (EMAIL, SMS, PUSH). Las plantillas (text/template con .StoreName, .ProductName, .Quantity, .ExpiresAt, .MinutesLeft, .ReservationID...) sustituyen a las de por defecto por tipo de aviso. Solo se avisa a los clientes con contacto en PUT /customers/{customerId}/contact.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Store ID"
+// @Param request body StoreNotificationSettingsRequest true "Canales, avisos, antelación y plantillas"
+// @Success 200 {object} domain.StoreNotificationSettings
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/notifications [put]
+func (h *NotificationHandler) PutStoreNotifications(c *gin.Context) {
+	var req StoreNotificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	settings := &domain.StoreNotificationSettings{
+		StoreID:               c.Param("id"),
+		Enabled:               true,
+		Channels:              req.Channels,
+		Kinds:                 req.Kinds,
+		ExpiringBeforeMinutes: defaultExpiringBeforeMinutes,
+		EmailFrom:             req.EmailFrom,
+		Templates:             req.Templates,
+	}
+	if req.Enabled != nil {
+		settings.Enabled = *req.Enabled
+	}
+	if req.ExpiringBeforeMinutes != nil {
+		settings.ExpiringBeforeMinutes = *req.ExpiringBeforeMinutes
+	}
+
+	settings, err := h.notificationService.SetSettings(c.Request.Context(), settings)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// DeleteStoreNotifications godoc
+// @Summary Eliminar la configuración de avisos de una tienda
+// @Description La tienda deja de enviar avisos de reservas a sus clientes
+// @Tags admin
+// @Param id path string true "Store ID"
+// @Success 204 "Configuración eliminada"
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/notifications [delete]
+func (h *NotificationHandler) DeleteStoreNotifications(c *gin.Context) {
+	if err := h.notificationService.DeleteSettings(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CustomerContactRequest representa el contacto de un cliente
+type CustomerContactRequest struct {
+	Email     string `json:"email,omitempty" example:"cliente@example.com"`
+	Phone     string `json:"phone,omitempty" example:"+34600111222"` // E.164
+	PushToken string `json:"push_token,omitempty"`
+}
+
+// GetCustomerContact godoc
+// @Summary Contacto de un cliente para los avisos de sus reservas
+// @Tags customers
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Success 200 {object} domain.CustomerContact
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /customers/{customerId}/contact [get]
+func (h *NotificationHandler) GetCustomerContact(c *gin.Context) {
+	contact, err := h.notificationService.GetContact(c.Request.Context(), c.Param("customerId"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contact)
+}
+
+// PutCustomerContact godoc
+// @Summary Guardar el contacto de un cliente
+// @Description Email, teléfono (E.164) y/o token push al que se envían los avisos de las reservas del cliente en las tiendas que los tienen activados. Reemplaza el contacto anterior.
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param customerId path string true "Customer ID"
+// @Param request body CustomerContactRequest true "Datos de contacto (al menos uno)"
+// @Success 200 {object} domain.CustomerContact
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /customers/{customerId}/contact [put]
+func (h *NotificationHandler) PutCustomerContact(c *gin.Context) {
+	var req CustomerContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	contact, err := h.notificationService.SetContact(c.Request.Context(), &domain.CustomerContact{
+		CustomerID: c.Param("customerId"),
+		Email:      req.Email,
+		Phone:      req.Phone,
+		PushToken:  req.PushToken,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contact)
+}
+
+// DeleteCustomerContact godoc
+// @Summary Eliminar el contacto de un cliente
+// @Description El cliente deja de recibir avisos de sus reservas
+// @Tags customers
+// @Param customerId path string true "Customer ID"
+// @Success 204 "Contacto eliminado"
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /customers/{customerId}/contact [delete]
+func (h *NotificationHandler) DeleteCustomerContact(c *gin.Context) {
+	if err := h.notificationService.DeleteContact(c.Request.Context(), c.Param("customerId")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListReservationNotifications godoc
+// @Summary Avisos enviados al cliente de una reserva
+// @Description Un registro por tipo de aviso y canal con su resultado: SENT, FAILED (con el error; no se reintenta) o SKIPPED (cliente sin contacto en el canal o canal sin adaptador configurado).
+// @Tags reservations
+// @Produce json
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} ReservationNotificationsResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reservations/{id}/notifications [get]
+func (h *NotificationHandler) ListReservationNotifications(c *gin.Context) {
+	id := c.Param("id")
+
+	deliveries, err := h.notificationService.ListDeliveries(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reservation_id": id,
+		"notifications":  deliveries,
+		"count":          len(deliveries),
+	})
+}
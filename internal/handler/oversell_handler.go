@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OversellHandler gestiona las tolerancias de sobreventa por tienda y por producto
+type OversellHandler struct {
+	oversellService *service.OversellService
+}
+
+// NewOversellHandler crea un nuevo handler de tolerancias de sobreventa
+func NewOversellHandler(oversellService *service.OversellService) *OversellHandler {
+	return &OversellHandler{
+		oversellService: oversellService,
+	}
+}
+
+// OversellToleranceRequest representa las unidades que la disponibilidad puede quedar bajo cero
+type OversellToleranceRequest struct {
+	MaxUnits int `json:"max_units" binding:"required,gt=0" example:"20"`
+}
+
+// ListOversellTolerances godoc
+// @Summary Listar tolerancias de sobreventa
+// @Tags admin
+// @Produce json
+// @Param scope query string false "STORE o PRODUCT"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/oversell [get]
+func (h *OversellHandler) ListOversellTolerances(c *gin.Context) {
+	scope := domain.OversellScope(strings.ToUpper(c.Query("scope")))
+	tolerances, err := h.oversellService.ListTolerances(c.Request.Context(), scope)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tolerances": tolerances,
+		"count":      len(tolerances),
+	})
+}
+
+// PutStoreOversell godoc
+// @Summary Configurar la tolerancia de sobreventa de una tienda
+// @Description Reservas, confirmaciones y ajustes de la tienda pueden dejar la disponibilidad (quantity - reserved) hasta -max_units. La tolerancia de producto prevalece.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de la tienda"
+// @Param request body OversellToleranceRequest true "Tolerancia"
+// @Success 200 {object} domain.OversellTolerance
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/oversell/stores/{id} [put]
+func (h *OversellHandler) PutStoreOversell(c *gin.Context) {
+	h.putTolerance(c, domain.OversellScopeStore)
+}
+
+// DeleteStoreOversell godoc
+// @Summary Eliminar la tolerancia de sobreventa de una tienda
+// @Tags admin
+// @Param id path string true "ID de la tienda"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/oversell/stores/{id} [delete]
+func (h *OversellHandler) DeleteStoreOversell(c *gin.Context) {
+	h.deleteTolerance(c, domain.OversellScopeStore)
+}
+
+// PutProductOversell godoc
+// @Summary Configurar la tolerancia de sobreventa de un producto
+// @Description Aplica al producto en todas las tiendas y prevalece sobre la tolerancia de la tienda
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param request body OversellToleranceRequest true "Tolerancia"
+// @Success 200 {object} domain.OversellTolerance
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/oversell/products/{id} [put]
+func (h *OversellHandler) PutProductOversell(c *gin.Context) {
+	h.putTolerance(c, domain.OversellScopeProduct)
+}
+
+// DeleteProductOversell godoc
+// @Summary Eliminar la tolerancia de sobreventa de un producto
+// @Tags admin
+// @Param id path string true "ID del producto"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/oversell/products/{id} [delete]
+func (h *OversellHandler) DeleteProductOversell(c *gin.Context) {
+	h.deleteTolerance(c, domain.OversellScopeProduct)
+}
+
+func (h *OversellHandler) putTolerance(c *gin.Context, scope domain.OversellScope) {
+	var req OversellToleranceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	tolerance, err := h.oversellService.SetTolerance(c.Request.Context(), &domain.OversellTolerance{
+		Scope:    scope,
+		ScopeID:  c.Param("id"),
+		MaxUnits: req.MaxUnits,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tolerance)
+}
+
+func (h *OversellHandler) deleteTolerance(c *gin.Context, scope domain.OversellScope) {
+	if err := h.oversellService.DeleteTolerance(c.Request.Context(), scope, c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"strings"
+
+	"inventory-system/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ValidatePathParams rechaza con 400 los IDs de producto y tienda de la ruta con formato
+// inválido antes de llegar al handler, para que un valor como "non-existent/MAD-001" no
+// llegue a consultar la BD. Se registra después de UseSerializer para responder en el
+// formato de la versión de la API.
+func ValidatePathParams() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, param := range c.Params {
+			if err := validatePathParam(c.FullPath(), param); err != nil {
+				handleError(c, err)
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// validatePathParam valida un parámetro según su nombre (:productId, :storeId) o, para :id,
+// según el recurso que le precede en la ruta (/products/:id, /stores/:id)
+func validatePathParam(fullPath string, param gin.Param) error {
+	switch param.Key {
+	case "productId":
+		return domain.ValidateProductID(param.Key, param.Value)
+	case "storeId":
+		return domain.ValidateStoreID(param.Key, param.Value)
+	case "id":
+		switch pathParamResource(fullPath, param.Key) {
+		case "products":
+			return domain.ValidateProductID(param.Key, param.Value)
+		case "stores":
+			return domain.ValidateStoreID(param.Key, param.Value)
+		}
+	}
+	return nil
+}
+
+// pathParamResource segmento de la ruta anterior al parámetro (p. ej. "products" en
+// /api/v1/products/:id/media)
+func pathParamResource(fullPath, key string) string {
+	segments := strings.Split(fullPath, "/")
+	for i := 1; i < len(segments); i++ {
+		if segments[i] == ":"+key {
+			return segments[i-1]
+		}
+	}
+	return ""
+}
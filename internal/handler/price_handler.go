@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PriceHandler maneja el historial de precios y los cambios programados
+type PriceHandler struct {
+	priceService *service.PriceService
+}
+
+// NewPriceHandler crea un nuevo handler de precios
+func NewPriceHandler(priceService *service.PriceService) *PriceHandler {
+	return &PriceHandler{
+		priceService: priceService,
+	}
+}
+
+// GetPriceHistory godoc
+// @Summary Historial de precios de un producto
+// @Description Cambios manuales (PUT /products/:id) y programados, del más reciente al más antiguo, con el autor (nombre de la API key)
+// @Tags products
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param limit query int false "Límite de resultados (máx. 500)" default(50)
+// @Param offset query int false "Offset para paginación" default(0)
+// @Success 200 {object} PriceHistoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/price-history [get]
+func (h *PriceHandler) GetPriceHistory(c *gin.Context) {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if limit <= 0 {
+		limit = domain.DefaultPriceHistoryLimit
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	productID := c.Param("id")
+	history, total, err := h.priceService.GetPriceHistory(c.Request.Context(), productID, limit, offset)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_id": productID,
+		"items":      history,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+// SchedulePriceChangeRequest representa la petición para programar un cambio de precio
+type SchedulePriceChangeRequest struct {
+	Price       *float64  `json:"price" binding:"required" example:"799.99"`
+	EffectiveAt time.Time `json:"effective_at" binding:"required" example:"2026-12-01T00:00:00Z"` // RFC3339, debe ser futuro
+}
+
+// SchedulePriceChange godoc
+// @Summary Programar un cambio de precio
+// @Description Un worker aplica el precio al llegar effective_at y lo registra en el historial con el autor que lo programó
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param request body SchedulePriceChangeRequest true "Nuevo precio y fecha efectiva"
+// @Success 201 {object} ScheduledPriceChangeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/scheduled-prices [post]
+func (h *PriceHandler) SchedulePriceChange(c *gin.Context) {
+	var req SchedulePriceChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	schedule, err := h.priceService.SchedulePriceChange(c.Request.Context(), c.Param("id"), *req.Price, req.EffectiveAt)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// ListScheduledPriceChanges godoc
+// @Summary Listar los cambios de precio programados de un producto
+// @Tags products
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Success 200 {object} ScheduledPriceChangeListResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/scheduled-prices [get]
+func (h *PriceHandler) ListScheduledPriceChanges(c *gin.Context) {
+	productID := c.Param("id")
+	schedules, err := h.priceService.ListScheduledPriceChanges(c.Request.Context(), productID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_id": productID,
+		"items":      schedules,
+		"count":      len(schedules),
+	})
+}
+
+// CancelScheduledPriceChange godoc
+// @Summary Cancelar un cambio de precio programado pendiente
+// @Tags products
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param scheduleId path string true "ID del cambio programado"
+// @Success 200 {object} ScheduledPriceChangeResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "El cambio ya se aplicó o se canceló"
+// @Security ApiKeyAuth
+// @Router /products/{id}/scheduled-prices/{scheduleId} [delete]
+func (h *PriceHandler) CancelScheduledPriceChange(c *gin.Context) {
+	schedule, err := h.priceService.CancelScheduledPriceChange(c.Request.Context(), c.Param("id"), c.Param("scheduleId"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
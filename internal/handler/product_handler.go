@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"inventory-system/internal/domain"
+	"inventory-system/internal/handler/dto"
 	"inventory-system/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -13,7 +18,8 @@ import (
 
 // ProductHandler maneja las peticiones HTTP para productos
 type ProductHandler struct {
-	productService *service.ProductService
+	productService     *service.ProductService
+	translationService *service.TranslationService
 }
 
 // NewProductHandler crea un nuevo handler de productos
@@ -23,39 +29,55 @@ func NewProductHandler(productService *service.ProductService) *ProductHandler {
 	}
 }
 
+// SetTranslationService habilita la localización de las lecturas (?locale= / Accept-Language)
+func (h *ProductHandler) SetTranslationService(translationService *service.TranslationService) {
+	h.translationService = translationService
+}
+
+// localize traduce los productos al idioma de la petición y lo anuncia en Content-Language
+func (h *ProductHandler) localize(c *gin.Context, products ...*domain.Product) error {
+	if h.translationService == nil {
+		return nil
+	}
+
+	locale := h.translationService.Policy().Resolve(c.Query("locale"), c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", locale)
+	c.Header("Vary", "Accept-Language")
+	return h.translationService.Localize(c.Request.Context(), locale, products...)
+}
+
 // CreateProduct godoc
 // @Summary Crear un nuevo producto
 // @Tags products
 // @Accept json
 // @Produce json
-// @Param product body domain.Product true "Producto a crear"
-// @Success 201 {object} domain.Product
+// @Param product body ProductRequest true "Producto a crear"
+// @Success 201 {object} ProductResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /products [post]
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
-	var product domain.Product
-
-	if err := c.ShouldBindJSON(&product); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request body",
-			Message: err.Error(),
-		})
+	var req dto.ProductRequest
+	if err := bindStrictJSON(c, &req); err != nil {
+		respondBindError(c, err)
 		return
 	}
+	product := req.ToDomain()
 
 	// Generar ID con UUID real si no viene
 	if product.ID == "" {
 		product.ID = uuid.New().String()
 	}
 
-	created, err := h.productService.CreateProduct(c.Request.Context(), &product)
+	created, err := h.productService.CreateProduct(c.Request.Context(), product)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, created)
+	respond(c, http.StatusCreated, created)
 }
 
 // GetProduct godoc
@@ -63,7 +85,9 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 // @Tags products
 // @Produce json
 // @Param id path string true "ID del producto"
-// @Success 200 {object} domain.Product
+// @Param locale query string false "Idioma (es, ca, en); tiene prioridad sobre Accept-Language"
+// @Param Accept-Language header string false "Idioma preferido; sin traducción se usa el idioma por defecto"
+// @Success 200 {object} ProductResponse
 // @Failure 404 {object} ErrorResponse
 // @Router /products/{id} [get]
 func (h *ProductHandler) GetProduct(c *gin.Context) {
@@ -74,8 +98,12 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		handleError(c, err)
 		return
 	}
+	if err := h.localize(c, product); err != nil {
+		handleError(c, err)
+		return
+	}
 
-	c.JSON(http.StatusOK, product)
+	respond(c, http.StatusOK, product)
 }
 
 // ListProducts godoc
@@ -85,31 +113,57 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 // @Param limit query int false "Límite de resultados" default(10)
 // @Param offset query int false "Offset para paginación" default(0)
 // @Param category query string false "Filtrar por categoría"
-// @Success 200 {array} domain.Product
+// @Param abc_class query string false "Filtrar por clase ABC" Enums(A, B, C)
+// @Param include_discontinued query bool false "Incluir productos DISCONTINUED (ocultos por defecto)"
+// @Param locale query string false "Idioma (es, ca, en); tiene prioridad sobre Accept-Language"
+// @Param Accept-Language header string false "Idioma preferido; sin traducción se usa el idioma por defecto"
+// @Success 200 {object} ProductListResponse
+// @Failure 400 {object} ErrorResponse
 // @Router /products [get]
 func (h *ProductHandler) ListProducts(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	category := c.Query("category")
 
+	includeDiscontinued, err := strconv.ParseBool(c.DefaultQuery("include_discontinued", "false"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid include_discontinued", "include_discontinued must be true or false")
+		return
+	}
+
+	abcClass, err := domain.ParseABCClass(c.Query("abc_class"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
 	var products []*domain.Product
-	var err error
 
-	if category != "" {
-		products, err = h.productService.ListProductsByCategory(c.Request.Context(), category, limit, offset)
+	if abcClass != domain.ABCClassNone {
+		products, err = h.productService.ListProductsByABCClass(c.Request.Context(), abcClass, category, limit, offset, includeDiscontinued)
+	} else if category != "" {
+		products, err = h.productService.ListProductsByCategory(c.Request.Context(), category, limit, offset, includeDiscontinued)
 	} else {
-		products, err = h.productService.ListProducts(c.Request.Context(), limit, offset)
+		products, err = h.productService.ListProducts(c.Request.Context(), limit, offset, includeDiscontinued)
 	}
 
+	if err == nil {
+		err = h.localize(c, products...)
+	}
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
 	// Get total count
-	total, _ := h.productService.CountProducts(c.Request.Context())
+	total, _ := h.productService.CountProducts(c.Request.Context(), includeDiscontinued)
 
-	c.JSON(http.StatusOK, gin.H{
+	meta := gin.H{
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	}
+	respondList(c, http.StatusOK, products, meta, gin.H{
 		"data":   products,
 		"total":  total,
 		"limit":  limit,
@@ -117,65 +171,234 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 	})
 }
 
+// SearchProducts godoc
+// @Summary Buscar productos
+// @Description Busca en nombre, descripción, SKU y categoría. Con SEARCH_BACKEND=elasticsearch o meilisearch consulta el índice externo (resultados por relevancia, tolerante a erratas); sin él hace una búsqueda por subcadena en la BD. Los productos descatalogados no aparecen.
+// @Tags products
+// @Produce json
+// @Param q query string true "Texto a buscar"
+// @Param limit query int false "Límite de resultados" default(10)
+// @Param offset query int false "Offset para paginación" default(0)
+// @Param locale query string false "Idioma (es, ca, en); tiene prioridad sobre Accept-Language"
+// @Param Accept-Language header string false "Idioma preferido; sin traducción se usa el idioma por defecto"
+// @Success 200 {object} ProductListResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /products/search [get]
+func (h *ProductHandler) SearchProducts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	query := c.Query("q")
+
+	products, err := h.productService.SearchProducts(c.Request.Context(), query, limit, offset)
+	if err == nil {
+		err = h.localize(c, products...)
+	}
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	meta := gin.H{
+		"query":  query,
+		"limit":  limit,
+		"offset": offset,
+	}
+	respondList(c, http.StatusOK, products, meta, gin.H{
+		"data":   products,
+		"query":  query,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
 // UpdateProduct godoc
 // @Summary Actualizar un producto
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path string true "ID del producto"
-// @Param product body domain.Product true "Datos del producto"
-// @Success 200 {object} domain.Product
+// @Param product body ProductRequest true "Datos del producto"
+// @Success 200 {object} ProductResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	id := c.Param("id")
 
-	var product domain.Product
-	if err := c.ShouldBindJSON(&product); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request body",
-			Message: err.Error(),
-		})
+	var req dto.ProductRequest
+	if err := bindStrictJSON(c, &req); err != nil {
+		respondBindError(c, err)
 		return
 	}
-
+	product := req.ToDomain()
 	product.ID = id
 
-	updated, err := h.productService.UpdateProduct(c.Request.Context(), &product)
+	updated, err := h.productService.UpdateProduct(c.Request.Context(), product)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, updated)
+}
+
+// UpsertProductBySKU godoc
+// @Summary Crear o actualizar un producto por SKU
+// @Description Pensado para sincronizaciones de catálogo (ERP): si el SKU no existe se crea el producto (201),
+// @Description si existe se actualiza con el body completo (200). "created" indica cuál de los dos ocurrió.
+// @Description El SKU del body es opcional y, si se envía, debe coincidir con el de la ruta.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param sku path string true "SKU del producto"
+// @Param product body ProductRequest true "Datos del producto"
+// @Success 200 {object} ProductUpsertResponse "Actualizado"
+// @Success 201 {object} ProductUpsertResponse "Creado"
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/sku/{sku} [put]
+func (h *ProductHandler) UpsertProductBySKU(c *gin.Context) {
+	var req dto.ProductRequest
+	if err := bindStrictJSON(c, &req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	upserted, created, err := h.productService.UpsertProductBySKU(c.Request.Context(), c.Param("sku"), req.ToDomain())
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, updated)
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	respond(c, status, gin.H{
+		"created": created,
+		"product": upserted,
+	})
+}
+
+// PatchProduct godoc
+// @Summary Actualizar parcialmente un producto
+// @Description Solo se modifican los campos presentes en el body (los omitidos o null conservan su valor).
+// @Description Un cambio de SKU se normaliza y valida igual que en PUT. El estado se cambia con PUT /products/{id}/status.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param product body ProductPatchRequest true "Campos a modificar"
+// @Success 200 {object} ProductResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "SKU duplicado"
+// @Security ApiKeyAuth
+// @Router /products/{id} [patch]
+func (h *ProductHandler) PatchProduct(c *gin.Context) {
+	var patch domain.ProductPatch
+	if err := bindStrictJSON(c, &patch); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	updated, err := h.productService.PatchProduct(c.Request.Context(), c.Param("id"), patch)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, updated)
 }
 
 // DeleteProduct godoc
 // @Summary Eliminar un producto
+// @Description Rechaza la eliminación (409 con el detalle en "details") si el producto tiene stock o reservas pendientes.
+// @Description Con force=true elimina igualmente; en ambos casos el stock y las reservas se archivan en lugar de borrarse en cascada.
 // @Tags products
+// @Produce json
 // @Param id path string true "ID del producto"
+// @Param force query bool false "Eliminar aunque tenga stock o reservas pendientes"
+// @Success 200 {object} ProductArchiveResponse "Con force=true"
 // @Success 204
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ProductInUseErrorResponse
+// @Security ApiKeyAuth
 // @Router /products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	id := c.Param("id")
 
-	err := h.productService.DeleteProduct(c.Request.Context(), id)
+	force, err := strconv.ParseBool(c.DefaultQuery("force", "false"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid force", "force must be true or false")
+		return
+	}
+
+	archive, err := h.productService.DeleteProduct(c.Request.Context(), id, force)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
+	if force {
+		respond(c, http.StatusOK, archive)
+		return
+	}
 	c.Status(http.StatusNoContent)
 }
 
+// ChangeProductStatusRequest representa la petición de cambio de estado de un producto
+type ChangeProductStatusRequest struct {
+	Status string `json:"status" binding:"required" example:"ACTIVE"` // DRAFT, ACTIVE o DISCONTINUED
+	Reason string `json:"reason" example:"lanzamiento"`               // Opcional: motivo del cambio
+}
+
+// ChangeProductStatus godoc
+// @Summary Cambiar el estado del ciclo de vida de un producto
+// @Description Transiciones permitidas: DRAFT → ACTIVE | DISCONTINUED, ACTIVE → DISCONTINUED, DISCONTINUED → ACTIVE.
+// @Description Solo los productos ACTIVE admiten inicializar stock y crear reservas. Emite product.status_changed.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param request body ChangeProductStatusRequest true "Nuevo estado"
+// @Success 200 {object} ProductResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Transición no permitida"
+// @Security ApiKeyAuth
+// @Router /products/{id}/status [put]
+func (h *ProductHandler) ChangeProductStatus(c *gin.Context) {
+	var req ChangeProductStatusRequest
+	if err := bindStrictJSON(c, &req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	status := domain.ProductStatus(strings.ToUpper(req.Status))
+	product, err := h.productService.ChangeStatus(c.Request.Context(), c.Param("id"), status, req.Reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, product)
+}
+
 // GetProductBySKU godoc
 // @Summary Buscar producto por SKU
 // @Tags products
 // @Produce json
 // @Param sku path string true "SKU del producto"
-// @Success 200 {object} domain.Product
+// @Param locale query string false "Idioma (es, ca, en); tiene prioridad sobre Accept-Language"
+// @Param Accept-Language header string false "Idioma preferido; sin traducción se usa el idioma por defecto"
+// @Success 200 {object} ProductResponse
 // @Failure 404 {object} ErrorResponse
 // @Router /products/sku/{sku} [get]
 func (h *ProductHandler) GetProductBySKU(c *gin.Context) {
@@ -186,53 +409,94 @@ func (h *ProductHandler) GetProductBySKU(c *gin.Context) {
 		handleError(c, err)
 		return
 	}
+	if err := h.localize(c, product); err != nil {
+		handleError(c, err)
+		return
+	}
 
-	c.JSON(http.StatusOK, product)
+	respond(c, http.StatusOK, product)
 }
 
 // ErrorResponse representa una respuesta de error
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
+	Error     string      `json:"error"`
+	Message   string      `json:"message,omitempty"`
+	RequestID string      `json:"request_id,omitempty"` // X-Request-ID, para correlacionar con logs y eventos
+	Details   interface{} `json:"details,omitempty"`    // Contexto adicional (p. ej. dependencias de un producto en uso)
 }
 
 // handleError maneja errores de dominio y los convierte en respuestas HTTP
 func handleError(c *gin.Context, err error) {
-	switch e := err.(type) {
+	// El deadline de middleware.Timeout venció: el error suele ser el del driver al cancelar la consulta
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		log.Printf("⏱️  %s %s timed out (request_id=%s): %v", c.Request.Method, c.FullPath(), domain.CorrelationIDFromContext(c.Request.Context()), err)
+		respondError(c, http.StatusGatewayTimeout, "Gateway Timeout", "request exceeded its time limit")
+		return
+	}
+
+	status, title := errorStatus(err)
+	domainErr, _ := domain.AsDomainError(err)
+	switch e := domainErr.(type) {
+	case *domain.ProductInUseError:
+		respondErrorDetails(c, status, title, e.Error(), e.Dependencies)
+		return
+	case *domain.InsufficientStockError:
+		if e.Shortage != nil {
+			respondErrorDetails(c, status, title, e.Error(), e.Shortage)
+			return
+		}
+	case *domain.StoreClosedError:
+		respondErrorDetails(c, status, title, e.Error(), gin.H{"store_id": e.StoreID, "next_opening": e.NextOpening})
+		return
+	case *domain.QueueFullError:
+		c.Header("Retry-After", "1")
+	}
+
+	if status == http.StatusInternalServerError {
+		log.Printf("❌ %s %s failed (request_id=%s): %v", c.Request.Method, c.FullPath(), domain.CorrelationIDFromContext(c.Request.Context()), err)
+	}
+	respondError(c, status, title, err.Error())
+}
+
+// errorStatus traduce un error de dominio a su código HTTP y título (500 si no es un error tipado).
+// Clasifica el primer error de dominio de la cadena, así que un error envuelto por un servicio
+// ("failed to confirm in stock: %w") conserva su código. Lo comparten handleError y los
+// resultados por elemento de las operaciones masivas.
+func errorStatus(err error) (int, string) {
+	domainErr, _ := domain.AsDomainError(err)
+	switch e := domainErr.(type) {
 	case *domain.NotFoundError:
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error:   "Not Found",
-			Message: e.Error(),
-		})
+		return http.StatusNotFound, "Not Found"
 	case *domain.ValidationError:
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Validation Error",
-			Message: e.Error(),
-		})
+		return http.StatusBadRequest, "Validation Error"
 	case *domain.ConflictError:
-		c.JSON(http.StatusConflict, ErrorResponse{
-			Error:   "Conflict",
-			Message: e.Error(),
-		})
+		return http.StatusConflict, "Conflict"
 	case *domain.InsufficientStockError:
-		c.JSON(http.StatusConflict, ErrorResponse{
-			Error:   "Insufficient Stock",
-			Message: e.Error(),
-		})
+		return http.StatusConflict, "Insufficient Stock"
+	case *domain.ProductInUseError:
+		return http.StatusConflict, "Product In Use"
+	case *domain.InvalidStateError:
+		return http.StatusConflict, "Invalid State"
+	case *domain.CustomerLimitError:
+		return http.StatusTooManyRequests, "Customer Limit Exceeded"
+	case *domain.StoreClosedError:
+		return http.StatusConflict, "Store Closed"
+	case *domain.QueueFullError:
+		return http.StatusServiceUnavailable, "Queue Full"
 	case *domain.UnauthorizedError:
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
-			Error:   "Unauthorized",
-			Message: e.Error(),
-		})
+		return http.StatusUnauthorized, "Unauthorized"
 	case *domain.ForbiddenError:
-		c.JSON(http.StatusForbidden, ErrorResponse{
-			Error:   "Forbidden",
-			Message: e.Error(),
-		})
+		return http.StatusForbidden, "Forbidden"
+	case *domain.FeatureDisabledError:
+		return http.StatusForbidden, "Feature Disabled"
+	case *domain.SelfApprovalError:
+		return http.StatusForbidden, "Self Approval"
+	case *domain.ConfirmHookError:
+		if e.Rejected {
+			return http.StatusConflict, "Confirm Rejected"
+		}
+		return http.StatusBadGateway, "Confirm Hook Failed"
 	default:
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: err.Error(),
-		})
+		return http.StatusInternalServerError, "Internal Server Error"
 	}
 }
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProductUnitHandler maneja las unidades de medida de los productos
+type ProductUnitHandler struct {
+	unitService *service.ProductUnitService
+}
+
+// NewProductUnitHandler crea un nuevo handler de unidades de medida
+func NewProductUnitHandler(unitService *service.ProductUnitService) *ProductUnitHandler {
+	return &ProductUnitHandler{
+		unitService: unitService,
+	}
+}
+
+// ProductUnitsRequest representa la unidad base y las conversiones de un producto
+type ProductUnitsRequest struct {
+	BaseUnit    string                  `json:"base_unit" binding:"required" example:"EACH"`
+	Conversions []domain.UnitConversion `json:"conversions"`                                              // p. ej. [{"unit": "BOX", "factor": 12}]
+	Rounding    string                  `json:"rounding" enums:"NEAREST,DOWN,UP,EXACT" example:"NEAREST"` // Opcional: NEAREST por defecto
+}
+
+// toBaseQuantity convierte la cantidad de una petición a unidades base del producto.
+// Sin servicio de unidades solo se acepta la unidad base (unit vacía).
+func toBaseQuantity(c *gin.Context, unitService *service.ProductUnitService, productID string, quantity int, unit string) (int, error) {
+	if domain.NormalizeUnit(unit) == "" {
+		return quantity, nil
+	}
+	if unitService == nil {
+		return 0, &domain.ValidationError{Field: "unit", Message: "units of measure are not enabled"}
+	}
+	return unitService.ToBaseUnits(c.Request.Context(), productID, quantity, unit)
+}
+
+// toBaseDecimalQuantity como toBaseQuantity pero admite cantidades decimales (1.25 KG) en unidades
+// fractional del producto, si fractional_quantities está activado en las tiendas afectadas
+func toBaseDecimalQuantity(c *gin.Context, unitService *service.ProductUnitService, productID string, quantity float64, unit string, storeIDs ...string) (int, error) {
+	if domain.IsWholeQuantity(quantity) {
+		return toBaseQuantity(c, unitService, productID, int(quantity), unit)
+	}
+	if unitService == nil {
+		return 0, &domain.ValidationError{Field: "quantity", Message: "fractional quantities are not enabled"}
+	}
+	return unitService.ToBaseUnitsDecimal(c.Request.Context(), productID, quantity, unit, storeIDs...)
+}
+
+// GetProductUnits godoc
+// @Summary Unidades de medida de un producto
+// @Description Sin configuración el producto usa EACH sin conversiones
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} ProductUnitsResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/units [get]
+func (h *ProductUnitHandler) GetProductUnits(c *gin.Context) {
+	units, err := h.unitService.GetUnits(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, units)
+}
+
+// PutProductUnits godoc
+// @Summary Configurar la unidad base y las conversiones de un producto
+// @Description El stock se guarda en base_unit; las peticiones de stock y reservas pueden indicar quantity + unit (p. ej. 2 BOX = 24 EACH). Las conversiones con fractional admiten cantidades decimales (1.25 KG con base G = 1250) en las tiendas con el feature flag fractional_quantities; rounding (NEAREST, DOWN, UP, EXACT) decide cómo se redondean las que no dan unidades base enteras. La unidad base solo puede cambiar mientras el producto no tenga stock (409).
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param request body ProductUnitsRequest true "Unidad base y conversiones"
+// @Success 200 {object} ProductUnitsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Cambio de unidad base con stock"
+// @Security ApiKeyAuth
+// @Router /products/{id}/units [put]
+func (h *ProductUnitHandler) PutProductUnits(c *gin.Context) {
+	var req ProductUnitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	units, err := h.unitService.SetUnits(c.Request.Context(), &domain.ProductUnits{
+		ProductID:   c.Param("id"),
+		BaseUnit:    req.BaseUnit,
+		Conversions: req.Conversions,
+		Rounding:    domain.RoundingMode(req.Rounding),
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, units)
+}
+
+// DeleteProductUnits godoc
+// @Summary Eliminar las unidades de medida de un producto
+// @Description El producto vuelve a EACH sin conversiones (409 si la unidad base era otra y hay stock)
+// @Tags products
+// @Param id path string true "Product ID"
+// @Success 204 "Unidades eliminadas"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Unidad base distinta de EACH con stock"
+// @Security ApiKeyAuth
+// @Router /products/{id}/units [delete]
+func (h *ProductUnitHandler) DeleteProductUnits(c *gin.Context) {
+	if err := h.unitService.DeleteUnits(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
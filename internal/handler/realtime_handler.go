@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"inventory-system/internal/realtime"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// RealtimeHandler expone suscripciones websocket a cambios de disponibilidad
+type RealtimeHandler struct {
+	hub *realtime.Hub
+}
+
+// NewRealtimeHandler crea un nuevo handler de tiempo real
+func NewRealtimeHandler(hub *realtime.Hub) *RealtimeHandler {
+	return &RealtimeHandler{
+		hub: hub,
+	}
+}
+
+// SubscribeAvailability godoc
+// @Summary Suscribirse a cambios de disponibilidad (websocket)
+// @Description Filtra en servidor por tienda, categoría completa y/o lista de productos
+// @Tags realtime
+// @Param storeId query string false "Tienda (ej. MAD-001)"
+// @Param category query string false "Categoría completa (ej. electronics)"
+// @Param productIds query string false "IDs de producto separados por coma"
+// @Success 101
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /realtime/availability [get]
+func (h *RealtimeHandler) SubscribeAvailability(c *gin.Context) {
+	filter := realtime.Filter{
+		StoreID:  c.Query("storeId"),
+		Category: c.Query("category"),
+	}
+
+	if ids := c.Query("productIds"); ids != "" {
+		filter.ProductIDs = make(map[string]bool)
+		for _, id := range strings.Split(ids, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				filter.ProductIDs[id] = true
+			}
+		}
+	}
+
+	if filter.StoreID == "" && filter.Category == "" && len(filter.ProductIDs) == 0 {
+		respondError(c, http.StatusBadRequest, "Invalid subscription", "at least one of storeId, category or productIds is required")
+		return
+	}
+
+	server := websocket.Server{
+		// El origen ya se controla vía CORS + API Key
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			sub := h.hub.Subscribe(filter)
+			defer h.hub.Unsubscribe(sub)
+
+			// Detectar desconexión del cliente
+			closed := make(chan struct{})
+			go func() {
+				var discard []byte
+				for {
+					if err := websocket.Message.Receive(ws, &discard); err != nil {
+						close(closed)
+						return
+					}
+				}
+			}()
+
+			for {
+				select {
+				case <-closed:
+					return
+				case update, ok := <-sub.Updates:
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(ws, update); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+
+	server.ServeHTTP(c.Writer, c.Request)
+}
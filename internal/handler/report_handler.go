@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler maneja las peticiones HTTP de reportes
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+// NewReportHandler crea un nuevo handler de reportes
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// GetOverview godoc
+// @Summary Vista global del inventario (dashboard de operaciones)
+// @Tags reports
+// @Produce json
+// @Param category query string false "Filtrar por categoría"
+// @Param group query string false "Limitar a las tiendas de un grupo (región, franquicia)"
+// @Param top query int false "Cantidad de productos con menor disponibilidad" default(10)
+// @Success 200 {object} domain.InventoryOverview
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Grupo inexistente"
+// @Security ApiKeyAuth
+// @Router /reports/overview [get]
+func (h *ReportHandler) GetOverview(c *gin.Context) {
+	category := c.Query("category")
+	top, _ := strconv.Atoi(c.DefaultQuery("top", "10"))
+
+	overview, err := h.reportService.GetOverview(c.Request.Context(), category, c.Query("group"), top)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// GetOutOfStock godoc
+// @Summary Listar productos sin disponibilidad
+// @Description Filas de stock con available <= 0 y el tiempo que llevan así (derivado del último movimiento que alteró la disponibilidad), las más antiguas primero; oversold marca las vendidas por encima del stock (available < 0)
+// @Tags stock
+// @Produce json
+// @Param storeId query string false "Limitar a una tienda"
+// @Param group query string false "Limitar a las tiendas de un grupo (región, franquicia)"
+// @Success 200 {object} OutOfStockResponse
+// @Failure 404 {object} ErrorResponse "Grupo inexistente"
+// @Security ApiKeyAuth
+// @Router /stock/out-of-stock [get]
+func (h *ReportHandler) GetOutOfStock(c *gin.Context) {
+	report, err := h.reportService.GetOutOfStockReport(c.Request.Context(), c.Query("storeId"), c.Query("group"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetAdjustmentsByReason godoc
+// @Summary Ajustes manuales de stock agrupados por motivo
+// @Description Movimientos stock.updated con motivo del catálogo (POST /adjust, PUT /stock con reason, ajustes aprobados) con las unidades sumadas y retiradas por motivo, los de más movimientos primero
+// @Tags reports
+// @Produce json
+// @Param store_id query string false "Limitar a una tienda"
+// @Param from query string false "Desde (RFC3339 o YYYY-MM-DD, inclusivo)"
+// @Param to query string false "Hasta (RFC3339 o YYYY-MM-DD, exclusivo)"
+// @Success 200 {object} domain.AdjustmentReasonReport
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reports/adjustments [get]
+func (h *ReportHandler) GetAdjustmentsByReason(c *gin.Context) {
+	from, err := queryTime(c, "from")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	to, err := queryTime(c, "to")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	report, err := h.reportService.GetAdjustmentsByReason(c.Request.Context(), c.Query("store_id"), from, to)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetKPIs godoc
+// @Summary KPIs de negocio como series temporales (datasource JSON de Grafana)
+// @Description Un punto por intervalo con el formato de series de Grafana ([{"target", "datapoints": [[valor, epoch_ms]]}]): reservations_per_minute (reservas creadas / minutos del intervalo), stock_outs (filas de stock que se quedaron a 0 unidades), fill_rate (confirmadas / reservas cerradas en el intervalo) y expiry_rate (caducadas / reservas cerradas). Las tasas son null en los intervalos sin reservas cerradas. Se calcula sobre la tabla de eventos, así que no cubre periodos ya purgados.
+// @Tags reports
+// @Produce json
+// @Param from query string false "Desde (RFC3339 o YYYY-MM-DD, inclusivo); por defecto 6h antes de to"
+// @Param to query string false "Hasta (RFC3339 o YYYY-MM-DD, exclusivo); por defecto ahora"
+// @Param interval query string false "Tamaño de cada punto (ej. 1m, 5m, 1h; mínimo 1m, máximo 2000 puntos)" default(5m)
+// @Param metrics query string false "KPIs separados por coma (reservations_per_minute,stock_outs,fill_rate,expiry_rate); por defecto todos"
+// @Param store_id query string false "Limitar a una tienda"
+// @Success 200 {array} domain.KPISeries
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reports/kpis [get]
+func (h *ReportHandler) GetKPIs(c *gin.Context) {
+	interval, err := time.ParseDuration(c.DefaultQuery("interval", "5m"))
+	if err != nil || interval <= 0 {
+		respondError(c, http.StatusBadRequest, "Invalid interval", "interval must be a positive duration (e.g. 1m, 5m, 1h)")
+		return
+	}
+	from, err := queryTime(c, "from")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	to, err := queryTime(c, "to")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	end := time.Now()
+	if to != nil {
+		end = *to
+	}
+	start := end.Add(-defaultKPIRange)
+	if from != nil {
+		start = *from
+	}
+
+	var metrics []string
+	for _, metric := range strings.Split(c.Query("metrics"), ",") {
+		if metric = strings.TrimSpace(metric); metric != "" {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	series, err := h.reportService.GetKPIs(c.Request.Context(), c.Query("store_id"), metrics, start, end, interval)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// defaultKPIRange rango de GetKPIs si no se indica from
+const defaultKPIRange = 6 * time.Hour
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReservationGroupHandler maneja los grupos de reservas en distintas tiendas (envío partido)
+type ReservationGroupHandler struct {
+	groupService *service.ReservationGroupService
+	unitService  *service.ProductUnitService // Opcional: cantidades en unidades de pedido (BOX, CASE...)
+}
+
+// NewReservationGroupHandler crea un nuevo handler de grupos de reservas
+func NewReservationGroupHandler(groupService *service.ReservationGroupService) *ReservationGroupHandler {
+	return &ReservationGroupHandler{
+		groupService: groupService,
+	}
+}
+
+// SetProductUnitService habilita cantidad + unidad en las peticiones (se convierten a la unidad base)
+func (h *ReservationGroupHandler) SetProductUnitService(unitService *service.ProductUnitService) {
+	h.unitService = unitService
+}
+
+// CreateReservationGroupRequest representa la petición para crear un grupo de reservas
+type CreateReservationGroupRequest struct {
+	CustomerID string                        `json:"customer_id" binding:"required"`
+	TTLMinutes int                           `json:"ttl_minutes" binding:"omitempty,min=1"`
+	Priority   string                        `json:"priority" enums:"LOW,NORMAL,HIGH"` // Opcional: NORMAL por defecto
+	Items      []ReservationGroupItemRequest `json:"items" binding:"required,min=1,max=20,dive"`
+}
+
+// ReservationGroupItemRequest representa una reserva hija del grupo
+type ReservationGroupItemRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	StoreID   string `json:"store_id" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,min=1"`
+	Unit      string `json:"unit" example:"BOX"` // Opcional: unidad base del producto por defecto
+}
+
+// CreateReservationGroup godoc
+// @Summary Crear un grupo de reservas en distintas tiendas (envío partido)
+// @Description Crea una reserva por item (hasta 20). Si alguna no se puede crear se cancelan las ya creadas y no se crea el grupo.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param request body CreateReservationGroupRequest true "Reservas del grupo"
+// @Success 201 {object} ReservationGroupResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Stock insuficiente en alguna tienda"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Failure 429 {object} ErrorResponse "Límite de reservas del cliente superado (anti-acaparamiento)"
+// @Security ApiKeyAuth
+// @Router /reservations/groups [post]
+func (h *ReservationGroupHandler) CreateReservationGroup(c *gin.Context) {
+	var req CreateReservationGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	priority, err := domain.ParseReservationPriority(req.Priority)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	items := make([]domain.ReservationGroupItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		quantity, err := toBaseQuantity(c, h.unitService, item.ProductID, item.Quantity, item.Unit)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+		items = append(items, domain.ReservationGroupItem{ProductID: item.ProductID, StoreID: item.StoreID, Quantity: quantity})
+	}
+
+	group, err := h.groupService.CreateReservationGroup(c.Request.Context(), req.CustomerID, items, req.TTLMinutes, priority)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// GetReservationGroup godoc
+// @Summary Obtener un grupo de reservas con su estado agregado
+// @Tags reservations
+// @Produce json
+// @Param id path string true "ID del grupo"
+// @Success 200 {object} ReservationGroupResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reservations/groups/{id} [get]
+func (h *ReservationGroupHandler) GetReservationGroup(c *gin.Context) {
+	group, err := h.groupService.GetReservationGroup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// ConfirmReservationGroupRequest representa la petición opcional al confirmar un grupo
+type ConfirmReservationGroupRequest struct {
+	ReferenceID string `json:"reference_id"` // Ticket o pedido de la venta (se incluye en cada reservation.confirmed)
+}
+
+// ConfirmReservationGroup godoc
+// @Summary Confirmar todas las reservas de un grupo
+// @Description Confirma cada reserva pendiente en su tienda. Un fallo en una reserva no revierte las demás: responde 207 en el formato multi-status común, con el resultado de cada una y el estado agregado (PARTIAL si quedan en estados distintos).
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del grupo"
+// @Param request body ConfirmReservationGroupRequest false "Referencia de la venta (opcional)"
+// @Success 200 {object} ReservationGroupActionResponse
+// @Success 207 {object} ReservationGroupActionResponse "Alguna reserva no se pudo confirmar"
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reservations/groups/{id}/confirm [post]
+func (h *ReservationGroupHandler) ConfirmReservationGroup(c *gin.Context) {
+	var req ConfirmReservationGroupRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	report, err := h.groupService.ConfirmReservationGroup(c.Request.Context(), c.Param("id"), req.ReferenceID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respondGroupReport(c, report)
+}
+
+// CancelReservationGroup godoc
+// @Summary Cancelar todas las reservas de un grupo
+// @Description Cancela cada reserva pendiente en su tienda. Un fallo en una reserva no revierte las demás: responde 207 en el formato multi-status común, con el resultado de cada una.
+// @Tags reservations
+// @Produce json
+// @Param id path string true "ID del grupo"
+// @Success 200 {object} ReservationGroupActionResponse
+// @Success 207 {object} ReservationGroupActionResponse "Alguna reserva no se pudo cancelar"
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reservations/groups/{id}/cancel [post]
+func (h *ReservationGroupHandler) CancelReservationGroup(c *gin.Context) {
+	report, err := h.groupService.CancelReservationGroup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respondGroupReport(c, report)
+}
+
+// respondGroupReport responde el resultado de cada reserva hija en el formato multi-status
+// (200 si la acción se aplicó en todas y 207 si alguna falló)
+func respondGroupReport(c *gin.Context, report *domain.ReservationGroupActionReport) {
+	response := ReservationGroupActionResponse{
+		GroupID:             report.GroupID,
+		Action:              string(report.Action),
+		Status:              string(report.Status),
+		MultiStatusResponse: newMultiStatus(len(report.Results)),
+	}
+	for i, result := range report.Results {
+		entry := ReservationGroupResultEntry{
+			ReservationID: result.ReservationID,
+			StoreID:       result.StoreID,
+			Status:        string(result.Status),
+		}
+		switch result.Outcome {
+		case domain.ReservationGroupSucceeded:
+			response.Succeed(i, http.StatusOK, entry)
+		case domain.ReservationGroupSkipped:
+			response.Skip(i, entry)
+		default:
+			response.Fail(i, result.Err, entry)
+		}
+	}
+
+	respondMultiStatus(c, &response.MultiStatusResponse, response)
+}
@@ -3,6 +3,9 @@ package handler
 import (
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"inventory-system/internal/domain"
 	"inventory-system/internal/service"
@@ -13,22 +16,39 @@ import (
 // ReservationHandler maneja las peticiones HTTP para reservas
 type ReservationHandler struct {
 	reservationService *service.ReservationService
+	serialService      *service.SerialService
+	flashSaleService   *service.FlashSaleService   // Opcional: cola de reservas para productos en flash sale
+	unitService        *service.ProductUnitService // Opcional: cantidades en unidades de pedido (BOX, CASE...)
 }
 
 // NewReservationHandler crea un nuevo handler de reservas
-func NewReservationHandler(reservationService *service.ReservationService) *ReservationHandler {
+func NewReservationHandler(reservationService *service.ReservationService, serialService *service.SerialService) *ReservationHandler {
 	return &ReservationHandler{
 		reservationService: reservationService,
+		serialService:      serialService,
 	}
 }
 
+// SetProductUnitService habilita cantidad + unidad en las peticiones (se convierten a la unidad base)
+func (h *ReservationHandler) SetProductUnitService(unitService *service.ProductUnitService) {
+	h.unitService = unitService
+}
+
+// SetFlashSaleService habilita el encolado de reservas de productos en modo alta contención
+func (h *ReservationHandler) SetFlashSaleService(flashSaleService *service.FlashSaleService) {
+	h.flashSaleService = flashSaleService
+}
+
 // CreateReservationRequest representa la petición para crear una reserva
 type CreateReservationRequest struct {
-	ProductID  string `json:"product_id" binding:"required"`
-	StoreID    string `json:"store_id" binding:"required"`
-	CustomerID string `json:"customer_id" binding:"required"`
-	Quantity   int    `json:"quantity" binding:"required,min=1"`
-	TTLMinutes int    `json:"ttl_minutes" binding:"required,min=1,max=1440"` // Max 24 horas
+	ProductID  string  `json:"product_id" binding:"required"`
+	StoreID    string  `json:"store_id"` // Opcional con una API key de una sola tienda: se usa la suya
+	CustomerID string  `json:"customer_id" binding:"required"`
+	Quantity   float64 `json:"quantity" binding:"required,gt=0"`      // Decimal solo en unidades fractional
+	TTLMinutes int     `json:"ttl_minutes" binding:"omitempty,min=1"` // Opcional: TTL por defecto/máximo según configuración
+	Priority   string  `json:"priority" enums:"LOW,NORMAL,HIGH"`      // Opcional: NORMAL por defecto
+	Unit       string  `json:"unit" example:"BOX"`                    // Opcional: unidad base del producto por defecto
+	Preorder   bool    `json:"preorder"`                              // Preventa contra stock entrante (PREORDER)
 }
 
 // CreateReservation godoc
@@ -37,31 +57,83 @@ type CreateReservationRequest struct {
 // @Accept json
 // @Produce json
 // @Param request body CreateReservationRequest true "Datos de la reserva"
-// @Success 201 {object} domain.Reservation
+// @Param X-Sales-Channel header string false "Canal de venta: la reserva descuenta de su asignación (también ?channel=)" Enums(WEB, STORE, MARKETPLACE)
+// @Description Para productos en modo flash sale la petición se encola y se responde 202 con un ticket que se consulta en GET /reservations/tickets/{token} (con prioridad NORMAL). priority (LOW, NORMAL, HIGH) decide qué reservas se liberan antes ante falta de stock. Con preorder=true se crea una preventa (PREORDER) contra las unidades entrantes de pedidos de compra y transferencias programados: no reserva stock ni caduca, y solo se puede confirmar cuando la recepción le asigna stock (pasa a PENDING con su TTL). Con canal de venta (X-Sales-Channel) la reserva solo consume la asignación de ese canal, o la parte no asignada si el canal no tiene asignación.
+// @Success 201 {object} ReservationResponse
+// @Success 202 {object} ReservationTicketResponse "Petición encolada (producto en flash sale)"
 // @Failure 400 {object} ErrorResponse
-// @Failure 409 {object} ErrorResponse "Stock insuficiente"
+// @Failure 413 {object} ErrorResponse
+// @Failure 409 {object} InsufficientStockErrorResponse "Stock insuficiente (details: disponible, caducidades próximas y preventa posible)"
+// @Failure 429 {object} ErrorResponse "Límite de reservas del cliente superado (anti-acaparamiento)"
+// @Failure 503 {object} ErrorResponse "Cola de reservas llena"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
 // @Router /reservations [post]
 func (h *ReservationHandler) CreateReservation(c *gin.Context) {
 	var req CreateReservationRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request body",
-			Message: err.Error(),
-		})
+	if err := bindStrictJSON(c, &req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if req.StoreID == "" {
+		req.StoreID = domain.ActingStoreFromContext(c.Request.Context())
+	}
+	if req.StoreID == "" {
+		handleError(c, &domain.ValidationError{Field: "store_id", Message: "store_id is required"})
 		return
 	}
 
 	// Log request for debugging
-	log.Printf("CreateReservation: ProductID=%s, StoreID=%s, CustomerID=%s, Quantity=%d, TTL=%d",
+	log.Printf("CreateReservation: ProductID=%s, StoreID=%s, CustomerID=%s, Quantity=%g, TTL=%d",
 		req.ProductID, req.StoreID, req.CustomerID, req.Quantity, req.TTLMinutes)
 
-	reservation, err := h.reservationService.CreateReservation(
+	priority, err := domain.ParseReservationPriority(req.Priority)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	quantity, err := toBaseDecimalQuantity(c, h.unitService, req.ProductID, req.Quantity, req.Unit, req.StoreID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if req.Preorder {
+		reservation, err := h.reservationService.CreatePreorder(c.Request.Context(), req.ProductID, req.StoreID, req.CustomerID, quantity, req.TTLMinutes, priority)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+		respond(c, http.StatusCreated, reservation)
+		return
+	}
+
+	if h.flashSaleService != nil {
+		enabled, err := h.flashSaleService.IsEnabled(c.Request.Context(), req.ProductID)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+		if enabled {
+			ticket, err := h.flashSaleService.Enqueue(c.Request.Context(), req.ProductID, req.StoreID, req.CustomerID, quantity, req.TTLMinutes)
+			if err != nil {
+				handleError(c, err)
+				return
+			}
+			c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/tickets/"+ticket.Token)
+			respond(c, http.StatusAccepted, ticket)
+			return
+		}
+	}
+
+	reservation, err := h.reservationService.CreateReservationWithPriority(
 		c.Request.Context(),
 		req.ProductID,
 		req.StoreID,
 		req.CustomerID,
-		req.Quantity,
+		quantity,
 		req.TTLMinutes,
+		priority,
 	)
 
 	if err != nil {
@@ -69,7 +141,38 @@ func (h *ReservationHandler) CreateReservation(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, reservation)
+	respond(c, http.StatusCreated, reservation)
+}
+
+// AllocatePreorders godoc
+// @Summary Asignar el stock recibido a las preventas de un producto en una tienda
+// @Tags reservations
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Description Asigna la cantidad vendible a las preventas (PREORDER) por orden de llegada hasta la primera que no cabe; las asignadas pasan a PENDING y se pueden confirmar. El worker de cambios programados lo hace solo al aplicar una llegada de pedido de compra o transferencia; este endpoint sirve para recepciones registradas por otra vía (PUT /stock).
+// @Success 200 {object} PreorderAllocationResponse
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/preorders/allocate [post]
+func (h *ReservationHandler) AllocatePreorders(c *gin.Context) {
+	productID := c.Param("productId")
+	storeID := c.Param("storeId")
+	allocated, err := h.reservationService.AllocatePreorders(c.Request.Context(), productID, storeID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if allocated == nil {
+		allocated = []*domain.Reservation{}
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"product_id":   productID,
+		"store_id":     storeID,
+		"reservations": allocated,
+		"count":        len(allocated),
+	})
 }
 
 // GetReservation godoc
@@ -77,8 +180,9 @@ func (h *ReservationHandler) CreateReservation(c *gin.Context) {
 // @Tags reservations
 // @Produce json
 // @Param id path string true "ID de la reserva"
-// @Success 200 {object} domain.Reservation
+// @Success 200 {object} ReservationResponse
 // @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /reservations/{id} [get]
 func (h *ReservationHandler) GetReservation(c *gin.Context) {
 	id := c.Param("id")
@@ -89,32 +193,66 @@ func (h *ReservationHandler) GetReservation(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, reservation)
+	respond(c, http.StatusOK, reservation)
+}
+
+// ConfirmReservationRequest representa la petición opcional al confirmar una reserva
+type ConfirmReservationRequest struct {
+	SerialNumbers  []string `json:"serial_numbers"`  // Números de serie vendidos (artículos serializados)
+	WarrantyMonths int      `json:"warranty_months"` // Meses de garantía desde la venta
+	ReferenceID    string   `json:"reference_id"`    // Ticket o pedido de la venta (se incluye en reservation.confirmed)
 }
 
 // ConfirmReservation godoc
 // @Summary Confirmar una reserva (procesa la venta)
 // @Tags reservations
+// @Accept json
 // @Produce json
 // @Param id path string true "ID de la reserva"
-// @Success 200 {object} map[string]string
+// @Param request body ConfirmReservationRequest false "Referencia de la venta y números de serie vendidos (opcional)"
+// @Success 200 {object} ReservationStatusResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "La reserva ya no está PENDING o el hook de confirmación la rechazó"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Failure 502 {object} ErrorResponse "El hook de confirmación falló tras los reintentos (la reserva sigue PENDING)"
+// @Security ApiKeyAuth
 // @Router /reservations/{id}/confirm [post]
 func (h *ReservationHandler) ConfirmReservation(c *gin.Context) {
 	id := c.Param("id")
 
-	err := h.reservationService.ConfirmReservation(c.Request.Context(), id)
+	// El body es opcional: referencia de la venta y números de serie
+	var req ConfirmReservationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	err := h.reservationService.ConfirmReservationWithReference(c.Request.Context(), id, req.ReferenceID)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message":        "Reservation confirmed successfully",
 		"reservation_id": id,
 		"status":         "CONFIRMED",
-	})
+	}
+
+	if len(req.SerialNumbers) > 0 {
+		// La venta ya está confirmada: un fallo aquí se reporta pero no la revierte
+		serials, err := h.serialService.RegisterSerials(c.Request.Context(), id, req.SerialNumbers, req.WarrantyMonths)
+		if err != nil {
+			response["serials_error"] = err.Error()
+		} else {
+			response["serials"] = serials
+		}
+	}
+
+	respond(c, http.StatusOK, response)
 }
 
 // CancelReservation godoc
@@ -122,9 +260,12 @@ func (h *ReservationHandler) ConfirmReservation(c *gin.Context) {
 // @Tags reservations
 // @Produce json
 // @Param id path string true "ID de la reserva"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} ReservationStatusResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "La reserva ya no está PENDING"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
 // @Router /reservations/{id}/cancel [post]
 func (h *ReservationHandler) CancelReservation(c *gin.Context) {
 	id := c.Param("id")
@@ -135,83 +276,337 @@ func (h *ReservationHandler) CancelReservation(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"message":        "Reservation cancelled successfully",
 		"reservation_id": id,
 		"status":         "CANCELLED",
 	})
 }
 
+// MarkReadyForPickup godoc
+// @Summary Marcar una reserva como lista para recoger en tienda (click-and-collect)
+// @Description La tienda tiene el pedido preparado: la reserva pasa de PENDING a READY_FOR_PICKUP, su expiración pasa a ser el plazo de recogida (RESERVATION_PICKUP_WINDOW_HOURS) y se genera el código de 6 dígitos que el cliente presenta al recoger. El código solo se devuelve en esta respuesta. Las unidades siguen reservadas; si no se recoge a tiempo el worker de expiración la caduca y las libera. Emite reservation.ready_for_pickup.
+// @Tags reservations
+// @Produce json
+// @Param id path string true "ID de la reserva"
+// @Success 200 {object} ReservationPickupResponse
+// @Failure 400 {object} ErrorResponse "La reserva ya expiró"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "La reserva ya no está PENDING"
+// @Security ApiKeyAuth
+// @Router /reservations/{id}/ready-for-pickup [post]
+func (h *ReservationHandler) MarkReadyForPickup(c *gin.Context) {
+	reservation, pickup, err := h.reservationService.MarkReadyForPickup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"reservation": reservation,
+		"pickup":      pickup,
+	})
+}
+
+// PickUpReservationRequest representa el código que presenta el cliente al recoger
+type PickUpReservationRequest struct {
+	Code string `json:"code" binding:"required" example:"482913"`
+}
+
+// PickUpReservation godoc
+// @Summary Entregar una reserva lista para recoger validando el código del cliente
+// @Description Valida en la tienda el código de recogida: la reserva pasa a PICKED_UP, se descuenta el stock como en una confirmación y se emite reservation.picked_up (mismo payload que reservation.confirmed). Tras 5 códigos erróneos la recogida se bloquea (409) y solo se puede cancelar.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de la reserva"
+// @Param request body PickUpReservationRequest true "Código de recogida"
+// @Success 200 {object} ReservationPickupResponse
+// @Failure 400 {object} ErrorResponse "Código erróneo o plazo de recogida vencido"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "La reserva no está READY_FOR_PICKUP o la recogida está bloqueada"
+// @Security ApiKeyAuth
+// @Router /reservations/{id}/pickup [post]
+func (h *ReservationHandler) PickUpReservation(c *gin.Context) {
+	var req PickUpReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	reservation, pickup, err := h.reservationService.PickUpReservation(c.Request.Context(), c.Param("id"), req.Code)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"reservation": reservation,
+		"pickup":      pickup,
+	})
+}
+
 // GetPendingByStore godoc
 // @Summary Obtener reservas pendientes de una tienda
+// @Description Listado paginado; por defecto ordenado por expires_at ascendente (las próximas a expirar primero)
 // @Tags reservations
 // @Produce json
 // @Param storeId path string true "ID de la tienda"
-// @Success 200 {array} domain.Reservation
+// @Param limit query int false "Máximo de resultados (máx. 500)" default(50)
+// @Param offset query int false "Resultados a saltar" default(0)
+// @Param sort query string false "Campo de orden (created_at, expires_at)" default(expires_at)
+// @Param order query string false "Dirección (asc, desc)" default(asc)
+// @Param customer_id query string false "Filtrar por cliente"
+// @Param created_from query string false "Creadas desde (RFC3339 o YYYY-MM-DD, inclusivo)"
+// @Param created_to query string false "Creadas hasta (RFC3339 o YYYY-MM-DD, exclusivo)"
+// @Success 200 {object} PendingReservationsResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /reservations/store/{storeId}/pending [get]
 func (h *ReservationHandler) GetPendingByStore(c *gin.Context) {
 	storeID := c.Param("storeId")
 
-	reservations, err := h.reservationService.GetPendingByStore(c.Request.Context(), storeID)
+	filter, err := parseReservationFilter(c, domain.ReservationSortExpiresAt, false)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	status := domain.ReservationStatusPending
+	filter.StoreID = storeID
+	filter.Status = &status
+
+	reservations, total, err := h.reservationService.ListReservations(c.Request.Context(), filter)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respondList(c, http.StatusOK, reservations, gin.H{
+		"store_id": storeID,
+		"count":    len(reservations),
+		"total":    total,
+		"limit":    filter.Limit,
+		"offset":   filter.Offset,
+	}, gin.H{
 		"store_id":     storeID,
 		"reservations": reservations,
 		"count":        len(reservations),
+		"total":        total,
+		"limit":        filter.Limit,
+		"offset":       filter.Offset,
 	})
 }
 
 // GetReservationsByProduct godoc
 // @Summary Obtener reservas de un producto en una tienda
+// @Description Listado paginado; por defecto ordenado por created_at descendente (las más recientes primero)
 // @Tags reservations
 // @Produce json
 // @Param productId path string true "ID del producto"
 // @Param storeId path string true "ID de la tienda"
-// @Param status query string false "Filtrar por estado (PENDING, CONFIRMED, CANCELLED, EXPIRED)"
-// @Success 200 {array} domain.Reservation
+// @Param status query string false "Filtrar por estado (PENDING, CONFIRMED, CANCELLED, EXPIRED, PREORDER)"
+// @Param limit query int false "Máximo de resultados (máx. 500)" default(50)
+// @Param offset query int false "Resultados a saltar" default(0)
+// @Param sort query string false "Campo de orden (created_at, expires_at)" default(created_at)
+// @Param order query string false "Dirección (asc, desc)" default(desc)
+// @Param customer_id query string false "Filtrar por cliente"
+// @Param created_from query string false "Creadas desde (RFC3339 o YYYY-MM-DD, inclusivo)"
+// @Param created_to query string false "Creadas hasta (RFC3339 o YYYY-MM-DD, exclusivo)"
+// @Success 200 {object} ProductReservationsResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /reservations/product/{productId}/store/{storeId} [get]
 func (h *ReservationHandler) GetReservationsByProduct(c *gin.Context) {
 	productID := c.Param("productId")
 	storeID := c.Param("storeId")
 	statusStr := c.Query("status")
 
-	var status *domain.ReservationStatus
+	filter, err := parseReservationFilter(c, domain.ReservationSortCreatedAt, true)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	filter.ProductID = productID
+	filter.StoreID = storeID
 	if statusStr != "" {
 		s := domain.ReservationStatus(statusStr)
-		status = &s
+		filter.Status = &s
 	}
 
-	reservations, err := h.reservationService.GetReservationsByProduct(c.Request.Context(), productID, storeID, status)
+	reservations, total, err := h.reservationService.ListReservations(c.Request.Context(), filter)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respondList(c, http.StatusOK, reservations, gin.H{
+		"product_id": productID,
+		"store_id":   storeID,
+		"status":     statusStr,
+		"count":      len(reservations),
+		"total":      total,
+		"limit":      filter.Limit,
+		"offset":     filter.Offset,
+	}, gin.H{
 		"product_id":   productID,
 		"store_id":     storeID,
 		"status":       statusStr,
 		"reservations": reservations,
 		"count":        len(reservations),
+		"total":        total,
+		"limit":        filter.Limit,
+		"offset":       filter.Offset,
 	})
 }
 
+// parseReservationFilter lee los parámetros comunes de paginación, orden y filtros de los
+// listados de reservas. El orden por defecto depende del endpoint.
+func parseReservationFilter(c *gin.Context, defaultSort string, defaultDesc bool) (domain.ReservationFilter, error) {
+	filter := domain.ReservationFilter{
+		CustomerID: c.Query("customer_id"),
+		SortBy:     c.DefaultQuery("sort", defaultSort),
+		SortDesc:   defaultDesc,
+	}
+
+	var err error
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultReservationListLimit
+	}
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		return filter, err
+	}
+
+	switch c.Query("order") {
+	case "":
+	case "asc":
+		filter.SortDesc = false
+	case "desc":
+		filter.SortDesc = true
+	default:
+		return filter, &domain.ValidationError{Field: "order", Message: "order must be asc or desc"}
+	}
+
+	if filter.CreatedFrom, err = queryTime(c, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = queryTime(c, "created_to"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// queryInt lee un entero opcional del query string (0 si no viene)
+func queryInt(c *gin.Context, name string) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, &domain.ValidationError{Field: name, Message: "must be an integer"}
+	}
+	return value, nil
+}
+
+// queryTime lee una fecha opcional del query string en RFC3339 o YYYY-MM-DD
+func queryTime(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", raw, time.Local); err == nil {
+		return &t, nil
+	}
+	return nil, &domain.ValidationError{Field: name, Message: "must be RFC3339 or YYYY-MM-DD"}
+}
+
 // GetReservationStats godoc
 // @Summary Obtener estadísticas de reservas
 // @Tags reservations
 // @Produce json
-// @Success 200 {object} map[string]int
+// @Param window query string false "Ventana para tasas y tiempos (ej. 1h, 24h, 168h)" default(24h)
+// @Param group query string false "Limitar a las tiendas de un grupo (región, franquicia)"
+// @Success 200 {object} ReservationStatsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Grupo inexistente"
+// @Security ApiKeyAuth
 // @Router /reservations/stats [get]
 func (h *ReservationHandler) GetReservationStats(c *gin.Context) {
-	stats, err := h.reservationService.GetReservationStats(c.Request.Context())
+	window, err := time.ParseDuration(c.DefaultQuery("window", "24h"))
+	if err != nil || window <= 0 {
+		respondError(c, http.StatusBadRequest, "Invalid window", "window must be a positive duration (e.g. 1h, 24h, 168h)")
+		return
+	}
+
+	stats, err := h.reservationService.GetReservationStats(c.Request.Context(), window, c.Query("group"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, stats)
+}
+
+// RunReservationExpiration godoc
+// @Summary Ejecutar la expiración de reservas bajo demanda
+// @Description Ejecuta la misma pasada que el worker de expiración: reservas PENDING con el TTL vencido (reason TTL) y, si RESERVATION_LOW_PRIORITY_SHORTAGE_GRACE_MINUTES está configurado, reservas LOW de productos sin stock vendible (reason LOW_PRIORITY_SHORTAGE). Útil tras una caída del worker o un desfase de reloj. Con dry_run=true no modifica nada y retorna las reservas que se expirarían.
+// @Tags admin
+// @Produce json
+// @Param dry_run query bool false "Listar las reservas que se expirarían sin expirarlas"
+// @Success 200 {object} ReservationExpirationRunResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/reservations/expire [post]
+func (h *ReservationHandler) RunReservationExpiration(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid dry_run", "dry_run must be true or false")
+		return
+	}
+
+	run, err := h.reservationService.RunExpiration(c.Request.Context(), dryRun)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if !dryRun {
+		log.Printf("✅ Expired %d reservations on demand (%d failed)", run.Expired, run.Failed)
+	}
+	respond(c, http.StatusOK, run)
+}
+
+// GetReservationTicket godoc
+// @Summary Consultar una petición de reserva encolada (flash sale)
+// @Description QUEUED mientras espera al writer de su producto/tienda; COMPLETED con reservation_id o FAILED con error_code (INSUFFICIENT_STOCK, CUSTOMER_LIMIT_EXCEEDED...). Los tickets resueltos se conservan FLASH_SALE_TICKET_TTL_MINUTES.
+// @Tags reservations
+// @Produce json
+// @Param token path string true "Token del ticket"
+// @Success 200 {object} ReservationTicketResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reservations/tickets/{token} [get]
+func (h *ReservationHandler) GetReservationTicket(c *gin.Context) {
+	if h.flashSaleService == nil {
+		handleError(c, &domain.NotFoundError{Resource: "reservation ticket", ID: c.Param("token")})
+		return
+	}
+
+	ticket, err := h.flashSaleService.GetTicket(c.Param("token"))
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, stats)
+	respond(c, http.StatusOK, ticket)
 }
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReservationImportHandler maneja la importación de reservas desde un OMS externo
+type ReservationImportHandler struct {
+	importService *service.ReservationImportService
+}
+
+// NewReservationImportHandler crea un nuevo handler de importación de reservas
+func NewReservationImportHandler(importService *service.ReservationImportService) *ReservationImportHandler {
+	return &ReservationImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportReservationItem representa una reserva existente en el OMS externo
+type ImportReservationItem struct {
+	ExternalID  string     `json:"external_id" binding:"required" example:"OMS-778812"`
+	ProductID   string     `json:"product_id" binding:"required"`
+	StoreID     string     `json:"store_id" binding:"required" example:"MAD-001"`
+	CustomerID  string     `json:"customer_id" binding:"required"`
+	Quantity    int        `json:"quantity" binding:"required,min=1" example:"2"`
+	Status      string     `json:"status" binding:"required" enums:"PENDING,CONFIRMED,CANCELLED,EXPIRED" example:"PENDING"`
+	Priority    string     `json:"priority" enums:"LOW,NORMAL,HIGH" example:"NORMAL"`            // Opcional: NORMAL por defecto
+	Channel     string     `json:"channel" enums:"WEB,STORE,MARKETPLACE" example:"WEB"`          // Opcional
+	CreatedAt   time.Time  `json:"created_at" binding:"required" example:"2026-10-01T09:30:00Z"` // Fecha original en el OMS
+	ExpiresAt   time.Time  `json:"expires_at" example:"2026-10-20T09:30:00Z"`                    // Obligatoria para PENDING (no se aplica el TTL por defecto)
+	ConfirmedAt *time.Time `json:"confirmed_at" example:"2026-10-01T10:00:00Z"`                  // Opcional para CONFIRMED: created_at por defecto
+}
+
+// ImportReservationsRequest representa la petición de importación de reservas
+type ImportReservationsRequest struct {
+	Reservations []ImportReservationItem `json:"reservations" binding:"required,min=1,dive"`
+}
+
+// ImportReservations godoc
+// @Summary Importar reservas existentes desde un OMS externo
+// @Description Migra reservas conservando external_id, estado y fechas originales (sin TTL por defecto). Solo las PENDING incrementan Reserved (respetando asignaciones de canal y sobreventa) y emiten reservation.created. Cada reserva se importa por separado y la respuesta usa el formato multi-status común: las rechazadas quedan FAILED con su código y motivo (207) y los external_id ya importados SKIPPED, así que la importación se puede repetir. Con dry_run=true solo se valida, sin escribir nada.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "Validar sin importar"
+// @Param request body ImportReservationsRequest true "Reservas a importar (máximo 500)"
+// @Success 200 {object} ReservationImportResponse
+// @Success 207 {object} ReservationImportResponse "Alguna reserva no se pudo importar"
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reservations/import [post]
+func (h *ReservationImportHandler) ImportReservations(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid dry_run", "dry_run must be true or false")
+		return
+	}
+
+	var req ImportReservationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	items := make([]*domain.ReservationImport, 0, len(req.Reservations))
+	for _, r := range req.Reservations {
+		item := &domain.ReservationImport{
+			ExternalID:  r.ExternalID,
+			ProductID:   r.ProductID,
+			StoreID:     r.StoreID,
+			CustomerID:  r.CustomerID,
+			Quantity:    r.Quantity,
+			Status:      domain.ReservationStatus(r.Status),
+			Priority:    domain.ReservationPriority(r.Priority),
+			Channel:     domain.SalesChannel(r.Channel),
+			CreatedAt:   r.CreatedAt,
+			ExpiresAt:   r.ExpiresAt,
+			ConfirmedAt: r.ConfirmedAt,
+		}
+		items = append(items, item)
+	}
+
+	report, err := h.importService.ImportReservations(c.Request.Context(), items, dryRun)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response := ReservationImportResponse{DryRun: report.DryRun, MultiStatusResponse: newMultiStatus(len(report.Results))}
+	for i, result := range report.Results {
+		item := ReservationImportItem{ExternalID: result.ExternalID, ReservationID: result.ReservationID}
+		switch result.Outcome {
+		case domain.ReservationImportImported:
+			response.Succeed(i, http.StatusCreated, item)
+		case domain.ReservationImportValid:
+			response.Succeed(i, http.StatusOK, item)
+		case domain.ReservationImportSkipped:
+			response.Skip(i, item)
+		default:
+			response.Fail(i, result.Err, item)
+		}
+	}
+
+	respondMultiStatus(c, &response.MultiStatusResponse, response)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReservationIntentHandler maneja las intenciones de reserva (add-to-cart sin bloquear stock)
+type ReservationIntentHandler struct {
+	intentService *service.ReservationIntentService
+	unitService   *service.ProductUnitService // Opcional: cantidades en unidades de pedido (BOX, CASE...)
+}
+
+// NewReservationIntentHandler crea un nuevo handler de intenciones de reserva
+func NewReservationIntentHandler(intentService *service.ReservationIntentService) *ReservationIntentHandler {
+	return &ReservationIntentHandler{
+		intentService: intentService,
+	}
+}
+
+// SetProductUnitService habilita cantidad + unidad en las peticiones (se convierten a la unidad base)
+func (h *ReservationIntentHandler) SetProductUnitService(unitService *service.ProductUnitService) {
+	h.unitService = unitService
+}
+
+// CreateReservationIntentRequest representa la petición para registrar una intención de reserva
+type CreateReservationIntentRequest struct {
+	ProductID  string `json:"product_id" binding:"required"`
+	StoreID    string `json:"store_id" binding:"required"`
+	CustomerID string `json:"customer_id" binding:"required"`
+	Quantity   int    `json:"quantity" binding:"required,min=1"`
+	Unit       string `json:"unit" example:"BOX"` // Opcional: unidad base del producto por defecto
+}
+
+// ConvertReservationIntentRequest representa la conversión de una intención en reserva
+type ConvertReservationIntentRequest struct {
+	TTLMinutes int `json:"ttl_minutes" binding:"omitempty,min=1"` // Opcional: TTL por defecto/máximo según configuración
+}
+
+// CreateReservationIntent godoc
+// @Summary Registrar una intención de reserva (sin bloquear stock)
+// @Description Consulta la disponibilidad y registra el interés del cliente sin incrementar reserved. Devuelve un token válido RESERVATION_INTENT_TTL_MINUTES que se convierte en reserva con POST /reservations/intents/{token}/convert. Se registra aunque no haya stock suficiente (available = false).
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param request body CreateReservationIntentRequest true "Producto, tienda, cliente y cantidad"
+// @Success 201 {object} ReservationIntentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Producto o stock inexistente"
+// @Failure 409 {object} ErrorResponse "Producto no vendible"
+// @Security ApiKeyAuth
+// @Router /reservations/intents [post]
+func (h *ReservationIntentHandler) CreateReservationIntent(c *gin.Context) {
+	var req CreateReservationIntentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	quantity, err := toBaseQuantity(c, h.unitService, req.ProductID, req.Quantity, req.Unit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	intent, err := h.intentService.CreateIntent(c.Request.Context(), &domain.ReservationIntent{
+		ProductID:  req.ProductID,
+		StoreID:    req.StoreID,
+		CustomerID: req.CustomerID,
+		Quantity:   quantity,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, intent)
+}
+
+// GetReservationIntent godoc
+// @Summary Consultar una intención de reserva
+// @Tags reservations
+// @Produce json
+// @Param token path string true "Token de la intención"
+// @Success 200 {object} ReservationIntentResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reservations/intents/{token} [get]
+func (h *ReservationIntentHandler) GetReservationIntent(c *gin.Context) {
+	intent, err := h.intentService.GetIntent(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, intent)
+}
+
+// ConvertReservationIntent godoc
+// @Summary Convertir una intención en reserva
+// @Description Crea la reserva (bloquea el stock) con los datos de la intención. El stock se vuelve a comprobar; cada intención se convierte una sola vez y no después de expires_at (409 Invalid State).
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param token path string true "Token de la intención"
+// @Param request body ConvertReservationIntentRequest false "TTL opcional de la reserva"
+// @Success 201 {object} ReservationResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Intención convertida o caducada, o stock insuficiente"
+// @Failure 429 {object} ErrorResponse "Límite de reservas del cliente superado (anti-acaparamiento)"
+// @Security ApiKeyAuth
+// @Router /reservations/intents/{token}/convert [post]
+func (h *ReservationIntentHandler) ConvertReservationIntent(c *gin.Context) {
+	var req ConvertReservationIntentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	reservation, err := h.intentService.ConvertIntent(c.Request.Context(), c.Param("token"), req.TTLMinutes)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reservation)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReservedReconciliationHandler maneja la reconciliación de stock.reserved con las reservas abiertas
+type ReservedReconciliationHandler struct {
+	reconciliationService *service.ReservedReconciliationService
+}
+
+// NewReservedReconciliationHandler crea un nuevo handler de reconciliación de reservado
+func NewReservedReconciliationHandler(reconciliationService *service.ReservedReconciliationService) *ReservedReconciliationHandler {
+	return &ReservedReconciliationHandler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// ReconcileReserved godoc
+// @Summary Reconciliar el stock reservado con las reservas abiertas
+// @Description Recalcula reserved de cada producto y tienda como la suma de sus reservas PENDING y reporta las diferencias. Por defecto solo informa; con apply=true corrige cada fila y emite stock.reserved_corrected (queda en la cadena de auditoría). Las filas que cambian mientras se reconcilia se omiten.
+// @Tags admin
+// @Produce json
+// @Param store_id query string false "Limitar a una tienda"
+// @Param apply query bool false "Aplicar las correcciones (default: false)"
+// @Success 200 {object} domain.ReservedReconciliation
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stock/reconcile-reserved [post]
+func (h *ReservedReconciliationHandler) ReconcileReserved(c *gin.Context) {
+	apply, err := strconv.ParseBool(c.DefaultQuery("apply", "false"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid apply", err.Error())
+		return
+	}
+
+	result, err := h.reconciliationService.ReconcileReserved(c.Request.Context(), c.Query("store_id"), apply)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if apply {
+		log.Printf("🧮 Reserved reconciliation: %d discrepancies, %d corrected", len(result.Discrepancies), result.Corrected)
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterCoreRoutes registra los endpoints de productos, stock y reservas.
+// Se comparte entre /api/v1 y /api/v2: los handlers son los mismos y solo cambia
+// el serializer registrado en el grupo (ver UseSerializer).
+func RegisterCoreRoutes(
+	api *gin.RouterGroup,
+	apiKeyAuth gin.HandlerFunc,
+	productHandler *ProductHandler,
+	stockHandler *StockHandler,
+	reservationHandler *ReservationHandler,
+) {
+	// Product endpoints (lectura pública, escritura protegida)
+	products := api.Group("/products")
+	{
+		// Públicos (sin API Key)
+		products.GET("", productHandler.ListProducts)
+		products.GET("/search", productHandler.SearchProducts)
+		products.GET("/:id", productHandler.GetProduct)
+		products.GET("/sku/:sku", productHandler.GetProductBySKU)
+
+		// Protegidos (requieren API Key)
+		products.POST("", apiKeyAuth, productHandler.CreateProduct)
+		products.PUT("/:id", apiKeyAuth, productHandler.UpdateProduct)
+		products.PATCH("/:id", apiKeyAuth, productHandler.PatchProduct)
+		products.PUT("/sku/:sku", apiKeyAuth, productHandler.UpsertProductBySKU)
+		products.DELETE("/:id", apiKeyAuth, productHandler.DeleteProduct)
+		products.PUT("/:id/status", apiKeyAuth, productHandler.ChangeProductStatus)
+	}
+
+	// Stock endpoints (todos protegidos)
+	stock := api.Group("/stock", apiKeyAuth)
+	{
+		stock.POST("", stockHandler.InitializeStock)
+		stock.GET("/product/:productId", stockHandler.GetAllStockByProduct)
+		stock.GET("/store/:storeId", stockHandler.GetAllStockByStore)
+		stock.GET("/low-stock", stockHandler.GetLowStockItems)
+		stock.GET("/compare", stockHandler.CompareStores)
+		stock.GET("/:productId/:storeId", stockHandler.GetStockByProductAndStore)
+		stock.GET("/:productId/:storeId/availability", stockHandler.CheckAvailability)
+		stock.PUT("/:productId/:storeId", stockHandler.UpdateStock)
+		stock.POST("/:productId/:storeId/adjust", stockHandler.AdjustStock)
+		stock.POST("/transfer", stockHandler.TransferStock)
+	}
+
+	// Reservation endpoints (todos protegidos)
+	reservations := api.Group("/reservations", apiKeyAuth)
+	{
+		reservations.POST("", reservationHandler.CreateReservation)
+		reservations.GET("/:id", reservationHandler.GetReservation)
+		reservations.POST("/:id/confirm", reservationHandler.ConfirmReservation)
+		reservations.POST("/:id/cancel", reservationHandler.CancelReservation)
+		reservations.POST("/:id/ready-for-pickup", reservationHandler.MarkReadyForPickup)
+		reservations.POST("/:id/pickup", reservationHandler.PickUpReservation)
+		reservations.GET("/store/:storeId/pending", reservationHandler.GetPendingByStore)
+		reservations.GET("/product/:productId/store/:storeId", reservationHandler.GetReservationsByProduct)
+		reservations.GET("/stats", reservationHandler.GetReservationStats)
+		reservations.GET("/tickets/:token", reservationHandler.GetReservationTicket)
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RunDownHandler maneja la descatalogación de productos
+type RunDownHandler struct {
+	rundownService *service.RunDownService
+}
+
+// NewRunDownHandler crea un nuevo handler de descatalogación
+func NewRunDownHandler(rundownService *service.RunDownService) *RunDownHandler {
+	return &RunDownHandler{
+		rundownService: rundownService,
+	}
+}
+
+// DiscontinueProductRequest representa la petición de descatalogación
+type DiscontinueProductRequest struct {
+	Reason string `json:"reason"` // Opcional: motivo de la descatalogación
+}
+
+// DiscontinueProduct godoc
+// @Summary Descatalogar un producto y generar su plan de run-down
+// @Description Bloquea la reposición (ajustes positivos, alta de stock y transferencias), mantiene las reservas hasta agotar el stock de cada tienda y archiva la entrada de surtido al agotarse
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param request body DiscontinueProductRequest false "Motivo"
+// @Success 201 {object} ProductRunDownResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "El producto ya está descatalogado"
+// @Security ApiKeyAuth
+// @Router /products/{id}/discontinue [post]
+func (h *RunDownHandler) DiscontinueProduct(c *gin.Context) {
+	var req DiscontinueProductRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	rundown, err := h.rundownService.Discontinue(c.Request.Context(), c.Param("id"), req.Reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rundown)
+}
+
+// GetRunDown godoc
+// @Summary Obtener el avance del run-down de un producto descatalogado
+// @Tags products
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Success 200 {object} ProductRunDownResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/rundown [get]
+func (h *RunDownHandler) GetRunDown(c *gin.Context) {
+	rundown, err := h.rundownService.GetRunDown(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rundown)
+}
@@ -0,0 +1,954 @@
+package handler
+
+import (
+	"time"
+
+	"inventory-system/internal/handler/dto"
+)
+
+// Este archivo define los tipos que documentan los schemas de la spec OpenAPI
+// (anotaciones @Param/@Success de los endpoints core). Reflejan el formato v1 de
+// cada respuesta para que la spec no dependa de los structs de dominio: si cambia
+// un campo en el dominio, el contrato documentado no cambia sin que se note.
+
+// ProductRequest representa el body de creación/actualización de un producto (es el mismo
+// tipo con el que se decodifica el request)
+type ProductRequest = dto.ProductRequest
+
+// ProductPatchRequest representa el body de la actualización parcial de un producto.
+// Todos los campos son opcionales; los omitidos conservan su valor.
+type ProductPatchRequest struct {
+	SKU         *string  `json:"sku,omitempty" example:"PROD-001"`
+	Name        *string  `json:"name,omitempty" example:"Laptop HP Pavilion 15"`
+	Description *string  `json:"description,omitempty" example:"Laptop de 15 pulgadas"`
+	Category    *string  `json:"category,omitempty" example:"electronics"`
+	Price       *float64 `json:"price,omitempty" example:"849.99"`
+}
+
+// ProductUpsertResponse representa el resultado de PUT /products/sku/{sku}
+type ProductUpsertResponse struct {
+	Created bool            `json:"created" example:"true"` // true si el SKU no existía y se creó el producto
+	Product ProductResponse `json:"product"`
+}
+
+// ProductResponse representa un producto del catálogo
+type ProductResponse struct {
+	ID          string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SKU         string    `json:"sku" example:"PROD-001"`
+	Name        string    `json:"name" example:"Laptop HP Pavilion 15"`
+	Description string    `json:"description" example:"Laptop de 15 pulgadas"`
+	Category    string    `json:"category" example:"electronics"`
+	Price       float64   `json:"price" example:"899.99"`
+	Status      string    `json:"status" example:"ACTIVE"`
+	ABCClass    string    `json:"abcClass" example:"A"` // A, B o C; vacía hasta la primera clasificación
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	Media  []ProductMediaResponse `json:"media,omitempty"`               // Imágenes en orden de presentación
+	Locale string                 `json:"locale,omitempty" example:"es"` // Idioma de name/description
+}
+
+// ProductListResponse representa un listado paginado de productos
+type ProductListResponse struct {
+	Data   []ProductResponse `json:"data"`
+	Total  int               `json:"total" example:"5"`
+	Limit  int               `json:"limit" example:"10"`
+	Offset int               `json:"offset" example:"0"`
+}
+
+// ProductArchiveResponse representa el resultado de eliminar un producto con force=true
+type ProductArchiveResponse struct {
+	ProductID            string    `json:"product_id"`
+	SKU                  string    `json:"sku" example:"PROD-001"`
+	ArchivedStock        int       `json:"archived_stock" example:"4"`
+	ArchivedReservations int       `json:"archived_reservations" example:"12"`
+	ArchivedAt           time.Time `json:"archived_at"`
+}
+
+// ProductInUseErrorResponse representa el 409 al eliminar un producto con stock o reservas pendientes
+type ProductInUseErrorResponse struct {
+	Error     string                   `json:"error" example:"Product In Use"`
+	Message   string                   `json:"message"`
+	RequestID string                   `json:"request_id,omitempty"`
+	Details   ProductDependenciesEntry `json:"details"`
+}
+
+// ProductDependenciesEntry resume el stock y las reservas que impiden eliminar un producto
+type ProductDependenciesEntry struct {
+	ProductID           string   `json:"product_id"`
+	StockUnits          int      `json:"stock_units" example:"45"`
+	ReservedUnits       int      `json:"reserved_units" example:"3"`
+	Stores              []string `json:"stores" example:"MAD-001,BCN-001"`
+	PendingReservations int      `json:"pending_reservations" example:"2"`
+}
+
+// InsufficientStockErrorResponse representa el 409 al reservar sin stock suficiente
+type InsufficientStockErrorResponse struct {
+	Error     string             `json:"error" example:"Insufficient Stock"`
+	Message   string             `json:"message"`
+	RequestID string             `json:"request_id,omitempty"`
+	Details   StockShortageEntry `json:"details"`
+}
+
+// StockShortageEntry diagnostica la falta de stock: caducidades próximas y preventa posible
+type StockShortageEntry struct {
+	Quantity          int        `json:"quantity" example:"10"`
+	Reserved          int        `json:"reserved" example:"8"`
+	Available         int        `json:"available" example:"2"`
+	Requested         int        `json:"requested" example:"5"`
+	NextExpiryAt      *time.Time `json:"next_expiry_at,omitempty"`
+	NextExpiryUnits   int        `json:"next_expiry_units,omitempty" example:"3"`
+	SufficientAt      *time.Time `json:"sufficient_at,omitempty"`
+	PreorderAvailable int        `json:"preorder_available" example:"0"`
+	PreorderPossible  bool       `json:"preorder_possible"`
+}
+
+// ProductRunDownResponse representa el plan de run-down de un producto descatalogado
+type ProductRunDownResponse struct {
+	ProductID   string                 `json:"product_id"`
+	Status      string                 `json:"status" enums:"IN_PROGRESS,COMPLETED"`
+	Reason      string                 `json:"reason,omitempty" example:"Fin de ciclo de vida"`
+	Stores      []RunDownStoreResponse `json:"stores"`
+	Progress    RunDownProgressEntry   `json:"progress"`
+	CreatedAt   time.Time              `json:"created_at"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+}
+
+// RunDownStoreResponse representa el avance del run-down en una tienda
+type RunDownStoreResponse struct {
+	StoreID         string     `json:"store_id" example:"BCN-001"`
+	InitialQuantity int        `json:"initial_quantity" example:"25"`
+	Quantity        int        `json:"quantity" example:"12"`
+	Reserved        int        `json:"reserved" example:"1"`
+	Status          string     `json:"status" enums:"RUNNING_DOWN,ARCHIVED"`
+	ArchivedAt      *time.Time `json:"archived_at,omitempty"`
+}
+
+// RunDownProgressEntry resume el avance del plan
+type RunDownProgressEntry struct {
+	Stores         int     `json:"stores" example:"4"`
+	ArchivedStores int     `json:"archived_stores" example:"1"`
+	InitialUnits   int     `json:"initial_units" example:"63"`
+	RemainingUnits int     `json:"remaining_units" example:"30"`
+	Percent        float64 `json:"percent" example:"52.4"`
+}
+
+// PriceHistoryResponse representa el historial de precios paginado de un producto
+type PriceHistoryResponse struct {
+	ProductID string                `json:"product_id"`
+	Items     []PriceChangeResponse `json:"items"`
+	Total     int                   `json:"total" example:"3"`
+	Limit     int                   `json:"limit" example:"50"`
+	Offset    int                   `json:"offset" example:"0"`
+}
+
+// PriceChangeResponse representa un cambio de precio registrado
+type PriceChangeResponse struct {
+	ID          string    `json:"id"`
+	ProductID   string    `json:"product_id"`
+	OldPrice    float64   `json:"old_price" example:"899.99"`
+	NewPrice    float64   `json:"new_price" example:"799.99"`
+	Actor       string    `json:"actor" example:"store-MAD-001"`
+	Source      string    `json:"source" enums:"MANUAL,SCHEDULED"`
+	ScheduledID string    `json:"scheduled_id,omitempty"`
+	EffectiveAt time.Time `json:"effective_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ScheduledPriceChangeResponse representa un cambio de precio programado
+type ScheduledPriceChangeResponse struct {
+	ID          string     `json:"id"`
+	ProductID   string     `json:"product_id"`
+	NewPrice    float64    `json:"new_price" example:"799.99"`
+	Actor       string     `json:"actor" example:"store-MAD-001"`
+	Status      string     `json:"status" enums:"PENDING,APPLIED,CANCELLED"`
+	EffectiveAt time.Time  `json:"effective_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
+
+// ScheduledPriceChangeListResponse representa los cambios programados de un producto
+type ScheduledPriceChangeListResponse struct {
+	ProductID string                         `json:"product_id"`
+	Items     []ScheduledPriceChangeResponse `json:"items"`
+	Count     int                            `json:"count" example:"1"`
+}
+
+// ProductMediaResponse representa una imagen de un producto
+type ProductMediaResponse struct {
+	ID          string    `json:"id"`
+	ProductID   string    `json:"product_id"`
+	URL         string    `json:"url" example:"/media/products/550e8400-e29b-41d4-a716-446655440000/3f1c.jpg"`
+	ContentType string    `json:"content_type" example:"image/jpeg"`
+	SizeBytes   int64     `json:"size_bytes" example:"183204"`
+	AltText     string    `json:"alt_text" example:"Vista frontal"`
+	Position    int       `json:"position" example:"0"` // 0 = imagen principal
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ProductMediaListResponse representa las imágenes de un producto
+type ProductMediaListResponse struct {
+	ProductID string                 `json:"product_id"`
+	Items     []ProductMediaResponse `json:"items"`
+	Count     int                    `json:"count" example:"2"`
+}
+
+// ProductMediaPatchRequest representa el body para cambiar los metadatos de una imagen
+type ProductMediaPatchRequest struct {
+	AltText  *string `json:"alt_text,omitempty" example:"Vista lateral"`
+	Position *int    `json:"position,omitempty" example:"1"`
+}
+
+// ProductTranslationResponse representa la traducción de un producto a un idioma
+type ProductTranslationResponse struct {
+	ProductID   string    `json:"product_id"`
+	Locale      string    `json:"locale" example:"ca"`
+	Name        string    `json:"name" example:"Portàtil HP Pavilion 15"`
+	Description string    `json:"description" example:"Portàtil de 15 polzades"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ProductTranslationListResponse representa las traducciones de un producto
+type ProductTranslationListResponse struct {
+	ProductID     string                       `json:"product_id"`
+	DefaultLocale string                       `json:"default_locale" example:"es"`
+	Items         []ProductTranslationResponse `json:"items"`
+	Count         int                          `json:"count" example:"2"`
+}
+
+// ProductUnitsResponse representa la unidad base y las conversiones de un producto
+type ProductUnitsResponse struct {
+	ProductID   string                `json:"product_id"`
+	BaseUnit    string                `json:"base_unit" example:"EACH"`
+	Conversions []UnitConversionEntry `json:"conversions"`
+	Rounding    string                `json:"rounding" enums:"NEAREST,DOWN,UP,EXACT" example:"NEAREST"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// UnitConversionEntry representa una unidad de pedido y sus unidades base
+type UnitConversionEntry struct {
+	Unit       string `json:"unit" example:"BOX"`
+	Factor     int    `json:"factor" example:"12"`
+	Fractional bool   `json:"fractional,omitempty"` // Admite cantidades decimales (p. ej. 1.25 KG con base G)
+}
+
+// StockResponse representa el stock de un producto en una tienda
+type StockResponse struct {
+	ID          string    `json:"id" example:"stock-mad-001"`
+	ProductID   string    `json:"productId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	StoreID     string    `json:"storeId" example:"MAD-001"`
+	Quantity    int       `json:"quantity" example:"10"`
+	Reserved    int       `json:"reserved" example:"2"` // Reservas de clientes y retenciones internas
+	Held        int       `json:"held" example:"1"`     // Parte de reserved retenida para uso interno (exposición, reparación, calidad)
+	MinStock    int       `json:"minStock" example:"5"`
+	MaxStock    int       `json:"maxStock" example:"0"`
+	SafetyStock int       `json:"safetyStock" example:"2"`
+	Version     int       `json:"version" example:"1"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// StockByProductResponse representa el stock de un producto en todas las tiendas
+type StockByProductResponse struct {
+	ProductID      string          `json:"product_id"`
+	Stores         []StockResponse `json:"stores"`
+	TotalQuantity  int             `json:"total_quantity"`
+	TotalReserved  int             `json:"total_reserved"`
+	TotalHeld      int             `json:"total_held"` // Parte de total_reserved retenida para uso interno
+	TotalAvailable int             `json:"total_available"`
+	TotalSellable  int             `json:"total_sellable"` // Sin el stock de seguridad
+}
+
+// StockByStoreResponse representa todo el stock de una tienda
+type StockByStoreResponse struct {
+	StoreID string          `json:"store_id"`
+	Items   []StockResponse `json:"items"`
+	Count   int             `json:"count"`
+}
+
+// LowStockResponse representa los productos por debajo del umbral
+type LowStockResponse struct {
+	Threshold int             `json:"threshold" example:"10"`
+	StoreID   string          `json:"store_id" example:"MAD-001"`
+	Category  string          `json:"category" example:"electronics"`
+	ABCClass  string          `json:"abc_class" example:"A"`
+	Items     []StockResponse `json:"items"`
+	Count     int             `json:"count"`
+	Total     int             `json:"total" example:"12"`
+	Limit     int             `json:"limit" example:"50"`
+	Offset    int             `json:"offset" example:"0"`
+}
+
+// ABCClassificationRunResponse representa el resumen de una pasada de clasificación ABC
+type ABCClassificationRunResponse struct {
+	WindowDays   int       `json:"window_days" example:"90"`
+	Since        time.Time `json:"since"`
+	Products     int       `json:"products" example:"120"`
+	ClassA       int       `json:"class_a" example:"18"`
+	ClassB       int       `json:"class_b" example:"27"`
+	ClassC       int       `json:"class_c" example:"75"`
+	ClassifiedAt time.Time `json:"classified_at"`
+}
+
+// AvailabilityViewRebuildResponse representa el resumen de una reconstrucción del read model de disponibilidad
+type AvailabilityViewRebuildResponse struct {
+	Rows      int       `json:"rows" example:"480"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration" example:"35ms"`
+}
+
+// SearchReindexResponse representa el resumen de una reconstrucción del índice de búsqueda
+type SearchReindexResponse struct {
+	Backend   string    `json:"backend" example:"meilisearch"`
+	Indexed   int       `json:"indexed" example:"120"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration" example:"1.204s"`
+}
+
+// OutOfStockResponse representa el listado de filas de stock sin disponibilidad
+type OutOfStockResponse struct {
+	StoreID     string                   `json:"store_id,omitempty" example:"VAL-001"`
+	GroupID     string                   `json:"group_id,omitempty" example:"region-levante"`
+	GeneratedAt time.Time                `json:"generated_at"`
+	Items       []OutOfStockItemResponse `json:"items"`
+	Count       int                      `json:"count"`
+	Oversold    int                      `json:"oversold" example:"0"`
+}
+
+// OutOfStockItemResponse representa un producto sin disponibilidad en una tienda
+type OutOfStockItemResponse struct {
+	ProductID      string    `json:"product_id"`
+	SKU            string    `json:"sku" example:"PROD-003"`
+	Name           string    `json:"name"`
+	Category       string    `json:"category" example:"electronics"`
+	StoreID        string    `json:"store_id" example:"VAL-001"`
+	Quantity       int       `json:"quantity" example:"0"`
+	Reserved       int       `json:"reserved" example:"0"`
+	Held           int       `json:"held" example:"0"` // Parte de reserved retenida para uso interno
+	Available      int       `json:"available" example:"0"`
+	OutSince       time.Time `json:"out_since"`
+	OutForSeconds  int64     `json:"out_for_seconds" example:"86400"`
+	OutSinceSource string    `json:"out_since_source" enums:"movement,stock_row"`
+	Oversold       bool      `json:"oversold"`
+}
+
+// StockTransferResponse representa el resultado de una transferencia inmediata
+type StockTransferResponse struct {
+	Message     string  `json:"message" example:"Stock transferred successfully"`
+	ProductID   string  `json:"product_id"`
+	FromStoreID string  `json:"from_store_id" example:"MAD-001"`
+	ToStoreID   string  `json:"to_store_id" example:"BCN-001"`
+	Quantity    float64 `json:"quantity" example:"5"` // En la unidad de la petición
+}
+
+// ChannelAllocationsResponse representa el reparto del stock vendible de una fila entre canales
+type ChannelAllocationsResponse struct {
+	ProductID   string                   `json:"product_id"`
+	StoreID     string                   `json:"store_id" example:"MAD-001"`
+	Sellable    int                      `json:"sellable" example:"30"`    // quantity - reserved - safety_stock
+	Unallocated int                      `json:"unallocated" example:"10"` // Vendible sin asignar a ningún canal
+	Allocations []ChannelAllocationEntry `json:"allocations"`
+	Count       int                      `json:"count" example:"2"`
+}
+
+// ChannelAllocationEntry representa las unidades asignadas a un canal de venta
+type ChannelAllocationEntry struct {
+	ProductID string    `json:"product_id"`
+	StoreID   string    `json:"store_id" example:"MAD-001"`
+	Channel   string    `json:"channel" enums:"WEB,STORE,MARKETPLACE" example:"WEB"`
+	Allocated int       `json:"allocated" example:"20"` // Se descuenta al confirmar reservas del canal
+	Reserved  int       `json:"reserved" example:"5"`   // Reservas pendientes del canal contra la asignación
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ScheduledStockChangeResponse representa un cambio de stock programado
+type ScheduledStockChangeResponse struct {
+	ID          string     `json:"id"`
+	ProductID   string     `json:"product_id"`
+	StoreID     string     `json:"store_id" example:"MAD-001"`
+	Type        string     `json:"type" enums:"ADJUST,SET" example:"ADJUST"`
+	Quantity    int        `json:"quantity" example:"500"`
+	Actor       string     `json:"actor" example:"store-MAD-001"`
+	Source      string     `json:"source" enums:"MANUAL,PURCHASE_ORDER,TRANSFER" example:"PURCHASE_ORDER"`
+	Reference   string     `json:"reference,omitempty" example:"PO-2026-0142"` // Nº de pedido de compra o de transferencia
+	Status      string     `json:"status" enums:"PENDING,APPLIED,CANCELLED,FAILED"`
+	Error       string     `json:"error,omitempty"` // Motivo del rechazo (FAILED)
+	EffectiveAt time.Time  `json:"effective_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
+
+// ScheduledStockChangeListResponse representa los cambios programados de una fila de stock
+type ScheduledStockChangeListResponse struct {
+	ProductID string                         `json:"product_id"`
+	StoreID   string                         `json:"store_id" example:"MAD-001"`
+	Items     []ScheduledStockChangeResponse `json:"items"`
+	Count     int                            `json:"count" example:"1"`
+}
+
+// StockAdjustmentResponse representa un ajuste manual de stock pendiente de aprobación
+type StockAdjustmentResponse struct {
+	ID              string     `json:"id"`
+	ProductID       string     `json:"product_id"`
+	StoreID         string     `json:"store_id" example:"MAD-001"`
+	Type            string     `json:"type" enums:"ADJUST,SET" example:"ADJUST"`
+	Quantity        int        `json:"quantity" example:"-150"`        // Ajuste (ADJUST) o cantidad final (SET)
+	CurrentQuantity int        `json:"current_quantity" example:"400"` // Cantidad de la fila al solicitarlo
+	Reason          string     `json:"reason,omitempty" example:"damaged"`
+	RequestedBy     string     `json:"requested_by" example:"store-MAD-001"`
+	Status          string     `json:"status" enums:"PENDING,APPROVED,REJECTED"`
+	ReviewedBy      string     `json:"reviewed_by,omitempty" example:"Logística"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// StockAdjustmentListResponse representa una página de ajustes de stock
+type StockAdjustmentListResponse struct {
+	Items  []StockAdjustmentResponse `json:"items"`
+	Count  int                       `json:"count" example:"1"`
+	Limit  int                       `json:"limit" example:"50"`
+	Offset int                       `json:"offset" example:"0"`
+}
+
+// StockAdjustmentApprovalResponse representa un ajuste aprobado y la fila de stock resultante
+type StockAdjustmentApprovalResponse struct {
+	Adjustment StockAdjustmentResponse `json:"adjustment"`
+	Stock      StockResponse           `json:"stock"`
+}
+
+// AdjustmentReasonResponse representa un motivo del catálogo de ajustes de stock
+type AdjustmentReasonResponse struct {
+	Code        string    `json:"code" example:"damaged"`
+	Description string    `json:"description" example:"Producto dañado o roto"`
+	Active      bool      `json:"active" example:"true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AdjustmentReasonListResponse representa el catálogo de motivos de ajuste
+type AdjustmentReasonListResponse struct {
+	Reasons []AdjustmentReasonResponse `json:"reasons"`
+	Count   int                        `json:"count" example:"4"`
+}
+
+// StockHoldResponse representa una retención interna de stock (exposición, reparación, calidad)
+type StockHoldResponse struct {
+	ID         string     `json:"id"`
+	ProductID  string     `json:"product_id"`
+	StoreID    string     `json:"store_id" example:"MAD-001"`
+	Type       string     `json:"type" enums:"DISPLAY,REPAIR,QUALITY_HOLD" example:"DISPLAY"`
+	Quantity   int        `json:"quantity" example:"1"`
+	Note       string     `json:"note,omitempty" example:"Unidad del escaparate"`
+	Status     string     `json:"status" enums:"ACTIVE,RELEASED"`
+	CreatedBy  string     `json:"created_by" example:"store-MAD-001"`
+	ReleasedBy string     `json:"released_by,omitempty" example:"store-MAD-001"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+}
+
+// StockHoldListResponse representa una página de retenciones internas de stock
+type StockHoldListResponse struct {
+	Items  []StockHoldResponse `json:"items"`
+	Count  int                 `json:"count" example:"1"`
+	Limit  int                 `json:"limit" example:"50"`
+	Offset int                 `json:"offset" example:"0"`
+}
+
+// StockHistoryEntryResponse representa un paso de la línea temporal de una fila de stock
+type StockHistoryEntryResponse struct {
+	Seq               int64               `json:"seq" example:"1042"`
+	EventID           string              `json:"event_id"`
+	EventType         string              `json:"event_type" example:"reservation.confirmed"`
+	At                time.Time           `json:"at"`
+	Actor             string              `json:"actor,omitempty" example:"store-MAD-001"`
+	CorrelationID     string              `json:"correlation_id,omitempty"`
+	QuantityDelta     int                 `json:"quantity_delta" example:"-5"`
+	ReservedDelta     int                 `json:"reserved_delta" example:"-5"`
+	Quantity          int                 `json:"quantity" example:"20"` // Después del evento
+	Reserved          int                 `json:"reserved" example:"2"`  // Después del evento
+	Version           int                 `json:"version" example:"7"`   // Después del evento
+	Reason            string              `json:"reason,omitempty" example:"DAMAGED"`
+	Reference         string              `json:"reference,omitempty"` // Reserva, retención o ajuste relacionado
+	UntrackedQuantity int                 `json:"untracked_quantity,omitempty"`
+	UntrackedReserved int                 `json:"untracked_reserved,omitempty"`
+	Notes             []StockNoteResponse `json:"notes,omitempty"` // Notas del personal sobre este movimiento
+}
+
+// StockHistoryResponse representa la línea temporal de una fila de stock (más reciente primero)
+type StockHistoryResponse struct {
+	ProductID string                      `json:"product_id"`
+	StoreID   string                      `json:"store_id" example:"MAD-001"`
+	Quantity  int                         `json:"quantity" example:"20"`
+	Reserved  int                         `json:"reserved" example:"2"`
+	Version   int                         `json:"version" example:"7"`
+	Entries   []StockHistoryEntryResponse `json:"entries"`
+	Count     int                         `json:"count" example:"1"`
+	HasMore   bool                        `json:"has_more" example:"false"`
+	Notes     []StockNoteResponse         `json:"notes,omitempty"` // Notas sobre la fila en el tramo de la página
+}
+
+// StockNoteAttachmentResponse representa los metadatos de una foto o documento adjunto a una nota
+type StockNoteAttachmentResponse struct {
+	URL         string `json:"url" example:"https://files.example.com/mad-001/albaran-4411.jpg"`
+	FileName    string `json:"file_name,omitempty" example:"albaran-4411.jpg"`
+	ContentType string `json:"content_type,omitempty" example:"image/jpeg"`
+	SizeBytes   int64  `json:"size_bytes,omitempty" example:"482133"`
+}
+
+// StockNoteResponse representa una nota del personal sobre una fila de stock o un movimiento
+type StockNoteResponse struct {
+	ID          string                        `json:"id"`
+	ProductID   string                        `json:"product_id"`
+	StoreID     string                        `json:"store_id" example:"MAD-001"`
+	EventID     string                        `json:"event_id,omitempty"` // Movimiento al que se refiere (vacío = la fila)
+	Text        string                        `json:"text" example:"Caja dañada, faltan 3 unidades"`
+	Attachments []StockNoteAttachmentResponse `json:"attachments,omitempty"`
+	CreatedBy   string                        `json:"created_by" example:"store-MAD-001"`
+	CreatedAt   time.Time                     `json:"created_at"`
+}
+
+// StockNoteListResponse representa las notas de una fila de stock (más reciente primero)
+type StockNoteListResponse struct {
+	Items []StockNoteResponse `json:"items"`
+	Count int                 `json:"count" example:"1"`
+}
+
+// ReservationImportItem representa los datos de una reserva en el resultado de la importación
+type ReservationImportItem struct {
+	ExternalID    string `json:"external_id" example:"OMS-778812"`
+	ReservationID string `json:"reservation_id,omitempty"` // Reserva creada o la de la importación anterior (SKIPPED)
+}
+
+// ReservationImportResponse representa el resultado de una importación de reservas: el formato
+// multi-status común con data de tipo ReservationImportItem
+type ReservationImportResponse struct {
+	DryRun bool `json:"dry_run" example:"false"`
+	MultiStatusResponse
+}
+
+// ReservationExpirationResponse representa una reserva expirada en una pasada de expiración
+type ReservationExpirationResponse struct {
+	ReservationID string    `json:"reservation_id"`
+	ProductID     string    `json:"product_id"`
+	StoreID       string    `json:"store_id" example:"MAD-001"`
+	CustomerID    string    `json:"customer_id"`
+	Quantity      int       `json:"quantity" example:"2"`
+	Priority      string    `json:"priority" enums:"LOW,NORMAL,HIGH"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Reason        string    `json:"reason" enums:"TTL,LOW_PRIORITY_SHORTAGE"`
+	Error         string    `json:"error,omitempty"` // Motivo si no se pudo expirar
+}
+
+// ReservationExpirationRunResponse representa el resumen de una pasada de expiración de reservas
+type ReservationExpirationRunResponse struct {
+	DryRun       bool                            `json:"dry_run" example:"true"`
+	RanAt        time.Time                       `json:"ran_at"`
+	Expired      int                             `json:"expired" example:"3"` // Con dry_run: reservas que se expirarían
+	Failed       int                             `json:"failed" example:"0"`
+	Reservations []ReservationExpirationResponse `json:"reservations"`
+}
+
+// AvailabilityResponse representa el resultado de una verificación de disponibilidad
+type AvailabilityResponse struct {
+	ProductID  string `json:"product_id"`
+	StoreID    string `json:"store_id"`
+	Requested  int    `json:"requested" example:"3"`
+	Available  int    `json:"available" example:"8"`
+	Sufficient bool   `json:"sufficient" example:"true"`
+}
+
+// RouteLatencyResponse representa las latencias de una ruta en la ventana móvil
+type RouteLatencyResponse struct {
+	Route           string  `json:"route" example:"POST /api/v1/reservations"`
+	Count           int     `json:"count" example:"1250"`
+	Errors          int     `json:"errors" example:"2"` // Respuestas 5xx
+	P50Ms           float64 `json:"p50_ms" example:"12.4"`
+	P95Ms           float64 `json:"p95_ms" example:"48.1"`
+	P99Ms           float64 `json:"p99_ms" example:"130.7"`
+	MaxMs           float64 `json:"max_ms" example:"410.2"`
+	BudgetMs        float64 `json:"budget_ms,omitempty" example:"300"`
+	OverBudget      int     `json:"over_budget" example:"4"` // Requests que superaron el presupuesto
+	OverBudgetRatio float64 `json:"over_budget_ratio" example:"0.0032"`
+}
+
+// LatencyReportResponse representa los percentiles de latencia por ruta (GET /admin/latency)
+type LatencyReportResponse struct {
+	WindowSeconds float64                `json:"window_seconds" example:"300"`
+	Since         time.Time              `json:"since"`
+	GeneratedAt   time.Time              `json:"generated_at"`
+	Routes        []RouteLatencyResponse `json:"routes"` // Mayor p99 primero
+	Count         int                    `json:"count" example:"24"`
+}
+
+// StockContentionResponse representa las escrituras y conflictos de versión de una fila de stock
+type StockContentionResponse struct {
+	ProductID      string     `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	StoreID        string     `json:"store_id" example:"MAD-001"`
+	Writes         int        `json:"writes" example:"340"`
+	Conflicts      int        `json:"conflicts" example:"51"` // Escrituras rechazadas por versión desactualizada
+	ConflictRate   float64    `json:"conflict_rate" example:"0.15"`
+	LastConflictAt *time.Time `json:"last_conflict_at,omitempty"`
+	FlashSale      bool       `json:"flash_sale" example:"false"` // Ya usa la cola de alta concurrencia
+}
+
+// ContentionReportResponse representa las filas de stock más disputadas (GET /admin/contention)
+type ContentionReportResponse struct {
+	WindowSeconds  float64                   `json:"window_seconds" example:"3600"`
+	Since          time.Time                 `json:"since"`
+	GeneratedAt    time.Time                 `json:"generated_at"`
+	Rows           []StockContentionResponse `json:"rows"` // Más conflictos primero
+	Count          int                       `json:"count" example:"3"`
+	TotalWrites    int64                     `json:"total_writes" example:"18230"`  // Desde el arranque del proceso
+	TotalConflicts int64                     `json:"total_conflicts" example:"212"` // Desde el arranque del proceso
+}
+
+// StoreSyncStatusResponse representa el estado de sincronización de una instancia edge
+type StoreSyncStatusResponse struct {
+	InstanceID    string     `json:"instance_id" example:"edge-mad-01"`
+	StoreID       string     `json:"store_id" example:"MAD-001"`
+	PendingEvents int        `json:"pending_events" example:"12"`
+	LastSyncAt    *time.Time `json:"last_sync_at,omitempty"`
+	LastHeartbeat time.Time  `json:"last_heartbeat"`
+	SyncedThrough *time.Time `json:"synced_through,omitempty"` // Hasta cuándo tiene la central sus cambios
+	LagMinutes    float64    `json:"lag_minutes" example:"42.5"`
+	Stale         bool       `json:"stale" example:"true"`
+}
+
+// StoreSyncReportResponse representa el estado de sincronización de las tiendas (GET /admin/stores/status)
+type StoreSyncReportResponse struct {
+	StaleAfterMinutes float64                   `json:"stale_after_minutes" example:"15"`
+	Stores            []StoreSyncStatusResponse `json:"stores"` // Más atrasada primero
+	Count             int                       `json:"count" example:"8"`
+	StaleCount        int                       `json:"stale_count" example:"1"`
+	GeneratedAt       time.Time                 `json:"generated_at"`
+}
+
+// AvailabilityForecastResponse representa la disponibilidad prevista de una fila de stock en una fecha (?date=)
+type AvailabilityForecastResponse struct {
+	ProductID            string                         `json:"product_id"`
+	StoreID              string                         `json:"store_id" example:"MAD-001"`
+	Date                 time.Time                      `json:"date"`
+	Quantity             int                            `json:"quantity" example:"10"`
+	Reserved             int                            `json:"reserved" example:"4"`
+	Available            int                            `json:"available" example:"4"` // Vendible actual
+	SafetyStock          int                            `json:"safety_stock" example:"2"`
+	ExpiringReservations int                            `json:"expiring_reservations" example:"3"` // Reservas PENDING que caducan antes de la fecha
+	Inbound              int                            `json:"inbound" example:"50"`              // Entradas programadas (pedidos de compra, transferencias)
+	Outbound             int                            `json:"outbound" example:"0"`
+	Preordered           int                            `json:"preordered" example:"0"` // Preventas sin asignar
+	ProjectedQuantity    int                            `json:"projected_quantity" example:"60"`
+	ProjectedReserved    int                            `json:"projected_reserved" example:"1"`
+	ProjectedAvailable   int                            `json:"projected_available" example:"57"`
+	ScheduledChanges     []ScheduledStockChangeResponse `json:"scheduled_changes"`
+	Requested            int                            `json:"requested,omitempty" example:"20"`
+	Sufficient           *bool                          `json:"sufficient,omitempty" example:"true"`
+}
+
+// StockComparisonResponse representa la comparación de surtido entre dos tiendas
+type StockComparisonResponse struct {
+	StoreA        string                   `json:"storeA" example:"MAD-001"`
+	StoreB        string                   `json:"storeB" example:"VAL-001"`
+	MinDifference int                      `json:"minDifference" example:"10"`
+	OnlyInA       []StockResponse          `json:"onlyInA"`
+	OnlyInB       []StockResponse          `json:"onlyInB"`
+	Disparities   []StockDisparityResponse `json:"disparities"`
+	Summary       StockComparisonSummary   `json:"summary"`
+}
+
+// StockDisparityResponse representa la diferencia de disponibilidad de un producto
+type StockDisparityResponse struct {
+	ProductID     string `json:"productId"`
+	AvailableA    int    `json:"availableA" example:"18"`
+	AvailableB    int    `json:"availableB" example:"0"`
+	Difference    int    `json:"difference" example:"18"`
+	SurplusStore  string `json:"surplusStore" example:"MAD-001"`
+	ShortageStore string `json:"shortageStore" example:"VAL-001"`
+}
+
+// StockComparisonSummary resume el resultado de la comparación
+type StockComparisonSummary struct {
+	Common      int `json:"common"`
+	OnlyInA     int `json:"onlyInA"`
+	OnlyInB     int `json:"onlyInB"`
+	Disparities int `json:"disparities"`
+}
+
+// ReservationTicketResponse representa una petición de reserva encolada (producto en flash sale)
+type ReservationTicketResponse struct {
+	Token         string     `json:"token"`
+	ProductID     string     `json:"product_id"`
+	StoreID       string     `json:"store_id" example:"MAD-001"`
+	CustomerID    string     `json:"customer_id" example:"customer-123"`
+	Quantity      int        `json:"quantity" example:"1"`
+	Status        string     `json:"status" enums:"QUEUED,COMPLETED,FAILED" example:"QUEUED"`
+	Position      int        `json:"position" example:"42"`
+	ReservationID string     `json:"reservation_id,omitempty"`
+	ErrorCode     string     `json:"error_code,omitempty" example:"INSUFFICIENT_STOCK"`
+	Error         string     `json:"error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// ReservationIntentResponse representa una intención de reserva (sin stock bloqueado)
+type ReservationIntentResponse struct {
+	Token             string     `json:"token"`
+	ProductID         string     `json:"product_id"`
+	StoreID           string     `json:"store_id" example:"MAD-001"`
+	CustomerID        string     `json:"customer_id" example:"customer-123"`
+	Quantity          int        `json:"quantity" example:"1"`
+	Available         bool       `json:"available" example:"true"`
+	AvailableQuantity int        `json:"available_quantity" example:"12"`
+	Status            string     `json:"status" enums:"ACTIVE,CONVERTED,EXPIRED" example:"ACTIVE"`
+	ReservationID     string     `json:"reservation_id,omitempty"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	ConvertedAt       *time.Time `json:"converted_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// ReservationResponse representa una reserva de stock
+type ReservationResponse struct {
+	ID          string     `json:"id"`
+	ProductID   string     `json:"productId"`
+	StoreID     string     `json:"storeId" example:"MAD-001"`
+	CustomerID  string     `json:"customerId" example:"customer-123"`
+	Quantity    int        `json:"quantity" example:"2"`
+	Status      string     `json:"status" enums:"PENDING,CONFIRMED,CANCELLED,EXPIRED,PREORDER,READY_FOR_PICKUP,PICKED_UP" example:"PENDING"`
+	Priority    string     `json:"priority" enums:"LOW,NORMAL,HIGH" example:"NORMAL"`
+	Channel     string     `json:"channel,omitempty" enums:"WEB,STORE,MARKETPLACE" example:"WEB"` // Canal de venta del request (X-Sales-Channel)
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	ConfirmedAt *time.Time `json:"confirmedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
+
+// PreorderAllocationResponse representa las preventas a las que se asignó stock recibido
+type PreorderAllocationResponse struct {
+	ProductID    string                `json:"product_id"`
+	StoreID      string                `json:"store_id" example:"MAD-001"`
+	Reservations []ReservationResponse `json:"reservations"` // Ahora PENDING, en orden de llegada
+	Count        int                   `json:"count" example:"2"`
+}
+
+// PendingReservationsResponse representa las reservas pendientes de una tienda
+type PendingReservationsResponse struct {
+	StoreID      string                `json:"store_id"`
+	Reservations []ReservationResponse `json:"reservations"`
+	Count        int                   `json:"count"`
+	Total        int                   `json:"total" example:"120"`
+	Limit        int                   `json:"limit" example:"50"`
+	Offset       int                   `json:"offset" example:"0"`
+}
+
+// ProductReservationsResponse representa las reservas de un producto en una tienda
+type ProductReservationsResponse struct {
+	ProductID    string                `json:"product_id"`
+	StoreID      string                `json:"store_id"`
+	Status       string                `json:"status"`
+	Reservations []ReservationResponse `json:"reservations"`
+	Count        int                   `json:"count"`
+	Total        int                   `json:"total" example:"120"`
+	Limit        int                   `json:"limit" example:"50"`
+	Offset       int                   `json:"offset" example:"0"`
+}
+
+// ReservationPickupDetails representa la recogida en tienda de una reserva
+type ReservationPickupDetails struct {
+	ReservationID string     `json:"reservation_id"`
+	StoreID       string     `json:"store_id" example:"MAD-001"`
+	Code          string     `json:"code,omitempty" example:"482913"` // Solo al marcarla lista: no se vuelve a mostrar
+	Attempts      int        `json:"attempts" example:"0"`            // Códigos erróneos presentados
+	ReadyAt       time.Time  `json:"ready_at"`
+	PickupBy      time.Time  `json:"pickup_by"` // Fin del plazo de recogida
+	PickedUpAt    *time.Time `json:"picked_up_at,omitempty"`
+}
+
+// ReservationPickupResponse representa una reserva de recogida en tienda con su recogida
+type ReservationPickupResponse struct {
+	Reservation ReservationResponse      `json:"reservation"`
+	Pickup      ReservationPickupDetails `json:"pickup"`
+}
+
+// ReservationStatusResponse representa el resultado de confirmar o cancelar una reserva
+type ReservationStatusResponse struct {
+	Message       string                       `json:"message" example:"Reservation confirmed successfully"`
+	ReservationID string                       `json:"reservation_id"`
+	Status        string                       `json:"status" example:"CONFIRMED"`
+	Serials       []SerialRegistrationResponse `json:"serials,omitempty"`
+	SerialsError  string                       `json:"serials_error,omitempty"`
+}
+
+// ReservationStatsResponse representa las estadísticas de reservas
+type ReservationStatsResponse struct {
+	TotalReservations     int                         `json:"total_reservations"`
+	PendingReservations   int                         `json:"pending_reservations"`
+	ConfirmedReservations int                         `json:"confirmed_reservations"`
+	CancelledReservations int                         `json:"cancelled_reservations"`
+	ExpiredReservations   int                         `json:"expired_reservations"`
+	ByStatus              map[string]int              `json:"by_status"`
+	ByStore               map[string]int              `json:"by_store"`
+	ByGroup               map[string]int              `json:"by_group,omitempty"`
+	GroupID               string                      `json:"group_id,omitempty" example:"region-levante"`
+	Window                ReservationWindowStatsEntry `json:"window"`
+}
+
+// ReservationWindowStatsEntry representa las métricas de la ventana de tiempo consultada
+type ReservationWindowStatsEntry struct {
+	Duration                string    `json:"duration" example:"24h0m0s"`
+	Since                   time.Time `json:"since"`
+	Created                 int       `json:"created"`
+	Confirmed               int       `json:"confirmed"`
+	Expired                 int       `json:"expired"`
+	AvgTimeToConfirmSeconds float64   `json:"avg_time_to_confirm_seconds"`
+	ExpirationRate          float64   `json:"expiration_rate"`
+	ConversionRate          float64   `json:"conversion_rate"`
+}
+
+// ProductAvailabilityResponse representa la disponibilidad pública de un producto por tienda
+type ProductAvailabilityResponse struct {
+	ProductID string                      `json:"product_id"`
+	Stores    []StoreAvailabilityResponse `json:"stores"`
+}
+
+// StoreAvailabilityResponse representa la disponibilidad de un producto en una tienda
+type StoreAvailabilityResponse struct {
+	StoreID   string `json:"store_id" example:"MAD-001"`
+	Status    string `json:"status" enums:"in_stock,low_stock,out_of_stock"`
+	Available *int   `json:"available,omitempty" example:"12"` // Se omite sin API key si la tienda oculta cantidades
+	// Tipo de ubicación; solo con fulfillment
+	LocationType string `json:"location_type,omitempty" enums:"STORE,WAREHOUSE,DARKSTORE"`
+}
+
+// AutoReservationResponse representa una reserva con selección automática de tienda
+type AutoReservationResponse struct {
+	ProductID    string                `json:"productId"`
+	Requested    int                   `json:"requested" example:"5"`
+	Split        bool                  `json:"split"`             // La cantidad se repartió entre varias tiendas
+	GroupID      string                `json:"groupId,omitempty"` // Con reparto: grupo de reservas (/reservations/groups/{id})
+	Reservations []ReservationResponse `json:"reservations"`
+}
+
+// ReservationGroupResponse representa un grupo de reservas en distintas tiendas
+type ReservationGroupResponse struct {
+	ID           string                `json:"id"`
+	CustomerID   string                `json:"customerId" example:"customer-123"`
+	Status       string                `json:"status" enums:"PENDING,CONFIRMED,CANCELLED,EXPIRED,PARTIAL"`
+	Reservations []ReservationResponse `json:"reservations"`
+	CreatedAt    time.Time             `json:"createdAt"`
+}
+
+// ReservationGroupActionResponse representa el resultado de confirmar o cancelar un grupo: el
+// formato multi-status común con data de tipo ReservationGroupResultEntry
+type ReservationGroupActionResponse struct {
+	GroupID string `json:"groupId"`
+	Action  string `json:"action" enums:"confirm,cancel"`
+	Status  string `json:"status" enums:"PENDING,CONFIRMED,CANCELLED,EXPIRED,PARTIAL"` // Estado agregado del grupo tras la acción
+	MultiStatusResponse
+}
+
+// ReservationGroupResultEntry representa una reserva del grupo en el resultado de la acción
+type ReservationGroupResultEntry struct {
+	ReservationID string `json:"reservationId"`
+	StoreID       string `json:"storeId" example:"BCN-001"`
+	Status        string `json:"status" enums:"PENDING,CONFIRMED,CANCELLED,EXPIRED"` // Estado de la reserva tras la acción
+}
+
+// TransferReservationResponse representa una reserva servida mediante transferencia
+type TransferReservationResponse struct {
+	Reservation      ReservationResponse     `json:"reservation"`
+	Transfer         StockTransferDraftEntry `json:"transfer"`
+	PreferredStoreID string                  `json:"preferredStoreId" example:"VAL-001"`
+	SourceStoreID    string                  `json:"sourceStoreId" example:"BCN-001"`
+	State            string                  `json:"state" enums:"AWAITING_TRANSFER,FULFILLED,CANCELLED,OUT_OF_SYNC"`
+}
+
+// StockTransferDraftEntry representa la transferencia vinculada a una reserva
+type StockTransferDraftEntry struct {
+	ID            string     `json:"id"`
+	ProductID     string     `json:"productId"`
+	FromStoreID   string     `json:"fromStoreId" example:"BCN-001"`
+	ToStoreID     string     `json:"toStoreId" example:"VAL-001"`
+	Quantity      int        `json:"quantity" example:"2"`
+	Status        string     `json:"status" enums:"DRAFT,COMPLETED,CANCELLED"`
+	ReservationID string     `json:"reservationId,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
+}
+
+// SerialRegistrationResponse representa un número de serie vendido
+type SerialRegistrationResponse struct {
+	ID                string     `json:"id"`
+	SerialNumber      string     `json:"serialNumber" example:"SN-0001"`
+	ProductID         string     `json:"productId"`
+	StoreID           string     `json:"storeId"`
+	ReservationID     string     `json:"reservationId"`
+	CustomerID        string     `json:"customerId"`
+	SoldAt            time.Time  `json:"soldAt"`
+	WarrantyExpiresAt *time.Time `json:"warrantyExpiresAt,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+}
+
+// ReservationSerialsResponse representa los números de serie de una reserva
+type ReservationSerialsResponse struct {
+	ReservationID string                       `json:"reservation_id"`
+	Serials       []SerialRegistrationResponse `json:"serials"`
+	Count         int                          `json:"count"`
+}
+
+// NotificationDeliveryResponse representa un aviso enviado (o intentado) al cliente de una reserva
+type NotificationDeliveryResponse struct {
+	ID            string     `json:"id"`
+	ReservationID string     `json:"reservation_id"`
+	StoreID       string     `json:"store_id" example:"MAD-001"`
+	Kind          string     `json:"kind" example:"RESERVATION_CREATED"`
+	Channel       string     `json:"channel" example:"EMAIL"`
+	Recipient     string     `json:"recipient,omitempty" example:"cliente@example.com"`
+	Status        string     `json:"status" example:"SENT"` // SENT, FAILED, SKIPPED
+	Error         string     `json:"error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// ReservationNotificationsResponse representa los avisos al cliente de una reserva
+type ReservationNotificationsResponse struct {
+	ReservationID string                         `json:"reservation_id"`
+	Notifications []NotificationDeliveryResponse `json:"notifications"`
+	Count         int                            `json:"count"`
+}
+
+// SerialLookupResponse representa los registros de venta de un número de serie
+type SerialLookupResponse struct {
+	SerialNumber  string                       `json:"serial_number" example:"SN-0001"`
+	Registrations []SerialRegistrationResponse `json:"registrations"`
+	Count         int                          `json:"count"`
+}
+
+// EventResponse representa un evento del ledger
+type EventResponse struct {
+	ID            string     `json:"id"`
+	EventType     string     `json:"event_type" example:"stock.updated"`
+	AggregateID   string     `json:"aggregate_id"`
+	AggregateType string     `json:"aggregate_type" example:"stock"`
+	StoreID       string     `json:"store_id" example:"MAD-001"`
+	Payload       string     `json:"payload"` // JSON serializado
+	CreatedAt     time.Time  `json:"created_at"`
+	Synced        bool       `json:"synced"`
+	SyncedAt      *time.Time `json:"synced_at,omitempty"`
+	Seq           int64      `json:"seq,omitempty" example:"1024"`
+	CorrelationID string     `json:"correlation_id,omitempty"`
+	Actor         string     `json:"actor,omitempty" example:"store-MAD-001"` // API key que lo originó ("system" en workers)
+}
+
+// EventListResponse representa un recorrido del ledger de eventos
+type EventListResponse struct {
+	StoreID   string          `json:"store_id" example:"MAD-001"`
+	ProductID string          `json:"product_id"`
+	Events    []EventResponse `json:"events"`
+	Count     int             `json:"count"`
+}
+
+// MovementListResponse representa los movimientos de stock del ledger
+type MovementListResponse struct {
+	StoreID   string          `json:"store_id" example:"MAD-001"`
+	ProductID string          `json:"product_id"`
+	Movements []EventResponse `json:"movements"`
+	Count     int             `json:"count"`
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchIndexHandler maneja el mantenimiento del índice de búsqueda externo
+type SearchIndexHandler struct {
+	indexer *service.SearchIndexer
+}
+
+// NewSearchIndexHandler crea un nuevo handler del índice de búsqueda
+func NewSearchIndexHandler(indexer *service.SearchIndexer) *SearchIndexHandler {
+	return &SearchIndexHandler{
+		indexer: indexer,
+	}
+}
+
+// ReindexSearch godoc
+// @Summary Reconstruir el índice de búsqueda
+// @Description Vuelve a indexar todos los productos no descatalogados (datos de catálogo y tiendas con stock vendible) en el backend de SEARCH_BACKEND. Sirve para la carga inicial y para recuperar eventos que el indexador descartó.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} SearchReindexResponse
+// @Failure 409 {object} ErrorResponse "Índice desactivado (SEARCH_BACKEND=none)"
+// @Failure 500 {object} ErrorResponse "Error del backend de búsqueda"
+// @Security ApiKeyAuth
+// @Router /admin/search/reindex [post]
+func (h *SearchIndexHandler) ReindexSearch(c *gin.Context) {
+	result, err := h.indexer.Reindex(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	log.Printf("🔎 Reindexed %d products in %s (%s)", result.Indexed, result.Backend, result.Duration)
+	c.JSON(http.StatusOK, result)
+}
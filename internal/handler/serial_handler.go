@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SerialHandler maneja las peticiones HTTP de números de serie y garantías
+type SerialHandler struct {
+	serialService *service.SerialService
+}
+
+// NewSerialHandler crea un nuevo handler de números de serie
+func NewSerialHandler(serialService *service.SerialService) *SerialHandler {
+	return &SerialHandler{
+		serialService: serialService,
+	}
+}
+
+// RegisterSerialsRequest representa la petición para registrar números de serie vendidos
+type RegisterSerialsRequest struct {
+	SerialNumbers  []string `json:"serial_numbers" binding:"required,min=1,dive,required"`
+	WarrantyMonths int      `json:"warranty_months" binding:"min=0,max=120"`
+}
+
+// RegisterSerials godoc
+// @Summary Registrar números de serie vendidos en una reserva confirmada
+// @Tags serials
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de la reserva"
+// @Param request body RegisterSerialsRequest true "Números de serie"
+// @Success 201 {object} ReservationSerialsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reservations/{id}/serials [post]
+func (h *SerialHandler) RegisterSerials(c *gin.Context) {
+	id := c.Param("id")
+
+	var req RegisterSerialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	registrations, err := h.serialService.RegisterSerials(c.Request.Context(), id, req.SerialNumbers, req.WarrantyMonths)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"reservation_id": id,
+		"serials":        registrations,
+		"count":          len(registrations),
+	})
+}
+
+// GetReservationSerials godoc
+// @Summary Obtener números de serie registrados en una reserva
+// @Tags serials
+// @Produce json
+// @Param id path string true "ID de la reserva"
+// @Success 200 {object} ReservationSerialsResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reservations/{id}/serials [get]
+func (h *SerialHandler) GetReservationSerials(c *gin.Context) {
+	id := c.Param("id")
+
+	registrations, err := h.serialService.GetReservationSerials(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reservation_id": id,
+		"serials":        registrations,
+		"count":          len(registrations),
+	})
+}
+
+// LookupSerial godoc
+// @Summary Consultar el registro de venta de un número de serie (garantías)
+// @Tags serials
+// @Produce json
+// @Param serial path string true "Número de serie"
+// @Success 200 {object} SerialLookupResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /serials/{serial} [get]
+func (h *SerialHandler) LookupSerial(c *gin.Context) {
+	serial := c.Param("serial")
+
+	registrations, err := h.serialService.LookupSerial(c.Request.Context(), serial)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"serial_number": serial,
+		"registrations": registrations,
+		"count":         len(registrations),
+	})
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"strings"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serializerKey clave del contexto de gin donde se guarda el serializer de la versión
+// (middleware.Recovery la lee para responder los panics con el mismo formato)
+const serializerKey = "response_serializer"
+
+// Serializer define el formato de respuesta de una versión de la API.
+// Los handlers son compartidos entre versiones y solo cambia el serializer
+// que se registra en el grupo de rutas con UseSerializer.
+type Serializer interface {
+	// Object serializa un recurso individual
+	Object(c *gin.Context, status int, data interface{})
+	// List serializa una colección. meta contiene paginación/contexto (v2)
+	// y legacy la respuesta completa con el formato histórico (v1).
+	List(c *gin.Context, status int, items interface{}, meta gin.H, legacy gin.H)
+	// StreamList equivale a List para colecciones grandes: escribe cada elemento según lo produce
+	// each. legacy no lleva los elementos ni "count": van en itemsKey y "count" (v1).
+	StreamList(c *gin.Context, status int, meta gin.H, legacy gin.H, itemsKey string, each streamEach)
+	// Error serializa un error. details es opcional (nil si el error no aporta contexto extra).
+	Error(c *gin.Context, status int, title, message string, details interface{})
+}
+
+// V1Serializer mantiene el formato de respuesta original de /api/v1: los structs de dominio
+// se serializan tal cual (camelCase en productos, stock y reservas) y no se cambian por
+// compatibilidad con los clientes existentes
+type V1Serializer struct{}
+
+// Object retorna el recurso sin envolver
+func (V1Serializer) Object(c *gin.Context, status int, data interface{}) {
+	c.JSON(status, data)
+}
+
+// List retorna la respuesta histórica de cada endpoint
+func (V1Serializer) List(c *gin.Context, status int, items interface{}, meta gin.H, legacy gin.H) {
+	c.JSON(status, legacy)
+}
+
+// StreamList retorna la respuesta histórica con los elementos en itemsKey y "count" al final
+func (V1Serializer) StreamList(c *gin.Context, status int, meta gin.H, legacy gin.H, itemsKey string, each streamEach) {
+	streamJSONList(c, status, legacy, itemsKey, func(count int) gin.H {
+		return gin.H{"count": count}
+	}, each)
+}
+
+// Error retorna {"error": title, "message": message, "request_id": ..., "details": ...}
+func (V1Serializer) Error(c *gin.Context, status int, title, message string, details interface{}) {
+	c.JSON(status, ErrorResponse{
+		Error:     title,
+		Message:   message,
+		RequestID: requestID(c),
+		Details:   details,
+	})
+}
+
+// V2Serializer usa un envelope uniforme: {"data": ..., "meta": {...}} y
+// {"error": {"code": ..., "title": ..., "message": ...}}. Los recursos se convierten a los
+// DTOs del paquete dto, todos en snake_case.
+type V2Serializer struct{}
+
+// V2ErrorBody representa el detalle de un error en /api/v2
+type V2ErrorBody struct {
+	Code      string      `json:"code"`
+	Title     string      `json:"title"`
+	Message   string      `json:"message,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// V2ErrorResponse representa una respuesta de error en /api/v2
+type V2ErrorResponse struct {
+	Error V2ErrorBody `json:"error"`
+}
+
+// Object envuelve el recurso en "data"
+func (V2Serializer) Object(c *gin.Context, status int, data interface{}) {
+	c.JSON(status, gin.H{"data": dto.Convert(data)})
+}
+
+// List envuelve la colección en "data" y la paginación/contexto en "meta"
+func (V2Serializer) List(c *gin.Context, status int, items interface{}, meta gin.H, legacy gin.H) {
+	if meta == nil {
+		meta = gin.H{}
+	}
+	c.JSON(status, gin.H{
+		"data": dto.Convert(items),
+		"meta": meta,
+	})
+}
+
+// StreamList escribe "data" elemento a elemento y "meta" (con "count") al final
+func (V2Serializer) StreamList(c *gin.Context, status int, meta gin.H, legacy gin.H, itemsKey string, each streamEach) {
+	streamJSONList(c, status, nil, "data", func(count int) gin.H {
+		tail := gin.H{"count": count}
+		for key, value := range meta {
+			tail[key] = value
+		}
+		return gin.H{"meta": tail}
+	}, func(emit func(item interface{}) error) error {
+		return each(func(item interface{}) error {
+			return emit(dto.Convert(item))
+		})
+	})
+}
+
+// Error retorna un código de error estable derivado del título (p.ej. "Not Found" -> NOT_FOUND)
+func (V2Serializer) Error(c *gin.Context, status int, title, message string, details interface{}) {
+	c.JSON(status, V2ErrorResponse{
+		Error: V2ErrorBody{
+			Code:      errorCode(title),
+			Title:     title,
+			Message:   message,
+			RequestID: requestID(c),
+			Details:   details,
+		},
+	})
+}
+
+// errorCode convierte un título de error en un código en mayúsculas separado por "_"
+func errorCode(title string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(title), "_"))
+}
+
+// requestID obtiene el ID de correlación del request (ver middleware.RequestID)
+func requestID(c *gin.Context) string {
+	return domain.CorrelationIDFromContext(c.Request.Context())
+}
+
+// UseSerializer registra el serializer de respuestas para un grupo de rutas
+func UseSerializer(serializer Serializer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(serializerKey, serializer)
+		c.Next()
+	}
+}
+
+// serializerFor obtiene el serializer del request (v1 por defecto)
+func serializerFor(c *gin.Context) Serializer {
+	if value, ok := c.Get(serializerKey); ok {
+		if serializer, ok := value.(Serializer); ok {
+			return serializer
+		}
+	}
+	return V1Serializer{}
+}
+
+// respond serializa un recurso individual según la versión de la API
+func respond(c *gin.Context, status int, data interface{}) {
+	serializerFor(c).Object(c, status, data)
+}
+
+// respondList serializa una colección según la versión de la API
+func respondList(c *gin.Context, status int, items interface{}, meta gin.H, legacy gin.H) {
+	serializerFor(c).List(c, status, items, meta, legacy)
+}
+
+// respondStream serializa una colección grande elemento a elemento según la versión de la API
+func respondStream(c *gin.Context, status int, meta gin.H, legacy gin.H, itemsKey string, each streamEach) {
+	serializerFor(c).StreamList(c, status, meta, legacy, itemsKey, each)
+}
+
+// respondError serializa un error según la versión de la API
+func respondError(c *gin.Context, status int, title, message string) {
+	serializerFor(c).Error(c, status, title, message, nil)
+}
+
+// respondErrorDetails serializa un error con contexto adicional en "details"
+func respondErrorDetails(c *gin.Context, status int, title, message string, details interface{}) {
+	serializerFor(c).Error(c, status, title, message, details)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StockAdjustmentHandler maneja los ajustes manuales de stock pendientes de aprobación
+type StockAdjustmentHandler struct {
+	adjustmentService *service.StockAdjustmentService
+}
+
+// NewStockAdjustmentHandler crea un nuevo handler de ajustes de stock
+func NewStockAdjustmentHandler(adjustmentService *service.StockAdjustmentService) *StockAdjustmentHandler {
+	return &StockAdjustmentHandler{
+		adjustmentService: adjustmentService,
+	}
+}
+
+// ListAdjustments godoc
+// @Summary Listar ajustes de stock pendientes de aprobación (o ya revisados)
+// @Tags stock
+// @Produce json
+// @Param status query string false "Filtrar por estado" Enums(PENDING, APPROVED, REJECTED)
+// @Param store_id query string false "Filtrar por tienda"
+// @Param limit query int false "Límite de resultados" default(50)
+// @Param offset query int false "Offset para paginación" default(0)
+// @Success 200 {object} StockAdjustmentListResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /adjustments [get]
+func (h *StockAdjustmentHandler) ListAdjustments(c *gin.Context) {
+	status := domain.StockAdjustmentStatus(strings.ToUpper(c.Query("status")))
+	switch status {
+	case "", domain.StockAdjustmentPending, domain.StockAdjustmentApproved, domain.StockAdjustmentRejected:
+	default:
+		handleError(c, &domain.ValidationError{Field: "status", Message: "status must be PENDING, APPROVED or REJECTED"})
+		return
+	}
+	storeID := c.Query("store_id")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	adjustments, err := h.adjustmentService.ListAdjustments(c.Request.Context(), status, storeID, limit, offset)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":  adjustments,
+		"count":  len(adjustments),
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetAdjustment godoc
+// @Summary Obtener un ajuste de stock
+// @Tags stock
+// @Produce json
+// @Param id path string true "ID del ajuste"
+// @Success 200 {object} StockAdjustmentResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /adjustments/{id} [get]
+func (h *StockAdjustmentHandler) GetAdjustment(c *gin.Context) {
+	adjustment, err := h.adjustmentService.GetAdjustment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, adjustment)
+}
+
+// ApproveAdjustment godoc
+// @Summary Aprobar un ajuste de stock pendiente
+// @Description Aplica el ajuste sobre la cantidad actual (ADJUST suma sus unidades, SET fija la cantidad) y emite stock.adjustment_approved y stock.updated. Lo debe aprobar una API key distinta de la que lo solicitó. Si ya no se puede aplicar (stock negativo, producto descatalogado) responde el error y el ajuste sigue PENDING.
+// @Tags stock
+// @Produce json
+// @Param id path string true "ID del ajuste"
+// @Success 200 {object} StockAdjustmentApprovalResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "La API key solicitó el ajuste o está limitada a otras tiendas"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "El ajuste ya se revisó"
+// @Security ApiKeyAuth
+// @Router /adjustments/{id}/approve [post]
+func (h *StockAdjustmentHandler) ApproveAdjustment(c *gin.Context) {
+	adjustment, stock, err := h.adjustmentService.ApproveAdjustment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"adjustment": adjustment,
+		"stock":      stock,
+	})
+}
+
+// RejectAdjustment godoc
+// @Summary Rechazar un ajuste de stock pendiente
+// @Description Descarta el ajuste sin tocar el stock y emite stock.adjustment_rejected. Lo debe rechazar una API key distinta de la que lo solicitó.
+// @Tags stock
+// @Produce json
+// @Param id path string true "ID del ajuste"
+// @Success 200 {object} StockAdjustmentResponse
+// @Failure 403 {object} ErrorResponse "La API key solicitó el ajuste o está limitada a otras tiendas"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "El ajuste ya se revisó"
+// @Security ApiKeyAuth
+// @Router /adjustments/{id}/reject [post]
+func (h *StockAdjustmentHandler) RejectAdjustment(c *gin.Context) {
+	adjustment, err := h.adjustmentService.RejectAdjustment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, adjustment)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"inventory-system/internal/domain"
 	"inventory-system/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -11,7 +12,9 @@ import (
 
 // StockHandler maneja las peticiones HTTP para stock
 type StockHandler struct {
-	stockService *service.StockService
+	stockService      *service.StockService
+	unitService       *service.ProductUnitService     // Opcional: cantidades en unidades de pedido (BOX, CASE...)
+	adjustmentService *service.StockAdjustmentService // Opcional: aprobación de ajustes manuales grandes
 }
 
 // NewStockHandler crea un nuevo handler de stock
@@ -21,14 +24,26 @@ func NewStockHandler(stockService *service.StockService) *StockHandler {
 	}
 }
 
+// SetProductUnitService habilita cantidad + unidad en las peticiones (se convierten a la unidad base)
+func (h *StockHandler) SetProductUnitService(unitService *service.ProductUnitService) {
+	h.unitService = unitService
+}
+
+// SetStockAdjustmentService hace que PUT y adjust dejen pendientes de aprobación los ajustes que
+// superan los umbrales configurados
+func (h *StockHandler) SetStockAdjustmentService(adjustmentService *service.StockAdjustmentService) {
+	h.adjustmentService = adjustmentService
+}
+
 // GetStockByProductAndStore godoc
 // @Summary Obtener stock de un producto en una tienda
 // @Tags stock
 // @Produce json
 // @Param productId path string true "ID del producto"
 // @Param storeId path string true "ID de la tienda"
-// @Success 200 {object} domain.Stock
+// @Success 200 {object} StockResponse
 // @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /stock/{productId}/{storeId} [get]
 func (h *StockHandler) GetStockByProductAndStore(c *gin.Context) {
 	productID := c.Param("productId")
@@ -40,7 +55,54 @@ func (h *StockHandler) GetStockByProductAndStore(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, stock)
+	respond(c, http.StatusOK, stock)
+}
+
+// GetStockHistory godoc
+// @Summary Historial de una fila de stock
+// @Description Línea temporal de quantity, reserved y version reconstruida desde los eventos de stock y de reservas, del más reciente al más antiguo. Cada entrada indica quién hizo el cambio (actor), el request que lo originó (correlation_id) y los valores tras aplicarlo. untracked_* recoge diferencias con los valores absolutos de los eventos (cambios que no dejaron evento). Para la página siguiente se envía como before_seq el seq de la última entrada recibida.
+// @Tags stock
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Param from query string false "Desde (RFC3339 o YYYY-MM-DD, inclusivo)"
+// @Param to query string false "Hasta (RFC3339 o YYYY-MM-DD, exclusivo)"
+// @Param before_seq query int false "Solo eventos con seq menor (cursor)"
+// @Param limit query int false "Máximo de entradas (máx. 1000)" default(100)
+// @Success 200 {object} StockHistoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/history [get]
+func (h *StockHandler) GetStockHistory(c *gin.Context) {
+	var filter domain.StockHistoryFilter
+	var err error
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		handleError(c, err)
+		return
+	}
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		handleError(c, err)
+		return
+	}
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		handleError(c, err)
+		return
+	}
+	if raw := c.Query("before_seq"); raw != "" {
+		if filter.BeforeSeq, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid before_seq", err.Error())
+			return
+		}
+	}
+
+	history, err := h.stockService.GetStockHistory(c.Request.Context(), c.Param("productId"), c.Param("storeId"), filter)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
 }
 
 // GetAllStockByProduct godoc
@@ -48,8 +110,9 @@ func (h *StockHandler) GetStockByProductAndStore(c *gin.Context) {
 // @Tags stock
 // @Produce json
 // @Param productId path string true "ID del producto"
-// @Success 200 {array} domain.Stock
+// @Success 200 {object} StockByProductResponse
 // @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /stock/product/{productId} [get]
 func (h *StockHandler) GetAllStockByProduct(c *gin.Context) {
 	productID := c.Param("productId")
@@ -63,59 +126,67 @@ func (h *StockHandler) GetAllStockByProduct(c *gin.Context) {
 	// Calcular disponibilidad total
 	totalQuantity := 0
 	totalReserved := 0
+	totalHeld := 0
+	totalSellable := 0
 	for _, stock := range stocks {
 		totalQuantity += stock.Quantity
 		totalReserved += stock.Reserved
+		totalHeld += stock.Held
+		totalSellable += stock.Sellable()
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"product_id":      productID,
 		"stores":          stocks,
 		"total_quantity":  totalQuantity,
 		"total_reserved":  totalReserved,
+		"total_held":      totalHeld,
 		"total_available": totalQuantity - totalReserved,
+		"total_sellable":  totalSellable,
 	})
 }
 
 // GetAllStockByStore godoc
 // @Summary Obtener todo el stock de una tienda
+// @Description Respuesta en streaming: los elementos se escriben según se leen de la base de datos y "count" va al final
 // @Tags stock
 // @Produce json
 // @Param storeId path string true "ID de la tienda"
-// @Success 200 {array} domain.Stock
+// @Success 200 {object} StockByStoreResponse
+// @Security ApiKeyAuth
 // @Router /stock/store/{storeId} [get]
 func (h *StockHandler) GetAllStockByStore(c *gin.Context) {
 	storeID := c.Param("storeId")
 
-	stocks, err := h.stockService.GetAllStockByStore(c.Request.Context(), storeID)
-	if err != nil {
-		handleError(c, err)
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"store_id": storeID,
-		"items":    stocks,
-		"count":    len(stocks),
+	respondStream(c, http.StatusOK, gin.H{"store_id": storeID}, gin.H{"store_id": storeID}, "items", func(emit func(interface{}) error) error {
+		return h.stockService.StreamStockByStore(c.Request.Context(), storeID, func(stock *domain.Stock) error {
+			return emit(stock)
+		})
 	})
 }
 
 // UpdateStockRequest representa la petición para actualizar stock
 type UpdateStockRequest struct {
-	Quantity int `json:"quantity" binding:"required,min=0"`
+	Quantity float64 `json:"quantity" binding:"required,min=0"` // Decimal solo en unidades fractional
+	Unit     string  `json:"unit" example:"BOX"`                // Opcional: unidad base del producto por defecto
+	Reason   string  `json:"reason" example:"correction"`       // Opcional: código del catálogo de motivos (GET /adjustment-reasons)
 }
 
 // UpdateStock godoc
 // @Summary Actualizar cantidad de stock
+// @Description Si el cambio supera ADJUSTMENT_APPROVAL_MAX_UNITS o ADJUSTMENT_APPROVAL_MAX_PERCENT no se aplica: responde 202 con un ajuste PENDING que otra API key aprueba en POST /adjustments/{id}/approve.
 // @Tags stock
 // @Accept json
 // @Produce json
 // @Param productId path string true "ID del producto"
 // @Param storeId path string true "ID de la tienda"
 // @Param request body UpdateStockRequest true "Nueva cantidad"
-// @Success 200 {object} domain.Stock
+// @Success 200 {object} StockResponse
+// @Success 202 {object} StockAdjustmentResponse "El cambio supera el umbral de aprobación: queda pendiente"
 // @Failure 400 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse "Optimistic lock failure"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
 // @Router /stock/{productId}/{storeId} [put]
 func (h *StockHandler) UpdateStock(c *gin.Context) {
 	productID := c.Param("productId")
@@ -123,37 +194,52 @@ func (h *StockHandler) UpdateStock(c *gin.Context) {
 
 	var req UpdateStockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request body",
-			Message: err.Error(),
-		})
+		respondBindError(c, err)
+		return
+	}
+
+	quantity, err := toBaseDecimalQuantity(c, h.unitService, productID, req.Quantity, req.Unit, storeID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if h.adjustmentService != nil {
+		stock, pending, err := h.adjustmentService.UpdateStock(c.Request.Context(), productID, storeID, quantity, req.Reason)
+		respondAdjustment(c, stock, pending, err)
 		return
 	}
 
-	stock, err := h.stockService.UpdateStock(c.Request.Context(), productID, storeID, req.Quantity)
+	stock, err := h.stockService.UpdateStockWithReason(c.Request.Context(), productID, storeID, quantity, req.Reason)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, stock)
+	respond(c, http.StatusOK, stock)
 }
 
 // AdjustStockRequest representa la petición para ajustar stock
 type AdjustStockRequest struct {
-	Adjustment int `json:"adjustment" binding:"required"`
+	Adjustment float64 `json:"adjustment" binding:"required"` // Decimal solo en unidades fractional
+	Unit       string  `json:"unit" example:"BOX"`            // Opcional: unidad base del producto por defecto
+	Reason     string  `json:"reason" example:"damaged"`      // Código del catálogo de motivos (GET /adjustment-reasons)
 }
 
 // AdjustStock godoc
 // @Summary Ajustar stock (incrementar o decrementar)
+// @Description reason es obligatorio: un código activo del catálogo (GET /adjustment-reasons) que queda en el movimiento stock.updated. Si el ajuste supera ADJUSTMENT_APPROVAL_MAX_UNITS o ADJUSTMENT_APPROVAL_MAX_PERCENT no se aplica: responde 202 con un ajuste PENDING que otra API key aprueba en POST /adjustments/{id}/approve.
 // @Tags stock
 // @Accept json
 // @Produce json
 // @Param productId path string true "ID del producto"
 // @Param storeId path string true "ID de la tienda"
 // @Param request body AdjustStockRequest true "Ajuste (positivo o negativo)"
-// @Success 200 {object} domain.Stock
+// @Success 200 {object} StockResponse
+// @Success 202 {object} StockAdjustmentResponse "El ajuste supera el umbral de aprobación: queda pendiente"
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
 // @Router /stock/{productId}/{storeId}/adjust [post]
 func (h *StockHandler) AdjustStock(c *gin.Context) {
 	productID := c.Param("productId")
@@ -161,28 +247,98 @@ func (h *StockHandler) AdjustStock(c *gin.Context) {
 
 	var req AdjustStockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request body",
-			Message: err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 
-	stock, err := h.stockService.AdjustStock(c.Request.Context(), productID, storeID, req.Adjustment)
+	adjustment, err := toBaseDecimalQuantity(c, h.unitService, productID, req.Adjustment, req.Unit, storeID)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, stock)
+	if h.adjustmentService != nil {
+		stock, pending, err := h.adjustmentService.AdjustStock(c.Request.Context(), productID, storeID, adjustment, req.Reason)
+		respondAdjustment(c, stock, pending, err)
+		return
+	}
+
+	stock, err := h.stockService.AdjustStockWithReason(c.Request.Context(), productID, storeID, adjustment, req.Reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, stock)
+}
+
+// respondAdjustment responde la fila actualizada o, si el cambio quedó pendiente de aprobación,
+// 202 con el ajuste pendiente
+func respondAdjustment(c *gin.Context, stock *domain.Stock, pending *domain.StockAdjustment, err error) {
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if pending != nil {
+		respond(c, http.StatusAccepted, pending)
+		return
+	}
+	respond(c, http.StatusOK, stock)
+}
+
+// SafetyStockRequest representa las unidades de stock de seguridad de una fila de stock
+type SafetyStockRequest struct {
+	SafetyStock *int   `json:"safety_stock" binding:"required,min=0" example:"2"`
+	Unit        string `json:"unit" example:"BOX"` // Opcional: unidad base del producto por defecto
+}
+
+// SetSafetyStock godoc
+// @Summary Configurar el stock de seguridad
+// @Description Unidades guardadas para la venta en tienda: siguen en quantity pero no se pueden reservar y no cuentan en la disponibilidad (availability, intenciones, tiempo real) ni en el cálculo de stock bajo
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Param request body SafetyStockRequest true "Stock de seguridad"
+// @Success 200 {object} StockResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/safety-stock [put]
+func (h *StockHandler) SetSafetyStock(c *gin.Context) {
+	productID := c.Param("productId")
+	storeID := c.Param("storeId")
+
+	var req SafetyStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	safetyStock, err := toBaseQuantity(c, h.unitService, productID, *req.SafetyStock, req.Unit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	stock, err := h.stockService.SetSafetyStock(c.Request.Context(), productID, storeID, safetyStock)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, stock)
 }
 
 // TransferStockRequest representa la petición para transferir stock
 type TransferStockRequest struct {
-	ProductID   string `json:"product_id" binding:"required"`
-	FromStoreID string `json:"from_store_id" binding:"required"`
-	ToStoreID   string `json:"to_store_id" binding:"required"`
-	Quantity    int    `json:"quantity" binding:"required,min=1"`
+	ProductID   string  `json:"product_id" binding:"required"`
+	FromStoreID string  `json:"from_store_id" binding:"required"`
+	ToStoreID   string  `json:"to_store_id" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"required,gt=0"` // Decimal solo en unidades fractional
+	Unit        string  `json:"unit" example:"BOX"`               // Opcional: unidad base del producto por defecto
 }
 
 // TransferStock godoc
@@ -191,27 +347,32 @@ type TransferStockRequest struct {
 // @Accept json
 // @Produce json
 // @Param request body TransferStockRequest true "Datos de transferencia"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} StockTransferResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse "Stock insuficiente"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
 // @Router /stock/transfer [post]
 func (h *StockHandler) TransferStock(c *gin.Context) {
 	var req TransferStockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request body",
-			Message: err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 
-	err := h.stockService.TransferStock(c.Request.Context(), req.ProductID, req.FromStoreID, req.ToStoreID, req.Quantity)
+	quantity, err := toBaseDecimalQuantity(c, h.unitService, req.ProductID, req.Quantity, req.Unit, req.FromStoreID, req.ToStoreID)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	err = h.stockService.TransferStock(c.Request.Context(), req.ProductID, req.FromStoreID, req.ToStoreID, quantity)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
 		"message":       "Stock transferred successfully",
 		"product_id":    req.ProductID,
 		"from_store_id": req.FromStoreID,
@@ -222,32 +383,143 @@ func (h *StockHandler) TransferStock(c *gin.Context) {
 
 // GetLowStockItems godoc
 // @Summary Obtener productos con stock bajo
+// @Description Filas cuya disponibilidad está por debajo de su min_stock (si está definido) o del umbral indicado. Primero las de productos de clase A, después B y por último C y sin clasificar; dentro de cada clase, de menor a mayor disponibilidad
 // @Tags stock
 // @Produce json
-// @Param threshold query int false "Umbral de stock bajo" default(10)
-// @Success 200 {array} domain.Stock
+// @Param threshold query int false "Umbral para las filas sin min_stock" default(10)
+// @Param storeId query string false "Limitar a una tienda"
+// @Param category query string false "Limitar a una categoría de producto"
+// @Param abc_class query string false "Limitar a una clase ABC de producto" Enums(A, B, C)
+// @Param limit query int false "Máximo de resultados (máx. 500)" default(50)
+// @Param offset query int false "Resultados a saltar" default(0)
+// @Param format query string false "json, o csv/xlsx para descargar todas las filas (ignora limit y offset)" Enums(json, csv, xlsx) default(json)
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Success 200 {object} LowStockResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /stock/low-stock [get]
 func (h *StockHandler) GetLowStockItems(c *gin.Context) {
-	threshold, _ := strconv.Atoi(c.DefaultQuery("threshold", "10"))
+	filter := domain.LowStockFilter{
+		StoreID:   c.Query("storeId"),
+		Category:  c.Query("category"),
+		Threshold: domain.DefaultMinStock,
+	}
+
+	var err error
+	if filter.ABCClass, err = domain.ParseABCClass(c.Query("abc_class")); err != nil {
+		handleError(c, err)
+		return
+	}
+	if raw := c.Query("threshold"); raw != "" {
+		if filter.Threshold, err = strconv.Atoi(raw); err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid threshold", err.Error())
+			return
+		}
+	}
+
+	format, ok := parseTableFormat(c)
+	if !ok {
+		return
+	}
+	if format != formatJSON {
+		h.downloadLowStockItems(c, format, filter)
+		return
+	}
+
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		handleError(c, err)
+		return
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultLowStockLimit
+	}
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		handleError(c, err)
+		return
+	}
 
-	stocks, err := h.stockService.GetLowStockItems(c.Request.Context(), threshold)
+	stocks, total, err := h.stockService.GetLowStockItems(c.Request.Context(), filter)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"threshold": threshold,
+	respondList(c, http.StatusOK, stocks, gin.H{
+		"threshold": filter.Threshold,
+		"store_id":  filter.StoreID,
+		"category":  filter.Category,
+		"abc_class": filter.ABCClass,
+		"count":     len(stocks),
+		"total":     total,
+		"limit":     filter.Limit,
+		"offset":    filter.Offset,
+	}, gin.H{
+		"threshold": filter.Threshold,
+		"store_id":  filter.StoreID,
+		"category":  filter.Category,
+		"abc_class": filter.ABCClass,
 		"items":     stocks,
 		"count":     len(stocks),
+		"total":     total,
+		"limit":     filter.Limit,
+		"offset":    filter.Offset,
+	})
+}
+
+// lowStockColumns columnas de la descarga de stock bajo
+var lowStockColumns = []string{"product_id", "store_id", "quantity", "reserved", "safety_stock", "sellable", "min_stock", "max_stock", "updated_at"}
+
+// downloadLowStockItems descarga todas las filas de stock bajo del filtro como CSV o XLSX
+func (h *StockHandler) downloadLowStockItems(c *gin.Context, format string, filter domain.LowStockFilter) {
+	// Validar antes de enviar la cabecera del fichero
+	if err := filter.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	streamTable(c, format, "low-stock", lowStockColumns, func(w tableWriter) error {
+		return h.stockService.StreamLowStockItems(c.Request.Context(), filter, func(stock *domain.Stock) error {
+			return w.WriteRow(stock.ProductID, stock.StoreID, stock.Quantity, stock.Reserved, stock.SafetyStock,
+				stock.Sellable(), stock.MinStock, stock.MaxStock, stock.UpdatedAt)
+		})
 	})
 }
 
+// CompareStores godoc
+// @Summary Comparar surtido y disponibilidad entre dos tiendas
+// @Description Lista productos presentes solo en una de las tiendas y productos comunes con gran diferencia de disponibilidad
+// @Tags stock
+// @Produce json
+// @Param storeA query string true "Store ID A"
+// @Param storeB query string true "Store ID B"
+// @Param minDifference query int false "Diferencia mínima de disponibilidad" default(10)
+// @Success 200 {object} StockComparisonResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /stock/compare [get]
+func (h *StockHandler) CompareStores(c *gin.Context) {
+	minDifference, err := strconv.Atoi(c.DefaultQuery("minDifference", "10"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid minDifference", err.Error())
+		return
+	}
+
+	comparison, err := h.stockService.CompareStores(c.Request.Context(), c.Query("storeA"), c.Query("storeB"), minDifference)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, comparison)
+}
+
 // InitializeStockRequest representa la petición para inicializar stock
 type InitializeStockRequest struct {
-	ProductID       string `json:"product_id" binding:"required"`
-	StoreID         string `json:"store_id" binding:"required"`
-	InitialQuantity int    `json:"initial_quantity" binding:"required,min=0"`
+	ProductID       string  `json:"product_id" binding:"required"`
+	StoreID         string  `json:"store_id" binding:"required"`
+	InitialQuantity float64 `json:"initial_quantity" binding:"required,min=0"` // Decimal solo en unidades fractional
+	Unit            string  `json:"unit" example:"BOX"`                        // Opcional: unidad base del producto por defecto
 }
 
 // InitializeStock godoc
@@ -256,26 +528,64 @@ type InitializeStockRequest struct {
 // @Accept json
 // @Produce json
 // @Param request body InitializeStockRequest true "Datos de inicialización"
-// @Success 201 {object} domain.Stock
+// @Success 201 {object} StockResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
 // @Router /stock [post]
 func (h *StockHandler) InitializeStock(c *gin.Context) {
 	var req InitializeStockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request body",
-			Message: err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 
-	stock, err := h.stockService.InitializeStock(c.Request.Context(), req.ProductID, req.StoreID, req.InitialQuantity)
+	quantity, err := toBaseDecimalQuantity(c, h.unitService, req.ProductID, req.InitialQuantity, req.Unit, req.StoreID)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, stock)
+	stock, err := h.stockService.InitializeStock(c.Request.Context(), req.ProductID, req.StoreID, quantity)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusCreated, stock)
+}
+
+// GetProductAvailability godoc
+// @Summary Disponibilidad pública de un producto por tienda
+// @Description Endpoint público. Sin API key, las tiendas configuradas con hide_quantities (PUT /admin/stores/{id}/stock-visibility) solo informan el tramo (status); con API key válida se responden siempre las cantidades vendibles.
+// @Tags stock
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param store_id query string false "Limitar a una tienda"
+// @Param fulfillment query string false "Forma de entrega: solo las ubicaciones cuyo tipo la sirve en la política del canal, las preferidas primero" Enums(SHIP, PICKUP)
+// @Param X-Sales-Channel header string false "Canal de venta: elige la política de fulfillment (también ?channel=)" Enums(WEB, STORE, MARKETPLACE)
+// @Param X-API-Key header string false "API key opcional: incluye las cantidades de todas las tiendas"
+// @Success 200 {object} ProductAvailabilityResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /products/{id}/availability [get]
+func (h *StockHandler) GetProductAvailability(c *gin.Context) {
+	// OptionalAPIKeyAuth solo deja api_key en el contexto si la key es válida
+	authenticated := c.GetString("api_key") != ""
+
+	fulfillment, err := domain.ParseFulfillmentMode(c.Query("fulfillment"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	availability, err := h.stockService.GetProductAvailability(c.Request.Context(), c.Param("id"), c.Query("store_id"), authenticated, fulfillment)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
 }
 
 // CheckAvailability godoc
@@ -284,19 +594,31 @@ func (h *StockHandler) InitializeStock(c *gin.Context) {
 // @Produce json
 // @Param productId path string true "ID del producto"
 // @Param storeId path string true "ID de la tienda"
-// @Param quantity query int true "Cantidad requerida"
-// @Success 200 {object} map[string]interface{}
+// @Description Con date responde la disponibilidad prevista en esa fecha (AvailabilityForecastResponse): el stock actual más las reservas pendientes que caducan antes y los cambios programados (pedidos de compra, transferencias) que llegan antes. Con date quantity es opcional.
+// @Param quantity query int false "Cantidad requerida (obligatoria sin date)"
+// @Param unit query string false "Unidad de quantity (por defecto la unidad base del producto)"
+// @Param date query string false "Fecha futura (RFC3339, o YYYY-MM-DD = hasta el final del día)"
+// @Param X-Sales-Channel header string false "Canal de venta: disponibilidad de su asignación o de la parte no asignada (también ?channel=)" Enums(WEB, STORE, MARKETPLACE)
+// @Success 200 {object} AvailabilityResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /stock/{productId}/{storeId}/availability [get]
 func (h *StockHandler) CheckAvailability(c *gin.Context) {
 	productID := c.Param("productId")
 	storeID := c.Param("storeId")
+	if c.Query("date") != "" {
+		h.checkAvailabilityOn(c, productID, storeID)
+		return
+	}
 	quantity, _ := strconv.Atoi(c.Query("quantity"))
 
 	if quantity <= 0 {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid quantity",
-			Message: "Quantity must be positive",
-		})
+		respondError(c, http.StatusBadRequest, "Invalid quantity", "Quantity must be positive")
+		return
+	}
+	quantity, err := toBaseQuantity(c, h.unitService, productID, quantity, c.Query("unit"))
+	if err != nil {
+		handleError(c, err)
 		return
 	}
 
@@ -308,7 +630,7 @@ func (h *StockHandler) CheckAvailability(c *gin.Context) {
 
 	actualAvailable, _ := h.stockService.GetAvailableStock(c.Request.Context(), productID, storeID)
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"product_id": productID,
 		"store_id":   storeID,
 		"requested":  quantity,
@@ -316,3 +638,40 @@ func (h *StockHandler) CheckAvailability(c *gin.Context) {
 		"sufficient": available,
 	})
 }
+
+// checkAvailabilityOn responde la disponibilidad prevista en ?date= (quantity opcional)
+func (h *StockHandler) checkAvailabilityOn(c *gin.Context, productID, storeID string) {
+	date, err := queryTime(c, "date")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if len(c.Query("date")) == len("2006-01-02") {
+		next := date.AddDate(0, 0, 1) // Todo el día indicado
+		date = &next
+	}
+
+	quantity, err := queryInt(c, "quantity")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if quantity < 0 {
+		handleError(c, &domain.ValidationError{Field: "quantity", Message: "quantity must be positive"})
+		return
+	}
+	if quantity > 0 {
+		if quantity, err = toBaseQuantity(c, h.unitService, productID, quantity, c.Query("unit")); err != nil {
+			handleError(c, err)
+			return
+		}
+	}
+
+	forecast, err := h.stockService.GetAvailabilityForecast(c.Request.Context(), productID, storeID, *date, quantity)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, forecast)
+}
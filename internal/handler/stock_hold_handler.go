@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StockHoldHandler maneja las retenciones internas de stock (exposición, reparación, calidad)
+type StockHoldHandler struct {
+	holdService *service.StockHoldService
+	unitService *service.ProductUnitService // opcional: cantidades en unidades de pedido
+}
+
+// NewStockHoldHandler crea un nuevo handler de retenciones de stock
+func NewStockHoldHandler(holdService *service.StockHoldService) *StockHoldHandler {
+	return &StockHoldHandler{
+		holdService: holdService,
+	}
+}
+
+// SetProductUnitService habilita cantidades en unidades de pedido (quantity + unit)
+func (h *StockHoldHandler) SetProductUnitService(unitService *service.ProductUnitService) {
+	h.unitService = unitService
+}
+
+// PlaceStockHoldRequest representa la petición para retener unidades para uso interno
+type PlaceStockHoldRequest struct {
+	Type     string  `json:"type" binding:"required" enums:"DISPLAY,REPAIR,QUALITY_HOLD" example:"DISPLAY"`
+	Quantity float64 `json:"quantity" binding:"required,gt=0" example:"1"` // Decimal solo en unidades fractional
+	Unit     string  `json:"unit" example:"BOX"`                           // Opcional: unidad base del producto por defecto
+	Note     string  `json:"note" example:"Unidad del escaparate"`         // Opcional
+}
+
+// PlaceHold godoc
+// @Summary Retener unidades para uso interno
+// @Description Retira de la venta unidades vendibles para exposición (DISPLAY), reparación (REPAIR) o control de calidad (QUALITY_HOLD). Cuentan en reserved y en held de la fila y reducen la disponibilidad como una reserva, pero no tienen cliente ni caducan: siguen retenidas hasta POST /holds/{id}/release. Emite stock.hold_placed.
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Param request body PlaceStockHoldRequest true "Tipo y cantidad"
+// @Success 201 {object} StockHoldResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "No hay cantidad vendible suficiente"
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/holds [post]
+func (h *StockHoldHandler) PlaceHold(c *gin.Context) {
+	productID := c.Param("productId")
+	storeID := c.Param("storeId")
+
+	var req PlaceStockHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	quantity, err := toBaseDecimalQuantity(c, h.unitService, productID, req.Quantity, req.Unit, storeID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	hold, err := h.holdService.PlaceHold(c.Request.Context(), productID, storeID, domain.StockHoldType(req.Type), quantity, req.Note)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, hold)
+}
+
+// ListHolds godoc
+// @Summary Listar retenciones internas de stock
+// @Tags stock
+// @Produce json
+// @Param store_id query string false "Filtrar por tienda"
+// @Param product_id query string false "Filtrar por producto"
+// @Param type query string false "Filtrar por tipo" Enums(DISPLAY, REPAIR, QUALITY_HOLD)
+// @Param status query string false "Filtrar por estado" Enums(ACTIVE, RELEASED)
+// @Param limit query int false "Límite de resultados" default(50)
+// @Param offset query int false "Offset para paginación" default(0)
+// @Success 200 {object} StockHoldListResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /holds [get]
+func (h *StockHoldHandler) ListHolds(c *gin.Context) {
+	filter := domain.StockHoldFilter{
+		StoreID:   c.Query("store_id"),
+		ProductID: c.Query("product_id"),
+		Type:      domain.StockHoldType(strings.ToUpper(c.Query("type"))),
+		Status:    domain.StockHoldStatus(strings.ToUpper(c.Query("status"))),
+	}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		handleError(c, &domain.ValidationError{Field: "type", Message: "type must be DISPLAY, REPAIR or QUALITY_HOLD"})
+		return
+	}
+	switch filter.Status {
+	case "", domain.StockHoldActive, domain.StockHoldReleased:
+	default:
+		handleError(c, &domain.ValidationError{Field: "status", Message: "status must be ACTIVE or RELEASED"})
+		return
+	}
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	filter.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	holds, err := h.holdService.ListHolds(c.Request.Context(), filter)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":  holds,
+		"count":  len(holds),
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// GetHold godoc
+// @Summary Obtener una retención interna de stock
+// @Tags stock
+// @Produce json
+// @Param id path string true "ID de la retención"
+// @Success 200 {object} StockHoldResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /holds/{id} [get]
+func (h *StockHoldHandler) GetHold(c *gin.Context) {
+	hold, err := h.holdService.GetHold(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// ReleaseHold godoc
+// @Summary Liberar una retención interna de stock
+// @Description Devuelve las unidades a la venta (resta de reserved y held) y emite stock.hold_released.
+// @Tags stock
+// @Produce json
+// @Param id path string true "ID de la retención"
+// @Success 200 {object} StockHoldResponse
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "La retención ya se liberó"
+// @Security ApiKeyAuth
+// @Router /holds/{id}/release [post]
+func (h *StockHoldHandler) ReleaseHold(c *gin.Context) {
+	hold, err := h.holdService.ReleaseHold(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StockNoteHandler maneja las notas del personal sobre filas de stock y sus movimientos
+type StockNoteHandler struct {
+	noteService *service.StockNoteService
+}
+
+// NewStockNoteHandler crea un nuevo handler de notas de stock
+func NewStockNoteHandler(noteService *service.StockNoteService) *StockNoteHandler {
+	return &StockNoteHandler{
+		noteService: noteService,
+	}
+}
+
+// StockNoteAttachmentRequest representa los metadatos de una foto o documento ya subido
+type StockNoteAttachmentRequest struct {
+	URL         string `json:"url" binding:"required" example:"https://files.example.com/mad-001/albaran-4411.jpg"`
+	FileName    string `json:"file_name" example:"albaran-4411.jpg"`
+	ContentType string `json:"content_type" example:"image/jpeg"` // image/* o application/pdf
+	SizeBytes   int64  `json:"size_bytes" example:"482133"`
+}
+
+// CreateStockNoteRequest representa la petición para anotar una fila de stock o un movimiento
+type CreateStockNoteRequest struct {
+	Text        string                       `json:"text" binding:"required" example:"Caja dañada, faltan 3 unidades"`
+	EventID     string                       `json:"event_id"` // Opcional: movimiento de la línea temporal al que se refiere
+	Attachments []StockNoteAttachmentRequest `json:"attachments"`
+}
+
+// AddNote godoc
+// @Summary Anotar una fila de stock o un movimiento
+// @Description Guarda una nota del personal (p. ej. "caja dañada, faltan 3 unidades") con los metadatos de sus fotos o documentos, para dar contexto a ajustes posteriores y reclamaciones al proveedor. Con event_id la nota se refiere a ese movimiento de la línea temporal (GET /stock/{productId}/{storeId}/history); sin él, a la fila. No cambia cantidades ni emite eventos.
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Param request body CreateStockNoteRequest true "Texto y adjuntos"
+// @Success 201 {object} StockNoteResponse
+// @Failure 400 {object} ErrorResponse "Datos inválidos o event_id de otra fila de stock"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Failure 404 {object} ErrorResponse "Fila de stock o evento no encontrado"
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/notes [post]
+func (h *StockNoteHandler) AddNote(c *gin.Context) {
+	var req CreateStockNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	note := &domain.StockNote{
+		EventID:     req.EventID,
+		Text:        req.Text,
+		Attachments: make([]domain.StockNoteAttachment, len(req.Attachments)),
+	}
+	for i, attachment := range req.Attachments {
+		note.Attachments[i] = domain.StockNoteAttachment{
+			URL:         attachment.URL,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			SizeBytes:   attachment.SizeBytes,
+		}
+	}
+
+	note, err := h.noteService.AddNote(c.Request.Context(), c.Param("productId"), c.Param("storeId"), note)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// ListNotes godoc
+// @Summary Listar las notas de una fila de stock
+// @Description Notas del personal sobre la fila y sus movimientos, de la más reciente a la más antigua. La línea temporal (history) también las incluye junto a cada movimiento.
+// @Tags stock
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Param event_id query string false "Solo las notas de este movimiento"
+// @Param limit query int false "Límite de resultados (máx. 500)" default(50)
+// @Success 200 {object} StockNoteListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/notes [get]
+func (h *StockNoteHandler) ListNotes(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	notes, err := h.noteService.ListNotes(c.Request.Context(), c.Param("productId"), c.Param("storeId"), c.Query("event_id"), limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": notes,
+		"count": len(notes),
+	})
+}
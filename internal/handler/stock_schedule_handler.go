@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StockScheduleHandler maneja los cambios de stock programados
+type StockScheduleHandler struct {
+	scheduleService *service.StockScheduleService
+	unitService     *service.ProductUnitService // opcional: cantidades en unidades de pedido
+}
+
+// NewStockScheduleHandler crea un nuevo handler de cambios de stock programados
+func NewStockScheduleHandler(scheduleService *service.StockScheduleService) *StockScheduleHandler {
+	return &StockScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// SetProductUnitService habilita cantidades en unidades de pedido (quantity + unit)
+func (h *StockScheduleHandler) SetProductUnitService(unitService *service.ProductUnitService) {
+	h.unitService = unitService
+}
+
+// ScheduleStockChangeRequest representa la petición para programar un cambio de stock
+type ScheduleStockChangeRequest struct {
+	Type        string    `json:"type" enums:"ADJUST,SET" example:"ADJUST"`                               // Opcional: ADJUST por defecto
+	Quantity    *int      `json:"quantity" binding:"required" example:"500"`                              // Ajuste (ADJUST, puede ser negativo) o cantidad final (SET)
+	Unit        string    `json:"unit" example:"BOX"`                                                     // Opcional: unidad base del producto por defecto
+	EffectiveAt time.Time `json:"effective_at" binding:"required" example:"2026-12-04T10:00:00Z"`         // RFC3339, debe ser futuro
+	Source      string    `json:"source" enums:"MANUAL,PURCHASE_ORDER,TRANSFER" example:"PURCHASE_ORDER"` // Opcional: MANUAL por defecto
+	Reference   string    `json:"reference" example:"PO-2026-0142"`                                       // Opcional: nº de pedido o de transferencia
+}
+
+// ScheduleStockChange godoc
+// @Summary Programar un cambio de stock
+// @Description Un worker aplica el cambio al llegar effective_at en una única transacción (como PUT/adjust: respeta reservas y sobreventa y emite stock.updated). Si ya no se puede aplicar queda FAILED con el motivo en error. Las llegadas previstas de pedidos de compra y transferencias se programan con source y reference y cuentan en la disponibilidad futura (GET /stock/{productId}/{storeId}/availability?date=).
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Param request body ScheduleStockChangeRequest true "Cambio y fecha efectiva"
+// @Success 201 {object} ScheduledStockChangeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/scheduled-changes [post]
+func (h *StockScheduleHandler) ScheduleStockChange(c *gin.Context) {
+	productID := c.Param("productId")
+	storeID := c.Param("storeId")
+
+	var req ScheduleStockChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	quantity, err := toBaseQuantity(c, h.unitService, productID, *req.Quantity, req.Unit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	schedule, err := h.scheduleService.ScheduleStockChange(c.Request.Context(), &domain.ScheduledStockChange{
+		ProductID:   productID,
+		StoreID:     storeID,
+		Type:        domain.ScheduledStockChangeType(req.Type),
+		Quantity:    quantity,
+		EffectiveAt: req.EffectiveAt,
+		Source:      domain.ScheduledStockSource(req.Source),
+		Reference:   req.Reference,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// ListScheduledStockChanges godoc
+// @Summary Listar los cambios de stock programados de un producto en una tienda
+// @Tags stock
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Success 200 {object} ScheduledStockChangeListResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/scheduled-changes [get]
+func (h *StockScheduleHandler) ListScheduledStockChanges(c *gin.Context) {
+	productID := c.Param("productId")
+	storeID := c.Param("storeId")
+	schedules, err := h.scheduleService.ListScheduledStockChanges(c.Request.Context(), productID, storeID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_id": productID,
+		"store_id":   storeID,
+		"items":      schedules,
+		"count":      len(schedules),
+	})
+}
+
+// CancelScheduledStockChange godoc
+// @Summary Cancelar un cambio de stock programado pendiente
+// @Tags stock
+// @Produce json
+// @Param productId path string true "ID del producto"
+// @Param storeId path string true "ID de la tienda"
+// @Param scheduleId path string true "ID del cambio programado"
+// @Success 200 {object} ScheduledStockChangeResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "El cambio ya se aplicó, falló o se canceló"
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
+// @Router /stock/{productId}/{storeId}/scheduled-changes/{scheduleId} [delete]
+func (h *StockScheduleHandler) CancelScheduledStockChange(c *gin.Context) {
+	schedule, err := h.scheduleService.CancelScheduledStockChange(c.Request.Context(), c.Param("productId"), c.Param("storeId"), c.Param("scheduleId"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
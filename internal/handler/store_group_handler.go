@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoreGroupHandler gestiona los grupos de tiendas (regiones, franquicias)
+type StoreGroupHandler struct {
+	groupService *service.StoreGroupService
+}
+
+// NewStoreGroupHandler crea un nuevo handler de grupos de tiendas
+func NewStoreGroupHandler(groupService *service.StoreGroupService) *StoreGroupHandler {
+	return &StoreGroupHandler{
+		groupService: groupService,
+	}
+}
+
+// StoreGroupRequest representa los datos de un grupo de tiendas
+type StoreGroupRequest struct {
+	ID                string   `json:"id,omitempty" example:"region-levante"` // Solo en la creación; vacío = se genera
+	Name              string   `json:"name" binding:"required" example:"Levante"`
+	Type              string   `json:"type" binding:"required" example:"REGION"` // REGION | FRANCHISE
+	RestrictTransfers bool     `json:"restrict_transfers" example:"true"`        // Transferencias solo entre tiendas del grupo
+	StoreIDs          []string `json:"store_ids" example:"VAL-001,ALC-001"`
+}
+
+func (r StoreGroupRequest) toDomain(id string) *domain.StoreGroup {
+	return &domain.StoreGroup{
+		ID:                id,
+		Name:              r.Name,
+		Type:              domain.StoreGroupType(r.Type),
+		RestrictTransfers: r.RestrictTransfers,
+		StoreIDs:          r.StoreIDs,
+	}
+}
+
+// CreateStoreGroup godoc
+// @Summary Crear un grupo de tiendas
+// @Description Una tienda pertenece como mucho a un grupo de cada tipo (409 si ya está en otro). Con restrict_transfers, las transferencias que salen o llegan a sus tiendas deben quedarse dentro del grupo.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body StoreGroupRequest true "Nombre, tipo, restricción y tiendas"
+// @Success 201 {object} domain.StoreGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/store-groups [post]
+func (h *StoreGroupHandler) CreateStoreGroup(c *gin.Context) {
+	var req StoreGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	group, err := h.groupService.CreateGroup(c.Request.Context(), req.toDomain(req.ID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// ListStoreGroups godoc
+// @Summary Listar grupos de tiendas
+// @Tags admin
+// @Produce json
+// @Param type query string false "Filtrar por tipo (REGION, FRANCHISE)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/store-groups [get]
+func (h *StoreGroupHandler) ListStoreGroups(c *gin.Context) {
+	groups, err := h.groupService.ListGroups(c.Request.Context(), c.Query("type"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"groups": groups,
+		"count":  len(groups),
+	})
+}
+
+// GetStoreGroup godoc
+// @Summary Obtener un grupo de tiendas
+// @Tags admin
+// @Produce json
+// @Param id path string true "ID del grupo"
+// @Success 200 {object} domain.StoreGroup
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/store-groups/{id} [get]
+func (h *StoreGroupHandler) GetStoreGroup(c *gin.Context) {
+	group, err := h.groupService.GetGroup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// UpdateStoreGroup godoc
+// @Summary Reemplazar un grupo de tiendas
+// @Description Reemplaza nombre, tipo, restricción y la lista completa de tiendas
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del grupo"
+// @Param request body StoreGroupRequest true "Nombre, tipo, restricción y tiendas"
+// @Success 200 {object} domain.StoreGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/store-groups/{id} [put]
+func (h *StoreGroupHandler) UpdateStoreGroup(c *gin.Context) {
+	var req StoreGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	group, err := h.groupService.UpdateGroup(c.Request.Context(), req.toDomain(c.Param("id")))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteStoreGroup godoc
+// @Summary Eliminar un grupo de tiendas
+// @Description Las tiendas no se modifican; dejan de aplicarse la agregación y la restricción de transferencias del grupo
+// @Tags admin
+// @Param id path string true "ID del grupo"
+// @Success 204 "Grupo eliminado"
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/store-groups/{id} [delete]
+func (h *StoreGroupHandler) DeleteStoreGroup(c *gin.Context) {
+	if err := h.groupService.DeleteGroup(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoreHandler maneja las peticiones HTTP de tiendas
+type StoreHandler struct {
+	storeService *service.StoreService
+}
+
+// NewStoreHandler crea un nuevo handler de tiendas
+func NewStoreHandler(storeService *service.StoreService) *StoreHandler {
+	return &StoreHandler{
+		storeService: storeService,
+	}
+}
+
+// BootstrapStoreRequest representa el request de onboarding de una tienda
+type BootstrapStoreRequest struct {
+	Name         string                    `json:"name" binding:"required"`
+	Address      string                    `json:"address"`
+	City         string                    `json:"city"`
+	Country      string                    `json:"country"`
+	Phone        string                    `json:"phone"`
+	Email        string                    `json:"email"`
+	LocationType string                    `json:"location_type" enums:"STORE,WAREHOUSE,DARKSTORE"` // Por defecto STORE
+	Template     domain.AssortmentTemplate `json:"template"`
+	MinStock     *int                      `json:"min_stock"`
+	MaxStock     int                       `json:"max_stock"`
+}
+
+// BootstrapStore godoc
+// @Summary Onboarding de una tienda en una sola operación (idempotente)
+// @Description Crea la tienda, aplica el template de surtido, inicializa el stock en cero con umbrales de alerta y emite una API key
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Store ID"
+// @Param request body BootstrapStoreRequest true "Datos de la tienda"
+// @Success 201 {object} domain.StoreBootstrapResult
+// @Success 200 {object} domain.StoreBootstrapResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/bootstrap [post]
+func (h *StoreHandler) BootstrapStore(c *gin.Context) {
+	var req BootstrapStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	minStock := domain.DefaultMinStock
+	if req.MinStock != nil {
+		minStock = *req.MinStock
+	}
+
+	result, err := h.storeService.BootstrapStore(c.Request.Context(), &domain.StoreBootstrap{
+		Store: domain.Store{
+			ID:           c.Param("id"),
+			Name:         req.Name,
+			Address:      req.Address,
+			City:         req.City,
+			Country:      req.Country,
+			Phone:        req.Phone,
+			Email:        req.Email,
+			LocationType: domain.LocationType(req.LocationType),
+		},
+		Template: req.Template,
+		MinStock: minStock,
+		MaxStock: req.MaxStock,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.StoreCreated || result.StockInitialized > 0 || result.APIKeyCreated {
+		status = http.StatusCreated
+	}
+
+	c.JSON(status, result)
+}
+
+// StoreHoursRequest representa el horario de apertura de una tienda
+type StoreHoursRequest struct {
+	Timezone      string                 `json:"timezone" binding:"required" example:"Europe/Madrid"`
+	Periods       []domain.OpeningPeriod `json:"periods" binding:"required"`
+	CutoffMinutes int                    `json:"cutoff_minutes" example:"30"` // Sin reservas nuevas en los últimos N minutos de cada franja
+}
+
+// GetStoreHours godoc
+// @Summary Horario de apertura de una tienda
+// @Tags admin
+// @Produce json
+// @Param id path string true "Store ID"
+// @Success 200 {object} domain.StoreHours
+// @Failure 404 {object} ErrorResponse "Tienda inexistente o sin horario"
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/hours [get]
+func (h *StoreHandler) GetStoreHours(c *gin.Context) {
+	hours, err := h.storeService.GetStoreHours(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// PutStoreHours godoc
+// @Summary Configurar el horario de apertura y el corte de reservas de una tienda
+// @Description Con horario, las reservas solo se crean con la tienda abierta y fuera del corte previo al cierre (409 Store Closed), y el TTL solo corre en horas de apertura. Las reservas pendientes conservan su vencimiento.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Store ID"
+// @Param request body StoreHoursRequest true "Zona horaria, franjas por día y corte"
+// @Success 200 {object} domain.StoreHours
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/hours [put]
+func (h *StoreHandler) PutStoreHours(c *gin.Context) {
+	var req StoreHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	hours, err := h.storeService.SetStoreHours(c.Request.Context(), &domain.StoreHours{
+		StoreID:       c.Param("id"),
+		Timezone:      req.Timezone,
+		Periods:       req.Periods,
+		CutoffMinutes: req.CutoffMinutes,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// DeleteStoreHours godoc
+// @Summary Eliminar el horario de una tienda
+// @Description La tienda vuelve a admitir reservas a cualquier hora con el TTL en tiempo continuo
+// @Tags admin
+// @Param id path string true "Store ID"
+// @Success 204 "Horario eliminado"
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/hours [delete]
+func (h *StoreHandler) DeleteStoreHours(c *gin.Context) {
+	if err := h.storeService.DeleteStoreHours(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// StockVisibilityRequest representa la visibilidad pública de la disponibilidad de una tienda
+type StockVisibilityRequest struct {
+	HideQuantities    bool `json:"hide_quantities"`                                 // Sin API key solo se responde el tramo
+	LowStockThreshold int  `json:"low_stock_threshold" binding:"min=0" example:"5"` // 0 = min_stock de cada fila o 10
+}
+
+// GetStockVisibility godoc
+// @Summary Visibilidad pública de la disponibilidad de una tienda
+// @Tags admin
+// @Produce json
+// @Param id path string true "Store ID"
+// @Success 200 {object} domain.StockVisibility
+// @Failure 404 {object} ErrorResponse "Tienda inexistente o sin configuración"
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/stock-visibility [get]
+func (h *StoreHandler) GetStockVisibility(c *gin.Context) {
+	visibility, err := h.storeService.GetStockVisibility(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, visibility)
+}
+
+// PutStockVisibility godoc
+// @Summary Ocultar al público las cantidades de una tienda
+// @Description Con hide_quantities, GET /products/{id}/availability sin API key solo responde el tramo de la tienda (in_stock, low_stock, out_of_stock); con API key se siguen respondiendo las cantidades. low_stock_threshold fija el umbral de low_stock.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Store ID"
+// @Param request body StockVisibilityRequest true "Visibilidad y umbral de low_stock"
+// @Success 200 {object} domain.StockVisibility
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/stock-visibility [put]
+func (h *StoreHandler) PutStockVisibility(c *gin.Context) {
+	var req StockVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	visibility, err := h.storeService.SetStockVisibility(c.Request.Context(), &domain.StockVisibility{
+		StoreID:           c.Param("id"),
+		HideQuantities:    req.HideQuantities,
+		LowStockThreshold: req.LowStockThreshold,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, visibility)
+}
+
+// DeleteStockVisibility godoc
+// @Summary Eliminar la visibilidad configurada de una tienda
+// @Description La tienda vuelve a mostrar las cantidades al público con el umbral de low_stock de cada fila
+// @Tags admin
+// @Param id path string true "Store ID"
+// @Success 204 "Configuración eliminada"
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/stock-visibility [delete]
+func (h *StoreHandler) DeleteStockVisibility(c *gin.Context) {
+	if err := h.storeService.DeleteStockVisibility(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// StoreLocationTypeRequest representa el tipo de ubicación de una tienda
+type StoreLocationTypeRequest struct {
+	LocationType string `json:"location_type" binding:"required" enums:"STORE,WAREHOUSE,DARKSTORE"`
+}
+
+// PutStoreLocationType godoc
+// @Summary Cambiar el tipo de ubicación de una tienda
+// @Description STORE (tienda con público), WAREHOUSE (almacén) o DARKSTORE. La política de fulfillment de cada canal (GET /admin/fulfillment-policies) decide qué tipos sirven envíos y recogidas.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Store ID"
+// @Param request body StoreLocationTypeRequest true "Tipo de ubicación"
+// @Success 200 {object} domain.Store
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stores/{id}/location-type [put]
+func (h *StoreHandler) PutStoreLocationType(c *gin.Context) {
+	var req StoreLocationTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	store, err := h.storeService.SetLocationType(c.Request.Context(), c.Param("id"), domain.LocationType(req.LocationType))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, store)
+}
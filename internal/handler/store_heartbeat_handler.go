@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoreHeartbeatHandler maneja los latidos de las instancias edge y su estado de sincronización
+type StoreHeartbeatHandler struct {
+	heartbeatService *service.StoreHeartbeatService
+}
+
+// NewStoreHeartbeatHandler crea un nuevo handler de latidos
+func NewStoreHeartbeatHandler(heartbeatService *service.StoreHeartbeatService) *StoreHeartbeatHandler {
+	return &StoreHeartbeatHandler{
+		heartbeatService: heartbeatService,
+	}
+}
+
+// StoreHeartbeatRequest latido periódico de una instancia edge
+type StoreHeartbeatRequest struct {
+	InstanceID    string     `json:"instance_id" binding:"required" example:"edge-mad-01"`
+	StoreID       string     `json:"store_id" binding:"required" example:"MAD-001"`
+	PendingEvents int        `json:"pending_events" binding:"min=0" example:"12"`
+	LastSyncAt    *time.Time `json:"last_sync_at,omitempty"`
+}
+
+// PostHeartbeat godoc
+// @Summary Registrar el latido de una instancia edge
+// @Description Las instancias edge envían periódicamente (p. ej. cada minuto) cuántos eventos tienen pendientes de sincronizar y cuándo sincronizaron por última vez. Se guarda el último latido de cada instance_id; sin last_sync_at se conserva el anterior. Una API key limitada a tiendas solo puede informar de las suyas.
+// @Tags sync
+// @Accept json
+// @Produce json
+// @Param request body StoreHeartbeatRequest true "Estado de sincronización de la instancia"
+// @Success 204 "Latido registrado"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "La API key está limitada a otras tiendas"
+// @Security ApiKeyAuth
+// @Router /sync/heartbeat [post]
+func (h *StoreHeartbeatHandler) PostHeartbeat(c *gin.Context) {
+	var req StoreHeartbeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	err := h.heartbeatService.RecordHeartbeat(c.Request.Context(), &domain.StoreHeartbeat{
+		InstanceID:    req.InstanceID,
+		StoreID:       req.StoreID,
+		PendingEvents: req.PendingEvents,
+		LastSyncAt:    req.LastSyncAt,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetStoresStatus godoc
+// @Summary Estado de sincronización de las tiendas
+// @Description Último latido de cada instancia edge con sus eventos pendientes, desde cuándo tiene la central sus cambios (synced_through: el latido si no tiene pendientes, si no su última sincronización) y si lleva más de STORE_SYNC_STALE_MINUTES sin sincronizar (stale). Ordenado de la más atrasada a la más reciente.
+// @Tags admin
+// @Produce json
+// @Param store_id query string false "Limitar a una tienda"
+// @Param stale query bool false "Solo las tiendas atrasadas (default: false)"
+// @Success 200 {object} StoreSyncReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/stores/status [get]
+func (h *StoreHeartbeatHandler) GetStoresStatus(c *gin.Context) {
+	staleOnly, err := strconv.ParseBool(c.DefaultQuery("stale", "false"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid stale", err.Error())
+		return
+	}
+
+	report, err := h.heartbeatService.GetSyncStatus(c.Request.Context(), c.Query("store_id"), staleOnly)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Formatos de los listados descargables (?format=)
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+	formatXLSX = "xlsx"
+)
+
+// parseTableFormat lee ?format= (json por defecto). Responde 400 y retorna false si no es válido.
+func parseTableFormat(c *gin.Context) (string, bool) {
+	format := strings.ToLower(c.DefaultQuery("format", formatJSON))
+	switch format {
+	case formatJSON, formatCSV, formatXLSX:
+		return format, true
+	}
+	respondError(c, http.StatusBadRequest, "Invalid format", "format must be json, csv or xlsx")
+	return "", false
+}
+
+// tableWriter escribe las filas de un listado directamente en la respuesta, sin acumularlas en memoria.
+// Los valores pueden ser string, int, time.Time o *time.Time.
+type tableWriter interface {
+	WriteRow(values ...interface{}) error
+	Close() error
+}
+
+// streamTable responde con el listado como fichero adjunto (<name>.csv o <name>.xlsx).
+// write emite las filas; si falla a mitad de la descarga la cabecera ya se envió,
+// de modo que el error solo se registra y el cliente recibe un fichero truncado.
+func streamTable(c *gin.Context, format, name string, columns []string, write func(tableWriter) error) {
+	contentType := "text/csv; charset=utf-8"
+	if format == formatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+format))
+	c.Status(http.StatusOK)
+
+	var writer tableWriter
+	var err error
+	if format == formatXLSX {
+		writer, err = newXLSXWriter(c.Writer, columns)
+	} else {
+		writer, err = newCSVWriter(c.Writer, columns)
+	}
+	if err == nil {
+		err = write(writer)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Printf("Error streaming %s.%s: %v", name, format, err)
+		c.Abort()
+	}
+}
+
+// cellText formatea un valor como texto (fechas en RFC3339 UTC)
+func cellText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
+
+// csvWriter escribe CSV; encoding/csv vacía su buffer en la respuesta cada pocos KB
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer, record: make([]string, len(columns))}, nil
+}
+
+func (w *csvWriter) WriteRow(values ...interface{}) error {
+	for i, value := range values {
+		w.record[i] = cellText(value)
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// xlsxParts partes fijas de un libro con una sola hoja. La hoja (xl/worksheets/sheet1.xml)
+// se escribe al final, fila a fila, como última entrada del zip.
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// xlsxWriter escribe un libro XLSX mínimo: los enteros como celdas numéricas y el resto como texto
+type xlsxWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+	row   strings.Builder
+}
+
+func newXLSXWriter(w io.Writer, columns []string) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, xml.Header+part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+
+	writer := &xlsxWriter{zip: archive, sheet: sheet}
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	if err := writer.WriteRow(header...); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *xlsxWriter) WriteRow(values ...interface{}) error {
+	w.row.Reset()
+	w.row.WriteString("<row>")
+	for _, value := range values {
+		if n, ok := value.(int); ok {
+			w.row.WriteString(`<c><v>` + strconv.Itoa(n) + `</v></c>`)
+			continue
+		}
+		w.row.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(&w.row, []byte(cellText(value))); err != nil {
+			return err
+		}
+		w.row.WriteString(`</t></is></c>`)
+	}
+	w.row.WriteString("</row>")
+
+	_, err := io.WriteString(w.sheet, w.row.String())
+	return err
+}
+
+func (w *xlsxWriter) Close() error {
+	if _, err := io.WriteString(w.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return w.zip.Close()
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransferReservationHandler maneja las reservas servidas mediante transferencia entre tiendas
+type TransferReservationHandler struct {
+	transferReservationService *service.TransferReservationService
+	unitService                *service.ProductUnitService // Opcional: cantidades en unidades de pedido (BOX, CASE...)
+}
+
+// NewTransferReservationHandler crea un nuevo handler de reservas con transferencia
+func NewTransferReservationHandler(transferReservationService *service.TransferReservationService) *TransferReservationHandler {
+	return &TransferReservationHandler{
+		transferReservationService: transferReservationService,
+	}
+}
+
+// SetProductUnitService habilita cantidad + unidad en las peticiones (se convierten a la unidad base)
+func (h *TransferReservationHandler) SetProductUnitService(unitService *service.ProductUnitService) {
+	h.unitService = unitService
+}
+
+// CreateTransferReservationRequest representa la petición de reserva con transferencia
+type CreateTransferReservationRequest struct {
+	ProductID        string `json:"product_id" binding:"required"`
+	PreferredStoreID string `json:"preferred_store_id" binding:"required"` // Tienda donde el cliente recogerá el producto
+	SourceStoreID    string `json:"source_store_id"`                       // Opcional: si se omite se elige la tienda con más disponibilidad
+	CustomerID       string `json:"customer_id" binding:"required"`
+	Quantity         int    `json:"quantity" binding:"required,min=1"`
+	TTLMinutes       int    `json:"ttl_minutes" binding:"omitempty,min=1"`
+	Unit             string `json:"unit" example:"BOX"` // Opcional: unidad base del producto por defecto
+}
+
+// CreateTransferReservation godoc
+// @Summary Reservar en otra tienda y crear la transferencia hacia la tienda preferida
+// @Description Cuando la tienda preferida no tiene stock, reserva en la tienda origen, crea el borrador de transferencia y vincula ambos. Actúa la tienda preferida: una API key limitada a tiendas solo puede crearla si incluye preferred_store_id, aunque no incluya la tienda origen.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param request body CreateTransferReservationRequest true "Datos de la reserva"
+// @Success 201 {object} TransferReservationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "La tienda preferida tiene stock o no hay stock en otras tiendas"
+// @Failure 403 {object} ErrorResponse "La API key no puede escribir en la tienda preferida"
+// @Security ApiKeyAuth
+// @Router /reservations/transfer [post]
+func (h *TransferReservationHandler) CreateTransferReservation(c *gin.Context) {
+	var req CreateTransferReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	quantity, err := toBaseQuantity(c, h.unitService, req.ProductID, req.Quantity, req.Unit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	result, err := h.transferReservationService.CreateTransferReservation(
+		c.Request.Context(),
+		req.ProductID,
+		req.PreferredStoreID,
+		req.SourceStoreID,
+		req.CustomerID,
+		quantity,
+		req.TTLMinutes,
+	)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// GetTransferReservation godoc
+// @Summary Obtener el estado combinado de una reserva con transferencia
+// @Tags reservations
+// @Produce json
+// @Param id path string true "ID de la reserva"
+// @Success 200 {object} TransferReservationResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /reservations/{id}/transfer [get]
+func (h *TransferReservationHandler) GetTransferReservation(c *gin.Context) {
+	result, err := h.transferReservationService.GetTransferReservation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
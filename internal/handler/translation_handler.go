@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TranslationHandler maneja las traducciones de productos
+type TranslationHandler struct {
+	translationService *service.TranslationService
+}
+
+// NewTranslationHandler crea un nuevo handler de traducciones
+func NewTranslationHandler(translationService *service.TranslationService) *TranslationHandler {
+	return &TranslationHandler{
+		translationService: translationService,
+	}
+}
+
+// ProductTranslationRequest representa el body de una traducción
+type ProductTranslationRequest struct {
+	Name        string `json:"name" binding:"required" example:"Portàtil HP Pavilion 15"`
+	Description string `json:"description" example:"Portàtil de 15 polzades"` // Vacía = se usa la del idioma por defecto
+}
+
+// ListTranslations godoc
+// @Summary Listar las traducciones de un producto
+// @Description El idioma por defecto no aparece: su texto es el name/description del propio producto
+// @Tags products
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Success 200 {object} ProductTranslationListResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/translations [get]
+func (h *TranslationHandler) ListTranslations(c *gin.Context) {
+	productID := c.Param("id")
+	translations, err := h.translationService.ListTranslations(c.Request.Context(), productID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_id":     productID,
+		"default_locale": h.translationService.Policy().Default,
+		"items":          translations,
+		"count":          len(translations),
+	})
+}
+
+// PutTranslation godoc
+// @Summary Crear o reemplazar la traducción de un producto
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del producto"
+// @Param locale path string true "Idioma (p. ej. ca, en); no admite el idioma por defecto"
+// @Param request body ProductTranslationRequest true "Nombre y descripción traducidos"
+// @Success 200 {object} ProductTranslationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/translations/{locale} [put]
+func (h *TranslationHandler) PutTranslation(c *gin.Context) {
+	var req ProductTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	translation, err := h.translationService.PutTranslation(c.Request.Context(), c.Param("id"), c.Param("locale"), req.Name, req.Description)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+// DeleteTranslation godoc
+// @Summary Eliminar la traducción de un producto
+// @Description Las lecturas en ese idioma vuelven a usar el idioma por defecto
+// @Tags products
+// @Param id path string true "ID del producto"
+// @Param locale path string true "Idioma"
+// @Success 204 "Traducción eliminada"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/translations/{locale} [delete]
+func (h *TranslationHandler) DeleteTranslation(c *gin.Context) {
+	if err := h.translationService.DeleteTranslation(c.Request.Context(), c.Param("id"), c.Param("locale")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,95 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"inventory-system/internal/domain"
+)
+
+// AvailabilityCachePublisher decora un EventPublisher y mantiene el cache de disponibilidad
+// al día con los eventos que emiten los servicios tras confirmar cada cambio en la BD:
+//
+//   - reservation.created: DECRBY quantity
+//   - reservation.cancelled / reservation.expired: INCRBY quantity
+//   - reservation.confirmed, reservation.picked_up: sin cambios (quantity y reserved bajan a la vez)
+//   - reservation.ready_for_pickup: sin cambios (las unidades siguen reservadas)
+//   - cualquier otro evento con producto y tienda (stock.*, transfer.*, ...): se invalida
+//     la entrada y la siguiente lectura la rellena desde la BD
+//
+// Solo debe envolver el publisher de los servicios: los re-intentos del outbox
+// (EventSyncService) volverían a aplicar los deltas de eventos ya contabilizados.
+type AvailabilityCachePublisher struct {
+	inner domain.EventPublisher
+	cache domain.AvailabilityCache
+}
+
+// NewAvailabilityCachePublisher crea el decorador
+func NewAvailabilityCachePublisher(inner domain.EventPublisher, cache domain.AvailabilityCache) *AvailabilityCachePublisher {
+	return &AvailabilityCachePublisher{
+		inner: inner,
+		cache: cache,
+	}
+}
+
+// Publish actualiza el cache y delega en el publisher interno
+func (p *AvailabilityCachePublisher) Publish(ctx context.Context, event *domain.Event) error {
+	p.apply(ctx, event)
+	return p.inner.Publish(ctx, event)
+}
+
+// PublishBatch actualiza el cache con cada evento y delega en el publisher interno
+func (p *AvailabilityCachePublisher) PublishBatch(ctx context.Context, events []*domain.Event) error {
+	for _, event := range events {
+		p.apply(ctx, event)
+	}
+	return p.inner.PublishBatch(ctx, events)
+}
+
+// Close cierra el cache y el publisher interno
+func (p *AvailabilityCachePublisher) Close() error {
+	if err := p.cache.Close(); err != nil {
+		log.Printf("Error closing availability cache: %v", err)
+	}
+	return p.inner.Close()
+}
+
+// apply traduce el evento a operaciones sobre el cache. Los errores solo se registran:
+// el evento ya está persistido y el TTL del cache acota cualquier desviación.
+func (p *AvailabilityCachePublisher) apply(ctx context.Context, event *domain.Event) {
+	var payload struct {
+		ProductID   string `json:"product_id"`
+		StoreID     string `json:"store_id"`
+		FromStoreID string `json:"from_store_id"`
+		ToStoreID   string `json:"to_store_id"`
+		Quantity    int    `json:"quantity"`
+	}
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil || payload.ProductID == "" {
+		return
+	}
+
+	var err error
+	switch event.EventType {
+	case domain.EventReservationCreated, domain.EventPreorderAllocated:
+		err = p.cache.Reserve(ctx, payload.ProductID, payload.StoreID, payload.Quantity)
+	case domain.EventReservationCancelled, domain.EventReservationExpired:
+		err = p.cache.Release(ctx, payload.ProductID, payload.StoreID, payload.Quantity)
+	case domain.EventReservationConfirmed, domain.EventReservationPickedUp, domain.EventReservationReady,
+		domain.EventPreorderCreated, domain.EventPreorderCancelled:
+		return
+	default:
+		for _, storeID := range []string{payload.StoreID, payload.FromStoreID, payload.ToStoreID} {
+			if storeID == "" {
+				continue
+			}
+			if invalidateErr := p.cache.Invalidate(ctx, payload.ProductID, storeID); invalidateErr != nil {
+				err = invalidateErr
+			}
+		}
+	}
+
+	if err != nil {
+		log.Printf("Warning: failed to update availability cache for event %s: %v", event.ID, err)
+	}
+}
@@ -0,0 +1,229 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// Pinger lo implementan los publishers que pueden comprobar la conexión con el broker
+// sin publicar nada (p. ej. RedisPublisher). El circuit breaker lo usa para sondear.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// CircuitBreakerConfig configuración del circuit breaker del publisher
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // Errores consecutivos que abren el circuito (5 por defecto)
+	OpenTimeout      time.Duration // Tiempo abierto antes de volver a probar el broker (30s por defecto)
+}
+
+// CircuitBreakerPublisher decora un EventPublisher con un circuit breaker.
+//
+// Tras FailureThreshold errores consecutivos el circuito se abre: Publish devuelve
+// domain.ErrPublisherUnavailable sin contactar con el broker, de modo que los flujos de
+// negocio no se bloquean esperando timeouts y los eventos quedan solo en el outbox.
+// Pasado OpenTimeout se sondea el broker (Ping si el publisher lo soporta, o una única
+// publicación de prueba en half-open) y el circuito se cierra al primer éxito.
+type CircuitBreakerPublisher struct {
+	inner domain.EventPublisher
+	cfg   CircuitBreakerConfig
+	now   func() time.Time
+
+	mu        sync.Mutex
+	state     domain.CircuitState
+	failures  int
+	openedAt  time.Time
+	lastError string
+	probing   bool // Hay una publicación de prueba en curso (half-open)
+
+	rejected atomic.Uint64
+	opens    atomic.Uint64
+
+	done chan struct{}
+}
+
+// NewCircuitBreakerPublisher crea el decorador y, si el publisher interno implementa
+// Pinger, arranca el sondeo periódico mientras el circuito está abierto
+func NewCircuitBreakerPublisher(inner domain.EventPublisher, cfg CircuitBreakerConfig) *CircuitBreakerPublisher {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+
+	b := &CircuitBreakerPublisher{
+		inner: inner,
+		cfg:   cfg,
+		now:   time.Now,
+		state: domain.CircuitClosed,
+		done:  make(chan struct{}),
+	}
+
+	if pinger, ok := inner.(Pinger); ok {
+		go b.probeLoop(pinger)
+	}
+
+	return b
+}
+
+// Publish delega en el publisher interno salvo que el circuito esté abierto
+func (b *CircuitBreakerPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	err = b.inner.Publish(ctx, event)
+	b.record(probe, err)
+	return err
+}
+
+// PublishBatch delega en el publisher interno salvo que el circuito esté abierto.
+// Un lote fallido cuenta como un único error.
+func (b *CircuitBreakerPublisher) PublishBatch(ctx context.Context, events []*domain.Event) error {
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	err = b.inner.PublishBatch(ctx, events)
+	b.record(probe, err)
+	return err
+}
+
+// Close detiene el sondeo y cierra el publisher interno
+func (b *CircuitBreakerPublisher) Close() error {
+	close(b.done)
+	return b.inner.Close()
+}
+
+// Status retorna el estado actual del circuito
+func (b *CircuitBreakerPublisher) Status() domain.PublisherStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := domain.PublisherStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		FailureThreshold:    b.cfg.FailureThreshold,
+		LastError:           b.lastError,
+		RejectedTotal:       b.rejected.Load(),
+		OpensTotal:          b.opens.Load(),
+	}
+	if b.state != domain.CircuitClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+
+	return status
+}
+
+// allow decide si una publicación puede pasar. probe indica que es la publicación
+// de prueba de half-open.
+func (b *CircuitBreakerPublisher) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case domain.CircuitClosed:
+		return false, nil
+
+	case domain.CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cfg.OpenTimeout {
+			b.rejected.Add(1)
+			return false, domain.ErrPublisherUnavailable
+		}
+		b.state = domain.CircuitHalfOpen
+		fallthrough
+
+	default: // half-open: solo una publicación de prueba a la vez
+		if b.probing {
+			b.rejected.Add(1)
+			return false, domain.ErrPublisherUnavailable
+		}
+		b.probing = true
+		return true, nil
+	}
+}
+
+// record actualiza el circuito con el resultado de una publicación
+func (b *CircuitBreakerPublisher) record(probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+
+	if err == nil {
+		if b.state != domain.CircuitClosed {
+			log.Printf("✅ Event publisher recovered, circuit closed")
+		}
+		b.state = domain.CircuitClosed
+		b.failures = 0
+		b.lastError = ""
+		return
+	}
+
+	b.failures++
+	b.lastError = err.Error()
+
+	if probe || (b.state == domain.CircuitClosed && b.failures >= b.cfg.FailureThreshold) {
+		b.open()
+	}
+}
+
+// open abre (o reabre tras una prueba fallida) el circuito. Debe llamarse con b.mu bloqueado.
+func (b *CircuitBreakerPublisher) open() {
+	if b.state == domain.CircuitClosed {
+		b.opens.Add(1)
+		log.Printf("🔌 Event publisher circuit opened after %d consecutive failures (last: %s); events stay in the outbox, next probe in %s",
+			b.failures, b.lastError, b.cfg.OpenTimeout)
+	}
+	b.state = domain.CircuitOpen
+	b.openedAt = b.now()
+}
+
+// probeLoop sondea el broker con Ping mientras el circuito está abierto
+func (b *CircuitBreakerPublisher) probeLoop(pinger Pinger) {
+	ticker := time.NewTicker(b.cfg.OpenTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			due := b.state == domain.CircuitOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout
+			b.mu.Unlock()
+			if !due {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := pinger.Ping(ctx)
+			cancel()
+
+			b.mu.Lock()
+			if b.state == domain.CircuitOpen {
+				if err == nil {
+					log.Printf("✅ Event broker reachable again, circuit closed")
+					b.state = domain.CircuitClosed
+					b.failures = 0
+					b.lastError = ""
+				} else {
+					b.lastError = err.Error()
+					b.openedAt = b.now()
+				}
+			}
+			b.mu.Unlock()
+		}
+	}
+}
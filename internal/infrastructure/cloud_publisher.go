@@ -0,0 +1,132 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"inventory-system/internal/domain"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// Código compartido por los publishers de brokers gestionados (Google Cloud Pub/Sub, AWS SNS/SQS)
+// y los demás clientes cloud (S3, reporte de errores).
+
+// cloudHTTPClient cliente HTTP de los clientes cloud que hablan con la API del proveedor directamente
+var cloudHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// cloudCloseTimeout espera máxima de Close a que terminen las publicaciones en curso
+const cloudCloseTimeout = 10 * time.Second
+
+// errPublisherClosed se devuelve al publicar después de Close
+var errPublisherClosed = errors.New("publisher closed")
+
+// eventAttributes atributos de mensaje comunes: permiten filtrar suscripciones
+// (filter policies de SNS, filtros de Pub/Sub) sin deserializar el evento
+func eventAttributes(event *domain.Event) map[string]string {
+	attributes := map[string]string{
+		"event_id":       event.ID,
+		"event_type":     event.EventType,
+		"store_id":       event.StoreID,
+		"aggregate_type": event.AggregateType,
+		"aggregate_id":   event.AggregateID,
+	}
+	if event.CorrelationID != "" {
+		attributes["correlation_id"] = event.CorrelationID
+	}
+	return attributes
+}
+
+// eventMessageBody serializa el evento completo a JSON (cuerpo de los mensajes SNS/SQS)
+func eventMessageBody(ctx context.Context, event *domain.Event) (string, error) {
+	domain.AttachCorrelationID(ctx, event)
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event %s: %w", event.ID, err)
+	}
+	return string(eventJSON), nil
+}
+
+// inflightGroup registra las publicaciones en curso para que Close las espere
+// (flush) y rechace las nuevas
+type inflightGroup struct {
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// begin registra una publicación; retorna errPublisherClosed tras Close
+func (g *inflightGroup) begin() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return errPublisherClosed
+	}
+	g.wg.Add(1)
+	return nil
+}
+
+func (g *inflightGroup) done() {
+	g.wg.Done()
+}
+
+// close rechaza nuevas publicaciones y espera a las que están en curso. Retorna false
+// si se agotó el timeout.
+func (g *inflightGroup) close(timeout time.Duration) bool {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// AWSCredentialsConfig región y credenciales de los publishers de AWS. Sin access key se usa la
+// cadena de credenciales por defecto del SDK (variables de entorno, perfil, rol de la tarea o
+// de la instancia).
+type AWSCredentialsConfig struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Solo con credenciales temporales (STS)
+}
+
+// loadAWSConfig carga la configuración del SDK de AWS
+func loadAWSConfig(ctx context.Context, creds AWSCredentialsConfig) (aws.Config, error) {
+	var options []func(*awsconfig.LoadOptions) error
+	if creds.Region != "" {
+		options = append(options, awsconfig.WithRegion(creds.Region))
+	}
+	if creds.AccessKeyID != "" {
+		options = append(options, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	if cfg.Region == "" {
+		return aws.Config{}, errors.New("AWS region is required")
+	}
+	return cfg, nil
+}
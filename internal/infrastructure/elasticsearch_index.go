@@ -0,0 +1,158 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// ElasticsearchIndex implementa domain.SearchIndex sobre la API REST de Elasticsearch
+// (compatible con OpenSearch). El índice se crea con el mapping dinámico en la primera
+// escritura; las búsquedas son multi_match con fuzziness sobre nombre, SKU, categoría y
+// descripción.
+type ElasticsearchIndex struct {
+	baseURL       string
+	index         string
+	authorization string
+}
+
+// ElasticsearchIndexConfig configuración para ElasticsearchIndex
+type ElasticsearchIndexConfig struct {
+	URL    string // "http://localhost:9200"
+	Index  string // Nombre del índice (default: "products")
+	APIKey string // Opcional: API key codificada (cabecera "Authorization: ApiKey ...")
+}
+
+// NewElasticsearchIndex crea el cliente y verifica la conexión con el cluster
+func NewElasticsearchIndex(cfg ElasticsearchIndexConfig) (*ElasticsearchIndex, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("elasticsearch URL is required")
+	}
+	if cfg.Index == "" {
+		cfg.Index = "products"
+	}
+
+	i := &ElasticsearchIndex{
+		baseURL: strings.TrimRight(cfg.URL, "/"),
+		index:   cfg.Index,
+	}
+	if cfg.APIKey != "" {
+		i.authorization = "ApiKey " + cfg.APIKey
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := searchRequest(ctx, http.MethodGet, i.baseURL+"/", i.authorization, "", nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to connect to Elasticsearch: %w", err)
+	}
+
+	log.Printf("✅ Using Elasticsearch index %s at %s", cfg.Index, i.baseURL)
+
+	return i, nil
+}
+
+// Upsert indexa los documentos con una única llamada a _bulk
+func (i *ElasticsearchIndex) Upsert(ctx context.Context, docs ...*domain.ProductSearchDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, doc := range docs {
+		action := map[string]map[string]string{"index": {"_index": i.index, "_id": doc.ID}}
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return fmt.Errorf("failed to marshal search document %s: %w", doc.ID, err)
+		}
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			ID    string          `json:"_id"`
+			Error json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if _, err := searchRequest(ctx, http.MethodPost, i.baseURL+"/_bulk", i.authorization, "application/x-ndjson", body.Bytes(), &result); err != nil {
+		return fmt.Errorf("failed to index documents in Elasticsearch: %w", err)
+	}
+
+	if result.Errors {
+		var errs []error
+		for _, item := range result.Items {
+			for _, entry := range item {
+				if len(entry.Error) > 0 {
+					errs = append(errs, fmt.Errorf("document %s: %s", entry.ID, entry.Error))
+				}
+			}
+		}
+		return fmt.Errorf("failed to index documents in Elasticsearch: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// Delete elimina el documento del producto; un 404 no es error
+func (i *ElasticsearchIndex) Delete(ctx context.Context, productID string) error {
+	endpoint := fmt.Sprintf("%s/%s/_doc/%s", i.baseURL, url.PathEscape(i.index), url.PathEscape(productID))
+	if _, err := searchRequest(ctx, http.MethodDelete, endpoint, i.authorization, "", nil, nil, http.StatusNotFound); err != nil {
+		return fmt.Errorf("failed to delete document from Elasticsearch: %w", err)
+	}
+	return nil
+}
+
+// Search retorna los IDs de los productos que coinciden con query, por relevancia.
+// Un índice todavía inexistente (404) equivale a cero resultados.
+func (i *ElasticsearchIndex) Search(ctx context.Context, query string, limit, offset int) ([]string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"from":    offset,
+		"size":    limit,
+		"_source": false,
+		"query": map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":     query,
+				"fields":    []string{"name^3", "sku^2", "category", "description"},
+				"fuzziness": "AUTO",
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	endpoint := fmt.Sprintf("%s/%s/_search", i.baseURL, url.PathEscape(i.index))
+	if _, err := searchRequest(ctx, http.MethodPost, endpoint, i.authorization, "application/json", body, &result, http.StatusNotFound); err != nil {
+		return nil, fmt.Errorf("failed to search Elasticsearch: %w", err)
+	}
+
+	ids := make([]string, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids, nil
+}
+
+// Close no mantiene conexiones propias (usa el cliente HTTP compartido)
+func (i *ElasticsearchIndex) Close() error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// SentryReporter implementa ErrorReporter enviando cada panic como evento a Sentry
+// (endpoint store del DSN), sin depender del SDK
+type SentryReporter struct {
+	storeURL    string
+	auth        string
+	environment string
+	client      *http.Client
+}
+
+// NewSentryReporter crea un reporter a partir del DSN (https://<key>@<host>/<project>)
+func NewSentryReporter(dsn, environment string) (*SentryReporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || u.User.Username() == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN")
+	}
+	path := strings.Trim(u.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	projectID, prefix := path[slash+1:], ""
+	if slash >= 0 {
+		prefix = "/" + path[:slash]
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN: missing project ID")
+	}
+
+	return &SentryReporter{
+		storeURL:    fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, projectID),
+		auth:        fmt.Sprintf("Sentry sentry_version=7, sentry_client=inventory-system/1.0, sentry_key=%s", u.User.Username()),
+		environment: environment,
+		client:      cloudHTTPClient,
+	}, nil
+}
+
+// ReportPanic envía el panic a Sentry con el request ID y la ruta como tags
+func (r *SentryReporter) ReportPanic(ctx context.Context, report *domain.PanicReport) error {
+	event := map[string]interface{}{
+		"event_id":    strings.ReplaceAll(uuid.New().String(), "-", ""),
+		"timestamp":   report.OccurredAt.UTC().Format(time.RFC3339),
+		"level":       "error",
+		"platform":    "go",
+		"logger":      "http.recovery",
+		"server_name": report.InstanceID,
+		"environment": r.environment,
+		"exception": map[string]interface{}{
+			"values": []map[string]interface{}{{"type": "panic", "value": report.Value}},
+		},
+		"request": map[string]interface{}{"method": report.Method, "url": report.Path},
+		"tags": map[string]string{
+			"request_id": report.RequestID,
+			"route":      report.Route,
+		},
+		"extra": map[string]string{"stack": report.Stack},
+	}
+
+	return postJSON(ctx, r.client, r.storeURL, event, map[string]string{"X-Sentry-Auth": r.auth})
+}
+
+// WebhookReporter implementa ErrorReporter enviando cada panic como JSON (domain.PanicReport)
+// a una URL: integración genérica con cualquier servicio que acepte webhooks
+type WebhookReporter struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookReporter crea un reporter que hace POST del panic a target
+func NewWebhookReporter(target string) *WebhookReporter {
+	return &WebhookReporter{
+		url:    target,
+		client: cloudHTTPClient,
+	}
+}
+
+// ReportPanic envía el panic al webhook
+func (r *WebhookReporter) ReportPanic(ctx context.Context, report *domain.PanicReport) error {
+	return postJSON(ctx, r.client, r.url, report, nil)
+}
+
+// postJSON hace POST de body como JSON y falla si la respuesta no es 2xx
+func postJSON(ctx context.Context, client *http.Client, target string, body interface{}, headers map[string]string) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &httpStatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(msg))}
+	}
+	return nil
+}
+
+// httpStatusError respuesta no 2xx de postJSON
+type httpStatusError struct {
+	StatusCode int
+	Body       string // Primeros 512 bytes de la respuesta
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("endpoint returned %d: %s", e.StatusCode, e.Body)
+}
@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// HTTPConfirmHook implementa ConfirmHook haciendo POST de la confirmación (domain.ReservationConfirmation)
+// a una URL. El ID de la reserva va en Idempotency-Key para que el receptor pueda ignorar los
+// reintentos y, con secreto, el cuerpo se firma con HMAC-SHA256 en X-Inventory-Signature.
+//
+// Una respuesta 4xx (salvo 408 y 429) rechaza la confirmación sin reintentar; el resto de
+// errores se reintentan según la política del hook.
+type HTTPConfirmHook struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewHTTPConfirmHook crea un hook que hace POST a target con el timeout indicado por intento
+func NewHTTPConfirmHook(target, secret string, timeout time.Duration) *HTTPConfirmHook {
+	return &HTTPConfirmHook{
+		url:    target,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name identifica el hook en logs y errores
+func (h *HTTPConfirmHook) Name() string {
+	return "http"
+}
+
+// OnConfirm envía la confirmación a la URL del hook
+func (h *HTTPConfirmHook) OnConfirm(ctx context.Context, confirmation *domain.ReservationConfirmation) error {
+	payload, err := json.Marshal(confirmation)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{"Idempotency-Key": confirmation.ReservationID}
+	if h.secret != "" {
+		mac := hmac.New(sha256.New, []byte(h.secret))
+		mac.Write(payload)
+		headers["X-Inventory-Signature"] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	err = postJSON(ctx, h.client, h.url, json.RawMessage(payload), headers)
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 &&
+		statusErr.StatusCode != http.StatusRequestTimeout && statusErr.StatusCode != http.StatusTooManyRequests {
+		return &domain.ConfirmRejectedError{Reason: statusErr.Error()}
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"inventory-system/internal/domain"
+)
+
+// LocalBlobStore implementa domain.BlobStore sobre un directorio local.
+// Los ficheros los sirve la propia API bajo publicBaseURL (router.Static).
+type LocalBlobStore struct {
+	dir           string
+	publicBaseURL string
+}
+
+// NewLocalBlobStore crea el store. El directorio se crea en la primera subida.
+func NewLocalBlobStore(dir, publicBaseURL string) *LocalBlobStore {
+	return &LocalBlobStore{
+		dir:           dir,
+		publicBaseURL: strings.TrimRight(publicBaseURL, "/"),
+	}
+}
+
+// Dir directorio raíz de los ficheros
+func (s *LocalBlobStore) Dir() string {
+	return s.dir
+}
+
+// path resuelve la clave dentro del directorio raíz, rechazando rutas que escapen de él
+func (s *LocalBlobStore) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
+
+// Put escribe el contenido en un fichero temporal y lo renombra al destino
+func (s *LocalBlobStore) Put(ctx context.Context, key, contentType string, content io.Reader, size int64) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create media directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create media file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write media file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write media file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to store media file: %w", err)
+	}
+
+	return s.publicBaseURL + "/" + strings.TrimLeft(key, "/"), nil
+}
+
+// Get abre el fichero
+func (s *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &domain.NotFoundError{Resource: "Blob", ID: key}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open media file: %w", err)
+	}
+	return file, nil
+}
+
+// Delete elimina el fichero
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete media file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// MeilisearchIndex implementa domain.SearchIndex sobre la API REST de Meilisearch.
+// Las escrituras son asíncronas en Meilisearch (devuelven una task): un documento
+// puede tardar unos milisegundos en aparecer en las búsquedas.
+type MeilisearchIndex struct {
+	baseURL       string
+	index         string
+	authorization string
+}
+
+// MeilisearchIndexConfig configuración para MeilisearchIndex
+type MeilisearchIndexConfig struct {
+	URL    string // "http://localhost:7700"
+	Index  string // UID del índice (default: "products")
+	APIKey string // Opcional: master key o API key con permisos sobre el índice
+}
+
+// NewMeilisearchIndex crea el cliente, verifica la conexión y crea el índice con "id"
+// como clave primaria (si ya existe, Meilisearch descarta la task sin cambios)
+func NewMeilisearchIndex(cfg MeilisearchIndexConfig) (*MeilisearchIndex, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("meilisearch URL is required")
+	}
+	if cfg.Index == "" {
+		cfg.Index = "products"
+	}
+
+	i := &MeilisearchIndex{
+		baseURL: strings.TrimRight(cfg.URL, "/"),
+		index:   cfg.Index,
+	}
+	if cfg.APIKey != "" {
+		i.authorization = "Bearer " + cfg.APIKey
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := searchRequest(ctx, http.MethodGet, i.baseURL+"/health", "", "", nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to connect to Meilisearch: %w", err)
+	}
+
+	body, _ := json.Marshal(map[string]string{"uid": cfg.Index, "primaryKey": "id"})
+	if _, err := searchRequest(ctx, http.MethodPost, i.baseURL+"/indexes", i.authorization, "application/json", body, nil); err != nil {
+		return nil, fmt.Errorf("failed to create Meilisearch index: %w", err)
+	}
+
+	log.Printf("✅ Using Meilisearch index %s at %s", cfg.Index, i.baseURL)
+
+	return i, nil
+}
+
+// Upsert añade o reemplaza los documentos
+func (i *MeilisearchIndex) Upsert(ctx context.Context, docs ...*domain.ProductSearchDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(docs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search documents: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/indexes/%s/documents?primaryKey=id", i.baseURL, url.PathEscape(i.index))
+	if _, err := searchRequest(ctx, http.MethodPost, endpoint, i.authorization, "application/json", body, nil); err != nil {
+		return fmt.Errorf("failed to index documents in Meilisearch: %w", err)
+	}
+	return nil
+}
+
+// Delete elimina el documento del producto (Meilisearch no falla si no existe)
+func (i *MeilisearchIndex) Delete(ctx context.Context, productID string) error {
+	endpoint := fmt.Sprintf("%s/indexes/%s/documents/%s", i.baseURL, url.PathEscape(i.index), url.PathEscape(productID))
+	if _, err := searchRequest(ctx, http.MethodDelete, endpoint, i.authorization, "", nil, nil); err != nil {
+		return fmt.Errorf("failed to delete document from Meilisearch: %w", err)
+	}
+	return nil
+}
+
+// Search retorna los IDs de los productos que coinciden con query, por relevancia
+func (i *MeilisearchIndex) Search(ctx context.Context, query string, limit, offset int) ([]string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"q":                    query,
+		"limit":                limit,
+		"offset":               offset,
+		"attributesToRetrieve": []string{"id"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Hits []struct {
+			ID string `json:"id"`
+		} `json:"hits"`
+	}
+	endpoint := fmt.Sprintf("%s/indexes/%s/search", i.baseURL, url.PathEscape(i.index))
+	if _, err := searchRequest(ctx, http.MethodPost, endpoint, i.authorization, "application/json", body, &result); err != nil {
+		return nil, fmt.Errorf("failed to search Meilisearch: %w", err)
+	}
+
+	ids := make([]string, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids, nil
+}
+
+// Close no mantiene conexiones propias (usa el cliente HTTP compartido)
+func (i *MeilisearchIndex) Close() error {
+	return nil
+}
@@ -0,0 +1,276 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Placeholders admitidos en NATSPublisherConfig.SubjectTemplate
+const (
+	NATSSubjectEventType     = "{event_type}"     // stock.updated (aporta sus propios tokens)
+	NATSSubjectStoreID       = "{store_id}"       // MAD-001
+	NATSSubjectAggregateType = "{aggregate_type}" // stock, reservation, product...
+)
+
+// DefaultNATSSubjectTemplate publica en inventory.<tienda>.<tipo de evento>,
+// p. ej. inventory.MAD-001.reservation.confirmed
+const DefaultNATSSubjectTemplate = "inventory." + NATSSubjectStoreID + "." + NATSSubjectEventType
+
+// NATSPublisher implementa EventPublisher usando NATS JetStream.
+// Cada evento se publica con jetstream.Publish y se considera publicado cuando
+// JetStream devuelve el PubAck. El ID del evento viaja en la cabecera Nats-Msg-Id,
+// de modo que los reintentos del worker de sincronización se deduplican en el stream.
+type NATSPublisher struct {
+	cfg NATSPublisherConfig
+	nc  *nats.Conn
+	js  jetstream.JetStream
+}
+
+// NATSPublisherConfig configuración para NATSPublisher
+type NATSPublisherConfig struct {
+	URL             string        // "nats://localhost:4222" (tls:// para TLS; admite user:pass@host)
+	Token           string        // Token de autenticación ("" para sin token)
+	StreamName      string        // Stream JetStream; si no existe se crea ("" = no gestionarlo)
+	SubjectTemplate string        // Ver DefaultNATSSubjectTemplate
+	AckTimeout      time.Duration // Espera máxima del PubAck (5s por defecto)
+}
+
+// NewNATSPublisher crea una nueva instancia de NATSPublisher, conecta con el servidor
+// y se asegura de que el stream existe.
+//
+// Ejemplo:
+//
+//	publisher, err := NewNATSPublisher(NATSPublisherConfig{
+//	    URL:        "nats://localhost:4222",
+//	    StreamName: "INVENTORY",
+//	})
+//	defer publisher.Close()
+func NewNATSPublisher(cfg NATSPublisherConfig) (*NATSPublisher, error) {
+	// Valores por defecto
+	if cfg.URL == "" {
+		cfg.URL = "nats://localhost:4222"
+	}
+	if cfg.SubjectTemplate == "" {
+		cfg.SubjectTemplate = DefaultNATSSubjectTemplate
+	}
+	if cfg.AckTimeout == 0 {
+		cfg.AckTimeout = 5 * time.Second
+	}
+
+	options := []nats.Option{
+		nats.Name("inventory-system"),
+		nats.MaxReconnects(-1), // El worker de sincronización reintenta mientras tanto
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Printf("Warning: NATS connection lost: %v", err)
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			log.Printf("✅ Reconnected to NATS at %s", redactURL(nc.ConnectedUrl()))
+		}),
+	}
+	if cfg.Token != "" {
+		options = append(options, nats.Token(cfg.Token))
+	}
+
+	nc, err := nats.Connect(cfg.URL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(nc, jetstream.WithPublishAsyncTimeout(cfg.AckTimeout))
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	p := &NATSPublisher{cfg: cfg, nc: nc, js: js}
+
+	if cfg.StreamName != "" {
+		if err := p.ensureStream(); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+
+	log.Printf("✅ Connected to NATS at %s (stream: %s, subjects: %s)", redactURL(cfg.URL), cfg.StreamName, cfg.SubjectTemplate)
+
+	return p, nil
+}
+
+// Subject construye el subject de un evento a partir de SubjectTemplate
+func (p *NATSPublisher) Subject(event *domain.Event) string {
+	return strings.NewReplacer(
+		NATSSubjectEventType, natsTokens(event.EventType),
+		NATSSubjectStoreID, natsToken(event.StoreID),
+		NATSSubjectAggregateType, natsToken(event.AggregateType),
+	).Replace(p.cfg.SubjectTemplate)
+}
+
+// StreamSubjects devuelve el filtro de subjects del stream: el prefijo literal de la plantilla
+// seguido de ">", p. ej. "inventory.>"
+func (p *NATSPublisher) StreamSubjects() string {
+	prefix := p.cfg.SubjectTemplate
+	if i := strings.Index(prefix, "{"); i >= 0 {
+		prefix = prefix[:i]
+	}
+	prefix = strings.TrimSuffix(prefix, ".")
+	if prefix == "" {
+		return ">"
+	}
+	return prefix + ".>"
+}
+
+// Publish publica un evento en JetStream y espera su PubAck.
+//
+// El mensaje es el JSON completo del evento (igual que el campo payload de Redis)
+// y lleva la cabecera Nats-Msg-Id con el ID del evento.
+func (p *NATSPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	domain.AttachCorrelationID(ctx, event)
+
+	// Serializar evento completo a JSON
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.AckTimeout)
+	defer cancel()
+
+	subject := p.Subject(event)
+	ack, err := p.js.Publish(ctx, subject, eventJSON, jetstream.WithMsgID(event.ID))
+	if err != nil {
+		return fmt.Errorf("failed to publish event to NATS (subject %s): %w", subject, err)
+	}
+
+	log.Printf("📤 Event published to NATS: subject=%s, stream=%s, seq=%d, id=%s",
+		subject, ack.Stream, ack.Sequence, event.ID)
+
+	return nil
+}
+
+// PublishBatch envía todos los eventos sin esperar y después recoge los PubAck,
+// lo que evita un round-trip por evento.
+func (p *NATSPublisher) PublishBatch(ctx context.Context, events []*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	type inFlight struct {
+		event  *domain.Event
+		future jetstream.PubAckFuture
+	}
+	sent := make([]inFlight, 0, len(events))
+
+	var errs []error
+	for _, event := range events {
+		domain.AttachCorrelationID(ctx, event)
+
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to marshal event %s: %w", event.ID, err))
+			continue
+		}
+
+		future, err := p.js.PublishAsync(p.Subject(event), eventJSON, jetstream.WithMsgID(event.ID))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("event %s: %w", event.ID, err))
+			continue
+		}
+		sent = append(sent, inFlight{event: event, future: future})
+	}
+
+	for _, msg := range sent {
+		select {
+		case <-msg.future.Ok():
+		case err := <-msg.future.Err():
+			errs = append(errs, fmt.Errorf("event %s: %w", msg.event.ID, err))
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("event %s: %w", msg.event.ID, ctx.Err()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to publish batch to NATS: %w", errors.Join(errs...))
+	}
+
+	log.Printf("📤 Batch published to NATS: %d events", len(events))
+
+	return nil
+}
+
+// Close espera los PubAck pendientes (hasta AckTimeout) y cierra la conexión a NATS
+func (p *NATSPublisher) Close() error {
+	log.Printf("🔌 Closing NATS connection")
+	select {
+	case <-p.js.PublishAsyncComplete():
+	case <-time.After(p.cfg.AckTimeout):
+	}
+	p.nc.Close()
+	return nil
+}
+
+// ensureStream crea el stream si no existe. Si ya existe con otra configuración se deja como está.
+func (p *NATSPublisher) ensureStream() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.AckTimeout)
+	defer cancel()
+
+	_, err := p.js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     p.cfg.StreamName,
+		Subjects: []string{p.StreamSubjects()},
+		Storage:  jetstream.FileStorage,
+	})
+	if errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+		log.Printf("Warning: NATS stream %s already exists with a different configuration, leaving it unchanged", p.cfg.StreamName)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create NATS stream %s: %w", p.cfg.StreamName, err)
+	}
+
+	return nil
+}
+
+// natsToken convierte un valor en un único token de subject (sin separadores ni comodines)
+func natsToken(value string) string {
+	if value == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+		return r
+	}, value)
+}
+
+// natsTokens conserva los puntos del tipo de evento para que los consumidores
+// puedan filtrar por jerarquía (inventory.*.reservation.>)
+func natsTokens(value string) string {
+	parts := strings.Split(value, ".")
+	for i, part := range parts {
+		parts[i] = natsToken(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// redactURL oculta la contraseña de la URL en los logs
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,85 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// NotificationPublisher decora un EventPublisher y pasa los eventos de reserva con aviso al
+// cliente (creada, caducada, confirmada) al servicio de notificaciones. Igual que
+// SearchIndexPublisher los procesa en una goroutine para no añadir la latencia del servidor
+// SMTP o del proveedor de SMS a las reservas; si la cola se llena el evento se descarta y el
+// cliente no recibe ese aviso.
+//
+// Puede envolver los re-intentos del outbox: cada aviso se reclama en la BD antes de enviarse,
+// así que repetir un evento no duplica avisos.
+type NotificationPublisher struct {
+	inner  domain.EventPublisher
+	handle func(ctx context.Context, event *domain.Event) error
+	queue  chan *domain.Event
+	done   chan struct{}
+}
+
+// NewNotificationPublisher crea el decorador y arranca su worker
+func NewNotificationPublisher(inner domain.EventPublisher, handle func(ctx context.Context, event *domain.Event) error) *NotificationPublisher {
+	p := &NotificationPublisher{
+		inner:  inner,
+		handle: handle,
+		queue:  make(chan *domain.Event, 1024),
+		done:   make(chan struct{}),
+	}
+
+	go p.run()
+
+	return p
+}
+
+// Publish delega en el publisher interno y encola el evento para las notificaciones
+func (p *NotificationPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	p.enqueue(event)
+	return p.inner.Publish(ctx, event)
+}
+
+// PublishBatch delega en el publisher interno y encola los eventos para las notificaciones
+func (p *NotificationPublisher) PublishBatch(ctx context.Context, events []*domain.Event) error {
+	for _, event := range events {
+		p.enqueue(event)
+	}
+	return p.inner.PublishBatch(ctx, events)
+}
+
+// Close detiene el worker y cierra el publisher interno
+func (p *NotificationPublisher) Close() error {
+	close(p.done)
+	return p.inner.Close()
+}
+
+func (p *NotificationPublisher) enqueue(event *domain.Event) {
+	if _, ok := domain.NotificationKindForEvent(event.EventType); !ok {
+		return
+	}
+
+	select {
+	case p.queue <- event:
+	default:
+		log.Printf("⚠️  Notification queue full, dropping event %s", event.ID)
+	}
+}
+
+func (p *NotificationPublisher) run() {
+	for {
+		select {
+		case <-p.done:
+			return
+		case event := <-p.queue:
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err := p.handle(ctx, event); err != nil {
+				log.Printf("⚠️  Failed to notify event %s (%s): %v", event.ID, event.EventType, err)
+			}
+			cancel()
+		}
+	}
+}
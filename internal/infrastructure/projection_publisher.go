@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+
+	"inventory-system/internal/domain"
+)
+
+// ProjectionPublisher decora un EventPublisher y aplica cada evento a un read model antes de
+// delegar la publicación. A diferencia de SearchIndexPublisher lo hace de forma síncrona: cuando
+// el servicio responde, las lecturas sobre el read model ya reflejan el cambio.
+//
+// Los errores solo se registran: el cambio ya está confirmado en la BD y el read model se puede
+// reconstruir. Puede envolver los re-intentos del outbox si la proyección es idempotente.
+type ProjectionPublisher struct {
+	inner   domain.EventPublisher
+	name    string
+	project func(ctx context.Context, event *domain.Event) error
+}
+
+// NewProjectionPublisher crea el decorador. name identifica el read model en los logs.
+func NewProjectionPublisher(inner domain.EventPublisher, name string, project func(ctx context.Context, event *domain.Event) error) *ProjectionPublisher {
+	return &ProjectionPublisher{
+		inner:   inner,
+		name:    name,
+		project: project,
+	}
+}
+
+// Publish proyecta el evento y delega en el publisher interno
+func (p *ProjectionPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	p.apply(ctx, event)
+	return p.inner.Publish(ctx, event)
+}
+
+// PublishBatch proyecta cada evento y delega en el publisher interno
+func (p *ProjectionPublisher) PublishBatch(ctx context.Context, events []*domain.Event) error {
+	for _, event := range events {
+		p.apply(ctx, event)
+	}
+	return p.inner.PublishBatch(ctx, events)
+}
+
+// Close cierra el publisher interno
+func (p *ProjectionPublisher) Close() error {
+	return p.inner.Close()
+}
+
+func (p *ProjectionPublisher) apply(ctx context.Context, event *domain.Event) {
+	if err := p.project(ctx, event); err != nil {
+		log.Printf("⚠️  Failed to project event %s (%s) into %s: %v", event.ID, event.EventType, p.name, err)
+	}
+}
@@ -0,0 +1,181 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"inventory-system/internal/domain"
+
+	"cloud.google.com/go/pubsub"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// PubSubPublisher implementa EventPublisher usando Google Cloud Pub/Sub.
+//
+// Cada mensaje lleva el evento completo en data, los atributos event_type, store_id,
+// aggregate_type, aggregate_id y correlation_id, y aggregate_id como ordering key: con una
+// suscripción con ordenación habilitada los eventos de un mismo producto o reserva se
+// entregan en orden. El cliente agrupa los mensajes en lotes; Close los envía antes de cerrar.
+type PubSubPublisher struct {
+	client   *pubsub.Client
+	topic    *pubsub.Topic
+	inflight inflightGroup
+}
+
+// PubSubPublisherConfig configuración para PubSubPublisher
+type PubSubPublisherConfig struct {
+	ProjectID       string // Proyecto de GCP
+	Topic           string // Topic (ej: "inventory-events")
+	CredentialsFile string // JSON de cuenta de servicio; vacío = credenciales por defecto (servidor de metadatos en GCE/GKE/Cloud Run)
+	Endpoint        string // Opcional: endpoint regional (necesario para ordenación estricta)
+	EmulatorHost    string // Opcional: "localhost:8085" (sin autenticación)
+}
+
+// NewPubSubPublisher crea una nueva instancia de PubSubPublisher.
+//
+// Ejemplo:
+//
+//	publisher, err := NewPubSubPublisher(PubSubPublisherConfig{
+//	    ProjectID: "my-project",
+//	    Topic:     "inventory-events",
+//	})
+//	defer publisher.Close()
+func NewPubSubPublisher(cfg PubSubPublisherConfig) (*PubSubPublisher, error) {
+	if cfg.ProjectID == "" || cfg.Topic == "" {
+		return nil, errors.New("project ID and topic are required")
+	}
+
+	var options []option.ClientOption
+	switch {
+	case cfg.EmulatorHost != "":
+		options = append(options,
+			option.WithEndpoint(cfg.EmulatorHost),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		)
+	default:
+		if cfg.CredentialsFile != "" {
+			options = append(options, option.WithCredentialsFile(cfg.CredentialsFile))
+		}
+		if cfg.Endpoint != "" {
+			options = append(options, option.WithEndpoint(pubSubEndpoint(cfg.Endpoint)))
+		}
+	}
+
+	client, err := pubsub.NewClient(context.Background(), cfg.ProjectID, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Pub/Sub client: %w", err)
+	}
+
+	topic := client.Topic(cfg.Topic)
+	topic.EnableMessageOrdering = true
+
+	log.Printf("✅ Using Google Cloud Pub/Sub topic projects/%s/topics/%s", cfg.ProjectID, cfg.Topic)
+
+	return &PubSubPublisher{client: client, topic: topic}, nil
+}
+
+// Publish publica un evento en el topic y espera a que Pub/Sub lo confirme
+func (p *PubSubPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	if err := p.inflight.begin(); err != nil {
+		return err
+	}
+	defer p.inflight.done()
+
+	message, err := newPubSubMessage(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	id, err := p.topic.Publish(ctx, message).Get(ctx)
+	if err != nil {
+		p.topic.ResumePublish(message.OrderingKey)
+		return fmt.Errorf("failed to publish event to Pub/Sub: %w", err)
+	}
+
+	log.Printf("📤 Event published to Pub/Sub: type=%s, store=%s, id=%s, message_id=%s",
+		event.EventType, event.StoreID, event.ID, id)
+
+	return nil
+}
+
+// PublishBatch encola todos los eventos en el cliente (que los agrupa en lotes) y después
+// espera las confirmaciones
+func (p *PubSubPublisher) PublishBatch(ctx context.Context, events []*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := p.inflight.begin(); err != nil {
+		return err
+	}
+	defer p.inflight.done()
+
+	results := make([]*pubsub.PublishResult, 0, len(events))
+	for _, event := range events {
+		message, err := newPubSubMessage(ctx, event)
+		if err != nil {
+			return err
+		}
+		results = append(results, p.topic.Publish(ctx, message))
+	}
+
+	var errs []error
+	for i, result := range results {
+		if _, err := result.Get(ctx); err != nil {
+			// Con ordenación, un fallo pausa la ordering key hasta reanudarla: el worker
+			// de sincronización reintenta el evento
+			p.topic.ResumePublish(events[i].AggregateID)
+			errs = append(errs, fmt.Errorf("event %s: %w", events[i].ID, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to publish batch to Pub/Sub: %w", errors.Join(errs...))
+	}
+
+	log.Printf("📤 Batch published to Pub/Sub: %d events", len(events))
+
+	return nil
+}
+
+// Close rechaza nuevas publicaciones, envía los mensajes pendientes y cierra el cliente
+func (p *PubSubPublisher) Close() error {
+	log.Printf("🔌 Flushing Pub/Sub publisher")
+	if !p.inflight.close(cloudCloseTimeout) {
+		return errors.New("timeout waiting for in-flight Pub/Sub publishes")
+	}
+	p.topic.Stop()
+	return p.client.Close()
+}
+
+func newPubSubMessage(ctx context.Context, event *domain.Event) (*pubsub.Message, error) {
+	domain.AttachCorrelationID(ctx, event)
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event %s: %w", event.ID, err)
+	}
+
+	return &pubsub.Message{
+		Data:        eventJSON,
+		Attributes:  eventAttributes(event),
+		OrderingKey: event.AggregateID,
+	}, nil
+}
+
+// pubSubEndpoint convierte PUBSUB_ENDPOINT (p. ej. https://europe-west1-pubsub.googleapis.com)
+// en el host:puerto gRPC que espera el cliente
+func pubSubEndpoint(endpoint string) string {
+	host := strings.TrimSuffix(strings.TrimPrefix(endpoint, "https://"), "/")
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "443")
+	}
+	return host
+}
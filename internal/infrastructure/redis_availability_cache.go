@@ -0,0 +1,125 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// adjustIfExists aplica DECRBY/INCRBY solo si la clave existe. Un contador sin semilla
+// no se crea desde un delta: el siguiente Get será un miss y se leerá la BD.
+var adjustIfExists = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call(ARGV[1], KEYS[1], ARGV[2])
+end
+return false
+`)
+
+// RedisAvailabilityCache implementa domain.AvailabilityCache con contadores atómicos en Redis.
+// Cada entrada expira tras el TTL configurado, lo que acota el tiempo que un contador
+// desviado (p. ej. un evento perdido) puede sobrevivir antes de releerse de la BD.
+type RedisAvailabilityCache struct {
+	client    *redis.Client
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// RedisAvailabilityCacheConfig configuración para RedisAvailabilityCache
+type RedisAvailabilityCacheConfig struct {
+	Addr      string        // "localhost:6379"
+	Password  string        // "" para sin password
+	DB        int           // 0 por defecto
+	KeyPrefix string        // Prefijo de las claves (default: "inventory:availability")
+	TTL       time.Duration // Vida de cada contador (default: 5 minutos)
+}
+
+// NewRedisAvailabilityCache crea el cache y verifica la conexión
+func NewRedisAvailabilityCache(cfg RedisAvailabilityCacheConfig) (*RedisAvailabilityCache, error) {
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = "inventory:availability"
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 5 * time.Minute
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	log.Printf("✅ Availability cache connected to Redis at %s (ttl: %s)", cfg.Addr, cfg.TTL)
+
+	return &RedisAvailabilityCache{
+		client:    client,
+		keyPrefix: cfg.KeyPrefix,
+		ttl:       cfg.TTL,
+	}, nil
+}
+
+func (c *RedisAvailabilityCache) key(productID, storeID string) string {
+	return c.keyPrefix + ":" + productID + ":" + storeID
+}
+
+// Get retorna la disponibilidad cacheada (ok = false si no hay entrada)
+func (c *RedisAvailabilityCache) Get(ctx context.Context, productID, storeID string) (int, bool, error) {
+	available, err := c.client.Get(ctx, c.key(productID, storeID)).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read availability cache: %w", err)
+	}
+	return available, true, nil
+}
+
+// Set guarda la disponibilidad leída de la BD con el TTL configurado
+func (c *RedisAvailabilityCache) Set(ctx context.Context, productID, storeID string, available int) error {
+	if err := c.client.Set(ctx, c.key(productID, storeID), available, c.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to write availability cache: %w", err)
+	}
+	return nil
+}
+
+// Reserve descuenta quantity con DECRBY si la entrada existe
+func (c *RedisAvailabilityCache) Reserve(ctx context.Context, productID, storeID string, quantity int) error {
+	return c.adjust(ctx, "DECRBY", productID, storeID, quantity)
+}
+
+// Release devuelve quantity con INCRBY si la entrada existe
+func (c *RedisAvailabilityCache) Release(ctx context.Context, productID, storeID string, quantity int) error {
+	return c.adjust(ctx, "INCRBY", productID, storeID, quantity)
+}
+
+func (c *RedisAvailabilityCache) adjust(ctx context.Context, command, productID, storeID string, quantity int) error {
+	err := adjustIfExists.Run(ctx, c.client, []string{c.key(productID, storeID)}, command, quantity).Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to %s availability cache: %w", command, err)
+	}
+	return nil
+}
+
+// Invalidate elimina la entrada
+func (c *RedisAvailabilityCache) Invalidate(ctx context.Context, productID, storeID string) error {
+	if err := c.client.Del(ctx, c.key(productID, storeID)).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate availability cache: %w", err)
+	}
+	return nil
+}
+
+// Close cierra la conexión a Redis
+func (c *RedisAvailabilityCache) Close() error {
+	return c.client.Close()
+}
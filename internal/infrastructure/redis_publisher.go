@@ -82,6 +82,8 @@ func NewRedisPublisher(cfg RedisPublisherConfig) (*RedisPublisher, error) {
 //   - payload: JSON completo del evento
 //   - timestamp: Unix timestamp
 func (p *RedisPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	domain.AttachCorrelationID(ctx, event)
+
 	// Serializar evento completo a JSON
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
@@ -94,12 +96,13 @@ func (p *RedisPublisher) Publish(ctx context.Context, event *domain.Event) error
 		MaxLen: p.maxLen,
 		Approx: true, // ~MaxLen (más eficiente que exacto)
 		Values: map[string]interface{}{
-			"id":           event.ID,
-			"event_type":   event.EventType,
-			"store_id":     event.StoreID,
-			"aggregate_id": event.AggregateID,
-			"payload":      string(eventJSON),
-			"timestamp":    event.CreatedAt.Unix(),
+			"id":             event.ID,
+			"event_type":     event.EventType,
+			"store_id":       event.StoreID,
+			"aggregate_id":   event.AggregateID,
+			"correlation_id": event.CorrelationID,
+			"payload":        string(eventJSON),
+			"timestamp":      event.CreatedAt.Unix(),
 		},
 	}
 
@@ -124,6 +127,8 @@ func (p *RedisPublisher) PublishBatch(ctx context.Context, events []*domain.Even
 	pipe := p.client.Pipeline()
 
 	for _, event := range events {
+		domain.AttachCorrelationID(ctx, event)
+
 		eventJSON, err := json.Marshal(event)
 		if err != nil {
 			return fmt.Errorf("failed to marshal event %s: %w", event.ID, err)
@@ -134,12 +139,13 @@ func (p *RedisPublisher) PublishBatch(ctx context.Context, events []*domain.Even
 			MaxLen: p.maxLen,
 			Approx: true,
 			Values: map[string]interface{}{
-				"id":           event.ID,
-				"event_type":   event.EventType,
-				"store_id":     event.StoreID,
-				"aggregate_id": event.AggregateID,
-				"payload":      string(eventJSON),
-				"timestamp":    event.CreatedAt.Unix(),
+				"id":             event.ID,
+				"event_type":     event.EventType,
+				"store_id":       event.StoreID,
+				"aggregate_id":   event.AggregateID,
+				"correlation_id": event.CorrelationID,
+				"payload":        string(eventJSON),
+				"timestamp":      event.CreatedAt.Unix(),
 			},
 		})
 	}
@@ -154,6 +160,11 @@ func (p *RedisPublisher) PublishBatch(ctx context.Context, events []*domain.Even
 	return nil
 }
 
+// Ping comprueba la conexión con Redis (usado por el circuit breaker para sondear el broker).
+func (p *RedisPublisher) Ping(ctx context.Context) error {
+	return p.client.Ping(ctx).Err()
+}
+
 // Close cierra la conexión a Redis.
 func (p *RedisPublisher) Close() error {
 	if p.client != nil {
@@ -162,3 +173,23 @@ func (p *RedisPublisher) Close() error {
 	}
 	return nil
 }
+
+// DecodeRedisStreamMessage reconstruye el evento de un mensaje del stream (campo payload).
+// Pensado para consumidores con XREADGROUP, junto con service.IdempotentConsumer: el ID
+// del evento (no el ID del mensaje del stream) es la clave de idempotencia.
+func DecodeRedisStreamMessage(msg redis.XMessage) (*domain.Event, error) {
+	payload, ok := msg.Values["payload"].(string)
+	if !ok {
+		return nil, fmt.Errorf("stream message %s has no payload", msg.ID)
+	}
+
+	var event domain.Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stream message %s: %w", msg.ID, err)
+	}
+	if event.ID == "" {
+		return nil, fmt.Errorf("stream message %s has no event ID", msg.ID)
+	}
+
+	return &event, nil
+}
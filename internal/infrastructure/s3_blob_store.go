@@ -0,0 +1,135 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+
+	"inventory-system/internal/domain"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3BlobStore implementa domain.BlobStore sobre un bucket S3 con el SDK de AWS. Con un endpoint
+// propio (MinIO, LocalStack) usa direccionamiento path-style (<endpoint>/<bucket>/<key>).
+type S3BlobStore struct {
+	client        *s3.Client
+	bucket        string
+	publicBaseURL string
+}
+
+// S3BlobStoreConfig configuración para S3BlobStore
+type S3BlobStoreConfig struct {
+	AWSCredentialsConfig // Sin access key = cadena de credenciales del SDK (perfil, rol de la tarea/instancia)
+	Bucket               string
+	Endpoint             string // Opcional (MinIO, LocalStack). Por defecto el endpoint regional
+	UsePathStyle         bool   // Bucket en la ruta en lugar del host (necesario con MinIO)
+	PublicBaseURL        string // Opcional (CDN). Por defecto la URL del bucket
+}
+
+// NewS3BlobStore crea una nueva instancia de S3BlobStore.
+//
+// Ejemplo (MinIO):
+//
+//	store, err := NewS3BlobStore(S3BlobStoreConfig{
+//	    AWSCredentialsConfig: AWSCredentialsConfig{Region: "us-east-1"},
+//	    Bucket:               "inventory-media",
+//	    Endpoint:             "http://localhost:9000",
+//	    UsePathStyle:         true,
+//	})
+func NewS3BlobStore(cfg S3BlobStoreConfig) (*S3BlobStore, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("S3 bucket is required")
+	}
+
+	awsCfg, err := loadAWSConfig(context.Background(), cfg.AWSCredentialsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.PublicBaseURL == "" {
+		switch {
+		case cfg.Endpoint != "" && cfg.UsePathStyle:
+			cfg.PublicBaseURL = strings.TrimRight(cfg.Endpoint, "/") + "/" + cfg.Bucket
+		case cfg.Endpoint != "":
+			u, err := url.Parse(cfg.Endpoint)
+			if err != nil || u.Host == "" {
+				return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+			}
+			cfg.PublicBaseURL = u.Scheme + "://" + cfg.Bucket + "." + u.Host
+		default:
+			cfg.PublicBaseURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, awsCfg.Region)
+		}
+	}
+
+	log.Printf("✅ Using S3 bucket %s for product media", cfg.Bucket)
+
+	return &S3BlobStore{
+		client: s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+			if cfg.Endpoint != "" {
+				o.BaseEndpoint = aws.String(cfg.Endpoint)
+			}
+			o.UsePathStyle = cfg.UsePathStyle
+		}),
+		bucket:        cfg.Bucket,
+		publicBaseURL: strings.TrimRight(cfg.PublicBaseURL, "/"),
+	}, nil
+}
+
+// Put sube el objeto. El contenido se lee completo en memoria (acotado por el límite de subida
+// del handler): el SDK necesita un cuerpo con Seek para firmarlo en endpoints sin TLS (MinIO).
+func (s *S3BlobStore) Put(ctx context.Context, key, contentType string, content io.Reader, size int64) (string, error) {
+	body, err := io.ReadAll(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to read media content: %w", err)
+	}
+
+	key = strings.TrimLeft(key, "/")
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(body),
+		ContentLength: aws.Int64(int64(len(body))),
+		ContentType:   aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload media to S3: %w", err)
+	}
+
+	return s.publicBaseURL + "/" + key, nil
+}
+
+// Get descarga el objeto con una petición firmada (no requiere que el bucket sea público)
+func (s *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(strings.TrimLeft(key, "/")),
+	})
+	if err != nil {
+		var noSuchKey *s3types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, &domain.NotFoundError{Resource: "Blob", ID: key}
+		}
+		return nil, fmt.Errorf("failed to download object from S3: %w", err)
+	}
+	return output.Body, nil
+}
+
+// Delete elimina el objeto (S3 responde 204 aunque no exista)
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(strings.TrimLeft(key, "/")),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete media from S3: %w", err)
+	}
+	return nil
+}
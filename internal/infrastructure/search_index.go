@@ -0,0 +1,65 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Código compartido por los índices de búsqueda externos (Elasticsearch, Meilisearch),
+// que se usan a través de su API REST para no depender de los clientes oficiales.
+
+// searchHTTPClient cliente HTTP de los índices de búsqueda
+var searchHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// searchRequest ejecuta una llamada JSON contra el backend de búsqueda. Los estados de
+// allowed (además de 2xx) no son error; result puede ser nil. Retorna el código HTTP.
+func searchRequest(
+	ctx context.Context,
+	method, url, authorization, contentType string,
+	body []byte,
+	result interface{},
+	allowed ...int,
+) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := searchHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+
+	ok := resp.StatusCode >= 200 && resp.StatusCode < 300
+	for _, status := range allowed {
+		ok = ok || resp.StatusCode == status
+	}
+	if !ok {
+		return resp.StatusCode, fmt.Errorf("%s %s returned %d: %s", method, url, resp.StatusCode, bytes.TrimSpace(data))
+	}
+
+	if result != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 && len(data) > 0 {
+		if err := json.Unmarshal(data, result); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode search response: %w", err)
+		}
+	}
+
+	return resp.StatusCode, nil
+}
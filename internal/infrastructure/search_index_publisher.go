@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// searchIndexEventPrefixes eventos que cambian datos o disponibilidad de un producto
+var searchIndexEventPrefixes = []string{"product.", "stock.", "reservation.", "transfer."}
+
+// SearchIndexPublisher decora un EventPublisher y pasa los eventos de catálogo y de stock al
+// indexador de búsqueda. El indexado se hace en una goroutine para no añadir la latencia del
+// backend de búsqueda a los servicios; si la cola se llena el evento se descarta y el índice
+// queda desfasado hasta el siguiente cambio del producto o un reindexado completo.
+//
+// A diferencia de AvailabilityCachePublisher puede envolver también los re-intentos del
+// outbox: el handler reconstruye el documento desde la BD, así que repetirlo es inocuo.
+type SearchIndexPublisher struct {
+	inner  domain.EventPublisher
+	handle func(ctx context.Context, event *domain.Event) error
+	queue  chan *domain.Event
+	done   chan struct{}
+}
+
+// NewSearchIndexPublisher crea el decorador y arranca su worker
+func NewSearchIndexPublisher(inner domain.EventPublisher, handle func(ctx context.Context, event *domain.Event) error) *SearchIndexPublisher {
+	p := &SearchIndexPublisher{
+		inner:  inner,
+		handle: handle,
+		queue:  make(chan *domain.Event, 1024),
+		done:   make(chan struct{}),
+	}
+
+	go p.run()
+
+	return p
+}
+
+// Publish delega en el publisher interno y encola el evento para el indexador
+func (p *SearchIndexPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	p.enqueue(event)
+	return p.inner.Publish(ctx, event)
+}
+
+// PublishBatch delega en el publisher interno y encola los eventos para el indexador
+func (p *SearchIndexPublisher) PublishBatch(ctx context.Context, events []*domain.Event) error {
+	for _, event := range events {
+		p.enqueue(event)
+	}
+	return p.inner.PublishBatch(ctx, events)
+}
+
+// Close detiene el worker y cierra el publisher interno
+func (p *SearchIndexPublisher) Close() error {
+	close(p.done)
+	return p.inner.Close()
+}
+
+func (p *SearchIndexPublisher) enqueue(event *domain.Event) {
+	relevant := false
+	for _, prefix := range searchIndexEventPrefixes {
+		relevant = relevant || strings.HasPrefix(event.EventType, prefix)
+	}
+	if !relevant {
+		return
+	}
+
+	select {
+	case p.queue <- event:
+	default:
+		log.Printf("⚠️  Search index queue full, dropping event %s", event.ID)
+	}
+}
+
+func (p *SearchIndexPublisher) run() {
+	for {
+		select {
+		case <-p.done:
+			return
+		case event := <-p.queue:
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			if err := p.handle(ctx, event); err != nil {
+				log.Printf("⚠️  Failed to index event %s (%s): %v", event.ID, event.EventType, err)
+			}
+			cancel()
+		}
+	}
+}
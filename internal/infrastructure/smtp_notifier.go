@@ -0,0 +1,127 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// SMTPConfig servidor SMTP de los avisos por email
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Vacío = sin autenticación
+	Password string
+	From     string // Remitente por defecto (las tiendas pueden usar otro)
+	Timeout  time.Duration
+}
+
+// SMTPNotifier implementa Notifier para EMAIL enviando cada aviso como un mensaje de texto
+// plano UTF-8. Usa STARTTLS si el servidor lo anuncia (obligatorio para autenticarse fuera de
+// localhost) y el ID del aviso como Message-ID, de modo que los reenvíos se pueden detectar.
+type SMTPNotifier struct {
+	config SMTPConfig
+}
+
+// NewSMTPNotifier crea el notifier de email
+func NewSMTPNotifier(config SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{config: config}
+}
+
+// Channel canal que atiende el notifier
+func (n *SMTPNotifier) Channel() domain.NotificationChannel {
+	return domain.NotificationChannelEmail
+}
+
+// Send envía el aviso por SMTP
+func (n *SMTPNotifier) Send(ctx context.Context, notification *domain.Notification) error {
+	from := notification.From
+	if from == "" {
+		from = n.config.From
+	}
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	message := n.message(sender, notification)
+
+	ctx, cancel := context.WithTimeout(ctx, n.config.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.config.Host}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+		}
+	}
+	if n.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(sender.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM rejected: %w", err)
+	}
+	if err := client.Rcpt(notification.To); err != nil {
+		return fmt.Errorf("SMTP RCPT TO rejected: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA rejected: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to write SMTP message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP message rejected: %w", err)
+	}
+	return client.Quit()
+}
+
+// message compone las cabeceras y el cuerpo del email
+func (n *SMTPNotifier) message(sender *mail.Address, notification *domain.Notification) []byte {
+	var msg bytes.Buffer
+	headers := [][2]string{
+		{"From", sender.String()},
+		{"To", notification.To},
+		{"Subject", mime.QEncoding.Encode("utf-8", notification.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", "<" + notification.ID + "@" + n.config.Host + ">"},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `text/plain; charset="utf-8"`},
+		{"Content-Transfer-Encoding", "8bit"},
+	}
+	for _, header := range headers {
+		msg.WriteString(header[0] + ": " + header[1] + "\r\n")
+	}
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(notification.Body, "\r\n", "\n"), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes()
+}
@@ -0,0 +1,197 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"inventory-system/internal/domain"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+// snsMaxBatch máximo de entradas por llamada a PublishBatch
+const snsMaxBatch = 10
+
+// SNSPublisher implementa EventPublisher usando AWS SNS.
+//
+// Los eventos se publican en un topic al que se suscriben colas SQS (fan-out). Cada mensaje
+// lleva los atributos event_type, store_id, aggregate_type, aggregate_id y correlation_id,
+// utilizables en filter policies. Con un topic FIFO (.fifo) se usa aggregate_id como
+// MessageGroupId (orden por producto/reserva) y el ID del evento como MessageDeduplicationId.
+type SNSPublisher struct {
+	client   *sns.Client
+	topicARN string
+	fifo     bool
+	inflight inflightGroup
+}
+
+// SNSPublisherConfig configuración para SNSPublisher
+type SNSPublisherConfig struct {
+	AWSCredentialsConfig        // Region vacía = región del ARN
+	TopicARN             string // arn:aws:sns:eu-west-1:123456789012:inventory-events(.fifo)
+	Endpoint             string // Opcional (LocalStack, VPC endpoint). Por defecto el endpoint regional
+}
+
+// NewSNSPublisher crea una nueva instancia de SNSPublisher.
+//
+// Ejemplo:
+//
+//	publisher, err := NewSNSPublisher(SNSPublisherConfig{
+//	    TopicARN: "arn:aws:sns:eu-west-1:123456789012:inventory-events.fifo",
+//	})
+//	defer publisher.Close()
+func NewSNSPublisher(cfg SNSPublisherConfig) (*SNSPublisher, error) {
+	// arn:aws:sns:<region>:<account>:<topic>
+	parts := strings.Split(cfg.TopicARN, ":")
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "sns" {
+		return nil, fmt.Errorf("invalid SNS topic ARN %q", cfg.TopicARN)
+	}
+	if cfg.Region == "" {
+		cfg.Region = parts[3]
+	}
+
+	awsCfg, err := loadAWSConfig(context.Background(), cfg.AWSCredentialsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &SNSPublisher{
+		client: sns.NewFromConfig(awsCfg, func(o *sns.Options) {
+			if cfg.Endpoint != "" {
+				o.BaseEndpoint = aws.String(cfg.Endpoint)
+			}
+		}),
+		topicARN: cfg.TopicARN,
+		fifo:     strings.HasSuffix(cfg.TopicARN, ".fifo"),
+	}
+
+	log.Printf("✅ Using AWS SNS topic %s (fifo: %t)", cfg.TopicARN, p.fifo)
+
+	return p, nil
+}
+
+// Publish publica un evento en el topic
+func (p *SNSPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	if err := p.inflight.begin(); err != nil {
+		return err
+	}
+	defer p.inflight.done()
+
+	message, err := eventMessageBody(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	input := &sns.PublishInput{
+		TopicArn:          aws.String(p.topicARN),
+		Message:           aws.String(message),
+		MessageAttributes: snsAttributes(event),
+	}
+	if p.fifo {
+		input.MessageGroupId = aws.String(event.AggregateID)
+		input.MessageDeduplicationId = aws.String(event.ID)
+	}
+
+	output, err := p.client.Publish(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to publish event to SNS: %w", err)
+	}
+
+	log.Printf("📤 Event published to SNS: type=%s, store=%s, id=%s, message_id=%s",
+		event.EventType, event.StoreID, event.ID, aws.ToString(output.MessageId))
+
+	return nil
+}
+
+// PublishBatch publica los eventos en llamadas a PublishBatch de hasta 10 entradas.
+// Falla si SNS rechaza alguna entrada; el worker de sincronización las reintenta.
+func (p *SNSPublisher) PublishBatch(ctx context.Context, events []*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := p.inflight.begin(); err != nil {
+		return err
+	}
+	defer p.inflight.done()
+
+	var errs []error
+	for start := 0; start < len(events); start += snsMaxBatch {
+		chunk := events[start:min(start+snsMaxBatch, len(events))]
+
+		entries := make([]snstypes.PublishBatchRequestEntry, 0, len(chunk))
+		for i, event := range chunk {
+			message, err := eventMessageBody(ctx, event)
+			if err != nil {
+				return err
+			}
+			entry := snstypes.PublishBatchRequestEntry{
+				Id:                aws.String(strconv.Itoa(i)),
+				Message:           aws.String(message),
+				MessageAttributes: snsAttributes(event),
+			}
+			if p.fifo {
+				entry.MessageGroupId = aws.String(event.AggregateID)
+				entry.MessageDeduplicationId = aws.String(event.ID)
+			}
+			entries = append(entries, entry)
+		}
+
+		output, err := p.client.PublishBatch(ctx, &sns.PublishBatchInput{
+			TopicArn:                   aws.String(p.topicARN),
+			PublishBatchRequestEntries: entries,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish batch to SNS: %w", err)
+		}
+		for _, failed := range output.Failed {
+			errs = append(errs, fmt.Errorf("event %s: %s: %s",
+				batchEventID(chunk, failed.Id), aws.ToString(failed.Code), aws.ToString(failed.Message)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to publish batch to SNS: %w", errors.Join(errs...))
+	}
+
+	log.Printf("📤 Batch published to SNS: %d events", len(events))
+
+	return nil
+}
+
+// Close espera a que terminen las publicaciones en curso y rechaza las nuevas
+func (p *SNSPublisher) Close() error {
+	log.Printf("🔌 Flushing SNS publisher")
+	if !p.inflight.close(cloudCloseTimeout) {
+		return errors.New("timeout waiting for in-flight SNS publishes")
+	}
+	return nil
+}
+
+// snsAttributes atributos del mensaje (SNS rechaza atributos vacíos)
+func snsAttributes(event *domain.Event) map[string]snstypes.MessageAttributeValue {
+	attributes := make(map[string]snstypes.MessageAttributeValue)
+	for name, value := range eventAttributes(event) {
+		if value != "" {
+			attributes[name] = snstypes.MessageAttributeValue{
+				DataType:    aws.String("String"),
+				StringValue: aws.String(value),
+			}
+		}
+	}
+	return attributes
+}
+
+// batchEventID resuelve el ID de entrada de un lote (su índice en chunk) al ID del evento
+func batchEventID(chunk []*domain.Event, entryID *string) string {
+	index, err := strconv.Atoi(aws.ToString(entryID))
+	if err != nil || index < 0 || index >= len(chunk) {
+		return aws.ToString(entryID)
+	}
+	return chunk[index].ID
+}
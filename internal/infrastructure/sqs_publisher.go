@@ -0,0 +1,190 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"inventory-system/internal/domain"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// sqsMaxBatch máximo de entradas por llamada a SendMessageBatch
+const sqsMaxBatch = 10
+
+// SQSPublisher implementa EventPublisher enviando los eventos directamente a una cola SQS,
+// para despliegues con un único consumidor que no necesitan el fan-out de SNS.
+//
+// Los mensajes llevan los mismos atributos que con SNS. Con una cola FIFO (.fifo) se usa
+// aggregate_id como MessageGroupId y el ID del evento como MessageDeduplicationId.
+type SQSPublisher struct {
+	client   *sqs.Client
+	queueURL string
+	fifo     bool
+	inflight inflightGroup
+}
+
+// SQSPublisherConfig configuración para SQSPublisher
+type SQSPublisherConfig struct {
+	AWSCredentialsConfig        // Region vacía = región de la URL de la cola
+	QueueURL             string // https://sqs.eu-west-1.amazonaws.com/123456789012/inventory-events(.fifo)
+	Endpoint             string // Opcional (LocalStack, VPC endpoint). Por defecto el endpoint regional
+}
+
+// NewSQSPublisher crea una nueva instancia de SQSPublisher.
+//
+// Ejemplo:
+//
+//	publisher, err := NewSQSPublisher(SQSPublisherConfig{
+//	    QueueURL: "https://sqs.eu-west-1.amazonaws.com/123456789012/inventory-events.fifo",
+//	})
+//	defer publisher.Close()
+func NewSQSPublisher(cfg SQSPublisherConfig) (*SQSPublisher, error) {
+	u, err := url.Parse(cfg.QueueURL)
+	if err != nil || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return nil, fmt.Errorf("invalid SQS queue URL %q", cfg.QueueURL)
+	}
+	if cfg.Region == "" {
+		// sqs.<region>.amazonaws.com
+		if parts := strings.Split(u.Hostname(), "."); len(parts) >= 4 && parts[0] == "sqs" {
+			cfg.Region = parts[1]
+		}
+	}
+
+	awsCfg, err := loadAWSConfig(context.Background(), cfg.AWSCredentialsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &SQSPublisher{
+		client: sqs.NewFromConfig(awsCfg, func(o *sqs.Options) {
+			if cfg.Endpoint != "" {
+				o.BaseEndpoint = aws.String(cfg.Endpoint)
+			}
+		}),
+		queueURL: cfg.QueueURL,
+		fifo:     strings.HasSuffix(u.Path, ".fifo"),
+	}
+
+	log.Printf("✅ Using AWS SQS queue %s (fifo: %t)", cfg.QueueURL, p.fifo)
+
+	return p, nil
+}
+
+// Publish envía un evento a la cola
+func (p *SQSPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	if err := p.inflight.begin(); err != nil {
+		return err
+	}
+	defer p.inflight.done()
+
+	body, err := eventMessageBody(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(p.queueURL),
+		MessageBody:       aws.String(body),
+		MessageAttributes: sqsAttributes(event),
+	}
+	if p.fifo {
+		input.MessageGroupId = aws.String(event.AggregateID)
+		input.MessageDeduplicationId = aws.String(event.ID)
+	}
+
+	output, err := p.client.SendMessage(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to publish event to SQS: %w", err)
+	}
+
+	log.Printf("📤 Event published to SQS: type=%s, store=%s, id=%s, message_id=%s",
+		event.EventType, event.StoreID, event.ID, aws.ToString(output.MessageId))
+
+	return nil
+}
+
+// PublishBatch envía los eventos en llamadas a SendMessageBatch de hasta 10 entradas.
+// Falla si SQS rechaza alguna entrada; el worker de sincronización las reintenta.
+func (p *SQSPublisher) PublishBatch(ctx context.Context, events []*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := p.inflight.begin(); err != nil {
+		return err
+	}
+	defer p.inflight.done()
+
+	var errs []error
+	for start := 0; start < len(events); start += sqsMaxBatch {
+		chunk := events[start:min(start+sqsMaxBatch, len(events))]
+
+		entries := make([]sqstypes.SendMessageBatchRequestEntry, 0, len(chunk))
+		for i, event := range chunk {
+			body, err := eventMessageBody(ctx, event)
+			if err != nil {
+				return err
+			}
+			entry := sqstypes.SendMessageBatchRequestEntry{
+				Id:                aws.String(strconv.Itoa(i)),
+				MessageBody:       aws.String(body),
+				MessageAttributes: sqsAttributes(event),
+			}
+			if p.fifo {
+				entry.MessageGroupId = aws.String(event.AggregateID)
+				entry.MessageDeduplicationId = aws.String(event.ID)
+			}
+			entries = append(entries, entry)
+		}
+
+		output, err := p.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
+			QueueUrl: aws.String(p.queueURL),
+			Entries:  entries,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish batch to SQS: %w", err)
+		}
+		for _, failed := range output.Failed {
+			errs = append(errs, fmt.Errorf("event %s: %s: %s",
+				batchEventID(chunk, failed.Id), aws.ToString(failed.Code), aws.ToString(failed.Message)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to publish batch to SQS: %w", errors.Join(errs...))
+	}
+
+	log.Printf("📤 Batch published to SQS: %d events", len(events))
+
+	return nil
+}
+
+// Close espera a que terminen las publicaciones en curso y rechaza las nuevas
+func (p *SQSPublisher) Close() error {
+	log.Printf("🔌 Flushing SQS publisher")
+	if !p.inflight.close(cloudCloseTimeout) {
+		return errors.New("timeout waiting for in-flight SQS publishes")
+	}
+	return nil
+}
+
+// sqsAttributes atributos del mensaje (SQS rechaza atributos vacíos)
+func sqsAttributes(event *domain.Event) map[string]sqstypes.MessageAttributeValue {
+	attributes := make(map[string]sqstypes.MessageAttributeValue)
+	for name, value := range eventAttributes(event) {
+		if value != "" {
+			attributes[name] = sqstypes.MessageAttributeValue{
+				DataType:    aws.String("String"),
+				StringValue: aws.String(value),
+			}
+		}
+	}
+	return attributes
+}
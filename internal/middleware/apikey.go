@@ -1,56 +1,74 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 
+	"inventory-system/internal/auth"
+	"inventory-system/internal/domain"
+
 	"github.com/gin-gonic/gin"
 )
 
 // APIKeyAuth valida una API Key simple en el header X-API-Key
-func APIKeyAuth(validAPIKeys map[string]string) gin.HandlerFunc {
+func APIKeyAuth(keyRing *auth.KeyRing) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
 
 		if apiKey == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": "missing X-API-Key header",
+				"error":      "Unauthorized",
+				"message":    "missing X-API-Key header",
+				"request_id": c.GetString(RequestIDKey),
 			})
 			c.Abort()
 			return
 		}
 
 		// Verificar si la API key es válida
-		storeName, valid := validAPIKeys[apiKey]
+		storeName, valid := keyRing.Lookup(apiKey)
 		if !valid {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Unauthorized",
-				"message": "invalid API key",
+				"error":      "Unauthorized",
+				"message":    "invalid API key",
+				"request_id": c.GetString(RequestIDKey),
 			})
 			c.Abort()
 			return
 		}
 
-		// Guardar información en el contexto
+		// Guardar información en el contexto (el nombre de la key es el autor de los cambios
+		// y sus tiendas limitan en cuáles puede escribir)
 		c.Set("api_key", apiKey)
 		c.Set("store_name", storeName)
+		c.Request = c.Request.WithContext(withKeyIdentity(c.Request.Context(), keyRing, apiKey, storeName))
 
 		c.Next()
 	}
 }
 
 // OptionalAPIKeyAuth valida la API key si está presente, pero no la requiere
-func OptionalAPIKeyAuth(validAPIKeys map[string]string) gin.HandlerFunc {
+func OptionalAPIKeyAuth(keyRing *auth.KeyRing) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
 
 		if apiKey != "" {
-			if storeName, valid := validAPIKeys[apiKey]; valid {
+			if storeName, valid := keyRing.Lookup(apiKey); valid {
 				c.Set("api_key", apiKey)
 				c.Set("store_name", storeName)
+				c.Request = c.Request.WithContext(withKeyIdentity(c.Request.Context(), keyRing, apiKey, storeName))
 			}
 		}
 
 		c.Next()
 	}
 }
+
+// withKeyIdentity añade al context el autor (nombre de la key) y las tiendas a las que está limitada
+func withKeyIdentity(ctx context.Context, keyRing *auth.KeyRing, apiKey, name string) context.Context {
+	ctx = domain.WithActor(ctx, name)
+	if stores := keyRing.StoreScope(apiKey); stores != nil {
+		ctx = domain.WithStoreScope(ctx, stores)
+	}
+	return ctx
+}
@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+
+	// brotliLevel prioriza la velocidad: los listados grandes se comprimen al vuelo
+	brotliLevel = 4
+)
+
+// streamEncoder es el compresor común a gzip y brotli
+type streamEncoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var encoderPools = map[string]*sync.Pool{
+	encodingBrotli: {New: func() interface{} { return brotli.NewWriterLevel(io.Discard, brotliLevel) }},
+	encodingGzip: {New: func() interface{} {
+		writer, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return writer
+	}},
+}
+
+// Compression comprime las respuestas con brotli o gzip según Accept-Encoding (brotli si el cliente
+// acepta ambos con la misma preferencia). Las respuestas menores de minBytes se envían sin comprimir,
+// salvo que el handler haga Flush (streaming). No comprime los tipos ya comprimidos (XLSX, imágenes),
+// las respuestas que ya traen Content-Encoding ni las conexiones websocket.
+func Compression(minBytes int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minBytes: minBytes}
+		c.Writer = writer
+		// Ante un panic se descarta lo acumulado y Recovery responde con el writer original
+		defer func() { c.Writer = writer.ResponseWriter }()
+
+		c.Next()
+		writer.close()
+	}
+}
+
+// negotiateEncoding elige la codificación con mayor q de Accept-Encoding ("" = sin comprimir)
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		switch name {
+		case encodingBrotli, encodingGzip:
+		case "*":
+			name = encodingGzip
+		default:
+			continue
+		}
+		if q > bestQ || (q == bestQ && q > 0 && name == encodingBrotli) {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressible indica si merece la pena comprimir el Content-Type
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"),
+		mediaType == "application/json",
+		mediaType == "application/javascript",
+		mediaType == "application/xml":
+		return true
+	}
+	return false
+}
+
+// compressWriter acumula el cuerpo hasta minBytes para decidir si comprimirlo; a partir de ahí
+// escribe a través del compresor sin acumular
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minBytes int
+	buffer   []byte
+	decided  bool
+	encoder  streamEncoder
+}
+
+// Write acumula o comprime el cuerpo
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buffer = append(w.buffer, data...)
+		if len(w.buffer) < w.minBytes {
+			return len(data), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString evita que el ResponseWriter embebido escriba sin pasar por Write
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written considera escrita la respuesta en cuanto hay cuerpo acumulado
+func (w *compressWriter) Written() bool {
+	return len(w.buffer) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush envía lo acumulado (comprimido, aunque no llegue a minBytes: habrá más) y vacía el compresor
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide fija las cabeceras y escribe lo acumulado. compress se ignora si la respuesta no admite
+// compresión (cabeceras ya enviadas, Content-Encoding propio, rango parcial o tipo no comprimible).
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	buffer := w.buffer
+	w.buffer = nil
+
+	header := w.Header()
+	status := w.Status()
+	if compress && !w.ResponseWriter.Written() && header.Get("Content-Encoding") == "" &&
+		header.Get("Content-Range") == "" && status != http.StatusNoContent &&
+		status != http.StatusNotModified && status != http.StatusPartialContent {
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", http.DetectContentType(buffer))
+		}
+		compress = compressible(header.Get("Content-Type"))
+	} else {
+		compress = false
+	}
+
+	if compress {
+		header.Set("Content-Encoding", w.encoding)
+		header.Add("Vary", "Accept-Encoding")
+		header.Del("Content-Length")
+		w.encoder = encoderPools[w.encoding].Get().(streamEncoder)
+		w.encoder.Reset(w.ResponseWriter)
+		_, err := w.encoder.Write(buffer)
+		return err
+	}
+
+	if len(buffer) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buffer)
+	return err
+}
+
+// close envía lo que quede: sin comprimir si no llegó a minBytes
+func (w *compressWriter) close() {
+	if !w.decided {
+		if len(w.buffer) == 0 {
+			return
+		}
+		_ = w.decide(false)
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+		w.encoder.Reset(io.Discard)
+		encoderPools[w.encoding].Put(w.encoder)
+		w.encoder = nil
+	}
+}
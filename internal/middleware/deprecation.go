@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation marca las respuestas como deprecadas (headers Deprecation, Sunset y Link).
+// successor es la ruta base de la versión que la reemplaza; sunset es opcional (zero = sin fecha).
+func Deprecation(successor string, sunset time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Set("Deprecation", "true")
+		c.Writer.Header().Set("Link", "<"+successor+">; rel=\"successor-version\"")
+		if !sunset.IsZero() {
+			c.Writer.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxIdempotentResponseBytes respuestas mayores no se guardan: el reintento se procesa de nuevo
+const maxIdempotentResponseBytes = 1 << 20
+
+// IdempotencyStore guarda las peticiones con Idempotency-Key y sus respuestas
+type IdempotencyStore interface {
+	Begin(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, scope, key string, status int, contentType string, body []byte) error
+	Release(ctx context.Context, scope, key string) error
+}
+
+// Idempotency deduplica las escrituras (POST, PUT, PATCH, DELETE) bajo /api que llevan
+// Idempotency-Key: la primera petición se procesa y su respuesta se guarda; los reintentos con la
+// misma clave y la misma petición reciben esa respuesta (con Idempotent-Replayed: true) sin
+// volver a aplicarse. Reutilizar la clave con otra petición responde 422 y reintentar mientras la
+// original sigue en curso, 409 con Retry-After. Las claves son por API key, así que debe
+// registrarse como middleware global, antes de que APIKeyAuth valide la key en cada grupo. Las
+// respuestas 5xx, 401, 408 y 429 no se guardan: el reintento se procesa de nuevo.
+func Idempotency(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(domain.IdempotencyKeyHeader)
+		apiKey := c.GetHeader("X-API-Key")
+		if key == "" || apiKey == "" || !isWriteMethod(c.Request.Method) || !strings.HasPrefix(c.Request.URL.Path, "/api/") {
+			c.Next()
+			return
+		}
+		if len(key) > domain.MaxIdempotencyKeyLength {
+			abortWithError(c, http.StatusBadRequest, "Bad Request",
+				fmt.Sprintf("%s cannot exceed %d characters", domain.IdempotencyKeyHeader, domain.MaxIdempotencyKeyLength))
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			if body, err = io.ReadAll(c.Request.Body); err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					abortWithError(c, http.StatusRequestEntityTooLarge, "Payload Too Large", fmt.Sprintf("request body cannot exceed %d bytes", tooLarge.Limit))
+				} else {
+					abortWithError(c, http.StatusBadRequest, "Bad Request", "failed to read request body")
+				}
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		record := &domain.IdempotencyRecord{
+			Scope:       auth.HashKey(apiKey),
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.RequestURI(),
+			RequestHash: domain.HashIdempotentRequest(c.Request.Method, c.Request.URL.RequestURI(), body),
+			CreatedAt:   time.Now(),
+		}
+		existing, err := store.Begin(c.Request.Context(), record)
+		if err != nil {
+			log.Printf("Error registering idempotency key: %v", err)
+			abortWithError(c, http.StatusInternalServerError, "Internal Server Error", "failed to register idempotency key")
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				abortWithError(c, http.StatusUnprocessableEntity, "Unprocessable Entity",
+					fmt.Sprintf("%s was already used for a different request", domain.IdempotencyKeyHeader))
+			case !existing.Completed():
+				c.Header("Retry-After", "1")
+				abortWithError(c, http.StatusConflict, "Conflict",
+					fmt.Sprintf("a request with this %s is still in progress", domain.IdempotencyKeyHeader))
+			default:
+				c.Header(domain.IdempotencyReplayedHeader, "true")
+				c.Data(existing.Status, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() { c.Writer = writer.ResponseWriter }()
+
+		c.Next()
+
+		// La respuesta se guarda aunque el cliente se haya ido: es justo el caso que se reintenta
+		ctx := context.WithoutCancel(c.Request.Context())
+		status := writer.Status()
+		if storableIdempotentStatus(status) && !writer.overflow {
+			err = store.Complete(ctx, record.Scope, key, status, writer.Header().Get("Content-Type"), writer.body)
+		} else {
+			err = store.Release(ctx, record.Scope, key)
+		}
+		if err != nil {
+			log.Printf("Error saving idempotent response for key %s: %v", key, err)
+		}
+	}
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// storableIdempotentStatus indica si la respuesta es definitiva y se repite en los reintentos
+func storableIdempotentStatus(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+// idempotencyWriter copia el cuerpo de la respuesta mientras lo escribe
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body     []byte
+	overflow bool
+}
+
+// Write escribe el cuerpo y lo copia hasta maxIdempotentResponseBytes
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	if !w.overflow {
+		if len(w.body)+len(data) > maxIdempotentResponseBytes {
+			w.overflow, w.body = true, nil
+		} else {
+			w.body = append(w.body, data...)
+		}
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString evita que el ResponseWriter embebido escriba sin pasar por Write
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LatencyRecorder registra la duración de cada request por ruta
+type LatencyRecorder interface {
+	Record(route string, statusCode int, duration, budget time.Duration, at time.Time)
+}
+
+// LatencyBudget mide la duración de cada request y la registra por ruta ("MÉTODO /ruta" tal
+// como se registra en gin) junto con su presupuesto de latencia. budgets usa los mismos patrones
+// que los timeouts (ruta exacta o prefijo con "*", con o sin método; 0 = sin presupuesto). Las
+// rutas no registradas (404) no se miden, para no crear una serie por cada URL desconocida.
+func LatencyBudget(recorder LatencyRecorder, budgets TimeoutPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			return
+		}
+		method := c.Request.Method
+		recorder.Record(method+" "+route, c.Writer.Status(), time.Since(start), budgets.Resolve(method, route), start)
+	}
+}
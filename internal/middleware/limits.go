@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit rechaza con 413 los bodies que superan maxBytes. Si el cliente declara
+// Content-Length se rechaza sin leer el body; si no (chunked), el body se envuelve en
+// http.MaxBytesReader y el error aparece al decodificarlo. Las subidas multipart tienen
+// su propio límite (MEDIA_MAX_UPLOAD_MB) y no se ven afectadas.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxBytes <= 0 || c.Request.Body == nil || strings.HasPrefix(c.ContentType(), "multipart/") {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":      "Payload Too Large",
+				"message":    fmt.Sprintf("request body cannot exceed %d bytes", maxBytes),
+				"request_id": c.GetString(RequestIDKey),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
+// ListLimit rechaza con 400 los listados con ?limit= mayor que max, para que un cliente no
+// pueda recorrer una tabla entera en un solo request. Los valores no numéricos los valida
+// cada handler.
+func ListLimit(max int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if raw := c.Query("limit"); raw != "" && max > 0 {
+			if limit, err := strconv.Atoi(raw); err == nil && limit > max {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":      "Bad Request",
+					"message":    fmt.Sprintf("limit cannot exceed %d", max),
+					"request_id": c.GetString(RequestIDKey),
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
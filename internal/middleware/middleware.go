@@ -1,11 +1,19 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"runtime/debug"
+	"syscall"
 	"time"
 
+	"inventory-system/internal/domain"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // Logger middleware para logging de requests
@@ -30,20 +38,101 @@ func Logger() gin.HandlerFunc {
 		}
 
 		// Log HTTP requests with standard logger
-		log.Printf("[GIN] %s | %3d | %13v | %15s | %-7s %s",
+		log.Printf("[GIN] %s | %3d | %13v | %15s | %-7s %s | request_id=%s",
 			start.Format("2006/01/02 - 15:04:05"),
 			statusCode,
 			latency,
 			clientIP,
 			method,
 			path,
+			c.GetString(RequestIDKey),
 		)
 	}
 }
 
-// Recovery middleware para recuperarse de panics
-func Recovery() gin.HandlerFunc {
-	return gin.Recovery()
+// panicReportTimeout límite para enviar un panic al ErrorReporter
+const panicReportTimeout = 10 * time.Second
+
+// responseSerializerKey clave donde handler.UseSerializer guarda el serializer de la versión
+const responseSerializerKey = "response_serializer"
+
+// errorSerializer subconjunto de handler.Serializer con el que Recovery y Timeout responden
+type errorSerializer interface {
+	Error(c *gin.Context, status int, title, message string, details interface{})
+}
+
+// abortWithError responde con el formato de error de la versión de la API de la ruta
+// (v1 si la ruta no tiene serializer) y aborta la cadena de handlers
+func abortWithError(c *gin.Context, status int, title, message string) {
+	if serializer, ok := c.Value(responseSerializerKey).(errorSerializer); ok {
+		serializer.Error(c, status, title, message, nil)
+	} else {
+		c.JSON(status, gin.H{
+			"error":      title,
+			"message":    message,
+			"request_id": c.GetString(RequestIDKey),
+		})
+	}
+	c.Abort()
+}
+
+// Recovery middleware para recuperarse de panics. Registra el valor y el stack con el request ID,
+// responde 500 con el formato de error de la versión de la API (v1 si la ruta no tiene serializer)
+// y, si hay reporter, envía el panic en segundo plano (Sentry, webhook) sin retrasar la respuesta.
+// Si el cliente cerró la conexión solo se registra: no hay a quién responder ni es un bug.
+func Recovery(reporter domain.ErrorReporter, instanceID string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// net/http usa ErrAbortHandler para abortar la respuesta a propósito
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			requestID := c.GetString(RequestIDKey)
+			if err, ok := recovered.(error); ok && (errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)) {
+				log.Printf("[RECOVERY] connection closed by client | request_id=%s | %s %s | %v", requestID, c.Request.Method, c.Request.URL.Path, err)
+				c.Abort()
+				return
+			}
+
+			report := &domain.PanicReport{
+				RequestID:  requestID,
+				Method:     c.Request.Method,
+				Path:       c.Request.URL.Path,
+				Route:      c.FullPath(),
+				Value:      fmt.Sprintf("%v", recovered),
+				Stack:      string(debug.Stack()),
+				InstanceID: instanceID,
+				OccurredAt: time.Now(),
+			}
+			log.Printf("[PANIC] request_id=%s method=%s path=%s route=%s panic=%s\n%s",
+				report.RequestID, report.Method, report.Path, report.Route, report.Value, report.Stack)
+
+			if reporter != nil {
+				go func() {
+					ctx, cancel := context.WithTimeout(context.Background(), panicReportTimeout)
+					defer cancel()
+					if err := reporter.ReportPanic(ctx, report); err != nil {
+						log.Printf("⚠️  Failed to report panic (request_id=%s): %v", report.RequestID, err)
+					}
+				}()
+			}
+
+			// Si el handler ya empezó a responder no se puede cambiar el status
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+
+			abortWithError(c, http.StatusInternalServerError, "Internal Server Error", "unexpected error processing the request")
+		}()
+
+		c.Next()
+	}
 }
 
 // CORS middleware para permitir peticiones cross-origin
@@ -51,7 +140,7 @@ func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Sales-Channel, Idempotency-Key, accept, origin, Cache-Control, X-Requested-With")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
@@ -63,30 +152,58 @@ func CORS() gin.HandlerFunc {
 	}
 }
 
-// RequestID middleware para agregar un ID único a cada request
+// RequestIDHeader header con el que se recibe y se devuelve el ID del request
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey clave del contexto de gin donde se guarda el ID del request
+const RequestIDKey = "request_id"
+
+// RequestID middleware para agregar un ID único a cada request.
+// El ID se propaga también en el context del request (domain.WithCorrelationID),
+// de modo que servicios y repositorios lo adjuntan a los eventos que emiten.
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := c.GetHeader("X-Request-ID")
+		requestID := c.GetHeader(RequestIDHeader)
 		if requestID == "" {
-			requestID = fmt.Sprintf("%d", time.Now().UnixNano())
+			requestID = uuid.New().String()
 		}
 
-		c.Set("request_id", requestID)
-		c.Writer.Header().Set("X-Request-ID", requestID)
+		c.Set(RequestIDKey, requestID)
+		c.Writer.Header().Set(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(domain.WithCorrelationID(c.Request.Context(), requestID))
 
 		c.Next()
 	}
 }
 
-// Timeout middleware para limitar el tiempo de procesamiento
-func Timeout(timeout time.Duration) gin.HandlerFunc {
+// SalesChannelHeader header con el que el cliente indica su canal de venta (alternativa: ?channel=)
+const SalesChannelHeader = "X-Sales-Channel"
+
+// SalesChannel middleware que lee el canal de venta del request y lo propaga en el context
+// (domain.WithSalesChannel): la disponibilidad y las reservas se limitan a la asignación del canal.
+// Un canal desconocido se rechaza con 400.
+func SalesChannel() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// NOTA: Implementación simplificada para prototipo
-		// En producción se debe usar context.WithTimeout para cancelar operaciones largas
-		// Ejemplo:
-		// ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
-		// defer cancel()
-		// c.Request = c.Request.WithContext(ctx)
+		value := c.GetHeader(SalesChannelHeader)
+		if value == "" {
+			value = c.Query("channel")
+		}
+
+		channel, err := domain.ParseSalesChannel(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Bad Request",
+				"message":    err.Error(),
+				"request_id": c.GetString(RequestIDKey),
+			})
+			c.Abort()
+			return
+		}
+
+		if channel != "" {
+			c.Request = c.Request.WithContext(domain.WithSalesChannel(c.Request.Context(), channel))
+		}
+
 		c.Next()
 	}
 }
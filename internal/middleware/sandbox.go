@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SandboxErrorHeader permite al integrador forzar un error concreto en modo sandbox (p. ej. "503")
+const SandboxErrorHeader = "X-Sandbox-Error"
+
+// Sandbox simula latencia y errores en las rutas /api del modo sandbox.
+// latency es la latencia base, jitter el máximo aleatorio añadido y errorRate (0-1) la
+// probabilidad de responder 503. La secuencia aleatoria es determinista a partir de seed.
+func Sandbox(latency, jitter time.Duration, errorRate float64, seed int64) gin.HandlerFunc {
+	var mu sync.Mutex
+	rng := rand.New(rand.NewSource(seed))
+
+	return func(c *gin.Context) {
+		c.Writer.Header().Set("X-Sandbox-Mode", "true")
+
+		if !strings.HasPrefix(c.Request.URL.Path, "/api/") {
+			c.Next()
+			return
+		}
+
+		mu.Lock()
+		delay := latency
+		if jitter > 0 {
+			delay += time.Duration(rng.Int63n(int64(jitter) + 1))
+		}
+		fail := errorRate > 0 && rng.Float64() < errorRate
+		mu.Unlock()
+
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-c.Request.Context().Done():
+				c.AbortWithStatus(http.StatusRequestTimeout)
+				return
+			}
+		}
+
+		// Error forzado por el cliente (solo códigos 4xx/5xx)
+		if forced := c.GetHeader(SandboxErrorHeader); forced != "" {
+			status, err := strconv.Atoi(forced)
+			if err == nil && status >= 400 && status <= 599 {
+				c.JSON(status, gin.H{
+					"error":   http.StatusText(status),
+					"message": "error forced by " + SandboxErrorHeader + " header",
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		if fail {
+			c.Writer.Header().Set("Retry-After", "1")
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Service Unavailable",
+				"message": "simulated sandbox failure",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
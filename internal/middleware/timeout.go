@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutPolicy decide el timeout de cada ruta: un valor por defecto y overrides por patrón.
+// Un patrón es "[MÉTODO ]ruta", con la ruta tal como se registra en gin ("/api/v1/products/:id")
+// o un prefijo terminado en "*" ("/api/v1/reports/*"). Timeout 0 = sin límite.
+type TimeoutPolicy struct {
+	Default   time.Duration
+	Overrides map[string]time.Duration
+}
+
+// Resolve retorna el timeout de la ruta. Prevalece el patrón exacto con método, después el
+// exacto sin método y, entre prefijos, el más largo (con método antes que sin él)
+func (p TimeoutPolicy) Resolve(method, route string) time.Duration {
+	if timeout, ok := p.Overrides[method+" "+route]; ok {
+		return timeout
+	}
+	if timeout, ok := p.Overrides[route]; ok {
+		return timeout
+	}
+
+	best, bestLen, found := time.Duration(0), -1, false
+	for pattern, timeout := range p.Overrides {
+		patternMethod, prefix, hasMethod := strings.Cut(pattern, " ")
+		if !hasMethod {
+			prefix, patternMethod = patternMethod, ""
+		}
+		if (patternMethod != "" && patternMethod != method) || !strings.HasSuffix(prefix, "*") {
+			continue
+		}
+		prefix = strings.TrimSuffix(prefix, "*")
+		if !strings.HasPrefix(route, prefix) {
+			continue
+		}
+		// Con método pesa más que sin él a igual longitud
+		length := len(prefix) * 2
+		if patternMethod != "" {
+			length++
+		}
+		if length > bestLen {
+			best, bestLen, found = timeout, length, true
+		}
+	}
+	if found {
+		return best
+	}
+	return p.Default
+}
+
+// Timeout pone un deadline al context del request según policy. Los repositorios usan el
+// context en sus consultas, así que un bloqueo de SQLite o la espera de una conexión del pool
+// se cancelan al vencer. Si el handler no respondió (o respondió tras el deadline sin escribir),
+// se responde 504; handleError también responde 504 a los errores causados por el deadline.
+func Timeout(policy TimeoutPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := policy.Resolve(c.Request.Method, c.FullPath())
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			abortWithError(c, http.StatusGatewayTimeout, "Gateway Timeout", fmt.Sprintf("request exceeded the %v time limit", timeout))
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageRecorder registra el uso de una API key
+type UsageRecorder interface {
+	Record(apiKey string, statusCode int, at time.Time)
+}
+
+// APIKeyUsage registra cada request autenticado con API key.
+// Debe registrarse como middleware global: lee "api_key" del contexto
+// después de que APIKeyAuth lo haya validado en el grupo de rutas.
+func APIKeyUsage(recorder UsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		apiKey := c.GetString("api_key")
+		if apiKey == "" {
+			return
+		}
+
+		recorder.Record(apiKey, c.Writer.Status(), time.Now())
+	}
+}
@@ -0,0 +1,124 @@
+package realtime
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// AvailabilityUpdate representa un cambio de disponibilidad de un producto en una tienda
+type AvailabilityUpdate struct {
+	ProductID string    `json:"product_id"`
+	StoreID   string    `json:"store_id"`
+	Category  string    `json:"category"`
+	Quantity  int       `json:"quantity"`
+	Reserved  int       `json:"reserved"`
+	Available int       `json:"available"` // Vendible: sin el stock de seguridad
+	EventType string    `json:"event_type"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Filter define qué actualizaciones recibe un suscriptor.
+// Los campos vacíos no filtran. ProductIDs y Category se combinan con OR:
+// un suscriptor de una categoría recibe todos sus productos sin listarlos.
+type Filter struct {
+	StoreID    string
+	Category   string
+	ProductIDs map[string]bool
+}
+
+// Matches verifica si una actualización pasa el filtro
+func (f Filter) Matches(update *AvailabilityUpdate) bool {
+	if f.StoreID != "" && f.StoreID != update.StoreID {
+		return false
+	}
+
+	if f.Category == "" && len(f.ProductIDs) == 0 {
+		return true
+	}
+
+	if f.Category != "" && f.Category == update.Category {
+		return true
+	}
+
+	return f.ProductIDs[update.ProductID]
+}
+
+// Subscriber es un cliente conectado al hub
+type Subscriber struct {
+	ID      uint64
+	Filter  Filter
+	Updates chan *AvailabilityUpdate
+}
+
+// Hub distribuye actualizaciones de disponibilidad a los suscriptores.
+// Es soft real-time: si un suscriptor no consume a tiempo, sus mensajes se descartan
+// en lugar de bloquear al resto.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[uint64]*Subscriber
+	nextID      uint64
+	bufferSize  int
+}
+
+// NewHub crea un nuevo hub
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = 64
+	}
+	return &Hub{
+		subscribers: make(map[uint64]*Subscriber),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe registra un nuevo suscriptor con el filtro indicado
+func (h *Hub) Subscribe(filter Filter) *Subscriber {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	sub := &Subscriber{
+		ID:      h.nextID,
+		Filter:  filter,
+		Updates: make(chan *AvailabilityUpdate, h.bufferSize),
+	}
+	h.subscribers[sub.ID] = sub
+
+	return sub
+}
+
+// Unsubscribe elimina un suscriptor y cierra su canal
+func (h *Hub) Unsubscribe(sub *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub.ID]; ok {
+		delete(h.subscribers, sub.ID)
+		close(sub.Updates)
+	}
+}
+
+// Broadcast envía la actualización a los suscriptores cuyo filtro coincide
+func (h *Hub) Broadcast(update *AvailabilityUpdate) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, sub := range h.subscribers {
+		if !sub.Filter.Matches(update) {
+			continue
+		}
+		select {
+		case sub.Updates <- update:
+		default:
+			log.Printf("⚠️  Realtime subscriber %d is slow, dropping update for product %s", sub.ID, update.ProductID)
+		}
+	}
+}
+
+// Count retorna la cantidad de suscriptores conectados
+func (h *Hub) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
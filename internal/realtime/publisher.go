@@ -0,0 +1,145 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// HubPublisher decora un EventPublisher y, además de delegar la publicación,
+// alimenta el Hub con la disponibilidad actualizada de los productos afectados.
+// El cálculo se hace en una goroutine para no añadir latencia a los servicios.
+type HubPublisher struct {
+	inner       domain.EventPublisher
+	hub         *Hub
+	stockRepo   *repository.StockRepository
+	productRepo *repository.ProductRepository
+	queue       chan *domain.Event
+	done        chan struct{}
+}
+
+// NewHubPublisher crea el decorador y arranca su worker
+func NewHubPublisher(
+	inner domain.EventPublisher,
+	hub *Hub,
+	stockRepo *repository.StockRepository,
+	productRepo *repository.ProductRepository,
+) *HubPublisher {
+	p := &HubPublisher{
+		inner:       inner,
+		hub:         hub,
+		stockRepo:   stockRepo,
+		productRepo: productRepo,
+		queue:       make(chan *domain.Event, 1024),
+		done:        make(chan struct{}),
+	}
+
+	go p.run()
+
+	return p
+}
+
+// Publish delega en el publisher interno y encola el evento para el hub
+func (p *HubPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	p.enqueue(event)
+	return p.inner.Publish(ctx, event)
+}
+
+// PublishBatch delega en el publisher interno y encola los eventos para el hub
+func (p *HubPublisher) PublishBatch(ctx context.Context, events []*domain.Event) error {
+	for _, event := range events {
+		p.enqueue(event)
+	}
+	return p.inner.PublishBatch(ctx, events)
+}
+
+// Close detiene el worker y cierra el publisher interno
+func (p *HubPublisher) Close() error {
+	close(p.done)
+	return p.inner.Close()
+}
+
+func (p *HubPublisher) enqueue(event *domain.Event) {
+	if !strings.HasPrefix(event.EventType, "stock.") && !strings.HasPrefix(event.EventType, "reservation.") {
+		return
+	}
+	if p.hub.Count() == 0 {
+		return
+	}
+
+	select {
+	case p.queue <- event:
+	default:
+		log.Printf("⚠️  Realtime queue full, dropping event %s", event.ID)
+	}
+}
+
+func (p *HubPublisher) run() {
+	for {
+		select {
+		case <-p.done:
+			return
+		case event := <-p.queue:
+			p.broadcast(event)
+		}
+	}
+}
+
+// affectedStock extrae los pares (producto, tienda) afectados por un evento
+func affectedStock(event *domain.Event) (productID string, storeIDs []string) {
+	var payload struct {
+		ProductID   string `json:"product_id"`
+		StoreID     string `json:"store_id"`
+		FromStoreID string `json:"from_store_id"`
+		ToStoreID   string `json:"to_store_id"`
+	}
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return "", nil
+	}
+
+	for _, id := range []string{payload.StoreID, payload.FromStoreID, payload.ToStoreID} {
+		if id != "" {
+			storeIDs = append(storeIDs, id)
+		}
+	}
+
+	return payload.ProductID, storeIDs
+}
+
+func (p *HubPublisher) broadcast(event *domain.Event) {
+	productID, storeIDs := affectedStock(event)
+	if productID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	product, err := p.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return
+	}
+
+	for _, storeID := range storeIDs {
+		stock, err := p.stockRepo.GetByProductAndStore(ctx, productID, storeID)
+		if err != nil {
+			continue
+		}
+
+		p.hub.Broadcast(&AvailabilityUpdate{
+			ProductID: productID,
+			StoreID:   storeID,
+			Category:  product.Category,
+			Quantity:  stock.Quantity,
+			Reserved:  stock.Reserved,
+			Available: stock.Sellable(),
+			EventType: event.EventType,
+			Timestamp: event.CreatedAt,
+		})
+	}
+}
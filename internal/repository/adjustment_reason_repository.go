@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// AdjustmentReasonRepository maneja el catálogo de motivos de ajuste de stock
+type AdjustmentReasonRepository struct {
+	db *sql.DB
+}
+
+// NewAdjustmentReasonRepository crea una nueva instancia del repositorio
+func NewAdjustmentReasonRepository(db *sql.DB) *AdjustmentReasonRepository {
+	return &AdjustmentReasonRepository{db: db}
+}
+
+// Upsert crea el motivo o reemplaza su descripción y estado
+func (r *AdjustmentReasonRepository) Upsert(ctx context.Context, reason *domain.AdjustmentReason) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO adjustment_reasons (code, description, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(code) DO UPDATE SET
+			description = excluded.description,
+			active = excluded.active,
+			updated_at = excluded.updated_at
+	`, reason.Code, reason.Description, reason.Active, reason.CreatedAt, reason.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save adjustment reason: %w", err)
+	}
+	return nil
+}
+
+// Get obtiene un motivo por código
+func (r *AdjustmentReasonRepository) Get(ctx context.Context, code string) (*domain.AdjustmentReason, error) {
+	var reason domain.AdjustmentReason
+	err := r.db.QueryRowContext(ctx, `
+		SELECT code, description, active, created_at, updated_at
+		FROM adjustment_reasons
+		WHERE code = ?
+	`, code).Scan(&reason.Code, &reason.Description, &reason.Active, &reason.CreatedAt, &reason.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "AdjustmentReason", ID: code}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adjustment reason: %w", err)
+	}
+	return &reason, nil
+}
+
+// List lista los motivos por código; includeInactive incluye los desactivados
+func (r *AdjustmentReasonRepository) List(ctx context.Context, includeInactive bool) ([]*domain.AdjustmentReason, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT code, description, active, created_at, updated_at
+		FROM adjustment_reasons
+		WHERE ? OR active = 1
+		ORDER BY code
+	`, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list adjustment reasons: %w", err)
+	}
+	defer rows.Close()
+
+	reasons := []*domain.AdjustmentReason{}
+	for rows.Next() {
+		var reason domain.AdjustmentReason
+		if err := rows.Scan(&reason.Code, &reason.Description, &reason.Active, &reason.CreatedAt, &reason.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan adjustment reason: %w", err)
+		}
+		reasons = append(reasons, &reason)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating adjustment reasons: %w", err)
+	}
+
+	return reasons, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// APIKeyRepository maneja la persistencia de API keys emitidas en runtime.
+// Solo se guarda el hash de la key, nunca la key en claro.
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository crea una nueva instancia del repositorio
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create persiste una API key con su hash
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.StoreAPIKey, keyHash string) error {
+	query := `
+		INSERT INTO api_keys (id, key_hash, name, store_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, key.ID, keyHash, key.Name, key.StoreID, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveByStore obtiene la API key activa (no revocada) de una tienda
+func (r *APIKeyRepository) GetActiveByStore(ctx context.Context, storeID string) (*domain.StoreAPIKey, error) {
+	query := `
+		SELECT id, name, store_id, created_at
+		FROM api_keys
+		WHERE store_id = ? AND revoked_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var key domain.StoreAPIKey
+	err := r.db.QueryRowContext(ctx, query, storeID).Scan(
+		&key.ID,
+		&key.Name,
+		&key.StoreID,
+		&key.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "APIKey", ID: "store=" + storeID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// ListActiveHashes obtiene hash -> key (nombre y tienda) de todas las keys activas
+// (para cargar el keyring al iniciar)
+func (r *APIKeyRepository) ListActiveHashes(ctx context.Context) (map[string]*domain.StoreAPIKey, error) {
+	query := `
+		SELECT key_hash, name, COALESCE(store_id, '')
+		FROM api_keys
+		WHERE revoked_at IS NULL
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]*domain.StoreAPIKey)
+	for rows.Next() {
+		var hash string
+		var key domain.StoreAPIKey
+		if err := rows.Scan(&hash, &key.Name, &key.StoreID); err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		hashes[hash] = &key
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating api keys: %w", err)
+	}
+
+	return hashes, nil
+}
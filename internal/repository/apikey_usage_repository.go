@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// APIKeyUsageRepository maneja la persistencia del uso de API keys
+type APIKeyUsageRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyUsageRepository crea una nueva instancia del repositorio
+func NewAPIKeyUsageRepository(db *sql.DB) *APIKeyUsageRepository {
+	return &APIKeyUsageRepository{db: db}
+}
+
+// Increment suma requests y errores al rollup diario de una API key
+func (r *APIKeyUsageRepository) Increment(ctx context.Context, keyID, day string, requests, errors int, lastUsedAt time.Time) error {
+	query := `
+		INSERT INTO api_key_usage (key_id, day, request_count, error_count, last_used_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(key_id, day) DO UPDATE SET
+			request_count = request_count + excluded.request_count,
+			error_count = error_count + excluded.error_count,
+			last_used_at = CASE WHEN excluded.last_used_at > last_used_at
+				THEN excluded.last_used_at ELSE last_used_at END
+	`
+
+	_, err := r.db.ExecContext(ctx, query, keyID, day, requests, errors, lastUsedAt)
+	if err != nil {
+		return fmt.Errorf("failed to increment api key usage: %w", err)
+	}
+
+	return nil
+}
+
+// GetDaily obtiene el rollup diario de una API key desde el día indicado (inclusive)
+func (r *APIKeyUsageRepository) GetDaily(ctx context.Context, keyID, sinceDay string) ([]domain.APIKeyUsageDay, error) {
+	query := `
+		SELECT day, request_count, error_count, last_used_at
+		FROM api_key_usage
+		WHERE key_id = ? AND day >= ?
+		ORDER BY day ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, keyID, sinceDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key usage: %w", err)
+	}
+	defer rows.Close()
+
+	var days []domain.APIKeyUsageDay
+	for rows.Next() {
+		var d domain.APIKeyUsageDay
+		if err := rows.Scan(&d.Day, &d.RequestCount, &d.ErrorCount, &d.LastUsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan api key usage: %w", err)
+		}
+		days = append(days, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating api key usage: %w", err)
+	}
+
+	return days, nil
+}
+
+// GetLastUsed obtiene la última vez que se usó cada API key registrada
+func (r *APIKeyUsageRepository) GetLastUsed(ctx context.Context) (map[string]time.Time, error) {
+	query := `
+		SELECT u.key_id, u.last_used_at
+		FROM api_key_usage u
+		WHERE u.day = (SELECT MAX(day) FROM api_key_usage WHERE key_id = u.key_id)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key last usage: %w", err)
+	}
+	defer rows.Close()
+
+	lastUsed := make(map[string]time.Time)
+	for rows.Next() {
+		var keyID string
+		var usedAt time.Time
+		if err := rows.Scan(&keyID, &usedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan api key last usage: %w", err)
+		}
+		lastUsed[keyID] = usedAt
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating api key last usage: %w", err)
+	}
+
+	return lastUsed, nil
+}
+
+// GetFirstTrackedDay obtiene el primer día con uso registrado ("" si no hay datos)
+func (r *APIKeyUsageRepository) GetFirstTrackedDay(ctx context.Context) (string, error) {
+	var day sql.NullString
+	err := r.db.QueryRowContext(ctx, `SELECT MIN(day) FROM api_key_usage`).Scan(&day)
+	if err != nil {
+		return "", fmt.Errorf("failed to get first tracked day: %w", err)
+	}
+	return day.String, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// AssortmentJobRepository maneja la persistencia de jobs de clonación de surtido
+type AssortmentJobRepository struct {
+	db *sql.DB
+}
+
+// NewAssortmentJobRepository crea una nueva instancia del repositorio
+func NewAssortmentJobRepository(db *sql.DB) *AssortmentJobRepository {
+	return &AssortmentJobRepository{db: db}
+}
+
+// Create persiste un nuevo job
+func (r *AssortmentJobRepository) Create(ctx context.Context, job *domain.AssortmentCloneJob) error {
+	targets, err := json.Marshal(job.TargetStoreIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal target stores: %w", err)
+	}
+
+	query := `
+		INSERT INTO assortment_clone_jobs (id, source_store_id, target_store_ids, copy_thresholds, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		job.ID,
+		job.SourceStoreID,
+		string(targets),
+		job.CopyThresholds,
+		job.Status,
+		job.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create assortment clone job: %w", err)
+	}
+
+	return nil
+}
+
+// MarkRunning marca el job como en ejecución
+func (r *AssortmentJobRepository) MarkRunning(ctx context.Context, id string, startedAt time.Time) error {
+	query := `UPDATE assortment_clone_jobs SET status = ?, started_at = ? WHERE id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, domain.JobStatusRunning, startedAt, id); err != nil {
+		return fmt.Errorf("failed to mark assortment clone job as running: %w", err)
+	}
+
+	return nil
+}
+
+// Complete guarda el resultado final del job
+func (r *AssortmentJobRepository) Complete(ctx context.Context, job *domain.AssortmentCloneJob) error {
+	report, err := json.Marshal(job.Report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job report: %w", err)
+	}
+
+	query := `
+		UPDATE assortment_clone_jobs
+		SET status = ?, report = ?, error = ?, completed_at = ?
+		WHERE id = ?
+	`
+
+	_, err = r.db.ExecContext(ctx, query, job.Status, string(report), job.Error, job.CompletedAt, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to complete assortment clone job: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID obtiene un job por su ID
+func (r *AssortmentJobRepository) GetByID(ctx context.Context, id string) (*domain.AssortmentCloneJob, error) {
+	query := `
+		SELECT id, source_store_id, target_store_ids, copy_thresholds, status,
+		       COALESCE(report, ''), COALESCE(error, ''), created_at, started_at, completed_at
+		FROM assortment_clone_jobs
+		WHERE id = ?
+	`
+
+	var job domain.AssortmentCloneJob
+	var targets, report string
+	var startedAt, completedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&job.ID,
+		&job.SourceStoreID,
+		&targets,
+		&job.CopyThresholds,
+		&job.Status,
+		&report,
+		&job.Error,
+		&job.CreatedAt,
+		&startedAt,
+		&completedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "AssortmentCloneJob", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assortment clone job: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(targets), &job.TargetStoreIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal target stores: %w", err)
+	}
+	if report != "" {
+		if err := json.Unmarshal([]byte(report), &job.Report); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job report: %w", err)
+		}
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		job.CompletedAt = &completedAt.Time
+	}
+
+	return &job, nil
+}
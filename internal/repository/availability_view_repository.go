@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"inventory-system/internal/domain"
+)
+
+// availabilityProjection SELECT que calcula las filas del read model desde la tabla stock. El tramo
+// usa el mismo criterio que StockVisibility.Status sin configuración de tienda.
+const availabilityProjection = `
+	SELECT product_id, store_id,
+		quantity - reserved - safety_stock,
+		min_stock,
+		CASE
+			WHEN quantity - reserved - safety_stock <= 0 THEN 'out_of_stock'
+			WHEN quantity - reserved - safety_stock <= CASE WHEN min_stock > 0 THEN min_stock ELSE ? END THEN 'low_stock'
+			ELSE 'in_stock'
+		END,
+		CURRENT_TIMESTAMP
+	FROM stock`
+
+// AvailabilityViewRepository maneja el read model de disponibilidad (tabla availability_view).
+// Solo se escribe proyectando desde stock, así que cualquier refresco es idempotente.
+type AvailabilityViewRepository struct {
+	db *sql.DB
+}
+
+// NewAvailabilityViewRepository crea una nueva instancia del repositorio
+func NewAvailabilityViewRepository(db *sql.DB) *AvailabilityViewRepository {
+	return &AvailabilityViewRepository{db: db}
+}
+
+// RefreshProduct vuelve a proyectar las filas de un producto en todas sus tiendas. Las tiendas
+// cuya fila de stock ya no existe (archivado, producto eliminado) salen del read model.
+func (r *AvailabilityViewRepository) RefreshProduct(ctx context.Context, productID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM availability_view WHERE product_id = ?`, productID); err != nil {
+		return fmt.Errorf("failed to clear availability view: %w", err)
+	}
+	query := `INSERT INTO availability_view (product_id, store_id, available, min_stock, status, updated_at)` +
+		availabilityProjection + ` WHERE product_id = ?`
+	if _, err := tx.ExecContext(ctx, query, domain.DefaultMinStock, productID); err != nil {
+		return fmt.Errorf("failed to refresh availability view: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Rebuild reconstruye el read model completo desde stock. Retorna el número de filas.
+func (r *AvailabilityViewRepository) Rebuild(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM availability_view`); err != nil {
+		return 0, fmt.Errorf("failed to clear availability view: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, `INSERT INTO availability_view (product_id, store_id, available, min_stock, status, updated_at)`+
+		availabilityProjection, domain.DefaultMinStock)
+	if err != nil {
+		return 0, fmt.Errorf("failed to rebuild availability view: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return int(rows), nil
+}
+
+// Get obtiene la fila de un producto en una tienda (NotFoundError si no está proyectada)
+func (r *AvailabilityViewRepository) Get(ctx context.Context, productID, storeID string) (*domain.AvailabilityView, error) {
+	var view domain.AvailabilityView
+	err := r.db.QueryRowContext(ctx, `
+		SELECT product_id, store_id, available, min_stock, status, updated_at
+		FROM availability_view
+		WHERE product_id = ? AND store_id = ?
+	`, productID, storeID).Scan(
+		&view.ProductID,
+		&view.StoreID,
+		&view.Available,
+		&view.MinStock,
+		&view.Status,
+		&view.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "AvailabilityView", ID: fmt.Sprintf("product=%s, store=%s", productID, storeID)}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability view: %w", err)
+	}
+	return &view, nil
+}
+
+// GetLowStockItems equivale a StockRepository.GetLowStockItems filtrando y ordenando sobre el read
+// model; de stock solo se leen las filas de la página
+func (r *AvailabilityViewRepository) GetLowStockItems(ctx context.Context, filter domain.LowStockFilter) ([]*domain.Stock, error) {
+	var stocks []*domain.Stock
+	err := r.EachLowStockItem(ctx, filter, func(stock *domain.Stock) error {
+		stocks = append(stocks, stock)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// EachLowStockItem equivale a StockRepository.EachLowStockItem sobre el read model
+func (r *AvailabilityViewRepository) EachLowStockItem(ctx context.Context, filter domain.LowStockFilter, fn func(*domain.Stock) error) error {
+	where, args := lowStockViewClause(filter)
+	query := `
+		SELECT s.id, s.product_id, s.store_id, s.quantity, s.reserved, s.held, s.min_stock, s.max_stock, s.safety_stock, s.version, s.updated_at
+		FROM availability_view v
+		JOIN stock s ON s.product_id = v.product_id AND s.store_id = v.store_id` + where + `
+		ORDER BY ` + abcPriority + `, v.available ASC, v.store_id, v.product_id`
+
+	if filter.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get low stock items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stock domain.Stock
+		err := rows.Scan(
+			&stock.ID,
+			&stock.ProductID,
+			&stock.StoreID,
+			&stock.Quantity,
+			&stock.Reserved,
+			&stock.Held,
+			&stock.MinStock,
+			&stock.MaxStock,
+			&stock.SafetyStock,
+			&stock.Version,
+			&stock.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan stock: %w", err)
+		}
+		if err := fn(&stock); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating stocks: %w", err)
+	}
+
+	return nil
+}
+
+// CountLowStockItems cuenta las filas de stock bajo del filtro sobre el read model (ignora la paginación)
+func (r *AvailabilityViewRepository) CountLowStockItems(ctx context.Context, filter domain.LowStockFilter) (int, error) {
+	where, args := lowStockViewClause(filter)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM availability_view v`+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count low stock items: %w", err)
+	}
+
+	return count, nil
+}
+
+// lowStockViewClause es lowStockClause sobre el read model: la condición usa la columna available
+// indexada en lugar de calcular la cantidad vendible sobre stock
+func lowStockViewClause(filter domain.LowStockFilter) (string, []interface{}) {
+	clause := "\n\t\tJOIN products p ON p.id = v.product_id"
+
+	conditions := []string{"v.available < CASE WHEN v.min_stock > 0 THEN v.min_stock ELSE ? END"}
+	args := []interface{}{filter.Threshold}
+
+	if filter.StoreID != "" {
+		conditions = append(conditions, "v.store_id = ?")
+		args = append(args, filter.StoreID)
+	}
+	if filter.Category != "" {
+		conditions = append(conditions, "p.category = ?")
+		args = append(args, filter.Category)
+	}
+	if filter.ABCClass != domain.ABCClassNone {
+		conditions = append(conditions, "p.abc_class = ?")
+		args = append(args, filter.ABCClass)
+	}
+
+	return clause + "\n\t\tWHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// BackupRepository genera copias de la base de datos en caliente
+type BackupRepository struct {
+	db *sql.DB
+}
+
+// NewBackupRepository crea una nueva instancia del repositorio
+func NewBackupRepository(db *sql.DB) *BackupRepository {
+	return &BackupRepository{db: db}
+}
+
+// VacuumInto escribe una copia consistente y compactada de la base de datos en path (que no debe
+// existir). Las lecturas siguen funcionando mientras tanto; las escrituras esperan a que termine.
+func (r *BackupRepository) VacuumInto(ctx context.Context, path string) error {
+	if _, err := r.db.ExecContext(ctx, `VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// ChannelAllocationRepository maneja las asignaciones de stock por canal de venta.
+// Las reservas descuentan de ellas desde StockRepository (Reserve/Release/ConfirmChannelStock).
+type ChannelAllocationRepository struct {
+	db *sql.DB
+}
+
+// NewChannelAllocationRepository crea una nueva instancia del repositorio
+func NewChannelAllocationRepository(db *sql.DB) *ChannelAllocationRepository {
+	return &ChannelAllocationRepository{db: db}
+}
+
+// queryer lo implementan *sql.DB y *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// queryChannelAllocations obtiene las asignaciones de una fila de stock ordenadas por canal
+func queryChannelAllocations(ctx context.Context, q queryer, productID, storeID string) ([]*domain.ChannelAllocation, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT product_id, store_id, channel, allocated, reserved, updated_at
+		FROM channel_allocations
+		WHERE product_id = ? AND store_id = ?
+		ORDER BY channel
+	`, productID, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel allocations: %w", err)
+	}
+	defer rows.Close()
+
+	allocations := []*domain.ChannelAllocation{}
+	for rows.Next() {
+		var allocation domain.ChannelAllocation
+		err := rows.Scan(
+			&allocation.ProductID,
+			&allocation.StoreID,
+			&allocation.Channel,
+			&allocation.Allocated,
+			&allocation.Reserved,
+			&allocation.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan channel allocation: %w", err)
+		}
+		allocations = append(allocations, &allocation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating channel allocations: %w", err)
+	}
+
+	return allocations, nil
+}
+
+// ListByStock obtiene la fila de stock y sus asignaciones por canal
+func (r *ChannelAllocationRepository) ListByStock(ctx context.Context, productID, storeID string) (*domain.Stock, []*domain.ChannelAllocation, error) {
+	stock, err := NewStockRepository(r.db).GetByProductAndStore(ctx, productID, storeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	allocations, err := queryChannelAllocations(ctx, r.db, productID, storeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stock, allocations, nil
+}
+
+// Set fija las unidades asignadas a un canal. La asignación no puede quedar por debajo de lo que el
+// canal ya tiene reservado contra ella, y lo asignado sin reservar de todos los canales debe caber
+// en la cantidad vendible de la fila (se comprueba en la misma transacción).
+func (r *ChannelAllocationRepository) Set(ctx context.Context, productID, storeID string, channel domain.SalesChannel, allocated int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stock, allocations, err := lockChannelAllocations(ctx, tx, productID, storeID)
+	if err != nil {
+		return err
+	}
+
+	reserved := 0
+	outstanding := allocated
+	for _, allocation := range allocations {
+		if allocation.Channel == channel {
+			reserved = allocation.Reserved
+			outstanding -= reserved
+			continue
+		}
+		outstanding += max(allocation.Remaining(), 0)
+	}
+
+	if allocated < reserved {
+		return &domain.ConflictError{
+			Message: fmt.Sprintf("channel %s already has %d units reserved against its allocation", channel, reserved),
+		}
+	}
+	if outstanding > stock.Sellable() {
+		return &domain.InsufficientStockError{
+			ProductID: productID,
+			StoreID:   storeID,
+			Available: stock.Sellable(),
+			Requested: outstanding,
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO channel_allocations (product_id, store_id, channel, allocated, reserved, updated_at)
+		VALUES (?, ?, ?, ?, 0, CURRENT_TIMESTAMP)
+		ON CONFLICT(product_id, store_id, channel) DO UPDATE SET
+			allocated = excluded.allocated,
+			updated_at = excluded.updated_at
+	`, productID, storeID, channel, allocated)
+	if err != nil {
+		return fmt.Errorf("failed to save channel allocation: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Delete elimina la asignación de un canal: sus unidades vuelven a la parte no asignada
+func (r *ChannelAllocationRepository) Delete(ctx context.Context, productID, storeID string, channel domain.SalesChannel) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM channel_allocations WHERE product_id = ? AND store_id = ? AND channel = ?
+	`, productID, storeID, channel)
+	if err != nil {
+		return fmt.Errorf("failed to delete channel allocation: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{
+			Resource: "ChannelAllocation",
+			ID:       fmt.Sprintf("product=%s, store=%s, channel=%s", productID, storeID, channel),
+		}
+	}
+	return nil
+}
+
+// Transfer mueve unidades asignadas sin reservar de un canal a otro (creando la asignación de
+// destino si no existe)
+func (r *ChannelAllocationRepository) Transfer(ctx context.Context, productID, storeID string, from, to domain.SalesChannel, quantity int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, allocations, err := lockChannelAllocations(ctx, tx, productID, storeID)
+	if err != nil {
+		return err
+	}
+
+	source := domain.FindChannelAllocation(allocations, from)
+	if source == nil {
+		return &domain.NotFoundError{
+			Resource: "ChannelAllocation",
+			ID:       fmt.Sprintf("product=%s, store=%s, channel=%s", productID, storeID, from),
+		}
+	}
+	if source.Remaining() < quantity {
+		return &domain.InsufficientStockError{
+			ProductID: productID,
+			StoreID:   storeID,
+			Available: source.Remaining(),
+			Requested: quantity,
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE channel_allocations
+		SET allocated = allocated - ?,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE product_id = ? AND store_id = ? AND channel = ?
+	`, quantity, productID, storeID, from)
+	if err != nil {
+		return fmt.Errorf("failed to update channel allocation: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO channel_allocations (product_id, store_id, channel, allocated, reserved, updated_at)
+		VALUES (?, ?, ?, ?, 0, CURRENT_TIMESTAMP)
+		ON CONFLICT(product_id, store_id, channel) DO UPDATE SET
+			allocated = allocated + excluded.allocated,
+			updated_at = excluded.updated_at
+	`, productID, storeID, to, quantity)
+	if err != nil {
+		return fmt.Errorf("failed to update channel allocation: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// lockChannelAllocations lee la fila de stock y sus asignaciones dentro de la transacción
+func lockChannelAllocations(ctx context.Context, tx *sql.Tx, productID, storeID string) (*domain.Stock, []*domain.ChannelAllocation, error) {
+	var stock domain.Stock
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, product_id, store_id, quantity, reserved, safety_stock
+		FROM stock
+		WHERE product_id = ? AND store_id = ?
+	`, productID, storeID).Scan(
+		&stock.ID,
+		&stock.ProductID,
+		&stock.StoreID,
+		&stock.Quantity,
+		&stock.Reserved,
+		&stock.SafetyStock,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil, &domain.NotFoundError{
+			Resource: "Stock",
+			ID:       fmt.Sprintf("product=%s, store=%s", productID, storeID),
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lock stock: %w", err)
+	}
+
+	allocations, err := queryChannelAllocations(ctx, tx, productID, storeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &stock, allocations, nil
+}
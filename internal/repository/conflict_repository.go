@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// ConflictRepository maneja las operaciones de persistencia para conflictos de sincronización
+type ConflictRepository struct {
+	db *sql.DB
+}
+
+// NewConflictRepository crea una nueva instancia del repositorio
+func NewConflictRepository(db *sql.DB) *ConflictRepository {
+	return &ConflictRepository{db: db}
+}
+
+// Create registra un nuevo conflicto
+func (r *ConflictRepository) Create(ctx context.Context, conflict *domain.StockConflict) error {
+	query := `
+		INSERT INTO conflicts (id, product_id, store_id, origin_instance, strategy, status,
+			local_version, local_quantity, local_reserved, remote_base_version, remote_quantity, remote_delta,
+			resolved_quantity, reason, created_at, resolved_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		conflict.ID,
+		conflict.ProductID,
+		conflict.StoreID,
+		conflict.OriginInstance,
+		conflict.Strategy,
+		conflict.Status,
+		conflict.LocalVersion,
+		conflict.LocalQuantity,
+		conflict.LocalReserved,
+		conflict.RemoteBase,
+		conflict.RemoteQuantity,
+		conflict.RemoteDelta,
+		conflict.ResolvedQuantity,
+		conflict.Reason,
+		conflict.CreatedAt,
+		conflict.ResolvedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create conflict: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID obtiene un conflicto por su ID
+func (r *ConflictRepository) GetByID(ctx context.Context, id string) (*domain.StockConflict, error) {
+	query := `
+		SELECT id, product_id, store_id, origin_instance, strategy, status,
+			local_version, local_quantity, local_reserved, remote_base_version, remote_quantity, remote_delta,
+			resolved_quantity, reason, created_at, resolved_at
+		FROM conflicts
+		WHERE id = ?
+	`
+
+	conflict, err := scanConflict(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "Conflict", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conflict: %w", err)
+	}
+
+	return conflict, nil
+}
+
+// ListByStatus lista conflictos por estado (opcionalmente filtrados por tienda)
+func (r *ConflictRepository) ListByStatus(ctx context.Context, status domain.ConflictStatus, storeID string, limit, offset int) ([]*domain.StockConflict, error) {
+	query := `
+		SELECT id, product_id, store_id, origin_instance, strategy, status,
+			local_version, local_quantity, local_reserved, remote_base_version, remote_quantity, remote_delta,
+			resolved_quantity, reason, created_at, resolved_at
+		FROM conflicts
+		WHERE status = ? AND (? = '' OR store_id = ?)
+		ORDER BY created_at ASC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, status, storeID, storeID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []*domain.StockConflict
+	for rows.Next() {
+		conflict, err := scanConflict(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan conflict: %w", err)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating conflicts: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+// MarkResolved marca un conflicto como resuelto manualmente
+func (r *ConflictRepository) MarkResolved(ctx context.Context, id string, resolvedQuantity int, reason string) error {
+	query := `
+		UPDATE conflicts
+		SET status = ?,
+		    resolved_quantity = ?,
+		    reason = ?,
+		    resolved_at = ?
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		domain.ConflictStatusResolved,
+		resolvedQuantity,
+		reason,
+		time.Now(),
+		id,
+		domain.ConflictStatusUnresolved,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to resolve conflict: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &domain.NotFoundError{Resource: "Unresolved conflict", ID: id}
+	}
+
+	return nil
+}
+
+// rowScanner abstrae *sql.Row y *sql.Rows para reutilizar el escaneo
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanConflict(row rowScanner) (*domain.StockConflict, error) {
+	var conflict domain.StockConflict
+	var resolvedQuantity sql.NullInt64
+	var reason sql.NullString
+	var resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&conflict.ID,
+		&conflict.ProductID,
+		&conflict.StoreID,
+		&conflict.OriginInstance,
+		&conflict.Strategy,
+		&conflict.Status,
+		&conflict.LocalVersion,
+		&conflict.LocalQuantity,
+		&conflict.LocalReserved,
+		&conflict.RemoteBase,
+		&conflict.RemoteQuantity,
+		&conflict.RemoteDelta,
+		&resolvedQuantity,
+		&reason,
+		&conflict.CreatedAt,
+		&resolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolvedQuantity.Valid {
+		q := int(resolvedQuantity.Int64)
+		conflict.ResolvedQuantity = &q
+	}
+	conflict.Reason = reason.String
+	if resolvedAt.Valid {
+		conflict.ResolvedAt = &resolvedAt.Time
+	}
+
+	return &conflict, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"inventory-system/internal/domain"
@@ -19,14 +21,52 @@ func NewEventRepository(db *sql.DB) *EventRepository {
 	return &EventRepository{db: db}
 }
 
-// Save guarda un nuevo evento
+// chainMu serializa las escrituras de eventos para mantener la cadena de auditoría
+// (lectura del último hash + inserción deben ser atómicas dentro del proceso)
+var chainMu sync.Mutex
+
+// Save guarda un nuevo evento encadenándolo al anterior (seq, prev_hash, hash)
 func (r *EventRepository) Save(ctx context.Context, event *domain.Event) error {
+	domain.AttachCorrelationID(ctx, event)
+	domain.AttachActor(ctx, event)
+
+	chainMu.Lock()
+	defer chainMu.Unlock()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var lastSeq int64
+	var prevHash string
+	err = tx.QueryRowContext(ctx, `
+		SELECT seq, COALESCE(hash, '')
+		FROM events
+		WHERE seq IS NOT NULL
+		ORDER BY seq DESC
+		LIMIT 1
+	`).Scan(&lastSeq, &prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get audit chain head: %w", err)
+	}
+
+	event.Seq = lastSeq + 1
+	event.PrevHash = prevHash
+	event.Hash = domain.ComputeEventHash(event, prevHash)
+
+	var correlationID interface{}
+	if event.CorrelationID != "" {
+		correlationID = event.CorrelationID
+	}
+
 	query := `
-		INSERT INTO events (id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO events (id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, seq, prev_hash, hash, correlation_id, actor)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		event.ID,
 		event.EventType,
 		event.AggregateID,
@@ -35,19 +75,110 @@ func (r *EventRepository) Save(ctx context.Context, event *domain.Event) error {
 		event.Payload,
 		event.CreatedAt,
 		event.Synced,
+		event.Seq,
+		event.PrevHash,
+		event.Hash,
+		correlationID,
+		event.Actor,
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to save event: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit event: %w", err)
+	}
+
 	return nil
 }
 
+// GetChainRange obtiene los eventos encadenados con fromSeq <= seq <= toSeq, en orden
+func (r *EventRepository) GetChainRange(ctx context.Context, fromSeq, toSeq int64) ([]*domain.Event, error) {
+	query := `
+		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at,
+		       seq, COALESCE(prev_hash, ''), COALESCE(hash, '')
+		FROM events
+		WHERE seq IS NOT NULL AND seq >= ? AND seq <= ?
+		ORDER BY seq ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, fromSeq, toSeq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		var event domain.Event
+		err := rows.Scan(
+			&event.ID,
+			&event.EventType,
+			&event.AggregateID,
+			&event.AggregateType,
+			&event.StoreID,
+			&event.Payload,
+			&event.CreatedAt,
+			&event.Seq,
+			&event.PrevHash,
+			&event.Hash,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit chain: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetChainHead obtiene el seq y hash del último evento encadenado (0, "" si la cadena está vacía)
+func (r *EventRepository) GetChainHead(ctx context.Context) (int64, string, error) {
+	return r.chainLink(ctx, `
+		SELECT seq, COALESCE(hash, '')
+		FROM events
+		WHERE seq IS NOT NULL
+		ORDER BY seq DESC
+		LIMIT 1
+	`)
+}
+
+// GetChainLinkBefore obtiene el seq y hash del evento encadenado inmediatamente anterior a seq
+func (r *EventRepository) GetChainLinkBefore(ctx context.Context, seq int64) (int64, string, error) {
+	return r.chainLink(ctx, `
+		SELECT seq, COALESCE(hash, '')
+		FROM events
+		WHERE seq IS NOT NULL AND seq < ?
+		ORDER BY seq DESC
+		LIMIT 1
+	`, seq)
+}
+
+func (r *EventRepository) chainLink(ctx context.Context, query string, args ...interface{}) (int64, string, error) {
+	var seq int64
+	var hash string
+
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&seq, &hash)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to get audit chain link: %w", err)
+	}
+
+	return seq, hash, nil
+}
+
 // GetByID obtiene un evento por su ID
 func (r *EventRepository) GetByID(ctx context.Context, id string) (*domain.Event, error) {
 	query := `
-		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at
+		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at,
+		       COALESCE(correlation_id, ''), COALESCE(actor, '')
 		FROM events
 		WHERE id = ?
 	`
@@ -65,6 +196,8 @@ func (r *EventRepository) GetByID(ctx context.Context, id string) (*domain.Event
 		&event.CreatedAt,
 		&event.Synced,
 		&syncedAt,
+		&event.CorrelationID,
+		&event.Actor,
 	)
 
 	if err == sql.ErrNoRows {
@@ -87,7 +220,8 @@ func (r *EventRepository) GetByID(ctx context.Context, id string) (*domain.Event
 // GetPendingEvents obtiene todos los eventos que no han sido sincronizados
 func (r *EventRepository) GetPendingEvents(ctx context.Context, limit int) ([]*domain.Event, error) {
 	query := `
-		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at
+		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at,
+		       COALESCE(correlation_id, ''), COALESCE(actor, '')
 		FROM events
 		WHERE synced = false
 		ORDER BY created_at ASC
@@ -115,6 +249,8 @@ func (r *EventRepository) GetPendingEvents(ctx context.Context, limit int) ([]*d
 			&event.CreatedAt,
 			&event.Synced,
 			&syncedAt,
+			&event.CorrelationID,
+			&event.Actor,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
@@ -137,7 +273,8 @@ func (r *EventRepository) GetPendingEvents(ctx context.Context, limit int) ([]*d
 // GetByAggregateID obtiene todos los eventos de un agregado específico (Product o Stock)
 func (r *EventRepository) GetByAggregateID(ctx context.Context, aggregateID string) ([]*domain.Event, error) {
 	query := `
-		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at
+		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at,
+		       COALESCE(correlation_id, ''), COALESCE(actor, '')
 		FROM events
 		WHERE aggregate_id = ?
 		ORDER BY created_at ASC
@@ -164,6 +301,8 @@ func (r *EventRepository) GetByAggregateID(ctx context.Context, aggregateID stri
 			&event.CreatedAt,
 			&event.Synced,
 			&syncedAt,
+			&event.CorrelationID,
+			&event.Actor,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
@@ -186,7 +325,8 @@ func (r *EventRepository) GetByAggregateID(ctx context.Context, aggregateID stri
 // GetByStore obtiene todos los eventos de una tienda
 func (r *EventRepository) GetByStore(ctx context.Context, storeID string, limit, offset int) ([]*domain.Event, error) {
 	query := `
-		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at
+		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at,
+		       COALESCE(correlation_id, ''), COALESCE(actor, '')
 		FROM events
 		WHERE store_id = ?
 		ORDER BY created_at DESC
@@ -214,6 +354,8 @@ func (r *EventRepository) GetByStore(ctx context.Context, storeID string, limit,
 			&event.CreatedAt,
 			&event.Synced,
 			&syncedAt,
+			&event.CorrelationID,
+			&event.Actor,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
@@ -233,6 +375,152 @@ func (r *EventRepository) GetByStore(ctx context.Context, storeID string, limit,
 	return events, nil
 }
 
+// EachEvent recorre los eventos del filtro por orden de seq sin acumularlos en memoria
+// (listados JSON en streaming). Se detiene en el primer error de fn.
+func (r *EventRepository) EachEvent(ctx context.Context, filter domain.EventFilter, fn func(*domain.Event) error) error {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.MovementsOnly {
+		conditions = append(conditions, "aggregate_type = 'stock'", "event_type <> ?")
+		args = append(args, domain.EventStockSnapshot)
+	}
+	if filter.StoreID != "" {
+		conditions = append(conditions, "store_id = ?")
+		args = append(args, filter.StoreID)
+	}
+	if filter.ProductID != "" {
+		conditions = append(conditions, "aggregate_id = ?")
+		args = append(args, filter.ProductID)
+	}
+	if filter.EventType != "" {
+		conditions = append(conditions, "event_type = ?")
+		args = append(args, filter.EventType)
+	}
+	if filter.AfterSeq > 0 {
+		conditions = append(conditions, "seq > ?")
+		args = append(args, filter.AfterSeq)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.To)
+	}
+
+	query := `
+		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at,
+		       COALESCE(seq, 0), COALESCE(correlation_id, ''), COALESCE(actor, '')
+		FROM events
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY seq ASC, created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.Event
+		var syncedAt sql.NullTime
+
+		err := rows.Scan(
+			&event.ID,
+			&event.EventType,
+			&event.AggregateID,
+			&event.AggregateType,
+			&event.StoreID,
+			&event.Payload,
+			&event.CreatedAt,
+			&event.Synced,
+			&syncedAt,
+			&event.Seq,
+			&event.CorrelationID,
+			&event.Actor,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		if syncedAt.Valid {
+			event.SyncedAt = &syncedAt.Time
+		}
+
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return nil
+}
+
+// EachStockMovement recorre los eventos que afectan a una fila de stock (stock y reservas del
+// producto en la tienda), del más reciente al más antiguo. Se detiene en el primer error de fn.
+func (r *EventRepository) EachStockMovement(ctx context.Context, productID, storeID string, fn func(*domain.Event) error) error {
+	query := `
+		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at,
+		       COALESCE(seq, 0), COALESCE(correlation_id, ''), COALESCE(actor, '')
+		FROM events
+		WHERE (aggregate_type = 'stock' AND aggregate_id = ?
+		       AND (store_id = ? OR (event_type = ? AND json_extract(payload, '$.to_store_id') = ?)))
+		   OR (aggregate_type = 'reservation' AND store_id = ? AND json_extract(payload, '$.product_id') = ?)
+		ORDER BY COALESCE(seq, 0) DESC, created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		productID, storeID, domain.EventStockTransferred, storeID,
+		storeID, productID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get stock movements: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.Event
+		var syncedAt sql.NullTime
+
+		err := rows.Scan(
+			&event.ID,
+			&event.EventType,
+			&event.AggregateID,
+			&event.AggregateType,
+			&event.StoreID,
+			&event.Payload,
+			&event.CreatedAt,
+			&event.Synced,
+			&syncedAt,
+			&event.Seq,
+			&event.CorrelationID,
+			&event.Actor,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		if syncedAt.Valid {
+			event.SyncedAt = &syncedAt.Time
+		}
+
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating stock movements: %w", err)
+	}
+
+	return nil
+}
+
 // MarkAsSynced marca un evento como sincronizado
 func (r *EventRepository) MarkAsSynced(ctx context.Context, eventID string) error {
 	query := `
@@ -301,7 +589,9 @@ func (r *EventRepository) MarkMultipleAsSynced(ctx context.Context, eventIDs []s
 	return nil
 }
 
-// DeleteOldSynced elimina eventos sincronizados antiguos (limpieza periódica)
+// DeleteOldSynced elimina eventos sincronizados antiguos (limpieza periódica).
+// Los eventos forman la cadena de auditoría: borrar registros intermedios hace que
+// la verificación reporte huecos en la secuencia.
 func (r *EventRepository) DeleteOldSynced(ctx context.Context, olderThan time.Time) (int64, error) {
 	query := `
 		DELETE FROM events
@@ -322,6 +612,143 @@ func (r *EventRepository) DeleteOldSynced(ctx context.Context, olderThan time.Ti
 	return rowsAffected, nil
 }
 
+// GetRetentionBoundary obtiene el último seq que la retención puede borrar: el prefijo de la cadena
+// formado solo por eventos publicados anteriores a olderThan. El último evento encadenado nunca se
+// borra porque Save lo usa como cabeza de la cadena. Retorna 0 si no hay nada que borrar.
+func (r *EventRepository) GetRetentionBoundary(ctx context.Context, olderThan time.Time) (int64, error) {
+	headSeq, _, err := r.GetChainHead(ctx)
+	if err != nil || headSeq == 0 {
+		return 0, err
+	}
+
+	var firstKept int64
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(MIN(seq), 0)
+		FROM events
+		WHERE seq IS NOT NULL AND (synced = false OR created_at >= ?)
+	`, olderThan).Scan(&firstKept)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get retention boundary: %w", err)
+	}
+
+	if firstKept == 0 || firstKept > headSeq {
+		return headSeq - 1, nil
+	}
+	return firstKept - 1, nil
+}
+
+// ListChainPrefix obtiene hasta limit eventos con seq <= uptoSeq, del más antiguo al más reciente
+func (r *EventRepository) ListChainPrefix(ctx context.Context, uptoSeq int64, limit int) ([]*domain.Event, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at,
+		       seq, COALESCE(prev_hash, ''), COALESCE(hash, ''), COALESCE(correlation_id, ''), COALESCE(actor, '')
+		FROM events
+		WHERE seq IS NOT NULL AND seq <= ?
+		ORDER BY seq ASC
+		LIMIT ?
+	`, uptoSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		var event domain.Event
+		var syncedAt sql.NullTime
+
+		err := rows.Scan(
+			&event.ID,
+			&event.EventType,
+			&event.AggregateID,
+			&event.AggregateType,
+			&event.StoreID,
+			&event.Payload,
+			&event.CreatedAt,
+			&event.Synced,
+			&syncedAt,
+			&event.Seq,
+			&event.PrevHash,
+			&event.Hash,
+			&event.CorrelationID,
+			&event.Actor,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		if syncedAt.Valid {
+			event.SyncedAt = &syncedAt.Time
+		}
+
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return events, nil
+}
+
+// CountChainPrefix cuenta los eventos con seq <= uptoSeq
+func (r *EventRepository) CountChainPrefix(ctx context.Context, uptoSeq int64) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events WHERE seq IS NOT NULL AND seq <= ?`, uptoSeq).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count events: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteChainPrefix borra los eventos con seq <= checkpoint.Seq y guarda el checkpoint en la misma
+// transacción, de modo que la verificación de la cadena continúe desde el último eslabón borrado
+func (r *EventRepository) DeleteChainPrefix(ctx context.Context, checkpoint domain.AuditCheckpoint) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM events WHERE seq IS NOT NULL AND seq <= ?`, checkpoint.Seq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete events: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_checkpoints (seq, hash, created_at) VALUES (?, ?, ?)
+		ON CONFLICT(seq) DO NOTHING
+	`, checkpoint.Seq, checkpoint.Hash, checkpoint.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save audit checkpoint: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// GetAuditCheckpoint obtiene el checkpoint más reciente de la cadena (nil si nunca se borró nada)
+func (r *EventRepository) GetAuditCheckpoint(ctx context.Context) (*domain.AuditCheckpoint, error) {
+	var checkpoint domain.AuditCheckpoint
+	err := r.db.QueryRowContext(ctx, `
+		SELECT seq, hash, created_at FROM audit_checkpoints ORDER BY seq DESC LIMIT 1
+	`).Scan(&checkpoint.Seq, &checkpoint.Hash, &checkpoint.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
 // CountPending cuenta eventos pendientes de sincronización
 func (r *EventRepository) CountPending(ctx context.Context) (int, error) {
 	query := `SELECT COUNT(*) FROM events WHERE synced = false`
@@ -338,7 +765,8 @@ func (r *EventRepository) CountPending(ctx context.Context) (int, error) {
 // GetEventsByType obtiene eventos de un tipo específico
 func (r *EventRepository) GetEventsByType(ctx context.Context, eventType string, limit, offset int) ([]*domain.Event, error) {
 	query := `
-		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at
+		SELECT id, event_type, aggregate_id, aggregate_type, store_id, payload, created_at, synced, synced_at,
+		       COALESCE(correlation_id, ''), COALESCE(actor, '')
 		FROM events
 		WHERE event_type = ?
 		ORDER BY created_at DESC
@@ -366,6 +794,8 @@ func (r *EventRepository) GetEventsByType(ctx context.Context, eventType string,
 			&event.CreatedAt,
 			&event.Synced,
 			&syncedAt,
+			&event.CorrelationID,
+			&event.Actor,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// ExportRepository maneja los jobs de exportación y las consultas que generan sus filas
+type ExportRepository struct {
+	db *sql.DB
+}
+
+// NewExportRepository crea una nueva instancia del repositorio
+func NewExportRepository(db *sql.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+const exportJobColumns = `id, type, format, filters, status, actor, row_count, size_bytes, blob_key, error, created_at, started_at, completed_at, expires_at`
+
+// Create persiste un nuevo job de exportación
+func (r *ExportRepository) Create(ctx context.Context, job *domain.ExportJob) error {
+	filters, err := json.Marshal(job.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal export filters: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO export_jobs (id, type, format, filters, status, actor, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, job.ID, job.Type, job.Format, string(filters), job.Status, job.Actor, job.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID obtiene un job de exportación
+func (r *ExportRepository) GetByID(ctx context.Context, id string) (*domain.ExportJob, error) {
+	job, err := scanExportJob(r.db.QueryRowContext(ctx, `SELECT `+exportJobColumns+` FROM export_jobs WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "Export", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export job: %w", err)
+	}
+	return job, nil
+}
+
+// ClaimNext marca como RUNNING el job pendiente más antiguo y lo retorna (nil si no hay ninguno).
+// También recupera los jobs RUNNING iniciados antes de staleBefore (la instancia que los
+// generaba se detuvo). La actualización condicional evita que dos instancias tomen el mismo job.
+func (r *ExportRepository) ClaimNext(ctx context.Context, now, staleBefore time.Time) (*domain.ExportJob, error) {
+	for {
+		var id string
+		err := r.db.QueryRowContext(ctx, `
+			SELECT id FROM export_jobs
+			WHERE status = ? OR (status = ? AND started_at < ?)
+			ORDER BY created_at ASC
+			LIMIT 1
+		`, domain.JobStatusPending, domain.JobStatusRunning, staleBefore).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pending export job: %w", err)
+		}
+
+		result, err := r.db.ExecContext(ctx, `
+			UPDATE export_jobs SET status = ?, started_at = ?
+			WHERE id = ? AND (status = ? OR (status = ? AND started_at < ?))
+		`, domain.JobStatusRunning, now, id, domain.JobStatusPending, domain.JobStatusRunning, staleBefore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim export job: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			continue // Lo tomó otra instancia
+		}
+
+		return r.GetByID(ctx, id)
+	}
+}
+
+// Complete guarda el resultado final del job (COMPLETED o FAILED)
+func (r *ExportRepository) Complete(ctx context.Context, job *domain.ExportJob) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET status = ?, row_count = ?, size_bytes = ?, blob_key = ?, error = ?, completed_at = ?, expires_at = ?
+		WHERE id = ?
+	`, job.Status, job.Rows, job.SizeBytes, job.BlobKey, job.Error, job.CompletedAt, job.ExpiresAt, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to complete export job: %w", err)
+	}
+	return nil
+}
+
+// ListExpired obtiene los jobs cuya retención terminó
+func (r *ExportRepository) ListExpired(ctx context.Context, now time.Time) ([]*domain.ExportJob, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+exportJobColumns+`
+		FROM export_jobs
+		WHERE expires_at IS NOT NULL AND expires_at <= ?
+	`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired export jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*domain.ExportJob, 0)
+	for rows.Next() {
+		job, err := scanExportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan export job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating export jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// Delete elimina un job de exportación
+func (r *ExportRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM export_jobs WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete export job: %w", err)
+	}
+	return nil
+}
+
+// WriteRows recorre las filas de la exportación en el orden de domain.ExportColumns y llama a
+// emit con cada una. Los valores son string, int, time.Time, *time.Time o json.RawMessage.
+// Retorna el número de filas emitidas.
+func (r *ExportRepository) WriteRows(ctx context.Context, job *domain.ExportJob, emit func([]interface{}) error) (int, error) {
+	query, args := exportQuery(job)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query export rows: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		values, err := scanExportRow(job.Type, rows)
+		if err != nil {
+			return count, fmt.Errorf("failed to scan export row: %w", err)
+		}
+		if err := emit(values); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if err = rows.Err(); err != nil {
+		return count, fmt.Errorf("error iterating export rows: %w", err)
+	}
+
+	return count, nil
+}
+
+// exportQuery construye la consulta parametrizada de cada tipo de exportación
+func exportQuery(job *domain.ExportJob) (string, []interface{}) {
+	filters := job.Filters
+
+	switch job.Type {
+	case domain.ExportMovements:
+		// Los stock.snapshot del backfill son fotos del estado, no movimientos
+		conditions := []string{"aggregate_type = 'stock'", "event_type <> ?"}
+		args := []interface{}{domain.EventStockSnapshot}
+		if filters.StoreID != "" {
+			conditions = append(conditions, "store_id = ?")
+			args = append(args, filters.StoreID)
+		}
+		if filters.ProductID != "" {
+			conditions = append(conditions, "aggregate_id = ?")
+			args = append(args, filters.ProductID)
+		}
+		if filters.From != nil {
+			conditions = append(conditions, "created_at >= ?")
+			args = append(args, *filters.From)
+		}
+		if filters.To != nil {
+			conditions = append(conditions, "created_at < ?")
+			args = append(args, *filters.To)
+		}
+		return `
+			SELECT id, created_at, event_type, aggregate_id, store_id, COALESCE(correlation_id, ''), payload
+			FROM events
+			WHERE ` + strings.Join(conditions, " AND ") + `
+			ORDER BY created_at ASC, seq ASC
+		`, args
+
+	case domain.ExportReservations:
+		where, args := reservationFilterClause(domain.ReservationFilter{
+			ProductID:   filters.ProductID,
+			StoreID:     filters.StoreID,
+			Status:      filters.Status,
+			CreatedFrom: filters.From,
+			CreatedTo:   filters.To,
+		})
+		return `
+			SELECT id, product_id, store_id, customer_id, quantity, status, priority, channel, created_at, expires_at, confirmed_at
+			FROM reservations` + where + `
+			ORDER BY created_at ASC, id ASC
+		`, args
+	}
+
+	var conditions []string
+	var args []interface{}
+	if filters.StoreID != "" {
+		conditions = append(conditions, "s.store_id = ?")
+		args = append(args, filters.StoreID)
+	}
+	if filters.ProductID != "" {
+		conditions = append(conditions, "s.product_id = ?")
+		args = append(args, filters.ProductID)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return `
+		SELECT s.product_id, p.sku, s.store_id, s.quantity, s.reserved, s.safety_stock, s.min_stock, s.max_stock, s.updated_at
+		FROM stock s
+		JOIN products p ON p.id = s.product_id` + where + `
+		ORDER BY s.store_id ASC, p.sku ASC
+	`, args
+}
+
+// scanExportRow escanea una fila de la consulta de exportación en el orden de sus columnas
+func scanExportRow(exportType domain.ExportType, row rowScanner) ([]interface{}, error) {
+	switch exportType {
+	case domain.ExportMovements:
+		var id, eventType, productID, storeID, correlationID, payload string
+		var createdAt time.Time
+		if err := row.Scan(&id, &createdAt, &eventType, &productID, &storeID, &correlationID, &payload); err != nil {
+			return nil, err
+		}
+		return []interface{}{id, createdAt, eventType, productID, storeID, correlationID, json.RawMessage(payload)}, nil
+
+	case domain.ExportReservations:
+		var reservation domain.Reservation
+		var confirmedAt sql.NullTime
+		err := row.Scan(&reservation.ID, &reservation.ProductID, &reservation.StoreID, &reservation.CustomerID,
+			&reservation.Quantity, &reservation.Status, &reservation.Priority, &reservation.Channel,
+			&reservation.CreatedAt, &reservation.ExpiresAt, &confirmedAt)
+		if err != nil {
+			return nil, err
+		}
+		if confirmedAt.Valid {
+			reservation.ConfirmedAt = &confirmedAt.Time
+		}
+		return []interface{}{reservation.ID, reservation.ProductID, reservation.StoreID, reservation.CustomerID,
+			reservation.Quantity, string(reservation.Status), string(reservation.Priority), string(reservation.Channel),
+			reservation.CreatedAt, reservation.ExpiresAt, reservation.ConfirmedAt}, nil
+	}
+
+	var stock domain.Stock
+	var sku string
+	err := row.Scan(&stock.ProductID, &sku, &stock.StoreID, &stock.Quantity, &stock.Reserved, &stock.SafetyStock,
+		&stock.MinStock, &stock.MaxStock, &stock.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{stock.ProductID, sku, stock.StoreID, stock.Quantity, stock.Reserved, stock.SafetyStock,
+		stock.Available(), stock.MinStock, stock.MaxStock, stock.UpdatedAt}, nil
+}
+
+// scanExportJob escanea una fila de export_jobs
+func scanExportJob(row rowScanner) (*domain.ExportJob, error) {
+	var job domain.ExportJob
+	var filters string
+	var startedAt, completedAt, expiresAt sql.NullTime
+
+	err := row.Scan(
+		&job.ID,
+		&job.Type,
+		&job.Format,
+		&filters,
+		&job.Status,
+		&job.Actor,
+		&job.Rows,
+		&job.SizeBytes,
+		&job.BlobKey,
+		&job.Error,
+		&job.CreatedAt,
+		&startedAt,
+		&completedAt,
+		&expiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(filters), &job.Filters); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal export filters: %w", err)
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		job.CompletedAt = &completedAt.Time
+	}
+	if expiresAt.Valid {
+		job.ExpiresAt = &expiresAt.Time
+	}
+
+	return &job, nil
+}
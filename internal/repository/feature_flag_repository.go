@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// FeatureFlagRepository maneja los feature flags configurados desde la API de admin
+type FeatureFlagRepository struct {
+	db *sql.DB
+}
+
+// NewFeatureFlagRepository crea una nueva instancia del repositorio
+func NewFeatureFlagRepository(db *sql.DB) *FeatureFlagRepository {
+	return &FeatureFlagRepository{db: db}
+}
+
+// Upsert crea o reemplaza la regla de una funcionalidad (global o de una tienda)
+func (r *FeatureFlagRepository) Upsert(ctx context.Context, rule *domain.FeatureFlagRule) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO feature_flags (feature, store_id, enabled, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(feature, store_id) DO UPDATE SET
+			enabled = excluded.enabled,
+			updated_at = excluded.updated_at
+	`, rule.Feature, rule.StoreID, rule.Enabled, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save feature flag: %w", err)
+	}
+	return nil
+}
+
+// List lista todas las reglas
+func (r *FeatureFlagRepository) List(ctx context.Context) ([]*domain.FeatureFlagRule, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT feature, store_id, enabled, updated_at
+		FROM feature_flags
+		ORDER BY feature, store_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*domain.FeatureFlagRule{}
+	for rows.Next() {
+		rule := domain.FeatureFlagRule{Source: domain.FeatureFlagSourceAdmin}
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&rule.Feature, &rule.StoreID, &rule.Enabled, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan feature flag: %w", err)
+		}
+		if updatedAt.Valid {
+			rule.UpdatedAt = &updatedAt.Time
+		}
+		rules = append(rules, &rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating feature flags: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Delete elimina la regla de una funcionalidad (global o de una tienda)
+func (r *FeatureFlagRepository) Delete(ctx context.Context, feature domain.Feature, storeID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM feature_flags WHERE feature = ? AND store_id = ?`, feature, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		id := string(feature)
+		if storeID != "" {
+			id += "@" + storeID
+		}
+		return &domain.NotFoundError{Resource: "FeatureFlag", ID: id}
+	}
+	return nil
+}
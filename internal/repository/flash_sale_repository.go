@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// FlashSaleRepository persiste los productos en modo alta contención
+type FlashSaleRepository struct {
+	db *sql.DB
+}
+
+// NewFlashSaleRepository crea una nueva instancia del repositorio
+func NewFlashSaleRepository(db *sql.DB) *FlashSaleRepository {
+	return &FlashSaleRepository{db: db}
+}
+
+// Enable activa el modo para un producto (idempotente: conserva la activación original)
+func (r *FlashSaleRepository) Enable(ctx context.Context, product *domain.FlashSaleProduct) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO flash_sale_products (product_id, enabled_by, enabled_at)
+		VALUES (?, ?, ?)
+	`, product.ProductID, product.EnabledBy, product.EnabledAt)
+	if err != nil {
+		return fmt.Errorf("failed to enable flash sale mode: %w", err)
+	}
+
+	return nil
+}
+
+// Disable desactiva el modo para un producto
+func (r *FlashSaleRepository) Disable(ctx context.Context, productID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM flash_sale_products WHERE product_id = ?`, productID)
+	if err != nil {
+		return fmt.Errorf("failed to disable flash sale mode: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &domain.NotFoundError{Resource: "flash sale product", ID: productID}
+	}
+
+	return nil
+}
+
+// List obtiene los productos en modo alta contención
+func (r *FlashSaleRepository) List(ctx context.Context) ([]*domain.FlashSaleProduct, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT product_id, enabled_by, enabled_at
+		FROM flash_sale_products
+		ORDER BY enabled_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flash sale products: %w", err)
+	}
+	defer rows.Close()
+
+	products := make([]*domain.FlashSaleProduct, 0)
+	for rows.Next() {
+		var product domain.FlashSaleProduct
+		if err := rows.Scan(&product.ProductID, &product.EnabledBy, &product.EnabledAt); err != nil {
+			return nil, fmt.Errorf("failed to scan flash sale product: %w", err)
+		}
+		products = append(products, &product)
+	}
+
+	return products, rows.Err()
+}
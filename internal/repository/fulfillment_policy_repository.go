@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// FulfillmentPolicyRepository maneja las políticas de servicio (envío/recogida) por canal
+type FulfillmentPolicyRepository struct {
+	db *sql.DB
+}
+
+// NewFulfillmentPolicyRepository crea una nueva instancia del repositorio
+func NewFulfillmentPolicyRepository(db *sql.DB) *FulfillmentPolicyRepository {
+	return &FulfillmentPolicyRepository{db: db}
+}
+
+// Upsert crea o reemplaza la política de un canal
+func (r *FulfillmentPolicyRepository) Upsert(ctx context.Context, policy *domain.FulfillmentPolicy) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO fulfillment_policies (channel, ship_types, pickup_types, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(channel) DO UPDATE SET
+			ship_types = excluded.ship_types,
+			pickup_types = excluded.pickup_types,
+			updated_at = excluded.updated_at
+	`, policy.Channel, joinLocationTypes(policy.Ship), joinLocationTypes(policy.Pickup), policy.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save fulfillment policy: %w", err)
+	}
+	return nil
+}
+
+// GetByChannel obtiene la política de un canal (NotFoundError si no tiene)
+func (r *FulfillmentPolicyRepository) GetByChannel(ctx context.Context, channel domain.SalesChannel) (*domain.FulfillmentPolicy, error) {
+	policies, err := r.list(ctx, `WHERE channel = ?`, channel)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, &domain.NotFoundError{Resource: "FulfillmentPolicy", ID: string(channel)}
+	}
+	return policies[0], nil
+}
+
+// List obtiene las políticas configuradas, por canal
+func (r *FulfillmentPolicyRepository) List(ctx context.Context) ([]*domain.FulfillmentPolicy, error) {
+	return r.list(ctx, `ORDER BY channel`)
+}
+
+// Delete elimina la política de un canal: vuelve a la política por defecto
+func (r *FulfillmentPolicyRepository) Delete(ctx context.Context, channel domain.SalesChannel) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM fulfillment_policies WHERE channel = ?`, channel)
+	if err != nil {
+		return fmt.Errorf("failed to delete fulfillment policy: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "FulfillmentPolicy", ID: string(channel)}
+	}
+	return nil
+}
+
+func (r *FulfillmentPolicyRepository) list(ctx context.Context, where string, args ...interface{}) ([]*domain.FulfillmentPolicy, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT channel, ship_types, pickup_types, updated_at
+		FROM fulfillment_policies `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fulfillment policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*domain.FulfillmentPolicy
+	for rows.Next() {
+		var policy domain.FulfillmentPolicy
+		var ship, pickup string
+		var updatedAt time.Time
+		if err := rows.Scan(&policy.Channel, &ship, &pickup, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan fulfillment policy: %w", err)
+		}
+		policy.Ship = splitLocationTypes(ship)
+		policy.Pickup = splitLocationTypes(pickup)
+		policy.UpdatedAt = &updatedAt
+		policies = append(policies, &policy)
+	}
+	return policies, rows.Err()
+}
+
+func joinLocationTypes(types []domain.LocationType) string {
+	values := make([]string, len(types))
+	for i, locationType := range types {
+		values[i] = string(locationType)
+	}
+	return strings.Join(values, ",")
+}
+
+func splitLocationTypes(value string) []domain.LocationType {
+	types := []domain.LocationType{}
+	for _, item := range splitList(value) {
+		types = append(types, domain.LocationType(item))
+	}
+	return types
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// IdempotencyRepository guarda las respuestas de las escrituras con Idempotency-Key
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyRepository crea una nueva instancia del repositorio
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Begin registra la petición como en curso. Si la clave ya estaba registrada retorna ese
+// registro y no guarda nada; las claves caducadas (IdempotencyKeyTTL) y las peticiones en
+// curso abandonadas (IdempotencyPendingTimeout) se descartan antes.
+func (r *IdempotencyRepository) Begin(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE scope = ? AND idempotency_key = ?
+		  AND (created_at < ? OR (status = 0 AND created_at < ?))
+	`, record.Scope, record.Key,
+		record.CreatedAt.Add(-domain.IdempotencyKeyTTL), record.CreatedAt.Add(-domain.IdempotencyPendingTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to discard stale idempotency key: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (scope, idempotency_key, method, path, request_hash, status, created_at)
+		VALUES (?, ?, ?, ?, ?, 0, ?)
+		ON CONFLICT(scope, idempotency_key) DO NOTHING
+	`, record.Scope, record.Key, record.Method, record.Path, record.RequestHash, record.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save idempotency key: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		return nil, nil
+	}
+
+	var existing domain.IdempotencyRecord
+	var body []byte
+	err = r.db.QueryRowContext(ctx, `
+		SELECT scope, idempotency_key, method, path, request_hash, status, content_type, body, created_at
+		FROM idempotency_keys
+		WHERE scope = ? AND idempotency_key = ?
+	`, record.Scope, record.Key).Scan(
+		&existing.Scope,
+		&existing.Key,
+		&existing.Method,
+		&existing.Path,
+		&existing.RequestHash,
+		&existing.Status,
+		&existing.ContentType,
+		&body,
+		&existing.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	existing.Body = body
+	return &existing, nil
+}
+
+// Complete guarda la respuesta de la petición para repetirla en los reintentos
+func (r *IdempotencyRepository) Complete(ctx context.Context, scope, key string, status int, contentType string, body []byte) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET status = ?, content_type = ?, body = ?
+		WHERE scope = ? AND idempotency_key = ?
+	`, status, contentType, body, scope, key)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release libera la clave sin guardar respuesta (error transitorio): el reintento se procesa de nuevo
+func (r *IdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys WHERE scope = ? AND idempotency_key = ?
+	`, scope, key)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteOlderThan elimina las claves registradas antes de before
+func (r *IdempotencyRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
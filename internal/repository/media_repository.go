@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"inventory-system/internal/domain"
+)
+
+// MediaRepository maneja los registros de imágenes de productos
+type MediaRepository struct {
+	db *sql.DB
+}
+
+// NewMediaRepository crea una nueva instancia del repositorio
+func NewMediaRepository(db *sql.DB) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+const mediaColumns = `id, product_id, url, storage_key, content_type, size_bytes, alt_text, position, created_at`
+
+func scanMedia(scanner interface{ Scan(...interface{}) error }) (*domain.ProductMedia, error) {
+	var media domain.ProductMedia
+	err := scanner.Scan(
+		&media.ID,
+		&media.ProductID,
+		&media.URL,
+		&media.StorageKey,
+		&media.ContentType,
+		&media.SizeBytes,
+		&media.AltText,
+		&media.Position,
+		&media.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// Create inserta una imagen
+func (r *MediaRepository) Create(ctx context.Context, media *domain.ProductMedia) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO product_media (`+mediaColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, media.ID, media.ProductID, media.URL, media.StorageKey, media.ContentType,
+		media.SizeBytes, media.AltText, media.Position, media.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create product media: %w", err)
+	}
+	return nil
+}
+
+// GetByID obtiene una imagen de un producto
+func (r *MediaRepository) GetByID(ctx context.Context, productID, id string) (*domain.ProductMedia, error) {
+	media, err := scanMedia(r.db.QueryRowContext(ctx, `
+		SELECT `+mediaColumns+`
+		FROM product_media
+		WHERE id = ? AND product_id = ?
+	`, id, productID))
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "ProductMedia", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product media: %w", err)
+	}
+	return media, nil
+}
+
+// ListByProduct lista las imágenes de un producto en orden de presentación
+func (r *MediaRepository) ListByProduct(ctx context.Context, productID string) ([]*domain.ProductMedia, error) {
+	return r.list(ctx, `WHERE product_id = ?`, productID)
+}
+
+// ListByProducts lista las imágenes de varios productos (para adjuntarlas a un listado)
+func (r *MediaRepository) ListByProducts(ctx context.Context, productIDs []string) ([]*domain.ProductMedia, error) {
+	if len(productIDs) == 0 {
+		return []*domain.ProductMedia{}, nil
+	}
+
+	args := make([]interface{}, len(productIDs))
+	for i, id := range productIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(productIDs)), ", ")
+
+	return r.list(ctx, `WHERE product_id IN (`+placeholders+`)`, args...)
+}
+
+func (r *MediaRepository) list(ctx context.Context, where string, args ...interface{}) ([]*domain.ProductMedia, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+mediaColumns+`
+		FROM product_media
+		`+where+`
+		ORDER BY product_id, position, created_at
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product media: %w", err)
+	}
+	defer rows.Close()
+
+	media := make([]*domain.ProductMedia, 0)
+	for rows.Next() {
+		item, err := scanMedia(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product media: %w", err)
+		}
+		media = append(media, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product media: %w", err)
+	}
+
+	return media, nil
+}
+
+// UpdateAltText actualiza el texto alternativo de una imagen
+func (r *MediaRepository) UpdateAltText(ctx context.Context, productID, id, altText string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE product_media SET alt_text = ? WHERE id = ? AND product_id = ?
+	`, altText, id, productID)
+	if err != nil {
+		return fmt.Errorf("failed to update product media: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "ProductMedia", ID: id}
+	}
+	return nil
+}
+
+// SetPositions renumera las imágenes de un producto (position = índice en orderedIDs)
+// en una transacción
+func (r *MediaRepository) SetPositions(ctx context.Context, productID string, orderedIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for position, id := range orderedIDs {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE product_media SET position = ? WHERE id = ? AND product_id = ?
+		`, position, id, productID); err != nil {
+			return fmt.Errorf("failed to reorder product media: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Delete elimina una imagen
+func (r *MediaRepository) Delete(ctx context.Context, productID, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM product_media WHERE id = ? AND product_id = ?`, id, productID)
+	if err != nil {
+		return fmt.Errorf("failed to delete product media: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "ProductMedia", ID: id}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"inventory-system/internal/domain"
+)
+
+// NotificationRepository maneja los contactos de clientes, la configuración de avisos de cada
+// tienda y el registro de avisos enviados
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository crea una nueva instancia del repositorio
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// UpsertContact crea o reemplaza el contacto de un cliente
+func (r *NotificationRepository) UpsertContact(ctx context.Context, contact *domain.CustomerContact) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO customer_contacts (customer_id, email, phone, push_token, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(customer_id) DO UPDATE SET
+			email = excluded.email,
+			phone = excluded.phone,
+			push_token = excluded.push_token,
+			updated_at = excluded.updated_at
+	`, contact.CustomerID, contact.Email, contact.Phone, contact.PushToken, contact.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save customer contact: %w", err)
+	}
+	return nil
+}
+
+// GetContact obtiene el contacto de un cliente (NotFoundError si no tiene)
+func (r *NotificationRepository) GetContact(ctx context.Context, customerID string) (*domain.CustomerContact, error) {
+	var contact domain.CustomerContact
+	err := r.db.QueryRowContext(ctx, `
+		SELECT customer_id, email, phone, push_token, updated_at
+		FROM customer_contacts
+		WHERE customer_id = ?
+	`, customerID).Scan(&contact.CustomerID, &contact.Email, &contact.Phone, &contact.PushToken, &contact.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "CustomerContact", ID: customerID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer contact: %w", err)
+	}
+	return &contact, nil
+}
+
+// DeleteContact elimina el contacto de un cliente
+func (r *NotificationRepository) DeleteContact(ctx context.Context, customerID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM customer_contacts WHERE customer_id = ?`, customerID)
+	if err != nil {
+		return fmt.Errorf("failed to delete customer contact: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "CustomerContact", ID: customerID}
+	}
+	return nil
+}
+
+// UpsertSettings crea o reemplaza la configuración de avisos de una tienda
+func (r *NotificationRepository) UpsertSettings(ctx context.Context, settings *domain.StoreNotificationSettings) error {
+	templates, err := json.Marshal(settings.Templates)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification templates: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO store_notification_settings (store_id, enabled, channels, kinds, expiring_before_minutes, email_from, templates, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(store_id) DO UPDATE SET
+			enabled = excluded.enabled,
+			channels = excluded.channels,
+			kinds = excluded.kinds,
+			expiring_before_minutes = excluded.expiring_before_minutes,
+			email_from = excluded.email_from,
+			templates = excluded.templates,
+			updated_at = excluded.updated_at
+	`, settings.StoreID, settings.Enabled, joinChannels(settings.Channels), joinKinds(settings.Kinds),
+		settings.ExpiringBeforeMinutes, settings.EmailFrom, string(templates), settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save notification settings: %w", err)
+	}
+	return nil
+}
+
+// GetSettings obtiene la configuración de avisos de una tienda (NotFoundError si no tiene)
+func (r *NotificationRepository) GetSettings(ctx context.Context, storeID string) (*domain.StoreNotificationSettings, error) {
+	settings, err := r.querySettings(ctx, `WHERE store_id = ?`, storeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return nil, &domain.NotFoundError{Resource: "StoreNotificationSettings", ID: storeID}
+	}
+	return settings[0], nil
+}
+
+// ListEnabledSettings obtiene la configuración de las tiendas con avisos activados
+func (r *NotificationRepository) ListEnabledSettings(ctx context.Context) ([]*domain.StoreNotificationSettings, error) {
+	return r.querySettings(ctx, `WHERE enabled = 1 ORDER BY store_id`)
+}
+
+// DeleteSettings elimina la configuración de avisos de una tienda
+func (r *NotificationRepository) DeleteSettings(ctx context.Context, storeID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM store_notification_settings WHERE store_id = ?`, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification settings: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "StoreNotificationSettings", ID: storeID}
+	}
+	return nil
+}
+
+func (r *NotificationRepository) querySettings(ctx context.Context, where string, args ...interface{}) ([]*domain.StoreNotificationSettings, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT store_id, enabled, channels, kinds, expiring_before_minutes, email_from, templates, updated_at
+		FROM store_notification_settings
+	`+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*domain.StoreNotificationSettings
+	for rows.Next() {
+		var settings domain.StoreNotificationSettings
+		var channels, kinds, templates string
+		err := rows.Scan(&settings.StoreID, &settings.Enabled, &channels, &kinds,
+			&settings.ExpiringBeforeMinutes, &settings.EmailFrom, &templates, &settings.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification settings: %w", err)
+		}
+		settings.Channels = []domain.NotificationChannel{}
+		for _, channel := range splitList(channels) {
+			settings.Channels = append(settings.Channels, domain.NotificationChannel(channel))
+		}
+		settings.Kinds = []domain.NotificationKind{}
+		for _, kind := range splitList(kinds) {
+			settings.Kinds = append(settings.Kinds, domain.NotificationKind(kind))
+		}
+		if err := json.Unmarshal([]byte(templates), &settings.Templates); err != nil {
+			return nil, fmt.Errorf("failed to decode notification templates: %w", err)
+		}
+		list = append(list, &settings)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification settings: %w", err)
+	}
+	return list, nil
+}
+
+// ClaimDelivery registra el aviso como PENDING si no existe ya uno para la misma reserva, tipo y
+// canal. Retorna false si otro evento (o instancia) ya lo reclamó: no hay que enviarlo.
+func (r *NotificationRepository) ClaimDelivery(ctx context.Context, delivery *domain.NotificationDelivery) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO reservation_notifications (id, reservation_id, store_id, kind, channel, recipient, status, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, '', ?)
+		ON CONFLICT(reservation_id, kind, channel) DO NOTHING
+	`, delivery.ID, delivery.ReservationID, delivery.StoreID, delivery.Kind, delivery.Channel,
+		delivery.Recipient, delivery.Status, delivery.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim notification: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// FinishDelivery guarda el resultado del envío de un aviso reclamado
+func (r *NotificationRepository) FinishDelivery(ctx context.Context, delivery *domain.NotificationDelivery) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE reservation_notifications
+		SET status = ?, error = ?, sent_at = ?
+		WHERE id = ?
+	`, delivery.Status, delivery.Error, delivery.SentAt, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update notification: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries obtiene los avisos de una reserva en orden de envío
+func (r *NotificationRepository) ListDeliveries(ctx context.Context, reservationID string) ([]*domain.NotificationDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, reservation_id, store_id, kind, channel, recipient, status, error, created_at, sent_at
+		FROM reservation_notifications
+		WHERE reservation_id = ?
+		ORDER BY created_at ASC, id ASC
+	`, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.NotificationDelivery{}
+	for rows.Next() {
+		var delivery domain.NotificationDelivery
+		var sentAt sql.NullTime
+		err := rows.Scan(&delivery.ID, &delivery.ReservationID, &delivery.StoreID, &delivery.Kind, &delivery.Channel,
+			&delivery.Recipient, &delivery.Status, &delivery.Error, &delivery.CreatedAt, &sentAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if sentAt.Valid {
+			delivery.SentAt = &sentAt.Time
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+	return deliveries, nil
+}
+
+func joinChannels(channels []domain.NotificationChannel) string {
+	values := make([]string, len(channels))
+	for i, channel := range channels {
+		values[i] = string(channel)
+	}
+	return strings.Join(values, ",")
+}
+
+func joinKinds(kinds []domain.NotificationKind) string {
+	values := make([]string, len(kinds))
+	for i, kind := range kinds {
+		values[i] = string(kind)
+	}
+	return strings.Join(values, ",")
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// OversellRepository maneja las tolerancias de sobreventa por tienda y por producto
+type OversellRepository struct {
+	db *sql.DB
+}
+
+// NewOversellRepository crea una nueva instancia del repositorio
+func NewOversellRepository(db *sql.DB) *OversellRepository {
+	return &OversellRepository{db: db}
+}
+
+// Upsert crea o reemplaza una tolerancia
+func (r *OversellRepository) Upsert(ctx context.Context, tolerance *domain.OversellTolerance) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO oversell_tolerances (scope, scope_id, max_units, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(scope, scope_id) DO UPDATE SET
+			max_units = excluded.max_units,
+			updated_at = excluded.updated_at
+	`, tolerance.Scope, tolerance.ScopeID, tolerance.MaxUnits, tolerance.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save oversell tolerance: %w", err)
+	}
+	return nil
+}
+
+// List lista las tolerancias configuradas (scope vacío = todas)
+func (r *OversellRepository) List(ctx context.Context, scope domain.OversellScope) ([]*domain.OversellTolerance, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT scope, scope_id, max_units, updated_at
+		FROM oversell_tolerances
+		WHERE (? = '' OR scope = ?)
+		ORDER BY scope, scope_id
+	`, scope, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oversell tolerances: %w", err)
+	}
+	defer rows.Close()
+
+	tolerances := []*domain.OversellTolerance{}
+	for rows.Next() {
+		var tolerance domain.OversellTolerance
+		if err := rows.Scan(&tolerance.Scope, &tolerance.ScopeID, &tolerance.MaxUnits, &tolerance.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan oversell tolerance: %w", err)
+		}
+		tolerances = append(tolerances, &tolerance)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating oversell tolerances: %w", err)
+	}
+
+	return tolerances, nil
+}
+
+// Delete elimina una tolerancia
+func (r *OversellRepository) Delete(ctx context.Context, scope domain.OversellScope, scopeID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM oversell_tolerances WHERE scope = ? AND scope_id = ?`, scope, scopeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete oversell tolerance: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "OversellTolerance", ID: fmt.Sprintf("%s/%s", scope, scopeID)}
+	}
+	return nil
+}
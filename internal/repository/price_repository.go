@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+)
+
+// PriceRepository maneja el historial de precios y los cambios de precio programados
+type PriceRepository struct {
+	db *sql.DB
+}
+
+// NewPriceRepository crea una nueva instancia del repositorio
+func NewPriceRepository(db *sql.DB) *PriceRepository {
+	return &PriceRepository{db: db}
+}
+
+// insertPriceChange registra un cambio de precio dentro de la transacción que lo aplica
+func insertPriceChange(ctx context.Context, tx *sql.Tx, change *domain.PriceChange) error {
+	if change.ID == "" {
+		change.ID = uuid.New().String()
+	}
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+
+	var scheduledID interface{}
+	if change.ScheduledID != "" {
+		scheduledID = change.ScheduledID
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO price_history (id, product_id, old_price, new_price, actor, source, scheduled_id, effective_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, change.ID, change.ProductID, change.OldPrice, change.NewPrice, change.Actor, change.Source,
+		scheduledID, change.EffectiveAt, change.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record price change: %w", err)
+	}
+
+	return nil
+}
+
+// ListHistory obtiene el historial de precios de un producto, del más reciente al más antiguo
+func (r *PriceRepository) ListHistory(ctx context.Context, productID string, limit, offset int) ([]*domain.PriceChange, error) {
+	query := `
+		SELECT id, product_id, old_price, new_price, actor, source, COALESCE(scheduled_id, ''), effective_at, created_at
+		FROM price_history
+		WHERE product_id = ?
+		ORDER BY effective_at DESC, created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, productID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make([]*domain.PriceChange, 0)
+	for rows.Next() {
+		var change domain.PriceChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.ProductID,
+			&change.OldPrice,
+			&change.NewPrice,
+			&change.Actor,
+			&change.Source,
+			&change.ScheduledID,
+			&change.EffectiveAt,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan price change: %w", err)
+		}
+		history = append(history, &change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating price history: %w", err)
+	}
+
+	return history, nil
+}
+
+// CountHistory cuenta los cambios de precio registrados para un producto
+func (r *PriceRepository) CountHistory(ctx context.Context, productID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM price_history WHERE product_id = ?`, productID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count price history: %w", err)
+	}
+	return count, nil
+}
+
+// CreateSchedule persiste un cambio de precio programado
+func (r *PriceRepository) CreateSchedule(ctx context.Context, schedule *domain.ScheduledPriceChange) error {
+	query := `
+		INSERT INTO scheduled_price_changes (id, product_id, new_price, actor, status, effective_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		schedule.ID,
+		schedule.ProductID,
+		schedule.NewPrice,
+		schedule.Actor,
+		schedule.Status,
+		schedule.EffectiveAt,
+		schedule.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled price change: %w", err)
+	}
+
+	return nil
+}
+
+// GetSchedule obtiene un cambio de precio programado por ID
+func (r *PriceRepository) GetSchedule(ctx context.Context, id string) (*domain.ScheduledPriceChange, error) {
+	query := `
+		SELECT id, product_id, new_price, actor, status, effective_at, created_at, applied_at
+		FROM scheduled_price_changes
+		WHERE id = ?
+	`
+
+	schedule, err := scanSchedule(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "ScheduledPriceChange", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled price change: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// ListSchedules obtiene los cambios programados de un producto ordenados por effective_at
+func (r *PriceRepository) ListSchedules(ctx context.Context, productID string) ([]*domain.ScheduledPriceChange, error) {
+	query := `
+		SELECT id, product_id, new_price, actor, status, effective_at, created_at, applied_at
+		FROM scheduled_price_changes
+		WHERE product_id = ?
+		ORDER BY effective_at ASC
+	`
+
+	return r.querySchedules(ctx, query, productID)
+}
+
+// ListDue obtiene los cambios pendientes cuyo effective_at ya llegó, del más antiguo al más reciente
+func (r *PriceRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.ScheduledPriceChange, error) {
+	query := `
+		SELECT id, product_id, new_price, actor, status, effective_at, created_at, applied_at
+		FROM scheduled_price_changes
+		WHERE status = ? AND effective_at <= ?
+		ORDER BY effective_at ASC
+		LIMIT ?
+	`
+
+	return r.querySchedules(ctx, query, domain.ScheduledPricePending, now, limit)
+}
+
+// Cancel anula un cambio programado que sigue pendiente
+func (r *PriceRepository) Cancel(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE scheduled_price_changes SET status = ? WHERE id = ? AND status = ?`,
+		domain.ScheduledPriceCancelled, id, domain.ScheduledPricePending,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to cancel scheduled price change: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return &domain.ConflictError{Message: fmt.Sprintf("scheduled price change %s is not pending", id)}
+	}
+
+	return nil
+}
+
+// Apply aplica un cambio programado en una única transacción: actualiza el precio del producto,
+// registra el cambio en el historial y marca el cambio como APPLIED. Retorna nil, nil si el cambio
+// ya no estaba pendiente (p. ej. lo aplicó otra instancia o se canceló mientras tanto).
+func (r *PriceRepository) Apply(ctx context.Context, schedule *domain.ScheduledPriceChange, appliedAt time.Time) (*domain.PriceChange, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE scheduled_price_changes SET status = ?, applied_at = ? WHERE id = ? AND status = ?`,
+		domain.ScheduledPriceApplied, appliedAt, schedule.ID, domain.ScheduledPricePending,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark scheduled price change as applied: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, nil
+	}
+
+	var oldPrice float64
+	err = tx.QueryRowContext(ctx, `SELECT price FROM products WHERE id = ?`, schedule.ProductID).Scan(&oldPrice)
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "Product", ID: schedule.ProductID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product price: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE products SET price = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		schedule.NewPrice, schedule.ProductID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product price: %w", err)
+	}
+
+	change := &domain.PriceChange{
+		ProductID:   schedule.ProductID,
+		OldPrice:    oldPrice,
+		NewPrice:    schedule.NewPrice,
+		Actor:       schedule.Actor,
+		Source:      domain.PriceChangeScheduled,
+		ScheduledID: schedule.ID,
+		EffectiveAt: schedule.EffectiveAt,
+	}
+	if err := insertPriceChange(ctx, tx, change); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit scheduled price change: %w", err)
+	}
+
+	schedule.Status = domain.ScheduledPriceApplied
+	schedule.AppliedAt = &appliedAt
+
+	return change, nil
+}
+
+func (r *PriceRepository) querySchedules(ctx context.Context, query string, args ...interface{}) ([]*domain.ScheduledPriceChange, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled price changes: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make([]*domain.ScheduledPriceChange, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled price change: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheduled price changes: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// scanSchedule escanea una fila de scheduled_price_changes
+func scanSchedule(row rowScanner) (*domain.ScheduledPriceChange, error) {
+	var schedule domain.ScheduledPriceChange
+	var appliedAt sql.NullTime
+
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.ProductID,
+		&schedule.NewPrice,
+		&schedule.Actor,
+		&schedule.Status,
+		&schedule.EffectiveAt,
+		&schedule.CreatedAt,
+		&appliedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if appliedAt.Valid {
+		schedule.AppliedAt = &appliedAt.Time
+	}
+
+	return &schedule, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// ProcessedEventRepository persiste los eventos ya aplicados por cada consumidor
+type ProcessedEventRepository struct {
+	db *sql.DB
+}
+
+// NewProcessedEventRepository crea una nueva instancia del repositorio
+func NewProcessedEventRepository(db *sql.DB) *ProcessedEventRepository {
+	return &ProcessedEventRepository{db: db}
+}
+
+// Claim reclama un evento para un consumidor. Retorna false si ya está aplicado o si otra
+// instancia lo está procesando y su reclamación no ha caducado (lease).
+// La inserción por clave primaria hace la reclamación atómica entre réplicas.
+func (r *ProcessedEventRepository) Claim(ctx context.Context, consumer string, event *domain.Event, lease time.Duration) (bool, error) {
+	now := time.Now()
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO processed_events (consumer, event_id, event_type, status, claimed_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, consumer, event.ID, event.EventType, domain.ProcessedEventProcessing, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim event: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 1 {
+		return true, nil
+	}
+
+	// Ya existe: solo se recupera una reclamación abandonada
+	result, err = r.db.ExecContext(ctx, `
+		UPDATE processed_events
+		SET claimed_at = ?
+		WHERE consumer = ? AND event_id = ? AND status = ? AND claimed_at < ?
+	`, now, consumer, event.ID, domain.ProcessedEventProcessing, now.Add(-lease))
+	if err != nil {
+		return false, fmt.Errorf("failed to reclaim event: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+
+	return rows == 1, nil
+}
+
+// MarkDone marca el evento como aplicado por el consumidor
+func (r *ProcessedEventRepository) MarkDone(ctx context.Context, consumer, eventID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE processed_events SET status = ?, processed_at = ?
+		WHERE consumer = ? AND event_id = ?
+	`, domain.ProcessedEventDone, time.Now(), consumer, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to mark event as processed: %w", err)
+	}
+
+	return nil
+}
+
+// Release elimina la reclamación de un evento cuyo handler falló, para que la
+// siguiente entrega lo vuelva a intentar
+func (r *ProcessedEventRepository) Release(ctx context.Context, consumer, eventID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM processed_events
+		WHERE consumer = ? AND event_id = ? AND status = ?
+	`, consumer, eventID, domain.ProcessedEventProcessing)
+	if err != nil {
+		return fmt.Errorf("failed to release event: %w", err)
+	}
+
+	return nil
+}
+
+// Get obtiene el registro de idempotencia de un evento para un consumidor
+func (r *ProcessedEventRepository) Get(ctx context.Context, consumer, eventID string) (*domain.ProcessedEvent, error) {
+	var (
+		processed   domain.ProcessedEvent
+		processedAt sql.NullTime
+	)
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT consumer, event_id, event_type, status, claimed_at, processed_at
+		FROM processed_events
+		WHERE consumer = ? AND event_id = ?
+	`, consumer, eventID).Scan(
+		&processed.Consumer,
+		&processed.EventID,
+		&processed.EventType,
+		&processed.Status,
+		&processed.ClaimedAt,
+		&processedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "processed event", ID: eventID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get processed event: %w", err)
+	}
+	if processedAt.Valid {
+		processed.ProcessedAt = &processedAt.Time
+	}
+
+	return &processed, nil
+}
+
+// DeleteProcessedBefore elimina los registros aplicados antes de la fecha indicada.
+// Debe conservarse al menos la ventana de retención del stream/topic, o una
+// re-entrega antigua volvería a aplicarse.
+func (r *ProcessedEventRepository) DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM processed_events WHERE status = ? AND processed_at < ?
+	`, domain.ProcessedEventDone, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete processed events: %w", err)
+	}
+
+	return result.RowsAffected()
+}
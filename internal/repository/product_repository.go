@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"inventory-system/internal/domain"
 )
@@ -21,10 +23,14 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 // Create crea un nuevo producto
 func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
 	query := `
-		INSERT INTO products (id, sku, name, description, category, price, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		INSERT INTO products (id, sku, name, description, category, price, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 	`
 
+	if product.Status == "" {
+		product.Status = domain.ProductStatusActive
+	}
+
 	_, err := r.db.ExecContext(ctx, query,
 		product.ID,
 		product.SKU,
@@ -32,6 +38,7 @@ func (r *ProductRepository) Create(ctx context.Context, product *domain.Product)
 		product.Description,
 		product.Category,
 		product.Price,
+		product.Status,
 	)
 
 	if err != nil {
@@ -44,7 +51,7 @@ func (r *ProductRepository) Create(ctx context.Context, product *domain.Product)
 // GetByID obtiene un producto por su ID
 func (r *ProductRepository) GetByID(ctx context.Context, id string) (*domain.Product, error) {
 	query := `
-		SELECT id, sku, name, description, category, price, created_at, updated_at
+		SELECT id, sku, name, description, category, price, status, abc_class, created_at, updated_at
 		FROM products
 		WHERE id = ?
 	`
@@ -57,6 +64,8 @@ func (r *ProductRepository) GetByID(ctx context.Context, id string) (*domain.Pro
 		&product.Description,
 		&product.Category,
 		&product.Price,
+		&product.Status,
+		&product.ABCClass,
 		&product.CreatedAt,
 		&product.UpdatedAt,
 	)
@@ -71,22 +80,27 @@ func (r *ProductRepository) GetByID(ctx context.Context, id string) (*domain.Pro
 	return &product, nil
 }
 
-// GetBySKU obtiene un producto por su SKU
+// GetBySKU obtiene un producto por su SKU sin distinguir mayúsculas/minúsculas.
+// Si hay duplicados heredados que solo difieren en el caso, se prefiere la coincidencia exacta.
 func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.Product, error) {
 	query := `
-		SELECT id, sku, name, description, category, price, created_at, updated_at
+		SELECT id, sku, name, description, category, price, status, abc_class, created_at, updated_at
 		FROM products
-		WHERE sku = ?
+		WHERE sku = ? COLLATE NOCASE
+		ORDER BY sku = ? DESC
+		LIMIT 1
 	`
 
 	var product domain.Product
-	err := r.db.QueryRowContext(ctx, query, sku).Scan(
+	err := r.db.QueryRowContext(ctx, query, sku, sku).Scan(
 		&product.ID,
 		&product.SKU,
 		&product.Name,
 		&product.Description,
 		&product.Category,
 		&product.Price,
+		&product.Status,
+		&product.ABCClass,
 		&product.CreatedAt,
 		&product.UpdatedAt,
 	)
@@ -101,16 +115,18 @@ func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.P
 	return &product, nil
 }
 
-// List obtiene una lista paginada de productos
-func (r *ProductRepository) List(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
+// List obtiene una lista paginada de productos. Los descatalogados solo se incluyen
+// si includeDiscontinued es true.
+func (r *ProductRepository) List(ctx context.Context, limit, offset int, includeDiscontinued bool) ([]*domain.Product, error) {
 	query := `
-		SELECT id, sku, name, description, category, price, created_at, updated_at
+		SELECT id, sku, name, description, category, price, status, abc_class, created_at, updated_at
 		FROM products
+		WHERE (? OR status != ?)
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, includeDiscontinued, domain.ProductStatusDiscontinued, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list products: %w", err)
 	}
@@ -126,6 +142,8 @@ func (r *ProductRepository) List(ctx context.Context, limit, offset int) ([]*dom
 			&product.Description,
 			&product.Category,
 			&product.Price,
+			&product.Status,
+			&product.ABCClass,
 			&product.CreatedAt,
 			&product.UpdatedAt,
 		)
@@ -142,17 +160,18 @@ func (r *ProductRepository) List(ctx context.Context, limit, offset int) ([]*dom
 	return products, nil
 }
 
-// ListByCategory obtiene productos por categoría
-func (r *ProductRepository) ListByCategory(ctx context.Context, category string, limit, offset int) ([]*domain.Product, error) {
+// ListByCategory obtiene productos por categoría. Los descatalogados solo se incluyen
+// si includeDiscontinued es true.
+func (r *ProductRepository) ListByCategory(ctx context.Context, category string, limit, offset int, includeDiscontinued bool) ([]*domain.Product, error) {
 	query := `
-		SELECT id, sku, name, description, category, price, created_at, updated_at
+		SELECT id, sku, name, description, category, price, status, abc_class, created_at, updated_at
 		FROM products
-		WHERE category = ?
+		WHERE category = ? AND (? OR status != ?)
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, category, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, category, includeDiscontinued, domain.ProductStatusDiscontinued, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list products by category: %w", err)
 	}
@@ -168,6 +187,53 @@ func (r *ProductRepository) ListByCategory(ctx context.Context, category string,
 			&product.Description,
 			&product.Category,
 			&product.Price,
+			&product.Status,
+			&product.ABCClass,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, &product)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating products: %w", err)
+	}
+
+	return products, nil
+}
+
+// ListByABCClass obtiene productos de una clase ABC, opcionalmente de una sola categoría
+// ("" = todas). Los descatalogados solo se incluyen si includeDiscontinued es true.
+func (r *ProductRepository) ListByABCClass(ctx context.Context, class domain.ABCClass, category string, limit, offset int, includeDiscontinued bool) ([]*domain.Product, error) {
+	query := `
+		SELECT id, sku, name, description, category, price, status, abc_class, created_at, updated_at
+		FROM products
+		WHERE abc_class = ? AND (? = '' OR category = ?) AND (? OR status != ?)
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, class, category, category, includeDiscontinued, domain.ProductStatusDiscontinued, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list products by ABC class: %w", err)
+	}
+	defer rows.Close()
+
+	var products []*domain.Product
+	for rows.Next() {
+		var product domain.Product
+		err := rows.Scan(
+			&product.ID,
+			&product.SKU,
+			&product.Name,
+			&product.Description,
+			&product.Category,
+			&product.Price,
+			&product.Status,
+			&product.ABCClass,
 			&product.CreatedAt,
 			&product.UpdatedAt,
 		)
@@ -184,15 +250,78 @@ func (r *ProductRepository) ListByCategory(ctx context.Context, category string,
 	return products, nil
 }
 
-// Update actualiza un producto existente
+// Search busca productos cuyo nombre, descripción, SKU o categoría contienen query (sin
+// distinguir mayúsculas). Es la búsqueda sin índice externo: no ordena por relevancia.
+func (r *ProductRepository) Search(ctx context.Context, query string, limit, offset int, includeDiscontinued bool) ([]*domain.Product, error) {
+	sqlQuery := `
+		SELECT id, sku, name, description, category, price, status, abc_class, created_at, updated_at
+		FROM products
+		WHERE (name LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\' OR sku LIKE ? ESCAPE '\' OR category LIKE ? ESCAPE '\')
+		  AND (? OR status != ?)
+		ORDER BY name ASC, id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query) + "%"
+	rows, err := r.db.QueryContext(ctx, sqlQuery, pattern, pattern, pattern, pattern, includeDiscontinued, domain.ProductStatusDiscontinued, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	defer rows.Close()
+
+	var products []*domain.Product
+	for rows.Next() {
+		var product domain.Product
+		err := rows.Scan(
+			&product.ID,
+			&product.SKU,
+			&product.Name,
+			&product.Description,
+			&product.Category,
+			&product.Price,
+			&product.Status,
+			&product.ABCClass,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, &product)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating products: %w", err)
+	}
+
+	return products, nil
+}
+
+// Update actualiza un producto existente. Si cambia el precio, registra el cambio en
+// price_history (autor tomado del context) dentro de la misma transacción.
 func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldPrice float64
+	err = tx.QueryRowContext(ctx, `SELECT price FROM products WHERE id = ?`, product.ID).Scan(&oldPrice)
+	if err == sql.ErrNoRows {
+		return &domain.NotFoundError{Resource: "Product", ID: product.ID}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+
 	query := `
 		UPDATE products
 		SET sku = ?, name = ?, description = ?, category = ?, price = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		product.SKU,
 		product.Name,
 		product.Description,
@@ -200,18 +329,113 @@ func (r *ProductRepository) Update(ctx context.Context, product *domain.Product)
 		product.Price,
 		product.ID,
 	)
-
 	if err != nil {
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 
+	if oldPrice != product.Price {
+		change := &domain.PriceChange{
+			ProductID:   product.ID,
+			OldPrice:    oldPrice,
+			NewPrice:    product.Price,
+			Actor:       domain.ActorFromContext(ctx),
+			Source:      domain.PriceChangeManual,
+			EffectiveAt: time.Now(),
+		}
+		if err := insertPriceChange(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatus cambia el estado del ciclo de vida de un producto
+func (r *ProductRepository) UpdateStatus(ctx context.Context, id string, status domain.ProductStatus) error {
+	query := `UPDATE products SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update product status: %w", err)
+	}
+
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
 	if rowsAffected == 0 {
-		return &domain.NotFoundError{Resource: "Product", ID: product.ID}
+		return &domain.NotFoundError{Resource: "Product", ID: id}
+	}
+
+	return nil
+}
+
+// GetConsumption retorna las unidades confirmadas desde since y su valor (al precio actual) de
+// cada producto que no está en DRAFT, incluidos los que no tuvieron consumo
+func (r *ProductRepository) GetConsumption(ctx context.Context, since time.Time) ([]domain.ProductConsumption, error) {
+	query := `
+		SELECT p.id, COALESCE(SUM(r.quantity), 0), COALESCE(SUM(r.quantity), 0) * p.price
+		FROM products p
+		LEFT JOIN reservations r ON r.product_id = p.id AND r.status = ? AND r.confirmed_at >= ?
+		WHERE p.status != ?
+		GROUP BY p.id, p.price
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.ReservationStatusConfirmed, since, domain.ProductStatusDraft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product consumption: %w", err)
+	}
+	defer rows.Close()
+
+	var consumption []domain.ProductConsumption
+	for rows.Next() {
+		var item domain.ProductConsumption
+		if err := rows.Scan(&item.ProductID, &item.Units, &item.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan product consumption: %w", err)
+		}
+		consumption = append(consumption, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product consumption: %w", err)
+	}
+
+	return consumption, nil
+}
+
+// ReplaceABCClasses guarda la clasificación ABC en una transacción. Los productos que no
+// aparecen en classes (DRAFT) quedan sin clase. No modifica updated_at: la clase no es una
+// edición del producto.
+func (r *ProductRepository) ReplaceABCClasses(ctx context.Context, classes map[string]domain.ABCClass) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE products SET abc_class = ''`); err != nil {
+		return fmt.Errorf("failed to reset ABC classes: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `UPDATE products SET abc_class = ? WHERE id = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare ABC class update: %w", err)
+	}
+	defer stmt.Close()
+
+	for productID, class := range classes {
+		if _, err := stmt.ExecContext(ctx, class, productID); err != nil {
+			return fmt.Errorf("failed to update ABC class: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit ABC classes: %w", err)
 	}
 
 	return nil
@@ -238,12 +462,127 @@ func (r *ProductRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// Count retorna el total de productos
-func (r *ProductRepository) Count(ctx context.Context) (int, error) {
-	query := `SELECT COUNT(*) FROM products`
+// GetDependencies obtiene el stock y las reservas pendientes que dependen de un producto
+func (r *ProductRepository) GetDependencies(ctx context.Context, id string) (*domain.ProductDependencies, error) {
+	deps := &domain.ProductDependencies{ProductID: id, Stores: []string{}}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT store_id, quantity, reserved
+		FROM stock
+		WHERE product_id = ? AND (quantity > 0 OR reserved > 0)
+		ORDER BY store_id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product stock: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var storeID string
+		var quantity, reserved int
+		if err := rows.Scan(&storeID, &quantity, &reserved); err != nil {
+			return nil, fmt.Errorf("failed to scan product stock: %w", err)
+		}
+		deps.Stores = append(deps.Stores, storeID)
+		deps.StockUnits += quantity
+		deps.ReservedUnits += reserved
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product stock: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM reservations WHERE product_id = ? AND status IN (?, ?)`,
+		id, domain.ReservationStatusPending, domain.ReservationStatusReadyForPickup).Scan(&deps.PendingReservations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending reservations: %w", err)
+	}
+
+	return deps, nil
+}
+
+// ArchiveAndDelete copia el stock y las reservas del producto a las tablas de archivo y luego
+// los elimina junto con el producto, todo en una transacción. Las reservas pendientes o listas para recoger se archivan como CANCELLED.
+func (r *ProductRepository) ArchiveAndDelete(ctx context.Context, product *domain.Product) (*domain.ProductArchive, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	archive := &domain.ProductArchive{
+		ProductID:  product.ID,
+		SKU:        product.SKU,
+		ArchivedAt: time.Now(),
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO archived_stock (id, product_id, product_sku, store_id, quantity, reserved, min_stock, max_stock, updated_at, archived_at)
+		SELECT id, product_id, ?, store_id, quantity, reserved, min_stock, max_stock, updated_at, ?
+		FROM stock
+		WHERE product_id = ?
+	`, product.SKU, archive.ArchivedAt, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive stock: %w", err)
+	}
+	stockRows, _ := result.RowsAffected()
+	archive.ArchivedStock = int(stockRows)
+
+	result, err = tx.ExecContext(ctx, `
+		INSERT INTO archived_reservations (id, product_id, product_sku, store_id, customer_id, quantity, status,
+		                                   expires_at, confirmed_at, created_at, updated_at, archived_at)
+		SELECT id, product_id, ?, store_id, customer_id, quantity,
+		       CASE WHEN status IN (?, ?) THEN ? ELSE status END,
+		       expires_at, confirmed_at, created_at, updated_at, ?
+		FROM reservations
+		WHERE product_id = ?
+	`, product.SKU, domain.ReservationStatusPending, domain.ReservationStatusReadyForPickup, domain.ReservationStatusCancelled, archive.ArchivedAt, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive reservations: %w", err)
+	}
+	reservationRows, _ := result.RowsAffected()
+	archive.ArchivedReservations = int(reservationRows)
+
+	// Borrado explícito de las filas ya archivadas: no depender del ON DELETE CASCADE
+	// (SQLite solo lo aplica con foreign_keys activado)
+	for _, query := range []string{
+		`DELETE FROM reservations WHERE product_id = ?`,
+		`DELETE FROM stock WHERE product_id = ?`,
+		`DELETE FROM scheduled_price_changes WHERE product_id = ?`,
+		`DELETE FROM product_media WHERE product_id = ?`,
+		`DELETE FROM product_translations WHERE product_id = ?`,
+		`DELETE FROM product_units WHERE product_id = ?`,
+		`DELETE FROM channel_allocations WHERE product_id = ?`,
+		`DELETE FROM scheduled_stock_changes WHERE product_id = ?`,
+		`DELETE FROM reservation_imports WHERE product_id = ?`,
+		`DELETE FROM stock_adjustments WHERE product_id = ?`,
+		`DELETE FROM stock_holds WHERE product_id = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, product.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete archived rows: %w", err)
+		}
+	}
+
+	result, err = tx.ExecContext(ctx, `DELETE FROM products WHERE id = ?`, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete product: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, &domain.NotFoundError{Resource: "Product", ID: product.ID}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return archive, nil
+}
+
+// Count retorna el total de productos (los descatalogados solo si includeDiscontinued es true)
+func (r *ProductRepository) Count(ctx context.Context, includeDiscontinued bool) (int, error) {
+	query := `SELECT COUNT(*) FROM products WHERE (? OR status != ?)`
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, includeDiscontinued, domain.ProductStatusDiscontinued).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count products: %w", err)
 	}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// ProductUnitRepository maneja las unidades de medida de los productos
+type ProductUnitRepository struct {
+	db *sql.DB
+}
+
+// NewProductUnitRepository crea una nueva instancia del repositorio
+func NewProductUnitRepository(db *sql.DB) *ProductUnitRepository {
+	return &ProductUnitRepository{db: db}
+}
+
+// Upsert crea o reemplaza las unidades de un producto
+func (r *ProductUnitRepository) Upsert(ctx context.Context, units *domain.ProductUnits) error {
+	conversions, err := json.Marshal(units.Conversions)
+	if err != nil {
+		return fmt.Errorf("failed to encode unit conversions: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO product_units (product_id, base_unit, conversions, rounding, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(product_id) DO UPDATE SET
+			base_unit = excluded.base_unit,
+			conversions = excluded.conversions,
+			rounding = excluded.rounding,
+			updated_at = excluded.updated_at
+	`, units.ProductID, units.BaseUnit, string(conversions), units.Rounding, units.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save product units: %w", err)
+	}
+	return nil
+}
+
+// GetByProduct obtiene las unidades de un producto (NotFoundError si no tiene)
+func (r *ProductUnitRepository) GetByProduct(ctx context.Context, productID string) (*domain.ProductUnits, error) {
+	var units domain.ProductUnits
+	var conversions string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT product_id, base_unit, conversions, rounding, updated_at
+		FROM product_units
+		WHERE product_id = ?
+	`, productID).Scan(&units.ProductID, &units.BaseUnit, &conversions, &units.Rounding, &units.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "ProductUnits", ID: productID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product units: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(conversions), &units.Conversions); err != nil {
+		return nil, fmt.Errorf("failed to decode unit conversions: %w", err)
+	}
+	return &units, nil
+}
+
+// Delete elimina las unidades de un producto
+func (r *ProductUnitRepository) Delete(ctx context.Context, productID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM product_units WHERE product_id = ?`, productID)
+	if err != nil {
+		return fmt.Errorf("failed to delete product units: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "ProductUnits", ID: productID}
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// ReportRepository maneja las consultas agregadas para reportes
+type ReportRepository struct {
+	db *sql.DB
+}
+
+// NewReportRepository crea una nueva instancia del repositorio
+func NewReportRepository(db *sql.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// GetStoreTotals obtiene los totales de stock por tienda (opcionalmente filtrados por categoría
+// y por grupo de tiendas)
+func (r *ReportRepository) GetStoreTotals(ctx context.Context, category, groupID string) ([]domain.StoreInventoryTotals, error) {
+	query := `
+		SELECT s.store_id,
+		       COALESCE(st.name, ''),
+		       COUNT(*),
+		       COALESCE(SUM(s.quantity), 0),
+		       COALESCE(SUM(s.reserved), 0),
+		       COALESCE(SUM(s.held), 0),
+		       COALESCE(SUM(s.quantity - s.reserved), 0),
+		       COALESCE(SUM(CASE WHEN s.quantity - s.reserved <= 0 THEN 1 ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN s.quantity - s.reserved < 0 THEN 1 ELSE 0 END), 0)
+		FROM stock s
+		JOIN products p ON p.id = s.product_id
+		LEFT JOIN stores st ON st.id = s.store_id
+		WHERE (? = '' OR p.category = ?)
+		  AND (? = '' OR s.store_id IN (SELECT store_id FROM store_group_members WHERE group_id = ?))
+		GROUP BY s.store_id, st.name
+		ORDER BY s.store_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, category, category, groupID, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []domain.StoreInventoryTotals
+	for rows.Next() {
+		var t domain.StoreInventoryTotals
+		err := rows.Scan(
+			&t.StoreID,
+			&t.StoreName,
+			&t.Products,
+			&t.Quantity,
+			&t.Reserved,
+			&t.Held,
+			&t.Available,
+			&t.OutOfStock,
+			&t.Oversold,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan store totals: %w", err)
+		}
+		totals = append(totals, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating store totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+// GetLowestAvailability obtiene los N productos con menor cantidad vendible (sin el stock de
+// seguridad) sumada en toda la red (o en las tiendas de un grupo)
+func (r *ReportRepository) GetLowestAvailability(ctx context.Context, category, groupID string, limit int) ([]domain.LowStockProduct, error) {
+	query := `
+		SELECT p.id, p.sku, p.name, COALESCE(p.category, ''),
+		       COALESCE(SUM(s.quantity - s.reserved - s.safety_stock), 0) AS total_available,
+		       COALESCE(SUM(CASE WHEN s.quantity - s.reserved <= 0 THEN 1 ELSE 0 END), 0)
+		FROM products p
+		JOIN stock s ON s.product_id = p.id
+		WHERE (? = '' OR p.category = ?)
+		  AND (? = '' OR s.store_id IN (SELECT store_id FROM store_group_members WHERE group_id = ?))
+		GROUP BY p.id, p.sku, p.name, p.category
+		ORDER BY total_available ASC, p.sku ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, category, category, groupID, groupID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low stock products: %w", err)
+	}
+	defer rows.Close()
+
+	var products []domain.LowStockProduct
+	for rows.Next() {
+		var p domain.LowStockProduct
+		err := rows.Scan(
+			&p.ProductID,
+			&p.SKU,
+			&p.Name,
+			&p.Category,
+			&p.TotalAvailable,
+			&p.StoresOutOfStock,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan low stock product: %w", err)
+		}
+		products = append(products, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating low stock products: %w", err)
+	}
+
+	return products, nil
+}
+
+// CountLowStockEntries cuenta las filas de stock con cantidad vendible por debajo del umbral
+func (r *ReportRepository) CountLowStockEntries(ctx context.Context, threshold int) (int, error) {
+	query := `SELECT COUNT(*) FROM stock WHERE (quantity - reserved - safety_stock) < ?`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, threshold).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count low stock entries: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetLowStockEntries obtiene las N filas de stock por debajo del umbral: primero las de productos
+// de clase A y B y, dentro de cada clase, las de menor cantidad vendible
+func (r *ReportRepository) GetLowStockEntries(ctx context.Context, threshold, limit int) ([]domain.LowStockEntry, error) {
+	query := `
+		SELECT s.product_id, p.sku, p.abc_class, s.store_id, s.quantity, s.reserved, s.safety_stock,
+		       s.quantity - s.reserved - s.safety_stock AS available
+		FROM stock s
+		JOIN products p ON p.id = s.product_id
+		WHERE (s.quantity - s.reserved - s.safety_stock) < ?
+		ORDER BY ` + abcPriority + `, available ASC, p.sku ASC, s.store_id ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, threshold, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low stock entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.LowStockEntry
+	for rows.Next() {
+		var e domain.LowStockEntry
+		err := rows.Scan(
+			&e.ProductID,
+			&e.SKU,
+			&e.ABCClass,
+			&e.StoreID,
+			&e.Quantity,
+			&e.Reserved,
+			&e.SafetyStock,
+			&e.Available,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan low stock entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating low stock entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetOutOfStockEntries obtiene las filas de stock sin disponibilidad (quantity - reserved <= 0),
+// opcionalmente de una sola tienda o de un grupo. OutSince se inicializa con el updated_at de la fila.
+func (r *ReportRepository) GetOutOfStockEntries(ctx context.Context, storeID, groupID string) ([]domain.OutOfStockItem, error) {
+	query := `
+		SELECT s.product_id, p.sku, p.name, COALESCE(p.category, ''), s.store_id,
+		       s.quantity, s.reserved, s.held, s.quantity - s.reserved, s.updated_at
+		FROM stock s
+		JOIN products p ON p.id = s.product_id
+		WHERE (s.quantity - s.reserved) <= 0
+		  AND (? = '' OR s.store_id = ?)
+		  AND (? = '' OR s.store_id IN (SELECT store_id FROM store_group_members WHERE group_id = ?))
+		ORDER BY s.store_id ASC, p.sku ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, storeID, storeID, groupID, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get out of stock entries: %w", err)
+	}
+	defer rows.Close()
+
+	var items []domain.OutOfStockItem
+	for rows.Next() {
+		var item domain.OutOfStockItem
+		err := rows.Scan(
+			&item.ProductID,
+			&item.SKU,
+			&item.Name,
+			&item.Category,
+			&item.StoreID,
+			&item.Quantity,
+			&item.Reserved,
+			&item.Held,
+			&item.Available,
+			&item.OutSince,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan out of stock entry: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating out of stock entries: %w", err)
+	}
+
+	return items, nil
+}
+
+// GetLastAvailabilityChange obtiene el instante del último evento que alteró la disponibilidad
+// de un producto en una tienda: altas y ajustes de stock, retenciones internas, transferencias
+// (origen o destino) y reservas creadas, canceladas o expiradas. Las confirmaciones no cambian
+// la disponibilidad.
+// Retorna nil si no hay movimientos registrados.
+func (r *ReportRepository) GetLastAvailabilityChange(ctx context.Context, productID, storeID string) (*time.Time, error) {
+	query := `
+		SELECT created_at
+		FROM events
+		WHERE (event_type IN ('stock.created', 'stock.updated', 'stock.hold_placed', 'stock.hold_released')
+		       AND aggregate_id = ? AND store_id = ?)
+		   OR (event_type = 'stock.transferred' AND aggregate_id = ?
+		       AND (store_id = ? OR json_extract(payload, '$.to_store_id') = ?))
+		   OR (event_type IN ('reservation.created', 'reservation.cancelled', 'reservation.expired')
+		       AND store_id = ?
+		       AND aggregate_id IN (SELECT id FROM reservations WHERE product_id = ? AND store_id = ?))
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var at time.Time
+	err := r.db.QueryRowContext(ctx, query,
+		productID, storeID,
+		productID, storeID, storeID,
+		storeID, productID, storeID,
+	).Scan(&at)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last availability change: %w", err)
+	}
+
+	return &at, nil
+}
+
+// GetAdjustmentsByReason agrupa por motivo los movimientos stock.updated que registran uno
+// (ajustes manuales), opcionalmente de una tienda y en [from, to). Los de más movimientos primero.
+func (r *ReportRepository) GetAdjustmentsByReason(ctx context.Context, storeID string, from, to *time.Time) ([]domain.AdjustmentReasonSummary, error) {
+	conditions := []string{"event_type = ?", "COALESCE(json_extract(payload, '$.reason'), '') <> ''"}
+	args := []interface{}{domain.EventStockUpdated}
+	if storeID != "" {
+		conditions = append(conditions, "store_id = ?")
+		args = append(args, storeID)
+	}
+	if from != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *from)
+	}
+	if to != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *to)
+	}
+
+	query := `
+		SELECT reason,
+		       COUNT(*),
+		       COALESCE(SUM(CASE WHEN delta > 0 THEN delta ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN delta < 0 THEN -delta ELSE 0 END), 0)
+		FROM (
+			SELECT json_extract(payload, '$.reason') AS reason,
+			       json_extract(payload, '$.new_quantity') - json_extract(payload, '$.old_quantity') AS delta
+			FROM events
+			WHERE ` + strings.Join(conditions, " AND ") + `
+		)
+		GROUP BY reason
+		ORDER BY COUNT(*) DESC, reason
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adjustments by reason: %w", err)
+	}
+	defer rows.Close()
+
+	items := []domain.AdjustmentReasonSummary{}
+	for rows.Next() {
+		var item domain.AdjustmentReasonSummary
+		if err := rows.Scan(&item.Reason, &item.Adjustments, &item.UnitsAdded, &item.UnitsRemoved); err != nil {
+			return nil, fmt.Errorf("failed to scan adjustments by reason: %w", err)
+		}
+		item.NetUnits = item.UnitsAdded - item.UnitsRemoved
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating adjustments by reason: %w", err)
+	}
+
+	return items, nil
+}
+
+// GetKPIEvents obtiene los eventos de [from, to) de los que se derivan los KPIs de negocio,
+// opcionalmente de una tienda: reservas creadas, confirmadas (o recogidas), canceladas y caducadas, y
+// stock.updated que dejan una fila sin unidades (old_quantity > 0, new_quantity <= 0).
+func (r *ReportRepository) GetKPIEvents(ctx context.Context, storeID string, from, to time.Time) ([]domain.KPIEvent, error) {
+	query := `
+		SELECT CASE event_type
+		           WHEN ? THEN ?
+		           WHEN ? THEN ?
+		           WHEN ? THEN ?
+		           WHEN ? THEN ?
+		           WHEN ? THEN ?
+		           ELSE ?
+		       END,
+		       created_at
+		FROM events
+		WHERE created_at >= ? AND created_at < ?
+		  AND (event_type IN (?, ?, ?, ?, ?)
+		       OR (event_type = ?
+		           AND json_extract(payload, '$.old_quantity') > 0
+		           AND json_extract(payload, '$.new_quantity') <= 0))
+	`
+	args := []interface{}{
+		domain.EventReservationCreated, domain.KPIEventReservationCreated,
+		domain.EventReservationConfirmed, domain.KPIEventReservationConfirmed,
+		domain.EventReservationPickedUp, domain.KPIEventReservationConfirmed,
+		domain.EventReservationCancelled, domain.KPIEventReservationCancelled,
+		domain.EventReservationExpired, domain.KPIEventReservationExpired,
+		domain.KPIEventStockOut,
+		from, to,
+		domain.EventReservationCreated, domain.EventReservationConfirmed, domain.EventReservationPickedUp,
+		domain.EventReservationCancelled, domain.EventReservationExpired,
+		domain.EventStockUpdated,
+	}
+	if storeID != "" {
+		query += " AND store_id = ?"
+		args = append(args, storeID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KPI events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.KPIEvent{}
+	for rows.Next() {
+		var event domain.KPIEvent
+		if err := rows.Scan(&event.Kind, &event.At); err != nil {
+			return nil, fmt.Errorf("failed to scan KPI event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating KPI events: %w", err)
+	}
+
+	return events, nil
+}
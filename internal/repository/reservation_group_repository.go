@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// ReservationGroupRepository maneja los grupos de reservas (envío partido) y sus reservas hijas
+type ReservationGroupRepository struct {
+	db *sql.DB
+}
+
+// NewReservationGroupRepository crea una nueva instancia del repositorio
+func NewReservationGroupRepository(db *sql.DB) *ReservationGroupRepository {
+	return &ReservationGroupRepository{db: db}
+}
+
+// Create inserta el grupo y sus reservas hijas (en el orden del grupo) en una transacción
+func (r *ReservationGroupRepository) Create(ctx context.Context, group *domain.ReservationGroup) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO reservation_groups (id, customer_id, created_at)
+		VALUES (?, ?, ?)
+	`, group.ID, group.CustomerID, group.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create reservation group: %w", err)
+	}
+
+	for i, reservation := range group.Reservations {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO reservation_group_members (reservation_id, group_id, position)
+			VALUES (?, ?, ?)
+		`, reservation.ID, group.ID, i)
+		if err != nil {
+			return fmt.Errorf("failed to add reservation to group: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByID obtiene el grupo sin sus reservas (ver ReservationRepository.GetByGroup)
+func (r *ReservationGroupRepository) GetByID(ctx context.Context, id string) (*domain.ReservationGroup, error) {
+	var group domain.ReservationGroup
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, customer_id, created_at FROM reservation_groups WHERE id = ?
+	`, id).Scan(&group.ID, &group.CustomerID, &group.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "ReservationGroup", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation group: %w", err)
+	}
+	return &group, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// ReservationIntentRepository maneja las intenciones de reserva (reservas blandas sin bloqueo de stock)
+type ReservationIntentRepository struct {
+	db *sql.DB
+}
+
+// NewReservationIntentRepository crea una nueva instancia del repositorio
+func NewReservationIntentRepository(db *sql.DB) *ReservationIntentRepository {
+	return &ReservationIntentRepository{db: db}
+}
+
+// Create inserta una intención
+func (r *ReservationIntentRepository) Create(ctx context.Context, intent *domain.ReservationIntent) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO reservation_intents (token, product_id, store_id, customer_id, quantity, available_quantity, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, intent.Token, intent.ProductID, intent.StoreID, intent.CustomerID, intent.Quantity,
+		intent.AvailableQuantity, intent.ExpiresAt, intent.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create reservation intent: %w", err)
+	}
+	return nil
+}
+
+// GetByToken obtiene una intención (NotFoundError si no existe)
+func (r *ReservationIntentRepository) GetByToken(ctx context.Context, token string) (*domain.ReservationIntent, error) {
+	var intent domain.ReservationIntent
+	var reservationID sql.NullString
+	var convertedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT token, product_id, store_id, customer_id, quantity, available_quantity,
+		       reservation_id, expires_at, converted_at, created_at
+		FROM reservation_intents
+		WHERE token = ?
+	`, token).Scan(
+		&intent.Token,
+		&intent.ProductID,
+		&intent.StoreID,
+		&intent.CustomerID,
+		&intent.Quantity,
+		&intent.AvailableQuantity,
+		&reservationID,
+		&intent.ExpiresAt,
+		&convertedAt,
+		&intent.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "ReservationIntent", ID: token}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation intent: %w", err)
+	}
+
+	intent.ReservationID = reservationID.String
+	intent.Available = intent.AvailableQuantity >= intent.Quantity
+	if convertedAt.Valid {
+		intent.ConvertedAt = &convertedAt.Time
+	}
+	return &intent, nil
+}
+
+// Claim marca la intención como convertida si sigue vigente y sin convertir.
+// Retorna false si otra petición la reclamó antes o ya caducó.
+func (r *ReservationIntentRepository) Claim(ctx context.Context, token string, now time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE reservation_intents
+		SET converted_at = ?
+		WHERE token = ? AND converted_at IS NULL AND expires_at > ?
+	`, now, token, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim reservation intent: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n == 1, nil
+}
+
+// Unclaim deshace Claim cuando la reserva no se pudo crear
+func (r *ReservationIntentRepository) Unclaim(ctx context.Context, token string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE reservation_intents SET converted_at = NULL WHERE token = ? AND reservation_id IS NULL
+	`, token)
+	if err != nil {
+		return fmt.Errorf("failed to release reservation intent: %w", err)
+	}
+	return nil
+}
+
+// SetReservation vincula la reserva creada a la intención reclamada
+func (r *ReservationIntentRepository) SetReservation(ctx context.Context, token, reservationID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE reservation_intents SET reservation_id = ? WHERE token = ?
+	`, reservationID, token)
+	if err != nil {
+		return fmt.Errorf("failed to link reservation intent: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredBefore elimina las intenciones caducadas antes de la fecha indicada
+func (r *ReservationIntentRepository) DeleteExpiredBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM reservation_intents WHERE expires_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge reservation intents: %w", err)
+	}
+	return result.RowsAffected()
+}
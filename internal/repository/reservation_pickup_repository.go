@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// ReservationPickupRepository maneja las recogidas en tienda (click-and-collect) de las reservas
+type ReservationPickupRepository struct {
+	db *sql.DB
+}
+
+// NewReservationPickupRepository crea una nueva instancia del repositorio
+func NewReservationPickupRepository(db *sql.DB) *ReservationPickupRepository {
+	return &ReservationPickupRepository{db: db}
+}
+
+// MarkReady pasa la reserva a READY_FOR_PICKUP con el plazo de recogida como nueva expiración y
+// guarda la recogida, en una transacción. Retorna false si la reserva ya no estaba PENDING
+// (p. ej. se confirmó o caducó mientras tanto).
+func (r *ReservationPickupRepository) MarkReady(ctx context.Context, pickup *domain.ReservationPickup) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE reservations
+		SET status = ?, expires_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?
+	`, domain.ReservationStatusReadyForPickup, pickup.PickupBy, pickup.ReservationID, domain.ReservationStatusPending)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark reservation ready for pickup: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO reservation_pickups (reservation_id, store_id, code_hash, attempts, ready_at, pickup_by)
+		VALUES (?, ?, ?, 0, ?, ?)
+	`, pickup.ReservationID, pickup.StoreID, pickup.CodeHash, pickup.ReadyAt, pickup.PickupBy)
+	if err != nil {
+		return false, fmt.Errorf("failed to save reservation pickup: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetByReservation obtiene la recogida de una reserva (NotFoundError si no se marcó como lista)
+func (r *ReservationPickupRepository) GetByReservation(ctx context.Context, reservationID string) (*domain.ReservationPickup, error) {
+	var pickup domain.ReservationPickup
+	var pickedUpAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT reservation_id, store_id, code_hash, attempts, ready_at, pickup_by, picked_up_at
+		FROM reservation_pickups
+		WHERE reservation_id = ?
+	`, reservationID).Scan(
+		&pickup.ReservationID,
+		&pickup.StoreID,
+		&pickup.CodeHash,
+		&pickup.Attempts,
+		&pickup.ReadyAt,
+		&pickup.PickupBy,
+		&pickedUpAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "ReservationPickup", ID: reservationID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation pickup: %w", err)
+	}
+
+	if pickedUpAt.Valid {
+		pickup.PickedUpAt = &pickedUpAt.Time
+	}
+	return &pickup, nil
+}
+
+// RecordFailedAttempt suma un código erróneo a la recogida y retorna los intentos acumulados
+func (r *ReservationPickupRepository) RecordFailedAttempt(ctx context.Context, reservationID string) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE reservation_pickups SET attempts = attempts + 1 WHERE reservation_id = ?
+	`, reservationID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record pickup attempt: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return 0, &domain.NotFoundError{Resource: "ReservationPickup", ID: reservationID}
+	}
+
+	var attempts int
+	err = r.db.QueryRowContext(ctx, `SELECT attempts FROM reservation_pickups WHERE reservation_id = ?`, reservationID).Scan(&attempts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pickup attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// MarkPickedUp registra el instante de la recogida
+func (r *ReservationPickupRepository) MarkPickedUp(ctx context.Context, reservationID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE reservation_pickups SET picked_up_at = ? WHERE reservation_id = ?
+	`, at, reservationID)
+	if err != nil {
+		return fmt.Errorf("failed to mark reservation picked up: %w", err)
+	}
+	return nil
+}
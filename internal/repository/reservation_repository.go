@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"inventory-system/internal/domain"
@@ -22,8 +23,8 @@ func NewReservationRepository(db *sql.DB) *ReservationRepository {
 // Create crea una nueva reserva
 func (r *ReservationRepository) Create(ctx context.Context, reservation *domain.Reservation) error {
 	query := `
-		INSERT INTO reservations (id, product_id, store_id, customer_id, quantity, status, expires_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO reservations (id, product_id, store_id, customer_id, quantity, status, priority, channel, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	updatedAt := reservation.CreatedAt // Por defecto, igual a created_at
@@ -38,6 +39,8 @@ func (r *ReservationRepository) Create(ctx context.Context, reservation *domain.
 		reservation.CustomerID,
 		reservation.Quantity,
 		reservation.Status,
+		reservationPriority(reservation),
+		reservation.Channel,
 		reservation.ExpiresAt,
 		reservation.CreatedAt,
 		updatedAt,
@@ -50,22 +53,105 @@ func (r *ReservationRepository) Create(ctx context.Context, reservation *domain.
 	return nil
 }
 
+// CreateWithinLimits crea la reserva solo si el cliente no supera sus límites de retención.
+// La comprobación y la inserción son una única sentencia, así que dos peticiones
+// concurrentes del mismo cliente no pueden superar el límite entre ambas.
+func (r *ReservationRepository) CreateWithinLimits(ctx context.Context, reservation *domain.Reservation, limits domain.CustomerHoldLimits) error {
+	if !limits.Enabled() {
+		return r.Create(ctx, reservation)
+	}
+
+	query := `
+		INSERT INTO reservations (id, product_id, store_id, customer_id, quantity, status, priority, channel, expires_at, created_at, updated_at)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE (? = 0 OR (
+			SELECT COUNT(*) FROM reservations
+			WHERE customer_id = ? AND status IN ('PENDING', 'READY_FOR_PICKUP') AND expires_at > ?
+		) < ?)
+		AND (? = 0 OR (
+			SELECT COALESCE(SUM(quantity), 0) FROM reservations
+			WHERE customer_id = ? AND product_id = ? AND status IN ('PENDING', 'READY_FOR_PICKUP') AND expires_at > ?
+		) + ? <= ?)
+	`
+
+	updatedAt := reservation.CreatedAt
+	if reservation.UpdatedAt != nil {
+		updatedAt = *reservation.UpdatedAt
+	}
+	now := reservation.CreatedAt
+
+	result, err := r.db.ExecContext(ctx, query,
+		reservation.ID,
+		reservation.ProductID,
+		reservation.StoreID,
+		reservation.CustomerID,
+		reservation.Quantity,
+		reservation.Status,
+		reservationPriority(reservation),
+		reservation.Channel,
+		reservation.ExpiresAt,
+		reservation.CreatedAt,
+		updatedAt,
+		limits.MaxPendingReservations, reservation.CustomerID, now, limits.MaxPendingReservations,
+		limits.MaxUnitsPerProduct, reservation.CustomerID, reservation.ProductID, now, reservation.Quantity, limits.MaxUnitsPerProduct,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create reservation: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		holds, err := r.GetCustomerHolds(ctx, reservation.CustomerID, reservation.ProductID, now)
+		if err != nil {
+			return err
+		}
+		if err := limits.Check(reservation.CustomerID, reservation.ProductID, holds, reservation.Quantity); err != nil {
+			return err
+		}
+		// Otra reserva del cliente se liberó entre la inserción y la comprobación
+		return &domain.ConflictError{Message: "customer hold limits changed concurrently, retry"}
+	}
+
+	return nil
+}
+
+// GetCustomerHolds obtiene las reservas pendientes no expiradas de un cliente y las unidades
+// que retiene de un producto en todas las tiendas
+func (r *ReservationRepository) GetCustomerHolds(ctx context.Context, customerID, productID string, now time.Time) (domain.CustomerHolds, error) {
+	var holds domain.CustomerHolds
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN product_id = ? THEN quantity ELSE 0 END), 0)
+		FROM reservations
+		WHERE customer_id = ? AND status IN ('PENDING', 'READY_FOR_PICKUP') AND expires_at > ?
+	`, productID, customerID, now).Scan(&holds.PendingReservations, &holds.ProductUnits)
+	if err != nil {
+		return holds, fmt.Errorf("failed to get customer holds: %w", err)
+	}
+
+	return holds, nil
+}
+
 // GetByID obtiene una reserva por su ID
 func (r *ReservationRepository) GetByID(ctx context.Context, id string) (*domain.Reservation, error) {
 	query := `
-		SELECT id, product_id, store_id, quantity, status, expires_at, created_at, updated_at
+		SELECT id, product_id, store_id, customer_id, quantity, status, priority, channel, expires_at, confirmed_at, created_at, updated_at
 		FROM reservations
 		WHERE id = ?
 	`
 
 	var reservation domain.Reservation
+	var confirmedAt sql.NullTime
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&reservation.ID,
 		&reservation.ProductID,
 		&reservation.StoreID,
+		&reservation.CustomerID,
 		&reservation.Quantity,
 		&reservation.Status,
+		&reservation.Priority,
+		&reservation.Channel,
 		&reservation.ExpiresAt,
+		&confirmedAt,
 		&reservation.CreatedAt,
 		&reservation.UpdatedAt,
 	)
@@ -80,19 +166,84 @@ func (r *ReservationRepository) GetByID(ctx context.Context, id string) (*domain
 		return nil, fmt.Errorf("failed to get reservation: %w", err)
 	}
 
+	if confirmedAt.Valid {
+		reservation.ConfirmedAt = &confirmedAt.Time
+	}
+
 	return &reservation, nil
 }
 
-// UpdateStatus actualiza el estado de una reserva
-func (r *ReservationRepository) UpdateStatus(ctx context.Context, id string, status domain.ReservationStatus) error {
+// UpdateStatus cambia el estado de una reserva de from a to (compare-and-set). Retorna
+// InvalidStateError si la reserva ya no estaba en from (otra petición la cambió antes).
+func (r *ReservationRepository) UpdateStatus(ctx context.Context, id string, from, to domain.ReservationStatus) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateReservationStatus(ctx, tx, id, from, to, time.Now(), "update reservation status"); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyTransition persiste una transición del ciclo de vida en una transacción: cambia el estado
+// solo si la reserva sigue en transition.From y aplica su efecto sobre el stock (confirmar la
+// venta o liberar las unidades). Si otra petición ganó la carrera retorna InvalidStateError y el
+// stock queda intacto.
+func (r *ReservationRepository) ApplyTransition(ctx context.Context, transition *domain.ReservationTransition) error {
+	reservation := transition.Reservation
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = updateReservationStatus(ctx, tx, reservation.ID, transition.From, transition.To, transition.At, string(transition.Action)+" reservation")
+	if err != nil {
+		return err
+	}
+
+	switch transition.StockEffect() {
+	case domain.ReservationStockConfirm:
+		err = confirmChannelStock(ctx, tx, reservation.ProductID, reservation.StoreID, reservation.Channel, reservation.Quantity)
+		if err != nil {
+			return fmt.Errorf("failed to confirm in stock: %w", err)
+		}
+	case domain.ReservationStockRelease:
+		err = releaseChannelStock(ctx, tx, reservation.ProductID, reservation.StoreID, reservation.Channel, reservation.Quantity)
+		if err != nil {
+			return fmt.Errorf("failed to release reserved stock: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// updateReservationStatus cambia el estado dentro de tx si la reserva sigue en from. Sin filas
+// afectadas distingue la reserva inexistente (NotFoundError) de la que cambió de estado
+// (InvalidStateError con el estado actual).
+func updateReservationStatus(ctx context.Context, tx *sql.Tx, id string, from, to domain.ReservationStatus, at time.Time, action string) error {
 	query := `
 		UPDATE reservations
 		SET status = ?,
+		    confirmed_at = CASE WHEN ? IN ('CONFIRMED', 'PICKED_UP') THEN ? ELSE confirmed_at END,
 		    updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
+		WHERE id = ? AND status = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query, status, id)
+	result, err := tx.ExecContext(ctx, query, to, to, at, id, from)
 	if err != nil {
 		return fmt.Errorf("failed to update reservation status: %w", err)
 	}
@@ -101,28 +252,40 @@ func (r *ReservationRepository) UpdateStatus(ctx context.Context, id string, sta
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
+	if rowsAffected > 0 {
+		return nil
+	}
 
-	if rowsAffected == 0 {
+	var current string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM reservations WHERE id = ?`, id).Scan(&current)
+	if err == sql.ErrNoRows {
 		return &domain.NotFoundError{
 			Resource: "Reservation",
 			ID:       id,
 		}
 	}
+	if err != nil {
+		return fmt.Errorf("failed to get reservation status: %w", err)
+	}
 
-	return nil
+	return &domain.InvalidStateError{
+		CurrentState:    current,
+		AttemptedAction: action,
+	}
 }
 
-// GetPendingExpired obtiene todas las reservas pendientes que ya expiraron
+// GetPendingExpired obtiene todas las reservas pendientes que ya expiraron y las listas para
+// recoger cuyo plazo de recogida terminó
 func (r *ReservationRepository) GetPendingExpired(ctx context.Context) ([]*domain.Reservation, error) {
 	query := `
-		SELECT id, product_id, store_id, quantity, status, expires_at, created_at, updated_at
+		SELECT id, product_id, store_id, quantity, status, priority, channel, expires_at, created_at, updated_at
 		FROM reservations
-		WHERE status = ?
+		WHERE status IN (?, ?)
 		  AND expires_at < ?
 		ORDER BY expires_at ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, domain.ReservationStatusPending, time.Now())
+	rows, err := r.db.QueryContext(ctx, query, domain.ReservationStatusPending, domain.ReservationStatusReadyForPickup, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expired reservations: %w", err)
 	}
@@ -137,6 +300,8 @@ func (r *ReservationRepository) GetPendingExpired(ctx context.Context) ([]*domai
 			&reservation.StoreID,
 			&reservation.Quantity,
 			&reservation.Status,
+			&reservation.Priority,
+			&reservation.Channel,
 			&reservation.ExpiresAt,
 			&reservation.CreatedAt,
 			&reservation.UpdatedAt,
@@ -156,25 +321,48 @@ func (r *ReservationRepository) GetPendingExpired(ctx context.Context) ([]*domai
 
 // GetByProductAndStore obtiene todas las reservas de un producto en una tienda
 func (r *ReservationRepository) GetByProductAndStore(ctx context.Context, productID, storeID string, status *domain.ReservationStatus) ([]*domain.Reservation, error) {
-	var query string
-	var args []interface{}
+	return r.List(ctx, domain.ReservationFilter{
+		ProductID: productID,
+		StoreID:   storeID,
+		Status:    status,
+		SortBy:    domain.ReservationSortCreatedAt,
+		SortDesc:  true,
+	})
+}
+
+// GetPendingByStore obtiene todas las reservas pendientes de una tienda
+func (r *ReservationRepository) GetPendingByStore(ctx context.Context, storeID string) ([]*domain.Reservation, error) {
+	status := domain.ReservationStatusPending
+	return r.List(ctx, domain.ReservationFilter{
+		StoreID: storeID,
+		Status:  &status,
+		SortBy:  domain.ReservationSortExpiresAt,
+	})
+}
+
+// List obtiene las reservas que cumplen el filtro, ordenadas y paginadas.
+// Con Limit <= 0 no se aplica límite.
+func (r *ReservationRepository) List(ctx context.Context, filter domain.ReservationFilter) ([]*domain.Reservation, error) {
+	where, args := reservationFilterClause(filter)
 
-	if status != nil {
-		query = `
-			SELECT id, product_id, store_id, quantity, status, expires_at, created_at, updated_at
-			FROM reservations
-			WHERE product_id = ? AND store_id = ? AND status = ?
-			ORDER BY created_at DESC
-		`
-		args = []interface{}{productID, storeID, *status}
-	} else {
-		query = `
-			SELECT id, product_id, store_id, quantity, status, expires_at, created_at, updated_at
-			FROM reservations
-			WHERE product_id = ? AND store_id = ?
-			ORDER BY created_at DESC
-		`
-		args = []interface{}{productID, storeID}
+	sortColumn := "created_at"
+	if filter.SortBy == domain.ReservationSortExpiresAt {
+		sortColumn = "expires_at"
+	}
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+
+	// id como desempate para que la paginación sea estable
+	query := `
+		SELECT id, product_id, store_id, customer_id, quantity, status, priority, channel, expires_at, confirmed_at, created_at, updated_at
+		FROM reservations` + where + `
+		ORDER BY ` + sortColumn + ` ` + direction + `, id ` + direction
+
+	if filter.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, filter.Limit, filter.Offset)
 	}
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -186,19 +374,27 @@ func (r *ReservationRepository) GetByProductAndStore(ctx context.Context, produc
 	var reservations []*domain.Reservation
 	for rows.Next() {
 		var reservation domain.Reservation
+		var confirmedAt sql.NullTime
 		err := rows.Scan(
 			&reservation.ID,
 			&reservation.ProductID,
 			&reservation.StoreID,
+			&reservation.CustomerID,
 			&reservation.Quantity,
 			&reservation.Status,
+			&reservation.Priority,
+			&reservation.Channel,
 			&reservation.ExpiresAt,
+			&confirmedAt,
 			&reservation.CreatedAt,
 			&reservation.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan reservation: %w", err)
 		}
+		if confirmedAt.Valid {
+			reservation.ConfirmedAt = &confirmedAt.Time
+		}
 		reservations = append(reservations, &reservation)
 	}
 
@@ -209,45 +405,53 @@ func (r *ReservationRepository) GetByProductAndStore(ctx context.Context, produc
 	return reservations, nil
 }
 
-// GetPendingByStore obtiene todas las reservas pendientes de una tienda
-func (r *ReservationRepository) GetPendingByStore(ctx context.Context, storeID string) ([]*domain.Reservation, error) {
-	query := `
-		SELECT id, product_id, store_id, quantity, status, expires_at, created_at, updated_at
-		FROM reservations
-		WHERE store_id = ? AND status = ?
-		ORDER BY expires_at ASC
-	`
+// Count retorna el total de reservas que cumplen el filtro (ignora orden y paginación)
+func (r *ReservationRepository) Count(ctx context.Context, filter domain.ReservationFilter) (int, error) {
+	where, args := reservationFilterClause(filter)
 
-	rows, err := r.db.QueryContext(ctx, query, storeID, domain.ReservationStatusPending)
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM reservations`+where, args...).Scan(&count)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pending reservations: %w", err)
+		return 0, fmt.Errorf("failed to count reservations: %w", err)
 	}
-	defer rows.Close()
 
-	var reservations []*domain.Reservation
-	for rows.Next() {
-		var reservation domain.Reservation
-		err := rows.Scan(
-			&reservation.ID,
-			&reservation.ProductID,
-			&reservation.StoreID,
-			&reservation.Quantity,
-			&reservation.Status,
-			&reservation.ExpiresAt,
-			&reservation.CreatedAt,
-			&reservation.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan reservation: %w", err)
-		}
-		reservations = append(reservations, &reservation)
-	}
+	return count, nil
+}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating reservations: %w", err)
+// reservationFilterClause construye el WHERE parametrizado de un filtro de reservas
+func reservationFilterClause(filter domain.ReservationFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.ProductID != "" {
+		conditions = append(conditions, "product_id = ?")
+		args = append(args, filter.ProductID)
+	}
+	if filter.StoreID != "" {
+		conditions = append(conditions, "store_id = ?")
+		args = append(args, filter.StoreID)
+	}
+	if filter.CustomerID != "" {
+		conditions = append(conditions, "customer_id = ?")
+		args = append(args, filter.CustomerID)
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, "status = ?")
+		args = append(args, *filter.Status)
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.CreatedTo)
 	}
 
-	return reservations, nil
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "\n\t\tWHERE " + strings.Join(conditions, " AND "), args
 }
 
 // Delete elimina una reserva (usado para limpieza de reservas antiguas)
@@ -274,16 +478,110 @@ func (r *ReservationRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// priorityRank ordena las reservas por prioridad (LOW = 0, NORMAL = 1, HIGH = 2)
+const priorityRank = `CASE priority WHEN 'LOW' THEN 0 WHEN 'HIGH' THEN 2 ELSE 1 END`
+
+// reservationPriority retorna la prioridad a persistir (vacía = NORMAL)
+func reservationPriority(reservation *domain.Reservation) domain.ReservationPriority {
+	if reservation.Priority == "" {
+		return domain.ReservationPriorityNormal
+	}
+	return reservation.Priority
+}
+
+// GetPendingByReleaseOrder obtiene las reservas pendientes de un producto en una tienda en el
+// orden en que deben liberarse ante falta de stock: menor prioridad primero y, a igual
+// prioridad, las más recientes primero
+func (r *ReservationRepository) GetPendingByReleaseOrder(ctx context.Context, productID, storeID string) ([]*domain.Reservation, error) {
+	return r.queryReservations(ctx, `
+		SELECT id, product_id, store_id, customer_id, quantity, status, priority, channel, expires_at, confirmed_at, created_at, updated_at
+		FROM reservations
+		WHERE product_id = ? AND store_id = ? AND status = ?
+		ORDER BY `+priorityRank+` ASC, created_at DESC, id DESC
+	`, productID, storeID, domain.ReservationStatusPending)
+}
+
+// GetLowPriorityUnderShortage obtiene las reservas pendientes de prioridad LOW creadas antes de
+// createdBefore cuyo producto no tiene cantidad vendible en la tienda (quantity - reserved - safety_stock <= 0)
+func (r *ReservationRepository) GetLowPriorityUnderShortage(ctx context.Context, createdBefore time.Time) ([]*domain.Reservation, error) {
+	return r.queryReservations(ctx, `
+		SELECT r.id, r.product_id, r.store_id, r.customer_id, r.quantity, r.status, r.priority, r.channel,
+		       r.expires_at, r.confirmed_at, r.created_at, r.updated_at
+		FROM reservations r
+		JOIN stock s ON s.product_id = r.product_id AND s.store_id = r.store_id
+		WHERE r.status = ?
+		  AND r.priority = ?
+		  AND r.created_at < ?
+		  AND s.quantity - s.reserved - s.safety_stock <= 0
+		ORDER BY r.created_at ASC
+	`, domain.ReservationStatusPending, domain.ReservationPriorityLow, createdBefore)
+}
+
+// GetByGroup obtiene las reservas hijas de un grupo en el orden del grupo
+func (r *ReservationRepository) GetByGroup(ctx context.Context, groupID string) ([]*domain.Reservation, error) {
+	return r.queryReservations(ctx, `
+		SELECT r.id, r.product_id, r.store_id, r.customer_id, r.quantity, r.status, r.priority, r.channel,
+		       r.expires_at, r.confirmed_at, r.created_at, r.updated_at
+		FROM reservation_group_members m
+		JOIN reservations r ON r.id = m.reservation_id
+		WHERE m.group_id = ?
+		ORDER BY m.position ASC
+	`, groupID)
+}
+
+// queryReservations ejecuta una consulta que selecciona todas las columnas de reservations
+func (r *ReservationRepository) queryReservations(ctx context.Context, query string, args ...interface{}) ([]*domain.Reservation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservations: %w", err)
+	}
+	defer rows.Close()
+
+	var reservations []*domain.Reservation
+	for rows.Next() {
+		var reservation domain.Reservation
+		var confirmedAt sql.NullTime
+		err := rows.Scan(
+			&reservation.ID,
+			&reservation.ProductID,
+			&reservation.StoreID,
+			&reservation.CustomerID,
+			&reservation.Quantity,
+			&reservation.Status,
+			&reservation.Priority,
+			&reservation.Channel,
+			&reservation.ExpiresAt,
+			&confirmedAt,
+			&reservation.CreatedAt,
+			&reservation.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		if confirmedAt.Valid {
+			reservation.ConfirmedAt = &confirmedAt.Time
+		}
+		reservations = append(reservations, &reservation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
 // DeleteOldCompleted elimina reservas completadas/canceladas antiguas
 func (r *ReservationRepository) DeleteOldCompleted(ctx context.Context, olderThan time.Time) (int64, error) {
 	query := `
 		DELETE FROM reservations
-		WHERE status IN (?, ?)
+		WHERE status IN (?, ?, ?)
 		  AND updated_at < ?
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
 		domain.ReservationStatusConfirmed,
+		domain.ReservationStatusPickedUp,
 		domain.ReservationStatusCancelled,
 		olderThan,
 	)
@@ -300,6 +598,59 @@ func (r *ReservationRepository) DeleteOldCompleted(ctx context.Context, olderTha
 	return rowsAffected, nil
 }
 
+// ListTerminalBefore obtiene hasta limit reservas CONFIRMED, PICKED_UP, CANCELLED o EXPIRED cuyo último
+// cambio de estado es anterior a olderThan, de la más antigua a la más reciente
+func (r *ReservationRepository) ListTerminalBefore(ctx context.Context, olderThan time.Time, limit int) ([]*domain.Reservation, error) {
+	return r.queryReservations(ctx, `
+		SELECT id, product_id, store_id, customer_id, quantity, status, priority, channel, expires_at, confirmed_at, created_at, updated_at
+		FROM reservations
+		WHERE status IN (?, ?, ?, ?) AND updated_at < ?
+		ORDER BY updated_at ASC, id ASC
+		LIMIT ?
+	`, domain.ReservationStatusConfirmed, domain.ReservationStatusPickedUp, domain.ReservationStatusCancelled, domain.ReservationStatusExpired, olderThan, limit)
+}
+
+// CountTerminalBefore cuenta las reservas que ListTerminalBefore recorrería
+func (r *ReservationRepository) CountTerminalBefore(ctx context.Context, olderThan time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM reservations
+		WHERE status IN (?, ?, ?, ?) AND updated_at < ?
+	`, domain.ReservationStatusConfirmed, domain.ReservationStatusPickedUp, domain.ReservationStatusCancelled, domain.ReservationStatusExpired, olderThan).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count reservations: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteByIDs elimina las reservas indicadas
+func (r *ReservationRepository) DeleteByIDs(ctx context.Context, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM reservations WHERE id IN (`+strings.Join(placeholders, ", ")+`)`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete reservations: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
 // CountByStatus cuenta las reservas por estado
 func (r *ReservationRepository) CountByStatus(ctx context.Context, status domain.ReservationStatus) (int, error) {
 	query := `SELECT COUNT(*) FROM reservations WHERE status = ?`
@@ -312,3 +663,296 @@ func (r *ReservationRepository) CountByStatus(ctx context.Context, status domain
 
 	return count, nil
 }
+
+// SumPendingExpiringBefore suma las unidades de las reservas pendientes (o listas para recoger) de
+// una fila de stock que caducan antes de before
+func (r *ReservationRepository) SumPendingExpiringBefore(ctx context.Context, productID, storeID string, before time.Time) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(quantity), 0)
+		FROM reservations
+		WHERE product_id = ? AND store_id = ? AND status IN (?, ?) AND expires_at < ?
+	`
+
+	var total int
+	err := r.db.QueryRowContext(ctx, query, productID, storeID, domain.ReservationStatusPending, domain.ReservationStatusReadyForPickup, before).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum expiring reservations: %w", err)
+	}
+
+	return total, nil
+}
+
+// GetPendingByExpiry obtiene las reservas pendientes de una fila de stock de la que caduca antes
+// a la que caduca después
+func (r *ReservationRepository) GetPendingByExpiry(ctx context.Context, productID, storeID string) ([]*domain.Reservation, error) {
+	return r.queryReservations(ctx, `
+		SELECT id, product_id, store_id, customer_id, quantity, status, priority, channel, expires_at, confirmed_at, created_at, updated_at
+		FROM reservations
+		WHERE product_id = ? AND store_id = ? AND status = ?
+		ORDER BY expires_at ASC, id ASC
+	`, productID, storeID, domain.ReservationStatusPending)
+}
+
+// GetPendingExpiringUnnotified obtiene las reservas pendientes de una tienda que caducan en
+// (from, to] y todavía no tienen aviso de vencimiento próximo, de la que caduca antes a la que
+// caduca después
+func (r *ReservationRepository) GetPendingExpiringUnnotified(ctx context.Context, storeID string, from, to time.Time) ([]*domain.Reservation, error) {
+	return r.queryReservations(ctx, `
+		SELECT r.id, r.product_id, r.store_id, r.customer_id, r.quantity, r.status, r.priority, r.channel,
+		       r.expires_at, r.confirmed_at, r.created_at, r.updated_at
+		FROM reservations r
+		WHERE r.store_id = ? AND r.status = ? AND r.expires_at > ? AND r.expires_at <= ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM reservation_notifications n
+			WHERE n.reservation_id = r.id AND n.kind = ?
+		  )
+		ORDER BY r.expires_at ASC, r.id ASC
+	`, storeID, domain.ReservationStatusPending, from, to, domain.NotificationReservationExpiring)
+}
+
+// inboundUnits suma las unidades entrantes de una fila de stock: cambios programados pendientes
+// que suman stock y vienen de un pedido de compra o de una transferencia
+const inboundUnits = `(
+	SELECT COALESCE(SUM(quantity), 0) FROM scheduled_stock_changes
+	WHERE product_id = ? AND store_id = ? AND status = 'PENDING' AND type = 'ADJUST' AND quantity > 0
+	  AND source IN ('PURCHASE_ORDER', 'TRANSFER')
+)`
+
+// preorderedUnits suma las unidades de las preventas sin asignar de una fila de stock
+const preorderedUnits = `(
+	SELECT COALESCE(SUM(quantity), 0) FROM reservations
+	WHERE product_id = ? AND store_id = ? AND status = 'PREORDER'
+)`
+
+// GetPreorderCapacity obtiene las unidades entrantes de una fila de stock y las ya comprometidas
+// en preventas sin asignar
+func (r *ReservationRepository) GetPreorderCapacity(ctx context.Context, productID, storeID string) (inbound, preordered int, err error) {
+	err = r.db.QueryRowContext(ctx, `SELECT `+inboundUnits+`, `+preorderedUnits,
+		productID, storeID, productID, storeID).Scan(&inbound, &preordered)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get preorder capacity: %w", err)
+	}
+	return inbound, preordered, nil
+}
+
+// CreatePreorder crea una preventa solo si cabe en las unidades entrantes de la fila que no
+// están comprometidas en otras preventas. La comprobación y la inserción son una única
+// sentencia, así que dos preventas concurrentes no pueden superar lo entrante entre ambas.
+func (r *ReservationRepository) CreatePreorder(ctx context.Context, reservation *domain.Reservation) error {
+	query := `
+		INSERT INTO reservations (id, product_id, store_id, customer_id, quantity, status, priority, channel, expires_at, created_at, updated_at)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE ` + inboundUnits + ` - ` + preorderedUnits + ` >= ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		reservation.ID,
+		reservation.ProductID,
+		reservation.StoreID,
+		reservation.CustomerID,
+		reservation.Quantity,
+		domain.ReservationStatusPreorder,
+		reservationPriority(reservation),
+		reservation.Channel,
+		reservation.ExpiresAt,
+		reservation.CreatedAt,
+		reservation.CreatedAt,
+		reservation.ProductID, reservation.StoreID,
+		reservation.ProductID, reservation.StoreID,
+		reservation.Quantity,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create preorder: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		inbound, preordered, err := r.GetPreorderCapacity(ctx, reservation.ProductID, reservation.StoreID)
+		if err != nil {
+			return err
+		}
+		return &domain.InsufficientStockError{
+			ProductID: reservation.ProductID,
+			StoreID:   reservation.StoreID,
+			Available: inbound - preordered,
+			Requested: reservation.Quantity,
+		}
+	}
+
+	return nil
+}
+
+// GetPreordersFIFO obtiene las preventas sin asignar de una fila de stock por orden de llegada
+func (r *ReservationRepository) GetPreordersFIFO(ctx context.Context, productID, storeID string) ([]*domain.Reservation, error) {
+	return r.queryReservations(ctx, `
+		SELECT id, product_id, store_id, customer_id, quantity, status, priority, channel, expires_at, confirmed_at, created_at, updated_at
+		FROM reservations
+		WHERE product_id = ? AND store_id = ? AND status = ?
+		ORDER BY created_at ASC, id ASC
+	`, productID, storeID, domain.ReservationStatusPreorder)
+}
+
+// AllocatePreorder pasa una preventa a PENDING con su nueva expiración. Retorna false si ya no
+// estaba en PREORDER (p. ej. se canceló mientras se asignaba el stock).
+func (r *ReservationRepository) AllocatePreorder(ctx context.Context, id string, expiresAt time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE reservations
+		SET status = ?, expires_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?
+	`, domain.ReservationStatusPending, expiresAt, id, domain.ReservationStatusPreorder)
+	if err != nil {
+		return false, fmt.Errorf("failed to allocate preorder: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// inStoreGroup filtra por las tiendas de un grupo (vacío = todas)
+const inStoreGroup = `(? = '' OR store_id IN (SELECT store_id FROM store_group_members WHERE group_id = ?))`
+
+// CountGroupedByStatus cuenta las reservas agrupadas por estado (opcionalmente de un grupo de tiendas)
+func (r *ReservationRepository) CountGroupedByStatus(ctx context.Context, groupID string) (map[string]int, error) {
+	return r.countGrouped(ctx, `SELECT status, COUNT(*) FROM reservations WHERE `+inStoreGroup+` GROUP BY status`, groupID, groupID)
+}
+
+// CountGroupedByStore cuenta las reservas agrupadas por tienda (opcionalmente de un grupo de tiendas)
+func (r *ReservationRepository) CountGroupedByStore(ctx context.Context, groupID string) (map[string]int, error) {
+	return r.countGrouped(ctx, `SELECT store_id, COUNT(*) FROM reservations WHERE `+inStoreGroup+` GROUP BY store_id`, groupID, groupID)
+}
+
+func (r *ReservationRepository) countGrouped(ctx context.Context, query string, args ...interface{}) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reservations: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan reservation count: %w", err)
+		}
+		counts[key] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reservation counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetCreatedSince obtiene las reservas creadas a partir de una fecha (para métricas),
+// opcionalmente de un grupo de tiendas
+func (r *ReservationRepository) GetCreatedSince(ctx context.Context, since time.Time, groupID string) ([]*domain.Reservation, error) {
+	query := `
+		SELECT id, product_id, store_id, quantity, status, priority, channel, expires_at, confirmed_at, created_at, updated_at
+		FROM reservations
+		WHERE created_at >= ?
+		  AND ` + inStoreGroup + `
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, since, groupID, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservations since %s: %w", since, err)
+	}
+	defer rows.Close()
+
+	var reservations []*domain.Reservation
+	for rows.Next() {
+		var reservation domain.Reservation
+		var confirmedAt sql.NullTime
+		err := rows.Scan(
+			&reservation.ID,
+			&reservation.ProductID,
+			&reservation.StoreID,
+			&reservation.Quantity,
+			&reservation.Status,
+			&reservation.Priority,
+			&reservation.Channel,
+			&reservation.ExpiresAt,
+			&confirmedAt,
+			&reservation.CreatedAt,
+			&reservation.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		if confirmedAt.Valid {
+			reservation.ConfirmedAt = &confirmedAt.Time
+		}
+		reservations = append(reservations, &reservation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// GetImportedReservationID obtiene la reserva creada al importar un external_id ("" si no se importó)
+func (r *ReservationRepository) GetImportedReservationID(ctx context.Context, externalID string) (string, error) {
+	var reservationID string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT reservation_id FROM reservation_imports WHERE external_id = ?`, externalID,
+	).Scan(&reservationID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get imported reservation: %w", err)
+	}
+	return reservationID, nil
+}
+
+// CreateImported crea una reserva importada de un OMS externo con sus fechas y estado originales
+// y registra su external_id en la misma transacción
+func (r *ReservationRepository) CreateImported(ctx context.Context, reservation *domain.Reservation, externalID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO reservations (id, product_id, store_id, customer_id, quantity, status, priority, channel, expires_at, confirmed_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		reservation.ID,
+		reservation.ProductID,
+		reservation.StoreID,
+		reservation.CustomerID,
+		reservation.Quantity,
+		reservation.Status,
+		reservationPriority(reservation),
+		reservation.Channel,
+		reservation.ExpiresAt,
+		reservation.ConfirmedAt,
+		reservation.CreatedAt,
+		reservation.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create imported reservation: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO reservation_imports (external_id, reservation_id, product_id, imported_at) VALUES (?, ?, ?, ?)`,
+		externalID, reservation.ID, reservation.ProductID, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register imported reservation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit imported reservation: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// RunDownRepository maneja la persistencia de los planes de descatalogación
+type RunDownRepository struct {
+	db *sql.DB
+}
+
+// NewRunDownRepository crea una nueva instancia del repositorio
+func NewRunDownRepository(db *sql.DB) *RunDownRepository {
+	return &RunDownRepository{db: db}
+}
+
+// Create persiste el plan y sus entradas por tienda en una única transacción
+func (r *RunDownRepository) Create(ctx context.Context, rundown *domain.ProductRunDown) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO product_rundowns (product_id, status, reason, created_at)
+		VALUES (?, ?, ?, ?)
+	`, rundown.ProductID, rundown.Status, rundown.Reason, rundown.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create product rundown: %w", err)
+	}
+
+	for _, entry := range rundown.Stores {
+		var archivedAt interface{}
+		if entry.ArchivedAt != nil {
+			archivedAt = *entry.ArchivedAt
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO product_rundown_stores (product_id, store_id, initial_quantity, status, archived_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, rundown.ProductID, entry.StoreID, entry.InitialQuantity, entry.Status, archivedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create rundown entry for store %s: %w", entry.StoreID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit product rundown: %w", err)
+	}
+
+	return nil
+}
+
+// GetByProduct obtiene el plan de un producto con sus entradas por tienda
+func (r *RunDownRepository) GetByProduct(ctx context.Context, productID string) (*domain.ProductRunDown, error) {
+	query := `
+		SELECT product_id, status, COALESCE(reason, ''), created_at, completed_at
+		FROM product_rundowns
+		WHERE product_id = ?
+	`
+
+	var rundown domain.ProductRunDown
+	var completedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, productID).Scan(
+		&rundown.ProductID,
+		&rundown.Status,
+		&rundown.Reason,
+		&rundown.CreatedAt,
+		&completedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "ProductRunDown", ID: productID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product rundown: %w", err)
+	}
+
+	if completedAt.Valid {
+		rundown.CompletedAt = &completedAt.Time
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT store_id, initial_quantity, status, archived_at
+		FROM product_rundown_stores
+		WHERE product_id = ?
+		ORDER BY store_id
+	`, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rundown entries: %w", err)
+	}
+	defer rows.Close()
+
+	rundown.Stores = []domain.RunDownStoreEntry{}
+	for rows.Next() {
+		var entry domain.RunDownStoreEntry
+		var archivedAt sql.NullTime
+
+		if err := rows.Scan(&entry.StoreID, &entry.InitialQuantity, &entry.Status, &archivedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rundown entry: %w", err)
+		}
+		if archivedAt.Valid {
+			entry.ArchivedAt = &archivedAt.Time
+		}
+		rundown.Stores = append(rundown.Stores, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rundown entries: %w", err)
+	}
+
+	return &rundown, nil
+}
+
+// IsDiscontinued indica si el producto tiene un plan de descatalogación (en curso o completado)
+func (r *RunDownRepository) IsDiscontinued(ctx context.Context, productID string) (bool, error) {
+	var exists int
+	err := r.db.QueryRowContext(ctx, `SELECT 1 FROM product_rundowns WHERE product_id = ?`, productID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check product rundown: %w", err)
+	}
+	return true, nil
+}
+
+// ListInProgress obtiene los IDs de productos con el run-down en curso
+func (r *RunDownRepository) ListInProgress(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT product_id FROM product_rundowns
+		WHERE status = ?
+		ORDER BY created_at
+	`, domain.RunDownStatusInProgress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product rundowns: %w", err)
+	}
+	defer rows.Close()
+
+	var productIDs []string
+	for rows.Next() {
+		var productID string
+		if err := rows.Scan(&productID); err != nil {
+			return nil, fmt.Errorf("failed to scan product rundown: %w", err)
+		}
+		productIDs = append(productIDs, productID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product rundowns: %w", err)
+	}
+
+	return productIDs, nil
+}
+
+// ArchiveStore marca como archivada la entrada de surtido de una tienda
+func (r *RunDownRepository) ArchiveStore(ctx context.Context, productID, storeID string, archivedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE product_rundown_stores SET status = ?, archived_at = ?
+		WHERE product_id = ? AND store_id = ?
+	`, domain.RunDownEntryArchived, archivedAt, productID, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to archive rundown entry: %w", err)
+	}
+	return nil
+}
+
+// Complete marca el plan como completado
+func (r *RunDownRepository) Complete(ctx context.Context, productID string, completedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE product_rundowns SET status = ?, completed_at = ?
+		WHERE product_id = ?
+	`, domain.RunDownStatusCompleted, completedAt, productID)
+	if err != nil {
+		return fmt.Errorf("failed to complete product rundown: %w", err)
+	}
+	return nil
+}
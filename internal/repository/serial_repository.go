@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// SerialRepository maneja las operaciones de persistencia para números de serie vendidos
+type SerialRepository struct {
+	db *sql.DB
+}
+
+// NewSerialRepository crea una nueva instancia del repositorio
+func NewSerialRepository(db *sql.DB) *SerialRepository {
+	return &SerialRepository{db: db}
+}
+
+// CreateBatch registra varios números de serie en una sola transacción
+func (r *SerialRepository) CreateBatch(ctx context.Context, registrations []*domain.SerialRegistration) error {
+	if len(registrations) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO serial_registrations (id, serial_number, product_id, store_id, reservation_id, customer_id,
+			sold_at, warranty_expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, reg := range registrations {
+		_, err = stmt.ExecContext(ctx,
+			reg.ID,
+			reg.SerialNumber,
+			reg.ProductID,
+			reg.StoreID,
+			reg.ReservationID,
+			reg.CustomerID,
+			reg.SoldAt,
+			reg.WarrantyExpiresAt,
+			reg.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register serial %s: %w", reg.SerialNumber, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetBySerial obtiene los registros de venta de un número de serie (puede repetirse entre productos)
+func (r *SerialRepository) GetBySerial(ctx context.Context, serialNumber string) ([]*domain.SerialRegistration, error) {
+	query := `
+		SELECT id, serial_number, product_id, store_id, reservation_id, customer_id,
+			sold_at, warranty_expires_at, created_at
+		FROM serial_registrations
+		WHERE serial_number = ?
+		ORDER BY sold_at DESC
+	`
+
+	return r.query(ctx, query, serialNumber)
+}
+
+// GetByReservation obtiene los números de serie registrados para una reserva
+func (r *SerialRepository) GetByReservation(ctx context.Context, reservationID string) ([]*domain.SerialRegistration, error) {
+	query := `
+		SELECT id, serial_number, product_id, store_id, reservation_id, customer_id,
+			sold_at, warranty_expires_at, created_at
+		FROM serial_registrations
+		WHERE reservation_id = ?
+		ORDER BY serial_number
+	`
+
+	return r.query(ctx, query, reservationID)
+}
+
+// ExistsForProduct verifica si un número de serie ya fue vendido para un producto
+func (r *SerialRepository) ExistsForProduct(ctx context.Context, productID, serialNumber string) (bool, error) {
+	query := `SELECT COUNT(*) FROM serial_registrations WHERE product_id = ? AND serial_number = ?`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, productID, serialNumber).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check serial: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *SerialRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.SerialRegistration, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get serial registrations: %w", err)
+	}
+	defer rows.Close()
+
+	var registrations []*domain.SerialRegistration
+	for rows.Next() {
+		var reg domain.SerialRegistration
+		var warrantyExpiresAt sql.NullTime
+
+		err := rows.Scan(
+			&reg.ID,
+			&reg.SerialNumber,
+			&reg.ProductID,
+			&reg.StoreID,
+			&reg.ReservationID,
+			&reg.CustomerID,
+			&reg.SoldAt,
+			&warrantyExpiresAt,
+			&reg.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan serial registration: %w", err)
+		}
+
+		if warrantyExpiresAt.Valid {
+			reg.WarrantyExpiresAt = &warrantyExpiresAt.Time
+		}
+
+		registrations = append(registrations, &reg)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating serial registrations: %w", err)
+	}
+
+	return registrations, nil
+}
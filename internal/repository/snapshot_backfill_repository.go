@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// SnapshotBackfillRepository maneja el progreso del backfill de eventos stock.snapshot
+type SnapshotBackfillRepository struct {
+	db *sql.DB
+}
+
+// NewSnapshotBackfillRepository crea una nueva instancia del repositorio
+func NewSnapshotBackfillRepository(db *sql.DB) *SnapshotBackfillRepository {
+	return &SnapshotBackfillRepository{db: db}
+}
+
+// Create persiste un nuevo backfill
+func (r *SnapshotBackfillRepository) Create(ctx context.Context, backfill *domain.SnapshotBackfill) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO snapshot_backfills (id, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+	`, backfill.ID, backfill.Status, backfill.CreatedAt, backfill.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot backfill: %w", err)
+	}
+	return nil
+}
+
+// GetRunning obtiene el backfill sin terminar más reciente (nil si no hay ninguno)
+func (r *SnapshotBackfillRepository) GetRunning(ctx context.Context) (*domain.SnapshotBackfill, error) {
+	var backfill domain.SnapshotBackfill
+	var completedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, status, last_product_id, last_store_id, emitted, created_at, updated_at, completed_at
+		FROM snapshot_backfills
+		WHERE status = ?
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, domain.JobStatusRunning).Scan(
+		&backfill.ID,
+		&backfill.Status,
+		&backfill.LastProductID,
+		&backfill.LastStoreID,
+		&backfill.Emitted,
+		&backfill.CreatedAt,
+		&backfill.UpdatedAt,
+		&completedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running snapshot backfill: %w", err)
+	}
+
+	if completedAt.Valid {
+		backfill.CompletedAt = &completedAt.Time
+	}
+	return &backfill, nil
+}
+
+// SaveProgress guarda el cursor, el número de eventos emitidos y el estado del backfill
+func (r *SnapshotBackfillRepository) SaveProgress(ctx context.Context, backfill *domain.SnapshotBackfill) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE snapshot_backfills
+		SET status = ?, last_product_id = ?, last_store_id = ?, emitted = ?, updated_at = ?, completed_at = ?
+		WHERE id = ?
+	`, backfill.Status, backfill.LastProductID, backfill.LastStoreID, backfill.Emitted,
+		backfill.UpdatedAt, backfill.CompletedAt, backfill.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot backfill progress: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// StockAdjustmentRepository maneja los ajustes manuales de stock pendientes de aprobación
+type StockAdjustmentRepository struct {
+	db *sql.DB
+}
+
+// NewStockAdjustmentRepository crea una nueva instancia del repositorio
+func NewStockAdjustmentRepository(db *sql.DB) *StockAdjustmentRepository {
+	return &StockAdjustmentRepository{db: db}
+}
+
+const stockAdjustmentColumns = `id, product_id, store_id, type, quantity, current_quantity, reason, requested_by, status, reviewed_by, created_at, reviewed_at`
+
+// Create persiste un ajuste pendiente de aprobación
+func (r *StockAdjustmentRepository) Create(ctx context.Context, adjustment *domain.StockAdjustment) error {
+	query := `
+		INSERT INTO stock_adjustments (id, product_id, store_id, type, quantity, current_quantity, reason, requested_by, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		adjustment.ID,
+		adjustment.ProductID,
+		adjustment.StoreID,
+		adjustment.Type,
+		adjustment.Quantity,
+		adjustment.CurrentQuantity,
+		adjustment.Reason,
+		adjustment.RequestedBy,
+		adjustment.Status,
+		adjustment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create stock adjustment: %w", err)
+	}
+
+	return nil
+}
+
+// Get obtiene un ajuste por ID
+func (r *StockAdjustmentRepository) Get(ctx context.Context, id string) (*domain.StockAdjustment, error) {
+	query := `SELECT ` + stockAdjustmentColumns + ` FROM stock_adjustments WHERE id = ?`
+
+	adjustment, err := scanStockAdjustment(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "StockAdjustment", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock adjustment: %w", err)
+	}
+
+	return adjustment, nil
+}
+
+// List obtiene los ajustes (del más reciente al más antiguo) filtrados por estado y tienda
+// (vacíos = todos)
+func (r *StockAdjustmentRepository) List(ctx context.Context, status domain.StockAdjustmentStatus, storeID string, limit, offset int) ([]*domain.StockAdjustment, error) {
+	query := `
+		SELECT ` + stockAdjustmentColumns + `
+		FROM stock_adjustments
+		WHERE (? = '' OR status = ?) AND (? = '' OR store_id = ?)
+		ORDER BY created_at DESC, id
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, status, status, storeID, storeID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock adjustments: %w", err)
+	}
+	defer rows.Close()
+
+	adjustments := make([]*domain.StockAdjustment, 0)
+	for rows.Next() {
+		adjustment, err := scanStockAdjustment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock adjustment: %w", err)
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock adjustments: %w", err)
+	}
+
+	return adjustments, nil
+}
+
+// Reject marca como REJECTED un ajuste que sigue pendiente
+func (r *StockAdjustmentRepository) Reject(ctx context.Context, adjustment *domain.StockAdjustment, reviewer string, reviewedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE stock_adjustments SET status = ?, reviewed_by = ?, reviewed_at = ? WHERE id = ? AND status = ?`,
+		domain.StockAdjustmentRejected, reviewer, reviewedAt, adjustment.ID, domain.StockAdjustmentPending,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reject stock adjustment: %w", err)
+	}
+	if err := requirePendingAdjustment(result, adjustment.ID); err != nil {
+		return err
+	}
+
+	adjustment.Status = domain.StockAdjustmentRejected
+	adjustment.ReviewedBy = reviewer
+	adjustment.ReviewedAt = &reviewedAt
+	return nil
+}
+
+// Approve aplica un ajuste pendiente en una única transacción: lo marca como APPROVED y actualiza
+// la cantidad de la fila de stock respetando el suelo de sobreventa. allowIncrease = false rechaza
+// los ajustes que suben la cantidad (producto descatalogado). Si el ajuste no se puede aplicar no
+// cambia nada y sigue pendiente. Retorna la cantidad anterior y la nueva.
+func (r *StockAdjustmentRepository) Approve(ctx context.Context, adjustment *domain.StockAdjustment, reviewer string, reviewedAt time.Time, allowIncrease bool) (oldQuantity, newQuantity int, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE stock_adjustments SET status = ?, reviewed_by = ?, reviewed_at = ? WHERE id = ? AND status = ?`,
+		domain.StockAdjustmentApproved, reviewer, reviewedAt, adjustment.ID, domain.StockAdjustmentPending,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to approve stock adjustment: %w", err)
+	}
+	if err := requirePendingAdjustment(result, adjustment.ID); err != nil {
+		return 0, 0, err
+	}
+
+	var stock domain.Stock
+	var tolerance int
+	err = tx.QueryRowContext(ctx, `
+		SELECT s.id, s.quantity, s.reserved, `+oversellTolerance+`
+		FROM stock s
+		WHERE s.product_id = ? AND s.store_id = ?
+	`, adjustment.ProductID, adjustment.StoreID).Scan(&stock.ID, &stock.Quantity, &stock.Reserved, &tolerance)
+	if err == sql.ErrNoRows {
+		return 0, 0, &domain.NotFoundError{
+			Resource: "Stock",
+			ID:       fmt.Sprintf("product=%s, store=%s", adjustment.ProductID, adjustment.StoreID),
+		}
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get stock: %w", err)
+	}
+
+	oldQuantity = stock.Quantity
+	newQuantity = adjustment.NewQuantity(oldQuantity)
+	if newQuantity > oldQuantity && !allowIncrease {
+		return 0, 0, &domain.ConflictError{
+			Message: fmt.Sprintf("product %s is discontinued, stock cannot be replenished", adjustment.ProductID),
+		}
+	}
+	if tolerance == 0 && newQuantity < 0 {
+		return 0, 0, &domain.ValidationError{
+			Field:   "quantity",
+			Message: fmt.Sprintf("quantity cannot be negative (current: %d, new: %d)", oldQuantity, newQuantity),
+		}
+	}
+	if newQuantity-stock.Reserved < -tolerance {
+		return 0, 0, &domain.ValidationError{
+			Field: "quantity",
+			Message: fmt.Sprintf("new quantity (%d) with %d reserved exceeds the oversell tolerance of %d units",
+				newQuantity, stock.Reserved, tolerance),
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stock
+		SET quantity = ?,
+		    version = version + 1,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, newQuantity, stock.ID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to update stock quantity: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit stock adjustment: %w", err)
+	}
+
+	adjustment.Status = domain.StockAdjustmentApproved
+	adjustment.ReviewedBy = reviewer
+	adjustment.ReviewedAt = &reviewedAt
+
+	return oldQuantity, newQuantity, nil
+}
+
+// requirePendingAdjustment retorna ConflictError si la actualización no encontró el ajuste pendiente
+// (ya revisado, p. ej. por otra API key a la vez)
+func requirePendingAdjustment(result sql.Result, id string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return &domain.ConflictError{Message: fmt.Sprintf("stock adjustment %s is not pending", id)}
+	}
+	return nil
+}
+
+// scanStockAdjustment escanea una fila de stock_adjustments
+func scanStockAdjustment(row rowScanner) (*domain.StockAdjustment, error) {
+	var adjustment domain.StockAdjustment
+	var reviewedAt sql.NullTime
+
+	err := row.Scan(
+		&adjustment.ID,
+		&adjustment.ProductID,
+		&adjustment.StoreID,
+		&adjustment.Type,
+		&adjustment.Quantity,
+		&adjustment.CurrentQuantity,
+		&adjustment.Reason,
+		&adjustment.RequestedBy,
+		&adjustment.Status,
+		&adjustment.ReviewedBy,
+		&adjustment.CreatedAt,
+		&reviewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if reviewedAt.Valid {
+		adjustment.ReviewedAt = &reviewedAt.Time
+	}
+
+	return &adjustment, nil
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// StockHoldRepository maneja las retenciones internas de stock (exposición, reparación, calidad)
+type StockHoldRepository struct {
+	db *sql.DB
+}
+
+// NewStockHoldRepository crea una nueva instancia del repositorio
+func NewStockHoldRepository(db *sql.DB) *StockHoldRepository {
+	return &StockHoldRepository{db: db}
+}
+
+const stockHoldColumns = `id, product_id, store_id, type, quantity, note, status, created_by, released_by, created_at, released_at`
+
+// Place crea la retención y la suma a reserved y held de la fila de stock en una única
+// transacción. Solo retiene cantidad vendible: el stock de seguridad, lo ya reservado y la
+// tolerancia de sobreventa no cuentan.
+func (r *StockHoldRepository) Place(ctx context.Context, hold *domain.StockHold) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var stock domain.Stock
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, quantity, reserved, safety_stock
+		FROM stock
+		WHERE product_id = ? AND store_id = ?
+	`, hold.ProductID, hold.StoreID).Scan(&stock.ID, &stock.Quantity, &stock.Reserved, &stock.SafetyStock)
+	if err == sql.ErrNoRows {
+		return &domain.NotFoundError{
+			Resource: "Stock",
+			ID:       fmt.Sprintf("product=%s, store=%s", hold.ProductID, hold.StoreID),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock stock: %w", err)
+	}
+
+	if !stock.CanReserve(hold.Quantity) {
+		return &domain.InsufficientStockError{
+			ProductID: hold.ProductID,
+			StoreID:   hold.StoreID,
+			Available: stock.Sellable(),
+			Requested: hold.Quantity,
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO stock_holds (id, product_id, store_id, type, quantity, note, status, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, hold.ID, hold.ProductID, hold.StoreID, hold.Type, hold.Quantity, hold.Note, hold.Status, hold.CreatedBy, hold.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create stock hold: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stock
+		SET reserved = reserved + ?,
+		    held = held + ?,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, hold.Quantity, hold.Quantity, stock.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update held stock: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stock hold: %w", err)
+	}
+
+	return nil
+}
+
+// Release marca como RELEASED una retención activa y devuelve sus unidades a la fila de stock
+// en una única transacción. Retorna ConflictError si ya estaba liberada.
+func (r *StockHoldRepository) Release(ctx context.Context, hold *domain.StockHold, releasedBy string, releasedAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE stock_holds SET status = ?, released_by = ?, released_at = ? WHERE id = ? AND status = ?`,
+		domain.StockHoldReleased, releasedBy, releasedAt, hold.ID, domain.StockHoldActive,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release stock hold: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return &domain.ConflictError{Message: fmt.Sprintf("stock hold %s is not active", hold.ID)}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stock
+		SET reserved = MAX(reserved - ?, 0),
+		    held = MAX(held - ?, 0),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE product_id = ? AND store_id = ?
+	`, hold.Quantity, hold.Quantity, hold.ProductID, hold.StoreID)
+	if err != nil {
+		return fmt.Errorf("failed to update held stock: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stock hold release: %w", err)
+	}
+
+	hold.Status = domain.StockHoldReleased
+	hold.ReleasedBy = releasedBy
+	hold.ReleasedAt = &releasedAt
+	return nil
+}
+
+// Get obtiene una retención por ID
+func (r *StockHoldRepository) Get(ctx context.Context, id string) (*domain.StockHold, error) {
+	query := `SELECT ` + stockHoldColumns + ` FROM stock_holds WHERE id = ?`
+
+	hold, err := scanStockHold(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "StockHold", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+// List obtiene las retenciones del filtro, de la más reciente a la más antigua
+func (r *StockHoldRepository) List(ctx context.Context, filter domain.StockHoldFilter) ([]*domain.StockHold, error) {
+	query := `
+		SELECT ` + stockHoldColumns + `
+		FROM stock_holds
+		WHERE (? = '' OR store_id = ?) AND (? = '' OR product_id = ?)
+		  AND (? = '' OR type = ?) AND (? = '' OR status = ?)
+		ORDER BY created_at DESC, id
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		filter.StoreID, filter.StoreID,
+		filter.ProductID, filter.ProductID,
+		filter.Type, filter.Type,
+		filter.Status, filter.Status,
+		filter.Limit, filter.Offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock holds: %w", err)
+	}
+	defer rows.Close()
+
+	holds := make([]*domain.StockHold, 0)
+	for rows.Next() {
+		hold, err := scanStockHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock hold: %w", err)
+		}
+		holds = append(holds, hold)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+// scanStockHold escanea una fila de stock_holds
+func scanStockHold(row rowScanner) (*domain.StockHold, error) {
+	var hold domain.StockHold
+	var releasedAt sql.NullTime
+
+	err := row.Scan(
+		&hold.ID,
+		&hold.ProductID,
+		&hold.StoreID,
+		&hold.Type,
+		&hold.Quantity,
+		&hold.Note,
+		&hold.Status,
+		&hold.CreatedBy,
+		&hold.ReleasedBy,
+		&hold.CreatedAt,
+		&releasedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if releasedAt.Valid {
+		hold.ReleasedAt = &releasedAt.Time
+	}
+
+	return &hold, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// StockLedgerRepository reproduce el ledger de movimientos de stock guardado en la tabla events
+type StockLedgerRepository struct {
+	db *sql.DB
+}
+
+// NewStockLedgerRepository crea una nueva instancia del repositorio
+func NewStockLedgerRepository(db *sql.DB) *StockLedgerRepository {
+	return &StockLedgerRepository{db: db}
+}
+
+// stockLedgerQuery calcula la quantity esperada de cada (producto, tienda) desde su baseline más
+// reciente. Las transferencias ya se registran como un stock.updated en cada tienda, así que
+// stock.transferred no se suma; las reservas solo mueven quantity al confirmarse o recogerse.
+const stockLedgerQuery = `
+	WITH ledger AS (
+		SELECT seq, event_type, store_id, payload,
+		       CASE WHEN aggregate_type = 'stock' THEN aggregate_id
+		            ELSE json_extract(payload, '$.product_id') END AS product_id
+		FROM events
+		WHERE seq IS NOT NULL
+		  AND event_type IN ('stock.created', 'stock.snapshot', 'stock.updated', 'reservation.confirmed', 'reservation.picked_up')
+	),
+	baseline AS (
+		SELECT product_id, store_id, MAX(seq) AS seq
+		FROM ledger
+		WHERE event_type IN ('stock.created', 'stock.snapshot')
+		GROUP BY product_id, store_id
+	),
+	replay AS (
+		SELECT b.product_id, b.store_id, b.seq,
+		       MAX(CASE WHEN l.seq = b.seq THEN l.event_type END) AS baseline_type,
+		       SUM(CASE
+		           WHEN l.seq = b.seq AND l.event_type = 'stock.created' THEN json_extract(l.payload, '$.initial_quantity')
+		           WHEN l.seq = b.seq THEN json_extract(l.payload, '$.quantity')
+		           WHEN l.event_type = 'stock.updated' THEN json_extract(l.payload, '$.new_quantity') - json_extract(l.payload, '$.old_quantity')
+		           WHEN l.event_type IN ('reservation.confirmed', 'reservation.picked_up') THEN -json_extract(l.payload, '$.quantity')
+		           ELSE 0 END) AS expected
+		FROM baseline b
+		JOIN ledger l ON l.product_id = b.product_id AND l.store_id = b.store_id AND l.seq >= b.seq
+		GROUP BY b.product_id, b.store_id, b.seq
+	)
+	SELECT s.product_id, s.store_id, s.quantity,
+	       COALESCE(r.expected, 0), COALESCE(r.baseline_type, ''), COALESCE(r.seq, 0)
+	FROM stock s
+	LEFT JOIN replay r ON r.product_id = s.product_id AND r.store_id = s.store_id
+`
+
+// Replay retorna la quantity actual y la reproducida de todas las filas de stock
+func (r *StockLedgerRepository) Replay(ctx context.Context) ([]*domain.StockLedgerRow, error) {
+	return r.replay(ctx, stockLedgerQuery+"\n\tORDER BY s.store_id, s.product_id")
+}
+
+// ReplayRow retorna la quantity actual y la reproducida de una fila de stock
+func (r *StockLedgerRepository) ReplayRow(ctx context.Context, productID, storeID string) (*domain.StockLedgerRow, error) {
+	rows, err := r.replay(ctx, stockLedgerQuery+"\n\tWHERE s.product_id = ? AND s.store_id = ?", productID, storeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, &domain.NotFoundError{
+			Resource: "Stock",
+			ID:       fmt.Sprintf("product=%s, store=%s", productID, storeID),
+		}
+	}
+	return rows[0], nil
+}
+
+func (r *StockLedgerRepository) replay(ctx context.Context, query string, args ...interface{}) ([]*domain.StockLedgerRow, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay stock ledger: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*domain.StockLedgerRow
+	for rows.Next() {
+		var row domain.StockLedgerRow
+		if err := rows.Scan(&row.ProductID, &row.StoreID, &row.Quantity, &row.Expected, &row.BaselineType, &row.BaselineSeq); err != nil {
+			return nil, fmt.Errorf("failed to scan stock ledger row: %w", err)
+		}
+		result = append(result, &row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock ledger: %w", err)
+	}
+
+	return result, nil
+}
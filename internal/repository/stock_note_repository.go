@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// StockNoteRepository maneja las notas del personal sobre filas de stock y sus movimientos
+type StockNoteRepository struct {
+	db *sql.DB
+}
+
+// NewStockNoteRepository crea una nueva instancia del repositorio
+func NewStockNoteRepository(db *sql.DB) *StockNoteRepository {
+	return &StockNoteRepository{db: db}
+}
+
+const stockNoteColumns = `id, product_id, store_id, event_id, text, attachments, created_by, created_at`
+
+// Create guarda una nota nueva
+func (r *StockNoteRepository) Create(ctx context.Context, note *domain.StockNote) error {
+	attachments := note.Attachments
+	if attachments == nil {
+		attachments = []domain.StockNoteAttachment{}
+	}
+	encoded, err := json.Marshal(attachments)
+	if err != nil {
+		return fmt.Errorf("failed to encode stock note attachments: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO stock_notes (`+stockNoteColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, note.ID, note.ProductID, note.StoreID, note.EventID, note.Text, string(encoded), note.CreatedBy, note.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create stock note: %w", err)
+	}
+	return nil
+}
+
+// List lista las notas de una fila de stock, de la más reciente a la más antigua. Con eventID
+// solo las de ese movimiento.
+func (r *StockNoteRepository) List(ctx context.Context, productID, storeID, eventID string, limit int) ([]*domain.StockNote, error) {
+	return r.list(ctx, `
+		WHERE product_id = ? AND store_id = ? AND (? = '' OR event_id = ?)
+		ORDER BY created_at DESC, id
+		LIMIT ?
+	`, productID, storeID, eventID, eventID, limit)
+}
+
+// ListByEvents lista las notas de varios movimientos (para adjuntarlas a la línea temporal)
+func (r *StockNoteRepository) ListByEvents(ctx context.Context, productID, storeID string, eventIDs []string) ([]*domain.StockNote, error) {
+	if len(eventIDs) == 0 {
+		return []*domain.StockNote{}, nil
+	}
+
+	args := []interface{}{productID, storeID}
+	for _, id := range eventIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(eventIDs)), ", ")
+
+	return r.list(ctx, `
+		WHERE product_id = ? AND store_id = ? AND event_id IN (`+placeholders+`)
+		ORDER BY created_at, id
+	`, args...)
+}
+
+// ListRowNotes lista las notas sobre la fila (sin movimiento) creadas en [from, to), de la más
+// reciente a la más antigua (límites nil = sin límite)
+func (r *StockNoteRepository) ListRowNotes(ctx context.Context, productID, storeID string, from, to *time.Time) ([]*domain.StockNote, error) {
+	where := `WHERE product_id = ? AND store_id = ? AND event_id = ''`
+	args := []interface{}{productID, storeID}
+	if from != nil {
+		where += ` AND created_at >= ?`
+		args = append(args, *from)
+	}
+	if to != nil {
+		where += ` AND created_at < ?`
+		args = append(args, *to)
+	}
+	return r.list(ctx, where+` ORDER BY created_at DESC, id`, args...)
+}
+
+func (r *StockNoteRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.StockNote, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+stockNoteColumns+`
+		FROM stock_notes `+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := make([]*domain.StockNote, 0)
+	for rows.Next() {
+		var note domain.StockNote
+		var attachments string
+		if err := rows.Scan(&note.ID, &note.ProductID, &note.StoreID, &note.EventID, &note.Text,
+			&attachments, &note.CreatedBy, &note.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stock note: %w", err)
+		}
+		if err := json.Unmarshal([]byte(attachments), &note.Attachments); err != nil {
+			return nil, fmt.Errorf("failed to decode stock note attachments: %w", err)
+		}
+		notes = append(notes, &note)
+	}
+	return notes, rows.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"inventory-system/internal/domain"
 )
@@ -21,7 +22,7 @@ func NewStockRepository(db *sql.DB) *StockRepository {
 // GetByProductAndStore obtiene el stock de un producto en una tienda específica
 func (r *StockRepository) GetByProductAndStore(ctx context.Context, productID, storeID string) (*domain.Stock, error) {
 	query := `
-		SELECT id, product_id, store_id, quantity, reserved, version, updated_at
+		SELECT id, product_id, store_id, quantity, reserved, held, min_stock, max_stock, safety_stock, version, updated_at
 		FROM stock
 		WHERE product_id = ? AND store_id = ?
 	`
@@ -33,6 +34,10 @@ func (r *StockRepository) GetByProductAndStore(ctx context.Context, productID, s
 		&stock.StoreID,
 		&stock.Quantity,
 		&stock.Reserved,
+		&stock.Held,
+		&stock.MinStock,
+		&stock.MaxStock,
+		&stock.SafetyStock,
 		&stock.Version,
 		&stock.UpdatedAt,
 	)
@@ -53,7 +58,7 @@ func (r *StockRepository) GetByProductAndStore(ctx context.Context, productID, s
 // GetAllByProduct obtiene el stock de un producto en TODAS las tiendas
 func (r *StockRepository) GetAllByProduct(ctx context.Context, productID string) ([]*domain.Stock, error) {
 	query := `
-		SELECT id, product_id, store_id, quantity, reserved, version, updated_at
+		SELECT id, product_id, store_id, quantity, reserved, held, min_stock, max_stock, safety_stock, version, updated_at
 		FROM stock
 		WHERE product_id = ?
 		ORDER BY store_id
@@ -74,6 +79,10 @@ func (r *StockRepository) GetAllByProduct(ctx context.Context, productID string)
 			&stock.StoreID,
 			&stock.Quantity,
 			&stock.Reserved,
+			&stock.Held,
+			&stock.MinStock,
+			&stock.MaxStock,
+			&stock.SafetyStock,
 			&stock.Version,
 			&stock.UpdatedAt,
 		)
@@ -92,8 +101,22 @@ func (r *StockRepository) GetAllByProduct(ctx context.Context, productID string)
 
 // GetAllByStore obtiene todo el stock de una tienda
 func (r *StockRepository) GetAllByStore(ctx context.Context, storeID string) ([]*domain.Stock, error) {
+	var stocks []*domain.Stock
+	err := r.EachByStore(ctx, storeID, func(stock *domain.Stock) error {
+		stocks = append(stocks, stock)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// EachByStore recorre el stock de una tienda en el orden de GetAllByStore sin acumularlo en
+// memoria (listados JSON en streaming). Se detiene en el primer error de fn.
+func (r *StockRepository) EachByStore(ctx context.Context, storeID string, fn func(*domain.Stock) error) error {
 	query := `
-		SELECT id, product_id, store_id, quantity, reserved, version, updated_at
+		SELECT id, product_id, store_id, quantity, reserved, held, min_stock, max_stock, safety_stock, version, updated_at
 		FROM stock
 		WHERE store_id = ?
 		ORDER BY product_id
@@ -101,7 +124,54 @@ func (r *StockRepository) GetAllByStore(ctx context.Context, storeID string) ([]
 
 	rows, err := r.db.QueryContext(ctx, query, storeID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get stock by store: %w", err)
+		return fmt.Errorf("failed to get stock by store: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stock domain.Stock
+		err := rows.Scan(
+			&stock.ID,
+			&stock.ProductID,
+			&stock.StoreID,
+			&stock.Quantity,
+			&stock.Reserved,
+			&stock.Held,
+			&stock.MinStock,
+			&stock.MaxStock,
+			&stock.SafetyStock,
+			&stock.Version,
+			&stock.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan stock: %w", err)
+		}
+		if err := fn(&stock); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating stocks: %w", err)
+	}
+
+	return nil
+}
+
+// ListAfter obtiene hasta limit filas de stock ordenadas por (product_id, store_id) posteriores
+// al cursor (productID, storeID). Con el cursor vacío empieza por la primera fila.
+func (r *StockRepository) ListAfter(ctx context.Context, productID, storeID string, limit int) ([]*domain.Stock, error) {
+	query := `
+		SELECT id, product_id, store_id, quantity, reserved, held, min_stock, max_stock, safety_stock, version, updated_at
+		FROM stock
+		WHERE product_id > ? OR (product_id = ? AND store_id > ?)
+		ORDER BY product_id, store_id
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, productID, productID, storeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock: %w", err)
 	}
 	defer rows.Close()
 
@@ -114,6 +184,10 @@ func (r *StockRepository) GetAllByStore(ctx context.Context, storeID string) ([]
 			&stock.StoreID,
 			&stock.Quantity,
 			&stock.Reserved,
+			&stock.Held,
+			&stock.MinStock,
+			&stock.MaxStock,
+			&stock.SafetyStock,
 			&stock.Version,
 			&stock.UpdatedAt,
 		)
@@ -132,9 +206,13 @@ func (r *StockRepository) GetAllByStore(ctx context.Context, storeID string) ([]
 
 // Create crea un nuevo registro de stock
 func (r *StockRepository) Create(ctx context.Context, stock *domain.Stock) error {
+	if err := r.checkNegativeQuantity(ctx, stock); err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO stock (id, product_id, store_id, quantity, reserved, version, updated_at)
-		VALUES (?, ?, ?, ?, ?, 1, CURRENT_TIMESTAMP)
+		INSERT INTO stock (id, product_id, store_id, quantity, reserved, min_stock, max_stock, safety_stock, version, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, 1, CURRENT_TIMESTAMP)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -143,6 +221,9 @@ func (r *StockRepository) Create(ctx context.Context, stock *domain.Stock) error
 		stock.StoreID,
 		stock.Quantity,
 		stock.Reserved,
+		stock.MinStock,
+		stock.MaxStock,
+		stock.SafetyStock,
 	)
 
 	if err != nil {
@@ -152,8 +233,13 @@ func (r *StockRepository) Create(ctx context.Context, stock *domain.Stock) error
 	return nil
 }
 
-// UpdateQuantity actualiza la cantidad de stock con optimistic locking
+// UpdateQuantity actualiza la cantidad de stock con optimistic locking.
+// Una cantidad negativa solo se acepta con tolerancia de sobreventa.
 func (r *StockRepository) UpdateQuantity(ctx context.Context, stock *domain.Stock) error {
+	if err := r.checkNegativeQuantity(ctx, stock); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE stock
 		SET quantity = ?,
@@ -186,9 +272,129 @@ func (r *StockRepository) UpdateQuantity(ctx context.Context, stock *domain.Stoc
 	return nil
 }
 
-// ReserveStock incrementa la cantidad reservada (usado por reservas)
-// Usa SELECT FOR UPDATE para lock pesimista en operaciones críticas
+// UpdateThresholds actualiza los umbrales de alerta (min/max) de un registro de stock
+func (r *StockRepository) UpdateThresholds(ctx context.Context, productID, storeID string, minStock, maxStock int) error {
+	query := `
+		UPDATE stock
+		SET min_stock = ?,
+		    max_stock = ?,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE product_id = ? AND store_id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, minStock, maxStock, productID, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to update stock thresholds: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &domain.NotFoundError{
+			Resource: "Stock",
+			ID:       fmt.Sprintf("product=%s, store=%s", productID, storeID),
+		}
+	}
+
+	return nil
+}
+
+// UpdateSafetyStock actualiza las unidades de stock de seguridad de un registro de stock
+func (r *StockRepository) UpdateSafetyStock(ctx context.Context, productID, storeID string, safetyStock int) error {
+	query := `
+		UPDATE stock
+		SET safety_stock = ?,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE product_id = ? AND store_id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, safetyStock, productID, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to update safety stock: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &domain.NotFoundError{
+			Resource: "Stock",
+			ID:       fmt.Sprintf("product=%s, store=%s", productID, storeID),
+		}
+	}
+
+	return nil
+}
+
+// oversellTolerance unidades que la disponibilidad de una fila de stock (alias s) puede quedar por
+// debajo de cero: la tolerancia del producto o, si no tiene, la de la tienda (0 = sin sobreventa)
+const oversellTolerance = `COALESCE(
+		(SELECT max_units FROM oversell_tolerances WHERE scope = 'PRODUCT' AND scope_id = s.product_id),
+		(SELECT max_units FROM oversell_tolerances WHERE scope = 'STORE' AND scope_id = s.store_id),
+		0)`
+
+// GetOversellTolerance obtiene la tolerancia de sobreventa efectiva de un producto en una tienda
+func (r *StockRepository) GetOversellTolerance(ctx context.Context, productID, storeID string) (int, error) {
+	var tolerance int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT `+oversellTolerance+`
+		FROM (SELECT ? AS product_id, ? AS store_id) s
+	`, productID, storeID).Scan(&tolerance)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get oversell tolerance: %w", err)
+	}
+	return tolerance, nil
+}
+
+// GetChannelAvailable obtiene las unidades que puede reservar un canal en una fila de stock
+// (ver domain.ChannelAvailable; channel vacío = parte no asignada a ningún canal)
+func (r *StockRepository) GetChannelAvailable(ctx context.Context, productID, storeID string, channel domain.SalesChannel) (int, error) {
+	stock, err := r.GetByProductAndStore(ctx, productID, storeID)
+	if err != nil {
+		return 0, err
+	}
+	allocations, err := queryChannelAllocations(ctx, r.db, productID, storeID)
+	if err != nil {
+		return 0, err
+	}
+	return domain.ChannelAvailable(stock, allocations, channel), nil
+}
+
+// checkNegativeQuantity rechaza una cantidad negativa si el producto no tiene tolerancia de
+// sobreventa en la tienda (la tabla stock ya no lo impide con un CHECK)
+func (r *StockRepository) checkNegativeQuantity(ctx context.Context, stock *domain.Stock) error {
+	if stock.Quantity >= 0 {
+		return nil
+	}
+
+	tolerance, err := r.GetOversellTolerance(ctx, stock.ProductID, stock.StoreID)
+	if err != nil {
+		return err
+	}
+	if tolerance == 0 {
+		return &domain.ValidationError{
+			Field:   "quantity",
+			Message: fmt.Sprintf("quantity cannot be negative without an oversell tolerance (got %d)", stock.Quantity),
+		}
+	}
+
+	return nil
+}
+
+// ReserveStock incrementa la cantidad reservada (usado por reservas sin canal)
 func (r *StockRepository) ReserveStock(ctx context.Context, productID, storeID string, quantity int) error {
+	return r.ReserveChannelStock(ctx, productID, storeID, "", quantity)
+}
+
+// ReserveChannelStock incrementa la cantidad reservada para un canal de venta.
+// Si el canal tiene asignación reserva contra ella; si no, contra la parte no asignada.
+// Usa SELECT FOR UPDATE para lock pesimista en operaciones críticas
+func (r *StockRepository) ReserveChannelStock(ctx context.Context, productID, storeID string, channel domain.SalesChannel, quantity int) error {
 	// Iniciar transacción
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -198,19 +404,22 @@ func (r *StockRepository) ReserveStock(ctx context.Context, productID, storeID s
 
 	// SELECT FOR UPDATE - lock pesimista
 	query := `
-		SELECT id, product_id, store_id, quantity, reserved, version
-		FROM stock
-		WHERE product_id = ? AND store_id = ?
+		SELECT s.id, s.product_id, s.store_id, s.quantity, s.reserved, s.safety_stock, s.version, ` + oversellTolerance + `
+		FROM stock s
+		WHERE s.product_id = ? AND s.store_id = ?
 	`
 
 	var stock domain.Stock
+	var tolerance int
 	err = tx.QueryRowContext(ctx, query, productID, storeID).Scan(
 		&stock.ID,
 		&stock.ProductID,
 		&stock.StoreID,
 		&stock.Quantity,
 		&stock.Reserved,
+		&stock.SafetyStock,
 		&stock.Version,
+		&tolerance,
 	)
 
 	if err == sql.ErrNoRows {
@@ -223,9 +432,20 @@ func (r *StockRepository) ReserveStock(ctx context.Context, productID, storeID s
 		return fmt.Errorf("failed to lock stock: %w", err)
 	}
 
-	// Validar disponibilidad
-	available := stock.Quantity - stock.Reserved
-	if available < quantity {
+	allocations, err := queryChannelAllocations(ctx, tx, productID, storeID)
+	if err != nil {
+		return err
+	}
+
+	// Validar la cantidad vendible del canal: el stock de seguridad y las asignaciones de otros
+	// canales no se reservan, y con sobreventa la disponibilidad puede quedar hasta -tolerance
+	available := domain.ChannelAvailable(&stock, allocations, channel)
+	limit := available + tolerance
+	allocation := domain.FindChannelAllocation(allocations, channel)
+	if channel != "" && allocation != nil {
+		limit = min(allocation.Remaining(), stock.Sellable()+tolerance)
+	}
+	if limit < quantity {
 		return &domain.InsufficientStockError{
 			ProductID: productID,
 			StoreID:   storeID,
@@ -247,6 +467,18 @@ func (r *StockRepository) ReserveStock(ctx context.Context, productID, storeID s
 		return fmt.Errorf("failed to update reserved stock: %w", err)
 	}
 
+	if channel != "" && allocation != nil {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE channel_allocations
+			SET reserved = reserved + ?,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE product_id = ? AND store_id = ? AND channel = ?
+		`, quantity, productID, storeID, channel)
+		if err != nil {
+			return fmt.Errorf("failed to update channel allocation: %w", err)
+		}
+	}
+
 	// Commit transacción
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -255,8 +487,32 @@ func (r *StockRepository) ReserveStock(ctx context.Context, productID, storeID s
 	return nil
 }
 
-// ReleaseReservedStock libera stock reservado (cuando se cancela una reserva)
+// ReleaseReservedStock libera stock reservado sin canal (cuando se cancela una reserva)
 func (r *StockRepository) ReleaseReservedStock(ctx context.Context, productID, storeID string, quantity int) error {
+	return r.ReleaseChannelStock(ctx, productID, storeID, "", quantity)
+}
+
+// ReleaseChannelStock libera stock reservado por un canal y lo devuelve a su asignación
+func (r *StockRepository) ReleaseChannelStock(ctx context.Context, productID, storeID string, channel domain.SalesChannel, quantity int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := releaseChannelStock(ctx, tx, productID, storeID, channel, quantity); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// releaseChannelStock libera stock reservado dentro de tx (ver ReleaseChannelStock)
+func releaseChannelStock(ctx context.Context, tx *sql.Tx, productID, storeID string, channel domain.SalesChannel, quantity int) error {
 	query := `
 		UPDATE stock
 		SET reserved = reserved - ?,
@@ -265,7 +521,7 @@ func (r *StockRepository) ReleaseReservedStock(ctx context.Context, productID, s
 		  AND reserved >= ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query, quantity, productID, storeID, quantity)
+	result, err := tx.ExecContext(ctx, query, quantity, productID, storeID, quantity)
 	if err != nil {
 		return fmt.Errorf("failed to release reserved stock: %w", err)
 	}
@@ -281,22 +537,61 @@ func (r *StockRepository) ReleaseReservedStock(ctx context.Context, productID, s
 		}
 	}
 
+	// Solo se descuenta lo reservado contra la asignación (la reserva pudo salir de la parte no asignada)
+	if channel != "" {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE channel_allocations
+			SET reserved = reserved - MIN(?, reserved),
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE product_id = ? AND store_id = ? AND channel = ?
+		`, quantity, productID, storeID, channel)
+		if err != nil {
+			return fmt.Errorf("failed to update channel allocation: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// ConfirmReservation confirma una reserva (decrementa quantity y reserved)
+// ConfirmReservation confirma una reserva sin canal (decrementa quantity y reserved).
+// Con tolerancia de sobreventa quantity puede quedar negativa hasta -tolerancia.
 func (r *StockRepository) ConfirmReservation(ctx context.Context, productID, storeID string, quantity int) error {
+	return r.ConfirmChannelStock(ctx, productID, storeID, "", quantity)
+}
+
+// ConfirmChannelStock confirma una reserva de un canal: las unidades vendidas salen también de
+// su asignación
+func (r *StockRepository) ConfirmChannelStock(ctx context.Context, productID, storeID string, channel domain.SalesChannel, quantity int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := confirmChannelStock(ctx, tx, productID, storeID, channel, quantity); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// confirmChannelStock confirma una reserva dentro de tx (ver ConfirmChannelStock)
+func confirmChannelStock(ctx context.Context, tx *sql.Tx, productID, storeID string, channel domain.SalesChannel, quantity int) error {
 	query := `
-		UPDATE stock
+		UPDATE stock AS s
 		SET quantity = quantity - ?,
 		    reserved = reserved - ?,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE product_id = ? AND store_id = ?
-		  AND quantity >= ?
+		  AND quantity - ? >= -` + oversellTolerance + `
 		  AND reserved >= ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := tx.ExecContext(ctx, query,
 		quantity, quantity, productID, storeID, quantity, quantity)
 
 	if err != nil {
@@ -314,25 +609,56 @@ func (r *StockRepository) ConfirmReservation(ctx context.Context, productID, sto
 		}
 	}
 
+	if channel != "" {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE channel_allocations
+			SET allocated = allocated - MIN(?, reserved),
+			    reserved = reserved - MIN(?, reserved),
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE product_id = ? AND store_id = ? AND channel = ?
+		`, quantity, quantity, productID, storeID, channel)
+		if err != nil {
+			return fmt.Errorf("failed to update channel allocation: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// GetLowStockItems retorna productos con stock bajo (cantidad < umbral)
-func (r *StockRepository) GetLowStockItems(ctx context.Context, threshold int) ([]*domain.Stock, error) {
+// GetLowStockItems retorna las filas de stock bajo: cantidad vendible (quantity - reserved - safety_stock)
+// por debajo de su min_stock o, si no tiene, del umbral del filtro. Ordenadas de menor a mayor cantidad vendible.
+func (r *StockRepository) GetLowStockItems(ctx context.Context, filter domain.LowStockFilter) ([]*domain.Stock, error) {
+	var stocks []*domain.Stock
+	err := r.EachLowStockItem(ctx, filter, func(stock *domain.Stock) error {
+		stocks = append(stocks, stock)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// EachLowStockItem recorre las filas de stock bajo en el orden de GetLowStockItems sin acumularlas
+// en memoria (descargas CSV/XLSX). Se detiene en el primer error de fn.
+func (r *StockRepository) EachLowStockItem(ctx context.Context, filter domain.LowStockFilter, fn func(*domain.Stock) error) error {
+	where, args := lowStockClause(filter)
 	query := `
-		SELECT id, product_id, store_id, quantity, reserved, version, updated_at
-		FROM stock
-		WHERE (quantity - reserved) < ?
-		ORDER BY (quantity - reserved) ASC
-	`
+		SELECT s.id, s.product_id, s.store_id, s.quantity, s.reserved, s.held, s.min_stock, s.max_stock, s.safety_stock, s.version, s.updated_at
+		FROM stock s` + where + `
+		ORDER BY ` + abcPriority + `, (s.quantity - s.reserved - s.safety_stock) ASC, s.store_id, s.product_id`
+
+	if filter.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, filter.Limit, filter.Offset)
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, threshold)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get low stock items: %w", err)
+		return fmt.Errorf("failed to get low stock items: %w", err)
 	}
 	defer rows.Close()
 
-	var stocks []*domain.Stock
 	for rows.Next() {
 		var stock domain.Stock
 		err := rows.Scan(
@@ -341,18 +667,184 @@ func (r *StockRepository) GetLowStockItems(ctx context.Context, threshold int) (
 			&stock.StoreID,
 			&stock.Quantity,
 			&stock.Reserved,
+			&stock.Held,
+			&stock.MinStock,
+			&stock.MaxStock,
+			&stock.SafetyStock,
 			&stock.Version,
 			&stock.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan stock: %w", err)
+			return fmt.Errorf("failed to scan stock: %w", err)
+		}
+		if err := fn(&stock); err != nil {
+			return err
 		}
-		stocks = append(stocks, &stock)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating stocks: %w", err)
+		return fmt.Errorf("error iterating stocks: %w", err)
 	}
 
-	return stocks, nil
+	return nil
+}
+
+// CountLowStockItems cuenta las filas de stock bajo del filtro (ignora la paginación)
+func (r *StockRepository) CountLowStockItems(ctx context.Context, filter domain.LowStockFilter) (int, error) {
+	where, args := lowStockClause(filter)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM stock s`+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count low stock items: %w", err)
+	}
+
+	return count, nil
+}
+
+// abcPriority ordena primero los productos de clase A y después los de clase B; los de clase C
+// y los no clasificados van al final (requiere el JOIN con products como p)
+const abcPriority = "CASE p.abc_class WHEN 'A' THEN 0 WHEN 'B' THEN 1 ELSE 2 END"
+
+// lowStockClause construye el JOIN/WHERE de la consulta de stock bajo. El JOIN con products
+// se hace siempre porque el orden prioriza por clase ABC.
+func lowStockClause(filter domain.LowStockFilter) (string, []interface{}) {
+	clause := "\n\t\tJOIN products p ON p.id = s.product_id"
+
+	conditions := []string{"(s.quantity - s.reserved - s.safety_stock) < CASE WHEN s.min_stock > 0 THEN s.min_stock ELSE ? END"}
+	args := []interface{}{filter.Threshold}
+
+	if filter.StoreID != "" {
+		conditions = append(conditions, "s.store_id = ?")
+		args = append(args, filter.StoreID)
+	}
+	if filter.Category != "" {
+		conditions = append(conditions, "p.category = ?")
+		args = append(args, filter.Category)
+	}
+	if filter.ABCClass != domain.ABCClassNone {
+		conditions = append(conditions, "p.abc_class = ?")
+		args = append(args, filter.ABCClass)
+	}
+
+	return clause + "\n\t\tWHERE " + strings.Join(conditions, " AND "), args
+}
+
+// DeleteIfEmpty elimina la entrada de stock solo si no tiene unidades ni reservas.
+// Retorna false si la fila no existe o todavía tiene stock (la condición se evalúa en el propio DELETE).
+func (r *StockRepository) DeleteIfEmpty(ctx context.Context, productID, storeID string) (bool, error) {
+	query := `DELETE FROM stock WHERE product_id = ? AND store_id = ? AND quantity = 0 AND reserved = 0`
+
+	result, err := r.db.ExecContext(ctx, query, productID, storeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete stock: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// pendingReservedQuery suma de las reservas que retienen stock (PENDING y READY_FOR_PICKUP) de
+// cada (producto, tienda)
+const pendingReservedQuery = `
+	SELECT product_id, store_id, SUM(quantity) AS expected
+	FROM reservations
+	WHERE status IN ('PENDING', 'READY_FOR_PICKUP')
+	GROUP BY product_id, store_id
+`
+
+// FindReservedDiscrepancies compara stock.reserved con la suma de las reservas PENDING y READY_FOR_PICKUP de cada fila
+// más sus retenciones internas (held), con storeID vacío = todas las tiendas. Retorna las filas
+// revisadas y las que no coinciden.
+func (r *StockRepository) FindReservedDiscrepancies(ctx context.Context, storeID string) (int, []*domain.ReservedDiscrepancy, error) {
+	conditions := []string{"s.reserved <> COALESCE(p.expected, 0) + s.held"}
+	countQuery := `SELECT COUNT(*) FROM stock`
+	args := []interface{}{}
+	if storeID != "" {
+		conditions = append(conditions, "s.store_id = ?")
+		countQuery += ` WHERE store_id = ?`
+		args = append(args, storeID)
+	}
+
+	var checked int
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&checked); err != nil {
+		return 0, nil, fmt.Errorf("failed to count stock rows: %w", err)
+	}
+
+	query := `
+		SELECT s.product_id, s.store_id, s.reserved, COALESCE(p.expected, 0) + s.held
+		FROM stock s
+		LEFT JOIN (` + pendingReservedQuery + `) p ON p.product_id = s.product_id AND p.store_id = s.store_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY s.store_id, s.product_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to query reserved discrepancies: %w", err)
+	}
+	defer rows.Close()
+
+	discrepancies := []*domain.ReservedDiscrepancy{}
+	for rows.Next() {
+		var d domain.ReservedDiscrepancy
+		if err := rows.Scan(&d.ProductID, &d.StoreID, &d.Reserved, &d.Expected); err != nil {
+			return 0, nil, fmt.Errorf("failed to scan reserved discrepancy: %w", err)
+		}
+		d.Difference = d.Reserved - d.Expected
+		discrepancies = append(discrepancies, &d)
+	}
+
+	return checked, discrepancies, rows.Err()
+}
+
+// CorrectReserved reemplaza reserved por la suma actual de las reservas PENDING y READY_FOR_PICKUP de la fila más
+// sus retenciones internas (held). Solo se aplica si reserved sigue valiendo observed: si una
+// reserva lo ha cambiado desde el informe retorna false y la fila se revisa en la siguiente
+// reconciliación.
+func (r *StockRepository) CorrectReserved(ctx context.Context, productID, storeID string, observed int) (int, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var expected int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE((
+			SELECT SUM(quantity)
+			FROM reservations
+			WHERE product_id = ? AND store_id = ? AND status IN ('PENDING', 'READY_FOR_PICKUP')
+		), 0) + COALESCE((SELECT held FROM stock WHERE product_id = ? AND store_id = ?), 0)
+	`, productID, storeID, productID, storeID).Scan(&expected)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to sum pending reservations: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE stock
+		SET reserved = ?,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE product_id = ? AND store_id = ? AND reserved = ?
+	`, expected, productID, storeID, observed)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to correct reserved stock: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return 0, false, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return expected, true, nil
 }
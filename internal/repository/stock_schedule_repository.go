@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// StockScheduleRepository maneja los cambios de stock programados
+type StockScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewStockScheduleRepository crea una nueva instancia del repositorio
+func NewStockScheduleRepository(db *sql.DB) *StockScheduleRepository {
+	return &StockScheduleRepository{db: db}
+}
+
+const scheduledStockColumns = `id, product_id, store_id, type, quantity, actor, source, reference, status, error, effective_at, created_at, applied_at`
+
+// Create persiste un cambio de stock programado
+func (r *StockScheduleRepository) Create(ctx context.Context, schedule *domain.ScheduledStockChange) error {
+	query := `
+		INSERT INTO scheduled_stock_changes (id, product_id, store_id, type, quantity, actor, source, reference, status, effective_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		schedule.ID,
+		schedule.ProductID,
+		schedule.StoreID,
+		schedule.Type,
+		schedule.Quantity,
+		schedule.Actor,
+		schedule.Source,
+		schedule.Reference,
+		schedule.Status,
+		schedule.EffectiveAt,
+		schedule.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled stock change: %w", err)
+	}
+
+	return nil
+}
+
+// Get obtiene un cambio de stock programado por ID
+func (r *StockScheduleRepository) Get(ctx context.Context, id string) (*domain.ScheduledStockChange, error) {
+	query := `SELECT ` + scheduledStockColumns + ` FROM scheduled_stock_changes WHERE id = ?`
+
+	schedule, err := scanStockSchedule(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "ScheduledStockChange", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled stock change: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// ListByStock obtiene los cambios programados de una fila de stock ordenados por effective_at
+func (r *StockScheduleRepository) ListByStock(ctx context.Context, productID, storeID string) ([]*domain.ScheduledStockChange, error) {
+	query := `
+		SELECT ` + scheduledStockColumns + `
+		FROM scheduled_stock_changes
+		WHERE product_id = ? AND store_id = ?
+		ORDER BY effective_at ASC
+	`
+
+	return r.query(ctx, query, productID, storeID)
+}
+
+// ListPendingUntil obtiene los cambios pendientes de una fila de stock con effective_at anterior
+// a until, del más antiguo al más reciente
+func (r *StockScheduleRepository) ListPendingUntil(ctx context.Context, productID, storeID string, until time.Time) ([]*domain.ScheduledStockChange, error) {
+	query := `
+		SELECT ` + scheduledStockColumns + `
+		FROM scheduled_stock_changes
+		WHERE product_id = ? AND store_id = ? AND status = ? AND effective_at < ?
+		ORDER BY effective_at ASC
+	`
+
+	return r.query(ctx, query, productID, storeID, domain.ScheduledStockPending, until)
+}
+
+// ListDue obtiene los cambios pendientes cuyo effective_at ya llegó, del más antiguo al más reciente
+func (r *StockScheduleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.ScheduledStockChange, error) {
+	query := `
+		SELECT ` + scheduledStockColumns + `
+		FROM scheduled_stock_changes
+		WHERE status = ? AND effective_at <= ?
+		ORDER BY effective_at ASC
+		LIMIT ?
+	`
+
+	return r.query(ctx, query, domain.ScheduledStockPending, now, limit)
+}
+
+// Cancel anula un cambio programado que sigue pendiente
+func (r *StockScheduleRepository) Cancel(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE scheduled_stock_changes SET status = ? WHERE id = ? AND status = ?`,
+		domain.ScheduledStockCancelled, id, domain.ScheduledStockPending,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to cancel scheduled stock change: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return &domain.ConflictError{Message: fmt.Sprintf("scheduled stock change %s is not pending", id)}
+	}
+
+	return nil
+}
+
+// MarkFailed marca como FAILED un cambio pendiente que no se pudo aplicar
+func (r *StockScheduleRepository) MarkFailed(ctx context.Context, id, reason string, failedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE scheduled_stock_changes SET status = ?, error = ?, applied_at = ? WHERE id = ? AND status = ?`,
+		domain.ScheduledStockFailed, reason, failedAt, id, domain.ScheduledStockPending,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark scheduled stock change as failed: %w", err)
+	}
+	return nil
+}
+
+// Apply aplica un cambio programado en una única transacción: marca el cambio como APPLIED y
+// actualiza la cantidad de la fila de stock respetando el suelo de sobreventa. allowIncrease = false
+// rechaza los cambios que suben la cantidad (producto descatalogado). Retorna la cantidad anterior
+// y la nueva, o applied = false si el cambio ya no estaba pendiente (lo aplicó otra instancia o se
+// canceló mientras tanto).
+func (r *StockScheduleRepository) Apply(ctx context.Context, schedule *domain.ScheduledStockChange, appliedAt time.Time, allowIncrease bool) (oldQuantity, newQuantity int, applied bool, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE scheduled_stock_changes SET status = ?, applied_at = ? WHERE id = ? AND status = ?`,
+		domain.ScheduledStockApplied, appliedAt, schedule.ID, domain.ScheduledStockPending,
+	)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to mark scheduled stock change as applied: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return 0, 0, false, nil
+	}
+
+	var stock domain.Stock
+	var tolerance int
+	err = tx.QueryRowContext(ctx, `
+		SELECT s.id, s.quantity, s.reserved, `+oversellTolerance+`
+		FROM stock s
+		WHERE s.product_id = ? AND s.store_id = ?
+	`, schedule.ProductID, schedule.StoreID).Scan(&stock.ID, &stock.Quantity, &stock.Reserved, &tolerance)
+	if err == sql.ErrNoRows {
+		return 0, 0, false, &domain.NotFoundError{
+			Resource: "Stock",
+			ID:       fmt.Sprintf("product=%s, store=%s", schedule.ProductID, schedule.StoreID),
+		}
+	}
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to get stock: %w", err)
+	}
+
+	oldQuantity = stock.Quantity
+	newQuantity = schedule.NewQuantity(oldQuantity)
+	if newQuantity > oldQuantity && !allowIncrease {
+		return 0, 0, false, &domain.ConflictError{
+			Message: fmt.Sprintf("product %s is discontinued, stock cannot be replenished", schedule.ProductID),
+		}
+	}
+	if newQuantity-stock.Reserved < -tolerance {
+		return 0, 0, false, &domain.ValidationError{
+			Field: "quantity",
+			Message: fmt.Sprintf("new quantity (%d) with %d reserved exceeds the oversell tolerance of %d units",
+				newQuantity, stock.Reserved, tolerance),
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stock
+		SET quantity = ?,
+		    version = version + 1,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, newQuantity, stock.ID)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to update stock quantity: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, false, fmt.Errorf("failed to commit scheduled stock change: %w", err)
+	}
+
+	schedule.Status = domain.ScheduledStockApplied
+	schedule.AppliedAt = &appliedAt
+
+	return oldQuantity, newQuantity, true, nil
+}
+
+func (r *StockScheduleRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.ScheduledStockChange, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled stock changes: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make([]*domain.ScheduledStockChange, 0)
+	for rows.Next() {
+		schedule, err := scanStockSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled stock change: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheduled stock changes: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// scanStockSchedule escanea una fila de scheduled_stock_changes
+func scanStockSchedule(row rowScanner) (*domain.ScheduledStockChange, error) {
+	var schedule domain.ScheduledStockChange
+	var appliedAt sql.NullTime
+
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.ProductID,
+		&schedule.StoreID,
+		&schedule.Type,
+		&schedule.Quantity,
+		&schedule.Actor,
+		&schedule.Source,
+		&schedule.Reference,
+		&schedule.Status,
+		&schedule.Error,
+		&schedule.EffectiveAt,
+		&schedule.CreatedAt,
+		&appliedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if appliedAt.Valid {
+		schedule.AppliedAt = &appliedAt.Time
+	}
+
+	return &schedule, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// StockVisibilityRepository maneja la visibilidad pública de la disponibilidad por tienda
+type StockVisibilityRepository struct {
+	db *sql.DB
+}
+
+// NewStockVisibilityRepository crea una nueva instancia del repositorio
+func NewStockVisibilityRepository(db *sql.DB) *StockVisibilityRepository {
+	return &StockVisibilityRepository{db: db}
+}
+
+// Upsert crea o reemplaza la visibilidad de una tienda
+func (r *StockVisibilityRepository) Upsert(ctx context.Context, visibility *domain.StockVisibility) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO stock_visibility (store_id, hide_quantities, low_stock_threshold, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(store_id) DO UPDATE SET
+			hide_quantities = excluded.hide_quantities,
+			low_stock_threshold = excluded.low_stock_threshold,
+			updated_at = excluded.updated_at
+	`, visibility.StoreID, visibility.HideQuantities, visibility.LowStockThreshold, visibility.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save stock visibility: %w", err)
+	}
+	return nil
+}
+
+// GetByStore obtiene la visibilidad de una tienda (NotFoundError si no tiene)
+func (r *StockVisibilityRepository) GetByStore(ctx context.Context, storeID string) (*domain.StockVisibility, error) {
+	visibilities, err := r.list(ctx, `WHERE store_id = ?`, storeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(visibilities) == 0 {
+		return nil, &domain.NotFoundError{Resource: "StockVisibility", ID: storeID}
+	}
+	return visibilities[0], nil
+}
+
+// GetAll obtiene la visibilidad de todas las tiendas configuradas, por tienda
+func (r *StockVisibilityRepository) GetAll(ctx context.Context) (map[string]*domain.StockVisibility, error) {
+	visibilities, err := r.list(ctx, ``)
+	if err != nil {
+		return nil, err
+	}
+
+	byStore := make(map[string]*domain.StockVisibility, len(visibilities))
+	for _, visibility := range visibilities {
+		byStore[visibility.StoreID] = visibility
+	}
+	return byStore, nil
+}
+
+// Delete elimina la visibilidad de una tienda
+func (r *StockVisibilityRepository) Delete(ctx context.Context, storeID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM stock_visibility WHERE store_id = ?`, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete stock visibility: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "StockVisibility", ID: storeID}
+	}
+	return nil
+}
+
+func (r *StockVisibilityRepository) list(ctx context.Context, where string, args ...interface{}) ([]*domain.StockVisibility, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT store_id, hide_quantities, low_stock_threshold, updated_at
+		FROM stock_visibility `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock visibility: %w", err)
+	}
+	defer rows.Close()
+
+	var visibilities []*domain.StockVisibility
+	for rows.Next() {
+		var visibility domain.StockVisibility
+		if err := rows.Scan(&visibility.StoreID, &visibility.HideQuantities, &visibility.LowStockThreshold, &visibility.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stock visibility: %w", err)
+		}
+		visibilities = append(visibilities, &visibility)
+	}
+	return visibilities, rows.Err()
+}
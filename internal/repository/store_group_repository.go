@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"inventory-system/internal/domain"
+)
+
+// StoreGroupRepository maneja los grupos de tiendas (regiones, franquicias) y sus miembros
+type StoreGroupRepository struct {
+	db *sql.DB
+}
+
+// NewStoreGroupRepository crea una nueva instancia del repositorio
+func NewStoreGroupRepository(db *sql.DB) *StoreGroupRepository {
+	return &StoreGroupRepository{db: db}
+}
+
+// Create inserta el grupo y sus tiendas en una transacción
+func (r *StoreGroupRepository) Create(ctx context.Context, group *domain.StoreGroup) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO store_groups (id, name, group_type, restrict_transfers, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, group.ID, group.Name, group.Type, group.RestrictTransfers, group.CreatedAt, group.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create store group: %w", err)
+	}
+
+	if err := insertGroupMembers(ctx, tx, group); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Update reemplaza los datos y las tiendas del grupo en una transacción
+func (r *StoreGroupRepository) Update(ctx context.Context, group *domain.StoreGroup) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE store_groups
+		SET name = ?, group_type = ?, restrict_transfers = ?, updated_at = ?
+		WHERE id = ?
+	`, group.Name, group.Type, group.RestrictTransfers, group.UpdatedAt, group.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update store group: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "StoreGroup", ID: group.ID}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM store_group_members WHERE group_id = ?`, group.ID); err != nil {
+		return fmt.Errorf("failed to replace store group members: %w", err)
+	}
+	if err := insertGroupMembers(ctx, tx, group); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func insertGroupMembers(ctx context.Context, tx *sql.Tx, group *domain.StoreGroup) error {
+	for _, storeID := range group.StoreIDs {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO store_group_members (group_id, store_id, group_type)
+			VALUES (?, ?, ?)
+		`, group.ID, storeID, group.Type)
+		if err != nil {
+			return fmt.Errorf("failed to add store to group: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetByID obtiene un grupo con sus tiendas
+func (r *StoreGroupRepository) GetByID(ctx context.Context, id string) (*domain.StoreGroup, error) {
+	groups, err := r.list(ctx, `WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, &domain.NotFoundError{Resource: "StoreGroup", ID: id}
+	}
+	return groups[0], nil
+}
+
+// List lista los grupos con sus tiendas, opcionalmente de un tipo
+func (r *StoreGroupRepository) List(ctx context.Context, groupType domain.StoreGroupType) ([]*domain.StoreGroup, error) {
+	return r.list(ctx, `WHERE (? = '' OR group_type = ?)`, groupType, groupType)
+}
+
+// ListByStores lista los grupos a los que pertenece alguna de las tiendas
+func (r *StoreGroupRepository) ListByStores(ctx context.Context, storeIDs ...string) ([]*domain.StoreGroup, error) {
+	if len(storeIDs) == 0 {
+		return []*domain.StoreGroup{}, nil
+	}
+
+	args := make([]interface{}, len(storeIDs))
+	for i, id := range storeIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(storeIDs)), ", ")
+
+	return r.list(ctx, `WHERE id IN (SELECT group_id FROM store_group_members WHERE store_id IN (`+placeholders+`))`, args...)
+}
+
+func (r *StoreGroupRepository) list(ctx context.Context, where string, args ...interface{}) ([]*domain.StoreGroup, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, group_type, restrict_transfers, created_at, updated_at
+		FROM store_groups
+		`+where+`
+		ORDER BY group_type, name, id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list store groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make([]*domain.StoreGroup, 0)
+	byID := make(map[string]*domain.StoreGroup)
+	for rows.Next() {
+		var group domain.StoreGroup
+		err := rows.Scan(
+			&group.ID,
+			&group.Name,
+			&group.Type,
+			&group.RestrictTransfers,
+			&group.CreatedAt,
+			&group.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan store group: %w", err)
+		}
+		group.StoreIDs = []string{}
+		groups = append(groups, &group)
+		byID[group.ID] = &group
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating store groups: %w", err)
+	}
+	if len(groups) == 0 {
+		return groups, nil
+	}
+
+	if err := r.loadMembers(ctx, byID); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// loadMembers completa StoreIDs de los grupos indicados
+func (r *StoreGroupRepository) loadMembers(ctx context.Context, byID map[string]*domain.StoreGroup) error {
+	args := make([]interface{}, 0, len(byID))
+	for id := range byID {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT group_id, store_id
+		FROM store_group_members
+		WHERE group_id IN (`+placeholders+`)
+		ORDER BY store_id
+	`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to list store group members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var groupID, storeID string
+		if err := rows.Scan(&groupID, &storeID); err != nil {
+			return fmt.Errorf("failed to scan store group member: %w", err)
+		}
+		byID[groupID].StoreIDs = append(byID[groupID].StoreIDs, storeID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating store group members: %w", err)
+	}
+	return nil
+}
+
+// Delete elimina el grupo y sus tiendas en una transacción
+func (r *StoreGroupRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM store_group_members WHERE group_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete store group members: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM store_groups WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete store group: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "StoreGroup", ID: id}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
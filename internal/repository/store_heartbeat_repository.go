@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// StoreHeartbeatRepository guarda el último latido de cada instancia edge
+type StoreHeartbeatRepository struct {
+	db *sql.DB
+}
+
+// NewStoreHeartbeatRepository crea una nueva instancia del repositorio
+func NewStoreHeartbeatRepository(db *sql.DB) *StoreHeartbeatRepository {
+	return &StoreHeartbeatRepository{db: db}
+}
+
+// Upsert registra el latido de una instancia. Si no informa last_sync_at se conserva el
+// anterior, y la última alerta se mantiene para no repetirla mientras siga atrasada.
+func (r *StoreHeartbeatRepository) Upsert(ctx context.Context, heartbeat *domain.StoreHeartbeat) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO store_heartbeats (instance_id, store_id, pending_events, last_sync_at, received_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(instance_id) DO UPDATE SET
+			store_id = excluded.store_id,
+			pending_events = excluded.pending_events,
+			last_sync_at = COALESCE(excluded.last_sync_at, store_heartbeats.last_sync_at),
+			received_at = excluded.received_at
+	`, heartbeat.InstanceID, heartbeat.StoreID, heartbeat.PendingEvents, heartbeat.LastSyncAt, heartbeat.ReceivedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save store heartbeat: %w", err)
+	}
+	return nil
+}
+
+// List obtiene el último latido de cada instancia (storeID vacío = todas las tiendas)
+func (r *StoreHeartbeatRepository) List(ctx context.Context, storeID string) ([]*domain.StoreHeartbeat, error) {
+	query := `
+		SELECT instance_id, store_id, pending_events, last_sync_at, received_at, alerted_at
+		FROM store_heartbeats
+	`
+	args := []interface{}{}
+	if storeID != "" {
+		query += ` WHERE store_id = ?`
+		args = append(args, storeID)
+	}
+	query += ` ORDER BY store_id, instance_id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list store heartbeats: %w", err)
+	}
+	defer rows.Close()
+
+	heartbeats := []*domain.StoreHeartbeat{}
+	for rows.Next() {
+		var heartbeat domain.StoreHeartbeat
+		var lastSyncAt, alertedAt sql.NullTime
+		if err := rows.Scan(&heartbeat.InstanceID, &heartbeat.StoreID, &heartbeat.PendingEvents,
+			&lastSyncAt, &heartbeat.ReceivedAt, &alertedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan store heartbeat: %w", err)
+		}
+		if lastSyncAt.Valid {
+			heartbeat.LastSyncAt = &lastSyncAt.Time
+		}
+		if alertedAt.Valid {
+			heartbeat.AlertedAt = &alertedAt.Time
+		}
+		heartbeats = append(heartbeats, &heartbeat)
+	}
+
+	return heartbeats, rows.Err()
+}
+
+// MarkAlerted registra la alerta de sincronización atrasada de una instancia
+func (r *StoreHeartbeatRepository) MarkAlerted(ctx context.Context, instanceID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE store_heartbeats SET alerted_at = ? WHERE instance_id = ?`, at, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to mark store heartbeat alerted: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// StoreHoursRepository maneja los horarios de apertura de las tiendas
+type StoreHoursRepository struct {
+	db *sql.DB
+}
+
+// NewStoreHoursRepository crea una nueva instancia del repositorio
+func NewStoreHoursRepository(db *sql.DB) *StoreHoursRepository {
+	return &StoreHoursRepository{db: db}
+}
+
+// Upsert crea o reemplaza el horario de una tienda
+func (r *StoreHoursRepository) Upsert(ctx context.Context, hours *domain.StoreHours) error {
+	periods, err := json.Marshal(hours.Periods)
+	if err != nil {
+		return fmt.Errorf("failed to encode opening periods: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO store_hours (store_id, timezone, periods, cutoff_minutes, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(store_id) DO UPDATE SET
+			timezone = excluded.timezone,
+			periods = excluded.periods,
+			cutoff_minutes = excluded.cutoff_minutes,
+			updated_at = excluded.updated_at
+	`, hours.StoreID, hours.Timezone, string(periods), hours.CutoffMinutes, hours.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save store hours: %w", err)
+	}
+	return nil
+}
+
+// GetByStore obtiene el horario de una tienda (NotFoundError si no tiene)
+func (r *StoreHoursRepository) GetByStore(ctx context.Context, storeID string) (*domain.StoreHours, error) {
+	var hours domain.StoreHours
+	var periods string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT store_id, timezone, periods, cutoff_minutes, updated_at
+		FROM store_hours
+		WHERE store_id = ?
+	`, storeID).Scan(&hours.StoreID, &hours.Timezone, &periods, &hours.CutoffMinutes, &hours.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "StoreHours", ID: storeID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store hours: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(periods), &hours.Periods); err != nil {
+		return nil, fmt.Errorf("failed to decode opening periods: %w", err)
+	}
+	return &hours, nil
+}
+
+// Delete elimina el horario de una tienda
+func (r *StoreHoursRepository) Delete(ctx context.Context, storeID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM store_hours WHERE store_id = ?`, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete store hours: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "StoreHours", ID: storeID}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"inventory-system/internal/domain"
+)
+
+// StoreRepository maneja las operaciones de persistencia para tiendas
+type StoreRepository struct {
+	db *sql.DB
+}
+
+// NewStoreRepository crea una nueva instancia del repositorio
+func NewStoreRepository(db *sql.DB) *StoreRepository {
+	return &StoreRepository{db: db}
+}
+
+// Create crea una nueva tienda (sin tipo de ubicación = STORE)
+func (r *StoreRepository) Create(ctx context.Context, store *domain.Store) error {
+	if store.LocationType == "" {
+		store.LocationType = domain.LocationTypeStore
+	}
+
+	query := `
+		INSERT INTO stores (id, name, address, city, country, phone, email, active, created_at, location_type)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		store.ID,
+		store.Name,
+		store.Address,
+		store.City,
+		store.Country,
+		store.Phone,
+		store.Email,
+		store.Active,
+		store.CreatedAt,
+		store.LocationType,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID obtiene una tienda por su ID
+func (r *StoreRepository) GetByID(ctx context.Context, id string) (*domain.Store, error) {
+	query := `
+		SELECT id, name, COALESCE(address, ''), COALESCE(city, ''), COALESCE(country, ''),
+		       COALESCE(phone, ''), COALESCE(email, ''), active, created_at, location_type
+		FROM stores
+		WHERE id = ?
+	`
+
+	var store domain.Store
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&store.ID,
+		&store.Name,
+		&store.Address,
+		&store.City,
+		&store.Country,
+		&store.Phone,
+		&store.Email,
+		&store.Active,
+		&store.CreatedAt,
+		&store.LocationType,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "Store", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store: %w", err)
+	}
+
+	return &store, nil
+}
+
+// UpdateLocationType cambia el tipo de ubicación de una tienda
+func (r *StoreRepository) UpdateLocationType(ctx context.Context, id string, locationType domain.LocationType) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE stores SET location_type = ? WHERE id = ?`, locationType, id)
+	if err != nil {
+		return fmt.Errorf("failed to update store location type: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return &domain.NotFoundError{Resource: "Store", ID: id}
+	}
+
+	return nil
+}
+
+// GetLocationTypes obtiene el tipo de ubicación de todas las tiendas
+func (r *StoreRepository) GetLocationTypes(ctx context.Context) (map[string]domain.LocationType, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, location_type FROM stores`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store location types: %w", err)
+	}
+	defer rows.Close()
+
+	locations := make(map[string]domain.LocationType)
+	for rows.Next() {
+		var id string
+		var locationType domain.LocationType
+		if err := rows.Scan(&id, &locationType); err != nil {
+			return nil, fmt.Errorf("failed to scan store location type: %w", err)
+		}
+		locations[id] = locationType
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating store location types: %w", err)
+	}
+
+	return locations, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// TransferRepository maneja la persistencia de transferencias entre tiendas
+type TransferRepository struct {
+	db *sql.DB
+}
+
+// NewTransferRepository crea una nueva instancia del repositorio
+func NewTransferRepository(db *sql.DB) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+// Create persiste una nueva transferencia
+func (r *TransferRepository) Create(ctx context.Context, transfer *domain.StockTransfer) error {
+	query := `
+		INSERT INTO stock_transfers (id, product_id, from_store_id, to_store_id, quantity, status, reservation_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	var reservationID interface{}
+	if transfer.ReservationID != "" {
+		reservationID = transfer.ReservationID
+	}
+
+	_, err := r.db.ExecContext(ctx, query,
+		transfer.ID,
+		transfer.ProductID,
+		transfer.FromStoreID,
+		transfer.ToStoreID,
+		transfer.Quantity,
+		transfer.Status,
+		reservationID,
+		transfer.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create stock transfer: %w", err)
+	}
+
+	return nil
+}
+
+// GetByReservation obtiene la transferencia vinculada a una reserva
+func (r *TransferRepository) GetByReservation(ctx context.Context, reservationID string) (*domain.StockTransfer, error) {
+	query := `
+		SELECT id, product_id, from_store_id, to_store_id, quantity, status,
+		       COALESCE(reservation_id, ''), created_at, updated_at
+		FROM stock_transfers
+		WHERE reservation_id = ?
+	`
+
+	var transfer domain.StockTransfer
+	var updatedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, reservationID).Scan(
+		&transfer.ID,
+		&transfer.ProductID,
+		&transfer.FromStoreID,
+		&transfer.ToStoreID,
+		&transfer.Quantity,
+		&transfer.Status,
+		&transfer.ReservationID,
+		&transfer.CreatedAt,
+		&updatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, &domain.NotFoundError{Resource: "StockTransfer", ID: reservationID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock transfer: %w", err)
+	}
+
+	if updatedAt.Valid {
+		transfer.UpdatedAt = &updatedAt.Time
+	}
+
+	return &transfer, nil
+}
+
+// UpdateStatus actualiza el estado de una transferencia
+func (r *TransferRepository) UpdateStatus(ctx context.Context, id string, status domain.TransferStatus) error {
+	query := `UPDATE stock_transfers SET status = ?, updated_at = ? WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, status, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update stock transfer status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &domain.NotFoundError{Resource: "StockTransfer", ID: id}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"inventory-system/internal/domain"
+)
+
+// TranslationRepository maneja las traducciones de productos
+type TranslationRepository struct {
+	db *sql.DB
+}
+
+// NewTranslationRepository crea una nueva instancia del repositorio
+func NewTranslationRepository(db *sql.DB) *TranslationRepository {
+	return &TranslationRepository{db: db}
+}
+
+// Upsert crea o reemplaza la traducción de un producto en un idioma
+func (r *TranslationRepository) Upsert(ctx context.Context, translation *domain.ProductTranslation) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO product_translations (product_id, locale, name, description, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(product_id, locale) DO UPDATE SET
+			name = excluded.name,
+			description = excluded.description,
+			updated_at = excluded.updated_at
+	`, translation.ProductID, translation.Locale, translation.Name, translation.Description, translation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save product translation: %w", err)
+	}
+	return nil
+}
+
+// ListByProduct lista las traducciones de un producto
+func (r *TranslationRepository) ListByProduct(ctx context.Context, productID string) ([]*domain.ProductTranslation, error) {
+	return r.list(ctx, `WHERE product_id = ?`, productID)
+}
+
+// ListByProductsAndLocale obtiene las traducciones a un idioma de varios productos
+func (r *TranslationRepository) ListByProductsAndLocale(ctx context.Context, productIDs []string, locale string) ([]*domain.ProductTranslation, error) {
+	if len(productIDs) == 0 {
+		return []*domain.ProductTranslation{}, nil
+	}
+
+	args := make([]interface{}, 0, len(productIDs)+1)
+	args = append(args, locale)
+	for _, id := range productIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(productIDs)), ", ")
+
+	return r.list(ctx, `WHERE locale = ? AND product_id IN (`+placeholders+`)`, args...)
+}
+
+func (r *TranslationRepository) list(ctx context.Context, where string, args ...interface{}) ([]*domain.ProductTranslation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT product_id, locale, name, description, updated_at
+		FROM product_translations
+		`+where+`
+		ORDER BY product_id, locale
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product translations: %w", err)
+	}
+	defer rows.Close()
+
+	translations := make([]*domain.ProductTranslation, 0)
+	for rows.Next() {
+		var t domain.ProductTranslation
+		if err := rows.Scan(&t.ProductID, &t.Locale, &t.Name, &t.Description, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan product translation: %w", err)
+		}
+		translations = append(translations, &t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+// Delete elimina la traducción de un producto en un idioma
+func (r *TranslationRepository) Delete(ctx context.Context, productID, locale string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM product_translations WHERE product_id = ? AND locale = ?`, productID, locale)
+	if err != nil {
+		return fmt.Errorf("failed to delete product translation: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &domain.NotFoundError{Resource: "ProductTranslation", ID: productID + "/" + locale}
+	}
+	return nil
+}
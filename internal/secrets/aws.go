@@ -0,0 +1,80 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// AWSProviderConfig configuración para AWSProvider
+type AWSProviderConfig struct {
+	Region          string // "eu-west-1"; vacío = región de la configuración del SDK (AWS_REGION, perfil)
+	SecretID        string // Nombre o ARN del secreto
+	AccessKeyID     string // Opcional: vacío = cadena de credenciales del SDK (perfil, rol de la tarea/instancia)
+	SecretAccessKey string
+	SessionToken    string // Solo con credenciales temporales (STS)
+	Endpoint        string // Opcional (LocalStack, VPC endpoint). Por defecto el endpoint regional
+}
+
+// AWSProvider lee un secreto JSON de AWS Secrets Manager (GetSecretValue) con el SDK de AWS
+type AWSProvider struct {
+	client   *secretsmanager.Client
+	secretID string
+}
+
+// NewAWSProvider crea un provider de AWS Secrets Manager
+func NewAWSProvider(cfg AWSProviderConfig) (*AWSProvider, error) {
+	var options []func(*awsconfig.LoadOptions) error
+	if cfg.Region != "" {
+		options = append(options, awsconfig.WithRegion(cfg.Region))
+	}
+	if cfg.AccessKeyID != "" {
+		options = append(options, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken)))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	if awsCfg.Region == "" {
+		return nil, errors.New("AWS region is required")
+	}
+
+	return &AWSProvider{
+		client: secretsmanager.NewFromConfig(awsCfg, func(o *secretsmanager.Options) {
+			if cfg.Endpoint != "" {
+				o.BaseEndpoint = aws.String(cfg.Endpoint)
+			}
+		}),
+		secretID: cfg.SecretID,
+	}, nil
+}
+
+// Name identifica el provider en logs
+func (p *AWSProvider) Name() string {
+	return "aws"
+}
+
+// Fetch obtiene el SecretString, que debe ser un objeto JSON clave -> valor
+func (p *AWSProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	output, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(p.secretID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", p.secretID, err)
+	}
+
+	raw := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(aws.ToString(output.SecretString)), &raw); err != nil {
+		return nil, fmt.Errorf("secret %s is not a JSON object: %w", p.secretID, err)
+	}
+
+	return decodeSecretMap(raw), nil
+}
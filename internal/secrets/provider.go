@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Provider obtiene secretos de un gestor externo (Vault, AWS Secrets Manager...).
+// Fetch devuelve los secretos indexados por el nombre de la variable de
+// configuración que sustituyen (p. ej. "API_KEYS", "REDIS_PASSWORD").
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// defaultHTTPClient cliente compartido por los providers HTTP
+var defaultHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// decodeSecretMap convierte un objeto JSON de secretos en un mapa de strings.
+// Los valores no string (números, booleanos) se serializan tal cual.
+func decodeSecretMap(raw map[string]interface{}) map[string]string {
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case nil:
+			values[key] = ""
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				values[key] = fmt.Sprint(v)
+				continue
+			}
+			values[key] = string(encoded)
+		}
+	}
+	return values
+}
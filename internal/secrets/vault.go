@@ -0,0 +1,74 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// VaultProvider lee secretos de HashiCorp Vault (motor KV v1 o v2) por HTTP
+type VaultProvider struct {
+	addr   string // "https://vault.internal:8200"
+	token  string
+	path   string // ruta de la API sin /v1, p. ej. "secret/data/inventory"
+	client *http.Client
+}
+
+// NewVaultProvider crea un provider de Vault.
+//
+// Ejemplo (KV v2 montado en secret/):
+//
+//	provider := NewVaultProvider("https://vault:8200", token, "secret/data/inventory")
+func NewVaultProvider(addr, token, path string) *VaultProvider {
+	return &VaultProvider{
+		addr:   strings.TrimRight(addr, "/"),
+		token:  token,
+		path:   strings.Trim(path, "/"),
+		client: defaultHTTPClient,
+	}
+}
+
+// Name identifica el provider en logs
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// Fetch lee el secreto configurado. En KV v2 los valores están en data.data;
+// en KV v1 directamente en data.
+func (p *VaultProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.addr+"/v1/"+p.path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault secret %s: %w", p.path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("vault returned %d for %s: %s", resp.StatusCode, p.path, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode Vault response: %w", err)
+	}
+
+	data := payload.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, isV2 := data["metadata"]; isV2 {
+			data = nested
+		}
+	}
+
+	return decodeSecretMap(data), nil
+}
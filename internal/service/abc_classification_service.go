@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// ABCClassificationService clasifica los productos en A/B/C por su valor de consumo (unidades
+// de reservas confirmadas × precio) en una ventana móvil. La clase se guarda en el producto
+// para filtrar los listados y priorizar las alertas de stock bajo.
+type ABCClassificationService struct {
+	productRepo *repository.ProductRepository
+	window      time.Duration
+}
+
+// NewABCClassificationService crea una nueva instancia del servicio. window 0 desactiva el worker.
+func NewABCClassificationService(productRepo *repository.ProductRepository, window time.Duration) *ABCClassificationService {
+	return &ABCClassificationService{
+		productRepo: productRepo,
+		window:      window,
+	}
+}
+
+// Enabled indica si hay ventana configurada (ABC_WINDOW_DAYS > 0)
+func (s *ABCClassificationService) Enabled() bool {
+	return s.window > 0
+}
+
+// Classify recalcula la clase de todos los productos que no están en DRAFT y la guarda
+func (s *ABCClassificationService) Classify(ctx context.Context) (*domain.ABCClassificationRun, error) {
+	if !s.Enabled() {
+		return nil, &domain.ConflictError{
+			Message: "ABC classification is disabled (ABC_WINDOW_DAYS=0)",
+		}
+	}
+
+	now := time.Now()
+	since := now.Add(-s.window)
+
+	consumption, err := s.productRepo.GetConsumption(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	classes := domain.ClassifyABC(consumption)
+	if err := s.productRepo.ReplaceABCClasses(ctx, classes); err != nil {
+		return nil, err
+	}
+
+	run := &domain.ABCClassificationRun{
+		WindowDays:   int(s.window.Hours() / 24),
+		Since:        since,
+		Products:     len(classes),
+		ClassifiedAt: now,
+	}
+	for _, class := range classes {
+		switch class {
+		case domain.ABCClassA:
+			run.ClassA++
+		case domain.ABCClassB:
+			run.ClassB++
+		case domain.ABCClassC:
+			run.ClassC++
+		}
+	}
+	return run, nil
+}
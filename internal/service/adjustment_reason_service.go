@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// AdjustmentReasonService gestiona el catálogo de motivos de los ajustes manuales de stock
+type AdjustmentReasonService struct {
+	reasonRepo *repository.AdjustmentReasonRepository
+}
+
+// NewAdjustmentReasonService crea una nueva instancia del servicio
+func NewAdjustmentReasonService(reasonRepo *repository.AdjustmentReasonRepository) *AdjustmentReasonService {
+	return &AdjustmentReasonService{
+		reasonRepo: reasonRepo,
+	}
+}
+
+// ListReasons lista los motivos del catálogo; includeInactive incluye los desactivados
+func (s *AdjustmentReasonService) ListReasons(ctx context.Context, includeInactive bool) ([]*domain.AdjustmentReason, error) {
+	return s.reasonRepo.List(ctx, includeInactive)
+}
+
+// PutReason crea un motivo o actualiza su descripción y estado
+func (s *AdjustmentReasonService) PutReason(ctx context.Context, reason *domain.AdjustmentReason) (*domain.AdjustmentReason, error) {
+	reason.Code = strings.ToLower(strings.TrimSpace(reason.Code))
+	reason.Description = strings.TrimSpace(reason.Description)
+	if err := reason.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reason.CreatedAt = now
+	reason.UpdatedAt = now
+	if existing, err := s.reasonRepo.Get(ctx, reason.Code); err == nil {
+		reason.CreatedAt = existing.CreatedAt
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	if err := s.reasonRepo.Upsert(ctx, reason); err != nil {
+		return nil, err
+	}
+	return reason, nil
+}
+
+// DeactivateReason desactiva un motivo: deja de aceptarse en ajustes nuevos, pero se mantiene en
+// los movimientos ya registrados y en los reportes
+func (s *AdjustmentReasonService) DeactivateReason(ctx context.Context, code string) (*domain.AdjustmentReason, error) {
+	reason, err := s.reasonRepo.Get(ctx, strings.ToLower(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, err
+	}
+
+	reason.Active = false
+	reason.UpdatedAt = time.Now()
+	if err := s.reasonRepo.Upsert(ctx, reason); err != nil {
+		return nil, err
+	}
+	return reason, nil
+}
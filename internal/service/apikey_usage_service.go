@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+const usageDayLayout = "2006-01-02"
+
+// usageBucket identifica un rollup diario pendiente de persistir
+type usageBucket struct {
+	keyID string
+	day   string
+}
+
+// usageDelta acumula el uso en memoria entre flushes
+type usageDelta struct {
+	requests   int
+	errors     int
+	lastUsedAt time.Time
+}
+
+// APIKeyUsageService registra y reporta el uso de las API keys.
+// Los contadores se acumulan en memoria y se persisten periódicamente con Flush
+// para no añadir una escritura a la base de datos en cada request.
+type APIKeyUsageService struct {
+	usageRepo *repository.APIKeyUsageRepository
+	keyRing   *auth.KeyRing
+
+	mu      sync.Mutex
+	pending map[usageBucket]*usageDelta
+}
+
+// NewAPIKeyUsageService crea una nueva instancia del servicio
+func NewAPIKeyUsageService(usageRepo *repository.APIKeyUsageRepository, keyRing *auth.KeyRing) *APIKeyUsageService {
+	return &APIKeyUsageService{
+		usageRepo: usageRepo,
+		keyRing:   keyRing,
+		pending:   make(map[usageBucket]*usageDelta),
+	}
+}
+
+// Record registra un request autenticado con la API key (status >= 400 cuenta como error)
+func (s *APIKeyUsageService) Record(apiKey string, statusCode int, at time.Time) {
+	bucket := usageBucket{
+		keyID: domain.APIKeyID(apiKey),
+		day:   at.UTC().Format(usageDayLayout),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delta, ok := s.pending[bucket]
+	if !ok {
+		delta = &usageDelta{}
+		s.pending[bucket] = delta
+	}
+
+	delta.requests++
+	if statusCode >= 400 {
+		delta.errors++
+	}
+	if at.After(delta.lastUsedAt) {
+		delta.lastUsedAt = at
+	}
+}
+
+// Flush persiste los contadores acumulados. Retorna la cantidad de rollups escritos.
+// Si falla una escritura, los contadores no persistidos se devuelven al buffer.
+func (s *APIKeyUsageService) Flush(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[usageBucket]*usageDelta)
+	s.mu.Unlock()
+
+	flushed := 0
+	for bucket, delta := range pending {
+		err := s.usageRepo.Increment(ctx, bucket.keyID, bucket.day, delta.requests, delta.errors, delta.lastUsedAt)
+		if err != nil {
+			s.restore(pending)
+			return flushed, err
+		}
+		delete(pending, bucket)
+		flushed++
+	}
+
+	return flushed, nil
+}
+
+// restore devuelve al buffer los contadores que no se pudieron persistir
+func (s *APIKeyUsageService) restore(pending map[usageBucket]*usageDelta) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for bucket, delta := range pending {
+		current, ok := s.pending[bucket]
+		if !ok {
+			s.pending[bucket] = delta
+			continue
+		}
+		current.requests += delta.requests
+		current.errors += delta.errors
+		if delta.lastUsedAt.After(current.lastUsedAt) {
+			current.lastUsedAt = delta.lastUsedAt
+		}
+	}
+}
+
+// GetUsage obtiene el uso de una API key con el rollup diario de los últimos días
+func (s *APIKeyUsageService) GetUsage(ctx context.Context, keyID string, days int) (*domain.APIKeyUsage, error) {
+	name, ok := s.keyRing.IDs()[keyID]
+	if !ok {
+		return nil, &domain.NotFoundError{
+			Resource: "api_key",
+			ID:       keyID,
+		}
+	}
+
+	if days <= 0 || days > 365 {
+		return nil, &domain.ValidationError{
+			Field:   "days",
+			Message: "days must be between 1 and 365",
+		}
+	}
+
+	if _, err := s.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	since := now.UTC().AddDate(0, 0, -(days - 1)).Format(usageDayLayout)
+
+	daily, err := s.usageRepo.GetDaily(ctx, keyID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	lastUsed, err := s.usageRepo.GetLastUsed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	trackingSince, err := s.trackingSince(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := s.summarize(keyID, name, lastUsed, trackingSince, now)
+	usage.Daily = daily
+	if usage.Daily == nil {
+		usage.Daily = []domain.APIKeyUsageDay{}
+	}
+
+	for _, d := range daily {
+		usage.TotalRequests += d.RequestCount
+		usage.TotalErrors += d.ErrorCount
+	}
+	if usage.TotalRequests > 0 {
+		usage.ErrorRate = float64(usage.TotalErrors) / float64(usage.TotalRequests)
+	}
+
+	return usage, nil
+}
+
+// ListUsage obtiene el resumen de todas las API keys configuradas
+func (s *APIKeyUsageService) ListUsage(ctx context.Context) ([]domain.APIKeyUsage, error) {
+	if _, err := s.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	lastUsed, err := s.usageRepo.GetLastUsed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	trackingSince, err := s.trackingSince(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	keyNames := s.keyRing.IDs()
+	usages := make([]domain.APIKeyUsage, 0, len(keyNames))
+	for keyID, name := range keyNames {
+		usages = append(usages, *s.summarize(keyID, name, lastUsed, trackingSince, now))
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Name < usages[j].Name
+	})
+
+	return usages, nil
+}
+
+// FindUnusedKeys obtiene las API keys sin uso durante domain.APIKeyUnusedThreshold (candidatas a revocación)
+func (s *APIKeyUsageService) FindUnusedKeys(ctx context.Context) ([]domain.APIKeyUsage, error) {
+	usages, err := s.ListUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var unused []domain.APIKeyUsage
+	for _, usage := range usages {
+		if usage.RevocationCandidate {
+			unused = append(unused, usage)
+		}
+	}
+
+	return unused, nil
+}
+
+// summarize arma el resumen de una key a partir de su último uso conocido.
+// Una key nunca usada solo es candidata si el tracking lleva activo más que el umbral,
+// para no marcar todas las keys justo después de desplegar.
+func (s *APIKeyUsageService) summarize(keyID, name string, lastUsed map[string]time.Time, trackingSince time.Time, now time.Time) *domain.APIKeyUsage {
+	usage := &domain.APIKeyUsage{
+		KeyID: keyID,
+		Name:  name,
+	}
+
+	if t, ok := lastUsed[keyID]; ok {
+		usage.LastUsedAt = &t
+		usage.RevocationCandidate = now.Sub(t) >= domain.APIKeyUnusedThreshold
+	} else if !trackingSince.IsZero() {
+		usage.RevocationCandidate = now.Sub(trackingSince) >= domain.APIKeyUnusedThreshold
+	}
+
+	return usage
+}
+
+// trackingSince obtiene desde cuándo hay datos de uso registrados
+func (s *APIKeyUsageService) trackingSince(ctx context.Context) (time.Time, error) {
+	day, err := s.usageRepo.GetFirstTrackedDay(ctx)
+	if err != nil || day == "" {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(usageDayLayout, day)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	return t, nil
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// assortmentJobTimeout tiempo máximo de ejecución de un job de clonación
+const assortmentJobTimeout = 10 * time.Minute
+
+// AssortmentService clona el surtido de una tienda a otras como job asíncrono
+type AssortmentService struct {
+	jobRepo   *repository.AssortmentJobRepository
+	storeRepo *repository.StoreRepository
+	stockRepo *repository.StockRepository
+	eventRepo *repository.EventRepository
+	publisher domain.EventPublisher
+
+	rundownRepo *repository.RunDownRepository
+	viewRepo    *repository.AvailabilityViewRepository
+}
+
+// NewAssortmentService crea una nueva instancia del servicio
+func NewAssortmentService(
+	jobRepo *repository.AssortmentJobRepository,
+	storeRepo *repository.StoreRepository,
+	stockRepo *repository.StockRepository,
+	eventRepo *repository.EventRepository,
+	publisher domain.EventPublisher,
+) *AssortmentService {
+	return &AssortmentService{
+		jobRepo:   jobRepo,
+		storeRepo: storeRepo,
+		stockRepo: stockRepo,
+		eventRepo: eventRepo,
+		publisher: publisher,
+	}
+}
+
+// SetRunDownRepository hace que la clonación omita los productos descatalogados
+func (s *AssortmentService) SetRunDownRepository(rundownRepo *repository.RunDownRepository) {
+	s.rundownRepo = rundownRepo
+}
+
+// SetAvailabilityView hace que la copia de umbrales refresque el read model de disponibilidad
+// (no emite evento y min_stock decide el tramo y el stock bajo)
+func (s *AssortmentService) SetAvailabilityView(viewRepo *repository.AvailabilityViewRepository) {
+	s.viewRepo = viewRepo
+}
+
+// StartCloneJob valida y encola la clonación del surtido de sourceStoreID a las tiendas destino.
+// El job se ejecuta en background; su estado y reporte se consultan con GetCloneJob.
+func (s *AssortmentService) StartCloneJob(ctx context.Context, sourceStoreID string, targetStoreIDs []string, copyThresholds bool) (*domain.AssortmentCloneJob, error) {
+	job := &domain.AssortmentCloneJob{
+		ID:             uuid.New().String(),
+		SourceStoreID:  sourceStoreID,
+		TargetStoreIDs: dedupe(targetStoreIDs),
+		CopyThresholds: copyThresholds,
+		Status:         domain.JobStatusPending,
+		Report:         []domain.AssortmentCloneResult{},
+		CreatedAt:      time.Now(),
+	}
+
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validar que las tiendas destino existen antes de aceptar el job
+	for _, storeID := range job.TargetStoreIDs {
+		if _, err := s.storeRepo.GetByID(ctx, storeID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		jobCtx, cancel := context.WithTimeout(context.Background(), assortmentJobTimeout)
+		defer cancel()
+
+		if err := s.RunCloneJob(jobCtx, job); err != nil {
+			log.Printf("Error running assortment clone job %s: %v", job.ID, err)
+		}
+	}()
+
+	return job, nil
+}
+
+// GetCloneJob obtiene el estado y reporte de un job
+func (s *AssortmentService) GetCloneJob(ctx context.Context, id string) (*domain.AssortmentCloneJob, error) {
+	return s.jobRepo.GetByID(ctx, id)
+}
+
+// RunCloneJob ejecuta la clonación de forma síncrona y persiste el reporte.
+// Un fallo en una tienda destino se registra en su resultado sin detener el resto.
+func (s *AssortmentService) RunCloneJob(ctx context.Context, job *domain.AssortmentCloneJob) error {
+	startedAt := time.Now()
+	job.Status = domain.JobStatusRunning
+	job.StartedAt = &startedAt
+	if err := s.jobRepo.MarkRunning(ctx, job.ID, startedAt); err != nil {
+		return err
+	}
+
+	source, err := s.stockRepo.GetAllByStore(ctx, job.SourceStoreID)
+	if err == nil && len(source) == 0 {
+		err = &domain.NotFoundError{Resource: "Assortment", ID: "store=" + job.SourceStoreID}
+	}
+
+	if err != nil {
+		job.Status = domain.JobStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Report = make([]domain.AssortmentCloneResult, 0, len(job.TargetStoreIDs))
+		for _, storeID := range job.TargetStoreIDs {
+			job.Report = append(job.Report, s.cloneInto(ctx, source, storeID, job.CopyThresholds))
+		}
+		job.Status = domain.JobStatusCompleted
+	}
+
+	completedAt := time.Now()
+	job.CompletedAt = &completedAt
+
+	return s.jobRepo.Complete(ctx, job)
+}
+
+// cloneInto clona el surtido en una tienda destino. Las filas existentes no se tocan,
+// salvo los umbrales cuando copyThresholds está activo.
+func (s *AssortmentService) cloneInto(ctx context.Context, source []*domain.Stock, storeID string, copyThresholds bool) domain.AssortmentCloneResult {
+	result := domain.AssortmentCloneResult{StoreID: storeID}
+
+	existing, err := s.stockRepo.GetAllByStore(ctx, storeID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	stocked := make(map[string]bool, len(existing))
+	for _, stock := range existing {
+		stocked[stock.ProductID] = true
+	}
+
+	for _, src := range source {
+		if stocked[src.ProductID] {
+			if !copyThresholds {
+				result.Skipped++
+				continue
+			}
+			if err := s.stockRepo.UpdateThresholds(ctx, src.ProductID, storeID, src.MinStock, src.MaxStock); err != nil {
+				result.Error = err.Error()
+				return result
+			}
+			if s.viewRepo != nil {
+				if err := s.viewRepo.RefreshProduct(ctx, src.ProductID); err != nil {
+					log.Printf("Warning: failed to refresh availability view: %v", err)
+				}
+			}
+			result.ThresholdsUpdated++
+			continue
+		}
+
+		// No se añaden al surtido productos en run-down
+		if s.rundownRepo != nil {
+			discontinued, err := s.rundownRepo.IsDiscontinued(ctx, src.ProductID)
+			if err != nil {
+				result.Error = err.Error()
+				return result
+			}
+			if discontinued {
+				result.Skipped++
+				continue
+			}
+		}
+
+		stock := &domain.Stock{
+			ID:        uuid.New().String(),
+			ProductID: src.ProductID,
+			StoreID:   storeID,
+			Quantity:  0,
+			Reserved:  0,
+			Version:   1,
+		}
+		if copyThresholds {
+			stock.MinStock = src.MinStock
+			stock.MaxStock = src.MaxStock
+		}
+		if err := s.stockRepo.Create(ctx, stock); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		result.Created++
+
+		event := domain.NewStockCreatedEvent(src.ProductID, storeID, 0)
+		if err := s.eventRepo.Save(ctx, event); err != nil {
+			log.Printf("Warning: failed to save stock created event: %v", err)
+		}
+		if err := s.publisher.Publish(ctx, event); publishFailed(err) {
+			log.Printf("Warning: failed to publish stock created event: %v", err)
+		}
+	}
+
+	return result
+}
+
+// dedupe elimina IDs duplicados conservando el orden
+func dedupe(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// MaxAuditRange limita la cantidad de registros verificados en una sola petición
+const MaxAuditRange = 100000
+
+// AuditService verifica la integridad de la cadena de auditoría de eventos
+type AuditService struct {
+	eventRepo *repository.EventRepository
+}
+
+// NewAuditService crea una nueva instancia del servicio
+func NewAuditService(eventRepo *repository.EventRepository) *AuditService {
+	return &AuditService{
+		eventRepo: eventRepo,
+	}
+}
+
+// VerifyChain recalcula los hashes del tramo [fromSeq, toSeq] y comprueba que cada
+// registro apunte al anterior. toSeq = 0 verifica hasta el último registro.
+func (s *AuditService) VerifyChain(ctx context.Context, fromSeq, toSeq int64) (*domain.AuditVerification, error) {
+	if fromSeq <= 0 {
+		fromSeq = 1
+	}
+
+	headSeq, _, err := s.eventRepo.GetChainHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if toSeq <= 0 || toSeq > headSeq {
+		toSeq = headSeq
+	}
+
+	// Los registros anteriores al checkpoint los borró la retención: la verificación
+	// empieza en el primer registro conservado
+	checkpoint, err := s.eventRepo.GetAuditCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint != nil && fromSeq <= checkpoint.Seq {
+		fromSeq = checkpoint.Seq + 1
+	}
+
+	result := &domain.AuditVerification{
+		FromSeq: fromSeq,
+		ToSeq:   toSeq,
+		Valid:   true,
+		Issues:  []domain.AuditIssue{},
+	}
+
+	// Cadena vacía o tramo posterior al último registro: nada que verificar
+	if headSeq == 0 || fromSeq > toSeq {
+		return result, nil
+	}
+
+	if toSeq-fromSeq+1 > MaxAuditRange {
+		return nil, &domain.ValidationError{
+			Field:   "to",
+			Message: fmt.Sprintf("range cannot exceed %d records", MaxAuditRange),
+		}
+	}
+
+	// El primer registro del tramo se enlaza con el inmediatamente anterior
+	prevSeq, prevHash, err := s.eventRepo.GetChainLinkBefore(ctx, fromSeq)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint != nil && prevSeq <= checkpoint.Seq {
+		prevSeq, prevHash = checkpoint.Seq, checkpoint.Hash
+	}
+	if prevSeq == 0 {
+		prevSeq = fromSeq - 1
+	}
+
+	events, err := s.eventRepo.GetChainRange(ctx, fromSeq, toSeq)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.Seq != prevSeq+1 {
+			result.AddIssue(domain.AuditIssue{
+				Seq:     event.Seq,
+				EventID: event.ID,
+				Reason:  fmt.Sprintf("sequence gap: records %d-%d are missing", prevSeq+1, event.Seq-1),
+			})
+		}
+		if event.PrevHash != prevHash {
+			result.AddIssue(domain.AuditIssue{
+				Seq:     event.Seq,
+				EventID: event.ID,
+				Reason:  "prev_hash does not match the previous record",
+			})
+		}
+		if domain.ComputeEventHash(event, event.PrevHash) != event.Hash {
+			result.AddIssue(domain.AuditIssue{
+				Seq:     event.Seq,
+				EventID: event.ID,
+				Reason:  "hash mismatch: record was modified",
+			})
+		}
+
+		prevSeq, prevHash = event.Seq, event.Hash
+		result.Checked++
+	}
+
+	if prevSeq < toSeq {
+		result.AddIssue(domain.AuditIssue{
+			Seq:    prevSeq + 1,
+			Reason: fmt.Sprintf("sequence gap: records %d-%d are missing", prevSeq+1, toSeq),
+		})
+	}
+
+	result.HeadHash = prevHash
+	return result, nil
+}
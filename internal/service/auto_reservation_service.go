@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// AutoReservationService reserva un producto eligiendo la tienda: la primera de una lista
+// priorizada (o de todas, por disponibilidad) que pueda servir la cantidad. Si se permite,
+// reparte la cantidad entre varias tiendas cuando ninguna la cubre sola.
+type AutoReservationService struct {
+	reservationService *ReservationService
+	stockRepo          *repository.StockRepository
+	groupService       *ReservationGroupService
+	fulfillmentService *FulfillmentService
+}
+
+// NewAutoReservationService crea una nueva instancia del servicio
+func NewAutoReservationService(reservationService *ReservationService, stockRepo *repository.StockRepository) *AutoReservationService {
+	return &AutoReservationService{
+		reservationService: reservationService,
+		stockRepo:          stockRepo,
+	}
+}
+
+// SetReservationGroupService agrupa las reservas de un reparto para confirmarlas o cancelarlas juntas
+func (s *AutoReservationService) SetReservationGroupService(groupService *ReservationGroupService) {
+	s.groupService = groupService
+}
+
+// SetFulfillmentService permite elegir las tiendas según la forma de entrega (envío o recogida)
+func (s *AutoReservationService) SetFulfillmentService(fulfillmentService *FulfillmentService) {
+	s.fulfillmentService = fulfillmentService
+}
+
+// CreateAutoReservation reserva quantity unidades en las tiendas de stores, en orden de
+// preferencia (vacía o "any" = todas, de más a menos disponibilidad). Con allowSplit la
+// cantidad se reparte en orden si ninguna tienda la cubre sola; si aun así no alcanza no se
+// deja ninguna reserva y se retorna InsufficientStockError.
+//
+// Con fulfillment solo son candidatas las ubicaciones cuyo tipo sirve esa forma de entrega en
+// la política del canal; con "any" además se prueban antes los tipos preferidos (p. ej.
+// almacenes para envíos), y dentro de cada tipo de más a menos disponibilidad.
+func (s *AutoReservationService) CreateAutoReservation(ctx context.Context, productID string, stores []string, customerID string, quantity, ttlMinutes int, priority domain.ReservationPriority, allowSplit bool, fulfillment domain.FulfillmentMode) (*domain.AutoReservation, error) {
+	if quantity <= 0 {
+		return nil, &domain.ValidationError{
+			Field:   "quantity",
+			Message: "quantity must be positive",
+		}
+	}
+
+	candidates, err := s.candidateStocks(ctx, productID, stores)
+	if err != nil {
+		return nil, err
+	}
+	if candidates, err = s.fulfillmentCandidates(ctx, candidates, fulfillment, domain.IsAnyStore(stores)); err != nil {
+		return nil, err
+	}
+
+	result := &domain.AutoReservation{ProductID: productID, Requested: quantity}
+
+	// Primero una sola tienda que cubra toda la cantidad
+	for _, stock := range candidates {
+		if !stock.CanReserve(quantity) {
+			continue
+		}
+		reservation, err := s.reservationService.CreateReservationWithPriority(ctx, productID, stock.StoreID, customerID, quantity, ttlMinutes, priority)
+		if skipStore(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Reservations = append(result.Reservations, reservation)
+		return result, nil
+	}
+
+	available := 0
+	for _, stock := range candidates {
+		if stock.Sellable() > 0 {
+			available += stock.Sellable()
+		}
+	}
+	if !allowSplit || available < quantity {
+		return nil, s.insufficientStock(productID, candidates, available, quantity)
+	}
+
+	// Repartir en orden de preferencia
+	remaining := quantity
+	for _, stock := range candidates {
+		if remaining == 0 {
+			break
+		}
+		take := stock.Sellable()
+		if take <= 0 {
+			continue
+		}
+		if take > remaining {
+			take = remaining
+		}
+
+		reservation, err := s.reservationService.CreateReservationWithPriority(ctx, productID, stock.StoreID, customerID, take, ttlMinutes, priority)
+		if skipStore(err) {
+			continue
+		}
+		if err != nil {
+			s.rollback(ctx, result.Reservations)
+			return nil, err
+		}
+		result.Reservations = append(result.Reservations, reservation)
+		remaining -= take
+	}
+
+	if remaining > 0 {
+		s.rollback(ctx, result.Reservations)
+		return nil, s.insufficientStock(productID, candidates, quantity-remaining, quantity)
+	}
+
+	result.Split = len(result.Reservations) > 1
+	if result.Split && s.groupService != nil {
+		group, err := s.groupService.GroupReservations(ctx, customerID, result.Reservations)
+		if err != nil {
+			s.rollback(ctx, result.Reservations)
+			return nil, err
+		}
+		result.GroupID = group.ID
+	}
+	return result, nil
+}
+
+// candidateStocks retorna el stock del producto en las tiendas candidatas, en orden de preferencia.
+// Con "any" solo se consideran las tiendas a las que está limitada la API key; una lista
+// explícita con tiendas fuera de su alcance se rechaza entera.
+func (s *AutoReservationService) candidateStocks(ctx context.Context, productID string, stores []string) ([]*domain.Stock, error) {
+	stocks, err := s.stockRepo.GetAllByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if domain.IsAnyStore(stores) {
+		var candidates []*domain.Stock
+		for _, stock := range stocks {
+			if domain.AuthorizeStoreWrite(ctx, stock.StoreID) == nil {
+				candidates = append(candidates, stock)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Sellable() > candidates[j].Sellable()
+		})
+		return candidates, nil
+	}
+
+	byStore := make(map[string]*domain.Stock, len(stocks))
+	for _, stock := range stocks {
+		byStore[stock.StoreID] = stock
+	}
+
+	var candidates []*domain.Stock
+	seen := make(map[string]bool, len(stores))
+	for _, storeID := range stores {
+		if storeID == "" || storeID == domain.AutoReservationAnyStore {
+			return nil, &domain.ValidationError{
+				Field:   "stores",
+				Message: `stores must be a list of store IDs or ["any"]`,
+			}
+		}
+		if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+			return nil, err
+		}
+		if seen[storeID] {
+			continue
+		}
+		seen[storeID] = true
+
+		// Una tienda sin stock del producto no es candidata
+		if stock, ok := byStore[storeID]; ok {
+			candidates = append(candidates, stock)
+		}
+	}
+	return candidates, nil
+}
+
+// fulfillmentCandidates descarta las tiendas cuyo tipo de ubicación no sirve la forma de entrega
+// y, si reorder, ordena el resto por la preferencia de la política del canal
+func (s *AutoReservationService) fulfillmentCandidates(ctx context.Context, candidates []*domain.Stock, fulfillment domain.FulfillmentMode, reorder bool) ([]*domain.Stock, error) {
+	if fulfillment == "" || s.fulfillmentService == nil {
+		return candidates, nil
+	}
+	ranker, err := s.fulfillmentService.Ranker(ctx, fulfillment)
+	if err != nil {
+		return nil, err
+	}
+
+	var served []*domain.Stock
+	for _, stock := range candidates {
+		if _, ok := ranker.Rank(stock.StoreID); ok {
+			served = append(served, stock)
+		}
+	}
+	if reorder {
+		sort.SliceStable(served, func(i, j int) bool {
+			return ranker.Less(served[i].StoreID, served[j].StoreID)
+		})
+	}
+	return served, nil
+}
+
+// rollback cancela las reservas ya creadas cuando el reparto no se completa
+func (s *AutoReservationService) rollback(ctx context.Context, reservations []*domain.Reservation) {
+	for _, reservation := range reservations {
+		if err := s.reservationService.CancelReservation(ctx, reservation.ID); err != nil {
+			log.Printf("Warning: failed to roll back reservation %s: %v", reservation.ID, err)
+		}
+	}
+}
+
+func (s *AutoReservationService) insufficientStock(productID string, candidates []*domain.Stock, available, requested int) error {
+	storeIDs := make([]string, 0, len(candidates))
+	for _, stock := range candidates {
+		storeIDs = append(storeIDs, stock.StoreID)
+	}
+	return &domain.InsufficientStockError{
+		ProductID: productID,
+		StoreID:   strings.Join(storeIDs, ","),
+		Available: available,
+		Requested: requested,
+	}
+}
+
+// skipStore indica si el error solo descarta la tienda (sin stock suficiente para el canal
+// o cerrada) y se puede probar con la siguiente
+func skipStore(err error) bool {
+	return errors.Is(err, domain.ErrInsufficientStock) || errors.Is(err, domain.ErrStoreClosed)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// AvailabilityProjector mantiene el read model de disponibilidad (availability_view) a partir de
+// los eventos. Cada evento con product_id vuelve a proyectar desde stock todas las tiendas del
+// producto, así que las repeticiones y el orden de los eventos no alteran el resultado.
+type AvailabilityProjector struct {
+	viewRepo *repository.AvailabilityViewRepository
+}
+
+// NewAvailabilityProjector crea una nueva instancia del proyector
+func NewAvailabilityProjector(viewRepo *repository.AvailabilityViewRepository) *AvailabilityProjector {
+	return &AvailabilityProjector{viewRepo: viewRepo}
+}
+
+// HandleEvent proyecta el producto afectado por el evento (ignora eventos sin producto)
+func (p *AvailabilityProjector) HandleEvent(ctx context.Context, event *domain.Event) error {
+	var payload struct {
+		ProductID string `json:"product_id"`
+	}
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil || payload.ProductID == "" {
+		return nil
+	}
+	return p.viewRepo.RefreshProduct(ctx, payload.ProductID)
+}
+
+// Rebuild reconstruye el read model completo desde stock: al arrancar (cambios hechos con el
+// servicio parado) y bajo demanda si se sospecha de un evento perdido
+func (p *AvailabilityProjector) Rebuild(ctx context.Context) (*domain.AvailabilityViewRebuild, error) {
+	started := time.Now()
+
+	rows, err := p.viewRepo.Rebuild(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AvailabilityViewRebuild{
+		Rows:      rows,
+		StartedAt: started,
+		Duration:  time.Since(started).Round(time.Millisecond).String(),
+	}, nil
+}
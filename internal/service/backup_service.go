@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// backupNamePattern nombre de los ficheros de backup: inventory-<timestamp UTC>.db
+var backupNamePattern = regexp.MustCompile(`^inventory-\d{8}T\d{6}\.\d{3}Z\.db$`)
+
+// BackupService genera backups en caliente de la base de datos SQLite y conserva los más recientes
+type BackupService struct {
+	backupRepo *repository.BackupRepository
+	dir        string
+	retain     int
+
+	mu sync.Mutex // Serializa backups manuales y programados
+}
+
+// NewBackupService crea una nueva instancia del servicio. retain es el número de backups conservados.
+func NewBackupService(backupRepo *repository.BackupRepository, dir string, retain int) *BackupService {
+	return &BackupService{
+		backupRepo: backupRepo,
+		dir:        dir,
+		retain:     retain,
+	}
+}
+
+// CreateBackup escribe un backup nuevo en el directorio de backups y borra los que exceden la retención
+func (s *BackupService) CreateBackup(ctx context.Context) (*domain.Backup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	createdAt := time.Now().UTC()
+	name := "inventory-" + createdAt.Format("20060102T150405.000Z") + ".db"
+	path := filepath.Join(s.dir, name)
+
+	// VACUUM INTO escribe en un fichero temporal: un backup interrumpido nunca aparece en el listado
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+	if err := s.backupRepo.VacuumInto(ctx, tmp); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to store backup: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat backup: %w", err)
+	}
+	backup := &domain.Backup{Name: name, SizeBytes: info.Size(), CreatedAt: createdAt}
+
+	if pruned, err := s.pruneBackups(); err != nil {
+		log.Printf("Warning: failed to prune backups: %v", err)
+	} else if pruned > 0 {
+		log.Printf("🧹 Removed %d old backups", pruned)
+	}
+
+	return backup, nil
+}
+
+// ListBackups retorna los backups disponibles, del más reciente al más antiguo
+func (s *BackupService) ListBackups(ctx context.Context) ([]*domain.Backup, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return []*domain.Backup{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	backups := []*domain.Backup{}
+	for _, entry := range entries {
+		if entry.IsDir() || !backupNamePattern.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Borrado entre ReadDir e Info (poda concurrente)
+		}
+		backups = append(backups, &domain.Backup{Name: entry.Name(), SizeBytes: info.Size(), CreatedAt: info.ModTime().UTC()})
+	}
+
+	// El timestamp del nombre ordena cronológicamente
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Name > backups[j].Name })
+	return backups, nil
+}
+
+// OpenBackup abre un backup para descargarlo. El llamador cierra el contenido.
+func (s *BackupService) OpenBackup(ctx context.Context, name string) (*domain.Backup, io.ReadCloser, error) {
+	if !backupNamePattern.MatchString(name) {
+		return nil, nil, &domain.NotFoundError{Resource: "backup", ID: name}
+	}
+
+	file, err := os.Open(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		return nil, nil, &domain.NotFoundError{Resource: "backup", ID: name}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to stat backup: %w", err)
+	}
+
+	return &domain.Backup{Name: name, SizeBytes: info.Size(), CreatedAt: info.ModTime().UTC()}, file, nil
+}
+
+// pruneBackups borra los backups más antiguos que exceden la retención
+func (s *BackupService) pruneBackups() (int, error) {
+	backups, err := s.ListBackups(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for i := s.retain; i < len(backups); i++ {
+		if err := os.Remove(filepath.Join(s.dir, backups[i].Name)); err != nil && !os.IsNotExist(err) {
+			return pruned, fmt.Errorf("failed to remove backup %s: %w", backups[i].Name, err)
+		}
+		pruned++
+	}
+	return pruned, nil
+}
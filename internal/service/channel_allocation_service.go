@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// ChannelAllocationService gestiona el reparto del stock vendible de una fila entre canales de
+// venta. Las reservas descuentan de la asignación del canal del request (ver ReservationService).
+type ChannelAllocationService struct {
+	channelRepo  *repository.ChannelAllocationRepository
+	featureFlags *FeatureFlagService
+}
+
+// NewChannelAllocationService crea una nueva instancia del servicio
+func NewChannelAllocationService(channelRepo *repository.ChannelAllocationRepository) *ChannelAllocationService {
+	return &ChannelAllocationService{
+		channelRepo: channelRepo,
+	}
+}
+
+// SetFeatureFlags activa el feature flag channel_allocation: con la funcionalidad desactivada en
+// una tienda no se pueden crear ni mover asignaciones (las existentes se siguen aplicando)
+func (s *ChannelAllocationService) SetFeatureFlags(featureFlags *FeatureFlagService) {
+	s.featureFlags = featureFlags
+}
+
+// GetAllocations obtiene la fila de stock y sus asignaciones por canal
+func (s *ChannelAllocationService) GetAllocations(ctx context.Context, productID, storeID string) (*domain.Stock, []*domain.ChannelAllocation, error) {
+	return s.channelRepo.ListByStock(ctx, productID, storeID)
+}
+
+// SetAllocation fija las unidades asignadas a un canal
+func (s *ChannelAllocationService) SetAllocation(ctx context.Context, productID, storeID, channel string, allocated int) error {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return err
+	}
+	salesChannel, err := requireSalesChannel("channel", channel)
+	if err != nil {
+		return err
+	}
+	if allocated < 0 {
+		return &domain.ValidationError{
+			Field:   "allocated",
+			Message: "allocated cannot be negative",
+		}
+	}
+	if err := s.requireFeature(ctx, storeID); err != nil {
+		return err
+	}
+
+	return s.channelRepo.Set(ctx, productID, storeID, salesChannel, allocated)
+}
+
+// DeleteAllocation elimina la asignación de un canal (sus reservas pendientes se conservan)
+func (s *ChannelAllocationService) DeleteAllocation(ctx context.Context, productID, storeID, channel string) error {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return err
+	}
+	salesChannel, err := requireSalesChannel("channel", channel)
+	if err != nil {
+		return err
+	}
+
+	return s.channelRepo.Delete(ctx, productID, storeID, salesChannel)
+}
+
+// TransferAllocation mueve unidades asignadas y sin reservar de un canal a otro
+func (s *ChannelAllocationService) TransferAllocation(ctx context.Context, productID, storeID, fromChannel, toChannel string, quantity int) error {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return err
+	}
+	from, err := requireSalesChannel("from_channel", fromChannel)
+	if err != nil {
+		return err
+	}
+	to, err := requireSalesChannel("to_channel", toChannel)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return &domain.ValidationError{
+			Field:   "to_channel",
+			Message: "cannot transfer to the same channel",
+		}
+	}
+	if quantity <= 0 {
+		return &domain.ValidationError{
+			Field:   "quantity",
+			Message: "quantity must be positive",
+		}
+	}
+	if err := s.requireFeature(ctx, storeID); err != nil {
+		return err
+	}
+
+	return s.channelRepo.Transfer(ctx, productID, storeID, from, to, quantity)
+}
+
+// requireFeature comprueba el feature flag channel_allocation de la tienda
+func (s *ChannelAllocationService) requireFeature(ctx context.Context, storeID string) error {
+	if s.featureFlags == nil {
+		return nil
+	}
+	return s.featureFlags.Require(ctx, domain.FeatureChannelAllocation, storeID)
+}
+
+// requireSalesChannel valida un canal obligatorio
+func requireSalesChannel(field, value string) (domain.SalesChannel, error) {
+	channel, err := domain.ParseSalesChannel(value)
+	if err != nil || channel == "" {
+		return "", &domain.ValidationError{Field: field, Message: field + " must be WEB, STORE or MARKETPLACE"}
+	}
+	return channel, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// registeredConfirmHook hook de confirmación con su política
+type registeredConfirmHook struct {
+	hook   domain.ConfirmHook
+	policy domain.ConfirmHookPolicy
+}
+
+// confirmHooks ejecuta los hooks registrados en ReservationService: los síncronos antes de
+// descontar el stock (pueden impedir la confirmación) y los asíncronos en segundo plano una vez
+// confirmada la reserva
+type confirmHooks struct {
+	mu    sync.RWMutex
+	hooks []registeredConfirmHook
+	wg    sync.WaitGroup // Hooks asíncronos en curso
+}
+
+func (h *confirmHooks) add(hook domain.ConfirmHook, policy domain.ConfirmHookPolicy) {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	if !policy.Mode.IsValid() {
+		policy.Mode = domain.ConfirmHookSync
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = append(h.hooks, registeredConfirmHook{hook: hook, policy: policy})
+}
+
+func (h *confirmHooks) byMode(mode domain.ConfirmHookMode) []registeredConfirmHook {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var hooks []registeredConfirmHook
+	for _, registered := range h.hooks {
+		if registered.policy.Mode == mode {
+			hooks = append(hooks, registered)
+		}
+	}
+	return hooks
+}
+
+// runSync ejecuta en orden los hooks síncronos y se detiene en el primero que falla
+func (h *confirmHooks) runSync(ctx context.Context, confirmation *domain.ReservationConfirmation) error {
+	for _, registered := range h.byMode(domain.ConfirmHookSync) {
+		if err := registered.call(ctx, confirmation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAsync lanza los hooks asíncronos. Siguen aunque termine el request que confirmó la reserva.
+func (h *confirmHooks) runAsync(ctx context.Context, confirmation *domain.ReservationConfirmation) {
+	ctx = context.WithoutCancel(ctx)
+	for _, registered := range h.byMode(domain.ConfirmHookAsync) {
+		h.wg.Add(1)
+		go func(registered registeredConfirmHook) {
+			defer h.wg.Done()
+			if err := registered.call(ctx, confirmation); err != nil {
+				log.Printf("⚠️  %v (reservation %s already confirmed)", err, confirmation.ReservationID)
+			}
+		}(registered)
+	}
+}
+
+// wait espera a que terminen los hooks asíncronos en curso
+func (h *confirmHooks) wait() {
+	h.wg.Wait()
+}
+
+// call ejecuta el hook con reintentos y backoff exponencial. Un ConfirmRejectedError corta los
+// reintentos. El error retornado es siempre un ConfirmHookError.
+func (r registeredConfirmHook) call(ctx context.Context, confirmation *domain.ReservationConfirmation) error {
+	backoff := r.policy.Backoff
+	var err error
+	for attempt := 1; attempt <= r.policy.MaxAttempts; attempt++ {
+		if err = r.hook.OnConfirm(ctx, confirmation); err == nil {
+			return nil
+		}
+
+		var rejected *domain.ConfirmRejectedError
+		if errors.As(err, &rejected) {
+			return &domain.ConfirmHookError{Hook: r.hook.Name(), Attempts: attempt, Rejected: true, Err: err}
+		}
+		if attempt == r.policy.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return &domain.ConfirmHookError{Hook: r.hook.Name(), Attempts: attempt, Err: ctx.Err()}
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return &domain.ConfirmHookError{Hook: r.hook.Name(), Attempts: r.policy.MaxAttempts, Err: err}
+}
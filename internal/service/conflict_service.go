@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// ConflictService aplica cambios de stock remotos (sincronización entre tiendas)
+// resolviendo ediciones concurrentes con la política ADJUSTMENT_MERGE.
+// Los conflictos que no pueden resolverse automáticamente quedan registrados
+// como UNRESOLVED para intervención manual.
+type ConflictService struct {
+	conflictRepo *repository.ConflictRepository
+	stockRepo    *repository.StockRepository
+	eventRepo    *repository.EventRepository
+	publisher    domain.EventPublisher
+
+	reservationService *ReservationService // Opcional: liberar reservas por prioridad al resolver por debajo de lo reservado
+	contention         *ContentionService  // Opcional: conflictos de versión por fila de stock
+}
+
+// NewConflictService crea una nueva instancia del servicio
+func NewConflictService(
+	conflictRepo *repository.ConflictRepository,
+	stockRepo *repository.StockRepository,
+	eventRepo *repository.EventRepository,
+	publisher domain.EventPublisher,
+) *ConflictService {
+	return &ConflictService{
+		conflictRepo: conflictRepo,
+		stockRepo:    stockRepo,
+		eventRepo:    eventRepo,
+		publisher:    publisher,
+	}
+}
+
+// SetReservationService permite resolver conflictos con una cantidad inferior a lo reservado:
+// se cancelan las reservas pendientes de menor prioridad hasta que la cantidad las cubra
+func (s *ConflictService) SetReservationService(reservationService *ReservationService) {
+	s.reservationService = reservationService
+}
+
+// SetContentionService registra las escrituras de la sincronización y sus conflictos de
+// versión en el informe de contención
+func (s *ConflictService) SetContentionService(contention *ContentionService) {
+	s.contention = contention
+}
+
+// ApplyRemoteStockUpdate aplica un cambio de stock originado en otra instancia.
+//
+// Política:
+//   - Si la versión local coincide con la versión base del emisor, no hay
+//     conflicto y se escribe la nueva cantidad (fast-forward).
+//   - Si la versión local avanzó, se re-aplica el delta remoto sobre la cantidad
+//     local y se registra un conflicto AUTO_RESOLVED.
+//   - Si el merge dejaría el stock negativo o por debajo de lo reservado, no se
+//     modifica el stock y se registra un conflicto UNRESOLVED.
+//
+// Retorna el conflicto registrado, o nil si el cambio se aplicó sin conflicto.
+func (s *ConflictService) ApplyRemoteStockUpdate(ctx context.Context, update *domain.RemoteStockUpdate) (*domain.StockConflict, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+	if err := domain.AuthorizeStoreWrite(ctx, update.StoreID); err != nil {
+		return nil, err
+	}
+
+	local, err := s.stockRepo.GetByProductAndStore(ctx, update.ProductID, update.StoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fast-forward: nadie modificó el stock desde que el emisor lo leyó
+	if local.Version == update.BaseVersion {
+		if update.NewQuantity < local.Reserved {
+			return s.recordUnresolved(ctx, local, update, fmt.Sprintf(
+				"remote quantity (%d) is below reserved (%d)", update.NewQuantity, local.Reserved))
+		}
+		if err := s.writeQuantity(ctx, local, update.NewQuantity); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	merged, ok := domain.MergeStockAdjustment(local, update)
+	if !ok {
+		return s.recordUnresolved(ctx, local, update, fmt.Sprintf(
+			"merged quantity (%d) violates stock invariants (reserved: %d)", merged, local.Reserved))
+	}
+
+	if err := s.writeQuantity(ctx, local, merged); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	conflict := newStockConflict(local, update)
+	conflict.Status = domain.ConflictStatusAutoResolved
+	conflict.ResolvedQuantity = &merged
+	conflict.ResolvedAt = &now
+
+	if err := s.conflictRepo.Create(ctx, conflict); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🔀 Stock conflict auto-resolved: product=%s store=%s local_v=%d remote_base_v=%d merged=%d",
+		update.ProductID, update.StoreID, local.Version, update.BaseVersion, merged)
+
+	return conflict, nil
+}
+
+// ListUnresolved lista los conflictos que requieren intervención manual
+func (s *ConflictService) ListUnresolved(ctx context.Context, storeID string, limit, offset int) ([]*domain.StockConflict, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.conflictRepo.ListByStatus(ctx, domain.ConflictStatusUnresolved, storeID, limit, offset)
+}
+
+// ResolveConflict resuelve manualmente un conflicto fijando la cantidad definitiva.
+// Con el servicio de reservas configurado, una cantidad inferior a lo reservado libera las
+// reservas pendientes de menor prioridad; sin él se rechaza.
+func (s *ConflictService) ResolveConflict(ctx context.Context, conflictID string, quantity int, reason string) (*domain.StockConflict, error) {
+	conflict, err := s.conflictRepo.GetByID(ctx, conflictID)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.AuthorizeStoreWrite(ctx, conflict.StoreID); err != nil {
+		return nil, err
+	}
+
+	if conflict.Status != domain.ConflictStatusUnresolved {
+		return nil, &domain.InvalidStateError{
+			CurrentState:    string(conflict.Status),
+			AttemptedAction: "resolve conflict",
+		}
+	}
+
+	local, err := s.stockRepo.GetByProductAndStore(ctx, conflict.ProductID, conflict.StoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quantity < local.Reserved && s.reservationService != nil {
+		released, err := s.reservationService.ReleaseByPriority(ctx, local.ProductID, local.StoreID, local.Reserved-quantity)
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range released {
+			log.Printf("🔓 Reservation %s (%s) released to resolve conflict %s", reservation.ID, reservation.Priority, conflictID)
+		}
+
+		// La cancelación modificó reserved y la versión del stock
+		if local, err = s.stockRepo.GetByProductAndStore(ctx, conflict.ProductID, conflict.StoreID); err != nil {
+			return nil, err
+		}
+	}
+
+	if quantity < local.Reserved {
+		return nil, &domain.ValidationError{
+			Field:   "quantity",
+			Message: fmt.Sprintf("quantity (%d) cannot be less than reserved (%d)", quantity, local.Reserved),
+		}
+	}
+
+	if err := s.writeQuantity(ctx, local, quantity); err != nil {
+		return nil, err
+	}
+
+	if err := s.conflictRepo.MarkResolved(ctx, conflictID, quantity, reason); err != nil {
+		return nil, err
+	}
+
+	return s.conflictRepo.GetByID(ctx, conflictID)
+}
+
+// writeQuantity escribe la cantidad con optimistic locking y emite stock.updated
+func (s *ConflictService) writeQuantity(ctx context.Context, local *domain.Stock, quantity int) error {
+	oldQuantity := local.Quantity
+	local.Quantity = quantity
+
+	err := s.stockRepo.UpdateQuantity(ctx, local)
+	recordVersionedWrite(s.contention, local.ProductID, local.StoreID, err)
+	if err != nil {
+		return err
+	}
+
+	event := domain.NewStockUpdatedEvent(local.ProductID, local.StoreID, oldQuantity, quantity)
+
+	if err := s.eventRepo.Save(ctx, event); err != nil {
+		log.Printf("Warning: failed to save stock update event: %v", err)
+	}
+
+	if err := s.publisher.Publish(ctx, event); publishFailed(err) {
+		log.Printf("Warning: failed to publish stock update event: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ConflictService) recordUnresolved(ctx context.Context, local *domain.Stock, update *domain.RemoteStockUpdate, reason string) (*domain.StockConflict, error) {
+	conflict := newStockConflict(local, update)
+	conflict.Status = domain.ConflictStatusUnresolved
+	conflict.Reason = reason
+
+	if err := s.conflictRepo.Create(ctx, conflict); err != nil {
+		return nil, err
+	}
+
+	log.Printf("⚠️  Unresolved stock conflict %s: product=%s store=%s (%s)",
+		conflict.ID, update.ProductID, update.StoreID, reason)
+
+	return conflict, nil
+}
+
+func newStockConflict(local *domain.Stock, update *domain.RemoteStockUpdate) *domain.StockConflict {
+	return &domain.StockConflict{
+		ID:             uuid.New().String(),
+		ProductID:      update.ProductID,
+		StoreID:        update.StoreID,
+		OriginInstance: update.OriginInstance,
+		Strategy:       domain.ConflictStrategyAdjustmentMerge,
+		LocalVersion:   local.Version,
+		LocalQuantity:  local.Quantity,
+		LocalReserved:  local.Reserved,
+		RemoteBase:     update.BaseVersion,
+		RemoteQuantity: update.NewQuantity,
+		RemoteDelta:    update.Delta(),
+		CreatedAt:      time.Now(),
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// contentionSlots número de tramos de la ventana móvil de contención
+const contentionSlots = 60
+
+// stockRowContention escrituras de una fila de stock en un tramo de la ventana
+type stockRowContention struct {
+	productID      string
+	storeID        string
+	writes         int
+	conflicts      int
+	lastConflictAt time.Time
+}
+
+// contentionSlot tramo de la ventana móvil
+type contentionSlot struct {
+	start time.Time
+	rows  map[string]*stockRowContention
+}
+
+// ContentionService cuenta en el proceso las escrituras con optimistic locking por fila de
+// stock y cuántas fallan por conflicto de versión, para detectar los productos que conviene
+// pasar al modo flash sale (cola de alta concurrencia)
+type ContentionService struct {
+	window    time.Duration
+	slotSize  time.Duration
+	startedAt time.Time
+
+	flashSaleService *FlashSaleService // Opcional: marcar los productos que ya usan la cola
+
+	totalWrites    atomic.Int64
+	totalConflicts atomic.Int64
+
+	mu    sync.Mutex
+	slots [contentionSlots]contentionSlot
+}
+
+// NewContentionService crea el servicio con la ventana indicada (p. ej. 1 hora)
+func NewContentionService(window time.Duration) *ContentionService {
+	slotSize := window / contentionSlots
+	if slotSize <= 0 {
+		slotSize = time.Millisecond
+	}
+	return &ContentionService{
+		window:    window,
+		slotSize:  slotSize,
+		startedAt: time.Now(),
+	}
+}
+
+// SetFlashSaleService marca en el informe los productos que ya están en modo flash sale
+func (s *ContentionService) SetFlashSaleService(flashSaleService *FlashSaleService) {
+	s.flashSaleService = flashSaleService
+}
+
+// RecordWrite registra una escritura con optimistic locking sobre la fila (producto, tienda)
+// y si fue rechazada por conflicto de versión
+func (s *ContentionService) RecordWrite(productID, storeID string, conflict bool, at time.Time) {
+	s.totalWrites.Add(1)
+	if conflict {
+		s.totalConflicts.Add(1)
+	}
+
+	start := at.Truncate(s.slotSize)
+	index := int((start.UnixNano() / int64(s.slotSize)) % contentionSlots)
+	key := productID + "|" + storeID
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slot := &s.slots[index]
+	if slot.start.After(start) {
+		return // Más antiguo que la ventana
+	}
+	if !slot.start.Equal(start) {
+		slot.start = start
+		slot.rows = make(map[string]*stockRowContention)
+	}
+	row, ok := slot.rows[key]
+	if !ok {
+		row = &stockRowContention{productID: productID, storeID: storeID}
+		slot.rows[key] = row
+	}
+
+	row.writes++
+	if conflict {
+		row.conflicts++
+		if at.After(row.lastConflictAt) {
+			row.lastConflictAt = at
+		}
+	}
+}
+
+// Totals escrituras y conflictos de versión registrados desde el arranque del proceso
+func (s *ContentionService) Totals() (writes, conflicts int64) {
+	return s.totalWrites.Load(), s.totalConflicts.Load()
+}
+
+// Report calcula las filas con conflictos en los tramos de la ventana vigentes en now, de más
+// a menos conflictos (limit 0 = todas)
+func (s *ContentionService) Report(ctx context.Context, now time.Time, limit int) (*domain.ContentionReport, error) {
+	since := now.Add(-s.window)
+	if since.Before(s.startedAt) {
+		since = s.startedAt
+	}
+
+	merged := make(map[string]*stockRowContention)
+	s.mu.Lock()
+	for i := range s.slots {
+		slot := &s.slots[i]
+		if slot.rows == nil || !slot.start.Add(s.slotSize).After(now.Add(-s.window)) || slot.start.After(now) {
+			continue
+		}
+		for key, row := range slot.rows {
+			total, ok := merged[key]
+			if !ok {
+				total = &stockRowContention{productID: row.productID, storeID: row.storeID}
+				merged[key] = total
+			}
+			total.writes += row.writes
+			total.conflicts += row.conflicts
+			if row.lastConflictAt.After(total.lastConflictAt) {
+				total.lastConflictAt = row.lastConflictAt
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	totalWrites, totalConflicts := s.Totals()
+	report := &domain.ContentionReport{
+		WindowSeconds:  s.window.Seconds(),
+		Since:          since,
+		GeneratedAt:    now,
+		Rows:           []*domain.StockContention{},
+		TotalWrites:    totalWrites,
+		TotalConflicts: totalConflicts,
+	}
+	for _, row := range merged {
+		if row.conflicts == 0 {
+			continue
+		}
+		lastConflictAt := row.lastConflictAt
+		report.Rows = append(report.Rows, &domain.StockContention{
+			ProductID:      row.productID,
+			StoreID:        row.storeID,
+			Writes:         row.writes,
+			Conflicts:      row.conflicts,
+			ConflictRate:   float64(row.conflicts) / float64(row.writes),
+			LastConflictAt: &lastConflictAt,
+		})
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.Conflicts != b.Conflicts {
+			return a.Conflicts > b.Conflicts
+		}
+		if a.ConflictRate != b.ConflictRate {
+			return a.ConflictRate > b.ConflictRate
+		}
+		if a.ProductID != b.ProductID {
+			return a.ProductID < b.ProductID
+		}
+		return a.StoreID < b.StoreID
+	})
+	if limit > 0 && len(report.Rows) > limit {
+		report.Rows = report.Rows[:limit]
+	}
+	report.Count = len(report.Rows)
+
+	if s.flashSaleService != nil {
+		for _, row := range report.Rows {
+			enabled, err := s.flashSaleService.IsEnabled(ctx, row.ProductID)
+			if err != nil {
+				return nil, err
+			}
+			row.FlashSale = enabled
+		}
+	}
+
+	return report, nil
+}
+
+// recordVersionedWrite registra el resultado de una escritura con optimistic locking (sin
+// servicio de contención no hace nada)
+func recordVersionedWrite(contention *ContentionService, productID, storeID string, err error) {
+	if contention == nil {
+		return
+	}
+	if err != nil && !errors.Is(err, domain.ErrConflict) {
+		return // Errores de BD: no es contención
+	}
+	contention.RecordWrite(productID, storeID, err != nil, time.Now())
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -17,6 +18,13 @@ type EventPublisher interface {
 	Close() error
 }
 
+// publishFailed indica si un error de publicación en tiempo real debe registrarse.
+// Con el circuit breaker abierto el evento queda en el outbox y no se registra cada intento:
+// el worker de sincronización lo publicará cuando el broker se recupere.
+func publishFailed(err error) bool {
+	return err != nil && !errors.Is(err, domain.ErrPublisherUnavailable)
+}
+
 // EventSyncService maneja la sincronización de eventos con message brokers.
 // Actúa como mecanismo de RETRY para eventos que fallaron en la publicación inicial.
 type EventSyncService struct {
@@ -56,6 +64,11 @@ func (s *EventSyncService) SyncPendingEvents(ctx context.Context, batchSize int)
 	for _, event := range events {
 		// Intenta publicar en el broker (Redis/Kafka)
 		err := s.publisher.Publish(ctx, event)
+		if errors.Is(err, domain.ErrPublisherUnavailable) {
+			// Circuito abierto: el resto del lote fallaría igual
+			log.Printf("⏸️  Broker unavailable, %d pending events stay in the outbox", len(events)-syncedCount)
+			break
+		}
 		if err != nil {
 			log.Printf("⚠️  Failed to sync event %s: %v (will retry later)", event.ID, err)
 			failedCount++
@@ -108,6 +121,14 @@ func (s *EventSyncService) GetEventsByStore(ctx context.Context, storeID string,
 	return s.eventRepo.GetByStore(ctx, storeID, limit, offset)
 }
 
+// StreamEvents recorre los eventos del filtro por orden de seq sin acumularlos (listados en streaming)
+func (s *EventSyncService) StreamEvents(ctx context.Context, filter domain.EventFilter, fn func(*domain.Event) error) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	return s.eventRepo.EachEvent(ctx, filter, fn)
+}
+
 // GetEventsByProduct obtiene el historial de eventos de un producto
 func (s *EventSyncService) GetEventsByProduct(ctx context.Context, productID string) (interface{}, error) {
 	return s.eventRepo.GetByAggregateID(ctx, productID)
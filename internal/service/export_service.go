@@ -0,0 +1,260 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+const (
+	// exportJobTimeout tiempo máximo de generación; pasado este tiempo un job RUNNING se da
+	// por abandonado (la instancia se detuvo) y se vuelve a generar
+	exportJobTimeout = 15 * time.Minute
+
+	// exportBatchSize limita cuántos jobs genera el worker por pasada
+	exportBatchSize = 5
+)
+
+// ExportService genera exportaciones grandes (stock, movimientos, reservas) en background.
+// El fichero se guarda en el almacenamiento de blobs y se descarga a través de la API.
+type ExportService struct {
+	exportRepo *repository.ExportRepository
+	store      domain.BlobStore
+	retention  time.Duration
+}
+
+// NewExportService crea una nueva instancia del servicio. retention es el tiempo durante el que
+// se puede descargar el fichero generado.
+func NewExportService(exportRepo *repository.ExportRepository, store domain.BlobStore, retention time.Duration) *ExportService {
+	return &ExportService{
+		exportRepo: exportRepo,
+		store:      store,
+		retention:  retention,
+	}
+}
+
+// RequestExport valida y encola una exportación. El autor se toma del context.
+func (s *ExportService) RequestExport(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+
+	job.ID = uuid.New().String()
+	job.Status = domain.JobStatusPending
+	job.Actor = domain.ActorFromContext(ctx)
+	job.CreatedAt = time.Now()
+
+	if err := s.exportRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetExport obtiene el estado de una exportación
+func (s *ExportService) GetExport(ctx context.Context, id string) (*domain.ExportJob, error) {
+	return s.exportRepo.GetByID(ctx, id)
+}
+
+// OpenExport abre el fichero de una exportación completada. El llamador cierra el reader.
+func (s *ExportService) OpenExport(ctx context.Context, id string) (*domain.ExportJob, io.ReadCloser, error) {
+	job, err := s.exportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != domain.JobStatusCompleted {
+		return nil, nil, &domain.InvalidStateError{
+			CurrentState:    string(job.Status),
+			AttemptedAction: "download export " + id,
+		}
+	}
+
+	content, err := s.store.Get(ctx, job.BlobKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, content, nil
+}
+
+// ProcessPendingExports genera las exportaciones pendientes (usado por el worker periódico).
+// Retorna el número de exportaciones completadas.
+func (s *ExportService) ProcessPendingExports(ctx context.Context) (int, error) {
+	completed := 0
+	for i := 0; i < exportBatchSize; i++ {
+		now := time.Now()
+		job, err := s.exportRepo.ClaimNext(ctx, now, now.Add(-exportJobTimeout))
+		if err != nil {
+			return completed, err
+		}
+		if job == nil {
+			break
+		}
+
+		if err := s.RunExport(ctx, job); err != nil {
+			log.Printf("Warning: failed to complete export %s: %v", job.ID, err)
+			continue
+		}
+		if job.Status == domain.JobStatusCompleted {
+			completed++
+		}
+	}
+
+	return completed, nil
+}
+
+// RunExport genera el fichero de un job ya marcado como RUNNING y persiste el resultado.
+// Un error al generar deja el job FAILED; solo se retornan los errores al guardar el resultado.
+func (s *ExportService) RunExport(ctx context.Context, job *domain.ExportJob) error {
+	jobCtx, cancel := context.WithTimeout(ctx, exportJobTimeout)
+	defer cancel()
+
+	var buf bytes.Buffer
+	rows, err := s.generate(jobCtx, job, &buf)
+	if err == nil {
+		key := "exports/" + job.ID + "." + job.Format.Extension()
+		if _, err = s.store.Put(jobCtx, key, job.Format.ContentType(), bytes.NewReader(buf.Bytes()), int64(buf.Len())); err == nil {
+			job.BlobKey = key
+		}
+	}
+
+	completedAt := time.Now()
+	job.CompletedAt = &completedAt
+	if err != nil {
+		job.Status = domain.JobStatusFailed
+		job.Error = err.Error()
+	} else {
+		expiresAt := completedAt.Add(s.retention)
+		job.Status = domain.JobStatusCompleted
+		job.Rows = rows
+		job.SizeBytes = int64(buf.Len())
+		job.ExpiresAt = &expiresAt
+	}
+
+	return s.exportRepo.Complete(ctx, job)
+}
+
+// PurgeExpiredExports borra los ficheros y los jobs cuya retención terminó
+func (s *ExportService) PurgeExpiredExports(ctx context.Context) (int, error) {
+	expired, err := s.exportRepo.ListExpired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, job := range expired {
+		if job.BlobKey != "" {
+			if err := s.store.Delete(ctx, job.BlobKey); err != nil {
+				log.Printf("Warning: failed to delete export file %s: %v", job.BlobKey, err)
+				continue
+			}
+		}
+		if err := s.exportRepo.Delete(ctx, job.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// generate escribe las filas del job en w con el formato solicitado
+func (s *ExportService) generate(ctx context.Context, job *domain.ExportJob, w io.Writer) (int, error) {
+	columns := domain.ExportColumns(job.Type)
+
+	if job.Format == domain.ExportFormatJSON {
+		if _, err := io.WriteString(w, "["); err != nil {
+			return 0, err
+		}
+		first := true
+		rows, err := s.exportRepo.WriteRows(ctx, job, func(values []interface{}) error {
+			err := writeJSONRow(w, columns, values, first)
+			first = false
+			return err
+		})
+		if err != nil {
+			return rows, err
+		}
+		_, err = io.WriteString(w, "]\n")
+		return rows, err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return 0, err
+	}
+	record := make([]string, len(columns))
+	rows, err := s.exportRepo.WriteRows(ctx, job, func(values []interface{}) error {
+		for i, value := range values {
+			record[i] = csvValue(value)
+		}
+		return writer.Write(record)
+	})
+	if err != nil {
+		return rows, err
+	}
+	writer.Flush()
+	return rows, writer.Error()
+}
+
+// writeJSONRow escribe una fila como objeto JSON conservando el orden de las columnas
+func writeJSONRow(w io.Writer, columns []string, values []interface{}, first bool) error {
+	var buf bytes.Buffer
+	if !first {
+		buf.WriteByte(',')
+	}
+	buf.WriteByte('{')
+	for i, column := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		value := values[i]
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC().Format(time.RFC3339)
+		}
+		if t, ok := value.(*time.Time); ok && t != nil {
+			value = t.UTC().Format(time.RFC3339)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode column %s: %w", column, err)
+		}
+		name, _ := json.Marshal(column)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(encoded)
+	}
+	buf.WriteByte('}')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// csvValue formatea un valor de la exportación para CSV (fechas en RFC3339 UTC)
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case json.RawMessage:
+		return string(v)
+	}
+	return fmt.Sprint(value)
+}
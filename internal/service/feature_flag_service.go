@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// FeatureFlagService decide si una funcionalidad está activada para una tienda. Las reglas de
+// FEATURE_FLAGS se pueden sobrescribir en runtime desde la API de admin (tabla feature_flags).
+// Orden de resolución: admin de la tienda, config de la tienda, admin global, config global, valor
+// por defecto de la funcionalidad (activada salvo fractional_quantities).
+type FeatureFlagService struct {
+	flagRepo    *repository.FeatureFlagRepository
+	storeRepo   *repository.StoreRepository
+	configRules []*domain.FeatureFlagRule
+
+	mu         sync.RWMutex
+	adminRules []*domain.FeatureFlagRule // Cache de la tabla, se recarga tras cada cambio
+	loaded     bool
+}
+
+// NewFeatureFlagService crea una nueva instancia del servicio con las reglas de configuración
+func NewFeatureFlagService(flagRepo *repository.FeatureFlagRepository, storeRepo *repository.StoreRepository, configRules []*domain.FeatureFlagRule) *FeatureFlagService {
+	return &FeatureFlagService{
+		flagRepo:    flagRepo,
+		storeRepo:   storeRepo,
+		configRules: configRules,
+	}
+}
+
+// IsEnabled indica si la funcionalidad está activada para la tienda
+func (s *FeatureFlagService) IsEnabled(ctx context.Context, feature domain.Feature, storeID string) (bool, error) {
+	adminRules, err := s.rules(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	scopes := []string{""}
+	if storeID != "" {
+		scopes = []string{storeID, ""}
+	}
+	for _, scope := range scopes {
+		for _, rules := range [][]*domain.FeatureFlagRule{adminRules, s.configRules} {
+			if rule := findFeatureRule(rules, feature, scope); rule != nil {
+				return rule.Enabled, nil
+			}
+		}
+	}
+	return feature.EnabledByDefault(), nil
+}
+
+// Require retorna FeatureDisabledError si la funcionalidad está desactivada para la tienda
+func (s *FeatureFlagService) Require(ctx context.Context, feature domain.Feature, storeID string) error {
+	enabled, err := s.IsEnabled(ctx, feature, storeID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return &domain.FeatureDisabledError{Feature: feature, StoreID: storeID}
+	}
+	return nil
+}
+
+// ListFlags retorna el estado efectivo de cada funcionalidad: valor global y excepciones por tienda
+func (s *FeatureFlagService) ListFlags(ctx context.Context) ([]*domain.FeatureFlag, error) {
+	adminRules, err := s.rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]*domain.FeatureFlag, 0, len(domain.Features))
+	for _, feature := range domain.Features {
+		flag := &domain.FeatureFlag{Feature: feature, Enabled: feature.EnabledByDefault(), Source: domain.FeatureFlagSourceDefault}
+		stores := make(map[string]*domain.FeatureFlagRule)
+
+		// Las reglas de admin se aplican después y prevalecen
+		for _, rules := range [][]*domain.FeatureFlagRule{s.configRules, adminRules} {
+			for _, rule := range rules {
+				if rule.Feature != feature {
+					continue
+				}
+				if rule.StoreID == "" {
+					flag.Enabled, flag.Source = rule.Enabled, rule.Source
+				} else {
+					stores[rule.StoreID] = rule
+				}
+			}
+		}
+
+		flag.Stores = make([]*domain.FeatureFlagRule, 0, len(stores))
+		for _, rule := range stores {
+			flag.Stores = append(flag.Stores, rule)
+		}
+		sort.Slice(flag.Stores, func(i, j int) bool { return flag.Stores[i].StoreID < flag.Stores[j].StoreID })
+		flags = append(flags, flag)
+	}
+
+	return flags, nil
+}
+
+// SetFlag activa o desactiva una funcionalidad en todas las tiendas (StoreID vacío) o en una tienda
+func (s *FeatureFlagService) SetFlag(ctx context.Context, rule *domain.FeatureFlagRule) (*domain.FeatureFlagRule, error) {
+	rule.StoreID = strings.TrimSpace(rule.StoreID)
+	if err := validateFeature(rule.Feature); err != nil {
+		return nil, err
+	}
+	if rule.StoreID != "" {
+		if _, err := s.storeRepo.GetByID(ctx, rule.StoreID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	rule.Source = domain.FeatureFlagSourceAdmin
+	rule.UpdatedAt = &now
+	if err := s.flagRepo.Upsert(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, s.reload(ctx)
+}
+
+// DeleteFlag elimina la regla de admin de una funcionalidad: vuelve a aplicarse FEATURE_FLAGS
+func (s *FeatureFlagService) DeleteFlag(ctx context.Context, feature domain.Feature, storeID string) error {
+	if err := validateFeature(feature); err != nil {
+		return err
+	}
+	if err := s.flagRepo.Delete(ctx, feature, strings.TrimSpace(storeID)); err != nil {
+		return err
+	}
+	return s.reload(ctx)
+}
+
+// rules retorna las reglas de admin, cargándolas de la base de datos la primera vez
+func (s *FeatureFlagService) rules(ctx context.Context) ([]*domain.FeatureFlagRule, error) {
+	s.mu.RLock()
+	if s.loaded {
+		defer s.mu.RUnlock()
+		return s.adminRules, nil
+	}
+	s.mu.RUnlock()
+
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.adminRules, nil
+}
+
+func (s *FeatureFlagService) reload(ctx context.Context) error {
+	rules, err := s.flagRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.adminRules = rules
+	s.loaded = true
+	s.mu.Unlock()
+	return nil
+}
+
+func findFeatureRule(rules []*domain.FeatureFlagRule, feature domain.Feature, storeID string) *domain.FeatureFlagRule {
+	for _, rule := range rules {
+		if rule.Feature == feature && rule.StoreID == storeID {
+			return rule
+		}
+	}
+	return nil
+}
+
+func validateFeature(feature domain.Feature) error {
+	if !feature.IsValid() {
+		names := make([]string, len(domain.Features))
+		for i, f := range domain.Features {
+			names[i] = string(f)
+		}
+		return &domain.ValidationError{
+			Field:   "feature",
+			Message: "unknown feature (options: " + strings.Join(names, ", ") + ")",
+		}
+	}
+	return nil
+}
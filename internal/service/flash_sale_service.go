@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// flashSaleModeRefresh cada cuánto se recarga la lista de productos en flash sale
+// (la pueden cambiar otras instancias)
+const flashSaleModeRefresh = 5 * time.Second
+
+// flashSaleIdleTimeout tiempo sin peticiones tras el cual termina el writer de una cola
+const flashSaleIdleTimeout = time.Minute
+
+// FlashSaleConfig configuración de las colas de reservas
+type FlashSaleConfig struct {
+	QueueSize int           // Peticiones pendientes por (producto, tienda); más allá se responde 503
+	TicketTTL time.Duration // Tiempo que se conserva un ticket resuelto para consultarlo
+}
+
+// flashSaleRequest petición de reserva encolada
+type flashSaleRequest struct {
+	ctx        context.Context
+	ticket     *domain.ReservationTicket
+	ttlMinutes int
+}
+
+// FlashSaleService encola las reservas de productos en modo alta contención.
+//
+// En un lanzamiento miles de peticiones compiten por la misma fila de stock y se
+// serializan en el lock de la base de datos. En modo flash sale cada (producto, tienda)
+// tiene una cola en memoria con un único writer que crea las reservas de una en una:
+// POST /reservations responde 202 con un ticket y el cliente consulta el resultado.
+// Las colas son por instancia; los tickets no sobreviven a un reinicio.
+type FlashSaleService struct {
+	repo               *repository.FlashSaleRepository
+	productRepo        *repository.ProductRepository
+	reservationService *ReservationService
+	cfg                FlashSaleConfig
+
+	mu      sync.Mutex
+	queues  map[string]chan *flashSaleRequest // product_id|store_id -> cola
+	tickets map[string]*domain.ReservationTicket
+	closed  bool
+	wg      sync.WaitGroup
+
+	modeMu       sync.RWMutex
+	enabled      map[string]bool
+	modeLoadedAt time.Time
+}
+
+// NewFlashSaleService crea una nueva instancia del servicio
+func NewFlashSaleService(
+	repo *repository.FlashSaleRepository,
+	productRepo *repository.ProductRepository,
+	reservationService *ReservationService,
+	cfg FlashSaleConfig,
+) *FlashSaleService {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1000
+	}
+	if cfg.TicketTTL <= 0 {
+		cfg.TicketTTL = 10 * time.Minute
+	}
+
+	return &FlashSaleService{
+		repo:               repo,
+		productRepo:        productRepo,
+		reservationService: reservationService,
+		cfg:                cfg,
+		queues:             make(map[string]chan *flashSaleRequest),
+		tickets:            make(map[string]*domain.ReservationTicket),
+	}
+}
+
+// EnableProduct activa el modo alta contención para un producto
+func (s *FlashSaleService) EnableProduct(ctx context.Context, productID string) (*domain.FlashSaleProduct, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	product := &domain.FlashSaleProduct{
+		ProductID: productID,
+		EnabledBy: domain.ActorFromContext(ctx),
+		EnabledAt: time.Now(),
+	}
+	if err := s.repo.Enable(ctx, product); err != nil {
+		return nil, err
+	}
+	s.invalidateMode()
+
+	log.Printf("⚡ Flash sale mode enabled for product %s by %s", productID, product.EnabledBy)
+
+	return product, nil
+}
+
+// DisableProduct desactiva el modo. Las peticiones ya encoladas se procesan igualmente.
+func (s *FlashSaleService) DisableProduct(ctx context.Context, productID string) error {
+	if err := s.repo.Disable(ctx, productID); err != nil {
+		return err
+	}
+	s.invalidateMode()
+
+	log.Printf("⚡ Flash sale mode disabled for product %s", productID)
+
+	return nil
+}
+
+// ListProducts obtiene los productos en modo alta contención
+func (s *FlashSaleService) ListProducts(ctx context.Context) ([]*domain.FlashSaleProduct, error) {
+	return s.repo.List(ctx)
+}
+
+// IsEnabled indica si las reservas del producto deben encolarse
+func (s *FlashSaleService) IsEnabled(ctx context.Context, productID string) (bool, error) {
+	s.modeMu.RLock()
+	fresh := time.Since(s.modeLoadedAt) < flashSaleModeRefresh
+	enabled := s.enabled[productID]
+	s.modeMu.RUnlock()
+	if fresh {
+		return enabled, nil
+	}
+
+	products, err := s.repo.List(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	s.modeMu.Lock()
+	defer s.modeMu.Unlock()
+	s.enabled = make(map[string]bool, len(products))
+	for _, product := range products {
+		s.enabled[product.ProductID] = true
+	}
+	s.modeLoadedAt = time.Now()
+
+	return s.enabled[productID], nil
+}
+
+// invalidateMode fuerza la recarga de la lista en la siguiente consulta
+func (s *FlashSaleService) invalidateMode() {
+	s.modeMu.Lock()
+	s.modeLoadedAt = time.Time{}
+	s.modeMu.Unlock()
+}
+
+// Enqueue encola una petición de reserva y retorna su ticket. Las validaciones de la
+// reserva (stock, TTL, límites del cliente) las aplica el writer al procesarla.
+func (s *FlashSaleService) Enqueue(ctx context.Context, productID, storeID, customerID string, quantity, ttlMinutes int) (*domain.ReservationTicket, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	if quantity <= 0 {
+		return nil, &domain.ValidationError{Field: "quantity", Message: "quantity must be positive"}
+	}
+	if customerID == "" {
+		return nil, &domain.ValidationError{Field: "customerID", Message: "customerID is required"}
+	}
+
+	ticket := &domain.ReservationTicket{
+		Token:      uuid.New().String(),
+		ProductID:  productID,
+		StoreID:    storeID,
+		CustomerID: customerID,
+		Quantity:   quantity,
+		Status:     domain.ReservationTicketQueued,
+		CreatedAt:  time.Now(),
+	}
+	request := &flashSaleRequest{
+		// El writer sobrevive al request HTTP pero conserva correlation ID y actor
+		ctx:        context.WithoutCancel(ctx),
+		ticket:     ticket,
+		ttlMinutes: ttlMinutes,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, &domain.QueueFullError{ProductID: productID, StoreID: storeID, Capacity: 0}
+	}
+
+	key := productID + "|" + storeID
+	queue, ok := s.queues[key]
+	if !ok {
+		queue = make(chan *flashSaleRequest, s.cfg.QueueSize)
+		s.queues[key] = queue
+		s.wg.Add(1)
+		go s.runWriter(key, queue)
+	}
+
+	ticket.Position = len(queue)
+	select {
+	case queue <- request:
+	default:
+		return nil, &domain.QueueFullError{ProductID: productID, StoreID: storeID, Capacity: s.cfg.QueueSize}
+	}
+
+	s.tickets[ticket.Token] = ticket
+	copied := *ticket
+
+	return &copied, nil
+}
+
+// GetTicket obtiene el estado de un ticket
+func (s *FlashSaleService) GetTicket(token string) (*domain.ReservationTicket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket, ok := s.tickets[token]
+	if !ok {
+		return nil, &domain.NotFoundError{Resource: "reservation ticket", ID: token}
+	}
+	copied := *ticket
+
+	return &copied, nil
+}
+
+// PurgeExpiredTickets elimina los tickets resueltos hace más de TicketTTL
+func (s *FlashSaleService) PurgeExpiredTickets(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for token, ticket := range s.tickets {
+		if ticket.CompletedAt != nil && now.Sub(*ticket.CompletedAt) > s.cfg.TicketTTL {
+			delete(s.tickets, token)
+			purged++
+		}
+	}
+
+	return purged
+}
+
+// Close deja de aceptar peticiones y espera a que los writers vacíen sus colas
+func (s *FlashSaleService) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	for key, queue := range s.queues {
+		close(queue)
+		delete(s.queues, key)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// runWriter procesa en orden las peticiones de un (producto, tienda). Termina tras
+// flashSaleIdleTimeout sin peticiones o cuando se cierra la cola.
+func (s *FlashSaleService) runWriter(key string, queue chan *flashSaleRequest) {
+	defer s.wg.Done()
+
+	idle := time.NewTimer(flashSaleIdleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case request, ok := <-queue:
+			if !ok {
+				return
+			}
+			s.process(request)
+			idle.Reset(flashSaleIdleTimeout)
+
+		case <-idle.C:
+			// Enqueue envía con s.mu bloqueado: si la cola sigue vacía aquí, nadie más la usará
+			s.mu.Lock()
+			if len(queue) == 0 && s.queues[key] == queue {
+				delete(s.queues, key)
+				s.mu.Unlock()
+				return
+			}
+			s.mu.Unlock()
+			idle.Reset(flashSaleIdleTimeout)
+		}
+	}
+}
+
+// process crea la reserva de una petición y resuelve su ticket
+func (s *FlashSaleService) process(request *flashSaleRequest) {
+	ticket := request.ticket
+
+	reservation, err := s.reservationService.CreateReservation(
+		request.ctx,
+		ticket.ProductID,
+		ticket.StoreID,
+		ticket.CustomerID,
+		ticket.Quantity,
+		request.ttlMinutes,
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	ticket.CompletedAt = &now
+	if err != nil {
+		ticket.Status = domain.ReservationTicketFailed
+		ticket.Error = err.Error()
+		ticket.ErrorCode = domain.ErrorCode(err, "INTERNAL_ERROR")
+		return
+	}
+
+	ticket.Status = domain.ReservationTicketCompleted
+	ticket.ReservationID = reservation.ID
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// FulfillmentService decide desde qué tipos de ubicación (tiendas, almacenes, dark stores) se
+// sirve cada forma de entrega según el canal de venta del request. La disponibilidad y las
+// reservas automáticas lo usan para filtrar y ordenar las tiendas candidatas.
+type FulfillmentService struct {
+	policyRepo *repository.FulfillmentPolicyRepository
+	storeRepo  *repository.StoreRepository
+}
+
+// NewFulfillmentService crea una nueva instancia del servicio
+func NewFulfillmentService(policyRepo *repository.FulfillmentPolicyRepository, storeRepo *repository.StoreRepository) *FulfillmentService {
+	return &FulfillmentService{
+		policyRepo: policyRepo,
+		storeRepo:  storeRepo,
+	}
+}
+
+// GetPolicy obtiene la política del canal o, si no tiene una propia, la política por defecto
+func (s *FulfillmentService) GetPolicy(ctx context.Context, channel domain.SalesChannel) (*domain.FulfillmentPolicy, error) {
+	if channel == "" {
+		return domain.DefaultFulfillmentPolicy(channel), nil
+	}
+
+	policy, err := s.policyRepo.GetByChannel(ctx, channel)
+	var notFound *domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return domain.DefaultFulfillmentPolicy(channel), nil
+	}
+	return policy, err
+}
+
+// ListPolicies obtiene la política vigente de cada canal (default = true si no tiene una propia)
+func (s *FulfillmentService) ListPolicies(ctx context.Context) ([]*domain.FulfillmentPolicy, error) {
+	configured, err := s.policyRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byChannel := make(map[domain.SalesChannel]*domain.FulfillmentPolicy, len(configured))
+	for _, policy := range configured {
+		byChannel[policy.Channel] = policy
+	}
+
+	channels := []domain.SalesChannel{domain.SalesChannelWeb, domain.SalesChannelStore, domain.SalesChannelMarketplace}
+	policies := make([]*domain.FulfillmentPolicy, 0, len(channels))
+	for _, channel := range channels {
+		if policy, ok := byChannel[channel]; ok {
+			policies = append(policies, policy)
+			continue
+		}
+		policies = append(policies, domain.DefaultFulfillmentPolicy(channel))
+	}
+	return policies, nil
+}
+
+// SetPolicy crea o reemplaza la política de un canal
+func (s *FulfillmentService) SetPolicy(ctx context.Context, policy *domain.FulfillmentPolicy) (*domain.FulfillmentPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	policy.Default = false
+	policy.UpdatedAt = &now
+	if err := s.policyRepo.Upsert(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// DeletePolicy elimina la política de un canal: vuelve a la política por defecto
+func (s *FulfillmentService) DeletePolicy(ctx context.Context, channel domain.SalesChannel) error {
+	return s.policyRepo.Delete(ctx, channel)
+}
+
+// Ranker obtiene el ordenador de tiendas de la forma de entrega mode para el canal del request
+// (domain.SalesChannelFromContext). Sin forma de entrega retorna nil: no se filtra ni se ordena.
+func (s *FulfillmentService) Ranker(ctx context.Context, mode domain.FulfillmentMode) (*domain.FulfillmentRanker, error) {
+	if mode == "" {
+		return nil, nil
+	}
+
+	policy, err := s.GetPolicy(ctx, domain.SalesChannelFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	locations, err := s.storeRepo.GetLocationTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewFulfillmentRanker(policy, mode, locations), nil
+}
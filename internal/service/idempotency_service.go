@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// IdempotencyService guarda las respuestas de las escrituras con Idempotency-Key para que los
+// reintentos (p. ej. la cola offline de un TPV) no se apliquen dos veces. Implementa
+// middleware.IdempotencyStore.
+type IdempotencyService struct {
+	repo *repository.IdempotencyRepository
+}
+
+// NewIdempotencyService crea una nueva instancia del servicio
+func NewIdempotencyService(repo *repository.IdempotencyRepository) *IdempotencyService {
+	return &IdempotencyService{repo: repo}
+}
+
+// Begin registra la petición como en curso o retorna el registro previo de la clave
+func (s *IdempotencyService) Begin(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	return s.repo.Begin(ctx, record)
+}
+
+// Complete guarda la respuesta de la petición
+func (s *IdempotencyService) Complete(ctx context.Context, scope, key string, status int, contentType string, body []byte) error {
+	return s.repo.Complete(ctx, scope, key, status, contentType, body)
+}
+
+// Release libera la clave para que el reintento se procese de nuevo
+func (s *IdempotencyService) Release(ctx context.Context, scope, key string) error {
+	return s.repo.Release(ctx, scope, key)
+}
+
+// PurgeExpiredKeys elimina las claves registradas hace más de IdempotencyKeyTTL
+func (s *IdempotencyService) PurgeExpiredKeys(ctx context.Context) (int64, error) {
+	return s.repo.DeleteOlderThan(ctx, time.Now().Add(-domain.IdempotencyKeyTTL))
+}
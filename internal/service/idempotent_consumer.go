@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// EventHandlerFunc aplica un evento consumido (p. ej. ajustar stock en otra tienda)
+type EventHandlerFunc func(ctx context.Context, event *domain.Event) error
+
+// IdempotentConsumer envuelve el handler de un consumidor de eventos para que cada
+// evento se aplique una sola vez aunque el broker lo entregue varias (Redis Streams,
+// Kafka y el worker de sincronización son at-least-once).
+//
+// Flujo: reclamar (consumer, event_id) → ejecutar el handler → marcar DONE. Si el handler
+// falla se libera la reclamación y la siguiente entrega lo reintenta. Si el proceso muere
+// a mitad del handler, el evento queda en PROCESSING y puede reclamarse pasado el lease;
+// los handlers deben ser transaccionales para que ese reintento sea seguro.
+type IdempotentConsumer struct {
+	repo  *repository.ProcessedEventRepository
+	name  string
+	lease time.Duration
+}
+
+// NewIdempotentConsumer crea el helper para un consumidor. El nombre identifica al
+// consumidor (p. ej. "cross-store-sync"): consumidores distintos aplican el mismo evento
+// de forma independiente.
+func NewIdempotentConsumer(repo *repository.ProcessedEventRepository, name string) *IdempotentConsumer {
+	return &IdempotentConsumer{
+		repo:  repo,
+		name:  name,
+		lease: domain.DefaultConsumerClaimLease,
+	}
+}
+
+// SetClaimLease cambia el tiempo tras el cual una reclamación abandonada puede retomarse
+func (c *IdempotentConsumer) SetClaimLease(lease time.Duration) {
+	c.lease = lease
+}
+
+// Handle aplica el evento con handler si este consumidor no lo ha aplicado ya.
+// Retorna applied=false (sin error) para duplicados, que el consumidor debe confirmar
+// (XACK / commit de offset) igual que un evento aplicado.
+func (c *IdempotentConsumer) Handle(ctx context.Context, event *domain.Event, handler EventHandlerFunc) (bool, error) {
+	if event == nil || event.ID == "" {
+		return false, &domain.ValidationError{Field: "event_id", Message: "Event ID is required for idempotent processing"}
+	}
+
+	claimed, err := c.repo.Claim(ctx, c.name, event, c.lease)
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		log.Printf("⏭️  Consumer %s skipped duplicate event %s (%s)", c.name, event.ID, event.EventType)
+		return false, nil
+	}
+
+	if err := handler(ctx, event); err != nil {
+		if releaseErr := c.repo.Release(ctx, c.name, event.ID); releaseErr != nil {
+			log.Printf("Warning: failed to release event %s for consumer %s: %v", event.ID, c.name, releaseErr)
+		}
+		return false, fmt.Errorf("consumer %s failed to handle event %s: %w", c.name, event.ID, err)
+	}
+
+	if err := c.repo.MarkDone(ctx, c.name, event.ID); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
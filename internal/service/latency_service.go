@@ -0,0 +1,144 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// latencySlots número de tramos de la ventana móvil: al rotar se descarta 1/latencySlots de la ventana
+const latencySlots = 10
+
+// routeLatencySlot latencias de una ruta en un tramo de la ventana
+type routeLatencySlot struct {
+	digest     *domain.TDigest // Milisegundos
+	errors     int
+	overBudget int
+	budget     time.Duration // Último presupuesto resuelto para la ruta
+}
+
+// latencySlot tramo de la ventana móvil
+type latencySlot struct {
+	start  time.Time
+	routes map[string]*routeLatencySlot
+}
+
+// LatencyService calcula en el proceso los percentiles de latencia por ruta de una ventana
+// móvil, sin depender de un stack de métricas externo. La ventana se divide en tramos con un
+// t-digest por ruta; el informe fusiona los tramos vigentes.
+type LatencyService struct {
+	window    time.Duration
+	slotSize  time.Duration
+	startedAt time.Time
+
+	mu    sync.Mutex
+	slots [latencySlots]latencySlot
+}
+
+// NewLatencyService crea el servicio con la ventana indicada (p. ej. 5 minutos)
+func NewLatencyService(window time.Duration) *LatencyService {
+	slotSize := window / latencySlots
+	if slotSize <= 0 {
+		slotSize = time.Millisecond
+	}
+	return &LatencyService{
+		window:    window,
+		slotSize:  slotSize,
+		startedAt: time.Now(),
+	}
+}
+
+// Record registra la duración de un request de la ruta. budget es su presupuesto de latencia
+// (0 = sin presupuesto); las respuestas 5xx cuentan como errores.
+func (s *LatencyService) Record(route string, statusCode int, duration, budget time.Duration, at time.Time) {
+	start := at.Truncate(s.slotSize)
+	index := int((start.UnixNano() / int64(s.slotSize)) % latencySlots)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slot := &s.slots[index]
+	if slot.start.After(start) {
+		return // Más antiguo que la ventana
+	}
+	if !slot.start.Equal(start) {
+		slot.start = start
+		slot.routes = make(map[string]*routeLatencySlot)
+	}
+	stats, ok := slot.routes[route]
+	if !ok {
+		stats = &routeLatencySlot{digest: domain.NewTDigest(domain.DefaultTDigestCompression)}
+		slot.routes[route] = stats
+	}
+
+	stats.digest.Add(float64(duration) / float64(time.Millisecond))
+	stats.budget = budget
+	if statusCode >= 500 {
+		stats.errors++
+	}
+	if budget > 0 && duration > budget {
+		stats.overBudget++
+	}
+}
+
+// Report calcula p50/p95/p99 por ruta con los tramos de la ventana vigentes en now
+func (s *LatencyService) Report(now time.Time) *domain.LatencyReport {
+	since := now.Add(-s.window)
+	if since.Before(s.startedAt) {
+		since = s.startedAt
+	}
+
+	merged := make(map[string]*routeLatencySlot)
+	s.mu.Lock()
+	for i := range s.slots {
+		slot := &s.slots[i]
+		if slot.routes == nil || !slot.start.Add(s.slotSize).After(now.Add(-s.window)) || slot.start.After(now) {
+			continue
+		}
+		for route, stats := range slot.routes {
+			total, ok := merged[route]
+			if !ok {
+				total = &routeLatencySlot{digest: domain.NewTDigest(domain.DefaultTDigestCompression)}
+				merged[route] = total
+			}
+			total.digest.Merge(stats.digest)
+			total.errors += stats.errors
+			total.overBudget += stats.overBudget
+			total.budget = stats.budget
+		}
+	}
+	s.mu.Unlock()
+
+	report := &domain.LatencyReport{
+		WindowSeconds: s.window.Seconds(),
+		Since:         since,
+		GeneratedAt:   now,
+		Routes:        make([]*domain.RouteLatency, 0, len(merged)),
+	}
+	for route, stats := range merged {
+		count := stats.digest.Count()
+		report.Routes = append(report.Routes, &domain.RouteLatency{
+			Route:           route,
+			Count:           count,
+			Errors:          stats.errors,
+			P50Ms:           stats.digest.Quantile(0.50),
+			P95Ms:           stats.digest.Quantile(0.95),
+			P99Ms:           stats.digest.Quantile(0.99),
+			MaxMs:           stats.digest.Max(),
+			BudgetMs:        float64(stats.budget) / float64(time.Millisecond),
+			OverBudget:      stats.overBudget,
+			OverBudgetRatio: float64(stats.overBudget) / float64(count),
+		})
+	}
+	sort.Slice(report.Routes, func(i, j int) bool {
+		if report.Routes[i].P99Ms != report.Routes[j].P99Ms {
+			return report.Routes[i].P99Ms > report.Routes[j].P99Ms
+		}
+		return report.Routes[i].Route < report.Routes[j].Route
+	})
+	report.Count = len(report.Routes)
+
+	return report
+}
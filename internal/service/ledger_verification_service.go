@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// LedgerVerificationService compara la quantity de cada fila de stock con la que resulta de
+// reproducir sus movimientos, para detectar pronto escrituras que no pasaron por la API
+// (o que no registraron su evento) antes de que se confundan con ventas o roturas.
+type LedgerVerificationService struct {
+	ledgerRepo *repository.StockLedgerRepository
+
+	mu   sync.Mutex
+	last *domain.LedgerVerification
+}
+
+// NewLedgerVerificationService crea una nueva instancia del servicio
+func NewLedgerVerificationService(ledgerRepo *repository.StockLedgerRepository) *LedgerVerificationService {
+	return &LedgerVerificationService{ledgerRepo: ledgerRepo}
+}
+
+// Verify reproduce el ledger de todas las filas y guarda el resultado como última verificación.
+// El evento se guarda justo después de actualizar la fila, así que una diferencia puede ser un
+// movimiento en curso: cada fila que no cuadra se vuelve a comprobar antes de reportarla.
+func (s *LedgerVerificationService) Verify(ctx context.Context) (*domain.LedgerVerification, error) {
+	started := time.Now()
+
+	rows, err := s.ledgerRepo.Replay(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.LedgerVerification{
+		Checked:    len(rows),
+		Mismatches: []*domain.LedgerMismatch{},
+		StartedAt:  started,
+	}
+	for _, row := range rows {
+		if row.BaselineSeq == 0 {
+			result.Unverified++
+			continue
+		}
+		result.Verified++
+		if row.Quantity == row.Expected {
+			continue
+		}
+
+		row, err = s.ledgerRepo.ReplayRow(ctx, row.ProductID, row.StoreID)
+		var notFound *domain.NotFoundError
+		if errors.As(err, &notFound) {
+			continue // Fila borrada durante la verificación
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row.BaselineSeq == 0 || row.Quantity == row.Expected {
+			continue
+		}
+
+		result.Mismatches = append(result.Mismatches, &domain.LedgerMismatch{
+			ProductID:    row.ProductID,
+			StoreID:      row.StoreID,
+			Quantity:     row.Quantity,
+			Expected:     row.Expected,
+			Difference:   row.Quantity - row.Expected,
+			BaselineType: row.BaselineType,
+			BaselineSeq:  row.BaselineSeq,
+		})
+		log.Printf("⚠️  Stock ledger mismatch for %s/%s: quantity %d, movements since %s #%d add up to %d",
+			row.ProductID, row.StoreID, row.Quantity, row.BaselineType, row.BaselineSeq, row.Expected)
+	}
+	result.Duration = time.Since(started).Round(time.Millisecond).String()
+
+	s.mu.Lock()
+	s.last = result
+	s.mu.Unlock()
+
+	return result, nil
+}
+
+// Last retorna la última verificación desde que arrancó el proceso (nil si no se ha ejecutado)
+func (s *LedgerVerificationService) Last() *domain.LedgerVerification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
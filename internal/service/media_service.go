@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// MediaService gestiona las imágenes de los productos: el contenido se guarda en el
+// BlobStore configurado (disco local o S3) y los metadatos en product_media.
+type MediaService struct {
+	mediaRepo   *repository.MediaRepository
+	productRepo *repository.ProductRepository
+	store       domain.BlobStore
+	maxBytes    int64
+}
+
+// NewMediaService crea una nueva instancia del servicio. maxBytes <= 0 desactiva el límite.
+func NewMediaService(mediaRepo *repository.MediaRepository, productRepo *repository.ProductRepository, store domain.BlobStore, maxBytes int64) *MediaService {
+	return &MediaService{
+		mediaRepo:   mediaRepo,
+		productRepo: productRepo,
+		store:       store,
+		maxBytes:    maxBytes,
+	}
+}
+
+// MaxUploadBytes tamaño máximo de una imagen
+func (s *MediaService) MaxUploadBytes() int64 {
+	return s.maxBytes
+}
+
+// Upload guarda una imagen y la enlaza al producto. Sin posición se añade al final;
+// con posición se inserta en ese lugar y se desplazan las siguientes.
+func (s *MediaService) Upload(ctx context.Context, productID string, upload *domain.MediaUpload) (*domain.ProductMedia, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	if err := upload.Validate(s.maxBytes); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.mediaRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	media := &domain.ProductMedia{
+		ID:          uuid.New().String(),
+		ProductID:   productID,
+		ContentType: upload.ContentType,
+		SizeBytes:   upload.Size,
+		AltText:     upload.AltText,
+		Position:    len(existing),
+		CreatedAt:   time.Now(),
+	}
+	media.StorageKey = fmt.Sprintf("products/%s/%s%s", productID, media.ID, domain.MediaContentTypes[upload.ContentType])
+
+	content := upload.Content
+	if s.maxBytes > 0 {
+		content = io.LimitReader(content, s.maxBytes)
+	}
+	media.URL, err = s.store.Put(ctx, media.StorageKey, media.ContentType, content, upload.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mediaRepo.Create(ctx, media); err != nil {
+		s.deleteBlob(ctx, media.StorageKey)
+		return nil, err
+	}
+
+	if upload.Position != nil && *upload.Position < media.Position {
+		if err := s.move(ctx, append(existing, media), media.ID, *upload.Position); err != nil {
+			return nil, err
+		}
+		media.Position = *upload.Position
+	}
+
+	return media, nil
+}
+
+// ListMedia lista las imágenes de un producto en orden de presentación
+func (s *MediaService) ListMedia(ctx context.Context, productID string) ([]*domain.ProductMedia, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.mediaRepo.ListByProduct(ctx, productID)
+}
+
+// UpdateMedia cambia el texto alternativo y/o la posición de una imagen
+func (s *MediaService) UpdateMedia(ctx context.Context, productID, mediaID string, patch domain.ProductMediaPatch) (*domain.ProductMedia, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.mediaRepo.GetByID(ctx, productID, mediaID); err != nil {
+		return nil, err
+	}
+
+	if patch.AltText != nil {
+		if err := s.mediaRepo.UpdateAltText(ctx, productID, mediaID, *patch.AltText); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.Position != nil {
+		media, err := s.mediaRepo.ListByProduct(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.move(ctx, media, mediaID, *patch.Position); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.mediaRepo.GetByID(ctx, productID, mediaID)
+}
+
+// DeleteMedia elimina una imagen y compacta las posiciones de las restantes
+func (s *MediaService) DeleteMedia(ctx context.Context, productID, mediaID string) error {
+	media, err := s.mediaRepo.GetByID(ctx, productID, mediaID)
+	if err != nil {
+		return err
+	}
+	if err := s.mediaRepo.Delete(ctx, productID, mediaID); err != nil {
+		return err
+	}
+	s.deleteBlob(ctx, media.StorageKey)
+
+	remaining, err := s.mediaRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	return s.mediaRepo.SetPositions(ctx, productID, mediaIDs(remaining))
+}
+
+// AttachMedia rellena Product.Media en los productos indicados con una sola consulta
+func (s *MediaService) AttachMedia(ctx context.Context, products ...*domain.Product) error {
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		if product != nil {
+			ids = append(ids, product.ID)
+		}
+	}
+
+	media, err := s.mediaRepo.ListByProducts(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byProduct := make(map[string][]*domain.ProductMedia, len(ids))
+	for _, item := range media {
+		byProduct[item.ProductID] = append(byProduct[item.ProductID], item)
+	}
+	for _, product := range products {
+		if product != nil {
+			product.Media = byProduct[product.ID]
+		}
+	}
+	return nil
+}
+
+// DeleteBlobs elimina del blob store el contenido de imágenes cuyos registros ya se borraron
+// (p. ej. al eliminar el producto)
+func (s *MediaService) DeleteBlobs(ctx context.Context, media []*domain.ProductMedia) {
+	for _, item := range media {
+		s.deleteBlob(ctx, item.StorageKey)
+	}
+}
+
+// move coloca mediaID en position dentro de media (ordenado) y renumera
+func (s *MediaService) move(ctx context.Context, media []*domain.ProductMedia, mediaID string, position int) error {
+	ids := make([]string, 0, len(media))
+	for _, item := range media {
+		if item.ID != mediaID {
+			ids = append(ids, item.ID)
+		}
+	}
+	if position > len(ids) {
+		position = len(ids)
+	}
+
+	ids = append(ids[:position], append([]string{mediaID}, ids[position:]...)...)
+	return s.mediaRepo.SetPositions(ctx, media[0].ProductID, ids)
+}
+
+// deleteBlob borra el contenido; un fallo solo deja un fichero huérfano, así que se registra
+func (s *MediaService) deleteBlob(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		log.Printf("Warning: failed to delete media blob %s: %v", key, err)
+	}
+}
+
+func mediaIDs(media []*domain.ProductMedia) []string {
+	ids := make([]string, len(media))
+	for i, item := range media {
+		ids[i] = item.ID
+	}
+	return ids
+}
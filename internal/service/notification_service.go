@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// NotificationService avisa a los clientes de sus reservas (creada, a punto de caducar, caducada,
+// confirmada) por los canales que configura cada tienda. Los avisos de eventos llegan desde el
+// publisher (NotificationPublisher) y el de vencimiento próximo lo genera un worker.
+//
+// Cada aviso se reclama en reservation_notifications antes de enviarse, así que los eventos
+// repetidos (re-intentos del outbox, varias instancias) no duplican avisos; a cambio, un envío
+// fallido no se reintenta y queda registrado como FAILED.
+type NotificationService struct {
+	repo            *repository.NotificationRepository
+	reservationRepo *repository.ReservationRepository
+	productRepo     *repository.ProductRepository
+	storeRepo       *repository.StoreRepository
+	hoursRepo       *repository.StoreHoursRepository // Opcional: fechas en la zona horaria de la tienda
+	notifiers       map[domain.NotificationChannel]domain.Notifier
+}
+
+// NewNotificationService crea una nueva instancia del servicio
+func NewNotificationService(
+	repo *repository.NotificationRepository,
+	reservationRepo *repository.ReservationRepository,
+	productRepo *repository.ProductRepository,
+	storeRepo *repository.StoreRepository,
+) *NotificationService {
+	return &NotificationService{
+		repo:            repo,
+		reservationRepo: reservationRepo,
+		productRepo:     productRepo,
+		storeRepo:       storeRepo,
+		notifiers:       make(map[domain.NotificationChannel]domain.Notifier),
+	}
+}
+
+// RegisterNotifier añade el adaptador de un canal (reemplaza al anterior del mismo canal).
+// Los canales sin notifier se registran como SKIPPED.
+func (s *NotificationService) RegisterNotifier(notifier domain.Notifier) {
+	s.notifiers[notifier.Channel()] = notifier
+}
+
+// SetStoreHoursRepository muestra las fechas de los avisos en la zona horaria de cada tienda
+func (s *NotificationService) SetStoreHoursRepository(hoursRepo *repository.StoreHoursRepository) {
+	s.hoursRepo = hoursRepo
+}
+
+// GetSettings obtiene la configuración de avisos de una tienda
+func (s *NotificationService) GetSettings(ctx context.Context, storeID string) (*domain.StoreNotificationSettings, error) {
+	if _, err := s.storeRepo.GetByID(ctx, storeID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSettings(ctx, storeID)
+}
+
+// SetSettings crea o reemplaza la configuración de avisos de una tienda
+func (s *NotificationService) SetSettings(ctx context.Context, settings *domain.StoreNotificationSettings) (*domain.StoreNotificationSettings, error) {
+	if _, err := s.storeRepo.GetByID(ctx, settings.StoreID); err != nil {
+		return nil, err
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	settings.UpdatedAt = time.Now()
+	if err := s.repo.UpsertSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// DeleteSettings elimina la configuración: la tienda deja de enviar avisos
+func (s *NotificationService) DeleteSettings(ctx context.Context, storeID string) error {
+	return s.repo.DeleteSettings(ctx, storeID)
+}
+
+// GetContact obtiene el contacto de un cliente
+func (s *NotificationService) GetContact(ctx context.Context, customerID string) (*domain.CustomerContact, error) {
+	return s.repo.GetContact(ctx, customerID)
+}
+
+// SetContact crea o reemplaza el contacto de un cliente
+func (s *NotificationService) SetContact(ctx context.Context, contact *domain.CustomerContact) (*domain.CustomerContact, error) {
+	if err := contact.Validate(); err != nil {
+		return nil, err
+	}
+
+	contact.UpdatedAt = time.Now()
+	if err := s.repo.UpsertContact(ctx, contact); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// DeleteContact elimina el contacto de un cliente: no recibirá más avisos
+func (s *NotificationService) DeleteContact(ctx context.Context, customerID string) error {
+	return s.repo.DeleteContact(ctx, customerID)
+}
+
+// ListDeliveries obtiene los avisos enviados (o intentados) de una reserva
+func (s *NotificationService) ListDeliveries(ctx context.Context, reservationID string) ([]*domain.NotificationDelivery, error) {
+	if _, err := s.reservationRepo.GetByID(ctx, reservationID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, reservationID)
+}
+
+// HandleEvent avisa al cliente de los eventos de reserva que tienen aviso (ignora el resto)
+func (s *NotificationService) HandleEvent(ctx context.Context, event *domain.Event) error {
+	kind, ok := domain.NotificationKindForEvent(event.EventType)
+	if !ok {
+		return nil
+	}
+
+	var payload struct {
+		ReservationID string `json:"reservation_id"`
+		StoreID       string `json:"store_id"`
+	}
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil || payload.ReservationID == "" {
+		return nil
+	}
+
+	// La configuración va primero: la mayoría de las tiendas no tendrán avisos
+	settings, err := s.repo.GetSettings(ctx, payload.StoreID)
+	var notFound *domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !settings.Notifies(kind) {
+		return nil
+	}
+
+	reservation, err := s.reservationRepo.GetByID(ctx, payload.ReservationID)
+	if errors.As(err, &notFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if reservation.Status != domain.NotificationKindStatus(kind) {
+		return nil
+	}
+	return s.notify(ctx, reservation, kind, settings, time.Now())
+}
+
+// NotifyExpiring avisa de las reservas pendientes que caducan dentro de la antelación
+// configurada por su tienda. Retorna cuántas reservas se avisaron.
+func (s *NotificationService) NotifyExpiring(ctx context.Context, now time.Time) (int, error) {
+	settings, err := s.repo.ListEnabledSettings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	notified := 0
+	for _, store := range settings {
+		if !store.Notifies(domain.NotificationReservationExpiring) {
+			continue
+		}
+		until := now.Add(time.Duration(store.ExpiringBeforeMinutes) * time.Minute)
+		reservations, err := s.reservationRepo.GetPendingExpiringUnnotified(ctx, store.StoreID, now, until)
+		if err != nil {
+			return notified, err
+		}
+		for _, reservation := range reservations {
+			if err := s.notify(ctx, reservation, domain.NotificationReservationExpiring, store, now); err != nil {
+				return notified, err
+			}
+			notified++
+		}
+	}
+	return notified, nil
+}
+
+// notify envía el aviso kind de la reserva por cada canal de la tienda. Los fallos de envío
+// se registran en el aviso; solo se retornan los errores de la BD.
+func (s *NotificationService) notify(ctx context.Context, reservation *domain.Reservation, kind domain.NotificationKind, settings *domain.StoreNotificationSettings, now time.Time) error {
+	if !settings.Notifies(kind) {
+		return nil
+	}
+
+	var contact *domain.CustomerContact
+	if reservation.CustomerID != "" {
+		found, err := s.repo.GetContact(ctx, reservation.CustomerID)
+		var notFound *domain.NotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			return err
+		}
+		contact = found
+	}
+
+	var data *domain.NotificationData
+	for _, channel := range settings.Channels {
+		delivery := &domain.NotificationDelivery{
+			ID:            uuid.New().String(),
+			ReservationID: reservation.ID,
+			StoreID:       reservation.StoreID,
+			Kind:          kind,
+			Channel:       channel,
+			Status:        domain.NotificationStatusPending,
+			CreatedAt:     now,
+		}
+		if contact != nil {
+			delivery.Recipient = contact.Address(channel)
+		}
+		claimed, err := s.repo.ClaimDelivery(ctx, delivery)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		notifier := s.notifiers[channel]
+		switch {
+		case delivery.Recipient == "":
+			delivery.Status = domain.NotificationStatusSkipped
+			delivery.Error = fmt.Sprintf("customer has no %s contact", channel)
+		case notifier == nil:
+			delivery.Status = domain.NotificationStatusSkipped
+			delivery.Error = fmt.Sprintf("no notifier configured for %s", channel)
+		default:
+			if data == nil {
+				if data, err = s.notificationData(ctx, reservation, kind, now); err != nil {
+					return err
+				}
+			}
+			s.send(ctx, notifier, delivery, settings, data)
+		}
+
+		if err := s.repo.FinishDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send renderiza y envía un aviso y anota el resultado en delivery
+func (s *NotificationService) send(ctx context.Context, notifier domain.Notifier, delivery *domain.NotificationDelivery, settings *domain.StoreNotificationSettings, data *domain.NotificationData) {
+	subject, body, err := settings.Template(delivery.Kind).Render(data)
+	if err == nil {
+		notification := &domain.Notification{
+			ID:            delivery.ID,
+			Kind:          delivery.Kind,
+			Channel:       delivery.Channel,
+			ReservationID: delivery.ReservationID,
+			StoreID:       delivery.StoreID,
+			To:            delivery.Recipient,
+			Subject:       subject,
+			Body:          body,
+		}
+		if delivery.Channel == domain.NotificationChannelEmail {
+			notification.From = settings.EmailFrom
+		}
+		err = notifier.Send(ctx, notification)
+	}
+
+	if err != nil {
+		log.Printf("⚠️  Failed to send %s notification %s for reservation %s: %v", delivery.Channel, delivery.Kind, delivery.ReservationID, err)
+		delivery.Status = domain.NotificationStatusFailed
+		delivery.Error = err.Error()
+		return
+	}
+	sentAt := time.Now()
+	delivery.Status = domain.NotificationStatusSent
+	delivery.SentAt = &sentAt
+}
+
+// notificationData reúne los datos de las plantillas: nombre del producto y de la tienda y
+// vencimiento en la zona horaria de la tienda
+func (s *NotificationService) notificationData(ctx context.Context, reservation *domain.Reservation, kind domain.NotificationKind, now time.Time) (*domain.NotificationData, error) {
+	product, err := s.productRepo.GetByID(ctx, reservation.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	store, err := s.storeRepo.GetByID(ctx, reservation.StoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	location := time.UTC
+	if s.hoursRepo != nil {
+		hours, err := s.hoursRepo.GetByStore(ctx, reservation.StoreID)
+		var notFound *domain.NotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			return nil, err
+		}
+		if hours != nil {
+			if loc, err := time.LoadLocation(hours.Timezone); err == nil {
+				location = loc
+			}
+		}
+	}
+
+	minutesLeft := 0
+	if remaining := reservation.ExpiresAt.Sub(now); remaining > 0 {
+		minutesLeft = int(remaining.Round(time.Minute) / time.Minute)
+	}
+
+	return &domain.NotificationData{
+		Kind:          kind,
+		ReservationID: reservation.ID,
+		CustomerID:    reservation.CustomerID,
+		ProductID:     product.ID,
+		ProductName:   product.Name,
+		SKU:           product.SKU,
+		StoreID:       store.ID,
+		StoreName:     store.Name,
+		Quantity:      reservation.Quantity,
+		ExpiresAt:     reservation.ExpiresAt.In(location),
+		MinutesLeft:   minutesLeft,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// OversellService gestiona las tolerancias de sobreventa. El suelo se aplica en el
+// repositorio de stock (reservar/confirmar) y en StockService (ajustes).
+type OversellService struct {
+	oversellRepo *repository.OversellRepository
+	storeRepo    *repository.StoreRepository
+	productRepo  *repository.ProductRepository
+}
+
+// NewOversellService crea una nueva instancia del servicio
+func NewOversellService(oversellRepo *repository.OversellRepository, storeRepo *repository.StoreRepository, productRepo *repository.ProductRepository) *OversellService {
+	return &OversellService{
+		oversellRepo: oversellRepo,
+		storeRepo:    storeRepo,
+		productRepo:  productRepo,
+	}
+}
+
+// ListTolerances lista las tolerancias configuradas (scope vacío = todas)
+func (s *OversellService) ListTolerances(ctx context.Context, scope domain.OversellScope) ([]*domain.OversellTolerance, error) {
+	if scope != "" && !scope.IsValid() {
+		return nil, &domain.ValidationError{Field: "scope", Message: "scope must be STORE or PRODUCT"}
+	}
+	return s.oversellRepo.List(ctx, scope)
+}
+
+// SetTolerance crea o reemplaza la tolerancia de una tienda o de un producto
+func (s *OversellService) SetTolerance(ctx context.Context, tolerance *domain.OversellTolerance) (*domain.OversellTolerance, error) {
+	if err := tolerance.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch tolerance.Scope {
+	case domain.OversellScopeStore:
+		if _, err := s.storeRepo.GetByID(ctx, tolerance.ScopeID); err != nil {
+			return nil, err
+		}
+	case domain.OversellScopeProduct:
+		if _, err := s.productRepo.GetByID(ctx, tolerance.ScopeID); err != nil {
+			return nil, err
+		}
+	}
+
+	tolerance.UpdatedAt = time.Now()
+	if err := s.oversellRepo.Upsert(ctx, tolerance); err != nil {
+		return nil, err
+	}
+	return tolerance, nil
+}
+
+// DeleteTolerance elimina una tolerancia. El stock ya sobrevendido se conserva; las
+// operaciones siguientes vuelven a exigir disponibilidad no negativa.
+func (s *OversellService) DeleteTolerance(ctx context.Context, scope domain.OversellScope, scopeID string) error {
+	return s.oversellRepo.Delete(ctx, scope, scopeID)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// priceBatchSize limita cuántos cambios programados se aplican por pasada del worker
+const priceBatchSize = 100
+
+// PriceService gestiona el historial de precios y los cambios de precio programados.
+// Los cambios manuales se registran en ProductRepository.Update.
+type PriceService struct {
+	priceRepo   *repository.PriceRepository
+	productRepo *repository.ProductRepository
+}
+
+// NewPriceService crea una nueva instancia del servicio
+func NewPriceService(priceRepo *repository.PriceRepository, productRepo *repository.ProductRepository) *PriceService {
+	return &PriceService{
+		priceRepo:   priceRepo,
+		productRepo: productRepo,
+	}
+}
+
+// GetPriceHistory obtiene el historial de precios de un producto con el total para paginar
+func (s *PriceService) GetPriceHistory(ctx context.Context, productID string, limit, offset int) ([]*domain.PriceChange, int, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, 0, err
+	}
+
+	if limit <= 0 {
+		limit = domain.DefaultPriceHistoryLimit
+	}
+	if limit > domain.MaxPriceHistoryLimit {
+		return nil, 0, &domain.ValidationError{
+			Field:   "limit",
+			Message: fmt.Sprintf("limit cannot exceed %d", domain.MaxPriceHistoryLimit),
+		}
+	}
+	if offset < 0 {
+		return nil, 0, &domain.ValidationError{Field: "offset", Message: "offset cannot be negative"}
+	}
+
+	history, err := s.priceRepo.ListHistory(ctx, productID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.priceRepo.CountHistory(ctx, productID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return history, total, nil
+}
+
+// SchedulePriceChange programa un cambio de precio para effectiveAt. El autor se toma del context.
+func (s *PriceService) SchedulePriceChange(ctx context.Context, productID string, newPrice float64, effectiveAt time.Time) (*domain.ScheduledPriceChange, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	schedule := &domain.ScheduledPriceChange{
+		ID:          uuid.New().String(),
+		ProductID:   productID,
+		NewPrice:    newPrice,
+		Actor:       domain.ActorFromContext(ctx),
+		Status:      domain.ScheduledPricePending,
+		EffectiveAt: effectiveAt,
+		CreatedAt:   now,
+	}
+	if err := schedule.Validate(now); err != nil {
+		return nil, err
+	}
+
+	if err := s.priceRepo.CreateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// ListScheduledPriceChanges lista los cambios programados de un producto (todos los estados)
+func (s *PriceService) ListScheduledPriceChanges(ctx context.Context, productID string) ([]*domain.ScheduledPriceChange, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	return s.priceRepo.ListSchedules(ctx, productID)
+}
+
+// CancelScheduledPriceChange anula un cambio programado pendiente del producto
+func (s *PriceService) CancelScheduledPriceChange(ctx context.Context, productID, scheduleID string) (*domain.ScheduledPriceChange, error) {
+	schedule, err := s.priceRepo.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.ProductID != productID {
+		return nil, &domain.NotFoundError{Resource: "ScheduledPriceChange", ID: scheduleID}
+	}
+
+	if err := s.priceRepo.Cancel(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+
+	schedule.Status = domain.ScheduledPriceCancelled
+	return schedule, nil
+}
+
+// ProcessScheduledPriceChanges aplica los cambios cuyo effective_at ya llegó (usado por el worker periódico).
+// Retorna el número de cambios aplicados.
+func (s *PriceService) ProcessScheduledPriceChanges(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := s.priceRepo.ListDue(ctx, now, priceBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, schedule := range due {
+		change, err := s.priceRepo.Apply(ctx, schedule, now)
+		if err != nil {
+			log.Printf("Warning: failed to apply scheduled price change %s for product %s: %v", schedule.ID, schedule.ProductID, err)
+			continue
+		}
+		if change == nil {
+			continue
+		}
+
+		applied++
+		log.Printf("💲 Price of product %s changed %.2f → %.2f (scheduled by %s)",
+			change.ProductID, change.OldPrice, change.NewPrice, change.Actor)
+	}
+
+	return applied, nil
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 
 	"inventory-system/internal/domain"
 	"inventory-system/internal/repository"
@@ -14,6 +17,10 @@ import (
 type ProductService struct {
 	productRepo *repository.ProductRepository
 	eventRepo   *repository.EventRepository
+	publisher   domain.EventPublisher
+	skuPolicy   domain.SKUPolicy
+	media       *MediaService
+	searchIndex domain.SearchIndex
 }
 
 // NewProductService crea una nueva instancia del servicio
@@ -24,9 +31,37 @@ func NewProductService(
 	return &ProductService{
 		productRepo: productRepo,
 		eventRepo:   eventRepo,
+		skuPolicy:   domain.DefaultSKUPolicy(),
 	}
 }
 
+// SetSKUPolicy configura la normalización y validación de SKUs (por defecto DefaultSKUPolicy)
+func (s *ProductService) SetSKUPolicy(policy domain.SKUPolicy) {
+	s.skuPolicy = policy
+}
+
+// SetPublisher configura el publisher para los eventos de catálogo (por defecto solo se persisten)
+func (s *ProductService) SetPublisher(publisher domain.EventPublisher) {
+	s.publisher = publisher
+}
+
+// SetMediaService habilita las imágenes: se incluyen en las lecturas y su contenido se
+// borra del blob store al eliminar el producto
+func (s *ProductService) SetMediaService(media *MediaService) {
+	s.media = media
+}
+
+// SetSearchIndex hace que SearchProducts consulte el índice externo (SEARCH_BACKEND) en
+// lugar de la BD. El índice lo mantiene SearchIndexer con los eventos.
+func (s *ProductService) SetSearchIndex(index domain.SearchIndex) {
+	s.searchIndex = index
+}
+
+// NormalizeSKU aplica la política de SKUs; la usan también las importaciones
+func (s *ProductService) NormalizeSKU(sku string) (string, error) {
+	return s.skuPolicy.Normalize(sku)
+}
+
 // CreateProduct crea un nuevo producto
 func (s *ProductService) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
 	// Generar ID si no existe
@@ -34,12 +69,20 @@ func (s *ProductService) CreateProduct(ctx context.Context, product *domain.Prod
 		product.ID = uuid.New().String()
 	}
 
-	// Validar producto
+	// Normalizar SKU y validar producto
+	sku, err := s.skuPolicy.Normalize(product.SKU)
+	if err != nil {
+		return nil, err
+	}
+	product.SKU = sku
+	if product.Status == "" {
+		product.Status = domain.ProductStatusActive
+	}
 	if err := product.Validate(); err != nil {
 		return nil, err
 	}
 
-	// Verificar que no exista un producto con el mismo SKU
+	// Verificar que no exista un producto con el mismo SKU (sin distinguir mayúsculas)
 	existing, err := s.productRepo.GetBySKU(ctx, product.SKU)
 	if err == nil && existing != nil {
 		return nil, &domain.ConflictError{
@@ -53,21 +96,32 @@ func (s *ProductService) CreateProduct(ctx context.Context, product *domain.Prod
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
 
+	s.publishEvent(ctx, domain.NewProductEvent(domain.EventProductCreated, product))
+
 	return product, nil
 }
 
 // GetProduct obtiene un producto por ID
 func (s *ProductService) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
-	return s.productRepo.GetByID(ctx, id)
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return product, s.attachMedia(ctx, product)
 }
 
 // GetProductBySKU obtiene un producto por SKU
 func (s *ProductService) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
-	return s.productRepo.GetBySKU(ctx, sku)
+	product, err := s.productRepo.GetBySKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+	return product, s.attachMedia(ctx, product)
 }
 
-// ListProducts lista todos los productos con paginación
-func (s *ProductService) ListProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
+// ListProducts lista los productos con paginación. Los descatalogados se ocultan
+// salvo que includeDiscontinued sea true.
+func (s *ProductService) ListProducts(ctx context.Context, limit, offset int, includeDiscontinued bool) ([]*domain.Product, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -75,11 +129,15 @@ func (s *ProductService) ListProducts(ctx context.Context, limit, offset int) ([
 		offset = 0
 	}
 
-	return s.productRepo.List(ctx, limit, offset)
+	products, err := s.productRepo.List(ctx, limit, offset, includeDiscontinued)
+	if err != nil {
+		return nil, err
+	}
+	return products, s.attachMedia(ctx, products...)
 }
 
-// ListProductsByCategory lista productos de una categoría
-func (s *ProductService) ListProductsByCategory(ctx context.Context, category string, limit, offset int) ([]*domain.Product, error) {
+// ListProductsByCategory lista productos de una categoría (mismo criterio que ListProducts)
+func (s *ProductService) ListProductsByCategory(ctx context.Context, category string, limit, offset int, includeDiscontinued bool) ([]*domain.Product, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -87,12 +145,46 @@ func (s *ProductService) ListProductsByCategory(ctx context.Context, category st
 		offset = 0
 	}
 
-	return s.productRepo.ListByCategory(ctx, category, limit, offset)
+	products, err := s.productRepo.ListByCategory(ctx, category, limit, offset, includeDiscontinued)
+	if err != nil {
+		return nil, err
+	}
+	return products, s.attachMedia(ctx, products...)
 }
 
-// UpdateProduct actualiza un producto
+// ListProductsByABCClass lista productos de una clase ABC, opcionalmente de una categoría
+// (mismo criterio que ListProducts)
+func (s *ProductService) ListProductsByABCClass(ctx context.Context, class domain.ABCClass, category string, limit, offset int, includeDiscontinued bool) ([]*domain.Product, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	products, err := s.productRepo.ListByABCClass(ctx, class, category, limit, offset, includeDiscontinued)
+	if err != nil {
+		return nil, err
+	}
+	return products, s.attachMedia(ctx, products...)
+}
+
+// attachMedia incluye las imágenes en los productos si hay MediaService configurado
+func (s *ProductService) attachMedia(ctx context.Context, products ...*domain.Product) error {
+	if s.media == nil {
+		return nil
+	}
+	return s.media.AttachMedia(ctx, products...)
+}
+
+// UpdateProduct actualiza un producto. El estado no se modifica aquí (ver ChangeStatus).
 func (s *ProductService) UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	// Validar producto
+	// Normalizar SKU y validar producto
+	sku, err := s.skuPolicy.Normalize(product.SKU)
+	if err != nil {
+		return nil, err
+	}
+	product.SKU = sku
 	if err := product.Validate(); err != nil {
 		return nil, err
 	}
@@ -103,7 +195,9 @@ func (s *ProductService) UpdateProduct(ctx context.Context, product *domain.Prod
 		return nil, err
 	}
 
-	// Si cambia el SKU, verificar que no exista otro producto con ese SKU
+	product.Status = existing.Status
+
+	// Si cambia el SKU, verificar que no exista otro producto con ese SKU (sin distinguir mayúsculas)
 	if existing.SKU != product.SKU {
 		other, err := s.productRepo.GetBySKU(ctx, product.SKU)
 		if err == nil && other != nil && other.ID != product.ID {
@@ -119,33 +213,225 @@ func (s *ProductService) UpdateProduct(ctx context.Context, product *domain.Prod
 		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
 
-	return s.productRepo.GetByID(ctx, product.ID)
+	updated, err := s.productRepo.GetByID(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishEvent(ctx, domain.NewProductEvent(domain.EventProductUpdated, updated))
+
+	return updated, nil
+}
+
+// UpsertProductBySKU crea el producto si el SKU no existe o lo actualiza si ya existe,
+// de modo que una sincronización de catálogo no necesite consultar cada SKU antes.
+// El SKU de la ruta manda: si el body trae otro distinto se rechaza. created indica
+// si el producto se creó. El estado de un producto existente no se modifica.
+func (s *ProductService) UpsertProductBySKU(ctx context.Context, sku string, product *domain.Product) (*domain.Product, bool, error) {
+	normalized, err := s.skuPolicy.Normalize(sku)
+	if err != nil {
+		return nil, false, err
+	}
+	if product.SKU != "" {
+		bodySKU, err := s.skuPolicy.Normalize(product.SKU)
+		if err != nil {
+			return nil, false, err
+		}
+		if bodySKU != normalized {
+			return nil, false, &domain.ValidationError{
+				Field:   "sku",
+				Message: fmt.Sprintf("body SKU %s does not match path SKU %s", bodySKU, normalized),
+			}
+		}
+	}
+	product.SKU = normalized
+
+	// Dos sincronizaciones concurrentes pueden intentar crear el mismo SKU: la que
+	// pierde la carrera recibe ConflictError y reintenta como actualización
+	for attempt := 0; attempt < 2; attempt++ {
+		existing, err := s.productRepo.GetBySKU(ctx, normalized)
+		if err == nil {
+			product.ID = existing.ID
+			updated, err := s.UpdateProduct(ctx, product)
+			return updated, false, err
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, false, err
+		}
+
+		created, err := s.CreateProduct(ctx, product)
+		if errors.Is(err, domain.ErrConflict) && attempt == 0 {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return created, true, nil
+	}
+
+	return nil, false, &domain.ConflictError{Message: fmt.Sprintf("concurrent upsert of SKU %s", normalized)}
+}
+
+// PatchProduct actualiza solo los campos presentes en el patch. El resultado pasa
+// por las mismas validaciones que UpdateProduct (normalización y unicidad del SKU).
+func (s *ProductService) PatchProduct(ctx context.Context, id string, patch domain.ProductPatch) (*domain.Product, error) {
+	if patch.IsEmpty() {
+		return nil, &domain.ValidationError{
+			Field:   "body",
+			Message: "at least one of sku, name, description, category or price is required",
+		}
+	}
+
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	patch.Apply(product)
+
+	return s.UpdateProduct(ctx, product)
 }
 
-// DeleteProduct elimina un producto
-func (s *ProductService) DeleteProduct(ctx context.Context, id string) error {
-	// NOTA: Mejora futura - validar stock antes de eliminar
-	// Se debería verificar que no tenga stock en ninguna tienda:
-	// stocks, err := s.stockRepo.GetAllByProduct(ctx, id)
-	// if err != nil { return err }
-	// for _, stock := range stocks {
-	//     if stock.Quantity > 0 || stock.Reserved > 0 {
-	//         return &domain.ValidationError{
-	//             Field: "product_id",
-	//             Message: "cannot delete product with existing stock",
-	//         }
-	//     }
-	// }
+// DeleteProduct elimina un producto. Si todavía tiene stock o reservas pendientes retorna
+// ProductInUseError salvo que force sea true. El stock y las reservas del producto se archivan
+// (archived_stock / archived_reservations) en lugar de borrarse en cascada.
+func (s *ProductService) DeleteProduct(ctx context.Context, id string, force bool) (*domain.ProductArchive, error) {
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := s.productRepo.GetDependencies(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if deps.InUse() && !force {
+		return nil, &domain.ProductInUseError{Dependencies: *deps}
+	}
+
+	if err := s.attachMedia(ctx, product); err != nil {
+		return nil, err
+	}
+
+	archive, err := s.productRepo.ArchiveAndDelete(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.media != nil {
+		s.media.DeleteBlobs(ctx, product.Media)
+	}
 
-	return s.productRepo.Delete(ctx, id)
+	s.publishEvent(ctx, domain.NewProductEvent(domain.EventProductDeleted, product))
+
+	if deps.InUse() {
+		log.Printf("⚠️  Product %s (%s) force-deleted: archived %d stock rows and %d reservations (%d pending)",
+			product.ID, product.SKU, archive.ArchivedStock, archive.ArchivedReservations, deps.PendingReservations)
+	}
+
+	return archive, nil
 }
 
-// CountProducts cuenta el total de productos
-func (s *ProductService) CountProducts(ctx context.Context) (int, error) {
-	return s.productRepo.Count(ctx)
+// ChangeStatus cambia el estado del ciclo de vida del producto y emite product.status_changed.
+// Retorna InvalidStateError si la transición no está permitida.
+func (s *ProductService) ChangeStatus(ctx context.Context, id string, status domain.ProductStatus, reason string) (*domain.Product, error) {
+	if !status.IsValid() {
+		return nil, &domain.ValidationError{
+			Field:   "status",
+			Message: "status must be DRAFT, ACTIVE or DISCONTINUED",
+		}
+	}
+
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !product.Status.CanTransitionTo(status) {
+		return nil, &domain.InvalidStateError{
+			CurrentState:    string(product.Status),
+			AttemptedAction: "change status to " + string(status),
+		}
+	}
+
+	if err := s.productRepo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, err
+	}
+
+	from := product.Status
+	updated, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishEvent(ctx, domain.NewProductStatusChangedEvent(updated, from, reason))
+
+	return updated, nil
+}
+
+// publishEvent persiste y publica un evento de catálogo. Los fallos solo se registran:
+// el cambio ya está confirmado en la BD y el outbox reintenta la publicación.
+func (s *ProductService) publishEvent(ctx context.Context, event *domain.Event) {
+	// Persistir en BD
+	if err := s.eventRepo.Save(ctx, event); err != nil {
+		log.Printf("Warning: failed to save %s event: %v", event.EventType, err)
+	}
+
+	// Publicar a message broker
+	if s.publisher != nil {
+		if err := s.publisher.Publish(ctx, event); publishFailed(err) {
+			log.Printf("Warning: failed to publish %s event: %v", event.EventType, err)
+		}
+	}
 }
 
-// SearchProducts busca productos por nombre o descripción (simple)
+// CountProducts cuenta el total de productos (mismo criterio que ListProducts)
+func (s *ProductService) CountProducts(ctx context.Context, includeDiscontinued bool) (int, error) {
+	return s.productRepo.Count(ctx, includeDiscontinued)
+}
+
+// SearchProducts busca productos por nombre, descripción, SKU o categoría. Con índice externo
+// los resultados siguen su orden de relevancia y se leen de la BD, omitiendo los que ya no
+// existen o están descatalogados (el índice puede ir unos instantes por detrás).
 func (s *ProductService) SearchProducts(ctx context.Context, query string, limit, offset int) ([]*domain.Product, error) {
-	return s.ListProducts(ctx, limit, offset)
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, &domain.ValidationError{Field: "q", Message: "search query is required"}
+	}
+
+	if s.searchIndex == nil {
+		products, err := s.productRepo.Search(ctx, query, limit, offset, false)
+		if err != nil {
+			return nil, err
+		}
+		return products, s.attachMedia(ctx, products...)
+	}
+
+	ids, err := s.searchIndex.Search(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]*domain.Product, 0, len(ids))
+	for _, id := range ids {
+		product, err := s.productRepo.GetByID(ctx, id)
+		var notFound *domain.NotFoundError
+		if errors.As(err, &notFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if product.Status == domain.ProductStatusDiscontinued {
+			continue
+		}
+		products = append(products, product)
+	}
+	return products, s.attachMedia(ctx, products...)
 }
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// ProductUnitService gestiona las unidades de medida de los productos y convierte las
+// cantidades de las peticiones (cantidad + unidad) a la unidad base en la que se guarda el stock
+type ProductUnitService struct {
+	unitRepo     *repository.ProductUnitRepository
+	productRepo  *repository.ProductRepository
+	stockRepo    *repository.StockRepository
+	featureFlags *FeatureFlagService // Sin feature flags no se aceptan cantidades decimales
+}
+
+// NewProductUnitService crea una nueva instancia del servicio
+func NewProductUnitService(unitRepo *repository.ProductUnitRepository, productRepo *repository.ProductRepository, stockRepo *repository.StockRepository) *ProductUnitService {
+	return &ProductUnitService{
+		unitRepo:    unitRepo,
+		productRepo: productRepo,
+		stockRepo:   stockRepo,
+	}
+}
+
+// SetFeatureFlags habilita las cantidades decimales en las tiendas con fractional_quantities activado
+func (s *ProductUnitService) SetFeatureFlags(featureFlags *FeatureFlagService) {
+	s.featureFlags = featureFlags
+}
+
+// GetUnits obtiene las unidades de un producto (EACH sin conversiones si no tiene configuración)
+func (s *ProductUnitService) GetUnits(ctx context.Context, productID string) (*domain.ProductUnits, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.units(ctx, productID)
+}
+
+// SetUnits crea o reemplaza las unidades de un producto. La unidad base solo puede cambiar
+// mientras el producto no tenga stock: las cantidades guardadas cambiarían de significado.
+func (s *ProductUnitService) SetUnits(ctx context.Context, units *domain.ProductUnits) (*domain.ProductUnits, error) {
+	if _, err := s.productRepo.GetByID(ctx, units.ProductID); err != nil {
+		return nil, err
+	}
+	if err := units.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := s.units(ctx, units.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if current.BaseUnit != units.BaseUnit {
+		if err := s.checkNoStock(ctx, units.ProductID, "change base unit"); err != nil {
+			return nil, err
+		}
+	}
+
+	units.UpdatedAt = time.Now()
+	if err := s.unitRepo.Upsert(ctx, units); err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+// DeleteUnits elimina la configuración: el producto vuelve a EACH sin conversiones
+func (s *ProductUnitService) DeleteUnits(ctx context.Context, productID string) error {
+	current, err := s.unitRepo.GetByProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if current.BaseUnit != domain.UnitEach {
+		if err := s.checkNoStock(ctx, productID, "delete base unit "+current.BaseUnit); err != nil {
+			return err
+		}
+	}
+	return s.unitRepo.Delete(ctx, productID)
+}
+
+// ToBaseUnits convierte quantity expresada en unit (vacía = unidad base) a unidades base del producto
+func (s *ProductUnitService) ToBaseUnits(ctx context.Context, productID string, quantity int, unit string) (int, error) {
+	if domain.NormalizeUnit(unit) == "" {
+		return quantity, nil
+	}
+	units, err := s.units(ctx, productID)
+	if err != nil {
+		return 0, err
+	}
+	return units.ToBase(quantity, unit)
+}
+
+// ToBaseUnitsDecimal convierte una cantidad que puede ser decimal (1.25 KG) a unidades base. Las
+// enteras se convierten como en ToBaseUnits; las decimales requieren fractional_quantities en
+// todas las tiendas afectadas y una unidad fractional del producto.
+func (s *ProductUnitService) ToBaseUnitsDecimal(ctx context.Context, productID string, quantity float64, unit string, storeIDs ...string) (int, error) {
+	if domain.IsWholeQuantity(quantity) {
+		return s.ToBaseUnits(ctx, productID, int(quantity), unit)
+	}
+
+	for _, storeID := range storeIDs {
+		if s.featureFlags == nil {
+			return 0, &domain.FeatureDisabledError{Feature: domain.FeatureFractionalQuantities, StoreID: storeID}
+		}
+		if err := s.featureFlags.Require(ctx, domain.FeatureFractionalQuantities, storeID); err != nil {
+			return 0, err
+		}
+	}
+
+	units, err := s.units(ctx, productID)
+	if err != nil {
+		return 0, err
+	}
+	return units.ToBaseDecimal(quantity, unit)
+}
+
+// units obtiene la configuración del producto o la de por defecto
+func (s *ProductUnitService) units(ctx context.Context, productID string) (*domain.ProductUnits, error) {
+	units, err := s.unitRepo.GetByProduct(ctx, productID)
+	var notFound *domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return domain.DefaultProductUnits(productID), nil
+	}
+	return units, err
+}
+
+// checkNoStock verifica que el producto no tenga unidades en stock ni reservadas
+func (s *ProductUnitService) checkNoStock(ctx context.Context, productID, action string) error {
+	stocks, err := s.stockRepo.GetAllByProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	for _, stock := range stocks {
+		if stock.Quantity > 0 || stock.Reserved > 0 {
+			return &domain.ConflictError{
+				Message: fmt.Sprintf("cannot %s of product %s: store %s has stock in the current base unit", action, productID, stock.StoreID),
+			}
+		}
+	}
+	return nil
+}
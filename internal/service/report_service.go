@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// ReportService genera reportes agregados de inventario
+type ReportService struct {
+	reportRepo *repository.ReportRepository
+	groupRepo  *repository.StoreGroupRepository
+}
+
+// NewReportService crea una nueva instancia del servicio
+func NewReportService(reportRepo *repository.ReportRepository) *ReportService {
+	return &ReportService{
+		reportRepo: reportRepo,
+	}
+}
+
+// SetStoreGroupRepository activa los filtros y totales por grupo de tiendas
+func (s *ReportService) SetStoreGroupRepository(groupRepo *repository.StoreGroupRepository) {
+	s.groupRepo = groupRepo
+}
+
+// GetOverview obtiene la vista global del inventario: totales por tienda y por grupo,
+// los topN productos con menor disponibilidad en la red y el conteo de sin stock.
+// Con groupID todo se limita a las tiendas de ese grupo.
+func (s *ReportService) GetOverview(ctx context.Context, category, groupID string, topN int) (*domain.InventoryOverview, error) {
+	if topN <= 0 {
+		topN = 10
+	}
+	if topN > 100 {
+		return nil, &domain.ValidationError{
+			Field:   "top",
+			Message: "top cannot exceed 100",
+		}
+	}
+
+	groups, err := s.storeGroups(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	stores, err := s.reportRepo.GetStoreTotals(ctx, category, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	lowStock, err := s.reportRepo.GetLowestAvailability(ctx, category, groupID, topN)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &domain.InventoryOverview{
+		Category:    category,
+		GroupID:     groupID,
+		GeneratedAt: time.Now(),
+		Stores:      stores,
+		LowStock:    lowStock,
+	}
+
+	if overview.Stores == nil {
+		overview.Stores = []domain.StoreInventoryTotals{}
+	}
+	if overview.LowStock == nil {
+		overview.LowStock = []domain.LowStockProduct{}
+	}
+
+	for _, store := range stores {
+		overview.Totals.StockRows += store.Products
+		overview.Totals.Quantity += store.Quantity
+		overview.Totals.Reserved += store.Reserved
+		overview.Totals.Held += store.Held
+		overview.Totals.Available += store.Available
+		overview.OutOfStockCount += store.OutOfStock
+		overview.OversoldCount += store.Oversold
+	}
+
+	overview.Groups = groupTotals(groups, stores)
+
+	return overview, nil
+}
+
+// storeGroups retorna los grupos a agregar en el overview: el grupo filtrado (NotFoundError si
+// no existe) o todos. Sin repositorio de grupos no hay agregados.
+func (s *ReportService) storeGroups(ctx context.Context, groupID string) ([]*domain.StoreGroup, error) {
+	if s.groupRepo == nil {
+		return nil, nil
+	}
+	if groupID != "" {
+		group, err := s.groupRepo.GetByID(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		return []*domain.StoreGroup{group}, nil
+	}
+	return s.groupRepo.List(ctx, "")
+}
+
+// groupTotals suma los totales de las tiendas de cada grupo
+func groupTotals(groups []*domain.StoreGroup, stores []domain.StoreInventoryTotals) []domain.GroupInventoryTotals {
+	totals := make([]domain.GroupInventoryTotals, 0, len(groups))
+	for _, group := range groups {
+		t := domain.GroupInventoryTotals{
+			GroupID: group.ID,
+			Name:    group.Name,
+			Type:    group.Type,
+		}
+		for _, store := range stores {
+			if !group.HasStore(store.StoreID) {
+				continue
+			}
+			t.Stores++
+			t.StockRows += store.Products
+			t.Quantity += store.Quantity
+			t.Reserved += store.Reserved
+			t.Held += store.Held
+			t.Available += store.Available
+			t.OutOfStock += store.OutOfStock
+			t.Oversold += store.Oversold
+		}
+		totals = append(totals, t)
+	}
+	return totals
+}
+
+// GetLowStockMetrics obtiene las topN filas de stock por debajo del umbral para el exporter
+// de Prometheus. El total se calcula sin límite para detectar cuándo el listado está truncado.
+func (s *ReportService) GetLowStockMetrics(ctx context.Context, threshold, topN int) (*domain.LowStockMetrics, error) {
+	if threshold <= 0 {
+		return nil, &domain.ValidationError{
+			Field:   "threshold",
+			Message: "threshold must be positive",
+		}
+	}
+	if topN <= 0 {
+		return nil, &domain.ValidationError{
+			Field:   "top_n",
+			Message: "top_n must be positive",
+		}
+	}
+
+	total, err := s.reportRepo.CountLowStockEntries(ctx, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.reportRepo.GetLowStockEntries(ctx, threshold, topN)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []domain.LowStockEntry{}
+	}
+
+	return &domain.LowStockMetrics{
+		Threshold: threshold,
+		TopN:      topN,
+		Total:     total,
+		Items:     items,
+	}, nil
+}
+
+// GetOutOfStockReport lista las filas sin disponibilidad (opcionalmente de una tienda o de un
+// grupo de tiendas) con el tiempo que llevan así, para priorizar reposiciones. El inicio se deriva
+// del último movimiento que alteró la disponibilidad; sin movimientos se usa el updated_at de la fila de stock.
+func (s *ReportService) GetOutOfStockReport(ctx context.Context, storeID, groupID string) (*domain.OutOfStockReport, error) {
+	if groupID != "" && s.groupRepo != nil {
+		if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := s.reportRepo.GetOutOfStockEntries(ctx, storeID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	oversold := 0
+	for i := range items {
+		item := &items[i]
+		if item.Available < 0 {
+			item.Oversold = true
+			oversold++
+		}
+
+		since, err := s.reportRepo.GetLastAvailabilityChange(ctx, item.ProductID, item.StoreID)
+		if err != nil {
+			return nil, err
+		}
+		item.OutSinceSource = domain.OutSinceStockRow
+		if since != nil {
+			item.OutSince = *since
+			item.OutSinceSource = domain.OutSinceMovement
+		}
+
+		item.OutForSeconds = int64(now.Sub(item.OutSince).Seconds())
+		if item.OutForSeconds < 0 {
+			item.OutForSeconds = 0
+		}
+	}
+
+	// Las que llevan más tiempo sin stock primero
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].OutForSeconds > items[j].OutForSeconds
+	})
+
+	if items == nil {
+		items = []domain.OutOfStockItem{}
+	}
+
+	return &domain.OutOfStockReport{
+		StoreID:     storeID,
+		GroupID:     groupID,
+		GeneratedAt: now,
+		Items:       items,
+		Count:       len(items),
+		Oversold:    oversold,
+	}, nil
+}
+
+// GetAdjustmentsByReason agrupa por motivo los ajustes manuales registrados (opcionalmente de una
+// tienda y en [from, to)) con las unidades sumadas y retiradas, para analizar mermas y roturas
+func (s *ReportService) GetAdjustmentsByReason(ctx context.Context, storeID string, from, to *time.Time) (*domain.AdjustmentReasonReport, error) {
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, &domain.ValidationError{Field: "to", Message: "to must be after from"}
+	}
+
+	items, err := s.reportRepo.GetAdjustmentsByReason(ctx, storeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AdjustmentReasonReport{
+		StoreID:     storeID,
+		From:        from,
+		To:          to,
+		GeneratedAt: time.Now(),
+		Items:       items,
+		Count:       len(items),
+	}, nil
+}
+
+// maxKPIDatapoints límite de puntos por serie (rango / intervalo) de GetKPIs
+const maxKPIDatapoints = 2000
+
+// GetKPIs calcula las series temporales de los KPIs de negocio en [from, to) con un punto por
+// intervalo (alineado a interval), opcionalmente de una tienda. Sin metrics devuelve todos.
+func (s *ReportService) GetKPIs(ctx context.Context, storeID string, metrics []string, from, to time.Time, interval time.Duration) ([]domain.KPISeries, error) {
+	if interval < time.Minute {
+		return nil, &domain.ValidationError{Field: "interval", Message: "interval must be at least 1m"}
+	}
+	if !to.After(from) {
+		return nil, &domain.ValidationError{Field: "to", Message: "to must be after from"}
+	}
+	if to.Sub(from)/interval > maxKPIDatapoints {
+		return nil, &domain.ValidationError{Field: "interval", Message: "too many datapoints: use a larger interval or a shorter range"}
+	}
+	if len(metrics) == 0 {
+		metrics = domain.KPIMetrics
+	}
+	for _, metric := range metrics {
+		if !domain.IsValidKPIMetric(metric) {
+			return nil, &domain.ValidationError{Field: "metrics", Message: "unknown metric " + metric}
+		}
+	}
+
+	events, err := s.reportRepo.GetKPIEvents(ctx, storeID, from.Truncate(interval), to)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := domain.NewKPIBuckets(from, to, interval, events)
+	series := make([]domain.KPISeries, 0, len(metrics))
+	for _, metric := range metrics {
+		series = append(series, buckets.Series(metric))
+	}
+	return series, nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// ReservationGroupService gestiona grupos de reservas con hijas en distintas tiendas (envío
+// partido). Confirmar o cancelar el grupo aplica la acción en cada tienda por separado y
+// reporta el resultado de cada hija.
+type ReservationGroupService struct {
+	reservationService *ReservationService
+	reservationRepo    *repository.ReservationRepository
+	groupRepo          *repository.ReservationGroupRepository
+}
+
+// NewReservationGroupService crea una nueva instancia del servicio
+func NewReservationGroupService(reservationService *ReservationService, reservationRepo *repository.ReservationRepository, groupRepo *repository.ReservationGroupRepository) *ReservationGroupService {
+	return &ReservationGroupService{
+		reservationService: reservationService,
+		reservationRepo:    reservationRepo,
+		groupRepo:          groupRepo,
+	}
+}
+
+// CreateReservationGroup crea una reserva por item y las agrupa. Si alguna no se puede crear
+// se cancelan las ya creadas y no se crea el grupo.
+func (s *ReservationGroupService) CreateReservationGroup(ctx context.Context, customerID string, items []domain.ReservationGroupItem, ttlMinutes int, priority domain.ReservationPriority) (*domain.ReservationGroup, error) {
+	if len(items) == 0 || len(items) > domain.MaxReservationGroupSize {
+		return nil, &domain.ValidationError{
+			Field:   "items",
+			Message: fmt.Sprintf("a group must have between 1 and %d reservations", domain.MaxReservationGroupSize),
+		}
+	}
+
+	var reservations []*domain.Reservation
+	for _, item := range items {
+		reservation, err := s.reservationService.CreateReservationWithPriority(ctx, item.ProductID, item.StoreID, customerID, item.Quantity, ttlMinutes, priority)
+		if err != nil {
+			s.rollback(ctx, reservations)
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	group, err := s.GroupReservations(ctx, customerID, reservations)
+	if err != nil {
+		s.rollback(ctx, reservations)
+		return nil, err
+	}
+	return group, nil
+}
+
+// GroupReservations agrupa reservas ya creadas (p. ej. el reparto de una reserva automática)
+func (s *ReservationGroupService) GroupReservations(ctx context.Context, customerID string, reservations []*domain.Reservation) (*domain.ReservationGroup, error) {
+	group := &domain.ReservationGroup{
+		ID:           uuid.New().String(),
+		CustomerID:   customerID,
+		Reservations: reservations,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+
+	group.Refresh()
+	return group, nil
+}
+
+// GetReservationGroup obtiene el grupo con sus reservas hijas y el estado agregado
+func (s *ReservationGroupService) GetReservationGroup(ctx context.Context, id string) (*domain.ReservationGroup, error) {
+	group, err := s.groupRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if group.Reservations, err = s.reservationRepo.GetByGroup(ctx, id); err != nil {
+		return nil, err
+	}
+
+	group.Refresh()
+	return group, nil
+}
+
+// ConfirmReservationGroup confirma las reservas hijas pendientes; referenceID se incluye en
+// cada reservation.confirmed
+func (s *ReservationGroupService) ConfirmReservationGroup(ctx context.Context, id, referenceID string) (*domain.ReservationGroupActionReport, error) {
+	return s.apply(ctx, id, domain.ReservationActionConfirm, func(reservationID string) error {
+		return s.reservationService.ConfirmReservationWithReference(ctx, reservationID, referenceID)
+	})
+}
+
+// CancelReservationGroup cancela las reservas hijas pendientes
+func (s *ReservationGroupService) CancelReservationGroup(ctx context.Context, id string) (*domain.ReservationGroupActionReport, error) {
+	return s.apply(ctx, id, domain.ReservationActionCancel, func(reservationID string) error {
+		return s.reservationService.CancelReservation(ctx, reservationID)
+	})
+}
+
+// apply ejecuta la acción en cada hija (un fallo no detiene ni revierte las demás) y
+// reporta el estado de cada una tras la acción
+func (s *ReservationGroupService) apply(ctx context.Context, id string, action domain.ReservationAction, do func(reservationID string) error) (*domain.ReservationGroupActionReport, error) {
+	group, err := s.GetReservationGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	target := action.Target()
+	results := make(map[string]*domain.ReservationGroupResult, len(group.Reservations))
+	for _, reservation := range group.Reservations {
+		result := &domain.ReservationGroupResult{
+			ReservationID: reservation.ID,
+			StoreID:       reservation.StoreID,
+			Outcome:       domain.ReservationGroupSucceeded,
+		}
+		if reservation.Status == target {
+			result.Outcome = domain.ReservationGroupSkipped
+		} else if err := do(reservation.ID); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			result.Outcome = domain.ReservationGroupFailed
+			result.Error = err.Error()
+			result.Err = err
+		}
+		results[reservation.ID] = result
+	}
+
+	// Releer las hijas: el estado de las fallidas puede haber cambiado (p. ej. expiradas)
+	if group.Reservations, err = s.reservationRepo.GetByGroup(ctx, id); err != nil {
+		return nil, err
+	}
+	group.Refresh()
+
+	report := &domain.ReservationGroupActionReport{GroupID: id, Action: action, Status: group.Status}
+	for _, reservation := range group.Reservations {
+		if result, ok := results[reservation.ID]; ok {
+			result.Status = reservation.Status
+			report.Add(result)
+		}
+	}
+	return report, nil
+}
+
+// rollback cancela las reservas ya creadas cuando el grupo no se completa
+func (s *ReservationGroupService) rollback(ctx context.Context, reservations []*domain.Reservation) {
+	for _, reservation := range reservations {
+		if err := s.reservationService.CancelReservation(ctx, reservation.ID); err != nil {
+			log.Printf("Warning: failed to roll back reservation %s: %v", reservation.ID, err)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// ReservationImportService importa reservas existentes en un OMS externo durante una migración.
+// Conserva el estado y las fechas originales; solo las reservas PENDING incrementan Reserved.
+type ReservationImportService struct {
+	reservationRepo *repository.ReservationRepository
+	stockRepo       *repository.StockRepository
+	productRepo     *repository.ProductRepository
+	eventRepo       *repository.EventRepository
+	publisher       domain.EventPublisher
+}
+
+// NewReservationImportService crea una nueva instancia del servicio
+func NewReservationImportService(
+	reservationRepo *repository.ReservationRepository,
+	stockRepo *repository.StockRepository,
+	productRepo *repository.ProductRepository,
+	eventRepo *repository.EventRepository,
+	publisher domain.EventPublisher,
+) *ReservationImportService {
+	return &ReservationImportService{
+		reservationRepo: reservationRepo,
+		stockRepo:       stockRepo,
+		productRepo:     productRepo,
+		eventRepo:       eventRepo,
+		publisher:       publisher,
+	}
+}
+
+// stockKey identifica la disponibilidad de un canal en una fila de stock
+type stockKey struct {
+	productID string
+	storeID   string
+	channel   domain.SalesChannel
+}
+
+// ImportReservations importa las reservas externas una a una: un error en una no impide importar
+// las demás. Los external_id ya importados se omiten (SKIPPED), así que la importación se puede
+// repetir. Con dryRun solo se valida (incluida la disponibilidad acumulada de las PENDING del lote)
+// sin escribir nada.
+func (s *ReservationImportService) ImportReservations(ctx context.Context, items []*domain.ReservationImport, dryRun bool) (*domain.ReservationImportReport, error) {
+	if len(items) == 0 {
+		return nil, &domain.ValidationError{Field: "reservations", Message: "at least one reservation is required"}
+	}
+	if len(items) > domain.MaxReservationImportBatch {
+		return nil, &domain.ValidationError{
+			Field:   "reservations",
+			Message: fmt.Sprintf("at most %d reservations per import", domain.MaxReservationImportBatch),
+		}
+	}
+
+	now := time.Now()
+	report := &domain.ReservationImportReport{DryRun: dryRun, Results: make([]*domain.ReservationImportResult, 0, len(items))}
+	seen := make(map[string]bool, len(items))
+	planned := make(map[stockKey]int) // Unidades PENDING ya validadas en el lote (dry-run)
+
+	for _, item := range items {
+		result, err := s.importItem(ctx, item, now, dryRun, seen, planned)
+		if err != nil {
+			return nil, err
+		}
+		report.Add(result)
+	}
+
+	return report, nil
+}
+
+// importItem valida e importa (o solo valida, con dryRun) una reserva externa. Los errores de
+// negocio quedan en el resultado (FAILED); solo se retornan los errores de infraestructura.
+func (s *ReservationImportService) importItem(ctx context.Context, item *domain.ReservationImport, now time.Time, dryRun bool, seen map[string]bool, planned map[stockKey]int) (*domain.ReservationImportResult, error) {
+	result := &domain.ReservationImportResult{ExternalID: item.ExternalID}
+	failed := func(err error) (*domain.ReservationImportResult, error) {
+		if !isRejection(err) {
+			return nil, err
+		}
+		result.Outcome = domain.ReservationImportFailed
+		result.Error = err.Error()
+		result.Err = err
+		return result, nil
+	}
+
+	if err := item.Validate(now); err != nil {
+		return failed(err)
+	}
+	if seen[item.ExternalID] {
+		return failed(&domain.ValidationError{Field: "external_id", Message: "duplicated external_id in the import"})
+	}
+	seen[item.ExternalID] = true
+
+	existing, err := s.reservationRepo.GetImportedReservationID(ctx, item.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != "" {
+		result.ReservationID = existing
+		result.Outcome = domain.ReservationImportSkipped
+		return result, nil
+	}
+
+	if err := s.checkTarget(ctx, item); err != nil {
+		return failed(err)
+	}
+
+	if dryRun {
+		if item.Status == domain.ReservationStatusPending {
+			key := stockKey{item.ProductID, item.StoreID, item.Channel}
+			if err := s.checkAvailable(ctx, item, planned[key]); err != nil {
+				return failed(err)
+			}
+			planned[key] += item.Quantity
+		}
+		result.Outcome = domain.ReservationImportValid
+		return result, nil
+	}
+
+	reservationID, err := s.importReservation(ctx, item)
+	if err != nil {
+		// Otra importación concurrente registró el mismo external_id
+		if existing, _ := s.reservationRepo.GetImportedReservationID(ctx, item.ExternalID); existing != "" {
+			result.ReservationID = existing
+			result.Outcome = domain.ReservationImportSkipped
+			return result, nil
+		}
+		return failed(err)
+	}
+
+	result.ReservationID = reservationID
+	result.Outcome = domain.ReservationImportImported
+	return result, nil
+}
+
+// checkTarget verifica que el producto y la fila de stock existen. Una reserva PENDING
+// además requiere que el producto se pueda vender.
+func (s *ReservationImportService) checkTarget(ctx context.Context, item *domain.ReservationImport) error {
+	if err := domain.AuthorizeStoreWrite(ctx, item.StoreID); err != nil {
+		return err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, item.ProductID)
+	if err != nil {
+		return err
+	}
+	if item.Status == domain.ReservationStatusPending && !product.IsSellable() {
+		return &domain.InvalidStateError{
+			CurrentState:    string(product.Status),
+			AttemptedAction: "reserve product " + item.ProductID,
+		}
+	}
+
+	_, err = s.stockRepo.GetByProductAndStore(ctx, item.ProductID, item.StoreID)
+	return err
+}
+
+// checkAvailable comprueba (sin bloquear) que la disponibilidad del canal, con la tolerancia de
+// sobreventa, cubre la reserva además de las unidades ya validadas en el lote
+func (s *ReservationImportService) checkAvailable(ctx context.Context, item *domain.ReservationImport, planned int) error {
+	available, err := s.stockRepo.GetChannelAvailable(ctx, item.ProductID, item.StoreID, item.Channel)
+	if err != nil {
+		return err
+	}
+	tolerance, err := s.stockRepo.GetOversellTolerance(ctx, item.ProductID, item.StoreID)
+	if err != nil {
+		return err
+	}
+	if available+tolerance-planned < item.Quantity {
+		return &domain.InsufficientStockError{
+			ProductID: item.ProductID,
+			StoreID:   item.StoreID,
+			Available: available - planned,
+			Requested: item.Quantity,
+		}
+	}
+	return nil
+}
+
+// importReservation crea la reserva importada. Las PENDING reservan el stock del canal antes
+// de crearse (y lo liberan si la creación falla) y publican reservation.created.
+func (s *ReservationImportService) importReservation(ctx context.Context, item *domain.ReservationImport) (string, error) {
+	reservation := item.Reservation(uuid.New().String())
+	pending := reservation.Status == domain.ReservationStatusPending
+
+	if pending {
+		if err := s.stockRepo.ReserveChannelStock(ctx, reservation.ProductID, reservation.StoreID, reservation.Channel, reservation.Quantity); err != nil {
+			return "", err
+		}
+	}
+
+	if err := s.reservationRepo.CreateImported(ctx, reservation, item.ExternalID); err != nil {
+		if pending {
+			_ = s.stockRepo.ReleaseChannelStock(ctx, reservation.ProductID, reservation.StoreID, reservation.Channel, reservation.Quantity)
+		}
+		return "", err
+	}
+
+	if pending {
+		event := domain.NewReservationLifecycleEvent(domain.EventReservationCreated, reservation)
+		if err := s.eventRepo.Save(ctx, event); err != nil {
+			log.Printf("Warning: failed to save reservation created event: %v", err)
+		}
+		if err := s.publisher.Publish(ctx, event); publishFailed(err) {
+			log.Printf("Warning: failed to publish reservation created event: %v", err)
+		}
+	}
+
+	return reservation.ID, nil
+}
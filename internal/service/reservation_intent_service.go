@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// reservationIntentRetention tiempo que se conservan las intenciones caducadas (análisis de demanda)
+const reservationIntentRetention = 24 * time.Hour
+
+// ReservationIntentService gestiona las intenciones de reserva: consultan la disponibilidad y
+// registran el interés sin bloquear stock, y se convierten en reservas reales al iniciar el checkout
+type ReservationIntentService struct {
+	intentRepo         *repository.ReservationIntentRepository
+	productRepo        *repository.ProductRepository
+	stockService       *StockService
+	reservationService *ReservationService
+	ttl                time.Duration
+}
+
+// NewReservationIntentService crea una nueva instancia del servicio
+func NewReservationIntentService(
+	intentRepo *repository.ReservationIntentRepository,
+	productRepo *repository.ProductRepository,
+	stockService *StockService,
+	reservationService *ReservationService,
+	ttl time.Duration,
+) *ReservationIntentService {
+	return &ReservationIntentService{
+		intentRepo:         intentRepo,
+		productRepo:        productRepo,
+		stockService:       stockService,
+		reservationService: reservationService,
+		ttl:                ttl,
+	}
+}
+
+// CreateIntent registra el interés del cliente y la disponibilidad actual. No modifica Reserved:
+// la intención se registra aunque no haya stock suficiente (available = false).
+func (s *ReservationIntentService) CreateIntent(ctx context.Context, intent *domain.ReservationIntent) (*domain.ReservationIntent, error) {
+	if err := intent.Validate(); err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, intent.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.IsSellable() {
+		return nil, &domain.InvalidStateError{
+			CurrentState:    string(product.Status),
+			AttemptedAction: "reserve product " + intent.ProductID,
+		}
+	}
+
+	// Lectura ligera: con cache de disponibilidad no toca la tabla stock
+	available, err := s.stockService.GetAvailableStock(ctx, intent.ProductID, intent.StoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	intent.Token = uuid.New().String()
+	intent.AvailableQuantity = available
+	intent.Available = available >= intent.Quantity
+	intent.ExpiresAt = now.Add(s.ttl)
+	intent.CreatedAt = now
+	intent.Refresh(now)
+
+	if err := s.intentRepo.Create(ctx, intent); err != nil {
+		return nil, err
+	}
+	return intent, nil
+}
+
+// GetIntent obtiene una intención con su estado actual
+func (s *ReservationIntentService) GetIntent(ctx context.Context, token string) (*domain.ReservationIntent, error) {
+	intent, err := s.intentRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	intent.Refresh(time.Now())
+	return intent, nil
+}
+
+// ConvertIntent crea la reserva real de una intención vigente. El stock se comprueba de nuevo
+// (puede responder InsufficientStockError aunque la intención se registrara con disponibilidad).
+// Cada intención se convierte una sola vez; si la reserva falla, sigue siendo convertible.
+func (s *ReservationIntentService) ConvertIntent(ctx context.Context, token string, ttlMinutes int) (*domain.Reservation, error) {
+	intent, err := s.intentRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.intentRepo.Claim(ctx, token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		// Otra petición la convirtió o caducó entre la lectura y el claim
+		if current, err := s.intentRepo.GetByToken(ctx, token); err == nil {
+			intent = current
+		}
+		intent.Refresh(time.Now())
+		return nil, &domain.InvalidStateError{
+			CurrentState:    string(intent.Status),
+			AttemptedAction: "convert reservation intent " + token,
+		}
+	}
+
+	reservation, err := s.reservationService.CreateReservation(ctx, intent.ProductID, intent.StoreID, intent.CustomerID, intent.Quantity, ttlMinutes)
+	if err != nil {
+		if unclaimErr := s.intentRepo.Unclaim(ctx, token); unclaimErr != nil {
+			return nil, unclaimErr
+		}
+		return nil, err
+	}
+
+	if err := s.intentRepo.SetReservation(ctx, token, reservation.ID); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// PurgeExpiredIntents elimina las intenciones caducadas hace más de reservationIntentRetention
+func (s *ReservationIntentService) PurgeExpiredIntents(ctx context.Context) (int64, error) {
+	return s.intentRepo.DeleteExpiredBefore(ctx, time.Now().Add(-reservationIntentRetention))
+}
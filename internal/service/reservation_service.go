@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -19,6 +20,16 @@ type ReservationService struct {
 	productRepo     *repository.ProductRepository
 	eventRepo       *repository.EventRepository
 	publisher       domain.EventPublisher // ← Event publisher para pub/sub
+	ttlPolicy       domain.ReservationTTLPolicy
+	storeRepo       *repository.StoreRepository             // Opcional: metadatos de tienda en reservation.confirmed
+	holdLimits      domain.CustomerHoldLimits               // Anti-acaparamiento por cliente (cero = sin límite)
+	hoursRepo       *repository.StoreHoursRepository        // Opcional: horario de apertura y corte de reservas
+	groupRepo       *repository.StoreGroupRepository        // Opcional: filtros y desglose por grupo de tiendas en las estadísticas
+	shortageGrace   time.Duration                           // Antigüedad mínima de las reservas LOW expiradas por falta de stock (cero = desactivado)
+	states          *domain.ReservationStateMachine         // Transiciones confirm/cancel/expire y emisión de sus eventos
+	confirmHooks    confirmHooks                            // Integraciones externas de la confirmación (pago, pedidos)
+	pickupRepo      *repository.ReservationPickupRepository // Opcional: recogida en tienda (click-and-collect)
+	pickupWindow    time.Duration                           // Plazo de recogida desde que la reserva está lista
 }
 
 // NewReservationService crea una nueva instancia del servicio
@@ -29,18 +40,147 @@ func NewReservationService(
 	eventRepo *repository.EventRepository,
 	publisher domain.EventPublisher, // ← Inyección de dependencia
 ) *ReservationService {
-	return &ReservationService{
+	s := &ReservationService{
 		reservationRepo: reservationRepo,
 		stockRepo:       stockRepo,
 		productRepo:     productRepo,
 		eventRepo:       eventRepo,
 		publisher:       publisher,
+		ttlPolicy:       domain.DefaultReservationTTLPolicy(),
+		states:          domain.NewReservationStateMachine(),
 	}
+
+	s.states.OnTransition(domain.ReservationStatusConfirmed, func(ctx context.Context, t *domain.ReservationTransition) {
+		s.emitEvent(ctx, domain.NewReservationConfirmedEvent(t.Reservation, t.Product, s.storeMetadata(ctx, t.Reservation.StoreID), t.ReferenceID))
+	})
+	s.states.OnTransition(domain.ReservationStatusCancelled, func(ctx context.Context, t *domain.ReservationTransition) {
+		eventType := domain.EventReservationCancelled
+		if t.From == domain.ReservationStatusPreorder {
+			eventType = domain.EventPreorderCancelled
+		}
+		s.emitEvent(ctx, domain.NewReservationLifecycleEvent(eventType, t.Reservation))
+	})
+	s.states.OnTransition(domain.ReservationStatusPending, func(ctx context.Context, t *domain.ReservationTransition) {
+		s.emitEvent(ctx, domain.NewReservationLifecycleEvent(domain.EventPreorderAllocated, t.Reservation))
+	})
+	s.states.OnTransition(domain.ReservationStatusExpired, func(ctx context.Context, t *domain.ReservationTransition) {
+		s.emitEvent(ctx, domain.NewReservationLifecycleEvent(domain.EventReservationExpired, t.Reservation))
+	})
+	s.states.OnTransition(domain.ReservationStatusReadyForPickup, func(ctx context.Context, t *domain.ReservationTransition) {
+		s.emitEvent(ctx, domain.NewReservationLifecycleEvent(domain.EventReservationReady, t.Reservation))
+	})
+	s.states.OnTransition(domain.ReservationStatusPickedUp, func(ctx context.Context, t *domain.ReservationTransition) {
+		s.emitEvent(ctx, domain.NewReservationPickedUpEvent(t.Reservation, t.Product, s.storeMetadata(ctx, t.Reservation.StoreID)))
+	})
+
+	return s
+}
+
+// OnTransition registra un hook adicional para las transiciones de reservas que llevan a status
+func (s *ReservationService) OnTransition(status domain.ReservationStatus, hook domain.ReservationTransitionHook) {
+	s.states.OnTransition(status, hook)
+}
+
+// emitEvent persiste el evento y lo publica en el message broker
+func (s *ReservationService) emitEvent(ctx context.Context, event *domain.Event) {
+	// Persistir en BD
+	if err := s.eventRepo.Save(ctx, event); err != nil {
+		log.Printf("Warning: failed to save %s event: %v", event.EventType, err)
+	}
+
+	// Publicar a message broker
+	if err := s.publisher.Publish(ctx, event); publishFailed(err) {
+		log.Printf("Warning: failed to publish %s event: %v", event.EventType, err)
+	}
+}
+
+// AddConfirmHook registra un hook que participa en la confirmación de reservas. Los síncronos
+// se ejecutan en orden de registro antes de descontar el stock; los asíncronos, después.
+func (s *ReservationService) AddConfirmHook(hook domain.ConfirmHook, policy domain.ConfirmHookPolicy) {
+	s.confirmHooks.add(hook, policy)
+}
+
+// WaitConfirmHooks espera a que terminen los hooks asíncronos en curso
+func (s *ReservationService) WaitConfirmHooks() {
+	s.confirmHooks.wait()
+}
+
+// SetTTLPolicy configura los TTL por defecto y máximos (globales y por tienda)
+func (s *ReservationService) SetTTLPolicy(policy domain.ReservationTTLPolicy) {
+	s.ttlPolicy = policy
 }
 
-// CreateReservation crea una nueva reserva de stock
+// SetStoreRepository habilita los metadatos de tienda (nombre, ciudad, país) en reservation.confirmed
+func (s *ReservationService) SetStoreRepository(storeRepo *repository.StoreRepository) {
+	s.storeRepo = storeRepo
+}
+
+// SetStoreHoursRepository habilita el horario de las tiendas: sin reservas con la tienda
+// cerrada o dentro del corte, y TTL contado solo en horas de apertura
+func (s *ReservationService) SetStoreHoursRepository(hoursRepo *repository.StoreHoursRepository) {
+	s.hoursRepo = hoursRepo
+}
+
+// SetStoreGroupRepository habilita el filtro y el desglose por grupo de tiendas en las estadísticas
+func (s *ReservationService) SetStoreGroupRepository(groupRepo *repository.StoreGroupRepository) {
+	s.groupRepo = groupRepo
+}
+
+// SetCustomerHoldLimits configura los límites por cliente de unidades y reservas pendientes
+func (s *ReservationService) SetCustomerHoldLimits(limits domain.CustomerHoldLimits) {
+	s.holdLimits = limits
+}
+
+// SetLowPriorityShortageGrace hace que el worker de expiración libere antes de tiempo las reservas
+// LOW con más de grace de antigüedad cuando el producto se queda sin disponibilidad en la tienda
+func (s *ReservationService) SetLowPriorityShortageGrace(grace time.Duration) {
+	s.shortageGrace = grace
+}
+
+// SetPickupRepository habilita la recogida en tienda: las reservas pendientes se pueden marcar
+// como listas para recoger y caducan si el cliente no las recoge en window
+func (s *ReservationService) SetPickupRepository(pickupRepo *repository.ReservationPickupRepository, window time.Duration) {
+	s.pickupRepo = pickupRepo
+	s.pickupWindow = window
+}
+
+// CreateReservation crea una nueva reserva de stock con prioridad NORMAL.
+// Si ttlMinutes es 0 se aplica el TTL por defecto de la tienda.
 func (s *ReservationService) CreateReservation(ctx context.Context, productID, storeID, customerID string, quantity int, ttlMinutes int) (*domain.Reservation, error) {
+	return s.CreateReservationWithPriority(ctx, productID, storeID, customerID, quantity, ttlMinutes, domain.ReservationPriorityNormal)
+}
+
+// CreateReservationWithPriority crea una nueva reserva de stock con la prioridad indicada
+// (vacía = NORMAL). Ante falta de stock se liberan antes las reservas de menor prioridad.
+func (s *ReservationService) CreateReservationWithPriority(ctx context.Context, productID, storeID, customerID string, quantity int, ttlMinutes int, priority domain.ReservationPriority) (*domain.Reservation, error) {
+	return s.createReservation(ctx, productID, storeID, customerID, quantity, ttlMinutes, priority, false)
+}
+
+// CreatePreorder crea una preventa (PREORDER) contra las unidades entrantes de la fila de stock
+// (cambios programados de pedidos de compra y transferencias) que no estén comprometidas en otras
+// preventas. No reserva stock ni caduca: AllocatePreorders le asigna stock al recibirse la
+// mercancía y desde entonces es una reserva PENDING con el TTL pedido.
+func (s *ReservationService) CreatePreorder(ctx context.Context, productID, storeID, customerID string, quantity int, ttlMinutes int, priority domain.ReservationPriority) (*domain.Reservation, error) {
+	return s.createReservation(ctx, productID, storeID, customerID, quantity, ttlMinutes, priority, true)
+}
+
+// createReservation valida y crea una reserva, o una preventa si preorder es true
+func (s *ReservationService) createReservation(ctx context.Context, productID, storeID, customerID string, quantity int, ttlMinutes int, priority domain.ReservationPriority, preorder bool) (*domain.Reservation, error) {
+	// La API key de una tienda solo reserva en su tienda (las multi-tienda, en cualquiera)
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
+	}
+
 	// Validaciones
+	if priority == "" {
+		priority = domain.ReservationPriorityNormal
+	}
+	if !priority.IsValid() {
+		return nil, &domain.ValidationError{
+			Field:   "priority",
+			Message: "priority must be LOW, NORMAL or HIGH",
+		}
+	}
 	if quantity <= 0 {
 		return nil, &domain.ValidationError{
 			Field:   "quantity",
@@ -48,11 +188,9 @@ func (s *ReservationService) CreateReservation(ctx context.Context, productID, s
 		}
 	}
 
-	if ttlMinutes <= 0 {
-		return nil, &domain.ValidationError{
-			Field:   "ttlMinutes",
-			Message: "TTL must be positive",
-		}
+	ttlMinutes, err := s.ttlPolicy.Resolve(storeID, ttlMinutes)
+	if err != nil {
+		return nil, err
 	}
 
 	if customerID == "" {
@@ -62,20 +200,56 @@ func (s *ReservationService) CreateReservation(ctx context.Context, productID, s
 		}
 	}
 
-	// Validar que el producto existe
-	_, err := s.productRepo.GetByID(ctx, productID)
+	// Horario de la tienda: rechazar fuera de apertura o dentro del corte previo al cierre
+	now := time.Now()
+	hours, err := s.storeHours(ctx, storeID)
 	if err != nil {
 		return nil, err
 	}
+	if hours != nil {
+		if err := hours.CheckReservable(now); err != nil {
+			return nil, err
+		}
+	}
 
-	// Reservar stock (usa transacción interna con lock)
-	err = s.stockRepo.ReserveStock(ctx, productID, storeID, quantity)
+	// Validar que el producto existe y está activo
+	product, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
 		return nil, err
 	}
+	if !product.IsSellable() {
+		return nil, &domain.InvalidStateError{
+			CurrentState:    string(product.Status),
+			AttemptedAction: "reserve product " + productID,
+		}
+	}
+
+	// Rechazo rápido por límites del cliente, antes de tocar el stock.
+	// La garantía atómica la da CreateWithinLimits. Las preventas cuentan al asignarse.
+	if s.holdLimits.Enabled() && !preorder {
+		holds, err := s.reservationRepo.GetCustomerHolds(ctx, customerID, productID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if err := s.holdLimits.Check(customerID, productID, holds, quantity); err != nil {
+			return nil, err
+		}
+	}
 
-	// Crear reserva
-	expiresAt := time.Now().Add(time.Duration(ttlMinutes) * time.Minute)
+	// Reservar stock (usa transacción interna con lock) contra la asignación del canal del request
+	channel := domain.SalesChannelFromContext(ctx)
+	if !preorder {
+		err = s.stockRepo.ReserveChannelStock(ctx, productID, storeID, channel, quantity)
+		if err != nil {
+			return nil, s.diagnoseShortage(ctx, err)
+		}
+	}
+
+	// Crear reserva (con horario, el TTL no corre con la tienda cerrada)
+	expiresAt := now.Add(time.Duration(ttlMinutes) * time.Minute)
+	if hours != nil {
+		expiresAt = hours.ExpiresAt(now, time.Duration(ttlMinutes)*time.Minute)
+	}
 	reservation := &domain.Reservation{
 		ID:         uuid.New().String(),
 		ProductID:  productID,
@@ -83,19 +257,34 @@ func (s *ReservationService) CreateReservation(ctx context.Context, productID, s
 		CustomerID: customerID,
 		Quantity:   quantity,
 		Status:     domain.ReservationStatusPending,
+		Priority:   priority,
+		Channel:    channel,
 		ExpiresAt:  expiresAt,
-		CreatedAt:  time.Now(),
+		CreatedAt:  now,
+	}
+
+	if preorder {
+		// En una preventa expires_at solo guarda el TTL pedido: se recalcula al asignarla
+		reservation.Status = domain.ReservationStatusPreorder
+		if err := s.reservationRepo.CreatePreorder(ctx, reservation); err != nil {
+			return nil, err
+		}
+		s.emitEvent(ctx, domain.NewReservationLifecycleEvent(domain.EventPreorderCreated, reservation))
+		return reservation, nil
 	}
 
-	err = s.reservationRepo.Create(ctx, reservation)
+	err = s.reservationRepo.CreateWithinLimits(ctx, reservation, s.holdLimits)
 	if err != nil {
 		// Revertir reserva de stock
-		_ = s.stockRepo.ReleaseReservedStock(ctx, productID, storeID, quantity)
+		_ = s.stockRepo.ReleaseChannelStock(ctx, productID, storeID, channel, quantity)
+		if errors.Is(err, domain.ErrCustomerLimit) || errors.Is(err, domain.ErrConflict) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to create reservation: %w", err)
 	}
 
 	// Publicar evento
-	event := domain.NewReservationCreatedEvent(reservation.ID, productID, storeID, quantity)
+	event := domain.NewReservationLifecycleEvent(domain.EventReservationCreated, reservation)
 
 	// Persistir en BD
 	if err := s.eventRepo.Save(ctx, event); err != nil {
@@ -103,13 +292,33 @@ func (s *ReservationService) CreateReservation(ctx context.Context, productID, s
 	}
 
 	// Publicar a message broker
-	if err := s.publisher.Publish(ctx, event); err != nil {
+	if err := s.publisher.Publish(ctx, event); publishFailed(err) {
 		log.Printf("Warning: failed to publish reservation created event: %v", err)
 	}
 
 	return reservation, nil
 }
 
+// diagnoseShortage añade al error de falta de stock el diagnóstico de la fila (reservas que
+// caducan pronto y preventa posible). Si falla alguna consulta se devuelve el error sin él.
+func (s *ReservationService) diagnoseShortage(ctx context.Context, err error) error {
+	var insufficient *domain.InsufficientStockError
+	if !errors.As(err, &insufficient) {
+		return err
+	}
+
+	stock, stockErr := s.stockRepo.GetByProductAndStore(ctx, insufficient.ProductID, insufficient.StoreID)
+	pending, pendingErr := s.reservationRepo.GetPendingByExpiry(ctx, insufficient.ProductID, insufficient.StoreID)
+	inbound, preordered, capacityErr := s.reservationRepo.GetPreorderCapacity(ctx, insufficient.ProductID, insufficient.StoreID)
+	if diagErr := errors.Join(stockErr, pendingErr, capacityErr); diagErr != nil {
+		log.Printf("Warning: failed to diagnose stock shortage of product %s in store %s: %v", insufficient.ProductID, insufficient.StoreID, diagErr)
+		return err
+	}
+
+	insufficient.Shortage = domain.NewStockShortage(stock, insufficient.Available, insufficient.Requested, pending, inbound-preordered)
+	return err
+}
+
 // GetReservation obtiene una reserva por ID
 func (s *ReservationService) GetReservation(ctx context.Context, id string) (*domain.Reservation, error) {
 	return s.reservationRepo.GetByID(ctx, id)
@@ -117,18 +326,26 @@ func (s *ReservationService) GetReservation(ctx context.Context, id string) (*do
 
 // ConfirmReservation confirma una reserva (decrementa stock real)
 func (s *ReservationService) ConfirmReservation(ctx context.Context, reservationID string) error {
+	return s.ConfirmReservationWithReference(ctx, reservationID, "")
+}
+
+// ConfirmReservationWithReference confirma la reserva asociándola a la referencia de la venta
+// (ticket o pedido del sistema de caja), que se incluye en el evento reservation.confirmed
+func (s *ReservationService) ConfirmReservationWithReference(ctx context.Context, reservationID, referenceID string) error {
 	// Obtener reserva
 	reservation, err := s.reservationRepo.GetByID(ctx, reservationID)
 	if err != nil {
 		return err
 	}
 
+	if err := domain.AuthorizeStoreWrite(ctx, reservation.StoreID); err != nil {
+		return err
+	}
+
 	// Validar estado
-	if reservation.Status != domain.ReservationStatusPending {
-		return &domain.ValidationError{
-			Field:   "status",
-			Message: fmt.Sprintf("cannot confirm reservation with status: %s", reservation.Status),
-		}
+	transition, err := s.states.Begin(reservation, domain.ReservationActionConfirm)
+	if err != nil {
+		return err
 	}
 
 	// Validar expiración
@@ -139,79 +356,241 @@ func (s *ReservationService) ConfirmReservation(ctx context.Context, reservation
 		}
 	}
 
-	// Confirmar en stock (decrementa quantity y reserved)
-	err = s.stockRepo.ConfirmReservation(ctx, reservation.ProductID, reservation.StoreID, reservation.Quantity)
+	// El precio unitario del evento es el vigente en el momento de la confirmación
+	product, err := s.productRepo.GetByID(ctx, reservation.ProductID)
 	if err != nil {
-		return fmt.Errorf("failed to confirm in stock: %w", err)
+		return err
+	}
+	transition.Product = product
+	transition.ReferenceID = referenceID
+
+	// Hooks síncronos (p. ej. capturar el pago): si fallan la reserva sigue PENDING
+	confirmation := &domain.ReservationConfirmation{
+		ReservationID: reservation.ID,
+		ProductID:     reservation.ProductID,
+		SKU:           product.SKU,
+		StoreID:       reservation.StoreID,
+		CustomerID:    reservation.CustomerID,
+		Quantity:      reservation.Quantity,
+		UnitPrice:     product.Price,
+		ReferenceID:   referenceID,
+		ConfirmedAt:   transition.At,
+	}
+	if err := s.confirmHooks.runSync(ctx, confirmation); err != nil {
+		return err
+	}
+
+	// Actualizar estado y confirmar en stock (decrementa quantity y reserved) en una transacción
+	if err := s.reservationRepo.ApplyTransition(ctx, transition); err != nil {
+		return err
+	}
+
+	// Aplicar la transición (publica reservation.confirmed)
+	s.states.Complete(ctx, transition)
+	s.confirmHooks.runAsync(ctx, confirmation)
+
+	return nil
+}
+
+// MarkReadyForPickup marca una reserva pendiente como lista para recoger en su tienda: genera el
+// código de recogida (solo se devuelve aquí, en claro), sustituye la expiración por el plazo de
+// recogida y emite reservation.ready_for_pickup. Las unidades siguen reservadas hasta que el
+// cliente la recoge; si no lo hace en el plazo, el worker de expiración la caduca y las libera.
+func (s *ReservationService) MarkReadyForPickup(ctx context.Context, reservationID string) (*domain.Reservation, *domain.ReservationPickup, error) {
+	if s.pickupRepo == nil {
+		return nil, nil, fmt.Errorf("reservation pickup is not enabled")
 	}
 
-	// Actualizar estado de reserva
-	err = s.reservationRepo.UpdateStatus(ctx, reservationID, domain.ReservationStatusConfirmed)
+	reservation, err := s.reservationRepo.GetByID(ctx, reservationID)
 	if err != nil {
-		// Intentar revertir
-		_ = s.stockRepo.ReleaseReservedStock(ctx, reservation.ProductID, reservation.StoreID, reservation.Quantity)
-		return fmt.Errorf("failed to update reservation status: %w", err)
+		return nil, nil, err
 	}
 
-	// Publicar evento
-	event := domain.NewReservationConfirmedEvent(reservationID, reservation.ProductID, reservation.StoreID, reservation.Quantity)
+	if err := domain.AuthorizeStoreWrite(ctx, reservation.StoreID); err != nil {
+		return nil, nil, err
+	}
 
-	// Persistir en BD
-	if err := s.eventRepo.Save(ctx, event); err != nil {
-		log.Printf("Warning: failed to save reservation confirmed event: %v", err)
+	transition, err := s.states.Begin(reservation, domain.ReservationActionReadyForPickup)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Publicar a message broker
-	if err := s.publisher.Publish(ctx, event); err != nil {
-		log.Printf("Warning: failed to publish reservation confirmed event: %v", err)
+	if reservation.IsExpired() {
+		return nil, nil, &domain.ValidationError{
+			Field:   "expiresAt",
+			Message: "reservation has expired",
+		}
 	}
 
-	return nil
+	pickup, err := domain.NewReservationPickup(reservation, transition.At, s.pickupWindow)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Solo se aplica si sigue PENDING: una confirmación o expiración concurrente gana
+	marked, err := s.pickupRepo.MarkReady(ctx, pickup)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !marked {
+		return nil, nil, &domain.InvalidStateError{
+			CurrentState:    string(s.currentStatus(ctx, reservation)),
+			AttemptedAction: string(domain.ReservationActionReadyForPickup) + " reservation",
+		}
+	}
+	reservation.ExpiresAt = pickup.PickupBy
+
+	// Aplicar la transición (publica reservation.ready_for_pickup)
+	s.states.Complete(ctx, transition)
+
+	return reservation, pickup, nil
 }
 
-// CancelReservation cancela una reserva (libera stock reservado)
-func (s *ReservationService) CancelReservation(ctx context.Context, reservationID string) error {
-	// Obtener reserva
+// PickUpReservation entrega al cliente una reserva lista para recoger tras validar su código:
+// descuenta el stock como una confirmación y emite reservation.picked_up con los datos de la
+// venta. Cada código erróneo cuenta como intento; tras MaxPickupCodeAttempts la recogida queda
+// bloqueada y solo se puede cancelar.
+func (s *ReservationService) PickUpReservation(ctx context.Context, reservationID, code string) (*domain.Reservation, *domain.ReservationPickup, error) {
+	if s.pickupRepo == nil {
+		return nil, nil, fmt.Errorf("reservation pickup is not enabled")
+	}
+
 	reservation, err := s.reservationRepo.GetByID(ctx, reservationID)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	// Validar estado (solo se puede cancelar si está pending)
-	if reservation.Status != domain.ReservationStatusPending {
-		return &domain.ValidationError{
-			Field:   "status",
-			Message: fmt.Sprintf("cannot cancel reservation with status: %s", reservation.Status),
+	if err := domain.AuthorizeStoreWrite(ctx, reservation.StoreID); err != nil {
+		return nil, nil, err
+	}
+
+	transition, err := s.states.Begin(reservation, domain.ReservationActionPickUp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if reservation.IsExpired() {
+		return nil, nil, &domain.ValidationError{
+			Field:   "expiresAt",
+			Message: "pickup window has expired",
 		}
 	}
 
-	// Liberar stock reservado
-	err = s.stockRepo.ReleaseReservedStock(ctx, reservation.ProductID, reservation.StoreID, reservation.Quantity)
+	pickup, err := s.pickupRepo.GetByReservation(ctx, reservationID)
 	if err != nil {
-		return fmt.Errorf("failed to release reserved stock: %w", err)
+		return nil, nil, err
+	}
+	if pickup.Locked() {
+		return nil, nil, &domain.InvalidStateError{
+			CurrentState:    string(reservation.Status),
+			AttemptedAction: fmt.Sprintf("pick up reservation after %d invalid codes", pickup.Attempts),
+		}
+	}
+	if !pickup.Matches(code) {
+		attempts, err := s.pickupRepo.RecordFailedAttempt(ctx, reservationID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, &domain.ValidationError{
+			Field:   "code",
+			Message: fmt.Sprintf("invalid pickup code (%d attempts left)", max(domain.MaxPickupCodeAttempts-attempts, 0)),
+		}
 	}
 
-	// Actualizar estado
-	err = s.reservationRepo.UpdateStatus(ctx, reservationID, domain.ReservationStatusCancelled)
+	// El precio unitario del evento es el vigente en el momento de la recogida
+	product, err := s.productRepo.GetByID(ctx, reservation.ProductID)
 	if err != nil {
-		// Intentar revertir
-		_ = s.stockRepo.ReserveStock(ctx, reservation.ProductID, reservation.StoreID, reservation.Quantity)
-		return fmt.Errorf("failed to update reservation status: %w", err)
+		return nil, nil, err
 	}
+	transition.Product = product
 
-	// Publicar evento
-	event := domain.NewReservationCancelledEvent(reservationID, reservation.ProductID, reservation.StoreID, reservation.Quantity)
+	// Actualizar estado y descontar del stock (decrementa quantity y reserved) en una transacción
+	if err := s.reservationRepo.ApplyTransition(ctx, transition); err != nil {
+		return nil, nil, err
+	}
 
-	// Persistir en BD
-	if err := s.eventRepo.Save(ctx, event); err != nil {
-		log.Printf("Warning: failed to save reservation cancelled event: %v", err)
+	// La venta ya está hecha: un fallo aquí solo deja la recogida sin fecha
+	if err := s.pickupRepo.MarkPickedUp(ctx, reservationID, transition.At); err != nil {
+		log.Printf("Warning: %v", err)
+	} else {
+		pickup.PickedUpAt = &transition.At
 	}
 
-	// Publicar a message broker
-	if err := s.publisher.Publish(ctx, event); err != nil {
-		log.Printf("Warning: failed to publish reservation cancelled event: %v", err)
+	// Aplicar la transición (publica reservation.picked_up)
+	s.states.Complete(ctx, transition)
+
+	return reservation, pickup, nil
+}
+
+// currentStatus relee el estado de la reserva (el conocido si no se puede leer)
+func (s *ReservationService) currentStatus(ctx context.Context, reservation *domain.Reservation) domain.ReservationStatus {
+	current, err := s.reservationRepo.GetByID(ctx, reservation.ID)
+	if err != nil {
+		return reservation.Status
+	}
+	return current.Status
+}
+
+// storeHours obtiene el horario de la tienda (nil si no tiene o no está habilitado)
+func (s *ReservationService) storeHours(ctx context.Context, storeID string) (*domain.StoreHours, error) {
+	if s.hoursRepo == nil {
+		return nil, nil
+	}
+
+	hours, err := s.hoursRepo.GetByStore(ctx, storeID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return hours, nil
+}
+
+// storeMetadata obtiene la tienda para el payload de los eventos de venta (nil si no está registrada)
+func (s *ReservationService) storeMetadata(ctx context.Context, storeID string) *domain.Store {
+	if s.storeRepo == nil {
+		return nil
+	}
+
+	store, err := s.storeRepo.GetByID(ctx, storeID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			log.Printf("Warning: failed to load store %s for event metadata: %v", storeID, err)
+		}
+		return nil
+	}
+
+	return store
+}
+
+// CancelReservation cancela una reserva (libera stock reservado)
+func (s *ReservationService) CancelReservation(ctx context.Context, reservationID string) error {
+	// Obtener reserva
+	reservation, err := s.reservationRepo.GetByID(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+
+	if err := domain.AuthorizeStoreWrite(ctx, reservation.StoreID); err != nil {
+		return err
+	}
+
+	// Validar estado (solo se puede cancelar si está pending o es una preventa)
+	transition, err := s.states.Begin(reservation, domain.ReservationActionCancel)
+	if err != nil {
+		return err
+	}
+
+	// Actualizar estado y liberar stock reservado en una transacción (una preventa sin asignar
+	// no tiene stock reservado)
+	if err := s.reservationRepo.ApplyTransition(ctx, transition); err != nil {
+		return err
 	}
 
+	// Aplicar la transición (publica reservation.cancelled)
+	s.states.Complete(ctx, transition)
+
 	return nil
 }
 
@@ -232,69 +611,213 @@ func (s *ReservationService) ExpireReservation(ctx context.Context, reservationI
 	}
 
 	// Validar estado
-	if reservation.Status != domain.ReservationStatusPending {
+	if reservation.Status.IsFinal() {
 		return nil // Ya fue procesada
 	}
 
-	// Liberar stock
-	err = s.stockRepo.ReleaseReservedStock(ctx, reservation.ProductID, reservation.StoreID, reservation.Quantity)
+	return s.expire(ctx, reservation)
+}
+
+// expire libera el stock de una reserva pendiente, la marca como EXPIRED y emite reservation.expired
+func (s *ReservationService) expire(ctx context.Context, reservation *domain.Reservation) error {
+	transition, err := s.states.Begin(reservation, domain.ReservationActionExpire)
 	if err != nil {
-		return fmt.Errorf("failed to release reserved stock: %w", err)
+		return err
+	}
+
+	// Marcar como expirada y liberar stock en una transacción
+	if err := s.reservationRepo.ApplyTransition(ctx, transition); err != nil {
+		return err
+	}
+
+	// Aplicar la transición (publica reservation.expired)
+	s.states.Complete(ctx, transition)
+
+	return nil
+}
+
+// AllocatePreorders asigna stock real a las preventas de una fila de stock por orden de llegada
+// (FIFO). Cada preventa asignada reserva sus unidades, pasa a PENDING con el TTL que se pidió
+// al crearla y emite reservation.preorder_allocated; desde entonces se puede confirmar. Se detiene
+// en la primera que no cabe en la cantidad vendible, para que ninguna adelante a otra anterior.
+// Retorna las preventas asignadas.
+func (s *ReservationService) AllocatePreorders(ctx context.Context, productID, storeID string) ([]*domain.Reservation, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
 	}
 
-	// Marcar como expirada
-	err = s.reservationRepo.UpdateStatus(ctx, reservationID, domain.ReservationStatusExpired)
+	preorders, err := s.reservationRepo.GetPreordersFIFO(ctx, productID, storeID)
 	if err != nil {
-		_ = s.stockRepo.ReserveStock(ctx, reservation.ProductID, reservation.StoreID, reservation.Quantity)
-		return fmt.Errorf("failed to update reservation status: %w", err)
+		return nil, err
 	}
 
-	// Publicar evento
-	event := domain.NewReservationExpiredEvent(reservationID, reservation.ProductID, reservation.StoreID, reservation.Quantity)
+	var allocated []*domain.Reservation
+	for _, reservation := range preorders {
+		transition, err := s.states.Begin(reservation, domain.ReservationActionAllocate)
+		if err != nil {
+			return allocated, err
+		}
 
-	// Persistir en BD
-	if err := s.eventRepo.Save(ctx, event); err != nil {
-		log.Printf("Warning: failed to save reservation expired event: %v", err)
+		err = s.stockRepo.ReserveChannelStock(ctx, productID, storeID, reservation.Channel, reservation.Quantity)
+		if err != nil {
+			if errors.Is(err, domain.ErrInsufficientStock) {
+				break
+			}
+			return allocated, err
+		}
+
+		expiresAt := transition.At.Add(reservation.ExpiresAt.Sub(reservation.CreatedAt))
+		ok, err := s.reservationRepo.AllocatePreorder(ctx, reservation.ID, expiresAt)
+		if err != nil || !ok {
+			// Revertir: falló o se canceló mientras tanto
+			_ = s.stockRepo.ReleaseChannelStock(ctx, productID, storeID, reservation.Channel, reservation.Quantity)
+			if err != nil {
+				return allocated, err
+			}
+			continue
+		}
+
+		reservation.ExpiresAt = expiresAt
+		s.states.Complete(ctx, transition)
+		allocated = append(allocated, reservation)
 	}
 
-	// Publicar a message broker
-	if err := s.publisher.Publish(ctx, event); err != nil {
-		log.Printf("Warning: failed to publish reservation expired event: %v", err)
+	return allocated, nil
+}
+
+// ReleaseByPriority cancela reservas pendientes de un producto en una tienda hasta liberar al
+// menos units unidades, empezando por las de menor prioridad (y, a igual prioridad, las más
+// recientes). Retorna las reservas canceladas.
+func (s *ReservationService) ReleaseByPriority(ctx context.Context, productID, storeID string, units int) ([]*domain.Reservation, error) {
+	pending, err := s.reservationRepo.GetPendingByReleaseOrder(ctx, productID, storeID)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	var released []*domain.Reservation
+	freed := 0
+	for _, reservation := range pending {
+		if freed >= units {
+			break
+		}
+		if err := s.CancelReservation(ctx, reservation.ID); err != nil {
+			return released, err
+		}
+		reservation.Status = domain.ReservationStatusCancelled
+		released = append(released, reservation)
+		freed += reservation.Quantity
+	}
+
+	return released, nil
 }
 
 // ProcessExpiredReservations procesa todas las reservas expiradas (llamado por worker)
 func (s *ReservationService) ProcessExpiredReservations(ctx context.Context) (int, error) {
+	run, err := s.RunExpiration(ctx, false)
+	if run == nil {
+		return 0, err
+	}
+	return run.Expired, err
+}
+
+// RunExpiration ejecuta una pasada de expiración: las reservas pendientes que superaron su TTL y,
+// con shortageGrace, las LOW de productos sin stock vendible. Con dryRun no modifica nada y
+// retorna las reservas que se expirarían, teniendo en cuenta las unidades que liberarían las
+// anteriores de la misma pasada.
+func (s *ReservationService) RunExpiration(ctx context.Context, dryRun bool) (*domain.ReservationExpirationRun, error) {
+	run := &domain.ReservationExpirationRun{
+		DryRun:       dryRun,
+		RanAt:        time.Now(),
+		Reservations: []*domain.ReservationExpiration{},
+	}
+
 	// Obtener reservas expiradas
 	expired, err := s.reservationRepo.GetPendingExpired(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get expired reservations: %w", err)
+		return nil, fmt.Errorf("failed to get expired reservations: %w", err)
 	}
 
-	processedCount := 0
+	// Con dry-run el stock no cambia: se acumulan aquí las unidades que se liberarían por producto/tienda
+	released := make(map[string]int)
+	seen := make(map[string]bool)
 	for _, reservation := range expired {
-		err := s.ExpireReservation(ctx, reservation.ID)
-		if err != nil {
+		result := domain.NewReservationExpiration(reservation, domain.ExpirationReasonTTL)
+		seen[reservation.ID] = true
+		if dryRun {
+			released[reservation.ProductID+"|"+reservation.StoreID] += reservation.Quantity
+		} else if err := s.ExpireReservation(ctx, reservation.ID); err != nil {
 			// Log error pero continuar con las demás
 			log.Printf("Error expiring reservation %s: %v", reservation.ID, err)
-			continue
+			result.Error = err.Error()
+		}
+		run.Add(result)
+	}
+
+	if s.shortageGrace > 0 {
+		if err := s.expireLowPriorityUnderShortage(ctx, run, released, seen); err != nil {
+			return run, err
 		}
-		processedCount++
 	}
 
-	return processedCount, nil
+	return run, nil
 }
 
-// GetPendingByStore obtiene reservas pendientes de una tienda
-func (s *ReservationService) GetPendingByStore(ctx context.Context, storeID string) ([]*domain.Reservation, error) {
-	return s.reservationRepo.GetPendingByStore(ctx, storeID)
+// expireLowPriorityUnderShortage expira las reservas LOW más antiguas que shortageGrace de los
+// productos sin disponibilidad en su tienda. Se detiene en cada tienda en cuanto vuelve a haber
+// unidades disponibles, de modo que solo se libera lo necesario.
+func (s *ReservationService) expireLowPriorityUnderShortage(ctx context.Context, run *domain.ReservationExpirationRun, released map[string]int, seen map[string]bool) error {
+	candidates, err := s.reservationRepo.GetLowPriorityUnderShortage(ctx, time.Now().Add(-s.shortageGrace))
+	if err != nil {
+		return fmt.Errorf("failed to get low priority reservations: %w", err)
+	}
+
+	for _, reservation := range candidates {
+		if seen[reservation.ID] {
+			continue
+		}
+		key := reservation.ProductID + "|" + reservation.StoreID
+		stock, err := s.stockRepo.GetByProductAndStore(ctx, reservation.ProductID, reservation.StoreID)
+		if err != nil {
+			log.Printf("Error reading stock for reservation %s: %v", reservation.ID, err)
+			continue
+		}
+		if stock.Sellable()+released[key] > 0 {
+			continue
+		}
+
+		result := domain.NewReservationExpiration(reservation, domain.ExpirationReasonShortage)
+		if run.DryRun {
+			released[key] += reservation.Quantity
+		} else if err := s.expire(ctx, reservation); err != nil {
+			log.Printf("Error expiring low priority reservation %s: %v", reservation.ID, err)
+			result.Error = err.Error()
+		}
+		run.Add(result)
+	}
+
+	return nil
 }
 
-// GetReservationsByProduct obtiene reservas de un producto en una tienda
-func (s *ReservationService) GetReservationsByProduct(ctx context.Context, productID, storeID string, status *domain.ReservationStatus) ([]*domain.Reservation, error) {
-	return s.reservationRepo.GetByProductAndStore(ctx, productID, storeID, status)
+// ListReservations obtiene una página de reservas según el filtro y el total sin paginar
+func (s *ReservationService) ListReservations(ctx context.Context, filter domain.ReservationFilter) ([]*domain.Reservation, int, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	reservations, err := s.reservationRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.reservationRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if reservations == nil {
+		reservations = []*domain.Reservation{}
+	}
+	return reservations, total, nil
 }
 
 // CleanupOldReservations elimina reservas completadas/canceladas antiguas
@@ -310,25 +833,124 @@ func (s *ReservationService) CleanupOldReservations(ctx context.Context, daysOld
 	return s.reservationRepo.DeleteOldCompleted(ctx, olderThan)
 }
 
-// GetReservationStats obtiene estadísticas de reservas
-func (s *ReservationService) GetReservationStats(ctx context.Context) (map[string]int, error) {
-	stats := make(map[string]int)
+// GetReservationStats obtiene estadísticas de reservas, opcionalmente de un grupo de tiendas.
+// Los totales son históricos; las tasas y el tiempo medio de confirmación se
+// calculan sobre las reservas creadas dentro de la ventana indicada.
+func (s *ReservationService) GetReservationStats(ctx context.Context, window time.Duration, groupID string) (*domain.ReservationStats, error) {
+	if window <= 0 {
+		window = 24 * time.Hour
+	}
 
-	// Contar por estado
-	statuses := []domain.ReservationStatus{
+	groups, err := s.statsGroups(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	byStatus, err := s.reservationRepo.CountGroupedByStatus(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	byStore, err := s.reservationRepo.CountGroupedByStore(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Asegurar que todos los estados aparecen aunque tengan 0
+	for _, status := range []domain.ReservationStatus{
 		domain.ReservationStatusPending,
 		domain.ReservationStatusConfirmed,
 		domain.ReservationStatusCancelled,
 		domain.ReservationStatusExpired,
+		domain.ReservationStatusReadyForPickup,
+		domain.ReservationStatusPickedUp,
+	} {
+		if _, ok := byStatus[string(status)]; !ok {
+			byStatus[string(status)] = 0
+		}
 	}
 
-	for _, status := range statuses {
-		count, err := s.reservationRepo.CountByStatus(ctx, status)
-		if err != nil {
-			return nil, fmt.Errorf("failed to count reservations: %w", err)
+	stats := &domain.ReservationStats{
+		PendingReservations:   byStatus[string(domain.ReservationStatusPending)],
+		ConfirmedReservations: byStatus[string(domain.ReservationStatusConfirmed)],
+		CancelledReservations: byStatus[string(domain.ReservationStatusCancelled)],
+		ExpiredReservations:   byStatus[string(domain.ReservationStatusExpired)],
+		ByStatus:              byStatus,
+		ByStore:               byStore,
+		GroupID:               groupID,
+	}
+	for _, count := range byStatus {
+		stats.TotalReservations += count
+	}
+
+	if len(groups) > 0 {
+		stats.ByGroup = make(map[string]int, len(groups))
+		for _, group := range groups {
+			for _, storeID := range group.StoreIDs {
+				stats.ByGroup[group.ID] += byStore[storeID]
+			}
 		}
-		stats[string(status)] = count
 	}
 
+	since := time.Now().Add(-window)
+	recent, err := s.reservationRepo.GetCreatedSince(ctx, since, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Window = computeWindowStats(recent, window, since)
+
 	return stats, nil
 }
+
+// statsGroups retorna los grupos para by_group: el grupo filtrado (NotFoundError si no existe)
+// o todos. Sin repositorio de grupos no hay desglose por grupo.
+func (s *ReservationService) statsGroups(ctx context.Context, groupID string) ([]*domain.StoreGroup, error) {
+	if s.groupRepo == nil {
+		return nil, nil
+	}
+	if groupID != "" {
+		group, err := s.groupRepo.GetByID(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		return []*domain.StoreGroup{group}, nil
+	}
+	return s.groupRepo.List(ctx, "")
+}
+
+// computeWindowStats calcula las tasas de conversión/expiración de una ventana. Una recogida en
+// tienda (PICKED_UP) es una venta: cuenta como conversión igual que una confirmación.
+func computeWindowStats(reservations []*domain.Reservation, window time.Duration, since time.Time) domain.ReservationWindowStats {
+	ws := domain.ReservationWindowStats{
+		Duration: window.String(),
+		Since:    since,
+		Created:  len(reservations),
+	}
+
+	var totalConfirm time.Duration
+	confirmedWithTime := 0
+
+	for _, r := range reservations {
+		switch r.Status {
+		case domain.ReservationStatusConfirmed, domain.ReservationStatusPickedUp:
+			ws.Confirmed++
+			if r.ConfirmedAt != nil {
+				totalConfirm += r.ConfirmedAt.Sub(r.CreatedAt)
+				confirmedWithTime++
+			}
+		case domain.ReservationStatusExpired:
+			ws.Expired++
+		}
+	}
+
+	if confirmedWithTime > 0 {
+		ws.AvgTimeToConfirmSeconds = (totalConfirm / time.Duration(confirmedWithTime)).Seconds()
+	}
+	if ws.Created > 0 {
+		ws.ExpirationRate = float64(ws.Expired) / float64(ws.Created)
+		ws.ConversionRate = float64(ws.Confirmed) / float64(ws.Created)
+	}
+
+	return ws
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// ReservedReconciliationService detecta y corrige la deriva entre stock.reserved y las reservas
+// abiertas. reserved solo lo mueven los flujos de reserva; si un fallo entre la reserva y el
+// stock lo desajusta, el stock queda bloqueado (o sobrevendible) hasta corregirlo.
+type ReservedReconciliationService struct {
+	stockRepo *repository.StockRepository
+	eventRepo *repository.EventRepository
+	publisher EventPublisher
+}
+
+// NewReservedReconciliationService crea una nueva instancia del servicio
+func NewReservedReconciliationService(stockRepo *repository.StockRepository, eventRepo *repository.EventRepository, publisher EventPublisher) *ReservedReconciliationService {
+	return &ReservedReconciliationService{
+		stockRepo: stockRepo,
+		eventRepo: eventRepo,
+		publisher: publisher,
+	}
+}
+
+// ReconcileReserved recalcula reserved de cada (producto, tienda) como la suma de sus reservas
+// PENDING y reporta las diferencias (storeID vacío = todas las tiendas). Con apply las corrige:
+// cada corrección emite stock.reserved_corrected, que queda en la cadena de auditoría.
+func (s *ReservedReconciliationService) ReconcileReserved(ctx context.Context, storeID string, apply bool) (*domain.ReservedReconciliation, error) {
+	checked, discrepancies, err := s.stockRepo.FindReservedDiscrepancies(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ReservedReconciliation{
+		StoreID:       storeID,
+		Checked:       checked,
+		Discrepancies: discrepancies,
+		Applied:       apply,
+		ReconciledAt:  time.Now(),
+	}
+	if !apply {
+		return result, nil
+	}
+
+	actor := domain.ActorFromContext(ctx)
+	for _, d := range discrepancies {
+		expected, corrected, err := s.stockRepo.CorrectReserved(ctx, d.ProductID, d.StoreID, d.Reserved)
+		if err != nil {
+			return result, err
+		}
+		if !corrected {
+			log.Printf("⚠️  Reserved for %s/%s changed during reconciliation, skipped", d.ProductID, d.StoreID)
+			continue
+		}
+
+		d.Expected = expected
+		d.Difference = d.Reserved - expected
+		d.Corrected = true
+		result.Corrected++
+
+		event := domain.NewStockReservedCorrectedEvent(d.ProductID, d.StoreID, d.Reserved, expected, actor)
+		if err := s.eventRepo.Save(ctx, event); err != nil {
+			return result, err
+		}
+		if err := s.publisher.Publish(ctx, event); publishFailed(err) {
+			log.Printf("Warning: failed to publish event %s: %v", event.ID, err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// retentionBatchSize filas borradas (y archivadas en un mismo fichero) por lote
+const retentionBatchSize = 1000
+
+// purgeOptions controla el ritmo de un borrado por lotes
+type purgeOptions struct {
+	batchSize  int
+	maxBatches int           // 0 = hasta terminar
+	pause      time.Duration // Espera entre lotes para no acaparar la BD
+}
+
+// purgeProgress lotes y filas borradas de una pasada
+type purgeProgress struct {
+	batches int
+	deleted int64
+}
+
+// RetentionService borra los datos históricos que superan su ventana de retención y,
+// opcionalmente, los archiva antes como JSONL comprimido
+type RetentionService struct {
+	eventRepo       *repository.EventRepository
+	reservationRepo *repository.ReservationRepository
+	policies        []domain.RetentionPolicy
+	archive         domain.BlobStore // nil = borrar sin archivar
+
+	// Límites de las limpiezas bajo demanda (POST /admin/cleanup/:table)
+	cleanupMaxBatchSize int
+	cleanupPause        time.Duration
+
+	mu       sync.Mutex
+	stats    map[domain.RetentionTable]*domain.RetentionTableStats
+	cleaning map[domain.RetentionTable]bool
+}
+
+// NewRetentionService crea una nueva instancia del servicio. Las tablas con MaxAge <= 0 se conservan siempre.
+func NewRetentionService(eventRepo *repository.EventRepository, reservationRepo *repository.ReservationRepository, policies []domain.RetentionPolicy) *RetentionService {
+	stats := make(map[domain.RetentionTable]*domain.RetentionTableStats)
+	var active []domain.RetentionPolicy
+	for _, policy := range policies {
+		if policy.MaxAge <= 0 {
+			continue
+		}
+		active = append(active, policy)
+		stats[policy.Table] = &domain.RetentionTableStats{Table: policy.Table, MaxAge: policy.MaxAge}
+	}
+
+	return &RetentionService{
+		eventRepo:           eventRepo,
+		reservationRepo:     reservationRepo,
+		policies:            active,
+		cleanupMaxBatchSize: retentionBatchSize,
+		stats:               stats,
+		cleaning:            make(map[domain.RetentionTable]bool),
+	}
+}
+
+// SetCleanupLimits configura el tamaño de lote máximo y la pausa entre lotes de las limpiezas bajo demanda
+func (s *RetentionService) SetCleanupLimits(maxBatchSize int, pause time.Duration) {
+	s.cleanupMaxBatchSize = maxBatchSize
+	s.cleanupPause = pause
+}
+
+// SetArchiveStore activa el archivo de las filas antes de borrarlas
+func (s *RetentionService) SetArchiveStore(store domain.BlobStore) {
+	s.archive = store
+}
+
+// Enabled indica si alguna tabla tiene ventana de retención
+func (s *RetentionService) Enabled() bool {
+	return len(s.policies) > 0
+}
+
+// ApplyRetention aplica la ventana de retención de cada tabla y retorna las filas borradas por tabla.
+// Si el archivo de un lote falla, ese lote no se borra y la tabla se reintenta en la siguiente ejecución.
+func (s *RetentionService) ApplyRetention(ctx context.Context) (map[domain.RetentionTable]int64, error) {
+	now := time.Now()
+	deleted := make(map[domain.RetentionTable]int64)
+
+	for _, policy := range s.policies {
+		olderThan := now.Add(-policy.MaxAge)
+
+		progress, err := s.purge(ctx, policy.Table, olderThan, purgeOptions{batchSize: retentionBatchSize})
+
+		deleted[policy.Table] = progress.deleted
+		s.recordRun(policy.Table, now)
+		if err != nil {
+			return deleted, fmt.Errorf("retention of %s: %w", policy.Table, err)
+		}
+	}
+
+	return deleted, nil
+}
+
+// Cleanup ejecuta bajo demanda la retención de una tabla con el corte, el tamaño de lote y el
+// número de lotes de la petición, esperando la pausa configurada entre lotes. En dry run solo
+// cuenta las filas. Archiva igual que el worker si el archivo está activo. Solo se permite una
+// limpieza a la vez por tabla (ConflictError). Si el context se cancela entre lotes retorna
+// lo borrado hasta entonces. Sin BatchSize se usa el lote del worker (acotado al máximo).
+func (s *RetentionService) Cleanup(ctx context.Context, req domain.CleanupRequest) (*domain.CleanupResult, error) {
+	if req.BatchSize == 0 {
+		req.BatchSize = min(retentionBatchSize, s.cleanupMaxBatchSize)
+	}
+	if err := req.Validate(s.cleanupMaxBatchSize); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.cleaning[req.Table] {
+		s.mu.Unlock()
+		return nil, &domain.ConflictError{Message: fmt.Sprintf("a cleanup of %s is already running", req.Table)}
+	}
+	s.cleaning[req.Table] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.cleaning, req.Table)
+		s.mu.Unlock()
+	}()
+
+	start := time.Now()
+	matched, err := s.countPurgeable(ctx, req.Table, req.OlderThan)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.CleanupResult{
+		Table:     req.Table,
+		OlderThan: req.OlderThan,
+		DryRun:    req.DryRun,
+		Matched:   matched,
+		Remaining: matched,
+		BatchSize: req.BatchSize,
+	}
+	if !req.DryRun && matched > 0 {
+		progress, err := s.purge(ctx, req.Table, req.OlderThan, purgeOptions{
+			batchSize:  req.BatchSize,
+			maxBatches: req.MaxBatches,
+			pause:      s.cleanupPause,
+		})
+		result.Batches = progress.batches
+		result.Deleted = progress.deleted
+		if err != nil && !errors.Is(err, ctx.Err()) {
+			return nil, fmt.Errorf("cleanup of %s after %d rows: %w", req.Table, progress.deleted, err)
+		}
+		if result.Remaining, err = s.countPurgeable(context.WithoutCancel(ctx), req.Table, req.OlderThan); err != nil {
+			return nil, err
+		}
+	}
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+	return result, nil
+}
+
+// countPurgeable cuenta las filas que la retención borraría con el corte olderThan
+func (s *RetentionService) countPurgeable(ctx context.Context, table domain.RetentionTable, olderThan time.Time) (int64, error) {
+	if table == domain.RetentionReservations {
+		return s.reservationRepo.CountTerminalBefore(ctx, olderThan)
+	}
+	boundary, err := s.eventRepo.GetRetentionBoundary(ctx, olderThan)
+	if err != nil || boundary <= 0 {
+		return 0, err
+	}
+	return s.eventRepo.CountChainPrefix(ctx, boundary)
+}
+
+// purge borra por lotes las filas de la tabla anteriores a olderThan
+func (s *RetentionService) purge(ctx context.Context, table domain.RetentionTable, olderThan time.Time, opts purgeOptions) (purgeProgress, error) {
+	switch table {
+	case domain.RetentionEvents:
+		return s.purgeEvents(ctx, olderThan, opts)
+	case domain.RetentionReservations:
+		return s.purgeReservations(ctx, olderThan, opts)
+	}
+	return purgeProgress{}, fmt.Errorf("unknown retention table: %s", table)
+}
+
+// nextBatch indica si hay que seguir con otro lote, esperando antes la pausa entre lotes
+func (opts purgeOptions) nextBatch(ctx context.Context, progress purgeProgress) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if progress.batches == 0 || opts.pause <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(opts.pause)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// done indica si se alcanzó el número máximo de lotes
+func (opts purgeOptions) done(progress purgeProgress) bool {
+	return opts.maxBatches > 0 && progress.batches >= opts.maxBatches
+}
+
+// Stats retorna las filas recuperadas por tabla desde que arrancó el proceso
+func (s *RetentionService) Stats() []domain.RetentionTableStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]domain.RetentionTableStats, 0, len(s.stats))
+	for _, table := range domain.RetentionTables {
+		if tableStats, ok := s.stats[table]; ok {
+			stats = append(stats, *tableStats)
+		}
+	}
+	return stats
+}
+
+// purgeEvents borra por lotes el prefijo de la cadena anterior a olderThan. Cada lote guarda un
+// checkpoint con el último registro borrado para que la cadena siga siendo verificable.
+func (s *RetentionService) purgeEvents(ctx context.Context, olderThan time.Time, opts purgeOptions) (purgeProgress, error) {
+	var progress purgeProgress
+	boundary, err := s.eventRepo.GetRetentionBoundary(ctx, olderThan)
+	if err != nil || boundary <= 0 {
+		return progress, err
+	}
+
+	for !opts.done(progress) {
+		if err := opts.nextBatch(ctx, progress); err != nil {
+			return progress, err
+		}
+
+		events, err := s.eventRepo.ListChainPrefix(ctx, boundary, opts.batchSize)
+		if err != nil {
+			return progress, err
+		}
+		if len(events) == 0 {
+			return progress, nil
+		}
+
+		if err := s.archiveBatch(ctx, domain.RetentionEvents, len(events), func(enc *json.Encoder) error {
+			for _, event := range events {
+				if err := enc.Encode(event); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return progress, err
+		}
+
+		last := events[len(events)-1]
+		deleted, err := s.eventRepo.DeleteChainPrefix(ctx, domain.AuditCheckpoint{
+			Seq:       last.Seq,
+			Hash:      last.Hash,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return progress, err
+		}
+		progress.batches++
+		progress.deleted += deleted
+		s.recordDeleted(domain.RetentionEvents, deleted)
+	}
+	return progress, nil
+}
+
+// purgeReservations borra por lotes las reservas terminadas anteriores a olderThan
+func (s *RetentionService) purgeReservations(ctx context.Context, olderThan time.Time, opts purgeOptions) (purgeProgress, error) {
+	var progress purgeProgress
+	for !opts.done(progress) {
+		if err := opts.nextBatch(ctx, progress); err != nil {
+			return progress, err
+		}
+
+		reservations, err := s.reservationRepo.ListTerminalBefore(ctx, olderThan, opts.batchSize)
+		if err != nil {
+			return progress, err
+		}
+		if len(reservations) == 0 {
+			return progress, nil
+		}
+
+		if err := s.archiveBatch(ctx, domain.RetentionReservations, len(reservations), func(enc *json.Encoder) error {
+			for _, reservation := range reservations {
+				if err := enc.Encode(reservation); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return progress, err
+		}
+
+		ids := make([]string, len(reservations))
+		for i, reservation := range reservations {
+			ids[i] = reservation.ID
+		}
+		deleted, err := s.reservationRepo.DeleteByIDs(ctx, ids)
+		if err != nil {
+			return progress, err
+		}
+		progress.batches++
+		progress.deleted += deleted
+		s.recordDeleted(domain.RetentionReservations, deleted)
+	}
+	return progress, nil
+}
+
+// archiveBatch escribe un lote como JSONL comprimido en archive/<tabla>/<timestamp>.jsonl.gz.
+// No hace nada si el archivo está desactivado.
+func (s *RetentionService) archiveBatch(ctx context.Context, table domain.RetentionTable, rows int, write func(*json.Encoder) error) error {
+	if s.archive == nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := write(json.NewEncoder(gz)); err != nil {
+		return fmt.Errorf("failed to encode archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress archive: %w", err)
+	}
+
+	key := fmt.Sprintf("archive/%s/%s.jsonl.gz", table, time.Now().UTC().Format("20060102T150405.000000000Z"))
+	if _, err := s.archive.Put(ctx, key, "application/gzip", bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil {
+		return fmt.Errorf("failed to store archive %s: %w", key, err)
+	}
+	log.Printf("🗄️  Archived %d %s to %s", rows, table, key)
+
+	s.mu.Lock()
+	if stats, ok := s.stats[table]; ok {
+		stats.Archived += int64(rows)
+		stats.Files++
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// recordDeleted suma las filas borradas a las estadísticas (solo tablas con ventana de retención)
+func (s *RetentionService) recordDeleted(table domain.RetentionTable, deleted int64) {
+	s.mu.Lock()
+	if stats, ok := s.stats[table]; ok {
+		stats.Deleted += deleted
+	}
+	s.mu.Unlock()
+}
+
+func (s *RetentionService) recordRun(table domain.RetentionTable, at time.Time) {
+	s.mu.Lock()
+	s.stats[table].LastRunAt = &at
+	s.mu.Unlock()
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// RunDownService gestiona la descatalogación de productos: bloquea la reposición,
+// deja que cada tienda venda (y reserve) hasta agotar su stock y archiva la entrada
+// de surtido cuando ya no quedan unidades ni reservas.
+type RunDownService struct {
+	rundownRepo *repository.RunDownRepository
+	stockRepo   *repository.StockRepository
+	productRepo *repository.ProductRepository
+	eventRepo   *repository.EventRepository
+	publisher   domain.EventPublisher
+}
+
+// NewRunDownService crea una nueva instancia del servicio
+func NewRunDownService(
+	rundownRepo *repository.RunDownRepository,
+	stockRepo *repository.StockRepository,
+	productRepo *repository.ProductRepository,
+	eventRepo *repository.EventRepository,
+	publisher domain.EventPublisher,
+) *RunDownService {
+	return &RunDownService{
+		rundownRepo: rundownRepo,
+		stockRepo:   stockRepo,
+		productRepo: productRepo,
+		eventRepo:   eventRepo,
+		publisher:   publisher,
+	}
+}
+
+// Discontinue descataloga un producto y genera el plan de run-down con una entrada por tienda.
+// Las tiendas sin stock ni reservas se archivan inmediatamente.
+func (s *RunDownService) Discontinue(ctx context.Context, productID, reason string) (*domain.ProductRunDown, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	discontinued, err := s.rundownRepo.IsDiscontinued(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if discontinued {
+		return nil, &domain.ConflictError{
+			Message: fmt.Sprintf("product %s is already discontinued", productID),
+		}
+	}
+
+	stocks, err := s.stockRepo.GetAllByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	rundown := &domain.ProductRunDown{
+		ProductID: productID,
+		Status:    domain.RunDownStatusInProgress,
+		Reason:    reason,
+		Stores:    make([]domain.RunDownStoreEntry, 0, len(stocks)),
+		CreatedAt: time.Now(),
+	}
+	for _, stock := range stocks {
+		rundown.Stores = append(rundown.Stores, domain.RunDownStoreEntry{
+			StoreID:         stock.StoreID,
+			InitialQuantity: stock.Quantity,
+			Quantity:        stock.Quantity,
+			Reserved:        stock.Reserved,
+			Status:          domain.RunDownEntryRunningDown,
+		})
+	}
+
+	if err := s.rundownRepo.Create(ctx, rundown); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range rundown.Stores {
+		s.publishEvent(ctx, "product.discontinued", rundown, entry)
+	}
+
+	if err := s.advance(ctx, rundown); err != nil {
+		return nil, err
+	}
+
+	rundown.CalculateProgress()
+	return rundown, nil
+}
+
+// GetRunDown obtiene el estado del plan con el stock actual de cada tienda.
+// Antes de responder archiva las tiendas que ya agotaron su stock.
+func (s *RunDownService) GetRunDown(ctx context.Context, productID string) (*domain.ProductRunDown, error) {
+	rundown, err := s.rundownRepo.GetByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.advance(ctx, rundown); err != nil {
+		return nil, err
+	}
+
+	rundown.CalculateProgress()
+	return rundown, nil
+}
+
+// ProcessRunDowns avanza todos los planes en curso (usado por el worker periódico).
+// Retorna el número de entradas de surtido archivadas.
+func (s *RunDownService) ProcessRunDowns(ctx context.Context) (int, error) {
+	productIDs, err := s.rundownRepo.ListInProgress(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for _, productID := range productIDs {
+		rundown, err := s.rundownRepo.GetByProduct(ctx, productID)
+		if err != nil {
+			log.Printf("Warning: failed to load rundown for product %s: %v", productID, err)
+			continue
+		}
+
+		rundown.CalculateProgress()
+		before := rundown.Progress.ArchivedStores
+
+		if err := s.advance(ctx, rundown); err != nil {
+			log.Printf("Warning: failed to advance rundown for product %s: %v", productID, err)
+			continue
+		}
+
+		rundown.CalculateProgress()
+		archived += rundown.Progress.ArchivedStores - before
+	}
+
+	return archived, nil
+}
+
+// advance refresca el stock de cada entrada en run-down, archiva las agotadas
+// y completa el plan cuando todas las tiendas están archivadas
+func (s *RunDownService) advance(ctx context.Context, rundown *domain.ProductRunDown) error {
+	stocks, err := s.stockRepo.GetAllByProduct(ctx, rundown.ProductID)
+	if err != nil {
+		return err
+	}
+
+	byStore := make(map[string]*domain.Stock, len(stocks))
+	for _, stock := range stocks {
+		byStore[stock.StoreID] = stock
+	}
+
+	for i := range rundown.Stores {
+		entry := &rundown.Stores[i]
+		if entry.Status == domain.RunDownEntryArchived {
+			entry.Quantity, entry.Reserved = 0, 0
+			continue
+		}
+
+		stock, ok := byStore[entry.StoreID]
+		if ok {
+			entry.Quantity = stock.Quantity
+			entry.Reserved = stock.Reserved
+			if stock.Quantity > 0 || stock.Reserved > 0 {
+				continue
+			}
+
+			// El DELETE vuelve a comprobar que no haya stock ni reservas (evita carreras con reservas nuevas)
+			deleted, err := s.stockRepo.DeleteIfEmpty(ctx, rundown.ProductID, entry.StoreID)
+			if err != nil {
+				return err
+			}
+			if !deleted {
+				continue
+			}
+		}
+
+		now := time.Now()
+		if err := s.rundownRepo.ArchiveStore(ctx, rundown.ProductID, entry.StoreID, now); err != nil {
+			return err
+		}
+		entry.Status = domain.RunDownEntryArchived
+		entry.ArchivedAt = &now
+		entry.Quantity, entry.Reserved = 0, 0
+
+		s.publishEvent(ctx, "product.archived", rundown, *entry)
+	}
+
+	if rundown.Status == domain.RunDownStatusInProgress && rundown.AllArchived() {
+		now := time.Now()
+		if err := s.rundownRepo.Complete(ctx, rundown.ProductID, now); err != nil {
+			return err
+		}
+		rundown.Status = domain.RunDownStatusCompleted
+		rundown.CompletedAt = &now
+	}
+
+	return nil
+}
+
+func (s *RunDownService) publishEvent(ctx context.Context, eventType string, rundown *domain.ProductRunDown, entry domain.RunDownStoreEntry) {
+	event := domain.NewProductRunDownEvent(eventType, rundown, entry)
+
+	// Persistir en BD
+	if err := s.eventRepo.Save(ctx, event); err != nil {
+		log.Printf("Warning: failed to save %s event: %v", eventType, err)
+	}
+
+	// Publicar a message broker
+	if err := s.publisher.Publish(ctx, event); publishFailed(err) {
+		log.Printf("Warning: failed to publish %s event: %v", eventType, err)
+	}
+}
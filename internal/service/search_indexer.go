@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// searchReindexPageSize productos leídos e indexados por lote en Reindex
+const searchReindexPageSize = 200
+
+// SearchIndexer mantiene el índice de búsqueda externo a partir del stream de eventos.
+// Cada evento con product_id reconstruye el documento completo del producto desde la BD
+// (datos de catálogo y disponibilidad por tienda), así que el orden y las repeticiones de
+// eventos no importan: el índice converge al estado actual.
+type SearchIndexer struct {
+	index       domain.SearchIndex
+	backend     string
+	productRepo *repository.ProductRepository
+	stockRepo   *repository.StockRepository
+}
+
+// NewSearchIndexer crea una nueva instancia del indexador. index nil (SEARCH_BACKEND=none)
+// lo deja desactivado.
+func NewSearchIndexer(
+	index domain.SearchIndex,
+	backend string,
+	productRepo *repository.ProductRepository,
+	stockRepo *repository.StockRepository,
+) *SearchIndexer {
+	return &SearchIndexer{
+		index:       index,
+		backend:     backend,
+		productRepo: productRepo,
+		stockRepo:   stockRepo,
+	}
+}
+
+// Enabled indica si hay índice externo configurado
+func (i *SearchIndexer) Enabled() bool {
+	return i.index != nil
+}
+
+// HandleEvent reindexa el producto afectado por el evento (ignora eventos sin producto)
+func (i *SearchIndexer) HandleEvent(ctx context.Context, event *domain.Event) error {
+	var payload struct {
+		ProductID string `json:"product_id"`
+	}
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil || payload.ProductID == "" {
+		return nil
+	}
+	return i.IndexProduct(ctx, payload.ProductID)
+}
+
+// IndexProduct actualiza el documento del producto. Los productos eliminados o
+// descatalogados se quitan del índice.
+func (i *SearchIndexer) IndexProduct(ctx context.Context, productID string) error {
+	product, err := i.productRepo.GetByID(ctx, productID)
+	var notFound *domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return i.index.Delete(ctx, productID)
+	}
+	if err != nil {
+		return err
+	}
+	if product.Status == domain.ProductStatusDiscontinued {
+		return i.index.Delete(ctx, productID)
+	}
+
+	stocks, err := i.stockRepo.GetAllByProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	return i.index.Upsert(ctx, domain.NewProductSearchDocument(product, stocks))
+}
+
+// Reindex vuelve a indexar todos los productos no descatalogados, por lotes. Sirve para la
+// carga inicial y para recuperar eventos descartados; no borra documentos huérfanos, que
+// SearchProducts ya omite.
+func (i *SearchIndexer) Reindex(ctx context.Context) (*domain.SearchReindexResult, error) {
+	if !i.Enabled() {
+		return nil, &domain.ConflictError{
+			Message: "search index is disabled (SEARCH_BACKEND=none)",
+		}
+	}
+
+	result := &domain.SearchReindexResult{
+		Backend:   i.backend,
+		StartedAt: time.Now(),
+	}
+
+	for offset := 0; ; offset += searchReindexPageSize {
+		products, err := i.productRepo.List(ctx, searchReindexPageSize, offset, false)
+		if err != nil {
+			return nil, err
+		}
+
+		docs := make([]*domain.ProductSearchDocument, 0, len(products))
+		for _, product := range products {
+			stocks, err := i.stockRepo.GetAllByProduct(ctx, product.ID)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, domain.NewProductSearchDocument(product, stocks))
+		}
+		if err := i.index.Upsert(ctx, docs...); err != nil {
+			return nil, err
+		}
+		result.Indexed += len(docs)
+
+		if len(products) < searchReindexPageSize {
+			break
+		}
+	}
+
+	result.Duration = time.Since(result.StartedAt).Round(time.Millisecond).String()
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// SerialService maneja el registro de números de serie vendidos y su consulta para garantías
+type SerialService struct {
+	serialRepo      *repository.SerialRepository
+	reservationRepo *repository.ReservationRepository
+}
+
+// NewSerialService crea una nueva instancia del servicio
+func NewSerialService(
+	serialRepo *repository.SerialRepository,
+	reservationRepo *repository.ReservationRepository,
+) *SerialService {
+	return &SerialService{
+		serialRepo:      serialRepo,
+		reservationRepo: reservationRepo,
+	}
+}
+
+// RegisterSerials vincula números de serie a una reserva confirmada (venta).
+// No se pueden registrar más números de serie que unidades vendidas.
+func (s *SerialService) RegisterSerials(ctx context.Context, reservationID string, serialNumbers []string, warrantyMonths int) ([]*domain.SerialRegistration, error) {
+	if len(serialNumbers) == 0 {
+		return nil, &domain.ValidationError{
+			Field:   "serial_numbers",
+			Message: "at least one serial number is required",
+		}
+	}
+	if warrantyMonths < 0 {
+		return nil, &domain.ValidationError{
+			Field:   "warranty_months",
+			Message: "warranty months cannot be negative",
+		}
+	}
+
+	reservation, err := s.reservationRepo.GetByID(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reservation.Status != domain.ReservationStatusConfirmed {
+		return nil, &domain.InvalidStateError{
+			CurrentState:    string(reservation.Status),
+			AttemptedAction: "register serial numbers",
+		}
+	}
+
+	existing, err := s.serialRepo.GetByReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(existing)+len(serialNumbers) > reservation.Quantity {
+		return nil, &domain.ValidationError{
+			Field: "serial_numbers",
+			Message: fmt.Sprintf("cannot register %d serial numbers: reservation sold %d units and %d are already registered",
+				len(serialNumbers), reservation.Quantity, len(existing)),
+		}
+	}
+
+	soldAt := reservation.CreatedAt
+	if reservation.ConfirmedAt != nil {
+		soldAt = *reservation.ConfirmedAt
+	} else if reservation.UpdatedAt != nil {
+		soldAt = *reservation.UpdatedAt
+	}
+
+	var warrantyExpiresAt *time.Time
+	if warrantyMonths > 0 {
+		expires := soldAt.AddDate(0, warrantyMonths, 0)
+		warrantyExpiresAt = &expires
+	}
+
+	seen := make(map[string]bool)
+	registrations := make([]*domain.SerialRegistration, 0, len(serialNumbers))
+	for _, serial := range serialNumbers {
+		serial = strings.TrimSpace(serial)
+
+		if seen[serial] {
+			return nil, &domain.ValidationError{
+				Field:   "serial_numbers",
+				Message: fmt.Sprintf("duplicate serial number in request: %s", serial),
+			}
+		}
+		seen[serial] = true
+
+		sold, err := s.serialRepo.ExistsForProduct(ctx, reservation.ProductID, serial)
+		if err != nil {
+			return nil, err
+		}
+		if sold {
+			return nil, &domain.ConflictError{
+				Message: fmt.Sprintf("serial number %s is already registered for product %s", serial, reservation.ProductID),
+			}
+		}
+
+		reg := &domain.SerialRegistration{
+			ID:                uuid.New().String(),
+			SerialNumber:      serial,
+			ProductID:         reservation.ProductID,
+			StoreID:           reservation.StoreID,
+			ReservationID:     reservation.ID,
+			CustomerID:        reservation.CustomerID,
+			SoldAt:            soldAt,
+			WarrantyExpiresAt: warrantyExpiresAt,
+			CreatedAt:         time.Now(),
+		}
+		if err := reg.Validate(); err != nil {
+			return nil, err
+		}
+
+		registrations = append(registrations, reg)
+	}
+
+	if err := s.serialRepo.CreateBatch(ctx, registrations); err != nil {
+		return nil, err
+	}
+
+	return registrations, nil
+}
+
+// LookupSerial obtiene el registro de venta de un número de serie
+func (s *SerialService) LookupSerial(ctx context.Context, serialNumber string) ([]*domain.SerialRegistration, error) {
+	registrations, err := s.serialRepo.GetBySerial(ctx, serialNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(registrations) == 0 {
+		return nil, &domain.NotFoundError{Resource: "Serial", ID: serialNumber}
+	}
+
+	return registrations, nil
+}
+
+// GetReservationSerials obtiene los números de serie registrados para una reserva
+func (s *SerialService) GetReservationSerials(ctx context.Context, reservationID string) ([]*domain.SerialRegistration, error) {
+	if _, err := s.reservationRepo.GetByID(ctx, reservationID); err != nil {
+		return nil, err
+	}
+
+	return s.serialRepo.GetByReservation(ctx, reservationID)
+}
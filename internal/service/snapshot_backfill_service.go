@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// SnapshotBackfillService emite un evento stock.snapshot por cada fila de stock para que los
+// consumidores que se conectan tarde al pipeline de eventos partan de una línea base.
+type SnapshotBackfillService struct {
+	backfillRepo *repository.SnapshotBackfillRepository
+	stockRepo    *repository.StockRepository
+	eventRepo    *repository.EventRepository
+	publisher    EventPublisher
+}
+
+// NewSnapshotBackfillService crea una nueva instancia del servicio
+func NewSnapshotBackfillService(backfillRepo *repository.SnapshotBackfillRepository, stockRepo *repository.StockRepository, eventRepo *repository.EventRepository, publisher EventPublisher) *SnapshotBackfillService {
+	return &SnapshotBackfillService{
+		backfillRepo: backfillRepo,
+		stockRepo:    stockRepo,
+		eventRepo:    eventRepo,
+		publisher:    publisher,
+	}
+}
+
+// BackfillStockSnapshots recorre el stock en lotes de batchSize filas ordenadas por (producto, tienda)
+// y emite stock.snapshot para cada una. Reanuda el último backfill sin terminar o empieza uno nuevo.
+// El cursor se guarda después de cada lote: si el proceso se interrumpe, como mucho se repiten los
+// snapshots de un lote. Los eventos que no se pueden publicar quedan en el outbox para el worker de sync.
+func (s *SnapshotBackfillService) BackfillStockSnapshots(ctx context.Context, batchSize int) (*domain.SnapshotBackfill, error) {
+	if batchSize <= 0 {
+		batchSize = domain.DefaultSnapshotBackfillBatch
+	}
+
+	backfill, err := s.backfillRepo.GetRunning(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if backfill == nil {
+		now := time.Now()
+		backfill = &domain.SnapshotBackfill{
+			ID:        uuid.New().String(),
+			Status:    domain.JobStatusRunning,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := s.backfillRepo.Create(ctx, backfill); err != nil {
+			return nil, err
+		}
+	} else {
+		log.Printf("⏯️  Resuming snapshot backfill %s after %s/%s (%d emitted)",
+			backfill.ID, backfill.LastProductID, backfill.LastStoreID, backfill.Emitted)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return backfill, err
+		}
+
+		stocks, err := s.stockRepo.ListAfter(ctx, backfill.LastProductID, backfill.LastStoreID, batchSize)
+		if err != nil {
+			return backfill, err
+		}
+		if len(stocks) == 0 {
+			break
+		}
+
+		if err := s.emitSnapshots(ctx, backfill.ID, stocks); err != nil {
+			return backfill, err
+		}
+
+		last := stocks[len(stocks)-1]
+		backfill.LastProductID = last.ProductID
+		backfill.LastStoreID = last.StoreID
+		backfill.Emitted += len(stocks)
+		backfill.UpdatedAt = time.Now()
+		if err := s.backfillRepo.SaveProgress(ctx, backfill); err != nil {
+			return backfill, err
+		}
+		log.Printf("📸 Snapshot backfill %s: %d emitted", backfill.ID, backfill.Emitted)
+
+		if len(stocks) < batchSize {
+			break
+		}
+	}
+
+	completedAt := time.Now()
+	backfill.Status = domain.JobStatusCompleted
+	backfill.UpdatedAt = completedAt
+	backfill.CompletedAt = &completedAt
+	if err := s.backfillRepo.SaveProgress(ctx, backfill); err != nil {
+		return backfill, err
+	}
+
+	return backfill, nil
+}
+
+// emitSnapshots guarda los snapshots de un lote en el outbox y los publica. Solo se marcan como
+// sincronizados los publicados; el resto los reintenta el worker de sync.
+func (s *SnapshotBackfillService) emitSnapshots(ctx context.Context, backfillID string, stocks []*domain.Stock) error {
+	events := make([]*domain.Event, 0, len(stocks))
+	for _, stock := range stocks {
+		event := domain.NewStockSnapshotEvent(stock, backfillID)
+		if err := s.eventRepo.Save(ctx, event); err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+
+	published := make([]string, 0, len(events))
+	for _, event := range events {
+		err := s.publisher.Publish(ctx, event)
+		if errors.Is(err, domain.ErrPublisherUnavailable) {
+			// Circuito abierto: el resto del lote fallaría igual
+			break
+		}
+		if err != nil {
+			log.Printf("Warning: failed to publish snapshot event %s: %v", event.ID, err)
+			continue
+		}
+		published = append(published, event.ID)
+	}
+
+	if len(published) > 0 {
+		return s.eventRepo.MarkMultipleAsSynced(ctx, published)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// StockAdjustmentService aplica los ajustes manuales de stock (PUT /stock y POST /adjust) y deja
+// pendientes de aprobación los que superan los umbrales configurados: no cambian el stock hasta
+// que otra API key los aprueba. Cada paso emite su evento, que queda en la cadena de auditoría.
+type StockAdjustmentService struct {
+	adjustmentRepo *repository.StockAdjustmentRepository
+	stockService   *StockService
+	policy         domain.AdjustmentApprovalPolicy
+}
+
+// NewStockAdjustmentService crea una nueva instancia del servicio. Una política sin umbrales
+// aplica todos los ajustes directamente.
+func NewStockAdjustmentService(adjustmentRepo *repository.StockAdjustmentRepository, stockService *StockService, policy domain.AdjustmentApprovalPolicy) *StockAdjustmentService {
+	return &StockAdjustmentService{
+		adjustmentRepo: adjustmentRepo,
+		stockService:   stockService,
+		policy:         policy,
+	}
+}
+
+// AdjustStock suma adjustment a la fila de stock con un motivo obligatorio del catálogo. Si supera el umbral de aprobación no cambia el
+// stock y retorna el ajuste pendiente en lugar de la fila actualizada.
+func (s *StockAdjustmentService) AdjustStock(ctx context.Context, productID, storeID string, adjustment int, reason string) (*domain.Stock, *domain.StockAdjustment, error) {
+	return s.submit(ctx, &domain.StockAdjustment{
+		ProductID: productID,
+		StoreID:   storeID,
+		Type:      domain.StockAdjustmentAdjust,
+		Quantity:  adjustment,
+		Reason:    reason,
+	})
+}
+
+// UpdateStock fija la cantidad de la fila de stock, con la misma regla de aprobación que AdjustStock
+// (el motivo es opcional)
+func (s *StockAdjustmentService) UpdateStock(ctx context.Context, productID, storeID string, quantity int, reason string) (*domain.Stock, *domain.StockAdjustment, error) {
+	return s.submit(ctx, &domain.StockAdjustment{
+		ProductID: productID,
+		StoreID:   storeID,
+		Type:      domain.StockAdjustmentSet,
+		Quantity:  quantity,
+		Reason:    reason,
+	})
+}
+
+func (s *StockAdjustmentService) submit(ctx context.Context, adjustment *domain.StockAdjustment) (*domain.Stock, *domain.StockAdjustment, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, adjustment.StoreID); err != nil {
+		return nil, nil, err
+	}
+	if err := s.stockService.checkReason(ctx, adjustment.Reason, adjustment.Type == domain.StockAdjustmentAdjust); err != nil {
+		return nil, nil, err
+	}
+
+	stock, err := s.stockService.GetStockByProductAndStore(ctx, adjustment.ProductID, adjustment.StoreID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !s.policy.Requires(stock.Quantity, adjustment.Delta(stock.Quantity)) {
+		if adjustment.Type == domain.StockAdjustmentSet {
+			stock, err = s.stockService.updateStock(ctx, adjustment.ProductID, adjustment.StoreID, adjustment.Quantity, adjustment.Reason)
+		} else {
+			stock, err = s.stockService.adjustStock(ctx, adjustment.ProductID, adjustment.StoreID, adjustment.Quantity, adjustment.Reason)
+		}
+		return stock, nil, err
+	}
+
+	// Validar ya lo que se puede validar para no dejar pendiente un ajuste que no se podrá aplicar
+	newQuantity := adjustment.NewQuantity(stock.Quantity)
+	if err := s.stockService.checkOversellFloor(ctx, stock, newQuantity, "quantity"); err != nil {
+		return nil, nil, err
+	}
+	if newQuantity > stock.Quantity {
+		if err := s.stockService.ensureNotDiscontinued(ctx, adjustment.ProductID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	adjustment.ID = uuid.New().String()
+	adjustment.CurrentQuantity = stock.Quantity
+	adjustment.RequestedBy = domain.ActorFromContext(ctx)
+	adjustment.Status = domain.StockAdjustmentPending
+	adjustment.CreatedAt = time.Now()
+
+	if err := s.adjustmentRepo.Create(ctx, adjustment); err != nil {
+		return nil, nil, err
+	}
+
+	s.emit(ctx, domain.NewStockAdjustmentEvent(domain.EventAdjustmentRequested, adjustment))
+	log.Printf("📝 Stock adjustment %s of product %s in store %s (%d → %d) requested by %s awaits approval",
+		adjustment.ID, adjustment.ProductID, adjustment.StoreID, stock.Quantity, newQuantity, adjustment.RequestedBy)
+
+	return nil, adjustment, nil
+}
+
+// GetAdjustment obtiene un ajuste por ID
+func (s *StockAdjustmentService) GetAdjustment(ctx context.Context, id string) (*domain.StockAdjustment, error) {
+	return s.adjustmentRepo.Get(ctx, id)
+}
+
+// ListAdjustments lista los ajustes filtrados por estado y tienda (vacíos = todos)
+func (s *StockAdjustmentService) ListAdjustments(ctx context.Context, status domain.StockAdjustmentStatus, storeID string, limit, offset int) ([]*domain.StockAdjustment, error) {
+	return s.adjustmentRepo.List(ctx, status, storeID, limit, offset)
+}
+
+// ApproveAdjustment aplica un ajuste pendiente. El revisor (autor del context) debe ser una API
+// key distinta de la que lo solicitó. El ajuste se aplica sobre la cantidad actual: un ADJUST
+// suma sus unidades aunque la fila haya cambiado desde la solicitud y un SET fija la cantidad.
+// Si ya no se puede aplicar (stock negativo, producto descatalogado) sigue pendiente.
+func (s *StockAdjustmentService) ApproveAdjustment(ctx context.Context, id string) (*domain.StockAdjustment, *domain.Stock, error) {
+	adjustment, err := s.review(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	allowIncrease := true
+	if err := s.stockService.ensureNotDiscontinued(ctx, adjustment.ProductID); err != nil {
+		if !errors.Is(err, domain.ErrConflict) {
+			return nil, nil, err
+		}
+		allowIncrease = false
+	}
+
+	reviewer := domain.ActorFromContext(ctx)
+	oldQuantity, newQuantity, err := s.adjustmentRepo.Approve(ctx, adjustment, reviewer, time.Now(), allowIncrease)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.emit(ctx, domain.NewStockAdjustmentEvent(domain.EventAdjustmentApproved, adjustment))
+	s.emit(ctx, domain.NewStockUpdatedEventWithReason(adjustment.ProductID, adjustment.StoreID, oldQuantity, newQuantity, adjustment.Reason))
+	log.Printf("📦 Stock of product %s in store %s changed %d → %d (adjustment %s requested by %s, approved by %s)",
+		adjustment.ProductID, adjustment.StoreID, oldQuantity, newQuantity, adjustment.ID, adjustment.RequestedBy, reviewer)
+
+	stock, err := s.stockService.GetStockByProductAndStore(ctx, adjustment.ProductID, adjustment.StoreID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return adjustment, stock, nil
+}
+
+// RejectAdjustment descarta un ajuste pendiente sin tocar el stock. Como la aprobación, lo debe
+// revisar una API key distinta de la que lo solicitó.
+func (s *StockAdjustmentService) RejectAdjustment(ctx context.Context, id string) (*domain.StockAdjustment, error) {
+	adjustment, err := s.review(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.adjustmentRepo.Reject(ctx, adjustment, domain.ActorFromContext(ctx), time.Now()); err != nil {
+		return nil, err
+	}
+
+	s.emit(ctx, domain.NewStockAdjustmentEvent(domain.EventAdjustmentRejected, adjustment))
+	return adjustment, nil
+}
+
+// review obtiene el ajuste pendiente y comprueba que el revisor puede revisarlo
+func (s *StockAdjustmentService) review(ctx context.Context, id string) (*domain.StockAdjustment, error) {
+	adjustment, err := s.adjustmentRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := domain.AuthorizeStoreWrite(ctx, adjustment.StoreID); err != nil {
+		return nil, err
+	}
+	if adjustment.Status != domain.StockAdjustmentPending {
+		return nil, &domain.InvalidStateError{CurrentState: string(adjustment.Status), AttemptedAction: "review stock adjustment"}
+	}
+	if actor := domain.ActorFromContext(ctx); actor == adjustment.RequestedBy {
+		return nil, &domain.SelfApprovalError{AdjustmentID: adjustment.ID, Actor: actor}
+	}
+
+	return adjustment, nil
+}
+
+// emit persiste y publica el evento como el resto de cambios de stock
+func (s *StockAdjustmentService) emit(ctx context.Context, event *domain.Event) {
+	if err := s.stockService.eventRepo.Save(ctx, event); err != nil {
+		log.Printf("Warning: failed to save %s event: %v", event.EventType, err)
+	}
+
+	if err := s.stockService.publisher.Publish(ctx, event); publishFailed(err) {
+		log.Printf("Warning: failed to publish %s event: %v", event.EventType, err)
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// StockHoldService gestiona las retenciones internas de stock: unidades de exposición, en
+// reparación o bloqueadas por calidad. Reducen la disponibilidad como una reserva pero no tienen
+// cliente ni TTL; el personal las crea y las libera. Cada paso emite su evento de stock.
+type StockHoldService struct {
+	holdRepo     *repository.StockHoldRepository
+	stockService *StockService
+}
+
+// NewStockHoldService crea una nueva instancia del servicio
+func NewStockHoldService(holdRepo *repository.StockHoldRepository, stockService *StockService) *StockHoldService {
+	return &StockHoldService{
+		holdRepo:     holdRepo,
+		stockService: stockService,
+	}
+}
+
+// PlaceHold retiene quantity unidades vendibles de la fila de stock para uso interno
+func (s *StockHoldService) PlaceHold(ctx context.Context, productID, storeID string, holdType domain.StockHoldType, quantity int, note string) (*domain.StockHold, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	hold := &domain.StockHold{
+		ID:        uuid.New().String(),
+		ProductID: productID,
+		StoreID:   storeID,
+		Type:      domain.StockHoldType(strings.ToUpper(string(holdType))),
+		Quantity:  quantity,
+		Note:      strings.TrimSpace(note),
+		Status:    domain.StockHoldActive,
+		CreatedBy: domain.ActorFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+	if err := hold.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.holdRepo.Place(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	s.emit(ctx, domain.NewStockHoldEvent(domain.EventStockHoldPlaced, hold))
+	log.Printf("🔖 %s hold %s of %d units of product %s in store %s placed by %s",
+		hold.Type, hold.ID, hold.Quantity, hold.ProductID, hold.StoreID, hold.CreatedBy)
+
+	return hold, nil
+}
+
+// ReleaseHold libera una retención activa: sus unidades vuelven a estar disponibles
+func (s *StockHoldService) ReleaseHold(ctx context.Context, id string) (*domain.StockHold, error) {
+	hold, err := s.holdRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := domain.AuthorizeStoreWrite(ctx, hold.StoreID); err != nil {
+		return nil, err
+	}
+	if hold.Status != domain.StockHoldActive {
+		return nil, &domain.InvalidStateError{CurrentState: string(hold.Status), AttemptedAction: "release stock hold"}
+	}
+
+	if err := s.holdRepo.Release(ctx, hold, domain.ActorFromContext(ctx), time.Now()); err != nil {
+		return nil, err
+	}
+
+	s.emit(ctx, domain.NewStockHoldEvent(domain.EventStockHoldReleased, hold))
+	log.Printf("🔖 %s hold %s of product %s in store %s released by %s (%d units available again)",
+		hold.Type, hold.ID, hold.ProductID, hold.StoreID, hold.ReleasedBy, hold.Quantity)
+
+	return hold, nil
+}
+
+// GetHold obtiene una retención por ID
+func (s *StockHoldService) GetHold(ctx context.Context, id string) (*domain.StockHold, error) {
+	return s.holdRepo.Get(ctx, id)
+}
+
+// ListHolds lista las retenciones del filtro, de la más reciente a la más antigua
+func (s *StockHoldService) ListHolds(ctx context.Context, filter domain.StockHoldFilter) ([]*domain.StockHold, error) {
+	return s.holdRepo.List(ctx, filter)
+}
+
+// emit persiste y publica el evento como el resto de cambios de stock
+func (s *StockHoldService) emit(ctx context.Context, event *domain.Event) {
+	if err := s.stockService.eventRepo.Save(ctx, event); err != nil {
+		log.Printf("Warning: failed to save %s event: %v", event.EventType, err)
+	}
+
+	if err := s.stockService.publisher.Publish(ctx, event); publishFailed(err) {
+		log.Printf("Warning: failed to publish %s event: %v", event.EventType, err)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// Límites del listado de notas de stock
+const (
+	DefaultStockNoteLimit = 50
+	MaxStockNoteLimit     = 500
+)
+
+// StockNoteService gestiona las notas del personal sobre filas de stock y sus movimientos
+// (descuadres, mercancía dañada...). Son solo contexto: no cambian cantidades ni emiten eventos.
+type StockNoteService struct {
+	noteRepo  *repository.StockNoteRepository
+	stockRepo *repository.StockRepository
+	eventRepo *repository.EventRepository
+}
+
+// NewStockNoteService crea una nueva instancia del servicio
+func NewStockNoteService(noteRepo *repository.StockNoteRepository, stockRepo *repository.StockRepository, eventRepo *repository.EventRepository) *StockNoteService {
+	return &StockNoteService{
+		noteRepo:  noteRepo,
+		stockRepo: stockRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+// AddNote añade una nota a la fila de stock o, con note.EventID, a uno de sus movimientos
+func (s *StockNoteService) AddNote(ctx context.Context, productID, storeID string, note *domain.StockNote) (*domain.StockNote, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
+	}
+	if _, err := s.stockRepo.GetByProductAndStore(ctx, productID, storeID); err != nil {
+		return nil, err
+	}
+
+	if note.EventID != "" {
+		event, err := s.eventRepo.GetByID(ctx, note.EventID)
+		if err != nil {
+			return nil, err
+		}
+		if !event.IsStockMovementOf(productID, storeID) {
+			return nil, &domain.ValidationError{Field: "event_id", Message: "event is not a movement of this stock row"}
+		}
+	}
+
+	note.ID = uuid.New().String()
+	note.ProductID = productID
+	note.StoreID = storeID
+	note.CreatedBy = domain.ActorFromContext(ctx)
+	note.CreatedAt = time.Now()
+	if err := note.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, err
+	}
+
+	log.Printf("📝 Note %s on product %s in store %s added by %s (%d attachments)",
+		note.ID, productID, storeID, note.CreatedBy, len(note.Attachments))
+	return note, nil
+}
+
+// ListNotes lista las notas de una fila de stock, de la más reciente a la más antigua. Con
+// eventID solo las de ese movimiento.
+func (s *StockNoteService) ListNotes(ctx context.Context, productID, storeID, eventID string, limit int) ([]*domain.StockNote, error) {
+	if limit < 0 || limit > MaxStockNoteLimit {
+		return nil, &domain.ValidationError{Field: "limit", Message: "limit must be between 1 and 500"}
+	}
+	if limit == 0 {
+		limit = DefaultStockNoteLimit
+	}
+	if _, err := s.stockRepo.GetByProductAndStore(ctx, productID, storeID); err != nil {
+		return nil, err
+	}
+
+	return s.noteRepo.List(ctx, productID, storeID, eventID, limit)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// stockScheduleBatchSize limita cuántos cambios de stock programados se aplican por pasada del worker
+const stockScheduleBatchSize = 100
+
+// StockScheduleService gestiona los cambios de stock programados (ajustes futuros como liberar
+// unidades en una fecha de lanzamiento). Los cambios de precio programados los gestiona PriceService.
+type StockScheduleService struct {
+	scheduleRepo       *repository.StockScheduleRepository
+	stockService       *StockService
+	reservationService *ReservationService // Opcional: asigna las preventas al recibir pedidos de compra y transferencias
+}
+
+// NewStockScheduleService crea una nueva instancia del servicio
+func NewStockScheduleService(scheduleRepo *repository.StockScheduleRepository, stockService *StockService) *StockScheduleService {
+	return &StockScheduleService{
+		scheduleRepo: scheduleRepo,
+		stockService: stockService,
+	}
+}
+
+// SetReservationService configura la asignación de preventas al aplicar las llegadas de
+// pedidos de compra y transferencias
+func (s *StockScheduleService) SetReservationService(reservationService *ReservationService) {
+	s.reservationService = reservationService
+}
+
+// ScheduleStockChange programa un cambio de stock para effective_at. El autor se toma del context.
+func (s *StockScheduleService) ScheduleStockChange(ctx context.Context, schedule *domain.ScheduledStockChange) (*domain.ScheduledStockChange, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, schedule.StoreID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.stockService.GetStockByProductAndStore(ctx, schedule.ProductID, schedule.StoreID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := schedule.Validate(now); err != nil {
+		return nil, err
+	}
+
+	schedule.ID = uuid.New().String()
+	schedule.Actor = domain.ActorFromContext(ctx)
+	schedule.Status = domain.ScheduledStockPending
+	schedule.CreatedAt = now
+
+	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// ListScheduledStockChanges lista los cambios programados de una fila de stock (todos los estados)
+func (s *StockScheduleService) ListScheduledStockChanges(ctx context.Context, productID, storeID string) ([]*domain.ScheduledStockChange, error) {
+	if _, err := s.stockService.GetStockByProductAndStore(ctx, productID, storeID); err != nil {
+		return nil, err
+	}
+
+	return s.scheduleRepo.ListByStock(ctx, productID, storeID)
+}
+
+// CancelScheduledStockChange anula un cambio programado pendiente de la fila de stock
+func (s *StockScheduleService) CancelScheduledStockChange(ctx context.Context, productID, storeID, scheduleID string) (*domain.ScheduledStockChange, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.scheduleRepo.Get(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.ProductID != productID || schedule.StoreID != storeID {
+		return nil, &domain.NotFoundError{Resource: "ScheduledStockChange", ID: scheduleID}
+	}
+
+	if err := s.scheduleRepo.Cancel(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+
+	schedule.Status = domain.ScheduledStockCancelled
+	return schedule, nil
+}
+
+// ProcessScheduledStockChanges aplica los cambios cuyo effective_at ya llegó (usado por el worker periódico).
+// Los cambios que ya no se pueden aplicar (stock insuficiente, producto descatalogado) quedan FAILED.
+// Retorna el número de cambios aplicados.
+func (s *StockScheduleService) ProcessScheduledStockChanges(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := s.scheduleRepo.ListDue(ctx, now, stockScheduleBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, schedule := range due {
+		allowIncrease := true
+		if err := s.stockService.ensureNotDiscontinued(ctx, schedule.ProductID); err != nil {
+			if !errors.Is(err, domain.ErrConflict) {
+				log.Printf("Warning: failed to check run-down of product %s: %v", schedule.ProductID, err)
+				continue
+			}
+			allowIncrease = false
+		}
+		oldQuantity, newQuantity, ok, err := s.scheduleRepo.Apply(ctx, schedule, now, allowIncrease)
+		if err != nil {
+			log.Printf("Warning: failed to apply scheduled stock change %s for product %s in store %s: %v",
+				schedule.ID, schedule.ProductID, schedule.StoreID, err)
+			if isRejection(err) {
+				if err := s.scheduleRepo.MarkFailed(ctx, schedule.ID, err.Error(), now); err != nil {
+					log.Printf("Warning: %v", err)
+				}
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		applied++
+		s.publishStockUpdated(ctx, schedule.ProductID, schedule.StoreID, oldQuantity, newQuantity)
+		log.Printf("📦 Stock of product %s in store %s changed %d → %d (scheduled by %s)",
+			schedule.ProductID, schedule.StoreID, oldQuantity, newQuantity, schedule.Actor)
+		if schedule.Source != domain.ScheduledStockManual && newQuantity > oldQuantity {
+			s.allocatePreorders(ctx, schedule)
+		}
+	}
+
+	return applied, nil
+}
+
+// allocatePreorders asigna el stock recibido a las preventas de la fila (FIFO)
+func (s *StockScheduleService) allocatePreorders(ctx context.Context, schedule *domain.ScheduledStockChange) {
+	if s.reservationService == nil {
+		return
+	}
+	allocated, err := s.reservationService.AllocatePreorders(ctx, schedule.ProductID, schedule.StoreID)
+	if err != nil {
+		log.Printf("Warning: failed to allocate preorders of product %s in store %s: %v", schedule.ProductID, schedule.StoreID, err)
+	}
+	if len(allocated) > 0 {
+		log.Printf("📦 Allocated %d preorders of product %s in store %s (received %s %s)",
+			len(allocated), schedule.ProductID, schedule.StoreID, schedule.Source, schedule.Reference)
+	}
+}
+
+// publishStockUpdated persiste y publica stock.updated como un PUT /stock (invalida también la
+// disponibilidad cacheada)
+func (s *StockScheduleService) publishStockUpdated(ctx context.Context, productID, storeID string, oldQuantity, newQuantity int) {
+	event := domain.NewStockUpdatedEvent(productID, storeID, oldQuantity, newQuantity)
+
+	if err := s.stockService.eventRepo.Save(ctx, event); err != nil {
+		log.Printf("Warning: failed to save stock update event: %v", err)
+	}
+
+	if err := s.stockService.publisher.Publish(ctx, event); publishFailed(err) {
+		log.Printf("Warning: failed to publish stock update event: %v", err)
+	}
+}
+
+// isRejection indica si el error es de negocio (la operación no se puede aplicar) y no transitorio
+func isRejection(err error) bool {
+	for _, rejection := range []error{domain.ErrValidation, domain.ErrConflict, domain.ErrNotFound,
+		domain.ErrInsufficientStock, domain.ErrInvalidState, domain.ErrForbidden} {
+		if errors.Is(err, rejection) {
+			return true
+		}
+	}
+	return false
+}
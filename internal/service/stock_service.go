@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -13,10 +16,21 @@ import (
 
 // StockService maneja la lógica de negocio para stock
 type StockService struct {
-	stockRepo   *repository.StockRepository
-	productRepo *repository.ProductRepository
-	eventRepo   *repository.EventRepository
-	publisher   domain.EventPublisher // ← Event publisher para pub/sub en tiempo real
+	stockRepo       *repository.StockRepository
+	productRepo     *repository.ProductRepository
+	eventRepo       *repository.EventRepository
+	publisher       domain.EventPublisher // ← Event publisher para pub/sub en tiempo real
+	rundownRepo     *repository.RunDownRepository
+	cache           domain.AvailabilityCache // Opcional: fast-path de disponibilidad (nil = siempre BD)
+	groupRepo       *repository.StoreGroupRepository
+	visibilityRepo  *repository.StockVisibilityRepository
+	viewRepo        *repository.AvailabilityViewRepository // Opcional: read model de disponibilidad (nil = tabla stock)
+	reasonRepo      *repository.AdjustmentReasonRepository // Opcional: catálogo de motivos (nil = cualquier código)
+	scheduleRepo    *repository.StockScheduleRepository    // Opcional: entradas previstas en la disponibilidad futura
+	reservationRepo *repository.ReservationRepository      // Opcional: reservas que caducan en la disponibilidad futura
+	contention      *ContentionService                     // Opcional: conflictos de versión por fila de stock
+	fulfillment     *FulfillmentService                    // Opcional: disponibilidad por forma de entrega
+	noteRepo        *repository.StockNoteRepository        // Opcional: notas del personal en la línea temporal
 }
 
 // NewStockService crea una nueva instancia del servicio
@@ -34,6 +48,106 @@ func NewStockService(
 	}
 }
 
+// SetContentionService registra las escrituras de stock y sus conflictos de versión en el
+// informe de contención
+func (s *StockService) SetContentionService(contention *ContentionService) {
+	s.contention = contention
+}
+
+// SetRunDownRepository activa el bloqueo de reposición para productos descatalogados
+func (s *StockService) SetRunDownRepository(rundownRepo *repository.RunDownRepository) {
+	s.rundownRepo = rundownRepo
+}
+
+// SetAvailabilityCache activa el cache de disponibilidad en GetAvailableStock y CheckAvailability
+func (s *StockService) SetAvailabilityCache(cache domain.AvailabilityCache) {
+	s.cache = cache
+}
+
+// SetAvailabilityView hace que la disponibilidad y el stock bajo se lean del read model
+// availability_view. Lo mantiene AvailabilityProjector con los eventos; los cambios que no emiten
+// evento (stock de seguridad) lo refrescan aquí directamente.
+func (s *StockService) SetAvailabilityView(viewRepo *repository.AvailabilityViewRepository) {
+	s.viewRepo = viewRepo
+}
+
+// SetStoreGroupRepository activa las restricciones de transferencia de los grupos de tiendas
+func (s *StockService) SetStoreGroupRepository(groupRepo *repository.StoreGroupRepository) {
+	s.groupRepo = groupRepo
+}
+
+// SetStockVisibilityRepository activa la visibilidad por tienda en la disponibilidad pública
+func (s *StockService) SetStockVisibilityRepository(visibilityRepo *repository.StockVisibilityRepository) {
+	s.visibilityRepo = visibilityRepo
+}
+
+// SetFulfillmentService permite consultar la disponibilidad para una forma de entrega (envío o recogida)
+func (s *StockService) SetFulfillmentService(fulfillment *FulfillmentService) {
+	s.fulfillment = fulfillment
+}
+
+// SetStockNoteRepository incluye las notas del personal en la línea temporal de stock
+func (s *StockService) SetStockNoteRepository(noteRepo *repository.StockNoteRepository) {
+	s.noteRepo = noteRepo
+}
+
+// SetAdjustmentReasonRepository valida los motivos de los ajustes manuales contra el catálogo
+func (s *StockService) SetAdjustmentReasonRepository(reasonRepo *repository.AdjustmentReasonRepository) {
+	s.reasonRepo = reasonRepo
+}
+
+// checkReason valida el motivo de un ajuste manual: obligatorio si required y, con catálogo,
+// SetAvailabilityForecast habilita la disponibilidad en una fecha futura (?date= en availability)
+func (s *StockService) SetAvailabilityForecast(scheduleRepo *repository.StockScheduleRepository, reservationRepo *repository.ReservationRepository) {
+	s.scheduleRepo = scheduleRepo
+	s.reservationRepo = reservationRepo
+}
+
+// un código activo del catálogo
+func (s *StockService) checkReason(ctx context.Context, reason string, required bool) error {
+	if reason == "" {
+		if required {
+			return &domain.ValidationError{Field: "reason", Message: "reason is required (see GET /adjustment-reasons)"}
+		}
+		return nil
+	}
+	if s.reasonRepo == nil {
+		return nil
+	}
+
+	catalogued, err := s.reasonRepo.Get(ctx, reason)
+	if errors.Is(err, domain.ErrNotFound) {
+		return &domain.ValidationError{Field: "reason", Message: fmt.Sprintf("unknown reason %q (see GET /adjustment-reasons)", reason)}
+	}
+	if err != nil {
+		return err
+	}
+	if !catalogued.Active {
+		return &domain.ValidationError{Field: "reason", Message: fmt.Sprintf("reason %q is no longer active", reason)}
+	}
+	return nil
+}
+
+// ensureNotDiscontinued retorna ConflictError si el producto está descatalogado
+// (solo se permite vender el stock restante, no reponerlo)
+func (s *StockService) ensureNotDiscontinued(ctx context.Context, productID string) error {
+	if s.rundownRepo == nil {
+		return nil
+	}
+
+	discontinued, err := s.rundownRepo.IsDiscontinued(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if discontinued {
+		return &domain.ConflictError{
+			Message: fmt.Sprintf("product %s is discontinued, stock cannot be replenished", productID),
+		}
+	}
+
+	return nil
+}
+
 // GetStockByProductAndStore obtiene el stock de un producto en una tienda
 func (s *StockService) GetStockByProductAndStore(ctx context.Context, productID, storeID string) (*domain.Stock, error) {
 	// Validar que el producto existe
@@ -45,6 +159,99 @@ func (s *StockService) GetStockByProductAndStore(ctx context.Context, productID,
 	return s.stockRepo.GetByProductAndStore(ctx, productID, storeID)
 }
 
+// errStockHistoryDone detiene el recorrido de eventos cuando la línea temporal ya está completa
+var errStockHistoryDone = errors.New("stock history complete")
+
+// GetStockHistory reconstruye la línea temporal de quantity, reserved y version de una fila de
+// stock a partir de sus eventos, del más reciente al más antiguo, con quién hizo cada cambio
+func (s *StockService) GetStockHistory(ctx context.Context, productID, storeID string, filter domain.StockHistoryFilter) (*domain.StockHistory, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if filter.Limit == 0 {
+		filter.Limit = domain.DefaultStockHistoryLimit
+	}
+
+	stock, err := s.GetStockByProductAndStore(ctx, productID, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &domain.StockHistory{
+		ProductID: productID,
+		StoreID:   storeID,
+		Quantity:  stock.Quantity,
+		Reserved:  stock.Reserved,
+		Version:   stock.Version,
+		Entries:   make([]*domain.StockHistoryEntry, 0),
+	}
+
+	// Hay que deshacer también los eventos posteriores al rango para llegar a los valores correctos
+	rewinder := domain.NewStockHistoryRewinder(stock)
+	pageEnd := filter.To // Límite superior de la página, para las notas sobre la fila
+	err = s.eventRepo.EachStockMovement(ctx, productID, storeID, func(event *domain.Event) error {
+		entry := rewinder.Rewind(event)
+		switch {
+		case filter.BeforeSeq > 0 && entry.Seq >= filter.BeforeSeq:
+			if filter.To == nil || entry.At.Before(*filter.To) {
+				at := entry.At
+				pageEnd = &at
+			}
+			return nil
+		case filter.To != nil && !entry.At.Before(*filter.To):
+			return nil
+		case filter.From != nil && entry.At.Before(*filter.From):
+			return errStockHistoryDone
+		}
+		if len(history.Entries) == filter.Limit {
+			history.HasMore = true
+			return errStockHistoryDone
+		}
+		history.Entries = append(history.Entries, entry)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStockHistoryDone) {
+		return nil, err
+	}
+
+	if s.noteRepo != nil {
+		pageStart := filter.From
+		if history.HasMore {
+			at := history.Entries[len(history.Entries)-1].At
+			pageStart = &at
+		}
+		if err := s.attachStockNotes(ctx, history, pageStart, pageEnd); err != nil {
+			return nil, err
+		}
+	}
+
+	history.Count = len(history.Entries)
+	return history, nil
+}
+
+// attachStockNotes añade a cada entrada las notas de su movimiento y a la línea temporal las
+// notas sobre la fila creadas en el tramo [from, to) que cubre la página
+func (s *StockService) attachStockNotes(ctx context.Context, history *domain.StockHistory, from, to *time.Time) error {
+	eventIDs := make([]string, len(history.Entries))
+	byEvent := make(map[string]*domain.StockHistoryEntry, len(history.Entries))
+	for i, entry := range history.Entries {
+		eventIDs[i] = entry.EventID
+		byEvent[entry.EventID] = entry
+	}
+
+	notes, err := s.noteRepo.ListByEvents(ctx, history.ProductID, history.StoreID, eventIDs)
+	if err != nil {
+		return err
+	}
+	for _, note := range notes {
+		entry := byEvent[note.EventID]
+		entry.Notes = append(entry.Notes, note)
+	}
+
+	history.Notes, err = s.noteRepo.ListRowNotes(ctx, history.ProductID, history.StoreID, from, to)
+	return err
+}
+
 // GetAllStockByProduct obtiene el stock de un producto en TODAS las tiendas
 func (s *StockService) GetAllStockByProduct(ctx context.Context, productID string) ([]*domain.Stock, error) {
 	// Validar que el producto existe
@@ -56,18 +263,96 @@ func (s *StockService) GetAllStockByProduct(ctx context.Context, productID strin
 	return s.stockRepo.GetAllByProduct(ctx, productID)
 }
 
+// GetProductAvailability obtiene la disponibilidad de un producto por tienda (storeID vacío = todas
+// las tiendas con stock). Sin showQuantities, las tiendas que ocultan cantidades solo informan el tramo.
+// Con fulfillment solo se incluyen las ubicaciones cuyo tipo sirve esa forma de entrega en la
+// política del canal del request, las de los tipos preferidos primero.
+func (s *StockService) GetProductAvailability(ctx context.Context, productID, storeID string, showQuantities bool, fulfillment domain.FulfillmentMode) (*domain.ProductAvailability, error) {
+	stocks, err := s.GetAllStockByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranker *domain.FulfillmentRanker
+	if s.fulfillment != nil {
+		if ranker, err = s.fulfillment.Ranker(ctx, fulfillment); err != nil {
+			return nil, err
+		}
+	}
+
+	visibilities := map[string]*domain.StockVisibility{}
+	if s.visibilityRepo != nil {
+		if visibilities, err = s.visibilityRepo.GetAll(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &domain.ProductAvailability{ProductID: productID, Stores: []*domain.StoreAvailability{}}
+	for _, stock := range stocks {
+		if storeID != "" && stock.StoreID != storeID {
+			continue
+		}
+
+		visibility := visibilities[stock.StoreID]
+		availability := &domain.StoreAvailability{StoreID: stock.StoreID, Status: visibility.Status(stock)}
+		if ranker != nil {
+			if _, ok := ranker.Rank(stock.StoreID); !ok {
+				continue
+			}
+			availability.LocationType = ranker.LocationType(stock.StoreID)
+		}
+		if showQuantities || !visibility.HidesQuantities() {
+			available := stock.Sellable()
+			if available < 0 {
+				available = 0
+			}
+			availability.Available = &available
+		}
+		result.Stores = append(result.Stores, availability)
+	}
+
+	if storeID != "" && len(result.Stores) == 0 {
+		return nil, &domain.NotFoundError{Resource: "Stock", ID: fmt.Sprintf("product=%s, store=%s", productID, storeID)}
+	}
+	if ranker != nil {
+		sort.SliceStable(result.Stores, func(i, j int) bool {
+			return ranker.Less(result.Stores[i].StoreID, result.Stores[j].StoreID)
+		})
+	}
+	return result, nil
+}
+
 // GetAllStockByStore obtiene todo el stock de una tienda
 func (s *StockService) GetAllStockByStore(ctx context.Context, storeID string) ([]*domain.Stock, error) {
 	return s.stockRepo.GetAllByStore(ctx, storeID)
 }
 
-// UpdateStock actualiza la cantidad de stock (con optimistic locking)
+// StreamStockByStore recorre todo el stock de una tienda sin acumularlo (listados en streaming)
+func (s *StockService) StreamStockByStore(ctx context.Context, storeID string, fn func(*domain.Stock) error) error {
+	return s.stockRepo.EachByStore(ctx, storeID, fn)
+}
+
+// UpdateStock actualiza la cantidad de stock (con optimistic locking).
+// Con tolerancia de sobreventa la disponibilidad puede quedar hasta -tolerancia.
 func (s *StockService) UpdateStock(ctx context.Context, productID, storeID string, newQuantity int) (*domain.Stock, error) {
-	if newQuantity < 0 {
-		return nil, &domain.ValidationError{
-			Field:   "quantity",
-			Message: "quantity cannot be negative",
-		}
+	return s.updateStock(ctx, productID, storeID, newQuantity, "")
+}
+
+// UpdateStockWithReason actualiza la cantidad de stock registrando en el movimiento el motivo
+// (opcional; si se indica debe ser un código activo del catálogo)
+func (s *StockService) UpdateStockWithReason(ctx context.Context, productID, storeID string, newQuantity int, reason string) (*domain.Stock, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
+	}
+	if err := s.checkReason(ctx, reason, false); err != nil {
+		return nil, err
+	}
+	return s.updateStock(ctx, productID, storeID, newQuantity, reason)
+}
+
+func (s *StockService) updateStock(ctx context.Context, productID, storeID string, newQuantity int, reason string) (*domain.Stock, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
 	}
 
 	// Obtener stock actual
@@ -76,11 +361,14 @@ func (s *StockService) UpdateStock(ctx context.Context, productID, storeID strin
 		return nil, err
 	}
 
-	// Validar que la nueva cantidad no sea menor que la cantidad reservada
-	if newQuantity < stock.Reserved {
-		return nil, &domain.ValidationError{
-			Field:   "quantity",
-			Message: fmt.Sprintf("new quantity (%d) cannot be less than reserved (%d)", newQuantity, stock.Reserved),
+	if err := s.checkOversellFloor(ctx, stock, newQuantity, "quantity"); err != nil {
+		return nil, err
+	}
+
+	// Un producto descatalogado no se repone
+	if newQuantity > stock.Quantity {
+		if err := s.ensureNotDiscontinued(ctx, productID); err != nil {
+			return nil, err
 		}
 	}
 
@@ -90,12 +378,13 @@ func (s *StockService) UpdateStock(ctx context.Context, productID, storeID strin
 
 	// Usar optimistic locking
 	err = s.stockRepo.UpdateQuantity(ctx, stock)
+	recordVersionedWrite(s.contention, productID, storeID, err)
 	if err != nil {
 		return nil, err
 	}
 
 	// Publicar evento de actualización de stock
-	event := domain.NewStockUpdatedEvent(productID, storeID, oldQuantity, newQuantity)
+	event := domain.NewStockUpdatedEventWithReason(productID, storeID, oldQuantity, newQuantity, reason)
 
 	// Persistir evento en BD (para auditoría/event sourcing)
 	if err := s.eventRepo.Save(ctx, event); err != nil {
@@ -104,7 +393,7 @@ func (s *StockService) UpdateStock(ctx context.Context, productID, storeID strin
 	}
 
 	// Publicar evento a message broker (Redis/Kafka/etc.) en tiempo real
-	if err := s.publisher.Publish(ctx, event); err != nil {
+	if err := s.publisher.Publish(ctx, event); publishFailed(err) {
 		// Log error pero no fallar la operación
 		log.Printf("Warning: failed to publish stock update event: %v", err)
 	}
@@ -115,6 +404,26 @@ func (s *StockService) UpdateStock(ctx context.Context, productID, storeID strin
 
 // AdjustStock ajusta el stock (incrementa o decrementa)
 func (s *StockService) AdjustStock(ctx context.Context, productID, storeID string, adjustment int) (*domain.Stock, error) {
+	return s.adjustStock(ctx, productID, storeID, adjustment, "")
+}
+
+// AdjustStockWithReason ajusta el stock manualmente con un motivo obligatorio del catálogo, que
+// queda registrado en el movimiento (stock.updated)
+func (s *StockService) AdjustStockWithReason(ctx context.Context, productID, storeID string, adjustment int, reason string) (*domain.Stock, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
+	}
+	if err := s.checkReason(ctx, reason, true); err != nil {
+		return nil, err
+	}
+	return s.adjustStock(ctx, productID, storeID, adjustment, reason)
+}
+
+func (s *StockService) adjustStock(ctx context.Context, productID, storeID string, adjustment int, reason string) (*domain.Stock, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
+	}
+
 	// Obtener stock actual
 	stock, err := s.stockRepo.GetByProductAndStore(ctx, productID, storeID)
 	if err != nil {
@@ -122,34 +431,130 @@ func (s *StockService) AdjustStock(ctx context.Context, productID, storeID strin
 	}
 
 	newQuantity := stock.Quantity + adjustment
+	if err := s.checkOversellFloor(ctx, stock, newQuantity, "adjustment"); err != nil {
+		return nil, err
+	}
 
-	// Validar que no quede negativo
-	if newQuantity < 0 {
-		return nil, &domain.ValidationError{
-			Field:   "adjustment",
-			Message: fmt.Sprintf("adjustment would result in negative stock (current: %d, adjustment: %d)", stock.Quantity, adjustment),
-		}
+	return s.updateStock(ctx, productID, storeID, newQuantity, reason)
+}
+
+// checkOversellFloor valida que newQuantity no deje la disponibilidad por debajo del suelo de
+// sobreventa: sin tolerancia, no negativa ni menor que lo reservado
+func (s *StockService) checkOversellFloor(ctx context.Context, stock *domain.Stock, newQuantity int, field string) error {
+	tolerance, err := s.stockRepo.GetOversellTolerance(ctx, stock.ProductID, stock.StoreID)
+	if err != nil {
+		return err
 	}
 
-	// Validar que no sea menor que lo reservado
-	if newQuantity < stock.Reserved {
-		return nil, &domain.ValidationError{
-			Field:   "adjustment",
-			Message: fmt.Sprintf("new quantity (%d) cannot be less than reserved (%d)", newQuantity, stock.Reserved),
+	if tolerance == 0 {
+		if newQuantity < 0 {
+			return &domain.ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("quantity cannot be negative (current: %d, new: %d)", stock.Quantity, newQuantity),
+			}
 		}
+		if newQuantity < stock.Reserved {
+			return &domain.ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("new quantity (%d) cannot be less than reserved (%d)", newQuantity, stock.Reserved),
+			}
+		}
+		return nil
 	}
 
-	return s.UpdateStock(ctx, productID, storeID, newQuantity)
+	if newQuantity-stock.Reserved < -tolerance {
+		return &domain.ValidationError{
+			Field: field,
+			Message: fmt.Sprintf("new quantity (%d) with %d reserved exceeds the oversell tolerance of %d units",
+				newQuantity, stock.Reserved, tolerance),
+		}
+	}
+	return nil
 }
 
-// GetAvailableStock retorna la cantidad disponible (quantity - reserved)
+// GetAvailableStock retorna la cantidad vendible (quantity - reserved - safety_stock): el stock de
+// seguridad no se ofrece a reservas. Con cache configurado se responde desde él y solo los misses
+// leen la tabla stock. Si el request indica canal de venta se calcula sin cache lo que ese canal
+// puede reservar (su asignación o la parte no asignada).
 func (s *StockService) GetAvailableStock(ctx context.Context, productID, storeID string) (int, error) {
+	if channel := domain.SalesChannelFromContext(ctx); channel != "" {
+		return s.stockRepo.GetChannelAvailable(ctx, productID, storeID, channel)
+	}
+
+	if s.cache != nil {
+		available, ok, err := s.cache.Get(ctx, productID, storeID)
+		if err != nil {
+			log.Printf("Warning: availability cache unavailable, reading database: %v", err)
+		} else if ok {
+			return available, nil
+		}
+	}
+
+	available, err := s.readAvailable(ctx, productID, storeID)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, productID, storeID, available); err != nil {
+			log.Printf("Warning: failed to seed availability cache: %v", err)
+		}
+	}
+
+	return available, nil
+}
+
+// readAvailable lee la cantidad vendible del read model o, si no está configurado o la fila
+// todavía no está proyectada, de la tabla stock
+func (s *StockService) readAvailable(ctx context.Context, productID, storeID string) (int, error) {
+	if s.viewRepo != nil {
+		view, err := s.viewRepo.Get(ctx, productID, storeID)
+		if err == nil {
+			return view.Available, nil
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			log.Printf("Warning: availability view unavailable, reading stock: %v", err)
+		}
+	}
+
 	stock, err := s.stockRepo.GetByProductAndStore(ctx, productID, storeID)
 	if err != nil {
 		return 0, err
 	}
+	return stock.Sellable(), nil
+}
+
+// SetSafetyStock fija las unidades de stock de seguridad de una fila de stock. Siguen contando en
+// quantity pero dejan de estar disponibles para reservas y consultas de disponibilidad.
+func (s *StockService) SetSafetyStock(ctx context.Context, productID, storeID string, safetyStock int) (*domain.Stock, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	if safetyStock < 0 {
+		return nil, &domain.ValidationError{
+			Field:   "safety_stock",
+			Message: "safety stock cannot be negative",
+		}
+	}
+
+	if err := s.stockRepo.UpdateSafetyStock(ctx, productID, storeID, safetyStock); err != nil {
+		return nil, err
+	}
+
+	// La disponibilidad cacheada y la proyectada ya no son válidas
+	if s.cache != nil {
+		if err := s.cache.Invalidate(ctx, productID, storeID); err != nil {
+			log.Printf("Warning: failed to invalidate availability cache: %v", err)
+		}
+	}
+	if s.viewRepo != nil {
+		if err := s.viewRepo.RefreshProduct(ctx, productID); err != nil {
+			log.Printf("Warning: failed to refresh availability view: %v", err)
+		}
+	}
 
-	return stock.Available(), nil
+	return s.stockRepo.GetByProductAndStore(ctx, productID, storeID)
 }
 
 // CheckAvailability verifica si hay stock suficiente disponible
@@ -162,20 +567,195 @@ func (s *StockService) CheckAvailability(ctx context.Context, productID, storeID
 	return available >= quantity, nil
 }
 
-// GetLowStockItems obtiene productos con stock bajo
-func (s *StockService) GetLowStockItems(ctx context.Context, threshold int) ([]*domain.Stock, error) {
-	if threshold < 0 {
+// GetAvailabilityForecast calcula la disponibilidad prevista de una fila de stock en date: el
+// stock actual más las reservas pendientes que caducan antes y los cambios programados
+// (pedidos de compra, transferencias) que llegan antes. quantity > 0 indica si la cubre.
+func (s *StockService) GetAvailabilityForecast(ctx context.Context, productID, storeID string, date time.Time, quantity int) (*domain.AvailabilityForecast, error) {
+	if s.scheduleRepo == nil || s.reservationRepo == nil {
+		return nil, &domain.ValidationError{Field: "date", Message: "availability forecast is not enabled"}
+	}
+	if !date.After(time.Now()) {
+		return nil, &domain.ValidationError{Field: "date", Message: "date must be in the future"}
+	}
+
+	stock, err := s.GetStockByProductAndStore(ctx, productID, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiring, err := s.reservationRepo.SumPendingExpiringBefore(ctx, productID, storeID, date)
+	if err != nil {
+		return nil, err
+	}
+	_, preordered, err := s.reservationRepo.GetPreorderCapacity(ctx, productID, storeID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.scheduleRepo.ListPendingUntil(ctx, productID, storeID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := domain.NewAvailabilityForecast(stock, date, expiring, preordered, changes)
+	if quantity > 0 {
+		forecast.Check(quantity)
+	}
+	return forecast, nil
+}
+
+// GetLowStockItems obtiene una página de productos con stock bajo y el total sin paginar
+func (s *StockService) GetLowStockItems(ctx context.Context, filter domain.LowStockFilter) ([]*domain.Stock, int, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultLowStockLimit
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		stocks []*domain.Stock
+		total  int
+		err    error
+	)
+	if s.viewRepo != nil {
+		stocks, err = s.viewRepo.GetLowStockItems(ctx, filter)
+		if err == nil {
+			total, err = s.viewRepo.CountLowStockItems(ctx, filter)
+		}
+	} else {
+		stocks, err = s.stockRepo.GetLowStockItems(ctx, filter)
+		if err == nil {
+			total, err = s.stockRepo.CountLowStockItems(ctx, filter)
+		}
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if stocks == nil {
+		stocks = []*domain.Stock{}
+	}
+	return stocks, total, nil
+}
+
+// StreamLowStockItems recorre todas las filas de stock bajo del filtro, sin paginar (descargas CSV/XLSX)
+func (s *StockService) StreamLowStockItems(ctx context.Context, filter domain.LowStockFilter, fn func(*domain.Stock) error) error {
+	filter.Limit, filter.Offset = 0, 0
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	if s.viewRepo != nil {
+		return s.viewRepo.EachLowStockItem(ctx, filter, fn)
+	}
+	return s.stockRepo.EachLowStockItem(ctx, filter, fn)
+}
+
+// CompareStores compara el surtido y la disponibilidad de dos tiendas.
+// Devuelve los productos presentes solo en una de ellas y los productos comunes
+// cuya diferencia de disponibilidad es mayor o igual a minDifference.
+func (s *StockService) CompareStores(ctx context.Context, storeA, storeB string, minDifference int) (*domain.StockComparison, error) {
+	if storeA == "" || storeB == "" {
+		return nil, &domain.ValidationError{
+			Field:   "storeA,storeB",
+			Message: "both stores are required",
+		}
+	}
+	if storeA == storeB {
 		return nil, &domain.ValidationError{
-			Field:   "threshold",
-			Message: "threshold cannot be negative",
+			Field:   "storeB",
+			Message: "stores must be different",
+		}
+	}
+	if minDifference < 1 {
+		return nil, &domain.ValidationError{
+			Field:   "minDifference",
+			Message: "minDifference must be at least 1",
+		}
+	}
+
+	stocksA, err := s.stockRepo.GetAllByStore(ctx, storeA)
+	if err != nil {
+		return nil, err
+	}
+	stocksB, err := s.stockRepo.GetAllByStore(ctx, storeB)
+	if err != nil {
+		return nil, err
+	}
+
+	byProductB := make(map[string]*domain.Stock, len(stocksB))
+	for _, stock := range stocksB {
+		byProductB[stock.ProductID] = stock
+	}
+
+	comparison := &domain.StockComparison{
+		StoreA:        storeA,
+		StoreB:        storeB,
+		MinDifference: minDifference,
+		OnlyInA:       []*domain.Stock{},
+		OnlyInB:       []*domain.Stock{},
+		Disparities:   []domain.StockDisparity{},
+	}
+
+	for _, a := range stocksA {
+		b, ok := byProductB[a.ProductID]
+		if !ok {
+			comparison.OnlyInA = append(comparison.OnlyInA, a)
+			continue
+		}
+		delete(byProductB, a.ProductID)
+		comparison.Summary.Common++
+
+		diff := a.Available() - b.Available()
+		if abs(diff) < minDifference {
+			continue
+		}
+
+		disparity := domain.StockDisparity{
+			ProductID:     a.ProductID,
+			AvailableA:    a.Available(),
+			AvailableB:    b.Available(),
+			Difference:    diff,
+			SurplusStore:  storeA,
+			ShortageStore: storeB,
+		}
+		if diff < 0 {
+			disparity.SurplusStore, disparity.ShortageStore = storeB, storeA
+		}
+		comparison.Disparities = append(comparison.Disparities, disparity)
+	}
+
+	// Lo que queda en el mapa solo existe en B (se recorre stocksB para mantener el orden)
+	for _, b := range stocksB {
+		if _, ok := byProductB[b.ProductID]; ok {
+			comparison.OnlyInB = append(comparison.OnlyInB, b)
 		}
 	}
 
-	return s.stockRepo.GetLowStockItems(ctx, threshold)
+	// Las mayores diferencias primero
+	sort.SliceStable(comparison.Disparities, func(i, j int) bool {
+		return abs(comparison.Disparities[i].Difference) > abs(comparison.Disparities[j].Difference)
+	})
+
+	comparison.Summary.OnlyInA = len(comparison.OnlyInA)
+	comparison.Summary.OnlyInB = len(comparison.OnlyInB)
+	comparison.Summary.Disparities = len(comparison.Disparities)
+
+	return comparison, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
 }
 
 // InitializeStock crea stock inicial para un producto en una tienda
 func (s *StockService) InitializeStock(ctx context.Context, productID, storeID string, initialQuantity int) (*domain.Stock, error) {
+	if err := domain.AuthorizeStoreWrite(ctx, storeID); err != nil {
+		return nil, err
+	}
+
 	if initialQuantity < 0 {
 		return nil, &domain.ValidationError{
 			Field:   "initialQuantity",
@@ -183,11 +763,21 @@ func (s *StockService) InitializeStock(ctx context.Context, productID, storeID s
 		}
 	}
 
-	// Validar que el producto existe
-	_, err := s.productRepo.GetByID(ctx, productID)
+	// Validar que el producto existe y está activo
+	product, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
 		return nil, err
 	}
+	if !product.IsSellable() {
+		return nil, &domain.InvalidStateError{
+			CurrentState:    string(product.Status),
+			AttemptedAction: "initialize stock for product " + productID,
+		}
+	}
+
+	if err := s.ensureNotDiscontinued(ctx, productID); err != nil {
+		return nil, err
+	}
 
 	// Crear stock
 	stock := &domain.Stock{
@@ -213,7 +803,7 @@ func (s *StockService) InitializeStock(ctx context.Context, productID, storeID s
 	}
 
 	// Publicar a message broker
-	if err := s.publisher.Publish(ctx, event); err != nil {
+	if err := s.publisher.Publish(ctx, event); publishFailed(err) {
 		log.Printf("Warning: failed to publish stock created event: %v", err)
 	}
 
@@ -222,6 +812,11 @@ func (s *StockService) InitializeStock(ctx context.Context, productID, storeID s
 
 // TransferStock transfiere stock entre tiendas
 func (s *StockService) TransferStock(ctx context.Context, productID, fromStoreID, toStoreID string, quantity int) error {
+	// La tienda de origen es la que cede las unidades
+	if err := domain.AuthorizeStoreWrite(ctx, fromStoreID); err != nil {
+		return err
+	}
+
 	if quantity <= 0 {
 		return &domain.ValidationError{
 			Field:   "quantity",
@@ -236,11 +831,21 @@ func (s *StockService) TransferStock(ctx context.Context, productID, fromStoreID
 		}
 	}
 
-	// Verificar disponibilidad en tienda origen
-	available, err := s.GetAvailableStock(ctx, productID, fromStoreID)
+	if err := checkTransferGroups(ctx, s.groupRepo, fromStoreID, toStoreID); err != nil {
+		return err
+	}
+
+	// Durante el run-down cada tienda agota su propio stock
+	if err := s.ensureNotDiscontinued(ctx, productID); err != nil {
+		return err
+	}
+
+	// Verificar disponibilidad en tienda origen (contra la BD, nunca el cache)
+	source, err := s.stockRepo.GetByProductAndStore(ctx, productID, fromStoreID)
 	if err != nil {
 		return err
 	}
+	available := source.Available()
 
 	if available < quantity {
 		return &domain.InsufficientStockError{
@@ -274,7 +879,7 @@ func (s *StockService) TransferStock(ctx context.Context, productID, fromStoreID
 	}
 
 	// Publicar a message broker
-	if err := s.publisher.Publish(ctx, event); err != nil {
+	if err := s.publisher.Publish(ctx, event); publishFailed(err) {
 		log.Printf("Warning: failed to publish stock transfer event: %v", err)
 	}
 
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// StoreGroupService gestiona los grupos de tiendas (regiones, franquicias)
+type StoreGroupService struct {
+	groupRepo *repository.StoreGroupRepository
+}
+
+// NewStoreGroupService crea una nueva instancia del servicio
+func NewStoreGroupService(groupRepo *repository.StoreGroupRepository) *StoreGroupService {
+	return &StoreGroupService{
+		groupRepo: groupRepo,
+	}
+}
+
+// CreateGroup crea un grupo. Si no se indica ID se genera uno.
+func (s *StoreGroupService) CreateGroup(ctx context.Context, group *domain.StoreGroup) (*domain.StoreGroup, error) {
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
+
+	group.ID = strings.TrimSpace(group.ID)
+	if group.ID == "" {
+		group.ID = uuid.New().String()
+	} else if _, err := s.groupRepo.GetByID(ctx, group.ID); err == nil {
+		return nil, &domain.ConflictError{Message: fmt.Sprintf("store group %s already exists", group.ID)}
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	if err := s.checkMembership(ctx, group); err != nil {
+		return nil, err
+	}
+
+	group.CreatedAt = time.Now()
+	group.UpdatedAt = group.CreatedAt
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetGroup obtiene un grupo con sus tiendas
+func (s *StoreGroupService) GetGroup(ctx context.Context, id string) (*domain.StoreGroup, error) {
+	return s.groupRepo.GetByID(ctx, id)
+}
+
+// ListGroups lista los grupos, opcionalmente de un tipo
+func (s *StoreGroupService) ListGroups(ctx context.Context, groupType string) ([]*domain.StoreGroup, error) {
+	t := domain.StoreGroupType(strings.ToUpper(strings.TrimSpace(groupType)))
+	if t != "" && !t.IsValid() {
+		return nil, &domain.ValidationError{Field: "type", Message: "type must be REGION or FRANCHISE"}
+	}
+	return s.groupRepo.List(ctx, t)
+}
+
+// UpdateGroup reemplaza nombre, tipo, restricción y tiendas de un grupo
+func (s *StoreGroupService) UpdateGroup(ctx context.Context, group *domain.StoreGroup) (*domain.StoreGroup, error) {
+	existing, err := s.groupRepo.GetByID(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkMembership(ctx, group); err != nil {
+		return nil, err
+	}
+
+	group.CreatedAt = existing.CreatedAt
+	group.UpdatedAt = time.Now()
+	if err := s.groupRepo.Update(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// DeleteGroup elimina un grupo (las tiendas no se modifican)
+func (s *StoreGroupService) DeleteGroup(ctx context.Context, id string) error {
+	return s.groupRepo.Delete(ctx, id)
+}
+
+// checkMembership verifica que ninguna tienda del grupo pertenezca ya a otro grupo del mismo tipo
+func (s *StoreGroupService) checkMembership(ctx context.Context, group *domain.StoreGroup) error {
+	others, err := s.groupRepo.List(ctx, group.Type)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range others {
+		if other.ID == group.ID {
+			continue
+		}
+		for _, storeID := range group.StoreIDs {
+			if other.HasStore(storeID) {
+				return &domain.ConflictError{
+					Message: fmt.Sprintf("store %s already belongs to %s group %s", storeID, group.Type, other.ID),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkTransferGroups verifica las restricciones de transferencia de los grupos de ambas tiendas.
+// Sin repositorio de grupos no hay restricciones.
+func checkTransferGroups(ctx context.Context, groupRepo *repository.StoreGroupRepository, fromStoreID, toStoreID string) error {
+	if groupRepo == nil {
+		return nil
+	}
+
+	groups, err := groupRepo.ListByStores(ctx, fromStoreID, toStoreID)
+	if err != nil {
+		return err
+	}
+	return domain.CheckTransferAllowed(fromStoreID, toStoreID, groups)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// StoreHeartbeatService recibe los latidos de las instancias edge y detecta las tiendas que
+// llevan más de staleAfter sin sincronizar con la central
+type StoreHeartbeatService struct {
+	heartbeatRepo *repository.StoreHeartbeatRepository
+	eventRepo     *repository.EventRepository
+	publisher     domain.EventPublisher
+	staleAfter    time.Duration
+}
+
+// NewStoreHeartbeatService crea una nueva instancia del servicio
+func NewStoreHeartbeatService(
+	heartbeatRepo *repository.StoreHeartbeatRepository,
+	eventRepo *repository.EventRepository,
+	publisher domain.EventPublisher,
+	staleAfter time.Duration,
+) *StoreHeartbeatService {
+	return &StoreHeartbeatService{
+		heartbeatRepo: heartbeatRepo,
+		eventRepo:     eventRepo,
+		publisher:     publisher,
+		staleAfter:    staleAfter,
+	}
+}
+
+// RecordHeartbeat registra el latido de una instancia edge con la hora de recepción
+func (s *StoreHeartbeatService) RecordHeartbeat(ctx context.Context, heartbeat *domain.StoreHeartbeat) error {
+	if err := heartbeat.Validate(); err != nil {
+		return err
+	}
+	if err := domain.AuthorizeStoreWrite(ctx, heartbeat.StoreID); err != nil {
+		return err
+	}
+
+	heartbeat.ReceivedAt = time.Now()
+	return s.heartbeatRepo.Upsert(ctx, heartbeat)
+}
+
+// GetSyncStatus calcula el estado de sincronización de las instancias (storeID vacío = todas,
+// staleOnly = solo las atrasadas)
+func (s *StoreHeartbeatService) GetSyncStatus(ctx context.Context, storeID string, staleOnly bool) (*domain.StoreSyncReport, error) {
+	heartbeats, err := s.heartbeatRepo.List(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewStoreSyncReport(heartbeats, time.Now(), s.staleAfter, staleOnly), nil
+}
+
+// AlertStaleStores emite store.sync_stale por cada instancia atrasada que no se haya alertado
+// ya. Una instancia que vuelve a sincronizar después de la alerta se alerta de nuevo si vuelve
+// a atrasarse.
+func (s *StoreHeartbeatService) AlertStaleStores(ctx context.Context) ([]*domain.StoreSyncStatus, error) {
+	heartbeats, err := s.heartbeatRepo.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	alerted := []*domain.StoreSyncStatus{}
+	for _, heartbeat := range heartbeats {
+		status := domain.NewStoreSyncStatus(heartbeat, now, s.staleAfter)
+		if !status.Stale || (heartbeat.AlertedAt != nil && !heartbeat.SyncedThrough().After(*heartbeat.AlertedAt)) {
+			continue
+		}
+
+		log.Printf("⚠️  Store %s (instance %s) has not synced for %.0f minutes (%d pending events)",
+			status.StoreID, status.InstanceID, status.LagMinutes, status.PendingEvents)
+		event := domain.NewStoreSyncStaleEvent(status)
+		if err := s.eventRepo.Save(ctx, event); err != nil {
+			log.Printf("Warning: failed to save store sync stale event: %v", err)
+		}
+		if err := s.publisher.Publish(ctx, event); publishFailed(err) {
+			log.Printf("Warning: failed to publish store sync stale event: %v", err)
+		}
+		if err := s.heartbeatRepo.MarkAlerted(ctx, heartbeat.InstanceID, now); err != nil {
+			return alerted, err
+		}
+		alerted = append(alerted, status)
+	}
+
+	return alerted, nil
+}
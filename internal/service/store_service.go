@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// StoreService maneja la lógica de negocio de tiendas (onboarding)
+type StoreService struct {
+	storeRepo      *repository.StoreRepository
+	apiKeyRepo     *repository.APIKeyRepository
+	stockRepo      *repository.StockRepository
+	productRepo    *repository.ProductRepository
+	eventRepo      *repository.EventRepository
+	publisher      domain.EventPublisher
+	keyRing        *auth.KeyRing
+	hoursRepo      *repository.StoreHoursRepository
+	visibilityRepo *repository.StockVisibilityRepository
+}
+
+// NewStoreService crea una nueva instancia del servicio
+func NewStoreService(
+	storeRepo *repository.StoreRepository,
+	apiKeyRepo *repository.APIKeyRepository,
+	stockRepo *repository.StockRepository,
+	productRepo *repository.ProductRepository,
+	eventRepo *repository.EventRepository,
+	publisher domain.EventPublisher,
+	keyRing *auth.KeyRing,
+) *StoreService {
+	return &StoreService{
+		storeRepo:   storeRepo,
+		apiKeyRepo:  apiKeyRepo,
+		stockRepo:   stockRepo,
+		productRepo: productRepo,
+		eventRepo:   eventRepo,
+		publisher:   publisher,
+		keyRing:     keyRing,
+	}
+}
+
+// GetStore obtiene una tienda por ID
+func (s *StoreService) GetStore(ctx context.Context, id string) (*domain.Store, error) {
+	return s.storeRepo.GetByID(ctx, id)
+}
+
+// SetLocationType cambia el tipo de ubicación de una tienda (STORE, WAREHOUSE o DARKSTORE)
+func (s *StoreService) SetLocationType(ctx context.Context, storeID string, locationType domain.LocationType) (*domain.Store, error) {
+	locationType, err := domain.ParseLocationType(string(locationType))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storeRepo.UpdateLocationType(ctx, storeID, locationType); err != nil {
+		return nil, err
+	}
+	return s.storeRepo.GetByID(ctx, storeID)
+}
+
+// SetStoreHoursRepository habilita la gestión de horarios de apertura
+func (s *StoreService) SetStoreHoursRepository(hoursRepo *repository.StoreHoursRepository) {
+	s.hoursRepo = hoursRepo
+}
+
+// GetStoreHours obtiene el horario de una tienda
+func (s *StoreService) GetStoreHours(ctx context.Context, storeID string) (*domain.StoreHours, error) {
+	if _, err := s.storeRepo.GetByID(ctx, storeID); err != nil {
+		return nil, err
+	}
+	return s.hoursRepo.GetByStore(ctx, storeID)
+}
+
+// SetStoreHours crea o reemplaza el horario de una tienda. Solo afecta a las reservas nuevas:
+// las pendientes conservan su vencimiento.
+func (s *StoreService) SetStoreHours(ctx context.Context, hours *domain.StoreHours) (*domain.StoreHours, error) {
+	if _, err := s.storeRepo.GetByID(ctx, hours.StoreID); err != nil {
+		return nil, err
+	}
+	if err := hours.Validate(); err != nil {
+		return nil, err
+	}
+
+	hours.UpdatedAt = time.Now()
+	if err := s.hoursRepo.Upsert(ctx, hours); err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
+
+// DeleteStoreHours elimina el horario: la tienda vuelve a admitir reservas a cualquier hora
+func (s *StoreService) DeleteStoreHours(ctx context.Context, storeID string) error {
+	return s.hoursRepo.Delete(ctx, storeID)
+}
+
+// SetStockVisibilityRepository habilita la gestión de la visibilidad pública de la disponibilidad
+func (s *StoreService) SetStockVisibilityRepository(visibilityRepo *repository.StockVisibilityRepository) {
+	s.visibilityRepo = visibilityRepo
+}
+
+// GetStockVisibility obtiene la visibilidad de la disponibilidad de una tienda
+func (s *StoreService) GetStockVisibility(ctx context.Context, storeID string) (*domain.StockVisibility, error) {
+	if _, err := s.storeRepo.GetByID(ctx, storeID); err != nil {
+		return nil, err
+	}
+	return s.visibilityRepo.GetByStore(ctx, storeID)
+}
+
+// SetStockVisibility crea o reemplaza la visibilidad de la disponibilidad de una tienda
+func (s *StoreService) SetStockVisibility(ctx context.Context, visibility *domain.StockVisibility) (*domain.StockVisibility, error) {
+	if _, err := s.storeRepo.GetByID(ctx, visibility.StoreID); err != nil {
+		return nil, err
+	}
+	if err := visibility.Validate(); err != nil {
+		return nil, err
+	}
+
+	visibility.UpdatedAt = time.Now()
+	if err := s.visibilityRepo.Upsert(ctx, visibility); err != nil {
+		return nil, err
+	}
+	return visibility, nil
+}
+
+// DeleteStockVisibility elimina la configuración: la tienda vuelve a mostrar cantidades al público
+func (s *StoreService) DeleteStockVisibility(ctx context.Context, storeID string) error {
+	return s.visibilityRepo.Delete(ctx, storeID)
+}
+
+// BootstrapStore da de alta una tienda en una sola operación: crea la tienda,
+// aplica el template de surtido, inicializa el stock en cero con los umbrales de alerta
+// y emite una API key para la tienda.
+//
+// Es idempotente: si la tienda, las filas de stock o la API key ya existen se reutilizan
+// sin modificarlas, de modo que un reintento tras un fallo parcial completa el onboarding.
+func (s *StoreService) BootstrapStore(ctx context.Context, input *domain.StoreBootstrap) (*domain.StoreBootstrapResult, error) {
+	if err := input.Store.Validate(); err != nil {
+		return nil, err
+	}
+	if input.Template.IsEmpty() {
+		return nil, &domain.ValidationError{
+			Field:   "template",
+			Message: "assortment template must define product_ids, from_store_id or category",
+		}
+	}
+	if input.Template.FromStoreID == input.Store.ID {
+		return nil, &domain.ValidationError{
+			Field:   "template.from_store_id",
+			Message: "cannot copy the assortment from the same store",
+		}
+	}
+	if input.MinStock < 0 || input.MaxStock < 0 {
+		return nil, &domain.ValidationError{
+			Field:   "min_stock",
+			Message: "thresholds cannot be negative",
+		}
+	}
+	if input.MaxStock > 0 && input.MaxStock < input.MinStock {
+		return nil, &domain.ValidationError{
+			Field:   "max_stock",
+			Message: "max_stock must be greater than or equal to min_stock",
+		}
+	}
+
+	result := &domain.StoreBootstrapResult{
+		MinStock: input.MinStock,
+		MaxStock: input.MaxStock,
+	}
+
+	// 1. Resolver el surtido del template antes de escribir nada
+	productIDs, err := s.resolveTemplate(ctx, input.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Crear la tienda (o reutilizar la existente)
+	store, err := s.storeRepo.GetByID(ctx, input.Store.ID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+
+		store = &input.Store
+		store.Active = true
+		store.CreatedAt = time.Now()
+		if err := s.storeRepo.Create(ctx, store); err != nil {
+			return nil, err
+		}
+		result.StoreCreated = true
+	}
+	result.Store = store
+
+	// 3. Inicializar stock en cero con los umbrales de alerta
+	existing, err := s.stockRepo.GetAllByStore(ctx, store.ID)
+	if err != nil {
+		return nil, err
+	}
+	stocked := make(map[string]bool, len(existing))
+	for _, stock := range existing {
+		stocked[stock.ProductID] = true
+	}
+
+	for _, productID := range productIDs {
+		if stocked[productID] {
+			result.StockExisting++
+			continue
+		}
+
+		stock := &domain.Stock{
+			ID:        uuid.New().String(),
+			ProductID: productID,
+			StoreID:   store.ID,
+			Quantity:  0,
+			Reserved:  0,
+			MinStock:  input.MinStock,
+			MaxStock:  input.MaxStock,
+			Version:   1,
+		}
+		if err := s.stockRepo.Create(ctx, stock); err != nil {
+			return nil, err
+		}
+		stocked[productID] = true
+		result.StockInitialized++
+
+		event := domain.NewStockCreatedEvent(productID, store.ID, 0)
+		if err := s.eventRepo.Save(ctx, event); err != nil {
+			log.Printf("Warning: failed to save stock created event: %v", err)
+		}
+		if err := s.publisher.Publish(ctx, event); publishFailed(err) {
+			log.Printf("Warning: failed to publish stock created event: %v", err)
+		}
+	}
+
+	// 4. Emitir la API key de la tienda (solo si no tiene una activa)
+	apiKey, err := s.apiKeyRepo.GetActiveByStore(ctx, store.ID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+
+		apiKey, err = s.issueAPIKey(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		result.APIKeyCreated = true
+	}
+	result.APIKey = apiKey
+
+	return result, nil
+}
+
+// resolveTemplate obtiene los IDs de producto definidos por el template de surtido
+func (s *StoreService) resolveTemplate(ctx context.Context, template domain.AssortmentTemplate) ([]string, error) {
+	var productIDs []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			productIDs = append(productIDs, id)
+		}
+	}
+
+	switch {
+	case len(template.ProductIDs) > 0:
+		for _, id := range template.ProductIDs {
+			if _, err := s.productRepo.GetByID(ctx, id); err != nil {
+				return nil, err
+			}
+			add(id)
+		}
+
+	case template.FromStoreID != "":
+		stocks, err := s.stockRepo.GetAllByStore(ctx, template.FromStoreID)
+		if err != nil {
+			return nil, err
+		}
+		if len(stocks) == 0 {
+			return nil, &domain.NotFoundError{Resource: "Assortment", ID: "store=" + template.FromStoreID}
+		}
+		for _, stock := range stocks {
+			if template.Category != "" {
+				product, err := s.productRepo.GetByID(ctx, stock.ProductID)
+				if err != nil {
+					return nil, err
+				}
+				if product.Category != template.Category {
+					continue
+				}
+			}
+			add(stock.ProductID)
+		}
+
+	default:
+		const pageSize = 100
+		for offset := 0; ; offset += pageSize {
+			products, err := s.productRepo.ListByCategory(ctx, template.Category, pageSize, offset, false)
+			if err != nil {
+				return nil, err
+			}
+			for _, product := range products {
+				add(product.ID)
+			}
+			if len(products) < pageSize {
+				break
+			}
+		}
+	}
+
+	return productIDs, nil
+}
+
+// issueAPIKey genera, persiste y activa una API key para la tienda
+func (s *StoreService) issueAPIKey(ctx context.Context, store *domain.Store) (*domain.StoreAPIKey, error) {
+	key, err := auth.GenerateKey(fmt.Sprintf("sk-%s-", strings.ToLower(store.ID)))
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &domain.StoreAPIKey{
+		ID:        domain.APIKeyID(key),
+		Name:      store.Name,
+		StoreID:   store.ID,
+		Key:       key,
+		CreatedAt: time.Now(),
+	}
+
+	hash := auth.HashKey(key)
+	if err := s.apiKeyRepo.Create(ctx, apiKey, hash); err != nil {
+		return nil, err
+	}
+	s.keyRing.AddHash(hash, store.Name, store.ID)
+
+	return apiKey, nil
+}
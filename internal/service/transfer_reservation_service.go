@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// TransferReservationService gestiona reservas servidas desde otra tienda mediante transferencia.
+// La reserva se crea en la tienda origen (donde hay stock) y se vincula a un borrador
+// de transferencia hacia la tienda preferida por el cliente.
+type TransferReservationService struct {
+	reservationService *ReservationService
+	reservationRepo    *repository.ReservationRepository
+	stockRepo          *repository.StockRepository
+	transferRepo       *repository.TransferRepository
+	eventRepo          *repository.EventRepository
+	publisher          domain.EventPublisher
+	groupRepo          *repository.StoreGroupRepository
+	featureFlags       *FeatureFlagService
+}
+
+// NewTransferReservationService crea una nueva instancia del servicio
+func NewTransferReservationService(
+	reservationService *ReservationService,
+	reservationRepo *repository.ReservationRepository,
+	stockRepo *repository.StockRepository,
+	transferRepo *repository.TransferRepository,
+	eventRepo *repository.EventRepository,
+	publisher domain.EventPublisher,
+) *TransferReservationService {
+	return &TransferReservationService{
+		reservationService: reservationService,
+		reservationRepo:    reservationRepo,
+		stockRepo:          stockRepo,
+		transferRepo:       transferRepo,
+		eventRepo:          eventRepo,
+		publisher:          publisher,
+	}
+}
+
+// SetStoreGroupRepository activa las restricciones de transferencia de los grupos de tiendas
+func (s *TransferReservationService) SetStoreGroupRepository(groupRepo *repository.StoreGroupRepository) {
+	s.groupRepo = groupRepo
+}
+
+// SetFeatureFlags activa el feature flag transfer_reservations, evaluado en la tienda preferida
+func (s *TransferReservationService) SetFeatureFlags(featureFlags *FeatureFlagService) {
+	s.featureFlags = featureFlags
+}
+
+// CreateTransferReservation reserva en una tienda origen y crea el borrador de transferencia
+// hacia la tienda preferida. Si sourceStoreID está vacío se elige la tienda con más disponibilidad.
+func (s *TransferReservationService) CreateTransferReservation(ctx context.Context, productID, preferredStoreID, sourceStoreID, customerID string, quantity, ttlMinutes int) (*domain.TransferReservation, error) {
+	if preferredStoreID == "" {
+		return nil, &domain.ValidationError{
+			Field:   "preferred_store_id",
+			Message: "preferred store is required",
+		}
+	}
+	if quantity <= 0 {
+		return nil, &domain.ValidationError{
+			Field:   "quantity",
+			Message: "quantity must be positive",
+		}
+	}
+	if sourceStoreID == preferredStoreID {
+		return nil, &domain.ValidationError{
+			Field:   "source_store_id",
+			Message: "source store must be different from the preferred store",
+		}
+	}
+	// Actúa la tienda preferida, por su cliente: la reserva en la tienda origen forma parte de la
+	// transferencia y no exige que la API key pueda escribir en ella
+	if err := domain.AuthorizeStoreWrite(ctx, preferredStoreID); err != nil {
+		return nil, err
+	}
+	sourceCtx := domain.WithStoreScope(ctx, nil)
+	if s.featureFlags != nil {
+		if err := s.featureFlags.Require(ctx, domain.FeatureTransferReservations, preferredStoreID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Solo tiene sentido transferir si la tienda preferida no puede servir la reserva
+	preferred, err := s.stockRepo.GetByProductAndStore(ctx, productID, preferredStoreID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+	} else if preferred.CanReserve(quantity) {
+		return nil, &domain.ConflictError{
+			Message: fmt.Sprintf("store %s has enough stock, create a regular reservation instead", preferredStoreID),
+		}
+	}
+
+	if sourceStoreID == "" {
+		sourceStoreID, err = s.pickSourceStore(ctx, productID, preferredStoreID, quantity)
+		if err != nil {
+			return nil, err
+		}
+	} else if err := checkTransferGroups(ctx, s.groupRepo, sourceStoreID, preferredStoreID); err != nil {
+		return nil, err
+	}
+
+	// Reservar en la tienda origen (bloquea el stock y publica reservation.created)
+	reservation, err := s.reservationService.CreateReservation(sourceCtx, productID, sourceStoreID, customerID, quantity, ttlMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := &domain.StockTransfer{
+		ID:            uuid.New().String(),
+		ProductID:     productID,
+		FromStoreID:   sourceStoreID,
+		ToStoreID:     preferredStoreID,
+		Quantity:      quantity,
+		Status:        domain.TransferStatusDraft,
+		ReservationID: reservation.ID,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := transfer.Validate(); err == nil {
+		err = s.transferRepo.Create(ctx, transfer)
+	}
+	if err != nil {
+		// Revertir la reserva para no dejar stock bloqueado sin transferencia
+		if cancelErr := s.reservationService.CancelReservation(sourceCtx, reservation.ID); cancelErr != nil {
+			log.Printf("Warning: failed to roll back reservation %s: %v", reservation.ID, cancelErr)
+		}
+		return nil, fmt.Errorf("failed to create transfer draft: %w", err)
+	}
+
+	s.publishTransferEvent(ctx, transfer)
+
+	return s.combine(reservation, transfer, preferredStoreID), nil
+}
+
+// GetTransferReservation obtiene la reserva y su transferencia con el estado combinado.
+// Si la reserva ya fue confirmada, cancelada o expiró, la transferencia se sincroniza.
+func (s *TransferReservationService) GetTransferReservation(ctx context.Context, reservationID string) (*domain.TransferReservation, error) {
+	reservation, err := s.reservationRepo.GetByID(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer, err := s.transferRepo.GetByReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.syncTransfer(ctx, reservation, transfer); err != nil {
+		return nil, err
+	}
+
+	return s.combine(reservation, transfer, transfer.ToStoreID), nil
+}
+
+// syncTransfer lleva la transferencia en borrador al estado que corresponde a la reserva
+func (s *TransferReservationService) syncTransfer(ctx context.Context, reservation *domain.Reservation, transfer *domain.StockTransfer) error {
+	target := domain.TransferStatusFor(reservation.Status)
+	if transfer.Status != domain.TransferStatusDraft || target == domain.TransferStatusDraft {
+		return nil
+	}
+
+	if err := s.transferRepo.UpdateStatus(ctx, transfer.ID, target); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	transfer.Status = target
+	transfer.UpdatedAt = &now
+
+	s.publishTransferEvent(ctx, transfer)
+	return nil
+}
+
+// pickSourceStore elige la tienda con mayor disponibilidad capaz de servir la cantidad,
+// descartando las que los grupos de tiendas no permiten transferir a la preferida
+func (s *TransferReservationService) pickSourceStore(ctx context.Context, productID, preferredStoreID string, quantity int) (string, error) {
+	stocks, err := s.stockRepo.GetAllByProduct(ctx, productID)
+	if err != nil {
+		return "", err
+	}
+
+	var groups []*domain.StoreGroup
+	if s.groupRepo != nil {
+		storeIDs := []string{preferredStoreID}
+		for _, stock := range stocks {
+			storeIDs = append(storeIDs, stock.StoreID)
+		}
+		if groups, err = s.groupRepo.ListByStores(ctx, storeIDs...); err != nil {
+			return "", err
+		}
+	}
+
+	best := ""
+	bestAvailable := 0
+	for _, stock := range stocks {
+		if stock.StoreID == preferredStoreID {
+			continue
+		}
+		if domain.CheckTransferAllowed(stock.StoreID, preferredStoreID, groups) != nil {
+			continue
+		}
+		if stock.Sellable() > bestAvailable {
+			best = stock.StoreID
+			bestAvailable = stock.Sellable()
+		}
+	}
+
+	if best == "" || bestAvailable < quantity {
+		return "", &domain.InsufficientStockError{
+			ProductID: productID,
+			StoreID:   preferredStoreID,
+			Available: bestAvailable,
+			Requested: quantity,
+		}
+	}
+
+	return best, nil
+}
+
+func (s *TransferReservationService) combine(reservation *domain.Reservation, transfer *domain.StockTransfer, preferredStoreID string) *domain.TransferReservation {
+	combined := &domain.TransferReservation{
+		Reservation:      reservation,
+		Transfer:         transfer,
+		PreferredStoreID: preferredStoreID,
+		SourceStoreID:    transfer.FromStoreID,
+	}
+	combined.State = combined.CombinedState()
+	return combined
+}
+
+func (s *TransferReservationService) publishTransferEvent(ctx context.Context, transfer *domain.StockTransfer) {
+	event := domain.NewTransferEvent(transfer)
+
+	// Persistir en BD
+	if err := s.eventRepo.Save(ctx, event); err != nil {
+		log.Printf("Warning: failed to save %s event: %v", event.EventType, err)
+	}
+
+	// Publicar a message broker
+	if err := s.publisher.Publish(ctx, event); publishFailed(err) {
+		log.Printf("Warning: failed to publish %s event: %v", event.EventType, err)
+	}
+}
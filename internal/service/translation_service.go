@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+)
+
+// TranslationService gestiona las traducciones de nombre y descripción de los productos
+// y localiza las lecturas del catálogo con fallback al idioma por defecto.
+type TranslationService struct {
+	translationRepo *repository.TranslationRepository
+	productRepo     *repository.ProductRepository
+	policy          domain.LocalePolicy
+}
+
+// NewTranslationService crea una nueva instancia del servicio
+func NewTranslationService(translationRepo *repository.TranslationRepository, productRepo *repository.ProductRepository, policy domain.LocalePolicy) *TranslationService {
+	return &TranslationService{
+		translationRepo: translationRepo,
+		productRepo:     productRepo,
+		policy:          policy,
+	}
+}
+
+// Policy retorna los idiomas configurados
+func (s *TranslationService) Policy() domain.LocalePolicy {
+	return s.policy
+}
+
+// ListTranslations lista las traducciones de un producto
+func (s *TranslationService) ListTranslations(ctx context.Context, productID string) ([]*domain.ProductTranslation, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.translationRepo.ListByProduct(ctx, productID)
+}
+
+// PutTranslation crea o reemplaza la traducción de un producto a un idioma
+func (s *TranslationService) PutTranslation(ctx context.Context, productID, locale, name, description string) (*domain.ProductTranslation, error) {
+	locale, err := s.policy.TranslationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	translation := &domain.ProductTranslation{
+		ProductID:   productID,
+		Locale:      locale,
+		Name:        strings.TrimSpace(name),
+		Description: description,
+		UpdatedAt:   time.Now(),
+	}
+	if err := translation.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.translationRepo.Upsert(ctx, translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+// DeleteTranslation elimina la traducción de un producto a un idioma
+func (s *TranslationService) DeleteTranslation(ctx context.Context, productID, locale string) error {
+	locale, err := s.policy.TranslationLocale(locale)
+	if err != nil {
+		return err
+	}
+	return s.translationRepo.Delete(ctx, productID, locale)
+}
+
+// Localize aplica a los productos sus traducciones al idioma indicado con una sola consulta.
+// Los productos sin traducción se quedan con el texto (y Locale) del idioma por defecto.
+func (s *TranslationService) Localize(ctx context.Context, locale string, products ...*domain.Product) error {
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		if product != nil {
+			product.Locale = s.policy.Default
+			ids = append(ids, product.ID)
+		}
+	}
+	if locale == s.policy.Default || len(ids) == 0 {
+		return nil
+	}
+
+	translations, err := s.translationRepo.ListByProductsAndLocale(ctx, ids, locale)
+	if err != nil {
+		return err
+	}
+
+	byProduct := make(map[string]*domain.ProductTranslation, len(translations))
+	for _, translation := range translations {
+		byProduct[translation.ProductID] = translation
+	}
+	for _, product := range products {
+		if product == nil {
+			continue
+		}
+		if translation, ok := byProduct[product.ID]; ok {
+			translation.Apply(product)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 -- =========================================
 -- Schema para Sistema de Inventario Distribuido
 -- Compatible con SQLite y PostgreSQL
+--
+-- Esquema completo para una instalación nueva. Las bases de datos
+-- existentes se actualizan con los pasos versionados de
+-- internal/database/schema_version.go (tabla schema_version).
 -- =========================================
 
 -- Tabla de productos (catálogo global)
@@ -11,27 +15,35 @@ CREATE TABLE IF NOT EXISTS products (
     description TEXT,
     category TEXT,
     price REAL NOT NULL CHECK (price >= 0),
+    status TEXT NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('DRAFT', 'ACTIVE', 'DISCONTINUED')),
+    abc_class TEXT NOT NULL DEFAULT '' CHECK (abc_class IN ('', 'A', 'B', 'C')), -- Clase ABC por valor de consumo ('' = sin clasificar)
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 -- Índices para products
 CREATE INDEX IF NOT EXISTS idx_products_sku ON products(sku);
+CREATE INDEX IF NOT EXISTS idx_products_sku_nocase ON products(sku COLLATE NOCASE);
+CREATE INDEX IF NOT EXISTS idx_products_status_created ON products(status, created_at);
 CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
+CREATE INDEX IF NOT EXISTS idx_products_abc_class ON products(abc_class);
 
 -- Tabla de stock (multi-tenant por store_id)
 CREATE TABLE IF NOT EXISTS stock (
     id TEXT PRIMARY KEY,
     product_id TEXT NOT NULL,
     store_id TEXT NOT NULL,              -- Identificador de la tienda
-    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    quantity INTEGER NOT NULL DEFAULT 0, -- Negativa solo con tolerancia de sobreventa (oversell_tolerances)
     reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    held INTEGER NOT NULL DEFAULT 0 CHECK (held >= 0), -- Parte de reserved retenida para uso interno (stock_holds)
+    min_stock INTEGER NOT NULL DEFAULT 0,
+    max_stock INTEGER NOT NULL DEFAULT 0,
+    safety_stock INTEGER NOT NULL DEFAULT 0 CHECK (safety_stock >= 0), -- Para venta en tienda: fuera de lo reservable
     version INTEGER NOT NULL DEFAULT 1,  -- Optimistic locking
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     
     FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
-    UNIQUE(product_id, store_id),        -- Un stock por producto-tienda
-    CHECK (reserved <= quantity)
+    UNIQUE(product_id, store_id)         -- Un stock por producto-tienda
 );
 
 -- Índices para stock
@@ -46,8 +58,11 @@ CREATE TABLE IF NOT EXISTS reservations (
     store_id TEXT NOT NULL,              -- Tienda donde se reserva
     customer_id TEXT NOT NULL,
     quantity INTEGER NOT NULL CHECK (quantity > 0),
-    status TEXT NOT NULL CHECK (status IN ('PENDING', 'CONFIRMED', 'CANCELLED', 'EXPIRED')),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'CONFIRMED', 'CANCELLED', 'EXPIRED', 'PREORDER', 'READY_FOR_PICKUP', 'PICKED_UP')),
+    priority TEXT NOT NULL DEFAULT 'NORMAL' CHECK (priority IN ('LOW', 'NORMAL', 'HIGH')),
+    channel TEXT NOT NULL DEFAULT '' CHECK (channel IN ('', 'WEB', 'STORE', 'MARKETPLACE')), -- Canal de venta ('' = sin canal)
     expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP,
     
@@ -58,8 +73,12 @@ CREATE TABLE IF NOT EXISTS reservations (
 CREATE INDEX IF NOT EXISTS idx_reservations_store ON reservations(store_id);
 CREATE INDEX IF NOT EXISTS idx_reservations_customer ON reservations(customer_id);
 CREATE INDEX IF NOT EXISTS idx_reservations_status ON reservations(status);
-CREATE INDEX IF NOT EXISTS idx_reservations_expires ON reservations(expires_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_reservations_expires ON reservations(expires_at) WHERE status IN ('PENDING', 'READY_FOR_PICKUP');
 CREATE INDEX IF NOT EXISTS idx_reservations_status_expires ON reservations(status, expires_at);
+CREATE INDEX IF NOT EXISTS idx_reservations_created ON reservations(created_at);
+CREATE INDEX IF NOT EXISTS idx_reservations_store_status_expires ON reservations(store_id, status, expires_at);
+CREATE INDEX IF NOT EXISTS idx_reservations_product_store_created ON reservations(product_id, store_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_reservations_customer_created ON reservations(customer_id, created_at);
 
 -- Tabla de eventos (Event Sourcing)
 CREATE TABLE IF NOT EXISTS events (
@@ -71,7 +90,12 @@ CREATE TABLE IF NOT EXISTS events (
     payload TEXT NOT NULL,               -- JSON con datos del evento
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     synced INTEGER DEFAULT 0,            -- SQLite usa INTEGER para boolean
-    synced_at TIMESTAMP NULL
+    synced_at TIMESTAMP NULL,
+    seq INTEGER UNIQUE,                  -- Posición en la cadena de auditoría
+    prev_hash TEXT,                      -- Hash del registro anterior
+    hash TEXT,                           -- SHA-256 de este registro + prev_hash
+    correlation_id TEXT,                 -- X-Request-ID del request que originó el evento
+    actor TEXT                           -- API key que originó el cambio ("system" en workers)
 );
 
 -- Índices para events
@@ -79,6 +103,608 @@ CREATE INDEX IF NOT EXISTS idx_events_type_created ON events(event_type, created
 CREATE INDEX IF NOT EXISTS idx_events_store ON events(store_id);
 CREATE INDEX IF NOT EXISTS idx_events_aggregate ON events(aggregate_id);
 CREATE INDEX IF NOT EXISTS idx_events_unsynced ON events(synced) WHERE synced = 0;
+CREATE INDEX IF NOT EXISTS idx_events_correlation ON events(correlation_id);
+
+-- Tabla de conflictos de sincronización de stock (política ADJUSTMENT_MERGE)
+CREATE TABLE IF NOT EXISTS conflicts (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    origin_instance TEXT,                -- Instancia que originó el cambio remoto
+    strategy TEXT NOT NULL,              -- Política aplicada
+    status TEXT NOT NULL CHECK (status IN ('AUTO_RESOLVED', 'UNRESOLVED', 'RESOLVED')),
+    local_version INTEGER NOT NULL,
+    local_quantity INTEGER NOT NULL,
+    local_reserved INTEGER NOT NULL,
+    remote_base_version INTEGER NOT NULL,
+    remote_quantity INTEGER NOT NULL,
+    remote_delta INTEGER NOT NULL,
+    resolved_quantity INTEGER,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL
+);
+
+-- Índices para conflicts
+CREATE INDEX IF NOT EXISTS idx_conflicts_status ON conflicts(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_conflicts_store ON conflicts(store_id);
+
+-- Tabla de números de serie vendidos (garantías)
+CREATE TABLE IF NOT EXISTS serial_registrations (
+    id TEXT PRIMARY KEY,
+    serial_number TEXT NOT NULL,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    reservation_id TEXT NOT NULL,
+    customer_id TEXT NOT NULL,
+    sold_at TIMESTAMP NOT NULL,
+    warranty_expires_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(product_id, serial_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_serials_serial ON serial_registrations(serial_number);
+CREATE INDEX IF NOT EXISTS idx_serials_reservation ON serial_registrations(reservation_id);
+
+-- Tabla de uso por API key (rollup diario)
+CREATE TABLE IF NOT EXISTS api_key_usage (
+    key_id TEXT NOT NULL,
+    day TEXT NOT NULL,
+    request_count INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (key_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_usage_last_used ON api_key_usage(key_id, last_used_at);
+
+-- Tabla de API keys emitidas en runtime (solo se guarda el hash)
+CREATE TABLE IF NOT EXISTS api_keys (
+    id TEXT PRIMARY KEY,
+    key_hash TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    store_id TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_store ON api_keys(store_id);
+
+-- Tabla de jobs de clonación de surtido entre tiendas
+CREATE TABLE IF NOT EXISTS assortment_clone_jobs (
+    id TEXT PRIMARY KEY,
+    source_store_id TEXT NOT NULL,
+    target_store_ids TEXT NOT NULL,
+    copy_thresholds INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED')),
+    report TEXT,
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_assortment_jobs_status ON assortment_clone_jobs(status);
+
+-- Tabla de transferencias entre tiendas (borradores vinculados a reservas)
+CREATE TABLE IF NOT EXISTS stock_transfers (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    from_store_id TEXT NOT NULL,
+    to_store_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status TEXT NOT NULL CHECK (status IN ('DRAFT', 'COMPLETED', 'CANCELLED')),
+    reservation_id TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (reservation_id) REFERENCES reservations(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_reservation ON stock_transfers(reservation_id);
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_status ON stock_transfers(status);
+
+-- Tabla de planes de descatalogación (run-down de stock por tienda)
+CREATE TABLE IF NOT EXISTS product_rundowns (
+    product_id TEXT PRIMARY KEY,
+    status TEXT NOT NULL CHECK (status IN ('IN_PROGRESS', 'COMPLETED')),
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_rundowns_status ON product_rundowns(status);
+
+-- Entradas de surtido (producto/tienda) incluidas en cada plan de run-down
+CREATE TABLE IF NOT EXISTS product_rundown_stores (
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    initial_quantity INTEGER NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('RUNNING_DOWN', 'ARCHIVED')),
+    archived_at TIMESTAMP NULL,
+    PRIMARY KEY (product_id, store_id),
+    FOREIGN KEY (product_id) REFERENCES product_rundowns(product_id) ON DELETE CASCADE
+);
+
+-- Filas de stock archivadas al eliminar un producto con force=true (sin FK: el producto ya no existe)
+CREATE TABLE IF NOT EXISTS archived_stock (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    product_sku TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    reserved INTEGER NOT NULL,
+    min_stock INTEGER NOT NULL DEFAULT 0,
+    max_stock INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP,
+    archived_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_archived_stock_product ON archived_stock(product_id);
+
+-- Reservas archivadas al eliminar un producto con force=true (las pendientes se archivan como CANCELLED)
+CREATE TABLE IF NOT EXISTS archived_reservations (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    product_sku TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    customer_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP NULL,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    archived_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_archived_reservations_product ON archived_reservations(product_id);
+
+-- Historial de cambios de precio (manuales y programados)
+CREATE TABLE IF NOT EXISTS price_history (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    old_price REAL NOT NULL,
+    new_price REAL NOT NULL,
+    actor TEXT NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('MANUAL', 'SCHEDULED')),
+    scheduled_id TEXT,
+    effective_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_history_product_effective ON price_history(product_id, effective_at);
+
+-- Cambios de precio programados que aplica el worker al llegar effective_at
+CREATE TABLE IF NOT EXISTS scheduled_price_changes (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    new_price REAL NOT NULL CHECK (new_price >= 0),
+    actor TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPLIED', 'CANCELLED')),
+    effective_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    applied_at TIMESTAMP NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_price_changes_status_effective ON scheduled_price_changes(status, effective_at);
+CREATE INDEX IF NOT EXISTS idx_scheduled_price_changes_product ON scheduled_price_changes(product_id);
+
+-- Eventos ya aplicados por cada consumidor (idempotencia at-least-once)
+CREATE TABLE IF NOT EXISTS processed_events (
+    consumer TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PROCESSING', 'DONE')),
+    claimed_at TIMESTAMP NOT NULL,
+    processed_at TIMESTAMP NULL,
+    PRIMARY KEY (consumer, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events(processed_at);
+
+-- Productos en modo alta contención (flash sale): las reservas pasan por una cola
+CREATE TABLE IF NOT EXISTS flash_sale_products (
+    product_id TEXT PRIMARY KEY,
+    enabled_by TEXT NOT NULL,
+    enabled_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Imágenes de productos (el contenido vive en el blob store: disco local o S3)
+CREATE TABLE IF NOT EXISTS product_media (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    alt_text TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_media_product_position ON product_media(product_id, position);
+
+-- Traducciones de nombre y descripción (el idioma por defecto vive en products)
+CREATE TABLE IF NOT EXISTS product_translations (
+    product_id TEXT NOT NULL,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, locale),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Visibilidad pública de la disponibilidad por tienda: con hide_quantities las consultas sin
+-- API key solo reciben el tramo (in_stock, low_stock, out_of_stock)
+CREATE TABLE IF NOT EXISTS stock_visibility (
+    store_id TEXT PRIMARY KEY,
+    hide_quantities INTEGER NOT NULL DEFAULT 0,
+    low_stock_threshold INTEGER NOT NULL DEFAULT 0 CHECK (low_stock_threshold >= 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Read model de disponibilidad (CQRS): una fila por (producto, tienda) con la cantidad vendible
+-- ya calculada. La mantiene el proyector con los eventos de stock y se puede reconstruir desde
+-- stock en cualquier momento; las lecturas de disponibilidad y stock bajo la consultan a ella
+CREATE TABLE IF NOT EXISTS availability_view (
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    available INTEGER NOT NULL,
+    min_stock INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL CHECK (status IN ('in_stock', 'low_stock', 'out_of_stock')),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, store_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_availability_view_available ON availability_view(available);
+CREATE INDEX IF NOT EXISTS idx_availability_view_store_available ON availability_view(store_id, available);
+
+-- Horario de apertura y corte de reservas por tienda (franjas en JSON, hora local de timezone)
+CREATE TABLE IF NOT EXISTS store_hours (
+    store_id TEXT PRIMARY KEY,
+    timezone TEXT NOT NULL,
+    periods TEXT NOT NULL,
+    cutoff_minutes INTEGER NOT NULL DEFAULT 0 CHECK (cutoff_minutes >= 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Último latido de cada instancia edge (eventos pendientes y última sincronización con la central)
+CREATE TABLE IF NOT EXISTS store_heartbeats (
+    instance_id TEXT PRIMARY KEY,
+    store_id TEXT NOT NULL,
+    pending_events INTEGER NOT NULL DEFAULT 0 CHECK (pending_events >= 0),
+    last_sync_at TIMESTAMP,
+    received_at TIMESTAMP NOT NULL,
+    alerted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_store_heartbeats_store ON store_heartbeats(store_id);
+
+-- Contacto de los clientes para los avisos de sus reservas (las reservas solo guardan customer_id)
+CREATE TABLE IF NOT EXISTS customer_contacts (
+    customer_id TEXT PRIMARY KEY,
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    push_token TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Tipos de ubicación desde los que cada canal sirve envíos y recogidas, en orden de preferencia
+-- (listas separadas por comas; los canales sin fila usan la política por defecto)
+CREATE TABLE IF NOT EXISTS fulfillment_policies (
+    channel TEXT PRIMARY KEY,
+    ship_types TEXT NOT NULL DEFAULT '',
+    pickup_types TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Configuración de los avisos a clientes de cada tienda (sin fila = sin avisos)
+CREATE TABLE IF NOT EXISTS store_notification_settings (
+    store_id TEXT PRIMARY KEY,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    channels TEXT NOT NULL,
+    kinds TEXT NOT NULL DEFAULT '',
+    expiring_before_minutes INTEGER NOT NULL DEFAULT 0 CHECK (expiring_before_minutes >= 0),
+    email_from TEXT NOT NULL DEFAULT '',
+    templates TEXT NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Avisos de reservas enviados o intentados: como mucho uno por (reserva, tipo, canal)
+CREATE TABLE IF NOT EXISTS reservation_notifications (
+    id TEXT PRIMARY KEY,
+    reservation_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('RESERVATION_CREATED', 'RESERVATION_EXPIRING', 'RESERVATION_EXPIRED', 'RESERVATION_CONFIRMED')),
+    channel TEXT NOT NULL CHECK (channel IN ('EMAIL', 'SMS', 'PUSH')),
+    recipient TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SENT', 'FAILED', 'SKIPPED')),
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+    UNIQUE (reservation_id, kind, channel)
+);
+
+-- Grupos de tiendas (regiones, franquicias) para reportes agregados y transferencias acotadas
+CREATE TABLE IF NOT EXISTS store_groups (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    group_type TEXT NOT NULL CHECK (group_type IN ('REGION', 'FRANCHISE')),
+    restrict_transfers INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tiendas de cada grupo (una tienda pertenece como mucho a un grupo de cada tipo)
+CREATE TABLE IF NOT EXISTS store_group_members (
+    group_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    group_type TEXT NOT NULL,
+    PRIMARY KEY (group_id, store_id),
+    UNIQUE (store_id, group_type),
+    FOREIGN KEY (group_id) REFERENCES store_groups(id) ON DELETE CASCADE
+);
+
+-- Intenciones de reserva (add-to-cart): interés y disponibilidad registrados sin bloquear stock
+CREATE TABLE IF NOT EXISTS reservation_intents (
+    token TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    customer_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    available_quantity INTEGER NOT NULL,
+    reservation_id TEXT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    converted_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_intents_expires ON reservation_intents(expires_at);
+
+-- Unidades de medida por producto: el stock se guarda en base_unit y conversions (JSON)
+-- define cuántas unidades base tiene cada unidad de pedido (p. ej. BOX = 12 EACH)
+CREATE TABLE IF NOT EXISTS product_units (
+    product_id TEXT PRIMARY KEY,
+    base_unit TEXT NOT NULL,
+    conversions TEXT NOT NULL,
+    rounding TEXT NOT NULL DEFAULT 'NEAREST', -- Redondeo de cantidades decimales: NEAREST, DOWN, UP o EXACT
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Tolerancia de sobreventa: unidades que la disponibilidad (quantity - reserved) puede quedar por
+-- debajo de cero en tiendas con reposición desde almacén. La de producto prevalece sobre la de tienda.
+CREATE TABLE IF NOT EXISTS oversell_tolerances (
+    scope TEXT NOT NULL CHECK (scope IN ('STORE', 'PRODUCT')),
+    scope_id TEXT NOT NULL,
+    max_units INTEGER NOT NULL CHECK (max_units > 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, scope_id)
+);
+
+-- Asignación de stock por canal de venta: unidades vendibles de una fila de stock reservadas para
+-- un canal (WEB, STORE, MARKETPLACE). reserved = reservas pendientes del canal contra la asignación.
+CREATE TABLE IF NOT EXISTS channel_allocations (
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    channel TEXT NOT NULL CHECK (channel IN ('WEB', 'STORE', 'MARKETPLACE')),
+    allocated INTEGER NOT NULL DEFAULT 0 CHECK (allocated >= 0),
+    reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, store_id, channel),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Cambios de stock programados: ajustes (ADJUST) o cantidades fijas (SET) que el worker aplica
+-- al llegar effective_at. FAILED = rechazado al aplicarse (p. ej. dejaría stock negativo).
+CREATE TABLE IF NOT EXISTS scheduled_stock_changes (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('ADJUST', 'SET')),
+    quantity INTEGER NOT NULL,
+    actor TEXT NOT NULL,
+    source TEXT NOT NULL DEFAULT 'MANUAL' CHECK (source IN ('MANUAL', 'PURCHASE_ORDER', 'TRANSFER')), -- Origen de la entrada prevista
+    reference TEXT NOT NULL DEFAULT '', -- Nº de pedido de compra o de transferencia
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPLIED', 'CANCELLED', 'FAILED')),
+    error TEXT NOT NULL DEFAULT '',
+    effective_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    applied_at TIMESTAMP NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_stock_changes_status_effective ON scheduled_stock_changes(status, effective_at);
+CREATE INDEX IF NOT EXISTS idx_scheduled_stock_changes_stock ON scheduled_stock_changes(product_id, store_id);
+
+-- Reservas importadas de un OMS externo durante una migración (external_id → reserva creada).
+-- Permite repetir la importación sin duplicar reservas.
+CREATE TABLE IF NOT EXISTS reservation_imports (
+    external_id TEXT PRIMARY KEY,
+    reservation_id TEXT NOT NULL,
+    product_id TEXT NOT NULL,
+    imported_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_imports_reservation ON reservation_imports(reservation_id);
+
+-- Recogida en tienda (click-and-collect) de las reservas marcadas READY_FOR_PICKUP: hash del código
+-- que presenta el cliente, intentos fallidos y plazo de recogida (pickup_by = expires_at de la reserva)
+CREATE TABLE IF NOT EXISTS reservation_pickups (
+    reservation_id TEXT PRIMARY KEY,
+    store_id TEXT NOT NULL,
+    code_hash TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    ready_at TIMESTAMP NOT NULL,
+    pickup_by TIMESTAMP NOT NULL,
+    picked_up_at TIMESTAMP,
+    FOREIGN KEY (reservation_id) REFERENCES reservations(id) ON DELETE CASCADE
+);
+
+-- Grupos de reservas (envío partido): reservas hijas en distintas tiendas que se confirman o
+-- cancelan juntas. Una reserva pertenece como mucho a un grupo.
+CREATE TABLE IF NOT EXISTS reservation_groups (
+    id TEXT PRIMARY KEY,
+    customer_id TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS reservation_group_members (
+    reservation_id TEXT PRIMARY KEY,
+    group_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    FOREIGN KEY (group_id) REFERENCES reservation_groups(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_group_members_group ON reservation_group_members(group_id, position);
+
+-- Exportaciones asíncronas (STOCK, MOVEMENTS, RESERVATIONS): un worker genera el fichero en el
+-- almacenamiento de blobs (blob_key) y se borra al llegar expires_at
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id TEXT PRIMARY KEY,
+    type TEXT NOT NULL CHECK (type IN ('STOCK', 'MOVEMENTS', 'RESERVATIONS')),
+    format TEXT NOT NULL CHECK (format IN ('CSV', 'JSON')),
+    filters TEXT NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED')),
+    actor TEXT NOT NULL,
+    row_count INTEGER NOT NULL DEFAULT 0,
+    size_bytes INTEGER NOT NULL DEFAULT 0,
+    blob_key TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_jobs_status_created ON export_jobs(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_expires ON export_jobs(expires_at);
+
+-- Backfill de eventos stock.snapshot: cursor (last_product_id, last_store_id) guardado tras
+-- cada lote para reanudar una ejecución interrumpida
+CREATE TABLE IF NOT EXISTS snapshot_backfills (
+    id TEXT PRIMARY KEY,
+    status TEXT NOT NULL CHECK (status IN ('RUNNING', 'COMPLETED')),
+    last_product_id TEXT NOT NULL DEFAULT '',
+    last_store_id TEXT NOT NULL DEFAULT '',
+    emitted INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_snapshot_backfills_status ON snapshot_backfills(status);
+
+-- Checkpoints de la cadena de auditoría (último evento borrado por la retención)
+CREATE TABLE IF NOT EXISTS audit_checkpoints (
+    seq INTEGER PRIMARY KEY,
+    hash TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Feature flags configurados desde /admin/feature-flags (store_id '' = todas las tiendas).
+-- Prevalecen sobre FEATURE_FLAGS.
+CREATE TABLE IF NOT EXISTS feature_flags (
+    feature TEXT NOT NULL,
+    store_id TEXT NOT NULL DEFAULT '',
+    enabled INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (feature, store_id)
+);
+
+-- Ajustes manuales de stock que superan el umbral de aprobación (ADJUSTMENT_APPROVAL_*): no
+-- cambian el stock hasta que otra API key los aprueba o los rechaza.
+CREATE TABLE IF NOT EXISTS stock_adjustments (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('ADJUST', 'SET')),
+    quantity INTEGER NOT NULL,
+    current_quantity INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    requested_by TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED')),
+    reviewed_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_adjustments_status_created ON stock_adjustments(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_stock_adjustments_stock ON stock_adjustments(product_id, store_id);
+
+-- Retenciones internas de stock (exposición, reparación, calidad): cuentan en stock.reserved y
+-- stock.held mientras están ACTIVE. No tienen cliente ni expiran; el personal las libera.
+CREATE TABLE IF NOT EXISTS stock_holds (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('DISPLAY', 'REPAIR', 'QUALITY_HOLD')),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    note TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('ACTIVE', 'RELEASED')),
+    created_by TEXT NOT NULL,
+    released_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_holds_stock ON stock_holds(product_id, store_id, status);
+CREATE INDEX IF NOT EXISTS idx_stock_holds_status_created ON stock_holds(status, created_at);
+
+-- Notas del personal sobre una fila de stock o uno de sus movimientos (event_id vacío = la fila),
+-- con los metadatos de sus fotos o documentos adjuntos (JSON)
+CREATE TABLE IF NOT EXISTS stock_notes (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    event_id TEXT NOT NULL DEFAULT '',
+    text TEXT NOT NULL,
+    attachments TEXT NOT NULL DEFAULT '[]',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_notes_stock ON stock_notes(product_id, store_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_stock_notes_event ON stock_notes(event_id);
+
+-- Respuestas guardadas por Idempotency-Key (scope = hash de la API key), para repetir la
+-- respuesta de las escrituras reintentadas en lugar de aplicarlas dos veces. status 0 = en curso.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope TEXT NOT NULL,
+    idempotency_key TEXT NOT NULL,
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    content_type TEXT NOT NULL DEFAULT '',
+    body BLOB,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created ON idempotency_keys(created_at);
+
+-- Catálogo de motivos de los ajustes manuales de stock (POST /adjust, PUT /stock). Los motivos
+-- no se borran porque los movimientos (stock.updated) guardan su código: se desactivan.
+CREATE TABLE IF NOT EXISTS adjustment_reasons (
+    code TEXT PRIMARY KEY,
+    description TEXT NOT NULL,
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
 
 -- Tabla de tiendas (metadata, opcional pero útil)
 CREATE TABLE IF NOT EXISTS stores (
@@ -90,9 +716,17 @@ CREATE TABLE IF NOT EXISTS stores (
     phone TEXT,
     email TEXT,
     active INTEGER DEFAULT 1,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    location_type TEXT NOT NULL DEFAULT 'STORE' CHECK (location_type IN ('STORE', 'WAREHOUSE', 'DARKSTORE'))
 );
 
+-- Motivos de ajuste iniciales
+INSERT OR IGNORE INTO adjustment_reasons (code, description) VALUES
+    ('damaged', 'Producto dañado o roto'),
+    ('shrinkage', 'Merma: robo, pérdida o caducidad'),
+    ('found', 'Unidades encontradas en un recuento'),
+    ('correction', 'Corrección de un error de registro');
+
 -- =========================================
 -- Datos de ejemplo para testing
 -- =========================================
@@ -0,0 +1,163 @@
+// Package edge es el cliente embebible para terminales de punto de venta (TPV): habla con la API
+// v1 del inventario y, cuando no hay conexión, guarda las escrituras en una cola SQLite local y
+// las reenvía al recuperarla con su Idempotency-Key, de modo que ninguna se aplique dos veces.
+//
+// Uso típico:
+//
+//	queue, err := edge.OpenQueue("/var/lib/pos/edge-queue.db")
+//	client := edge.NewClient("https://inventory.example.com", apiKey, nil)
+//	pos := edge.New(client, queue)
+//	go pos.Run(ctx, 30*time.Second)
+//
+//	stock, _, err := pos.AdjustStock(ctx, productID, "MAD-001", -1, "sale")
+//	if errors.Is(err, edge.ErrQueued) {
+//		// Sin conexión: se aplicará al reenviar la cola
+//	}
+package edge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+// defaultTimeout timeout de cada petición si no se pasa un http.Client propio
+const defaultTimeout = 15 * time.Second
+
+// Client cliente HTTP de la API v1 del inventario, autenticado con una API key
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient crea un cliente para la API en baseURL (sin /api/v1). httpClient nil = uno con
+// timeout de 15 segundos.
+func NewClient(baseURL, apiKey string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/") + "/api/v1",
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// APIError respuesta de error de la API
+type APIError struct {
+	StatusCode int
+	Title      string // Campo error de la respuesta ("Not Found", "Insufficient Stock"...)
+	Message    string
+	RetryAfter time.Duration // Retry-After de la respuesta (0 si no lo trae)
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("inventory API: %d %s", e.StatusCode, e.Title)
+	}
+	return fmt.Sprintf("inventory API: %d %s: %s", e.StatusCode, e.Title, e.Message)
+}
+
+// Temporary indica si la misma petición puede funcionar más tarde: errores del servidor,
+// timeouts, límites de peticiones y peticiones con la misma Idempotency-Key aún en curso
+func (e *APIError) Temporary() bool {
+	switch {
+	case e.StatusCode >= http.StatusInternalServerError,
+		e.StatusCode == http.StatusRequestTimeout,
+		e.StatusCode == http.StatusTooManyRequests,
+		e.StatusCode == http.StatusConflict && e.RetryAfter > 0:
+		return true
+	}
+	return false
+}
+
+// GetStock obtiene la fila de stock de un producto en una tienda
+func (c *Client) GetStock(ctx context.Context, productID, storeID string) (*Stock, error) {
+	var stock Stock
+	if err := c.Do(ctx, http.MethodGet, stockPath(productID, storeID), "", nil, &stock); err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// GetReservation obtiene una reserva
+func (c *Client) GetReservation(ctx context.Context, id string) (*Reservation, error) {
+	var reservation Reservation
+	if err := c.Do(ctx, http.MethodGet, "/reservations/"+id, "", nil, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// Do envía una petición a la API (path relativo a /api/v1) y decodifica la respuesta en out
+// (nil = descartarla). Con idempotencyKey la petición se envía con Idempotency-Key.
+func (c *Client) Do(ctx context.Context, method, path, idempotencyKey string, body []byte, out interface{}) error {
+	_, err := c.do(ctx, method, path, idempotencyKey, body, out)
+	return err
+}
+
+// do es Do retornando además el código de estado de la respuesta
+func (c *Client) do(ctx context.Context, method, path, idempotencyKey string, body []byte, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set(domain.IdempotencyKeyHeader, idempotencyKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Title: http.StatusText(resp.StatusCode)}
+		var payload struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(raw, &payload) == nil && payload.Error != "" {
+			apiErr.Title, apiErr.Message = payload.Error, payload.Message
+		}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return resp.StatusCode, apiErr
+	}
+
+	if out != nil && len(raw) > 0 {
+		if err := json.Unmarshal(raw, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+func stockPath(productID, storeID string) string {
+	return "/stock/" + productID + "/" + storeID
+}
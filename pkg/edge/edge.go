@@ -0,0 +1,237 @@
+package edge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrQueued la API no estaba disponible: la escritura quedó en la cola local y se aplicará al
+// reenviarla (Flush o Run). No es un fallo: el TPV puede seguir vendiendo.
+var ErrQueued = errors.New("edge: inventory API unavailable, mutation queued for replay")
+
+// ErrReservationTicket el producto está en flash sale: la API aceptó la reserva como ticket (202)
+// y la resuelve en segundo plano; el resultado se consulta en la API
+var ErrReservationTicket = errors.New("edge: reservation accepted as a flash sale ticket")
+
+// flushBatchSize escrituras leídas de la cola en cada vuelta del reenvío
+const flushBatchSize = 100
+
+// Edge cliente store-and-forward: envía las escrituras a la API y, si no hay conexión (o la API
+// responde con un error temporal), las guarda en la cola para reenviarlas en orden. Cada
+// escritura lleva su Idempotency-Key desde el primer intento, así que un reenvío de algo que la
+// API ya aplicó (p. ej. se perdió la respuesta) recibe la respuesta original en lugar de
+// aplicarse dos veces.
+type Edge struct {
+	client *Client
+	queue  *Queue
+	mu     sync.Mutex // Serializa envíos y reenvíos para conservar el orden de la cola
+}
+
+// New crea el cliente store-and-forward
+func New(client *Client, queue *Queue) *Edge {
+	return &Edge{client: client, queue: queue}
+}
+
+// Client retorna el cliente de la API, para las lecturas (no se encolan)
+func (e *Edge) Client() *Client {
+	return e.client
+}
+
+// Queue retorna la cola local, p. ej. para revisar las escrituras rechazadas
+func (e *Edge) Queue() *Queue {
+	return e.queue
+}
+
+// AdjustStock ajusta el stock de una fila (positivo o negativo) con un motivo del catálogo. Si
+// el ajuste queda pendiente de aprobación retorna el ajuste pendiente en lugar de la fila.
+func (e *Edge) AdjustStock(ctx context.Context, productID, storeID string, adjustment int, reason string) (*Stock, *StockAdjustment, error) {
+	var raw json.RawMessage
+	status, err := e.submit(ctx, http.MethodPost, stockPath(productID, storeID)+"/adjust", map[string]interface{}{
+		"adjustment": adjustment,
+		"reason":     reason,
+	}, &raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if status == http.StatusAccepted {
+		var pending StockAdjustment
+		if err := json.Unmarshal(raw, &pending); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil, &pending, nil
+	}
+	var stock Stock
+	if err := json.Unmarshal(raw, &stock); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &stock, nil, nil
+}
+
+// CreateReservation crea una reserva. Encolada, su ID no se conoce hasta reenviarla.
+func (e *Edge) CreateReservation(ctx context.Context, req ReservationRequest) (*Reservation, error) {
+	var raw json.RawMessage
+	status, err := e.submit(ctx, http.MethodPost, "/reservations", req, &raw)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusAccepted {
+		return nil, ErrReservationTicket
+	}
+
+	var reservation Reservation
+	if err := json.Unmarshal(raw, &reservation); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &reservation, nil
+}
+
+// ConfirmReservation confirma una reserva (procesa la venta)
+func (e *Edge) ConfirmReservation(ctx context.Context, id string) error {
+	_, err := e.submit(ctx, http.MethodPost, "/reservations/"+id+"/confirm", nil, nil)
+	return err
+}
+
+// CancelReservation cancela una reserva
+func (e *Edge) CancelReservation(ctx context.Context, id string) error {
+	_, err := e.submit(ctx, http.MethodPost, "/reservations/"+id+"/cancel", nil, nil)
+	return err
+}
+
+// submit envía la escritura con una Idempotency-Key nueva o la encola (ErrQueued). Con
+// escrituras pendientes primero intenta reenviarlas; si siguen pendientes la nueva va detrás.
+func (e *Edge) submit(ctx context.Context, method, path string, payload interface{}, out *json.RawMessage) (int, error) {
+	mutation := &Mutation{
+		IdempotencyKey: uuid.New().String(),
+		Method:         method,
+		Path:           path,
+	}
+	if payload != nil {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode request: %w", err)
+		}
+		mutation.Body = body
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pending, err := e.queue.Len(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if pending > 0 {
+		if _, err := e.flush(ctx); err != nil && !isTemporary(err) {
+			return 0, err
+		}
+		if pending, err = e.queue.Len(ctx); err != nil {
+			return 0, err
+		}
+		if pending > 0 {
+			return 0, e.enqueue(ctx, mutation)
+		}
+	}
+
+	var target interface{}
+	if out != nil {
+		target = out
+	}
+	status, err := e.client.do(ctx, method, path, mutation.IdempotencyKey, mutation.Body, target)
+	if err != nil && isTemporary(err) {
+		return 0, e.enqueue(ctx, mutation)
+	}
+	return status, err
+}
+
+// enqueue guarda la escritura aunque el context se haya cancelado: puede que la API ya la
+// aplicara y el reenvío con la misma clave lo resuelve
+func (e *Edge) enqueue(ctx context.Context, mutation *Mutation) error {
+	if err := e.queue.Enqueue(context.WithoutCancel(ctx), mutation); err != nil {
+		return err
+	}
+	return ErrQueued
+}
+
+// FlushResult resultado de un reenvío de la cola
+type FlushResult struct {
+	Sent   int // Aplicadas (o ya aplicadas antes: respuesta repetida por la API)
+	Failed int // Rechazadas por la API: quedan en Queue().Failed
+}
+
+// Flush reenvía en orden las escrituras pendientes. Se detiene en el primer error temporal (sin
+// conexión todavía), que retorna; las rechazadas por la API se apartan y el reenvío continúa.
+func (e *Edge) Flush(ctx context.Context) (FlushResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.flush(ctx)
+}
+
+func (e *Edge) flush(ctx context.Context) (FlushResult, error) {
+	var result FlushResult
+	for {
+		batch, err := e.queue.Pending(ctx, flushBatchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(batch) == 0 {
+			return result, nil
+		}
+
+		for _, mutation := range batch {
+			_, err := e.client.do(ctx, mutation.Method, mutation.Path, mutation.IdempotencyKey, mutation.Body, nil)
+			switch {
+			case err == nil:
+				if err := e.queue.Delete(ctx, mutation.ID); err != nil {
+					return result, err
+				}
+				result.Sent++
+			case isTemporary(err):
+				if recordErr := e.queue.RecordAttempt(context.WithoutCancel(ctx), mutation.ID, err); recordErr != nil {
+					return result, recordErr
+				}
+				return result, err
+			default:
+				if err := e.queue.MarkFailed(ctx, mutation.ID, err); err != nil {
+					return result, err
+				}
+				result.Failed++
+			}
+		}
+	}
+}
+
+// Run reenvía la cola cada interval hasta que se cancele ctx. Los errores temporales se
+// reintentan en la siguiente vuelta.
+func (e *Edge) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = e.Flush(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isTemporary indica si el error es de conexión o una respuesta temporal de la API
+func isTemporary(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,162 @@
+package edge
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // SQLite driver (pure Go)
+)
+
+// Estados de una escritura en la cola
+const (
+	MutationPending = "PENDING" // Pendiente de reenviar
+	MutationFailed  = "FAILED"  // La API la rechazó: queda para revisión, no se reenvía
+)
+
+// Mutation escritura guardada en la cola local, con la Idempotency-Key con la que se reenvía
+type Mutation struct {
+	ID             int64
+	IdempotencyKey string
+	Method         string
+	Path           string // Relativo a /api/v1
+	Body           []byte
+	Status         string
+	Attempts       int
+	LastError      string
+	CreatedAt      time.Time
+}
+
+// Queue cola de escrituras pendientes en un fichero SQLite local. Se reenvían en el orden en
+// que se encolaron.
+type Queue struct {
+	db *sql.DB
+}
+
+// OpenQueue abre (o crea) la cola en path
+func OpenQueue(path string) (*Queue, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open edge queue: %w", err)
+	}
+	// Una sola conexión: SQLite serializa las escrituras y así ":memory:" es una única base
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS edge_mutations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			idempotency_key TEXT NOT NULL UNIQUE,
+			method TEXT NOT NULL,
+			path TEXT NOT NULL,
+			body BLOB,
+			status TEXT NOT NULL DEFAULT 'PENDING',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_edge_mutations_status ON edge_mutations(status, id);
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create edge queue: %w", err)
+	}
+	return &Queue{db: db}, nil
+}
+
+// Close cierra el fichero de la cola
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+// Enqueue guarda una escritura pendiente
+func (q *Queue) Enqueue(ctx context.Context, mutation *Mutation) error {
+	mutation.Status = MutationPending
+	if mutation.CreatedAt.IsZero() {
+		mutation.CreatedAt = time.Now()
+	}
+	result, err := q.db.ExecContext(ctx, `
+		INSERT INTO edge_mutations (idempotency_key, method, path, body, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, mutation.IdempotencyKey, mutation.Method, mutation.Path, mutation.Body, mutation.Status, mutation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue mutation: %w", err)
+	}
+	mutation.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// Len cuenta las escrituras pendientes de reenviar
+func (q *Queue) Len(ctx context.Context) (int, error) {
+	var count int
+	err := q.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM edge_mutations WHERE status = ?`, MutationPending).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending mutations: %w", err)
+	}
+	return count, nil
+}
+
+// Pending lista hasta limit escrituras pendientes, de la más antigua a la más reciente
+func (q *Queue) Pending(ctx context.Context, limit int) ([]*Mutation, error) {
+	return q.list(ctx, MutationPending, limit)
+}
+
+// Failed lista hasta limit escrituras rechazadas por la API, para revisarlas en el TPV
+func (q *Queue) Failed(ctx context.Context, limit int) ([]*Mutation, error) {
+	return q.list(ctx, MutationFailed, limit)
+}
+
+// Delete elimina una escritura (ya aplicada o descartada)
+func (q *Queue) Delete(ctx context.Context, id int64) error {
+	if _, err := q.db.ExecContext(ctx, `DELETE FROM edge_mutations WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete mutation: %w", err)
+	}
+	return nil
+}
+
+// RecordAttempt registra un reenvío fallido por un error temporal: sigue pendiente
+func (q *Queue) RecordAttempt(ctx context.Context, id int64, cause error) error {
+	_, err := q.db.ExecContext(ctx, `
+		UPDATE edge_mutations SET attempts = attempts + 1, last_error = ? WHERE id = ?
+	`, cause.Error(), id)
+	if err != nil {
+		return fmt.Errorf("failed to record mutation attempt: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed deja la escritura como rechazada por la API
+func (q *Queue) MarkFailed(ctx context.Context, id int64, cause error) error {
+	_, err := q.db.ExecContext(ctx, `
+		UPDATE edge_mutations SET status = ?, attempts = attempts + 1, last_error = ? WHERE id = ?
+	`, MutationFailed, cause.Error(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark mutation failed: %w", err)
+	}
+	return nil
+}
+
+func (q *Queue) list(ctx context.Context, status string, limit int) ([]*Mutation, error) {
+	rows, err := q.db.QueryContext(ctx, `
+		SELECT id, idempotency_key, method, path, body, status, attempts, last_error, created_at
+		FROM edge_mutations
+		WHERE status = ?
+		ORDER BY id
+		LIMIT ?
+	`, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mutations: %w", err)
+	}
+	defer rows.Close()
+
+	mutations := make([]*Mutation, 0)
+	for rows.Next() {
+		var mutation Mutation
+		if err := rows.Scan(&mutation.ID, &mutation.IdempotencyKey, &mutation.Method, &mutation.Path, &mutation.Body,
+			&mutation.Status, &mutation.Attempts, &mutation.LastError, &mutation.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan mutation: %w", err)
+		}
+		mutations = append(mutations, &mutation)
+	}
+	return mutations, rows.Err()
+}
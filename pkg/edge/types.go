@@ -0,0 +1,31 @@
+package edge
+
+import "inventory-system/internal/domain"
+
+// Tipos de dominio de la API v1, re-exportados para que el código del TPV no dependa de
+// paquetes internos del servidor
+type (
+	Stock               = domain.Stock
+	StockAdjustment     = domain.StockAdjustment
+	Reservation         = domain.Reservation
+	ReservationStatus   = domain.ReservationStatus
+	ReservationPriority = domain.ReservationPriority
+)
+
+// Estados de reserva
+const (
+	ReservationStatusPending   = domain.ReservationStatusPending
+	ReservationStatusConfirmed = domain.ReservationStatusConfirmed
+	ReservationStatusCancelled = domain.ReservationStatusCancelled
+	ReservationStatusExpired   = domain.ReservationStatusExpired
+)
+
+// ReservationRequest datos de una reserva nueva (StoreID opcional con una API key de una sola tienda)
+type ReservationRequest struct {
+	ProductID  string              `json:"product_id"`
+	StoreID    string              `json:"store_id,omitempty"`
+	CustomerID string              `json:"customer_id"`
+	Quantity   int                 `json:"quantity"`
+	TTLMinutes int                 `json:"ttl_minutes,omitempty"`
+	Priority   ReservationPriority `json:"priority,omitempty"`
+}
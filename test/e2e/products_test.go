@@ -169,10 +169,15 @@ func TestProducts_InvalidOperations(t *testing.T) {
 	})
 
 	t.Run("GetProduct_NotFound", func(t *testing.T) {
-		resp, body := client.GET(t, "/products/non-existent-id")
+		resp, body := client.GET(t, "/products/00000000-0000-0000-0000-000000000000")
 		AssertStatusCode(t, http.StatusNotFound, resp.StatusCode, body)
 	})
 
+	t.Run("GetProduct_InvalidID", func(t *testing.T) {
+		resp, body := client.GET(t, "/products/non-existent-id")
+		AssertStatusCode(t, http.StatusBadRequest, resp.StatusCode, body)
+	})
+
 	t.Run("GetProductBySKU_NotFound", func(t *testing.T) {
 		resp, body := client.GET(t, "/products/sku/NON-EXISTENT-SKU")
 		AssertStatusCode(t, http.StatusNotFound, resp.StatusCode, body)
@@ -187,12 +192,12 @@ func TestProducts_InvalidOperations(t *testing.T) {
 			"price":       10.0,
 		}
 
-		resp, body := client.PUT(t, "/products/non-existent-id", update)
+		resp, body := client.PUT(t, "/products/00000000-0000-0000-0000-000000000000", update)
 		AssertStatusCode(t, http.StatusNotFound, resp.StatusCode, body)
 	})
 
 	t.Run("DeleteProduct_NotFound", func(t *testing.T) {
-		resp, body := client.DELETE(t, "/products/non-existent-id")
+		resp, body := client.DELETE(t, "/products/00000000-0000-0000-0000-000000000000")
 		AssertStatusCode(t, http.StatusNotFound, resp.StatusCode, body)
 	})
 }
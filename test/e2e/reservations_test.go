@@ -133,12 +133,12 @@ func TestReservationsE2E_FullCycle(t *testing.T) {
 			ParseJSON(t, body, &stock)
 
 			// Nota: El stock reservado puede tardar en actualizarse
-			if stock.ReservedQty < 0 {
-				t.Errorf("Reserved quantity should not be negative, got %d", stock.ReservedQty)
+			if stock.Reserved < 0 {
+				t.Errorf("Reserved quantity should not be negative, got %d", stock.Reserved)
 			}
 			// Solo verificamos que no sea negativo, puede ser 0 si aún no se actualizó
 			t.Logf("Stock status: Total=%d, Reserved=%d, Available=%d",
-				stock.Quantity, stock.ReservedQty, stock.AvailableQty)
+				stock.Quantity, stock.Reserved, stock.Available())
 		})
 
 		// 4. Obtener reservas pendientes por tienda
@@ -201,8 +201,8 @@ func TestReservationsE2E_FullCycle(t *testing.T) {
 			if stock.Quantity != 45 { // 50 - 5
 				t.Errorf("Expected quantity 45 after confirmation, got %d", stock.Quantity)
 			}
-			if stock.ReservedQty != 0 {
-				t.Errorf("Expected reserved quantity 0 after confirmation, got %d", stock.ReservedQty)
+			if stock.Reserved != 0 {
+				t.Errorf("Expected reserved quantity 0 after confirmation, got %d", stock.Reserved)
 			}
 		})
 
@@ -225,7 +225,7 @@ func TestReservationsE2E_FullCycle(t *testing.T) {
 	})
 
 	// Cleanup
-	client.DELETE(t, "/products/"+productID)
+	client.DELETE(t, "/products/"+productID+"?force=true")
 }
 
 func TestReservations_CancelReservation(t *testing.T) {
@@ -280,7 +280,7 @@ func TestReservations_CancelReservation(t *testing.T) {
 	ParseJSON(t, body, &stockBefore)
 
 	// Solo verificamos que no sea negativo
-	t.Logf("Stock before cancel: Reserved=%d", stockBefore.ReservedQty)
+	t.Logf("Stock before cancel: Reserved=%d", stockBefore.Reserved)
 
 	// Cancelar reserva
 	resp, body = client.POST(t, "/reservations/"+reservationID+"/cancel", nil)
@@ -311,18 +311,18 @@ func TestReservations_CancelReservation(t *testing.T) {
 	ParseJSON(t, body, &stockAfter)
 
 	// Verificamos que el stock no sea negativo
-	if stockAfter.ReservedQty < 0 {
-		t.Errorf("Expected reserved quantity >= 0 after cancellation, got %d", stockAfter.ReservedQty)
+	if stockAfter.Reserved < 0 {
+		t.Errorf("Expected reserved quantity >= 0 after cancellation, got %d", stockAfter.Reserved)
 	}
-	if stockAfter.AvailableQty < 0 {
-		t.Errorf("Expected available quantity >= 0 after cancellation, got %d", stockAfter.AvailableQty)
+	if stockAfter.Available() < 0 {
+		t.Errorf("Expected available quantity >= 0 after cancellation, got %d", stockAfter.Available())
 	}
 
 	t.Logf("✅ Reservation cancelled. Stock after: Reserved=%d, Available=%d",
-		stockAfter.ReservedQty, stockAfter.AvailableQty)
+		stockAfter.Reserved, stockAfter.Available())
 
 	// Cleanup
-	client.DELETE(t, "/products/"+productID)
+	client.DELETE(t, "/products/"+productID+"?force=true")
 }
 
 func TestReservations_ExpiredReservation(t *testing.T) {
@@ -373,7 +373,7 @@ func TestReservations_ExpiredReservation(t *testing.T) {
 	// Verificar que tiene tiempo de expiración futuro (por defecto 30 minutos)
 	if created.ExpiresAt == "" {
 		t.Log("Warning: ExpiresAt field is empty, skipping expiration test")
-		client.DELETE(t, "/products/"+productID)
+		client.DELETE(t, "/products/"+productID+"?force=true")
 		return
 	}
 
@@ -398,7 +398,7 @@ func TestReservations_ExpiredReservation(t *testing.T) {
 	t.Logf("✅ Reservation created with expiration at %s", created.ExpiresAt)
 
 	// Cleanup
-	client.DELETE(t, "/products/"+productID)
+	client.DELETE(t, "/products/"+productID+"?force=true")
 }
 
 func TestReservations_InvalidOperations(t *testing.T) {
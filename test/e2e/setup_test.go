@@ -2,20 +2,84 @@ package e2e
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"inventory-system/internal/app"
+	"inventory-system/internal/config"
+
+	"github.com/gin-gonic/gin"
 )
 
-const (
-	baseURL = "http://localhost:8080"
-	apiV1   = baseURL + "/api/v1"
-	apiKey  = "dev-key-store-001" // API Key para tests E2E
+const apiKey = "dev-key-store-001" // API Key para tests E2E
+
+// baseURL y apiV1 apuntan al servidor bajo test. Por defecto TestMain levanta la
+// aplicación completa en proceso; con E2E_BASE_URL se ataca un servidor externo.
+var (
+	baseURL string
+	apiV1   string
 )
 
+// TestMain arranca la aplicación en un puerto aleatorio con una BD SQLite temporal,
+// de modo que los tests E2E no necesitan un servidor lanzado a mano (ni en CI)
+func TestMain(m *testing.M) {
+	if external := os.Getenv("E2E_BASE_URL"); external != "" {
+		baseURL = external
+		apiV1 = baseURL + "/api/v1"
+		os.Exit(m.Run())
+	}
+
+	os.Exit(runInProcess(m))
+}
+
+// runInProcess cablea la aplicación con app.New y la sirve con httptest
+func runInProcess(m *testing.M) int {
+	gin.SetMode(gin.TestMode)
+
+	tmpDir, err := os.MkdirTemp("", "inventory-e2e-")
+	if err != nil {
+		log.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := config.Load()
+	cfg.SQLitePath = filepath.Join(tmpDir, "e2e.db")
+	cfg.MessageBroker = "none"
+	cfg.SandboxMode = false
+	cfg.APIKeys[apiKey] = "E2E"
+
+	application, err := app.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+
+	ctx, stopWorkers := context.WithCancel(context.Background())
+	application.StartWorkers(ctx)
+
+	server := httptest.NewServer(application.Router)
+	baseURL = server.URL
+	apiV1 = baseURL + "/api/v1"
+
+	code := m.Run()
+
+	server.Close()
+	stopWorkers()
+	if err := application.Close(context.Background()); err != nil {
+		log.Printf("Error closing application: %v", err)
+	}
+
+	return code
+}
+
 // TestClient encapsula el cliente HTTP para tests E2E
 type TestClient struct {
 	client  *http.Client
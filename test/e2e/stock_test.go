@@ -1,25 +1,32 @@
 package e2e
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 )
 
-// StockResponse representa la respuesta de stock
+// StockResponse representa la respuesta de stock (formato v1: camelCase)
 type StockResponse struct {
-	ProductID    string  `json:"product_id"`
-	StoreID      string  `json:"store_id"`
-	Quantity     int     `json:"quantity"`
-	ReservedQty  int     `json:"reserved_qty"`
-	AvailableQty int     `json:"available_qty"`
-	MinStock     int     `json:"min_stock"`
-	MaxStock     int     `json:"max_stock"`
-	ReorderPoint int     `json:"reorder_point"`
-	ReorderQty   int     `json:"reorder_qty"`
-	Version      int     `json:"version"`
-	LastUpdated  string  `json:"last_updated"`
-	ProductName  *string `json:"product_name,omitempty"`
-	ProductSKU   *string `json:"product_sku,omitempty"`
+	ProductID   string `json:"productId"`
+	StoreID     string `json:"storeId"`
+	Quantity    int    `json:"quantity"`
+	Reserved    int    `json:"reserved"`
+	Held        int    `json:"held"`
+	MinStock    int    `json:"minStock"`
+	MaxStock    int    `json:"maxStock"`
+	SafetyStock int    `json:"safetyStock"`
+	Version     int    `json:"version"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+// Available calcula la disponibilidad (v1 no la incluye en la respuesta)
+func (s StockResponse) Available() int {
+	return s.Quantity - s.Reserved
 }
 
 // AvailabilityResponse representa la respuesta de disponibilidad
@@ -90,10 +97,9 @@ func TestStockE2E_FullCycle(t *testing.T) {
 			if stock.Quantity != 100 {
 				t.Errorf("Expected quantity 100, got %d", stock.Quantity)
 			}
-			// El campo AvailableQty puede no estar bien calculado en la respuesta inicial
-			if stock.AvailableQty != 100 && stock.Quantity-stock.ReservedQty != 100 {
-				t.Logf("Warning: Expected available 100, got %d (quantity=%d, reserved=%d)",
-					stock.AvailableQty, stock.Quantity, stock.ReservedQty)
+			if stock.Available() != 100 {
+				t.Errorf("Expected available 100, got %d (quantity=%d, reserved=%d)",
+					stock.Available(), stock.Quantity, stock.Reserved)
 			}
 		})
 
@@ -105,11 +111,10 @@ func TestStockE2E_FullCycle(t *testing.T) {
 			var stock StockResponse
 			ParseJSON(t, body, &stock)
 
-			// Los campos ProductID y StoreID pueden venir vacíos en algunas respuestas
-			if stock.ProductID != "" && stock.ProductID != productID {
+			if stock.ProductID != productID {
 				t.Errorf("Expected product ID %s, got %s", productID, stock.ProductID)
 			}
-			if stock.StoreID != "" && stock.StoreID != "MAD-001" {
+			if stock.StoreID != "MAD-001" {
 				t.Errorf("Expected store ID MAD-001, got %s", stock.StoreID)
 			}
 		})
@@ -155,7 +160,7 @@ func TestStockE2E_FullCycle(t *testing.T) {
 		t.Run("AdjustStock", func(t *testing.T) {
 			adjustment := map[string]interface{}{
 				"adjustment": -20,
-				"reason":     "E2E test adjustment",
+				"reason":     "correction",
 			}
 
 			resp, body := client.POST(t, "/stock/"+productID+"/MAD-001/adjust", adjustment)
@@ -206,10 +211,47 @@ func TestStockE2E_FullCycle(t *testing.T) {
 			// Puede estar vacío si no hay items con bajo stock
 			t.Logf("Found %d low stock items", response.Count)
 		})
+
+		// 9. Descargar el stock bajo como CSV y XLSX
+		t.Run("DownloadLowStockItems", func(t *testing.T) {
+			resp, body := client.GET(t, "/stock/low-stock?threshold=200&storeId=MAD-001&format=csv")
+			AssertStatusCode(t, http.StatusOK, resp.StatusCode, body)
+
+			if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/csv") {
+				t.Errorf("Expected text/csv, got %s", resp.Header.Get("Content-Type"))
+			}
+			records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+			if err != nil {
+				t.Fatalf("Error parsing CSV: %v", err)
+			}
+			if len(records) < 2 || records[0][0] != "product_id" || !strings.Contains(string(body), productID) {
+				t.Errorf("Expected header and the product row, got %v", records)
+			}
+
+			resp, body = client.GET(t, "/stock/low-stock?threshold=200&storeId=MAD-001&format=xlsx")
+			AssertStatusCode(t, http.StatusOK, resp.StatusCode, body)
+
+			archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+			if err != nil {
+				t.Fatalf("Error opening XLSX: %v", err)
+			}
+			sheet, err := archive.Open("xl/worksheets/sheet1.xml")
+			if err != nil {
+				t.Fatalf("Expected worksheet in XLSX: %v", err)
+			}
+			defer sheet.Close()
+			content, _ := io.ReadAll(sheet)
+			if !strings.Contains(string(content), productID) {
+				t.Errorf("Expected product row in worksheet")
+			}
+
+			resp, body = client.GET(t, "/stock/low-stock?format=pdf")
+			AssertStatusCode(t, http.StatusBadRequest, resp.StatusCode, body)
+		})
 	})
 
 	// Cleanup: eliminar el producto
-	client.DELETE(t, "/products/"+productID)
+	client.DELETE(t, "/products/"+productID+"?force=true")
 }
 
 func TestStock_TransferStock(t *testing.T) {
@@ -290,19 +332,26 @@ func TestStock_TransferStock(t *testing.T) {
 		stockOriginAfter.Quantity, stockDestAfter.Quantity)
 
 	// Cleanup
-	client.DELETE(t, "/products/"+productID)
+	client.DELETE(t, "/products/"+productID+"?force=true")
 }
 
 func TestStock_InvalidOperations(t *testing.T) {
 	client := NewTestClient()
 
 	t.Run("GetStock_NotFound", func(t *testing.T) {
-		resp, body := client.GET(t, "/stock/non-existent/MAD-001")
+		resp, body := client.GET(t, "/stock/00000000-0000-0000-0000-000000000000/MAD-001")
 		AssertStatusCode(t, http.StatusNotFound, resp.StatusCode, body)
 	})
 
+	t.Run("GetStock_InvalidIDs", func(t *testing.T) {
+		for _, path := range []string{"/stock/non-existent/MAD-001", "/stock/00000000-0000-0000-0000-000000000000/MAD.001"} {
+			resp, body := client.GET(t, path)
+			AssertStatusCode(t, http.StatusBadRequest, resp.StatusCode, body)
+		}
+	})
+
 	t.Run("CheckAvailability_MissingQuantity", func(t *testing.T) {
-		resp, body := client.GET(t, "/stock/some-product/MAD-001/availability")
+		resp, body := client.GET(t, "/stock/00000000-0000-0000-0000-000000000000/MAD-001/availability")
 		AssertStatusCode(t, http.StatusBadRequest, resp.StatusCode, body)
 	})
 
@@ -0,0 +1,80 @@
+//go:build integration
+
+// Package integration contiene los tests contra servicios reales (Redis) levantados en
+// contenedores efímeros con testcontainers-go. Se ejecutan solo con el build tag "integration":
+//
+//	go test -tags integration ./test/integration/
+//
+// Si INTEGRATION_REDIS_ADDR está definido se usa esa instancia en lugar de levantar un contenedor.
+//
+// Postgres queda fuera de esta suite: la capa de repositorios solo tiene driver SQLite, así que
+// no hay repositorios ni bloqueos de Postgres que probar. Cuando exista el driver se añadirá
+// StartPostgres con testcontainers-go/modules/postgres siguiendo el mismo patrón.
+package integration
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+// redisImage es la misma imagen que usa docker-compose.yml
+const redisImage = "redis:7-alpine"
+
+// StartRedis devuelve la dirección de un Redis listo para usar. Levanta un contenedor
+// con un puerto aleatorio y lo elimina al terminar el test. Sin Docker el test se salta.
+func StartRedis(t *testing.T) string {
+	t.Helper()
+
+	if addr := os.Getenv("INTEGRATION_REDIS_ADDR"); addr != "" {
+		waitForRedis(t, addr)
+		return addr
+	}
+
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	container, err := tcredis.Run(context.Background(), redisImage)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("Failed to start Redis container: %v", err)
+	}
+
+	// ConnectionString devuelve p. ej. "redis://localhost:49154"
+	uri, err := container.ConnectionString(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get Redis address: %v", err)
+	}
+	addr := strings.TrimPrefix(uri, "redis://")
+
+	waitForRedis(t, addr)
+	return addr
+}
+
+// waitForRedis espera hasta 30s a que Redis responda a PING
+func waitForRedis(tb testing.TB, addr string) {
+	tb.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := client.Ping(ctx).Err()
+		cancel()
+
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			tb.Fatalf("Redis at %s not ready: %v", addr, err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
@@ -0,0 +1,150 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newStream crea un publisher sobre un stream exclusivo del test
+func newStream(t *testing.T, addr string) (*infrastructure.RedisPublisher, *redis.Client, string) {
+	t.Helper()
+
+	stream := fmt.Sprintf("inventory-events-test-%d", time.Now().UnixNano())
+	publisher, err := infrastructure.NewRedisPublisher(infrastructure.RedisPublisherConfig{
+		Addr:       addr,
+		StreamName: stream,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Redis publisher: %v", err)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() {
+		client.Del(context.Background(), stream)
+		client.Close()
+		publisher.Close()
+	})
+
+	return publisher, client, stream
+}
+
+func readStream(t *testing.T, client *redis.Client, stream string) []redis.XMessage {
+	t.Helper()
+
+	messages, err := client.XRange(context.Background(), stream, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("Failed to read stream %s: %v", stream, err)
+	}
+	return messages
+}
+
+func TestRedisPublisher_Publish(t *testing.T) {
+	addr := StartRedis(t)
+	publisher, client, stream := newStream(t, addr)
+	ctx := context.Background()
+
+	event := domain.NewStockUpdatedEvent("product-1", "MAD-001", 10, 7)
+	if err := publisher.Publish(ctx, event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	messages := readStream(t, client, stream)
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message in stream, got %d", len(messages))
+	}
+
+	values := messages[0].Values
+	if values["id"] != event.ID || values["event_type"] != "stock.updated" || values["store_id"] != "MAD-001" {
+		t.Errorf("Unexpected stream fields: %v", values)
+	}
+
+	var decoded domain.Event
+	if err := json.Unmarshal([]byte(values["payload"].(string)), &decoded); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+	if decoded.ID != event.ID || decoded.Payload != event.Payload {
+		t.Errorf("Expected payload to round-trip, got %+v", decoded)
+	}
+}
+
+func TestRedisPublisher_PublishBatch(t *testing.T) {
+	addr := StartRedis(t)
+	publisher, client, stream := newStream(t, addr)
+
+	events := []*domain.Event{
+		domain.NewReservationCreatedEvent("res-1", "product-1", "MAD-001", 2),
+		domain.NewReservationConfirmedEvent(
+			&domain.Reservation{ID: "res-1", ProductID: "product-1", StoreID: "MAD-001", CustomerID: "customer-1", Quantity: 2},
+			&domain.Product{ID: "product-1", SKU: "PROD-001", Price: 10},
+			nil, "",
+		),
+		domain.NewReservationCreatedEvent("res-2", "product-1", "BCN-001", 1),
+	}
+	if err := publisher.PublishBatch(context.Background(), events); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	messages := readStream(t, client, stream)
+	if len(messages) != len(events) {
+		t.Fatalf("Expected %d messages, got %d", len(events), len(messages))
+	}
+	for i, message := range messages {
+		if message.Values["id"] != events[i].ID {
+			t.Errorf("Expected message %d to be %s, got %v", i, events[i].ID, message.Values["id"])
+		}
+	}
+}
+
+// TestStockService_PublishesToRedis ejecuta operaciones reales del servicio contra Redis
+func TestStockService_PublishesToRedis(t *testing.T) {
+	addr := StartRedis(t)
+	publisher, client, stream := newStream(t, addr)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+	ctx := context.Background()
+
+	productID := "550e8400-e29b-41d4-a716-446655440000"
+	if _, err := stockService.AdjustStock(ctx, productID, "MAD-001", 5); err != nil {
+		t.Fatalf("Failed to adjust stock: %v", err)
+	}
+	reservation, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "customer-1", 2, 15)
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	if err := reservationService.ConfirmReservation(ctx, reservation.ID); err != nil {
+		t.Fatalf("Failed to confirm reservation: %v", err)
+	}
+
+	messages := readStream(t, client, stream)
+	want := []string{"stock.updated", "reservation.created", "reservation.confirmed"}
+	if len(messages) != len(want) {
+		t.Fatalf("Expected %d messages, got %d", len(want), len(messages))
+	}
+	for i, message := range messages {
+		if message.Values["event_type"] != want[i] {
+			t.Errorf("Expected message %d to be %s, got %v", i, want[i], message.Values["event_type"])
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package load
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+// catalogSizes son los tamaños de catálogo con los que se ejecuta cada benchmark
+var catalogSizes = []int{100, 1000, 10000}
+
+func BenchmarkReserveStock(b *testing.B) {
+	for _, size := range catalogSizes {
+		b.Run(fmt.Sprintf("products=%d", size), func(b *testing.B) {
+			db := testutil.SetupTestDB(b)
+			defer db.Close()
+
+			ids := SeedCatalog(b, db, size)
+			stockRepo := repository.NewStockRepository(db)
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := stockRepo.ReserveStock(ctx, ids[i%len(ids)], SeedStoreID, 1); err != nil {
+					b.Fatalf("ReserveStock failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAdjustStock(b *testing.B) {
+	for _, size := range catalogSizes {
+		b.Run(fmt.Sprintf("products=%d", size), func(b *testing.B) {
+			db := testutil.SetupTestDB(b)
+			defer db.Close()
+
+			ids := SeedCatalog(b, db, size)
+			stockService := service.NewStockService(
+				repository.NewStockRepository(db),
+				repository.NewProductRepository(db),
+				repository.NewEventRepository(db),
+				mocks.NewNoOpPublisher(),
+			)
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Alternar +1/-1 mantiene el stock estable durante todo el benchmark
+				adjustment := 1
+				if i%2 == 1 {
+					adjustment = -1
+				}
+				if _, err := stockService.AdjustStock(ctx, ids[i%len(ids)], SeedStoreID, adjustment); err != nil {
+					b.Fatalf("AdjustStock failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkListProducts(b *testing.B) {
+	for _, size := range catalogSizes {
+		b.Run(fmt.Sprintf("products=%d", size), func(b *testing.B) {
+			db := testutil.SetupTestDB(b)
+			defer db.Close()
+
+			SeedCatalog(b, db, size)
+			productService := service.NewProductService(repository.NewProductRepository(db), repository.NewEventRepository(db))
+			ctx := context.Background()
+			const pageSize = 50
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				offset := (i * pageSize) % size
+				if _, err := productService.ListProducts(ctx, pageSize, offset, false); err != nil {
+					b.Fatalf("ListProducts failed: %v", err)
+				}
+			}
+		})
+	}
+}
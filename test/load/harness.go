@@ -0,0 +1,97 @@
+// Package load contiene el harness de carga y los benchmarks de rendimiento.
+//
+// Los benchmarks (go test -bench) miden las operaciones críticas contra SQLite en
+// memoria con distintos tamaños de catálogo. Los tests de carga solo se ejecutan
+// con LOAD_TEST=1 (ver docs/PERFORMANCE.md y `make load`).
+package load
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Config define una ejecución de carga
+type Config struct {
+	Workers  int           // Goroutines concurrentes
+	Duration time.Duration // Duración de la prueba
+}
+
+// Result resume una ejecución de carga
+type Result struct {
+	Requests   int
+	Errors     int
+	Elapsed    time.Duration
+	Throughput float64 // operaciones exitosas por segundo
+	P50        time.Duration
+	P95        time.Duration
+	P99        time.Duration
+}
+
+// Run ejecuta op en cfg.Workers goroutines durante cfg.Duration y mide latencias.
+// worker identifica a la goroutine e iteration la operación dentro de ella.
+func Run(ctx context.Context, cfg Config, op func(ctx context.Context, worker, iteration int) error) Result {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		errors    int
+		wg        sync.WaitGroup
+	)
+
+	start := time.Now()
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			var local []time.Duration
+			localErrors := 0
+			for i := 0; ctx.Err() == nil; i++ {
+				opStart := time.Now()
+				if err := op(ctx, worker, i); err != nil {
+					if ctx.Err() != nil {
+						break // Cancelada por fin de la prueba, no cuenta como error
+					}
+					localErrors++
+					continue
+				}
+				local = append(local, time.Since(opStart))
+			}
+
+			mu.Lock()
+			latencies = append(latencies, local...)
+			errors += localErrors
+			mu.Unlock()
+		}(w)
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	return Result{
+		Requests:   len(latencies) + errors,
+		Errors:     errors,
+		Elapsed:    elapsed,
+		Throughput: float64(len(latencies)) / elapsed.Seconds(),
+		P50:        percentile(latencies, 0.50),
+		P95:        percentile(latencies, 0.95),
+		P99:        percentile(latencies, 0.99),
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx]
+}
@@ -0,0 +1,151 @@
+package load
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Objetivos documentados en docs/PERFORMANCE.md (sobrescribibles por entorno)
+const (
+	defaultTargetRPS = 500
+	defaultWorkers   = 16
+	defaultDuration  = 10 * time.Second
+	loadAPIKey       = "load-test-key"
+)
+
+func TestMain(m *testing.M) {
+	// El publisher NoOp loguea cada evento: silenciar para no distorsionar las mediciones
+	log.SetOutput(io.Discard)
+	gin.SetMode(gin.ReleaseMode)
+	os.Exit(m.Run())
+}
+
+// TestReservationThroughput mide reservas/segundo vía HTTP contra la API en proceso
+// (o contra LOAD_BASE_URL si se define) y falla si no alcanza LOAD_TARGET_RPS.
+func TestReservationThroughput(t *testing.T) {
+	if os.Getenv("LOAD_TEST") != "1" {
+		t.Skip("load test disabled: set LOAD_TEST=1 (make load)")
+	}
+
+	targetRPS := envInt("LOAD_TARGET_RPS", defaultTargetRPS)
+	workers := envInt("LOAD_WORKERS", defaultWorkers)
+	duration := time.Duration(envInt("LOAD_DURATION_SECONDS", int(defaultDuration/time.Second))) * time.Second
+
+	baseURL := os.Getenv("LOAD_BASE_URL")
+	apiKey := os.Getenv("LOAD_API_KEY")
+	var productIDs []string
+	storeID := os.Getenv("LOAD_STORE_ID")
+
+	if baseURL == "" {
+		db := testutil.SetupTestDB(t)
+		defer db.Close()
+
+		productIDs = SeedCatalog(t, db, envInt("LOAD_PRODUCTS", 1000))
+		storeID = SeedStoreID
+		apiKey = loadAPIKey
+
+		server := httptest.NewServer(newLoadRouter(db))
+		defer server.Close()
+		baseURL = server.URL
+	} else {
+		productIDs = []string{os.Getenv("LOAD_PRODUCT_ID")}
+		if productIDs[0] == "" || storeID == "" || apiKey == "" {
+			t.Fatal("LOAD_BASE_URL requires LOAD_PRODUCT_ID, LOAD_STORE_ID and LOAD_API_KEY")
+		}
+	}
+
+	client := &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: workers},
+	}
+
+	result := Run(context.Background(), Config{Workers: workers, Duration: duration}, func(ctx context.Context, worker, iteration int) error {
+		body, _ := json.Marshal(map[string]interface{}{
+			"product_id":  productIDs[(worker*7919+iteration)%len(productIDs)],
+			"store_id":    storeID,
+			"customer_id": fmt.Sprintf("load-customer-%d", worker),
+			"quantity":    1,
+		})
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/api/v1/reservations", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode != http.StatusCreated {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	})
+
+	t.Logf("reservations: %d ok, %d errors in %s (%.0f/s) p50=%s p95=%s p99=%s",
+		result.Requests-result.Errors, result.Errors, result.Elapsed.Round(time.Millisecond),
+		result.Throughput, result.P50, result.P95, result.P99)
+
+	if result.Errors > 0 {
+		t.Errorf("Expected no errors, got %d", result.Errors)
+	}
+	if result.Throughput < float64(targetRPS) {
+		t.Errorf("Throughput %.0f reservations/s is below target %d/s", result.Throughput, targetRPS)
+	}
+}
+
+// newLoadRouter monta los endpoints core como en cmd/api/main.go (sin middlewares de logging)
+func newLoadRouter(db *sql.DB) *gin.Engine {
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+
+	productHandler := handler.NewProductHandler(service.NewProductService(productRepo, eventRepo))
+	stockHandler := handler.NewStockHandler(service.NewStockService(stockRepo, productRepo, eventRepo, publisher))
+	reservationHandler := handler.NewReservationHandler(
+		service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher),
+		service.NewSerialService(repository.NewSerialRepository(db), reservationRepo),
+	)
+
+	router := gin.New()
+	v1 := router.Group("/api/v1", handler.UseSerializer(handler.V1Serializer{}))
+	apiKeyAuth := middleware.APIKeyAuth(auth.NewKeyRing(map[string]string{loadAPIKey: "Load Test"}))
+	handler.RegisterCoreRoutes(v1, apiKeyAuth, productHandler, stockHandler, reservationHandler)
+
+	return router
+}
+
+func envInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,59 @@
+package load
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+// SeedStoreID es la tienda donde se crea el stock de carga
+const SeedStoreID = "LOAD-001"
+
+// SeedQuantity es el stock inicial de cada producto sembrado (suficiente para no agotarse)
+const SeedQuantity = 1_000_000_000
+
+// SeedCatalog inserta n productos con stock en SeedStoreID y retorna sus IDs
+func SeedCatalog(tb testing.TB, db *sql.DB, n int) []string {
+	tb.Helper()
+
+	tx, err := db.Begin()
+	if err != nil {
+		tb.Fatalf("Failed to begin seed transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	productStmt, err := tx.Prepare(`
+		INSERT INTO products (id, sku, name, description, category, price)
+		VALUES (?, ?, ?, 'Producto de carga', 'load', 9.99)
+	`)
+	if err != nil {
+		tb.Fatalf("Failed to prepare product insert: %v", err)
+	}
+	defer productStmt.Close()
+
+	stockStmt, err := tx.Prepare(`
+		INSERT INTO stock (id, product_id, store_id, quantity, reserved, version)
+		VALUES (?, ?, ?, ?, 0, 1)
+	`)
+	if err != nil {
+		tb.Fatalf("Failed to prepare stock insert: %v", err)
+	}
+	defer stockStmt.Close()
+
+	ids := make([]string, n)
+	for i := 0; i < n; i++ {
+		ids[i] = fmt.Sprintf("load-product-%06d", i)
+		if _, err := productStmt.Exec(ids[i], fmt.Sprintf("LOAD-%06d", i), fmt.Sprintf("Load Product %d", i)); err != nil {
+			tb.Fatalf("Failed to seed product: %v", err)
+		}
+		if _, err := stockStmt.Exec(fmt.Sprintf("load-stock-%06d", i), ids[i], SeedStoreID, SeedQuantity); err != nil {
+			tb.Fatalf("Failed to seed stock: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tb.Fatalf("Failed to commit seed transaction: %v", err)
+	}
+
+	return ids
+}
@@ -0,0 +1,93 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryAvailabilityCache implementación en memoria de domain.AvailabilityCache para tests.
+// Reproduce la semántica de RedisAvailabilityCache: Reserve/Release solo modifican
+// entradas existentes.
+type MemoryAvailabilityCache struct {
+	mu      sync.Mutex
+	entries map[string]int
+	Hits    int
+	Misses  int
+}
+
+// NewMemoryAvailabilityCache crea un cache vacío
+func NewMemoryAvailabilityCache() *MemoryAvailabilityCache {
+	return &MemoryAvailabilityCache{entries: make(map[string]int)}
+}
+
+func availabilityKey(productID, storeID string) string {
+	return productID + ":" + storeID
+}
+
+// Get retorna la entrada y registra el hit/miss
+func (c *MemoryAvailabilityCache) Get(ctx context.Context, productID, storeID string) (int, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	available, ok := c.entries[availabilityKey(productID, storeID)]
+	if ok {
+		c.Hits++
+	} else {
+		c.Misses++
+	}
+	return available, ok, nil
+}
+
+// Set guarda la entrada
+func (c *MemoryAvailabilityCache) Set(ctx context.Context, productID, storeID string, available int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[availabilityKey(productID, storeID)] = available
+	return nil
+}
+
+// Reserve descuenta quantity si la entrada existe
+func (c *MemoryAvailabilityCache) Reserve(ctx context.Context, productID, storeID string, quantity int) error {
+	c.adjust(productID, storeID, -quantity)
+	return nil
+}
+
+// Release devuelve quantity si la entrada existe
+func (c *MemoryAvailabilityCache) Release(ctx context.Context, productID, storeID string, quantity int) error {
+	c.adjust(productID, storeID, quantity)
+	return nil
+}
+
+func (c *MemoryAvailabilityCache) adjust(productID, storeID string, delta int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := availabilityKey(productID, storeID)
+	if available, ok := c.entries[key]; ok {
+		c.entries[key] = available + delta
+	}
+}
+
+// Invalidate elimina la entrada
+func (c *MemoryAvailabilityCache) Invalidate(ctx context.Context, productID, storeID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, availabilityKey(productID, storeID))
+	return nil
+}
+
+// Peek retorna la entrada sin contar hit/miss
+func (c *MemoryAvailabilityCache) Peek(productID, storeID string) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	available, ok := c.entries[availabilityKey(productID, storeID)]
+	return available, ok
+}
+
+// Close no hace nada
+func (c *MemoryAvailabilityCache) Close() error {
+	return nil
+}
@@ -153,8 +153,9 @@ func NewNoOpPublisher() *NoOpPublisher {
 
 // Publish no hace nada, solo registra el evento en logs
 func (p *NoOpPublisher) Publish(ctx context.Context, event *domain.Event) error {
-	log.Printf("[NoOp] Event would be published: type=%s, store=%s, id=%s",
-		event.EventType, event.StoreID, event.ID)
+	domain.AttachCorrelationID(ctx, event)
+	log.Printf("[NoOp] Event would be published: type=%s, store=%s, id=%s, correlation_id=%s",
+		event.EventType, event.StoreID, event.ID, event.CorrelationID)
 	return nil
 }
 
@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestClassifyABC(t *testing.T) {
+	classes := domain.ClassifyABC([]domain.ProductConsumption{
+		{ProductID: "p4", Value: 4},
+		{ProductID: "p1", Value: 70},
+		{ProductID: "p5", Value: 0},
+		{ProductID: "p2", Value: 20}, // Empieza en el 70%: sigue siendo A aunque cruce el 80%
+		{ProductID: "p3", Value: 6},
+	})
+
+	expected := map[string]domain.ABCClass{"p1": "A", "p2": "A", "p3": "B", "p4": "C", "p5": "C"}
+	for productID, class := range expected {
+		if classes[productID] != class {
+			t.Errorf("Expected %s to be %s, got %s", productID, class, classes[productID])
+		}
+	}
+
+	// Sin consumo en la ventana todos son C
+	if class := domain.ClassifyABC([]domain.ProductConsumption{{ProductID: "p1"}})["p1"]; class != domain.ABCClassC {
+		t.Errorf("Expected C without consumption, got %s", class)
+	}
+}
+
+func TestABCClassificationService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, repository.NewEventRepository(db), mocks.NewNoOpPublisher())
+	classificationService := service.NewABCClassificationService(productRepo, 90*24*time.Hour)
+
+	ctx := context.Background()
+	laptop := "550e8400-e29b-41d4-a716-446655440000"   // 599.99
+	keyboard := "550e8400-e29b-41d4-a716-446655440002" // 89.99
+
+	sell := func(productID string, quantity int) {
+		reservation, err := reservationService.CreateReservation(ctx, productID, "BCN-001", "customer-abc", quantity, 30)
+		if err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+		if err := reservationService.ConfirmReservation(ctx, reservation.ID); err != nil {
+			t.Fatalf("Error confirming reservation: %v", err)
+		}
+	}
+	sell(laptop, 2)   // 1199.98: más del 80% del valor
+	sell(keyboard, 1) // 89.99: empieza en el 93%
+
+	run, err := classificationService.Classify(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if run.ClassA != 1 || run.ClassB != 1 || run.ClassC != 3 {
+		t.Errorf("Expected 1 A, 1 B and 3 C, got %+v", run)
+	}
+
+	t.Run("StoredOnProduct", func(t *testing.T) {
+		product, err := productRepo.GetByID(ctx, keyboard)
+		if err != nil || product.ABCClass != domain.ABCClassB {
+			t.Errorf("Expected keyboard to be B, got %+v (%v)", product, err)
+		}
+
+		products, err := productRepo.ListByABCClass(ctx, domain.ABCClassA, "", 10, 0, false)
+		if err != nil || len(products) != 1 || products[0].ID != laptop {
+			t.Errorf("Expected only the laptop in class A, got %d products (%v)", len(products), err)
+		}
+	})
+
+	t.Run("LowStockPrioritizesClassA", func(t *testing.T) {
+		items, err := stockRepo.GetLowStockItems(ctx, domain.LowStockFilter{Threshold: domain.DefaultMinStock})
+		if err != nil || len(items) == 0 {
+			t.Fatalf("Expected low stock items, got %d (%v)", len(items), err)
+		}
+		if items[0].ProductID != laptop {
+			t.Errorf("Expected class A product first, got %s", items[0].ProductID)
+		}
+
+		items, err = stockRepo.GetLowStockItems(ctx, domain.LowStockFilter{Threshold: domain.DefaultMinStock, ABCClass: domain.ABCClassB})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for _, item := range items {
+			if item.ProductID != keyboard {
+				t.Errorf("Expected only class B rows, got %s", item.ProductID)
+			}
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		if _, err := service.NewABCClassificationService(productRepo, 0).Classify(ctx); err == nil {
+			t.Error("Expected ConflictError with ABC_WINDOW_DAYS=0")
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestAdjustmentReasons(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	reasonRepo := repository.NewAdjustmentReasonRepository(db)
+	stockService := service.NewStockService(stockRepo, repository.NewProductRepository(db), eventRepo, mocks.NewNoOpPublisher())
+	stockService.SetAdjustmentReasonRepository(reasonRepo)
+	reasonService := service.NewAdjustmentReasonService(reasonRepo)
+	reportService := service.NewReportService(repository.NewReportRepository(db))
+
+	silenceLogs(t)
+	ctx := context.Background()
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+
+	t.Run("RejectsMissingOrUnknownReason", func(t *testing.T) {
+		for _, reason := range []string{"", "lost-in-transit"} {
+			_, err := stockService.AdjustStockWithReason(ctx, laptop, "MAD-001", -1, reason)
+			if validationErr, ok := err.(*domain.ValidationError); !ok || validationErr.Field != "reason" {
+				t.Errorf("Expected a reason ValidationError for %q, got %v", reason, err)
+			}
+		}
+
+		// En PUT /stock el motivo es opcional
+		if _, err := stockService.UpdateStockWithReason(ctx, laptop, "MAD-001", 12, ""); err != nil {
+			t.Errorf("Expected no error without reason on update, got %v", err)
+		}
+	})
+
+	t.Run("ManagesCatalog", func(t *testing.T) {
+		if _, err := reasonService.PutReason(ctx, &domain.AdjustmentReason{Code: "Returned", Description: "Devolución de cliente", Active: true}); err != nil {
+			t.Fatalf("Error creating reason: %v", err)
+		}
+		if _, err := reasonService.PutReason(ctx, &domain.AdjustmentReason{Code: "bad code", Description: "x", Active: true}); err == nil {
+			t.Error("Expected a validation error for an invalid code")
+		}
+		if _, err := stockService.AdjustStockWithReason(ctx, laptop, "MAD-001", 1, "returned"); err != nil {
+			t.Errorf("Expected the new reason to be accepted, got %v", err)
+		}
+
+		if _, err := reasonService.DeactivateReason(ctx, "returned"); err != nil {
+			t.Fatalf("Error deactivating reason: %v", err)
+		}
+		if _, err := stockService.AdjustStockWithReason(ctx, laptop, "MAD-001", 1, "returned"); err == nil {
+			t.Error("Expected an error adjusting with an inactive reason")
+		}
+
+		active, _ := reasonService.ListReasons(ctx, false)
+		all, _ := reasonService.ListReasons(ctx, true)
+		if len(active) != 4 || len(all) != 5 {
+			t.Errorf("Expected 4 active and 5 total reasons, got %d and %d", len(active), len(all))
+		}
+	})
+
+	t.Run("RecordsReasonOnMovementAndReports", func(t *testing.T) {
+		moves := []struct {
+			store  string
+			delta  int
+			reason string
+		}{
+			{"MAD-001", -2, domain.AdjustmentReasonDamaged},
+			{"MAD-001", -1, domain.AdjustmentReasonDamaged},
+			{"BCN-001", 3, domain.AdjustmentReasonFound},
+		}
+		for _, move := range moves {
+			if _, err := stockService.AdjustStockWithReason(ctx, laptop, move.store, move.delta, move.reason); err != nil {
+				t.Fatalf("Error adjusting stock: %v", err)
+			}
+		}
+
+		events, err := eventRepo.GetEventsByType(ctx, domain.EventStockUpdated, 50, 0)
+		if err != nil {
+			t.Fatalf("Error getting events: %v", err)
+		}
+		found := false
+		for _, event := range events {
+			var payload domain.StockUpdatedPayload
+			if err := json.Unmarshal([]byte(event.Payload), &payload); err == nil && payload.StoreID == "BCN-001" {
+				found = payload.Reason == domain.AdjustmentReasonFound && payload.NewQuantity-payload.OldQuantity == 3
+			}
+		}
+		if !found {
+			t.Error("Expected the reason in the stock.updated payload of BCN-001")
+		}
+
+		report, err := reportService.GetAdjustmentsByReason(ctx, "MAD-001", nil, nil)
+		if err != nil {
+			t.Fatalf("Error getting report: %v", err)
+		}
+		if report.Count != 2 || report.Items[0].Reason != domain.AdjustmentReasonDamaged {
+			t.Fatalf("Expected damaged and returned in MAD-001, got %+v", report.Items)
+		}
+		if damaged := report.Items[0]; damaged.Adjustments != 2 || damaged.UnitsRemoved != 3 || damaged.NetUnits != -3 {
+			t.Errorf("Unexpected damaged summary: %+v", damaged)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newVersionedRouter monta los endpoints core en /api/v1 y /api/v2 igual que cmd/api/main.go
+func newVersionedRouter(t *testing.T) (*gin.Engine, func()) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db := testutil.SetupTestDB(t)
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+
+	productHandler := handler.NewProductHandler(service.NewProductService(productRepo, eventRepo))
+	stockHandler := handler.NewStockHandler(service.NewStockService(stockRepo, productRepo, eventRepo, publisher))
+	reservationHandler := handler.NewReservationHandler(
+		service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher),
+		service.NewSerialService(repository.NewSerialRepository(db), reservationRepo),
+	)
+
+	apiKeyAuth := middleware.APIKeyAuth(auth.NewKeyRing(map[string]string{"test-key": "Test Store"}))
+
+	router := gin.New()
+	v1 := router.Group("/api/v1", handler.UseSerializer(handler.V1Serializer{}))
+	core := v1.Group("", middleware.Deprecation("/api/v2", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+	handler.RegisterCoreRoutes(core, apiKeyAuth, productHandler, stockHandler, reservationHandler)
+
+	v2 := router.Group("/api/v2", handler.UseSerializer(handler.V2Serializer{}))
+	handler.RegisterCoreRoutes(v2, apiKeyAuth, productHandler, stockHandler, reservationHandler)
+
+	return router, func() { db.Close() }
+}
+
+func doVersionedRequest(t *testing.T, router *gin.Engine, method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		raw, _ := json.Marshal(body)
+		reader = bytes.NewReader(raw)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "test-key")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var decoded map[string]interface{}
+	if w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("Invalid JSON response for %s %s: %v", method, path, err)
+		}
+	}
+	return w, decoded
+}
+
+func TestAPIVersioning_Compatibility(t *testing.T) {
+	router, cleanup := newVersionedRouter(t)
+	defer cleanup()
+
+	productID := "550e8400-e29b-41d4-a716-446655440000"
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		status int
+		// v1Key es una clave que debe existir en la respuesta v1 (formato histórico)
+		v1Key string
+		// v2List indica si la respuesta v2 es una colección (data + meta)
+		v2List bool
+		// errorCode es el código esperado en v2 cuando la respuesta es un error
+		errorCode string
+	}{
+		{name: "GetProduct", method: http.MethodGet, path: "/products/" + productID, status: http.StatusOK, v1Key: "sku"},
+		{name: "ListProducts", method: http.MethodGet, path: "/products?limit=2", status: http.StatusOK, v1Key: "total", v2List: true},
+		{name: "StockByStore", method: http.MethodGet, path: "/stock/store/MAD-001", status: http.StatusOK, v1Key: "items", v2List: true},
+		{name: "ProductNotFound", method: http.MethodGet, path: "/products/missing", status: http.StatusNotFound, v1Key: "error", errorCode: "NOT_FOUND"},
+		{name: "InvalidReservationBody", method: http.MethodPost, path: "/reservations", body: map[string]interface{}{"quantity": 1}, status: http.StatusBadRequest, v1Key: "error", errorCode: "INVALID_REQUEST_BODY"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name+"_v1", func(t *testing.T) {
+			w, body := doVersionedRequest(t, router, tc.method, "/api/v1"+tc.path, tc.body)
+			if w.Code != tc.status {
+				t.Fatalf("Expected status %d, got %d: %s", tc.status, w.Code, w.Body.String())
+			}
+			if _, ok := body[tc.v1Key]; !ok {
+				t.Errorf("Expected v1 key %q in response, got %v", tc.v1Key, body)
+			}
+			if tc.errorCode != "" {
+				if _, ok := body["error"].(string); !ok {
+					t.Errorf("Expected v1 error to be a string, got %v", body["error"])
+				}
+			}
+			if w.Header().Get("Deprecation") != "true" || w.Header().Get("Sunset") == "" {
+				t.Errorf("Expected deprecation headers on v1, got %v", w.Header())
+			}
+		})
+
+		t.Run(tc.name+"_v2", func(t *testing.T) {
+			w, body := doVersionedRequest(t, router, tc.method, "/api/v2"+tc.path, tc.body)
+			if w.Code != tc.status {
+				t.Fatalf("Expected status %d, got %d: %s", tc.status, w.Code, w.Body.String())
+			}
+			if w.Header().Get("Deprecation") != "" {
+				t.Error("Expected no deprecation header on v2")
+			}
+
+			if tc.errorCode != "" {
+				errBody, ok := body["error"].(map[string]interface{})
+				if !ok || errBody["code"] != tc.errorCode {
+					t.Errorf("Expected v2 error code %s, got %v", tc.errorCode, body["error"])
+				}
+				return
+			}
+
+			if _, ok := body["data"]; !ok {
+				t.Fatalf("Expected v2 data envelope, got %v", body)
+			}
+			if tc.v2List {
+				if _, ok := body["data"].([]interface{}); !ok {
+					t.Errorf("Expected v2 data to be a list, got %T", body["data"])
+				}
+				if _, ok := body["meta"].(map[string]interface{}); !ok {
+					t.Errorf("Expected v2 meta object, got %v", body["meta"])
+				}
+			}
+		})
+	}
+}
+
+func TestAPIVersioning_FieldNaming(t *testing.T) {
+	router, cleanup := newVersionedRouter(t)
+	defer cleanup()
+
+	productID := "550e8400-e29b-41d4-a716-446655440000"
+
+	// v1 conserva el formato histórico (camelCase en los recursos core)
+	_, v1 := doVersionedRequest(t, router, http.MethodGet, "/api/v1/stock/"+productID+"/MAD-001", nil)
+	if _, ok := v1["productId"]; !ok {
+		t.Errorf("Expected v1 stock to keep productId, got %v", v1)
+	}
+
+	// v2 usa los DTOs en snake_case
+	_, v2 := doVersionedRequest(t, router, http.MethodGet, "/api/v2/stock/"+productID+"/MAD-001", nil)
+	stock, _ := v2["data"].(map[string]interface{})
+	for _, key := range []string{"product_id", "store_id", "reserved", "available", "safety_stock", "updated_at"} {
+		if _, ok := stock[key]; !ok {
+			t.Errorf("Expected v2 stock key %q, got %v", key, stock)
+		}
+	}
+	if _, ok := stock["productId"]; ok {
+		t.Errorf("Expected no camelCase keys in v2 stock, got %v", stock)
+	}
+	if stock["available"] != stock["quantity"].(float64)-stock["reserved"].(float64) {
+		t.Errorf("Expected available = quantity - reserved, got %v", stock)
+	}
+
+	_, v2 = doVersionedRequest(t, router, http.MethodGet, "/api/v2/stock/store/MAD-001", nil)
+	items, _ := v2["data"].([]interface{})
+	if len(items) == 0 {
+		t.Fatalf("Expected stock items for MAD-001, got %v", v2)
+	}
+	if first, _ := items[0].(map[string]interface{}); first["store_id"] != "MAD-001" {
+		t.Errorf("Expected streamed v2 items in snake_case, got %v", items[0])
+	}
+
+	_, v2 = doVersionedRequest(t, router, http.MethodGet, "/api/v2/products/"+productID, nil)
+	product, _ := v2["data"].(map[string]interface{})
+	if _, ok := product["created_at"]; !ok {
+		t.Errorf("Expected v2 product created_at, got %v", product)
+	}
+
+	// Los bodies de productos solo admiten los campos editables
+	w, _ := doVersionedRequest(t, router, http.MethodPost, "/api/v2/products", map[string]interface{}{
+		"sku": "NAMING-001", "name": "Naming", "price": 10, "createdAt": "2026-01-01T00:00:00Z",
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a computed field in the product body, got %d: %s", w.Code, w.Body.String())
+	}
+}
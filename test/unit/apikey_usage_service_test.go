@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+)
+
+func TestAPIKeyUsageService_RecordAndReport(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	apiKeys := map[string]string{
+		"key-active": "Store Active",
+		"key-stale":  "Store Stale",
+	}
+	usageService := service.NewAPIKeyUsageService(repository.NewAPIKeyUsageRepository(db), auth.NewKeyRing(apiKeys))
+	ctx := context.Background()
+
+	now := time.Now()
+	usageService.Record("key-active", 200, now)
+	usageService.Record("key-active", 201, now)
+	usageService.Record("key-active", 409, now)
+	usageService.Record("key-active", 200, now.AddDate(0, 0, -1))
+	usageService.Record("key-stale", 200, now.AddDate(0, 0, -120))
+
+	t.Run("Flush", func(t *testing.T) {
+		flushed, err := usageService.Flush(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if flushed != 3 {
+			t.Errorf("Expected 3 daily rollups flushed, got %d", flushed)
+		}
+	})
+
+	t.Run("GetUsage_DailyRollup", func(t *testing.T) {
+		usage, err := usageService.GetUsage(ctx, domain.APIKeyID("key-active"), 30)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(usage.Daily) != 2 {
+			t.Fatalf("Expected 2 days, got %d", len(usage.Daily))
+		}
+		if usage.TotalRequests != 4 || usage.TotalErrors != 1 {
+			t.Errorf("Expected 4 requests and 1 error, got %d/%d", usage.TotalRequests, usage.TotalErrors)
+		}
+		if usage.ErrorRate != 0.25 {
+			t.Errorf("Expected error rate 0.25, got %f", usage.ErrorRate)
+		}
+		if usage.LastUsedAt == nil || usage.RevocationCandidate {
+			t.Errorf("Expected recently used key, got %+v", usage)
+		}
+	})
+
+	t.Run("GetUsage_UnknownKey", func(t *testing.T) {
+		_, err := usageService.GetUsage(ctx, "unknown", 30)
+		if _, ok := err.(*domain.NotFoundError); !ok {
+			t.Errorf("Expected NotFoundError, got %v", err)
+		}
+	})
+
+	t.Run("FindUnusedKeys", func(t *testing.T) {
+		unused, err := usageService.FindUnusedKeys(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(unused) != 1 || unused[0].Name != "Store Stale" {
+			t.Errorf("Expected only Store Stale as revocation candidate, got %+v", unused)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestAssortmentService_CloneJob(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	storeRepo := repository.NewStoreRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	jobRepo := repository.NewAssortmentJobRepository(db)
+	assortmentService := service.NewAssortmentService(
+		jobRepo,
+		storeRepo,
+		stockRepo,
+		repository.NewEventRepository(db),
+		mocks.NewNoOpPublisher(),
+	)
+	ctx := context.Background()
+	productID := "550e8400-e29b-41d4-a716-446655440000"
+
+	if err := storeRepo.Create(ctx, &domain.Store{ID: "ZAR-001", Name: "Zaragoza Centro", Active: true, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Error creating store: %v", err)
+	}
+	if err := stockRepo.UpdateThresholds(ctx, productID, "MAD-001", 4, 40); err != nil {
+		t.Fatalf("Error setting thresholds: %v", err)
+	}
+
+	t.Run("Run_ClonesIntoNewAndExistingStores", func(t *testing.T) {
+		job := &domain.AssortmentCloneJob{
+			ID:             "job-clone-001",
+			SourceStoreID:  "MAD-001",
+			TargetStoreIDs: []string{"ZAR-001", "BCN-001"},
+			CopyThresholds: true,
+			Status:         domain.JobStatusPending,
+			CreatedAt:      time.Now(),
+		}
+		if err := jobRepo.Create(ctx, job); err != nil {
+			t.Fatalf("Error creating job: %v", err)
+		}
+
+		if err := assortmentService.RunCloneJob(ctx, job); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		stored, err := assortmentService.GetCloneJob(ctx, job.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stored.Status != domain.JobStatusCompleted || len(stored.Report) != 2 {
+			t.Fatalf("Unexpected job state: %+v", stored)
+		}
+		if stored.Report[0].Created != 5 {
+			t.Errorf("Expected 5 rows created in ZAR-001, got %+v", stored.Report[0])
+		}
+		if stored.Report[1].Created != 0 || stored.Report[1].ThresholdsUpdated != 5 {
+			t.Errorf("Expected thresholds updated in BCN-001, got %+v", stored.Report[1])
+		}
+
+		stock, err := stockRepo.GetByProductAndStore(ctx, productID, "ZAR-001")
+		if err != nil {
+			t.Fatalf("Expected cloned stock, got %v", err)
+		}
+		if stock.Quantity != 0 || stock.MinStock != 4 || stock.MaxStock != 40 {
+			t.Errorf("Unexpected cloned stock: %+v", stock)
+		}
+
+		bcn, _ := stockRepo.GetByProductAndStore(ctx, productID, "BCN-001")
+		if bcn.Quantity != 15 || bcn.MinStock != 4 {
+			t.Errorf("Expected quantity kept and thresholds copied in BCN-001, got %+v", bcn)
+		}
+	})
+
+	t.Run("Start_UnknownTargetStore", func(t *testing.T) {
+		_, err := assortmentService.StartCloneJob(ctx, "MAD-001", []string{"XXX-999"}, false)
+		if _, ok := err.(*domain.NotFoundError); !ok {
+			t.Errorf("Expected NotFoundError, got %v", err)
+		}
+	})
+
+	t.Run("Start_TargetIsSource", func(t *testing.T) {
+		_, err := assortmentService.StartCloneJob(ctx, "MAD-001", []string{"MAD-001"}, false)
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+}
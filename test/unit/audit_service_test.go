@@ -0,0 +1,105 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+)
+
+func TestAuditService_VerifyChain(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	eventRepo := repository.NewEventRepository(db)
+	auditService := service.NewAuditService(eventRepo)
+	ctx := context.Background()
+
+	var events []*domain.Event
+	for i := 0; i < 5; i++ {
+		event := domain.NewStockUpdatedEvent("550e8400-e29b-41d4-a716-446655440000", "MAD-001", i, i+1)
+		if err := eventRepo.Save(ctx, event); err != nil {
+			t.Fatalf("Error saving event: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	t.Run("Save_ChainsRecords", func(t *testing.T) {
+		if events[0].Seq != 1 || events[0].PrevHash != "" {
+			t.Errorf("Expected genesis record with seq 1, got seq %d prev %q", events[0].Seq, events[0].PrevHash)
+		}
+		for i := 1; i < len(events); i++ {
+			if events[i].PrevHash != events[i-1].Hash {
+				t.Errorf("Expected record %d to point to previous hash", events[i].Seq)
+			}
+		}
+	})
+
+	t.Run("VerifyChain_Valid", func(t *testing.T) {
+		result, err := auditService.VerifyChain(ctx, 0, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !result.Valid || result.Checked != 5 {
+			t.Errorf("Expected valid chain with 5 records, got %+v", result)
+		}
+		if result.HeadHash != events[4].Hash {
+			t.Errorf("Expected head hash of last record")
+		}
+	})
+
+	t.Run("VerifyChain_MarkAsSyncedDoesNotBreakChain", func(t *testing.T) {
+		if err := eventRepo.MarkAsSynced(ctx, events[1].ID); err != nil {
+			t.Fatalf("Error marking event as synced: %v", err)
+		}
+
+		result, _ := auditService.VerifyChain(ctx, 0, 0)
+		if !result.Valid {
+			t.Errorf("Expected chain to remain valid after sync, got %+v", result.Issues)
+		}
+	})
+
+	t.Run("VerifyChain_DetectsModifiedRecord", func(t *testing.T) {
+		if _, err := db.Exec(`UPDATE events SET payload = '{"tampered":true}' WHERE id = ?`, events[2].ID); err != nil {
+			t.Fatalf("Error tampering event: %v", err)
+		}
+
+		result, err := auditService.VerifyChain(ctx, 0, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Valid || len(result.Issues) != 1 || result.Issues[0].Seq != 3 {
+			t.Errorf("Expected a single issue at seq 3, got %+v", result.Issues)
+		}
+
+		// Un tramo que no incluye el registro alterado sigue siendo válido
+		result, _ = auditService.VerifyChain(ctx, 4, 5)
+		if !result.Valid || result.Checked != 2 {
+			t.Errorf("Expected range 4-5 to be valid, got %+v", result)
+		}
+	})
+
+	t.Run("VerifyChain_DetectsDeletedRecord", func(t *testing.T) {
+		if _, err := db.Exec(`DELETE FROM events WHERE id = ?`, events[3].ID); err != nil {
+			t.Fatalf("Error deleting event: %v", err)
+		}
+
+		result, _ := auditService.VerifyChain(ctx, 4, 5)
+		if result.Valid {
+			t.Fatal("Expected deleted record to be detected")
+		}
+
+		foundGap := false
+		for _, issue := range result.Issues {
+			if issue.Seq == 5 && issue.EventID == events[4].ID {
+				foundGap = true
+			}
+		}
+		if !foundGap {
+			t.Errorf("Expected issue at seq 5, got %+v", result.Issues)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestAutoReservationService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+	autoReservationService := service.NewAutoReservationService(reservationService, stockRepo)
+
+	ctx := context.Background()
+
+	// Disponibilidad del producto 0002: MAD-001 18, BCN-001 25, VAL-001 0, SEV-001 17
+	productID := "550e8400-e29b-41d4-a716-446655440002"
+	reserved := func(storeID string) int {
+		stock, _ := stockRepo.GetByProductAndStore(ctx, productID, storeID)
+		return stock.Reserved
+	}
+
+	t.Run("PicksFirstStoreInList", func(t *testing.T) {
+		result, err := autoReservationService.CreateAutoReservation(ctx, productID, []string{"VAL-001", "MAD-001", "BCN-001"}, "customer-1", 5, 30, "", false, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(result.Reservations) != 1 || result.Reservations[0].StoreID != "MAD-001" || result.Split {
+			t.Errorf("Expected a single reservation in MAD-001, got %+v", result.Reservations)
+		}
+	})
+
+	t.Run("AnyPicksMostAvailable", func(t *testing.T) {
+		result, err := autoReservationService.CreateAutoReservation(ctx, productID, []string{domain.AutoReservationAnyStore}, "customer-2", 3, 30, domain.ReservationPriorityHigh, false, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Reservations[0].StoreID != "BCN-001" || result.Reservations[0].Priority != domain.ReservationPriorityHigh {
+			t.Errorf("Expected HIGH reservation in BCN-001, got %+v", result.Reservations[0])
+		}
+	})
+
+	t.Run("NoSingleStoreWithoutSplit", func(t *testing.T) {
+		_, err := autoReservationService.CreateAutoReservation(ctx, productID, []string{"SEV-001", "MAD-001"}, "customer-3", 25, 30, "", false, "")
+		if _, ok := err.(*domain.InsufficientStockError); !ok {
+			t.Errorf("Expected InsufficientStockError, got %v", err)
+		}
+	})
+
+	t.Run("SplitsInListOrder", func(t *testing.T) {
+		// SEV-001 aporta sus 17 unidades y MAD-001 (13 tras la primera reserva) el resto
+		result, err := autoReservationService.CreateAutoReservation(ctx, productID, []string{"SEV-001", "MAD-001"}, "customer-3", 25, 30, "", true, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !result.Split || len(result.Reservations) != 2 {
+			t.Fatalf("Expected split across two stores, got %+v", result)
+		}
+		if result.Reservations[0].StoreID != "SEV-001" || result.Reservations[0].Quantity != 17 ||
+			result.Reservations[1].StoreID != "MAD-001" || result.Reservations[1].Quantity != 8 {
+			t.Errorf("Unexpected split: %+v, %+v", result.Reservations[0], result.Reservations[1])
+		}
+	})
+
+	t.Run("SplitShortLeavesNoReservations", func(t *testing.T) {
+		before := reserved("BCN-001")
+
+		_, err := autoReservationService.CreateAutoReservation(ctx, productID, []string{"BCN-001", "VAL-001"}, "customer-4", 100, 30, "", true, "")
+		if _, ok := err.(*domain.InsufficientStockError); !ok {
+			t.Errorf("Expected InsufficientStockError, got %v", err)
+		}
+		if got := reserved("BCN-001"); got != before {
+			t.Errorf("Expected BCN-001 reserved to stay at %d, got %d", before, got)
+		}
+	})
+
+	t.Run("RespectsStoreScope", func(t *testing.T) {
+		scoped := domain.WithStoreScope(ctx, []string{"SEV-001"})
+
+		if _, err := autoReservationService.CreateAutoReservation(scoped, productID, []string{"BCN-001"}, "customer-5", 1, 30, "", false, ""); err == nil {
+			t.Error("Expected error reserving outside the key's stores")
+		} else if _, ok := err.(*domain.ForbiddenError); !ok {
+			t.Errorf("Expected ForbiddenError, got %v", err)
+		}
+
+		// SEV-001 ya no tiene disponibilidad: any no sale de las tiendas de la key
+		_, err := autoReservationService.CreateAutoReservation(scoped, productID, nil, "customer-5", 1, 30, "", false, "")
+		if _, ok := err.(*domain.InsufficientStockError); !ok {
+			t.Errorf("Expected InsufficientStockError within the key's stores, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestAvailabilityCache(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	cache := mocks.NewMemoryAvailabilityCache()
+	inner := mocks.NewMockPublisher()
+	publisher := infrastructure.NewAvailabilityCachePublisher(inner, cache)
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	stockService.SetAvailabilityCache(cache)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, eventRepo, publisher)
+
+	ctx := context.Background()
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(ctx, product.ID, "CACHE-001", 10); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+
+	t.Run("MissSeedsFromDatabase", func(t *testing.T) {
+		available, err := stockService.GetAvailableStock(ctx, product.ID, "CACHE-001")
+		if err != nil || available != 10 {
+			t.Fatalf("Expected 10 available, got %d (%v)", available, err)
+		}
+		if cached, ok := cache.Peek(product.ID, "CACHE-001"); !ok || cached != 10 {
+			t.Fatalf("Expected cache seeded with 10, got %d (ok=%v)", cached, ok)
+		}
+
+		if _, err := stockService.GetAvailableStock(ctx, product.ID, "CACHE-001"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cache.Hits != 1 || cache.Misses != 1 {
+			t.Errorf("Expected 1 hit and 1 miss, got %d hits and %d misses", cache.Hits, cache.Misses)
+		}
+	})
+
+	t.Run("ReservationsAdjustCounters", func(t *testing.T) {
+		reservation, err := reservationService.CreateReservation(ctx, product.ID, "CACHE-001", "customer-1", 3, 15)
+		if err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+		if cached, _ := cache.Peek(product.ID, "CACHE-001"); cached != 7 {
+			t.Fatalf("Expected 7 after reserve, got %d", cached)
+		}
+
+		if err := reservationService.CancelReservation(ctx, reservation.ID); err != nil {
+			t.Fatalf("Error cancelling reservation: %v", err)
+		}
+		if cached, _ := cache.Peek(product.ID, "CACHE-001"); cached != 10 {
+			t.Fatalf("Expected 10 after release, got %d", cached)
+		}
+
+		confirmed, err := reservationService.CreateReservation(ctx, product.ID, "CACHE-001", "customer-1", 2, 15)
+		if err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+		if err := reservationService.ConfirmReservation(ctx, confirmed.ID); err != nil {
+			t.Fatalf("Error confirming reservation: %v", err)
+		}
+
+		available, err := stockService.GetAvailableStock(ctx, product.ID, "CACHE-001")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		stock, _ := stockRepo.GetByProductAndStore(ctx, product.ID, "CACHE-001")
+		if available != stock.Available() {
+			t.Errorf("Cache drifted from database: cache=%d db=%d", available, stock.Available())
+		}
+	})
+
+	t.Run("StockUpdateInvalidates", func(t *testing.T) {
+		if _, err := stockService.UpdateStock(ctx, product.ID, "CACHE-001", 50); err != nil {
+			t.Fatalf("Error updating stock: %v", err)
+		}
+		if _, ok := cache.Peek(product.ID, "CACHE-001"); ok {
+			t.Fatal("Expected stock.updated to invalidate the entry")
+		}
+
+		available, err := stockService.GetAvailableStock(ctx, product.ID, "CACHE-001")
+		if err != nil || available != 50 {
+			t.Errorf("Expected 50 available after re-seed, got %d (%v)", available, err)
+		}
+	})
+
+	t.Run("CountersNotCreatedFromDeltas", func(t *testing.T) {
+		if err := cache.Invalidate(ctx, product.ID, "CACHE-001"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := reservationService.CreateReservation(ctx, product.ID, "CACHE-001", "customer-2", 1, 15); err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+		if _, ok := cache.Peek(product.ID, "CACHE-001"); ok {
+			t.Error("Expected no entry: a DECRBY on a missing key must not create it")
+		}
+	})
+}
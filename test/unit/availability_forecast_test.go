@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestAvailabilityForecast(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewNoOpPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	scheduleRepo := repository.NewStockScheduleRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+	scheduleService := service.NewStockScheduleService(scheduleRepo, stockService)
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+
+	silenceLogs(t)
+	ctx := domain.WithActor(context.Background(), "purchasing")
+	date := time.Now().Add(48 * time.Hour)
+
+	var validation *domain.ValidationError
+	if _, err := stockService.GetAvailabilityForecast(ctx, "any", "MAD-001", date, 0); !errors.As(err, &validation) {
+		t.Errorf("Expected ValidationError while the forecast is not configured, got %v", err)
+	}
+	stockService.SetAvailabilityForecast(scheduleRepo, reservationRepo)
+
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(ctx, product.ID, "MAD-001", 10); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+	// 3 unidades en una reserva pendiente que caduca en 30 minutos
+	if _, err := reservationService.CreateReservation(ctx, product.ID, "MAD-001", "customer-1", 3, 30); err != nil {
+		t.Fatalf("Error creating reservation: %v", err)
+	}
+
+	inbound, err := scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+		ProductID: product.ID, StoreID: "MAD-001", Quantity: 50, Source: "purchase_order", Reference: " PO-2026-0142 ",
+		EffectiveAt: time.Now().Add(24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Error scheduling inbound purchase order: %v", err)
+	}
+	if inbound.Source != domain.ScheduledStockPurchaseOrder || inbound.Reference != "PO-2026-0142" {
+		t.Errorf("Expected normalized PURCHASE_ORDER source and reference, got %s %q", inbound.Source, inbound.Reference)
+	}
+	// Llega después de la fecha: no cuenta
+	if _, err := scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+		ProductID: product.ID, StoreID: "MAD-001", Quantity: 20, Source: domain.ScheduledStockTransfer,
+		EffectiveAt: time.Now().Add(96 * time.Hour),
+	}); err != nil {
+		t.Fatalf("Error scheduling inbound transfer: %v", err)
+	}
+	if _, err := scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+		ProductID: product.ID, StoreID: "MAD-001", Quantity: 5, Source: "SUPPLIER", EffectiveAt: time.Now().Add(time.Hour),
+	}); !errors.As(err, &validation) {
+		t.Errorf("Expected ValidationError for unknown source, got %v", err)
+	}
+
+	forecast, err := stockService.GetAvailabilityForecast(ctx, product.ID, "MAD-001", date, 55)
+	if err != nil {
+		t.Fatalf("Error getting availability forecast: %v", err)
+	}
+	if forecast.Available != 7 || forecast.ExpiringReservations != 3 || forecast.Inbound != 50 {
+		t.Errorf("Expected available=7 expiring=3 inbound=50, got %+v", forecast)
+	}
+	if forecast.ProjectedQuantity != 60 || forecast.ProjectedReserved != 0 || forecast.ProjectedAvailable != 60 {
+		t.Errorf("Expected projected quantity=60 reserved=0 available=60, got %+v", forecast)
+	}
+	if len(forecast.ScheduledChanges) != 1 || forecast.ScheduledChanges[0].ID != inbound.ID {
+		t.Errorf("Expected only the purchase order before the date, got %d changes", len(forecast.ScheduledChanges))
+	}
+	if forecast.Sufficient == nil || !*forecast.Sufficient {
+		t.Errorf("Expected 55 units to be promisable by the date, got %+v", forecast.Sufficient)
+	}
+
+	// Sin cantidad no se evalúa; antes de la llegada solo cuentan las reservas que caducan
+	early, err := stockService.GetAvailabilityForecast(ctx, product.ID, "MAD-001", time.Now().Add(2*time.Hour), 0)
+	if err != nil {
+		t.Fatalf("Error getting early forecast: %v", err)
+	}
+	if early.Sufficient != nil || early.Inbound != 0 || early.ProjectedAvailable != 10 {
+		t.Errorf("Expected 10 units available before the purchase order arrives, got %+v", early)
+	}
+
+	if _, err := stockService.GetAvailabilityForecast(ctx, product.ID, "MAD-001", time.Now().Add(-time.Hour), 0); !errors.As(err, &validation) {
+		t.Errorf("Expected ValidationError for a past date, got %v", err)
+	}
+	var notFound *domain.NotFoundError
+	if _, err := stockService.GetAvailabilityForecast(ctx, product.ID, "BCN-001", date, 0); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError for a store without stock, got %v", err)
+	}
+}
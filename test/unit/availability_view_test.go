@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestAvailabilityView(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	viewRepo := repository.NewAvailabilityViewRepository(db)
+	projector := service.NewAvailabilityProjector(viewRepo)
+
+	ctx := context.Background()
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+
+	result, err := projector.Rebuild(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Rows != 20 {
+		t.Errorf("Expected 20 projected rows, got %d", result.Rows)
+	}
+
+	publisher := infrastructure.NewProjectionPublisher(mocks.NewNoOpPublisher(), "availability_view", projector.HandleEvent)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	stockService.SetAvailabilityView(viewRepo)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, eventRepo, publisher)
+
+	t.Run("ProjectedFromEvents", func(t *testing.T) {
+		if _, err := stockService.UpdateStock(ctx, laptop, "MAD-001", 30); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := reservationService.CreateReservation(ctx, laptop, "MAD-001", "customer-view", 4, 30); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		view, err := viewRepo.Get(ctx, laptop, "MAD-001")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if view.Available != 26 || view.Status != domain.AvailabilityInStock {
+			t.Errorf("Expected 26 available in_stock, got %d %s", view.Available, view.Status)
+		}
+
+		available, err := stockService.GetAvailableStock(ctx, laptop, "MAD-001")
+		if err != nil || available != 26 {
+			t.Errorf("Expected 26 available from the view, got %d (%v)", available, err)
+		}
+	})
+
+	t.Run("SafetyStockRefreshesView", func(t *testing.T) {
+		if _, err := stockService.SetSafetyStock(ctx, laptop, "MAD-001", 20); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		view, err := viewRepo.Get(ctx, laptop, "MAD-001")
+		if err != nil || view.Available != 6 || view.Status != domain.AvailabilityLowStock {
+			t.Errorf("Expected 6 available low_stock, got %+v (%v)", view, err)
+		}
+	})
+
+	t.Run("LowStockMatchesWriteTable", func(t *testing.T) {
+		filter := domain.LowStockFilter{Threshold: domain.DefaultMinStock}
+		fromView, total, err := stockService.GetLowStockItems(ctx, filter)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		filter.Limit = domain.DefaultLowStockLimit
+		fromStock, err := stockRepo.GetLowStockItems(ctx, filter)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(fromView) != len(fromStock) || total != len(fromStock) {
+			t.Fatalf("Expected %d low stock rows, got %d (total %d)", len(fromStock), len(fromView), total)
+		}
+		for i := range fromStock {
+			if fromView[i].ID != fromStock[i].ID {
+				t.Errorf("Row %d: expected %s, got %s", i, fromStock[i].ID, fromView[i].ID)
+			}
+		}
+	})
+
+	t.Run("RebuildRepairsDrift", func(t *testing.T) {
+		// Cambio sin evento: el read model se queda desfasado hasta reconstruirlo
+		if _, err := db.Exec(`UPDATE stock SET quantity = 0, reserved = 0 WHERE product_id = ? AND store_id = 'BCN-001'`, laptop); err != nil {
+			t.Fatalf("Failed to update stock: %v", err)
+		}
+		if _, err := projector.Rebuild(ctx); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		view, err := viewRepo.Get(ctx, laptop, "BCN-001")
+		if err != nil || view.Available != 0 || view.Status != domain.AvailabilityOutOfStock {
+			t.Errorf("Expected out_of_stock after rebuild, got %+v (%v)", view, err)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"inventory-system/internal/database"
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+)
+
+func TestBackupService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	dir := t.TempDir()
+	backupService := service.NewBackupService(repository.NewBackupRepository(db), dir, 2)
+	ctx := context.Background()
+
+	var products int
+	db.QueryRow(`SELECT COUNT(*) FROM products`).Scan(&products)
+
+	var backups []*domain.Backup
+	for i := 0; i < 3; i++ {
+		backup, err := backupService.CreateBackup(ctx)
+		if err != nil {
+			t.Fatalf("Error creating backup: %v", err)
+		}
+		backups = append(backups, backup)
+	}
+
+	t.Run("KeepsMostRecentBackups", func(t *testing.T) {
+		listed, err := backupService.ListBackups(ctx)
+		if err != nil {
+			t.Fatalf("Error listing backups: %v", err)
+		}
+		if len(listed) != 2 || listed[0].Name != backups[2].Name || listed[1].Name != backups[1].Name {
+			t.Fatalf("Expected the 2 most recent backups, got %+v", listed)
+		}
+		if _, err := os.Stat(filepath.Join(dir, backups[0].Name)); !os.IsNotExist(err) {
+			t.Errorf("Expected oldest backup to be removed")
+		}
+	})
+
+	t.Run("BackupIsUsableDatabase", func(t *testing.T) {
+		copyDB, err := sql.Open("sqlite", filepath.Join(dir, backups[2].Name))
+		if err != nil {
+			t.Fatalf("Error opening backup: %v", err)
+		}
+		defer copyDB.Close()
+
+		var copied int
+		if err := copyDB.QueryRow(`SELECT COUNT(*) FROM products`).Scan(&copied); err != nil {
+			t.Fatalf("Error querying backup: %v", err)
+		}
+		if copied != products {
+			t.Errorf("Expected %d products in backup, got %d", products, copied)
+		}
+	})
+
+	t.Run("OpenBackup_RejectsUnknownNames", func(t *testing.T) {
+		for _, name := range []string{"../inventory.db", "inventory-20200101T000000.000Z.db"} {
+			_, _, err := backupService.OpenBackup(ctx, name)
+			var notFound *domain.NotFoundError
+			if !errors.As(err, &notFound) {
+				t.Errorf("Expected NotFoundError for %q, got %v", name, err)
+			}
+		}
+
+		backup, content, err := backupService.OpenBackup(ctx, backups[2].Name)
+		if err != nil {
+			t.Fatalf("Error opening backup: %v", err)
+		}
+		content.Close()
+		if backup.SizeBytes != backups[2].SizeBytes {
+			t.Errorf("Expected size %d, got %d", backups[2].SizeBytes, backup.SizeBytes)
+		}
+	})
+
+	t.Run("RestoreBackup", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "inventory.db")
+		if err := os.WriteFile(dbPath, []byte("corrupted"), 0o644); err != nil {
+			t.Fatalf("Error writing database: %v", err)
+		}
+
+		previous, err := database.RestoreBackup(filepath.Join(dir, backups[2].Name), dbPath)
+		if err != nil {
+			t.Fatalf("Error restoring backup: %v", err)
+		}
+		if kept, _ := os.ReadFile(previous); string(kept) != "corrupted" {
+			t.Errorf("Expected previous database kept at %s", previous)
+		}
+
+		restored, err := sql.Open("sqlite", dbPath)
+		if err != nil {
+			t.Fatalf("Error opening restored database: %v", err)
+		}
+		defer restored.Close()
+		var count int
+		if err := restored.QueryRow(`SELECT COUNT(*) FROM products`).Scan(&count); err != nil || count != products {
+			t.Errorf("Expected %d products after restore, got %d (err %v)", products, count, err)
+		}
+
+		// Un backup corrupto no reemplaza la base de datos
+		if _, err := database.RestoreBackup(previous, dbPath); err == nil {
+			t.Errorf("Expected corrupted backup to be rejected")
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestChannelAllocations(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewMockPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, eventRepo, publisher)
+	channelService := service.NewChannelAllocationService(repository.NewChannelAllocationRepository(db))
+
+	ctx := context.Background()
+	web := domain.WithSalesChannel(ctx, domain.SalesChannelWeb)
+	marketplace := domain.WithSalesChannel(ctx, domain.SalesChannelMarketplace)
+
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(ctx, product.ID, "MAD-001", 10); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+
+	t.Run("Validation", func(t *testing.T) {
+		var validation *domain.ValidationError
+		if err := channelService.SetAllocation(ctx, product.ID, "MAD-001", "PHONE", 1); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for unknown channel, got %v", err)
+		}
+		if err := channelService.TransferAllocation(ctx, product.ID, "MAD-001", "WEB", "WEB", 1); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for same channel transfer, got %v", err)
+		}
+		var insufficient *domain.InsufficientStockError
+		if err := channelService.SetAllocation(ctx, product.ID, "MAD-001", "WEB", 11); !errors.As(err, &insufficient) {
+			t.Errorf("Expected InsufficientStockError when allocating above sellable, got %v", err)
+		}
+	})
+
+	t.Run("MarketplaceCannotConsumeWebAllocation", func(t *testing.T) {
+		if err := channelService.SetAllocation(ctx, product.ID, "MAD-001", "web", 6); err != nil {
+			t.Fatalf("Error setting web allocation: %v", err)
+		}
+
+		available, err := stockService.GetAvailableStock(marketplace, product.ID, "MAD-001")
+		if err != nil || available != 4 {
+			t.Errorf("Expected 4 units for marketplace, got %d (%v)", available, err)
+		}
+		available, err = stockService.GetAvailableStock(web, product.ID, "MAD-001")
+		if err != nil || available != 6 {
+			t.Errorf("Expected 6 units for web, got %d (%v)", available, err)
+		}
+
+		var insufficient *domain.InsufficientStockError
+		if _, err := reservationService.CreateReservation(marketplace, product.ID, "MAD-001", "customer-1", 5, 0); !errors.As(err, &insufficient) {
+			t.Fatalf("Expected InsufficientStockError for marketplace, got %v", err)
+		}
+		if _, err := reservationService.CreateReservation(marketplace, product.ID, "MAD-001", "customer-1", 4, 0); err != nil {
+			t.Fatalf("Expected marketplace reservation of the unallocated units, got %v", err)
+		}
+
+		reservation, err := reservationService.CreateReservation(web, product.ID, "MAD-001", "customer-2", 6, 0)
+		if err != nil {
+			t.Fatalf("Expected web reservation of its allocation, got %v", err)
+		}
+		if reservation.Channel != domain.SalesChannelWeb {
+			t.Errorf("Expected WEB channel on reservation, got %q", reservation.Channel)
+		}
+
+		var payload domain.ReservationEventPayload
+		if err := json.Unmarshal([]byte(publisher.GetLastEvent().Payload), &payload); err != nil {
+			t.Fatalf("Error decoding event payload: %v", err)
+		}
+		if payload.Channel != domain.SalesChannelWeb {
+			t.Errorf("Expected WEB channel in reservation.created, got %q", payload.Channel)
+		}
+
+		if err := reservationService.CancelReservation(ctx, reservation.ID); err != nil {
+			t.Fatalf("Error cancelling web reservation: %v", err)
+		}
+		available, _ = stockService.GetAvailableStock(web, product.ID, "MAD-001")
+		if available != 6 {
+			t.Errorf("Expected cancellation to return 6 units to web, got %d", available)
+		}
+	})
+
+	t.Run("TransferBetweenAllocations", func(t *testing.T) {
+		var insufficient *domain.InsufficientStockError
+		if err := channelService.TransferAllocation(ctx, product.ID, "MAD-001", "WEB", "MARKETPLACE", 7); !errors.As(err, &insufficient) {
+			t.Errorf("Expected InsufficientStockError transferring above remaining, got %v", err)
+		}
+		if err := channelService.TransferAllocation(ctx, product.ID, "MAD-001", "WEB", "MARKETPLACE", 2); err != nil {
+			t.Fatalf("Error transferring allocation: %v", err)
+		}
+
+		_, allocations, err := channelService.GetAllocations(ctx, product.ID, "MAD-001")
+		if err != nil {
+			t.Fatalf("Error getting allocations: %v", err)
+		}
+		webAllocation := domain.FindChannelAllocation(allocations, domain.SalesChannelWeb)
+		marketAllocation := domain.FindChannelAllocation(allocations, domain.SalesChannelMarketplace)
+		if webAllocation == nil || webAllocation.Allocated != 4 || marketAllocation == nil || marketAllocation.Allocated != 2 {
+			t.Errorf("Expected WEB 4 and MARKETPLACE 2 after transfer, got %+v", allocations)
+		}
+	})
+
+	t.Run("ConfirmConsumesAllocation", func(t *testing.T) {
+		reservation, err := reservationService.CreateReservation(web, product.ID, "MAD-001", "customer-3", 3, 0)
+		if err != nil {
+			t.Fatalf("Error creating web reservation: %v", err)
+		}
+		if err := reservationService.ConfirmReservation(ctx, reservation.ID); err != nil {
+			t.Fatalf("Error confirming web reservation: %v", err)
+		}
+
+		_, allocations, _ := channelService.GetAllocations(ctx, product.ID, "MAD-001")
+		webAllocation := domain.FindChannelAllocation(allocations, domain.SalesChannelWeb)
+		if webAllocation.Allocated != 1 || webAllocation.Reserved != 0 {
+			t.Errorf("Expected WEB allocated 1 and reserved 0 after confirm, got %+v", webAllocation)
+		}
+
+		var payload domain.ReservationConfirmedPayload
+		if err := json.Unmarshal([]byte(publisher.GetLastEvent().Payload), &payload); err != nil {
+			t.Fatalf("Error decoding event payload: %v", err)
+		}
+		if payload.Channel != domain.SalesChannelWeb {
+			t.Errorf("Expected WEB channel in reservation.confirmed, got %q", payload.Channel)
+		}
+	})
+
+	t.Run("RejectsOtherStoreScope", func(t *testing.T) {
+		barcelona := domain.WithStoreScope(ctx, []string{"BCN-001"})
+		var forbidden *domain.ForbiddenError
+		if err := channelService.SetAllocation(barcelona, product.ID, "MAD-001", "STORE", 1); !errors.As(err, &forbidden) {
+			t.Errorf("Expected ForbiddenError setting another store's allocation, got %v", err)
+		}
+		if err := channelService.TransferAllocation(barcelona, product.ID, "MAD-001", "WEB", "STORE", 1); !errors.As(err, &forbidden) {
+			t.Errorf("Expected ForbiddenError transferring another store's allocation, got %v", err)
+		}
+		if err := channelService.DeleteAllocation(barcelona, product.ID, "MAD-001", "MARKETPLACE"); !errors.As(err, &forbidden) {
+			t.Errorf("Expected ForbiddenError deleting another store's allocation, got %v", err)
+		}
+
+		_, allocations, _ := channelService.GetAllocations(ctx, product.ID, "MAD-001")
+		if domain.FindChannelAllocation(allocations, domain.SalesChannelStore) != nil ||
+			domain.FindChannelAllocation(allocations, domain.SalesChannelMarketplace) == nil {
+			t.Errorf("Expected allocations unchanged after rejected writes, got %+v", allocations)
+		}
+
+		madrid := domain.WithStoreScope(ctx, []string{"MAD-001"})
+		if err := channelService.TransferAllocation(madrid, product.ID, "MAD-001", "MARKETPLACE", "WEB", 1); err != nil {
+			t.Errorf("Expected write within the key's store scope, got %v", err)
+		}
+	})
+
+	t.Run("AllocationBelowReserved", func(t *testing.T) {
+		if _, err := reservationService.CreateReservation(web, product.ID, "MAD-001", "customer-4", 1, 0); err != nil {
+			t.Fatalf("Error creating web reservation: %v", err)
+		}
+		var conflict *domain.ConflictError
+		if err := channelService.SetAllocation(ctx, product.ID, "MAD-001", "WEB", 0); !errors.As(err, &conflict) {
+			t.Errorf("Expected ConflictError lowering allocation below reserved, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestCircuitBreakerPublisher(t *testing.T) {
+	inner := mocks.NewMockPublisher()
+	inner.ShouldFail = true
+	breaker := infrastructure.NewCircuitBreakerPublisher(inner, infrastructure.CircuitBreakerConfig{
+		FailureThreshold: 3,
+		OpenTimeout:      50 * time.Millisecond,
+	})
+	defer breaker.Close()
+	ctx := context.Background()
+	event := domain.NewStockCreatedEvent("prod-1", "MAD-001", 1)
+
+	t.Run("OpensAfterConsecutiveFailures", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			if err := breaker.Publish(ctx, event); errors.Is(err, domain.ErrPublisherUnavailable) {
+				t.Fatalf("Attempt %d should reach the broker", i+1)
+			}
+		}
+
+		status := breaker.Status()
+		if status.State != domain.CircuitOpen || status.OpensTotal != 1 {
+			t.Fatalf("Expected open circuit after 3 failures, got %+v", status)
+		}
+	})
+
+	t.Run("FailsFastWhileOpen", func(t *testing.T) {
+		calls := inner.PublishCount
+		for i := 0; i < 5; i++ {
+			if err := breaker.Publish(ctx, event); !errors.Is(err, domain.ErrPublisherUnavailable) {
+				t.Fatalf("Expected ErrPublisherUnavailable, got %v", err)
+			}
+		}
+		if inner.PublishCount != calls {
+			t.Errorf("Expected broker not to be contacted while open, got %d calls", inner.PublishCount-calls)
+		}
+		if breaker.Status().RejectedTotal != 5 {
+			t.Errorf("Expected 5 rejected publishes, got %d", breaker.Status().RejectedTotal)
+		}
+	})
+
+	t.Run("FailedProbeReopens", func(t *testing.T) {
+		time.Sleep(60 * time.Millisecond)
+		if err := breaker.Publish(ctx, event); errors.Is(err, domain.ErrPublisherUnavailable) || err == nil {
+			t.Fatalf("Expected probe to reach the broker and fail, got %v", err)
+		}
+		if breaker.Status().State != domain.CircuitOpen {
+			t.Errorf("Expected circuit to reopen after failed probe, got %s", breaker.Status().State)
+		}
+	})
+
+	t.Run("SuccessfulProbeCloses", func(t *testing.T) {
+		inner.ShouldFail = false
+		time.Sleep(60 * time.Millisecond)
+		if err := breaker.Publish(ctx, event); err != nil {
+			t.Fatalf("Expected probe to succeed, got %v", err)
+		}
+		status := breaker.Status()
+		if status.State != domain.CircuitClosed || status.ConsecutiveFailures != 0 {
+			t.Errorf("Expected closed circuit, got %+v", status)
+		}
+
+		metrics := handler.FormatPublisherMetrics(status, 2)
+		for _, want := range []string{
+			`inventory_publisher_circuit_state{inventory_publisher_circuit_state="closed"} 1`,
+			"inventory_publisher_rejected_total 5",
+			"inventory_outbox_pending_events 2",
+		} {
+			if !strings.Contains(metrics, want) {
+				t.Errorf("Expected metrics to contain %q, got:\n%s", want, metrics)
+			}
+		}
+	})
+}
+
+func TestEventSyncService_StopsWhenCircuitOpen(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	eventRepo := repository.NewEventRepository(db)
+	ctx := context.Background()
+
+	inner := mocks.NewMockPublisher()
+	inner.ShouldFail = true
+	breaker := infrastructure.NewCircuitBreakerPublisher(inner, infrastructure.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Hour,
+	})
+	defer breaker.Close()
+
+	for i := 0; i < 10; i++ {
+		eventRepo.Save(ctx, domain.NewStockCreatedEvent(testutil.GenerateID(), "MAD-001", i))
+	}
+
+	syncService := service.NewEventSyncService(eventRepo, breaker)
+	synced, err := syncService.SyncPendingEvents(ctx, 10)
+	if err != nil || synced != 0 {
+		t.Fatalf("Expected nothing synced, got %d (%v)", synced, err)
+	}
+
+	// Dos intentos abren el circuito; el resto del lote no llega al broker
+	if inner.PublishCount != 2 {
+		t.Errorf("Expected 2 broker calls before the circuit opened, got %d", inner.PublishCount)
+	}
+	if pending, _ := syncService.GetPendingEventsCount(ctx); pending != 10 {
+		t.Errorf("Expected 10 events kept in the outbox, got %d", pending)
+	}
+}
@@ -0,0 +1,253 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/infrastructure"
+
+	"cloud.google.com/go/pubsub/apiv1/pubsubpb"
+	"cloud.google.com/go/pubsub/pstest"
+)
+
+func TestPubSubPublisher_OrderingKeyAndAttributes(t *testing.T) {
+	silenceLogs(t)
+
+	server := pstest.NewServer()
+	defer server.Close()
+	if _, err := server.GServer.CreateTopic(context.Background(), &pubsubpb.Topic{
+		Name: "projects/my-project/topics/inventory-events",
+	}); err != nil {
+		t.Fatalf("Error creating topic: %v", err)
+	}
+
+	publisher, err := infrastructure.NewPubSubPublisher(infrastructure.PubSubPublisherConfig{
+		ProjectID:    "my-project",
+		Topic:        "inventory-events",
+		EmulatorHost: server.Addr,
+	})
+	if err != nil {
+		t.Fatalf("Error creating publisher: %v", err)
+	}
+
+	event := domain.NewReservationCreatedEvent("res-1", "prod-1", "MAD-001", 2)
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	batch := []*domain.Event{
+		domain.NewStockUpdatedEvent("prod-1", "BCN-001", 1, 2),
+		domain.NewStockUpdatedEvent("prod-2", "BCN-001", 3, 4),
+	}
+	if err := publisher.PublishBatch(context.Background(), batch); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := publisher.Close(); err != nil {
+		t.Fatalf("Expected clean close, got %v", err)
+	}
+	if err := publisher.Publish(context.Background(), event); err == nil {
+		t.Error("Expected error publishing after Close")
+	}
+
+	messages := server.Messages()
+	if len(messages) != 3 {
+		t.Fatalf("Expected 3 messages, got %d", len(messages))
+	}
+
+	var first *pstest.Message
+	for _, message := range messages {
+		if message.Attributes["event_id"] == event.ID {
+			first = message
+		}
+	}
+	if first == nil {
+		t.Fatalf("Expected message for event %s", event.ID)
+	}
+	if first.OrderingKey != "res-1" {
+		t.Errorf("Expected ordering key res-1, got %q", first.OrderingKey)
+	}
+	if first.Attributes["event_type"] != "reservation.created" || first.Attributes["store_id"] != "MAD-001" {
+		t.Errorf("Expected event_type/store_id attributes, got %v", first.Attributes)
+	}
+	var decoded domain.Event
+	if err := json.Unmarshal(first.Data, &decoded); err != nil || decoded.ID != event.ID {
+		t.Errorf("Expected event %s in data, got %s", event.ID, first.Data)
+	}
+}
+
+// awsTestCredentials credenciales estáticas: el servidor de prueba comprueba la firma SigV4 del SDK
+var awsTestCredentials = infrastructure.AWSCredentialsConfig{
+	AccessKeyID:     "AKID",
+	SecretAccessKey: "secret",
+}
+
+// requireSigV4 rechaza las peticiones que no vienen firmadas para el servicio y la región
+func requireSigV4(w http.ResponseWriter, r *http.Request, service string) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+		!strings.Contains(auth, "/eu-west-1/"+service+"/aws4_request") {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>SignatureDoesNotMatch</Code><Message>bad</Message></Error></ErrorResponse>`)
+		return false
+	}
+	return true
+}
+
+func TestSNSPublisher_FIFOAndBatchFailures(t *testing.T) {
+	silenceLogs(t)
+
+	var (
+		mu       sync.Mutex
+		requests []url.Values
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !requireSigV4(w, r, "sns") {
+			return
+		}
+		r.ParseForm()
+		mu.Lock()
+		requests = append(requests, r.PostForm)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/xml")
+		switch r.PostForm.Get("Action") {
+		case "Publish":
+			fmt.Fprint(w, `<PublishResponse><PublishResult><MessageId>m-1</MessageId></PublishResult></PublishResponse>`)
+		case "PublishBatch":
+			fmt.Fprint(w, `<PublishBatchResponse><PublishBatchResult>
+				<Successful><member><Id>0</Id><MessageId>m-2</MessageId></member></Successful>
+				<Failed><member><Id>1</Id><Code>InternalError</Code><Message>try again</Message><SenderFault>false</SenderFault></member></Failed>
+			</PublishBatchResult></PublishBatchResponse>`)
+		}
+	}))
+	defer server.Close()
+
+	publisher, err := infrastructure.NewSNSPublisher(infrastructure.SNSPublisherConfig{
+		AWSCredentialsConfig: awsTestCredentials,
+		TopicARN:             "arn:aws:sns:eu-west-1:123456789012:inventory-events.fifo",
+		Endpoint:             server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Error creating publisher: %v", err)
+	}
+	defer publisher.Close()
+
+	event := domain.NewStockCreatedEvent("prod-1", "MAD-001", 10)
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	batch := []*domain.Event{
+		domain.NewStockUpdatedEvent("prod-1", "MAD-001", 10, 8),
+		domain.NewStockUpdatedEvent("prod-2", "BCN-001", 5, 4),
+	}
+	err = publisher.PublishBatch(context.Background(), batch)
+	if err == nil || !strings.Contains(err.Error(), batch[1].ID) {
+		t.Errorf("Expected failure for event %s, got %v", batch[1].ID, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	publish := requests[0]
+	if publish.Get("MessageGroupId") != "prod-1" || publish.Get("MessageDeduplicationId") != event.ID {
+		t.Errorf("Expected FIFO group prod-1 and dedup id %s, got %v", event.ID, publish)
+	}
+
+	attributes := make(map[string]string)
+	for i := 1; ; i++ {
+		name := publish.Get(fmt.Sprintf("MessageAttributes.entry.%d.Name", i))
+		if name == "" {
+			break
+		}
+		attributes[name] = publish.Get(fmt.Sprintf("MessageAttributes.entry.%d.Value.StringValue", i))
+	}
+	if attributes["event_type"] != "stock.created" || attributes["store_id"] != "MAD-001" {
+		t.Errorf("Expected event_type/store_id attributes, got %v", attributes)
+	}
+
+	if requests[1].Get("PublishBatchRequestEntries.member.2.MessageGroupId") != "prod-2" {
+		t.Errorf("Expected batch entry grouped by aggregate, got %v", requests[1])
+	}
+}
+
+func TestSQSPublisher_FIFOAndBatchFailures(t *testing.T) {
+	silenceLogs(t)
+
+	var (
+		mu       sync.Mutex
+		requests []map[string]interface{}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !requireSigV4(w, r, "sqs") {
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var request map[string]interface{}
+		json.Unmarshal(body, &request)
+		mu.Lock()
+		requests = append(requests, request)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch r.Header.Get("X-Amz-Target") {
+		case "AmazonSQS.SendMessage":
+			fmt.Fprint(w, `{"MessageId":"m-1"}`)
+		case "AmazonSQS.SendMessageBatch":
+			fmt.Fprint(w, `{"Successful":[{"Id":"0","MessageId":"m-2"}],
+				"Failed":[{"Id":"1","Code":"InternalError","Message":"try again","SenderFault":false}]}`)
+		}
+	}))
+	defer server.Close()
+
+	// Sin AWS_REGION se toma la región de la URL de la cola
+	publisher, err := infrastructure.NewSQSPublisher(infrastructure.SQSPublisherConfig{
+		AWSCredentialsConfig: awsTestCredentials,
+		QueueURL:             "https://sqs.eu-west-1.amazonaws.com/123456789012/inventory-events.fifo",
+		Endpoint:             server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Error creating publisher: %v", err)
+	}
+	defer publisher.Close()
+
+	event := domain.NewStockCreatedEvent("prod-1", "MAD-001", 10)
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	batch := []*domain.Event{
+		domain.NewStockUpdatedEvent("prod-1", "MAD-001", 10, 8),
+		domain.NewStockUpdatedEvent("prod-2", "BCN-001", 5, 4),
+	}
+	err = publisher.PublishBatch(context.Background(), batch)
+	if err == nil || !strings.Contains(err.Error(), batch[1].ID) {
+		t.Errorf("Expected failure for event %s, got %v", batch[1].ID, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	send := requests[0]
+	if send["MessageGroupId"] != "prod-1" || send["MessageDeduplicationId"] != event.ID {
+		t.Errorf("Expected FIFO group prod-1 and dedup id %s, got %v", event.ID, send)
+	}
+	attributes, _ := send["MessageAttributes"].(map[string]interface{})
+	if storeID, _ := attributes["store_id"].(map[string]interface{}); storeID["StringValue"] != "MAD-001" {
+		t.Errorf("Expected store_id attribute, got %v", attributes)
+	}
+
+	entries, _ := requests[1]["Entries"].([]interface{})
+	if len(entries) != 2 || entries[1].(map[string]interface{})["MessageGroupId"] != "prod-2" {
+		t.Errorf("Expected batch entries grouped by aggregate, got %v", requests[1])
+	}
+}
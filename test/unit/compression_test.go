@@ -0,0 +1,118 @@
+package unit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"inventory-system/internal/middleware"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+func newCompressionRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	large := strings.Repeat(`{"product_id":"550e8400-e29b-41d4-a716-446655440000","store_id":"MAD-001"},`, 100)
+
+	router := gin.New()
+	router.Use(middleware.Compression(1024))
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/large", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(large))
+	})
+	router.GET("/xlsx", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", []byte(large))
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		c.Status(http.StatusOK)
+		c.Writer.WriteString("[1")
+		c.Writer.Flush()
+		c.Writer.WriteString(",2]")
+	})
+	return router
+}
+
+func doCompressedRequest(router *gin.Engine, path, acceptEncoding string) (*httptest.ResponseRecorder, string) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var reader io.Reader = bytes.NewReader(w.Body.Bytes())
+	switch w.Header().Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(w.Body)
+		if err != nil {
+			return w, ""
+		}
+		reader = gz
+	case "br":
+		reader = brotli.NewReader(w.Body)
+	}
+	body, _ := io.ReadAll(reader)
+	return w, string(body)
+}
+
+func TestCompression_NegotiatesEncoding(t *testing.T) {
+	router := newCompressionRouter()
+
+	for _, tc := range []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"br;q=0, gzip;q=0", ""},
+		{"*", "gzip"},
+		{"identity", ""},
+	} {
+		w, body := doCompressedRequest(router, "/large", tc.acceptEncoding)
+		if got := w.Header().Get("Content-Encoding"); got != tc.expected {
+			t.Errorf("Accept-Encoding %q: expected encoding %q, got %q", tc.acceptEncoding, tc.expected, got)
+		}
+		if !strings.HasPrefix(body, `{"product_id"`) || len(body) != 7500 {
+			t.Errorf("Accept-Encoding %q: unexpected decoded body (%d bytes)", tc.acceptEncoding, len(body))
+		}
+		if tc.expected != "" {
+			if w.Body.Len() >= len(body) || w.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("Accept-Encoding %q: expected a smaller body with Vary, got %d bytes", tc.acceptEncoding, w.Body.Len())
+			}
+		}
+	}
+}
+
+func TestCompression_SkipsSmallAndCompressedResponses(t *testing.T) {
+	router := newCompressionRouter()
+
+	w, body := doCompressedRequest(router, "/small", "gzip, br")
+	if w.Header().Get("Content-Encoding") != "" || body != `{"status":"ok"}` {
+		t.Errorf("Expected an uncompressed small response, got %q with encoding %q", body, w.Header().Get("Content-Encoding"))
+	}
+
+	w, body = doCompressedRequest(router, "/xlsx", "gzip, br")
+	if w.Header().Get("Content-Encoding") != "" || len(body) != 7500 {
+		t.Errorf("Expected the XLSX download untouched, got encoding %q", w.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestCompression_FlushCompressesStreams(t *testing.T) {
+	router := newCompressionRouter()
+
+	w, body := doCompressedRequest(router, "/stream", "gzip")
+	if w.Header().Get("Content-Encoding") != "gzip" || body != "[1,2]" {
+		t.Errorf("Expected a gzip stream with [1,2], got %q with encoding %q", body, w.Header().Get("Content-Encoding"))
+	}
+}
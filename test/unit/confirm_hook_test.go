@@ -0,0 +1,191 @@
+package unit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+// fakeConfirmHook falla las primeras failures llamadas con err y registra las confirmaciones recibidas
+type fakeConfirmHook struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	calls    []*domain.ReservationConfirmation
+}
+
+func (f *fakeConfirmHook) Name() string {
+	return "fake"
+}
+
+func (f *fakeConfirmHook) OnConfirm(ctx context.Context, confirmation *domain.ReservationConfirmation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, confirmation)
+	if len(f.calls) <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func (f *fakeConfirmHook) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func TestConfirmHooks(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+
+	ctx := context.Background()
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+
+	newService := func(hook domain.ConfirmHook, policy domain.ConfirmHookPolicy) *service.ReservationService {
+		s := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, mocks.NewNoOpPublisher())
+		s.AddConfirmHook(hook, policy)
+		return s
+	}
+	reserve := func(s *service.ReservationService) *domain.Reservation {
+		reservation, err := s.CreateReservation(ctx, laptop, "MAD-001", "customer-hook", 1, 30)
+		if err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+		return reservation
+	}
+	status := func(id string) domain.ReservationStatus {
+		reservation, err := reservationRepo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("Error getting reservation: %v", err)
+		}
+		return reservation.Status
+	}
+
+	t.Run("SyncRetriesUntilSuccess", func(t *testing.T) {
+		hook := &fakeConfirmHook{failures: 2, err: errors.New("payment gateway unavailable")}
+		s := newService(hook, domain.ConfirmHookPolicy{Mode: domain.ConfirmHookSync, MaxAttempts: 3, Backoff: time.Millisecond})
+		reservation := reserve(s)
+
+		if err := s.ConfirmReservationWithReference(ctx, reservation.ID, "TICKET-1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if hook.count() != 3 || status(reservation.ID) != domain.ReservationStatusConfirmed {
+			t.Errorf("Expected confirmed after 3 attempts, got %d attempts and %s", hook.count(), status(reservation.ID))
+		}
+		if c := hook.calls[0]; c.ReservationID != reservation.ID || c.ReferenceID != "TICKET-1" || c.UnitPrice != 599.99 {
+			t.Errorf("Unexpected confirmation payload: %+v", c)
+		}
+	})
+
+	t.Run("SyncFailureKeepsPending", func(t *testing.T) {
+		hook := &fakeConfirmHook{failures: 5, err: errors.New("timeout")}
+		s := newService(hook, domain.ConfirmHookPolicy{Mode: domain.ConfirmHookSync, MaxAttempts: 2, Backoff: time.Millisecond})
+		reservation := reserve(s)
+		before, _ := stockRepo.GetByProductAndStore(ctx, laptop, "MAD-001")
+
+		err := s.ConfirmReservation(ctx, reservation.ID)
+		var hookErr *domain.ConfirmHookError
+		if !errors.As(err, &hookErr) || hookErr.Rejected || hookErr.Attempts != 2 {
+			t.Fatalf("Expected ConfirmHookError after 2 attempts, got %v", err)
+		}
+		after, _ := stockRepo.GetByProductAndStore(ctx, laptop, "MAD-001")
+		if status(reservation.ID) != domain.ReservationStatusPending || after.Quantity != before.Quantity {
+			t.Errorf("Expected pending reservation and untouched stock, got %s (%d -> %d)", status(reservation.ID), before.Quantity, after.Quantity)
+		}
+	})
+
+	t.Run("RejectionIsNotRetried", func(t *testing.T) {
+		hook := &fakeConfirmHook{failures: 5, err: &domain.ConfirmRejectedError{Reason: "card declined"}}
+		s := newService(hook, domain.ConfirmHookPolicy{Mode: domain.ConfirmHookSync, MaxAttempts: 3, Backoff: time.Millisecond})
+		reservation := reserve(s)
+
+		err := s.ConfirmReservation(ctx, reservation.ID)
+		var hookErr *domain.ConfirmHookError
+		if !errors.As(err, &hookErr) || !hookErr.Rejected || hook.count() != 1 {
+			t.Errorf("Expected a rejection after 1 attempt, got %v (%d attempts)", err, hook.count())
+		}
+	})
+
+	t.Run("AsyncDoesNotBlockConfirm", func(t *testing.T) {
+		hook := &fakeConfirmHook{failures: 5, err: errors.New("order service down")}
+		s := newService(hook, domain.ConfirmHookPolicy{Mode: domain.ConfirmHookAsync, MaxAttempts: 2, Backoff: time.Millisecond})
+		reservation := reserve(s)
+
+		silenceLogs(t)
+		if err := s.ConfirmReservation(ctx, reservation.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		s.WaitConfirmHooks()
+		if hook.count() != 2 || status(reservation.ID) != domain.ReservationStatusConfirmed {
+			t.Errorf("Expected confirmed with 2 async attempts, got %d attempts and %s", hook.count(), status(reservation.ID))
+		}
+	})
+}
+
+func TestHTTPConfirmHook(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		body      []byte
+		signature string
+		key       string
+	)
+	responses := []int{http.StatusOK, http.StatusPaymentRequired, http.StatusServiceUnavailable}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ = io.ReadAll(r.Body)
+		signature, key = r.Header.Get("X-Inventory-Signature"), r.Header.Get("Idempotency-Key")
+		w.WriteHeader(responses[0])
+		responses = responses[1:]
+	}))
+	defer server.Close()
+
+	hook := infrastructure.NewHTTPConfirmHook(server.URL, "s3cret", time.Second)
+	ctx := context.Background()
+	confirmation := &domain.ReservationConfirmation{ReservationID: "res-1", ProductID: "p1", StoreID: "MAD-001", Quantity: 2}
+
+	if err := hook.OnConfirm(ctx, confirmation); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	mu.Lock()
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if signature != "sha256="+hex.EncodeToString(mac.Sum(nil)) || key != "res-1" {
+		t.Errorf("Unexpected headers: signature %q, idempotency key %q", signature, key)
+	}
+	var received domain.ReservationConfirmation
+	if err := json.Unmarshal(body, &received); err != nil || received.Quantity != 2 {
+		t.Errorf("Unexpected body %s (%v)", body, err)
+	}
+	mu.Unlock()
+
+	var rejected *domain.ConfirmRejectedError
+	if err := hook.OnConfirm(ctx, confirmation); !errors.As(err, &rejected) {
+		t.Errorf("Expected ConfirmRejectedError for 402, got %v", err)
+	}
+	if err := hook.OnConfirm(ctx, confirmation); err == nil || errors.As(err, &rejected) {
+		t.Errorf("Expected a retryable error for 503, got %v", err)
+	}
+}
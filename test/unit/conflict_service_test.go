@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestConflictService_ApplyRemoteStockUpdate(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	conflictRepo := repository.NewConflictRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	conflictService := service.NewConflictService(conflictRepo, stockRepo, eventRepo, mocks.NewNoOpPublisher())
+
+	ctx := context.Background()
+	productID := "550e8400-e29b-41d4-a716-446655440000"
+
+	t.Run("FastForward_NoConflict", func(t *testing.T) {
+		// MAD-001: quantity=10, reserved=0, version=1
+		conflict, err := conflictService.ApplyRemoteStockUpdate(ctx, &domain.RemoteStockUpdate{
+			ProductID:    productID,
+			StoreID:      "MAD-001",
+			BaseVersion:  1,
+			BaseQuantity: 10,
+			NewQuantity:  12,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if conflict != nil {
+			t.Errorf("Expected no conflict, got %+v", conflict)
+		}
+
+		stock, _ := stockRepo.GetByProductAndStore(ctx, productID, "MAD-001")
+		if stock.Quantity != 12 {
+			t.Errorf("Expected quantity 12, got %d", stock.Quantity)
+		}
+	})
+
+	t.Run("ConcurrentEdit_AdjustmentMerge", func(t *testing.T) {
+		// MAD-001 ya está en version=2, quantity=12. El emisor vio version=1 con 10 y vendió 3.
+		conflict, err := conflictService.ApplyRemoteStockUpdate(ctx, &domain.RemoteStockUpdate{
+			ProductID:      productID,
+			StoreID:        "MAD-001",
+			OriginInstance: "api-002",
+			BaseVersion:    1,
+			BaseQuantity:   10,
+			NewQuantity:    7,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if conflict == nil || conflict.Status != domain.ConflictStatusAutoResolved {
+			t.Fatalf("Expected AUTO_RESOLVED conflict, got %+v", conflict)
+		}
+		if conflict.ResolvedQuantity == nil || *conflict.ResolvedQuantity != 9 {
+			t.Errorf("Expected merged quantity 9, got %v", conflict.ResolvedQuantity)
+		}
+
+		stock, _ := stockRepo.GetByProductAndStore(ctx, productID, "MAD-001")
+		if stock.Quantity != 9 {
+			t.Errorf("Expected quantity 9, got %d", stock.Quantity)
+		}
+	})
+
+	t.Run("ConcurrentEdit_Unresolvable", func(t *testing.T) {
+		// BCN-001: quantity=15, reserved=2. Forzar una versión local más reciente.
+		stock, _ := stockRepo.GetByProductAndStore(ctx, productID, "BCN-001")
+		stock.Quantity = 4
+		if err := stockRepo.UpdateQuantity(ctx, stock); err != nil {
+			t.Fatalf("Error updating stock: %v", err)
+		}
+
+		// El emisor vio 15 y retiró 14: 4 - 14 < reserved
+		conflict, err := conflictService.ApplyRemoteStockUpdate(ctx, &domain.RemoteStockUpdate{
+			ProductID:    productID,
+			StoreID:      "BCN-001",
+			BaseVersion:  1,
+			BaseQuantity: 15,
+			NewQuantity:  1,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if conflict == nil || conflict.Status != domain.ConflictStatusUnresolved {
+			t.Fatalf("Expected UNRESOLVED conflict, got %+v", conflict)
+		}
+
+		unchanged, _ := stockRepo.GetByProductAndStore(ctx, productID, "BCN-001")
+		if unchanged.Quantity != 4 {
+			t.Errorf("Expected stock untouched at 4, got %d", unchanged.Quantity)
+		}
+
+		unresolved, err := conflictService.ListUnresolved(ctx, "BCN-001", 10, 0)
+		if err != nil {
+			t.Fatalf("Expected no error listing conflicts, got %v", err)
+		}
+		if len(unresolved) != 1 {
+			t.Fatalf("Expected 1 unresolved conflict, got %d", len(unresolved))
+		}
+
+		resolved, err := conflictService.ResolveConflict(ctx, conflict.ID, 3, "recount")
+		if err != nil {
+			t.Fatalf("Expected no error resolving conflict, got %v", err)
+		}
+		if resolved.Status != domain.ConflictStatusResolved {
+			t.Errorf("Expected RESOLVED, got %s", resolved.Status)
+		}
+
+		final, _ := stockRepo.GetByProductAndStore(ctx, productID, "BCN-001")
+		if final.Quantity != 3 {
+			t.Errorf("Expected quantity 3 after manual resolution, got %d", final.Quantity)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/handler"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestContentionService_Report(t *testing.T) {
+	contention := service.NewContentionService(time.Hour)
+	ctx := context.Background()
+	now := time.Now()
+
+	// Laptop en MAD-001: 10 escrituras, 4 conflictos; mouse en BCN-001: 4 escrituras, 1 conflicto
+	for i := 0; i < 10; i++ {
+		contention.RecordWrite("laptop", "MAD-001", i < 4, now.Add(-time.Duration(i)*time.Minute))
+	}
+	for i := 0; i < 4; i++ {
+		contention.RecordWrite("mouse", "BCN-001", i == 0, now.Add(-10*time.Minute))
+	}
+	// Sin conflictos no hay contención
+	contention.RecordWrite("laptop", "BCN-001", false, now)
+
+	report, err := contention.Report(ctx, now, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Count != 2 || report.TotalWrites != 15 || report.TotalConflicts != 5 {
+		t.Fatalf("Expected 2 rows with 15 writes and 5 conflicts, got %d rows, %d writes, %d conflicts",
+			report.Count, report.TotalWrites, report.TotalConflicts)
+	}
+	hottest := report.Rows[0]
+	if hottest.ProductID != "laptop" || hottest.StoreID != "MAD-001" || hottest.Writes != 10 || hottest.Conflicts != 4 || hottest.ConflictRate != 0.4 {
+		t.Errorf("Expected laptop/MAD-001 with 4 of 10 writes in conflict first, got %+v", hottest)
+	}
+	if hottest.LastConflictAt == nil || !hottest.LastConflictAt.Equal(now) {
+		t.Errorf("Expected last conflict at %v, got %v", now, hottest.LastConflictAt)
+	}
+
+	if limited, _ := contention.Report(ctx, now, 1); limited.Count != 1 || limited.Rows[0].ProductID != "laptop" {
+		t.Errorf("Expected only the hottest row with limit=1, got %+v", limited.Rows)
+	}
+
+	// Pasada la ventana las filas salen del informe, pero los totales del proceso se mantienen
+	later, _ := contention.Report(ctx, now.Add(2*time.Hour), 0)
+	if later.Count != 0 || later.TotalConflicts != 5 {
+		t.Errorf("Expected no rows but 5 total conflicts after the window, got %d rows and %d conflicts", later.Count, later.TotalConflicts)
+	}
+
+	metrics := handler.FormatContentionMetrics(report)
+	for _, line := range []string{
+		"inventory_stock_versioned_writes_total 15\n",
+		"inventory_stock_version_conflicts_total 5\n",
+		`inventory_stock_contention_conflicts{product_id="laptop",store_id="MAD-001"} 4` + "\n",
+		`inventory_stock_contention_conflict_ratio{product_id="mouse",store_id="BCN-001"} 0.25` + "\n",
+	} {
+		if !strings.Contains(metrics, line) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, metrics)
+		}
+	}
+}
+
+func TestContentionService_RecordsStockWrites(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	silenceLogs(t)
+	stockService := service.NewStockService(repository.NewStockRepository(db), repository.NewProductRepository(db), repository.NewEventRepository(db), mocks.NewNoOpPublisher())
+	contention := service.NewContentionService(time.Hour)
+	stockService.SetContentionService(contention)
+	ctx := context.Background()
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+
+	if _, err := stockService.AdjustStock(ctx, laptop, "MAD-001", 2); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := stockService.UpdateStock(ctx, laptop, "MAD-001", 40); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if writes, conflicts := contention.Totals(); writes != 2 || conflicts != 0 {
+		t.Errorf("Expected 2 writes without conflicts, got %d writes and %d conflicts", writes, conflicts)
+	}
+	// Las filas sin conflictos no aparecen en el informe
+	if report, _ := contention.Report(ctx, time.Now(), 0); report.Count != 0 {
+		t.Errorf("Expected no contended rows, got %+v", report.Rows)
+	}
+}
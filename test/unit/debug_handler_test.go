@@ -0,0 +1,64 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"inventory-system/internal/handler"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDebugRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	// Montado con un prefijo distinto de /debug, como en /api/v1/admin/debug
+	router := gin.New()
+	handler.RegisterDebugRoutes(router.Group("/api/v1/admin/debug"), handler.NewDebugHandler(db, "api-test"))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("RuntimeStats", func(t *testing.T) {
+		w := get("/api/v1/admin/debug/runtime")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var stats handler.RuntimeStats
+		if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if stats.InstanceID != "api-test" || stats.Goroutines == 0 || stats.Memory.HeapAllocBytes == 0 || stats.Database.OpenConnections == 0 {
+			t.Errorf("Unexpected runtime stats: %+v", stats)
+		}
+	})
+
+	t.Run("NamedProfilesUnderPrefix", func(t *testing.T) {
+		w := get("/api/v1/admin/debug/pprof/goroutine?debug=1")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "goroutine profile") {
+			t.Errorf("Expected goroutine profile, got %d: %.200s", w.Code, w.Body.String())
+		}
+
+		if w := get("/api/v1/admin/debug/pprof/unknown"); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for unknown profile, got %d", w.Code)
+		}
+	})
+
+	t.Run("Expvar", func(t *testing.T) {
+		w := get("/api/v1/admin/debug/vars")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"memstats"`) {
+			t.Errorf("Expected expvar output, got %d", w.Code)
+		}
+	})
+}
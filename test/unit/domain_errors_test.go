@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+)
+
+func TestDomainErrors_WrappedKeepTheirType(t *testing.T) {
+	insufficient := &domain.InsufficientStockError{ProductID: "p1", StoreID: "MAD-001", Available: 1, Requested: 5}
+	err := fmt.Errorf("failed to confirm in stock: %w", insufficient)
+
+	if !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Error("Expected errors.Is to match the sentinel through wrapping")
+	}
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrConflict) {
+		t.Error("Expected the sentinel of other domain errors not to match")
+	}
+
+	var target *domain.InsufficientStockError
+	if !errors.As(err, &target) || target.Available != 1 {
+		t.Errorf("Expected errors.As to recover the concrete error, got %v", target)
+	}
+	if domainErr, ok := domain.AsDomainError(err); !ok || domainErr.Code() != "INSUFFICIENT_STOCK" {
+		t.Errorf("Expected INSUFFICIENT_STOCK, got %v", domainErr)
+	}
+	if code := domain.ErrorCode(errors.New("boom"), "INTERNAL_ERROR"); code != "INTERNAL_ERROR" {
+		t.Errorf("Expected the fallback code for untyped errors, got %s", code)
+	}
+
+	// El primer error de dominio de la cadena es el que se clasifica
+	hookErr := &domain.ConfirmHookError{Hook: "erp", Rejected: true, Err: &domain.ValidationError{Field: "sku", Message: "unknown"}}
+	if domainErr, _ := domain.AsDomainError(fmt.Errorf("confirm: %w", hookErr)); domainErr != hookErr {
+		t.Errorf("Expected the outermost domain error, got %v", domainErr)
+	}
+}
+
+func TestErrorStatus_WrappedDomainErrors(t *testing.T) {
+	var multi handler.MultiStatusResponse
+
+	multi.Fail(0, fmt.Errorf("failed to confirm in stock: %w", &domain.InsufficientStockError{ProductID: "p1", StoreID: "MAD-001"}), nil)
+	multi.Fail(1, fmt.Errorf("failed to load product: %w", &domain.NotFoundError{Resource: "Product", ID: "p9"}), nil)
+	multi.Fail(2, errors.New("database is locked"), nil)
+
+	expected := []struct {
+		status int
+		code   string
+	}{
+		{http.StatusConflict, "INSUFFICIENT_STOCK"},
+		{http.StatusNotFound, "NOT_FOUND"},
+		{http.StatusInternalServerError, "INTERNAL_SERVER_ERROR"},
+	}
+	for i, want := range expected {
+		item := multi.Results[i]
+		if item.Status != want.status || item.Code != want.code {
+			t.Errorf("Item %d: expected %d %q, got %d %q", i, want.status, want.code, item.Status, item.Code)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/pkg/edge"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Modos de la red simulada entre el TPV y la API
+const (
+	edgeOnline       = iota
+	edgeOffline      // La API no responde (503 del balanceador)
+	edgeLostResponse // La API aplica la petición pero la respuesta no llega
+)
+
+func TestEdge_StoreAndForward(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	silenceLogs(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+
+	ring := auth.NewKeyRing(map[string]string{"key-madrid": "Store Madrid"})
+	ring.SetStoreScopes(map[string][]string{"Store Madrid": {"MAD-001"}})
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Idempotency(service.NewIdempotencyService(repository.NewIdempotencyRepository(db))))
+	handler.RegisterCoreRoutes(router.Group("/api/v1", handler.UseSerializer(handler.V1Serializer{})), middleware.APIKeyAuth(ring),
+		handler.NewProductHandler(service.NewProductService(productRepo, eventRepo)), handler.NewStockHandler(stockService),
+		handler.NewReservationHandler(reservationService, service.NewSerialService(repository.NewSerialRepository(db), reservationRepo)))
+
+	var mode atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch mode.Load() {
+		case edgeOffline:
+			http.Error(w, `{"error":"Service Unavailable"}`, http.StatusServiceUnavailable)
+		case edgeLostResponse:
+			router.ServeHTTP(httptest.NewRecorder(), r)
+			panic(http.ErrAbortHandler) // Corta la conexión sin responder
+		default:
+			router.ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	queue, err := edge.OpenQueue(filepath.Join(t.TempDir(), "edge-queue.db"))
+	if err != nil {
+		t.Fatalf("Failed to open queue: %v", err)
+	}
+	defer queue.Close()
+	pos := edge.New(edge.NewClient(server.URL, "key-madrid", nil), queue)
+
+	ctx := context.Background()
+	laptop := "550e8400-e29b-41d4-a716-446655440000" // 10 unidades en MAD-001
+	quantity := func() int {
+		stock, err := pos.Client().GetStock(ctx, laptop, "MAD-001")
+		if err != nil {
+			t.Fatalf("Failed to get stock: %v", err)
+		}
+		return stock.Quantity
+	}
+
+	stock, _, err := pos.AdjustStock(ctx, laptop, "MAD-001", -1, "sale")
+	if err != nil || stock.Quantity != 9 {
+		t.Fatalf("Expected the online adjustment applied (9), got %+v (%v)", stock, err)
+	}
+
+	// Se pierde la respuesta: la API aplicó el ajuste pero el TPV lo encola
+	mode.Store(edgeLostResponse)
+	if _, _, err := pos.AdjustStock(ctx, laptop, "MAD-001", -2, "sale"); !errors.Is(err, edge.ErrQueued) {
+		t.Fatalf("Expected ErrQueued when the response is lost, got %v", err)
+	}
+
+	// Sin conexión todo se encola, en orden
+	mode.Store(edgeOffline)
+	if _, _, err := pos.AdjustStock(ctx, laptop, "MAD-001", -3, "sale"); !errors.Is(err, edge.ErrQueued) {
+		t.Fatalf("Expected ErrQueued offline, got %v", err)
+	}
+	if _, err := pos.CreateReservation(ctx, edge.ReservationRequest{ProductID: laptop, CustomerID: "customer-1", Quantity: 1}); !errors.Is(err, edge.ErrQueued) {
+		t.Fatalf("Expected ErrQueued offline, got %v", err)
+	}
+	if err := pos.CancelReservation(ctx, "missing-reservation"); !errors.Is(err, edge.ErrQueued) {
+		t.Fatalf("Expected ErrQueued offline, got %v", err)
+	}
+	if result, err := pos.Flush(ctx); err == nil || result.Sent != 0 {
+		t.Errorf("Expected the flush to stop while offline, got %+v (%v)", result, err)
+	}
+	if pending, _ := queue.Len(ctx); pending != 4 {
+		t.Fatalf("Expected 4 queued mutations, got %d", pending)
+	}
+
+	// Vuelve la conexión: el ajuste que ya se aplicó se repite sin descontar dos veces
+	mode.Store(edgeOnline)
+	result, err := pos.Flush(ctx)
+	if err != nil || result.Sent != 3 || result.Failed != 1 {
+		t.Fatalf("Expected 3 sent and 1 rejected, got %+v (%v)", result, err)
+	}
+	if got := quantity(); got != 4 {
+		t.Errorf("Expected 4 units (10 - 1 - 2 - 3), got %d", got)
+	}
+	stockRow, _ := stockRepo.GetByProductAndStore(ctx, laptop, "MAD-001")
+	if stockRow.Reserved != 1 {
+		t.Errorf("Expected the queued reservation created, got %d reserved", stockRow.Reserved)
+	}
+
+	failed, _ := queue.Failed(ctx, 10)
+	if len(failed) != 1 || failed[0].Path != "/reservations/missing-reservation/cancel" || failed[0].LastError == "" {
+		t.Errorf("Expected the rejected cancellation kept for review, got %+v", failed)
+	}
+
+	// Con la cola vacía las escrituras vuelven a enviarse directamente
+	if _, _, err := pos.AdjustStock(ctx, laptop, "MAD-001", 5, "found"); err != nil {
+		t.Errorf("Expected the adjustment applied online, got %v", err)
+	}
+}
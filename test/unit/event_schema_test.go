@@ -0,0 +1,106 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+)
+
+func TestEventSchemas_DecodeConstructedEvents(t *testing.T) {
+	confirmedAt := time.Now()
+	reservation := &domain.Reservation{
+		ID:          "res-1",
+		ProductID:   "prod-1",
+		StoreID:     "MAD-001",
+		CustomerID:  "customer-1",
+		Quantity:    2,
+		CreatedAt:   confirmedAt.Add(-time.Minute),
+		ConfirmedAt: &confirmedAt,
+	}
+	product := &domain.Product{ID: "prod-1", SKU: "SKU-1", Price: 10, Status: domain.ProductStatusDiscontinued}
+	transfer := &domain.StockTransfer{
+		ID:          "tr-1",
+		ProductID:   "prod-1",
+		FromStoreID: "MAD-001",
+		ToStoreID:   "BCN-001",
+		Quantity:    3,
+		Status:      domain.TransferStatusDraft,
+	}
+	rundown := &domain.ProductRunDown{ProductID: "prod-1", Reason: "end of season"}
+	entry := domain.RunDownStoreEntry{StoreID: "MAD-001", InitialQuantity: 5, Quantity: 5, Status: domain.RunDownEntryRunningDown}
+
+	events := []*domain.Event{
+		domain.NewStockUpdatedEvent("prod-1", "MAD-001", 1, 2),
+		domain.NewStockCreatedEvent("prod-1", "MAD-001", 10),
+		domain.NewStockTransferredEvent("prod-1", "MAD-001", "BCN-001", 3),
+		domain.NewReservationCreatedEvent("res-1", "prod-1", "MAD-001", 2),
+		domain.NewReservationConfirmedEvent(reservation, product, nil, "TICKET-1"),
+		domain.NewReservationCancelledEvent("res-1", "prod-1", "MAD-001", 2),
+		domain.NewReservationExpiredEvent("res-1", "prod-1", "MAD-001", 2),
+		domain.NewTransferEvent(transfer),
+		domain.NewProductRunDownEvent(domain.EventProductDiscontinued, rundown, entry),
+		domain.NewProductStatusChangedEvent(product, domain.ProductStatusActive, "recall"),
+	}
+
+	for _, event := range events {
+		payload, version, err := domain.EventSchemas.DecodeEvent(event)
+		if err != nil {
+			t.Errorf("%s: expected payload to decode, got %v", event.EventType, err)
+			continue
+		}
+		latest, _ := domain.EventSchemas.Latest(event.EventType)
+		if version != latest {
+			t.Errorf("%s: expected latest version %d, got %d", event.EventType, latest, version)
+		}
+		if payload == nil {
+			t.Errorf("%s: expected typed payload", event.EventType)
+		}
+	}
+
+	confirmed, err := domain.DecodeEventPayloadAs[*domain.ReservationConfirmedPayload](events[4])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if confirmed.ReferenceID != "TICKET-1" || confirmed.TotalPrice != 20 {
+		t.Errorf("Expected reference TICKET-1 and total 20, got %+v", confirmed)
+	}
+}
+
+func TestEventSchemas_Versioning(t *testing.T) {
+	t.Run("LegacyPayloadDecodesAsV1", func(t *testing.T) {
+		event := &domain.Event{
+			EventType: domain.EventReservationConfirmed,
+			Payload:   `{"reservation_id":"res-1","product_id":"prod-1","store_id":"MAD-001","quantity":1}`,
+		}
+
+		payload, version, err := domain.EventSchemas.DecodeEvent(event)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if version != 1 {
+			t.Errorf("Expected version 1, got %d", version)
+		}
+		if _, ok := payload.(*domain.ReservationEventPayload); !ok {
+			t.Errorf("Expected ReservationEventPayload, got %T", payload)
+		}
+
+		if _, err := domain.DecodeEventPayloadAs[*domain.ReservationConfirmedPayload](event); err == nil {
+			t.Error("Expected error decoding v1 payload as v2 type")
+		}
+	})
+
+	t.Run("UnknownVersion", func(t *testing.T) {
+		_, _, err := domain.EventSchemas.Decode(domain.EventStockUpdated, []byte(`{"schema_version":99,"product_id":"p","store_id":"s"}`))
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("MissingRequiredField", func(t *testing.T) {
+		_, _, err := domain.EventSchemas.Decode(domain.EventStockUpdated, []byte(`{"schema_version":1,"store_id":"s"}`))
+		if verr, ok := err.(*domain.ValidationError); !ok || verr.Field != "product_id" {
+			t.Errorf("Expected product_id ValidationError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestExports(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewMockPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	exportRepo := repository.NewExportRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	exportService := service.NewExportService(exportRepo, infrastructure.NewLocalBlobStore(t.TempDir(), ""), time.Hour)
+
+	ctx := domain.WithActor(context.Background(), "reporting")
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	for _, storeID := range []string{"MAD-001", "BCN-001"} {
+		if _, err := stockService.InitializeStock(ctx, product.ID, storeID, 10); err != nil {
+			t.Fatalf("Error initializing stock: %v", err)
+		}
+	}
+
+	download := func(t *testing.T, id string) []byte {
+		t.Helper()
+		_, content, err := exportService.OpenExport(ctx, id)
+		if err != nil {
+			t.Fatalf("Error opening export: %v", err)
+		}
+		defer content.Close()
+		data, err := io.ReadAll(content)
+		if err != nil {
+			t.Fatalf("Error reading export: %v", err)
+		}
+		return data
+	}
+
+	t.Run("StockCSV", func(t *testing.T) {
+		job, err := exportService.RequestExport(ctx, &domain.ExportJob{
+			Type:    "stock",
+			Filters: domain.ExportFilters{StoreID: "MAD-001", ProductID: product.ID},
+		})
+		if err != nil {
+			t.Fatalf("Error requesting export: %v", err)
+		}
+		if job.Status != domain.JobStatusPending || job.Format != domain.ExportFormatCSV || job.Actor != "reporting" {
+			t.Fatalf("Expected pending CSV export requested by reporting, got %+v", job)
+		}
+
+		// No se puede descargar hasta que el worker la genere
+		var invalidState *domain.InvalidStateError
+		if _, _, err := exportService.OpenExport(ctx, job.ID); !errors.As(err, &invalidState) {
+			t.Errorf("Expected InvalidStateError before completion, got %v", err)
+		}
+
+		completed, err := exportService.ProcessPendingExports(ctx)
+		if err != nil || completed != 1 {
+			t.Fatalf("Expected 1 completed export, got %d (%v)", completed, err)
+		}
+
+		job, _ = exportService.GetExport(ctx, job.ID)
+		if job.Status != domain.JobStatusCompleted || job.Rows != 1 || job.ExpiresAt == nil {
+			t.Fatalf("Expected completed export with 1 row, got %+v", job)
+		}
+
+		records, err := csv.NewReader(bytes.NewReader(download(t, job.ID))).ReadAll()
+		if err != nil {
+			t.Fatalf("Error parsing CSV: %v", err)
+		}
+		if len(records) != 2 || records[0][0] != "product_id" || records[1][2] != "MAD-001" || records[1][6] != "10" {
+			t.Errorf("Unexpected CSV content: %v", records)
+		}
+	})
+
+	t.Run("MovementsJSON", func(t *testing.T) {
+		job, err := exportService.RequestExport(ctx, &domain.ExportJob{
+			Type:    domain.ExportMovements,
+			Format:  "json",
+			Filters: domain.ExportFilters{ProductID: product.ID},
+		})
+		if err != nil {
+			t.Fatalf("Error requesting export: %v", err)
+		}
+		if _, err := exportService.ProcessPendingExports(ctx); err != nil {
+			t.Fatalf("Error processing exports: %v", err)
+		}
+
+		var rows []map[string]interface{}
+		if err := json.Unmarshal(download(t, job.ID), &rows); err != nil {
+			t.Fatalf("Error parsing JSON: %v", err)
+		}
+		if len(rows) != 2 || rows[0]["event_type"] != string(domain.EventStockCreated) {
+			t.Fatalf("Expected 2 stock.created movements, got %v", rows)
+		}
+		if _, ok := rows[0]["payload"].(map[string]interface{}); !ok {
+			t.Errorf("Expected payload embedded as JSON object, got %T", rows[0]["payload"])
+		}
+	})
+
+	t.Run("InvalidFilters", func(t *testing.T) {
+		from := time.Now()
+		var validation *domain.ValidationError
+		_, err := exportService.RequestExport(ctx, &domain.ExportJob{
+			Type:    domain.ExportStock,
+			Filters: domain.ExportFilters{From: &from},
+		})
+		if !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for from on STOCK export, got %v", err)
+		}
+		if _, err := exportService.RequestExport(ctx, &domain.ExportJob{Type: "SALES"}); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for unknown type, got %v", err)
+		}
+	})
+
+	t.Run("PurgeExpired", func(t *testing.T) {
+		job, err := exportService.RequestExport(ctx, &domain.ExportJob{Type: domain.ExportReservations})
+		if err != nil {
+			t.Fatalf("Error requesting export: %v", err)
+		}
+		if _, err := exportService.ProcessPendingExports(ctx); err != nil {
+			t.Fatalf("Error processing exports: %v", err)
+		}
+
+		// Forzar el fin de la retención
+		if _, err := db.Exec(`UPDATE export_jobs SET expires_at = ? WHERE id = ?`, time.Now().Add(-time.Minute), job.ID); err != nil {
+			t.Fatalf("Error expiring export: %v", err)
+		}
+
+		purged, err := exportService.PurgeExpiredExports(ctx)
+		if err != nil || purged != 1 {
+			t.Fatalf("Expected 1 purged export, got %d (%v)", purged, err)
+		}
+		var notFound *domain.NotFoundError
+		if _, err := exportService.GetExport(ctx, job.ID); !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError after purge, got %v", err)
+		}
+	})
+}
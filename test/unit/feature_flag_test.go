@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestFeatureFlagService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	flagService := service.NewFeatureFlagService(repository.NewFeatureFlagRepository(db), repository.NewStoreRepository(db), []*domain.FeatureFlagRule{
+		{Feature: domain.FeatureTransferReservations, Enabled: false, Source: domain.FeatureFlagSourceConfig},
+		{Feature: domain.FeatureTransferReservations, StoreID: "MAD-001", Enabled: true, Source: domain.FeatureFlagSourceConfig},
+	})
+	ctx := context.Background()
+
+	assertEnabled := func(t *testing.T, feature domain.Feature, storeID string, expected bool) {
+		t.Helper()
+		enabled, err := flagService.IsEnabled(ctx, feature, storeID)
+		if err != nil {
+			t.Fatalf("Error evaluating flag: %v", err)
+		}
+		if enabled != expected {
+			t.Errorf("Expected %s for %q enabled=%v, got %v", feature, storeID, expected, enabled)
+		}
+	}
+
+	t.Run("ConfigRules", func(t *testing.T) {
+		assertEnabled(t, domain.FeatureChannelAllocation, "BCN-001", true)     // Sin regla: activada
+		assertEnabled(t, domain.FeatureFractionalQuantities, "BCN-001", false) // Desactivada por defecto
+		assertEnabled(t, domain.FeatureTransferReservations, "BCN-001", false)
+		assertEnabled(t, domain.FeatureTransferReservations, "MAD-001", true)
+	})
+
+	t.Run("AdminRulesOverrideConfig", func(t *testing.T) {
+		if _, err := flagService.SetFlag(ctx, &domain.FeatureFlagRule{Feature: domain.FeatureTransferReservations, StoreID: "MAD-001", Enabled: false}); err != nil {
+			t.Fatalf("Error setting flag: %v", err)
+		}
+		if _, err := flagService.SetFlag(ctx, &domain.FeatureFlagRule{Feature: domain.FeatureTransferReservations, Enabled: true}); err != nil {
+			t.Fatalf("Error setting flag: %v", err)
+		}
+
+		assertEnabled(t, domain.FeatureTransferReservations, "MAD-001", false)
+		assertEnabled(t, domain.FeatureTransferReservations, "BCN-001", true)
+
+		flags, err := flagService.ListFlags(ctx)
+		if err != nil {
+			t.Fatalf("Error listing flags: %v", err)
+		}
+		var transfer *domain.FeatureFlag
+		for _, flag := range flags {
+			if flag.Feature == domain.FeatureTransferReservations {
+				transfer = flag
+			}
+		}
+		if transfer == nil || !transfer.Enabled || transfer.Source != domain.FeatureFlagSourceAdmin ||
+			len(transfer.Stores) != 1 || transfer.Stores[0].Enabled || transfer.Stores[0].Source != domain.FeatureFlagSourceAdmin {
+			t.Errorf("Unexpected effective flag: %+v", transfer)
+		}
+
+		// Al borrar la regla de admin vuelve a aplicarse la de configuración
+		if err := flagService.DeleteFlag(ctx, domain.FeatureTransferReservations, "MAD-001"); err != nil {
+			t.Fatalf("Error deleting flag: %v", err)
+		}
+		assertEnabled(t, domain.FeatureTransferReservations, "MAD-001", true)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		var validation *domain.ValidationError
+		if _, err := flagService.SetFlag(ctx, &domain.FeatureFlagRule{Feature: "backorders", Enabled: true}); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for unknown feature, got %v", err)
+		}
+
+		var notFound *domain.NotFoundError
+		if _, err := flagService.SetFlag(ctx, &domain.FeatureFlagRule{Feature: domain.FeatureChannelAllocation, StoreID: "XXX-999", Enabled: false}); !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError for unknown store, got %v", err)
+		}
+		if err := flagService.DeleteFlag(ctx, domain.FeatureChannelAllocation, "BCN-001"); !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError deleting a missing rule, got %v", err)
+		}
+	})
+
+	t.Run("GatesChannelAllocations", func(t *testing.T) {
+		publisher := mocks.NewMockPublisher()
+		stockRepo := repository.NewStockRepository(db)
+		productRepo := repository.NewProductRepository(db)
+		stockService := service.NewStockService(stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+		channelService := service.NewChannelAllocationService(repository.NewChannelAllocationRepository(db))
+		channelService.SetFeatureFlags(flagService)
+
+		product := testutil.CreateTestProduct()
+		if err := productRepo.Create(ctx, product); err != nil {
+			t.Fatalf("Error creating product: %v", err)
+		}
+		for _, storeID := range []string{"MAD-001", "BCN-001"} {
+			if _, err := stockService.InitializeStock(ctx, product.ID, storeID, 10); err != nil {
+				t.Fatalf("Error initializing stock: %v", err)
+			}
+		}
+
+		if _, err := flagService.SetFlag(ctx, &domain.FeatureFlagRule{Feature: domain.FeatureChannelAllocation, StoreID: "BCN-001", Enabled: false}); err != nil {
+			t.Fatalf("Error setting flag: %v", err)
+		}
+
+		var disabled *domain.FeatureDisabledError
+		if err := channelService.SetAllocation(ctx, product.ID, "BCN-001", "WEB", 5); !errors.As(err, &disabled) || disabled.StoreID != "BCN-001" {
+			t.Errorf("Expected FeatureDisabledError for BCN-001, got %v", err)
+		}
+		if err := channelService.SetAllocation(ctx, product.ID, "MAD-001", "WEB", 5); err != nil {
+			t.Errorf("Expected allocation allowed in MAD-001, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestFlashSaleService_QueuedReservations(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationService := service.NewReservationService(
+		repository.NewReservationRepository(db),
+		stockRepo,
+		productRepo,
+		repository.NewEventRepository(db),
+		mocks.NewNoOpPublisher(),
+	)
+	flashSale := service.NewFlashSaleService(repository.NewFlashSaleRepository(db), productRepo, reservationService, service.FlashSaleConfig{
+		QueueSize: 100,
+		TicketTTL: time.Minute,
+	})
+
+	ctx := domain.WithActor(context.Background(), "drops-team")
+	productID := "550e8400-e29b-41d4-a716-446655440001" // MAD-001: 50 unidades, 5 reservadas
+
+	if enabled, _ := flashSale.IsEnabled(ctx, productID); enabled {
+		t.Fatal("Expected flash sale mode to be disabled by default")
+	}
+	product, err := flashSale.EnableProduct(ctx, productID)
+	if err != nil || product.EnabledBy != "drops-team" {
+		t.Fatalf("Expected product enabled by drops-team, got %+v (%v)", product, err)
+	}
+	if enabled, _ := flashSale.IsEnabled(ctx, productID); !enabled {
+		t.Fatal("Expected flash sale mode to be enabled")
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		tokens []string
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticket, err := flashSale.Enqueue(ctx, productID, "MAD-001", testutil.GenerateID(), 5, 15)
+			if err != nil {
+				t.Errorf("Expected ticket, got %v", err)
+				return
+			}
+			mu.Lock()
+			tokens = append(tokens, ticket.Token)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Close espera a que el writer vacíe la cola
+	flashSale.Close()
+
+	completed, failed := 0, 0
+	for _, token := range tokens {
+		ticket, err := flashSale.GetTicket(token)
+		if err != nil {
+			t.Fatalf("Expected ticket %s, got %v", token, err)
+		}
+		switch ticket.Status {
+		case domain.ReservationTicketCompleted:
+			completed++
+			if ticket.ReservationID == "" {
+				t.Errorf("Expected reservation ID in completed ticket %s", token)
+			}
+		case domain.ReservationTicketFailed:
+			failed++
+			if ticket.ErrorCode != "INSUFFICIENT_STOCK" {
+				t.Errorf("Expected INSUFFICIENT_STOCK, got %s (%s)", ticket.ErrorCode, ticket.Error)
+			}
+		default:
+			t.Errorf("Expected resolved ticket after Close, got %s", ticket.Status)
+		}
+	}
+	if completed != 9 || failed != 11 {
+		t.Errorf("Expected 9 completed and 11 failed tickets (45 available / 5), got %d and %d", completed, failed)
+	}
+
+	stock, _ := stockRepo.GetByProductAndStore(ctx, productID, "MAD-001")
+	if stock.Reserved != 50 {
+		t.Errorf("Expected all 50 units reserved, got %d", stock.Reserved)
+	}
+
+	if _, err := flashSale.Enqueue(ctx, productID, "MAD-001", "late-customer", 1, 15); err == nil {
+		t.Error("Expected Enqueue to fail after Close")
+	}
+	if purged := flashSale.PurgeExpiredTickets(time.Now().Add(2 * time.Minute)); purged != 20 {
+		t.Errorf("Expected 20 purged tickets, got %d", purged)
+	}
+
+	if err := flashSale.DisableProduct(ctx, productID); err != nil {
+		t.Fatalf("Expected no error disabling, got %v", err)
+	}
+	if enabled, _ := flashSale.IsEnabled(ctx, productID); enabled {
+		t.Error("Expected flash sale mode to be disabled")
+	}
+}
@@ -0,0 +1,145 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestFulfillmentPolicy_Validate(t *testing.T) {
+	var validationErr *domain.ValidationError
+	for name, policy := range map[string]*domain.FulfillmentPolicy{
+		"no channel":        {Ship: []domain.LocationType{domain.LocationTypeWarehouse}},
+		"no location types": {Channel: domain.SalesChannelWeb},
+		"unknown type":      {Channel: domain.SalesChannelWeb, Ship: []domain.LocationType{"LOCKER"}},
+		"duplicated type":   {Channel: domain.SalesChannelWeb, Pickup: []domain.LocationType{"STORE", "store"}},
+	} {
+		if err := policy.Validate(); !errors.As(err, &validationErr) {
+			t.Errorf("%s: expected ValidationError, got %v", name, err)
+		}
+	}
+
+	policy := &domain.FulfillmentPolicy{Channel: domain.SalesChannelWeb, Ship: []domain.LocationType{" warehouse"}}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Expected valid policy, got %v", err)
+	}
+	if policy.Ship[0] != domain.LocationTypeWarehouse || len(policy.Pickup) != 0 {
+		t.Errorf("Expected normalized ship types and no pickup, got %+v", policy)
+	}
+}
+
+func TestFulfillment_AvailabilityAndAutoReservation(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	storeRepo := repository.NewStoreRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+	fulfillmentService := service.NewFulfillmentService(repository.NewFulfillmentPolicyRepository(db), storeRepo)
+	stockService := service.NewStockService(stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+	stockService.SetFulfillmentService(fulfillmentService)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+	autoReservationService := service.NewAutoReservationService(reservationService, stockRepo)
+	autoReservationService.SetFulfillmentService(fulfillmentService)
+	ctx := context.Background()
+
+	// Vendible del laptop: MAD-001 10, BCN-001 13, VAL-001 4, SEV-001 17
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+	if err := storeRepo.UpdateLocationType(ctx, "VAL-001", domain.LocationTypeWarehouse); err != nil {
+		t.Fatalf("Failed to update location type: %v", err)
+	}
+	if err := storeRepo.UpdateLocationType(ctx, "BCN-001", domain.LocationTypeDarkstore); err != nil {
+		t.Fatalf("Failed to update location type: %v", err)
+	}
+
+	storeIDs := func(availability *domain.ProductAvailability) []string {
+		ids := make([]string, 0, len(availability.Stores))
+		for _, store := range availability.Stores {
+			ids = append(ids, store.StoreID)
+		}
+		return ids
+	}
+
+	t.Run("AvailabilityByFulfillment", func(t *testing.T) {
+		ship, err := stockService.GetProductAvailability(ctx, laptop, "", true, domain.FulfillmentShip)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if ids := storeIDs(ship); len(ids) != 4 || ids[0] != "VAL-001" || ids[1] != "BCN-001" {
+			t.Errorf("Expected the warehouse and then the dark store first for shipping, got %v", ids)
+		}
+		if ship.Stores[0].LocationType != domain.LocationTypeWarehouse {
+			t.Errorf("Expected location_type WAREHOUSE, got %q", ship.Stores[0].LocationType)
+		}
+
+		pickup, err := stockService.GetProductAvailability(ctx, laptop, "", true, domain.FulfillmentPickup)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		ids := storeIDs(pickup)
+		if len(ids) != 3 || ids[2] != "BCN-001" {
+			t.Errorf("Expected stores and then the dark store for pickup (no warehouse), got %v", ids)
+		}
+
+		all, err := stockService.GetProductAvailability(ctx, laptop, "", true, "")
+		if err != nil || len(all.Stores) != 4 || all.Stores[0].LocationType != "" {
+			t.Errorf("Expected every store without location types when no fulfillment is given, got %+v (%v)", all, err)
+		}
+	})
+
+	t.Run("AutoReservationPrefersWarehouseForShipping", func(t *testing.T) {
+		result, err := autoReservationService.CreateAutoReservation(ctx, laptop, nil, "customer-ship", 3, 30, "", false, domain.FulfillmentShip)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Reservations[0].StoreID != "VAL-001" {
+			t.Errorf("Expected the reservation in the warehouse, got %s", result.Reservations[0].StoreID)
+		}
+	})
+
+	t.Run("AutoReservationPickupSkipsWarehouses", func(t *testing.T) {
+		result, err := autoReservationService.CreateAutoReservation(ctx, laptop, []string{"VAL-001", "MAD-001"}, "customer-pickup", 1, 30, "", false, domain.FulfillmentPickup)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Reservations[0].StoreID != "MAD-001" {
+			t.Errorf("Expected the reservation in MAD-001, got %s", result.Reservations[0].StoreID)
+		}
+	})
+
+	t.Run("ChannelPolicy", func(t *testing.T) {
+		if _, err := fulfillmentService.SetPolicy(ctx, &domain.FulfillmentPolicy{
+			Channel: domain.SalesChannelWeb,
+			Ship:    []domain.LocationType{domain.LocationTypeStore},
+		}); err != nil {
+			t.Fatalf("Failed to save policy: %v", err)
+		}
+
+		web := domain.WithSalesChannel(ctx, domain.SalesChannelWeb)
+		result, err := autoReservationService.CreateAutoReservation(web, laptop, nil, "customer-web", 3, 30, "", false, domain.FulfillmentShip)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Reservations[0].StoreID != "SEV-001" {
+			t.Errorf("Expected the store with the most availability (SEV-001), got %s", result.Reservations[0].StoreID)
+		}
+		if _, err := autoReservationService.CreateAutoReservation(web, laptop, nil, "customer-web", 1, 30, "", false, domain.FulfillmentPickup); !errors.Is(err, domain.ErrInsufficientStock) {
+			t.Errorf("Expected insufficient stock when the channel has no pickup, got %v", err)
+		}
+
+		policies, err := fulfillmentService.ListPolicies(ctx)
+		if err != nil || len(policies) != 3 {
+			t.Fatalf("Expected a policy per channel, got %d (%v)", len(policies), err)
+		}
+		if policies[0].Channel != domain.SalesChannelWeb || policies[0].Default || !policies[1].Default {
+			t.Errorf("Expected only WEB with its own policy, got %+v / %+v", policies[0], policies[1])
+		}
+	})
+}
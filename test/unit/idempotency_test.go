@@ -0,0 +1,113 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/domain"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	silenceLogs(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	idempotencyService := service.NewIdempotencyService(repository.NewIdempotencyRepository(db))
+
+	calls := 0
+	router := gin.New()
+	router.Use(middleware.Idempotency(idempotencyService))
+	router.POST("/api/v1/things", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+	router.POST("/api/v1/flaky", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Service Unavailable"})
+	})
+
+	send := func(apiKey, key, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set("X-API-Key", apiKey)
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(domain.IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("key-madrid", "sale-1", "/api/v1/things", `{"quantity":1}`)
+	if first.Code != http.StatusCreated || calls != 1 {
+		t.Fatalf("Expected the first request processed, got %d (%d calls)", first.Code, calls)
+	}
+
+	// El reintento con la misma clave repite la respuesta sin volver a procesarse
+	replay := send("key-madrid", "sale-1", "/api/v1/things", `{"quantity":1}`)
+	if replay.Code != http.StatusCreated || replay.Body.String() != first.Body.String() || calls != 1 {
+		t.Errorf("Expected the original response replayed, got %d %s (%d calls)", replay.Code, replay.Body.String(), calls)
+	}
+	if replay.Header().Get(domain.IdempotencyReplayedHeader) != "true" {
+		t.Errorf("Expected %s header on the replay", domain.IdempotencyReplayedHeader)
+	}
+
+	// La misma clave con otra petición se rechaza
+	if w := send("key-madrid", "sale-1", "/api/v1/things", `{"quantity":2}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 reusing the key for another request, got %d", w.Code)
+	}
+
+	// Las claves son por API key; sin clave no hay deduplicación
+	if w := send("key-barcelona", "sale-1", "/api/v1/things", `{"quantity":1}`); w.Code != http.StatusCreated || calls != 2 {
+		t.Errorf("Expected another API key's request processed, got %d (%d calls)", w.Code, calls)
+	}
+	send("key-madrid", "", "/api/v1/things", `{"quantity":1}`)
+	send("key-madrid", "", "/api/v1/things", `{"quantity":1}`)
+	if calls != 4 {
+		t.Errorf("Expected requests without key processed every time, got %d calls", calls)
+	}
+
+	// Los errores del servidor no se guardan: el reintento se procesa de nuevo
+	send("key-madrid", "flaky-1", "/api/v1/flaky", `{}`)
+	send("key-madrid", "flaky-1", "/api/v1/flaky", `{}`)
+	if calls != 6 {
+		t.Errorf("Expected 5xx responses not stored, got %d calls", calls)
+	}
+
+	// Con la petición original aún en curso se pide reintentar
+	pending := &domain.IdempotencyRecord{
+		Scope:       auth.HashKey("key-madrid"),
+		Key:         "sale-2",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/things",
+		RequestHash: domain.HashIdempotentRequest(http.MethodPost, "/api/v1/things", []byte(`{}`)),
+		CreatedAt:   time.Now(),
+	}
+	if _, err := idempotencyService.Begin(context.Background(), pending); err != nil {
+		t.Fatalf("Failed to register pending key: %v", err)
+	}
+	if w := send("key-madrid", "sale-2", "/api/v1/things", `{}`); w.Code != http.StatusConflict || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 409 with Retry-After while in progress, got %d", w.Code)
+	}
+
+	// Las claves caducadas se purgan
+	if _, err := db.Exec(`UPDATE idempotency_keys SET created_at = ?`, time.Now().Add(-domain.IdempotencyKeyTTL-time.Hour)); err != nil {
+		t.Fatalf("Failed to age keys: %v", err)
+	}
+	if purged, err := idempotencyService.PurgeExpiredKeys(context.Background()); err != nil || purged != 3 {
+		t.Errorf("Expected 3 purged keys, got %d (%v)", purged, err)
+	}
+}
@@ -0,0 +1,127 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+)
+
+func TestIdempotentConsumer_AppliesEachEventOnce(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	repo := repository.NewProcessedEventRepository(db)
+	consumer := service.NewIdempotentConsumer(repo, "cross-store-sync")
+	ctx := context.Background()
+	event := domain.NewStockUpdatedEvent("prod-1", "MAD-001", 10, 8)
+
+	applied := 0
+	handler := func(ctx context.Context, e *domain.Event) error {
+		applied++
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		ok, err := consumer.Handle(ctx, event, handler)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if ok != (i == 0) {
+			t.Errorf("Delivery %d: expected applied=%t, got %t", i+1, i == 0, ok)
+		}
+	}
+	if applied != 1 {
+		t.Errorf("Expected handler to run once, ran %d times", applied)
+	}
+
+	record, err := repo.Get(ctx, "cross-store-sync", event.ID)
+	if err != nil || record.Status != domain.ProcessedEventDone || record.ProcessedAt == nil {
+		t.Errorf("Expected DONE record, got %+v (%v)", record, err)
+	}
+
+	// Otro consumidor aplica el mismo evento de forma independiente
+	other := service.NewIdempotentConsumer(repo, "search-indexer")
+	if ok, _ := other.Handle(ctx, event, handler); !ok || applied != 2 {
+		t.Errorf("Expected second consumer to apply the event, applied=%d", applied)
+	}
+}
+
+func TestIdempotentConsumer_RetriesAfterFailure(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	repo := repository.NewProcessedEventRepository(db)
+	consumer := service.NewIdempotentConsumer(repo, "cross-store-sync")
+	ctx := context.Background()
+	event := domain.NewStockUpdatedEvent("prod-1", "MAD-001", 10, 8)
+
+	ok, err := consumer.Handle(ctx, event, func(ctx context.Context, e *domain.Event) error {
+		return errors.New("db locked")
+	})
+	if ok || err == nil {
+		t.Fatalf("Expected handler error, got applied=%t err=%v", ok, err)
+	}
+
+	ok, err = consumer.Handle(ctx, event, func(ctx context.Context, e *domain.Event) error { return nil })
+	if !ok || err != nil {
+		t.Errorf("Expected redelivery to be applied after failure, got applied=%t err=%v", ok, err)
+	}
+}
+
+func TestIdempotentConsumer_ReclaimsAbandonedClaim(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	repo := repository.NewProcessedEventRepository(db)
+	ctx := context.Background()
+	event := domain.NewStockUpdatedEvent("prod-1", "MAD-001", 10, 8)
+
+	// Una instancia reclamó el evento y murió antes de terminar
+	if claimed, err := repo.Claim(ctx, "cross-store-sync", event, time.Minute); !claimed || err != nil {
+		t.Fatalf("Expected first claim to succeed, got %t (%v)", claimed, err)
+	}
+
+	consumer := service.NewIdempotentConsumer(repo, "cross-store-sync")
+	noop := func(ctx context.Context, e *domain.Event) error { return nil }
+
+	if ok, _ := consumer.Handle(ctx, event, noop); ok {
+		t.Error("Expected in-flight claim to block redelivery within the lease")
+	}
+
+	consumer.SetClaimLease(time.Nanosecond)
+	if ok, err := consumer.Handle(ctx, event, noop); !ok || err != nil {
+		t.Errorf("Expected abandoned claim to be retaken, got applied=%t err=%v", ok, err)
+	}
+
+	deleted, err := repo.DeleteProcessedBefore(ctx, time.Now().Add(time.Second))
+	if err != nil || deleted != 1 {
+		t.Errorf("Expected 1 processed record purged, got %d (%v)", deleted, err)
+	}
+}
+
+func TestDecodeRedisStreamMessage(t *testing.T) {
+	event := domain.NewReservationCreatedEvent("res-1", "prod-1", "MAD-001", 2)
+	payload, _ := json.Marshal(event)
+
+	decoded, err := infrastructure.DecodeRedisStreamMessage(redis.XMessage{
+		ID:     "1700000000000-0",
+		Values: map[string]interface{}{"id": event.ID, "payload": string(payload)},
+	})
+	if err != nil || decoded.ID != event.ID || decoded.EventType != event.EventType {
+		t.Errorf("Expected event %s, got %+v (%v)", event.ID, decoded, err)
+	}
+
+	if _, err := infrastructure.DecodeRedisStreamMessage(redis.XMessage{ID: "1-0", Values: map[string]interface{}{}}); err == nil {
+		t.Error("Expected error for message without payload")
+	}
+}
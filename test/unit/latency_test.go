@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTDigest_Quantiles(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	values := rng.Perm(10000)
+
+	// Dos mitades fusionadas deben estimar lo mismo que un único digest
+	first, second := domain.NewTDigest(0), domain.NewTDigest(0)
+	for i, v := range values {
+		if i%2 == 0 {
+			first.Add(float64(v + 1))
+		} else {
+			second.Add(float64(v + 1))
+		}
+	}
+	merged := domain.NewTDigest(0)
+	merged.Merge(first)
+	merged.Merge(second)
+
+	if merged.Count() != 10000 || merged.Max() != 10000 {
+		t.Fatalf("Expected 10000 values with max 10000, got count=%d max=%v", merged.Count(), merged.Max())
+	}
+	for _, tc := range []struct {
+		q, expected, tolerance float64
+	}{
+		{0.50, 5000, 100},
+		{0.95, 9500, 30},
+		{0.99, 9900, 10},
+	} {
+		if got := merged.Quantile(tc.q); math.Abs(got-tc.expected) > tc.tolerance {
+			t.Errorf("Quantile(%v): expected %v ± %v, got %v", tc.q, tc.expected, tc.tolerance, got)
+		}
+	}
+
+	if empty := domain.NewTDigest(0); empty.Quantile(0.99) != 0 {
+		t.Errorf("Expected 0 for an empty digest")
+	}
+}
+
+func TestLatencyService_RollingWindowAndBudget(t *testing.T) {
+	latency := service.NewLatencyService(time.Minute)
+	now := time.Now()
+
+	for i := 1; i <= 100; i++ {
+		status := http.StatusOK
+		if i == 100 {
+			status = http.StatusInternalServerError
+		}
+		latency.Record("POST /api/v1/reservations", status, time.Duration(i)*time.Millisecond, 90*time.Millisecond, now)
+	}
+	latency.Record("GET /health", http.StatusOK, time.Millisecond, 0, now)
+	// Fuera de la ventana: no cuenta
+	latency.Record("GET /api/v1/products", http.StatusOK, time.Second, 0, now.Add(-2*time.Minute))
+
+	report := latency.Report(now)
+	if report.Count != 2 || report.Routes[0].Route != "POST /api/v1/reservations" {
+		t.Fatalf("Expected 2 routes with reservations first (highest p99), got %+v", report.Routes)
+	}
+	reservations := report.Routes[0]
+	if reservations.Count != 100 || reservations.Errors != 1 || reservations.MaxMs != 100 {
+		t.Errorf("Expected 100 requests, 1 error and max 100ms, got %+v", reservations)
+	}
+	if math.Abs(reservations.P50Ms-50) > 2 || reservations.P99Ms < 97 {
+		t.Errorf("Expected p50 ≈ 50ms and p99 ≈ 99ms, got p50=%v p99=%v", reservations.P50Ms, reservations.P99Ms)
+	}
+	if reservations.BudgetMs != 90 || reservations.OverBudget != 10 || reservations.OverBudgetRatio != 0.1 {
+		t.Errorf("Expected 10 of 100 requests over the 90ms budget, got %+v", reservations)
+	}
+
+	if later := latency.Report(now.Add(2 * time.Minute)); later.Count != 0 {
+		t.Errorf("Expected the window to roll over, got %+v", later.Routes)
+	}
+}
+
+func TestLatencyBudgetMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	latency := service.NewLatencyService(time.Minute)
+
+	router := gin.New()
+	router.Use(middleware.LatencyBudget(latency, middleware.TimeoutPolicy{
+		Default:   500 * time.Millisecond,
+		Overrides: map[string]time.Duration{"GET /api/v1/products/:id": time.Nanosecond},
+	}))
+	router.GET("/api/v1/products/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/api/v1/products/1", "/api/v1/products/2", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	report := latency.Report(time.Now())
+	if report.Count != 1 {
+		t.Fatalf("Expected only the registered route to be measured, got %+v", report.Routes)
+	}
+	route := report.Routes[0]
+	if route.Route != "GET /api/v1/products/:id" || route.Count != 2 || route.OverBudget != 2 {
+		t.Errorf("Expected 2 requests over the route budget, got %+v", route)
+	}
+}
@@ -0,0 +1,122 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestLedgerVerification(t *testing.T) {
+	silenceLogs(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+	productService := service.NewProductService(productRepo, eventRepo)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, eventRepo, publisher)
+	ledgerService := service.NewLedgerVerificationService(repository.NewStockLedgerRepository(db))
+
+	ctx := context.Background()
+
+	product, err := productService.CreateProduct(ctx, &domain.Product{SKU: "LEDGER-001", Name: "Monitor", Category: "electronics", Price: 199.99})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Alta, ajuste, venta y transferencia: todos los movimientos quedan en el ledger
+	if _, err := stockService.InitializeStock(ctx, product.ID, "MAD-001", 50); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := stockService.InitializeStock(ctx, product.ID, "BCN-001", 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := stockService.UpdateStock(ctx, product.ID, "MAD-001", 60); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	reservation, err := reservationService.CreateReservation(ctx, product.ID, "MAD-001", "customer-ledger", 5, 30)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := reservationService.ConfirmReservation(ctx, reservation.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := stockService.TransferStock(ctx, product.ID, "MAD-001", "BCN-001", 10); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if ledgerService.Last() != nil {
+		t.Fatal("Expected no verification before the first run")
+	}
+
+	t.Run("Consistent", func(t *testing.T) {
+		result, err := ledgerService.Verify(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		// Las 20 filas de los datos de prueba no tienen eventos
+		if result.Checked != 22 || result.Verified != 2 || result.Unverified != 20 || len(result.Mismatches) != 0 {
+			t.Errorf("Expected 2 verified rows without mismatches, got %+v", result)
+		}
+	})
+
+	t.Run("SnapshotBaseline", func(t *testing.T) {
+		backfill := service.NewSnapshotBackfillService(repository.NewSnapshotBackfillRepository(db), stockRepo, eventRepo, publisher)
+		if _, err := backfill.BackfillStockSnapshots(ctx, 100); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		result, err := ledgerService.Verify(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Verified != 22 || result.Unverified != 0 || len(result.Mismatches) != 0 {
+			t.Errorf("Expected every row verified after the backfill, got %+v", result)
+		}
+	})
+
+	t.Run("DetectsSilentWrite", func(t *testing.T) {
+		if _, err := stockService.UpdateStock(ctx, product.ID, "MAD-001", 48); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		// Escritura fuera de la API: no registra movimiento
+		if _, err := db.Exec(`UPDATE stock SET quantity = 40 WHERE product_id = ? AND store_id = 'MAD-001'`, product.ID); err != nil {
+			t.Fatalf("Failed to update stock: %v", err)
+		}
+
+		result, err := ledgerService.Verify(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(result.Mismatches) != 1 {
+			t.Fatalf("Expected 1 mismatch, got %+v", result)
+		}
+		mismatch := result.Mismatches[0]
+		if mismatch.ProductID != product.ID || mismatch.Quantity != 40 || mismatch.Expected != 48 || mismatch.Difference != -8 {
+			t.Errorf("Expected 40 stored vs 48 replayed, got %+v", mismatch)
+		}
+		if mismatch.BaselineType != domain.EventStockSnapshot {
+			t.Errorf("Expected snapshot baseline, got %s", mismatch.BaselineType)
+		}
+
+		if last := ledgerService.Last(); last != result {
+			t.Error("Expected the last verification to be kept")
+		}
+		metrics := handler.FormatLedgerMetrics(result)
+		if !strings.Contains(metrics, "inventory_ledger_mismatched_rows 1\n") ||
+			!strings.Contains(metrics, `inventory_ledger_mismatch_difference{product_id="`+product.ID+`",store_id="MAD-001"} -8`) {
+			t.Errorf("Unexpected metrics:\n%s", metrics)
+		}
+	})
+}
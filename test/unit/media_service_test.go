@@ -0,0 +1,122 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+)
+
+// pngHeader firma PNG suficiente para los tests (el servicio no decodifica la imagen)
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func pngUpload(altText string, position *int) *domain.MediaUpload {
+	return &domain.MediaUpload{
+		Filename:    "front.png",
+		ContentType: "image/png",
+		Size:        int64(len(pngHeader)),
+		AltText:     altText,
+		Position:    position,
+		Content:     bytes.NewReader(pngHeader),
+	}
+}
+
+func TestMediaService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	dir := t.TempDir()
+	productRepo := repository.NewProductRepository(db)
+	mediaService := service.NewMediaService(repository.NewMediaRepository(db), productRepo,
+		infrastructure.NewLocalBlobStore(dir, "/media"), 1<<20)
+	productService := service.NewProductService(productRepo, repository.NewEventRepository(db))
+	productService.SetMediaService(mediaService)
+
+	ctx := context.Background()
+	product, err := productService.CreateProduct(ctx, testutil.CreateTestProduct())
+	if err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+
+	first, err := mediaService.Upload(ctx, product.ID, pngUpload("Vista frontal", nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("UploadStoresBlob", func(t *testing.T) {
+		if first.Position != 0 || first.URL != "/media/"+first.StorageKey {
+			t.Errorf("Unexpected media record: position=%d url=%s", first.Position, first.URL)
+		}
+		if _, err := os.Stat(filepath.Join(dir, first.StorageKey)); err != nil {
+			t.Errorf("Expected blob on disk: %v", err)
+		}
+	})
+
+	t.Run("PositionInsertsAndShifts", func(t *testing.T) {
+		zero := 0
+		second, err := mediaService.Upload(ctx, product.ID, pngUpload("Vista lateral", &zero))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		media, _ := mediaService.ListMedia(ctx, product.ID)
+		if len(media) != 2 || media[0].ID != second.ID || media[1].ID != first.ID || media[1].Position != 1 {
+			t.Fatalf("Expected new image first and the previous one shifted, got %+v", media)
+		}
+
+		one := 1
+		moved, err := mediaService.UpdateMedia(ctx, product.ID, second.ID, domain.ProductMediaPatch{Position: &one})
+		if err != nil || moved.Position != 1 {
+			t.Fatalf("Expected image moved to position 1, got %+v (%v)", moved, err)
+		}
+	})
+
+	t.Run("ProductResponsesIncludeMedia", func(t *testing.T) {
+		loaded, err := productService.GetProduct(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(loaded.Media) != 2 || loaded.Media[0].ID != first.ID {
+			t.Errorf("Expected 2 images with %s first, got %+v", first.ID, loaded.Media)
+		}
+	})
+
+	t.Run("RejectsInvalidUploads", func(t *testing.T) {
+		upload := pngUpload("", nil)
+		upload.ContentType = "application/pdf"
+		if _, err := mediaService.Upload(ctx, product.ID, upload); err == nil {
+			t.Error("Expected validation error for non-image content")
+		}
+
+		upload = pngUpload("", nil)
+		upload.Size = 2 << 20
+		if _, err := mediaService.Upload(ctx, product.ID, upload); err == nil {
+			t.Error("Expected validation error for oversized upload")
+		}
+
+		if _, err := mediaService.Upload(ctx, "missing-product", pngUpload("", nil)); err == nil {
+			t.Error("Expected not found error for unknown product")
+		}
+	})
+
+	t.Run("DeleteRemovesBlobAndCompacts", func(t *testing.T) {
+		if err := mediaService.DeleteMedia(ctx, product.ID, first.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, first.StorageKey)); !os.IsNotExist(err) {
+			t.Errorf("Expected blob removed, got %v", err)
+		}
+
+		media, _ := mediaService.ListMedia(ctx, product.ID)
+		if len(media) != 1 || media[0].Position != 0 {
+			t.Errorf("Expected remaining image at position 0, got %+v", media)
+		}
+	})
+}
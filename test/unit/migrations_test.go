@@ -0,0 +1,165 @@
+package unit
+
+import (
+	"database/sql"
+	"testing"
+
+	"inventory-system/internal/config"
+	"inventory-system/internal/database"
+)
+
+// legacySchemaSQL tablas tal como las creaban versiones anteriores, sin schema_version. La de
+// reservas ya tiene confirmed_at (una base de datos intermedia) para comprobar que el paso que
+// la añade es idempotente.
+const legacySchemaSQL = `
+CREATE TABLE products (
+    id TEXT PRIMARY KEY,
+    sku TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    category TEXT,
+    price REAL NOT NULL CHECK (price >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE stock (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    UNIQUE(product_id, store_id),
+    CHECK (reserved <= quantity)
+);
+CREATE TABLE reservations (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    customer_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'CONFIRMED', 'CANCELLED', 'EXPIRED')),
+    expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_reservations_customer ON reservations(customer_id);
+CREATE TABLE events (
+    id TEXT PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    aggregate_type TEXT NOT NULL,
+    store_id TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    synced INTEGER DEFAULT 0,
+    synced_at TIMESTAMP NULL
+);
+CREATE TABLE stores (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    address TEXT,
+    city TEXT,
+    country TEXT,
+    phone TEXT,
+    email TEXT,
+    active INTEGER DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO products (id, sku, name, price) VALUES ('prod-legacy', 'LEGACY-001', 'Legacy', 10);
+INSERT INTO stock (id, product_id, store_id, quantity, reserved) VALUES ('stock-legacy', 'prod-legacy', 'MAD-001', 10, 2);
+INSERT INTO reservations (id, product_id, store_id, customer_id, quantity, status, expires_at, updated_at)
+VALUES ('res-legacy', 'prod-legacy', 'MAD-001', 'cust-1', 2, 'CONFIRMED', '2025-01-01 10:15:00', '2025-01-01 10:05:00');
+INSERT INTO events (id, event_type, aggregate_id, aggregate_type, store_id, payload)
+VALUES ('evt-legacy', 'stock.created', 'prod-legacy', 'stock', 'MAD-001', '{}');
+`
+
+func TestInitializeSchema_MigratesLegacyDatabase(t *testing.T) {
+	silenceLogs(t)
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(legacySchemaSQL); err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+
+	if err := database.InitializeSchema(db, &config.Config{}); err != nil {
+		t.Fatalf("Failed to migrate legacy database: %v", err)
+	}
+
+	// Las columnas añadidas después del esquema base existen y las filas antiguas las leen
+	var (
+		status, abcClass, priority, channel string
+		confirmedAt                         sql.NullString
+		minStock, safetyStock, held         int
+	)
+	if err := db.QueryRow(`SELECT status, abc_class FROM products WHERE id = 'prod-legacy'`).Scan(&status, &abcClass); err != nil {
+		t.Fatalf("Expected products.status and abc_class: %v", err)
+	}
+	if status != "ACTIVE" || abcClass != "" {
+		t.Errorf("Expected defaults ACTIVE/'', got %q/%q", status, abcClass)
+	}
+	if err := db.QueryRow(`SELECT min_stock, safety_stock, held FROM stock WHERE id = 'stock-legacy'`).Scan(&minStock, &safetyStock, &held); err != nil {
+		t.Fatalf("Expected stock.min_stock, safety_stock and held: %v", err)
+	}
+	if err := db.QueryRow(`SELECT priority, channel, confirmed_at FROM reservations WHERE id = 'res-legacy'`).Scan(&priority, &channel, &confirmedAt); err != nil {
+		t.Fatalf("Expected reservation to survive the table rebuild: %v", err)
+	}
+	if priority != "NORMAL" || channel != "" {
+		t.Errorf("Expected defaults NORMAL/'', got %q/%q", priority, channel)
+	}
+	if !confirmedAt.Valid {
+		t.Error("Expected confirmed_at to be backfilled for confirmed reservations")
+	}
+	if _, err := db.Exec(`UPDATE events SET seq = 1, prev_hash = '', hash = 'h', correlation_id = 'c', actor = 'a' WHERE id = 'evt-legacy'`); err != nil {
+		t.Errorf("Expected events chain columns: %v", err)
+	}
+	if _, err := db.Exec(`SELECT rounding FROM product_units`); err != nil {
+		t.Errorf("Expected product_units.rounding: %v", err)
+	}
+	if _, err := db.Exec(`SELECT location_type FROM stores`); err != nil {
+		t.Errorf("Expected stores.location_type: %v", err)
+	}
+
+	// La reconstrucción de reservations cambia el CHECK de estado y conserva los índices
+	if _, err := db.Exec(`UPDATE reservations SET status = 'PICKED_UP' WHERE id = 'res-legacy'`); err != nil {
+		t.Errorf("Expected PICKED_UP to be accepted: %v", err)
+	}
+	var indexes int
+	db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index'
+		AND name IN ('idx_reservations_customer', 'idx_reservations_expires')`).Scan(&indexes)
+	if indexes != 2 {
+		t.Errorf("Expected reservation indexes to be kept, got %d", indexes)
+	}
+
+	// Sin los CHECK de quantity la sobreventa puede dejarla negativa
+	if _, err := db.Exec(`UPDATE stock SET quantity = -1 WHERE id = 'stock-legacy'`); err != nil {
+		t.Errorf("Expected negative quantity to be accepted by the table: %v", err)
+	}
+
+	var applied, latest int
+	db.QueryRow(`SELECT COUNT(*), MAX(version) FROM schema_version`).Scan(&applied, &latest)
+	if applied == 0 || applied != latest {
+		t.Fatalf("Expected every migration recorded once, got %d rows up to version %d", applied, latest)
+	}
+
+	// Una segunda ejecución no aplica nada
+	if err := database.InitializeSchema(db, &config.Config{}); err != nil {
+		t.Fatalf("Expected second run to succeed: %v", err)
+	}
+	var again int
+	db.QueryRow(`SELECT COUNT(*) FROM schema_version`).Scan(&again)
+	if again != applied {
+		t.Errorf("Expected no new migrations on second run, got %d (was %d)", again, applied)
+	}
+}
@@ -0,0 +1,49 @@
+package unit
+
+import (
+	"net/http"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+)
+
+func TestMultiStatusResponse(t *testing.T) {
+	var multi handler.MultiStatusResponse
+
+	multi.Succeed(0, http.StatusCreated, "created")
+	multi.Skip(1, "already-imported")
+	if multi.HTTPStatus() != http.StatusOK {
+		t.Errorf("Expected 200 without failures, got %d", multi.HTTPStatus())
+	}
+
+	multi.Fail(2, &domain.InsufficientStockError{ProductID: "p1", StoreID: "MAD-001", Available: 1, Requested: 5}, nil)
+	multi.Fail(3, &domain.NotFoundError{Resource: "Product", ID: "p9"}, nil)
+
+	if multi.HTTPStatus() != http.StatusMultiStatus {
+		t.Errorf("Expected 207 with failures, got %d", multi.HTTPStatus())
+	}
+	if multi.Total != 4 || multi.Succeeded != 1 || multi.Skipped != 1 || multi.Failed != 2 {
+		t.Errorf("Unexpected summary: %+v", multi)
+	}
+
+	expected := []struct {
+		status  int
+		outcome handler.MultiStatusOutcome
+		code    string
+	}{
+		{http.StatusCreated, handler.MultiStatusSucceeded, ""},
+		{http.StatusOK, handler.MultiStatusSkipped, ""},
+		{http.StatusConflict, handler.MultiStatusFailed, "INSUFFICIENT_STOCK"},
+		{http.StatusNotFound, handler.MultiStatusFailed, "NOT_FOUND"},
+	}
+	for i, want := range expected {
+		item := multi.Results[i]
+		if item.Index != i || item.Status != want.status || item.Outcome != want.outcome || item.Code != want.code {
+			t.Errorf("Item %d: expected %d %s %q, got %+v", i, want.status, want.outcome, want.code, item)
+		}
+	}
+	if multi.Results[2].Error == "" {
+		t.Error("Expected the error message on failed items")
+	}
+}
@@ -0,0 +1,165 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/infrastructure"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// startJetStream arranca un servidor NATS embebido con JetStream en un directorio temporal
+func startJetStream(t *testing.T, token string) *server.Server {
+	srv, err := server.NewServer(&server.Options{
+		Host:          "127.0.0.1",
+		Port:          -1, // Puerto libre
+		JetStream:     true,
+		StoreDir:      t.TempDir(),
+		Authorization: token,
+		NoLog:         true,
+		NoSigs:        true,
+	})
+	if err != nil {
+		t.Fatalf("Error creating NATS server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+// streamMessages lee los subjects y Nats-Msg-Id guardados en el stream
+func streamMessages(t *testing.T, url, token, stream string) ([]string, []string) {
+	nc, err := nats.Connect(url, nats.Token(token))
+	if err != nil {
+		t.Fatalf("Error connecting: %v", err)
+	}
+	defer nc.Close()
+	js, _ := jetstream.New(nc)
+
+	ctx := context.Background()
+	s, err := js.Stream(ctx, stream)
+	if err != nil {
+		t.Fatalf("Expected stream %s, got %v", stream, err)
+	}
+	info, _ := s.Info(ctx)
+
+	var subjects, msgIDs []string
+	for seq := uint64(1); seq <= info.State.LastSeq; seq++ {
+		msg, err := s.GetMsg(ctx, seq)
+		if err != nil {
+			t.Fatalf("Error reading message %d: %v", seq, err)
+		}
+		subjects = append(subjects, msg.Subject)
+		msgIDs = append(msgIDs, msg.Header.Get(jetstream.MsgIDHeader))
+	}
+	return subjects, msgIDs
+}
+
+func TestNATSPublisher_PublishToJetStream(t *testing.T) {
+	silenceLogs(t)
+	srv := startJetStream(t, "s3cret")
+
+	publisher, err := infrastructure.NewNATSPublisher(infrastructure.NATSPublisherConfig{
+		URL:        srv.ClientURL(),
+		Token:      "s3cret",
+		StreamName: "INVENTORY",
+	})
+	if err != nil {
+		t.Fatalf("Error creating publisher: %v", err)
+	}
+	defer publisher.Close()
+
+	event := domain.NewReservationCreatedEvent("res-1", "prod-1", "MAD-001", 2)
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// El reintento del mismo evento se deduplica por Nats-Msg-Id
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Expected no error on retry, got %v", err)
+	}
+
+	batch := []*domain.Event{
+		domain.NewStockUpdatedEvent("prod-1", "BCN-001", 1, 2),
+		domain.NewStockCreatedEvent("prod-2", "VAL-001", 5),
+	}
+	if err := publisher.PublishBatch(context.Background(), batch); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	subjects, msgIDs := streamMessages(t, srv.ClientURL(), "s3cret", "INVENTORY")
+	expected := []string{
+		"inventory.MAD-001.reservation.created",
+		"inventory.BCN-001.stock.updated",
+		"inventory.VAL-001.stock.created",
+	}
+	if strings.Join(subjects, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected subjects %v (duplicate dropped), got %v", expected, subjects)
+	}
+	if len(msgIDs) == 0 || msgIDs[0] != event.ID {
+		t.Errorf("Expected Nats-Msg-Id %s, got %v", event.ID, msgIDs)
+	}
+
+	// Un stream existente no impide arrancar otra instancia
+	second, err := infrastructure.NewNATSPublisher(infrastructure.NATSPublisherConfig{
+		URL:             srv.ClientURL(),
+		Token:           "s3cret",
+		StreamName:      "INVENTORY",
+		SubjectTemplate: "events.{store_id}.{event_type}",
+	})
+	if err != nil {
+		t.Fatalf("Expected the existing stream left unchanged, got %v", err)
+	}
+	second.Close()
+}
+
+func TestNATSPublisher_SubjectTemplateAndNoResponders(t *testing.T) {
+	silenceLogs(t)
+	srv := startJetStream(t, "")
+
+	publisher, err := infrastructure.NewNATSPublisher(infrastructure.NATSPublisherConfig{
+		URL:             srv.ClientURL(),
+		SubjectTemplate: "unrouted.{aggregate_type}.{store_id}",
+		AckTimeout:      time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Error creating publisher: %v", err)
+	}
+	defer publisher.Close()
+
+	event := domain.NewStockCreatedEvent("prod-1", "store.with*wildcards", 1)
+	if subject := publisher.Subject(event); subject != "unrouted.stock.store_with_wildcards" {
+		t.Errorf("Expected sanitized subject, got %s", subject)
+	}
+	if streamSubjects := publisher.StreamSubjects(); streamSubjects != "unrouted.>" {
+		t.Errorf("Expected unrouted.> stream filter, got %s", streamSubjects)
+	}
+
+	// Sin stream que escuche el subject la publicación falla en lugar de perderse
+	err = publisher.Publish(context.Background(), event)
+	if !errors.Is(err, jetstream.ErrNoStreamResponse) {
+		t.Errorf("Expected no-responders error, got %v", err)
+	}
+}
+
+func TestNATSPublisher_RejectsBadToken(t *testing.T) {
+	silenceLogs(t)
+	srv := startJetStream(t, "s3cret")
+
+	_, err := infrastructure.NewNATSPublisher(infrastructure.NATSPublisherConfig{
+		URL:   srv.ClientURL(),
+		Token: "wrong",
+	})
+	if err == nil {
+		t.Error("Expected an authorization error")
+	}
+}
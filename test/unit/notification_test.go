@@ -0,0 +1,182 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+// recordingNotifier notifier de prueba que guarda los avisos enviados
+type recordingNotifier struct {
+	channel domain.NotificationChannel
+	err     error
+	sent    []*domain.Notification
+}
+
+func (n *recordingNotifier) Channel() domain.NotificationChannel {
+	return n.channel
+}
+
+func (n *recordingNotifier) Send(ctx context.Context, notification *domain.Notification) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestStoreNotificationSettings_Validate(t *testing.T) {
+	var validationErr *domain.ValidationError
+	for name, settings := range map[string]*domain.StoreNotificationSettings{
+		"no channels":     {},
+		"unknown channel": {Channels: []domain.NotificationChannel{"FAX"}},
+		"unknown kind":    {Channels: []domain.NotificationChannel{"EMAIL"}, Kinds: []domain.NotificationKind{"RESERVATION_LOST"}},
+		"bad template": {Channels: []domain.NotificationChannel{"EMAIL"}, Templates: map[domain.NotificationKind]domain.NotificationTemplate{
+			domain.NotificationReservationCreated: {Subject: "Hola", Body: "{{.Unknown}}"},
+		}},
+	} {
+		if err := settings.Validate(); !errors.As(err, &validationErr) {
+			t.Errorf("%s: expected ValidationError, got %v", name, err)
+		}
+	}
+
+	settings := &domain.StoreNotificationSettings{Enabled: true, Channels: []domain.NotificationChannel{"email", " sms"}, ExpiringBeforeMinutes: 0}
+	if err := settings.Validate(); err != nil {
+		t.Fatalf("Expected valid settings, got %v", err)
+	}
+	if settings.Channels[0] != domain.NotificationChannelEmail || settings.Channels[1] != domain.NotificationChannelSMS {
+		t.Errorf("Expected normalized channels, got %v", settings.Channels)
+	}
+	// Sin antelación no hay aviso de vencimiento próximo
+	if settings.Notifies(domain.NotificationReservationExpiring) || !settings.Notifies(domain.NotificationReservationExpired) {
+		t.Errorf("Expected every notification except the expiring one")
+	}
+
+	if err := (&domain.CustomerContact{CustomerID: "c-1", Phone: "600111222"}).Validate(); !errors.As(err, &validationErr) || validationErr.Field != "phone" {
+		t.Errorf("Expected ValidationError on phone for a non E.164 number, got %v", err)
+	}
+}
+
+func TestNotificationService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	silenceLogs(t)
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	storeRepo := repository.NewStoreRepository(db)
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, repository.NewEventRepository(db), mocks.NewNoOpPublisher())
+	notificationService := service.NewNotificationService(repository.NewNotificationRepository(db), reservationRepo, productRepo, storeRepo)
+	email := &recordingNotifier{channel: domain.NotificationChannelEmail}
+	notificationService.RegisterNotifier(email)
+	ctx := context.Background()
+
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+	reservation, err := reservationService.CreateReservation(ctx, laptop, "MAD-001", "customer-1", 1, 30)
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	created := domain.NewReservationCreatedEvent(reservation.ID, laptop, "MAD-001", 1)
+
+	// Sin configuración la tienda no avisa
+	if err := notificationService.HandleEvent(ctx, created); err != nil {
+		t.Fatalf("HandleEvent failed: %v", err)
+	}
+	if len(email.sent) != 0 {
+		t.Fatalf("Expected no notifications without store settings, got %d", len(email.sent))
+	}
+
+	_, err = notificationService.SetSettings(ctx, &domain.StoreNotificationSettings{
+		StoreID:               "MAD-001",
+		Enabled:               true,
+		Channels:              []domain.NotificationChannel{domain.NotificationChannelEmail, domain.NotificationChannelSMS},
+		ExpiringBeforeMinutes: 45,
+		EmailFrom:             "madrid@example.com",
+		Templates: map[domain.NotificationKind]domain.NotificationTemplate{
+			domain.NotificationReservationExpiring: {Subject: "Quedan {{.MinutesLeft}} minutos", Body: "{{.ProductName}} en {{.StoreName}}"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save settings: %v", err)
+	}
+	if _, err := notificationService.SetContact(ctx, &domain.CustomerContact{CustomerID: "customer-1", Email: "cliente@example.com"}); err != nil {
+		t.Fatalf("Failed to save contact: %v", err)
+	}
+
+	// Los eventos repetidos (re-intentos del outbox) no duplican avisos
+	for i := 0; i < 2; i++ {
+		if err := notificationService.HandleEvent(ctx, created); err != nil {
+			t.Fatalf("HandleEvent failed: %v", err)
+		}
+	}
+	if len(email.sent) != 1 {
+		t.Fatalf("Expected 1 email for the created reservation, got %d", len(email.sent))
+	}
+	sent := email.sent[0]
+	if sent.To != "cliente@example.com" || sent.From != "madrid@example.com" || sent.Kind != domain.NotificationReservationCreated {
+		t.Errorf("Unexpected notification: %+v", sent)
+	}
+	if !strings.Contains(sent.Subject, "Madrid") || !strings.Contains(sent.Body, reservation.ID) {
+		t.Errorf("Expected the default template rendered with store and reservation, got %q / %q", sent.Subject, sent.Body)
+	}
+
+	// El vencimiento próximo lo avisa el worker, una sola vez y con la plantilla de la tienda
+	now := time.Now()
+	if count, err := notificationService.NotifyExpiring(ctx, now); err != nil || count != 1 {
+		t.Fatalf("Expected 1 expiring reservation notified, got %d (%v)", count, err)
+	}
+	if count, err := notificationService.NotifyExpiring(ctx, now.Add(time.Minute)); err != nil || count != 0 {
+		t.Errorf("Expected the expiring notification only once, got %d (%v)", count, err)
+	}
+	if last := email.sent[len(email.sent)-1]; last.Subject != "Quedan 30 minutos" || !strings.Contains(last.Body, "Laptop") {
+		t.Errorf("Expected the store template for the expiring notification, got %q / %q", last.Subject, last.Body)
+	}
+
+	// Un evento que llega cuando la reserva ya cambió de estado no avisa
+	if err := reservationService.ConfirmReservation(ctx, reservation.ID); err != nil {
+		t.Fatalf("Failed to confirm reservation: %v", err)
+	}
+	expired := domain.NewReservationExpiredEvent(reservation.ID, laptop, "MAD-001", 1)
+	if err := notificationService.HandleEvent(ctx, expired); err != nil {
+		t.Fatalf("HandleEvent failed: %v", err)
+	}
+	email.err = errors.New("smtp: 451 try again later")
+	confirmed := domain.NewReservationLifecycleEvent(domain.EventReservationConfirmed, reservation)
+	if err := notificationService.HandleEvent(ctx, confirmed); err != nil {
+		t.Fatalf("HandleEvent failed: %v", err)
+	}
+
+	deliveries, err := notificationService.ListDeliveries(ctx, reservation.ID)
+	if err != nil {
+		t.Fatalf("Failed to list deliveries: %v", err)
+	}
+	statuses := map[string]domain.NotificationStatus{}
+	for _, delivery := range deliveries {
+		statuses[string(delivery.Kind)+"/"+string(delivery.Channel)] = delivery.Status
+	}
+	expected := map[string]domain.NotificationStatus{
+		"RESERVATION_CREATED/EMAIL":   domain.NotificationStatusSent,
+		"RESERVATION_CREATED/SMS":     domain.NotificationStatusSkipped, // Sin teléfono ni notifier de SMS
+		"RESERVATION_EXPIRING/EMAIL":  domain.NotificationStatusSent,
+		"RESERVATION_EXPIRING/SMS":    domain.NotificationStatusSkipped,
+		"RESERVATION_CONFIRMED/EMAIL": domain.NotificationStatusFailed,
+		"RESERVATION_CONFIRMED/SMS":   domain.NotificationStatusSkipped,
+	}
+	if len(statuses) != len(expected) {
+		t.Errorf("Expected %d deliveries, got %v", len(expected), statuses)
+	}
+	for key, status := range expected {
+		if statuses[key] != status {
+			t.Errorf("%s: expected %s, got %s", key, status, statuses[key])
+		}
+	}
+}
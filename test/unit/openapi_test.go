@@ -0,0 +1,86 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"inventory-system/internal/apidocs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type openAPISpec struct {
+	BasePath string                                `json:"basePath"`
+	Paths    map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+func loadOpenAPISpec(t *testing.T) openAPISpec {
+	t.Helper()
+
+	var spec openAPISpec
+	if err := json.Unmarshal(apidocs.Spec(), &spec); err != nil {
+		t.Fatalf("Invalid embedded OpenAPI spec: %v", err)
+	}
+	return spec
+}
+
+func TestOpenAPI_DocumentsCoreRoutes(t *testing.T) {
+	spec := loadOpenAPISpec(t)
+
+	router, cleanup := newVersionedRouter(t)
+	defer cleanup()
+
+	paramPattern := regexp.MustCompile(`:(\w+)`)
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, spec.BasePath+"/") {
+			continue
+		}
+
+		path := paramPattern.ReplaceAllString(strings.TrimPrefix(route.Path, spec.BasePath), "{$1}")
+		operations, ok := spec.Paths[path]
+		if !ok {
+			t.Errorf("Route %s %s is not documented", route.Method, path)
+			continue
+		}
+		if _, ok := operations[strings.ToLower(route.Method)]; !ok {
+			t.Errorf("Method %s is not documented for %s", route.Method, path)
+		}
+	}
+}
+
+func TestOpenAPI_CoreSchemasUseDTOs(t *testing.T) {
+	spec := loadOpenAPISpec(t)
+
+	for path, operations := range spec.Paths {
+		if !strings.HasPrefix(path, "/products") && !strings.HasPrefix(path, "/stock") && !strings.HasPrefix(path, "/reservations") {
+			continue
+		}
+		for method, operation := range operations {
+			if strings.Contains(string(operation), "#/definitions/domain.") {
+				t.Errorf("%s %s documents a domain struct instead of a DTO", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestOpenAPI_ServesSpecAndUI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	apidocs.Register(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK || !json.Valid(w.Body.Bytes()) {
+		t.Errorf("Expected valid JSON spec, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "/openapi.json") {
+		t.Errorf("Expected Swagger UI page pointing to /openapi.json, got %d", w.Code)
+	}
+}
@@ -0,0 +1,177 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestOversellTolerance(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewMockPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, eventRepo, publisher)
+	oversellService := service.NewOversellService(repository.NewOversellRepository(db), repository.NewStoreRepository(db), productRepo)
+	reportService := service.NewReportService(repository.NewReportRepository(db))
+
+	ctx := context.Background()
+	newProduct := func(storeID string, quantity int) string {
+		product := testutil.CreateTestProduct()
+		if err := productRepo.Create(ctx, product); err != nil {
+			t.Fatalf("Error creating product: %v", err)
+		}
+		if _, err := stockService.InitializeStock(ctx, product.ID, storeID, quantity); err != nil {
+			t.Fatalf("Error initializing stock: %v", err)
+		}
+		return product.ID
+	}
+	setTolerance := func(scope domain.OversellScope, scopeID string, maxUnits int) {
+		_, err := oversellService.SetTolerance(ctx, &domain.OversellTolerance{Scope: scope, ScopeID: scopeID, MaxUnits: maxUnits})
+		if err != nil {
+			t.Fatalf("Error setting %s tolerance: %v", scope, err)
+		}
+	}
+	stock := func(productID, storeID string) *domain.Stock {
+		s, err := stockRepo.GetByProductAndStore(ctx, productID, storeID)
+		if err != nil {
+			t.Fatalf("Error reading stock: %v", err)
+		}
+		return s
+	}
+
+	t.Run("WithoutToleranceRejectsOversell", func(t *testing.T) {
+		productID := newProduct("MAD-001", 2)
+		var insufficient *domain.InsufficientStockError
+		if _, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "customer-1", 3, 0); !errors.As(err, &insufficient) {
+			t.Errorf("Expected InsufficientStockError, got %v", err)
+		}
+		var validation *domain.ValidationError
+		if _, err := stockService.AdjustStock(ctx, productID, "MAD-001", -3); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for negative stock, got %v", err)
+		}
+	})
+
+	t.Run("ReserveAndConfirmDownToFloor", func(t *testing.T) {
+		setTolerance(domain.OversellScopeStore, "BCN-001", 3)
+		productID := newProduct("BCN-001", 2)
+
+		reservation, err := reservationService.CreateReservation(ctx, productID, "BCN-001", "customer-1", 4, 0)
+		if err != nil {
+			t.Fatalf("Expected reservation within tolerance, got %v", err)
+		}
+		if s := stock(productID, "BCN-001"); s.Available() != -2 {
+			t.Errorf("Expected available -2, got %d", s.Available())
+		}
+
+		var insufficient *domain.InsufficientStockError
+		if _, err := reservationService.CreateReservation(ctx, productID, "BCN-001", "customer-2", 2, 0); !errors.As(err, &insufficient) {
+			t.Errorf("Expected InsufficientStockError beyond the floor, got %v", err)
+		}
+
+		if err := reservationService.ConfirmReservation(ctx, reservation.ID); err != nil {
+			t.Fatalf("Expected confirmation within tolerance, got %v", err)
+		}
+		if s := stock(productID, "BCN-001"); s.Quantity != -2 || s.Reserved != 0 {
+			t.Errorf("Expected quantity -2 and reserved 0, got %d/%d", s.Quantity, s.Reserved)
+		}
+	})
+
+	t.Run("AdjustRespectsFloor", func(t *testing.T) {
+		productID := newProduct("BCN-001", 2)
+		if _, err := stockService.AdjustStock(ctx, productID, "BCN-001", -5); err != nil {
+			t.Fatalf("Expected adjustment within tolerance, got %v", err)
+		}
+		var validation *domain.ValidationError
+		if _, err := stockService.AdjustStock(ctx, productID, "BCN-001", -1); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError beyond the floor, got %v", err)
+		}
+	})
+
+	t.Run("ProductToleranceTakesPrecedence", func(t *testing.T) {
+		productID := newProduct("BCN-001", 0)
+		setTolerance(domain.OversellScopeProduct, productID, 1)
+
+		tolerance, err := stockRepo.GetOversellTolerance(ctx, productID, "BCN-001")
+		if err != nil || tolerance != 1 {
+			t.Fatalf("Expected product tolerance 1, got %d (%v)", tolerance, err)
+		}
+		var insufficient *domain.InsufficientStockError
+		if _, err := reservationService.CreateReservation(ctx, productID, "BCN-001", "customer-1", 2, 0); !errors.As(err, &insufficient) {
+			t.Errorf("Expected InsufficientStockError with product tolerance, got %v", err)
+		}
+
+		if err := oversellService.DeleteTolerance(ctx, domain.OversellScopeProduct, productID); err != nil {
+			t.Fatalf("Error deleting tolerance: %v", err)
+		}
+		if tolerance, _ := stockRepo.GetOversellTolerance(ctx, productID, "BCN-001"); tolerance != 3 {
+			t.Errorf("Expected store tolerance 3 after delete, got %d", tolerance)
+		}
+	})
+
+	t.Run("ReportsFlagOversold", func(t *testing.T) {
+		report, err := reportService.GetOutOfStockReport(ctx, "BCN-001", "")
+		if err != nil {
+			t.Fatalf("Error getting report: %v", err)
+		}
+		if report.Oversold != 2 {
+			t.Errorf("Expected 2 oversold rows, got %d", report.Oversold)
+		}
+		for _, item := range report.Items {
+			if item.Oversold != (item.Available < 0) {
+				t.Errorf("Unexpected oversold flag for %+v", item)
+			}
+		}
+
+		overview, err := reportService.GetOverview(ctx, "", "", 0)
+		if err != nil {
+			t.Fatalf("Error getting overview: %v", err)
+		}
+		if overview.OversoldCount != 2 {
+			t.Errorf("Expected oversold count 2, got %d", overview.OversoldCount)
+		}
+	})
+
+	t.Run("RepositoryRejectsNegativeWithoutTolerance", func(t *testing.T) {
+		// La tabla ya no tiene CHECK (quantity >= 0): el suelo lo aplica el repositorio
+		productID := newProduct("MAD-001", 2)
+		s := stock(productID, "MAD-001")
+		s.Quantity = -1
+		var validation *domain.ValidationError
+		if err := stockRepo.UpdateQuantity(ctx, s); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError without tolerance, got %v", err)
+		}
+
+		if _, err := stockService.InitializeStock(ctx, productID, "BCN-001", 2); err != nil {
+			t.Fatalf("Error initializing stock: %v", err)
+		}
+		s = stock(productID, "BCN-001")
+		s.Quantity = -1
+		if err := stockRepo.UpdateQuantity(ctx, s); err != nil {
+			t.Errorf("Expected negative quantity within BCN-001 tolerance, got %v", err)
+		}
+	})
+
+	t.Run("ValidatesTolerance", func(t *testing.T) {
+		var validation *domain.ValidationError
+		_, err := oversellService.SetTolerance(ctx, &domain.OversellTolerance{Scope: "STORE", ScopeID: "MAD-001"})
+		if !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for max_units 0, got %v", err)
+		}
+		var notFound *domain.NotFoundError
+		_, err = oversellService.SetTolerance(ctx, &domain.OversellTolerance{Scope: "STORE", ScopeID: "XXX-999", MaxUnits: 1})
+		if !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError for unknown store, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidatePathParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	reached := 0
+	ok := func(c *gin.Context) {
+		reached++
+		c.Status(http.StatusOK)
+	}
+	router := gin.New()
+	api := router.Group("/api/v1", handler.UseSerializer(handler.V1Serializer{}), handler.ValidatePathParams())
+	api.GET("/products/:id", ok)
+	api.GET("/stock/:productId/:storeId", ok)
+	api.GET("/admin/stores/:id/hours", ok)
+	api.GET("/reservations/:id", ok)
+
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+	for _, tc := range []struct {
+		path   string
+		status int
+		field  string
+	}{
+		{path: "/api/v1/products/" + laptop, status: http.StatusOK},
+		{path: "/api/v1/products/non-existent", status: http.StatusBadRequest, field: "id"},
+		{path: "/api/v1/stock/" + laptop + "/MAD-001", status: http.StatusOK},
+		{path: "/api/v1/stock/non-existent/MAD-001", status: http.StatusBadRequest, field: "productId"},
+		{path: "/api/v1/stock/" + laptop + "/MAD.001", status: http.StatusBadRequest, field: "storeId"},
+		{path: "/api/v1/admin/stores/MAD-001/hours", status: http.StatusOK},
+		{path: "/api/v1/admin/stores/-MAD/hours", status: http.StatusBadRequest, field: "id"},
+		// Los IDs de otros recursos no se validan aquí
+		{path: "/api/v1/reservations/non-existent-id", status: http.StatusOK},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.status {
+			t.Errorf("%s: expected %d, got %d: %s", tc.path, tc.status, w.Code, w.Body.String())
+		}
+		if tc.field != "" && !strings.Contains(w.Body.String(), tc.field+": ") {
+			t.Errorf("%s: expected a validation error on %s, got %s", tc.path, tc.field, w.Body.String())
+		}
+	}
+	if reached != 4 {
+		t.Errorf("Expected only the 4 valid requests to reach the handler, got %d", reached)
+	}
+
+	// Los mismos formatos se exigen al crear productos y tiendas
+	var validationErr *domain.ValidationError
+	if err := (&domain.Product{ID: "prod-1", SKU: "SKU-1", Name: "Laptop"}).Validate(); !errors.As(err, &validationErr) || validationErr.Field != "id" {
+		t.Errorf("Expected ValidationError on id for a non-UUID product ID, got %v", err)
+	}
+	if err := (&domain.Store{ID: "MAD 001", Name: "Madrid"}).Validate(); !errors.As(err, &validationErr) || validationErr.Field != "id" {
+		t.Errorf("Expected ValidationError on id for a store ID with spaces, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestPreorderReservations(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewNoOpPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+	scheduleService := service.NewStockScheduleService(repository.NewStockScheduleRepository(db), stockService)
+	scheduleService.SetReservationService(reservationService)
+
+	silenceLogs(t)
+	ctx := domain.WithActor(context.Background(), "purchasing")
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(ctx, product.ID, "MAD-001", 0); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+
+	var insufficient *domain.InsufficientStockError
+	if _, err := reservationService.CreatePreorder(ctx, product.ID, "MAD-001", "customer-0", 1, 30, ""); !errors.As(err, &insufficient) {
+		t.Fatalf("Expected InsufficientStockError without inbound stock, got %v", err)
+	}
+
+	inbound, err := scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+		ProductID: product.ID, StoreID: "MAD-001", Quantity: 5, Source: domain.ScheduledStockPurchaseOrder,
+		Reference: "PO-2026-0142", EffectiveAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Error scheduling inbound purchase order: %v", err)
+	}
+
+	preorder := func(customerID string, quantity int) (*domain.Reservation, error) {
+		return reservationService.CreatePreorder(ctx, product.ID, "MAD-001", customerID, quantity, 30, "")
+	}
+	first, err := preorder("customer-1", 3)
+	if err != nil {
+		t.Fatalf("Error creating first preorder: %v", err)
+	}
+	if first.Status != domain.ReservationStatusPreorder {
+		t.Errorf("Expected PREORDER status, got %s", first.Status)
+	}
+	second, err := preorder("customer-2", 1)
+	if err != nil {
+		t.Fatalf("Error creating second preorder: %v", err)
+	}
+	third, err := preorder("customer-3", 1)
+	if err != nil {
+		t.Fatalf("Error creating third preorder: %v", err)
+	}
+	if _, err := preorder("customer-4", 1); !errors.As(err, &insufficient) || insufficient.Available != 0 {
+		t.Errorf("Expected InsufficientStockError once the inbound units are promised, got %v", err)
+	}
+
+	// Sin stock asignado no se confirma ni reserva unidades
+	var invalidState *domain.InvalidStateError
+	if err := reservationService.ConfirmReservation(ctx, first.ID); !errors.As(err, &invalidState) {
+		t.Errorf("Expected InvalidStateError confirming a preorder, got %v", err)
+	}
+	if err := reservationService.CancelReservation(ctx, third.ID); err != nil {
+		t.Fatalf("Error cancelling preorder: %v", err)
+	}
+	stock, _ := stockService.GetStockByProductAndStore(ctx, product.ID, "MAD-001")
+	if stock.Reserved != 0 {
+		t.Errorf("Expected preorders not to reserve stock, got reserved=%d", stock.Reserved)
+	}
+
+	// Llegan 3 de las 5 unidades: la primera preventa se asigna y la segunda espera (FIFO)
+	db.Exec(`UPDATE scheduled_stock_changes SET quantity = 3, effective_at = ? WHERE id = ?`, time.Now().Add(-time.Second), inbound.ID)
+	if applied, err := scheduleService.ProcessScheduledStockChanges(ctx); err != nil || applied != 1 {
+		t.Fatalf("Expected the purchase order to be applied, got %d (err: %v)", applied, err)
+	}
+	allocated, _ := reservationService.GetReservation(ctx, first.ID)
+	if allocated.Status != domain.ReservationStatusPending || time.Until(allocated.ExpiresAt) < 29*time.Minute {
+		t.Errorf("Expected the first preorder to be PENDING with its 30 minute TTL, got %s expiring %v", allocated.Status, allocated.ExpiresAt)
+	}
+	waiting, _ := reservationService.GetReservation(ctx, second.ID)
+	if waiting.Status != domain.ReservationStatusPreorder {
+		t.Errorf("Expected the second preorder to keep waiting, got %s", waiting.Status)
+	}
+
+	// Recepción registrada fuera del calendario
+	if _, err := stockService.UpdateStock(ctx, product.ID, "MAD-001", 4); err != nil {
+		t.Fatalf("Error updating stock: %v", err)
+	}
+	result, err := reservationService.AllocatePreorders(ctx, product.ID, "MAD-001")
+	if err != nil || len(result) != 1 || result[0].ID != second.ID {
+		t.Fatalf("Expected the second preorder to be allocated, got %v (err: %v)", result, err)
+	}
+	stock, _ = stockService.GetStockByProductAndStore(ctx, product.ID, "MAD-001")
+	if stock.Reserved != 4 {
+		t.Errorf("Expected reserved=4 after allocating both preorders, got %d", stock.Reserved)
+	}
+
+	if err := reservationService.ConfirmReservation(ctx, first.ID); err != nil {
+		t.Errorf("Expected the allocated preorder to be confirmable, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+)
+
+func TestPriceService_HistoryAndScheduledChanges(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	priceRepo := repository.NewPriceRepository(db)
+	productService := service.NewProductService(productRepo, repository.NewEventRepository(db))
+	priceService := service.NewPriceService(priceRepo, productRepo)
+	ctx := domain.WithActor(context.Background(), "store-MAD-001")
+
+	product, err := productService.CreateProduct(ctx, testutil.CreateTestProduct())
+	if err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+
+	t.Run("ManualUpdateRecordsHistory", func(t *testing.T) {
+		current, _ := productService.GetProduct(ctx, product.ID)
+		oldPrice := current.Price
+		current.Price = oldPrice + 10
+		if _, err := productService.UpdateProduct(ctx, current); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Cambiar solo el nombre no genera entrada
+		current.Name = "Renamed"
+		if _, err := productService.UpdateProduct(ctx, current); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		history, total, err := priceService.GetPriceHistory(ctx, product.ID, 0, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if total != 1 || len(history) != 1 {
+			t.Fatalf("Expected 1 price change, got %d", total)
+		}
+		change := history[0]
+		if change.OldPrice != oldPrice || change.NewPrice != oldPrice+10 {
+			t.Errorf("Expected %.2f -> %.2f, got %.2f -> %.2f", oldPrice, oldPrice+10, change.OldPrice, change.NewPrice)
+		}
+		if change.Actor != "store-MAD-001" || change.Source != domain.PriceChangeManual {
+			t.Errorf("Expected manual change by store-MAD-001, got %s by %s", change.Source, change.Actor)
+		}
+	})
+
+	t.Run("ScheduleRejectsPastDate", func(t *testing.T) {
+		_, err := priceService.SchedulePriceChange(ctx, product.ID, 5, time.Now().Add(-time.Minute))
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("WorkerAppliesDueChanges", func(t *testing.T) {
+		schedule, err := priceService.SchedulePriceChange(ctx, product.ID, 42.5, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Todavía no llegó effective_at
+		if applied, _ := priceService.ProcessScheduledPriceChanges(ctx); applied != 0 {
+			t.Fatalf("Expected no changes applied before effective_at, got %d", applied)
+		}
+
+		db.Exec(`UPDATE scheduled_price_changes SET effective_at = ? WHERE id = ?`, time.Now().Add(-time.Second), schedule.ID)
+
+		applied, err := priceService.ProcessScheduledPriceChanges(context.Background())
+		if err != nil || applied != 1 {
+			t.Fatalf("Expected 1 change applied, got %d (%v)", applied, err)
+		}
+
+		updated, _ := productService.GetProduct(ctx, product.ID)
+		if updated.Price != 42.5 {
+			t.Errorf("Expected price 42.5, got %.2f", updated.Price)
+		}
+
+		history, total, _ := priceService.GetPriceHistory(ctx, product.ID, 0, 0)
+		var recorded *domain.PriceChange
+		for _, change := range history {
+			if change.ScheduledID == schedule.ID {
+				recorded = change
+			}
+		}
+		if total != 2 || recorded == nil {
+			t.Fatalf("Expected scheduled change recorded in history, got %d entries", total)
+		}
+		if recorded.Source != domain.PriceChangeScheduled || recorded.Actor != "store-MAD-001" || recorded.NewPrice != 42.5 {
+			t.Errorf("Expected scheduled change attributed to its author, got %+v", recorded)
+		}
+
+		if _, err := priceService.CancelScheduledPriceChange(ctx, product.ID, schedule.ID); err == nil {
+			t.Error("Expected error cancelling an applied change")
+		}
+	})
+
+	t.Run("CancelPending", func(t *testing.T) {
+		schedule, _ := priceService.SchedulePriceChange(ctx, product.ID, 1, time.Now().Add(time.Hour))
+		cancelled, err := priceService.CancelScheduledPriceChange(ctx, product.ID, schedule.ID)
+		if err != nil || cancelled.Status != domain.ScheduledPriceCancelled {
+			t.Fatalf("Expected cancelled schedule, got %v", err)
+		}
+	})
+}
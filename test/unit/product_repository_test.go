@@ -150,7 +150,7 @@ func TestProductRepository_List(t *testing.T) {
 	}
 
 	// Listar todos (incluyendo los 5 del schema inicial)
-	list, err := repo.List(ctx, 10, 0)
+	list, err := repo.List(ctx, 10, 0, false)
 	if err != nil {
 		t.Fatalf("Failed to list products: %v", err)
 	}
@@ -160,7 +160,7 @@ func TestProductRepository_List(t *testing.T) {
 	}
 
 	// Test paginación
-	page1, err := repo.List(ctx, 2, 0)
+	page1, err := repo.List(ctx, 2, 0, false)
 	if err != nil {
 		t.Fatalf("Failed to get page 1: %v", err)
 	}
@@ -168,7 +168,7 @@ func TestProductRepository_List(t *testing.T) {
 		t.Errorf("Expected 2 products in page 1, got %d", len(page1))
 	}
 
-	page2, err := repo.List(ctx, 2, 2)
+	page2, err := repo.List(ctx, 2, 2, false)
 	if err != nil {
 		t.Fatalf("Failed to get page 2: %v", err)
 	}
@@ -185,7 +185,7 @@ func TestProductRepository_ListByCategory(t *testing.T) {
 	ctx := context.Background()
 
 	// Los datos de ejemplo ya tienen productos de electronics (minúscula)
-	list, err := repo.ListByCategory(ctx, "electronics", 10, 0)
+	list, err := repo.ListByCategory(ctx, "electronics", 10, 0, false)
 	if err != nil {
 		t.Fatalf("Failed to list by category: %v", err)
 	}
@@ -298,7 +298,7 @@ func TestProductRepository_Count(t *testing.T) {
 	ctx := context.Background()
 
 	// Contar (debería tener los 5 productos del schema)
-	count, err := repo.Count(ctx)
+	count, err := repo.Count(ctx, true)
 	if err != nil {
 		t.Fatalf("Failed to count products: %v", err)
 	}
@@ -325,7 +325,7 @@ func TestProductRepository_Count(t *testing.T) {
 	}
 
 	// Contar nuevamente
-	newCount, err := repo.Count(ctx)
+	newCount, err := repo.Count(ctx, true)
 	if err != nil {
 		t.Fatalf("Failed to count products: %v", err)
 	}
@@ -2,11 +2,13 @@ package unit
 
 import (
 	"context"
+	"regexp"
 	"testing"
 
 	"inventory-system/internal/domain"
 	"inventory-system/internal/repository"
 	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
 	"inventory-system/test/testutil"
 )
 
@@ -206,7 +208,7 @@ func TestProductService_ListProducts(t *testing.T) {
 		}
 
 		// List products
-		products, err := productService.ListProducts(ctx, 10, 0)
+		products, err := productService.ListProducts(ctx, 10, 0, false)
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -217,7 +219,7 @@ func TestProductService_ListProducts(t *testing.T) {
 	})
 
 	t.Run("ListProducts_Pagination", func(t *testing.T) {
-		products, err := productService.ListProducts(ctx, 2, 0)
+		products, err := productService.ListProducts(ctx, 2, 0, false)
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -286,6 +288,84 @@ func TestProductService_UpdateProduct(t *testing.T) {
 	})
 }
 
+func TestProductService_PatchProduct(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	productService := service.NewProductService(productRepo, repository.NewEventRepository(db))
+
+	ctx := context.Background()
+
+	created, err := productService.CreateProduct(ctx, &domain.Product{
+		SKU:         "PATCH-001",
+		Name:        "Original Name",
+		Description: "Original description",
+		Category:    "electronics",
+		Price:       100.00,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	t.Run("PatchProduct_OnlyPrice", func(t *testing.T) {
+		price := 80.00
+		updated, err := productService.PatchProduct(ctx, created.ID, domain.ProductPatch{Price: &price})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if updated.Price != 80.00 {
+			t.Errorf("Expected price 80.00, got %.2f", updated.Price)
+		}
+		if updated.Name != "Original Name" || updated.Description != "Original description" || updated.SKU != "PATCH-001" {
+			t.Errorf("Expected untouched fields to be preserved, got %+v", updated)
+		}
+	})
+
+	t.Run("PatchProduct_SKUIsNormalizedAndUnique", func(t *testing.T) {
+		if _, err := productService.CreateProduct(ctx, &domain.Product{SKU: "PATCH-002", Name: "Other", Price: 1}); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+
+		duplicate := "patch-002"
+		if _, err := productService.PatchProduct(ctx, created.ID, domain.ProductPatch{SKU: &duplicate}); err == nil {
+			t.Fatal("Expected conflict for duplicate SKU, got nil")
+		} else if _, ok := err.(*domain.ConflictError); !ok {
+			t.Fatalf("Expected ConflictError, got %T", err)
+		}
+
+		sku := "patch-003"
+		updated, err := productService.PatchProduct(ctx, created.ID, domain.ProductPatch{SKU: &sku})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if updated.SKU != "PATCH-003" {
+			t.Errorf("Expected normalized SKU PATCH-003, got %s", updated.SKU)
+		}
+	})
+
+	t.Run("PatchProduct_InvalidResult", func(t *testing.T) {
+		empty := ""
+		if _, err := productService.PatchProduct(ctx, created.ID, domain.ProductPatch{Name: &empty}); err == nil {
+			t.Error("Expected validation error for empty name, got nil")
+		}
+	})
+
+	t.Run("PatchProduct_EmptyPatch", func(t *testing.T) {
+		if _, err := productService.PatchProduct(ctx, created.ID, domain.ProductPatch{}); err == nil {
+			t.Error("Expected validation error for empty patch, got nil")
+		}
+	})
+
+	t.Run("PatchProduct_NotFound", func(t *testing.T) {
+		price := 1.0
+		if _, err := productService.PatchProduct(ctx, "non-existent-id", domain.ProductPatch{Price: &price}); err == nil {
+			t.Error("Expected error for non-existent product, got nil")
+		}
+	})
+}
+
 func TestProductService_DeleteProduct(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
@@ -311,7 +391,7 @@ func TestProductService_DeleteProduct(t *testing.T) {
 		}
 
 		// Delete product
-		err = productService.DeleteProduct(ctx, created.ID)
+		_, err = productService.DeleteProduct(ctx, created.ID, false)
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -324,9 +404,247 @@ func TestProductService_DeleteProduct(t *testing.T) {
 	})
 
 	t.Run("DeleteProduct_NotFound", func(t *testing.T) {
-		err := productService.DeleteProduct(ctx, "non-existent-id")
+		_, err := productService.DeleteProduct(ctx, "non-existent-id", false)
 		if err == nil {
 			t.Error("Expected error for non-existent product, got nil")
 		}
 	})
+
+	t.Run("DeleteProduct_InUse", func(t *testing.T) {
+		// PROD-001 tiene stock sembrado en las 4 tiendas
+		productID := "550e8400-e29b-41d4-a716-446655440000"
+		reservation := testutil.CreateTestReservation(productID, "MAD-001")
+		if err := repository.NewReservationRepository(db).Create(ctx, reservation); err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+
+		_, err := productService.DeleteProduct(ctx, productID, false)
+		inUse, ok := err.(*domain.ProductInUseError)
+		if !ok {
+			t.Fatalf("Expected ProductInUseError, got %v", err)
+		}
+		deps := inUse.Dependencies
+		if deps.StockUnits != 50 || deps.ReservedUnits != 6 || len(deps.Stores) != 4 || deps.PendingReservations != 1 {
+			t.Errorf("Unexpected dependencies: %+v", deps)
+		}
+
+		if _, err := productService.GetProduct(ctx, productID); err != nil {
+			t.Errorf("Expected product to survive the guard, got %v", err)
+		}
+	})
+
+	t.Run("DeleteProduct_ForceArchives", func(t *testing.T) {
+		productID := "550e8400-e29b-41d4-a716-446655440000"
+
+		archive, err := productService.DeleteProduct(ctx, productID, true)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if archive.SKU != "PROD-001" || archive.ArchivedStock != 4 || archive.ArchivedReservations != 1 {
+			t.Errorf("Unexpected archive summary: %+v", archive)
+		}
+
+		var stockRows, archivedStock int
+		db.QueryRow(`SELECT COUNT(*) FROM stock WHERE product_id = ?`, productID).Scan(&stockRows)
+		db.QueryRow(`SELECT COUNT(*) FROM archived_stock WHERE product_id = ?`, productID).Scan(&archivedStock)
+		if stockRows != 0 || archivedStock != 4 {
+			t.Errorf("Expected stock moved to archive, got %d live and %d archived", stockRows, archivedStock)
+		}
+
+		var status string
+		db.QueryRow(`SELECT status FROM archived_reservations WHERE product_id = ?`, productID).Scan(&status)
+		if status != string(domain.ReservationStatusCancelled) {
+			t.Errorf("Expected pending reservation archived as CANCELLED, got %s", status)
+		}
+	})
+}
+
+func TestProductService_SKUNormalization(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productService := service.NewProductService(repository.NewProductRepository(db), repository.NewEventRepository(db))
+	ctx := context.Background()
+
+	t.Run("UppercaseAndCaseInsensitiveUniqueness", func(t *testing.T) {
+		created, err := productService.CreateProduct(ctx, &domain.Product{SKU: "  abc-1 ", Name: "Lowercase SKU", Price: 10})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if created.SKU != "ABC-1" {
+			t.Errorf("Expected SKU normalized to ABC-1, got %q", created.SKU)
+		}
+
+		_, err = productService.CreateProduct(ctx, &domain.Product{SKU: "Abc-1", Name: "Duplicate", Price: 10})
+		if _, ok := err.(*domain.ConflictError); !ok {
+			t.Errorf("Expected ConflictError for case-insensitive duplicate, got %v", err)
+		}
+
+		found, err := productService.GetProductBySKU(ctx, "abc-1")
+		if err != nil || found.ID != created.ID {
+			t.Errorf("Expected lookup by SKU to ignore case, got %v", err)
+		}
+	})
+
+	t.Run("UpdateNormalizesAndChecksConflicts", func(t *testing.T) {
+		other, err := productService.CreateProduct(ctx, &domain.Product{SKU: "XYZ-2", Name: "Other", Price: 10})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		other.SKU = "abc-1"
+		if _, err := productService.UpdateProduct(ctx, other); err == nil {
+			t.Error("Expected conflict when renaming to an existing SKU with different case")
+		}
+	})
+
+	t.Run("PolicyRules", func(t *testing.T) {
+		productService.SetSKUPolicy(domain.SKUPolicy{
+			Pattern:   regexp.MustCompile(`^[A-Z]{3}-[0-9]{3}$`),
+			MaxLength: 7,
+			Uppercase: true,
+		})
+		defer productService.SetSKUPolicy(domain.DefaultSKUPolicy())
+
+		for _, sku := range []string{"AB-001", "ABCD-0001", "ABC_001"} {
+			_, err := productService.CreateProduct(ctx, &domain.Product{SKU: sku, Name: "Invalid", Price: 1})
+			if _, ok := err.(*domain.ValidationError); !ok {
+				t.Errorf("Expected ValidationError for SKU %q, got %v", sku, err)
+			}
+		}
+
+		created, err := productService.CreateProduct(ctx, &domain.Product{SKU: "qrs-123", Name: "Valid", Price: 1})
+		if err != nil || created.SKU != "QRS-123" {
+			t.Errorf("Expected QRS-123 to be accepted, got %v", err)
+		}
+	})
+}
+
+func TestProductService_Lifecycle(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	productService := service.NewProductService(productRepo, eventRepo)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, mocks.NewNoOpPublisher())
+	ctx := context.Background()
+
+	product, err := productService.CreateProduct(ctx, &domain.Product{SKU: "LIFE-001", Name: "Lifecycle", Price: 5, Status: domain.ProductStatusDraft})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("DraftCannotReceiveStock", func(t *testing.T) {
+		_, err := stockService.InitializeStock(ctx, product.ID, "MAD-001", 10)
+		if _, ok := err.(*domain.InvalidStateError); !ok {
+			t.Errorf("Expected InvalidStateError for DRAFT product, got %v", err)
+		}
+	})
+
+	t.Run("ActivateAndEmitEvent", func(t *testing.T) {
+		updated, err := productService.ChangeStatus(ctx, product.ID, domain.ProductStatusActive, "launch")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if updated.Status != domain.ProductStatusActive {
+			t.Errorf("Expected ACTIVE, got %s", updated.Status)
+		}
+		if _, err := stockService.InitializeStock(ctx, product.ID, "MAD-001", 10); err != nil {
+			t.Errorf("Expected stock initialization for ACTIVE product, got %v", err)
+		}
+
+		var events int
+		db.QueryRow(`SELECT COUNT(*) FROM events WHERE event_type = 'product.status_changed' AND aggregate_id = ?`, product.ID).Scan(&events)
+		if events != 1 {
+			t.Errorf("Expected 1 product.status_changed event, got %d", events)
+		}
+	})
+
+	t.Run("InvalidTransition", func(t *testing.T) {
+		_, err := productService.ChangeStatus(ctx, product.ID, domain.ProductStatusDraft, "")
+		if _, ok := err.(*domain.InvalidStateError); !ok {
+			t.Errorf("Expected InvalidStateError for ACTIVE -> DRAFT, got %v", err)
+		}
+	})
+
+	t.Run("DiscontinuedHiddenFromDefaultListing", func(t *testing.T) {
+		if _, err := productService.ChangeStatus(ctx, product.ID, domain.ProductStatusDiscontinued, "end of life"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if _, err := productService.GetProduct(ctx, product.ID); err != nil {
+			t.Errorf("Expected discontinued product to stay readable, got %v", err)
+		}
+
+		contains := func(products []*domain.Product) bool {
+			for _, p := range products {
+				if p.ID == product.ID {
+					return true
+				}
+			}
+			return false
+		}
+		visible, _ := productService.ListProducts(ctx, 100, 0, false)
+		if contains(visible) {
+			t.Error("Expected discontinued product hidden from default listing")
+		}
+		all, _ := productService.ListProducts(ctx, 100, 0, true)
+		if !contains(all) {
+			t.Error("Expected discontinued product listed with include_discontinued")
+		}
+	})
+}
+
+func TestProductService_UpsertProductBySKU(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	productService := service.NewProductService(productRepo, repository.NewEventRepository(db))
+
+	ctx := context.Background()
+
+	var createdID string
+
+	t.Run("CreatesUnknownSKU", func(t *testing.T) {
+		product, created, err := productService.UpsertProductBySKU(ctx, "erp-001", &domain.Product{Name: "ERP Product", Price: 10})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !created {
+			t.Error("Expected created=true for a new SKU")
+		}
+		if product.SKU != "ERP-001" || product.Status != domain.ProductStatusActive {
+			t.Errorf("Expected normalized ACTIVE product ERP-001, got %s (%s)", product.SKU, product.Status)
+		}
+		createdID = product.ID
+	})
+
+	t.Run("UpdatesExistingSKU", func(t *testing.T) {
+		before, _ := productService.CountProducts(ctx, true)
+
+		product, created, err := productService.UpsertProductBySKU(ctx, "ERP-001", &domain.Product{SKU: "erp-001", Name: "ERP Product v2", Price: 12})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if created {
+			t.Error("Expected created=false for an existing SKU")
+		}
+		if product.ID != createdID || product.Name != "ERP Product v2" || product.Price != 12 {
+			t.Errorf("Expected product %s updated in place, got %+v", createdID, product)
+		}
+
+		if after, _ := productService.CountProducts(ctx, true); after != before {
+			t.Errorf("Expected no new product when upserting an existing SKU, got %d → %d", before, after)
+		}
+	})
+
+	t.Run("RejectsMismatchedBodySKU", func(t *testing.T) {
+		_, _, err := productService.UpsertProductBySKU(ctx, "ERP-001", &domain.Product{SKU: "ERP-999", Name: "Mismatch", Price: 1})
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
 }
@@ -0,0 +1,221 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+)
+
+func TestProductUnits_Validate(t *testing.T) {
+	units := &domain.ProductUnits{
+		ProductID:   "p1",
+		BaseUnit:    " each ",
+		Conversions: []domain.UnitConversion{{Unit: "box", Factor: 12}},
+	}
+	if err := units.Validate(); err != nil {
+		t.Fatalf("Expected valid units, got %v", err)
+	}
+	if units.BaseUnit != "EACH" || units.Conversions[0].Unit != "BOX" {
+		t.Errorf("Expected normalized units, got %+v", units)
+	}
+
+	invalid := []*domain.ProductUnits{
+		{BaseUnit: ""},
+		{BaseUnit: "EACH", Conversions: []domain.UnitConversion{{Unit: "EACH", Factor: 1}}},
+		{BaseUnit: "EACH", Conversions: []domain.UnitConversion{{Unit: "BOX", Factor: 12}, {Unit: "box", Factor: 6}}},
+		{BaseUnit: "EACH", Conversions: []domain.UnitConversion{{Unit: "BOX", Factor: 0}}},
+		{BaseUnit: "EACH", Conversions: []domain.UnitConversion{{Unit: "1BOX", Factor: 12}}},
+	}
+	for _, u := range invalid {
+		var validation *domain.ValidationError
+		if err := u.Validate(); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for %+v, got %v", u, err)
+		}
+	}
+}
+
+func TestProductUnits_ToBase(t *testing.T) {
+	units := &domain.ProductUnits{
+		ProductID:   "p1",
+		BaseUnit:    "EACH",
+		Conversions: []domain.UnitConversion{{Unit: "BOX", Factor: 12}},
+	}
+
+	tests := []struct {
+		quantity int
+		unit     string
+		expected int
+	}{
+		{3, "", 3},
+		{3, "EACH", 3},
+		{2, "box", 24},
+		{-1, "BOX", -12},
+	}
+	for _, tt := range tests {
+		got, err := units.ToBase(tt.quantity, tt.unit)
+		if err != nil || got != tt.expected {
+			t.Errorf("ToBase(%d, %q) = %d, %v; expected %d", tt.quantity, tt.unit, got, err, tt.expected)
+		}
+	}
+
+	if _, err := units.ToBase(1, "PALLET"); err == nil {
+		t.Error("Expected error for undefined unit")
+	}
+}
+
+func TestProductUnits_ToBaseDecimal(t *testing.T) {
+	units := &domain.ProductUnits{
+		ProductID:   "p1",
+		BaseUnit:    "G",
+		Conversions: []domain.UnitConversion{{Unit: "KG", Factor: 1000, Fractional: true}, {Unit: "BOX", Factor: 500}},
+	}
+
+	tests := []struct {
+		quantity float64
+		unit     string
+		rounding domain.RoundingMode
+		expected int
+	}{
+		{2, "BOX", domain.RoundingNearest, 1000},
+		{1.25, "KG", domain.RoundingNearest, 1250},
+		{1.1, "KG", domain.RoundingExact, 1100}, // 1.1 * 1000 no es exacto en coma flotante
+		{0.0015, "KG", domain.RoundingNearest, 2},
+		{0.0015, "KG", domain.RoundingDown, 1},
+		{0.0011, "KG", domain.RoundingUp, 2},
+		{-0.0015, "KG", domain.RoundingDown, -1},
+		{-0.0011, "KG", domain.RoundingUp, -2},
+	}
+	for _, tt := range tests {
+		units.Rounding = tt.rounding
+		got, err := units.ToBaseDecimal(tt.quantity, tt.unit)
+		if err != nil || got != tt.expected {
+			t.Errorf("ToBaseDecimal(%g, %q) with %s = %d, %v; expected %d", tt.quantity, tt.unit, tt.rounding, got, err, tt.expected)
+		}
+	}
+
+	invalid := []struct {
+		quantity float64
+		unit     string
+		rounding domain.RoundingMode
+	}{
+		{1.5, "", domain.RoundingNearest},      // Unidad base
+		{1.5, "BOX", domain.RoundingNearest},   // Sin fractional
+		{0.0004, "KG", domain.RoundingNearest}, // Se redondea a cero
+		{0.0015, "KG", domain.RoundingExact},   // No da gramos enteros
+		{1e300, "KG", domain.RoundingNearest},  // Fuera de rango
+	}
+	for _, tt := range invalid {
+		units.Rounding = tt.rounding
+		var validation *domain.ValidationError
+		if _, err := units.ToBaseDecimal(tt.quantity, tt.unit); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for %g %q with %s, got %v", tt.quantity, tt.unit, tt.rounding, err)
+		}
+	}
+
+	if err := (&domain.ProductUnits{BaseUnit: "G", Rounding: "sideways"}).Validate(); err == nil {
+		t.Error("Expected error for an unknown rounding mode")
+	}
+}
+
+func TestProductUnitService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	unitService := service.NewProductUnitService(repository.NewProductUnitRepository(db), productRepo, stockRepo)
+
+	ctx := context.Background()
+	stocked := "550e8400-e29b-41d4-a716-446655440000" // PROD-001, con stock en las 4 tiendas
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+
+	t.Run("DefaultsToEach", func(t *testing.T) {
+		units, err := unitService.GetUnits(ctx, stocked)
+		if err != nil || units.BaseUnit != domain.UnitEach || len(units.Conversions) != 0 {
+			t.Errorf("Expected EACH without conversions, got %+v (%v)", units, err)
+		}
+		if _, err := unitService.ToBaseUnits(ctx, stocked, 1, "BOX"); err == nil {
+			t.Error("Expected error for BOX without configuration")
+		}
+	})
+
+	t.Run("ConvertsConfiguredUnits", func(t *testing.T) {
+		_, err := unitService.SetUnits(ctx, &domain.ProductUnits{
+			ProductID:   stocked,
+			BaseUnit:    "EACH",
+			Conversions: []domain.UnitConversion{{Unit: "BOX", Factor: 12}},
+		})
+		if err != nil {
+			t.Fatalf("Error setting units: %v", err)
+		}
+
+		quantity, err := unitService.ToBaseUnits(ctx, stocked, 2, "box")
+		if err != nil || quantity != 24 {
+			t.Errorf("Expected 24 EACH, got %d (%v)", quantity, err)
+		}
+	})
+
+	t.Run("BaseUnitChangeRequiresNoStock", func(t *testing.T) {
+		var conflict *domain.ConflictError
+		_, err := unitService.SetUnits(ctx, &domain.ProductUnits{ProductID: stocked, BaseUnit: "KG"})
+		if !errors.As(err, &conflict) {
+			t.Errorf("Expected ConflictError with stock, got %v", err)
+		}
+
+		units, err := unitService.SetUnits(ctx, &domain.ProductUnits{
+			ProductID:   product.ID,
+			BaseUnit:    "g",
+			Conversions: []domain.UnitConversion{{Unit: "KG", Factor: 1000}},
+		})
+		if err != nil || units.BaseUnit != "G" {
+			t.Fatalf("Expected base unit change without stock, got %+v (%v)", units, err)
+		}
+
+		if units.Rounding != domain.RoundingNearest {
+			t.Errorf("Expected NEAREST rounding by default, got %s", units.Rounding)
+		}
+
+		// Las cantidades decimales requieren fractional_quantities en la tienda (desactivado por defecto)
+		if _, err := unitService.SetUnits(ctx, &domain.ProductUnits{
+			ProductID:   product.ID,
+			BaseUnit:    "G",
+			Conversions: []domain.UnitConversion{{Unit: "KG", Factor: 1000, Fractional: true}},
+			Rounding:    domain.RoundingDown,
+		}); err != nil {
+			t.Fatalf("Error setting fractional units: %v", err)
+		}
+		flagService := service.NewFeatureFlagService(repository.NewFeatureFlagRepository(db), repository.NewStoreRepository(db), []*domain.FeatureFlagRule{
+			{Feature: domain.FeatureFractionalQuantities, StoreID: "MAD-001", Enabled: true, Source: domain.FeatureFlagSourceConfig},
+		})
+		var disabled *domain.FeatureDisabledError
+		if _, err := unitService.ToBaseUnitsDecimal(ctx, product.ID, 1.25, "KG", "MAD-001"); !errors.As(err, &disabled) {
+			t.Errorf("Expected FeatureDisabledError without feature flags, got %v", err)
+		}
+		unitService.SetFeatureFlags(flagService)
+		if quantity, err := unitService.ToBaseUnitsDecimal(ctx, product.ID, 1.2505, "KG", "MAD-001"); err != nil || quantity != 1250 {
+			t.Errorf("Expected 1250 G rounded down, got %d (%v)", quantity, err)
+		}
+		if _, err := unitService.ToBaseUnitsDecimal(ctx, product.ID, 1.25, "KG", "MAD-001", "BCN-001"); !errors.As(err, &disabled) || disabled.StoreID != "BCN-001" {
+			t.Errorf("Expected FeatureDisabledError for BCN-001, got %v", err)
+		}
+		if quantity, err := unitService.ToBaseUnitsDecimal(ctx, product.ID, 2, "KG", "BCN-001"); err != nil || quantity != 2000 {
+			t.Errorf("Expected whole quantities without the feature flag, got %d (%v)", quantity, err)
+		}
+
+		if err := unitService.DeleteUnits(ctx, product.ID); err != nil {
+			t.Errorf("Error deleting units: %v", err)
+		}
+		var notFound *domain.NotFoundError
+		if err := unitService.DeleteUnits(ctx, product.ID); !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError on second delete, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package unit
+
+import (
+	"testing"
+
+	"inventory-system/internal/realtime"
+)
+
+func TestRealtimeHub_CategoryFilter(t *testing.T) {
+	hub := realtime.NewHub(8)
+
+	electronics := hub.Subscribe(realtime.Filter{StoreID: "MAD-001", Category: "electronics"})
+	defer hub.Unsubscribe(electronics)
+
+	single := hub.Subscribe(realtime.Filter{ProductIDs: map[string]bool{"prod-mouse": true}})
+	defer hub.Unsubscribe(single)
+
+	hub.Broadcast(&realtime.AvailabilityUpdate{ProductID: "prod-laptop", StoreID: "MAD-001", Category: "electronics"})
+	hub.Broadcast(&realtime.AvailabilityUpdate{ProductID: "prod-laptop", StoreID: "BCN-001", Category: "electronics"})
+	hub.Broadcast(&realtime.AvailabilityUpdate{ProductID: "prod-mouse", StoreID: "MAD-001", Category: "accessories"})
+
+	if got := len(electronics.Updates); got != 1 {
+		t.Errorf("Expected 1 update for electronics in MAD-001, got %d", got)
+	}
+	if update := <-electronics.Updates; update.StoreID != "MAD-001" || update.ProductID != "prod-laptop" {
+		t.Errorf("Unexpected update delivered: %+v", update)
+	}
+
+	if got := len(single.Updates); got != 1 {
+		t.Errorf("Expected 1 update for product subscription, got %d", got)
+	}
+}
+
+func TestRealtimeHub_SlowSubscriberDoesNotBlock(t *testing.T) {
+	hub := realtime.NewHub(1)
+
+	sub := hub.Subscribe(realtime.Filter{StoreID: "MAD-001"})
+	defer hub.Unsubscribe(sub)
+
+	for i := 0; i < 5; i++ {
+		hub.Broadcast(&realtime.AvailabilityUpdate{ProductID: "p", StoreID: "MAD-001"})
+	}
+
+	if got := len(sub.Updates); got != 1 {
+		t.Errorf("Expected buffer to hold 1 update, got %d", got)
+	}
+}
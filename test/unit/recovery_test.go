@@ -0,0 +1,137 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// capturingReporter guarda los panics reportados
+type capturingReporter struct {
+	reports chan *domain.PanicReport
+}
+
+func (r *capturingReporter) ReportPanic(ctx context.Context, report *domain.PanicReport) error {
+	r.reports <- report
+	return nil
+}
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	silenceLogs(t)
+
+	reporter := &capturingReporter{reports: make(chan *domain.PanicReport, 2)}
+	router := gin.New()
+	router.Use(middleware.Recovery(reporter, "api-test"))
+	router.Use(middleware.RequestID())
+	boom := func(c *gin.Context) { panic("boom") }
+	router.GET("/api/v1/products/:id", handler.UseSerializer(handler.V1Serializer{}), boom)
+	router.GET("/api/v2/products/:id", handler.UseSerializer(handler.V2Serializer{}), boom)
+
+	get := func(path, requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(middleware.RequestIDHeader, requestID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	waitReport := func(t *testing.T) *domain.PanicReport {
+		t.Helper()
+		select {
+		case report := <-reporter.reports:
+			return report
+		case <-time.After(2 * time.Second):
+			t.Fatal("Expected panic to be reported")
+			return nil
+		}
+	}
+
+	t.Run("V1ErrorWithRequestID", func(t *testing.T) {
+		w := get("/api/v1/products/123", "trace-panic-1")
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected 500, got %d", w.Code)
+		}
+		var body handler.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if body.RequestID != "trace-panic-1" || body.Error != "Internal Server Error" {
+			t.Errorf("Unexpected error body: %s", w.Body.String())
+		}
+
+		report := waitReport(t)
+		if report.RequestID != "trace-panic-1" || report.Value != "boom" || report.Route != "/api/v1/products/:id" ||
+			report.InstanceID != "api-test" || !strings.Contains(report.Stack, "recovery_test.go") {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("V2Envelope", func(t *testing.T) {
+		w := get("/api/v2/products/123", "trace-panic-2")
+		var body handler.V2ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if w.Code != http.StatusInternalServerError || body.Error.Code != "INTERNAL_SERVER_ERROR" || body.Error.RequestID != "trace-panic-2" {
+			t.Errorf("Expected v2 error envelope, got %d: %s", w.Code, w.Body.String())
+		}
+		waitReport(t)
+	})
+}
+
+func TestErrorReporters(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("X-Sentry-Auth")
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &gotBody)
+	}))
+	defer server.Close()
+
+	report := &domain.PanicReport{
+		RequestID: "trace-1", Method: "POST", Path: "/api/v1/reservations", Route: "/api/v1/reservations",
+		Value: "boom", Stack: "goroutine 1 [running]:", InstanceID: "api-001", OccurredAt: time.Now(),
+	}
+
+	t.Run("Sentry", func(t *testing.T) {
+		dsn := strings.Replace(server.URL, "http://", "http://public-key@", 1) + "/sentry/42"
+		reporter, err := infrastructure.NewSentryReporter(dsn, "production")
+		if err != nil {
+			t.Fatalf("NewSentryReporter failed: %v", err)
+		}
+		if err := reporter.ReportPanic(context.Background(), report); err != nil {
+			t.Fatalf("ReportPanic failed: %v", err)
+		}
+
+		if gotPath != "/sentry/api/42/store/" || !strings.Contains(gotAuth, "sentry_key=public-key") {
+			t.Errorf("Unexpected Sentry request: path=%s auth=%s", gotPath, gotAuth)
+		}
+		tags, _ := gotBody["tags"].(map[string]interface{})
+		if gotBody["environment"] != "production" || tags["request_id"] != "trace-1" || len(gotBody["event_id"].(string)) != 32 {
+			t.Errorf("Unexpected Sentry event: %v", gotBody)
+		}
+
+		if _, err := infrastructure.NewSentryReporter("https://sentry.example.com/42", ""); err == nil {
+			t.Error("Expected DSN without key to be rejected")
+		}
+	})
+
+	t.Run("Webhook", func(t *testing.T) {
+		if err := infrastructure.NewWebhookReporter(server.URL+"/hooks/panics").ReportPanic(context.Background(), report); err != nil {
+			t.Fatalf("ReportPanic failed: %v", err)
+		}
+		if gotPath != "/hooks/panics" || gotBody["request_id"] != "trace-1" || gotBody["stack"] != report.Stack {
+			t.Errorf("Unexpected webhook request: path=%s body=%v", gotPath, gotBody)
+		}
+	})
+}
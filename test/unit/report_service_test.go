@@ -0,0 +1,283 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+)
+
+func TestReportService_GetOverview(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	reportService := service.NewReportService(repository.NewReportRepository(db))
+	ctx := context.Background()
+
+	t.Run("AllCategories", func(t *testing.T) {
+		overview, err := reportService.GetOverview(ctx, "", "", 3)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(overview.Stores) != 4 {
+			t.Fatalf("Expected 4 stores, got %d", len(overview.Stores))
+		}
+		if overview.Totals.StockRows != 20 {
+			t.Errorf("Expected 20 stock rows, got %d", overview.Totals.StockRows)
+		}
+		if overview.OutOfStockCount != 1 {
+			t.Errorf("Expected 1 out of stock row, got %d", overview.OutOfStockCount)
+		}
+		if len(overview.LowStock) != 3 {
+			t.Fatalf("Expected 3 low stock products, got %d", len(overview.LowStock))
+		}
+		if overview.LowStock[0].SKU != "PROD-004" || overview.LowStock[0].TotalAvailable != 21 {
+			t.Errorf("Expected PROD-004 with 21 available first, got %+v", overview.LowStock[0])
+		}
+
+		for _, store := range overview.Stores {
+			if store.StoreID == "VAL-001" {
+				if store.StoreName != "Valencia Norte" {
+					t.Errorf("Expected store name Valencia Norte, got %s", store.StoreName)
+				}
+				if store.OutOfStock != 1 {
+					t.Errorf("Expected 1 out of stock in VAL-001, got %d", store.OutOfStock)
+				}
+			}
+		}
+	})
+
+	t.Run("CategoryFilter", func(t *testing.T) {
+		overview, err := reportService.GetOverview(ctx, "electronics", "", 10)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if overview.Totals.StockRows != 8 {
+			t.Errorf("Expected 8 stock rows, got %d", overview.Totals.StockRows)
+		}
+		if overview.Totals.Available != 65 {
+			t.Errorf("Expected 65 available, got %d", overview.Totals.Available)
+		}
+		if overview.OutOfStockCount != 0 {
+			t.Errorf("Expected 0 out of stock, got %d", overview.OutOfStockCount)
+		}
+		if len(overview.LowStock) != 2 {
+			t.Errorf("Expected 2 products, got %d", len(overview.LowStock))
+		}
+	})
+
+	t.Run("TopTooLarge", func(t *testing.T) {
+		_, err := reportService.GetOverview(ctx, "", "", 500)
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+}
+
+func TestReportService_GetLowStockMetrics(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	reportService := service.NewReportService(repository.NewReportRepository(db))
+	ctx := context.Background()
+
+	t.Run("BoundedByTopN", func(t *testing.T) {
+		metrics, err := reportService.GetLowStockMetrics(ctx, 10, 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(metrics.Items) != 2 {
+			t.Fatalf("Expected 2 items, got %d", len(metrics.Items))
+		}
+		if metrics.Total <= len(metrics.Items) {
+			t.Errorf("Expected total (%d) to include rows beyond top-N", metrics.Total)
+		}
+		if metrics.Items[0].StoreID != "VAL-001" || metrics.Items[0].Available != 0 {
+			t.Errorf("Expected VAL-001 with 0 available first, got %+v", metrics.Items[0])
+		}
+		if metrics.Items[0].SKU == "" {
+			t.Error("Expected SKU label to be populated")
+		}
+	})
+
+	t.Run("FormatOpenMetrics", func(t *testing.T) {
+		metrics, err := reportService.GetLowStockMetrics(ctx, 10, 50)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		body := handler.FormatLowStockMetrics(metrics)
+		if !strings.HasSuffix(body, "# EOF\n") {
+			t.Error("Expected exposition to end with # EOF")
+		}
+		if !strings.Contains(body, `inventory_low_stock_available{product_id="550e8400-e29b-41d4-a716-446655440002",sku="PROD-003",store_id="VAL-001"} 0`) {
+			t.Errorf("Expected labeled gauge for VAL-001 out of stock row, got:\n%s", body)
+		}
+		if !strings.Contains(body, fmt.Sprintf("inventory_low_stock_rows %d\n", metrics.Total)) {
+			t.Error("Expected inventory_low_stock_rows gauge")
+		}
+	})
+
+	t.Run("InvalidTopN", func(t *testing.T) {
+		_, err := reportService.GetLowStockMetrics(ctx, 10, 0)
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+}
+
+func TestReportService_GetOutOfStockReport(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	reportService := service.NewReportService(repository.NewReportRepository(db))
+	eventRepo := repository.NewEventRepository(db)
+	ctx := context.Background()
+
+	t.Run("StoreFilterFallsBackToStockRow", func(t *testing.T) {
+		report, err := reportService.GetOutOfStockReport(ctx, "VAL-001", "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if report.Count != 1 || report.Items[0].SKU != "PROD-003" {
+			t.Fatalf("Expected only PROD-003 out of stock in VAL-001, got %+v", report.Items)
+		}
+		if report.Items[0].OutSinceSource != domain.OutSinceStockRow {
+			t.Errorf("Expected source %s without movements, got %s", domain.OutSinceStockRow, report.Items[0].OutSinceSource)
+		}
+	})
+
+	t.Run("DerivedFromLastMovement", func(t *testing.T) {
+		productID := "550e8400-e29b-41d4-a716-446655440000"
+
+		// MAD-001 se queda sin stock por un ajuste hace 2 horas
+		if _, err := db.Exec(`UPDATE stock SET quantity = 0 WHERE product_id = ? AND store_id = 'MAD-001'`, productID); err != nil {
+			t.Fatalf("Failed to update stock: %v", err)
+		}
+		event := domain.NewStockUpdatedEvent(productID, "MAD-001", 10, 0)
+		event.CreatedAt = time.Now().Add(-2 * time.Hour)
+		if err := eventRepo.Save(ctx, event); err != nil {
+			t.Fatalf("Failed to save event: %v", err)
+		}
+
+		// Un movimiento de otra tienda no cuenta
+		other := domain.NewStockUpdatedEvent(productID, "BCN-001", 5, 8)
+		if err := eventRepo.Save(ctx, other); err != nil {
+			t.Fatalf("Failed to save event: %v", err)
+		}
+
+		report, err := reportService.GetOutOfStockReport(ctx, "", "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if report.Count != 2 {
+			t.Fatalf("Expected 2 out of stock rows, got %d", report.Count)
+		}
+
+		first := report.Items[0]
+		if first.StoreID != "MAD-001" || first.OutSinceSource != domain.OutSinceMovement {
+			t.Fatalf("Expected MAD-001 derived from movement first, got %+v", first)
+		}
+		if first.OutForSeconds < 7100 || first.OutForSeconds > 7300 {
+			t.Errorf("Expected ~7200s out of stock, got %d", first.OutForSeconds)
+		}
+	})
+}
+
+func TestReportService_GetKPIs(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	reportService := service.NewReportService(repository.NewReportRepository(db))
+	eventRepo := repository.NewEventRepository(db)
+	ctx := context.Background()
+
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	reservation := &domain.Reservation{ID: "res-kpi", ProductID: laptop, StoreID: "MAD-001", Quantity: 1}
+	events := []struct {
+		event  *domain.Event
+		offset time.Duration
+	}{
+		{domain.NewReservationCreatedEvent("res-kpi-1", laptop, "MAD-001", 1), 1 * time.Minute},
+		{domain.NewReservationCreatedEvent("res-kpi-2", laptop, "MAD-001", 1), 2 * time.Minute},
+		{domain.NewReservationLifecycleEvent(domain.EventReservationConfirmed, reservation), 3 * time.Minute},
+		{domain.NewReservationLifecycleEvent(domain.EventReservationConfirmed, reservation), 4 * time.Minute},
+		{domain.NewReservationLifecycleEvent(domain.EventReservationCancelled, reservation), 5 * time.Minute},
+		{domain.NewReservationExpiredEvent("res-kpi-1", laptop, "MAD-001", 1), 6 * time.Minute},
+		{domain.NewReservationCreatedEvent("res-kpi-3", laptop, "MAD-001", 1), 12 * time.Minute},
+		{domain.NewStockUpdatedEvent(laptop, "MAD-001", 3, 0), 14 * time.Minute},
+		{domain.NewStockUpdatedEvent(laptop, "MAD-001", 5, 4), 15 * time.Minute},                 // No se queda sin stock
+		{domain.NewStockUpdatedEvent(laptop, "BCN-001", 2, 0), 16 * time.Minute},                 // Otra tienda
+		{domain.NewReservationCreatedEvent("res-kpi-4", laptop, "MAD-001", 1), 25 * time.Minute}, // Fuera del rango
+	}
+	for _, e := range events {
+		e.event.CreatedAt = base.Add(e.offset)
+		if err := eventRepo.Save(ctx, e.event); err != nil {
+			t.Fatalf("Failed to save event: %v", err)
+		}
+	}
+
+	series, err := reportService.GetKPIs(ctx, "MAD-001", nil, base, base.Add(20*time.Minute), 10*time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(series) != len(domain.KPIMetrics) {
+		t.Fatalf("Expected %d series, got %d", len(domain.KPIMetrics), len(series))
+	}
+
+	values := map[string][]string{}
+	for _, s := range series {
+		if len(s.Datapoints) != 2 {
+			t.Fatalf("%s: expected 2 datapoints, got %d", s.Target, len(s.Datapoints))
+		}
+		if !s.Datapoints[1].Timestamp.Equal(base.Add(10 * time.Minute)) {
+			t.Errorf("%s: expected the second bucket at 10:10, got %v", s.Target, s.Datapoints[1].Timestamp)
+		}
+		for _, p := range s.Datapoints {
+			if p.Value == nil {
+				values[s.Target] = append(values[s.Target], "null")
+				continue
+			}
+			values[s.Target] = append(values[s.Target], fmt.Sprintf("%.2f", *p.Value))
+		}
+	}
+	expected := map[string][]string{
+		domain.KPIReservationsPerMinute: {"0.20", "0.10"},
+		domain.KPIStockOuts:             {"0.00", "1.00"},
+		domain.KPIFillRate:              {"0.50", "null"}, // 2 confirmadas de 4 cerradas
+		domain.KPIExpiryRate:            {"0.25", "null"},
+	}
+	for target, want := range expected {
+		if strings.Join(values[target], ",") != strings.Join(want, ",") {
+			t.Errorf("%s: expected %v, got %v", target, want, values[target])
+		}
+	}
+
+	// Formato de Grafana: [valor, epoch_ms]
+	raw, err := json.Marshal(series[2])
+	if err != nil {
+		t.Fatalf("Failed to marshal series: %v", err)
+	}
+	if want := fmt.Sprintf(`{"target":"fill_rate","datapoints":[[0.5,%d],[null,%d]]}`, base.UnixMilli(), base.Add(10*time.Minute).UnixMilli()); string(raw) != want {
+		t.Errorf("Expected %s, got %s", want, raw)
+	}
+
+	if _, err := reportService.GetKPIs(ctx, "", []string{"conversion"}, base, base.Add(time.Hour), time.Minute); err == nil {
+		t.Error("Expected error for an unknown metric")
+	}
+	if _, err := reportService.GetKPIs(ctx, "", nil, base, base.Add(30*24*time.Hour), time.Minute); err == nil {
+		t.Error("Expected error for too many datapoints")
+	}
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID_PropagatesToErrorsAndEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	silenceLogs(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewMockPublisher()
+
+	productHandler := handler.NewProductHandler(service.NewProductService(productRepo, eventRepo))
+	stockHandler := handler.NewStockHandler(service.NewStockService(stockRepo, productRepo, eventRepo, publisher))
+	reservationHandler := handler.NewReservationHandler(
+		service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher),
+		service.NewSerialService(repository.NewSerialRepository(db), reservationRepo),
+	)
+	apiKeyAuth := middleware.APIKeyAuth(auth.NewKeyRing(map[string]string{"test-key": "Test Store"}))
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	handler.RegisterCoreRoutes(router.Group("/api/v1", handler.UseSerializer(handler.V1Serializer{})), apiKeyAuth, productHandler, stockHandler, reservationHandler)
+	handler.RegisterCoreRoutes(router.Group("/api/v2", handler.UseSerializer(handler.V2Serializer{})), apiKeyAuth, productHandler, stockHandler, reservationHandler)
+
+	post := func(path, requestID string, body interface{}) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", "test-key")
+		if requestID != "" {
+			req.Header.Set(middleware.RequestIDHeader, requestID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	const productID = "550e8400-e29b-41d4-a716-446655440002"
+
+	t.Run("reservation event carries correlation_id", func(t *testing.T) {
+		w := post("/api/v1/reservations", "trace-ok-1", map[string]interface{}{
+			"product_id": productID, "store_id": "BCN-001", "customer_id": "c-1", "quantity": 1,
+		})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var stored int
+		err := db.QueryRow(`SELECT COUNT(*) FROM events WHERE event_type = 'reservation.created' AND correlation_id = ?`, "trace-ok-1").Scan(&stored)
+		if err != nil {
+			t.Fatalf("Failed to read event: %v", err)
+		}
+		if stored != 1 {
+			t.Errorf("Expected 1 stored event with correlation_id trace-ok-1, got %d", stored)
+		}
+
+		published := publisher.GetEventsByType("reservation.created")
+		if len(published) != 1 || published[0].CorrelationID != "trace-ok-1" {
+			t.Errorf("Expected published event with correlation_id trace-ok-1, got %+v", published)
+		}
+	})
+
+	t.Run("v1 error body includes request_id", func(t *testing.T) {
+		w := post("/api/v1/reservations", "trace-fail-1", map[string]interface{}{
+			"product_id": productID, "store_id": "VAL-001", "customer_id": "c-1", "quantity": 5,
+		})
+		if w.Code != http.StatusConflict {
+			t.Fatalf("Expected 409, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var body handler.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if body.RequestID != "trace-fail-1" {
+			t.Errorf("Expected request_id trace-fail-1, got %q", body.RequestID)
+		}
+		if w.Header().Get(middleware.RequestIDHeader) != "trace-fail-1" {
+			t.Errorf("Expected X-Request-ID header to be echoed")
+		}
+	})
+
+	t.Run("v2 error envelope includes generated request_id", func(t *testing.T) {
+		w := post("/api/v2/reservations", "", map[string]interface{}{
+			"product_id": productID, "store_id": "VAL-001", "customer_id": "c-1", "quantity": 5,
+		})
+
+		var body handler.V2ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &body)
+		generated := w.Header().Get(middleware.RequestIDHeader)
+		if generated == "" || body.Error.RequestID != generated {
+			t.Errorf("Expected error request_id to match generated header %q, got %q", generated, body.Error.RequestID)
+		}
+	})
+}
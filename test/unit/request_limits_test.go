@@ -0,0 +1,94 @@
+package unit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	silenceLogs(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewMockPublisher()
+
+	productHandler := handler.NewProductHandler(service.NewProductService(productRepo, eventRepo))
+	stockHandler := handler.NewStockHandler(service.NewStockService(stockRepo, productRepo, eventRepo, publisher))
+	reservationHandler := handler.NewReservationHandler(
+		service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher),
+		service.NewSerialService(repository.NewSerialRepository(db), reservationRepo),
+	)
+	apiKeyAuth := middleware.APIKeyAuth(auth.NewKeyRing(map[string]string{"test-key": "Test Store"}))
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.BodyLimit(1024))
+	router.Use(middleware.ListLimit(50))
+	handler.RegisterCoreRoutes(router.Group("/api/v1", handler.UseSerializer(handler.V1Serializer{})), apiKeyAuth, productHandler, stockHandler, reservationHandler)
+
+	send := func(method, path string, body io.Reader, contentLength int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", "test-key")
+		if contentLength >= 0 {
+			req.ContentLength = contentLength
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("RejectsOversizedBody", func(t *testing.T) {
+		body := `{"sku":"BIG-001","name":"` + strings.Repeat("x", 2048) + `","price":1}`
+
+		// Con Content-Length se rechaza sin leer el body
+		if w := send(http.MethodPost, "/api/v1/products", strings.NewReader(body), int64(len(body))); w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected 413 with Content-Length, got %d: %s", w.Code, w.Body.String())
+		}
+		// Sin Content-Length (chunked) se corta al decodificar
+		if w := send(http.MethodPost, "/api/v1/products", io.NopCloser(strings.NewReader(body)), -1); w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected 413 for chunked body, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("RejectsUnknownFieldsOnStrictEndpoints", func(t *testing.T) {
+		body := `{"sku":"STRICT-001","name":"Strict","price":10,"prize":12}`
+		w := send(http.MethodPost, "/api/v1/products", strings.NewReader(body), int64(len(body)))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `unknown field \"prize\"`) {
+			t.Errorf("Expected 400 for unknown field, got %d: %s", w.Code, w.Body.String())
+		}
+
+		body = `{"sku":"STRICT-001","name":"Strict","price":10}`
+		if w := send(http.MethodPost, "/api/v1/products", strings.NewReader(body), int64(len(body))); w.Code != http.StatusCreated {
+			t.Errorf("Expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("LimitsListSize", func(t *testing.T) {
+		if w := send(http.MethodGet, "/api/v1/products?limit=1000000", nil, -1); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for limit above maximum, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := send(http.MethodGet, "/api/v1/products?limit=50", nil, -1); w.Code != http.StatusOK {
+			t.Errorf("Expected 200 for limit at maximum, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestReservationGroupService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+	groupService := service.NewReservationGroupService(reservationService, reservationRepo, repository.NewReservationGroupRepository(db))
+
+	ctx := context.Background()
+
+	// Producto 0002: MAD-001 y BCN-001 con stock, VAL-001 sin stock
+	productID := "550e8400-e29b-41d4-a716-446655440002"
+	items := func(stores ...string) []domain.ReservationGroupItem {
+		var list []domain.ReservationGroupItem
+		for _, store := range stores {
+			list = append(list, domain.ReservationGroupItem{ProductID: productID, StoreID: store, Quantity: 1})
+		}
+		return list
+	}
+	reserved := func(storeID string) int {
+		stock, _ := stockRepo.GetByProductAndStore(ctx, productID, storeID)
+		return stock.Reserved
+	}
+
+	t.Run("CreateRollsBackOnFailure", func(t *testing.T) {
+		before := reserved("MAD-001")
+
+		_, err := groupService.CreateReservationGroup(ctx, "customer-1", items("MAD-001", "VAL-001"), 30, "")
+		if _, ok := err.(*domain.InsufficientStockError); !ok {
+			t.Errorf("Expected InsufficientStockError, got %v", err)
+		}
+		if got := reserved("MAD-001"); got != before {
+			t.Errorf("Expected MAD-001 reservation to be rolled back, reserved %d -> %d", before, got)
+		}
+	})
+
+	t.Run("ConfirmReportsPartialFailure", func(t *testing.T) {
+		group, err := groupService.CreateReservationGroup(ctx, "customer-2", items("MAD-001", "BCN-001"), 30, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if group.Status != domain.ReservationGroupPending || len(group.StoreIDs()) != 2 {
+			t.Fatalf("Expected PENDING group in two stores, got %s %v", group.Status, group.StoreIDs())
+		}
+
+		// La reserva de BCN-001 se cancela por su cuenta antes de confirmar el grupo
+		if err := reservationService.CancelReservation(ctx, group.Reservations[1].ID); err != nil {
+			t.Fatalf("Error cancelling reservation: %v", err)
+		}
+
+		report, err := groupService.ConfirmReservationGroup(ctx, group.ID, "ORDER-1")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if report.Succeeded != 1 || report.Failed != 1 || report.Status != domain.ReservationGroupPartial {
+			t.Errorf("Expected 1 succeeded, 1 failed and PARTIAL, got %+v", report)
+		}
+		if failed := report.Results[1]; failed.StoreID != "BCN-001" || failed.Status != domain.ReservationStatusCancelled || failed.Error == "" {
+			t.Errorf("Expected BCN-001 failure with its current status, got %+v", failed)
+		}
+
+		fetched, err := groupService.GetReservationGroup(ctx, group.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if fetched.Status != domain.ReservationGroupPartial || fetched.Reservations[0].Status != domain.ReservationStatusConfirmed {
+			t.Errorf("Expected PARTIAL group with MAD-001 confirmed, got %s", fetched.Status)
+		}
+	})
+
+	t.Run("CancelIsRepeatable", func(t *testing.T) {
+		group, err := groupService.CreateReservationGroup(ctx, "customer-3", items("MAD-001", "BCN-001"), 30, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		report, err := groupService.CancelReservationGroup(ctx, group.ID)
+		if err != nil || report.Succeeded != 2 || report.Status != domain.ReservationGroupCancelled {
+			t.Fatalf("Expected both reservations cancelled, got %+v (%v)", report, err)
+		}
+
+		report, err = groupService.CancelReservationGroup(ctx, group.ID)
+		if err != nil || report.Skipped != 2 || report.Failed != 0 {
+			t.Errorf("Expected repeated cancel to skip both reservations, got %+v (%v)", report, err)
+		}
+	})
+
+	t.Run("AutoReservationSplitCreatesGroup", func(t *testing.T) {
+		autoReservationService := service.NewAutoReservationService(reservationService, stockRepo)
+		autoReservationService.SetReservationGroupService(groupService)
+
+		// Ninguna de las dos tiendas cubre sola la cantidad
+		barcelona, _ := stockRepo.GetByProductAndStore(ctx, productID, "BCN-001")
+		result, err := autoReservationService.CreateAutoReservation(ctx, productID, []string{"MAD-001", "BCN-001"}, "customer-4", barcelona.Sellable()+1, 30, "", true, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.GroupID == "" {
+			t.Fatal("Expected split reservation to be grouped")
+		}
+
+		group, err := groupService.GetReservationGroup(ctx, result.GroupID)
+		if err != nil || len(group.Reservations) != 2 {
+			t.Errorf("Expected group with the two split reservations, got %+v (%v)", group, err)
+		}
+	})
+
+	t.Run("UnknownGroup", func(t *testing.T) {
+		if _, err := groupService.ConfirmReservationGroup(ctx, "missing", ""); err == nil {
+			t.Error("Expected NotFoundError")
+		} else if _, ok := err.(*domain.NotFoundError); !ok {
+			t.Errorf("Expected NotFoundError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestImportReservations(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewMockPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	importService := service.NewReservationImportService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+
+	ctx := context.Background()
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(ctx, product.ID, "MAD-001", 10); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+
+	createdAt := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	expiresAt := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+	hold := func(externalID string, status domain.ReservationStatus, quantity int) *domain.ReservationImport {
+		return &domain.ReservationImport{
+			ExternalID: externalID,
+			ProductID:  product.ID,
+			StoreID:    "MAD-001",
+			CustomerID: "customer-1",
+			Quantity:   quantity,
+			Status:     status,
+			CreatedAt:  createdAt,
+			ExpiresAt:  expiresAt,
+		}
+	}
+	reserved := func() int {
+		stock, _ := stockService.GetStockByProductAndStore(ctx, product.ID, "MAD-001")
+		return stock.Reserved
+	}
+
+	t.Run("DryRunDoesNotWrite", func(t *testing.T) {
+		report, err := importService.ImportReservations(ctx, []*domain.ReservationImport{
+			hold("OMS-1", domain.ReservationStatusPending, 6),
+			hold("OMS-2", domain.ReservationStatusPending, 6), // 12 > 10 disponibles en el lote
+			hold("OMS-3", domain.ReservationStatusConfirmed, 20),
+		}, true)
+		if err != nil {
+			t.Fatalf("Error validating import: %v", err)
+		}
+		if !report.DryRun || report.Imported != 2 || report.Failed != 1 {
+			t.Fatalf("Expected 2 valid and 1 failed, got %+v", report)
+		}
+		if report.Results[0].Outcome != domain.ReservationImportValid || report.Results[1].Outcome != domain.ReservationImportFailed {
+			t.Errorf("Expected cumulative availability check, got %+v, %+v", report.Results[0], report.Results[1])
+		}
+		if reserved() != 0 {
+			t.Errorf("Expected dry run not to reserve stock, got %d reserved", reserved())
+		}
+		if id, _ := reservationRepo.GetImportedReservationID(ctx, "OMS-1"); id != "" {
+			t.Errorf("Expected dry run not to create reservations, got %s", id)
+		}
+	})
+
+	t.Run("ImportKeepsStateAndTimestamps", func(t *testing.T) {
+		expired := hold("OMS-5", domain.ReservationStatusPending, 1)
+		expired.ExpiresAt = time.Now().Add(-time.Hour)
+
+		report, err := importService.ImportReservations(ctx, []*domain.ReservationImport{
+			hold("OMS-1", domain.ReservationStatusPending, 6),
+			hold("OMS-3", domain.ReservationStatusConfirmed, 20),
+			hold("OMS-4", domain.ReservationStatusCancelled, 2),
+			expired,
+		}, false)
+		if err != nil {
+			t.Fatalf("Error importing reservations: %v", err)
+		}
+		if report.Imported != 3 || report.Failed != 1 {
+			t.Fatalf("Expected 3 imported and 1 failed, got %+v", report)
+		}
+		var validation *domain.ValidationError
+		if report.Results[3].Outcome != domain.ReservationImportFailed || report.Results[3].Error == "" {
+			t.Errorf("Expected expired PENDING hold rejected, got %+v", report.Results[3])
+		}
+		if err := expired.Validate(time.Now()); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for expired PENDING hold, got %v", err)
+		}
+
+		// Solo la PENDING incrementa Reserved
+		if reserved() != 6 {
+			t.Errorf("Expected 6 reserved, got %d", reserved())
+		}
+
+		pending, err := reservationRepo.GetByID(ctx, report.Results[0].ReservationID)
+		if err != nil {
+			t.Fatalf("Error getting imported reservation: %v", err)
+		}
+		if !pending.CreatedAt.Equal(createdAt) || !pending.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("Expected original timestamps, got created %v expires %v", pending.CreatedAt, pending.ExpiresAt)
+		}
+		if event := publisher.GetLastEvent(); event == nil || event.EventType != domain.EventReservationCreated {
+			t.Errorf("Expected reservation.created event, got %+v", event)
+		}
+
+		confirmed, _ := reservationRepo.GetByID(ctx, report.Results[1].ReservationID)
+		if confirmed.Status != domain.ReservationStatusConfirmed || confirmed.ConfirmedAt == nil {
+			t.Errorf("Expected CONFIRMED reservation with confirmed_at, got %+v", confirmed)
+		}
+	})
+
+	t.Run("ReimportSkipsExisting", func(t *testing.T) {
+		report, err := importService.ImportReservations(ctx, []*domain.ReservationImport{
+			hold("OMS-1", domain.ReservationStatusPending, 6),
+			hold("OMS-6", domain.ReservationStatusPending, 5), // Solo quedan 4 disponibles
+		}, false)
+		if err != nil {
+			t.Fatalf("Error importing reservations: %v", err)
+		}
+		if report.Skipped != 1 || report.Results[0].ReservationID == "" {
+			t.Errorf("Expected OMS-1 skipped with its reservation, got %+v", report.Results[0])
+		}
+		if report.Failed != 1 || report.Results[1].Outcome != domain.ReservationImportFailed {
+			t.Errorf("Expected OMS-6 rejected for insufficient stock, got %+v", report.Results[1])
+		}
+		if reserved() != 6 {
+			t.Errorf("Expected reserved unchanged at 6, got %d", reserved())
+		}
+	})
+}
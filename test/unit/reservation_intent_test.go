@@ -0,0 +1,135 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestReservationIntentService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewMockPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	intentRepo := repository.NewReservationIntentRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, eventRepo, publisher)
+	intentService := service.NewReservationIntentService(intentRepo, productRepo, stockService, reservationService, 15*time.Minute)
+
+	ctx := context.Background()
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(ctx, product.ID, "INT-001", 5); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+
+	newIntent := func(quantity int) *domain.ReservationIntent {
+		return &domain.ReservationIntent{ProductID: product.ID, StoreID: "INT-001", CustomerID: "customer-1", Quantity: quantity}
+	}
+	reserved := func() int {
+		stock, err := stockRepo.GetByProductAndStore(ctx, product.ID, "INT-001")
+		if err != nil {
+			t.Fatalf("Error reading stock: %v", err)
+		}
+		return stock.Reserved
+	}
+
+	t.Run("DoesNotLockStock", func(t *testing.T) {
+		intent, err := intentService.CreateIntent(ctx, newIntent(3))
+		if err != nil {
+			t.Fatalf("Error creating intent: %v", err)
+		}
+		if !intent.Available || intent.AvailableQuantity != 5 || intent.Status != domain.ReservationIntentActive {
+			t.Errorf("Expected active intent with 5 available, got %+v", intent)
+		}
+		if got := reserved(); got != 0 {
+			t.Errorf("Expected reserved to stay at 0, got %d", got)
+		}
+
+		unavailable, err := intentService.CreateIntent(ctx, newIntent(8))
+		if err != nil {
+			t.Fatalf("Expected intent recorded without stock, got %v", err)
+		}
+		if unavailable.Available {
+			t.Error("Expected available = false for 8 units")
+		}
+	})
+
+	t.Run("Convert", func(t *testing.T) {
+		intent, err := intentService.CreateIntent(ctx, newIntent(2))
+		if err != nil {
+			t.Fatalf("Error creating intent: %v", err)
+		}
+
+		reservation, err := intentService.ConvertIntent(ctx, intent.Token, 0)
+		if err != nil {
+			t.Fatalf("Error converting intent: %v", err)
+		}
+		if reservation.Quantity != 2 || reservation.Status != domain.ReservationStatusPending {
+			t.Errorf("Unexpected reservation: %+v", reservation)
+		}
+		if got := reserved(); got != 2 {
+			t.Errorf("Expected 2 reserved after conversion, got %d", got)
+		}
+
+		converted, err := intentService.GetIntent(ctx, intent.Token)
+		if err != nil || converted.Status != domain.ReservationIntentConverted || converted.ReservationID != reservation.ID {
+			t.Errorf("Expected CONVERTED intent linked to %s, got %+v (%v)", reservation.ID, converted, err)
+		}
+
+		var invalid *domain.InvalidStateError
+		if _, err := intentService.ConvertIntent(ctx, intent.Token, 0); !errors.As(err, &invalid) {
+			t.Errorf("Expected InvalidStateError on second conversion, got %v", err)
+		}
+	})
+
+	t.Run("FailedConversionStaysActive", func(t *testing.T) {
+		intent, err := intentService.CreateIntent(ctx, newIntent(4))
+		if err != nil {
+			t.Fatalf("Error creating intent: %v", err)
+		}
+
+		var insufficient *domain.InsufficientStockError
+		if _, err := intentService.ConvertIntent(ctx, intent.Token, 0); !errors.As(err, &insufficient) {
+			t.Fatalf("Expected InsufficientStockError with 3 available, got %v", err)
+		}
+
+		current, err := intentService.GetIntent(ctx, intent.Token)
+		if err != nil || current.Status != domain.ReservationIntentActive {
+			t.Errorf("Expected intent to remain ACTIVE, got %+v (%v)", current, err)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		expiring := service.NewReservationIntentService(intentRepo, productRepo, stockService, reservationService, time.Millisecond)
+		intent, err := expiring.CreateIntent(ctx, newIntent(1))
+		if err != nil {
+			t.Fatalf("Error creating intent: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		var invalid *domain.InvalidStateError
+		if _, err := expiring.ConvertIntent(ctx, intent.Token, 0); !errors.As(err, &invalid) || invalid.CurrentState != string(domain.ReservationIntentExpired) {
+			t.Errorf("Expected InvalidStateError from EXPIRED, got %v", err)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		var notFound *domain.NotFoundError
+		if _, err := intentService.GetIntent(ctx, "missing"); !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestReservationPickup_Code(t *testing.T) {
+	reservation := &domain.Reservation{ID: "res-1", StoreID: "MAD-001"}
+	now := time.Now()
+
+	pickup, err := domain.NewReservationPickup(reservation, now, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to generate pickup: %v", err)
+	}
+	if len(pickup.Code) != domain.PickupCodeDigits || !pickup.PickupBy.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("Unexpected pickup: %+v", pickup)
+	}
+	if !pickup.Matches(" "+pickup.Code+" ") || pickup.Matches("abcdef") {
+		t.Errorf("Expected the code to match only itself")
+	}
+	// El hash se sala con la reserva: el mismo código no vale para otra
+	other := &domain.ReservationPickup{ReservationID: "res-2", CodeHash: pickup.CodeHash}
+	if other.Matches(pickup.Code) {
+		t.Errorf("Expected the code hash to be bound to its reservation")
+	}
+}
+
+func TestReservationService_PickupWorkflow(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	silenceLogs(t)
+	stockRepo := repository.NewStockRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, repository.NewProductRepository(db), eventRepo, mocks.NewNoOpPublisher())
+	reservationService.SetPickupRepository(repository.NewReservationPickupRepository(db), time.Hour)
+	ctx := context.Background()
+
+	// Laptop en MAD-001: 10 unidades, ninguna reservada
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+	reservation, err := reservationService.CreateReservation(ctx, laptop, "MAD-001", "customer-1", 2, 30)
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	// Solo se recogen las reservas listas para recoger
+	var invalidState *domain.InvalidStateError
+	if _, _, err := reservationService.PickUpReservation(ctx, reservation.ID, "000000"); !errors.As(err, &invalidState) {
+		t.Fatalf("Expected InvalidStateError picking up a PENDING reservation, got %v", err)
+	}
+
+	ready, pickup, err := reservationService.MarkReadyForPickup(ctx, reservation.ID)
+	if err != nil {
+		t.Fatalf("Failed to mark ready for pickup: %v", err)
+	}
+	if ready.Status != domain.ReservationStatusReadyForPickup || pickup.Code == "" {
+		t.Fatalf("Expected READY_FOR_PICKUP with a code, got %s / %+v", ready.Status, pickup)
+	}
+	if stored, _ := reservationService.GetReservation(ctx, reservation.ID); stored.ExpiresAt.Before(time.Now().Add(50 * time.Minute)) {
+		t.Errorf("Expected the expiry moved to the pickup window, got %v", stored.ExpiresAt)
+	}
+	if err := reservationService.ConfirmReservation(ctx, reservation.ID); !errors.As(err, &invalidState) {
+		t.Errorf("Expected InvalidStateError confirming a reservation ready for pickup, got %v", err)
+	}
+
+	var validationErr *domain.ValidationError
+	wrong := "000000"
+	if pickup.Code == wrong {
+		wrong = "111111"
+	}
+	if _, _, err := reservationService.PickUpReservation(ctx, reservation.ID, wrong); !errors.As(err, &validationErr) || validationErr.Field != "code" {
+		t.Fatalf("Expected ValidationError on code, got %v", err)
+	}
+
+	picked, done, err := reservationService.PickUpReservation(ctx, reservation.ID, pickup.Code)
+	if err != nil {
+		t.Fatalf("Failed to pick up reservation: %v", err)
+	}
+	if picked.Status != domain.ReservationStatusPickedUp || picked.ConfirmedAt == nil || done.PickedUpAt == nil || done.Attempts != 1 {
+		t.Errorf("Unexpected picked up reservation: %+v / %+v", picked, done)
+	}
+	stock, _ := stockRepo.GetByProductAndStore(ctx, laptop, "MAD-001")
+	if stock.Quantity != 8 || stock.Reserved != 0 {
+		t.Errorf("Expected 8 units and none reserved after the pickup, got %d/%d", stock.Quantity, stock.Reserved)
+	}
+	events, _ := eventRepo.GetByAggregateID(ctx, reservation.ID)
+	types := map[string]bool{}
+	for _, event := range events {
+		types[event.EventType] = true
+	}
+	if !types[domain.EventReservationReady] || !types[domain.EventReservationPickedUp] {
+		t.Errorf("Expected ready_for_pickup and picked_up events, got %v", types)
+	}
+
+	// Tras MaxPickupCodeAttempts códigos erróneos la recogida se bloquea
+	locked, _ := reservationService.CreateReservation(ctx, laptop, "MAD-001", "customer-2", 1, 30)
+	lockedPickup := mustMarkReady(t, reservationService, locked.ID)
+	for i := 0; i < domain.MaxPickupCodeAttempts; i++ {
+		_, _, _ = reservationService.PickUpReservation(ctx, locked.ID, "wrong")
+	}
+	if _, _, err := reservationService.PickUpReservation(ctx, locked.ID, lockedPickup.Code); !errors.As(err, &invalidState) {
+		t.Errorf("Expected InvalidStateError after too many invalid codes, got %v", err)
+	}
+
+	// Sin recoger en el plazo, el worker de expiración la caduca y libera las unidades
+	if _, err := db.Exec(`UPDATE reservations SET expires_at = ? WHERE id = ?`, time.Now().Add(-time.Minute), locked.ID); err != nil {
+		t.Fatalf("Failed to move pickup deadline: %v", err)
+	}
+	if count, err := reservationService.ProcessExpiredReservations(ctx); err != nil || count != 1 {
+		t.Fatalf("Expected 1 expired pickup, got %d (%v)", count, err)
+	}
+	expired, _ := reservationService.GetReservation(ctx, locked.ID)
+	stock, _ = stockRepo.GetByProductAndStore(ctx, laptop, "MAD-001")
+	if expired.Status != domain.ReservationStatusExpired || stock.Reserved != 0 {
+		t.Errorf("Expected EXPIRED and the units released, got %s with %d reserved", expired.Status, stock.Reserved)
+	}
+}
+
+func mustMarkReady(t *testing.T, reservationService *service.ReservationService, reservationID string) *domain.ReservationPickup {
+	t.Helper()
+	_, pickup, err := reservationService.MarkReadyForPickup(context.Background(), reservationID)
+	if err != nil {
+		t.Fatalf("Failed to mark ready for pickup: %v", err)
+	}
+	return pickup
+}
+
+func TestReservationService_StatsCountPickups(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	silenceLogs(t)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), repository.NewStockRepository(db), repository.NewProductRepository(db), repository.NewEventRepository(db), mocks.NewNoOpPublisher())
+	reservationService.SetPickupRepository(repository.NewReservationPickupRepository(db), time.Hour)
+	ctx := context.Background()
+
+	// Sin recogidas los dos estados aparecen igualmente con 0
+	stats, err := reservationService.GetReservationStats(ctx, time.Hour, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, status := range []string{"PICKED_UP", "READY_FOR_PICKUP"} {
+		if count, ok := stats.ByStatus[status]; !ok || count != 0 {
+			t.Errorf("Expected %s key with 0, got %v", status, stats.ByStatus)
+		}
+	}
+
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+	var created []*domain.Reservation
+	for i := 0; i < 4; i++ {
+		reservation, err := reservationService.CreateReservation(ctx, laptop, "MAD-001", "customer-stats", 1, 30)
+		if err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+		created = append(created, reservation)
+	}
+
+	// Una confirmada, una recogida en tienda, una lista para recoger y una pendiente
+	if err := reservationService.ConfirmReservation(ctx, created[0].ID); err != nil {
+		t.Fatalf("Failed to confirm reservation: %v", err)
+	}
+	_, pickup, err := reservationService.MarkReadyForPickup(ctx, created[1].ID)
+	if err != nil {
+		t.Fatalf("Failed to mark ready for pickup: %v", err)
+	}
+	if _, _, err := reservationService.PickUpReservation(ctx, created[1].ID, pickup.Code); err != nil {
+		t.Fatalf("Failed to pick up reservation: %v", err)
+	}
+	if _, _, err := reservationService.MarkReadyForPickup(ctx, created[2].ID); err != nil {
+		t.Fatalf("Failed to mark ready for pickup: %v", err)
+	}
+
+	stats, err = reservationService.GetReservationStats(ctx, time.Hour, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if stats.ByStatus["PICKED_UP"] != 1 || stats.ByStatus["READY_FOR_PICKUP"] != 1 {
+		t.Errorf("Unexpected by_status counts: %v", stats.ByStatus)
+	}
+	if stats.Window.Confirmed != 2 || stats.Window.ConversionRate != 0.5 {
+		t.Errorf("Expected the pickup counted as a conversion (2, 0.5), got %d, %f", stats.Window.Confirmed, stats.Window.ConversionRate)
+	}
+	if stats.Window.AvgTimeToConfirmSeconds < 0 {
+		t.Errorf("Expected non-negative time to confirm, got %f", stats.Window.AvgTimeToConfirmSeconds)
+	}
+}
@@ -0,0 +1,187 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestReservationPriority(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewMockPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+
+	ctx := context.Background()
+	newProduct := func(storeID string, quantity int) string {
+		product := testutil.CreateTestProduct()
+		if err := productRepo.Create(ctx, product); err != nil {
+			t.Fatalf("Error creating product: %v", err)
+		}
+		if _, err := stockService.InitializeStock(ctx, product.ID, storeID, quantity); err != nil {
+			t.Fatalf("Error initializing stock: %v", err)
+		}
+		return product.ID
+	}
+	reserve := func(productID, storeID string, quantity int, priority domain.ReservationPriority) *domain.Reservation {
+		reservation, err := reservationService.CreateReservationWithPriority(ctx, productID, storeID, "customer-1", quantity, 0, priority)
+		if err != nil {
+			t.Fatalf("Error creating %s reservation: %v", priority, err)
+		}
+		return reservation
+	}
+	status := func(id string) domain.ReservationStatus {
+		reservation, err := reservationService.GetReservation(ctx, id)
+		if err != nil {
+			t.Fatalf("Error reading reservation: %v", err)
+		}
+		return reservation.Status
+	}
+
+	t.Run("DefaultsToNormal", func(t *testing.T) {
+		productID := newProduct("PRI-001", 5)
+		reservation, err := reservationService.CreateReservation(ctx, productID, "PRI-001", "customer-1", 1, 0)
+		if err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+		stored, err := reservationService.GetReservation(ctx, reservation.ID)
+		if err != nil || stored.Priority != domain.ReservationPriorityNormal {
+			t.Errorf("Expected NORMAL priority, got %+v (%v)", stored, err)
+		}
+
+		if _, err := domain.ParseReservationPriority("urgent"); err == nil {
+			t.Error("Expected ValidationError for unknown priority")
+		}
+	})
+
+	t.Run("ConflictResolutionReleasesLowestPriorityFirst", func(t *testing.T) {
+		conflictService := service.NewConflictService(repository.NewConflictRepository(db), stockRepo, eventRepo, publisher)
+		conflictService.SetReservationService(reservationService)
+
+		productID := newProduct("PRI-002", 6)
+		low := reserve(productID, "PRI-002", 2, domain.ReservationPriorityLow)
+		high := reserve(productID, "PRI-002", 2, domain.ReservationPriorityHigh)
+		normal := reserve(productID, "PRI-002", 2, domain.ReservationPriorityNormal)
+
+		stock, err := stockRepo.GetByProductAndStore(ctx, productID, "PRI-002")
+		if err != nil {
+			t.Fatalf("Error reading stock: %v", err)
+		}
+		conflict, err := conflictService.ApplyRemoteStockUpdate(ctx, &domain.RemoteStockUpdate{
+			ProductID:    productID,
+			StoreID:      "PRI-002",
+			BaseVersion:  stock.Version,
+			BaseQuantity: stock.Quantity,
+			NewQuantity:  3,
+		})
+		if err != nil || conflict == nil || conflict.Status != domain.ConflictStatusUnresolved {
+			t.Fatalf("Expected UNRESOLVED conflict, got %+v (%v)", conflict, err)
+		}
+
+		if _, err := conflictService.ResolveConflict(ctx, conflict.ID, 3, "recount"); err != nil {
+			t.Fatalf("Error resolving conflict: %v", err)
+		}
+
+		if status(low.ID) != domain.ReservationStatusCancelled || status(normal.ID) != domain.ReservationStatusCancelled {
+			t.Error("Expected LOW and NORMAL reservations to be released")
+		}
+		if status(high.ID) != domain.ReservationStatusPending {
+			t.Error("Expected HIGH reservation to be kept")
+		}
+
+		stock, _ = stockRepo.GetByProductAndStore(ctx, productID, "PRI-002")
+		if stock.Quantity != 3 || stock.Reserved != 2 {
+			t.Errorf("Expected quantity 3 / reserved 2, got %d / %d", stock.Quantity, stock.Reserved)
+		}
+	})
+
+	t.Run("ShortageExpiresLowPriorityHolds", func(t *testing.T) {
+		reservationService.SetLowPriorityShortageGrace(time.Millisecond)
+		defer reservationService.SetLowPriorityShortageGrace(0)
+
+		soldOut := newProduct("PRI-003", 4)
+		lowA := reserve(soldOut, "PRI-003", 1, domain.ReservationPriorityLow)
+		lowB := reserve(soldOut, "PRI-003", 1, domain.ReservationPriorityLow)
+		high := reserve(soldOut, "PRI-003", 2, domain.ReservationPriorityHigh)
+
+		inStock := newProduct("PRI-003", 5)
+		spare := reserve(inStock, "PRI-003", 1, domain.ReservationPriorityLow)
+
+		time.Sleep(10 * time.Millisecond)
+
+		processed, err := reservationService.ProcessExpiredReservations(ctx)
+		if err != nil {
+			t.Fatalf("Error processing reservations: %v", err)
+		}
+		if processed != 1 {
+			t.Errorf("Expected 1 reservation released (only until units are available), got %d", processed)
+		}
+		if status(lowA.ID) != domain.ReservationStatusExpired || status(lowB.ID) != domain.ReservationStatusPending {
+			t.Error("Expected only the oldest LOW reservation to expire")
+		}
+		if status(high.ID) != domain.ReservationStatusPending || status(spare.ID) != domain.ReservationStatusPending {
+			t.Error("Expected HIGH and in-stock LOW reservations to stay pending")
+		}
+	})
+
+	t.Run("ManualExpirationDryRun", func(t *testing.T) {
+		reservationService.SetLowPriorityShortageGrace(time.Millisecond)
+		defer reservationService.SetLowPriorityShortageGrace(0)
+
+		productID := newProduct("PRI-004", 4)
+		overdue := reserve(productID, "PRI-004", 1, domain.ReservationPriorityNormal)
+		low := reserve(productID, "PRI-004", 1, domain.ReservationPriorityLow)
+		reserve(productID, "PRI-004", 2, domain.ReservationPriorityHigh)
+		if _, err := db.Exec(`UPDATE reservations SET expires_at = ? WHERE id = ?`, time.Now().Add(-time.Minute), overdue.ID); err != nil {
+			t.Fatalf("Error backdating reservation: %v", err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		expiredIDs := func(run *domain.ReservationExpirationRun) map[string]domain.ExpirationReason {
+			ids := make(map[string]domain.ExpirationReason)
+			for _, r := range run.Reservations {
+				if r.ProductID == productID {
+					ids[r.ReservationID] = r.Reason
+				}
+			}
+			return ids
+		}
+
+		// El TTL libera una unidad, así que la reserva LOW ya no se expira por falta de stock
+		dry, err := reservationService.RunExpiration(ctx, true)
+		if err != nil {
+			t.Fatalf("Error running dry-run: %v", err)
+		}
+		planned := expiredIDs(dry)
+		if !dry.DryRun || len(planned) != 1 || planned[overdue.ID] != domain.ExpirationReasonTTL {
+			t.Errorf("Expected only the overdue reservation to be planned, got %v", planned)
+		}
+		if status(overdue.ID) != domain.ReservationStatusPending {
+			t.Error("Expected dry-run not to modify reservations")
+		}
+
+		run, err := reservationService.RunExpiration(ctx, false)
+		if err != nil {
+			t.Fatalf("Error running expiration: %v", err)
+		}
+		if done := expiredIDs(run); len(done) != 1 || done[overdue.ID] != domain.ExpirationReasonTTL || run.Failed != 0 {
+			t.Errorf("Expected real run to match dry-run, got %v (failed %d)", done, run.Failed)
+		}
+		if status(overdue.ID) != domain.ReservationStatusExpired || status(low.ID) != domain.ReservationStatusPending {
+			t.Error("Expected overdue reservation expired and LOW reservation kept")
+		}
+	})
+}
@@ -2,6 +2,7 @@ package unit
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -88,11 +89,23 @@ func TestReservationRepository_UpdateStatus(t *testing.T) {
 	}
 
 	// Actualizar a CONFIRMED
-	err = repo.UpdateStatus(ctx, reservation.ID, domain.ReservationStatusConfirmed)
+	err = repo.UpdateStatus(ctx, reservation.ID, domain.ReservationStatusPending, domain.ReservationStatusConfirmed)
 	if err != nil {
 		t.Fatalf("Failed to update status: %v", err)
 	}
 
+	// Compare-and-set: una segunda transición desde PENDING ya no aplica
+	err = repo.UpdateStatus(ctx, reservation.ID, domain.ReservationStatusPending, domain.ReservationStatusCancelled)
+	var invalidState *domain.InvalidStateError
+	if !errors.As(err, &invalidState) || invalidState.CurrentState != string(domain.ReservationStatusConfirmed) {
+		t.Errorf("Expected InvalidStateError from CONFIRMED, got %v", err)
+	}
+
+	err = repo.UpdateStatus(ctx, "missing", domain.ReservationStatusPending, domain.ReservationStatusConfirmed)
+	if _, ok := err.(*domain.NotFoundError); !ok {
+		t.Errorf("Expected NotFoundError, got %T", err)
+	}
+
 	// Verificar
 	updated, err := repo.GetByID(ctx, reservation.ID)
 	if err != nil {
@@ -440,3 +453,119 @@ func TestReservationRepository_CountByStatus(t *testing.T) {
 		t.Errorf("Expected at least 1 confirmed reservation, got %d", count)
 	}
 }
+
+func TestReservationRepository_ListAndCount(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := repository.NewReservationRepository(db)
+	ctx := context.Background()
+
+	productID := "550e8400-e29b-41d4-a716-446655440000"
+	storeID := "SEV-001"
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	// 5 reservas: creadas cada minuto, expiran en orden inverso; las pares son de customer-a
+	for i := 0; i < 5; i++ {
+		customer := "customer-b"
+		if i%2 == 0 {
+			customer = "customer-a"
+		}
+		r := &domain.Reservation{
+			ID:         "list-" + string(rune('a'+i)),
+			ProductID:  productID,
+			StoreID:    storeID,
+			CustomerID: customer,
+			Quantity:   1,
+			Status:     domain.ReservationStatusPending,
+			ExpiresAt:  base.Add(time.Duration(60-i) * time.Minute),
+			CreatedAt:  base.Add(time.Duration(i) * time.Minute),
+		}
+		if err := repo.Create(ctx, r); err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+	}
+
+	filter := domain.ReservationFilter{
+		StoreID:  storeID,
+		SortBy:   domain.ReservationSortCreatedAt,
+		SortDesc: true,
+		Limit:    2,
+		Offset:   1,
+	}
+	page, err := repo.List(ctx, filter)
+	if err != nil {
+		t.Fatalf("Failed to list reservations: %v", err)
+	}
+	if len(page) != 2 || page[0].ID != "list-d" || page[1].ID != "list-c" {
+		t.Fatalf("Unexpected page: %v", reservationIDs(page))
+	}
+	if page[0].CustomerID != "customer-b" {
+		t.Errorf("Expected customer_id to be loaded, got %q", page[0].CustomerID)
+	}
+
+	total, err := repo.Count(ctx, filter)
+	if err != nil {
+		t.Fatalf("Failed to count reservations: %v", err)
+	}
+	if total != 5 {
+		t.Errorf("Expected total 5, got %d", total)
+	}
+
+	// Orden por expiración ascendente: la última creada expira primero
+	byExpiry, _ := repo.List(ctx, domain.ReservationFilter{StoreID: storeID, SortBy: domain.ReservationSortExpiresAt, Limit: 1})
+	if len(byExpiry) != 1 || byExpiry[0].ID != "list-e" {
+		t.Errorf("Expected list-e first by expires_at, got %v", reservationIDs(byExpiry))
+	}
+
+	// Filtros por cliente y rango de creación [base+1m, base+4m)
+	from := base.Add(time.Minute)
+	to := base.Add(4 * time.Minute)
+	filtered := domain.ReservationFilter{StoreID: storeID, CustomerID: "customer-a", CreatedFrom: &from, CreatedTo: &to}
+	results, err := repo.List(ctx, filtered)
+	if err != nil {
+		t.Fatalf("Failed to list filtered reservations: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "list-c" {
+		t.Errorf("Expected only list-c, got %v", reservationIDs(results))
+	}
+	if n, _ := repo.Count(ctx, filtered); n != 1 {
+		t.Errorf("Expected filtered total 1, got %d", n)
+	}
+}
+
+func TestReservationFilter_Validate(t *testing.T) {
+	filter := domain.ReservationFilter{}
+	if err := filter.Validate(); err != nil {
+		t.Fatalf("Expected empty filter to be valid: %v", err)
+	}
+	if filter.Limit != domain.DefaultReservationListLimit || filter.SortBy != domain.ReservationSortCreatedAt {
+		t.Errorf("Expected defaults to be applied, got limit=%d sort=%s", filter.Limit, filter.SortBy)
+	}
+
+	unknown := domain.ReservationStatus("SHIPPED")
+	from := time.Now()
+	to := from.Add(-time.Hour)
+	invalid := []domain.ReservationFilter{
+		{Status: &unknown},
+		{SortBy: "quantity"},
+		{Limit: domain.MaxReservationListLimit + 1},
+		{Offset: -1},
+		{CreatedFrom: &from, CreatedTo: &to},
+	}
+	for _, f := range invalid {
+		if err := f.Validate(); err == nil {
+			t.Errorf("Expected validation error for %+v", f)
+		} else if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %T", err)
+		}
+	}
+}
+
+func reservationIDs(reservations []*domain.Reservation) []string {
+	ids := make([]string, 0, len(reservations))
+	for _, r := range reservations {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
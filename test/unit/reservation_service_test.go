@@ -0,0 +1,372 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func newTestReservationService(t *testing.T) (*service.ReservationService, *repository.StockRepository, func()) {
+	t.Helper()
+
+	db := testutil.SetupTestDB(t)
+
+	stockRepo := repository.NewStockRepository(db)
+	reservationService := service.NewReservationService(
+		repository.NewReservationRepository(db),
+		stockRepo,
+		repository.NewProductRepository(db),
+		repository.NewEventRepository(db),
+		mocks.NewNoOpPublisher(),
+	)
+
+	return reservationService, stockRepo, func() { db.Close() }
+}
+
+func TestReservationService_GetReservationStats(t *testing.T) {
+	reservationService, _, cleanup := newTestReservationService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	productID := "550e8400-e29b-41d4-a716-446655440001"
+
+	var created []*domain.Reservation
+	for i := 0; i < 4; i++ {
+		storeID := "MAD-001"
+		if i == 3 {
+			storeID = "BCN-001"
+		}
+		r, err := reservationService.CreateReservation(ctx, productID, storeID, "CUST-STATS", 1, 15)
+		if err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+		created = append(created, r)
+	}
+
+	if err := reservationService.ConfirmReservation(ctx, created[0].ID); err != nil {
+		t.Fatalf("Error confirming reservation: %v", err)
+	}
+	if err := reservationService.CancelReservation(ctx, created[1].ID); err != nil {
+		t.Fatalf("Error cancelling reservation: %v", err)
+	}
+
+	stats, err := reservationService.GetReservationStats(ctx, time.Hour, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if stats.TotalReservations != 4 {
+		t.Errorf("Expected total 4, got %d", stats.TotalReservations)
+	}
+	if stats.PendingReservations != 2 || stats.ConfirmedReservations != 1 || stats.CancelledReservations != 1 {
+		t.Errorf("Unexpected counts: %+v", stats)
+	}
+	if stats.ByStatus["EXPIRED"] != 0 {
+		t.Errorf("Expected EXPIRED key with 0, got %d", stats.ByStatus["EXPIRED"])
+	}
+	if stats.ByStore["MAD-001"] != 3 || stats.ByStore["BCN-001"] != 1 {
+		t.Errorf("Unexpected by_store counts: %v", stats.ByStore)
+	}
+	if stats.Window.Created != 4 {
+		t.Errorf("Expected 4 reservations in window, got %d", stats.Window.Created)
+	}
+	if stats.Window.ConversionRate != 0.25 {
+		t.Errorf("Expected conversion rate 0.25, got %f", stats.Window.ConversionRate)
+	}
+	if stats.Window.AvgTimeToConfirmSeconds < 0 {
+		t.Errorf("Expected non-negative time to confirm, got %f", stats.Window.AvgTimeToConfirmSeconds)
+	}
+}
+
+func TestReservationService_TTLPolicy(t *testing.T) {
+	reservationService, _, cleanup := newTestReservationService(t)
+	defer cleanup()
+
+	reservationService.SetTTLPolicy(domain.ReservationTTLPolicy{
+		ReservationTTLBounds: domain.ReservationTTLBounds{DefaultMinutes: 20, MaxMinutes: 60},
+		StoreOverrides: map[string]domain.ReservationTTLBounds{
+			"BCN-001": {DefaultMinutes: 5},
+		},
+	})
+
+	ctx := context.Background()
+	productID := "550e8400-e29b-41d4-a716-446655440001"
+
+	t.Run("OmittedTTL_UsesDefault", func(t *testing.T) {
+		reservation, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "CUST-TTL", 1, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		ttl := reservation.ExpiresAt.Sub(reservation.CreatedAt)
+		if ttl < 19*time.Minute || ttl > 21*time.Minute {
+			t.Errorf("Expected ~20 minutes TTL, got %v", ttl)
+		}
+	})
+
+	t.Run("OmittedTTL_UsesStoreOverride", func(t *testing.T) {
+		reservation, err := reservationService.CreateReservation(ctx, productID, "BCN-001", "CUST-TTL", 1, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		ttl := reservation.ExpiresAt.Sub(reservation.CreatedAt)
+		if ttl < 4*time.Minute || ttl > 6*time.Minute {
+			t.Errorf("Expected ~5 minutes TTL, got %v", ttl)
+		}
+	})
+
+	t.Run("TTLAboveMax_Fails", func(t *testing.T) {
+		_, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "CUST-TTL", 1, 90)
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+}
+
+func TestReservationService_ConfirmedEventPayload(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewMockPublisher()
+	reservationService := service.NewReservationService(
+		repository.NewReservationRepository(db),
+		repository.NewStockRepository(db),
+		repository.NewProductRepository(db),
+		repository.NewEventRepository(db),
+		publisher,
+	)
+	reservationService.SetStoreRepository(repository.NewStoreRepository(db))
+	ctx := context.Background()
+
+	// PROD-001 (599.99) en BCN-001
+	reservation, err := reservationService.CreateReservation(ctx, "550e8400-e29b-41d4-a716-446655440000", "BCN-001", "CUST-SALE", 2, 15)
+	if err != nil {
+		t.Fatalf("Error creating reservation: %v", err)
+	}
+	if err := reservationService.ConfirmReservationWithReference(ctx, reservation.ID, "TICKET-42"); err != nil {
+		t.Fatalf("Error confirming reservation: %v", err)
+	}
+
+	events := publisher.GetEventsByType("reservation.confirmed")
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 reservation.confirmed event, got %d", len(events))
+	}
+
+	var payload domain.ReservationConfirmedPayload
+	if err := json.Unmarshal([]byte(events[0].Payload), &payload); err != nil {
+		t.Fatalf("Invalid payload: %v", err)
+	}
+
+	if payload.SchemaVersion != domain.ReservationConfirmedSchemaVersion {
+		t.Errorf("Expected schema_version %d, got %d", domain.ReservationConfirmedSchemaVersion, payload.SchemaVersion)
+	}
+	if payload.CustomerID != "CUST-SALE" || payload.ReferenceID != "TICKET-42" || payload.SKU != "PROD-001" {
+		t.Errorf("Unexpected sale fields: %+v", payload)
+	}
+	if payload.UnitPrice != 599.99 || payload.TotalPrice != 599.99*2 {
+		t.Errorf("Expected unit price 599.99 and total %.2f, got %.2f / %.2f", 599.99*2, payload.UnitPrice, payload.TotalPrice)
+	}
+	if payload.Store.ID != "BCN-001" || payload.Store.City != "Barcelona" || payload.Store.Name == "" {
+		t.Errorf("Expected BCN-001 store metadata, got %+v", payload.Store)
+	}
+	if payload.Quantity != 2 || payload.ReservationID != reservation.ID {
+		t.Errorf("Expected v1 fields preserved, got %+v", payload)
+	}
+}
+
+func TestReservationService_CustomerHoldLimits(t *testing.T) {
+	reservationService, stockRepo, cleanup := newTestReservationService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	productID := "550e8400-e29b-41d4-a716-446655440001"
+	otherProductID := "550e8400-e29b-41d4-a716-446655440002"
+	reservationService.SetCustomerHoldLimits(domain.CustomerHoldLimits{
+		MaxUnitsPerProduct:     3,
+		MaxPendingReservations: 3,
+	})
+
+	// 2 unidades en Madrid + 1 en Barcelona = límite de unidades del producto
+	if _, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "BOT-1", 2, 15); err != nil {
+		t.Fatalf("Expected first reservation to succeed, got %v", err)
+	}
+	if _, err := reservationService.CreateReservation(ctx, productID, "BCN-001", "BOT-1", 1, 15); err != nil {
+		t.Fatalf("Expected second reservation to succeed, got %v", err)
+	}
+
+	before, _ := stockRepo.GetByProductAndStore(ctx, productID, "VAL-001")
+	_, err := reservationService.CreateReservation(ctx, productID, "VAL-001", "BOT-1", 1, 15)
+	limitErr, ok := err.(*domain.CustomerLimitError)
+	if !ok || limitErr.Limit != domain.CustomerLimitProductUnits || limitErr.Current != 3 {
+		t.Fatalf("Expected units limit error, got %v", err)
+	}
+	after, _ := stockRepo.GetByProductAndStore(ctx, productID, "VAL-001")
+	if after.Reserved != before.Reserved {
+		t.Errorf("Expected rejected reservation not to hold stock, reserved %d → %d", before.Reserved, after.Reserved)
+	}
+
+	// Otro cliente no se ve afectado
+	if _, err := reservationService.CreateReservation(ctx, productID, "VAL-001", "CUST-OK", 3, 15); err != nil {
+		t.Errorf("Expected other customer to reserve, got %v", err)
+	}
+
+	// Tercera reserva pendiente (otro producto) permitida, la cuarta no
+	if _, err := reservationService.CreateReservation(ctx, otherProductID, "MAD-001", "BOT-1", 1, 15); err != nil {
+		t.Fatalf("Expected third pending reservation to succeed, got %v", err)
+	}
+	_, err = reservationService.CreateReservation(ctx, otherProductID, "BCN-001", "BOT-1", 1, 15)
+	if limitErr, ok := err.(*domain.CustomerLimitError); !ok || limitErr.Limit != domain.CustomerLimitPendingReservations {
+		t.Errorf("Expected pending reservations limit error, got %v", err)
+	}
+}
+
+func TestReservationService_CustomerHoldLimitsConcurrent(t *testing.T) {
+	reservationService, _, cleanup := newTestReservationService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	productID := "550e8400-e29b-41d4-a716-446655440001"
+	reservationService.SetCustomerHoldLimits(domain.CustomerHoldLimits{MaxUnitsPerProduct: 3})
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "BOT-2", 1, 15); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 3 {
+		t.Errorf("Expected exactly 3 concurrent reservations within the limit, got %d", succeeded)
+	}
+}
+
+func TestReservationService_ConcurrentConfirmAndCancel(t *testing.T) {
+	reservationService, stockRepo, cleanup := newTestReservationService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	productID := "550e8400-e29b-41d4-a716-446655440001"
+
+	before, err := stockRepo.GetByProductAndStore(ctx, productID, "MAD-001")
+	if err != nil {
+		t.Fatalf("Error getting stock: %v", err)
+	}
+
+	confirmed := 0
+	for i := 0; i < 10; i++ {
+		reservation, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "CUST-RACE", 1, 15)
+		if err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+
+		var (
+			wg                    sync.WaitGroup
+			confirmErr, cancelErr error
+		)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			confirmErr = reservationService.ConfirmReservation(ctx, reservation.ID)
+		}()
+		go func() {
+			defer wg.Done()
+			cancelErr = reservationService.CancelReservation(ctx, reservation.ID)
+		}()
+		wg.Wait()
+
+		// Solo una de las dos transiciones se aplica; la otra ve el estado que dejó la primera
+		if (confirmErr == nil) == (cancelErr == nil) {
+			t.Fatalf("Expected exactly one of confirm/cancel to win, got confirm=%v cancel=%v", confirmErr, cancelErr)
+		}
+		loser := confirmErr
+		if loser == nil {
+			loser = cancelErr
+			confirmed++
+		}
+		if _, ok := loser.(*domain.InvalidStateError); !ok {
+			t.Errorf("Expected InvalidStateError for the losing transition, got %v", loser)
+		}
+	}
+
+	// Las reservas ya no retienen nada y solo las confirmadas descuentan quantity
+	after, err := stockRepo.GetByProductAndStore(ctx, productID, "MAD-001")
+	if err != nil {
+		t.Fatalf("Error getting stock: %v", err)
+	}
+	if after.Reserved != before.Reserved {
+		t.Errorf("Expected reserved %d after the races, got %d", before.Reserved, after.Reserved)
+	}
+	if after.Quantity != before.Quantity-confirmed {
+		t.Errorf("Expected quantity %d after %d confirmations, got %d", before.Quantity-confirmed, confirmed, after.Quantity)
+	}
+}
+
+func TestReservationService_StateTransitions(t *testing.T) {
+	reservationService, _, cleanup := newTestReservationService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	productID := "550e8400-e29b-41d4-a716-446655440001"
+
+	var transitions []string
+	record := func(ctx context.Context, tr *domain.ReservationTransition) {
+		transitions = append(transitions, string(tr.From)+"->"+string(tr.To))
+	}
+	reservationService.OnTransition(domain.ReservationStatusConfirmed, record)
+	reservationService.OnTransition(domain.ReservationStatusCancelled, record)
+
+	confirmed, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "CUST-STATE", 1, 15)
+	if err != nil {
+		t.Fatalf("Error creating reservation: %v", err)
+	}
+	if err := reservationService.ConfirmReservation(ctx, confirmed.ID); err != nil {
+		t.Fatalf("Error confirming reservation: %v", err)
+	}
+
+	// Los estados finales no admiten más transiciones
+	for name, apply := range map[string]func(context.Context, string) error{
+		"confirm": reservationService.ConfirmReservation,
+		"cancel":  reservationService.CancelReservation,
+	} {
+		err := apply(ctx, confirmed.ID)
+		if _, ok := err.(*domain.InvalidStateError); !ok {
+			t.Errorf("Expected InvalidStateError on %s of a CONFIRMED reservation, got %v", name, err)
+		}
+	}
+
+	cancelled, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "CUST-STATE", 1, 15)
+	if err != nil {
+		t.Fatalf("Error creating reservation: %v", err)
+	}
+	if err := reservationService.CancelReservation(ctx, cancelled.ID); err != nil {
+		t.Fatalf("Error cancelling reservation: %v", err)
+	}
+
+	if len(transitions) != 2 || transitions[0] != "PENDING->CONFIRMED" || transitions[1] != "PENDING->CANCELLED" {
+		t.Errorf("Expected hooks for the two applied transitions only, got %v", transitions)
+	}
+
+	for _, status := range []domain.ReservationStatus{domain.ReservationStatusConfirmed, domain.ReservationStatusCancelled, domain.ReservationStatusExpired} {
+		if !status.IsFinal() || domain.ReservationStatusPending.IsFinal() || !domain.ReservationStatusPending.CanTransitionTo(status) {
+			t.Errorf("Unexpected transitions for %s", status)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestReservedReconciliation(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewMockPublisher()
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, repository.NewProductRepository(db), eventRepo, mocks.NewNoOpPublisher())
+	reconciliationService := service.NewReservedReconciliationService(stockRepo, eventRepo, publisher)
+
+	ctx := context.Background()
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+
+	if _, err := reservationService.CreateReservation(ctx, laptop, "MAD-001", "customer-reconcile", 4, 30); err != nil {
+		t.Fatalf("Error creating reservation: %v", err)
+	}
+	// Deriva: reserved sube sin reserva que lo respalde
+	if _, err := db.Exec(`UPDATE stock SET reserved = 9 WHERE product_id = ? AND store_id = 'MAD-001'`, laptop); err != nil {
+		t.Fatalf("Failed to update stock: %v", err)
+	}
+
+	t.Run("DryRun", func(t *testing.T) {
+		// Los datos de prueba traen reserved sin reservas en otras 3 filas de MAD-001
+		result, err := reconciliationService.ReconcileReserved(ctx, "MAD-001", false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Checked != 5 || len(result.Discrepancies) != 4 || result.Corrected != 0 {
+			t.Fatalf("Expected 5 rows checked and 4 discrepancies, got %+v", result)
+		}
+
+		var found *domain.ReservedDiscrepancy
+		for _, d := range result.Discrepancies {
+			if d.ProductID == laptop {
+				found = d
+			}
+		}
+		if found == nil || found.Reserved != 9 || found.Expected != 4 || found.Difference != 5 || found.Corrected {
+			t.Errorf("Expected laptop 9 reserved vs 4 pending, got %+v", found)
+		}
+
+		stock, _ := stockRepo.GetByProductAndStore(ctx, laptop, "MAD-001")
+		if stock.Reserved != 9 {
+			t.Errorf("Expected dry run to leave reserved at 9, got %d", stock.Reserved)
+		}
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		result, err := reconciliationService.ReconcileReserved(ctx, "MAD-001", true)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Corrected != 4 {
+			t.Errorf("Expected 4 rows corrected, got %+v", result)
+		}
+
+		stock, _ := stockRepo.GetByProductAndStore(ctx, laptop, "MAD-001")
+		if stock.Reserved != 4 {
+			t.Errorf("Expected reserved corrected to 4, got %d", stock.Reserved)
+		}
+
+		if publisher.Count() != 4 {
+			t.Fatalf("Expected 4 events, got %d", publisher.Count())
+		}
+		payload, err := domain.DecodeEventPayload(publisher.GetLastEvent())
+		if err != nil {
+			t.Fatalf("Expected valid payload, got %v", err)
+		}
+		if _, ok := payload.(*domain.StockReservedCorrectedPayload); !ok {
+			t.Errorf("Expected stock.reserved_corrected payload, got %T", payload)
+		}
+
+		result, err = reconciliationService.ReconcileReserved(ctx, "MAD-001", false)
+		if err != nil || len(result.Discrepancies) != 0 {
+			t.Errorf("Expected no discrepancies after applying, got %+v (%v)", result, err)
+		}
+	})
+
+	t.Run("SkipsConcurrentChange", func(t *testing.T) {
+		// reserved ya no vale lo observado en el informe: no se toca
+		_, corrected, err := stockRepo.CorrectReserved(ctx, laptop, "BCN-001", 7)
+		if err != nil || corrected {
+			t.Errorf("Expected stale correction skipped, got %v (%v)", corrected, err)
+		}
+
+		stock, _ := stockRepo.GetByProductAndStore(ctx, laptop, "BCN-001")
+		if stock.Reserved != 2 {
+			t.Errorf("Expected reserved unchanged at 2, got %d", stock.Reserved)
+		}
+	})
+}
@@ -0,0 +1,329 @@
+package unit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+)
+
+func TestRetentionService_Events(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	eventRepo := repository.NewEventRepository(db)
+	auditService := service.NewAuditService(eventRepo)
+	ctx := context.Background()
+
+	// 1-3 publicados y antiguos, 4 pendiente y antiguo, 5-6 publicados y recientes
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	var events []*domain.Event
+	for i := 0; i < 6; i++ {
+		event := domain.NewStockUpdatedEvent("550e8400-e29b-41d4-a716-446655440000", "MAD-001", i, i+1)
+		if i < 4 {
+			event.CreatedAt = old
+		}
+		if err := eventRepo.Save(ctx, event); err != nil {
+			t.Fatalf("Error saving event: %v", err)
+		}
+		if i != 3 {
+			if err := eventRepo.MarkAsSynced(ctx, event.ID); err != nil {
+				t.Fatalf("Error marking event as synced: %v", err)
+			}
+		}
+		events = append(events, event)
+	}
+
+	archiveDir := t.TempDir()
+	retentionService := service.NewRetentionService(eventRepo, repository.NewReservationRepository(db), []domain.RetentionPolicy{
+		{Table: domain.RetentionEvents, MaxAge: 30 * 24 * time.Hour},
+	})
+	retentionService.SetArchiveStore(infrastructure.NewLocalBlobStore(archiveDir, ""))
+
+	t.Run("DeletesOnlyChainPrefixBeforePendingEvent", func(t *testing.T) {
+		deleted, err := retentionService.ApplyRetention(ctx)
+		if err != nil {
+			t.Fatalf("Error applying retention: %v", err)
+		}
+		if deleted[domain.RetentionEvents] != 3 {
+			t.Fatalf("Expected 3 deleted events, got %d", deleted[domain.RetentionEvents])
+		}
+
+		var remaining int
+		db.QueryRow(`SELECT COUNT(*) FROM events`).Scan(&remaining)
+		if remaining != 3 {
+			t.Errorf("Expected 3 remaining events, got %d", remaining)
+		}
+
+		checkpoint, err := eventRepo.GetAuditCheckpoint(ctx)
+		if err != nil || checkpoint == nil || checkpoint.Seq != 3 || checkpoint.Hash != events[2].Hash {
+			t.Errorf("Expected checkpoint at seq 3, got %+v (err %v)", checkpoint, err)
+		}
+	})
+
+	t.Run("ChainStaysVerifiable", func(t *testing.T) {
+		result, err := auditService.VerifyChain(ctx, 0, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !result.Valid || result.FromSeq != 4 || result.Checked != 3 {
+			t.Errorf("Expected valid chain from seq 4 with 3 records, got %+v", result)
+		}
+
+		// El borrado de un registro conservado sigue detectándose como hueco
+		if _, err := db.Exec(`DELETE FROM events WHERE seq = 5`); err != nil {
+			t.Fatalf("Error deleting event: %v", err)
+		}
+		result, _ = auditService.VerifyChain(ctx, 0, 0)
+		if result.Valid {
+			t.Errorf("Expected gap to be reported")
+		}
+	})
+
+	t.Run("ArchivesDeletedEvents", func(t *testing.T) {
+		archived := readRetentionArchive(t, archiveDir, domain.RetentionEvents)
+		if len(archived) != 3 {
+			t.Fatalf("Expected 3 archived events, got %d", len(archived))
+		}
+
+		var event domain.Event
+		if err := json.Unmarshal([]byte(archived[0]), &event); err != nil {
+			t.Fatalf("Error decoding archived event: %v", err)
+		}
+		if event.ID != events[0].ID || event.Hash != events[0].Hash {
+			t.Errorf("Expected first archived event %s, got %+v", events[0].ID, event)
+		}
+
+		stats := retentionService.Stats()
+		if len(stats) != 1 || stats[0].Deleted != 3 || stats[0].Archived != 3 || stats[0].Files != 1 || stats[0].LastRunAt == nil {
+			t.Errorf("Unexpected retention stats: %+v", stats)
+		}
+
+		metrics := handler.FormatRetentionMetrics(stats)
+		if !strings.Contains(metrics, `inventory_retention_deleted_rows_total{table="events"} 3`) {
+			t.Errorf("Expected deleted rows metric, got:\n%s", metrics)
+		}
+	})
+}
+
+func TestRetentionService_KeepsChainHead(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	eventRepo := repository.NewEventRepository(db)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		event := domain.NewStockUpdatedEvent("550e8400-e29b-41d4-a716-446655440000", "MAD-001", i, i+1)
+		event.CreatedAt = time.Now().Add(-48 * time.Hour)
+		if err := eventRepo.Save(ctx, event); err != nil {
+			t.Fatalf("Error saving event: %v", err)
+		}
+		if err := eventRepo.MarkAsSynced(ctx, event.ID); err != nil {
+			t.Fatalf("Error marking event as synced: %v", err)
+		}
+	}
+
+	retentionService := service.NewRetentionService(eventRepo, repository.NewReservationRepository(db), []domain.RetentionPolicy{
+		{Table: domain.RetentionEvents, MaxAge: 24 * time.Hour},
+	})
+	deleted, err := retentionService.ApplyRetention(ctx)
+	if err != nil {
+		t.Fatalf("Error applying retention: %v", err)
+	}
+	if deleted[domain.RetentionEvents] != 2 {
+		t.Fatalf("Expected 2 deleted events, got %d", deleted[domain.RetentionEvents])
+	}
+
+	// Los eventos nuevos siguen encadenándose tras la cabeza conservada
+	event := domain.NewStockUpdatedEvent("550e8400-e29b-41d4-a716-446655440000", "MAD-001", 3, 4)
+	if err := eventRepo.Save(ctx, event); err != nil {
+		t.Fatalf("Error saving event: %v", err)
+	}
+	if event.Seq != 4 {
+		t.Errorf("Expected seq 4 after retention, got %d", event.Seq)
+	}
+
+	result, err := service.NewAuditService(eventRepo).VerifyChain(ctx, 0, 0)
+	if err != nil || !result.Valid || result.Checked != 2 {
+		t.Errorf("Expected valid chain with 2 records, got %+v (err %v)", result, err)
+	}
+}
+
+func TestRetentionService_Reservations(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	reservationRepo := repository.NewReservationRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	old := now.Add(-100 * 24 * time.Hour)
+	reservations := []struct {
+		id        string
+		status    domain.ReservationStatus
+		updatedAt time.Time
+	}{
+		{"res-retention-confirmed", domain.ReservationStatusConfirmed, old},
+		{"res-retention-expired", domain.ReservationStatusExpired, old},
+		{"res-retention-pending", domain.ReservationStatusPending, old},
+		{"res-retention-recent", domain.ReservationStatusCancelled, now},
+	}
+	for _, r := range reservations {
+		err := reservationRepo.Create(ctx, &domain.Reservation{
+			ID:        r.id,
+			ProductID: "550e8400-e29b-41d4-a716-446655440000",
+			StoreID:   "MAD-001",
+			Quantity:  1,
+			Status:    r.status,
+			ExpiresAt: r.updatedAt.Add(15 * time.Minute),
+			CreatedAt: r.updatedAt,
+			UpdatedAt: testutil.PtrTime(r.updatedAt),
+		})
+		if err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+	}
+
+	archiveDir := t.TempDir()
+	retentionService := service.NewRetentionService(repository.NewEventRepository(db), reservationRepo, []domain.RetentionPolicy{
+		{Table: domain.RetentionEvents, MaxAge: 0},
+		{Table: domain.RetentionReservations, MaxAge: 90 * 24 * time.Hour},
+	})
+	retentionService.SetArchiveStore(infrastructure.NewLocalBlobStore(archiveDir, ""))
+
+	if _, err := retentionService.ApplyRetention(ctx); err != nil {
+		t.Fatalf("Error applying retention: %v", err)
+	}
+
+	for _, r := range reservations {
+		_, err := reservationRepo.GetByID(ctx, r.id)
+		kept := err == nil
+		terminalAndOld := r.status != domain.ReservationStatusPending && r.updatedAt.Equal(old)
+		if kept == terminalAndOld {
+			t.Errorf("Reservation %s (%s): expected kept=%v", r.id, r.status, !terminalAndOld)
+		}
+	}
+
+	archived := strings.Join(readRetentionArchive(t, archiveDir, domain.RetentionReservations), "\n")
+	if !strings.Contains(archived, "res-retention-confirmed") || !strings.Contains(archived, "res-retention-expired") {
+		t.Errorf("Expected deleted reservations in the archive, got %s", archived)
+	}
+
+	if stats := retentionService.Stats(); len(stats) != 1 || stats[0].Table != domain.RetentionReservations {
+		t.Errorf("Expected stats only for reservations, got %+v", stats)
+	}
+}
+
+func TestRetentionService_Cleanup(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	eventRepo := repository.NewEventRepository(db)
+	ctx := context.Background()
+
+	// 5 publicados y antiguos + la cabeza de la cadena, que nunca se borra
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	for i := 0; i < 6; i++ {
+		event := domain.NewStockUpdatedEvent("550e8400-e29b-41d4-a716-446655440000", "MAD-001", i, i+1)
+		event.CreatedAt = old
+		if err := eventRepo.Save(ctx, event); err != nil {
+			t.Fatalf("Error saving event: %v", err)
+		}
+		if err := eventRepo.MarkAsSynced(ctx, event.ID); err != nil {
+			t.Fatalf("Error marking event as synced: %v", err)
+		}
+	}
+
+	// Sin ventana de retención: la limpieza bajo demanda no depende del worker
+	retentionService := service.NewRetentionService(eventRepo, repository.NewReservationRepository(db), nil)
+	retentionService.SetCleanupLimits(3, time.Millisecond)
+	request := domain.CleanupRequest{Table: domain.RetentionEvents, OlderThan: time.Now().Add(-30 * 24 * time.Hour), BatchSize: 2}
+
+	t.Run("RejectsInvalidRequests", func(t *testing.T) {
+		for _, invalid := range []domain.CleanupRequest{
+			{Table: "products", OlderThan: request.OlderThan, BatchSize: 2},
+			{Table: domain.RetentionEvents, OlderThan: request.OlderThan, BatchSize: 4},
+			{Table: domain.RetentionEvents, OlderThan: time.Now().Add(time.Hour), BatchSize: 2},
+		} {
+			if _, err := retentionService.Cleanup(ctx, invalid); err == nil {
+				t.Errorf("Expected a validation error for %+v", invalid)
+			}
+		}
+	})
+
+	t.Run("DryRunOnlyCounts", func(t *testing.T) {
+		dryRun := request
+		dryRun.DryRun = true
+		result, err := retentionService.Cleanup(ctx, dryRun)
+		if err != nil {
+			t.Fatalf("Error running cleanup: %v", err)
+		}
+		if result.Matched != 5 || result.Deleted != 0 || result.Remaining != 5 {
+			t.Errorf("Expected 5 matched and nothing deleted, got %+v", result)
+		}
+	})
+
+	t.Run("StopsAfterMaxBatches", func(t *testing.T) {
+		limited := request
+		limited.MaxBatches = 2
+		result, err := retentionService.Cleanup(ctx, limited)
+		if err != nil {
+			t.Fatalf("Error running cleanup: %v", err)
+		}
+		if result.Deleted != 4 || result.Batches != 2 || result.Remaining != 1 {
+			t.Errorf("Expected 4 deleted in 2 batches and 1 remaining, got %+v", result)
+		}
+
+		result, err = retentionService.Cleanup(ctx, request)
+		if err != nil || result.Deleted != 1 || result.Remaining != 0 {
+			t.Errorf("Expected the last event deleted, got %+v (%v)", result, err)
+		}
+
+		verification, err := service.NewAuditService(eventRepo).VerifyChain(ctx, 0, 0)
+		if err != nil || !verification.Valid || verification.Checked != 1 {
+			t.Errorf("Expected the chain to stay verifiable, got %+v (%v)", verification, err)
+		}
+	})
+}
+
+// readRetentionArchive lee las líneas de todos los ficheros archivados de una tabla
+func readRetentionArchive(t *testing.T, dir string, table domain.RetentionTable) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "archive", string(table), "*.jsonl.gz"))
+	if err != nil {
+		t.Fatalf("Error listing archive: %v", err)
+	}
+
+	var lines []string
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("Error opening archive: %v", err)
+		}
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("Error decompressing archive: %v", err)
+		}
+		scanner := bufio.NewScanner(gz)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		gz.Close()
+		file.Close()
+	}
+	return lines
+}
@@ -0,0 +1,131 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestRunDownService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	rundownRepo := repository.NewRunDownRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	stockService.SetRunDownRepository(rundownRepo)
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+	rundownService := service.NewRunDownService(rundownRepo, stockRepo, productRepo, eventRepo, publisher)
+
+	ctx := context.Background()
+
+	// Producto 0002: MAD-001 20/2, BCN-001 25/0, VAL-001 0/0, SEV-001 18/1
+	productID := "550e8400-e29b-41d4-a716-446655440002"
+
+	t.Run("Discontinue_CreatesPlanAndArchivesEmptyStores", func(t *testing.T) {
+		rundown, err := rundownService.Discontinue(ctx, productID, "end of life")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if rundown.Status != domain.RunDownStatusInProgress {
+			t.Errorf("Expected IN_PROGRESS, got %s", rundown.Status)
+		}
+		if rundown.Progress.Stores != 4 || rundown.Progress.ArchivedStores != 1 {
+			t.Errorf("Expected 4 stores with 1 archived, got %+v", rundown.Progress)
+		}
+		for _, entry := range rundown.Stores {
+			if entry.StoreID == "VAL-001" && entry.Status != domain.RunDownEntryArchived {
+				t.Errorf("Expected VAL-001 to be archived, got %s", entry.Status)
+			}
+		}
+
+		if _, err := stockRepo.GetByProductAndStore(ctx, productID, "VAL-001"); err == nil {
+			t.Error("Expected VAL-001 stock entry to be removed from the assortment")
+		}
+	})
+
+	t.Run("Discontinue_AlreadyDiscontinued", func(t *testing.T) {
+		_, err := rundownService.Discontinue(ctx, productID, "")
+		if _, ok := err.(*domain.ConflictError); !ok {
+			t.Errorf("Expected ConflictError, got %v", err)
+		}
+	})
+
+	t.Run("ReplenishmentBlocked", func(t *testing.T) {
+		if _, err := stockService.AdjustStock(ctx, productID, "MAD-001", 5); err == nil {
+			t.Error("Expected positive adjustment to be rejected")
+		} else if _, ok := err.(*domain.ConflictError); !ok {
+			t.Errorf("Expected ConflictError, got %v", err)
+		}
+
+		if err := stockService.TransferStock(ctx, productID, "BCN-001", "MAD-001", 1); err == nil {
+			t.Error("Expected transfer of discontinued product to be rejected")
+		}
+
+		if _, err := stockService.AdjustStock(ctx, productID, "MAD-001", -1); err != nil {
+			t.Errorf("Expected negative adjustment to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("ReservationsStillAllowed", func(t *testing.T) {
+		reservation, err := reservationService.CreateReservation(ctx, productID, "BCN-001", "customer-1", 5, 30)
+		if err != nil {
+			t.Fatalf("Expected reservation to be allowed, got %v", err)
+		}
+		if err := reservationService.ConfirmReservation(ctx, reservation.ID); err != nil {
+			t.Fatalf("Expected confirmation to succeed, got %v", err)
+		}
+	})
+
+	t.Run("ProcessRunDowns_CompletesWhenExhausted", func(t *testing.T) {
+		// Agotar el stock restante en cada tienda
+		if _, err := stockService.UpdateStock(ctx, productID, "BCN-001", 0); err != nil {
+			t.Fatalf("Failed to exhaust BCN-001: %v", err)
+		}
+		for store, reserved := range map[string]int{"MAD-001": 2, "SEV-001": 1} {
+			if err := stockRepo.ReleaseReservedStock(ctx, productID, store, reserved); err != nil {
+				t.Fatalf("Failed to release reserved stock in %s: %v", store, err)
+			}
+			if _, err := stockService.UpdateStock(ctx, productID, store, 0); err != nil {
+				t.Fatalf("Failed to exhaust %s: %v", store, err)
+			}
+		}
+
+		archived, err := rundownService.ProcessRunDowns(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if archived != 3 {
+			t.Errorf("Expected 3 archived entries, got %d", archived)
+		}
+
+		rundown, err := rundownService.GetRunDown(ctx, productID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if rundown.Status != domain.RunDownStatusCompleted || rundown.CompletedAt == nil {
+			t.Errorf("Expected COMPLETED plan, got %s", rundown.Status)
+		}
+		if rundown.Progress.RemainingUnits != 0 || rundown.Progress.Percent != 100 {
+			t.Errorf("Expected fully run down progress, got %+v", rundown.Progress)
+		}
+	})
+
+	t.Run("GetRunDown_NotDiscontinued", func(t *testing.T) {
+		_, err := rundownService.GetRunDown(ctx, "550e8400-e29b-41d4-a716-446655440000")
+		if _, ok := err.(*domain.NotFoundError); !ok {
+			t.Errorf("Expected NotFoundError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/infrastructure"
+)
+
+func TestS3BlobStore_PathStyle(t *testing.T) {
+	silenceLogs(t)
+
+	var (
+		mu           sync.Mutex
+		objects      = make(map[string][]byte)
+		contentTypes = make(map[string]string)
+	)
+
+	// Servidor S3 mínimo en path-style: /<bucket>/<key>
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !requireSigV4(w, r, "s3") {
+			return
+		}
+		key, ok := strings.CutPrefix(r.URL.Path, "/inventory-media/")
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[key] = body
+			contentTypes[key] = r.Header.Get("Content-Type")
+		case http.MethodGet:
+			body, found := objects[key]
+			if !found {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+				return
+			}
+			w.Write(body)
+		case http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	credentials := awsTestCredentials
+	credentials.Region = "eu-west-1"
+	store, err := infrastructure.NewS3BlobStore(infrastructure.S3BlobStoreConfig{
+		AWSCredentialsConfig: credentials,
+		Bucket:               "inventory-media",
+		Endpoint:             server.URL,
+		UsePathStyle:         true,
+	})
+	if err != nil {
+		t.Fatalf("Error creating store: %v", err)
+	}
+
+	ctx := context.Background()
+	url, err := store.Put(ctx, "products/prod-1/img.png", "image/png", strings.NewReader("png-bytes"), 9)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if url != server.URL+"/inventory-media/products/prod-1/img.png" {
+		t.Errorf("Expected path-style public URL, got %s", url)
+	}
+	mu.Lock()
+	if string(objects["products/prod-1/img.png"]) != "png-bytes" || contentTypes["products/prod-1/img.png"] != "image/png" {
+		t.Errorf("Expected object stored with its content type, got %q (%s)", objects["products/prod-1/img.png"], contentTypes["products/prod-1/img.png"])
+	}
+	mu.Unlock()
+
+	body, err := store.Get(ctx, "products/prod-1/img.png")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	content, _ := io.ReadAll(body)
+	body.Close()
+	if string(content) != "png-bytes" {
+		t.Errorf("Expected png-bytes, got %q", content)
+	}
+
+	if err := store.Delete(ctx, "products/prod-1/img.png"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var notFound *domain.NotFoundError
+	if _, err := store.Get(ctx, "products/prod-1/img.png"); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError after delete, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestSafetyStock(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewMockPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, eventRepo, publisher)
+
+	ctx := context.Background()
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(ctx, product.ID, "MAD-001", 5); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+
+	t.Run("RejectsNegative", func(t *testing.T) {
+		var validation *domain.ValidationError
+		if _, err := stockService.SetSafetyStock(ctx, product.ID, "MAD-001", -1); !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+		var notFound *domain.NotFoundError
+		if _, err := stockService.SetSafetyStock(ctx, product.ID, "XXX-999", 1); !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError for missing stock row, got %v", err)
+		}
+	})
+
+	t.Run("ExcludedFromAvailability", func(t *testing.T) {
+		stock, err := stockService.SetSafetyStock(ctx, product.ID, "MAD-001", 3)
+		if err != nil {
+			t.Fatalf("Error setting safety stock: %v", err)
+		}
+		if stock.SafetyStock != 3 || stock.Quantity != 5 || stock.Sellable() != 2 {
+			t.Errorf("Expected quantity 5, safety 3 and sellable 2, got %+v", stock)
+		}
+
+		available, err := stockService.GetAvailableStock(ctx, product.ID, "MAD-001")
+		if err != nil || available != 2 {
+			t.Errorf("Expected 2 available units, got %d (%v)", available, err)
+		}
+	})
+
+	t.Run("NotReservable", func(t *testing.T) {
+		var insufficient *domain.InsufficientStockError
+		if _, err := reservationService.CreateReservation(ctx, product.ID, "MAD-001", "customer-1", 3, 0); !errors.As(err, &insufficient) {
+			t.Fatalf("Expected InsufficientStockError, got %v", err)
+		}
+		if insufficient.Available != 2 {
+			t.Errorf("Expected 2 sellable units in error, got %d", insufficient.Available)
+		}
+		if _, err := reservationService.CreateReservation(ctx, product.ID, "MAD-001", "customer-1", 2, 0); err != nil {
+			t.Errorf("Expected reservation of the sellable units, got %v", err)
+		}
+	})
+
+	t.Run("CountsAsLowStock", func(t *testing.T) {
+		items, _, err := stockService.GetLowStockItems(ctx, domain.LowStockFilter{StoreID: "MAD-001", Threshold: 1})
+		if err != nil {
+			t.Fatalf("Error getting low stock: %v", err)
+		}
+		found := false
+		for _, item := range items {
+			found = found || item.ProductID == product.ID
+		}
+		if !found {
+			t.Error("Expected row without sellable units in low stock")
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"inventory-system/internal/config"
+	"inventory-system/internal/database"
+	"inventory-system/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSeedSandboxData_Deterministic(t *testing.T) {
+	snapshot := func(seed int64) map[string]int {
+		db, err := sql.Open("sqlite", config.SandboxSQLitePath)
+		if err != nil {
+			t.Fatalf("Failed to open sandbox database: %v", err)
+		}
+		defer db.Close()
+		db.SetMaxOpenConns(1)
+
+		if err := database.InitializeSchema(db, &config.Config{}); err != nil {
+			t.Fatalf("Failed to initialize schema: %v", err)
+		}
+		if err := database.SeedSandboxData(db, 20, seed); err != nil {
+			t.Fatalf("Failed to seed sandbox data: %v", err)
+		}
+
+		rows, err := db.Query(`SELECT id, quantity FROM stock WHERE id LIKE 'sbx-%'`)
+		if err != nil {
+			t.Fatalf("Failed to query stock: %v", err)
+		}
+		defer rows.Close()
+
+		stock := make(map[string]int)
+		for rows.Next() {
+			var id string
+			var quantity int
+			if err := rows.Scan(&id, &quantity); err != nil {
+				t.Fatalf("Failed to scan stock: %v", err)
+			}
+			stock[id] = quantity
+		}
+		return stock
+	}
+
+	first := snapshot(7)
+	second := snapshot(7)
+	other := snapshot(8)
+
+	if len(first) != 80 {
+		t.Fatalf("Expected 20 products x 4 stores, got %d stock rows", len(first))
+	}
+
+	differs := false
+	for id, quantity := range first {
+		if second[id] != quantity {
+			t.Errorf("Expected same quantity for %s with the same seed, got %d and %d", id, quantity, second[id])
+		}
+		if other[id] != quantity {
+			differs = true
+		}
+	}
+	if !differs {
+		t.Error("Expected a different seed to produce different quantities")
+	}
+}
+
+func TestSandboxMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(latency time.Duration, errorRate float64) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.Sandbox(latency, 0, errorRate, 1))
+		router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.GET("/api/v1/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router
+	}
+
+	do := func(router *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("ErrorRateOnlyAffectsAPI", func(t *testing.T) {
+		router := newRouter(0, 1)
+
+		if w := do(router, "/api/v1/products", nil); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected 503 with error rate 1, got %d", w.Code)
+		}
+		if w := do(router, "/health", nil); w.Code != http.StatusOK {
+			t.Errorf("Expected /health to be unaffected, got %d", w.Code)
+		}
+	})
+
+	t.Run("ForcedError", func(t *testing.T) {
+		router := newRouter(0, 0)
+
+		w := do(router, "/api/v1/products", map[string]string{middleware.SandboxErrorHeader: "429"})
+		if w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected forced 429, got %d", w.Code)
+		}
+		if w.Header().Get("X-Sandbox-Mode") != "true" {
+			t.Error("Expected X-Sandbox-Mode header")
+		}
+	})
+
+	t.Run("SimulatedLatency", func(t *testing.T) {
+		router := newRouter(30*time.Millisecond, 0)
+
+		start := time.Now()
+		if w := do(router, "/api/v1/products", nil); w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", w.Code)
+		}
+		if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+			t.Errorf("Expected at least 30ms of simulated latency, got %v", elapsed)
+		}
+	})
+}
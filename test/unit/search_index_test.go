@@ -0,0 +1,263 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/infrastructure"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+// fakeSearchIndex índice en memoria: Search devuelve los documentos cuyo nombre contiene query
+type fakeSearchIndex struct {
+	mu   sync.Mutex
+	docs map[string]*domain.ProductSearchDocument
+}
+
+func newFakeSearchIndex() *fakeSearchIndex {
+	return &fakeSearchIndex{docs: make(map[string]*domain.ProductSearchDocument)}
+}
+
+func (f *fakeSearchIndex) Upsert(ctx context.Context, docs ...*domain.ProductSearchDocument) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, doc := range docs {
+		f.docs[doc.ID] = doc
+	}
+	return nil
+}
+
+func (f *fakeSearchIndex) Delete(ctx context.Context, productID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.docs, productID)
+	return nil
+}
+
+func (f *fakeSearchIndex) Search(ctx context.Context, query string, limit, offset int) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ids []string
+	for id, doc := range f.docs {
+		if strings.Contains(strings.ToLower(doc.Name), strings.ToLower(query)) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeSearchIndex) Close() error {
+	return nil
+}
+
+func (f *fakeSearchIndex) get(productID string) *domain.ProductSearchDocument {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.docs[productID]
+}
+
+func TestSearchIndexer(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	index := newFakeSearchIndex()
+	indexer := service.NewSearchIndexer(index, domain.SearchBackendMeilisearch, productRepo, stockRepo)
+
+	publisher := mocks.NewMockPublisher()
+	productService := service.NewProductService(productRepo, eventRepo)
+	productService.SetPublisher(publisher)
+
+	ctx := context.Background()
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+
+	// Aplica al índice los eventos publicados hasta ahora, como haría SearchIndexPublisher
+	applyEvents := func() {
+		for _, event := range publisher.PublishedEvents {
+			if err := indexer.HandleEvent(ctx, event); err != nil {
+				t.Fatalf("Expected no error indexing %s, got %v", event.EventType, err)
+			}
+		}
+		publisher.PublishedEvents = nil
+	}
+
+	t.Run("Reindex", func(t *testing.T) {
+		result, err := indexer.Reindex(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Indexed != 5 || result.Backend != domain.SearchBackendMeilisearch {
+			t.Errorf("Expected 5 products indexed in meilisearch, got %+v", result)
+		}
+
+		doc := index.get(laptop)
+		if doc == nil || !doc.InStock || len(doc.AvailableStores) != 4 {
+			t.Errorf("Expected laptop available in the 4 stores, got %+v", doc)
+		}
+	})
+
+	t.Run("ProductEvents", func(t *testing.T) {
+		created, err := productService.CreateProduct(ctx, &domain.Product{
+			SKU: "SEARCH-001", Name: "Auriculares Sony", Category: "audio", Price: 129.99,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		applyEvents()
+
+		doc := index.get(created.ID)
+		if doc == nil || doc.InStock {
+			t.Fatalf("Expected indexed product without stock, got %+v", doc)
+		}
+
+		created.Name = "Auriculares Sony WH-1000"
+		if _, err := productService.UpdateProduct(ctx, created); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		applyEvents()
+		if doc := index.get(created.ID); doc == nil || doc.Name != "Auriculares Sony WH-1000" {
+			t.Errorf("Expected updated name in index, got %+v", doc)
+		}
+
+		if _, err := productService.DeleteProduct(ctx, created.ID, false); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		applyEvents()
+		if doc := index.get(created.ID); doc != nil {
+			t.Errorf("Expected deleted product removed from index, got %+v", doc)
+		}
+	})
+
+	t.Run("AvailabilityFromStockEvents", func(t *testing.T) {
+		stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+		if _, err := stockService.UpdateStock(ctx, laptop, "MAD-001", 0); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		applyEvents()
+
+		doc := index.get(laptop)
+		if doc == nil || len(doc.AvailableStores) != 3 {
+			t.Fatalf("Expected laptop available in 3 stores, got %+v", doc)
+		}
+		for _, storeID := range doc.AvailableStores {
+			if storeID == "MAD-001" {
+				t.Errorf("Expected MAD-001 removed from available stores, got %v", doc.AvailableStores)
+			}
+		}
+	})
+
+	t.Run("SearchProducts", func(t *testing.T) {
+		productService.SetSearchIndex(index)
+		products, err := productService.SearchProducts(ctx, "laptop", 10, 0)
+		if err != nil || len(products) != 1 || products[0].ID != laptop {
+			t.Errorf("Expected the laptop from the index, got %d products (%v)", len(products), err)
+		}
+
+		// Un documento huérfano (producto ya borrado) se omite
+		index.Upsert(ctx, &domain.ProductSearchDocument{ID: "missing", Name: "Laptop fantasma"})
+		products, err = productService.SearchProducts(ctx, "laptop", 10, 0)
+		if err != nil || len(products) != 1 {
+			t.Errorf("Expected orphan document skipped, got %d products (%v)", len(products), err)
+		}
+
+		if _, err := productService.SearchProducts(ctx, "  ", 10, 0); err == nil {
+			t.Error("Expected ValidationError for an empty query")
+		}
+	})
+
+	t.Run("DatabaseFallback", func(t *testing.T) {
+		dbService := service.NewProductService(productRepo, eventRepo)
+		products, err := dbService.SearchProducts(ctx, "logitech", 10, 0)
+		if err != nil || len(products) != 2 {
+			t.Errorf("Expected 2 Logitech products from the database, got %d (%v)", len(products), err)
+		}
+
+		// Los comodines de LIKE se buscan literalmente
+		products, err = dbService.SearchProducts(ctx, "%", 10, 0)
+		if err != nil || len(products) != 0 {
+			t.Errorf("Expected no products matching a literal %%, got %d (%v)", len(products), err)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		disabled := service.NewSearchIndexer(nil, domain.SearchBackendNone, productRepo, stockRepo)
+		if _, err := disabled.Reindex(ctx); err == nil {
+			t.Error("Expected ConflictError with SEARCH_BACKEND=none")
+		}
+	})
+}
+
+func TestElasticsearchIndex(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		bulk string
+		auth string
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":{"number":"8.13.0"}}`))
+	})
+	mux.HandleFunc("/_bulk", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bulk, auth = string(data), r.Header.Get("Authorization")
+		mu.Unlock()
+		w.Write([]byte(`{"errors":false,"items":[]}`))
+	})
+	mux.HandleFunc("/products/_search", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["size"] != float64(5) {
+			http.Error(w, "bad size", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"hits":{"hits":[{"_id":"p2"},{"_id":"p1"}]}}`))
+	})
+	mux.HandleFunc("/products/_doc/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"result":"not_found"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	index, err := infrastructure.NewElasticsearchIndex(infrastructure.ElasticsearchIndexConfig{URL: server.URL, APIKey: "secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ctx := context.Background()
+
+	if err := index.Upsert(ctx, &domain.ProductSearchDocument{ID: "p1", Name: "Laptop"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	mu.Lock()
+	if !strings.Contains(bulk, `{"index":{"_id":"p1","_index":"products"}}`) || !strings.Contains(bulk, `"name":"Laptop"`) {
+		t.Errorf("Unexpected bulk body: %s", bulk)
+	}
+	if auth != "ApiKey secret" {
+		t.Errorf("Expected ApiKey authorization, got %q", auth)
+	}
+	mu.Unlock()
+
+	ids, err := index.Search(ctx, "laptop", 5, 0)
+	if err != nil || len(ids) != 2 || ids[0] != "p2" {
+		t.Errorf("Expected hits in relevance order, got %v (%v)", ids, err)
+	}
+
+	// Borrar un documento inexistente no es error
+	if err := index.Delete(ctx, "p3"); err != nil {
+		t.Errorf("Expected no error deleting a missing document, got %v", err)
+	}
+}
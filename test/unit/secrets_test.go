@@ -0,0 +1,161 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/config"
+	"inventory-system/internal/secrets"
+)
+
+func TestLoad_SecretFiles(t *testing.T) {
+	keysFile := writeConfigFile(t, "api_keys", "file-key-0001:Store Madrid\n")
+	passwordFile := writeConfigFile(t, "redis_password", "s3cret\n")
+	t.Setenv("API_KEYS", "")
+	t.Setenv("API_KEYS_FILE", keysFile)
+	t.Setenv("REDIS_PASSWORD_FILE", passwordFile)
+
+	cfg := config.Load()
+
+	if cfg.APIKeys["file-key-0001"] != "Store Madrid" {
+		t.Errorf("Expected API keys from API_KEYS_FILE, got %v", cfg.APIKeys)
+	}
+	if cfg.RedisPassword != "s3cret" {
+		t.Errorf("Expected trimmed password from REDIS_PASSWORD_FILE, got %q", cfg.RedisPassword)
+	}
+}
+
+func TestLoad_MissingSecretFileFailsValidation(t *testing.T) {
+	t.Setenv("REDIS_PASSWORD_FILE", "/nonexistent/redis_password")
+
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "REDIS_PASSWORD_FILE") {
+		t.Errorf("Expected REDIS_PASSWORD_FILE error, got: %v", err)
+	}
+}
+
+func TestVaultProvider_KVv2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/inventory" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"API_KEYS": "vault-key-0001:Store Madrid", "REDIS_PASSWORD": "from-vault"},
+				"metadata": map[string]interface{}{"version": 3},
+			},
+		})
+	}))
+	defer server.Close()
+
+	t.Setenv("API_KEYS", "")
+	t.Setenv("REDIS_PASSWORD", "from-env")
+	t.Setenv("SECRETS_PROVIDER", "vault")
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "root-token")
+	t.Setenv("VAULT_SECRET_PATH", "secret/data/inventory")
+
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid configuration, got: %v", err)
+	}
+
+	if cfg.APIKeys["vault-key-0001"] != "Store Madrid" {
+		t.Errorf("Expected API keys from Vault, got %v", cfg.APIKeys)
+	}
+	if cfg.RedisPassword != "from-env" {
+		t.Errorf("Expected env var to take precedence over Vault, got %q", cfg.RedisPassword)
+	}
+}
+
+func TestAWSProvider_SignsAndParsesSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			t.Errorf("Unexpected X-Amz-Target: %s", r.Header.Get("X-Amz-Target"))
+		}
+		authz := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authz, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+			!strings.Contains(authz, "/eu-west-1/secretsmanager/aws4_request") {
+			t.Errorf("Unexpected Authorization header: %s", authz)
+		}
+
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["SecretId"] != "inventory/prod" {
+			t.Errorf("Expected SecretId inventory/prod, got %v", body)
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"SecretString": `{"API_KEYS":"aws-key-0001:Store Madrid"}`,
+		})
+	}))
+	defer server.Close()
+
+	provider, err := secrets.NewAWSProvider(secrets.AWSProviderConfig{
+		Region:          "eu-west-1",
+		SecretID:        "inventory/prod",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Endpoint:        server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Error creating provider: %v", err)
+	}
+
+	values, err := provider.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if values["API_KEYS"] != "aws-key-0001:Store Madrid" {
+		t.Errorf("Unexpected secret values: %v", values)
+	}
+}
+
+func TestReloadAPIKeys_RotatesWithoutRestart(t *testing.T) {
+	keysFile := writeConfigFile(t, "api_keys", "old-key-0001:Store Madrid")
+	t.Setenv("API_KEYS", "")
+	t.Setenv("API_KEYS_FILE", keysFile)
+
+	cfg := config.Load()
+	if !cfg.APIKeysReloadable() {
+		t.Fatal("Expected API keys from API_KEYS_FILE to be reloadable")
+	}
+
+	ring := auth.NewKeyRing(cfg.APIKeys)
+	ring.AddHash(auth.HashKey("issued-at-runtime"), "Store Valencia")
+
+	// Rotación: el orquestador reescribe el secreto montado
+	if err := os.WriteFile(keysFile, []byte("new-key-0002:Store Madrid"), 0o600); err != nil {
+		t.Fatalf("Failed to rotate key file: %v", err)
+	}
+
+	keys, err := cfg.ReloadAPIKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ReloadAPIKeys failed: %v", err)
+	}
+
+	added, removed := ring.ReplaceStatic(keys)
+	if added != 1 || removed != 1 {
+		t.Errorf("Expected 1 added and 1 revoked, got %d/%d", added, removed)
+	}
+	if _, ok := ring.Lookup("old-key-0001"); ok {
+		t.Error("Expected rotated key to be revoked")
+	}
+	if _, ok := ring.Lookup("new-key-0002"); !ok {
+		t.Error("Expected new key to be accepted")
+	}
+	if _, ok := ring.Lookup("issued-at-runtime"); !ok {
+		t.Error("Expected runtime-issued key to survive the rotation")
+	}
+}
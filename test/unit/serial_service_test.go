@@ -0,0 +1,86 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestSerialService_RegisterAndLookup(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	reservationRepo := repository.NewReservationRepository(db)
+	reservationService := service.NewReservationService(
+		reservationRepo,
+		repository.NewStockRepository(db),
+		repository.NewProductRepository(db),
+		repository.NewEventRepository(db),
+		mocks.NewNoOpPublisher(),
+	)
+	serialService := service.NewSerialService(repository.NewSerialRepository(db), reservationRepo)
+
+	ctx := context.Background()
+	productID := "550e8400-e29b-41d4-a716-446655440000"
+
+	reservation, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "CUST-WARRANTY", 2, 15)
+	if err != nil {
+		t.Fatalf("Error creating reservation: %v", err)
+	}
+
+	t.Run("Register_PendingReservation_Fails", func(t *testing.T) {
+		_, err := serialService.RegisterSerials(ctx, reservation.ID, []string{"SN-001"}, 24)
+		if _, ok := err.(*domain.InvalidStateError); !ok {
+			t.Errorf("Expected InvalidStateError, got %v", err)
+		}
+	})
+
+	if err := reservationService.ConfirmReservation(ctx, reservation.ID); err != nil {
+		t.Fatalf("Error confirming reservation: %v", err)
+	}
+
+	t.Run("Register_Success", func(t *testing.T) {
+		regs, err := serialService.RegisterSerials(ctx, reservation.ID, []string{"SN-001", "SN-002"}, 24)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(regs) != 2 {
+			t.Fatalf("Expected 2 registrations, got %d", len(regs))
+		}
+		if regs[0].CustomerID != "CUST-WARRANTY" {
+			t.Errorf("Expected customer CUST-WARRANTY, got %s", regs[0].CustomerID)
+		}
+		if regs[0].WarrantyExpiresAt == nil {
+			t.Error("Expected warranty expiration to be set")
+		}
+	})
+
+	t.Run("Register_MoreThanSold_Fails", func(t *testing.T) {
+		_, err := serialService.RegisterSerials(ctx, reservation.ID, []string{"SN-003"}, 0)
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("Lookup_Found", func(t *testing.T) {
+		regs, err := serialService.LookupSerial(ctx, "SN-002")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(regs) != 1 || regs[0].ReservationID != reservation.ID {
+			t.Errorf("Unexpected lookup result: %+v", regs)
+		}
+	})
+
+	t.Run("Lookup_NotFound", func(t *testing.T) {
+		_, err := serialService.LookupSerial(ctx, "SN-UNKNOWN")
+		if _, ok := err.(*domain.NotFoundError); !ok {
+			t.Errorf("Expected NotFoundError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package unit
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"inventory-system/internal/app"
+	"inventory-system/internal/config"
+)
+
+func TestNewServer(t *testing.T) {
+	t.Setenv("MESSAGE_BROKER", "none")
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+
+	// serve arranca srv en un puerto libre y retorna el protocolo con el que responde
+	serve := func(t *testing.T, srv *http.Server, client *http.Client) int {
+		t.Helper()
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Error listening: %v", err)
+		}
+		scheme := "http"
+		if srv.TLSConfig != nil {
+			scheme = "https"
+			go srv.ServeTLS(listener, "", "")
+		} else {
+			go srv.Serve(listener)
+		}
+		defer srv.Close()
+
+		resp, err := client.Get(scheme + "://" + listener.Addr().String() + "/")
+		if err != nil {
+			t.Fatalf("Error requesting server: %v", err)
+		}
+		defer resp.Body.Close()
+		return resp.ProtoMajor
+	}
+
+	t.Run("TimeoutsFromConfig", func(t *testing.T) {
+		t.Setenv("HTTP_READ_TIMEOUT_SECONDS", "5")
+		t.Setenv("HTTP_WRITE_TIMEOUT_SECONDS", "7")
+
+		srv, err := app.NewServer(config.Load(), handler)
+		if err != nil {
+			t.Fatalf("NewServer failed: %v", err)
+		}
+		if srv.ReadTimeout != 5*time.Second || srv.WriteTimeout != 7*time.Second || srv.IdleTimeout != 120*time.Second || srv.TLSConfig != nil {
+			t.Errorf("Unexpected server settings: read=%v write=%v idle=%v tls=%v", srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout, srv.TLSConfig != nil)
+		}
+	})
+
+	t.Run("TLSWithHTTP2", func(t *testing.T) {
+		certFile, keyFile := writeSelfSignedCert(t)
+		t.Setenv("TLS_CERT_FILE", certFile)
+		t.Setenv("TLS_KEY_FILE", keyFile)
+
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			ForceAttemptHTTP2: true,
+		}}
+
+		srv, err := app.NewServer(config.Load(), handler)
+		if err != nil {
+			t.Fatalf("NewServer failed: %v", err)
+		}
+		if proto := serve(t, srv, client); proto != 2 {
+			t.Errorf("Expected HTTP/2 over TLS, got HTTP/%d", proto)
+		}
+
+		t.Setenv("HTTP2_ENABLED", "false")
+		srv, err = app.NewServer(config.Load(), handler)
+		if err != nil {
+			t.Fatalf("NewServer failed: %v", err)
+		}
+		client.CloseIdleConnections()
+		if proto := serve(t, srv, client); proto != 1 {
+			t.Errorf("Expected HTTP/1.1 with HTTP2_ENABLED=false, got HTTP/%d", proto)
+		}
+	})
+
+	t.Run("UnencryptedHTTP2", func(t *testing.T) {
+		protocols := new(http.Protocols)
+		protocols.SetUnencryptedHTTP2(true)
+		client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+		srv, err := app.NewServer(config.Load(), handler)
+		if err != nil {
+			t.Fatalf("NewServer failed: %v", err)
+		}
+		if proto := serve(t, srv, client); proto != 2 {
+			t.Errorf("Expected h2c without TLS, got HTTP/%d", proto)
+		}
+	})
+
+	t.Run("InvalidCertificate", func(t *testing.T) {
+		t.Setenv("TLS_CERT_FILE", filepath.Join(t.TempDir(), "missing.pem"))
+		t.Setenv("TLS_KEY_FILE", filepath.Join(t.TempDir(), "missing-key.pem"))
+
+		if _, err := app.NewServer(config.Load(), handler); err == nil {
+			t.Error("Expected error loading a missing certificate")
+		}
+	})
+}
+
+// writeSelfSignedCert genera un certificado autofirmado para 127.0.0.1 y retorna sus rutas
+func writeSelfSignedCert(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Error creating certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Error marshaling key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return certFile, keyFile
+}
@@ -0,0 +1,94 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestBackfillStockSnapshots(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewMockPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	backfillRepo := repository.NewSnapshotBackfillRepository(db)
+	backfillService := service.NewSnapshotBackfillService(backfillRepo, stockRepo, eventRepo, publisher)
+
+	ctx := context.Background()
+	var total int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM stock`).Scan(&total); err != nil {
+		t.Fatalf("Error counting stock: %v", err)
+	}
+	if total < 3 {
+		t.Fatalf("Expected seeded stock rows, got %d", total)
+	}
+
+	t.Run("EmitsOneSnapshotPerRow", func(t *testing.T) {
+		backfill, err := backfillService.BackfillStockSnapshots(ctx, 2)
+		if err != nil {
+			t.Fatalf("Error running backfill: %v", err)
+		}
+		if backfill.Status != domain.JobStatusCompleted || backfill.Emitted != total {
+			t.Fatalf("Expected completed backfill with %d snapshots, got %+v", total, backfill)
+		}
+
+		snapshots := publisher.GetEventsByType(domain.EventStockSnapshot)
+		if len(snapshots) != total {
+			t.Fatalf("Expected %d published snapshots, got %d", total, len(snapshots))
+		}
+		payload, err := domain.DecodeEventPayloadAs[*domain.StockSnapshotPayload](snapshots[0])
+		if err != nil {
+			t.Fatalf("Error decoding snapshot: %v", err)
+		}
+		if payload.BackfillID != backfill.ID || payload.Available != payload.Quantity-payload.Reserved {
+			t.Errorf("Unexpected snapshot payload: %+v", payload)
+		}
+
+		pending, _ := eventRepo.CountPending(ctx)
+		if pending != 0 {
+			t.Errorf("Expected published snapshots marked as synced, got %d pending", pending)
+		}
+	})
+
+	t.Run("ResumesInterruptedBackfill", func(t *testing.T) {
+		publisher.Reset()
+
+		// Backfill interrumpido después de la primera fila
+		var productID, storeID string
+		if err := db.QueryRow(`SELECT product_id, store_id FROM stock ORDER BY product_id, store_id LIMIT 1`).Scan(&productID, &storeID); err != nil {
+			t.Fatalf("Error getting first stock row: %v", err)
+		}
+		now := time.Now()
+		interrupted := &domain.SnapshotBackfill{ID: "backfill-interrupted", Status: domain.JobStatusRunning, CreatedAt: now, UpdatedAt: now}
+		if err := backfillRepo.Create(ctx, interrupted); err != nil {
+			t.Fatalf("Error creating backfill: %v", err)
+		}
+		interrupted.LastProductID, interrupted.LastStoreID, interrupted.Emitted = productID, storeID, 1
+		if err := backfillRepo.SaveProgress(ctx, interrupted); err != nil {
+			t.Fatalf("Error saving progress: %v", err)
+		}
+
+		backfill, err := backfillService.BackfillStockSnapshots(ctx, 0)
+		if err != nil {
+			t.Fatalf("Error resuming backfill: %v", err)
+		}
+		if backfill.ID != interrupted.ID || backfill.Emitted != total {
+			t.Errorf("Expected backfill %s resumed up to %d, got %+v", interrupted.ID, total, backfill)
+		}
+		if published := len(publisher.GetEventsByType(domain.EventStockSnapshot)); published != total-1 {
+			t.Errorf("Expected %d snapshots after the cursor, got %d", total-1, published)
+		}
+
+		if running, _ := backfillRepo.GetRunning(ctx); running != nil {
+			t.Errorf("Expected no running backfill, got %+v", running)
+		}
+	})
+}
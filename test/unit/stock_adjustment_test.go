@@ -0,0 +1,158 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestAdjustmentApprovalPolicy(t *testing.T) {
+	policy := domain.AdjustmentApprovalPolicy{MaxUnits: 100, MaxPercent: 50}
+
+	tests := []struct {
+		name     string
+		current  int
+		delta    int
+		expected bool
+	}{
+		{"WithinThresholds", 100, 40, false},
+		{"AboveUnits", 1000, 150, true},
+		{"NegativeAboveUnits", 1000, -101, true},
+		{"AbovePercent", 10, 6, true},
+		{"EmptyRowSkipsPercent", 0, 50, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Requires(tt.current, tt.delta); got != tt.expected {
+				t.Errorf("Requires(%d, %d) = %v, expected %v", tt.current, tt.delta, got, tt.expected)
+			}
+		})
+	}
+
+	if (domain.AdjustmentApprovalPolicy{}).Requires(10, 1000) {
+		t.Error("Expected a zero policy to never require approval")
+	}
+}
+
+func TestStockAdjustmentApproval(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, mocks.NewNoOpPublisher())
+	adjustmentService := service.NewStockAdjustmentService(repository.NewStockAdjustmentRepository(db), stockService,
+		domain.AdjustmentApprovalPolicy{MaxUnits: 5})
+
+	silenceLogs(t)
+	alice := domain.WithActor(context.Background(), "alice")
+	bob := domain.WithActor(context.Background(), "bob")
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+
+	quantity := func() int {
+		stock, err := stockRepo.GetByProductAndStore(context.Background(), laptop, "MAD-001")
+		if err != nil {
+			t.Fatalf("Error getting stock: %v", err)
+		}
+		return stock.Quantity
+	}
+	request := func(adjustment int) *domain.StockAdjustment {
+		stock, pending, err := adjustmentService.AdjustStock(alice, laptop, "MAD-001", adjustment, domain.AdjustmentReasonCorrection)
+		if err != nil {
+			t.Fatalf("Error adjusting stock: %v", err)
+		}
+		if stock != nil || pending == nil || pending.Status != domain.StockAdjustmentPending {
+			t.Fatalf("Expected a pending adjustment, got stock %+v and adjustment %+v", stock, pending)
+		}
+		return pending
+	}
+
+	t.Run("SmallAdjustmentAppliesDirectly", func(t *testing.T) {
+		before := quantity()
+		stock, pending, err := adjustmentService.AdjustStock(alice, laptop, "MAD-001", 2, domain.AdjustmentReasonFound)
+		if err != nil || pending != nil || stock == nil {
+			t.Fatalf("Expected the adjustment to apply directly, got %+v (%v)", pending, err)
+		}
+		if quantity() != before+2 {
+			t.Errorf("Expected quantity %d, got %d", before+2, quantity())
+		}
+	})
+
+	t.Run("LargeAdjustmentAwaitsApproval", func(t *testing.T) {
+		before := quantity()
+		pending := request(20)
+		if quantity() != before || pending.RequestedBy != "alice" || pending.CurrentQuantity != before {
+			t.Errorf("Expected untouched stock and requester alice, got quantity %d and %+v", quantity(), pending)
+		}
+
+		_, _, err := adjustmentService.ApproveAdjustment(alice, pending.ID)
+		var selfApproval *domain.SelfApprovalError
+		if !errors.As(err, &selfApproval) {
+			t.Fatalf("Expected SelfApprovalError, got %v", err)
+		}
+
+		approved, stock, err := adjustmentService.ApproveAdjustment(bob, pending.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if approved.Status != domain.StockAdjustmentApproved || approved.ReviewedBy != "bob" || stock.Quantity != before+20 {
+			t.Errorf("Expected approved by bob with quantity %d, got %+v and %d", before+20, approved, stock.Quantity)
+		}
+
+		if _, _, err := adjustmentService.ApproveAdjustment(bob, pending.ID); err == nil {
+			t.Error("Expected an error approving an adjustment twice")
+		}
+
+		events, err := eventRepo.GetEventsByType(context.Background(), domain.EventAdjustmentApproved, 10, 0)
+		if err != nil || len(events) != 1 {
+			t.Errorf("Expected 1 approved event, got %d (%v)", len(events), err)
+		}
+	})
+
+	t.Run("SetIsMeasuredAgainstCurrentQuantity", func(t *testing.T) {
+		before := quantity()
+		if _, pending, err := adjustmentService.UpdateStock(alice, laptop, "MAD-001", before+3, ""); err != nil || pending != nil {
+			t.Fatalf("Expected a small SET to apply directly, got %+v (%v)", pending, err)
+		}
+		_, pending, err := adjustmentService.UpdateStock(alice, laptop, "MAD-001", 0, domain.AdjustmentReasonShrinkage)
+		if err != nil || pending == nil || pending.Type != domain.StockAdjustmentSet {
+			t.Fatalf("Expected a pending SET, got %+v (%v)", pending, err)
+		}
+	})
+
+	t.Run("RejectKeepsStock", func(t *testing.T) {
+		before := quantity()
+		pending := request(-10)
+
+		rejected, err := adjustmentService.RejectAdjustment(bob, pending.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if rejected.Status != domain.StockAdjustmentRejected || quantity() != before {
+			t.Errorf("Expected rejected with quantity %d, got %s and %d", before, rejected.Status, quantity())
+		}
+
+		var invalidState *domain.InvalidStateError
+		if _, _, err := adjustmentService.ApproveAdjustment(bob, pending.ID); !errors.As(err, &invalidState) {
+			t.Errorf("Expected InvalidStateError approving a rejected adjustment, got %v", err)
+		}
+	})
+
+	t.Run("InvalidAdjustmentIsNotQueued", func(t *testing.T) {
+		if _, _, err := adjustmentService.AdjustStock(alice, laptop, "MAD-001", -(quantity() + 100), domain.AdjustmentReasonDamaged); err == nil {
+			t.Error("Expected a validation error for an adjustment below zero")
+		}
+
+		pending, err := adjustmentService.ListAdjustments(context.Background(), domain.StockAdjustmentPending, "MAD-001", 50, 0)
+		if err != nil || len(pending) != 1 {
+			t.Errorf("Expected only the pending SET in the queue, got %d (%v)", len(pending), err)
+		}
+	})
+}
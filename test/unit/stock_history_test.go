@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestStockHistory(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, eventRepo, publisher)
+	holdService := service.NewStockHoldService(repository.NewStockHoldRepository(db), stockService)
+
+	silenceLogs(t)
+	alice := domain.WithActor(context.Background(), "alice")
+	bob := domain.WithActor(context.Background(), "bob")
+
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(context.Background(), product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(alice, product.ID, "MAD-001", 10); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+	if _, err := stockService.UpdateStock(alice, product.ID, "MAD-001", 15); err != nil {
+		t.Fatalf("Error updating stock: %v", err)
+	}
+	reservation, err := reservationService.CreateReservation(bob, product.ID, "MAD-001", "customer-1", 5, 0)
+	if err != nil {
+		t.Fatalf("Error creating reservation: %v", err)
+	}
+	if err := reservationService.ConfirmReservation(bob, reservation.ID); err != nil {
+		t.Fatalf("Error confirming reservation: %v", err)
+	}
+	if _, err := holdService.PlaceHold(alice, product.ID, "MAD-001", domain.StockHoldDisplay, 2, ""); err != nil {
+		t.Fatalf("Error placing hold: %v", err)
+	}
+
+	history, err := stockService.GetStockHistory(context.Background(), product.ID, "MAD-001", domain.StockHistoryFilter{})
+	if err != nil {
+		t.Fatalf("Error getting stock history: %v", err)
+	}
+	if history.Quantity != 10 || history.Reserved != 2 || history.Version != 2 {
+		t.Errorf("Expected current quantity=10 reserved=2 version=2, got %+v", history)
+	}
+
+	expected := []struct {
+		eventType          string
+		actor              string
+		quantity, reserved int
+		version            int
+	}{
+		{domain.EventStockHoldPlaced, "alice", 10, 2, 2},
+		{domain.EventReservationConfirmed, "bob", 10, 0, 2},
+		{domain.EventReservationCreated, "bob", 15, 5, 2},
+		{domain.EventStockUpdated, "alice", 15, 0, 2},
+		{domain.EventStockCreated, "alice", 10, 0, 1},
+	}
+	if history.Count != len(expected) || history.HasMore {
+		t.Fatalf("Expected %d entries without more pages, got %d (has_more=%v)", len(expected), history.Count, history.HasMore)
+	}
+	for i, want := range expected {
+		got := history.Entries[i]
+		if got.EventType != want.eventType || got.Actor != want.actor ||
+			got.Quantity != want.quantity || got.Reserved != want.reserved || got.Version != want.version {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, want, got)
+		}
+		if got.UntrackedQuantity != 0 || got.UntrackedReserved != 0 {
+			t.Errorf("Entry %d: expected no untracked changes, got %+v", i, got)
+		}
+	}
+	if confirmed := history.Entries[1]; confirmed.QuantityDelta != -5 || confirmed.Reference != reservation.ID {
+		t.Errorf("Expected the confirmation to take 5 units for reservation %s, got %+v", reservation.ID, confirmed)
+	}
+
+	// Paginación con before_seq
+	page, err := stockService.GetStockHistory(context.Background(), product.ID, "MAD-001", domain.StockHistoryFilter{Limit: 2})
+	if err != nil || page.Count != 2 || !page.HasMore {
+		t.Fatalf("Expected a first page of 2 entries with more, got %+v (err: %v)", page, err)
+	}
+	next, err := stockService.GetStockHistory(context.Background(), product.ID, "MAD-001",
+		domain.StockHistoryFilter{Limit: 2, BeforeSeq: page.Entries[1].Seq})
+	if err != nil || next.Count != 2 || next.Entries[0].EventType != domain.EventReservationCreated || next.Entries[0].Reserved != 5 {
+		t.Fatalf("Expected the next page to start at reservation.created, got %+v (err: %v)", next, err)
+	}
+
+	var validation *domain.ValidationError
+	if _, err := stockService.GetStockHistory(context.Background(), product.ID, "MAD-001",
+		domain.StockHistoryFilter{Limit: domain.MaxStockHistoryLimit + 1}); !errors.As(err, &validation) {
+		t.Errorf("Expected ValidationError for a limit above the maximum, got %v", err)
+	}
+	var notFound *domain.NotFoundError
+	if _, err := stockService.GetStockHistory(context.Background(), product.ID, "BCN-001", domain.StockHistoryFilter{}); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError for a store without stock, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestStockHolds(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+	stockService := service.NewStockService(stockRepo, repository.NewProductRepository(db), eventRepo, publisher)
+	holdService := service.NewStockHoldService(repository.NewStockHoldRepository(db), stockService)
+
+	silenceLogs(t)
+	ctx := domain.WithActor(context.Background(), "alice")
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+
+	stock := func() *domain.Stock {
+		s, err := stockRepo.GetByProductAndStore(context.Background(), laptop, "MAD-001")
+		if err != nil {
+			t.Fatalf("Error getting stock: %v", err)
+		}
+		return s
+	}
+	initial := stock()
+
+	hold, err := holdService.PlaceHold(ctx, laptop, "MAD-001", "display", 2, " Escaparate ")
+	if err != nil {
+		t.Fatalf("Error placing hold: %v", err)
+	}
+	if hold.Type != domain.StockHoldDisplay || hold.Status != domain.StockHoldActive || hold.CreatedBy != "alice" || hold.Note != "Escaparate" {
+		t.Errorf("Unexpected hold: %+v", hold)
+	}
+	if s := stock(); s.Reserved != initial.Reserved+2 || s.Held != initial.Held+2 || s.Sellable() != initial.Sellable()-2 {
+		t.Errorf("Expected the hold in reserved and held, got reserved=%d held=%d", s.Reserved, s.Held)
+	}
+
+	var insufficient *domain.InsufficientStockError
+	if _, err := holdService.PlaceHold(ctx, laptop, "MAD-001", domain.StockHoldRepair, initial.Sellable(), ""); !errors.As(err, &insufficient) {
+		t.Errorf("Expected InsufficientStockError holding more than the sellable quantity, got %v", err)
+	}
+	var validation *domain.ValidationError
+	if _, err := holdService.PlaceHold(ctx, laptop, "MAD-001", "CUSTOMER", 1, ""); !errors.As(err, &validation) {
+		t.Errorf("Expected ValidationError for an unknown hold type, got %v", err)
+	}
+
+	// La reconciliación de reserved cuenta las retenciones: no las borra
+	reconciliation, err := service.NewReservedReconciliationService(stockRepo, eventRepo, publisher).ReconcileReserved(ctx, "MAD-001", true)
+	if err != nil {
+		t.Fatalf("Error reconciling reserved: %v", err)
+	}
+	for _, d := range reconciliation.Discrepancies {
+		if d.ProductID == laptop {
+			t.Errorf("Expected no discrepancy for a row with only holds, got %+v", d)
+		}
+	}
+
+	holds, err := holdService.ListHolds(context.Background(), domain.StockHoldFilter{StoreID: "MAD-001", Status: domain.StockHoldActive, Limit: 10})
+	if err != nil || len(holds) != 1 || holds[0].ID != hold.ID {
+		t.Fatalf("Expected the active hold in the list, got %v (err: %v)", holds, err)
+	}
+
+	released, err := holdService.ReleaseHold(domain.WithActor(context.Background(), "bob"), hold.ID)
+	if err != nil {
+		t.Fatalf("Error releasing hold: %v", err)
+	}
+	if released.Status != domain.StockHoldReleased || released.ReleasedBy != "bob" || released.ReleasedAt == nil {
+		t.Errorf("Unexpected released hold: %+v", released)
+	}
+	if s := stock(); s.Reserved != initial.Reserved || s.Held != initial.Held {
+		t.Errorf("Expected the units back after the release, got reserved=%d held=%d", s.Reserved, s.Held)
+	}
+
+	var invalidState *domain.InvalidStateError
+	if _, err := holdService.ReleaseHold(ctx, hold.ID); !errors.As(err, &invalidState) {
+		t.Errorf("Expected InvalidStateError releasing twice, got %v", err)
+	}
+
+	events, err := eventRepo.GetByAggregateID(context.Background(), laptop)
+	if err != nil {
+		t.Fatalf("Error getting events: %v", err)
+	}
+	types := map[string]int{}
+	for _, event := range events {
+		types[event.EventType]++
+	}
+	if types[domain.EventStockHoldPlaced] != 1 || types[domain.EventStockHoldReleased] != 1 {
+		t.Errorf("Expected one stock.hold_placed and one stock.hold_released event, got %v", types)
+	}
+}
@@ -0,0 +1,269 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+// Producto 0002 en tres tiendas con stock (MAD-001 20/2, BCN-001 25/0, SEV-001 18/1)
+var invariantProductID = "550e8400-e29b-41d4-a716-446655440002"
+var invariantStores = []string{"MAD-001", "BCN-001", "SEV-001"}
+
+const (
+	opReserve = iota
+	opConfirm
+	opCancel
+	opExpire
+	opAdjust
+	opTransfer
+	opCount
+)
+
+// stockInvariantHarness aplica operaciones de stock/reservas y verifica después de cada una que:
+//   - reserved >= 0, quantity >= reserved y available >= 0
+//   - reserved == reservado inicial + suma de reservas PENDING de la tienda
+//   - quantity - quantity inicial == suma de movimientos (modelo y eventos persistidos)
+type stockInvariantHarness struct {
+	t                  testing.TB
+	ctx                context.Context
+	db                 *sql.DB
+	stockRepo          *repository.StockRepository
+	stockService       *service.StockService
+	reservationService *service.ReservationService
+
+	initialQuantity map[string]int
+	initialReserved map[string]int
+	movements       map[string]int // Delta de quantity por tienda según las operaciones aceptadas
+	pending         []*domain.Reservation
+	history         []string
+}
+
+func newStockInvariantHarness(t testing.TB) *stockInvariantHarness {
+	db := testutil.SetupTestDB(t)
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+
+	h := &stockInvariantHarness{
+		t:                  t,
+		ctx:                context.Background(),
+		db:                 db,
+		stockRepo:          stockRepo,
+		stockService:       service.NewStockService(stockRepo, productRepo, eventRepo, publisher),
+		reservationService: service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher),
+		initialQuantity:    make(map[string]int),
+		initialReserved:    make(map[string]int),
+		movements:          make(map[string]int),
+	}
+
+	for _, store := range invariantStores {
+		stock, err := stockRepo.GetByProductAndStore(h.ctx, invariantProductID, store)
+		if err != nil {
+			t.Fatalf("Failed to load initial stock for %s: %v", store, err)
+		}
+		h.initialQuantity[store] = stock.Quantity
+		h.initialReserved[store] = stock.Reserved
+	}
+
+	return h
+}
+
+// apply ejecuta la operación codificada por (op, a, b). Las operaciones rechazadas
+// con un error de dominio son válidas y no deben modificar el stock.
+func (h *stockInvariantHarness) apply(op, a, b byte) {
+	store := invariantStores[int(a)%len(invariantStores)]
+	var desc string
+	var err error
+
+	switch int(op) % opCount {
+	case opReserve:
+		quantity := int(b)%10 + 1
+		desc = fmt.Sprintf("reserve %d in %s", quantity, store)
+		var reservation *domain.Reservation
+		reservation, err = h.reservationService.CreateReservation(h.ctx, invariantProductID, store, "customer-prop", quantity, 30)
+		if err == nil {
+			h.pending = append(h.pending, reservation)
+		}
+
+	case opConfirm, opCancel, opExpire:
+		if len(h.pending) == 0 {
+			return
+		}
+		i := int(b) % len(h.pending)
+		reservation := h.pending[i]
+
+		switch int(op) % opCount {
+		case opConfirm:
+			desc = fmt.Sprintf("confirm %d in %s", reservation.Quantity, reservation.StoreID)
+			err = h.reservationService.ConfirmReservation(h.ctx, reservation.ID)
+			if err == nil {
+				h.movements[reservation.StoreID] -= reservation.Quantity
+			}
+		case opCancel:
+			desc = fmt.Sprintf("cancel %d in %s", reservation.Quantity, reservation.StoreID)
+			err = h.reservationService.CancelReservation(h.ctx, reservation.ID)
+		default:
+			desc = fmt.Sprintf("expire %d in %s", reservation.Quantity, reservation.StoreID)
+			if _, execErr := h.db.Exec(`UPDATE reservations SET expires_at = ? WHERE id = ?`, time.Now().Add(-time.Minute), reservation.ID); execErr != nil {
+				h.t.Fatalf("Failed to backdate reservation: %v", execErr)
+			}
+			err = h.reservationService.ExpireReservation(h.ctx, reservation.ID)
+		}
+
+		if err == nil {
+			h.pending = append(h.pending[:i], h.pending[i+1:]...)
+		}
+
+	case opAdjust:
+		adjustment := int(b)%21 - 10
+		desc = fmt.Sprintf("adjust %+d in %s", adjustment, store)
+		_, err = h.stockService.AdjustStock(h.ctx, invariantProductID, store, adjustment)
+		if err == nil {
+			h.movements[store] += adjustment
+		}
+
+	case opTransfer:
+		to := invariantStores[(int(a)+1+int(b)%2)%len(invariantStores)]
+		quantity := int(b)%10 + 1
+		desc = fmt.Sprintf("transfer %d from %s to %s", quantity, store, to)
+		err = h.stockService.TransferStock(h.ctx, invariantProductID, store, to, quantity)
+		if err == nil {
+			h.movements[store] -= quantity
+			h.movements[to] += quantity
+		}
+	}
+
+	if err != nil {
+		var domainErr domain.DomainError
+		if !errors.As(err, &domainErr) {
+			h.fail("%s: unexpected non-domain error: %v", desc, err)
+		}
+		desc += " (rejected)"
+	}
+	h.history = append(h.history, desc)
+
+	h.check()
+}
+
+// check verifica los invariantes en todas las tiendas
+func (h *stockInvariantHarness) check() {
+	pendingByStore := make(map[string]int)
+	for _, reservation := range h.pending {
+		pendingByStore[reservation.StoreID] += reservation.Quantity
+	}
+
+	for _, store := range invariantStores {
+		stock, err := h.stockRepo.GetByProductAndStore(h.ctx, invariantProductID, store)
+		if err != nil {
+			h.fail("failed to load stock for %s: %v", store, err)
+		}
+
+		if stock.Reserved < 0 {
+			h.fail("%s: reserved is negative (%d)", store, stock.Reserved)
+		}
+		if stock.Quantity < stock.Reserved {
+			h.fail("%s: quantity (%d) below reserved (%d)", store, stock.Quantity, stock.Reserved)
+		}
+		if stock.Available() < 0 {
+			h.fail("%s: negative available (%d)", store, stock.Available())
+		}
+		if want := h.initialReserved[store] + pendingByStore[store]; stock.Reserved != want {
+			h.fail("%s: reserved is %d, pending reservations account for %d", store, stock.Reserved, want)
+		}
+
+		delta := stock.Quantity - h.initialQuantity[store]
+		if delta != h.movements[store] {
+			h.fail("%s: quantity delta is %d, accepted movements sum %d", store, delta, h.movements[store])
+		}
+		if ledger := h.ledgerDelta(store); ledger != delta {
+			h.fail("%s: quantity delta is %d, persisted events sum %d", store, delta, ledger)
+		}
+	}
+}
+
+// ledgerDelta suma los movimientos de quantity registrados en la tabla de eventos
+func (h *stockInvariantHarness) ledgerDelta(store string) int {
+	var delta int
+	err := h.db.QueryRow(`
+		SELECT COALESCE(SUM(CASE event_type
+			WHEN 'stock.updated' THEN json_extract(payload, '$.new_quantity') - json_extract(payload, '$.old_quantity')
+			WHEN 'reservation.confirmed' THEN -json_extract(payload, '$.quantity')
+			ELSE 0 END), 0)
+		FROM events
+		WHERE store_id = ? AND json_extract(payload, '$.product_id') = ?
+	`, store, invariantProductID).Scan(&delta)
+	if err != nil {
+		h.fail("failed to sum event ledger: %v", err)
+	}
+	return delta
+}
+
+func (h *stockInvariantHarness) fail(format string, args ...interface{}) {
+	h.t.Helper()
+	h.t.Fatalf("invariant violated after %d operations: %s\nhistory: %v", len(h.history), fmt.Sprintf(format, args...), h.history)
+}
+
+func (h *stockInvariantHarness) close() {
+	h.db.Close()
+}
+
+// silenceLogs descarta los logs del NoOp publisher durante secuencias largas
+func silenceLogs(tb testing.TB) {
+	log.SetOutput(io.Discard)
+	tb.Cleanup(func() { log.SetOutput(os.Stderr) })
+}
+
+func TestStockInvariants_RandomSequences(t *testing.T) {
+	silenceLogs(t)
+
+	for seed := int64(1); seed <= 50; seed++ {
+		t.Run(fmt.Sprintf("Seed%d", seed), func(t *testing.T) {
+			h := newStockInvariantHarness(t)
+			defer h.close()
+
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < 80; i++ {
+				h.apply(byte(rng.Intn(256)), byte(rng.Intn(256)), byte(rng.Intn(256)))
+			}
+		})
+	}
+}
+
+// FuzzStockInvariants interpreta la entrada como tripletas (operación, tienda, argumento).
+// Ejecutar con: go test -run '^$' -fuzz FuzzStockInvariants ./test/unit/
+func FuzzStockInvariants(f *testing.F) {
+	f.Add([]byte{opReserve, 0, 9, opConfirm, 0, 0, opAdjust, 0, 0})
+	f.Add([]byte{opReserve, 1, 4, opReserve, 1, 4, opCancel, 0, 1, opTransfer, 1, 3, opExpire, 0, 0})
+	f.Add([]byte{opAdjust, 2, 20, opReserve, 2, 9, opAdjust, 2, 0, opTransfer, 2, 9})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		if len(ops) > 300 {
+			ops = ops[:300]
+		}
+		silenceLogs(t)
+
+		h := newStockInvariantHarness(t)
+		defer h.close()
+
+		for i := 0; i+2 < len(ops); i += 3 {
+			h.apply(ops[i], ops[i+1], ops[i+2])
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestStockNote_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		note domain.StockNote
+		ok   bool
+	}{
+		{"texto con adjuntos", domain.StockNote{Text: " Caja dañada ", Attachments: []domain.StockNoteAttachment{{URL: "https://files.example.com/a.jpg", ContentType: "IMAGE/JPEG"}, {URL: "http://files.example.com/a.pdf", ContentType: "application/pdf"}}}, true},
+		{"sin texto", domain.StockNote{Text: "   "}, false},
+		{"texto demasiado largo", domain.StockNote{Text: strings.Repeat("a", domain.MaxStockNoteLength+1)}, false},
+		{"url relativa", domain.StockNote{Text: "x", Attachments: []domain.StockNoteAttachment{{URL: "/a.jpg"}}}, false},
+		{"esquema no http", domain.StockNote{Text: "x", Attachments: []domain.StockNoteAttachment{{URL: "ftp://files.example.com/a.jpg"}}}, false},
+		{"tipo no permitido", domain.StockNote{Text: "x", Attachments: []domain.StockNoteAttachment{{URL: "https://files.example.com/a.exe", ContentType: "application/octet-stream"}}}, false},
+		{"tamaño negativo", domain.StockNote{Text: "x", Attachments: []domain.StockNoteAttachment{{URL: "https://files.example.com/a.jpg", SizeBytes: -1}}}, false},
+		{"demasiados adjuntos", domain.StockNote{Text: "x", Attachments: make([]domain.StockNoteAttachment, domain.MaxStockNoteAttachments+1)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.note.Validate()
+			if tt.ok && err != nil {
+				t.Errorf("Expected valid note, got %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Errorf("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestStockNoteService_NotesInHistory(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	silenceLogs(t)
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	noteRepo := repository.NewStockNoteRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, mocks.NewNoOpPublisher())
+	stockService.SetStockNoteRepository(noteRepo)
+	noteService := service.NewStockNoteService(noteRepo, stockRepo, eventRepo)
+	alice := domain.WithActor(context.Background(), "alice")
+
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(context.Background(), product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(alice, product.ID, "MAD-001", 10); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+	if _, err := stockService.InitializeStock(alice, product.ID, "BCN-001", 5); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+	if _, err := stockService.UpdateStock(alice, product.ID, "MAD-001", 7); err != nil {
+		t.Fatalf("Error updating stock: %v", err)
+	}
+	history, _ := stockService.GetStockHistory(context.Background(), product.ID, "MAD-001", domain.StockHistoryFilter{})
+	movement := history.Entries[0]
+
+	// Nota sobre el movimiento: la caja llegó con 3 unidades menos
+	note, err := noteService.AddNote(alice, product.ID, "MAD-001", &domain.StockNote{
+		EventID:     movement.EventID,
+		Text:        "Caja dañada, faltan 3 unidades",
+		Attachments: []domain.StockNoteAttachment{{URL: "https://files.example.com/albaran.jpg", ContentType: "image/jpeg", SizeBytes: 1024}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to add note: %v", err)
+	}
+	if note.ID == "" || note.CreatedBy != "alice" || len(note.Attachments) != 1 {
+		t.Errorf("Unexpected note: %+v", note)
+	}
+
+	// El movimiento tiene que ser de la fila anotada
+	bcn, _ := stockService.GetStockHistory(context.Background(), product.ID, "BCN-001", domain.StockHistoryFilter{})
+	var validationErr *domain.ValidationError
+	if _, err := noteService.AddNote(alice, product.ID, "MAD-001", &domain.StockNote{EventID: bcn.Entries[0].EventID, Text: "x"}); !errors.As(err, &validationErr) || validationErr.Field != "event_id" {
+		t.Errorf("Expected ValidationError on event_id for another row's movement, got %v", err)
+	}
+	var notFound *domain.NotFoundError
+	if _, err := noteService.AddNote(alice, product.ID, "SEV-001", &domain.StockNote{Text: "x"}); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError for a missing stock row, got %v", err)
+	}
+
+	// Nota sobre la fila entre dos movimientos
+	time.Sleep(5 * time.Millisecond)
+	if _, err := noteService.AddNote(alice, product.ID, "MAD-001", &domain.StockNote{Text: "Recuento pendiente"}); err != nil {
+		t.Fatalf("Failed to add row note: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := stockService.UpdateStock(alice, product.ID, "MAD-001", 9); err != nil {
+		t.Fatalf("Error updating stock: %v", err)
+	}
+
+	notes, err := noteService.ListNotes(context.Background(), product.ID, "MAD-001", "", 0)
+	if err != nil || len(notes) != 2 || notes[0].Text != "Recuento pendiente" {
+		t.Fatalf("Expected both notes, newest first, got %v (%v)", notes, err)
+	}
+	if notes, _ := noteService.ListNotes(context.Background(), product.ID, "MAD-001", movement.EventID, 0); len(notes) != 1 {
+		t.Errorf("Expected 1 note for the movement, got %d", len(notes))
+	}
+
+	// La primera página (último ajuste) no cubre la nota de la fila; la siguiente sí, con la del movimiento
+	page, err := stockService.GetStockHistory(context.Background(), product.ID, "MAD-001", domain.StockHistoryFilter{Limit: 1})
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	if len(page.Notes) != 0 || len(page.Entries[0].Notes) != 0 {
+		t.Errorf("Expected no notes on the first page, got %+v", page)
+	}
+	next, err := stockService.GetStockHistory(context.Background(), product.ID, "MAD-001", domain.StockHistoryFilter{Limit: 1, BeforeSeq: page.Entries[0].Seq})
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	if next.Entries[0].EventID != movement.EventID || len(next.Entries[0].Notes) != 1 || next.Entries[0].Notes[0].ID != note.ID {
+		t.Errorf("Expected the movement note on its entry, got %+v", next.Entries[0])
+	}
+	if len(next.Notes) != 1 || next.Notes[0].Text != "Recuento pendiente" {
+		t.Errorf("Expected the row note on the second page, got %+v", next.Notes)
+	}
+}
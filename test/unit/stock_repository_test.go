@@ -332,7 +332,7 @@ func TestStockRepository_GetLowStockItems(t *testing.T) {
 	}
 
 	// Buscar items con stock bajo (threshold = 5)
-	lowItems, err := repo.GetLowStockItems(ctx, 5)
+	lowItems, err := repo.GetLowStockItems(ctx, domain.LowStockFilter{Threshold: 5})
 	if err != nil {
 		t.Fatalf("Failed to get low stock items: %v", err)
 	}
@@ -0,0 +1,158 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestScheduledStockChanges(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewMockPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+	scheduleRepo := repository.NewStockScheduleRepository(db)
+	scheduleService := service.NewStockScheduleService(scheduleRepo, stockService)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+
+	ctx := domain.WithActor(context.Background(), "merchandising")
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(ctx, product.ID, "MAD-001", 10); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+
+	makeDue := func(id string) {
+		db.Exec(`UPDATE scheduled_stock_changes SET effective_at = ? WHERE id = ?`, time.Now().Add(-time.Second), id)
+	}
+
+	t.Run("Validation", func(t *testing.T) {
+		var validation *domain.ValidationError
+		_, err := scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+			ProductID: product.ID, StoreID: "MAD-001", Quantity: 5, EffectiveAt: time.Now().Add(-time.Minute),
+		})
+		if !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for past effective_at, got %v", err)
+		}
+		_, err = scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+			ProductID: product.ID, StoreID: "MAD-001", Type: "MOVE", Quantity: 5, EffectiveAt: time.Now().Add(time.Hour),
+		})
+		if !errors.As(err, &validation) {
+			t.Errorf("Expected ValidationError for unknown type, got %v", err)
+		}
+		var notFound *domain.NotFoundError
+		_, err = scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+			ProductID: product.ID, StoreID: "XXX-999", Quantity: 5, EffectiveAt: time.Now().Add(time.Hour),
+		})
+		if !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError for missing stock row, got %v", err)
+		}
+	})
+
+	t.Run("AppliedWhenDue", func(t *testing.T) {
+		schedule, err := scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+			ProductID: product.ID, StoreID: "MAD-001", Quantity: 500, EffectiveAt: time.Now().Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("Error scheduling stock change: %v", err)
+		}
+		if schedule.Type != domain.ScheduledStockAdjust || schedule.Actor != "merchandising" {
+			t.Errorf("Expected ADJUST scheduled by merchandising, got %+v", schedule)
+		}
+
+		if applied, _ := scheduleService.ProcessScheduledStockChanges(ctx); applied != 0 {
+			t.Fatalf("Expected no changes applied before effective_at, got %d", applied)
+		}
+
+		makeDue(schedule.ID)
+		applied, err := scheduleService.ProcessScheduledStockChanges(ctx)
+		if err != nil || applied != 1 {
+			t.Fatalf("Expected 1 change applied, got %d (%v)", applied, err)
+		}
+
+		stock, _ := stockService.GetStockByProductAndStore(ctx, product.ID, "MAD-001")
+		if stock.Quantity != 510 {
+			t.Errorf("Expected quantity 510, got %d", stock.Quantity)
+		}
+		if event := publisher.GetLastEvent(); event == nil || event.EventType != domain.EventStockUpdated {
+			t.Errorf("Expected stock.updated event, got %+v", event)
+		}
+
+		// Aplicado una sola vez
+		if applied, _ := scheduleService.ProcessScheduledStockChanges(ctx); applied != 0 {
+			t.Errorf("Expected change applied only once, got %d", applied)
+		}
+	})
+
+	t.Run("FailsBelowReserved", func(t *testing.T) {
+		if _, err := reservationService.CreateReservation(ctx, product.ID, "MAD-001", "customer-1", 20, 0); err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+		schedule, err := scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+			ProductID: product.ID, StoreID: "MAD-001", Type: domain.ScheduledStockSet, Quantity: 5, EffectiveAt: time.Now().Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("Error scheduling stock change: %v", err)
+		}
+
+		makeDue(schedule.ID)
+		if applied, _ := scheduleService.ProcessScheduledStockChanges(ctx); applied != 0 {
+			t.Errorf("Expected change below reserved not applied, got %d", applied)
+		}
+
+		failed, _ := scheduleRepo.Get(ctx, schedule.ID)
+		if failed.Status != domain.ScheduledStockFailed || failed.Error == "" {
+			t.Errorf("Expected FAILED with reason, got %+v", failed)
+		}
+		stock, _ := stockService.GetStockByProductAndStore(ctx, product.ID, "MAD-001")
+		if stock.Quantity != 510 {
+			t.Errorf("Expected quantity unchanged at 510, got %d", stock.Quantity)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		schedule, err := scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+			ProductID: product.ID, StoreID: "MAD-001", Quantity: -10, EffectiveAt: time.Now().Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("Error scheduling stock change: %v", err)
+		}
+
+		var notFound *domain.NotFoundError
+		if _, err := scheduleService.CancelScheduledStockChange(ctx, product.ID, "BCN-001", schedule.ID); !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError cancelling from another store, got %v", err)
+		}
+
+		cancelled, err := scheduleService.CancelScheduledStockChange(ctx, product.ID, "MAD-001", schedule.ID)
+		if err != nil || cancelled.Status != domain.ScheduledStockCancelled {
+			t.Fatalf("Expected cancelled change, got %+v (%v)", cancelled, err)
+		}
+
+		var conflict *domain.ConflictError
+		if _, err := scheduleService.CancelScheduledStockChange(ctx, product.ID, "MAD-001", schedule.ID); !errors.As(err, &conflict) {
+			t.Errorf("Expected ConflictError cancelling twice, got %v", err)
+		}
+
+		makeDue(schedule.ID)
+		if applied, _ := scheduleService.ProcessScheduledStockChanges(ctx); applied != 0 {
+			t.Errorf("Expected cancelled change not applied, got %d", applied)
+		}
+
+		schedules, err := scheduleService.ListScheduledStockChanges(ctx, product.ID, "MAD-001")
+		if err != nil || len(schedules) != 3 {
+			t.Errorf("Expected 3 scheduled changes listed, got %d (%v)", len(schedules), err)
+		}
+	})
+}
@@ -436,21 +436,145 @@ func TestStockService_GetLowStockItems(t *testing.T) {
 		}
 
 		threshold := 10
-		lowStocks, err := stockService.GetLowStockItems(ctx, threshold)
+		lowStocks, total, err := stockService.GetLowStockItems(ctx, domain.LowStockFilter{StoreID: "STORE-LOW", Threshold: threshold})
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
 
-		// Since we can't filter by storeID, we check if at least 2 low stock items were found
-		if len(lowStocks) < 2 {
-			t.Errorf("Expected at least 2 low stock items, got %d", len(lowStocks))
+		if len(lowStocks) != 2 || total != 2 {
+			t.Fatalf("Expected 2 low stock items in STORE-LOW, got %d (total %d)", len(lowStocks), total)
 		}
 
-		// Verify all returned items are below threshold
-		for _, stock := range lowStocks {
-			if stock.Quantity >= threshold {
-				t.Errorf("Expected quantity < %d, got %d", threshold, stock.Quantity)
-			}
+		// Ordenados de menor a mayor disponibilidad
+		if lowStocks[0].ID != stock3.ID || lowStocks[1].ID != stock1.ID {
+			t.Errorf("Expected [%s %s], got [%s %s]", stock3.ID, stock1.ID, lowStocks[0].ID, lowStocks[1].ID)
+		}
+	})
+
+	t.Run("GetLowStockItems_MinStockOverridesThreshold", func(t *testing.T) {
+		product := testutil.CreateTestProduct(func(p *domain.Product) {
+			p.SKU = "LOW-STOCK-MIN"
+			p.Category = "low-stock-category"
+		})
+		if err := productRepo.Create(ctx, product); err != nil {
+			t.Fatalf("Error creating product: %v", err)
+		}
+
+		// 20 disponibles: por encima del umbral de la consulta pero por debajo de su min_stock
+		withMin := testutil.CreateTestStock(product.ID, "STORE-MIN", func(s *domain.Stock) {
+			s.Quantity = 20
+			s.MinStock = 25
+		})
+		if err := stockRepo.Create(ctx, withMin); err != nil {
+			t.Fatalf("Error creating stock: %v", err)
+		}
+
+		// 5 disponibles con min_stock 3: no es stock bajo aunque esté por debajo del umbral
+		aboveMin := testutil.CreateTestStock(product.ID, "STORE-MIN-2", func(s *domain.Stock) {
+			s.Quantity = 5
+			s.MinStock = 3
+		})
+		if err := stockRepo.Create(ctx, aboveMin); err != nil {
+			t.Fatalf("Error creating stock: %v", err)
+		}
+
+		lowStocks, total, err := stockService.GetLowStockItems(ctx, domain.LowStockFilter{Category: "low-stock-category", Threshold: 10})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if total != 1 || len(lowStocks) != 1 || lowStocks[0].ID != withMin.ID {
+			t.Errorf("Expected only %s, got %d items (total %d)", withMin.ID, len(lowStocks), total)
+		}
+	})
+
+	t.Run("GetLowStockItems_Pagination", func(t *testing.T) {
+		page, total, err := stockService.GetLowStockItems(ctx, domain.LowStockFilter{StoreID: "STORE-LOW", Threshold: 10, Limit: 1, Offset: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if total != 2 || len(page) != 1 {
+			t.Errorf("Expected 1 item of 2, got %d of %d", len(page), total)
+		}
+
+		if _, _, err := stockService.GetLowStockItems(ctx, domain.LowStockFilter{Threshold: -1}); err == nil {
+			t.Error("Expected validation error for negative threshold")
+		}
+	})
+}
+
+func TestStockService_CompareStores(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+
+	ctx := context.Background()
+
+	t.Run("CompareStores_Disparities", func(t *testing.T) {
+		comparison, err := stockService.CompareStores(ctx, "MAD-001", "VAL-001", 10)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if comparison.Summary.Common != 5 {
+			t.Errorf("Expected 5 common products, got %d", comparison.Summary.Common)
+		}
+		if len(comparison.OnlyInA) != 0 || len(comparison.OnlyInB) != 0 {
+			t.Errorf("Expected no exclusive products, got %d/%d", len(comparison.OnlyInA), len(comparison.OnlyInB))
+		}
+
+		// MAD-001 vs VAL-001: producto 0002 (18 vs 0) y 0001 (45 vs 35)
+		if len(comparison.Disparities) != 2 {
+			t.Fatalf("Expected 2 disparities, got %d", len(comparison.Disparities))
+		}
+		first := comparison.Disparities[0]
+		if first.ProductID != "550e8400-e29b-41d4-a716-446655440002" || first.Difference != 18 {
+			t.Errorf("Expected largest disparity first, got %+v", first)
+		}
+		if first.SurplusStore != "MAD-001" || first.ShortageStore != "VAL-001" {
+			t.Errorf("Expected MAD-001 as surplus store, got %+v", first)
+		}
+	})
+
+	t.Run("CompareStores_OnlyInOneStore", func(t *testing.T) {
+		product := testutil.CreateTestProduct(func(p *domain.Product) {
+			p.SKU = "COMPARE-001"
+		})
+		if err := productRepo.Create(ctx, product); err != nil {
+			t.Fatalf("Error creating product: %v", err)
+		}
+		if _, err := stockService.InitializeStock(ctx, product.ID, "VAL-001", 30); err != nil {
+			t.Fatalf("Error initializing stock: %v", err)
+		}
+
+		comparison, err := stockService.CompareStores(ctx, "MAD-001", "VAL-001", 10)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(comparison.OnlyInB) != 1 || comparison.OnlyInB[0].ProductID != product.ID {
+			t.Errorf("Expected new product only in VAL-001, got %+v", comparison.OnlyInB)
+		}
+		if comparison.Summary.OnlyInB != 1 {
+			t.Errorf("Expected summary onlyInB 1, got %d", comparison.Summary.OnlyInB)
+		}
+	})
+
+	t.Run("CompareStores_SameStore", func(t *testing.T) {
+		_, err := stockService.CompareStores(ctx, "MAD-001", "MAD-001", 10)
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("CompareStores_MissingStore", func(t *testing.T) {
+		_, err := stockService.CompareStores(ctx, "MAD-001", "", 10)
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
 		}
 	})
 }
@@ -0,0 +1,82 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestReservationShortageDiagnostics(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	publisher := mocks.NewNoOpPublisher()
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo, repository.NewEventRepository(db), publisher)
+	scheduleService := service.NewStockScheduleService(repository.NewStockScheduleRepository(db), stockService)
+
+	silenceLogs(t)
+	ctx := domain.WithActor(context.Background(), "web")
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if _, err := stockService.InitializeStock(ctx, product.ID, "MAD-001", 7); err != nil {
+		t.Fatalf("Error initializing stock: %v", err)
+	}
+
+	soon, err := reservationService.CreateReservation(ctx, product.ID, "MAD-001", "customer-1", 3, 10)
+	if err != nil {
+		t.Fatalf("Error creating reservation: %v", err)
+	}
+	later, err := reservationService.CreateReservation(ctx, product.ID, "MAD-001", "customer-2", 2, 30)
+	if err != nil {
+		t.Fatalf("Error creating reservation: %v", err)
+	}
+
+	var insufficient *domain.InsufficientStockError
+	_, err = reservationService.CreateReservation(ctx, product.ID, "MAD-001", "customer-3", 6, 15)
+	if !errors.As(err, &insufficient) || insufficient.Shortage == nil {
+		t.Fatalf("Expected InsufficientStockError with diagnostics, got %v", err)
+	}
+	shortage := insufficient.Shortage
+	if shortage.Quantity != 7 || shortage.Reserved != 5 || shortage.Available != 2 || shortage.Requested != 6 {
+		t.Errorf("Unexpected stock figures: %+v", shortage)
+	}
+	if shortage.NextExpiryAt == nil || !shortage.NextExpiryAt.Equal(soon.ExpiresAt) || shortage.NextExpiryUnits != 3 {
+		t.Errorf("Expected 3 units freed at %v, got %d at %v", soon.ExpiresAt, shortage.NextExpiryUnits, shortage.NextExpiryAt)
+	}
+	if shortage.SufficientAt == nil || !shortage.SufficientAt.Equal(later.ExpiresAt) {
+		t.Errorf("Expected the request to fit at %v, got %v", later.ExpiresAt, shortage.SufficientAt)
+	}
+	if shortage.PreorderPossible || shortage.PreorderAvailable != 0 {
+		t.Errorf("Expected no preorder without inbound stock, got %+v", shortage)
+	}
+
+	// Con una llegada programada se ofrece la preventa; las caducidades no alcanzan para 10 unidades
+	if _, err := scheduleService.ScheduleStockChange(ctx, &domain.ScheduledStockChange{
+		ProductID: product.ID, StoreID: "MAD-001", Quantity: 12, Source: domain.ScheduledStockPurchaseOrder,
+		EffectiveAt: time.Now().Add(24 * time.Hour),
+	}); err != nil {
+		t.Fatalf("Error scheduling inbound stock: %v", err)
+	}
+	_, err = reservationService.CreateReservation(ctx, product.ID, "MAD-001", "customer-3", 10, 15)
+	if !errors.As(err, &insufficient) || insufficient.Shortage == nil {
+		t.Fatalf("Expected InsufficientStockError with diagnostics, got %v", err)
+	}
+	if insufficient.Shortage.SufficientAt != nil {
+		t.Errorf("Expected no sufficient_at when expiries cannot cover the request, got %v", insufficient.Shortage.SufficientAt)
+	}
+	if !insufficient.Shortage.PreorderPossible || insufficient.Shortage.PreorderAvailable != 12 {
+		t.Errorf("Expected a preorder of 12 inbound units to be possible, got %+v", insufficient.Shortage)
+	}
+}
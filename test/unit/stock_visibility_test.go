@@ -0,0 +1,98 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStockVisibility_Status(t *testing.T) {
+	visibility := &domain.StockVisibility{LowStockThreshold: 5}
+
+	for _, tc := range []struct {
+		stock      domain.Stock
+		visibility *domain.StockVisibility
+		expected   domain.AvailabilityStatus
+	}{
+		{domain.Stock{Quantity: 10, Reserved: 10}, visibility, domain.AvailabilityOutOfStock},
+		{domain.Stock{Quantity: 10, SafetyStock: 5}, visibility, domain.AvailabilityLowStock},
+		{domain.Stock{Quantity: 6}, visibility, domain.AvailabilityInStock},
+		{domain.Stock{Quantity: 6}, nil, domain.AvailabilityLowStock}, // Sin configuración: DefaultMinStock
+		{domain.Stock{Quantity: 6, MinStock: 3}, nil, domain.AvailabilityInStock},
+	} {
+		if got := tc.visibility.Status(&tc.stock); got != tc.expected {
+			t.Errorf("%+v: expected %s, got %s", tc.stock, tc.expected, got)
+		}
+	}
+}
+
+func TestProductAvailability_HidesQuantitiesFromPublic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockService := service.NewStockService(repository.NewStockRepository(db), repository.NewProductRepository(db), repository.NewEventRepository(db), mocks.NewNoOpPublisher())
+	visibilityRepo := repository.NewStockVisibilityRepository(db)
+	stockService.SetStockVisibilityRepository(visibilityRepo)
+
+	router := gin.New()
+	router.GET("/api/v1/products/:id/availability", middleware.OptionalAPIKeyAuth(auth.NewKeyRing(map[string]string{"test-key": "Test Store"})), handler.NewStockHandler(stockService).GetProductAvailability)
+
+	// Producto 0002: MAD-001 con 18 vendibles, VAL-001 sin stock
+	productID := "550e8400-e29b-41d4-a716-446655440002"
+	if err := visibilityRepo.Upsert(context.Background(), &domain.StockVisibility{StoreID: "MAD-001", HideQuantities: true, LowStockThreshold: 20, UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("Error saving visibility: %v", err)
+	}
+
+	get := func(key string) map[string]*domain.StoreAvailability {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/products/"+productID+"/availability", nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var body domain.ProductAvailability
+		json.Unmarshal(w.Body.Bytes(), &body)
+		stores := make(map[string]*domain.StoreAvailability)
+		for _, store := range body.Stores {
+			stores[store.StoreID] = store
+		}
+		return stores
+	}
+
+	public := get("")
+	if madrid := public["MAD-001"]; madrid.Available != nil || madrid.Status != domain.AvailabilityLowStock {
+		t.Errorf("Expected MAD-001 to show only low_stock to the public, got %+v", madrid)
+	}
+	if valencia := public["VAL-001"]; valencia.Available == nil || *valencia.Available != 0 || valencia.Status != domain.AvailabilityOutOfStock {
+		t.Errorf("Expected VAL-001 quantity to stay visible, got %+v", valencia)
+	}
+
+	// Una key inválida se trata como petición pública
+	if madrid := get("wrong-key")["MAD-001"]; madrid.Available != nil {
+		t.Errorf("Expected invalid key to get buckets only, got %+v", madrid)
+	}
+
+	if madrid := get("test-key")["MAD-001"]; madrid.Available == nil || *madrid.Available != 18 {
+		t.Errorf("Expected authenticated call to get 18 units in MAD-001, got %+v", madrid)
+	}
+}
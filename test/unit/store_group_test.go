@@ -0,0 +1,183 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestStoreGroups(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	publisher := mocks.NewMockPublisher()
+	groupRepo := repository.NewStoreGroupRepository(db)
+	groupService := service.NewStoreGroupService(groupRepo)
+
+	reportService := service.NewReportService(repository.NewReportRepository(db))
+	reportService.SetStoreGroupRepository(groupRepo)
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	stockService.SetStoreGroupRepository(groupRepo)
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+	reservationService.SetStoreGroupRepository(groupRepo)
+	transferReservationService := service.NewTransferReservationService(reservationService, reservationRepo, stockRepo, repository.NewTransferRepository(db), eventRepo, publisher)
+	transferReservationService.SetStoreGroupRepository(groupRepo)
+
+	const laptop = "550e8400-e29b-41d4-a716-446655440000"
+	const monitor = "550e8400-e29b-41d4-a716-446655440003"
+
+	t.Run("Manage", func(t *testing.T) {
+		groups := []*domain.StoreGroup{
+			{ID: "este", Name: "Este", Type: "region", RestrictTransfers: true, StoreIDs: []string{"VAL-001", "BCN-001", "VAL-001"}},
+			{ID: "centro-sur", Name: "Centro y Sur", Type: domain.StoreGroupRegion, StoreIDs: []string{"MAD-001", "SEV-001"}},
+			{ID: "franquicia-val", Name: "Franquicia Valencia", Type: domain.StoreGroupFranchise, StoreIDs: []string{"VAL-001"}},
+		}
+		for _, group := range groups {
+			if _, err := groupService.CreateGroup(ctx, group); err != nil {
+				t.Fatalf("Error creating group %s: %v", group.ID, err)
+			}
+		}
+
+		este, err := groupService.GetGroup(ctx, "este")
+		if err != nil {
+			t.Fatalf("Error getting group: %v", err)
+		}
+		if este.Type != domain.StoreGroupRegion || len(este.StoreIDs) != 2 || !este.RestrictTransfers {
+			t.Errorf("Expected normalized REGION group with 2 stores, got %+v", este)
+		}
+
+		var conflict *domain.ConflictError
+		_, err = groupService.CreateGroup(ctx, &domain.StoreGroup{Name: "Norte", Type: domain.StoreGroupRegion, StoreIDs: []string{"BCN-001"}})
+		if !errors.As(err, &conflict) {
+			t.Errorf("Expected ConflictError for a store already in a REGION group, got %v", err)
+		}
+		if _, err := groupService.CreateGroup(ctx, &domain.StoreGroup{ID: "este", Name: "Otra", Type: domain.StoreGroupFranchise}); !errors.As(err, &conflict) {
+			t.Errorf("Expected ConflictError for a duplicated ID, got %v", err)
+		}
+
+		regions, err := groupService.ListGroups(ctx, "REGION")
+		if err != nil || len(regions) != 2 {
+			t.Errorf("Expected 2 regions, got %d (%v)", len(regions), err)
+		}
+	})
+
+	t.Run("Overview", func(t *testing.T) {
+		overview, err := reportService.GetOverview(ctx, "", "", 5)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(overview.Groups) != 3 {
+			t.Fatalf("Expected 3 group totals, got %d", len(overview.Groups))
+		}
+		for _, group := range overview.Groups {
+			if group.GroupID == "este" && (group.Stores != 2 || group.StockRows != 10 || group.Available != 144 || group.OutOfStock != 1) {
+				t.Errorf("Unexpected totals for este: %+v", group)
+			}
+		}
+
+		filtered, err := reportService.GetOverview(ctx, "", "centro-sur", 5)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(filtered.Stores) != 2 || filtered.OutOfStockCount != 0 || len(filtered.Groups) != 1 {
+			t.Errorf("Expected 2 stores without out of stock rows, got %+v", filtered)
+		}
+
+		var notFound *domain.NotFoundError
+		if _, err := reportService.GetOverview(ctx, "", "missing", 5); !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError for an unknown group, got %v", err)
+		}
+	})
+
+	t.Run("OutOfStock", func(t *testing.T) {
+		report, err := reportService.GetOutOfStockReport(ctx, "", "este")
+		if err != nil || report.Count != 1 || report.Items[0].StoreID != "VAL-001" {
+			t.Errorf("Expected VAL-001 out of stock row, got %+v (%v)", report, err)
+		}
+
+		report, err = reportService.GetOutOfStockReport(ctx, "", "centro-sur")
+		if err != nil || report.Count != 0 {
+			t.Errorf("Expected no rows for centro-sur, got %+v (%v)", report, err)
+		}
+	})
+
+	t.Run("ReservationStats", func(t *testing.T) {
+		if _, err := reservationService.CreateReservation(ctx, laptop, "VAL-001", "customer-1", 1, 15); err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+		if _, err := reservationService.CreateReservation(ctx, laptop, "MAD-001", "customer-2", 1, 15); err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+
+		stats, err := reservationService.GetReservationStats(ctx, time.Hour, "este")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats.TotalReservations != 1 || stats.ByStore["MAD-001"] != 0 || stats.Window.Created != 1 {
+			t.Errorf("Expected only the VAL-001 reservation, got %+v", stats)
+		}
+
+		stats, err = reservationService.GetReservationStats(ctx, time.Hour, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats.ByGroup["este"] != 1 || stats.ByGroup["centro-sur"] != 1 || stats.ByGroup["franquicia-val"] != 1 {
+			t.Errorf("Unexpected by_group: %v", stats.ByGroup)
+		}
+	})
+
+	t.Run("TransferRestriction", func(t *testing.T) {
+		var conflict *domain.ConflictError
+		if err := stockService.TransferStock(ctx, laptop, "VAL-001", "MAD-001", 1); !errors.As(err, &conflict) {
+			t.Errorf("Expected ConflictError leaving a restricted group, got %v", err)
+		}
+		if err := stockService.TransferStock(ctx, laptop, "MAD-001", "BCN-001", 1); !errors.As(err, &conflict) {
+			t.Errorf("Expected ConflictError entering a restricted group, got %v", err)
+		}
+		if err := stockService.TransferStock(ctx, laptop, "BCN-001", "VAL-001", 1); err != nil {
+			t.Errorf("Expected transfer within the group, got %v", err)
+		}
+		if err := stockService.TransferStock(ctx, laptop, "MAD-001", "SEV-001", 1); err != nil {
+			t.Errorf("Expected transfer within an unrestricted group, got %v", err)
+		}
+
+		// BCN-001 tiene más monitores, pero el grupo este no puede servir a MAD-001
+		combined, err := transferReservationService.CreateTransferReservation(ctx, monitor, "MAD-001", "", "customer-3", 6, 15)
+		if err != nil {
+			t.Fatalf("Error creating transfer reservation: %v", err)
+		}
+		if combined.SourceStoreID != "SEV-001" {
+			t.Errorf("Expected SEV-001 as source, got %s", combined.SourceStoreID)
+		}
+		if _, err := transferReservationService.CreateTransferReservation(ctx, monitor, "MAD-001", "BCN-001", "customer-3", 6, 15); !errors.As(err, &conflict) {
+			t.Errorf("Expected ConflictError for an explicit source outside the group, got %v", err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := groupService.DeleteGroup(ctx, "este"); err != nil {
+			t.Fatalf("Error deleting group: %v", err)
+		}
+		if err := stockService.TransferStock(ctx, laptop, "VAL-001", "MAD-001", 1); err != nil {
+			t.Errorf("Expected transfer allowed once the group is deleted, got %v", err)
+		}
+
+		var notFound *domain.NotFoundError
+		if err := groupService.DeleteGroup(ctx, "este"); !errors.As(err, &notFound) {
+			t.Errorf("Expected NotFoundError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestStoreSyncStatus(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	lastSync := now.Add(-40 * time.Minute)
+
+	upToDate := &domain.StoreHeartbeat{InstanceID: "edge-bcn", StoreID: "BCN-001", ReceivedAt: now.Add(-time.Minute), LastSyncAt: &lastSync}
+	behind := &domain.StoreHeartbeat{InstanceID: "edge-mad", StoreID: "MAD-001", PendingEvents: 8, ReceivedAt: now.Add(-time.Minute), LastSyncAt: &lastSync}
+	neverSynced := &domain.StoreHeartbeat{InstanceID: "edge-val", StoreID: "VAL-001", PendingEvents: 3, ReceivedAt: now.Add(-5 * time.Minute)}
+
+	report := domain.NewStoreSyncReport([]*domain.StoreHeartbeat{upToDate, behind, neverSynced}, now, 15*time.Minute, false)
+	if report.Count != 3 || report.StaleCount != 1 {
+		t.Fatalf("Expected 3 stores with 1 stale, got %d with %d stale", report.Count, report.StaleCount)
+	}
+	// Sin eventos pendientes la central está al día en el momento del latido
+	if first := report.Stores[0]; first.InstanceID != "edge-mad" || !first.Stale || first.LagMinutes != 40 {
+		t.Errorf("Expected edge-mad 40 minutes behind first, got %+v", first)
+	}
+	if last := report.Stores[2]; last.InstanceID != "edge-bcn" || last.Stale || last.LagMinutes != 1 {
+		t.Errorf("Expected edge-bcn up to date last, got %+v", last)
+	}
+	if report.Stores[1].SyncedThrough != nil || report.Stores[1].Stale {
+		t.Errorf("Expected a never synced instance to count from its heartbeat, got %+v", report.Stores[1])
+	}
+
+	if stale := domain.NewStoreSyncReport([]*domain.StoreHeartbeat{upToDate, behind}, now, 15*time.Minute, true); stale.Count != 1 || stale.Stores[0].InstanceID != "edge-mad" {
+		t.Errorf("Expected only the stale store with stale=true, got %+v", stale.Stores)
+	}
+}
+
+func TestStoreHeartbeatService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	silenceLogs(t)
+	eventRepo := repository.NewEventRepository(db)
+	heartbeatService := service.NewStoreHeartbeatService(repository.NewStoreHeartbeatRepository(db), eventRepo, mocks.NewNoOpPublisher(), 15*time.Minute)
+	ctx := context.Background()
+
+	var validationErr *domain.ValidationError
+	if err := heartbeatService.RecordHeartbeat(ctx, &domain.StoreHeartbeat{StoreID: "MAD-001"}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError without instance_id, got %v", err)
+	}
+	var forbidden *domain.ForbiddenError
+	scoped := domain.WithStoreScope(ctx, []string{"BCN-001"})
+	if err := heartbeatService.RecordHeartbeat(scoped, &domain.StoreHeartbeat{InstanceID: "edge-mad", StoreID: "MAD-001"}); !errors.As(err, &forbidden) {
+		t.Errorf("Expected ForbiddenError for another store, got %v", err)
+	}
+
+	lastSync := time.Now().Add(-time.Minute)
+	heartbeat := func(pending int, lastSync *time.Time) {
+		t.Helper()
+		if err := heartbeatService.RecordHeartbeat(ctx, &domain.StoreHeartbeat{InstanceID: "edge-mad", StoreID: "MAD-001", PendingEvents: pending, LastSyncAt: lastSync}); err != nil {
+			t.Fatalf("Error recording heartbeat: %v", err)
+		}
+	}
+	alerts := func() int {
+		t.Helper()
+		events, err := eventRepo.GetEventsByType(ctx, domain.EventStoreSyncStale, 100, 0)
+		if err != nil {
+			t.Fatalf("Error listing events: %v", err)
+		}
+		return len(events)
+	}
+
+	heartbeat(2, &lastSync)
+	if alerted, err := heartbeatService.AlertStaleStores(ctx); err != nil || len(alerted) != 0 {
+		t.Fatalf("Expected no alerts for a synced store, got %v (err: %v)", alerted, err)
+	}
+
+	// Sigue enviando latidos pero no sincroniza desde hace 30 minutos (se conserva last_sync_at)
+	db.Exec(`UPDATE store_heartbeats SET last_sync_at = ? WHERE instance_id = 'edge-mad'`, time.Now().Add(-30*time.Minute))
+	heartbeat(25, nil)
+	status, err := heartbeatService.GetSyncStatus(ctx, "MAD-001", true)
+	if err != nil || status.Count != 1 || status.Stores[0].PendingEvents != 25 || status.Stores[0].LastSyncAt == nil {
+		t.Fatalf("Expected MAD-001 to be stale with 25 pending events, got %+v (err: %v)", status, err)
+	}
+	if alerted, err := heartbeatService.AlertStaleStores(ctx); err != nil || len(alerted) != 1 {
+		t.Fatalf("Expected one alert, got %v (err: %v)", alerted, err)
+	}
+	if _, err := heartbeatService.AlertStaleStores(ctx); err != nil || alerts() != 1 {
+		t.Errorf("Expected a single store.sync_stale while the store stays behind, got %d (err: %v)", alerts(), err)
+	}
+
+	// Se recupera y vuelve a atrasarse: nueva alerta
+	heartbeat(0, nil)
+	if status, _ := heartbeatService.GetSyncStatus(ctx, "", true); status.Count != 0 {
+		t.Errorf("Expected no stale stores after syncing, got %+v", status.Stores)
+	}
+	db.Exec(`UPDATE store_heartbeats SET alerted_at = ?, received_at = ? WHERE instance_id = 'edge-mad'`,
+		time.Now().Add(-40*time.Minute), time.Now().Add(-20*time.Minute))
+	if _, err := heartbeatService.AlertStaleStores(ctx); err != nil || alerts() != 2 {
+		t.Errorf("Expected a new alert once the store falls behind again, got %d (err: %v)", alerts(), err)
+	}
+}
@@ -0,0 +1,134 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+// madridHours lunes a sábado 10:00-14:00 y 17:00-21:00, domingo cerrado
+func madridHours() *domain.StoreHours {
+	var periods []domain.OpeningPeriod
+	for _, day := range []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday"} {
+		periods = append(periods,
+			domain.OpeningPeriod{Day: day, Open: "10:00", Close: "14:00"},
+			domain.OpeningPeriod{Day: day, Open: "17:00", Close: "21:00"},
+		)
+	}
+	return &domain.StoreHours{StoreID: "MAD-001", Timezone: "Europe/Madrid", Periods: periods, CutoffMinutes: 15}
+}
+
+func TestStoreHours_Schedule(t *testing.T) {
+	hours := madridHours()
+	if err := hours.Validate(); err != nil {
+		t.Fatalf("Expected valid schedule, got %v", err)
+	}
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 10, day, hour, minute, 0, 0, madrid) } // 12 = lunes
+
+	t.Run("Reservable", func(t *testing.T) {
+		if err := hours.CheckReservable(at(12, 11, 0)); err != nil {
+			t.Errorf("Expected open at 11:00, got %v", err)
+		}
+
+		var closed *domain.StoreClosedError
+		if err := hours.CheckReservable(at(12, 15, 0)); !errors.As(err, &closed) || !closed.NextOpening.Equal(at(12, 17, 0)) {
+			t.Errorf("Expected closed until 17:00, got %v", err)
+		}
+		if err := hours.CheckReservable(at(12, 13, 50)); !errors.As(err, &closed) || !closed.NextOpening.Equal(at(12, 17, 0)) {
+			t.Errorf("Expected cutoff before 14:00 to point at 17:00, got %v", err)
+		}
+		if err := hours.CheckReservable(at(18, 12, 0)); !errors.As(err, &closed) || !closed.NextOpening.Equal(at(19, 10, 0)) {
+			t.Errorf("Expected Sunday closed until Monday 10:00, got %v", err)
+		}
+	})
+
+	t.Run("TTLSkipsClosedHours", func(t *testing.T) {
+		if got := hours.ExpiresAt(at(12, 11, 0), 30*time.Minute); !got.Equal(at(12, 11, 30)) {
+			t.Errorf("Expected 11:30, got %v", got)
+		}
+		if got := hours.ExpiresAt(at(12, 13, 40), 30*time.Minute); !got.Equal(at(12, 17, 10)) {
+			t.Errorf("Expected 17:10 after the midday break, got %v", got)
+		}
+		if got := hours.ExpiresAt(at(17, 20, 50), 30*time.Minute); !got.Equal(at(19, 10, 20)) {
+			t.Errorf("Expected Monday 10:20 across Sunday, got %v", got)
+		}
+	})
+
+	t.Run("RejectsInvalidSchedules", func(t *testing.T) {
+		invalid := []*domain.StoreHours{
+			{Timezone: "Mars/Olympus", Periods: []domain.OpeningPeriod{{Day: "monday", Open: "09:00", Close: "10:00"}}},
+			{Timezone: "Europe/Madrid", Periods: []domain.OpeningPeriod{{Day: "funday", Open: "09:00", Close: "10:00"}}},
+			{Timezone: "Europe/Madrid", Periods: []domain.OpeningPeriod{{Day: "monday", Open: "22:00", Close: "02:00"}}},
+			{Timezone: "Europe/Madrid", Periods: []domain.OpeningPeriod{
+				{Day: "monday", Open: "09:00", Close: "14:00"},
+				{Day: "monday", Open: "13:00", Close: "20:00"},
+			}},
+		}
+		for i, hours := range invalid {
+			if err := hours.Validate(); err == nil {
+				t.Errorf("Case %d: expected validation error", i)
+			}
+		}
+	})
+}
+
+func TestReservationService_StoreHours(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	hoursRepo := repository.NewStoreHoursRepository(db)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(db), stockRepo, productRepo,
+		repository.NewEventRepository(db), mocks.NewMockPublisher())
+	reservationService.SetStoreHoursRepository(hoursRepo)
+
+	ctx := context.Background()
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	if err := stockRepo.Create(ctx, testutil.CreateTestStock(product.ID, "HRS-001")); err != nil {
+		t.Fatalf("Error creating stock: %v", err)
+	}
+
+	// Abierta todo el día salvo hoy: la reserva debe rechazarse
+	today := strings.ToLower(time.Now().UTC().Weekday().String())
+	var periods []domain.OpeningPeriod
+	for _, day := range []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"} {
+		if day != today {
+			periods = append(periods, domain.OpeningPeriod{Day: day, Open: "00:00", Close: "23:59"})
+		}
+	}
+	hours := &domain.StoreHours{StoreID: "HRS-001", Timezone: "UTC", Periods: periods}
+	if err := hoursRepo.Upsert(ctx, hours); err != nil {
+		t.Fatalf("Error saving store hours: %v", err)
+	}
+
+	_, err := reservationService.CreateReservation(ctx, product.ID, "HRS-001", "customer-1", 1, 30)
+	var closed *domain.StoreClosedError
+	if !errors.As(err, &closed) {
+		t.Fatalf("Expected StoreClosedError, got %v", err)
+	}
+	stock, _ := stockRepo.GetByProductAndStore(ctx, product.ID, "HRS-001")
+	if stock.Reserved != 0 {
+		t.Errorf("Expected no stock reserved for a closed store, got %d", stock.Reserved)
+	}
+
+	// Sin horario la tienda admite reservas a cualquier hora
+	if err := hoursRepo.Delete(ctx, "HRS-001"); err != nil {
+		t.Fatalf("Error deleting store hours: %v", err)
+	}
+	if _, err := reservationService.CreateReservation(ctx, product.ID, "HRS-001", "customer-1", 1, 30); err != nil {
+		t.Errorf("Expected reservation without schedule, got %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestKeyRing_StoreScope(t *testing.T) {
+	ring := auth.NewKeyRing(map[string]string{
+		"key-madrid":    "Store Madrid",
+		"key-logistics": "Logistics",
+		"key-ecommerce": "Ecommerce",
+		"key-reports":   "Reports",
+	})
+
+	// Sin scopes configurados todas las keys son multi-tienda
+	if got := ring.StoreScope("key-madrid"); got != nil {
+		t.Errorf("Expected multi-store scope without API_KEY_STORE_SCOPES, got %v", got)
+	}
+	if names := ring.UnscopedNames(); len(names) != 4 {
+		t.Errorf("Expected every key reported as unscoped, got %v", names)
+	}
+
+	ring.SetStoreScopes(map[string][]string{
+		"Store Madrid": {"MAD-001"},
+		"Logistics":    {"MAD-001", "BCN-001"},
+		"Ecommerce":    {auth.AllStores},
+	})
+	ring.AddHash(auth.HashKey("key-zaragoza"), "Zaragoza Centro", "ZAR-001")
+
+	for key, expected := range map[string][]string{
+		"key-madrid":    {"MAD-001"},
+		"key-logistics": {"MAD-001", "BCN-001"},
+		"key-ecommerce": nil,
+		"key-reports":   {},
+		"key-zaragoza":  {"ZAR-001"},
+	} {
+		got := ring.StoreScope(key)
+		if len(got) != len(expected) || (got == nil) != (expected == nil) || (len(got) > 0 && got[0] != expected[0]) {
+			t.Errorf("%s: expected scope %#v, got %#v", key, expected, got)
+		}
+	}
+
+	// Con scopes configurados, la key sin entrada se reporta al arrancar
+	if names := ring.UnscopedNames(); len(names) != 1 || names[0] != "Reports" {
+		t.Errorf("Expected only Reports reported as unscoped, got %v", names)
+	}
+
+	// Los scopes por nombre se aplican también a las keys rotadas
+	ring.ReplaceStatic(map[string]string{"key-madrid-2": "Store Madrid"})
+	if got := ring.StoreScope("key-madrid-2"); len(got) != 1 || got[0] != "MAD-001" {
+		t.Errorf("Expected rotated key to keep the Store Madrid scope, got %v", got)
+	}
+}
+
+func TestStoreScopedWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	silenceLogs(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewMockPublisher()
+
+	stockService := service.NewStockService(stockRepo, productRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+	reservationHandler := handler.NewReservationHandler(reservationService, service.NewSerialService(repository.NewSerialRepository(db), reservationRepo))
+
+	ring := auth.NewKeyRing(map[string]string{"key-madrid": "Store Madrid", "key-ecommerce": "Ecommerce", "key-reports": "Reports"})
+	ring.SetStoreScopes(map[string][]string{"Store Madrid": {"MAD-001"}, "Ecommerce": {auth.AllStores}})
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	handler.RegisterCoreRoutes(router.Group("/api/v1", handler.UseSerializer(handler.V1Serializer{})), middleware.APIKeyAuth(ring),
+		handler.NewProductHandler(service.NewProductService(productRepo, eventRepo)), handler.NewStockHandler(stockService), reservationHandler)
+
+	ctx := context.Background()
+	product := testutil.CreateTestProduct()
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	for _, store := range []string{"MAD-001", "BCN-001"} {
+		if _, err := stockService.InitializeStock(ctx, product.ID, store, 10); err != nil {
+			t.Fatalf("Error initializing stock: %v", err)
+		}
+	}
+
+	post := func(key, path string, body interface{}) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw))
+		req.Header.Set("X-API-Key", key)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	reservation := func(storeID string) map[string]interface{} {
+		body := map[string]interface{}{"product_id": product.ID, "customer_id": "customer-1", "quantity": 1}
+		if storeID != "" {
+			body["store_id"] = storeID
+		}
+		return body
+	}
+
+	t.Run("RejectsCrossStoreWrites", func(t *testing.T) {
+		if w := post("key-madrid", "/api/v1/reservations", reservation("BCN-001")); w.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 reserving in another store, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := post("key-madrid", "/api/v1/stock/"+product.ID+"/BCN-001/adjust", map[string]int{"adjustment": 5}); w.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 adjusting another store, got %d: %s", w.Code, w.Body.String())
+		}
+
+		stock, _ := stockRepo.GetByProductAndStore(ctx, product.ID, "BCN-001")
+		if stock.Quantity != 10 || stock.Reserved != 0 {
+			t.Errorf("Expected BCN-001 stock untouched, got quantity=%d reserved=%d", stock.Quantity, stock.Reserved)
+		}
+	})
+
+	t.Run("DefaultsToActingStore", func(t *testing.T) {
+		w := post("key-madrid", "/api/v1/reservations", reservation(""))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var created domain.Reservation
+		json.Unmarshal(w.Body.Bytes(), &created)
+		if created.StoreID != "MAD-001" {
+			t.Errorf("Expected reservation in the key's store, got %q", created.StoreID)
+		}
+
+		if w := post("key-ecommerce", "/api/v1/reservations", reservation("")); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 without store_id for a multi-store key, got %d", w.Code)
+		}
+	})
+
+	t.Run("MultiStoreKeyWritesAnywhere", func(t *testing.T) {
+		w := post("key-ecommerce", "/api/v1/reservations", reservation("BCN-001"))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var created domain.Reservation
+		json.Unmarshal(w.Body.Bytes(), &created)
+
+		// Una key de otra tienda tampoco puede confirmar ni cancelar la reserva
+		if w := post("key-madrid", "/api/v1/reservations/"+created.ID+"/cancel", nil); w.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 cancelling a BCN-001 reservation, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("UnscopedKeyCannotWrite", func(t *testing.T) {
+		for _, store := range []string{"MAD-001", "BCN-001"} {
+			if w := post("key-reports", "/api/v1/reservations", reservation(store)); w.Code != http.StatusForbidden {
+				t.Errorf("Expected 403 reserving in %s with a key without scope entry, got %d: %s", store, w.Code, w.Body.String())
+			}
+		}
+	})
+}
+
+func TestStoreScopedSyncAndTransfers(t *testing.T) {
+	silenceLogs(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+
+	conflictService := service.NewConflictService(repository.NewConflictRepository(db), stockRepo, eventRepo, publisher)
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+	transferReservationService := service.NewTransferReservationService(reservationService, reservationRepo, stockRepo, repository.NewTransferRepository(db), eventRepo, publisher)
+
+	ctx := context.Background()
+	madrid := domain.WithStoreScope(ctx, []string{"MAD-001"})
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+
+	t.Run("SyncRejectsCrossStoreWrites", func(t *testing.T) {
+		before, _ := stockRepo.GetByProductAndStore(ctx, laptop, "BCN-001")
+		var forbidden *domain.ForbiddenError
+		_, err := conflictService.ApplyRemoteStockUpdate(madrid, &domain.RemoteStockUpdate{
+			ProductID:    laptop,
+			StoreID:      "BCN-001",
+			BaseVersion:  before.Version,
+			BaseQuantity: before.Quantity,
+			NewQuantity:  before.Quantity + 50,
+		})
+		if !errors.As(err, &forbidden) {
+			t.Fatalf("Expected ForbiddenError syncing another store, got %v", err)
+		}
+
+		// Conflicto sin resolver en BCN-001: la key de Madrid tampoco puede resolverlo
+		conflict, err := conflictService.ApplyRemoteStockUpdate(ctx, &domain.RemoteStockUpdate{
+			ProductID:    laptop,
+			StoreID:      "BCN-001",
+			BaseVersion:  before.Version + 100,
+			BaseQuantity: 1000,
+			NewQuantity:  0,
+		})
+		if err != nil || conflict == nil || conflict.Status != domain.ConflictStatusUnresolved {
+			t.Fatalf("Expected an UNRESOLVED conflict, got %+v (%v)", conflict, err)
+		}
+		if _, err := conflictService.ResolveConflict(madrid, conflict.ID, 50, "recount"); !errors.As(err, &forbidden) {
+			t.Errorf("Expected ForbiddenError resolving another store's conflict, got %v", err)
+		}
+
+		after, _ := stockRepo.GetByProductAndStore(ctx, laptop, "BCN-001")
+		if after.Quantity != before.Quantity || after.Version != before.Version {
+			t.Errorf("Expected BCN-001 stock untouched, got quantity=%d version=%d", after.Quantity, after.Version)
+		}
+	})
+
+	t.Run("TransferActsForPreferredStore", func(t *testing.T) {
+		// VAL-001 no tiene stock del producto 0002; lo sirve BCN-001
+		productID := "550e8400-e29b-41d4-a716-446655440002"
+		valencia := domain.WithStoreScope(ctx, []string{"VAL-001"})
+		barcelona := domain.WithStoreScope(ctx, []string{"BCN-001"})
+
+		created, err := transferReservationService.CreateTransferReservation(valencia, productID, "VAL-001", "BCN-001", "customer-1", 1, 30)
+		if err != nil {
+			t.Fatalf("Expected the preferred store's key to create the transfer reservation, got %v", err)
+		}
+		if created.Reservation.StoreID != "BCN-001" {
+			t.Errorf("Expected the reservation in the source store, got %s", created.Reservation.StoreID)
+		}
+
+		var forbidden *domain.ForbiddenError
+		if _, err := transferReservationService.CreateTransferReservation(barcelona, productID, "VAL-001", "BCN-001", "customer-2", 1, 30); !errors.As(err, &forbidden) {
+			t.Errorf("Expected ForbiddenError for the source store's key, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestStoreService_BootstrapStore(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	keyRing := auth.NewKeyRing(map[string]string{"dev-key-admin": "Admin"})
+	stockRepo := repository.NewStockRepository(db)
+	storeService := service.NewStoreService(
+		repository.NewStoreRepository(db),
+		repository.NewAPIKeyRepository(db),
+		stockRepo,
+		repository.NewProductRepository(db),
+		repository.NewEventRepository(db),
+		mocks.NewNoOpPublisher(),
+		keyRing,
+	)
+	ctx := context.Background()
+
+	input := &domain.StoreBootstrap{
+		Store:    domain.Store{ID: "ZAR-001", Name: "Zaragoza Centro", City: "Zaragoza"},
+		Template: domain.AssortmentTemplate{FromStoreID: "MAD-001", Category: "electronics"},
+		MinStock: 3,
+		MaxStock: 20,
+	}
+
+	var issuedKey string
+
+	t.Run("FirstRun_CreatesEverything", func(t *testing.T) {
+		result, err := storeService.BootstrapStore(ctx, input)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !result.StoreCreated || !result.APIKeyCreated {
+			t.Errorf("Expected store and API key to be created, got %+v", result)
+		}
+		if result.StockInitialized != 2 {
+			t.Errorf("Expected 2 stock rows initialized, got %d", result.StockInitialized)
+		}
+		if result.APIKey == nil || result.APIKey.Key == "" {
+			t.Fatal("Expected API key to be returned on creation")
+		}
+		issuedKey = result.APIKey.Key
+
+		if name, ok := keyRing.Lookup(issuedKey); !ok || name != "Zaragoza Centro" {
+			t.Errorf("Expected issued key to be active in key ring, got %q %v", name, ok)
+		}
+
+		stock, err := stockRepo.GetByProductAndStore(ctx, "550e8400-e29b-41d4-a716-446655440000", "ZAR-001")
+		if err != nil {
+			t.Fatalf("Expected stock row, got %v", err)
+		}
+		if stock.Quantity != 0 || stock.MinStock != 3 || stock.MaxStock != 20 {
+			t.Errorf("Unexpected stock row: %+v", stock)
+		}
+	})
+
+	t.Run("SecondRun_IsIdempotent", func(t *testing.T) {
+		result, err := storeService.BootstrapStore(ctx, input)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.StoreCreated || result.APIKeyCreated || result.StockInitialized != 0 {
+			t.Errorf("Expected nothing new to be created, got %+v", result)
+		}
+		if result.StockExisting != 2 {
+			t.Errorf("Expected 2 existing stock rows, got %d", result.StockExisting)
+		}
+		if result.APIKey == nil || result.APIKey.Key != "" {
+			t.Errorf("Expected existing API key without secret, got %+v", result.APIKey)
+		}
+	})
+
+	t.Run("EmptyTemplate_Fails", func(t *testing.T) {
+		_, err := storeService.BootstrapStore(ctx, &domain.StoreBootstrap{
+			Store: domain.Store{ID: "ZAR-002", Name: "Zaragoza Norte"},
+		})
+		if _, ok := err.(*domain.ValidationError); !ok {
+			t.Errorf("Expected ValidationError, got %v", err)
+		}
+	})
+
+	t.Run("UnknownProduct_Fails", func(t *testing.T) {
+		_, err := storeService.BootstrapStore(ctx, &domain.StoreBootstrap{
+			Store:    domain.Store{ID: "ZAR-003", Name: "Zaragoza Sur"},
+			Template: domain.AssortmentTemplate{ProductIDs: []string{"missing-product"}},
+		})
+		if _, ok := err.(*domain.NotFoundError); !ok {
+			t.Errorf("Expected NotFoundError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStreamedList_StockByStore(t *testing.T) {
+	router, cleanup := newVersionedRouter(t)
+	defer cleanup()
+
+	w, v1 := doVersionedRequest(t, router, http.MethodGet, "/api/v1/stock/store/MAD-001", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	items, _ := v1["items"].([]interface{})
+	if v1["store_id"] != "MAD-001" || len(items) == 0 || v1["count"] != float64(len(items)) {
+		t.Errorf("Expected the v1 shape with store_id, items and count, got %v", v1)
+	}
+
+	_, v2 := doVersionedRequest(t, router, http.MethodGet, "/api/v2/stock/store/MAD-001", nil)
+	data, _ := v2["data"].([]interface{})
+	meta, _ := v2["meta"].(map[string]interface{})
+	if len(data) != len(items) || meta["count"] != float64(len(items)) || meta["store_id"] != "MAD-001" {
+		t.Errorf("Expected the v2 envelope with %d items, got %v", len(items), v2)
+	}
+
+	_, empty := doVersionedRequest(t, router, http.MethodGet, "/api/v1/stock/store/NOPE-001", nil)
+	if items, ok := empty["items"].([]interface{}); !ok || len(items) != 0 || empty["count"] != float64(0) {
+		t.Errorf("Expected an empty items array for a store without stock, got %v", empty)
+	}
+}
+
+func TestStreamedList_Events(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	silenceLogs(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+	stockService := service.NewStockService(repository.NewStockRepository(db), repository.NewProductRepository(db), eventRepo, publisher)
+	eventHandler := handler.NewEventHandler(service.NewEventSyncService(eventRepo, publisher))
+
+	router := gin.New()
+	v1 := router.Group("/api/v1", handler.UseSerializer(handler.V1Serializer{}))
+	v1.GET("/stock/movements", eventHandler.ListMovements)
+	v1.GET("/admin/events", eventHandler.ListEvents)
+
+	laptop := "550e8400-e29b-41d4-a716-446655440000"
+	for _, store := range []string{"MAD-001", "MAD-001", "BCN-001"} {
+		if _, err := stockService.AdjustStock(context.Background(), laptop, store, 1); err != nil {
+			t.Fatalf("Error adjusting stock: %v", err)
+		}
+	}
+
+	get := func(path string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("Invalid JSON response for %s: %v", path, err)
+		}
+		return w.Code, decoded
+	}
+
+	_, movements := get("/api/v1/stock/movements?store_id=MAD-001&product_id=" + laptop)
+	if movements["count"] != float64(2) {
+		t.Fatalf("Expected 2 movements in MAD-001, got %v", movements)
+	}
+
+	_, events := get("/api/v1/admin/events?type=" + domain.EventStockUpdated)
+	list, _ := events["events"].([]interface{})
+	if len(list) != 3 {
+		t.Fatalf("Expected 3 stock.updated events, got %d", len(list))
+	}
+	first, _ := list[0].(map[string]interface{})
+	seq, _ := first["seq"].(float64)
+	_, resumed := get("/api/v1/admin/events?type=" + domain.EventStockUpdated + "&after_seq=" + strconv.FormatInt(int64(seq), 10))
+	if resumed["count"] != float64(2) {
+		t.Errorf("Expected 2 events after the first seq, got %v", resumed["count"])
+	}
+
+	if code, _ := get("/api/v1/stock/movements?from=2026-02-01&to=2026-01-01"); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an inverted range, got %d", code)
+	}
+}
@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"inventory-system/internal/auth"
+	"inventory-system/internal/handler"
+	"inventory-system/internal/middleware"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeoutPolicy_Resolve(t *testing.T) {
+	policy := middleware.TimeoutPolicy{
+		Default: 30 * time.Second,
+		Overrides: map[string]time.Duration{
+			"/api/v1/reports/*":            120 * time.Second,
+			"GET /api/v1/reports/exports*": 0,
+			"POST /api/v1/reservations":    5 * time.Second,
+			"/api/v1/products/:id":         10 * time.Second,
+		},
+	}
+
+	for _, tc := range []struct {
+		method, route string
+		expected      time.Duration
+	}{
+		{"POST", "/api/v1/reservations", 5 * time.Second},
+		{"GET", "/api/v1/reservations", 30 * time.Second},
+		{"PUT", "/api/v1/products/:id", 10 * time.Second},
+		{"GET", "/api/v1/reports/low-stock", 120 * time.Second},
+		{"GET", "/api/v1/reports/exports/:id/download", 0},
+		{"POST", "/api/v1/reports/exports", 120 * time.Second},
+	} {
+		if got := policy.Resolve(tc.method, tc.route); got != tc.expected {
+			t.Errorf("%s %s: expected %v, got %v", tc.method, tc.route, tc.expected, got)
+		}
+	}
+}
+
+func TestTimeout_CancelsQueriesWith504(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	silenceLogs(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	publisher := mocks.NewMockPublisher()
+
+	productHandler := handler.NewProductHandler(service.NewProductService(productRepo, eventRepo))
+	stockHandler := handler.NewStockHandler(service.NewStockService(stockRepo, productRepo, eventRepo, publisher))
+	reservationHandler := handler.NewReservationHandler(
+		service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher),
+		service.NewSerialService(repository.NewSerialRepository(db), reservationRepo),
+	)
+	apiKeyAuth := middleware.APIKeyAuth(auth.NewKeyRing(map[string]string{"test-key": "Test Store"}))
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Timeout(middleware.TimeoutPolicy{Default: 100 * time.Millisecond}))
+	handler.RegisterCoreRoutes(router.Group("/api/v1", handler.UseSerializer(handler.V1Serializer{})), apiKeyAuth, productHandler, stockHandler, reservationHandler)
+	handler.RegisterCoreRoutes(router.Group("/api/v2", handler.UseSerializer(handler.V2Serializer{})), apiKeyAuth, productHandler, stockHandler, reservationHandler)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-API-Key", "test-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("/api/v1/products"); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 before locking the database, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Una transacción abierta ocupa la única conexión: las consultas esperan hasta el deadline
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	start := time.Now()
+	w := get("/api/v1/products")
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("Expected 504, got %d: %s", w.Code, w.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected request to be cancelled at the deadline, took %v", elapsed)
+	}
+
+	w = get("/api/v2/products")
+	var body handler.V2ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusGatewayTimeout || body.Error.Code != "GATEWAY_TIMEOUT" {
+		t.Errorf("Expected v2 GATEWAY_TIMEOUT error, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/mocks"
+	"inventory-system/test/testutil"
+)
+
+func TestTransferReservationService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	stockRepo := repository.NewStockRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	transferRepo := repository.NewTransferRepository(db)
+	publisher := mocks.NewNoOpPublisher()
+
+	reservationService := service.NewReservationService(reservationRepo, stockRepo, productRepo, eventRepo, publisher)
+	transferReservationService := service.NewTransferReservationService(reservationService, reservationRepo, stockRepo, transferRepo, eventRepo, publisher)
+
+	ctx := context.Background()
+
+	// VAL-001 no tiene stock del producto 0002; BCN-001 es la tienda con más disponibilidad (25)
+	productID := "550e8400-e29b-41d4-a716-446655440002"
+
+	t.Run("CreateTransferReservation_PicksBestSource", func(t *testing.T) {
+		result, err := transferReservationService.CreateTransferReservation(ctx, productID, "VAL-001", "", "customer-1", 3, 30)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.SourceStoreID != "BCN-001" || result.Reservation.StoreID != "BCN-001" {
+			t.Errorf("Expected source store BCN-001, got %s", result.SourceStoreID)
+		}
+		if result.Transfer.ToStoreID != "VAL-001" || result.Transfer.ReservationID != result.Reservation.ID {
+			t.Errorf("Expected transfer to VAL-001 linked to reservation, got %+v", result.Transfer)
+		}
+		if result.State != domain.TransferReservationAwaitingTransfer {
+			t.Errorf("Expected state AWAITING_TRANSFER, got %s", result.State)
+		}
+
+		stock, _ := stockRepo.GetByProductAndStore(ctx, productID, "BCN-001")
+		if stock.Reserved != 3 {
+			t.Errorf("Expected 3 reserved at source store, got %d", stock.Reserved)
+		}
+	})
+
+	t.Run("GetTransferReservation_ConfirmedCompletesTransfer", func(t *testing.T) {
+		created, err := transferReservationService.CreateTransferReservation(ctx, productID, "VAL-001", "SEV-001", "customer-2", 2, 30)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if err := reservationService.ConfirmReservation(ctx, created.Reservation.ID); err != nil {
+			t.Fatalf("Error confirming reservation: %v", err)
+		}
+
+		result, err := transferReservationService.GetTransferReservation(ctx, created.Reservation.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Transfer.Status != domain.TransferStatusCompleted {
+			t.Errorf("Expected transfer COMPLETED, got %s", result.Transfer.Status)
+		}
+		if result.State != domain.TransferReservationFulfilled {
+			t.Errorf("Expected state FULFILLED, got %s", result.State)
+		}
+	})
+
+	t.Run("GetTransferReservation_CancelledCancelsTransfer", func(t *testing.T) {
+		created, err := transferReservationService.CreateTransferReservation(ctx, productID, "VAL-001", "", "customer-3", 1, 30)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if err := reservationService.CancelReservation(ctx, created.Reservation.ID); err != nil {
+			t.Fatalf("Error cancelling reservation: %v", err)
+		}
+
+		result, err := transferReservationService.GetTransferReservation(ctx, created.Reservation.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.State != domain.TransferReservationCancelled {
+			t.Errorf("Expected state CANCELLED, got %s", result.State)
+		}
+	})
+
+	t.Run("CreateTransferReservation_PreferredStoreHasStock", func(t *testing.T) {
+		_, err := transferReservationService.CreateTransferReservation(ctx, productID, "MAD-001", "", "customer-4", 1, 30)
+		if _, ok := err.(*domain.ConflictError); !ok {
+			t.Errorf("Expected ConflictError, got %v", err)
+		}
+	})
+
+	t.Run("CreateTransferReservation_NoSourceWithStock", func(t *testing.T) {
+		_, err := transferReservationService.CreateTransferReservation(ctx, productID, "VAL-001", "", "customer-5", 100, 30)
+		if _, ok := err.(*domain.InsufficientStockError); !ok {
+			t.Errorf("Expected InsufficientStockError, got %v", err)
+		}
+	})
+
+	t.Run("GetTransferReservation_RegularReservation", func(t *testing.T) {
+		reservation, err := reservationService.CreateReservation(ctx, productID, "MAD-001", "customer-6", 1, 30)
+		if err != nil {
+			t.Fatalf("Error creating reservation: %v", err)
+		}
+
+		_, err = transferReservationService.GetTransferReservation(ctx, reservation.ID)
+		if _, ok := err.(*domain.NotFoundError); !ok {
+			t.Errorf("Expected NotFoundError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/domain"
+	"inventory-system/internal/repository"
+	"inventory-system/internal/service"
+	"inventory-system/test/testutil"
+)
+
+func TestLocalePolicy_Resolve(t *testing.T) {
+	policy := domain.DefaultLocalePolicy()
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		expected       string
+	}{
+		{"QueryWins", "ca", "en-GB,en;q=0.9", "ca"},
+		{"QueryRegionTag", "en-US", "", "en"},
+		{"UnsupportedQueryFallsBack", "fr", "ca", "es"},
+		{"AcceptLanguageByQuality", "", "fr-FR;q=0.9, en;q=0.8, ca-ES", "ca"},
+		{"SkipsUnsupported", "", "de, fr;q=0.9, en;q=0.5", "en"},
+		{"ZeroQualityIgnored", "", "ca;q=0, en;q=0.1", "en"},
+		{"NoPreference", "", "", "es"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Resolve(tt.query, tt.acceptLanguage); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTranslationService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	productRepo := repository.NewProductRepository(db)
+	translationService := service.NewTranslationService(repository.NewTranslationRepository(db), productRepo, domain.DefaultLocalePolicy())
+	productService := service.NewProductService(productRepo, repository.NewEventRepository(db))
+
+	ctx := context.Background()
+	translated, err := productService.CreateProduct(ctx, testutil.CreateTestProduct())
+	if err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+	untranslated, err := productService.CreateProduct(ctx, testutil.CreateTestProduct())
+	if err != nil {
+		t.Fatalf("Error creating product: %v", err)
+	}
+
+	if _, err := translationService.PutTranslation(ctx, translated.ID, "ca-ES", "Portàtil", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("RejectsDefaultAndUnsupportedLocales", func(t *testing.T) {
+		for _, locale := range []string{"es", "fr"} {
+			if _, err := translationService.PutTranslation(ctx, translated.ID, locale, "Name", ""); err == nil {
+				t.Errorf("Expected validation error for locale %s", locale)
+			}
+		}
+	})
+
+	t.Run("LocalizeFallsBackToDefault", func(t *testing.T) {
+		a, _ := productService.GetProduct(ctx, translated.ID)
+		b, _ := productService.GetProduct(ctx, untranslated.ID)
+		description := a.Description
+
+		if err := translationService.Localize(ctx, "ca", a, b); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if a.Name != "Portàtil" || a.Locale != "ca" {
+			t.Errorf("Expected Catalan name, got %s (%s)", a.Name, a.Locale)
+		}
+		if a.Description != description {
+			t.Errorf("Expected empty translated description to keep the default, got %q", a.Description)
+		}
+		if b.Name != untranslated.Name || b.Locale != "es" {
+			t.Errorf("Expected default text for untranslated product, got %s (%s)", b.Name, b.Locale)
+		}
+	})
+
+	t.Run("PutReplacesAndDeleteRemoves", func(t *testing.T) {
+		if _, err := translationService.PutTranslation(ctx, translated.ID, "ca", "Portàtil HP", "Descripció"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		translations, _ := translationService.ListTranslations(ctx, translated.ID)
+		if len(translations) != 1 || translations[0].Name != "Portàtil HP" {
+			t.Fatalf("Expected a single replaced translation, got %+v", translations)
+		}
+
+		if err := translationService.DeleteTranslation(ctx, translated.ID, "ca"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := translationService.DeleteTranslation(ctx, translated.ID, "ca"); err == nil {
+			t.Error("Expected not found when deleting a missing translation")
+		}
+	})
+}